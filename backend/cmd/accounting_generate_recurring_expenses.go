@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/abdelrahman146/kyora/internal/domain/accounting"
+	"github.com/abdelrahman146/kyora/internal/platform/email"
+	"github.com/abdelrahman146/kyora/internal/server"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+// accountingGenerateRecurringExpensesCmd records the expenses of recurring expenses due today
+// and reminds owners of large charges coming up. It is meant to be scheduled daily (e.g. a cron
+// job early in the morning): missed days are caught up and reminders are sent once per
+// occurrence, so a failed run can simply be repeated.
+var accountingGenerateRecurringExpensesCmd = &cobra.Command{
+	Use:   "accounting-generate-recurring-expenses",
+	Short: "Record the expenses of recurring expenses that are due and remind owners of upcoming ones",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		businessID, _ := cmd.Flags().GetString("business-id")
+		reminderDays, _ := cmd.Flags().GetInt("reminder-days")
+		reminderMin, _ := cmd.Flags().GetString("reminder-min-amount")
+		reminderMinAmount, err := decimal.NewFromString(reminderMin)
+		if err != nil {
+			return err
+		}
+
+		svcs, err := server.NewServices(nil)
+		if err != nil {
+			return err
+		}
+		defer svcs.Close()
+		svcs.Accounting.SetNotification(accounting.NewNotification(svcs.Email, email.NewEmail(), svcs.Account))
+
+		return forEachRegion(cmd.Context(), svcs, businessID, func(ctx context.Context, name string) error {
+			result, err := svcs.Accounting.GenerateDueRecurringExpenses(ctx, accounting.GenerateRecurringExpensesOptions{
+				BusinessID:        businessID,
+				ReminderDays:      reminderDays,
+				ReminderMinAmount: reminderMinAmount,
+			})
+			if err != nil {
+				slog.Error("recurring expenses generation failed", "error", err, "region", name)
+				return err
+			}
+			slog.Info("recurring expenses generation completed", "region", name,
+				"due", result.Due, "created", result.Created, "ended", result.Ended,
+				"reminded", result.Reminded, "failed", result.Failed)
+			return nil
+		})
+	},
+}
+
+func init() {
+	accountingGenerateRecurringExpensesCmd.Flags().String("business-id", "", "Limit the run to a single business")
+	accountingGenerateRecurringExpensesCmd.Flags().Int("reminder-days", 3, "Days ahead to remind owners of upcoming recurring expenses; 0 disables reminders")
+	accountingGenerateRecurringExpensesCmd.Flags().String("reminder-min-amount", "500", "Smallest recurring expense amount, in the business currency, worth a reminder")
+	rootCmd.AddCommand(accountingGenerateRecurringExpensesCmd)
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/region"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// declaredIndexes lists every index declared by the domain storages.
+func declaredIndexes() []database.Index {
+	var indexes []database.Index
+	indexes = append(indexes, order.Indexes...)
+	indexes = append(indexes, customer.Indexes...)
+	indexes = append(indexes, inventory.Indexes...)
+	return indexes
+}
+
+// dbIndexesCmd verifies (and optionally creates) the declared secondary indexes.
+var dbIndexesCmd = &cobra.Command{
+	Use:   "db-indexes",
+	Short: "Verify or create the declared database indexes",
+	Long: `Verify that every index declared by the domain storages exists.
+Use --create to create the missing ones. This is intended for deployments that run
+with database auto-migration disabled.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		create, _ := cmd.Flags().GetBool("create")
+		ctx := context.Background()
+
+		// Building an index on a large table can take much longer than an API query;
+		// lift the request-oriented timeouts for this maintenance connection.
+		viper.Set(config.DatabaseStatementTimeout, 0)
+		viper.Set(config.DatabaseQueryTimeout, 0)
+
+		dsn := viper.GetString(config.DatabaseDSN)
+		logLevel := viper.GetString(config.DatabaseLogLevel)
+		db, err := database.NewConnection(dsn, logLevel)
+		if err != nil {
+			return err
+		}
+		defer db.CloseConnection()
+		if err := database.ConnectRegions(db, logLevel); err != nil {
+			return err
+		}
+
+		totalMissing := 0
+		for _, name := range db.Regions() {
+			regionCtx := region.WithRegion(ctx, name)
+			missing, err := database.MissingIndexes(regionCtx, db, declaredIndexes()...)
+			if err != nil {
+				slog.Error("failed to verify indexes", "error", err, "region", name)
+				return err
+			}
+			if len(missing) == 0 {
+				slog.Info("all declared indexes exist", "region", name)
+				continue
+			}
+			for _, idx := range missing {
+				slog.Warn("missing index", "index", idx.Name, "table", idx.Table, "region", name)
+			}
+			if !create {
+				totalMissing += len(missing)
+				continue
+			}
+			if err := database.CreateIndexes(regionCtx, db, missing...); err != nil {
+				slog.Error("failed to create indexes", "error", err, "region", name)
+				return err
+			}
+			slog.Info("created missing indexes", "count", len(missing), "region", name)
+		}
+		if totalMissing > 0 {
+			return fmt.Errorf("%d declared indexes are missing; rerun with --create", totalMissing)
+		}
+		return nil
+	},
+}
+
+func init() {
+	dbIndexesCmd.Flags().Bool("create", false, "create missing indexes")
+	rootCmd.AddCommand(dbIndexesCmd)
+}
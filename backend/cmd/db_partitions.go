@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/region"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// declaredPartitionings lists every table partitioning declared by the domain storages,
+// parents before the tables referencing them.
+func declaredPartitionings() []database.Partitioning {
+	var partitionings []database.Partitioning
+	partitionings = append(partitionings, order.Partitionings...)
+	return partitionings
+}
+
+// dbPartitionsCmd verifies (and optionally applies) the declared table partitionings.
+var dbPartitionsCmd = &cobra.Command{
+	Use:   "db-partitions",
+	Short: "Verify or apply the declared table partitionings",
+	Long: `Verify that every table with a declared partitioning is partitioned.
+Use --apply to convert the unpartitioned ones. Each table is copied into its partitions
+in a single transaction that locks it, so run this during a maintenance window.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apply, _ := cmd.Flags().GetBool("apply")
+		ctx := context.Background()
+
+		// Copying a large table can take much longer than an API query;
+		// lift the request-oriented timeouts for this maintenance connection.
+		viper.Set(config.DatabaseStatementTimeout, 0)
+		viper.Set(config.DatabaseQueryTimeout, 0)
+
+		dsn := viper.GetString(config.DatabaseDSN)
+		logLevel := viper.GetString(config.DatabaseLogLevel)
+		db, err := database.NewConnection(dsn, logLevel)
+		if err != nil {
+			return err
+		}
+		defer db.CloseConnection()
+		if err := database.ConnectRegions(db, logLevel); err != nil {
+			return err
+		}
+
+		totalPending := 0
+		for _, name := range db.Regions() {
+			regionCtx := region.WithRegion(ctx, name)
+			pending, err := database.UnpartitionedTables(regionCtx, db, declaredPartitionings()...)
+			if err != nil {
+				slog.Error("failed to verify partitionings", "error", err, "region", name)
+				return err
+			}
+			if len(pending) == 0 {
+				slog.Info("all declared partitionings are applied", "region", name)
+				continue
+			}
+			for _, p := range pending {
+				slog.Warn("table is not partitioned", "table", p.Table, "column", p.Column, "region", name)
+			}
+			if !apply {
+				totalPending += len(pending)
+				continue
+			}
+			for _, p := range pending {
+				if err := database.PartitionTable(regionCtx, db, p); err != nil {
+					slog.Error("failed to partition table", "error", err, "table", p.Table, "region", name)
+					return err
+				}
+				slog.Info("partitioned table", "table", p.Table, "partitions", p.Partitions, "region", name)
+			}
+		}
+		if totalPending > 0 {
+			return fmt.Errorf("%d declared partitionings are not applied; rerun with --apply", totalPending)
+		}
+		return nil
+	},
+}
+
+func init() {
+	dbPartitionsCmd.Flags().Bool("apply", false, "partition the unpartitioned tables")
+	rootCmd.AddCommand(dbPartitionsCmd)
+}
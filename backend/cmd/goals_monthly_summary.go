@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/analytics"
+	"github.com/abdelrahman146/kyora/internal/server"
+	"github.com/spf13/cobra"
+)
+
+// goalsMonthlySummaryCmd emails owners how their business did against last month's goals.
+// It is meant to be scheduled daily early in the month: goals whose month has not ended yet in
+// the business timezone, or whose email failed, are picked up by the next run.
+var goalsMonthlySummaryCmd = &cobra.Command{
+	Use:   "goals-monthly-summary",
+	Short: "Send end-of-month KPI goal summaries to business owners",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		month, _ := cmd.Flags().GetString("month")
+		businessID, _ := cmd.Flags().GetString("business-id")
+		if month == "" {
+			// the last day of the previous month
+			now := time.Now().UTC()
+			month = now.AddDate(0, 0, -now.Day()).Format(analytics.GoalMonthLayout)
+		}
+
+		svcs, err := server.NewServices(nil)
+		if err != nil {
+			return err
+		}
+		defer svcs.Close()
+
+		return forEachRegion(cmd.Context(), svcs, businessID, func(ctx context.Context, name string) error {
+			result, err := svcs.Analytics.SendMonthlyGoalSummaries(ctx, analytics.MonthlyGoalSummaryOptions{
+				Month:      month,
+				BusinessID: businessID,
+			})
+			if err != nil {
+				slog.Error("monthly goal summaries failed", "month", month, "error", err, "region", name)
+				return err
+			}
+			slog.Info("monthly goal summaries completed", "region", name,
+				"month", month, "sent", result.Sent, "pending", result.Pending, "failed", result.Failed)
+			return nil
+		})
+	},
+}
+
+func init() {
+	goalsMonthlySummaryCmd.Flags().String("month", "", "Month to summarize (YYYY-MM); defaults to the previous month")
+	goalsMonthlySummaryCmd.Flags().String("business-id", "", "Limit the run to a single business")
+	rootCmd.AddCommand(goalsMonthlySummaryCmd)
+}
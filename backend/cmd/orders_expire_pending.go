@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/server"
+	"github.com/spf13/cobra"
+)
+
+// ordersExpirePendingCmd cancels orders that stayed pending longer than their business'
+// pending order TTL and puts their items back in stock. It is meant to be scheduled often
+// (e.g. an hourly cron job); businesses without a TTL are left alone.
+var ordersExpirePendingCmd = &cobra.Command{
+	Use:   "orders-expire-pending",
+	Short: "Cancel stale pending orders and restock their items",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		businessID, _ := cmd.Flags().GetString("business-id")
+
+		// The services are wired with their bus handlers, like the server, so the
+		// order.cancelled and order.status_changed events of expired orders reach their
+		// consumers. Close waits for those handlers before the command exits.
+		svcs, err := server.NewServices(nil)
+		if err != nil {
+			return err
+		}
+		defer svcs.Close()
+
+		return forEachRegion(cmd.Context(), svcs, businessID, func(ctx context.Context, name string) error {
+			result, err := svcs.Order.ExpirePendingOrders(ctx, order.ExpirePendingOrdersOptions{
+				BusinessID: businessID,
+			})
+			if err != nil {
+				slog.Error("pending orders expiry failed", "error", err, "region", name)
+				return err
+			}
+			slog.Info("pending orders expiry completed", "region", name,
+				"businesses", result.Businesses, "expired", result.Expired, "failed", result.Failed)
+			return nil
+		})
+	},
+}
+
+func init() {
+	ordersExpirePendingCmd.Flags().String("business-id", "", "Limit the run to a single business")
+	rootCmd.AddCommand(ordersExpirePendingCmd)
+}
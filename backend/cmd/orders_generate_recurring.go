@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/server"
+	"github.com/spf13/cobra"
+)
+
+// ordersGenerateRecurringCmd creates the orders of recurring orders due today.
+// It is meant to be scheduled daily (e.g. a cron job early in the morning): recurring orders
+// already handled today are not due anymore, so a failed run can simply be repeated.
+var ordersGenerateRecurringCmd = &cobra.Command{
+	Use:   "orders-generate-recurring",
+	Short: "Create the orders of recurring orders (product subscriptions) that are due",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		businessID, _ := cmd.Flags().GetString("business-id")
+
+		// Created orders emit the same events as orders created in the app (order.paid for
+		// orders recorded as paid); the handlers wired here get them before the command exits.
+		svcs, err := server.NewServices(nil)
+		if err != nil {
+			return err
+		}
+		defer svcs.Close()
+
+		return forEachRegion(cmd.Context(), svcs, businessID, func(ctx context.Context, name string) error {
+			result, err := svcs.Order.GenerateDueRecurringOrders(ctx, order.GenerateRecurringOrdersOptions{
+				BusinessID: businessID,
+			})
+			if err != nil {
+				slog.Error("recurring orders generation failed", "error", err, "region", name)
+				return err
+			}
+			slog.Info("recurring orders generation completed", "region", name,
+				"due", result.Due, "created", result.Created, "skipped", result.Skipped, "failed", result.Failed)
+			return nil
+		})
+	},
+}
+
+func init() {
+	ordersGenerateRecurringCmd.Flags().String("business-id", "", "Limit the run to a single business")
+	rootCmd.AddCommand(ordersGenerateRecurringCmd)
+}
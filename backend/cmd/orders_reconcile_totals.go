@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/server"
+	"github.com/spf13/cobra"
+)
+
+// ordersReconcileTotalsCmd recomputes recent order totals from their items.
+// It is meant to be scheduled (e.g. a nightly cron job) next to onboarding-cleanup.
+var ordersReconcileTotalsCmd = &cobra.Command{
+	Use:   "orders-reconcile-totals",
+	Short: "Detect (and optionally repair) drift between order totals and their items",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		since, _ := cmd.Flags().GetDuration("since")
+		repair, _ := cmd.Flags().GetBool("repair")
+		businessID, _ := cmd.Flags().GetString("business-id")
+
+		svcs, err := server.NewServices(nil)
+		if err != nil {
+			return err
+		}
+		defer svcs.Close()
+
+		cutoff := time.Now().UTC().Add(-since)
+		return forEachRegion(cmd.Context(), svcs, businessID, func(ctx context.Context, name string) error {
+			result, err := svcs.Order.ReconcileOrderTotals(ctx, order.ReconcileOrderTotalsOptions{
+				BusinessID: businessID,
+				Since:      cutoff,
+				Repair:     repair,
+			})
+			if err != nil {
+				slog.Error("order totals reconciliation failed", "error", err, "region", name)
+				return err
+			}
+			slog.Info("order totals reconciliation completed", "region", name,
+				"checked", result.Checked, "mismatches", len(result.Mismatches), "repaired", result.Repaired)
+			return nil
+		})
+	},
+}
+
+func init() {
+	ordersReconcileTotalsCmd.Flags().Duration("since", 7*24*time.Hour, "Only check orders created within this window")
+	ordersReconcileTotalsCmd.Flags().Bool("repair", false, "Overwrite drifted totals with the values recomputed from items")
+	ordersReconcileTotalsCmd.Flags().String("business-id", "", "Limit the check to a single business")
+	rootCmd.AddCommand(ordersReconcileTotalsCmd)
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/abdelrahman146/kyora/internal/platform/region"
+	"github.com/abdelrahman146/kyora/internal/server"
+)
+
+// forEachRegion runs fn once per region a job must visit, with ctx routed to that region's
+// database, and stops at the first error. Business data lives in the region of its
+// workspace: a job limited to one business visits that business' region only, other jobs
+// visit every region.
+func forEachRegion(ctx context.Context, svcs *server.Services, businessID string, fn func(ctx context.Context, name string) error) error {
+	names := svcs.DB.Regions()
+	if businessID != "" {
+		name, err := svcs.Business.ResolveBusinessRegion(ctx, businessID)
+		if err != nil {
+			return fmt.Errorf("business %s: %w", businessID, err)
+		}
+		names = []string{name}
+	}
+	for _, name := range names {
+		if err := fn(region.WithRegion(ctx, name), name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
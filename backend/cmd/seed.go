@@ -194,7 +194,7 @@ var seedCmd = &cobra.Command{
 		inventorySvc := inventory.NewService(inventoryStorage, atomicProcessor, eventBus)
 
 		customerStorage := customer.NewStorage(db, cacheDB)
-		customerSvc := customer.NewService(customerStorage, atomicProcessor, eventBus)
+		customerSvc := customer.NewService(customerStorage, atomicProcessor, eventBus, inventorySvc)
 
 		accountingStorage := accounting.NewStorage(db, cacheDB)
 		accountingSvc := accounting.NewService(accountingStorage, atomicProcessor, eventBus)
@@ -407,7 +407,7 @@ func seedWorkspace(ctx context.Context, deps seedDeps, cfg workspaceSeedConfig,
 	var owner *account.User
 	var ws *account.Workspace
 	if err := step(label+" Creating workspace + owner", func() error {
-		u, w, err := deps.accountSvc.BootstrapWorkspaceAndOwner(ctx, cfg.Name, "Owner", ownerEmail, passwordHash, true, "")
+		u, w, err := deps.accountSvc.BootstrapWorkspaceAndOwner(ctx, cfg.Name, "Owner", ownerEmail, passwordHash, true, "", "")
 		if err != nil {
 			return fmt.Errorf("failed to create workspace/owner (try --clean): %w", err)
 		}
@@ -1030,7 +1030,7 @@ func seedAccountingData(ctx context.Context, svc *accounting.Service, owner *acc
 		if err != nil {
 			return err
 		}
-		_, err = svc.CreateWithdrawal(ctx, owner, biz, &accounting.CreateWithdrawalRequest{
+		_, _, err = svc.CreateWithdrawal(ctx, owner, biz, &accounting.CreateWithdrawalRequest{
 			WithdrawerID: owner.ID,
 			Amount:       decimal.NewFromFloat(1500 + rng.Float64()*1500).Round(2),
 			Note:         "Owner draw",
@@ -1130,7 +1130,7 @@ func seedAccountingData(ctx context.Context, svc *accounting.Service, owner *acc
 
 func seedTeamMembers(ctx context.Context, deps seedDeps, owner *account.User, ws *account.Workspace, members []seedTeamMember, workspaceName string) error {
 	for _, m := range members {
-		inv, err := deps.accountSvc.InviteUserToWorkspace(ctx, owner, ws.ID, m.Email, m.Role)
+		inv, err := deps.accountSvc.InviteUserToWorkspace(ctx, owner, ws.ID, &account.InviteUserInput{Email: m.Email, Role: m.Role})
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/analytics"
+	"github.com/abdelrahman146/kyora/internal/server"
+	"github.com/spf13/cobra"
+)
+
+// weeklyDigestCmd emails workspace members a summary of last week's activity.
+// It is meant to be scheduled weekly, early on Monday: members already emailed for the
+// week are skipped, so a failed run can simply be repeated.
+var weeklyDigestCmd = &cobra.Command{
+	Use:   "weekly-digest",
+	Short: "Send the weekly workspace activity digest to members",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		week, _ := cmd.Flags().GetString("week")
+		workspaceID, _ := cmd.Flags().GetString("workspace-id")
+		if week == "" {
+			week = analytics.PreviousDigestWeek(time.Now())
+		}
+
+		svcs, err := server.NewServices(nil)
+		if err != nil {
+			return err
+		}
+		defer svcs.Close()
+
+		// A workspace's businesses all live in its region, so each region sends the
+		// digests of its own workspaces.
+		return forEachRegion(cmd.Context(), svcs, "", func(ctx context.Context, name string) error {
+			result, err := svcs.Analytics.SendWeeklyDigests(ctx, analytics.WeeklyDigestOptions{
+				Week:        week,
+				WorkspaceID: workspaceID,
+			})
+			if err != nil {
+				slog.Error("weekly digests failed", "week", week, "error", err, "region", name)
+				return err
+			}
+			slog.Info("weekly digests completed", "region", name,
+				"week", week, "sent", result.Sent, "skipped", result.Skipped, "failed", result.Failed)
+			return nil
+		})
+	},
+}
+
+func init() {
+	weeklyDigestCmd.Flags().String("week", "", "Monday the week to summarize starts on (YYYY-MM-DD); defaults to last week")
+	weeklyDigestCmd.Flags().String("workspace-id", "", "Limit the run to a single workspace")
+	rootCmd.AddCommand(weeklyDigestCmd)
+}
@@ -34,6 +34,10 @@ func ErrInvitationCannotBeRevoked(err error) *problem.Problem {
 	return problem.Conflict("only pending invitations can be revoked").WithError(err).WithCode("account.invitation_cannot_be_revoked")
 }
 
+func ErrInvitationCannotBeResent(err error) *problem.Problem {
+	return problem.Conflict("only pending invitations can be resent").WithError(err).WithCode("account.invitation_cannot_be_resent")
+}
+
 func ErrCannotUpdateOwnRole(err error) *problem.Problem {
 	return problem.Forbidden("you cannot update your own role").WithError(err).WithCode("account.cannot_update_own_role")
 }
@@ -42,6 +46,74 @@ func ErrCannotUpdateOwnerRole(err error) *problem.Problem {
 	return problem.Forbidden("you cannot update the workspace owner's role").WithError(err).WithCode("account.cannot_update_owner_role")
 }
 
+func ErrCannotUpdateOwnPermissions(err error) *problem.Problem {
+	return problem.Forbidden("you cannot update your own permissions").WithError(err).WithCode("account.cannot_update_own_permissions")
+}
+
+func ErrPermissionFlagsMembersOnly(err error) *problem.Problem {
+	return problem.BadRequest("permission flags only apply to members with the user role").WithError(err).WithCode("account.permission_flags_members_only")
+}
+
+func ErrCustomRoleNotFound(err error) *problem.Problem {
+	return problem.NotFound("custom role not found").WithError(err).WithCode("account.custom_role_not_found")
+}
+
+func ErrCustomRoleNameTaken(err error) *problem.Problem {
+	return problem.Conflict("a custom role with this name already exists").WithError(err).WithCode("account.custom_role_name_taken")
+}
+
+func ErrCustomRoleInUse(err error) *problem.Problem {
+	return problem.Conflict("custom role is assigned to members, pending invitations or SSO provisioning").WithError(err).WithCode("account.custom_role_in_use")
+}
+
+func ErrCustomRoleMembersOnly(err error) *problem.Problem {
+	return problem.BadRequest("custom roles only apply to members with the user role").WithError(err).WithCode("account.custom_role_members_only")
+}
+
+func ErrInvalidPermission(permission string) *problem.Problem {
+	return problem.BadRequest("permission is not part of the permission matrix").With("permission", permission).WithCode("account.invalid_permission")
+}
+
+func ErrSSONotConfigured(err error) *problem.Problem {
+	return problem.NotFound("single sign-on is not configured").WithError(err).WithCode("account.sso_not_configured")
+}
+
+func ErrSSODomainTaken(err error) *problem.Problem {
+	return problem.Conflict("another workspace already signs in this email domain").WithError(err).WithCode("account.sso_domain_taken")
+}
+
+func ErrSSODomainNotVerified(err error) *problem.Problem {
+	return problem.UnprocessableEntity("the single sign-on domain is not verified; publish its verification TXT record, then verify it").WithError(err).WithCode("account.sso_domain_not_verified")
+}
+
+func ErrSSOPublicDomain(domain string) *problem.Problem {
+	return problem.BadRequest("single sign-on cannot be set up for a public email provider's domain").With("domain", domain).WithCode("account.sso_public_domain")
+}
+
+func ErrSSOInvalidConfig(detail string) *problem.Problem {
+	return problem.BadRequest(detail).WithCode("account.sso_invalid_config")
+}
+
+func ErrSSOProtocolNotSupported(protocol SSOProtocol) *problem.Problem {
+	return problem.UnprocessableEntity("sign-in is not available for this single sign-on protocol yet").With("protocol", protocol).WithCode("account.sso_protocol_not_supported")
+}
+
+func ErrSSOLoginFailed(err error) *problem.Problem {
+	return problem.Unauthorized("the identity provider did not sign the user in").WithError(err).WithCode("account.sso_login_failed")
+}
+
+func ErrSSODomainMismatch(err error) *problem.Problem {
+	return problem.Forbidden("the signed-in email is not in the single sign-on domain").WithError(err).WithCode("account.sso_domain_mismatch")
+}
+
+func ErrSSOUserNotProvisioned(err error) *problem.Problem {
+	return problem.Forbidden("no account exists for this user and just-in-time provisioning is off").WithError(err).WithCode("account.sso_user_not_provisioned")
+}
+
+func ErrSSOUserInOtherWorkspace(err error) *problem.Problem {
+	return problem.Forbidden("this user belongs to another workspace").WithError(err).WithCode("account.sso_user_in_other_workspace")
+}
+
 func ErrUserNotInWorkspace(err error) *problem.Problem {
 	return problem.NotFound("user is not a member of this workspace").WithError(err).WithCode("account.user_not_member")
 }
@@ -57,3 +129,11 @@ func ErrInvalidInvitationToken(err error) *problem.Problem {
 func ErrAccountOperationFailed(err error) *problem.Problem {
 	return problem.InternalError().WithError(err).WithCode("account.operation_failed")
 }
+
+func ErrEmailUnchanged(err error) *problem.Problem {
+	return problem.BadRequest("the new email is the same as the current one").WithError(err).WithCode("account.email_unchanged")
+}
+
+func ErrCurrentPasswordInvalid(err error) *problem.Problem {
+	return problem.Forbidden("current password is incorrect").WithError(err).WithCode("account.invalid_current_password")
+}
@@ -6,7 +6,9 @@ import (
 
 	"github.com/abdelrahman146/kyora/internal/platform/request"
 	"github.com/abdelrahman146/kyora/internal/platform/response"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
 	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
 	"github.com/gin-gonic/gin"
 )
 
@@ -220,6 +222,70 @@ func (h *HttpHandler) LogoutOtherDevices(c *gin.Context) {
 	response.SuccessEmpty(c, http.StatusNoContent)
 }
 
+// Magic link endpoints
+
+// RequestMagicLink emails a one-time sign-in link
+//
+// @Summary      Request magic link
+// @Description  Emails a one-time sign-in link to the user. Returns 204 whether or not the email exists.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body magicLinkRequest true "User email"
+// @Success      204
+// @Failure      400 {object} problem.Problem
+// @Failure      429 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/auth/magic-link [post]
+func (h *HttpHandler) RequestMagicLink(c *gin.Context) {
+	var req magicLinkRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+
+	_, err := h.service.RequestMagicLink(c.Request.Context(), req.Email)
+	if err != nil {
+		var p *problem.Problem
+		if errors.As(err, &p) && p.Status == http.StatusTooManyRequests {
+			response.Error(c, err)
+			return
+		}
+		// Return success even if email not found to prevent email enumeration
+		response.SuccessEmpty(c, http.StatusNoContent)
+		return
+	}
+
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// LoginWithMagicLink signs the user in with a magic link token
+//
+// @Summary      Login with magic link
+// @Description  Redeems a one-time sign-in link and returns access and refresh tokens
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body magicLinkLoginRequest true "Magic link token"
+// @Success      200 {object} LoginResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/auth/magic-link/login [post]
+func (h *HttpHandler) LoginWithMagicLink(c *gin.Context) {
+	var req magicLinkLoginRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+
+	loginResp, err := h.service.LoginWithMagicLink(c.Request.Context(), req.Token, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, loginResp)
+}
+
 // Password reset endpoints
 
 // ForgotPassword initiates the password reset process
@@ -346,6 +412,62 @@ func (h *HttpHandler) VerifyEmail(c *gin.Context) {
 	response.SuccessEmpty(c, http.StatusNoContent)
 }
 
+// ConfirmEmailChange applies a pending email change
+//
+// @Summary      Confirm email change
+// @Description  Moves the user's login email to the new address using the token sent to it, and sends the old address a link to undo the change
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body emailChangeTokenRequest true "Email change token"
+// @Success      204
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/auth/email-change/confirm [post]
+func (h *HttpHandler) ConfirmEmailChange(c *gin.Context) {
+	var req emailChangeTokenRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+
+	if err := h.service.ConfirmEmailChange(c.Request.Context(), req.Token); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// UndoEmailChange reverts a confirmed email change
+//
+// @Summary      Undo email change
+// @Description  Puts back the old login email using the token sent to the old address, and signs the user out of every device
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body emailChangeTokenRequest true "Email change undo token"
+// @Success      204
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/auth/email-change/undo [post]
+func (h *HttpHandler) UndoEmailChange(c *gin.Context) {
+	var req emailChangeTokenRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+
+	if err := h.service.UndoEmailChange(c.Request.Context(), req.Token); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
 // User profile endpoints
 
 // GetCurrentUser returns the authenticated user's profile
@@ -425,6 +547,99 @@ func (h *HttpHandler) UpdateCurrentUser(c *gin.Context) {
 	response.SuccessJSON(c, http.StatusOK, ToUserResponse(updatedUser))
 }
 
+// GetNotificationPreferences returns the authenticated user's email notification preferences
+//
+// @Summary      Get notification preferences
+// @Description  Returns the email notifications the currently authenticated user receives
+// @Tags         users
+// @Produce      json
+// @Success      200 {object} NotificationPreferencesResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/users/me/notification-preferences [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetNotificationPreferences(c *gin.Context) {
+	actor, err := ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToNotificationPreferencesResponse(actor))
+}
+
+// UpdateNotificationPreferences updates the authenticated user's email notification preferences
+//
+// @Summary      Update notification preferences
+// @Description  Opts the currently authenticated user in or out of email notifications such as the weekly digest
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        request body UpdateNotificationPreferencesInput true "Notification preferences"
+// @Success      200 {object} NotificationPreferencesResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/users/me/notification-preferences [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdateNotificationPreferences(c *gin.Context) {
+	actor, err := ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var input UpdateNotificationPreferencesInput
+	if err := request.ValidBody(c, &input); err != nil {
+		return
+	}
+
+	user, err := h.service.UpdateNotificationPreferences(c.Request.Context(), actor, &input)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToNotificationPreferencesResponse(user))
+}
+
+// RequestEmailChange starts changing the authenticated user's login email
+//
+// @Summary      Request email change
+// @Description  Sends a confirmation link to the new address; the login email changes once it is confirmed. Users with a password must send it.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        request body RequestEmailChangeInput true "New email"
+// @Success      204
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      429 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/users/me/email [post]
+// @Security     BearerAuth
+func (h *HttpHandler) RequestEmailChange(c *gin.Context) {
+	actor, err := ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var input RequestEmailChangeInput
+	if err := request.ValidBody(c, &input); err != nil {
+		return
+	}
+
+	if _, err := h.service.RequestEmailChange(c.Request.Context(), actor, &input); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
 // Workspace endpoints
 
 // GetCurrentWorkspace returns the authenticated user's workspace
@@ -455,13 +670,21 @@ func (h *HttpHandler) GetCurrentWorkspace(c *gin.Context) {
 	response.SuccessJSON(c, http.StatusOK, ToWorkspaceResponse(workspace))
 }
 
-// GetWorkspaceUsers returns all users in the workspace
+// GetWorkspaceUsers returns a paginated list of users in the workspace
 //
 // @Summary      Get workspace users
-// @Description  Returns all users that belong to the workspace
+// @Description  Returns a paginated list of users that belong to the workspace. Supports filtering by role and email verification status, and searching by name or email.
 // @Tags         workspaces
 // @Produce      json
-// @Success      200 {array} UserResponse
+// @Param        page query int false "Page number (default: 1)"
+// @Param        pageSize query int false "Page size (default: 20, max: 100)"
+// @Param        orderBy query []string false "Sort order (e.g., createdAt, -email, firstName)"
+// @Param        search query string false "Search term (matches first name, last name, or email)"
+// @Param        role query []string false "Filter by role (repeatable: user, admin)"
+// @Param        status query string false "Filter by email verification status (verified, unverified)"
+// @Param        customRoleId query string false "Filter by custom role ID"
+// @Success      200 {object} list.ListResponse[UserResponse]
+// @Failure      400 {object} problem.Problem
 // @Failure      401 {object} problem.Problem
 // @Failure      403 {object} problem.Problem
 // @Failure      500 {object} problem.Problem
@@ -474,17 +697,45 @@ func (h *HttpHandler) GetWorkspaceUsers(c *gin.Context) {
 		return
 	}
 
-	users, err := h.service.storage.user.FindMany(
-		c.Request.Context(),
-		h.service.storage.user.ScopeWorkspaceID(actor.WorkspaceID),
-		h.service.storage.user.WithOrderBy([]string{UserSchema.CreatedAt.Column() + " ASC"}),
-	)
+	var query listWorkspaceUsersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+	if query.SearchTerm != "" {
+		term, err := list.NormalizeSearchTerm(query.SearchTerm)
+		if err != nil {
+			response.Error(c, problem.BadRequest("invalid search term"))
+			return
+		}
+		query.SearchTerm = term
+	}
+	listReq := list.NewListRequest(query.Page, query.PageSize, query.OrderBy, query.SearchTerm)
+
+	filters := &ListWorkspaceUsersFilters{}
+	for _, r := range query.Role {
+		filters.Roles = append(filters.Roles, role.Role(r))
+	}
+	if query.Status != "" {
+		verified := query.Status == "verified"
+		filters.EmailVerified = &verified
+	}
+	filters.CustomRoleID = query.CustomRoleID
+
+	users, total, err := h.service.ListWorkspaceUsers(c.Request.Context(), actor.WorkspaceID, listReq, filters)
 	if err != nil {
-		response.Error(c, problem.InternalError().WithError(err))
+		response.Error(c, err)
 		return
 	}
 
-	response.SuccessJSON(c, http.StatusOK, ToUserResponses(users))
+	hasMore := int64(query.Page*query.PageSize) < total
+	response.SuccessJSON(c, http.StatusOK, list.NewListResponse(ToUserResponses(users), query.Page, query.PageSize, total, hasMore))
 }
 
 // GetWorkspaceUser returns a specific user in the workspace
@@ -553,7 +804,7 @@ func (h *HttpHandler) InviteUserToWorkspace(c *gin.Context) {
 		return
 	}
 
-	invitation, err := h.service.InviteUserToWorkspace(c.Request.Context(), actor, actor.WorkspaceID, input.Email, input.Role)
+	invitation, err := h.service.InviteUserToWorkspace(c.Request.Context(), actor, actor.WorkspaceID, &input)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -562,6 +813,42 @@ func (h *HttpHandler) InviteUserToWorkspace(c *gin.Context) {
 	response.SuccessJSON(c, http.StatusCreated, ToUserInvitationResponse(invitation))
 }
 
+// BulkInviteUsers invites several users to the workspace at once
+//
+// @Summary      Bulk invite users
+// @Description  Invites up to 50 users in one request. Each row is invited on its own and reported as invited or failed, so one bad row does not stop the others.
+// @Tags         invitations
+// @Accept       json
+// @Produce      json
+// @Param        request body BulkInviteInput true "Invitations"
+// @Success      200 {object} BulkInviteResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/invitations/bulk [post]
+// @Security     BearerAuth
+func (h *HttpHandler) BulkInviteUsers(c *gin.Context) {
+	actor, err := ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var input BulkInviteInput
+	if err := request.ValidBody(c, &input); err != nil {
+		return
+	}
+
+	results, err := h.service.BulkInviteUsers(c.Request.Context(), actor, actor.WorkspaceID, &input)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToBulkInviteResponse(results))
+}
+
 // GetWorkspaceInvitations returns all invitations for the workspace
 //
 // @Summary      Get workspace invitations
@@ -634,6 +921,54 @@ func (h *HttpHandler) RevokeInvitation(c *gin.Context) {
 	response.SuccessEmpty(c, http.StatusNoContent)
 }
 
+// ResendInvitation resends a pending invitation with a new link
+//
+// @Summary      Resend invitation
+// @Description  Emails a pending invitation again with a new link and expiry; earlier links stop working
+// @Tags         invitations
+// @Accept       json
+// @Produce      json
+// @Param        invitationId path string true "Invitation ID"
+// @Param        request body ResendInvitationInput false "Resend options"
+// @Success      200 {object} UserInvitationResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      429 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/invitations/{invitationId}/resend [post]
+// @Security     BearerAuth
+func (h *HttpHandler) ResendInvitation(c *gin.Context) {
+	actor, err := ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	invitationID := c.Param("invitationId")
+	if invitationID == "" {
+		response.Error(c, problem.BadRequest("invitationId is required"))
+		return
+	}
+
+	var input ResendInvitationInput
+	if c.Request.ContentLength != 0 {
+		if err := request.ValidBody(c, &input); err != nil {
+			return
+		}
+	}
+
+	invitation, err := h.service.ResendInvitation(c.Request.Context(), actor, actor.WorkspaceID, invitationID, &input)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToUserInvitationResponse(invitation))
+}
+
 // AcceptInvitation accepts a workspace invitation (public endpoint)
 //
 // @Summary      Accept invitation
@@ -742,7 +1077,7 @@ func (h *HttpHandler) AcceptInvitationWithGoogle(c *gin.Context) {
 // UpdateUserRole updates a user's role within the workspace
 //
 // @Summary      Update user role
-// @Description  Updates the role of a user within the workspace
+// @Description  Updates the role of a user within the workspace; members (role user) may also be given a custom role of the workspace
 // @Tags         workspaces
 // @Accept       json
 // @Produce      json
@@ -781,7 +1116,7 @@ func (h *HttpHandler) UpdateUserRole(c *gin.Context) {
 		return
 	}
 
-	updatedUser, err := h.service.UpdateUserRole(c.Request.Context(), actor, workspace, userID, input.Role)
+	updatedUser, err := h.service.UpdateUserRole(c.Request.Context(), actor, workspace, userID, input.Role, input.CustomRoleID)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -790,22 +1125,24 @@ func (h *HttpHandler) UpdateUserRole(c *gin.Context) {
 	response.SuccessJSON(c, http.StatusOK, ToUserResponse(updatedUser))
 }
 
-// RemoveUserFromWorkspace removes a user from the workspace
+// UpdateUserPermissions updates a member's permission flags within the workspace
 //
-// @Summary      Remove user from workspace
-// @Description  Removes a user from the workspace (soft delete)
+// @Summary      Update user permissions
+// @Description  Sets a member's permission flags: canManageOperations lets them manage orders and inventory, restrictFinancials hides costs, profit, expenses and accounting from them
 // @Tags         workspaces
+// @Accept       json
 // @Produce      json
 // @Param        userId path string true "User ID"
-// @Success      204
+// @Param        request body UpdateUserPermissionsInput true "Permission flags"
+// @Success      200 {object} UserResponse
 // @Failure      400 {object} problem.Problem
 // @Failure      401 {object} problem.Problem
 // @Failure      403 {object} problem.Problem
 // @Failure      404 {object} problem.Problem
 // @Failure      500 {object} problem.Problem
-// @Router       /v1/workspaces/users/{userId} [delete]
+// @Router       /v1/workspaces/users/{userId}/permissions [patch]
 // @Security     BearerAuth
-func (h *HttpHandler) RemoveUserFromWorkspace(c *gin.Context) {
+func (h *HttpHandler) UpdateUserPermissions(c *gin.Context) {
 	actor, err := ActorFromContext(c)
 	if err != nil {
 		response.Error(c, err)
@@ -818,35 +1155,485 @@ func (h *HttpHandler) RemoveUserFromWorkspace(c *gin.Context) {
 		return
 	}
 
-	// Prevent user from removing themselves
-	if actor.ID == userID {
-		response.Error(c, problem.Forbidden("you cannot remove yourself from the workspace"))
+	var input UpdateUserPermissionsInput
+	if err := request.ValidBody(c, &input); err != nil {
 		return
 	}
 
-	// Get target user scoped to the workspace (prevents ID probing).
-	targetUser, err := h.service.GetWorkspaceUserByID(c.Request.Context(), actor.WorkspaceID, userID)
+	workspace, err := h.service.GetWorkspaceByID(c.Request.Context(), actor.WorkspaceID)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	// Get workspace
-	workspace, err := h.service.GetWorkspaceByID(c.Request.Context(), actor.WorkspaceID)
+	updatedUser, err := h.service.UpdateUserPermissions(c.Request.Context(), actor, workspace, userID, &input)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	// Prevent removing the workspace owner
-	if targetUser.ID == workspace.OwnerID {
-		response.Error(c, problem.Forbidden("you cannot remove the workspace owner"))
-		return
-	}
+	response.SuccessJSON(c, http.StatusOK, ToUserResponse(updatedUser))
+}
 
-	// Soft delete the user
-	if err := h.service.storage.user.DeleteOne(c.Request.Context(), targetUser); err != nil {
-		response.Error(c, problem.InternalError().WithError(err))
+// RemoveUserFromWorkspace removes a user from the workspace
+//
+// @Summary      Remove user from workspace
+// @Description  Removes a user from the workspace (soft delete)
+// @Tags         workspaces
+// @Produce      json
+// @Param        userId path string true "User ID"
+// @Success      204
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/workspaces/users/{userId} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) RemoveUserFromWorkspace(c *gin.Context) {
+	actor, err := ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	userID := c.Param("userId")
+	if userID == "" {
+		response.Error(c, problem.BadRequest("userId is required"))
+		return
+	}
+
+	// Prevent user from removing themselves
+	if actor.ID == userID {
+		response.Error(c, problem.Forbidden("you cannot remove yourself from the workspace"))
+		return
+	}
+
+	// Get target user scoped to the workspace (prevents ID probing).
+	targetUser, err := h.service.GetWorkspaceUserByID(c.Request.Context(), actor.WorkspaceID, userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// Get workspace
+	workspace, err := h.service.GetWorkspaceByID(c.Request.Context(), actor.WorkspaceID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// Prevent removing the workspace owner
+	if targetUser.ID == workspace.OwnerID {
+		response.Error(c, problem.Forbidden("you cannot remove the workspace owner"))
+		return
+	}
+
+	// Soft delete the user
+	if err := h.service.storage.user.DeleteOne(c.Request.Context(), targetUser); err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// Custom role endpoints
+
+// GetPermissionMatrix returns the permissions custom roles can be defined from
+//
+// @Summary      Get permission matrix
+// @Description  Returns, per domain, the actions (view, manage, approve) a custom role can be granted, and the permissions of the built-in admin and user roles
+// @Tags         workspaces
+// @Produce      json
+// @Success      200 {object} PermissionMatrixResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Router       /v1/workspaces/roles/permissions [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetPermissionMatrix(c *gin.Context) {
+	response.SuccessJSON(c, http.StatusOK, ToPermissionMatrixResponse())
+}
+
+// ListCustomRoles returns the custom roles of the workspace
+//
+// @Summary      List custom roles
+// @Description  Returns the custom roles defined for the workspace, ordered by name
+// @Tags         workspaces
+// @Produce      json
+// @Success      200 {array} CustomRoleResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/workspaces/roles [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListCustomRoles(c *gin.Context) {
+	actor, err := ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	customRoles, err := h.service.ListCustomRoles(c.Request.Context(), actor.WorkspaceID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToCustomRoleResponses(customRoles))
+}
+
+// GetCustomRole returns a custom role of the workspace
+//
+// @Summary      Get custom role
+// @Description  Returns a custom role of the workspace by ID
+// @Tags         workspaces
+// @Produce      json
+// @Param        roleId path string true "Custom role ID"
+// @Success      200 {object} CustomRoleResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/workspaces/roles/{roleId} [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetCustomRole(c *gin.Context) {
+	actor, err := ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	customRole, err := h.service.GetCustomRoleByID(c.Request.Context(), actor.WorkspaceID, c.Param("roleId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToCustomRoleResponse(customRole))
+}
+
+// CreateCustomRole defines a custom role for the workspace
+//
+// @Summary      Create custom role
+// @Description  Defines a custom role from the permission matrix; members assigned to it get exactly its permissions, with their permission flags applied on top
+// @Tags         workspaces
+// @Accept       json
+// @Produce      json
+// @Param        request body CreateCustomRoleInput true "Custom role"
+// @Success      201 {object} CustomRoleResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/workspaces/roles [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CreateCustomRole(c *gin.Context) {
+	actor, err := ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var input CreateCustomRoleInput
+	if err := request.ValidBody(c, &input); err != nil {
+		return
+	}
+
+	workspace, err := h.service.GetWorkspaceByID(c.Request.Context(), actor.WorkspaceID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	customRole, err := h.service.CreateCustomRole(c.Request.Context(), actor, workspace, &input)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusCreated, ToCustomRoleResponse(customRole))
+}
+
+// UpdateCustomRole updates a custom role of the workspace
+//
+// @Summary      Update custom role
+// @Description  Updates the name, description or permissions of a custom role; its members get the new permissions on their next request
+// @Tags         workspaces
+// @Accept       json
+// @Produce      json
+// @Param        roleId path string true "Custom role ID"
+// @Param        request body UpdateCustomRoleInput true "Custom role fields"
+// @Success      200 {object} CustomRoleResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/workspaces/roles/{roleId} [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdateCustomRole(c *gin.Context) {
+	actor, err := ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var input UpdateCustomRoleInput
+	if err := request.ValidBody(c, &input); err != nil {
+		return
+	}
+
+	workspace, err := h.service.GetWorkspaceByID(c.Request.Context(), actor.WorkspaceID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	customRole, err := h.service.UpdateCustomRole(c.Request.Context(), actor, workspace, c.Param("roleId"), &input)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToCustomRoleResponse(customRole))
+}
+
+// DeleteCustomRole deletes a custom role of the workspace
+//
+// @Summary      Delete custom role
+// @Description  Deletes a custom role no member, pending invitation or SSO provisioning rule has
+// @Tags         workspaces
+// @Param        roleId path string true "Custom role ID"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/workspaces/roles/{roleId} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteCustomRole(c *gin.Context) {
+	actor, err := ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	workspace, err := h.service.GetWorkspaceByID(c.Request.Context(), actor.WorkspaceID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.service.DeleteCustomRole(c.Request.Context(), actor, workspace, c.Param("roleId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// StartSSOLogin starts signing in through the identity provider of the user's workspace
+//
+// @Summary      Start SSO login
+// @Description  Finds the workspace that set up single sign-on for the email's domain and returns the identity provider URL to send the user to. The provider sends the user back to the SSO redirect URL with a code and this state.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body ssoStartRequest true "Email of the user signing in"
+// @Success      200 {object} SSOStartResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      422 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/auth/sso/start [post]
+func (h *HttpHandler) StartSSOLogin(c *gin.Context) {
+	var req ssoStartRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+
+	url, state, err := h.service.StartSSOLogin(c.Request.Context(), req.Email)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, SSOStartResponse{URL: url, State: state})
+}
+
+// CompleteSSOLogin signs in the user the identity provider authenticated
+//
+// @Summary      Complete SSO login
+// @Description  Exchanges the code the identity provider sent back for the user's tokens. Users signing in for the first time are provisioned when the workspace allows it.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body ssoCallbackRequest true "State and code sent back by the identity provider"
+// @Success      200 {object} LoginResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/auth/sso/callback [post]
+func (h *HttpHandler) CompleteSSOLogin(c *gin.Context) {
+	var req ssoCallbackRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+
+	loginResp, err := h.service.CompleteSSOLogin(c.Request.Context(), req.State, req.Code, c.ClientIP(), c.GetHeader("User-Agent"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, loginResp)
+}
+
+// GetSSOConnection returns the single sign-on setup of the workspace
+//
+// @Summary      Get SSO connection
+// @Description  Returns the workspace's single sign-on setup, without the OIDC client secret
+// @Tags         workspaces
+// @Produce      json
+// @Success      200 {object} SSOConnectionResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/workspaces/sso [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetSSOConnection(c *gin.Context) {
+	actor, err := ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	conn, err := h.service.GetSSOConnection(c.Request.Context(), actor.WorkspaceID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToSSOConnectionResponse(conn))
+}
+
+// PutSSOConnection sets up single sign-on for the workspace
+//
+// @Summary      Set SSO connection
+// @Description  Creates or replaces the workspace's single sign-on setup: the OIDC identity provider, the email domain it signs in, and how first-time users are provisioned
+// @Tags         workspaces
+// @Accept       json
+// @Produce      json
+// @Param        request body PutSSOConnectionInput true "SSO setup"
+// @Success      200 {object} SSOConnectionResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      422 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/workspaces/sso [put]
+// @Security     BearerAuth
+func (h *HttpHandler) PutSSOConnection(c *gin.Context) {
+	actor, err := ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var input PutSSOConnectionInput
+	if err := request.ValidBody(c, &input); err != nil {
+		return
+	}
+
+	workspace, err := h.service.GetWorkspaceByID(c.Request.Context(), actor.WorkspaceID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	conn, err := h.service.PutSSOConnection(c.Request.Context(), actor, workspace, &input)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToSSOConnectionResponse(conn))
+}
+
+// VerifySSODomain verifies that the workspace owns the domain of its single sign-on setup
+//
+// @Summary      Verify SSO domain
+// @Description  Looks up the DNS TXT record named verificationRecordName and marks the SSO domain verified when it holds verificationRecordValue; the connection can only be enabled once its domain is verified
+// @Tags         workspaces
+// @Produce      json
+// @Success      200 {object} SSOConnectionResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      422 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/workspaces/sso/verify-domain [post]
+// @Security     BearerAuth
+func (h *HttpHandler) VerifySSODomain(c *gin.Context) {
+	actor, err := ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	workspace, err := h.service.GetWorkspaceByID(c.Request.Context(), actor.WorkspaceID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	conn, err := h.service.VerifySSODomain(c.Request.Context(), actor, workspace)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToSSOConnectionResponse(conn))
+}
+
+// DeleteSSOConnection removes the single sign-on setup of the workspace
+//
+// @Summary      Delete SSO connection
+// @Description  Removes the workspace's single sign-on setup; users it provisioned keep their accounts
+// @Tags         workspaces
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/workspaces/sso [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteSSOConnection(c *gin.Context) {
+	actor, err := ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	workspace, err := h.service.GetWorkspaceByID(c.Request.Context(), actor.WorkspaceID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.service.DeleteSSOConnection(c.Request.Context(), actor, workspace); err != nil {
+		response.Error(c, err)
 		return
 	}
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/abdelrahman146/kyora/internal/platform/auth"
 	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/region"
 	"github.com/abdelrahman146/kyora/internal/platform/response"
 	"github.com/abdelrahman146/kyora/internal/platform/types/ctxkey"
 	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
@@ -20,8 +21,7 @@ func EnforceActorPermissions(action role.Action, resource role.Resource) gin.Han
 			response.Error(c, err)
 			return
 		}
-		role := user.Role
-		if err := role.HasPermission(action, resource); err != nil {
+		if err := user.HasPermission(action, resource); err != nil {
 			response.Error(c, err)
 			return
 		}
@@ -90,6 +90,8 @@ func EnforceWorkspaceMembership(service *Service) gin.HandlerFunc {
 		l := logger.FromContext(c.Request.Context())
 		l.With("workspaceID", workspace.ID)
 		ctx := logger.WithContext(c.Request.Context(), l)
+		// Business data of the workspace is read from and written to its region.
+		ctx = region.WithRegion(ctx, workspace.Region)
 		c.Request = c.Request.WithContext(ctx)
 		c.Set(WorkspaceKey, workspace)
 		c.Next()
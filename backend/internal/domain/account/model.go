@@ -2,8 +2,13 @@ package account
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"slices"
 	"time"
 
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
 	"github.com/abdelrahman146/kyora/internal/platform/types/role"
 	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
 	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
@@ -26,7 +31,10 @@ type Workspace struct {
 	OwnerID               string         `gorm:"column:owner_id;type:text" json:"ownerId"`
 	StripeCustomerID      sql.NullString `gorm:"column:stripe_customer_id;type:text;unique" json:"stripeCustomerId"`
 	StripePaymentMethodID sql.NullString `gorm:"column:stripe_payment_method_id;type:text" json:"stripePaymentMethodId"`
-	Users                 []User         `gorm:"foreignKey:WorkspaceID;references:ID" json:"users,omitempty"`
+	// Region is the data residency region the workspace's business data lives in. Empty
+	// for workspaces created before regions existed, which live in the home region.
+	Region string `gorm:"column:region;type:text" json:"region"`
+	Users  []User `gorm:"foreignKey:WorkspaceID;references:ID" json:"users,omitempty"`
 }
 
 func (m *Workspace) TableName() string {
@@ -81,6 +89,64 @@ type User struct {
 	Password        string     `gorm:"column:password;type:text" json:"-"`
 	IsEmailVerified bool       `gorm:"column:is_email_verified;type:boolean;default:false" json:"isEmailVerified"`
 	AuthVersion     int        `gorm:"column:auth_version;type:int;default:1" json:"-"`
+	// Permission flags adjust a member's role (see role.Flag); they have no effect on admins.
+	CanManageOperations bool `gorm:"column:can_manage_operations;type:boolean;default:false" json:"canManageOperations"`
+	RestrictFinancials  bool `gorm:"column:restrict_financials;type:boolean;default:false" json:"restrictFinancials"`
+	// CustomRoleID assigns a member a custom role of the workspace, whose permissions replace
+	// those of the user role. Admins never have one.
+	CustomRoleID sql.NullString `gorm:"column:custom_role_id;type:text;index" json:"customRoleId"`
+	CustomRole   *CustomRole    `gorm:"foreignKey:CustomRoleID;references:ID" json:"customRole,omitempty"`
+	// Notification preferences: emails are on unless the user opts out.
+	WeeklyDigestOptOut bool `gorm:"column:weekly_digest_opt_out;type:boolean;default:false" json:"weeklyDigestOptOut"`
+}
+
+// PermissionFlags returns the role flags enabled for the user.
+func (m *User) PermissionFlags() []role.Flag {
+	var flags []role.Flag
+	if m.CanManageOperations {
+		flags = append(flags, role.FlagManageOperations)
+	}
+	if m.RestrictFinancials {
+		flags = append(flags, role.FlagRestrictFinancials)
+	}
+	return flags
+}
+
+// Permissions returns the permissions of the user's role, or of their custom role when they
+// have one. Flags are not applied. A custom role that was not preloaded grants nothing.
+func (m *User) Permissions() []string {
+	if m.Role == role.RoleUser && m.CustomRoleID.Valid {
+		if m.CustomRole == nil {
+			return nil
+		}
+		return m.CustomRole.Permissions
+	}
+	return role.RolePermissions[m.Role]
+}
+
+// EffectivePermissions lists, sorted, the permissions of the permission matrix HasPermission
+// grants the user once flags are applied.
+func (m *User) EffectivePermissions() []string {
+	var permissions []string
+	for resource, actions := range role.PermissionMatrix {
+		for _, action := range actions {
+			if m.HasPermission(action, resource) == nil {
+				permissions = append(permissions, role.Permission(action, resource))
+			}
+		}
+	}
+	slices.Sort(permissions)
+	return permissions
+}
+
+// HasPermission is the authorization check for a user: it checks a permission against the
+// user's role or custom role and their permission flags. Handlers and services must go
+// through it rather than test roles themselves.
+func (m *User) HasPermission(action role.Action, resource role.Resource) error {
+	if m.Role != role.RoleUser {
+		return m.Role.HasPermission(action, resource)
+	}
+	return role.Authorize(m.Permissions(), m.PermissionFlags(), action, resource)
 }
 
 /* Session Model */
@@ -160,27 +226,31 @@ func (m *User) BeforeCreate(tx *gorm.DB) (err error) {
 // User request DTOs are defined in model_request.go
 
 var UserSchema = struct {
-	ID              schema.Field
-	WorkspaceID     schema.Field
-	Role            schema.Field
-	FirstName       schema.Field
-	LastName        schema.Field
-	Email           schema.Field
-	IsEmailVerified schema.Field
-	CreatedAt       schema.Field
-	UpdatedAt       schema.Field
-	DeletedAt       schema.Field
+	ID                 schema.Field
+	WorkspaceID        schema.Field
+	Role               schema.Field
+	FirstName          schema.Field
+	LastName           schema.Field
+	Email              schema.Field
+	IsEmailVerified    schema.Field
+	CustomRoleID       schema.Field
+	WeeklyDigestOptOut schema.Field
+	CreatedAt          schema.Field
+	UpdatedAt          schema.Field
+	DeletedAt          schema.Field
 }{
-	CreatedAt:       schema.NewField("created_at", "createdAt"),
-	UpdatedAt:       schema.NewField("updated_at", "updatedAt"),
-	DeletedAt:       schema.NewField("deleted_at", "deletedAt"),
-	ID:              schema.NewField("id", "id"),
-	WorkspaceID:     schema.NewField("workspace_id", "workspaceId"),
-	Role:            schema.NewField("role", "role"),
-	FirstName:       schema.NewField("first_name", "firstName"),
-	LastName:        schema.NewField("last_name", "lastName"),
-	Email:           schema.NewField("email", "email"),
-	IsEmailVerified: schema.NewField("is_email_verified", "isEmailVerified"),
+	CreatedAt:          schema.NewField("created_at", "createdAt"),
+	UpdatedAt:          schema.NewField("updated_at", "updatedAt"),
+	DeletedAt:          schema.NewField("deleted_at", "deletedAt"),
+	ID:                 schema.NewField("id", "id"),
+	WorkspaceID:        schema.NewField("workspace_id", "workspaceId"),
+	Role:               schema.NewField("role", "role"),
+	FirstName:          schema.NewField("first_name", "firstName"),
+	LastName:           schema.NewField("last_name", "lastName"),
+	Email:              schema.NewField("email", "email"),
+	IsEmailVerified:    schema.NewField("is_email_verified", "isEmailVerified"),
+	CustomRoleID:       schema.NewField("custom_role_id", "customRoleId"),
+	WeeklyDigestOptOut: schema.NewField("weekly_digest_opt_out", "weeklyDigestOptOut"),
 }
 
 /* User Invitation Model */
@@ -213,6 +283,12 @@ type UserInvitation struct {
 	Inviter     *User            `gorm:"foreignKey:InviterID;references:ID" json:"inviter,omitempty"`
 	Status      InvitationStatus `gorm:"column:status;type:text;default:'pending'" json:"status"`
 	AcceptedAt  *gorm.DeletedAt  `gorm:"column:accepted_at;type:timestamp with time zone" json:"acceptedAt,omitempty"`
+	// CustomRoleID is the custom role the invitee gets on accepting (user role only).
+	CustomRoleID sql.NullString `gorm:"column:custom_role_id;type:text" json:"customRoleId"`
+	// ExpiresAt is when the invitation link stops working.
+	ExpiresAt *time.Time `gorm:"column:expires_at;type:timestamp with time zone" json:"expiresAt,omitempty"`
+	// TokenVersion is bumped on every resend; links carrying an older version are refused.
+	TokenVersion int `gorm:"column:token_version;type:integer;not null;default:0" json:"-"`
 }
 
 func (m *UserInvitation) TableName() string {
@@ -229,25 +305,243 @@ func (m *UserInvitation) BeforeCreate(tx *gorm.DB) (err error) {
 // Invitation request DTOs are defined in model_request.go
 
 var UserInvitationSchema = struct {
+	ID           schema.Field
+	WorkspaceID  schema.Field
+	Email        schema.Field
+	Role         schema.Field
+	InviterID    schema.Field
+	CustomRoleID schema.Field
+	Status       schema.Field
+	AcceptedAt   schema.Field
+	ExpiresAt    schema.Field
+	CreatedAt    schema.Field
+	UpdatedAt    schema.Field
+	DeletedAt    schema.Field
+}{
+	ID:           schema.NewField("id", "id"),
+	WorkspaceID:  schema.NewField("workspace_id", "workspaceId"),
+	Email:        schema.NewField("email", "email"),
+	Role:         schema.NewField("role", "role"),
+	InviterID:    schema.NewField("inviter_id", "inviterId"),
+	CustomRoleID: schema.NewField("custom_role_id", "customRoleId"),
+	Status:       schema.NewField("status", "status"),
+	AcceptedAt:   schema.NewField("accepted_at", "acceptedAt"),
+	ExpiresAt:    schema.NewField("expires_at", "expiresAt"),
+	CreatedAt:    schema.NewField("created_at", "createdAt"),
+	UpdatedAt:    schema.NewField("updated_at", "updatedAt"),
+	DeletedAt:    schema.NewField("deleted_at", "deletedAt"),
+}
+
+/* Custom Role Model */
+//-------------------*/
+
+const (
+	CustomRoleTable  = "custom_roles"
+	CustomRoleStruct = "CustomRole"
+	CustomRolePrefix = "crl"
+)
+
+// PermissionList is a set of "action:resource" permissions (see role.PermissionMatrix).
+type PermissionList []string
+
+func (l PermissionList) Value() (driver.Value, error) {
+	if l == nil {
+		l = PermissionList{}
+	}
+	b, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (l *PermissionList) Scan(value any) error {
+	if l == nil {
+		return problem.InternalError().WithError(errors.New("PermissionList scan into nil receiver"))
+	}
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*l = PermissionList{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return problem.InternalError().WithError(errors.New("unexpected scan type for PermissionList"))
+	}
+	return json.Unmarshal(raw, (*[]string)(l))
+}
+
+// CustomRole is a role a workspace defines from the permission matrix, e.g. a cashier who may
+// manage orders but not inventory. Members assigned to it get exactly its permissions, with
+// their permission flags applied on top.
+type CustomRole struct {
+	gorm.Model
+	ID          string         `gorm:"column:id;primaryKey;type:text" json:"id"`
+	WorkspaceID string         `gorm:"column:workspace_id;type:text;not null;index" json:"workspaceId"`
+	Name        string         `gorm:"column:name;type:text;not null" json:"name"`
+	Description string         `gorm:"column:description;type:text" json:"description"`
+	Permissions PermissionList `gorm:"column:permissions;type:jsonb;not null;default:'[]'" json:"permissions"`
+}
+
+func (m *CustomRole) TableName() string {
+	return CustomRoleTable
+}
+
+func (m *CustomRole) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(CustomRolePrefix)
+	}
+	return nil
+}
+
+// Custom role request DTOs are defined in model_request.go
+
+var CustomRoleSchema = struct {
 	ID          schema.Field
 	WorkspaceID schema.Field
-	Email       schema.Field
-	Role        schema.Field
-	InviterID   schema.Field
-	Status      schema.Field
-	AcceptedAt  schema.Field
+	Name        schema.Field
 	CreatedAt   schema.Field
 	UpdatedAt   schema.Field
 	DeletedAt   schema.Field
 }{
 	ID:          schema.NewField("id", "id"),
 	WorkspaceID: schema.NewField("workspace_id", "workspaceId"),
-	Email:       schema.NewField("email", "email"),
-	Role:        schema.NewField("role", "role"),
-	InviterID:   schema.NewField("inviter_id", "inviterId"),
-	Status:      schema.NewField("status", "status"),
-	AcceptedAt:  schema.NewField("accepted_at", "acceptedAt"),
+	Name:        schema.NewField("name", "name"),
 	CreatedAt:   schema.NewField("created_at", "createdAt"),
 	UpdatedAt:   schema.NewField("updated_at", "updatedAt"),
 	DeletedAt:   schema.NewField("deleted_at", "deletedAt"),
 }
+
+/* SSO Connection Model */
+//-----------------------*/
+
+const (
+	SSOConnectionTable  = "sso_connections"
+	SSOConnectionStruct = "SSOConnection"
+	SSOConnectionPrefix = "sso"
+)
+
+// SSOProtocol is how a workspace's identity provider signs its users in.
+type SSOProtocol string
+
+const (
+	SSOProtocolOIDC SSOProtocol = "oidc"
+	SSOProtocolSAML SSOProtocol = "saml"
+)
+
+// SSORoleMapping gives users the identity provider puts in Group a role when they are
+// provisioned on their first sign-in.
+type SSORoleMapping struct {
+	Group        string    `json:"group"`
+	Role         role.Role `json:"role"`
+	CustomRoleID string    `json:"customRoleId,omitempty"`
+}
+
+// SSORoleMappings are the role mappings of an SSO connection, first match first.
+type SSORoleMappings []SSORoleMapping
+
+func (l SSORoleMappings) Value() (driver.Value, error) {
+	if l == nil {
+		l = SSORoleMappings{}
+	}
+	b, err := json.Marshal([]SSORoleMapping(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (l *SSORoleMappings) Scan(value any) error {
+	if l == nil {
+		return problem.InternalError().WithError(errors.New("SSORoleMappings scan into nil receiver"))
+	}
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*l = SSORoleMappings{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return problem.InternalError().WithError(errors.New("unexpected scan type for SSORoleMappings"))
+	}
+	return json.Unmarshal(raw, (*[]SSORoleMapping)(l))
+}
+
+// SSOConnection is a workspace's enterprise single sign-on setup: users whose email is in
+// Domain sign in through the workspace's identity provider. With JITProvisioning, users the
+// provider signs in for the first time join the workspace with the role of the first mapping
+// whose group they are in, or DefaultRole.
+//
+// A workspace has at most one connection; it is deleted outright so its domain can be
+// claimed again. Workspaces prove they own Domain by publishing VerificationToken in a DNS
+// TXT record; until then the connection cannot be enabled, and several workspaces may claim
+// the same domain, but only one may verify it.
+type SSOConnection struct {
+	ID          string      `gorm:"column:id;primaryKey;type:text" json:"id"`
+	WorkspaceID string      `gorm:"column:workspace_id;type:text;not null;uniqueIndex" json:"workspaceId"`
+	Protocol    SSOProtocol `gorm:"column:protocol;type:text;not null" json:"protocol"`
+	Domain      string      `gorm:"column:domain;type:text;not null;index:idx_sso_connections_claimed_domain;uniqueIndex:idx_sso_connections_verified_domain,where:verified_at IS NOT NULL" json:"domain"`
+	Enabled     bool        `gorm:"column:enabled;type:boolean;not null;default:false" json:"enabled"`
+	// Domain ownership proof; the token changes whenever the domain does
+	VerificationToken string       `gorm:"column:verification_token;type:text;not null;default:''" json:"-"`
+	VerifiedAt        sql.NullTime `gorm:"column:verified_at" json:"verifiedAt"`
+	// OIDC client registration; the secret is never returned
+	OIDCIssuer       string `gorm:"column:oidc_issuer;type:text" json:"oidcIssuer"`
+	OIDCClientID     string `gorm:"column:oidc_client_id;type:text" json:"oidcClientId"`
+	OIDCClientSecret string `gorm:"column:oidc_client_secret;type:text" json:"-"`
+	// Just-in-time provisioning
+	JITProvisioning     bool            `gorm:"column:jit_provisioning;type:boolean;not null;default:false" json:"jitProvisioning"`
+	GroupsClaim         string          `gorm:"column:groups_claim;type:text" json:"groupsClaim"`
+	DefaultRole         role.Role       `gorm:"column:default_role;type:text;not null;default:'user'" json:"defaultRole"`
+	DefaultCustomRoleID sql.NullString  `gorm:"column:default_custom_role_id;type:text" json:"defaultCustomRoleId"`
+	RoleMappings        SSORoleMappings `gorm:"column:role_mappings;type:jsonb;not null;default:'[]'" json:"roleMappings"`
+	CreatedAt           time.Time       `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+	UpdatedAt           time.Time       `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
+}
+
+func (m *SSOConnection) TableName() string {
+	return SSOConnectionTable
+}
+
+func (m *SSOConnection) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(SSOConnectionPrefix)
+	}
+	return nil
+}
+
+// provisionedRole returns the role and custom role a user in groups is provisioned with.
+func (m *SSOConnection) provisionedRole(groups []string) (role.Role, sql.NullString) {
+	for _, mapping := range m.RoleMappings {
+		if slices.Contains(groups, mapping.Group) {
+			customRoleID := sql.NullString{}
+			if mapping.CustomRoleID != "" {
+				customRoleID = sql.NullString{String: mapping.CustomRoleID, Valid: true}
+			}
+			return mapping.Role, customRoleID
+		}
+	}
+	return m.DefaultRole, m.DefaultCustomRoleID
+}
+
+// SSO connection request DTOs are defined in model_request.go
+
+var SSOConnectionSchema = struct {
+	ID                  schema.Field
+	WorkspaceID         schema.Field
+	Domain              schema.Field
+	VerifiedAt          schema.Field
+	DefaultCustomRoleID schema.Field
+}{
+	ID:                  schema.NewField("id", "id"),
+	WorkspaceID:         schema.NewField("workspace_id", "workspaceId"),
+	Domain:              schema.NewField("domain", "domain"),
+	VerifiedAt:          schema.NewField("verified_at", "verifiedAt"),
+	DefaultCustomRoleID: schema.NewField("default_custom_role_id", "defaultCustomRoleId"),
+}
@@ -30,15 +30,111 @@ type UpdateUserInput struct {
 	LastName  *string `form:"lastName" json:"lastName"`
 }
 
+// UpdateNotificationPreferencesInput represents the request to update the authenticated
+// user's email notification preferences. Omitted preferences are left unchanged.
+type UpdateNotificationPreferencesInput struct {
+	WeeklyDigest *bool `json:"weeklyDigest"`
+}
+
+// RequestEmailChangeInput represents the request to change the authenticated user's login
+// email. Password is the current password; users who sign in without one leave it empty.
+type RequestEmailChangeInput struct {
+	NewEmail string `json:"newEmail" binding:"required,email"`
+	Password string `json:"password"`
+}
+
 // InviteUserInput represents the request to invite a user to a workspace.
 type InviteUserInput struct {
 	Email string    `form:"email" json:"email" binding:"required,email"`
 	Role  role.Role `form:"role" json:"role" binding:"required,oneof=user admin"`
+	// CustomRoleID gives the invitee a custom role of the workspace; only with the user role.
+	CustomRoleID string `form:"customRoleId" json:"customRoleId" binding:"omitempty"`
+	// ExpiresInDays overrides how long the invitation link works; defaults to the configured expiry.
+	ExpiresInDays int `form:"expiresInDays" json:"expiresInDays" binding:"omitempty,min=1,max=30"`
+}
+
+// BulkInviteRow is one invitee of a bulk invitation.
+type BulkInviteRow struct {
+	Email        string    `json:"email" binding:"required,email"`
+	Role         role.Role `json:"role" binding:"required,oneof=user admin"`
+	CustomRoleID string    `json:"customRoleId" binding:"omitempty"`
+}
+
+// BulkInviteInput represents the request to invite several users to a workspace at once.
+type BulkInviteInput struct {
+	Invitations   []BulkInviteRow `json:"invitations" binding:"required,min=1,max=50,dive"`
+	ExpiresInDays int             `json:"expiresInDays" binding:"omitempty,min=1,max=30"`
+}
+
+// ResendInvitationInput represents the request to resend a pending invitation.
+type ResendInvitationInput struct {
+	ExpiresInDays int `json:"expiresInDays" binding:"omitempty,min=1,max=30"`
 }
 
 // UpdateUserRoleInput represents the request to update a user's role.
 type UpdateUserRoleInput struct {
 	Role role.Role `form:"role" json:"role" binding:"required,oneof=user admin"`
+	// CustomRoleID assigns a custom role of the workspace; only with the user role. Omitting it
+	// removes the user's custom role.
+	CustomRoleID string `form:"customRoleId" json:"customRoleId" binding:"omitempty"`
+}
+
+// CreateCustomRoleInput represents the request to define a custom role for the workspace.
+type CreateCustomRoleInput struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"omitempty,max=500"`
+	// Permissions are "action:resource" pairs of the permission matrix, e.g. "manage:order".
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// UpdateCustomRoleInput represents the request to update a custom role. Omitted fields are left unchanged.
+type UpdateCustomRoleInput struct {
+	Name        *string   `json:"name" binding:"omitempty,min=1,max=100"`
+	Description *string   `json:"description" binding:"omitempty,max=500"`
+	Permissions *[]string `json:"permissions" binding:"omitempty"`
+}
+
+// SSORoleMappingInput maps an identity provider group to the role its users are provisioned with.
+type SSORoleMappingInput struct {
+	Group        string    `json:"group" binding:"required,max=200"`
+	Role         role.Role `json:"role" binding:"required,oneof=user admin"`
+	CustomRoleID string    `json:"customRoleId" binding:"omitempty"`
+}
+
+// PutSSOConnectionInput represents the request to set up the workspace's single sign-on.
+// It replaces the whole configuration, except that an empty oidcClientSecret keeps the
+// stored one. Only OIDC can be set up; saml is refused until sign-in supports it.
+type PutSSOConnectionInput struct {
+	Protocol SSOProtocol `json:"protocol" binding:"required,oneof=oidc saml"`
+	// Domain is the email domain whose users sign in through the identity provider.
+	Domain              string                `json:"domain" binding:"required,fqdn"`
+	Enabled             bool                  `json:"enabled"`
+	OIDCIssuer          string                `json:"oidcIssuer" binding:"omitempty,url"`
+	OIDCClientID        string                `json:"oidcClientId" binding:"omitempty,max=500"`
+	OIDCClientSecret    string                `json:"oidcClientSecret" binding:"omitempty,max=2000"`
+	JITProvisioning     bool                  `json:"jitProvisioning"`
+	GroupsClaim         string                `json:"groupsClaim" binding:"omitempty,max=100"`
+	DefaultRole         role.Role             `json:"defaultRole" binding:"omitempty,oneof=user admin"`
+	DefaultCustomRoleID string                `json:"defaultCustomRoleId" binding:"omitempty"`
+	RoleMappings        []SSORoleMappingInput `json:"roleMappings" binding:"omitempty,max=50,dive"`
+}
+
+// UpdateUserPermissionsInput represents the request to update a member's permission flags.
+// Omitted flags are left unchanged.
+type UpdateUserPermissionsInput struct {
+	CanManageOperations *bool `json:"canManageOperations"`
+	RestrictFinancials  *bool `json:"restrictFinancials"`
+}
+
+// listWorkspaceUsersQuery represents the query parameters for listing workspace users.
+type listWorkspaceUsersQuery struct {
+	Page         int      `form:"page" binding:"omitempty,min=1"`
+	PageSize     int      `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	OrderBy      []string `form:"orderBy" binding:"omitempty"`
+	SearchTerm   string   `form:"search" binding:"omitempty"`
+	Role         []string `form:"role" binding:"omitempty,dive,oneof=user admin"`
+	Status       string   `form:"status" binding:"omitempty,oneof=verified unverified"`
+	CustomRoleID string   `form:"customRoleId" binding:"omitempty"`
 }
 
 // Authentication request types
@@ -54,6 +150,27 @@ type googleLoginRequest struct {
 	Code string `json:"code" binding:"required"`
 }
 
+// ssoStartRequest represents the request to start signing in through the workspace's identity provider.
+type ssoStartRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ssoCallbackRequest represents what the identity provider sent back to the SSO redirect URL.
+type ssoCallbackRequest struct {
+	State string `json:"state" binding:"required"`
+	Code  string `json:"code" binding:"required"`
+}
+
+// magicLinkRequest represents the request to email a one-time sign-in link.
+type magicLinkRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// magicLinkLoginRequest represents the request to sign in with a magic link token.
+type magicLinkLoginRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
 // refreshRequest represents the request to refresh an access token.
 type refreshRequest struct {
 	RefreshToken string `json:"refreshToken" binding:"required"`
@@ -79,3 +196,8 @@ type requestEmailVerificationRequest struct {
 type verifyEmailRequest struct {
 	Token string `json:"token" binding:"required"`
 }
+
+// emailChangeTokenRequest represents the request to confirm or undo an email change with its token.
+type emailChangeTokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -1,9 +1,14 @@
 package account
 
 import (
+	"errors"
+	"sort"
 	"time"
 
+	"github.com/abdelrahman146/kyora/internal/platform/region"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
 	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
 )
 
 /* User Response DTO */
@@ -11,16 +16,23 @@ import (
 
 // UserResponse represents the API response shape for a User.
 // It excludes GORM metadata and sensitive fields (Password, AuthVersion).
+// Permissions are the "action:resource" pairs the user holds once their role, custom role and
+// flags are applied.
 type UserResponse struct {
-	ID              string    `json:"id"`
-	WorkspaceID     string    `json:"workspaceId"`
-	Role            role.Role `json:"role"`
-	FirstName       string    `json:"firstName"`
-	LastName        string    `json:"lastName"`
-	Email           string    `json:"email"`
-	IsEmailVerified bool      `json:"isEmailVerified"`
-	CreatedAt       time.Time `json:"createdAt"`
-	UpdatedAt       time.Time `json:"updatedAt"`
+	ID                  string    `json:"id"`
+	WorkspaceID         string    `json:"workspaceId"`
+	Role                role.Role `json:"role"`
+	FirstName           string    `json:"firstName"`
+	LastName            string    `json:"lastName"`
+	Email               string    `json:"email"`
+	IsEmailVerified     bool      `json:"isEmailVerified"`
+	CanManageOperations bool      `json:"canManageOperations"`
+	RestrictFinancials  bool      `json:"restrictFinancials"`
+	CustomRoleID        string    `json:"customRoleId,omitempty"`
+	CustomRoleName      string    `json:"customRoleName,omitempty"`
+	Permissions         []string  `json:"permissions"`
+	CreatedAt           time.Time `json:"createdAt"`
+	UpdatedAt           time.Time `json:"updatedAt"`
 }
 
 // ToUserResponse converts a User model to a UserResponse DTO
@@ -28,17 +40,25 @@ func ToUserResponse(user *User) *UserResponse {
 	if user == nil {
 		return nil
 	}
-	return &UserResponse{
-		ID:              user.ID,
-		WorkspaceID:     user.WorkspaceID,
-		Role:            user.Role,
-		FirstName:       user.FirstName,
-		LastName:        user.LastName,
-		Email:           user.Email,
-		IsEmailVerified: user.IsEmailVerified,
-		CreatedAt:       user.CreatedAt,
-		UpdatedAt:       user.UpdatedAt,
+	resp := &UserResponse{
+		ID:                  user.ID,
+		WorkspaceID:         user.WorkspaceID,
+		Role:                user.Role,
+		FirstName:           user.FirstName,
+		LastName:            user.LastName,
+		Email:               user.Email,
+		IsEmailVerified:     user.IsEmailVerified,
+		CanManageOperations: user.CanManageOperations,
+		RestrictFinancials:  user.RestrictFinancials,
+		CustomRoleID:        user.CustomRoleID.String,
+		Permissions:         user.EffectivePermissions(),
+		CreatedAt:           user.CreatedAt,
+		UpdatedAt:           user.UpdatedAt,
 	}
+	if user.CustomRole != nil {
+		resp.CustomRoleName = user.CustomRole.Name
+	}
+	return resp
 }
 
 // ToUserResponses converts a slice of User models to UserResponse DTOs
@@ -53,6 +73,17 @@ func ToUserResponses(users []*User) []*UserResponse {
 	return responses
 }
 
+// NotificationPreferencesResponse represents the email notifications a user receives.
+type NotificationPreferencesResponse struct {
+	// WeeklyDigest is the weekly summary of workspace activity.
+	WeeklyDigest bool `json:"weeklyDigest"`
+}
+
+// ToNotificationPreferencesResponse returns the notification preferences of user.
+func ToNotificationPreferencesResponse(user *User) NotificationPreferencesResponse {
+	return NotificationPreferencesResponse{WeeklyDigest: !user.WeeklyDigestOptOut}
+}
+
 /* Workspace Response DTO */
 //------------------------*/
 
@@ -63,6 +94,7 @@ type WorkspaceResponse struct {
 	OwnerID               string    `json:"ownerId"`
 	StripeCustomerID      *string   `json:"stripeCustomerId,omitempty"`
 	StripePaymentMethodID *string   `json:"stripePaymentMethodId,omitempty"`
+	Region                string    `json:"region"`
 	CreatedAt             time.Time `json:"createdAt"`
 	UpdatedAt             time.Time `json:"updatedAt"`
 }
@@ -76,6 +108,7 @@ func ToWorkspaceResponse(workspace *Workspace) *WorkspaceResponse {
 	resp := &WorkspaceResponse{
 		ID:        workspace.ID,
 		OwnerID:   workspace.OwnerID,
+		Region:    region.Resolve(workspace.Region),
 		CreatedAt: workspace.CreatedAt,
 		UpdatedAt: workspace.UpdatedAt,
 	}
@@ -96,15 +129,17 @@ func ToWorkspaceResponse(workspace *Workspace) *WorkspaceResponse {
 // UserInvitationResponse represents the API response shape for a UserInvitation.
 // It excludes GORM metadata and only includes relevant fields.
 type UserInvitationResponse struct {
-	ID          string           `json:"id"`
-	WorkspaceID string           `json:"workspaceId"`
-	Email       string           `json:"email"`
-	Role        role.Role        `json:"role"`
-	InviterID   string           `json:"inviterId"`
-	Status      InvitationStatus `json:"status"`
-	AcceptedAt  *time.Time       `json:"acceptedAt,omitempty"`
-	CreatedAt   time.Time        `json:"createdAt"`
-	UpdatedAt   time.Time        `json:"updatedAt"`
+	ID           string           `json:"id"`
+	WorkspaceID  string           `json:"workspaceId"`
+	Email        string           `json:"email"`
+	Role         role.Role        `json:"role"`
+	InviterID    string           `json:"inviterId"`
+	Status       InvitationStatus `json:"status"`
+	AcceptedAt   *time.Time       `json:"acceptedAt,omitempty"`
+	CustomRoleID string           `json:"customRoleId,omitempty"`
+	ExpiresAt    *time.Time       `json:"expiresAt,omitempty"`
+	CreatedAt    time.Time        `json:"createdAt"`
+	UpdatedAt    time.Time        `json:"updatedAt"`
 }
 
 // ToUserInvitationResponse converts a UserInvitation model to a UserInvitationResponse DTO
@@ -114,14 +149,16 @@ func ToUserInvitationResponse(invitation *UserInvitation) *UserInvitationRespons
 	}
 
 	resp := &UserInvitationResponse{
-		ID:          invitation.ID,
-		WorkspaceID: invitation.WorkspaceID,
-		Email:       invitation.Email,
-		Role:        invitation.Role,
-		InviterID:   invitation.InviterID,
-		Status:      invitation.Status,
-		CreatedAt:   invitation.CreatedAt,
-		UpdatedAt:   invitation.UpdatedAt,
+		ID:           invitation.ID,
+		WorkspaceID:  invitation.WorkspaceID,
+		Email:        invitation.Email,
+		Role:         invitation.Role,
+		InviterID:    invitation.InviterID,
+		Status:       invitation.Status,
+		CustomRoleID: invitation.CustomRoleID.String,
+		ExpiresAt:    invitation.ExpiresAt,
+		CreatedAt:    invitation.CreatedAt,
+		UpdatedAt:    invitation.UpdatedAt,
 	}
 
 	if invitation.AcceptedAt != nil && invitation.AcceptedAt.Valid {
@@ -144,6 +181,126 @@ func ToUserInvitationResponses(invitations []*UserInvitation) []*UserInvitationR
 	return responses
 }
 
+// BulkInviteRowError explains why a bulk invitation row failed.
+type BulkInviteRowError struct {
+	Code   string `json:"code,omitempty"`
+	Detail string `json:"detail"`
+}
+
+// BulkInviteRowResponse is the outcome of one bulk invitation row.
+type BulkInviteRowResponse struct {
+	Email      string                  `json:"email"`
+	Status     string                  `json:"status"` // invited or failed
+	Invitation *UserInvitationResponse `json:"invitation,omitempty"`
+	Error      *BulkInviteRowError     `json:"error,omitempty"`
+}
+
+// BulkInviteResponse reports every row of a bulk invitation, in request order.
+type BulkInviteResponse struct {
+	Results []*BulkInviteRowResponse `json:"results"`
+	Invited int                      `json:"invited"`
+	Failed  int                      `json:"failed"`
+}
+
+// ToBulkInviteResponse converts bulk invitation results to a BulkInviteResponse DTO
+func ToBulkInviteResponse(results []*BulkInviteResult) *BulkInviteResponse {
+	resp := &BulkInviteResponse{Results: make([]*BulkInviteRowResponse, len(results))}
+	for i, result := range results {
+		row := &BulkInviteRowResponse{Email: result.Email}
+		if result.Err == nil {
+			row.Status = "invited"
+			row.Invitation = ToUserInvitationResponse(result.Invitation)
+			resp.Invited++
+		} else {
+			row.Status = "failed"
+			row.Error = toBulkInviteRowError(result.Err)
+			resp.Failed++
+		}
+		resp.Results[i] = row
+	}
+	return resp
+}
+
+func toBulkInviteRowError(err error) *BulkInviteRowError {
+	var p *problem.Problem
+	if errors.As(err, &p) {
+		code, _ := p.Extensions["code"].(string)
+		return &BulkInviteRowError{Code: code, Detail: p.Detail}
+	}
+	return &BulkInviteRowError{Code: "account.operation_failed", Detail: "could not be invited"}
+}
+
+/* Custom Role Response DTO */
+//---------------------------*/
+
+// CustomRoleResponse represents the API response shape for a CustomRole.
+type CustomRoleResponse struct {
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspaceId"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// ToCustomRoleResponse converts a CustomRole model to a CustomRoleResponse DTO
+func ToCustomRoleResponse(customRole *CustomRole) *CustomRoleResponse {
+	if customRole == nil {
+		return nil
+	}
+	permissions := []string(customRole.Permissions)
+	if permissions == nil {
+		permissions = []string{}
+	}
+	return &CustomRoleResponse{
+		ID:          customRole.ID,
+		WorkspaceID: customRole.WorkspaceID,
+		Name:        customRole.Name,
+		Description: customRole.Description,
+		Permissions: permissions,
+		CreatedAt:   customRole.CreatedAt,
+		UpdatedAt:   customRole.UpdatedAt,
+	}
+}
+
+// ToCustomRoleResponses converts a slice of CustomRole models to CustomRoleResponse DTOs
+func ToCustomRoleResponses(customRoles []*CustomRole) []*CustomRoleResponse {
+	responses := make([]*CustomRoleResponse, len(customRoles))
+	for i, customRole := range customRoles {
+		responses[i] = ToCustomRoleResponse(customRole)
+	}
+	return responses
+}
+
+// PermissionMatrixEntry lists the actions a custom role can be granted on one domain.
+type PermissionMatrixEntry struct {
+	Resource role.Resource `json:"resource"`
+	Actions  []role.Action `json:"actions"`
+}
+
+// PermissionMatrixResponse is the permission matrix custom roles are defined from, with the
+// permissions of the built-in roles for reference.
+type PermissionMatrixResponse struct {
+	Resources []PermissionMatrixEntry `json:"resources"`
+	Roles     map[role.Role][]string  `json:"roles"`
+}
+
+// ToPermissionMatrixResponse builds the permission matrix response, domains sorted by name.
+func ToPermissionMatrixResponse() *PermissionMatrixResponse {
+	resp := &PermissionMatrixResponse{
+		Resources: make([]PermissionMatrixEntry, 0, len(role.PermissionMatrix)),
+		Roles:     role.RolePermissions,
+	}
+	for resource, actions := range role.PermissionMatrix {
+		resp.Resources = append(resp.Resources, PermissionMatrixEntry{Resource: resource, Actions: actions})
+	}
+	sort.Slice(resp.Resources, func(i, j int) bool {
+		return resp.Resources[i].Resource < resp.Resources[j].Resource
+	})
+	return resp
+}
+
 /* Auth Response Types */
 //-----------------------*/
 
@@ -168,3 +325,65 @@ func ToLoginResponse(user *User, token, refreshToken string) *LoginResponse {
 		RefreshToken: refreshToken,
 	}
 }
+
+// SSOConnectionResponse represents the API response shape for an SSOConnection. The OIDC
+// client secret is never returned.
+type SSOConnectionResponse struct {
+	ID                      string           `json:"id"`
+	WorkspaceID             string           `json:"workspaceId"`
+	Protocol                SSOProtocol      `json:"protocol"`
+	Domain                  string           `json:"domain"`
+	Enabled                 bool             `json:"enabled"`
+	DomainVerified          bool             `json:"domainVerified"`
+	DomainVerifiedAt        *time.Time       `json:"domainVerifiedAt,omitempty"`
+	VerificationRecordName  string           `json:"verificationRecordName"`
+	VerificationRecordValue string           `json:"verificationRecordValue"`
+	OIDCIssuer              string           `json:"oidcIssuer,omitempty"`
+	OIDCClientID            string           `json:"oidcClientId,omitempty"`
+	HasOIDCClientSecret     bool             `json:"hasOidcClientSecret"`
+	JITProvisioning         bool             `json:"jitProvisioning"`
+	GroupsClaim             string           `json:"groupsClaim"`
+	DefaultRole             role.Role        `json:"defaultRole"`
+	DefaultCustomRoleID     string           `json:"defaultCustomRoleId,omitempty"`
+	RoleMappings            []SSORoleMapping `json:"roleMappings"`
+	CreatedAt               time.Time        `json:"createdAt"`
+	UpdatedAt               time.Time        `json:"updatedAt"`
+}
+
+// ToSSOConnectionResponse converts an SSOConnection model to an SSOConnectionResponse DTO
+func ToSSOConnectionResponse(conn *SSOConnection) *SSOConnectionResponse {
+	if conn == nil {
+		return nil
+	}
+	mappings := []SSORoleMapping(conn.RoleMappings)
+	if mappings == nil {
+		mappings = []SSORoleMapping{}
+	}
+	return &SSOConnectionResponse{
+		ID:                      conn.ID,
+		WorkspaceID:             conn.WorkspaceID,
+		Protocol:                conn.Protocol,
+		Domain:                  conn.Domain,
+		Enabled:                 conn.Enabled,
+		DomainVerified:          conn.VerifiedAt.Valid,
+		DomainVerifiedAt:        transformer.NullTimePtr(conn.VerifiedAt),
+		VerificationRecordName:  conn.VerificationRecordName(),
+		VerificationRecordValue: conn.VerificationRecordValue(),
+		OIDCIssuer:              conn.OIDCIssuer,
+		OIDCClientID:            conn.OIDCClientID,
+		HasOIDCClientSecret:     conn.OIDCClientSecret != "",
+		JITProvisioning:         conn.JITProvisioning,
+		GroupsClaim:             conn.GroupsClaim,
+		DefaultRole:             conn.DefaultRole,
+		DefaultCustomRoleID:     transformer.FromNullString(conn.DefaultCustomRoleID),
+		RoleMappings:            mappings,
+		CreatedAt:               conn.CreatedAt,
+		UpdatedAt:               conn.UpdatedAt,
+	}
+}
+
+// SSOStartResponse is where to send the user to sign in through the identity provider.
+type SSOStartResponse struct {
+	URL   string `json:"url"`
+	State string `json:"state"`
+}
@@ -261,3 +261,101 @@ func (n *Notification) SendWorkspaceInvitationEmail(ctx context.Context, invitee
 	logger.InfoContext(ctx, "Workspace invitation email sent successfully")
 	return nil
 }
+
+// SendEmailChangeConfirmationEmail asks the new address to confirm an email change of user.
+func (n *Notification) SendEmailChangeConfirmationEmail(ctx context.Context, user *User, newEmail, token string, expiryTime time.Time) error {
+	if n.client == nil {
+		return fmt.Errorf("email client not available")
+	}
+	logger := slog.With("action", "send_email_change_confirmation", "user_id", user.ID, "email", newEmail)
+	logger.InfoContext(ctx, "Sending email change confirmation email")
+
+	confirmURL := fmt.Sprintf("%s/email-change/confirm?token=%s", n.info.BaseURL, token)
+	expiryHours := helpers.CeilPositiveHoursUntil(expiryTime)
+	if expiryHours < 1 {
+		expiryHours = 1
+	}
+	data := map[string]any{
+		"userName":     n.getUserDisplayName(user),
+		"oldEmail":     user.Email,
+		"newEmail":     newEmail,
+		"confirmURL":   confirmURL,
+		"productName":  n.info.ProductName,
+		"supportEmail": n.info.SupportEmail,
+		"helpURL":      n.info.HelpURL,
+		"expiryTime":   fmt.Sprintf("%d hours", expiryHours),
+		"currentYear":  fmt.Sprintf("%d", time.Now().Year()),
+	}
+	from := n.info.FormattedFrom()
+	if _, err := n.client.SendTemplate(ctx, email.TemplateEmailChangeConfirm, []string{newEmail}, from, "", data); err != nil {
+		logger.ErrorContext(ctx, "Failed to send email change confirmation email", "error", err)
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	logger.InfoContext(ctx, "Email change confirmation email sent successfully")
+	return nil
+}
+
+// SendEmailChangedNoticeEmail tells the old address its email was changed, with a link to undo it.
+func (n *Notification) SendEmailChangedNoticeEmail(ctx context.Context, user *User, oldEmail, token string, expiryTime time.Time) error {
+	if n.client == nil {
+		return fmt.Errorf("email client not available")
+	}
+	logger := slog.With("action", "send_email_changed_notice", "user_id", user.ID, "email", oldEmail)
+	logger.InfoContext(ctx, "Sending email changed notice email")
+
+	undoURL := fmt.Sprintf("%s/email-change/undo?token=%s", n.info.BaseURL, token)
+	expiryDays := helpers.CeilPositiveDaysUntil(expiryTime)
+	if expiryDays < 1 {
+		expiryDays = 1
+	}
+	data := map[string]any{
+		"userName":     n.getUserDisplayName(user),
+		"oldEmail":     oldEmail,
+		"newEmail":     user.Email,
+		"changeDate":   time.Now().Format("January 2, 2006"),
+		"undoURL":      undoURL,
+		"productName":  n.info.ProductName,
+		"supportEmail": n.info.SupportEmail,
+		"helpURL":      n.info.HelpURL,
+		"expiryTime":   fmt.Sprintf("%d days", expiryDays),
+		"currentYear":  fmt.Sprintf("%d", time.Now().Year()),
+	}
+	from := n.info.FormattedFrom()
+	if _, err := n.client.SendTemplate(ctx, email.TemplateEmailChangedNotice, []string{oldEmail}, from, "", data); err != nil {
+		logger.ErrorContext(ctx, "Failed to send email changed notice email", "error", err)
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	logger.InfoContext(ctx, "Email changed notice email sent successfully")
+	return nil
+}
+
+// SendMagicLinkEmail sends user a one-time sign-in link.
+func (n *Notification) SendMagicLinkEmail(ctx context.Context, user *User, token string, expiryTime time.Time) error {
+	if n.client == nil {
+		return fmt.Errorf("email client not available")
+	}
+	logger := slog.With("action", "send_magic_link", "user_id", user.ID, "email", user.Email)
+	logger.InfoContext(ctx, "Sending magic link email")
+
+	loginURL := fmt.Sprintf("%s/magic-link?token=%s", n.info.BaseURL, token)
+	expiryMinutes := helpers.CeilPositiveMinutesUntil(expiryTime)
+	if expiryMinutes < 1 {
+		expiryMinutes = 1
+	}
+	data := map[string]any{
+		"userName":      n.getUserDisplayName(user),
+		"loginURL":      loginURL,
+		"productName":   n.info.ProductName,
+		"supportEmail":  n.info.SupportEmail,
+		"helpURL":       n.info.HelpURL,
+		"expiryMinutes": expiryMinutes,
+		"currentYear":   fmt.Sprintf("%d", time.Now().Year()),
+	}
+	from := n.info.FormattedFrom()
+	if _, err := n.client.SendTemplate(ctx, email.TemplateMagicLink, []string{user.Email}, from, "", data); err != nil {
+		logger.ErrorContext(ctx, "Failed to send magic link email", "error", err)
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	logger.InfoContext(ctx, "Magic link email sent successfully")
+	return nil
+}
@@ -2,7 +2,9 @@ package account
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
@@ -12,12 +14,15 @@ import (
 	"github.com/abdelrahman146/kyora/internal/platform/database"
 	"github.com/abdelrahman146/kyora/internal/platform/email"
 	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/region"
 	"github.com/abdelrahman146/kyora/internal/platform/types/atomic"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
 	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
 	"github.com/abdelrahman146/kyora/internal/platform/types/role"
 	"github.com/abdelrahman146/kyora/internal/platform/utils/hash"
 	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
 	"github.com/abdelrahman146/kyora/internal/platform/utils/throttle"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
 	"github.com/spf13/viper"
 	"gorm.io/gorm"
 )
@@ -27,6 +32,11 @@ type Service struct {
 	storage         *Storage
 	atomicProcessor atomic.AtomicProcessor
 	Notification    *Notification
+	googleOAuth     auth.GoogleOAuthProvider
+	oidc            auth.OIDCProvider
+	ssoEntitlement  SSOEntitlement
+	txtResolver     TXTResolver
+	teamSeatLimit   TeamSeatLimit
 }
 
 func NewService(storage *Storage, atomicProcessor atomic.AtomicProcessor, bus *bus.Bus, emailClient email.Client) *Service {
@@ -37,11 +47,19 @@ func NewService(storage *Storage, atomicProcessor atomic.AtomicProcessor, bus *b
 		atomicProcessor: atomicProcessor,
 		bus:             bus,
 		Notification:    notification,
+		googleOAuth:     auth.NewGoogleOAuthProvider(),
+		oidc:            auth.NewOIDCProvider(),
+		txtResolver:     net.DefaultResolver,
 	}
 }
 
+// SetGoogleOAuthProvider replaces the provider used for Google sign-in.
+func (s *Service) SetGoogleOAuthProvider(provider auth.GoogleOAuthProvider) {
+	s.googleOAuth = provider
+}
+
 func (s *Service) GetUserByID(ctx context.Context, id string) (*User, error) {
-	return s.storage.user.FindByID(ctx, id, s.storage.user.WithPreload(WorkspaceStruct))
+	return s.storage.user.FindByID(ctx, id, s.storage.user.WithPreload(WorkspaceStruct), s.storage.user.WithPreload(CustomRoleStruct))
 }
 
 // GetWorkspaceUserByID returns a user only if they belong to the given workspace.
@@ -52,6 +70,7 @@ func (s *Service) GetWorkspaceUserByID(ctx context.Context, workspaceID, userID
 		s.storage.user.ScopeWorkspaceID(workspaceID),
 		s.storage.user.ScopeID(userID),
 		s.storage.user.WithPreload(WorkspaceStruct),
+		s.storage.user.WithPreload(CustomRoleStruct),
 	)
 	if err != nil {
 		if database.IsRecordNotFound(err) {
@@ -93,14 +112,94 @@ func (s *Service) CountWorkspaceUsers(ctx context.Context, workspaceID string) (
 	return s.storage.user.Count(ctx, s.storage.user.ScopeWorkspaceID(workspaceID))
 }
 
+// ListWeeklyDigestRecipients returns the verified users of the workspace who did not opt out
+// of the weekly digest.
+func (s *Service) ListWeeklyDigestRecipients(ctx context.Context, workspaceID string) ([]*User, error) {
+	return s.storage.user.FindMany(ctx,
+		s.storage.user.ScopeWorkspaceID(workspaceID),
+		s.storage.user.ScopeEquals(UserSchema.IsEmailVerified, true),
+		s.storage.user.ScopeEquals(UserSchema.WeeklyDigestOptOut, false),
+		s.storage.user.WithOrderBy([]string{"created_at ASC"}),
+	)
+}
+
+// UpdateNotificationPreferences updates the email notification preferences of the actor.
+func (s *Service) UpdateNotificationPreferences(ctx context.Context, actor *User, input *UpdateNotificationPreferencesInput) (*User, error) {
+	user, err := s.GetUserByID(ctx, actor.ID)
+	if err != nil {
+		return nil, err
+	}
+	if input.WeeklyDigest != nil {
+		user.WeeklyDigestOptOut = !*input.WeeklyDigest
+	}
+	if err := s.storage.user.UpdateOne(ctx, user); err != nil {
+		return nil, ErrAccountOperationFailed(err)
+	}
+	return user, nil
+}
+
 // CountWorkspaceUsersForPlanLimit is a wrapper that matches the billing EnforcePlanLimitFunc signature
 // It counts users in the given workspace (the id parameter is the workspaceID)
 func (s *Service) CountWorkspaceUsersForPlanLimit(ctx context.Context, actor *User, id string) (int64, error) {
 	return s.CountWorkspaceUsers(ctx, id)
 }
 
+// ListWorkspaceUsersFilters contains optional filters for workspace user listing.
+type ListWorkspaceUsersFilters struct {
+	Roles         []role.Role
+	EmailVerified *bool
+	CustomRoleID  string
+}
+
+// ListWorkspaceUsers returns a page of users in the workspace together with the total match count.
+// Without an explicit orderBy, users are returned oldest first so the owner stays on the first page.
+func (s *Service) ListWorkspaceUsers(ctx context.Context, workspaceID string, req *list.ListRequest, filters *ListWorkspaceUsersFilters) ([]*User, int64, error) {
+	baseScopes := []func(db *gorm.DB) *gorm.DB{
+		s.storage.user.ScopeWorkspaceID(workspaceID),
+	}
+	if filters != nil {
+		if len(filters.Roles) > 0 {
+			vals := make([]any, 0, len(filters.Roles))
+			for _, r := range filters.Roles {
+				vals = append(vals, r)
+			}
+			baseScopes = append(baseScopes, s.storage.user.ScopeIn(UserSchema.Role, vals))
+		}
+		if filters.EmailVerified != nil {
+			baseScopes = append(baseScopes, s.storage.user.ScopeEquals(UserSchema.IsEmailVerified, *filters.EmailVerified))
+		}
+		if filters.CustomRoleID != "" {
+			baseScopes = append(baseScopes, s.storage.user.ScopeEquals(UserSchema.CustomRoleID, filters.CustomRoleID))
+		}
+	}
+	if req.SearchTerm() != "" {
+		baseScopes = append(baseScopes, s.storage.user.ScopeSearchTerm(req.SearchTerm(), UserSchema.FirstName, UserSchema.LastName, UserSchema.Email))
+	}
+
+	orderBy := []string{UserSchema.CreatedAt.Column() + " ASC"}
+	if req.HasExplicitOrderBy() {
+		orderBy = req.ParsedOrderBy(UserSchema)
+	}
+
+	findOpts := append([]func(*gorm.DB) *gorm.DB{}, baseScopes...)
+	findOpts = append(findOpts,
+		s.storage.user.WithPagination(req.Offset(), req.Limit()),
+		s.storage.user.WithOrderBy(orderBy),
+		s.storage.user.WithPreload(CustomRoleStruct),
+	)
+	users, err := s.storage.user.FindMany(ctx, findOpts...)
+	if err != nil {
+		return nil, 0, ErrAccountOperationFailed(err)
+	}
+	count, err := s.storage.user.Count(ctx, baseScopes...)
+	if err != nil {
+		return nil, 0, ErrAccountOperationFailed(err)
+	}
+	return users, count, nil
+}
+
 func (s *Service) GetUserByEmail(ctx context.Context, email string) (*User, error) {
-	return s.storage.user.FindOne(ctx, s.storage.user.ScopeEquals(UserSchema.Email, email), s.storage.user.WithPreload(WorkspaceStruct))
+	return s.storage.user.FindOne(ctx, s.storage.user.ScopeEquals(UserSchema.Email, email), s.storage.user.WithPreload(WorkspaceStruct), s.storage.user.WithPreload(CustomRoleStruct))
 }
 
 func (s *Service) issueTokensForUser(ctx context.Context, user *User, clientIP, userAgent string) (*RefreshResponse, error) {
@@ -404,7 +503,7 @@ func (s *Service) GetGoogleAuthURL(ctx context.Context) (url string, state strin
 	if err != nil {
 		return "", "", ErrAccountOperationFailed(err)
 	}
-	url, err = auth.GoogleGetAuthURL(ctx, state)
+	url, err = s.googleOAuth.AuthURL(ctx, state)
 	if err != nil {
 		return "", "", ErrAccountOperationFailed(err)
 	}
@@ -412,16 +511,17 @@ func (s *Service) GetGoogleAuthURL(ctx context.Context) (url string, state strin
 }
 
 func (s *Service) ExchangeGoogleCodeAndFetchUser(ctx context.Context, code string) (*auth.GoogleUserInfo, error) {
-	info, err := auth.GoogleExchangeAndFetchUser(ctx, code)
+	info, err := s.googleOAuth.ExchangeAndFetchUser(ctx, code)
 	if err != nil {
 		return nil, ErrAccountOperationFailed(err)
 	}
 	return info, nil
 }
 
-// BootstrapWorkspaceAndOwner creates a new workspace and an owner user atomically.
+// BootstrapWorkspaceAndOwner creates a new workspace in the given data residency region
+// and an owner user atomically. An empty region stands for the home region.
 // It avoids exposing storage details to callers that need to initialize a tenant.
-func (s *Service) BootstrapWorkspaceAndOwner(ctx context.Context, firstName, lastName, email, passwordHash string, emailVerified bool, stripeCustomerID string) (*User, *Workspace, error) {
+func (s *Service) BootstrapWorkspaceAndOwner(ctx context.Context, firstName, lastName, email, passwordHash string, emailVerified bool, stripeCustomerID, dataRegion string) (*User, *Workspace, error) {
 	bootstrap := func(txCtx context.Context) (*User, *Workspace, error) {
 		// Guard against existing user
 		u, err := s.GetUserByEmail(txCtx, email)
@@ -432,7 +532,7 @@ func (s *Service) BootstrapWorkspaceAndOwner(ctx context.Context, firstName, las
 			return nil, nil, err
 		}
 
-		ws := &Workspace{}
+		ws := &Workspace{Region: region.Resolve(dataRegion)}
 		if err := s.storage.workspace.CreateOne(txCtx, ws); err != nil {
 			return nil, nil, err
 		}
@@ -483,71 +583,13 @@ func (s *Service) BootstrapWorkspaceAndOwner(ctx context.Context, firstName, las
 }
 
 // InviteUserToWorkspace creates a workspace invitation and sends an invitation email
-func (s *Service) InviteUserToWorkspace(ctx context.Context, actor *User, workspaceID, email string, role role.Role) (*UserInvitation, error) {
-	// Check if user already exists in the workspace
-	existingUser, err := s.GetUserByEmail(ctx, email)
-	if err == nil && existingUser != nil {
-		if existingUser.WorkspaceID == workspaceID {
-			return nil, ErrUserAlreadyExists(nil)
-		}
-	}
-
-	// Check if there's already a pending invitation
-	existingInvitation, err := s.storage.invitation.FindOne(
-		ctx,
-		s.storage.invitation.ScopeWorkspaceID(workspaceID),
-		s.storage.invitation.ScopeEquals(UserInvitationSchema.Email, email),
-		s.storage.invitation.ScopeEquals(UserInvitationSchema.Status, string(InvitationStatusPending)),
-	)
-	if err == nil && existingInvitation != nil {
-		return nil, ErrInvitationAlreadyExists(nil)
-	}
-
+func (s *Service) InviteUserToWorkspace(ctx context.Context, actor *User, workspaceID string, input *InviteUserInput) (*UserInvitation, error) {
 	// Get workspace details for email
 	workspace, err := s.GetWorkspaceByID(ctx, workspaceID)
 	if err != nil {
 		return nil, err
 	}
-
-	// Create the invitation record
-	invitation := &UserInvitation{
-		WorkspaceID: workspaceID,
-		Email:       email,
-		Role:        role,
-		InviterID:   actor.ID,
-		Status:      InvitationStatusPending,
-	}
-
-	if err := s.storage.invitation.CreateOne(ctx, invitation); err != nil {
-		return nil, ErrAccountOperationFailed(err)
-	}
-
-	// Create invitation token
-	token, expAt, err := s.storage.CreateWorkspaceInvitationToken(&WorkspaceInvitationPayload{
-		InvitationID: invitation.ID,
-		WorkspaceID:  workspaceID,
-		Email:        email,
-		Role:         string(role),
-		InviterID:    actor.ID,
-	})
-	if err != nil {
-		return nil, ErrAccountOperationFailed(err)
-	}
-
-	// Send invitation email
-	inviterName := fmt.Sprintf("%s %s", actor.FirstName, actor.LastName)
-	if inviterName == " " {
-		inviterName = actor.Email
-	}
-	workspaceName := fmt.Sprintf("Workspace %s", workspace.ID) // You may want to add a Name field to Workspace
-
-	err = s.Notification.SendWorkspaceInvitationEmail(ctx, email, workspaceName, inviterName, actor.Email, string(role), token, expAt)
-	if err != nil {
-		// Log error but don't fail
-		logger.FromContext(ctx).Error("Failed to send workspace invitation email, falling back to bus", "error", err)
-	}
-
-	return invitation, nil
+	return s.inviteUser(ctx, actor, workspace, input.Email, input.Role, input.CustomRoleID, input.ExpiresInDays)
 }
 
 // AcceptInvitation processes an invitation acceptance and creates a user account
@@ -568,12 +610,8 @@ func (s *Service) AcceptInvitation(ctx context.Context, token string, firstName,
 		return nil, nil, ErrInvitationNotFound(err)
 	}
 
-	// Check invitation status
-	if invitation.Status == InvitationStatusAccepted {
-		return nil, nil, ErrInvitationAlreadyAccepted(nil)
-	}
-	if invitation.Status == InvitationStatusExpired || invitation.Status == InvitationStatusRevoked {
-		return nil, nil, ErrInvitationExpired(nil)
+	if err := ensureInvitationRedeemable(invitation, payload); err != nil {
+		return nil, nil, err
 	}
 
 	// Check if user already exists
@@ -597,6 +635,7 @@ func (s *Service) AcceptInvitation(ctx context.Context, token string, firstName,
 		user := &User{
 			WorkspaceID:     invitation.WorkspaceID,
 			Role:            invitation.Role,
+			CustomRoleID:    invitation.CustomRoleID,
 			FirstName:       firstName,
 			LastName:        lastName,
 			Email:           invitation.Email,
@@ -662,12 +701,8 @@ func (s *Service) AcceptInvitationWithGoogleAuth(ctx context.Context, token stri
 		return nil, nil, ErrInvitationNotFound(err)
 	}
 
-	// Check invitation status
-	if invitation.Status == InvitationStatusAccepted {
-		return nil, nil, ErrInvitationAlreadyAccepted(nil)
-	}
-	if invitation.Status == InvitationStatusExpired || invitation.Status == InvitationStatusRevoked {
-		return nil, nil, ErrInvitationExpired(nil)
+	if err := ensureInvitationRedeemable(invitation, payload); err != nil {
+		return nil, nil, err
 	}
 
 	// Check if user already exists
@@ -685,6 +720,7 @@ func (s *Service) AcceptInvitationWithGoogleAuth(ctx context.Context, token stri
 		user := &User{
 			WorkspaceID:     invitation.WorkspaceID,
 			Role:            invitation.Role,
+			CustomRoleID:    invitation.CustomRoleID,
 			FirstName:       googleUserInfo.GivenName,
 			LastName:        googleUserInfo.FamilyName,
 			Email:           googleUserInfo.Email,
@@ -726,8 +762,9 @@ func (s *Service) AcceptInvitationWithGoogleAuth(ctx context.Context, token stri
 	return createdUser, workspace, nil
 }
 
-// UpdateUserRole updates a user's role within a workspace
-func (s *Service) UpdateUserRole(ctx context.Context, actor *User, workspace *Workspace, targetUserID string, newRole role.Role) (*User, error) {
+// UpdateUserRole updates a user's role within a workspace, and the custom role of a member:
+// an empty customRoleID removes it.
+func (s *Service) UpdateUserRole(ctx context.Context, actor *User, workspace *Workspace, targetUserID string, newRole role.Role, customRoleID string) (*User, error) {
 	// Prevent user from updating their own role
 	if actor.ID == targetUserID {
 		return nil, ErrCannotUpdateOwnRole(nil)
@@ -744,8 +781,49 @@ func (s *Service) UpdateUserRole(ctx context.Context, actor *User, workspace *Wo
 		return nil, ErrCannotUpdateOwnerRole(nil)
 	}
 
+	customRole, err := s.resolveCustomRoleAssignment(ctx, workspace.ID, newRole, customRoleID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Update role
 	targetUser.Role = newRole
+	targetUser.CustomRoleID = sql.NullString{}
+	// cleared so saving the user does not write back the preloaded custom role's ID
+	targetUser.CustomRole = nil
+	if customRole != nil {
+		targetUser.CustomRoleID = transformer.ToNullString(customRole.ID)
+	}
+	if err := s.storage.user.UpdateOne(ctx, targetUser); err != nil {
+		return nil, ErrAccountOperationFailed(err)
+	}
+	targetUser.CustomRole = customRole
+
+	return targetUser, nil
+}
+
+// UpdateUserPermissions updates a member's permission flags within a workspace
+func (s *Service) UpdateUserPermissions(ctx context.Context, actor *User, workspace *Workspace, targetUserID string, input *UpdateUserPermissionsInput) (*User, error) {
+	if actor.ID == targetUserID {
+		return nil, ErrCannotUpdateOwnPermissions(nil)
+	}
+
+	targetUser, err := s.GetWorkspaceUserByID(ctx, workspace.ID, targetUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Admins (including the owner) always have full access, so flags would be meaningless
+	if targetUser.Role != role.RoleUser {
+		return nil, ErrPermissionFlagsMembersOnly(nil)
+	}
+
+	if input.CanManageOperations != nil {
+		targetUser.CanManageOperations = *input.CanManageOperations
+	}
+	if input.RestrictFinancials != nil {
+		targetUser.RestrictFinancials = *input.RestrictFinancials
+	}
 	if err := s.storage.user.UpdateOne(ctx, targetUser); err != nil {
 		return nil, ErrAccountOperationFailed(err)
 	}
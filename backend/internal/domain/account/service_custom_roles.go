@@ -0,0 +1,174 @@
+package account
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"gorm.io/gorm"
+)
+
+// ListCustomRoles returns the custom roles of a workspace ordered by name.
+func (s *Service) ListCustomRoles(ctx context.Context, workspaceID string) ([]*CustomRole, error) {
+	roles, err := s.storage.customRole.FindMany(ctx,
+		s.storage.customRole.ScopeWorkspaceID(workspaceID),
+		s.storage.customRole.WithOrderBy([]string{CustomRoleSchema.Name.Column() + " ASC"}),
+	)
+	if err != nil {
+		return nil, ErrAccountOperationFailed(err)
+	}
+	return roles, nil
+}
+
+// GetCustomRoleByID returns a custom role only if it belongs to the given workspace.
+func (s *Service) GetCustomRoleByID(ctx context.Context, workspaceID, id string) (*CustomRole, error) {
+	customRole, err := s.storage.customRole.FindOne(ctx,
+		s.storage.customRole.ScopeWorkspaceID(workspaceID),
+		s.storage.customRole.ScopeID(id),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrCustomRoleNotFound(err)
+		}
+		return nil, ErrAccountOperationFailed(err)
+	}
+	return customRole, nil
+}
+
+// CreateCustomRole defines a custom role for the workspace from the permission matrix.
+func (s *Service) CreateCustomRole(ctx context.Context, actor *User, workspace *Workspace, input *CreateCustomRoleInput) (*CustomRole, error) {
+	name := strings.TrimSpace(input.Name)
+	if err := s.ensureCustomRoleNameAvailable(ctx, workspace.ID, name, ""); err != nil {
+		return nil, err
+	}
+	permissions, err := normalizePermissions(input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	customRole := &CustomRole{
+		WorkspaceID: workspace.ID,
+		Name:        name,
+		Description: strings.TrimSpace(input.Description),
+		Permissions: permissions,
+	}
+	if err := s.storage.customRole.CreateOne(ctx, customRole); err != nil {
+		return nil, ErrAccountOperationFailed(err)
+	}
+	return customRole, nil
+}
+
+// UpdateCustomRole updates a custom role. New permissions apply to its members on their next request.
+func (s *Service) UpdateCustomRole(ctx context.Context, actor *User, workspace *Workspace, id string, input *UpdateCustomRoleInput) (*CustomRole, error) {
+	customRole, err := s.GetCustomRoleByID(ctx, workspace.ID, id)
+	if err != nil {
+		return nil, err
+	}
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if err := s.ensureCustomRoleNameAvailable(ctx, workspace.ID, name, customRole.ID); err != nil {
+			return nil, err
+		}
+		customRole.Name = name
+	}
+	if input.Description != nil {
+		customRole.Description = strings.TrimSpace(*input.Description)
+	}
+	if input.Permissions != nil {
+		permissions, err := normalizePermissions(*input.Permissions)
+		if err != nil {
+			return nil, err
+		}
+		customRole.Permissions = permissions
+	}
+	if err := s.storage.customRole.UpdateOne(ctx, customRole); err != nil {
+		return nil, ErrAccountOperationFailed(err)
+	}
+	return customRole, nil
+}
+
+// DeleteCustomRole deletes a custom role no member, pending invitation or SSO provisioning rule has.
+func (s *Service) DeleteCustomRole(ctx context.Context, actor *User, workspace *Workspace, id string) error {
+	customRole, err := s.GetCustomRoleByID(ctx, workspace.ID, id)
+	if err != nil {
+		return err
+	}
+	members, err := s.storage.user.Count(ctx,
+		s.storage.user.ScopeWorkspaceID(workspace.ID),
+		s.storage.user.ScopeEquals(UserSchema.CustomRoleID, customRole.ID),
+	)
+	if err != nil {
+		return ErrAccountOperationFailed(err)
+	}
+	invitations, err := s.storage.invitation.Count(ctx,
+		s.storage.invitation.ScopeWorkspaceID(workspace.ID),
+		s.storage.invitation.ScopeEquals(UserInvitationSchema.CustomRoleID, customRole.ID),
+		s.storage.invitation.ScopeEquals(UserInvitationSchema.Status, string(InvitationStatusPending)),
+	)
+	if err != nil {
+		return ErrAccountOperationFailed(err)
+	}
+	provisioning, err := s.storage.sso.Count(ctx,
+		s.storage.sso.ScopeWorkspaceID(workspace.ID),
+		s.storage.sso.ScopeWhere("default_custom_role_id = ? OR role_mappings @> ?::jsonb", customRole.ID, `[{"customRoleId":"`+customRole.ID+`"}]`),
+	)
+	if err != nil {
+		return ErrAccountOperationFailed(err)
+	}
+	if members > 0 || invitations > 0 || provisioning > 0 {
+		return ErrCustomRoleInUse(nil)
+	}
+	if err := s.storage.customRole.DeleteOne(ctx, customRole); err != nil {
+		return ErrAccountOperationFailed(err)
+	}
+	return nil
+}
+
+// resolveCustomRoleAssignment returns the custom role to give a user of the role, or nil when
+// customRoleID is empty. Only members with the user role can have one.
+func (s *Service) resolveCustomRoleAssignment(ctx context.Context, workspaceID string, r role.Role, customRoleID string) (*CustomRole, error) {
+	customRoleID = strings.TrimSpace(customRoleID)
+	if customRoleID == "" {
+		return nil, nil
+	}
+	if r != role.RoleUser {
+		return nil, ErrCustomRoleMembersOnly(nil)
+	}
+	return s.GetCustomRoleByID(ctx, workspaceID, customRoleID)
+}
+
+func (s *Service) ensureCustomRoleNameAvailable(ctx context.Context, workspaceID, name, exceptID string) error {
+	scopes := []func(db *gorm.DB) *gorm.DB{
+		s.storage.customRole.ScopeWorkspaceID(workspaceID),
+		s.storage.customRole.ScopeWhere("LOWER(name) = LOWER(?)", name),
+	}
+	if exceptID != "" {
+		scopes = append(scopes, s.storage.customRole.ScopeNotEquals(CustomRoleSchema.ID, exceptID))
+	}
+	taken, err := s.storage.customRole.Count(ctx, scopes...)
+	if err != nil {
+		return ErrAccountOperationFailed(err)
+	}
+	if taken > 0 {
+		return ErrCustomRoleNameTaken(nil)
+	}
+	return nil
+}
+
+// normalizePermissions validates permissions against the permission matrix and returns them
+// deduplicated and sorted.
+func normalizePermissions(permissions []string) (PermissionList, error) {
+	normalized := make(PermissionList, 0, len(permissions))
+	for _, p := range permissions {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if !role.IsGrantable(p) {
+			return nil, ErrInvalidPermission(p)
+		}
+		if !slices.Contains(normalized, p) {
+			normalized = append(normalized, p)
+		}
+	}
+	slices.Sort(normalized)
+	return normalized, nil
+}
@@ -0,0 +1,165 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/bus"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/hash"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/throttle"
+)
+
+// RequestEmailChange starts moving the actor's login email to input.NewEmail by sending a
+// confirmation link to the new address. Nothing changes until that link is used.
+func (s *Service) RequestEmailChange(ctx context.Context, actor *User, input *RequestEmailChangeInput) (string, error) {
+	newEmail := strings.ToLower(strings.TrimSpace(input.NewEmail))
+	if !throttle.Allow(s.storage.cache, fmt.Sprintf("rl:auth:email_change:%s", actor.ID), time.Hour, 5, 30*time.Second) {
+		return "", ErrAuthRateLimited(nil)
+	}
+	if newEmail == strings.ToLower(actor.Email) {
+		return "", ErrEmailUnchanged(nil)
+	}
+	// Users who sign in with Google or SSO have no password to confirm.
+	if actor.Password != "" && !hash.ValidatePassword(input.Password, actor.Password) {
+		return "", ErrCurrentPasswordInvalid(nil)
+	}
+	if err := s.ensureEmailAvailable(ctx, newEmail); err != nil {
+		return "", err
+	}
+	token, expAt, err := s.storage.CreateEmailChangeToken(&EmailChangePayload{
+		UserID:      actor.ID,
+		WorkspaceID: actor.WorkspaceID,
+		OldEmail:    actor.Email,
+		NewEmail:    newEmail,
+	})
+	if err != nil {
+		return "", ErrAccountOperationFailed(err)
+	}
+	if err := s.Notification.SendEmailChangeConfirmationEmail(ctx, actor, newEmail, token, expAt); err != nil {
+		logger.FromContext(ctx).Error("Failed to send email change confirmation email", "error", err)
+	}
+	return token, nil
+}
+
+// ConfirmEmailChange applies the email change of a confirmation token and sends the old
+// address a notice with a link to undo it. Sessions are kept.
+func (s *Service) ConfirmEmailChange(ctx context.Context, token string) error {
+	payload, err := s.storage.GetEmailChangeToken(token)
+	if err != nil {
+		return ErrInvalidOrExpiredToken(err)
+	}
+	user, err := s.applyEmailChange(ctx, payload.UserID, payload.OldEmail, payload.NewEmail, false)
+	if err != nil {
+		return err
+	}
+	if err := s.storage.ConsumeEmailChangeToken(token); err != nil {
+		return ErrAccountOperationFailed(err)
+	}
+	undoToken, expAt, err := s.storage.CreateEmailChangeUndoToken(&EmailChangePayload{
+		UserID:      user.ID,
+		WorkspaceID: user.WorkspaceID,
+		OldEmail:    payload.OldEmail,
+		NewEmail:    payload.NewEmail,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to create email change undo token", "error", err, "user_id", user.ID)
+		return nil
+	}
+	if err := s.Notification.SendEmailChangedNoticeEmail(ctx, user, payload.OldEmail, undoToken, expAt); err != nil {
+		logger.FromContext(ctx).Error("Failed to send email changed notice email", "error", err)
+	}
+	return nil
+}
+
+// UndoEmailChange puts back the old email of an undo token. As the change may not have been
+// made by the owner of the account, every session is revoked.
+func (s *Service) UndoEmailChange(ctx context.Context, token string) error {
+	payload, err := s.storage.GetEmailChangeUndoToken(token)
+	if err != nil {
+		return ErrInvalidOrExpiredToken(err)
+	}
+	user, err := s.applyEmailChange(ctx, payload.UserID, payload.NewEmail, payload.OldEmail, true)
+	if err != nil {
+		return err
+	}
+	if err := s.storage.ConsumeEmailChangeUndoToken(token); err != nil {
+		return ErrAccountOperationFailed(err)
+	}
+	if err := s.storage.RevokeAllSessionsForUser(ctx, user.ID); err != nil {
+		return ErrAccountOperationFailed(err)
+	}
+	return nil
+}
+
+// applyEmailChange moves a user's email from `from` to `to`, along with the invitation
+// records of their workspace sent to `from`, and emits bus.AccountEmailChangedTopic. The
+// token is stale when the user's email is no longer `from`. signOut bumps AuthVersion so
+// access tokens stop working.
+func (s *Service) applyEmailChange(ctx context.Context, userID, from, to string, signOut bool) (*User, error) {
+	user, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, ErrInvalidOrExpiredToken(err)
+	}
+	if !strings.EqualFold(user.Email, from) {
+		return nil, ErrInvalidOrExpiredToken(nil)
+	}
+	if err := s.ensureEmailAvailable(ctx, to); err != nil {
+		return nil, err
+	}
+	err = s.atomicProcessor.Exec(ctx, func(txCtx context.Context) error {
+		user.Email = to
+		user.IsEmailVerified = true
+		if signOut {
+			user.AuthVersion++
+		}
+		if err := s.storage.user.UpdateOne(txCtx, user); err != nil {
+			if database.IsUniqueViolation(err) {
+				return ErrUserAlreadyExists(err)
+			}
+			return ErrAccountOperationFailed(err)
+		}
+		invitations, err := s.storage.invitation.FindMany(txCtx,
+			s.storage.invitation.ScopeWorkspaceID(user.WorkspaceID),
+			s.storage.invitation.ScopeEquals(UserInvitationSchema.Email, from),
+		)
+		if err != nil {
+			return ErrAccountOperationFailed(err)
+		}
+		if len(invitations) == 0 {
+			return nil
+		}
+		for _, inv := range invitations {
+			inv.Email = to
+		}
+		if err := s.storage.invitation.UpdateMany(txCtx, invitations); err != nil {
+			return ErrAccountOperationFailed(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.bus.Emit(bus.AccountEmailChangedTopic, &bus.AccountEmailChangedEvent{
+		Ctx:         ctx,
+		UserID:      user.ID,
+		WorkspaceID: user.WorkspaceID,
+		IsOwner:     user.Workspace != nil && user.Workspace.OwnerID == user.ID,
+		OldEmail:    from,
+		NewEmail:    to,
+		ChangedAt:   time.Now().UTC(),
+	})
+	return user, nil
+}
+
+func (s *Service) ensureEmailAvailable(ctx context.Context, email string) error {
+	if _, err := s.GetUserByEmail(ctx, email); err == nil {
+		return ErrUserAlreadyExists(nil)
+	} else if !database.IsRecordNotFound(err) {
+		return ErrAccountOperationFailed(err)
+	}
+	return nil
+}
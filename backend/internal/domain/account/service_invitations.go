@@ -0,0 +1,212 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/throttle"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+	"github.com/spf13/viper"
+)
+
+// TeamSeatLimit tells whether a workspace's plan has room for another team member when
+// `used` seats are taken. The billing service implements it; account cannot import billing.
+type TeamSeatLimit interface {
+	CheckTeamMemberLimit(ctx context.Context, workspaceID string, used int64) error
+}
+
+// SetTeamSeatLimit sets the plan check bulk invitations run before each row. Without one,
+// only the route's plan middleware limits them.
+func (s *Service) SetTeamSeatLimit(limit TeamSeatLimit) {
+	s.teamSeatLimit = limit
+}
+
+// BulkInviteResult is the outcome of one row of a bulk invitation: either the invitation
+// created or the error that stopped it.
+type BulkInviteResult struct {
+	Email      string
+	Invitation *UserInvitation
+	Err        error
+}
+
+// BulkInviteUsers invites every row of input, one at a time, and reports each row's outcome.
+// A failing row does not stop the others. Rows are also checked against the plan's team
+// member limit, counting the workspace's users plus the invitations created earlier in the
+// batch.
+func (s *Service) BulkInviteUsers(ctx context.Context, actor *User, workspaceID string, input *BulkInviteInput) ([]*BulkInviteResult, error) {
+	workspace, err := s.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	seats, err := s.CountWorkspaceUsers(ctx, workspaceID)
+	if err != nil {
+		return nil, ErrAccountOperationFailed(err)
+	}
+	results := make([]*BulkInviteResult, 0, len(input.Invitations))
+	for _, row := range input.Invitations {
+		result := &BulkInviteResult{Email: row.Email}
+		results = append(results, result)
+		if s.teamSeatLimit != nil {
+			if err := s.teamSeatLimit.CheckTeamMemberLimit(ctx, workspaceID, seats); err != nil {
+				result.Err = err
+				continue
+			}
+		}
+		invitation, err := s.inviteUser(ctx, actor, workspace, row.Email, row.Role, row.CustomRoleID, input.ExpiresInDays)
+		if err != nil {
+			result.Err = err
+			continue
+		}
+		result.Invitation = invitation
+		seats++
+	}
+	return results, nil
+}
+
+// ResendInvitation sends a pending invitation again with a new link and a new expiry, which
+// also revives invitations whose link has expired. Links sent before stop working.
+func (s *Service) ResendInvitation(ctx context.Context, actor *User, workspaceID, invitationID string, input *ResendInvitationInput) (*UserInvitation, error) {
+	invitation, err := s.storage.invitation.FindOne(ctx,
+		s.storage.invitation.ScopeWorkspaceID(workspaceID),
+		s.storage.invitation.ScopeID(invitationID),
+	)
+	if err != nil {
+		return nil, ErrInvitationNotFound(err)
+	}
+	if invitation.Status != InvitationStatusPending {
+		return nil, ErrInvitationCannotBeResent(nil)
+	}
+	// Abuse protection: prevent spamming the invitee (best-effort, cache-backed).
+	if !throttle.Allow(s.storage.cache, fmt.Sprintf("rl:invitation:resend:%s", invitation.ID), time.Hour, 5, time.Minute) {
+		return nil, ErrAuthRateLimited(nil)
+	}
+	workspace, err := s.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := invitationExpiry(input.ExpiresInDays)
+	invitation.ExpiresAt = &expiresAt
+	invitation.TokenVersion++
+	if err := s.storage.invitation.UpdateOne(ctx, invitation); err != nil {
+		return nil, ErrAccountOperationFailed(err)
+	}
+	if err := s.sendInvitation(ctx, actor, workspace, invitation); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// inviteUser creates an invitation to workspace and emails it. expiresInDays of zero uses
+// the configured expiry.
+func (s *Service) inviteUser(ctx context.Context, actor *User, workspace *Workspace, email string, role role.Role, customRoleID string, expiresInDays int) (*UserInvitation, error) {
+	customRole, err := s.resolveCustomRoleAssignment(ctx, workspace.ID, role, customRoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if user already exists in the workspace
+	existingUser, err := s.GetUserByEmail(ctx, email)
+	if err == nil && existingUser != nil {
+		if existingUser.WorkspaceID == workspace.ID {
+			return nil, ErrUserAlreadyExists(nil)
+		}
+	}
+
+	// Check if there's already a pending invitation
+	existingInvitation, err := s.storage.invitation.FindOne(
+		ctx,
+		s.storage.invitation.ScopeWorkspaceID(workspace.ID),
+		s.storage.invitation.ScopeEquals(UserInvitationSchema.Email, email),
+		s.storage.invitation.ScopeEquals(UserInvitationSchema.Status, string(InvitationStatusPending)),
+	)
+	if err == nil && existingInvitation != nil {
+		return nil, ErrInvitationAlreadyExists(nil)
+	}
+
+	// Create the invitation record
+	expiresAt := invitationExpiry(expiresInDays)
+	invitation := &UserInvitation{
+		WorkspaceID: workspace.ID,
+		Email:       email,
+		Role:        role,
+		InviterID:   actor.ID,
+		Status:      InvitationStatusPending,
+		ExpiresAt:   &expiresAt,
+	}
+	if customRole != nil {
+		invitation.CustomRoleID = transformer.ToNullString(customRole.ID)
+	}
+
+	if err := s.storage.invitation.CreateOne(ctx, invitation); err != nil {
+		return nil, ErrAccountOperationFailed(err)
+	}
+	if err := s.sendInvitation(ctx, actor, workspace, invitation); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// sendInvitation creates a link for the invitation's current token version and emails it.
+// Email failures are logged, not returned.
+func (s *Service) sendInvitation(ctx context.Context, actor *User, workspace *Workspace, invitation *UserInvitation) error {
+	payload := &WorkspaceInvitationPayload{
+		InvitationID: invitation.ID,
+		WorkspaceID:  invitation.WorkspaceID,
+		Email:        invitation.Email,
+		Role:         string(invitation.Role),
+		InviterID:    actor.ID,
+		TokenVersion: invitation.TokenVersion,
+	}
+	if invitation.ExpiresAt != nil {
+		payload.ExpAt = *invitation.ExpiresAt
+	}
+	token, expAt, err := s.storage.CreateWorkspaceInvitationToken(payload)
+	if err != nil {
+		return ErrAccountOperationFailed(err)
+	}
+
+	inviterName := fmt.Sprintf("%s %s", actor.FirstName, actor.LastName)
+	if inviterName == " " {
+		inviterName = actor.Email
+	}
+	workspaceName := fmt.Sprintf("Workspace %s", workspace.ID) // You may want to add a Name field to Workspace
+
+	err = s.Notification.SendWorkspaceInvitationEmail(ctx, invitation.Email, workspaceName, inviterName, actor.Email, string(invitation.Role), token, expAt)
+	if err != nil {
+		// Log error but don't fail
+		logger.FromContext(ctx).Error("Failed to send workspace invitation email", "error", err, "invitation_id", invitation.ID)
+	}
+	return nil
+}
+
+// ensureInvitationRedeemable checks that the invitation of a token can still be accepted.
+// Tokens from before the last resend are refused.
+func ensureInvitationRedeemable(invitation *UserInvitation, payload *WorkspaceInvitationPayload) error {
+	if invitation.Status == InvitationStatusAccepted {
+		return ErrInvitationAlreadyAccepted(nil)
+	}
+	if invitation.Status == InvitationStatusExpired || invitation.Status == InvitationStatusRevoked {
+		return ErrInvitationExpired(nil)
+	}
+	if invitation.TokenVersion != payload.TokenVersion {
+		return ErrInvalidInvitationToken(nil)
+	}
+	if invitation.ExpiresAt != nil && time.Now().After(*invitation.ExpiresAt) {
+		return ErrInvitationExpired(nil)
+	}
+	return nil
+}
+
+// invitationExpiry returns when an invitation sent now expires: after `days` days, or after
+// the configured expiry when days is zero.
+func invitationExpiry(days int) time.Time {
+	if days > 0 {
+		return time.Now().AddDate(0, 0, days)
+	}
+	return time.Now().Add(time.Duration(viper.GetInt32(config.WorkspaceInvitationTokenExpirySeconds)) * time.Second)
+}
@@ -0,0 +1,69 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/throttle"
+)
+
+// RequestMagicLink emails a one-time sign-in link to the user with the email. Unknown emails
+// return ErrInvalidCredentials, which callers must not reveal.
+func (s *Service) RequestMagicLink(ctx context.Context, email string) (string, error) {
+	normalizedEmail := strings.ToLower(strings.TrimSpace(email))
+	// Abuse protection: prevent spamming sign-in emails (best-effort, cache-backed).
+	if !throttle.Allow(s.storage.cache, fmt.Sprintf("rl:auth:magic_link:%s", normalizedEmail), time.Hour, 5, 30*time.Second) {
+		return "", ErrAuthRateLimited(nil)
+	}
+
+	user, err := s.GetUserByEmail(ctx, email)
+	if err != nil {
+		return "", ErrInvalidCredentials(err)
+	}
+	token, expAt, err := s.storage.CreateMagicLinkToken(&MagicLinkPayload{
+		UserID:      user.ID,
+		WorkspaceID: user.WorkspaceID,
+		Email:       user.Email,
+	})
+	if err != nil {
+		return "", ErrAccountOperationFailed(err)
+	}
+	if err := s.Notification.SendMagicLinkEmail(ctx, user, token, expAt); err != nil {
+		logger.FromContext(ctx).Error("Failed to send magic link email", "error", err)
+	}
+	return token, nil
+}
+
+// LoginWithMagicLink redeems a sign-in link and issues tokens. The link is consumed before
+// anything else so it can never be used twice. Redeeming it proves the user owns the inbox,
+// so the email is marked verified.
+func (s *Service) LoginWithMagicLink(ctx context.Context, token, clientIP, userAgent string) (*LoginResponse, error) {
+	payload, err := s.storage.GetMagicLinkToken(token)
+	if err != nil {
+		return nil, ErrInvalidOrExpiredToken(err)
+	}
+	if err := s.storage.ConsumeMagicLinkToken(token); err != nil {
+		return nil, ErrAccountOperationFailed(err)
+	}
+	user, err := s.GetUserByID(ctx, payload.UserID)
+	if err != nil {
+		return nil, ErrInvalidOrExpiredToken(err)
+	}
+	if !strings.EqualFold(user.Email, payload.Email) {
+		return nil, ErrInvalidOrExpiredToken(nil)
+	}
+	if !user.IsEmailVerified {
+		user.IsEmailVerified = true
+		if err := s.storage.user.UpdateOne(ctx, user); err != nil {
+			return nil, ErrAccountOperationFailed(err)
+		}
+	}
+	tokens, err := s.issueTokensForUser(ctx, user, clientIP, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	return ToLoginResponse(user, tokens.Token, tokens.RefreshToken), nil
+}
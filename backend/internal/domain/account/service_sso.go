@@ -0,0 +1,477 @@
+package account
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/spf13/viper"
+)
+
+// SSOEntitlement tells whether a workspace's plan includes single sign-on. The billing
+// service implements it; account cannot import billing.
+type SSOEntitlement interface {
+	CanUseSingleSignOn(ctx context.Context, workspaceID string) error
+}
+
+// SetSSOEntitlement sets the plan check SSO sign-in runs before the user is known. Without
+// one, SSO sign-in is refused.
+func (s *Service) SetSSOEntitlement(entitlement SSOEntitlement) {
+	s.ssoEntitlement = entitlement
+}
+
+// SetOIDCProvider replaces the provider used for OIDC single sign-on.
+func (s *Service) SetOIDCProvider(provider auth.OIDCProvider) {
+	s.oidc = provider
+}
+
+// TXTResolver looks up DNS TXT records; *net.Resolver implements it. Tests inject a fake so
+// domain verification runs without real DNS.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// SetTXTResolver replaces the resolver used to verify SSO domains.
+func (s *Service) SetTXTResolver(resolver TXTResolver) {
+	s.txtResolver = resolver
+}
+
+const (
+	// ssoVerificationRecordPrefix names the TXT record proving domain ownership, below the domain.
+	ssoVerificationRecordPrefix = "_kyora-verification."
+	ssoVerificationValuePrefix  = "kyora-verification="
+	ssoVerificationTimeout      = 10 * time.Second
+)
+
+// GetSSOConnection returns the single sign-on setup of a workspace.
+func (s *Service) GetSSOConnection(ctx context.Context, workspaceID string) (*SSOConnection, error) {
+	conn, err := s.storage.sso.FindOne(ctx, s.storage.sso.ScopeWorkspaceID(workspaceID))
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrSSONotConfigured(err)
+		}
+		return nil, ErrAccountOperationFailed(err)
+	}
+	return conn, nil
+}
+
+// PutSSOConnection creates or replaces the single sign-on setup of a workspace.
+func (s *Service) PutSSOConnection(ctx context.Context, actor *User, workspace *Workspace, input *PutSSOConnectionInput) (*SSOConnection, error) {
+	// SAML sign-in is not implemented, so a SAML connection could never sign anyone in
+	if input.Protocol != SSOProtocolOIDC {
+		return nil, ErrSSOProtocolNotSupported(input.Protocol)
+	}
+	conn, err := s.storage.sso.FindOne(ctx, s.storage.sso.ScopeWorkspaceID(workspace.ID))
+	if err != nil {
+		if !database.IsRecordNotFound(err) {
+			return nil, ErrAccountOperationFailed(err)
+		}
+		conn = &SSOConnection{WorkspaceID: workspace.ID}
+	}
+
+	domain := strings.ToLower(strings.TrimSpace(input.Domain))
+	// a public mail domain is shared by strangers, whom the connection would sign in
+	if isBlockedSSODomain(domain) {
+		return nil, ErrSSOPublicDomain(domain)
+	}
+	if err := s.ensureSSODomainUnclaimed(ctx, workspace.ID, domain); err != nil {
+		return nil, err
+	}
+	// a new domain must be proven with a new token
+	if conn.Domain != domain || conn.VerificationToken == "" {
+		token, err := id.RandomString(32)
+		if err != nil {
+			return nil, ErrAccountOperationFailed(err)
+		}
+		conn.Domain = domain
+		conn.VerificationToken = token
+		conn.VerifiedAt = sql.NullTime{}
+	}
+	conn.Protocol = input.Protocol
+	conn.Enabled = input.Enabled
+
+	issuer := strings.TrimSpace(input.OIDCIssuer)
+	clientID := strings.TrimSpace(input.OIDCClientID)
+	if issuer == "" || clientID == "" {
+		return nil, ErrSSOInvalidConfig("oidcIssuer and oidcClientId are required for OIDC")
+	}
+	if err := validateOIDCIssuer(issuer); err != nil {
+		return nil, err
+	}
+	if secret := strings.TrimSpace(input.OIDCClientSecret); secret != "" {
+		conn.OIDCClientSecret = secret
+	}
+	// a stored secret belongs to the client it was given for
+	if conn.OIDCClientSecret == "" || (input.OIDCClientSecret == "" && (conn.OIDCIssuer != issuer || conn.OIDCClientID != clientID)) {
+		return nil, ErrSSOInvalidConfig("oidcClientSecret is required for a new OIDC client")
+	}
+	conn.OIDCIssuer = issuer
+	conn.OIDCClientID = clientID
+
+	conn.JITProvisioning = input.JITProvisioning
+	conn.GroupsClaim = strings.TrimSpace(input.GroupsClaim)
+	conn.DefaultRole = input.DefaultRole
+	if conn.DefaultRole == "" {
+		conn.DefaultRole = role.RoleUser
+	}
+	defaultCustomRole, err := s.resolveCustomRoleAssignment(ctx, workspace.ID, conn.DefaultRole, input.DefaultCustomRoleID)
+	if err != nil {
+		return nil, err
+	}
+	conn.DefaultCustomRoleID = sql.NullString{}
+	if defaultCustomRole != nil {
+		conn.DefaultCustomRoleID = sql.NullString{String: defaultCustomRole.ID, Valid: true}
+	}
+	mappings := make(SSORoleMappings, 0, len(input.RoleMappings))
+	for _, m := range input.RoleMappings {
+		customRole, err := s.resolveCustomRoleAssignment(ctx, workspace.ID, m.Role, m.CustomRoleID)
+		if err != nil {
+			return nil, err
+		}
+		mapping := SSORoleMapping{Group: strings.TrimSpace(m.Group), Role: m.Role}
+		if customRole != nil {
+			mapping.CustomRoleID = customRole.ID
+		}
+		mappings = append(mappings, mapping)
+	}
+	conn.RoleMappings = mappings
+
+	// only a workspace that proved it owns the domain may sign its users in
+	if input.Enabled && !conn.VerifiedAt.Valid {
+		return nil, ErrSSODomainNotVerified(nil)
+	}
+
+	if conn.ID == "" {
+		err = s.storage.sso.CreateOne(ctx, conn)
+	} else {
+		err = s.storage.sso.UpdateOne(ctx, conn)
+	}
+	if err != nil {
+		return nil, ErrAccountOperationFailed(err)
+	}
+	return conn, nil
+}
+
+// VerifySSODomain checks the DNS TXT record proving that the workspace owns the domain of its
+// SSO connection, and marks the domain verified when the record carries the connection's
+// token. Verifying an already verified domain is a no-op.
+func (s *Service) VerifySSODomain(ctx context.Context, actor *User, workspace *Workspace) (*SSOConnection, error) {
+	conn, err := s.GetSSOConnection(ctx, workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+	if conn.VerifiedAt.Valid {
+		return conn, nil
+	}
+	lookupCtx, cancel := context.WithTimeout(ctx, ssoVerificationTimeout)
+	defer cancel()
+	records, err := s.txtResolver.LookupTXT(lookupCtx, conn.VerificationRecordName())
+	if err != nil {
+		return nil, ErrSSODomainNotVerified(err)
+	}
+	if !slices.ContainsFunc(records, func(r string) bool { return strings.TrimSpace(r) == conn.VerificationRecordValue() }) {
+		return nil, ErrSSODomainNotVerified(nil)
+	}
+	if err := s.ensureSSODomainUnclaimed(ctx, workspace.ID, conn.Domain); err != nil {
+		return nil, err
+	}
+	conn.VerifiedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	if err := s.storage.sso.UpdateOne(ctx, conn); err != nil {
+		// another workspace verified the domain concurrently
+		if database.IsUniqueViolation(err) {
+			return nil, ErrSSODomainTaken(err)
+		}
+		return nil, ErrAccountOperationFailed(err)
+	}
+	return conn, nil
+}
+
+// ensureSSODomainUnclaimed checks that no other workspace has verified domain.
+func (s *Service) ensureSSODomainUnclaimed(ctx context.Context, workspaceID, domain string) error {
+	taken, err := s.storage.sso.Count(ctx,
+		s.storage.sso.ScopeEquals(SSOConnectionSchema.Domain, domain),
+		s.storage.sso.ScopeNotEquals(SSOConnectionSchema.WorkspaceID, workspaceID),
+		s.storage.sso.ScopeWhere(SSOConnectionSchema.VerifiedAt.Column()+" IS NOT NULL"),
+	)
+	if err != nil {
+		return ErrAccountOperationFailed(err)
+	}
+	if taken > 0 {
+		return ErrSSODomainTaken(nil)
+	}
+	return nil
+}
+
+// DeleteSSOConnection removes the single sign-on setup of a workspace. Users it provisioned
+// keep their accounts.
+func (s *Service) DeleteSSOConnection(ctx context.Context, actor *User, workspace *Workspace) error {
+	conn, err := s.GetSSOConnection(ctx, workspace.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.storage.sso.DeleteOne(ctx, conn); err != nil {
+		return ErrAccountOperationFailed(err)
+	}
+	return nil
+}
+
+// StartSSOLogin starts signing in a user of email through the identity provider of the
+// workspace that set up SSO for the email's domain. It returns the provider URL to send the
+// user to and the state the provider hands back to the SSO redirect URL.
+func (s *Service) StartSSOLogin(ctx context.Context, email string) (url string, state string, err error) {
+	// several workspaces may claim a domain, but only the one that verified it signs its users in
+	conn, err := s.storage.sso.FindOne(ctx,
+		s.storage.sso.ScopeEquals(SSOConnectionSchema.Domain, emailDomain(email)),
+		s.storage.sso.ScopeWhere(SSOConnectionSchema.VerifiedAt.Column()+" IS NOT NULL"),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return "", "", ErrSSONotConfigured(err)
+		}
+		return "", "", ErrAccountOperationFailed(err)
+	}
+	if err := s.ensureSSOAvailable(ctx, conn); err != nil {
+		return "", "", err
+	}
+	nonce, err := id.RandomString(32)
+	if err != nil {
+		return "", "", ErrAccountOperationFailed(err)
+	}
+	state, err = s.storage.CreateSSOStateToken(&SSOStatePayload{
+		ConnectionID: conn.ID,
+		WorkspaceID:  conn.WorkspaceID,
+		Nonce:        nonce,
+	})
+	if err != nil {
+		return "", "", ErrAccountOperationFailed(err)
+	}
+	url, err = s.oidc.AuthURL(ctx, conn.oidcClient(), state, nonce)
+	if err != nil {
+		return "", "", ErrAccountOperationFailed(err)
+	}
+	return url, state, nil
+}
+
+// CompleteSSOLogin signs in the user the identity provider authenticated for state. A user
+// signing in for the first time is provisioned when the connection allows it.
+func (s *Service) CompleteSSOLogin(ctx context.Context, state, code, clientIP, userAgent string) (*LoginResponse, error) {
+	payload, err := s.storage.GetSSOStateToken(state)
+	if err != nil {
+		return nil, ErrInvalidOrExpiredToken(err)
+	}
+	// state is single use, whether the sign-in succeeds or not
+	_ = s.storage.ConsumeSSOStateToken(state)
+
+	conn, err := s.storage.sso.FindOne(ctx,
+		s.storage.sso.ScopeID(payload.ConnectionID),
+		s.storage.sso.ScopeWorkspaceID(payload.WorkspaceID),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrSSONotConfigured(err)
+		}
+		return nil, ErrAccountOperationFailed(err)
+	}
+	if err := s.ensureSSOAvailable(ctx, conn); err != nil {
+		return nil, err
+	}
+	identity, err := s.oidc.Exchange(ctx, conn.oidcClient(), code, payload.Nonce)
+	if err != nil {
+		return nil, ErrSSOLoginFailed(err)
+	}
+	if emailDomain(identity.Email) != conn.Domain {
+		return nil, ErrSSODomainMismatch(nil)
+	}
+
+	user, err := s.findSSOUser(ctx, conn.WorkspaceID, identity.Email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		if user, err = s.provisionSSOUser(ctx, conn, identity); err != nil {
+			return nil, err
+		}
+	}
+
+	tokens, err := s.issueTokensForUser(ctx, user, clientIP, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	return ToLoginResponse(user, tokens.Token, tokens.RefreshToken), nil
+}
+
+// findSSOUser returns the member of the workspace signing in as email, or nil when there is
+// none yet. Emails are matched regardless of case: an account of another workspace, however
+// its email is cased, refuses the sign-in rather than being linked or shadowed by a new
+// account.
+func (s *Service) findSSOUser(ctx context.Context, workspaceID, email string) (*User, error) {
+	users, err := s.storage.user.FindMany(ctx,
+		s.storage.user.ScopeWhere("LOWER("+UserSchema.Email.Column()+") = ?", strings.ToLower(email)),
+		s.storage.user.WithPreload(WorkspaceStruct),
+		s.storage.user.WithPreload(CustomRoleStruct),
+	)
+	if err != nil {
+		return nil, ErrAccountOperationFailed(err)
+	}
+	var match *User
+	for _, u := range users {
+		if u.WorkspaceID != workspaceID {
+			return nil, ErrSSOUserInOtherWorkspace(nil)
+		}
+		if match == nil || u.Email == email {
+			match = u
+		}
+	}
+	return match, nil
+}
+
+// ensureSSOAvailable checks that users can sign in through conn right now.
+func (s *Service) ensureSSOAvailable(ctx context.Context, conn *SSOConnection) error {
+	if !conn.Enabled || !conn.VerifiedAt.Valid {
+		return ErrSSONotConfigured(nil)
+	}
+	if s.ssoEntitlement == nil {
+		return ErrAccountOperationFailed(errors.New("sso entitlement check not configured"))
+	}
+	if err := s.ssoEntitlement.CanUseSingleSignOn(ctx, conn.WorkspaceID); err != nil {
+		return err
+	}
+	if conn.Protocol != SSOProtocolOIDC {
+		return ErrSSOProtocolNotSupported(conn.Protocol)
+	}
+	if viper.GetString(config.SSORedirectURL) == "" {
+		return ErrAccountOperationFailed(errors.New("sso redirect url not configured"))
+	}
+	return nil
+}
+
+// provisionSSOUser creates the account of a user the identity provider signed in for the
+// first time, with the role mapped from their groups.
+func (s *Service) provisionSSOUser(ctx context.Context, conn *SSOConnection, identity *auth.OIDCIdentity) (*User, error) {
+	if !conn.JITProvisioning {
+		return nil, ErrSSOUserNotProvisioned(nil)
+	}
+	r, customRoleID := conn.provisionedRole(identity.Groups)
+	firstName, lastName := identity.GivenName, identity.FamilyName
+	if firstName == "" && lastName == "" {
+		firstName, lastName, _ = strings.Cut(strings.TrimSpace(identity.Name), " ")
+	}
+	if firstName == "" {
+		firstName, _, _ = strings.Cut(identity.Email, "@")
+	}
+	user := &User{
+		WorkspaceID:     conn.WorkspaceID,
+		Role:            r,
+		CustomRoleID:    customRoleID,
+		FirstName:       firstName,
+		LastName:        lastName,
+		Email:           identity.Email,
+		Password:        "", // signs in through the identity provider
+		IsEmailVerified: true,
+	}
+	if err := s.storage.user.CreateOne(ctx, user); err != nil {
+		return nil, ErrAccountOperationFailed(err)
+	}
+	return s.GetUserByID(ctx, user.ID)
+}
+
+// VerificationRecordName is the DNS name of the TXT record proving the workspace owns Domain.
+func (m *SSOConnection) VerificationRecordName() string {
+	return ssoVerificationRecordPrefix + m.Domain
+}
+
+// VerificationRecordValue is the content the verification TXT record must have.
+func (m *SSOConnection) VerificationRecordValue() string {
+	return ssoVerificationValuePrefix + m.VerificationToken
+}
+
+func (m *SSOConnection) oidcClient() auth.OIDCClient {
+	return auth.OIDCClient{
+		Issuer:       m.OIDCIssuer,
+		ClientID:     m.OIDCClientID,
+		ClientSecret: m.OIDCClientSecret,
+		RedirectURL:  viper.GetString(config.SSORedirectURL),
+		GroupsClaim:  m.GroupsClaim,
+	}
+}
+
+// validateOIDCIssuer checks that issuer is an https URL on a public domain name and the
+// standard port, the only addresses the OIDC provider connects to.
+func validateOIDCIssuer(issuer string) error {
+	u, err := url.Parse(issuer)
+	if err != nil || u.Hostname() == "" {
+		return ErrSSOInvalidConfig("oidcIssuer is not a valid URL")
+	}
+	if u.Scheme != "https" || u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return ErrSSOInvalidConfig("oidcIssuer must be an https URL without credentials, query or fragment")
+	}
+	host := strings.ToLower(u.Hostname())
+	if net.ParseIP(host) != nil || host == "localhost" || !strings.Contains(host, ".") {
+		return ErrSSOInvalidConfig("oidcIssuer must use the provider's public domain name")
+	}
+	if port := u.Port(); port != "" && port != "443" {
+		return ErrSSOInvalidConfig("oidcIssuer must be served on the standard https port")
+	}
+	return nil
+}
+
+// publicMailDomains are the domains of mail providers anyone can sign up with.
+var publicMailDomains = []string{
+	"gmail.com", "googlemail.com",
+	"outlook.com", "hotmail.com", "live.com", "msn.com", "passport.com",
+	"yahoo.com", "ymail.com", "rocketmail.com", "aol.com", "aim.com",
+	"icloud.com", "me.com", "mac.com",
+	"proton.me", "protonmail.com", "pm.me", "tutanota.com", "tutanota.de", "tuta.io",
+	"zoho.com", "zohomail.com", "fastmail.com", "fastmail.fm", "hey.com", "hushmail.com",
+	"mail.com", "email.com", "gmx.com", "gmx.net", "gmx.de", "web.de", "t-online.de",
+	"yandex.com", "yandex.ru", "mail.ru", "inbox.ru", "list.ru", "bk.ru", "rambler.ru",
+	"qq.com", "163.com", "126.com", "sina.com", "naver.com", "daum.net",
+	"orange.fr", "free.fr", "laposte.net", "libero.it", "virgilio.it", "seznam.cz", "wp.pl", "o2.pl",
+	"comcast.net", "verizon.net", "att.net", "sbcglobal.net",
+}
+
+// publicMailBrands are public mail providers that also serve country domains, such as
+// yahoo.co.uk or hotmail.fr.
+var publicMailBrands = []string{"gmail", "googlemail", "outlook", "hotmail", "live", "yahoo", "ymail", "gmx", "yandex", "aol"}
+
+// isBlockedSSODomain tells whether domain, or a domain it is under, belongs to a public mail
+// provider or is blocked by configuration.
+func isBlockedSSODomain(domain string) bool {
+	blocked := append(slices.Clone(publicMailDomains), viper.GetStringSlice(config.SSOBlockedDomains)...)
+	for _, b := range blocked {
+		b = strings.ToLower(strings.TrimSpace(b))
+		if b != "" && (domain == b || strings.HasSuffix(domain, "."+b)) {
+			return true
+		}
+	}
+	// country domains: the brand followed by a public suffix like fr, co.uk or com.br
+	brand, suffix, _ := strings.Cut(domain, ".")
+	if !slices.Contains(publicMailBrands, brand) {
+		return false
+	}
+	labels := strings.Split(suffix, ".")
+	if len(labels) > 2 {
+		return false
+	}
+	for _, l := range labels {
+		if len(l) > 3 {
+			return false
+		}
+	}
+	return true
+}
+
+func emailDomain(email string) string {
+	_, domain, _ := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	return domain
+}
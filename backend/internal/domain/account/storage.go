@@ -3,6 +3,7 @@ package account
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/abdelrahman146/kyora/internal/platform/cache"
@@ -16,6 +17,10 @@ const (
 	resetPasswordTokenPrefix       = "pwreset:"
 	verifyEmailTokenPrefix         = "emailverify:"
 	workspaceInvitationTokenPrefix = "invitation:"
+	ssoStateTokenPrefix            = "ssostate:"
+	emailChangeTokenPrefix         = "emailchange:"
+	emailChangeUndoTokenPrefix     = "emailchangeundo:"
+	magicLinkTokenPrefix           = "magiclink:"
 )
 
 type Storage struct {
@@ -24,15 +29,22 @@ type Storage struct {
 	user       *database.Repository[User]
 	invitation *database.Repository[UserInvitation]
 	session    *database.Repository[Session]
+	customRole *database.Repository[CustomRole]
+	sso        *database.Repository[SSOConnection]
 }
 
+// NewStorage creates the account storage. Accounts live in the home region, as they are
+// looked up before the workspace region is known.
 func NewStorage(db *database.Database, cache *cache.Cache) *Storage {
+	db = db.Global()
 	return &Storage{
 		cache:      cache,
 		workspace:  database.NewRepository[Workspace](db),
 		user:       database.NewRepository[User](db),
 		invitation: database.NewRepository[UserInvitation](db),
 		session:    database.NewRepository[Session](db),
+		customRole: database.NewRepository[CustomRole](db),
+		sso:        database.NewRepository[SSOConnection](db),
 	}
 }
 
@@ -156,9 +168,12 @@ type WorkspaceInvitationPayload struct {
 	Email        string    `json:"email"`
 	Role         string    `json:"role"`
 	InviterID    string    `json:"inviterId"`
+	TokenVersion int       `json:"tokenVersion"`
 	ExpAt        time.Time `json:"expAt"`
 }
 
+// CreateWorkspaceInvitationToken stores an invitation token until payload.ExpAt, or for the
+// configured expiry when ExpAt is zero.
 func (s *Storage) CreateWorkspaceInvitationToken(payload *WorkspaceInvitationPayload) (string, time.Time, error) {
 	token, err := id.RandomString(32) // Generate random token
 	if err != nil {
@@ -166,7 +181,14 @@ func (s *Storage) CreateWorkspaceInvitationToken(payload *WorkspaceInvitationPay
 	}
 	key := workspaceInvitationTokenPrefix + token
 	ttl := viper.GetInt32(config.WorkspaceInvitationTokenExpirySeconds)
-	payload.ExpAt = time.Now().Add(time.Duration(ttl) * time.Second)
+	if payload.ExpAt.IsZero() {
+		payload.ExpAt = time.Now().Add(time.Duration(ttl) * time.Second)
+	} else {
+		ttl = int32(time.Until(payload.ExpAt).Seconds())
+		if ttl <= 0 {
+			return "", time.Time{}, errors.New("invitation token expiry is in the past")
+		}
+	}
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		return "", time.Time{}, err
@@ -194,3 +216,151 @@ func (s *Storage) GetWorkspaceInvitationToken(token string) (*WorkspaceInvitatio
 func (s *Storage) ConsumeWorkspaceInvitationToken(token string) error {
 	return s.cache.Delete(workspaceInvitationTokenPrefix + token)
 }
+
+// SSOStatePayload ties an SSO sign-in the identity provider sends back to the connection
+// it was started for. Nonce must come back in the ID token.
+type SSOStatePayload struct {
+	ConnectionID string    `json:"connectionId"`
+	WorkspaceID  string    `json:"workspaceId"`
+	Nonce        string    `json:"nonce"`
+	ExpAt        time.Time `json:"expAt"`
+}
+
+func (s *Storage) CreateSSOStateToken(payload *SSOStatePayload) (string, error) {
+	token, err := id.RandomString(32)
+	if err != nil {
+		return "", err
+	}
+	ttl := viper.GetInt32(config.SSOStateExpirySeconds)
+	payload.ExpAt = time.Now().Add(time.Duration(ttl) * time.Second)
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	if err := s.cache.SetX(ssoStateTokenPrefix+token, payloadBytes, ttl); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *Storage) GetSSOStateToken(token string) (*SSOStatePayload, error) {
+	var payload SSOStatePayload
+	data, err := s.cache.Get(ssoStateTokenPrefix + token)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+func (s *Storage) ConsumeSSOStateToken(token string) error {
+	return s.cache.Delete(ssoStateTokenPrefix + token)
+}
+
+// EmailChangePayload is a pending move of a user's login email from OldEmail to NewEmail.
+// The same payload backs the confirmation link sent to the new address and the undo link
+// sent to the old one.
+type EmailChangePayload struct {
+	UserID      string    `json:"userId"`
+	WorkspaceID string    `json:"workspaceId"`
+	OldEmail    string    `json:"oldEmail"`
+	NewEmail    string    `json:"newEmail"`
+	ExpAt       time.Time `json:"expAt"`
+}
+
+func (s *Storage) CreateEmailChangeToken(payload *EmailChangePayload) (string, time.Time, error) {
+	return s.createEmailChangeToken(emailChangeTokenPrefix, config.EmailChangeTokenExpirySeconds, payload)
+}
+
+func (s *Storage) GetEmailChangeToken(token string) (*EmailChangePayload, error) {
+	return s.getEmailChangeToken(emailChangeTokenPrefix + token)
+}
+
+func (s *Storage) ConsumeEmailChangeToken(token string) error {
+	return s.cache.Delete(emailChangeTokenPrefix + token)
+}
+
+func (s *Storage) CreateEmailChangeUndoToken(payload *EmailChangePayload) (string, time.Time, error) {
+	return s.createEmailChangeToken(emailChangeUndoTokenPrefix, config.EmailChangeUndoTokenExpirySeconds, payload)
+}
+
+func (s *Storage) GetEmailChangeUndoToken(token string) (*EmailChangePayload, error) {
+	return s.getEmailChangeToken(emailChangeUndoTokenPrefix + token)
+}
+
+func (s *Storage) ConsumeEmailChangeUndoToken(token string) error {
+	return s.cache.Delete(emailChangeUndoTokenPrefix + token)
+}
+
+func (s *Storage) createEmailChangeToken(prefix, ttlKey string, payload *EmailChangePayload) (string, time.Time, error) {
+	token, err := id.RandomString(32)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	ttl := viper.GetInt32(ttlKey)
+	payload.ExpAt = time.Now().Add(time.Duration(ttl) * time.Second)
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if err := s.cache.SetX(prefix+token, payloadBytes, ttl); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, payload.ExpAt, nil
+}
+
+func (s *Storage) getEmailChangeToken(key string) (*EmailChangePayload, error) {
+	var payload EmailChangePayload
+	data, err := s.cache.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// MagicLinkPayload is a one-time sign-in link sent to Email. It no longer works once the
+// user's email changes.
+type MagicLinkPayload struct {
+	UserID      string    `json:"userId"`
+	WorkspaceID string    `json:"workspaceId"`
+	Email       string    `json:"email"`
+	ExpAt       time.Time `json:"expAt"`
+}
+
+func (s *Storage) CreateMagicLinkToken(payload *MagicLinkPayload) (string, time.Time, error) {
+	token, err := id.RandomString(32)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	ttl := viper.GetInt32(config.MagicLinkTokenExpirySeconds)
+	payload.ExpAt = time.Now().Add(time.Duration(ttl) * time.Second)
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if err := s.cache.SetX(magicLinkTokenPrefix+token, payloadBytes, ttl); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, payload.ExpAt, nil
+}
+
+func (s *Storage) GetMagicLinkToken(token string) (*MagicLinkPayload, error) {
+	var payload MagicLinkPayload
+	data, err := s.cache.Get(magicLinkTokenPrefix + token)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+func (s *Storage) ConsumeMagicLinkToken(token string) error {
+	return s.cache.Delete(magicLinkTokenPrefix + token)
+}
@@ -1,6 +1,9 @@
 package accounting
 
-import "github.com/abdelrahman146/kyora/internal/platform/types/problem"
+import (
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/shopspring/decimal"
+)
 
 // Asset errors
 
@@ -43,6 +46,19 @@ func ErrWithdrawalInvalidAmount() *problem.Problem {
 	return problem.BadRequest("withdrawal amount must be greater than zero").WithCode("accounting.withdrawal_invalid_amount")
 }
 
+// ErrWithdrawalExceedsSafeToDraw returns a validation error for a withdrawal above the safe-to-draw amount
+func ErrWithdrawalExceedsSafeToDraw(safeToDraw, excess decimal.Decimal) *problem.Problem {
+	return problem.UnprocessableEntity("withdrawal exceeds the safe-to-draw amount").
+		With("safeToDrawAmount", safeToDraw.String()).
+		With("excess", excess.String()).
+		WithCode("accounting.withdrawal_exceeds_safe_to_draw")
+}
+
+// ErrWithdrawalOverrideForbidden returns a forbidden error when an actor without approve:accounting overrides the safe-to-draw limit
+func ErrWithdrawalOverrideForbidden() *problem.Problem {
+	return problem.Forbidden("overriding the safe-to-draw limit requires the approve:accounting permission").WithCode("accounting.withdrawal_override_forbidden")
+}
+
 // ErrWithdrawerNotFound returns a not found error when withdrawer user doesn't exist
 func ErrWithdrawerNotFound(withdrawerID string) *problem.Problem {
 	return problem.NotFound("withdrawer not found").With("withdrawerId", withdrawerID).WithCode("accounting.withdrawer_not_found")
@@ -56,6 +72,43 @@ func ErrInsufficientFunds(requested, available string) *problem.Problem {
 		WithCode("accounting.insufficient_funds")
 }
 
+// Cap table errors
+
+// ErrOwnerSharesExceeded returns a validation error when ownership percentages add up to more than 100
+func ErrOwnerSharesExceeded(total string) *problem.Problem {
+	return problem.BadRequest("ownership percentages must not exceed 100").With("total", total).WithCode("accounting.owner_shares_exceeded")
+}
+
+// ErrOwnerDuplicate returns a validation error when an owner is listed more than once
+func ErrOwnerDuplicate(ownerID string) *problem.Problem {
+	return problem.BadRequest("owner listed more than once").With("ownerId", ownerID).WithCode("accounting.owner_duplicate")
+}
+
+// ErrOwnerNotFound returns a validation error when an owner is not a user of the workspace
+func ErrOwnerNotFound(ownerID string) *problem.Problem {
+	return problem.BadRequest("owner not found").With("ownerId", ownerID).WithCode("accounting.owner_not_found")
+}
+
+// ErrOwnerNotInCapTable returns a validation error when a distribution pays someone without a share
+func ErrOwnerNotInCapTable(ownerID string) *problem.Problem {
+	return problem.BadRequest("owner has no share in the cap table").With("ownerId", ownerID).WithCode("accounting.owner_not_in_cap_table")
+}
+
+// ErrCapTableEmpty returns a validation error when profit is distributed before any share is set
+func ErrCapTableEmpty() *problem.Problem {
+	return problem.BadRequest("set the owners' shares before distributing profit").WithCode("accounting.cap_table_empty")
+}
+
+// ErrProfitDistributionInvalidPeriod returns a validation error for a period ending before it starts
+func ErrProfitDistributionInvalidPeriod() *problem.Problem {
+	return problem.BadRequest("distribution period must end on or after its start").WithCode("accounting.profit_distribution_invalid_period")
+}
+
+// ErrProfitDistributionInvalidPercent returns a validation error for a percent outside (0, 100]
+func ErrProfitDistributionInvalidPercent(percent string) *problem.Problem {
+	return problem.BadRequest("distribution percent must be greater than 0 and at most 100").With("percent", percent).WithCode("accounting.profit_distribution_invalid_percent")
+}
+
 // Expense errors
 
 // ErrExpenseNotFound returns a not found error for an expense
@@ -73,6 +126,50 @@ func ErrExpenseInvalidAmount() *problem.Problem {
 	return problem.BadRequest("expense amount must be greater than zero").WithCode("accounting.expense_invalid_amount")
 }
 
+// ErrExpenseInvalidVAT returns a validation error for an expense VAT outside [0, amount]
+func ErrExpenseInvalidVAT(vat, amount string) *problem.Problem {
+	return problem.BadRequest("expense VAT must be between zero and the expense amount").With("vat", vat).With("amount", amount).WithCode("accounting.expense_invalid_vat")
+}
+
+// ErrExchangeRateRequired returns a validation error for a foreign-currency expense without a
+// rate when no exchange rate provider is configured
+func ErrExchangeRateRequired(currency string) *problem.Problem {
+	return problem.BadRequest("an exchange rate is required for expenses in another currency").With("currency", currency).WithCode("accounting.exchange_rate_required")
+}
+
+// ErrExchangeRateUnavailable returns an error when the exchange rate provider has no rate for a currency
+func ErrExchangeRateUnavailable(currency string, err error) *problem.Problem {
+	return problem.UnprocessableEntity("no exchange rate is available for this currency, enter one manually").With("currency", currency).WithError(err).WithCode("accounting.exchange_rate_unavailable")
+}
+
+// ErrExpenseSplitPercentage returns a validation error when split percentages do not add up to 100
+func ErrExpenseSplitPercentage(total string) *problem.Problem {
+	return problem.BadRequest("split percentages must add up to 100").With("total", total).WithCode("accounting.expense_split_percentage")
+}
+
+// ErrExpenseSplitOrderLinked returns a validation error for splitting an expense booked from an order
+func ErrExpenseSplitOrderLinked() *problem.Problem {
+	return problem.BadRequest("expenses booked from an order cannot be split").WithCode("accounting.expense_split_order_linked")
+}
+
+// ErrExpenseSplitAllocated returns a validation error for splitting an expense allocated to orders or products
+func ErrExpenseSplitAllocated() *problem.Problem {
+	return problem.BadRequest("clear the allocations of the expense before splitting it").WithCode("accounting.expense_split_allocated")
+}
+
+// ErrExpenseSplitBusinessNotFound returns a validation error for a split line booked to a business outside the workspace
+func ErrExpenseSplitBusinessNotFound(businessID string) *problem.Problem {
+	return problem.BadRequest("split business not found").With("businessId", businessID).WithCode("accounting.expense_split_business_not_found")
+}
+
+// ErrExpenseSplitCurrencyMismatch returns a validation error for a split line booked to a business with another currency
+func ErrExpenseSplitCurrencyMismatch(businessID, currency string) *problem.Problem {
+	return problem.BadRequest("expenses can only be split across businesses with the same currency").
+		With("businessId", businessID).
+		With("currency", currency).
+		WithCode("accounting.expense_split_currency_mismatch")
+}
+
 // Recurring Expense errors
 
 // ErrRecurringExpenseNotFound returns a not found error for a recurring expense
@@ -95,11 +192,6 @@ func ErrRecurringExpenseInvalidDateRange() *problem.Problem {
 	return problem.BadRequest("recurring end date must be after start date").WithCode("accounting.recurring_expense_invalid_date_range")
 }
 
-// ErrRecurringExpenseInvalidAmount returns a validation error for invalid recurring expense amount.
-func ErrRecurringExpenseInvalidAmount() *problem.Problem {
-	return problem.BadRequest("recurring expense amount must be greater than zero").WithCode("accounting.recurring_expense_invalid_amount")
-}
-
 // ErrRecurringExpenseInvalidTransition returns a conflict error for invalid status transition
 func ErrRecurringExpenseInvalidTransition(from, to string) *problem.Problem {
 	return problem.Conflict("invalid status transition").
@@ -107,3 +199,93 @@ func ErrRecurringExpenseInvalidTransition(from, to string) *problem.Problem {
 		With("to", to).
 		WithCode("accounting.recurring_expense_invalid_transition")
 }
+
+// Expense allocation errors
+
+// ErrExpenseAllocationRuleNotFound returns a not found error for an allocation rule
+func ErrExpenseAllocationRuleNotFound(err error) *problem.Problem {
+	return problem.NotFound("expense allocation rule not found").WithError(err).WithCode("accounting.expense_allocation_rule_not_found")
+}
+
+// ErrAllocationPercentageExceeded returns a validation error when percentages add up to more than 100
+func ErrAllocationPercentageExceeded(total string) *problem.Problem {
+	return problem.BadRequest("allocation percentages must not exceed 100").With("total", total).WithCode("accounting.allocation_percentage_exceeded")
+}
+
+// ErrAllocationAmountExceeded returns a validation error when allocated amounts exceed the expense amount
+func ErrAllocationAmountExceeded(allocated, available string) *problem.Problem {
+	return problem.BadRequest("allocated amount exceeds the expense amount").
+		With("allocated", allocated).
+		With("available", available).
+		WithCode("accounting.allocation_amount_exceeded")
+}
+
+// ErrAllocationDuplicateTarget returns a validation error when a target appears on more than one line
+func ErrAllocationDuplicateTarget(targetID string) *problem.Problem {
+	return problem.BadRequest("allocation target listed more than once").With("targetId", targetID).WithCode("accounting.allocation_duplicate_target")
+}
+
+// ErrAllocationTargetNotFound returns a validation error when an order or product to allocate to does not exist
+func ErrAllocationTargetNotFound(targetType AllocationTargetType, targetID string) *problem.Problem {
+	return problem.BadRequest("allocation target not found").
+		With("targetType", string(targetType)).
+		With("targetId", targetID).
+		WithCode("accounting.allocation_target_not_found")
+}
+
+// Receipt intake errors
+
+// ErrExpenseDraftNotFound returns a not found error for an expense draft
+func ErrExpenseDraftNotFound(err error) *problem.Problem {
+	return problem.NotFound("expense draft not found").WithError(err).WithCode("accounting.expense_draft_not_found")
+}
+
+// ErrExpenseDraftNotPending returns a conflict error when a confirmed or discarded draft is changed
+func ErrExpenseDraftNotPending(status ExpenseDraftStatus) *problem.Problem {
+	return problem.Conflict("expense draft was already reviewed").With("status", string(status)).WithCode("accounting.expense_draft_not_pending")
+}
+
+// ErrExpenseDraftIncomplete returns a validation error when a draft is confirmed without an amount
+func ErrExpenseDraftIncomplete() *problem.Problem {
+	return problem.BadRequest("expense draft amount must be greater than zero").With("field", "amount").WithCode("accounting.expense_draft_incomplete")
+}
+
+// ErrReceiptIntakeUnavailable returns an error when receipt storage is not wired
+func ErrReceiptIntakeUnavailable() *problem.Problem {
+	return problem.InternalError().WithCode("accounting.receipt_intake_unavailable")
+}
+
+// ErrReceiptScanUnavailable returns an error when no OCR provider is configured
+func ErrReceiptScanUnavailable() *problem.Problem {
+	return problem.BadRequest("receipt scanning is not available").WithCode("accounting.receipt_scan_unavailable")
+}
+
+// ErrReceiptScanUnsupportedType returns a validation error for files that are not images or PDFs
+func ErrReceiptScanUnsupportedType(contentType string) *problem.Problem {
+	return problem.BadRequest("receipt must be an image or a PDF").With("contentType", contentType).WithCode("accounting.receipt_scan_unsupported_type")
+}
+
+// ErrReceiptScanFailed returns an error when the OCR provider could not read the receipt
+func ErrReceiptScanFailed(err error) *problem.Problem {
+	return problem.UnprocessableEntity("the receipt could not be read").WithError(err).WithCode("accounting.receipt_scan_failed")
+}
+
+// ErrReceiptScanRateLimited returns an error when a business scans too many receipts
+func ErrReceiptScanRateLimited() *problem.Problem {
+	return problem.TooManyRequests("too many receipt scans, try again later").WithCode("accounting.receipt_scan_rate_limited")
+}
+
+// ErrExpenseInboxNotFound returns a not found error when the business has no expense inbox
+func ErrExpenseInboxNotFound(err error) *problem.Problem {
+	return problem.NotFound("expense inbox not found").WithError(err).WithCode("accounting.expense_inbox_not_found")
+}
+
+// ErrExpenseInboxUnavailable returns an error when no inbox domain is configured
+func ErrExpenseInboxUnavailable() *problem.Problem {
+	return problem.BadRequest("expense inboxes are not available").WithCode("accounting.expense_inbox_unavailable")
+}
+
+// ErrExpenseInboxRateLimited returns a rate-limit error when an inbox receives too many receipts
+func ErrExpenseInboxRateLimited() *problem.Problem {
+	return problem.TooManyRequests("too many receipts received, please try again later").WithCode("accounting.expense_inbox_rate_limited")
+}
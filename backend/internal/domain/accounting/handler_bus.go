@@ -6,6 +6,7 @@ import (
 	"github.com/abdelrahman146/kyora/internal/domain/business"
 	"github.com/abdelrahman146/kyora/internal/platform/bus"
 	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
 	"github.com/shopspring/decimal"
 )
 
@@ -22,42 +23,209 @@ type BusHandler struct {
 // NewBusHandler registers accounting listeners on the event bus.
 func NewBusHandler(b *bus.Bus, svc *Service, businessSvc accountingRequiredBusinessService) {
 	h := &BusHandler{svc: svc, businessSvc: businessSvc}
-	b.Listen(bus.OrderPaymentSucceededTopic, h.HandleOrderPaymentSucceeded)
+	b.Handle(bus.OrderPaidTopic, "accounting.transaction_fee", h.HandleOrderPaid)
+	b.Handle(bus.OrderPaidTopic, "accounting.ledger_payment", h.HandleOrderPaidLedger)
+	b.Handle(bus.OrderFulfilledTopic, "accounting.ledger_sale", h.HandleOrderFulfilled)
+	b.Handle(bus.OrderImportedTopic, "accounting.ledger_import", h.HandleOrderImported)
+	b.Handle(bus.OrderReturnedTopic, "accounting.order_reversal", h.HandleOrderReturned)
+	b.Handle(bus.OrderRefundedTopic, "accounting.order_reversal", h.HandleOrderRefunded)
 }
 
-func (h *BusHandler) HandleOrderPaymentSucceeded(event any) {
-	e, ok := event.(*bus.OrderPaymentSucceededEvent)
+// HandleOrderPaid records the payment method fee of a paid order as an expense.
+// Malformed events are logged and dropped; failures to resolve or store the fee are
+// returned so the bus retries them and dead-letters them if they keep failing.
+func (h *BusHandler) HandleOrderPaid(event any) error {
+	e, ok := event.(*bus.OrderPaidEvent)
 	if !ok {
-		logger.FromContext(context.Background()).Error("invalid event type for OrderPaymentSucceededEvent")
-		return
+		logger.FromContext(context.Background()).Error("invalid event type for OrderPaidEvent")
+		return nil
 	}
 	if h.svc == nil || h.businessSvc == nil {
 		logger.FromContext(e.Ctx).Error("missing dependencies for accounting bus handler")
-		return
+		return nil
 	}
 	if e.BusinessID == "" || e.OrderID == "" {
-		logger.FromContext(e.Ctx).Error("missing required fields in OrderPaymentSucceededEvent", "businessId", e.BusinessID, "orderId", e.OrderID)
-		return
+		logger.FromContext(e.Ctx).Error("missing required fields in OrderPaidEvent", "businessId", e.BusinessID, "orderId", e.OrderID)
+		return nil
 	}
 	pm := business.PaymentMethodDescriptor(e.PaymentMethod)
 	enabled, feePercent, feeFixed, err := h.businessSvc.GetEffectivePaymentMethodFee(e.Ctx, e.BusinessID, pm)
 	if err != nil {
 		logger.FromContext(e.Ctx).Error("failed to resolve payment method fee", "error", err, "businessId", e.BusinessID, "paymentMethod", e.PaymentMethod)
-		return
+		return err
 	}
 	if !enabled {
-		return
+		return nil
 	}
 
 	fee := e.OrderTotal.Mul(feePercent).Add(feeFixed)
 	// No fee => no expense.
 	if fee.LessThanOrEqual(decimal.Zero) {
-		return
+		return nil
 	}
-	fee = fee.Round(2)
+	fee = money.Round(fee, e.Currency)
 
 	if err := h.svc.UpsertTransactionFeeExpenseForOrder(e.Ctx, e.BusinessID, e.OrderID, fee, e.Currency, e.PaidAt, e.PaymentMethod); err != nil {
 		logger.FromContext(e.Ctx).Error("failed to upsert transaction fee expense", "error", err, "businessId", e.BusinessID, "orderId", e.OrderID)
-		return
+		return err
 	}
+	return nil
+}
+
+// HandleOrderReturned reverses the revenue of a returned order, and its COGS when the goods
+// were restocked. A return of a refunded order also reverses its transaction fee.
+func (h *BusHandler) HandleOrderReturned(event any) error {
+	e, ok := event.(*bus.OrderReturnedEvent)
+	if !ok {
+		logger.FromContext(context.Background()).Error("invalid event type for OrderReturnedEvent")
+		return nil
+	}
+	if h.svc == nil {
+		logger.FromContext(e.Ctx).Error("missing dependencies for accounting bus handler")
+		return nil
+	}
+	if e.BusinessID == "" || e.OrderID == "" {
+		logger.FromContext(e.Ctx).Error("missing required fields in OrderReturnedEvent", "businessId", e.BusinessID, "orderId", e.OrderID)
+		return nil
+	}
+	if err := h.svc.RecordOrderReversal(e.Ctx, OrderReversalInput{
+		BusinessID: e.BusinessID,
+		OrderID:    e.OrderID,
+		OrderTotal: e.OrderTotal,
+		COGS:       e.COGS,
+		Currency:   e.Currency,
+		Restocked:  e.Restocked,
+		Refunded:   e.PaymentStatus == "refunded",
+		OccurredAt: e.ReturnedAt,
+	}); err != nil {
+		logger.FromContext(e.Ctx).Error("failed to record order reversal", "error", err, "businessId", e.BusinessID, "orderId", e.OrderID)
+		return err
+	}
+	return nil
+}
+
+// HandleOrderRefunded reverses the revenue and transaction fee of a refunded order. The COGS
+// stays booked until the goods come back through a restocking return.
+func (h *BusHandler) HandleOrderRefunded(event any) error {
+	e, ok := event.(*bus.OrderRefundedEvent)
+	if !ok {
+		logger.FromContext(context.Background()).Error("invalid event type for OrderRefundedEvent")
+		return nil
+	}
+	if h.svc == nil {
+		logger.FromContext(e.Ctx).Error("missing dependencies for accounting bus handler")
+		return nil
+	}
+	if e.BusinessID == "" || e.OrderID == "" {
+		logger.FromContext(e.Ctx).Error("missing required fields in OrderRefundedEvent", "businessId", e.BusinessID, "orderId", e.OrderID)
+		return nil
+	}
+	if err := h.svc.RecordOrderReversal(e.Ctx, OrderReversalInput{
+		BusinessID: e.BusinessID,
+		OrderID:    e.OrderID,
+		OrderTotal: e.OrderTotal,
+		COGS:       e.COGS,
+		Currency:   e.Currency,
+		Refunded:   true,
+		OccurredAt: e.RefundedAt,
+	}); err != nil {
+		logger.FromContext(e.Ctx).Error("failed to record order reversal", "error", err, "businessId", e.BusinessID, "orderId", e.OrderID)
+		return err
+	}
+	return nil
+}
+
+// HandleOrderPaidLedger books the payment of a paid order in the ledger.
+func (h *BusHandler) HandleOrderPaidLedger(event any) error {
+	e, ok := event.(*bus.OrderPaidEvent)
+	if !ok {
+		logger.FromContext(context.Background()).Error("invalid event type for OrderPaidEvent")
+		return nil
+	}
+	if h.svc == nil {
+		logger.FromContext(e.Ctx).Error("missing dependencies for accounting bus handler")
+		return nil
+	}
+	if e.BusinessID == "" || e.OrderID == "" {
+		logger.FromContext(e.Ctx).Error("missing required fields in OrderPaidEvent", "businessId", e.BusinessID, "orderId", e.OrderID)
+		return nil
+	}
+	if err := h.svc.PostOrderPayment(e.Ctx, OrderPaymentInput{
+		BusinessID:  e.BusinessID,
+		OrderID:     e.OrderID,
+		OrderNumber: e.OrderNumber,
+		OrderTotal:  e.OrderTotal,
+		Currency:    e.Currency,
+		PaidAt:      e.PaidAt,
+	}); err != nil {
+		logger.FromContext(e.Ctx).Error("failed to post order payment", "error", err, "businessId", e.BusinessID, "orderId", e.OrderID)
+		return err
+	}
+	return nil
+}
+
+// HandleOrderFulfilled books the sale of a fulfilled order in the ledger: its revenue and
+// VAT, and its COGS.
+func (h *BusHandler) HandleOrderFulfilled(event any) error {
+	e, ok := event.(*bus.OrderFulfilledEvent)
+	if !ok {
+		logger.FromContext(context.Background()).Error("invalid event type for OrderFulfilledEvent")
+		return nil
+	}
+	if h.svc == nil {
+		logger.FromContext(e.Ctx).Error("missing dependencies for accounting bus handler")
+		return nil
+	}
+	if e.BusinessID == "" || e.OrderID == "" {
+		logger.FromContext(e.Ctx).Error("missing required fields in OrderFulfilledEvent", "businessId", e.BusinessID, "orderId", e.OrderID)
+		return nil
+	}
+	if err := h.svc.PostOrderSale(e.Ctx, OrderSaleInput{
+		BusinessID:  e.BusinessID,
+		OrderID:     e.OrderID,
+		OrderNumber: e.OrderNumber,
+		OrderTotal:  e.OrderTotal,
+		VAT:         e.VAT,
+		COGS:        e.COGS,
+		Currency:    e.Currency,
+		FulfilledAt: e.FulfilledAt,
+	}); err != nil {
+		logger.FromContext(e.Ctx).Error("failed to post order sale", "error", err, "businessId", e.BusinessID, "orderId", e.OrderID)
+		return err
+	}
+	return nil
+}
+
+// HandleOrderImported books the sale and payment of an imported historical order in the
+// ledger. Imported orders emit no lifecycle events, and the backfill only runs when the
+// ledger is first opened, so without it orders imported later would be missing from the books.
+func (h *BusHandler) HandleOrderImported(event any) error {
+	e, ok := event.(*bus.OrderImportedEvent)
+	if !ok {
+		logger.FromContext(context.Background()).Error("invalid event type for OrderImportedEvent")
+		return nil
+	}
+	if h.svc == nil {
+		logger.FromContext(e.Ctx).Error("missing dependencies for accounting bus handler")
+		return nil
+	}
+	if e.BusinessID == "" || e.OrderID == "" {
+		logger.FromContext(e.Ctx).Error("missing required fields in OrderImportedEvent", "businessId", e.BusinessID, "orderId", e.OrderID)
+		return nil
+	}
+	if err := h.svc.PostImportedOrder(e.Ctx, ImportedOrderInput{
+		BusinessID:  e.BusinessID,
+		OrderID:     e.OrderID,
+		OrderNumber: e.OrderNumber,
+		OrderTotal:  e.OrderTotal,
+		VAT:         e.VAT,
+		COGS:        e.COGS,
+		Currency:    e.Currency,
+		FulfilledAt: e.FulfilledAt,
+		PaidAt:      e.PaidAt,
+	}); err != nil {
+		logger.FromContext(e.Ctx).Error("failed to post imported order", "error", err, "businessId", e.BusinessID, "orderId", e.OrderID)
+		return err
+	}
+	return nil
 }
@@ -1,31 +1,42 @@
 package accounting
 
 import (
+	"crypto/subtle"
+	"errors"
+	"io"
+	"mime"
 	"net/http"
+	"path/filepath"
 	"time"
 
 	"github.com/abdelrahman146/kyora/internal/domain/account"
 	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
 	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/config"
 	"github.com/abdelrahman146/kyora/internal/platform/database"
 	"github.com/abdelrahman146/kyora/internal/platform/request"
 	"github.com/abdelrahman146/kyora/internal/platform/response"
 	"github.com/abdelrahman146/kyora/internal/platform/types/list"
 	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
 )
 
 // HttpHandler handles HTTP requests for accounting domain operations
 type HttpHandler struct {
-	service      *Service
-	orderService *order.Service
+	service          *Service
+	orderService     *order.Service
+	inventoryService *inventory.Service
 }
 
 // NewHttpHandler creates a new HTTP handler for accounting operations
-func NewHttpHandler(service *Service, orderService *order.Service) *HttpHandler {
+func NewHttpHandler(service *Service, orderService *order.Service, inventoryService *inventory.Service) *HttpHandler {
 	return &HttpHandler{
-		service:      service,
-		orderService: orderService,
+		service:          service,
+		orderService:     orderService,
+		inventoryService: inventoryService,
 	}
 }
 
@@ -644,15 +655,17 @@ func (h *HttpHandler) GetWithdrawal(c *gin.Context) {
 // CreateWithdrawal creates a new withdrawal
 //
 // @Summary      Create withdrawal
-// @Description  Creates a new withdrawal for the authenticated workspace
+// @Description  Creates a new withdrawal for the authenticated workspace. On a business that guards withdrawals, one above the safe-to-draw amount carries a warning, or is rejected unless an admin sets override when the business enforces the limit.
 // @Tags         accounting
 // @Accept       json
 // @Produce      json
 // @Param        businessDescriptor path string true "Business descriptor"
 // @Param        request body CreateWithdrawalRequest true "Withdrawal data"
-// @Success      201 {object} accounting.WithdrawalResponse
+// @Success      201 {object} accounting.CreateWithdrawalResponse
 // @Failure      400 {object} problem.Problem
 // @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      422 {object} problem.Problem
 // @Failure      500 {object} problem.Problem
 // @Router       /v1/businesses/{businessDescriptor}/accounting/withdrawals [post]
 // @Security     BearerAuth
@@ -674,13 +687,16 @@ func (h *HttpHandler) CreateWithdrawal(c *gin.Context) {
 		return
 	}
 
-	withdrawal, err := h.service.CreateWithdrawal(c.Request.Context(), actor, biz, &req)
+	withdrawal, warning, err := h.service.CreateWithdrawal(c.Request.Context(), actor, biz, &req)
 	if err != nil {
-		response.Error(c, problem.InternalError().WithError(err))
+		response.Error(c, err)
 		return
 	}
 
-	response.SuccessJSON(c, http.StatusCreated, ToWithdrawalResponse(withdrawal))
+	response.SuccessJSON(c, http.StatusCreated, CreateWithdrawalResponse{
+		WithdrawalResponse: ToWithdrawalResponse(withdrawal),
+		Warning:            warning,
+	})
 }
 
 // UpdateWithdrawal updates an existing withdrawal
@@ -783,6 +799,181 @@ func (h *HttpHandler) DeleteWithdrawal(c *gin.Context) {
 	response.SuccessEmpty(c, http.StatusNoContent)
 }
 
+// Cap table endpoints
+
+// GetCapTable returns the owners of the business with their shares
+//
+// @Summary      Get cap table
+// @Description  Returns the owners of the business with the percentage each holds and what they invested and withdrew, largest share first
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {object} accounting.CapTableResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/cap-table [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetCapTable(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	entries, err := h.service.GetCapTable(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToCapTableResponse(entries, biz.Currency))
+}
+
+// SetCapTable replaces the owners of the business and their shares
+//
+// @Summary      Set cap table
+// @Description  Replaces the cap table. Owners must be users of the workspace, listed once, with percentages adding up to at most 100; an empty list clears it
+// @Tags         accounting
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        request body SetCapTableRequest true "Owner shares"
+// @Success      200 {object} accounting.CapTableResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/cap-table [put]
+// @Security     BearerAuth
+func (h *HttpHandler) SetCapTable(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var req SetCapTableRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	entries, err := h.service.SetCapTable(c.Request.Context(), actor, biz, req.Owners)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToCapTableResponse(entries, biz.Currency))
+}
+
+// GetProfitDistribution proposes per-owner withdrawals from the net profit of a period
+//
+// @Summary      Propose profit distribution
+// @Description  Splits a percentage of the period's net profit (income less expenses and COGS, from the ledger) between the owners of the cap table by their share, less what each already withdrew in the period or as a distribution of it
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        from query string true "Period start date (YYYY-MM-DD)"
+// @Param        to query string true "Period end date, inclusive (YYYY-MM-DD)"
+// @Param        percent query number false "Percentage of the net profit to distribute (default: 100)"
+// @Success      200 {object} accounting.ProfitDistributionResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/profit-distribution [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetProfitDistribution(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var query profitDistributionQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	percent := decimal.NewFromInt(100)
+	if query.Percent != nil {
+		percent = decimal.NewFromFloat(*query.Percent)
+	}
+	dist, err := h.service.ProposeProfitDistribution(c.Request.Context(), actor, biz, query.From, query.To, percent)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToProfitDistributionResponse(dist, biz.Currency))
+}
+
+// DistributeProfit records an approved profit distribution as withdrawals
+//
+// @Summary      Distribute profit
+// @Description  Records one withdrawal per owner for an approved profit distribution of the period, marked with the period it pays out. Without owners the proposed amounts are recorded; owners must be in the cap table
+// @Tags         accounting
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        request body DistributeProfitRequest true "Approved distribution"
+// @Success      201 {object} accounting.ProfitDistributionRecordedResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/profit-distribution [post]
+// @Security     BearerAuth
+func (h *HttpHandler) DistributeProfit(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var req DistributeProfitRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+	if req.From.IsZero() || req.To.IsZero() {
+		response.Error(c, problem.BadRequest("from and to are required"))
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	dist, withdrawals, err := h.service.DistributeProfit(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusCreated, ProfitDistributionRecordedResponse{
+		Distribution: ToProfitDistributionResponse(dist, biz.Currency),
+		Withdrawals:  ToWithdrawalResponses(withdrawals),
+	})
+}
+
 // Expense endpoints
 
 // ListExpenses returns a paginated list of expenses for the workspace
@@ -1041,33 +1232,61 @@ func (h *HttpHandler) DeleteExpense(c *gin.Context) {
 	response.SuccessEmpty(c, http.StatusNoContent)
 }
 
-// Recurring Expense endpoints
+// Expense allocation endpoints
 
-// ListRecurringExpenses returns a paginated list of recurring expenses for the workspace
+// ensureAllocationTargets checks that every order or product a rule allocates to belongs to the business.
+func (h *HttpHandler) ensureAllocationTargets(c *gin.Context, actor *account.User, biz *business.Business, targetType AllocationTargetType, lines []AllocationLine) error {
+	ids := make([]string, len(lines))
+	for i, line := range lines {
+		ids[i] = line.TargetID
+	}
+	var (
+		found map[string]bool
+		err   error
+	)
+	switch targetType {
+	case AllocationTargetOrder:
+		found, err = h.orderService.ExistingOrderIDs(c.Request.Context(), actor, biz, ids)
+	case AllocationTargetProduct:
+		found, err = h.inventoryService.ExistingProductIDs(c.Request.Context(), actor, biz, ids)
+	default:
+		return problem.BadRequest("invalid allocation target type").With("targetType", string(targetType))
+	}
+	if err != nil {
+		return problem.InternalError().WithError(err)
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return ErrAllocationTargetNotFound(targetType, id)
+		}
+	}
+	return nil
+}
+
+// GetExpenseSplit returns the lines an expense was split into
 //
-// @Summary      List recurring expenses
-// @Description  Returns a paginated list of all recurring expenses for the authenticated workspace
+// @Summary      Get expense split
+// @Description  Returns the lines an expense was split into, across categories and businesses of the workspace. An expense that is not split is its only line.
 // @Tags         accounting
 // @Produce      json
 // @Param        businessDescriptor path string true "Business descriptor"
-// @Param        page query int false "Page number (default: 1)"
-// @Param        pageSize query int false "Page size (default: 20, max: 100)"
-// @Param        orderBy query []string false "Sort order (e.g., -amount, nextRecurringDate)"
-// @Success      200 {object} list.ListResponse[accounting.RecurringExpenseResponse]
+// @Param        expenseId path string true "Expense ID"
+// @Success      200 {object} accounting.ExpenseSplitResponse
 // @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
 // @Failure      500 {object} problem.Problem
-// @Router       /v1/businesses/{businessDescriptor}/accounting/recurring-expenses [get]
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expenses/{expenseId}/split [get]
 // @Security     BearerAuth
-func (h *HttpHandler) ListRecurringExpenses(c *gin.Context) {
+func (h *HttpHandler) GetExpenseSplit(c *gin.Context) {
 	actor, err := account.ActorFromContext(c)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	var query listAssetsQuery
-	if err := c.ShouldBindQuery(&query); err != nil {
-		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+	expenseID := c.Param("expenseId")
+	if expenseID == "" {
+		response.Error(c, problem.BadRequest("expenseId is required"))
 		return
 	}
 
@@ -1077,54 +1296,51 @@ func (h *HttpHandler) ListRecurringExpenses(c *gin.Context) {
 		return
 	}
 
-	if query.Page == 0 {
-		query.Page = 1
-	}
-	if query.PageSize == 0 {
-		query.PageSize = 20
-	}
-
-	listReq := list.NewListRequest(query.Page, query.PageSize, query.OrderBy, "")
-	recurringExpenses, err := h.service.ListRecurringExpenses(c.Request.Context(), actor, biz, listReq)
-	if err != nil {
-		response.Error(c, problem.InternalError().WithError(err))
-		return
-	}
-
-	totalCount, err := h.service.CountRecurringExpenses(c.Request.Context(), actor, biz)
+	lines, err := h.service.ListExpenseSplit(c.Request.Context(), actor, biz, expenseID)
 	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrExpenseNotFound(err))
+			return
+		}
 		response.Error(c, problem.InternalError().WithError(err))
 		return
 	}
 
-	listResp := list.NewListResponse(ToRecurringExpenseResponses(recurringExpenses), query.Page, query.PageSize, totalCount, (int64(query.Page*query.PageSize) < totalCount))
-	response.SuccessJSON(c, http.StatusOK, listResp)
+	response.SuccessJSON(c, http.StatusOK, ToExpenseSplitResponse(lines))
 }
 
-// GetRecurringExpense returns a specific recurring expense by ID
+// SplitExpense splits an expense into lines with their own category and business
 //
-// @Summary      Get recurring expense
-// @Description  Returns a specific recurring expense by ID for the authenticated workspace
+// @Summary      Split expense
+// @Description  Splits an expense by percentage into lines with their own category, optionally booked to other businesses of the workspace with the same currency. Each line is an expense of its own, so reports count the lines. Splitting again replaces the lines; a single line undoes the split.
 // @Tags         accounting
+// @Accept       json
 // @Produce      json
 // @Param        businessDescriptor path string true "Business descriptor"
-// @Param        recurringExpenseId path string true "Recurring Expense ID"
-// @Success      200 {object} accounting.RecurringExpenseResponse
+// @Param        expenseId path string true "Expense ID"
+// @Param        request body SplitExpenseRequest true "Split lines"
+// @Success      200 {object} accounting.ExpenseSplitResponse
+// @Failure      400 {object} problem.Problem
 // @Failure      401 {object} problem.Problem
 // @Failure      404 {object} problem.Problem
 // @Failure      500 {object} problem.Problem
-// @Router       /v1/businesses/{businessDescriptor}/accounting/recurring-expenses/{recurringExpenseId} [get]
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expenses/{expenseId}/split [put]
 // @Security     BearerAuth
-func (h *HttpHandler) GetRecurringExpense(c *gin.Context) {
+func (h *HttpHandler) SplitExpense(c *gin.Context) {
 	actor, err := account.ActorFromContext(c)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	recurringExpenseID := c.Param("recurringExpenseId")
-	if recurringExpenseID == "" {
-		response.Error(c, problem.BadRequest("recurringExpenseId is required"))
+	expenseID := c.Param("expenseId")
+	if expenseID == "" {
+		response.Error(c, problem.BadRequest("expenseId is required"))
+		return
+	}
+
+	var req SplitExpenseRequest
+	if err := request.ValidBody(c, &req); err != nil {
 		return
 	}
 
@@ -1134,43 +1350,107 @@ func (h *HttpHandler) GetRecurringExpense(c *gin.Context) {
 		return
 	}
 
-	recurringExpense, err := h.service.GetRecurringExpenseByID(c.Request.Context(), actor, biz, recurringExpenseID)
+	lines, err := h.service.SplitExpense(c.Request.Context(), actor, biz, expenseID, &req)
 	if err != nil {
 		if database.IsRecordNotFound(err) {
-			response.Error(c, ErrRecurringExpenseNotFound(err))
+			response.Error(c, ErrExpenseNotFound(err))
 			return
 		}
-		response.Error(c, problem.InternalError().WithError(err))
+		response.Error(c, err)
 		return
 	}
 
-	response.SuccessJSON(c, http.StatusOK, ToRecurringExpenseResponse(recurringExpense))
+	response.SuccessJSON(c, http.StatusOK, ToExpenseSplitResponse(lines))
 }
 
-// CreateRecurringExpense creates a new recurring expense
+// GetAssetHistory returns the change history of an asset
 //
-// @Summary      Create recurring expense
-// @Description  Creates a new recurring expense for the authenticated workspace
+// @Summary      Get asset history
+// @Description  Returns every recorded change of the asset (creation, edits, deletion) with who made it and the values before and after, oldest first. The history stays available after the asset is deleted.
 // @Tags         accounting
-// @Accept       json
 // @Produce      json
 // @Param        businessDescriptor path string true "Business descriptor"
-// @Param        request body CreateRecurringExpenseRequest true "Recurring expense data"
-// @Success      201 {object} accounting.RecurringExpenseResponse
-// @Failure      400 {object} problem.Problem
+// @Param        assetId path string true "Asset ID"
+// @Success      200 {array} accounting.AuditEventResponse
 // @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
 // @Failure      500 {object} problem.Problem
-// @Router       /v1/businesses/{businessDescriptor}/accounting/recurring-expenses [post]
+// @Router       /v1/businesses/{businessDescriptor}/accounting/assets/{assetId}/history [get]
 // @Security     BearerAuth
-func (h *HttpHandler) CreateRecurringExpense(c *gin.Context) {
+func (h *HttpHandler) GetAssetHistory(c *gin.Context) {
+	h.getRecordHistory(c, AuditRecordAsset, "assetId", ErrAssetNotFound)
+}
+
+// GetInvestmentHistory returns the change history of an investment
+//
+// @Summary      Get investment history
+// @Description  Returns every recorded change of the investment (creation, edits, deletion) with who made it and the values before and after, oldest first. The history stays available after the investment is deleted.
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        investmentId path string true "Investment ID"
+// @Success      200 {array} accounting.AuditEventResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/investments/{investmentId}/history [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetInvestmentHistory(c *gin.Context) {
+	h.getRecordHistory(c, AuditRecordInvestment, "investmentId", ErrInvestmentNotFound)
+}
+
+// GetWithdrawalHistory returns the change history of a withdrawal
+//
+// @Summary      Get withdrawal history
+// @Description  Returns every recorded change of the withdrawal (creation, edits, deletion) with who made it and the values before and after, oldest first. The history stays available after the withdrawal is deleted.
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        withdrawalId path string true "Withdrawal ID"
+// @Success      200 {array} accounting.AuditEventResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/withdrawals/{withdrawalId}/history [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetWithdrawalHistory(c *gin.Context) {
+	h.getRecordHistory(c, AuditRecordWithdrawal, "withdrawalId", ErrWithdrawalNotFound)
+}
+
+// GetExpenseHistory returns the change history of an expense
+//
+// @Summary      Get expense history
+// @Description  Returns every recorded change of the expense (creation, edits, splits, deletion) with who made it and the values before and after, oldest first. Changes made by automation, such as recurring occurrences and transaction fees, have no actor. The history stays available after the expense is deleted.
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        expenseId path string true "Expense ID"
+// @Success      200 {array} accounting.AuditEventResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expenses/{expenseId}/history [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetExpenseHistory(c *gin.Context) {
+	h.getRecordHistory(c, AuditRecordExpense, "expenseId", ErrExpenseNotFound)
+}
+
+// getRecordHistory serves the change history of the record identified by the param path
+// parameter.
+func (h *HttpHandler) getRecordHistory(c *gin.Context, recordType AuditRecordType, param string, notFound func(error) *problem.Problem) {
 	actor, err := account.ActorFromContext(c)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	var req CreateRecurringExpenseRequest
-	if err := request.ValidBody(c, &req); err != nil {
+	recordID := c.Param(param)
+	if recordID == "" {
+		response.Error(c, problem.BadRequest(param+" is required"))
 		return
 	}
 
@@ -1180,47 +1460,43 @@ func (h *HttpHandler) CreateRecurringExpense(c *gin.Context) {
 		return
 	}
 
-	recurringExpense, err := h.service.CreateRecurringExpense(c.Request.Context(), actor, biz, &req)
+	events, err := h.service.ListRecordHistory(c.Request.Context(), actor, biz, recordType, recordID)
 	if err != nil {
-		response.Error(c, err)
+		if database.IsRecordNotFound(err) {
+			response.Error(c, notFound(err))
+			return
+		}
+		response.Error(c, problem.InternalError().WithError(err))
 		return
 	}
 
-	response.SuccessJSON(c, http.StatusCreated, ToRecurringExpenseResponse(recurringExpense))
+	response.SuccessJSON(c, http.StatusOK, ToAuditEventResponses(events))
 }
 
-// UpdateRecurringExpense updates an existing recurring expense
+// GetExpenseAllocations returns how an expense is allocated to orders or products
 //
-// @Summary      Update recurring expense
-// @Description  Updates an existing recurring expense for the authenticated workspace
+// @Summary      Get expense allocations
+// @Description  Returns the allocations of an expense together with its allocated and unallocated amounts
 // @Tags         accounting
-// @Accept       json
 // @Produce      json
 // @Param        businessDescriptor path string true "Business descriptor"
-// @Param        recurringExpenseId path string true "Recurring Expense ID"
-// @Param        request body UpdateRecurringExpenseRequest true "Recurring expense update data"
-// @Success      200 {object} accounting.RecurringExpenseResponse
-// @Failure      400 {object} problem.Problem
+// @Param        expenseId path string true "Expense ID"
+// @Success      200 {object} accounting.ExpenseAllocationsResponse
 // @Failure      401 {object} problem.Problem
 // @Failure      404 {object} problem.Problem
 // @Failure      500 {object} problem.Problem
-// @Router       /v1/businesses/{businessDescriptor}/accounting/recurring-expenses/{recurringExpenseId} [patch]
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expenses/{expenseId}/allocations [get]
 // @Security     BearerAuth
-func (h *HttpHandler) UpdateRecurringExpense(c *gin.Context) {
+func (h *HttpHandler) GetExpenseAllocations(c *gin.Context) {
 	actor, err := account.ActorFromContext(c)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	recurringExpenseID := c.Param("recurringExpenseId")
-	if recurringExpenseID == "" {
-		response.Error(c, problem.BadRequest("recurringExpenseId is required"))
-		return
-	}
-
-	var req UpdateRecurringExpenseRequest
-	if err := request.ValidBody(c, &req); err != nil {
+	expenseID := c.Param("expenseId")
+	if expenseID == "" {
+		response.Error(c, problem.BadRequest("expenseId is required"))
 		return
 	}
 
@@ -1230,43 +1506,57 @@ func (h *HttpHandler) UpdateRecurringExpense(c *gin.Context) {
 		return
 	}
 
-	recurringExpense, err := h.service.UpdateRecurringExpense(c.Request.Context(), actor, biz, recurringExpenseID, &req)
+	expense, err := h.service.GetExpenseByID(c.Request.Context(), actor, biz, expenseID)
 	if err != nil {
 		if database.IsRecordNotFound(err) {
-			response.Error(c, ErrRecurringExpenseNotFound(err))
+			response.Error(c, ErrExpenseNotFound(err))
 			return
 		}
-		response.Error(c, err)
+		response.Error(c, problem.InternalError().WithError(err))
 		return
 	}
 
-	response.SuccessJSON(c, http.StatusOK, ToRecurringExpenseResponse(recurringExpense))
+	allocations, err := h.service.ListExpenseAllocations(c.Request.Context(), actor, biz, expense.ID)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToExpenseAllocationsResponse(expense, allocations))
 }
 
-// DeleteRecurringExpense deletes a recurring expense
+// AllocateExpense allocates an expense to orders or products
 //
-// @Summary      Delete recurring expense
-// @Description  Deletes a recurring expense for the authenticated workspace
+// @Summary      Allocate expense
+// @Description  Splits an expense across orders or products by percentage or amount, either ad hoc or with a saved rule. Replaces any previous allocation of the expense.
 // @Tags         accounting
+// @Accept       json
 // @Produce      json
 // @Param        businessDescriptor path string true "Business descriptor"
-// @Param        recurringExpenseId path string true "Recurring Expense ID"
-// @Success      204
+// @Param        expenseId path string true "Expense ID"
+// @Param        request body AllocateExpenseRequest true "Allocation"
+// @Success      200 {object} accounting.ExpenseAllocationsResponse
+// @Failure      400 {object} problem.Problem
 // @Failure      401 {object} problem.Problem
 // @Failure      404 {object} problem.Problem
 // @Failure      500 {object} problem.Problem
-// @Router       /v1/businesses/{businessDescriptor}/accounting/recurring-expenses/{recurringExpenseId} [delete]
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expenses/{expenseId}/allocations [put]
 // @Security     BearerAuth
-func (h *HttpHandler) DeleteRecurringExpense(c *gin.Context) {
+func (h *HttpHandler) AllocateExpense(c *gin.Context) {
 	actor, err := account.ActorFromContext(c)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	recurringExpenseID := c.Param("recurringExpenseId")
-	if recurringExpenseID == "" {
-		response.Error(c, problem.BadRequest("recurringExpenseId is required"))
+	expenseID := c.Param("expenseId")
+	if expenseID == "" {
+		response.Error(c, problem.BadRequest("expenseId is required"))
+		return
+	}
+
+	var req AllocateExpenseRequest
+	if err := request.ValidBody(c, &req); err != nil {
 		return
 	}
 
@@ -1276,52 +1566,53 @@ func (h *HttpHandler) DeleteRecurringExpense(c *gin.Context) {
 		return
 	}
 
-	err = h.service.DeleteRecurringExpense(c.Request.Context(), actor, biz, recurringExpenseID)
+	rule, err := h.service.ResolveExpenseAllocation(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.ensureAllocationTargets(c, actor, biz, rule.TargetType, rule.Lines); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	expense, allocations, err := h.service.AllocateExpense(c.Request.Context(), actor, biz, expenseID, rule)
 	if err != nil {
 		if database.IsRecordNotFound(err) {
-			response.Error(c, ErrRecurringExpenseNotFound(err))
+			response.Error(c, ErrExpenseNotFound(err))
 			return
 		}
-		response.Error(c, problem.InternalError().WithError(err))
+		response.Error(c, err)
 		return
 	}
 
-	response.SuccessEmpty(c, http.StatusNoContent)
+	response.SuccessJSON(c, http.StatusOK, ToExpenseAllocationsResponse(expense, allocations))
 }
 
-// UpdateRecurringExpenseStatus updates the status of a recurring expense
+// ClearExpenseAllocations removes all allocations of an expense
 //
-// @Summary      Update recurring expense status
-// @Description  Updates the status of a recurring expense (active, paused, ended, canceled)
+// @Summary      Clear expense allocations
+// @Description  Removes every allocation of an expense so it counts as unallocated overhead again
 // @Tags         accounting
-// @Accept       json
 // @Produce      json
 // @Param        businessDescriptor path string true "Business descriptor"
-// @Param        recurringExpenseId path string true "Recurring Expense ID"
-// @Param        request body updateRecurringExpenseStatusRequest true "Status update data"
-// @Success      200 {object} accounting.RecurringExpenseResponse
-// @Failure      400 {object} problem.Problem
+// @Param        expenseId path string true "Expense ID"
+// @Success      204
 // @Failure      401 {object} problem.Problem
 // @Failure      404 {object} problem.Problem
-// @Failure      409 {object} problem.Problem
 // @Failure      500 {object} problem.Problem
-// @Router       /v1/businesses/{businessDescriptor}/accounting/recurring-expenses/{recurringExpenseId}/status [patch]
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expenses/{expenseId}/allocations [delete]
 // @Security     BearerAuth
-func (h *HttpHandler) UpdateRecurringExpenseStatus(c *gin.Context) {
+func (h *HttpHandler) ClearExpenseAllocations(c *gin.Context) {
 	actor, err := account.ActorFromContext(c)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	recurringExpenseID := c.Param("recurringExpenseId")
-	if recurringExpenseID == "" {
-		response.Error(c, problem.BadRequest("recurringExpenseId is required"))
-		return
-	}
-
-	var req updateRecurringExpenseStatusRequest
-	if err := request.ValidBody(c, &req); err != nil {
+	expenseID := c.Param("expenseId")
+	if expenseID == "" {
+		response.Error(c, problem.BadRequest("expenseId is required"))
 		return
 	}
 
@@ -1331,43 +1622,43 @@ func (h *HttpHandler) UpdateRecurringExpenseStatus(c *gin.Context) {
 		return
 	}
 
-	recurringExpense, err := h.service.UpdateRecurringExpenseStatus(c.Request.Context(), actor, biz, recurringExpenseID, req.Status)
-	if err != nil {
+	if err := h.service.ClearExpenseAllocations(c.Request.Context(), actor, biz, expenseID); err != nil {
 		if database.IsRecordNotFound(err) {
-			response.Error(c, ErrRecurringExpenseNotFound(err))
+			response.Error(c, ErrExpenseNotFound(err))
 			return
 		}
-		response.Error(c, err)
+		response.Error(c, problem.InternalError().WithError(err))
 		return
 	}
 
-	response.SuccessJSON(c, http.StatusOK, ToRecurringExpenseResponse(recurringExpense))
+	response.SuccessEmpty(c, http.StatusNoContent)
 }
 
-// GetRecurringExpenseOccurrences returns all expense occurrences for a recurring expense
+// ListExpenseAllocationRules returns a paginated list of saved allocation rules
 //
-// @Summary      Get recurring expense occurrences
-// @Description  Returns all expense occurrences (instances) for a specific recurring expense
+// @Summary      List expense allocation rules
+// @Description  Returns a paginated list of saved expense allocation rules for the business
 // @Tags         accounting
 // @Produce      json
 // @Param        businessDescriptor path string true "Business descriptor"
-// @Param        recurringExpenseId path string true "Recurring Expense ID"
-// @Success      200 {array} Expense
+// @Param        page query int false "Page number (default: 1)"
+// @Param        pageSize query int false "Page size (default: 20, max: 100)"
+// @Param        orderBy query []string false "Sort order (e.g., name, -createdAt)"
+// @Success      200 {object} list.ListResponse[accounting.ExpenseAllocationRuleResponse]
 // @Failure      401 {object} problem.Problem
-// @Failure      404 {object} problem.Problem
 // @Failure      500 {object} problem.Problem
-// @Router       /v1/businesses/{businessDescriptor}/accounting/recurring-expenses/{recurringExpenseId}/occurrences [get]
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expense-allocation-rules [get]
 // @Security     BearerAuth
-func (h *HttpHandler) GetRecurringExpenseOccurrences(c *gin.Context) {
+func (h *HttpHandler) ListExpenseAllocationRules(c *gin.Context) {
 	actor, err := account.ActorFromContext(c)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	recurringExpenseID := c.Param("recurringExpenseId")
-	if recurringExpenseID == "" {
-		response.Error(c, problem.BadRequest("recurringExpenseId is required"))
+	var query listExpenseAllocationRulesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
 		return
 	}
 
@@ -1377,173 +1668,143 @@ func (h *HttpHandler) GetRecurringExpenseOccurrences(c *gin.Context) {
 		return
 	}
 
-	// Verify recurring expense exists and belongs to workspace
-	_, err = h.service.GetRecurringExpenseByID(c.Request.Context(), actor, biz, recurringExpenseID)
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+
+	listReq := list.NewListRequest(query.Page, query.PageSize, query.OrderBy, "")
+	rules, err := h.service.ListExpenseAllocationRules(c.Request.Context(), actor, biz, listReq)
 	if err != nil {
-		if database.IsRecordNotFound(err) {
-			response.Error(c, ErrRecurringExpenseNotFound(err))
-			return
-		}
 		response.Error(c, problem.InternalError().WithError(err))
 		return
 	}
 
-	occurrences, err := h.service.GetRecurringExpenseOccurrences(c.Request.Context(), actor, biz, recurringExpenseID)
+	totalCount, err := h.service.CountExpenseAllocationRules(c.Request.Context(), actor, biz)
 	if err != nil {
 		response.Error(c, problem.InternalError().WithError(err))
 		return
 	}
 
-	response.SuccessJSON(c, http.StatusOK, occurrences)
-}
-
-// Summary endpoints
-
-type summaryResponse struct {
-	TotalAssetValue  string `json:"totalAssetValue"`
-	TotalInvestments string `json:"totalInvestments"`
-	TotalWithdrawals string `json:"totalWithdrawals"`
-	TotalExpenses    string `json:"totalExpenses"`
-	SafeToDrawAmount string `json:"safeToDrawAmount"`
-	Currency         string `json:"currency"`
-	From             string `json:"from,omitempty"`
-	To               string `json:"to,omitempty"`
+	listResp := list.NewListResponse(ToExpenseAllocationRuleResponses(rules), query.Page, query.PageSize, totalCount, (int64(query.Page*query.PageSize) < totalCount))
+	response.SuccessJSON(c, http.StatusOK, listResp)
 }
 
-// GetAccountingSummary returns a summary of accounting metrics
+// GetExpenseAllocationRule returns a saved allocation rule by ID
 //
-// @Summary      Get accounting summary
-// @Description  Returns a summary of key accounting metrics for the authenticated workspace
+// @Summary      Get expense allocation rule
+// @Description  Returns a saved expense allocation rule by ID
 // @Tags         accounting
 // @Produce      json
 // @Param        businessDescriptor path string true "Business descriptor"
-// @Param        from query string false "Start date (YYYY-MM-DD)"
-// @Param        to query string false "End date (YYYY-MM-DD)"
-// @Success      200 {object} summaryResponse
-// @Failure      400 {object} problem.Problem
+// @Param        ruleId path string true "Allocation rule ID"
+// @Success      200 {object} accounting.ExpenseAllocationRuleResponse
 // @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
 // @Failure      500 {object} problem.Problem
-// @Router       /v1/businesses/{businessDescriptor}/accounting/summary [get]
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expense-allocation-rules/{ruleId} [get]
 // @Security     BearerAuth
-func (h *HttpHandler) GetAccountingSummary(c *gin.Context) {
+func (h *HttpHandler) GetExpenseAllocationRule(c *gin.Context) {
 	actor, err := account.ActorFromContext(c)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	var query summaryQuery
-	if err := c.ShouldBindQuery(&query); err != nil {
-		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
-		return
-	}
-
 	biz, err := h.getBusinessForWorkspace(c, actor)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	// Parse dates
-	var from, to time.Time
-	if query.From != "" {
-		from, err = time.Parse("2006-01-02", query.From)
-		if err != nil {
-			response.Error(c, problem.BadRequest("invalid from date format, use YYYY-MM-DD").WithError(err))
-			return
-		}
-	}
-	if query.To != "" {
-		to, err = time.Parse("2006-01-02", query.To)
-		if err != nil {
-			response.Error(c, problem.BadRequest("invalid to date format, use YYYY-MM-DD").WithError(err))
+	rule, err := h.service.GetExpenseAllocationRuleByID(c.Request.Context(), actor, biz, c.Param("ruleId"))
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrExpenseAllocationRuleNotFound(err))
 			return
 		}
-	}
-
-	// Get metrics
-	totalAssetValue, err := h.service.SumAssetsValue(c.Request.Context(), actor, biz, from, to)
-	if err != nil {
 		response.Error(c, problem.InternalError().WithError(err))
 		return
 	}
 
-	totalInvestments, err := h.service.SumInvestmentsAmount(c.Request.Context(), actor, biz, from, to)
-	if err != nil {
-		response.Error(c, problem.InternalError().WithError(err))
-		return
-	}
+	response.SuccessJSON(c, http.StatusOK, ToExpenseAllocationRuleResponse(rule))
+}
 
-	totalWithdrawals, err := h.service.SumWithdrawalsAmount(c.Request.Context(), actor, biz, from, to)
+// CreateExpenseAllocationRule saves a reusable allocation rule
+//
+// @Summary      Create expense allocation rule
+// @Description  Saves a percentage or amount split across orders or products that can be applied to expenses
+// @Tags         accounting
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        request body CreateExpenseAllocationRuleRequest true "Allocation rule"
+// @Success      201 {object} accounting.ExpenseAllocationRuleResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expense-allocation-rules [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CreateExpenseAllocationRule(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
 	if err != nil {
-		response.Error(c, problem.InternalError().WithError(err))
+		response.Error(c, err)
 		return
 	}
 
-	totalExpenses, err := h.service.SumExpensesAmount(c.Request.Context(), actor, biz, from, to)
-	if err != nil {
-		response.Error(c, problem.InternalError().WithError(err))
+	var req CreateExpenseAllocationRuleRequest
+	if err := request.ValidBody(c, &req); err != nil {
 		return
 	}
 
-	// For safe to draw, we need revenue and COGS from orders.
-	// Investments are tracked separately and do not count as revenue.
-	revenue, err := h.orderService.SumOrdersTotal(c.Request.Context(), actor, biz, from, to)
+	biz, err := h.getBusinessForWorkspace(c, actor)
 	if err != nil {
-		response.Error(c, problem.InternalError().WithError(err))
+		response.Error(c, err)
 		return
 	}
-	cogs, err := h.orderService.SumOrdersCOGS(c.Request.Context(), actor, biz, from, to)
-	if err != nil {
-		response.Error(c, problem.InternalError().WithError(err))
+
+	if err := h.ensureAllocationTargets(c, actor, biz, req.TargetType, req.Lines); err != nil {
+		response.Error(c, err)
 		return
 	}
-	safeToDrawAmount, err := h.service.ComputeSafeToDrawAmount(c.Request.Context(), actor, biz, revenue, cogs, from, to)
+
+	rule, err := h.service.CreateExpenseAllocationRule(c.Request.Context(), actor, biz, &req)
 	if err != nil {
-		response.Error(c, problem.InternalError().WithError(err))
+		response.Error(c, err)
 		return
 	}
 
-	summary := summaryResponse{
-		TotalAssetValue:  totalAssetValue.String(),
-		TotalInvestments: totalInvestments.String(),
-		TotalWithdrawals: totalWithdrawals.String(),
-		TotalExpenses:    totalExpenses.String(),
-		SafeToDrawAmount: safeToDrawAmount.String(),
-		Currency:         biz.Currency,
-		From:             query.From,
-		To:               query.To,
-	}
-
-	response.SuccessJSON(c, http.StatusOK, summary)
+	response.SuccessJSON(c, http.StatusCreated, ToExpenseAllocationRuleResponse(rule))
 }
 
-// recentActivitiesQuery defines query parameters for recent activities
-
-// ListRecentActivities returns a unified list of recent accounting activities
+// UpdateExpenseAllocationRule updates a saved allocation rule
 //
-// @Summary      List recent activities
-// @Description  Returns a unified list of recent accounting activities (expenses, investments, withdrawals) sorted by date descending
+// @Summary      Update expense allocation rule
+// @Description  Updates a saved expense allocation rule. Expenses it was applied to keep their allocations until it is applied again.
 // @Tags         accounting
+// @Accept       json
 // @Produce      json
 // @Param        businessDescriptor path string true "Business descriptor"
-// @Param        limit query int false "Number of items to return (default: 10, max: 50)"
-// @Success      200 {object} accounting.RecentActivitiesResponse
+// @Param        ruleId path string true "Allocation rule ID"
+// @Param        request body UpdateExpenseAllocationRuleRequest true "Allocation rule update"
+// @Success      200 {object} accounting.ExpenseAllocationRuleResponse
 // @Failure      400 {object} problem.Problem
 // @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
 // @Failure      500 {object} problem.Problem
-// @Router       /v1/businesses/{businessDescriptor}/accounting/recent-activities [get]
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expense-allocation-rules/{ruleId} [patch]
 // @Security     BearerAuth
-func (h *HttpHandler) ListRecentActivities(c *gin.Context) {
+func (h *HttpHandler) UpdateExpenseAllocationRule(c *gin.Context) {
 	actor, err := account.ActorFromContext(c)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	var query recentActivitiesQuery
-	if err := c.ShouldBindQuery(&query); err != nil {
-		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+	var req UpdateExpenseAllocationRuleRequest
+	if err := request.ValidBody(c, &req); err != nil {
 		return
 	}
 
@@ -1553,21 +1814,1297 @@ func (h *HttpHandler) ListRecentActivities(c *gin.Context) {
 		return
 	}
 
-	// Default limit
-	limit := query.Limit
-	if limit <= 0 {
-		limit = 10
+	ruleID := c.Param("ruleId")
+	if req.TargetType != "" || req.Lines != nil {
+		existing, err := h.service.GetExpenseAllocationRuleByID(c.Request.Context(), actor, biz, ruleID)
+		if err != nil {
+			if database.IsRecordNotFound(err) {
+				response.Error(c, ErrExpenseAllocationRuleNotFound(err))
+				return
+			}
+			response.Error(c, problem.InternalError().WithError(err))
+			return
+		}
+		targetType, lines := existing.TargetType, []AllocationLine(existing.Lines)
+		if req.TargetType != "" {
+			targetType = req.TargetType
+		}
+		if req.Lines != nil {
+			lines = req.Lines
+		}
+		if err := h.ensureAllocationTargets(c, actor, biz, targetType, lines); err != nil {
+			response.Error(c, err)
+			return
+		}
 	}
 
-	activities, err := h.service.GetRecentActivities(c.Request.Context(), actor, biz, limit)
+	rule, err := h.service.UpdateExpenseAllocationRule(c.Request.Context(), actor, biz, ruleID, &req)
 	if err != nil {
-		response.Error(c, problem.InternalError().WithError(err))
-		return
-	}
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrExpenseAllocationRuleNotFound(err))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToExpenseAllocationRuleResponse(rule))
+}
+
+// DeleteExpenseAllocationRule deletes a saved allocation rule
+//
+// @Summary      Delete expense allocation rule
+// @Description  Deletes a saved expense allocation rule. Allocations made with it are kept.
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        ruleId path string true "Allocation rule ID"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expense-allocation-rules/{ruleId} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteExpenseAllocationRule(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.service.DeleteExpenseAllocationRule(c.Request.Context(), actor, biz, c.Param("ruleId")); err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrExpenseAllocationRuleNotFound(err))
+			return
+		}
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// Recurring Expense endpoints
+
+// ListRecurringExpenses returns a paginated list of recurring expenses for the workspace
+//
+// @Summary      List recurring expenses
+// @Description  Returns a paginated list of all recurring expenses for the authenticated workspace
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        page query int false "Page number (default: 1)"
+// @Param        pageSize query int false "Page size (default: 20, max: 100)"
+// @Param        orderBy query []string false "Sort order (e.g., -amount, nextRecurringDate)"
+// @Success      200 {object} list.ListResponse[accounting.RecurringExpenseResponse]
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/recurring-expenses [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListRecurringExpenses(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var query listAssetsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+
+	listReq := list.NewListRequest(query.Page, query.PageSize, query.OrderBy, "")
+	recurringExpenses, err := h.service.ListRecurringExpenses(c.Request.Context(), actor, biz, listReq)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	totalCount, err := h.service.CountRecurringExpenses(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	listResp := list.NewListResponse(ToRecurringExpenseResponses(recurringExpenses), query.Page, query.PageSize, totalCount, (int64(query.Page*query.PageSize) < totalCount))
+	response.SuccessJSON(c, http.StatusOK, listResp)
+}
+
+// GetRecurringExpense returns a specific recurring expense by ID
+//
+// @Summary      Get recurring expense
+// @Description  Returns a specific recurring expense by ID for the authenticated workspace
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        recurringExpenseId path string true "Recurring Expense ID"
+// @Success      200 {object} accounting.RecurringExpenseResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/recurring-expenses/{recurringExpenseId} [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetRecurringExpense(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	recurringExpenseID := c.Param("recurringExpenseId")
+	if recurringExpenseID == "" {
+		response.Error(c, problem.BadRequest("recurringExpenseId is required"))
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	recurringExpense, err := h.service.GetRecurringExpenseByID(c.Request.Context(), actor, biz, recurringExpenseID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrRecurringExpenseNotFound(err))
+			return
+		}
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToRecurringExpenseResponse(recurringExpense))
+}
+
+// CreateRecurringExpense creates a new recurring expense
+//
+// @Summary      Create recurring expense
+// @Description  Creates a new recurring expense for the authenticated workspace
+// @Tags         accounting
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        request body CreateRecurringExpenseRequest true "Recurring expense data"
+// @Success      201 {object} accounting.RecurringExpenseResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/recurring-expenses [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CreateRecurringExpense(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var req CreateRecurringExpenseRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	recurringExpense, err := h.service.CreateRecurringExpense(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusCreated, ToRecurringExpenseResponse(recurringExpense))
+}
+
+// UpdateRecurringExpense updates an existing recurring expense
+//
+// @Summary      Update recurring expense
+// @Description  Updates an existing recurring expense for the authenticated workspace
+// @Tags         accounting
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        recurringExpenseId path string true "Recurring Expense ID"
+// @Param        request body UpdateRecurringExpenseRequest true "Recurring expense update data"
+// @Success      200 {object} accounting.RecurringExpenseResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/recurring-expenses/{recurringExpenseId} [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdateRecurringExpense(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	recurringExpenseID := c.Param("recurringExpenseId")
+	if recurringExpenseID == "" {
+		response.Error(c, problem.BadRequest("recurringExpenseId is required"))
+		return
+	}
+
+	var req UpdateRecurringExpenseRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	recurringExpense, err := h.service.UpdateRecurringExpense(c.Request.Context(), actor, biz, recurringExpenseID, &req)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrRecurringExpenseNotFound(err))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToRecurringExpenseResponse(recurringExpense))
+}
+
+// DeleteRecurringExpense deletes a recurring expense
+//
+// @Summary      Delete recurring expense
+// @Description  Deletes a recurring expense for the authenticated workspace
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        recurringExpenseId path string true "Recurring Expense ID"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/recurring-expenses/{recurringExpenseId} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteRecurringExpense(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	recurringExpenseID := c.Param("recurringExpenseId")
+	if recurringExpenseID == "" {
+		response.Error(c, problem.BadRequest("recurringExpenseId is required"))
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	err = h.service.DeleteRecurringExpense(c.Request.Context(), actor, biz, recurringExpenseID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrRecurringExpenseNotFound(err))
+			return
+		}
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// UpdateRecurringExpenseStatus updates the status of a recurring expense
+//
+// @Summary      Update recurring expense status
+// @Description  Updates the status of a recurring expense (active, paused, ended, canceled)
+// @Tags         accounting
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        recurringExpenseId path string true "Recurring Expense ID"
+// @Param        request body updateRecurringExpenseStatusRequest true "Status update data"
+// @Success      200 {object} accounting.RecurringExpenseResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/recurring-expenses/{recurringExpenseId}/status [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdateRecurringExpenseStatus(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	recurringExpenseID := c.Param("recurringExpenseId")
+	if recurringExpenseID == "" {
+		response.Error(c, problem.BadRequest("recurringExpenseId is required"))
+		return
+	}
+
+	var req updateRecurringExpenseStatusRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	recurringExpense, err := h.service.UpdateRecurringExpenseStatus(c.Request.Context(), actor, biz, recurringExpenseID, req.Status)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrRecurringExpenseNotFound(err))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToRecurringExpenseResponse(recurringExpense))
+}
+
+// GetRecurringExpenseOccurrences returns all expense occurrences for a recurring expense
+//
+// @Summary      Get recurring expense occurrences
+// @Description  Returns all expense occurrences (instances) for a specific recurring expense
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        recurringExpenseId path string true "Recurring Expense ID"
+// @Success      200 {array} Expense
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/recurring-expenses/{recurringExpenseId}/occurrences [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetRecurringExpenseOccurrences(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	recurringExpenseID := c.Param("recurringExpenseId")
+	if recurringExpenseID == "" {
+		response.Error(c, problem.BadRequest("recurringExpenseId is required"))
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// Verify recurring expense exists and belongs to workspace
+	_, err = h.service.GetRecurringExpenseByID(c.Request.Context(), actor, biz, recurringExpenseID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrRecurringExpenseNotFound(err))
+			return
+		}
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	occurrences, err := h.service.GetRecurringExpenseOccurrences(c.Request.Context(), actor, biz, recurringExpenseID)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, occurrences)
+}
+
+// Summary endpoints
+
+type summaryResponse struct {
+	TotalAssetValue  string `json:"totalAssetValue"`
+	TotalInvestments string `json:"totalInvestments"`
+	TotalWithdrawals string `json:"totalWithdrawals"`
+	TotalExpenses    string `json:"totalExpenses"`
+	TotalRevenue     string `json:"totalRevenue"`
+	TotalCOGS        string `json:"totalCogs"`
+	GrossProfit      string `json:"grossProfit"`
+	NetProfit        string `json:"netProfit"`
+	SafeToDrawAmount string `json:"safeToDrawAmount"`
+	Currency         string `json:"currency"`
+	From             string `json:"from,omitempty"`
+	To               string `json:"to,omitempty"`
+}
+
+// GetAccountingSummary returns a summary of accounting metrics
+//
+// @Summary      Get accounting summary
+// @Description  Returns a summary of key accounting metrics for the authenticated workspace
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        from query string false "Start date (YYYY-MM-DD)"
+// @Param        to query string false "End date (YYYY-MM-DD)"
+// @Success      200 {object} summaryResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/summary [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetAccountingSummary(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var query summaryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// Parse dates
+	var from, to time.Time
+	if query.From != "" {
+		from, err = time.Parse("2006-01-02", query.From)
+		if err != nil {
+			response.Error(c, problem.BadRequest("invalid from date format, use YYYY-MM-DD").WithError(err))
+			return
+		}
+	}
+	if query.To != "" {
+		to, err = time.Parse("2006-01-02", query.To)
+		if err != nil {
+			response.Error(c, problem.BadRequest("invalid to date format, use YYYY-MM-DD").WithError(err))
+			return
+		}
+	}
+
+	// Get metrics
+	totalAssetValue, err := h.service.SumAssetsValue(c.Request.Context(), actor, biz, from, to)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	totalInvestments, err := h.service.SumInvestmentsAmount(c.Request.Context(), actor, biz, from, to)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	totalWithdrawals, err := h.service.SumWithdrawalsAmount(c.Request.Context(), actor, biz, from, to)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	totalExpenses, err := h.service.SumExpensesAmount(c.Request.Context(), actor, biz, from, to)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	// Revenue and COGS are recognized from order events, so trading needs no manual entry.
+	trading, err := h.service.ComputeTradingTotals(c.Request.Context(), actor, biz, from, to)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	// Safe to draw comes from the ledger: income booked from fulfilled orders less expenses,
+	// COGS and owner drawings. Investments are equity and do not count as income.
+	safeToDrawAmount, err := h.service.ComputeSafeToDrawAmount(c.Request.Context(), actor, biz, from, to)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	summary := summaryResponse{
+		TotalAssetValue:  totalAssetValue.String(),
+		TotalInvestments: totalInvestments.String(),
+		TotalWithdrawals: totalWithdrawals.String(),
+		TotalExpenses:    totalExpenses.String(),
+		TotalRevenue:     trading.Revenue.String(),
+		TotalCOGS:        trading.COGS.String(),
+		GrossProfit:      trading.GrossProfit.String(),
+		NetProfit:        trading.NetProfit.String(),
+		SafeToDrawAmount: safeToDrawAmount.String(),
+		Currency:         biz.Currency,
+		From:             query.From,
+		To:               query.To,
+	}
+
+	response.SuccessJSON(c, http.StatusOK, summary)
+}
+
+// recentActivitiesQuery defines query parameters for recent activities
+
+// ListRecentActivities returns a unified list of recent accounting activities
+//
+// @Summary      List recent activities
+// @Description  Returns a unified list of recent accounting activities (expenses, investments, withdrawals) sorted by date descending
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        limit query int false "Number of items to return (default: 10, max: 50)"
+// @Success      200 {object} accounting.RecentActivitiesResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/recent-activities [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListRecentActivities(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var query recentActivitiesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// Default limit
+	limit := query.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	activities, err := h.service.GetRecentActivities(c.Request.Context(), actor, biz, limit)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	resp := RecentActivitiesResponse{
+		Items: activities,
+	}
+
+	response.SuccessJSON(c, http.StatusOK, resp)
+}
+
+// Ledger endpoints
+
+// ledgerDateRange turns optional from/to dates into the range filtered on, including the
+// whole "to" date.
+func ledgerDateRange(fromDate, toDate *time.Time) (from, to time.Time) {
+	if fromDate != nil {
+		from = *fromDate
+	}
+	if toDate != nil {
+		to = toDate.AddDate(0, 0, 1)
+	}
+	return from, to
+}
+
+// ListLedgerAccounts returns the chart of accounts with the balance of each account
+//
+// @Summary      List ledger accounts
+// @Description  Returns the business' chart of accounts (assets, liabilities, equity, income, expenses) with the debits, credits and balance of each account over the given range
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        from query string false "Balances from date (YYYY-MM-DD)"
+// @Param        to query string false "Balances to date (YYYY-MM-DD)"
+// @Success      200 {object} accounting.LedgerAccountsResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/ledger/accounts [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListLedgerAccounts(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var query ledgerAccountsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	from, to := ledgerDateRange(query.From, query.To)
+	balances, err := h.service.ListLedgerAccountBalances(c.Request.Context(), actor, biz, from, to)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToLedgerAccountsResponse(balances, biz.Currency))
+}
+
+// ListJournalEntries returns a paginated list of journal entries
+//
+// @Summary      List journal entries
+// @Description  Returns the business' journal entries with their debit and credit lines, newest first. Entries are posted automatically from orders, expenses, investments, withdrawals and order reversals
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        page query int false "Page number (default: 1)"
+// @Param        pageSize query int false "Page size (default: 20, max: 100)"
+// @Param        orderBy query []string false "Sort order (e.g., postedAt, -postedAt)"
+// @Param        sourceType query string false "Filter by source (order_sale, order_payment, order_reversal, expense, investment, withdrawal)"
+// @Param        from query string false "Filter from date (YYYY-MM-DD)"
+// @Param        to query string false "Filter to date (YYYY-MM-DD)"
+// @Success      200 {object} list.ListResponse[accounting.JournalEntryResponse]
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/ledger/entries [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListJournalEntries(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var query listJournalEntriesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+
+	filter := &ListJournalEntriesFilter{SourceType: query.SourceType}
+	filter.From, filter.To = ledgerDateRange(query.From, query.To)
+
+	listReq := list.NewListRequest(query.Page, query.PageSize, query.OrderBy, "")
+	entries, err := h.service.ListJournalEntries(c.Request.Context(), actor, biz, listReq, filter)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	totalCount, err := h.service.CountJournalEntries(c.Request.Context(), actor, biz, filter)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	listResp := list.NewListResponse(ToJournalEntryResponses(entries), query.Page, query.PageSize, totalCount, (int64(query.Page*query.PageSize) < totalCount))
+	response.SuccessJSON(c, http.StatusOK, listResp)
+}
+
+// ExportJournalEntries downloads the journal entries of a period for an accounting package
+//
+// @Summary      Export journal entries
+// @Description  Downloads the journal entries posted in the range (sales, COGS, payments, order reversals, expenses, investments and withdrawals) as a QuickBooks IIF file (format=quickbooks) or a Xero manual journal CSV (format=xero). Debits are positive amounts and credits negative
+// @Tags         accounting
+// @Produce      text/plain
+// @Produce      text/csv
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        format query string true "quickbooks or xero"
+// @Param        from query string false "Entries from date (YYYY-MM-DD)"
+// @Param        to query string false "Entries to date (YYYY-MM-DD)"
+// @Success      200 {file} file
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/ledger/export [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ExportJournalEntries(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var query exportJournalQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	from, to := ledgerDateRange(query.From, query.To)
+	entries, err := h.service.ListJournalEntriesForExport(c.Request.Context(), actor, biz, from, to)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+
+	filename := "journal-" + biz.Descriptor
+	if query.From != nil {
+		filename += "-" + query.From.Format("2006-01-02")
+	}
+	if query.To != nil {
+		filename += "-" + query.To.Format("2006-01-02")
+	}
+	response.SuccessFile(c, http.StatusOK, query.Format.ContentType(), filename+"."+query.Format.Extension(), query.Format.Render(entries, biz.Location()))
+}
+
+// IntakeExpenseReceipt uploads a receipt and creates an expense draft from it
+//
+// @Summary      Upload expense receipt
+// @Description  Stores a receipt image or PDF and creates a pending expense draft prefilled with the amount, date and vendor read off it
+// @Tags         accounting
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        file formData file true "Receipt image or PDF"
+// @Success      201 {object} accounting.ExpenseDraftResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      413 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expense-drafts [post]
+// @Security     BearerAuth
+func (h *HttpHandler) IntakeExpenseReceipt(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	fileName, contentType, data, err := readReceiptFile(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	draft, err := h.service.IntakeReceipt(c.Request.Context(), biz, actor.ID, ExpenseDraftSourceUpload, "", fileName, contentType, data)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusCreated, ToExpenseDraftResponse(draft))
+}
+
+// ScanExpenseReceipt reads a receipt and returns the expense it describes without saving it
+//
+// @Summary      Scan expense receipt
+// @Description  Runs OCR on a receipt image or PDF and returns a prefilled expense (amount, VAT, date, vendor and suggested category) to review before creating it
+// @Tags         accounting
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        file formData file true "Receipt image or PDF"
+// @Success      200 {object} accounting.ExpenseScan
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      413 {object} problem.Problem
+// @Failure      422 {object} problem.Problem
+// @Failure      429 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expenses/scan [post]
+// @Security     BearerAuth
+func (h *HttpHandler) ScanExpenseReceipt(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	_, contentType, data, err := readReceiptFile(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	scan, err := h.service.ScanReceipt(c.Request.Context(), actor, biz, contentType, data)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, scan)
+}
+
+// readReceiptFile reads the receipt uploaded in the "file" form field, guessing its content
+// type from the file extension when the client did not send one.
+func readReceiptFile(c *gin.Context) (fileName, contentType string, data []byte, err error) {
+	fh, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return "", "", nil, problem.PayloadTooLarge("request body too large").WithCode("request.body_too_large")
+		}
+		return "", "", nil, problem.BadRequest("receipt file is required").With("field", "file").WithError(err)
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return "", "", nil, problem.InternalError().WithError(err)
+	}
+	defer f.Close()
+	data, err = io.ReadAll(f)
+	if err != nil {
+		return "", "", nil, problem.InternalError().WithError(err)
+	}
+	contentType, _, _ = mime.ParseMediaType(fh.Header.Get("Content-Type"))
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType, _, _ = mime.ParseMediaType(mime.TypeByExtension(filepath.Ext(fh.Filename)))
+	}
+	return fh.Filename, contentType, data, nil
+}
+
+// ListExpenseDrafts returns a paginated list of expense drafts
+//
+// @Summary      List expense drafts
+// @Description  Returns the business' receipt drafts, newest first unless ordered otherwise
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        page query int false "Page number (default: 1)"
+// @Param        pageSize query int false "Page size (default: 20, max: 100)"
+// @Param        orderBy query []string false "Sort order (e.g., -createdAt, amount)"
+// @Param        status query string false "pending, confirmed or discarded"
+// @Success      200 {object} list.ListResponse[accounting.ExpenseDraftResponse]
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expense-drafts [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListExpenseDrafts(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var query listExpenseDraftsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+
+	listReq := list.NewListRequest(query.Page, query.PageSize, query.OrderBy, "")
+	drafts, totalCount, err := h.service.ListExpenseDrafts(c.Request.Context(), actor, biz, listReq, query.Status)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
 
-	resp := RecentActivitiesResponse{
-		Items: activities,
+	listResp := list.NewListResponse(ToExpenseDraftResponses(drafts), query.Page, query.PageSize, totalCount, (int64(query.Page*query.PageSize) < totalCount))
+	response.SuccessJSON(c, http.StatusOK, listResp)
+}
+
+// GetExpenseDraft returns a specific expense draft by ID
+//
+// @Summary      Get expense draft
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        draftId path string true "Expense draft ID"
+// @Success      200 {object} accounting.ExpenseDraftResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expense-drafts/{draftId} [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetExpenseDraft(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
 	}
 
-	response.SuccessJSON(c, http.StatusOK, resp)
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	draft, err := h.service.GetExpenseDraftByID(c.Request.Context(), actor, biz, c.Param("draftId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToExpenseDraftResponse(draft))
+}
+
+// UpdateExpenseDraft corrects a pending expense draft
+//
+// @Summary      Update expense draft
+// @Description  Corrects the amount, date, vendor, category or note of a pending draft
+// @Tags         accounting
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        draftId path string true "Expense draft ID"
+// @Param        request body UpdateExpenseDraftRequest true "Draft corrections"
+// @Success      200 {object} accounting.ExpenseDraftResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expense-drafts/{draftId} [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdateExpenseDraft(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var req UpdateExpenseDraftRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	draft, err := h.service.UpdateExpenseDraft(c.Request.Context(), actor, biz, c.Param("draftId"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToExpenseDraftResponse(draft))
+}
+
+// ConfirmExpenseDraft records the expense described by a pending draft
+//
+// @Summary      Confirm expense draft
+// @Description  Creates a one-time expense from the draft and marks the draft confirmed
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        draftId path string true "Expense draft ID"
+// @Success      201 {object} accounting.ExpenseResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expense-drafts/{draftId}/confirm [post]
+// @Security     BearerAuth
+func (h *HttpHandler) ConfirmExpenseDraft(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	_, expense, err := h.service.ConfirmExpenseDraft(c.Request.Context(), actor, biz, c.Param("draftId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusCreated, ToExpenseResponse(expense))
+}
+
+// DiscardExpenseDraft discards a pending expense draft
+//
+// @Summary      Discard expense draft
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        draftId path string true "Expense draft ID"
+// @Success      200 {object} accounting.ExpenseDraftResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expense-drafts/{draftId}/discard [post]
+// @Security     BearerAuth
+func (h *HttpHandler) DiscardExpenseDraft(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	draft, err := h.service.DiscardExpenseDraft(c.Request.Context(), actor, biz, c.Param("draftId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToExpenseDraftResponse(draft))
+}
+
+// GetExpenseInbox returns the business' expense inbox address
+//
+// @Summary      Get expense inbox
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {object} accounting.ExpenseInboxResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expense-inbox [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetExpenseInbox(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	inbox, err := h.service.GetExpenseInbox(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToExpenseInboxResponse(inbox))
+}
+
+// EnableExpenseInbox gives the business an email address for receipts
+//
+// @Summary      Enable expense inbox
+// @Description  Creates the business' expense inbox, or returns the existing one. Receipts emailed to it become expense drafts.
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {object} accounting.ExpenseInboxResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expense-inbox [post]
+// @Security     BearerAuth
+func (h *HttpHandler) EnableExpenseInbox(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	inbox, err := h.service.EnableExpenseInbox(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToExpenseInboxResponse(inbox))
+}
+
+// RotateExpenseInbox replaces the expense inbox address
+//
+// @Summary      Rotate expense inbox
+// @Description  Issues a new inbox address; mail to the previous address is no longer accepted
+// @Tags         accounting
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {object} accounting.ExpenseInboxResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expense-inbox/rotate [post]
+// @Security     BearerAuth
+func (h *HttpHandler) RotateExpenseInbox(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	inbox, err := h.service.RotateExpenseInbox(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToExpenseInboxResponse(inbox))
+}
+
+// DisableExpenseInbox removes the expense inbox
+//
+// @Summary      Disable expense inbox
+// @Tags         accounting
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/accounting/expense-inbox [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DisableExpenseInbox(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.service.DisableExpenseInbox(c.Request.Context(), actor, biz); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// ReceiveInboundReceiptEmail accepts emails forwarded by the inbound mail provider
+//
+// @Summary      Expense inbox webhook
+// @Description  Turns the attachments of an email sent to an expense inbox into expense drafts. Authenticated with the shared secret in X-Inbox-Secret.
+// @Tags         accounting
+// @Accept       json
+// @Produce      json
+// @Param        X-Inbox-Secret header string true "Shared inbox webhook secret"
+// @Param        request body InboundReceiptEmail true "Inbound email"
+// @Success      200 {object} accounting.InboundReceiptEmailResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      429 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/webhooks/expense-inbox [post]
+func (h *HttpHandler) ReceiveInboundReceiptEmail(c *gin.Context) {
+	expected := viper.GetString(config.AccountingExpenseInboxSecret)
+	if expected == "" {
+		response.Error(c, problem.NotFound("not found").WithCode("accounting.expense_inbox_disabled"))
+		return
+	}
+	secret := c.GetHeader("X-Inbox-Secret")
+	if secret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(expected)) != 1 {
+		response.Error(c, problem.Unauthorized("unauthorized").WithCode("accounting.invalid_inbox_secret"))
+		return
+	}
+
+	var req InboundReceiptEmail
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+
+	drafts, err := h.service.ReceiveInboundReceiptEmail(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	ids := make([]string, len(drafts))
+	for i, d := range drafts {
+		ids[i] = d.ID
+	}
+	response.SuccessJSON(c, http.StatusOK, InboundReceiptEmailResponse{DraftIDs: ids})
 }
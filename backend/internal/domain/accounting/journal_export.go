@@ -0,0 +1,122 @@
+package accounting
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+)
+
+// JournalExportFormat is the accounting package a journal export is meant to be imported into.
+type JournalExportFormat string
+
+const (
+	// JournalExportQuickBooks is a QuickBooks Desktop IIF file of general journal transactions.
+	JournalExportQuickBooks JournalExportFormat = "quickbooks"
+	// JournalExportXero is a CSV laid out as Xero's manual journal import template.
+	JournalExportXero JournalExportFormat = "xero"
+)
+
+// ContentType returns the MIME type of the export file.
+func (f JournalExportFormat) ContentType() string {
+	if f == JournalExportQuickBooks {
+		return "text/plain"
+	}
+	return "text/csv"
+}
+
+// Extension returns the file extension of the export file, without the dot.
+func (f JournalExportFormat) Extension() string {
+	if f == JournalExportQuickBooks {
+		return "iif"
+	}
+	return "csv"
+}
+
+// Render renders the entries in the format. Dates are written in loc.
+func (f JournalExportFormat) Render(entries []*JournalEntry, loc *time.Location) []byte {
+	if f == JournalExportQuickBooks {
+		return RenderJournalIIF(entries, loc)
+	}
+	return RenderJournalXeroCSV(entries, loc)
+}
+
+// iifAccountTypes maps the default chart of accounts to QuickBooks account types, so importing
+// the file creates any account missing from the company file with the right type.
+var iifAccountTypes = map[LedgerAccountCode]string{
+	LedgerAccountCash:               "BANK",
+	LedgerAccountReceivable:         "AR",
+	LedgerAccountInventory:          "OCASSET",
+	LedgerAccountVATPayable:         "OCLIAB",
+	LedgerAccountOwnerCapital:       "EQUITY",
+	LedgerAccountOwnerDrawings:      "EQUITY",
+	LedgerAccountSales:              "INC",
+	LedgerAccountCOGS:               "COGS",
+	LedgerAccountOperatingExpenses:  "EXP",
+	LedgerAccountTransactionFeeCost: "EXP",
+}
+
+// iifField strips the tabs and line breaks that would break an IIF row.
+func iifField(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// signedAmount is the line's amount with debits positive and credits negative, which is how
+// both QuickBooks and Xero read journal lines.
+func signedAmount(l *JournalLine, currency string) string {
+	return money.StringFixed(l.Debit.Sub(l.Credit), currency)
+}
+
+// RenderJournalIIF renders journal entries as a QuickBooks IIF file: the chart of accounts,
+// then one GENERAL JOURNAL transaction per entry whose first line is the TRNS row and the
+// others SPL rows. The entry ID is the document number.
+func RenderJournalIIF(entries []*JournalEntry, loc *time.Location) []byte {
+	var buf bytes.Buffer
+	row := func(fields ...string) {
+		buf.WriteString(strings.Join(fields, "\t"))
+		buf.WriteString("\r\n")
+	}
+
+	row("!ACCNT", "NAME", "ACCNTTYPE", "ACCNUM")
+	for _, def := range DefaultChartOfAccounts {
+		row("ACCNT", def.Name, iifAccountTypes[def.Code], string(def.Code))
+	}
+
+	row("!TRNS", "TRNSID", "TRNSTYPE", "DATE", "ACCNT", "AMOUNT", "DOCNUM", "MEMO")
+	row("!SPL", "SPLID", "TRNSTYPE", "DATE", "ACCNT", "AMOUNT", "DOCNUM", "MEMO")
+	row("!ENDTRNS")
+	for _, e := range entries {
+		date := e.PostedAt.In(loc).Format("01/02/2006")
+		memo := iifField(e.Description)
+		for i, l := range e.Lines {
+			kind := "SPL"
+			if i == 0 {
+				kind = "TRNS"
+			}
+			row(kind, "", "GENERAL JOURNAL", date, iifField(l.Account.Name), signedAmount(l, e.Currency), e.ID, memo)
+		}
+		row("ENDTRNS")
+	}
+	return buf.Bytes()
+}
+
+// RenderJournalXeroCSV renders journal entries as a Xero manual journal import file, one row
+// per line. Xero groups rows into journals by narration and date, so the narration carries the
+// entry ID to keep entries with the same description on the same day apart. VAT is booked on
+// its own account, so lines carry no Xero tax rate.
+func RenderJournalXeroCSV(entries []*JournalEntry, loc *time.Location) []byte {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"*Narration", "*Date", "Description", "*AccountCode", "*TaxRate", "*Amount", "TrackingName1", "TrackingOption1", "TrackingName2", "TrackingOption2"})
+	for _, e := range entries {
+		narration := e.Description + " (" + e.ID + ")"
+		date := e.PostedAt.In(loc).Format("2006-01-02")
+		for _, l := range e.Lines {
+			_ = w.Write([]string{narration, date, l.Account.Name, string(l.Account.Code), "Tax Exempt", signedAmount(l, e.Currency), "", "", "", ""})
+		}
+	}
+	w.Flush()
+	return buf.Bytes()
+}
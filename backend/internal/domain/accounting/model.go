@@ -2,10 +2,15 @@ package accounting
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/abdelrahman146/kyora/internal/domain/account"
 	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
 	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
 	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
 	"github.com/shopspring/decimal"
@@ -153,6 +158,10 @@ type Withdrawal struct {
 	Withdrawer   *account.User      `json:"withdrawer,omitempty" gorm:"foreignKey:WithdrawerID;references:ID"`
 	Note         string             `json:"note" gorm:"column:note;type:text"`
 	WithdrawnAt  time.Time          `json:"withdrawnAt" gorm:"column:withdrawn_at;type:timestamptz;not null;default:now()"`
+	// DistributedFrom and DistributedTo are the profit period a profit distribution withdrawal
+	// pays out; they are not set on other withdrawals.
+	DistributedFrom sql.NullTime `json:"distributedFrom" gorm:"column:distributed_from;type:date"`
+	DistributedTo   sql.NullTime `json:"distributedTo" gorm:"column:distributed_to;type:date"`
 }
 
 func (m *Withdrawal) BeforeCreate(tx *gorm.DB) (err error) {
@@ -204,6 +213,14 @@ const (
 	ExpenseTypeRecurring ExpenseType = "recurring"
 )
 
+// ExchangeRateSource tells where the rate of a foreign-currency expense came from.
+type ExchangeRateSource string
+
+const (
+	ExchangeRateSourceManual ExchangeRateSource = "manual"
+	ExchangeRateSourceAuto   ExchangeRateSource = "auto"
+)
+
 type ExpenseCategory string
 
 const (
@@ -254,20 +271,30 @@ func ExpenseCategoriesList() []ExpenseCategory {
 	}
 }
 
+// Expense is money spent by the business. Amount, VAT and Currency are always in the business
+// currency so every aggregate can add them up; an expense paid in another currency also keeps
+// what was paid (Original*) and the ExchangeRate used to convert it.
 type Expense struct {
 	gorm.Model
-	ID                 string             `gorm:"column:id;primaryKey;type:text" json:"id"`
-	BusinessID         string             `gorm:"column:business_id;type:text;not null;index;uniqueIndex:idx_expense_business_order_category" json:"businessId"`
-	Business           *business.Business `gorm:"foreignKey:BusinessID;references:ID" json:"business,omitempty"`
-	OrderID            sql.NullString     `gorm:"column:order_id;type:text;index;uniqueIndex:idx_expense_business_order_category" json:"orderId,omitempty"`
-	RecurringExpenseID sql.NullString     `gorm:"column:recurring_expense_id;type:text;index" json:"recurringExpenseId"`
-	RecurringExpense   *RecurringExpense  `gorm:"foreignKey:RecurringExpenseID;references:ID" json:"recurringExpense,omitempty"`
-	Amount             decimal.Decimal    `gorm:"column:amount;type:numeric;not null" json:"amount"`
-	Currency           string             `gorm:"column:currency;type:text;not null;default:'USD'" json:"currency"`
-	OccurredOn         time.Time          `gorm:"column:occurred_on;type:date;not null;default:now()" json:"occurredOn"`
-	Category           ExpenseCategory    `gorm:"column:category;type:text;not null;index;uniqueIndex:idx_expense_business_order_category" json:"category"`
-	Type               ExpenseType        `gorm:"column:type;type:text;not null;index" json:"type"`
-	Note               sql.NullString     `gorm:"column:note;type:text" json:"note"`
+	ID                 string              `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID         string              `gorm:"column:business_id;type:text;not null;index;uniqueIndex:idx_expense_business_order_category" json:"businessId"`
+	Business           *business.Business  `gorm:"foreignKey:BusinessID;references:ID" json:"business,omitempty"`
+	OrderID            sql.NullString      `gorm:"column:order_id;type:text;index;uniqueIndex:idx_expense_business_order_category" json:"orderId,omitempty"`
+	RecurringExpenseID sql.NullString      `gorm:"column:recurring_expense_id;type:text;index" json:"recurringExpenseId"`
+	RecurringExpense   *RecurringExpense   `gorm:"foreignKey:RecurringExpenseID;references:ID" json:"recurringExpense,omitempty"`
+	Amount             decimal.Decimal     `gorm:"column:amount;type:numeric;not null" json:"amount"`
+	VAT                decimal.Decimal     `gorm:"column:vat;type:numeric;not null;default:0" json:"vat"` // Recoverable input VAT included in Amount.
+	Currency           string              `gorm:"column:currency;type:text;not null;default:'USD'" json:"currency"`
+	OriginalAmount     decimal.NullDecimal `gorm:"column:original_amount;type:numeric" json:"originalAmount"`
+	OriginalVAT        decimal.NullDecimal `gorm:"column:original_vat;type:numeric" json:"originalVat"`
+	OriginalCurrency   sql.NullString      `gorm:"column:original_currency;type:text" json:"originalCurrency"`
+	ExchangeRate       decimal.NullDecimal `gorm:"column:exchange_rate;type:numeric" json:"exchangeRate"`
+	ExchangeRateSource ExchangeRateSource  `gorm:"column:exchange_rate_source;type:text" json:"exchangeRateSource"`
+	OccurredOn         time.Time           `gorm:"column:occurred_on;type:date;not null;default:now()" json:"occurredOn"`
+	Category           ExpenseCategory     `gorm:"column:category;type:text;not null;index;uniqueIndex:idx_expense_business_order_category" json:"category"`
+	Type               ExpenseType         `gorm:"column:type;type:text;not null;index" json:"type"`
+	Note               sql.NullString      `gorm:"column:note;type:text" json:"note"`
+	SplitID            sql.NullString      `gorm:"column:split_id;type:text;index" json:"splitId,omitempty"` // Shared by the lines an expense was split into.
 }
 
 func (m *Expense) TableName() string {
@@ -289,11 +316,13 @@ var ExpenseSchema = struct {
 	OrderID            schema.Field
 	RecurringExpenseID schema.Field
 	Amount             schema.Field
+	VAT                schema.Field
 	Currency           schema.Field
 	OccurredOn         schema.Field
 	Category           schema.Field
 	Type               schema.Field
 	Note               schema.Field
+	SplitID            schema.Field
 	CreatedAt          schema.Field
 	UpdatedAt          schema.Field
 	DeletedAt          schema.Field
@@ -303,11 +332,13 @@ var ExpenseSchema = struct {
 	OrderID:            schema.NewField("order_id", "orderId"),
 	RecurringExpenseID: schema.NewField("recurring_expense_id", "recurringExpenseId"),
 	Amount:             schema.NewField("amount", "amount"),
+	VAT:                schema.NewField("vat", "vat"),
 	Currency:           schema.NewField("currency", "currency"),
 	OccurredOn:         schema.NewField("occurred_on", "occurredOn"),
 	Category:           schema.NewField("category", "category"),
 	Type:               schema.NewField("type", "type"),
 	Note:               schema.NewField("note", "note"),
+	SplitID:            schema.NewField("split_id", "splitId"),
 	CreatedAt:          schema.NewField("created_at", "createdAt"),
 	UpdatedAt:          schema.NewField("updated_at", "updatedAt"),
 	DeletedAt:          schema.NewField("deleted_at", "deletedAt"),
@@ -379,7 +410,9 @@ type RecurringExpense struct {
 	Category           ExpenseCategory           `gorm:"column:category;type:text;not null" json:"category"`
 	Status             RecurringExpenseStatus    `gorm:"column:status;type:text;not null;default:'active'" json:"status"`
 	Note               sql.NullString            `gorm:"column:note;type:text" json:"note"`
-	Expenses           []*Expense                `gorm:"foreignKey:RecurringExpenseID;references:ID" json:"expenses,omitempty"`
+	// ReminderSentFor is the occurrence date the last upcoming charge reminder was sent for.
+	ReminderSentFor sql.NullTime `gorm:"column:reminder_sent_for;type:date" json:"-"`
+	Expenses        []*Expense   `gorm:"foreignKey:RecurringExpenseID;references:ID" json:"expenses,omitempty"`
 }
 
 func (m *RecurringExpense) TableName() string {
@@ -407,6 +440,7 @@ var RecurringExpenseSchema = struct {
 	Category           schema.Field
 	Status             schema.Field
 	Note               schema.Field
+	ReminderSentFor    schema.Field
 	CreatedAt          schema.Field
 	UpdatedAt          schema.Field
 	DeletedAt          schema.Field
@@ -422,7 +456,255 @@ var RecurringExpenseSchema = struct {
 	Category:           schema.NewField("category", "category"),
 	Status:             schema.NewField("status", "status"),
 	Note:               schema.NewField("note", "note"),
+	ReminderSentFor:    schema.NewField("reminder_sent_for", "reminderSentFor"),
 	CreatedAt:          schema.NewField("created_at", "createdAt"),
 	UpdatedAt:          schema.NewField("updated_at", "updatedAt"),
 	DeletedAt:          schema.NewField("deleted_at", "deletedAt"),
 }
+
+/* Expense Allocation Models */
+//--------------------------------*/
+
+const (
+	ExpenseAllocationRuleTable  = "expense_allocation_rules"
+	ExpenseAllocationRuleStruct = "Rule"
+	ExpenseAllocationRulePrefix = "ealr"
+	ExpenseAllocationTable      = "expense_allocations"
+	ExpenseAllocationPrefix     = "eal"
+)
+
+// AllocationTargetType is what an expense is allocated to.
+type AllocationTargetType string
+
+const (
+	AllocationTargetOrder   AllocationTargetType = "order"
+	AllocationTargetProduct AllocationTargetType = "product"
+)
+
+// AllocationMethod is how allocation line values are interpreted.
+type AllocationMethod string
+
+const (
+	// AllocationMethodPercentage treats line values as percentages of the expense amount.
+	AllocationMethodPercentage AllocationMethod = "percentage"
+	// AllocationMethodAmount treats line values as fixed amounts in the expense currency.
+	AllocationMethodAmount AllocationMethod = "amount"
+)
+
+// AllocationLine assigns a share of an expense to one order or product.
+type AllocationLine struct {
+	TargetID string          `json:"targetId" binding:"required"`
+	Value    decimal.Decimal `json:"value" binding:"required,dgt=0"`
+}
+
+// AllocationLines is stored as a jsonb array on allocation rules.
+type AllocationLines []AllocationLine
+
+func (l AllocationLines) Value() (driver.Value, error) {
+	if l == nil {
+		l = AllocationLines{}
+	}
+	b, err := json.Marshal([]AllocationLine(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (l *AllocationLines) Scan(value any) error {
+	if l == nil {
+		return problem.InternalError().WithError(errors.New("AllocationLines scan into nil receiver"))
+	}
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*l = AllocationLines{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return problem.InternalError().WithError(fmt.Errorf("AllocationLines: unsupported scan type %T", value))
+	}
+	var out []AllocationLine
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return err
+	}
+	*l = AllocationLines(out)
+	return nil
+}
+
+// ExpenseAllocationRule is a saved split of indirect costs (e.g. "ad spend: 60% shirts, 40% hats")
+// that can be applied to any expense of the business.
+type ExpenseAllocationRule struct {
+	gorm.Model
+	ID         string               `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string               `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	Business   *business.Business   `gorm:"foreignKey:BusinessID;references:ID" json:"business,omitempty"`
+	Name       string               `gorm:"column:name;type:text;not null" json:"name"`
+	TargetType AllocationTargetType `gorm:"column:target_type;type:text;not null" json:"targetType"`
+	Method     AllocationMethod     `gorm:"column:method;type:text;not null" json:"method"`
+	Lines      AllocationLines      `gorm:"column:lines;type:jsonb;not null;default:'[]'" json:"lines"`
+}
+
+func (m *ExpenseAllocationRule) TableName() string {
+	return ExpenseAllocationRuleTable
+}
+
+func (m *ExpenseAllocationRule) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(ExpenseAllocationRulePrefix)
+	}
+	return
+}
+
+var ExpenseAllocationRuleSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	Name       schema.Field
+	TargetType schema.Field
+	Method     schema.Field
+	Lines      schema.Field
+	CreatedAt  schema.Field
+	UpdatedAt  schema.Field
+	DeletedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	Name:       schema.NewField("name", "name"),
+	TargetType: schema.NewField("target_type", "targetType"),
+	Method:     schema.NewField("method", "method"),
+	Lines:      schema.NewField("lines", "lines"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+	UpdatedAt:  schema.NewField("updated_at", "updatedAt"),
+	DeletedAt:  schema.NewField("deleted_at", "deletedAt"),
+}
+
+// ExpenseAllocation is the part of an expense charged to one order or product.
+// Allocations of an expense never exceed its amount; the rest stays unallocated overhead.
+type ExpenseAllocation struct {
+	gorm.Model
+	ID         string                 `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string                 `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	ExpenseID  string                 `gorm:"column:expense_id;type:text;not null;index" json:"expenseId"`
+	Expense    *Expense               `gorm:"foreignKey:ExpenseID;references:ID;constraint:OnDelete:CASCADE;" json:"expense,omitempty"`
+	RuleID     sql.NullString         `gorm:"column:rule_id;type:text;index" json:"ruleId"`
+	Rule       *ExpenseAllocationRule `gorm:"foreignKey:RuleID;references:ID;constraint:OnDelete:SET NULL;" json:"rule,omitempty"`
+	TargetType AllocationTargetType   `gorm:"column:target_type;type:text;not null;index:idx_expense_allocations_target" json:"targetType"`
+	TargetID   string                 `gorm:"column:target_id;type:text;not null;index:idx_expense_allocations_target" json:"targetId"`
+	Amount     decimal.Decimal        `gorm:"column:amount;type:numeric;not null" json:"amount"`
+	Currency   string                 `gorm:"column:currency;type:text;not null;default:'USD'" json:"currency"`
+}
+
+func (m *ExpenseAllocation) TableName() string {
+	return ExpenseAllocationTable
+}
+
+func (m *ExpenseAllocation) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(ExpenseAllocationPrefix)
+	}
+	return
+}
+
+var ExpenseAllocationSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	ExpenseID  schema.Field
+	RuleID     schema.Field
+	TargetType schema.Field
+	TargetID   schema.Field
+	Amount     schema.Field
+	Currency   schema.Field
+	CreatedAt  schema.Field
+	UpdatedAt  schema.Field
+	DeletedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	ExpenseID:  schema.NewField("expense_id", "expenseId"),
+	RuleID:     schema.NewField("rule_id", "ruleId"),
+	TargetType: schema.NewField("target_type", "targetType"),
+	TargetID:   schema.NewField("target_id", "targetId"),
+	Amount:     schema.NewField("amount", "amount"),
+	Currency:   schema.NewField("currency", "currency"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+	UpdatedAt:  schema.NewField("updated_at", "updatedAt"),
+	DeletedAt:  schema.NewField("deleted_at", "deletedAt"),
+}
+
+/* Order Reversal Model */
+//--------------------------------*/
+
+const (
+	OrderReversalTable  = "order_reversals"
+	OrderReversalStruct = "OrderReversal"
+	OrderReversalPrefix = "orv"
+)
+
+// OrderReversal takes a returned or refunded order back out of the books: its revenue, its
+// COGS once the goods are back in stock, and its transaction fee once the payment is refunded.
+// There is one per order; a later return or refund of the same order only adds what is not
+// reversed yet.
+type OrderReversal struct {
+	gorm.Model
+	ID             string          `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID     string          `gorm:"column:business_id;type:text;not null;index;uniqueIndex:idx_order_reversal_business_order" json:"businessId"`
+	OrderID        string          `gorm:"column:order_id;type:text;not null;uniqueIndex:idx_order_reversal_business_order" json:"orderId"`
+	Revenue        decimal.Decimal `gorm:"column:revenue;type:numeric;not null;default:0" json:"revenue"`
+	COGS           decimal.Decimal `gorm:"column:cogs;type:numeric;not null;default:0" json:"cogs"`
+	TransactionFee decimal.Decimal `gorm:"column:transaction_fee;type:numeric;not null;default:0" json:"transactionFee"`
+	Currency       string          `gorm:"column:currency;type:text;not null;default:'USD'" json:"currency"`
+	Restocked      bool            `gorm:"column:restocked;not null;default:false" json:"restocked"`
+	Refunded       bool            `gorm:"column:refunded;not null;default:false" json:"refunded"`
+	OccurredOn     time.Time       `gorm:"column:occurred_on;type:date;not null;default:now()" json:"occurredOn"`
+}
+
+func (m *OrderReversal) TableName() string {
+	return OrderReversalTable
+}
+
+func (m *OrderReversal) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(OrderReversalPrefix)
+	}
+	return
+}
+
+var OrderReversalSchema = struct {
+	ID             schema.Field
+	BusinessID     schema.Field
+	OrderID        schema.Field
+	Revenue        schema.Field
+	COGS           schema.Field
+	TransactionFee schema.Field
+	Currency       schema.Field
+	Restocked      schema.Field
+	Refunded       schema.Field
+	OccurredOn     schema.Field
+	CreatedAt      schema.Field
+	UpdatedAt      schema.Field
+	DeletedAt      schema.Field
+}{
+	ID:             schema.NewField("id", "id"),
+	BusinessID:     schema.NewField("business_id", "businessId"),
+	OrderID:        schema.NewField("order_id", "orderId"),
+	Revenue:        schema.NewField("revenue", "revenue"),
+	COGS:           schema.NewField("cogs", "cogs"),
+	TransactionFee: schema.NewField("transaction_fee", "transactionFee"),
+	Currency:       schema.NewField("currency", "currency"),
+	Restocked:      schema.NewField("restocked", "restocked"),
+	Refunded:       schema.NewField("refunded", "refunded"),
+	OccurredOn:     schema.NewField("occurred_on", "occurredOn"),
+	CreatedAt:      schema.NewField("created_at", "createdAt"),
+	UpdatedAt:      schema.NewField("updated_at", "updatedAt"),
+	DeletedAt:      schema.NewField("deleted_at", "deletedAt"),
+}
+
+// OrderReversalTotals adds up the order reversals of a period.
+type OrderReversalTotals struct {
+	Revenue         decimal.Decimal
+	COGS            decimal.Decimal
+	TransactionFees decimal.Decimal
+}
@@ -0,0 +1,123 @@
+package accounting
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"gorm.io/gorm"
+)
+
+// AuditRecordType is the kind of accounting record an AuditEvent belongs to.
+type AuditRecordType string
+
+const (
+	AuditRecordAsset      AuditRecordType = "asset"
+	AuditRecordExpense    AuditRecordType = "expense"
+	AuditRecordInvestment AuditRecordType = "investment"
+	AuditRecordWithdrawal AuditRecordType = "withdrawal"
+)
+
+// AuditEventType is the kind of mutation an AuditEvent records.
+type AuditEventType string
+
+const (
+	AuditEventCreated AuditEventType = "created"
+	AuditEventUpdated AuditEventType = "updated"
+	AuditEventDeleted AuditEventType = "deleted"
+)
+
+// AuditFieldChange is one field of a record changed by an event. From is omitted on
+// creation and To on deletion.
+type AuditFieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from,omitempty"`
+	To    any    `json:"to,omitempty"`
+}
+
+// AuditFieldChanges is the diff stored on an AuditEvent.
+type AuditFieldChanges []AuditFieldChange
+
+func (c AuditFieldChanges) Value() (driver.Value, error) {
+	if c == nil {
+		c = AuditFieldChanges{}
+	}
+	b, err := json.Marshal([]AuditFieldChange(c))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (c *AuditFieldChanges) Scan(value any) error {
+	if c == nil {
+		return problem.InternalError().WithError(errors.New("AuditFieldChanges scan into nil receiver"))
+	}
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*c = AuditFieldChanges{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return problem.InternalError().WithError(errors.New("unsupported AuditFieldChanges scan type"))
+	}
+	return json.Unmarshal(raw, (*[]AuditFieldChange)(c))
+}
+
+const (
+	AuditEventTable  = "accounting_audit_events"
+	AuditEventStruct = "AuditEvent"
+	AuditEventPrefix = "aud"
+)
+
+// AuditEvent is an immutable entry of the change history of an asset, expense, investment
+// or withdrawal: what changed, when and by whom. ActorID is empty for changes made by
+// background jobs. Events are never updated and are kept when the record is deleted.
+type AuditEvent struct {
+	ID         string            `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string            `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	RecordType AuditRecordType   `gorm:"column:record_type;type:text;not null;index:idx_accounting_audit_events_record" json:"recordType"`
+	RecordID   string            `gorm:"column:record_id;type:text;not null;index:idx_accounting_audit_events_record" json:"recordId"`
+	Type       AuditEventType    `gorm:"column:type;type:text;not null" json:"type"`
+	ActorID    sql.NullString    `gorm:"column:actor_id;type:text" json:"actorId"`
+	Changes    AuditFieldChanges `gorm:"column:changes;type:jsonb;not null;default:'[]'" json:"changes"`
+	CreatedAt  time.Time         `gorm:"column:created_at;type:timestamptz;autoCreateTime;index:idx_accounting_audit_events_record" json:"createdAt"`
+}
+
+func (m *AuditEvent) TableName() string {
+	return AuditEventTable
+}
+
+func (m *AuditEvent) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(AuditEventPrefix)
+	}
+	return
+}
+
+var AuditEventSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	RecordType schema.Field
+	RecordID   schema.Field
+	Type       schema.Field
+	ActorID    schema.Field
+	CreatedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	RecordType: schema.NewField("record_type", "recordType"),
+	RecordID:   schema.NewField("record_id", "recordId"),
+	Type:       schema.NewField("type", "type"),
+	ActorID:    schema.NewField("actor_id", "actorId"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+}
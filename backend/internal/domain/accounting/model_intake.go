@@ -0,0 +1,152 @@
+package accounting
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+/* Expense Draft Model */
+//---------------------*/
+
+const (
+	ExpenseDraftTable  = "expense_drafts"
+	ExpenseDraftStruct = "ExpenseDraft"
+	ExpenseDraftPrefix = "exd"
+)
+
+type ExpenseDraftStatus string
+
+const (
+	ExpenseDraftStatusPending   ExpenseDraftStatus = "pending"
+	ExpenseDraftStatusConfirmed ExpenseDraftStatus = "confirmed"
+	ExpenseDraftStatusDiscarded ExpenseDraftStatus = "discarded"
+)
+
+type ExpenseDraftSource string
+
+const (
+	ExpenseDraftSourceUpload ExpenseDraftSource = "upload"
+	ExpenseDraftSourceEmail  ExpenseDraftSource = "email"
+)
+
+// ExpenseDraft is a receipt waiting for review. Intake stores the receipt and prefills the
+// draft with what OCR could read off it; confirming the draft records the expense.
+type ExpenseDraft struct {
+	gorm.Model
+	ID              string             `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID      string             `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	Status          ExpenseDraftStatus `gorm:"column:status;type:text;not null;default:'pending';index" json:"status"`
+	Source          ExpenseDraftSource `gorm:"column:source;type:text;not null" json:"source"`
+	SenderEmail     string             `gorm:"column:sender_email;type:text" json:"senderEmail,omitempty"`
+	ReceiptAssetID  string             `gorm:"column:receipt_asset_id;type:text;not null" json:"receiptAssetId"`
+	ReceiptURL      string             `gorm:"column:receipt_url;type:text" json:"receiptUrl"`
+	Amount          decimal.Decimal    `gorm:"column:amount;type:numeric;not null;default:0" json:"amount"`
+	Currency        string             `gorm:"column:currency;type:text;not null" json:"currency"`
+	OccurredOn      sql.NullTime       `gorm:"column:occurred_on;type:date" json:"occurredOn"`
+	Vendor          string             `gorm:"column:vendor;type:text" json:"vendor"`
+	Category        ExpenseCategory    `gorm:"column:category;type:text;not null" json:"category"`
+	Note            sql.NullString     `gorm:"column:note;type:text" json:"note"`
+	RawText         string             `gorm:"column:raw_text;type:text" json:"rawText"`
+	ExtractionError string             `gorm:"column:extraction_error;type:text" json:"extractionError,omitempty"`
+	ExpenseID       sql.NullString     `gorm:"column:expense_id;type:text;index" json:"expenseId"`
+}
+
+func (m *ExpenseDraft) TableName() string {
+	return ExpenseDraftTable
+}
+
+func (m *ExpenseDraft) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(ExpenseDraftPrefix)
+	}
+	return
+}
+
+var ExpenseDraftSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	Status     schema.Field
+	Source     schema.Field
+	Amount     schema.Field
+	OccurredOn schema.Field
+	Vendor     schema.Field
+	Category   schema.Field
+	CreatedAt  schema.Field
+	UpdatedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	Status:     schema.NewField("status", "status"),
+	Source:     schema.NewField("source", "source"),
+	Amount:     schema.NewField("amount", "amount"),
+	OccurredOn: schema.NewField("occurred_on", "occurredOn"),
+	Vendor:     schema.NewField("vendor", "vendor"),
+	Category:   schema.NewField("category", "category"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+	UpdatedAt:  schema.NewField("updated_at", "updatedAt"),
+}
+
+// ExpenseScan is what OCR read off a receipt that was scanned without being stored. Expense
+// is prefilled for review and can be sent to POST /expenses as is; Currency is the one printed
+// on the receipt, while expenses are recorded in the business currency.
+type ExpenseScan struct {
+	Expense  CreateExpenseRequest `json:"expense"`
+	Vendor   string               `json:"vendor"`
+	Currency string               `json:"currency"`
+	RawText  string               `json:"rawText,omitempty"`
+}
+
+/* Expense Inbox Model */
+//---------------------*/
+
+const (
+	ExpenseInboxTable  = "expense_inboxes"
+	ExpenseInboxStruct = "ExpenseInbox"
+	ExpenseInboxPrefix = "exi"
+	// ExpenseInboxLocalPart prefixes the token in inbox addresses: expenses+<token>@<domain>.
+	ExpenseInboxLocalPart = "expenses"
+)
+
+// ExpenseInbox is the optional email address of a business that turns emailed receipts
+// into expense drafts. Receipts are attributed to the member who enabled the inbox.
+type ExpenseInbox struct {
+	gorm.Model
+	ID              string `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID      string `gorm:"column:business_id;type:text;not null;uniqueIndex" json:"businessId"`
+	Token           string `gorm:"column:token;type:text;not null;uniqueIndex" json:"-"`
+	CreatedByUserID string `gorm:"column:created_by_user_id;type:text;not null" json:"createdByUserId"`
+}
+
+func (m *ExpenseInbox) TableName() string {
+	return ExpenseInboxTable
+}
+
+func (m *ExpenseInbox) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(ExpenseInboxPrefix)
+	}
+	return
+}
+
+// Address returns the email address receipts are forwarded to.
+func (m *ExpenseInbox) Address() string {
+	return fmt.Sprintf("%s+%s@%s", ExpenseInboxLocalPart, m.Token, strings.TrimSpace(viper.GetString(config.AccountingExpenseInboxDomain)))
+}
+
+var ExpenseInboxSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	Token      schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	Token:      schema.NewField("token", "token"),
+}
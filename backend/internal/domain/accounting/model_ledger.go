@@ -0,0 +1,253 @@
+package accounting
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+/* Ledger Models */
+//--------------------------------*/
+
+const (
+	LedgerTable              = "ledgers"
+	LedgerPrefix             = "ldg"
+	LedgerAccountTable       = "ledger_accounts"
+	LedgerAccountPrefix      = "lac"
+	JournalEntryTable        = "journal_entries"
+	JournalEntryPrefix       = "jre"
+	JournalEntryLinesStruct  = "Lines"
+	JournalLineTable         = "journal_lines"
+	JournalLinePrefix        = "jrl"
+	JournalLineAccountStruct = "Lines.Account"
+)
+
+// LedgerAccountType is the section of the chart of accounts an account belongs to.
+type LedgerAccountType string
+
+const (
+	LedgerAccountTypeAsset     LedgerAccountType = "asset"
+	LedgerAccountTypeLiability LedgerAccountType = "liability"
+	LedgerAccountTypeEquity    LedgerAccountType = "equity"
+	LedgerAccountTypeIncome    LedgerAccountType = "income"
+	LedgerAccountTypeExpense   LedgerAccountType = "expense"
+)
+
+// DebitNormal reports whether debits increase accounts of this type (assets and expenses).
+func (t LedgerAccountType) DebitNormal() bool {
+	return t == LedgerAccountTypeAsset || t == LedgerAccountTypeExpense
+}
+
+// LedgerAccountCode identifies an account of the chart of accounts within a business.
+type LedgerAccountCode string
+
+const (
+	LedgerAccountCash               LedgerAccountCode = "1000"
+	LedgerAccountReceivable         LedgerAccountCode = "1100"
+	LedgerAccountInventory          LedgerAccountCode = "1200"
+	LedgerAccountVATPayable         LedgerAccountCode = "2000"
+	LedgerAccountOwnerCapital       LedgerAccountCode = "3000"
+	LedgerAccountOwnerDrawings      LedgerAccountCode = "3100"
+	LedgerAccountSales              LedgerAccountCode = "4000"
+	LedgerAccountCOGS               LedgerAccountCode = "5000"
+	LedgerAccountOperatingExpenses  LedgerAccountCode = "6000"
+	LedgerAccountTransactionFeeCost LedgerAccountCode = "6100"
+)
+
+// LedgerAccountDefinition describes one account of the default chart of accounts.
+type LedgerAccountDefinition struct {
+	Code LedgerAccountCode
+	Name string
+	Type LedgerAccountType
+}
+
+// DefaultChartOfAccounts is the chart every business ledger is opened with. Automatic
+// postings only use these accounts.
+var DefaultChartOfAccounts = []LedgerAccountDefinition{
+	{Code: LedgerAccountCash, Name: "Cash", Type: LedgerAccountTypeAsset},
+	{Code: LedgerAccountReceivable, Name: "Accounts receivable", Type: LedgerAccountTypeAsset},
+	{Code: LedgerAccountInventory, Name: "Inventory", Type: LedgerAccountTypeAsset},
+	{Code: LedgerAccountVATPayable, Name: "VAT payable", Type: LedgerAccountTypeLiability},
+	{Code: LedgerAccountOwnerCapital, Name: "Owner capital", Type: LedgerAccountTypeEquity},
+	{Code: LedgerAccountOwnerDrawings, Name: "Owner drawings", Type: LedgerAccountTypeEquity},
+	{Code: LedgerAccountSales, Name: "Sales", Type: LedgerAccountTypeIncome},
+	{Code: LedgerAccountCOGS, Name: "Cost of goods sold", Type: LedgerAccountTypeExpense},
+	{Code: LedgerAccountOperatingExpenses, Name: "Operating expenses", Type: LedgerAccountTypeExpense},
+	{Code: LedgerAccountTransactionFeeCost, Name: "Transaction fees", Type: LedgerAccountTypeExpense},
+}
+
+// Ledger is the book of a business. It is opened on first use, when the chart of accounts is
+// created and the existing orders, expenses, investments, withdrawals and order reversals are
+// posted; BackfilledAt records when that happened.
+type Ledger struct {
+	gorm.Model
+	ID           string       `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID   string       `gorm:"column:business_id;type:text;not null;uniqueIndex" json:"businessId"`
+	BackfilledAt sql.NullTime `gorm:"column:backfilled_at;type:timestamptz" json:"backfilledAt"`
+}
+
+func (m *Ledger) TableName() string {
+	return LedgerTable
+}
+
+func (m *Ledger) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(LedgerPrefix)
+	}
+	return
+}
+
+var LedgerSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+}
+
+// LedgerAccount is an account of a business' chart of accounts.
+type LedgerAccount struct {
+	gorm.Model
+	ID         string            `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string            `gorm:"column:business_id;type:text;not null;uniqueIndex:idx_ledger_account_business_code" json:"businessId"`
+	Code       LedgerAccountCode `gorm:"column:code;type:text;not null;uniqueIndex:idx_ledger_account_business_code" json:"code"`
+	Name       string            `gorm:"column:name;type:text;not null" json:"name"`
+	Type       LedgerAccountType `gorm:"column:type;type:text;not null" json:"type"`
+}
+
+func (m *LedgerAccount) TableName() string {
+	return LedgerAccountTable
+}
+
+func (m *LedgerAccount) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(LedgerAccountPrefix)
+	}
+	return
+}
+
+var LedgerAccountSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	Code       schema.Field
+	Name       schema.Field
+	Type       schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	Code:       schema.NewField("code", "code"),
+	Name:       schema.NewField("name", "name"),
+	Type:       schema.NewField("type", "type"),
+}
+
+// JournalSourceType is the kind of record a journal entry was posted from.
+type JournalSourceType string
+
+const (
+	// JournalSourceOrderSale recognizes an order's revenue and COGS once it is fulfilled.
+	JournalSourceOrderSale JournalSourceType = "order_sale"
+	// JournalSourceOrderPayment records the customer paying an order.
+	JournalSourceOrderPayment JournalSourceType = "order_payment"
+	// JournalSourceOrderReversal takes a returned or refunded order back out of the books.
+	JournalSourceOrderReversal JournalSourceType = "order_reversal"
+	JournalSourceExpense       JournalSourceType = "expense"
+	JournalSourceInvestment    JournalSourceType = "investment"
+	JournalSourceWithdrawal    JournalSourceType = "withdrawal"
+)
+
+// JournalEntry is a balanced set of debits and credits posted from one source record. There
+// is at most one entry per source: reposting a source replaces its entry.
+type JournalEntry struct {
+	gorm.Model
+	ID          string            `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID  string            `gorm:"column:business_id;type:text;not null;index;uniqueIndex:idx_journal_entry_business_source" json:"businessId"`
+	SourceType  JournalSourceType `gorm:"column:source_type;type:text;not null;uniqueIndex:idx_journal_entry_business_source" json:"sourceType"`
+	SourceID    string            `gorm:"column:source_id;type:text;not null;uniqueIndex:idx_journal_entry_business_source" json:"sourceId"`
+	Description string            `gorm:"column:description;type:text;not null" json:"description"`
+	Currency    string            `gorm:"column:currency;type:text;not null;default:'USD'" json:"currency"`
+	PostedAt    time.Time         `gorm:"column:posted_at;type:timestamptz;not null;index" json:"postedAt"`
+	Lines       []*JournalLine    `gorm:"foreignKey:EntryID;references:ID" json:"lines,omitempty"`
+}
+
+func (m *JournalEntry) TableName() string {
+	return JournalEntryTable
+}
+
+func (m *JournalEntry) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(JournalEntryPrefix)
+	}
+	return
+}
+
+var JournalEntrySchema = struct {
+	ID          schema.Field
+	BusinessID  schema.Field
+	SourceType  schema.Field
+	SourceID    schema.Field
+	Description schema.Field
+	Currency    schema.Field
+	PostedAt    schema.Field
+	CreatedAt   schema.Field
+}{
+	ID:          schema.NewField("id", "id"),
+	BusinessID:  schema.NewField("business_id", "businessId"),
+	SourceType:  schema.NewField("source_type", "sourceType"),
+	SourceID:    schema.NewField("source_id", "sourceId"),
+	Description: schema.NewField("description", "description"),
+	Currency:    schema.NewField("currency", "currency"),
+	PostedAt:    schema.NewField("posted_at", "postedAt"),
+	CreatedAt:   schema.NewField("created_at", "createdAt"),
+}
+
+// JournalLine debits or credits one account; exactly one of Debit and Credit is positive.
+type JournalLine struct {
+	gorm.Model
+	ID         string          `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string          `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	EntryID    string          `gorm:"column:entry_id;type:text;not null;index" json:"entryId"`
+	AccountID  string          `gorm:"column:account_id;type:text;not null;index" json:"accountId"`
+	Account    *LedgerAccount  `gorm:"foreignKey:AccountID;references:ID" json:"account,omitempty"`
+	Debit      decimal.Decimal `gorm:"column:debit;type:numeric;not null;default:0" json:"debit"`
+	Credit     decimal.Decimal `gorm:"column:credit;type:numeric;not null;default:0" json:"credit"`
+}
+
+func (m *JournalLine) TableName() string {
+	return JournalLineTable
+}
+
+func (m *JournalLine) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(JournalLinePrefix)
+	}
+	return
+}
+
+// LedgerAccountBalance is the activity of one account over a period.
+type LedgerAccountBalance struct {
+	Account *LedgerAccount
+	Debit   decimal.Decimal
+	Credit  decimal.Decimal
+}
+
+// Balance is the account's balance on its normal side: debits minus credits for assets and
+// expenses, credits minus debits otherwise.
+func (b *LedgerAccountBalance) Balance() decimal.Decimal {
+	if b.Account.Type.DebitNormal() {
+		return b.Debit.Sub(b.Credit)
+	}
+	return b.Credit.Sub(b.Debit)
+}
+
+// TradingTotals is what a period's trading booked to the ledger: revenue is sales net of VAT
+// and reversals, COGS the cost of the goods fulfilled orders shipped out.
+type TradingTotals struct {
+	Revenue     decimal.Decimal
+	COGS        decimal.Decimal
+	GrossProfit decimal.Decimal
+	NetProfit   decimal.Decimal
+}
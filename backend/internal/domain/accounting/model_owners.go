@@ -0,0 +1,83 @@
+package accounting
+
+import (
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+/* Cap Table Models */
+//--------------------------------*/
+
+const (
+	OwnerShareTable  = "owner_shares"
+	OwnerSharePrefix = "osh"
+)
+
+// OwnerShare is the percentage of a business an owner holds. The shares of a business make up
+// its cap table; they add up to at most 100, the rest being unassigned.
+type OwnerShare struct {
+	gorm.Model
+	ID         string          `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string          `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	OwnerID    string          `gorm:"column:owner_id;type:text;not null;index" json:"ownerId"`
+	Owner      *account.User   `gorm:"foreignKey:OwnerID;references:ID" json:"owner,omitempty"`
+	Percentage decimal.Decimal `gorm:"column:percentage;type:numeric;not null" json:"percentage"`
+}
+
+func (m *OwnerShare) TableName() string {
+	return OwnerShareTable
+}
+
+func (m *OwnerShare) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(OwnerSharePrefix)
+	}
+	return
+}
+
+var OwnerShareSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	OwnerID    schema.Field
+	Percentage schema.Field
+	CreatedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	OwnerID:    schema.NewField("owner_id", "ownerId"),
+	Percentage: schema.NewField("percentage", "percentage"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+}
+
+// CapTableEntry is an owner's share with what they put into and took out of the business.
+type CapTableEntry struct {
+	Share     *OwnerShare
+	Invested  decimal.Decimal
+	Withdrawn decimal.Decimal
+}
+
+// ProfitDistribution proposes how much of a period's net profit each owner may withdraw.
+// Distributable is Percent of the net profit (nothing when the period made a loss); each owner
+// is entitled to their share of it, less what they already withdrew in the period.
+type ProfitDistribution struct {
+	From          time.Time
+	To            time.Time
+	NetProfit     decimal.Decimal
+	Percent       decimal.Decimal
+	Distributable decimal.Decimal
+	Owners        []*OwnerDistribution
+}
+
+// OwnerDistribution is one owner's part of a profit distribution.
+type OwnerDistribution struct {
+	OwnerID     string
+	Percentage  decimal.Decimal
+	Entitlement decimal.Decimal
+	Withdrawn   decimal.Decimal
+	Proposed    decimal.Decimal
+}
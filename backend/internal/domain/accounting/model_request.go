@@ -11,7 +11,7 @@ import (
 type CreateAssetRequest struct {
 	Name        string          `form:"name" json:"name" binding:"required"`
 	Type        AssetType       `form:"type" json:"type" binding:"required"`
-	Value       decimal.Decimal `form:"value" json:"value" binding:"required"`
+	Value       decimal.Decimal `form:"value" json:"value" binding:"required,dgt=0"`
 	PurchasedAt time.Time       `form:"purchasedAt" json:"purchasedAt" binding:"omitempty"`
 	Note        string          `form:"note" json:"note" binding:"omitempty"`
 }
@@ -20,7 +20,7 @@ type CreateAssetRequest struct {
 type UpdateAssetRequest struct {
 	Name        string          `form:"name" json:"name" binding:"omitempty"`
 	Type        AssetType       `form:"type" json:"type" binding:"omitempty"`
-	Value       decimal.Decimal `form:"value" json:"value" binding:"omitempty"`
+	Value       decimal.Decimal `form:"value" json:"value" binding:"omitempty,dgt=0"`
 	PurchasedAt time.Time       `form:"purchasedAt" json:"purchasedAt" binding:"omitempty"`
 	Note        string          `form:"note" json:"note" binding:"omitempty"`
 }
@@ -28,7 +28,7 @@ type UpdateAssetRequest struct {
 // CreateInvestmentRequest is the request DTO for creating an investment.
 type CreateInvestmentRequest struct {
 	InvestorID string          `form:"investorId" json:"investorId" binding:"required"`
-	Amount     decimal.Decimal `form:"amount" json:"amount" binding:"required"`
+	Amount     decimal.Decimal `form:"amount" json:"amount" binding:"required,dgt=0"`
 	Note       string          `form:"note" json:"note" binding:"omitempty"`
 	InvestedAt time.Time       `form:"investedAt" json:"investedAt" binding:"omitempty"`
 }
@@ -36,22 +36,25 @@ type CreateInvestmentRequest struct {
 // UpdateInvestmentRequest is the request DTO for updating an investment.
 type UpdateInvestmentRequest struct {
 	InvestorID string          `form:"investorId" json:"investorId" binding:"omitempty"`
-	Amount     decimal.Decimal `form:"amount" json:"amount" binding:"omitempty"`
+	Amount     decimal.Decimal `form:"amount" json:"amount" binding:"omitempty,dgt=0"`
 	Note       string          `form:"note" json:"note" binding:"omitempty"`
 	InvestedAt time.Time       `form:"investedAt" json:"investedAt" binding:"omitempty"`
 }
 
 // CreateWithdrawalRequest is the request DTO for creating a withdrawal.
 type CreateWithdrawalRequest struct {
-	Amount       decimal.Decimal `form:"amount" json:"amount" binding:"required"`
+	Amount       decimal.Decimal `form:"amount" json:"amount" binding:"required,dgt=0"`
 	WithdrawerID string          `form:"withdrawerId" json:"withdrawerId" binding:"required"`
 	Note         string          `form:"note" json:"note" binding:"omitempty"`
 	WithdrawnAt  time.Time       `form:"withdrawnAt" json:"withdrawnAt" binding:"omitempty"`
+	// Override records the withdrawal even though it exceeds the safe-to-draw amount of a
+	// business that enforces it. Admins only.
+	Override bool `form:"override" json:"override" binding:"omitempty"`
 }
 
 // UpdateWithdrawalRequest is the request DTO for updating a withdrawal.
 type UpdateWithdrawalRequest struct {
-	Amount       decimal.Decimal `form:"amount" json:"amount" binding:"omitempty"`
+	Amount       decimal.Decimal `form:"amount" json:"amount" binding:"omitempty,dgt=0"`
 	WithdrawerID string          `form:"withdrawerId" json:"withdrawerId" binding:"omitempty"`
 	Note         string          `form:"note" json:"note" binding:"omitempty"`
 	WithdrawnAt  time.Time       `form:"withdrawnAt" json:"withdrawnAt" binding:"omitempty"`
@@ -59,22 +62,28 @@ type UpdateWithdrawalRequest struct {
 
 // CreateExpenseRequest is the request DTO for creating an expense.
 type CreateExpenseRequest struct {
-	Amount             decimal.Decimal `form:"amount" json:"amount" binding:"required"`
-	Category           ExpenseCategory `form:"category" json:"category" binding:"required"`
-	Type               ExpenseType     `form:"type" json:"type" binding:"required"`
-	RecurringExpenseID string          `form:"recurringExpenseId" json:"recurringExpenseId" binding:"omitempty,required_if=Type recurring"`
-	Note               string          `form:"note" json:"note" binding:"omitempty"`
-	OccurredOn         *date.Date      `form:"occurredOn" json:"occurredOn" binding:"omitempty"`
+	Amount             decimal.Decimal     `form:"amount" json:"amount" binding:"required,dgt=0"`
+	VAT                decimal.Decimal     `form:"vat" json:"vat" binding:"omitempty"`
+	Currency           string              `form:"currency" json:"currency" binding:"omitempty,len=3"`
+	ExchangeRate       decimal.NullDecimal `form:"exchangeRate" json:"exchangeRate" binding:"omitempty,dgt=0"`
+	Category           ExpenseCategory     `form:"category" json:"category" binding:"required"`
+	Type               ExpenseType         `form:"type" json:"type" binding:"required"`
+	RecurringExpenseID string              `form:"recurringExpenseId" json:"recurringExpenseId" binding:"omitempty,required_if=Type recurring"`
+	Note               string              `form:"note" json:"note" binding:"omitempty"`
+	OccurredOn         *date.Date          `form:"occurredOn" json:"occurredOn" binding:"omitempty"`
 }
 
 // UpdateExpenseRequest is the request DTO for updating an expense.
 type UpdateExpenseRequest struct {
-	Amount             decimal.Decimal `form:"amount" json:"amount" binding:"omitempty"`
-	Category           ExpenseCategory `form:"category" json:"category" binding:"omitempty"`
-	Type               ExpenseType     `form:"type" json:"type" binding:"omitempty"`
-	RecurringExpenseID string          `form:"recurringExpenseId" json:"recurringExpenseId" binding:"omitempty,required_if=Type recurring"`
-	Note               string          `form:"note" json:"note" binding:"omitempty"`
-	OccurredOn         *date.Date      `form:"occurredOn" json:"occurredOn" binding:"omitempty"`
+	Amount             decimal.Decimal     `form:"amount" json:"amount" binding:"omitempty,dgt=0"`
+	VAT                *decimal.Decimal    `form:"vat" json:"vat" binding:"omitempty"`
+	Currency           string              `form:"currency" json:"currency" binding:"omitempty,len=3"`
+	ExchangeRate       decimal.NullDecimal `form:"exchangeRate" json:"exchangeRate" binding:"omitempty,dgt=0"`
+	Category           ExpenseCategory     `form:"category" json:"category" binding:"omitempty"`
+	Type               ExpenseType         `form:"type" json:"type" binding:"omitempty"`
+	RecurringExpenseID string              `form:"recurringExpenseId" json:"recurringExpenseId" binding:"omitempty,required_if=Type recurring"`
+	Note               string              `form:"note" json:"note" binding:"omitempty"`
+	OccurredOn         *date.Date          `form:"occurredOn" json:"occurredOn" binding:"omitempty"`
 }
 
 // CreateRecurringExpenseRequest is the request DTO for creating a recurring expense.
@@ -82,7 +91,7 @@ type CreateRecurringExpenseRequest struct {
 	Frequency                    RecurringExpenseFrequency `form:"frequency" json:"frequency" binding:"required,oneof=daily weekly monthly yearly"`
 	RecurringEndDate             *date.Date                `form:"recurringEndDate" json:"recurringEndDate" binding:"omitempty"`
 	RecurringStartDate           date.Date                 `form:"recurringStartDate" json:"recurringStartDate" binding:"required"`
-	Amount                       decimal.Decimal           `form:"amount" json:"amount" binding:"required"`
+	Amount                       decimal.Decimal           `form:"amount" json:"amount" binding:"required,dgt=0"`
 	Category                     ExpenseCategory           `form:"category" json:"category" binding:"required,oneof=office travel supplies utilities payroll marketing rent software maintenance insurance taxes training consulting miscellaneous legal research equipment shipping transaction_fee other"`
 	Note                         string                    `form:"note" json:"note" binding:"omitempty"`
 	AutoCreateHistoricalExpenses bool                      `form:"autoCreateHistoricalExpenses" json:"autoCreateHistoricalExpenses" binding:"omitempty"`
@@ -93,11 +102,80 @@ type UpdateRecurringExpenseRequest struct {
 	Frequency          RecurringExpenseFrequency `form:"frequency" json:"frequency" binding:"omitempty,oneof=daily weekly monthly yearly"`
 	RecurringEndDate   *date.Date                `form:"recurringEndDate" json:"recurringEndDate" binding:"omitempty"`
 	RecurringStartDate *date.Date                `form:"recurringStartDate" json:"recurringStartDate" binding:"omitempty"`
-	Amount             decimal.Decimal           `form:"amount" json:"amount" binding:"omitempty"`
+	Amount             decimal.Decimal           `form:"amount" json:"amount" binding:"omitempty,dgt=0"`
 	Category           ExpenseCategory           `form:"category" json:"category" binding:"omitempty"`
 	Note               string                    `form:"note" json:"note" binding:"omitempty"`
 }
 
+// CreateExpenseAllocationRuleRequest is the request DTO for saving a reusable allocation rule.
+type CreateExpenseAllocationRuleRequest struct {
+	Name       string               `json:"name" binding:"required"`
+	TargetType AllocationTargetType `json:"targetType" binding:"required,oneof=order product"`
+	Method     AllocationMethod     `json:"method" binding:"required,oneof=percentage amount"`
+	Lines      []AllocationLine     `json:"lines" binding:"required,min=1,dive"`
+}
+
+// UpdateExpenseAllocationRuleRequest is the request DTO for updating an allocation rule.
+// Lines, when provided, replace the existing lines and are validated against the rule's method.
+type UpdateExpenseAllocationRuleRequest struct {
+	Name       string               `json:"name" binding:"omitempty"`
+	TargetType AllocationTargetType `json:"targetType" binding:"omitempty,oneof=order product"`
+	Method     AllocationMethod     `json:"method" binding:"omitempty,oneof=percentage amount"`
+	Lines      []AllocationLine     `json:"lines" binding:"omitempty,min=1,dive"`
+}
+
+// AllocateExpenseRequest is the request DTO for allocating an expense.
+// Either reference a saved rule with ruleId, or spell out targetType, method and lines.
+// Allocating replaces any previous allocation of the expense.
+type AllocateExpenseRequest struct {
+	RuleID     string               `json:"ruleId" binding:"omitempty"`
+	TargetType AllocationTargetType `json:"targetType" binding:"required_without=RuleID,omitempty,oneof=order product"`
+	Method     AllocationMethod     `json:"method" binding:"required_without=RuleID,omitempty,oneof=percentage amount"`
+	Lines      []AllocationLine     `json:"lines" binding:"required_without=RuleID,omitempty,min=1,dive"`
+}
+
+// ExpenseSplitLine is one line of a split expense: its share of the expense, its category and,
+// optionally, another business of the workspace it is booked to.
+type ExpenseSplitLine struct {
+	Category   ExpenseCategory `json:"category" binding:"required"`
+	Percentage decimal.Decimal `json:"percentage" binding:"required,dgt=0"`
+	BusinessID string          `json:"businessId" binding:"omitempty"`
+}
+
+// SplitExpenseRequest splits an expense into lines whose percentages add up to 100. Splitting
+// a split expense again replaces all of its lines; a single line undoes the split.
+type SplitExpenseRequest struct {
+	Lines []ExpenseSplitLine `json:"lines" binding:"required,min=1,dive"`
+}
+
+// OwnerShareLine sets the percentage of the business one owner holds.
+type OwnerShareLine struct {
+	OwnerID    string          `json:"ownerId" binding:"required"`
+	Percentage decimal.Decimal `json:"percentage" binding:"required,dgt=0"`
+}
+
+// SetCapTableRequest is the request DTO for replacing the cap table of a business.
+// Percentages must add up to at most 100; an empty list clears the cap table.
+type SetCapTableRequest struct {
+	Owners []OwnerShareLine `json:"owners" binding:"omitempty,dive"`
+}
+
+// OwnerDistributionLine is an approved withdrawal of one owner in a profit distribution.
+type OwnerDistributionLine struct {
+	OwnerID string          `json:"ownerId" binding:"required"`
+	Amount  decimal.Decimal `json:"amount" binding:"required,dgt=0"`
+}
+
+// DistributeProfitRequest is the request DTO for recording an approved profit distribution.
+// Without owners the proposed amounts are recorded as they are.
+type DistributeProfitRequest struct {
+	From        date.Date               `json:"from" binding:"required"`
+	To          date.Date               `json:"to" binding:"required"`
+	Percent     decimal.NullDecimal     `json:"percent" binding:"omitempty"`
+	Owners      []OwnerDistributionLine `json:"owners" binding:"omitempty,dive"`
+	WithdrawnAt time.Time               `json:"withdrawnAt" binding:"omitempty"`
+}
+
 // Query and handler request types
 
 // listAssetsQuery represents the query parameters for listing assets.
@@ -117,11 +195,48 @@ type listExpensesQuery struct {
 	To       *time.Time      `form:"to" binding:"omitempty" time_format:"2006-01-02"`
 }
 
+// listExpenseAllocationRulesQuery represents the query parameters for listing allocation rules.
+type listExpenseAllocationRulesQuery struct {
+	Page     int      `form:"page" binding:"omitempty,min=1"`
+	PageSize int      `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	OrderBy  []string `form:"orderBy" binding:"omitempty"`
+}
+
 // updateRecurringExpenseStatusRequest represents the request to update recurring expense status.
 type updateRecurringExpenseStatusRequest struct {
 	Status RecurringExpenseStatus `json:"status" binding:"required,oneof=active paused ended canceled"`
 }
 
+// ledgerAccountsQuery represents the query parameters for the chart of accounts balances.
+type ledgerAccountsQuery struct {
+	From *time.Time `form:"from" binding:"omitempty" time_format:"2006-01-02"`
+	To   *time.Time `form:"to" binding:"omitempty" time_format:"2006-01-02"`
+}
+
+// listJournalEntriesQuery represents the query parameters for listing journal entries.
+type listJournalEntriesQuery struct {
+	Page       int               `form:"page" binding:"omitempty,min=1"`
+	PageSize   int               `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	OrderBy    []string          `form:"orderBy" binding:"omitempty"`
+	SourceType JournalSourceType `form:"sourceType" binding:"omitempty,oneof=order_sale order_payment order_reversal expense investment withdrawal"`
+	From       *time.Time        `form:"from" binding:"omitempty" time_format:"2006-01-02"`
+	To         *time.Time        `form:"to" binding:"omitempty" time_format:"2006-01-02"`
+}
+
+// exportJournalQuery represents the query parameters for exporting journal entries.
+type exportJournalQuery struct {
+	Format JournalExportFormat `form:"format" binding:"required,oneof=quickbooks xero"`
+	From   *time.Time          `form:"from" binding:"omitempty" time_format:"2006-01-02"`
+	To     *time.Time          `form:"to" binding:"omitempty" time_format:"2006-01-02"`
+}
+
+// profitDistributionQuery represents the query parameters for proposing a profit distribution.
+type profitDistributionQuery struct {
+	From    time.Time `form:"from" binding:"required" time_format:"2006-01-02"`
+	To      time.Time `form:"to" binding:"required" time_format:"2006-01-02"`
+	Percent *float64  `form:"percent" binding:"omitempty,gt=0,lte=100"`
+}
+
 // summaryQuery represents the query parameters for accounting summary.
 type summaryQuery struct {
 	From string `form:"from" binding:"omitempty"`
@@ -132,3 +247,36 @@ type summaryQuery struct {
 type recentActivitiesQuery struct {
 	Limit int `form:"limit" binding:"omitempty,min=1,max=50"`
 }
+
+// UpdateExpenseDraftRequest is the request DTO for correcting an expense draft before confirming it.
+type UpdateExpenseDraftRequest struct {
+	Amount     *decimal.Decimal `json:"amount" binding:"omitempty"`
+	Currency   string           `json:"currency" binding:"omitempty,len=3"`
+	Category   ExpenseCategory  `json:"category" binding:"omitempty"`
+	Vendor     *string          `json:"vendor" binding:"omitempty"`
+	Note       *string          `json:"note" binding:"omitempty"`
+	OccurredOn *date.Date       `json:"occurredOn" binding:"omitempty"`
+}
+
+// listExpenseDraftsQuery represents the query parameters for listing expense drafts.
+type listExpenseDraftsQuery struct {
+	Page     int                `form:"page" binding:"omitempty,min=1"`
+	PageSize int                `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	OrderBy  []string           `form:"orderBy" binding:"omitempty"`
+	Status   ExpenseDraftStatus `form:"status" binding:"omitempty,oneof=pending confirmed discarded"`
+}
+
+// InboundReceiptEmail is the payload of the expense inbox webhook: an email forwarded by the
+// inbound mail provider, with its attachments base64-encoded.
+type InboundReceiptEmail struct {
+	From        string                     `json:"from" binding:"omitempty"`
+	To          []string                   `json:"to" binding:"required,min=1"`
+	Attachments []InboundReceiptAttachment `json:"attachments" binding:"omitempty,dive"`
+}
+
+// InboundReceiptAttachment is one attachment of an InboundReceiptEmail.
+type InboundReceiptAttachment struct {
+	FileName    string `json:"fileName" binding:"required"`
+	ContentType string `json:"contentType" binding:"required"`
+	Content     string `json:"content" binding:"required"`
+}
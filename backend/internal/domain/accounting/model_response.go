@@ -96,15 +96,33 @@ func ToInvestmentResponses(investments []*Investment) []InvestmentResponse {
 // WithdrawalResponse is the API response for Withdrawal entity
 // No DeletedAt field (GORM leakage removed)
 type WithdrawalResponse struct {
-	ID           string          `json:"id"`
-	BusinessID   string          `json:"businessId"`
-	Amount       decimal.Decimal `json:"amount"`
-	Currency     string          `json:"currency"`
-	WithdrawerID string          `json:"withdrawerId"`
-	Note         string          `json:"note"`
-	WithdrawnAt  time.Time       `json:"withdrawnAt"`
-	CreatedAt    time.Time       `json:"createdAt"`
-	UpdatedAt    time.Time       `json:"updatedAt"`
+	ID              string          `json:"id"`
+	BusinessID      string          `json:"businessId"`
+	Amount          decimal.Decimal `json:"amount"`
+	Currency        string          `json:"currency"`
+	WithdrawerID    string          `json:"withdrawerId"`
+	Note            string          `json:"note"`
+	WithdrawnAt     time.Time       `json:"withdrawnAt"`
+	DistributedFrom *time.Time      `json:"distributedFrom,omitempty"`
+	DistributedTo   *time.Time      `json:"distributedTo,omitempty"`
+	CreatedAt       time.Time       `json:"createdAt"`
+	UpdatedAt       time.Time       `json:"updatedAt"`
+}
+
+// SafeToDrawWarning flags a withdrawal recorded above the safe-to-draw amount. Overridden is
+// set when the business enforces the limit and an admin overrode it.
+type SafeToDrawWarning struct {
+	Code             string          `json:"code"`
+	SafeToDrawAmount decimal.Decimal `json:"safeToDrawAmount"`
+	Excess           decimal.Decimal `json:"excess"`
+	Overridden       bool            `json:"overridden"`
+}
+
+// CreateWithdrawalResponse is the withdrawal created, with a warning when it exceeds the
+// safe-to-draw amount.
+type CreateWithdrawalResponse struct {
+	WithdrawalResponse
+	Warning *SafeToDrawWarning `json:"warning,omitempty"`
 }
 
 // ToWithdrawalResponse converts Withdrawal model to WithdrawalResponse
@@ -114,15 +132,17 @@ func ToWithdrawalResponse(w *Withdrawal) WithdrawalResponse {
 	}
 
 	return WithdrawalResponse{
-		ID:           w.ID,
-		BusinessID:   w.BusinessID,
-		Amount:       w.Amount,
-		Currency:     w.Currency,
-		WithdrawerID: w.WithdrawerID,
-		Note:         w.Note,
-		WithdrawnAt:  w.WithdrawnAt,
-		CreatedAt:    w.CreatedAt,
-		UpdatedAt:    w.UpdatedAt,
+		ID:              w.ID,
+		BusinessID:      w.BusinessID,
+		Amount:          w.Amount,
+		Currency:        w.Currency,
+		WithdrawerID:    w.WithdrawerID,
+		Note:            w.Note,
+		WithdrawnAt:     w.WithdrawnAt,
+		DistributedFrom: transformer.NullTimePtr(w.DistributedFrom),
+		DistributedTo:   transformer.NullTimePtr(w.DistributedTo),
+		CreatedAt:       w.CreatedAt,
+		UpdatedAt:       w.UpdatedAt,
 	}
 }
 
@@ -139,18 +159,25 @@ func ToWithdrawalResponses(withdrawals []*Withdrawal) []WithdrawalResponse {
 // No DeletedAt field (GORM leakage removed)
 // Optional fields use pointers (orderId, recurringExpenseId, note)
 type ExpenseResponse struct {
-	ID                 string          `json:"id"`
-	BusinessID         string          `json:"businessId"`
-	OrderID            *string         `json:"orderId,omitempty"`
-	RecurringExpenseID *string         `json:"recurringExpenseId,omitempty"`
-	Amount             decimal.Decimal `json:"amount"`
-	Currency           string          `json:"currency"`
-	OccurredOn         time.Time       `json:"occurredOn"`
-	Category           ExpenseCategory `json:"category"`
-	Type               ExpenseType     `json:"type"`
-	Note               *string         `json:"note,omitempty"`
-	CreatedAt          time.Time       `json:"createdAt"`
-	UpdatedAt          time.Time       `json:"updatedAt"`
+	ID                 string             `json:"id"`
+	BusinessID         string             `json:"businessId"`
+	OrderID            *string            `json:"orderId,omitempty"`
+	RecurringExpenseID *string            `json:"recurringExpenseId,omitempty"`
+	Amount             decimal.Decimal    `json:"amount"`
+	VAT                decimal.Decimal    `json:"vat"`
+	Currency           string             `json:"currency"`
+	OriginalAmount     *decimal.Decimal   `json:"originalAmount,omitempty"`
+	OriginalVAT        *decimal.Decimal   `json:"originalVat,omitempty"`
+	OriginalCurrency   *string            `json:"originalCurrency,omitempty"`
+	ExchangeRate       *decimal.Decimal   `json:"exchangeRate,omitempty"`
+	ExchangeRateSource ExchangeRateSource `json:"exchangeRateSource,omitempty"`
+	OccurredOn         time.Time          `json:"occurredOn"`
+	Category           ExpenseCategory    `json:"category"`
+	Type               ExpenseType        `json:"type"`
+	Note               *string            `json:"note,omitempty"`
+	SplitID            *string            `json:"splitId,omitempty"`
+	CreatedAt          time.Time          `json:"createdAt"`
+	UpdatedAt          time.Time          `json:"updatedAt"`
 }
 
 // ToExpenseResponse converts Expense model to ExpenseResponse
@@ -159,20 +186,34 @@ func ToExpenseResponse(exp *Expense) ExpenseResponse {
 		return ExpenseResponse{}
 	}
 
-	return ExpenseResponse{
+	resp := ExpenseResponse{
 		ID:                 exp.ID,
 		BusinessID:         exp.BusinessID,
 		OrderID:            transformer.NullStringPtr(exp.OrderID),
 		RecurringExpenseID: transformer.NullStringPtr(exp.RecurringExpenseID),
 		Amount:             exp.Amount,
+		VAT:                exp.VAT,
 		Currency:           exp.Currency,
+		OriginalCurrency:   transformer.NullStringPtr(exp.OriginalCurrency),
+		ExchangeRateSource: exp.ExchangeRateSource,
 		OccurredOn:         exp.OccurredOn,
 		Category:           exp.Category,
 		Type:               exp.Type,
 		Note:               transformer.NullStringPtr(exp.Note),
+		SplitID:            transformer.NullStringPtr(exp.SplitID),
 		CreatedAt:          exp.CreatedAt,
 		UpdatedAt:          exp.UpdatedAt,
 	}
+	if exp.OriginalAmount.Valid {
+		resp.OriginalAmount = &exp.OriginalAmount.Decimal
+	}
+	if exp.OriginalVAT.Valid {
+		resp.OriginalVAT = &exp.OriginalVAT.Decimal
+	}
+	if exp.ExchangeRate.Valid {
+		resp.ExchangeRate = &exp.ExchangeRate.Decimal
+	}
+	return resp
 }
 
 // ToExpenseResponses converts a slice of Expense models to responses
@@ -184,6 +225,127 @@ func ToExpenseResponses(expenses []*Expense) []ExpenseResponse {
 	return responses
 }
 
+// ExpenseAllocationRuleResponse is the API response for ExpenseAllocationRule entity
+type ExpenseAllocationRuleResponse struct {
+	ID         string               `json:"id"`
+	BusinessID string               `json:"businessId"`
+	Name       string               `json:"name"`
+	TargetType AllocationTargetType `json:"targetType"`
+	Method     AllocationMethod     `json:"method"`
+	Lines      []AllocationLine     `json:"lines"`
+	CreatedAt  time.Time            `json:"createdAt"`
+	UpdatedAt  time.Time            `json:"updatedAt"`
+}
+
+// ToExpenseAllocationRuleResponse converts ExpenseAllocationRule model to ExpenseAllocationRuleResponse
+func ToExpenseAllocationRuleResponse(rule *ExpenseAllocationRule) ExpenseAllocationRuleResponse {
+	if rule == nil {
+		return ExpenseAllocationRuleResponse{}
+	}
+	lines := []AllocationLine{}
+	if rule.Lines != nil {
+		lines = []AllocationLine(rule.Lines)
+	}
+
+	return ExpenseAllocationRuleResponse{
+		ID:         rule.ID,
+		BusinessID: rule.BusinessID,
+		Name:       rule.Name,
+		TargetType: rule.TargetType,
+		Method:     rule.Method,
+		Lines:      lines,
+		CreatedAt:  rule.CreatedAt,
+		UpdatedAt:  rule.UpdatedAt,
+	}
+}
+
+// ToExpenseAllocationRuleResponses converts a slice of ExpenseAllocationRule models to responses
+func ToExpenseAllocationRuleResponses(rules []*ExpenseAllocationRule) []ExpenseAllocationRuleResponse {
+	responses := make([]ExpenseAllocationRuleResponse, len(rules))
+	for i, rule := range rules {
+		responses[i] = ToExpenseAllocationRuleResponse(rule)
+	}
+	return responses
+}
+
+// ExpenseAllocationResponse is the API response for ExpenseAllocation entity
+type ExpenseAllocationResponse struct {
+	ID         string               `json:"id"`
+	ExpenseID  string               `json:"expenseId"`
+	RuleID     *string              `json:"ruleId,omitempty"`
+	TargetType AllocationTargetType `json:"targetType"`
+	TargetID   string               `json:"targetId"`
+	Amount     decimal.Decimal      `json:"amount"`
+	Currency   string               `json:"currency"`
+	CreatedAt  time.Time            `json:"createdAt"`
+}
+
+// ToExpenseAllocationResponses converts a slice of ExpenseAllocation models to responses
+func ToExpenseAllocationResponses(allocations []*ExpenseAllocation) []ExpenseAllocationResponse {
+	responses := make([]ExpenseAllocationResponse, len(allocations))
+	for i, a := range allocations {
+		responses[i] = ExpenseAllocationResponse{
+			ID:         a.ID,
+			ExpenseID:  a.ExpenseID,
+			RuleID:     transformer.NullStringPtr(a.RuleID),
+			TargetType: a.TargetType,
+			TargetID:   a.TargetID,
+			Amount:     a.Amount,
+			Currency:   a.Currency,
+			CreatedAt:  a.CreatedAt,
+		}
+	}
+	return responses
+}
+
+// ExpenseAllocationsResponse lists how an expense is split and how much of it is left unallocated.
+// ExpenseSplitResponse lists the lines an expense was split into and the amount they add up to
+type ExpenseSplitResponse struct {
+	SplitID  *string           `json:"splitId,omitempty"`
+	Amount   decimal.Decimal   `json:"amount"`
+	Currency string            `json:"currency"`
+	Lines    []ExpenseResponse `json:"lines"`
+}
+
+// ToExpenseSplitResponse builds the split of an expense from its lines
+func ToExpenseSplitResponse(lines []*Expense) ExpenseSplitResponse {
+	resp := ExpenseSplitResponse{Amount: decimal.Zero, Lines: make([]ExpenseResponse, len(lines))}
+	for i, line := range lines {
+		resp.Amount = resp.Amount.Add(line.Amount)
+		resp.Lines[i] = ToExpenseResponse(line)
+	}
+	if len(lines) > 0 {
+		resp.SplitID = transformer.NullStringPtr(lines[0].SplitID)
+		resp.Currency = lines[0].Currency
+	}
+	return resp
+}
+
+type ExpenseAllocationsResponse struct {
+	ExpenseID   string                      `json:"expenseId"`
+	Amount      decimal.Decimal             `json:"amount"`
+	Allocated   decimal.Decimal             `json:"allocated"`
+	Unallocated decimal.Decimal             `json:"unallocated"`
+	Currency    string                      `json:"currency"`
+	Allocations []ExpenseAllocationResponse `json:"allocations"`
+}
+
+// ToExpenseAllocationsResponse builds the allocation breakdown of an expense
+func ToExpenseAllocationsResponse(exp *Expense, allocations []*ExpenseAllocation) ExpenseAllocationsResponse {
+	allocated := decimal.Zero
+	for _, a := range allocations {
+		allocated = allocated.Add(a.Amount)
+	}
+	return ExpenseAllocationsResponse{
+		ExpenseID:   exp.ID,
+		Amount:      exp.Amount,
+		Allocated:   allocated,
+		Unallocated: exp.Amount.Sub(allocated),
+		Currency:    exp.Currency,
+		Allocations: ToExpenseAllocationResponses(allocations),
+	}
+}
+
 // RecurringExpenseResponse is the API response for RecurringExpense entity
 // No DeletedAt field (GORM leakage removed)
 // Optional fields use pointers (recurringEndDate, note)
@@ -346,3 +508,303 @@ func AssetToRecentActivity(a *Asset) RecentActivityResponse {
 		CreatedAt:   a.CreatedAt,
 	}
 }
+
+// ExpenseDraftResponse is the API response for ExpenseDraft entity
+type ExpenseDraftResponse struct {
+	ID              string             `json:"id"`
+	BusinessID      string             `json:"businessId"`
+	Status          ExpenseDraftStatus `json:"status"`
+	Source          ExpenseDraftSource `json:"source"`
+	SenderEmail     string             `json:"senderEmail,omitempty"`
+	ReceiptAssetID  string             `json:"receiptAssetId"`
+	ReceiptURL      string             `json:"receiptUrl"`
+	Amount          decimal.Decimal    `json:"amount"`
+	Currency        string             `json:"currency"`
+	OccurredOn      *time.Time         `json:"occurredOn,omitempty"`
+	Vendor          string             `json:"vendor"`
+	Category        ExpenseCategory    `json:"category"`
+	Note            *string            `json:"note,omitempty"`
+	RawText         string             `json:"rawText,omitempty"`
+	ExtractionError string             `json:"extractionError,omitempty"`
+	ExpenseID       *string            `json:"expenseId,omitempty"`
+	CreatedAt       time.Time          `json:"createdAt"`
+	UpdatedAt       time.Time          `json:"updatedAt"`
+}
+
+// ToExpenseDraftResponse converts ExpenseDraft model to ExpenseDraftResponse
+func ToExpenseDraftResponse(d *ExpenseDraft) ExpenseDraftResponse {
+	if d == nil {
+		return ExpenseDraftResponse{}
+	}
+	return ExpenseDraftResponse{
+		ID:              d.ID,
+		BusinessID:      d.BusinessID,
+		Status:          d.Status,
+		Source:          d.Source,
+		SenderEmail:     d.SenderEmail,
+		ReceiptAssetID:  d.ReceiptAssetID,
+		ReceiptURL:      d.ReceiptURL,
+		Amount:          d.Amount,
+		Currency:        d.Currency,
+		OccurredOn:      transformer.NullTimePtr(d.OccurredOn),
+		Vendor:          d.Vendor,
+		Category:        d.Category,
+		Note:            transformer.NullStringPtr(d.Note),
+		RawText:         d.RawText,
+		ExtractionError: d.ExtractionError,
+		ExpenseID:       transformer.NullStringPtr(d.ExpenseID),
+		CreatedAt:       d.CreatedAt,
+		UpdatedAt:       d.UpdatedAt,
+	}
+}
+
+// ToExpenseDraftResponses converts a slice of ExpenseDraft models to responses
+func ToExpenseDraftResponses(drafts []*ExpenseDraft) []ExpenseDraftResponse {
+	responses := make([]ExpenseDraftResponse, len(drafts))
+	for i, d := range drafts {
+		responses[i] = ToExpenseDraftResponse(d)
+	}
+	return responses
+}
+
+// ExpenseInboxResponse is the API response for a business' expense inbox
+type ExpenseInboxResponse struct {
+	Address   string    `json:"address"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ToExpenseInboxResponse converts ExpenseInbox model to ExpenseInboxResponse
+func ToExpenseInboxResponse(m *ExpenseInbox) ExpenseInboxResponse {
+	if m == nil {
+		return ExpenseInboxResponse{}
+	}
+	return ExpenseInboxResponse{
+		Address:   m.Address(),
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+// InboundReceiptEmailResponse reports the drafts an inbound receipt email created
+type InboundReceiptEmailResponse struct {
+	DraftIDs []string `json:"draftIds"`
+}
+
+// LedgerAccountResponse is an account of the chart of accounts with its debits, credits and
+// balance over the requested period
+type LedgerAccountResponse struct {
+	ID      string            `json:"id"`
+	Code    LedgerAccountCode `json:"code"`
+	Name    string            `json:"name"`
+	Type    LedgerAccountType `json:"type"`
+	Debit   decimal.Decimal   `json:"debit"`
+	Credit  decimal.Decimal   `json:"credit"`
+	Balance decimal.Decimal   `json:"balance"`
+}
+
+// LedgerAccountsResponse is the chart of accounts of a business with its balances
+type LedgerAccountsResponse struct {
+	Accounts []LedgerAccountResponse `json:"accounts"`
+	Currency string                  `json:"currency"`
+}
+
+// ToLedgerAccountsResponse converts account balances to LedgerAccountsResponse
+func ToLedgerAccountsResponse(balances []*LedgerAccountBalance, currency string) LedgerAccountsResponse {
+	accounts := make([]LedgerAccountResponse, len(balances))
+	for i, b := range balances {
+		accounts[i] = LedgerAccountResponse{
+			ID:      b.Account.ID,
+			Code:    b.Account.Code,
+			Name:    b.Account.Name,
+			Type:    b.Account.Type,
+			Debit:   b.Debit,
+			Credit:  b.Credit,
+			Balance: b.Balance(),
+		}
+	}
+	return LedgerAccountsResponse{Accounts: accounts, Currency: currency}
+}
+
+// JournalLineResponse is the API response for a line of a journal entry
+type JournalLineResponse struct {
+	AccountID   string            `json:"accountId"`
+	AccountCode LedgerAccountCode `json:"accountCode"`
+	AccountName string            `json:"accountName"`
+	Debit       decimal.Decimal   `json:"debit"`
+	Credit      decimal.Decimal   `json:"credit"`
+}
+
+// JournalEntryResponse is the API response for JournalEntry entity
+type JournalEntryResponse struct {
+	ID          string                `json:"id"`
+	SourceType  JournalSourceType     `json:"sourceType"`
+	SourceID    string                `json:"sourceId"`
+	Description string                `json:"description"`
+	Currency    string                `json:"currency"`
+	PostedAt    time.Time             `json:"postedAt"`
+	Lines       []JournalLineResponse `json:"lines"`
+	CreatedAt   time.Time             `json:"createdAt"`
+}
+
+// ToJournalEntryResponse converts JournalEntry model to JournalEntryResponse
+func ToJournalEntryResponse(e *JournalEntry) JournalEntryResponse {
+	if e == nil {
+		return JournalEntryResponse{}
+	}
+	lines := make([]JournalLineResponse, len(e.Lines))
+	for i, l := range e.Lines {
+		lines[i] = JournalLineResponse{
+			AccountID: l.AccountID,
+			Debit:     l.Debit,
+			Credit:    l.Credit,
+		}
+		if l.Account != nil {
+			lines[i].AccountCode = l.Account.Code
+			lines[i].AccountName = l.Account.Name
+		}
+	}
+	return JournalEntryResponse{
+		ID:          e.ID,
+		SourceType:  e.SourceType,
+		SourceID:    e.SourceID,
+		Description: e.Description,
+		Currency:    e.Currency,
+		PostedAt:    e.PostedAt,
+		Lines:       lines,
+		CreatedAt:   e.CreatedAt,
+	}
+}
+
+// ToJournalEntryResponses converts a slice of JournalEntry models to responses
+func ToJournalEntryResponses(entries []*JournalEntry) []JournalEntryResponse {
+	responses := make([]JournalEntryResponse, len(entries))
+	for i, e := range entries {
+		responses[i] = ToJournalEntryResponse(e)
+	}
+	return responses
+}
+
+// OwnerShareResponse is an owner of the cap table with their share and what they invested
+// and withdrew over the life of the business
+type OwnerShareResponse struct {
+	OwnerID    string          `json:"ownerId"`
+	Percentage decimal.Decimal `json:"percentage"`
+	Invested   decimal.Decimal `json:"invested"`
+	Withdrawn  decimal.Decimal `json:"withdrawn"`
+}
+
+// CapTableResponse is the cap table of a business
+type CapTableResponse struct {
+	Owners          []OwnerShareResponse `json:"owners"`
+	TotalPercentage decimal.Decimal      `json:"totalPercentage"`
+	Unassigned      decimal.Decimal      `json:"unassigned"`
+	Currency        string               `json:"currency"`
+}
+
+// ToCapTableResponse converts cap table entries to CapTableResponse
+func ToCapTableResponse(entries []*CapTableEntry, currency string) CapTableResponse {
+	owners := make([]OwnerShareResponse, len(entries))
+	total := decimal.Zero
+	for i, e := range entries {
+		owners[i] = OwnerShareResponse{
+			OwnerID:    e.Share.OwnerID,
+			Percentage: e.Share.Percentage,
+			Invested:   e.Invested,
+			Withdrawn:  e.Withdrawn,
+		}
+		total = total.Add(e.Share.Percentage)
+	}
+	return CapTableResponse{
+		Owners:          owners,
+		TotalPercentage: total,
+		Unassigned:      decimal.NewFromInt(100).Sub(total),
+		Currency:        currency,
+	}
+}
+
+// OwnerDistributionResponse is one owner's part of a profit distribution
+type OwnerDistributionResponse struct {
+	OwnerID     string          `json:"ownerId"`
+	Percentage  decimal.Decimal `json:"percentage"`
+	Entitlement decimal.Decimal `json:"entitlement"`
+	Withdrawn   decimal.Decimal `json:"withdrawn"`
+	Proposed    decimal.Decimal `json:"proposed"`
+}
+
+// ProfitDistributionResponse proposes per-owner withdrawals from the net profit of a period
+type ProfitDistributionResponse struct {
+	From          time.Time                   `json:"from"`
+	To            time.Time                   `json:"to"`
+	NetProfit     decimal.Decimal             `json:"netProfit"`
+	Percent       decimal.Decimal             `json:"percent"`
+	Distributable decimal.Decimal             `json:"distributable"`
+	Owners        []OwnerDistributionResponse `json:"owners"`
+	Currency      string                      `json:"currency"`
+}
+
+// ToProfitDistributionResponse converts a ProfitDistribution to ProfitDistributionResponse
+func ToProfitDistributionResponse(d *ProfitDistribution, currency string) ProfitDistributionResponse {
+	owners := make([]OwnerDistributionResponse, len(d.Owners))
+	for i, o := range d.Owners {
+		owners[i] = OwnerDistributionResponse{
+			OwnerID:     o.OwnerID,
+			Percentage:  o.Percentage,
+			Entitlement: o.Entitlement,
+			Withdrawn:   o.Withdrawn,
+			Proposed:    o.Proposed,
+		}
+	}
+	return ProfitDistributionResponse{
+		From:          d.From,
+		To:            d.To,
+		NetProfit:     d.NetProfit,
+		Percent:       d.Percent,
+		Distributable: d.Distributable,
+		Owners:        owners,
+		Currency:      currency,
+	}
+}
+
+// ProfitDistributionRecordedResponse is a recorded profit distribution with the withdrawals it created
+type ProfitDistributionRecordedResponse struct {
+	Distribution ProfitDistributionResponse `json:"distribution"`
+	Withdrawals  []WithdrawalResponse       `json:"withdrawals"`
+}
+
+// AuditEventResponse is the API response for AuditEvent entity
+type AuditEventResponse struct {
+	ID         string             `json:"id"`
+	RecordType AuditRecordType    `json:"recordType"`
+	RecordID   string             `json:"recordId"`
+	Type       AuditEventType     `json:"type"`
+	ActorID    *string            `json:"actorId"`
+	Changes    []AuditFieldChange `json:"changes"`
+	CreatedAt  time.Time          `json:"createdAt"`
+}
+
+// ToAuditEventResponse converts AuditEvent model to AuditEventResponse
+func ToAuditEventResponse(e *AuditEvent) AuditEventResponse {
+	changes := []AuditFieldChange(e.Changes)
+	if changes == nil {
+		changes = []AuditFieldChange{}
+	}
+	return AuditEventResponse{
+		ID:         e.ID,
+		RecordType: e.RecordType,
+		RecordID:   e.RecordID,
+		Type:       e.Type,
+		ActorID:    transformer.NullStringPtr(e.ActorID),
+		Changes:    changes,
+		CreatedAt:  e.CreatedAt,
+	}
+}
+
+// ToAuditEventResponses converts a slice of AuditEvent models to responses
+func ToAuditEventResponses(events []*AuditEvent) []AuditEventResponse {
+	responses := make([]AuditEventResponse, len(events))
+	for i, e := range events {
+		responses[i] = ToAuditEventResponse(e)
+	}
+	return responses
+}
@@ -0,0 +1,82 @@
+package accounting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/email"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+)
+
+// Notification encapsulates email sending for accounting domain
+type Notification struct {
+	client     email.Client
+	info       email.EmailInfo
+	accountSvc *account.Service
+}
+
+// NewNotification wires the email client, defaults and account service
+func NewNotification(client email.Client, info email.EmailInfo, accountSvc *account.Service) *Notification {
+	return &Notification{client: client, info: info, accountSvc: accountSvc}
+}
+
+// SendRecurringExpenseReminderEmail tells the workspace owner a recurring expense will be recorded on dueDate
+func (n *Notification) SendRecurringExpenseReminderEmail(ctx context.Context, biz *business.Business, rexp *RecurringExpense, dueDate time.Time) error {
+	if n.client == nil {
+		return fmt.Errorf("email client not available")
+	}
+	logger := logger.FromContext(ctx).With("action", "sendRecurringExpenseReminder", "businessId", biz.ID, "recurringExpenseId", rexp.ID)
+	logger.Info("sending recurring expense reminder email")
+
+	ws, err := n.accountSvc.GetWorkspaceByID(ctx, biz.WorkspaceID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to get workspace", "error", err)
+		return fmt.Errorf("failed to get workspace: %w", err)
+	}
+	user, err := n.accountSvc.GetUserByID(ctx, ws.OwnerID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to get workspace owner", "error", err)
+		return fmt.Errorf("failed to get workspace owner: %w", err)
+	}
+
+	amount := fmt.Sprintf("%s %s", money.StringFixed(rexp.Amount, rexp.Currency), rexp.Currency)
+	data := map[string]any{
+		"userName":     n.getUserDisplayName(user),
+		"businessName": biz.Name,
+		"frequency":    string(rexp.Frequency),
+		"category":     string(rexp.Category),
+		"amount":       amount,
+		"dueDate":      dueDate.Format("January 2, 2006"),
+		"note":         rexp.Note.String,
+		"dashboardURL": fmt.Sprintf("%s/business/%s/accounting/expenses", n.info.BaseURL, biz.Descriptor),
+		"productName":  n.info.ProductName,
+		"supportEmail": n.info.SupportEmail,
+		"helpURL":      n.info.HelpURL,
+		"currentYear":  fmt.Sprintf("%d", time.Now().Year()),
+	}
+	subject := fmt.Sprintf("%s: %s %s expense due %s", biz.Name, amount, rexp.Category, dueDate.Format("Jan 2"))
+	from := n.info.FormattedFrom()
+	if _, err := n.client.SendTemplate(ctx, email.TemplateRecurringExpenseReminder, []string{user.Email}, from, subject, data); err != nil {
+		logger.Error("failed to send recurring expense reminder email", "error", err)
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	logger.Info("recurring expense reminder email sent successfully")
+	return nil
+}
+
+func (n *Notification) getUserDisplayName(user *account.User) string {
+	if user.FirstName != "" {
+		if user.LastName != "" {
+			return fmt.Sprintf("%s %s", user.FirstName, user.LastName)
+		}
+		return user.FirstName
+	}
+	if user.LastName != "" {
+		return user.LastName
+	}
+	return "there"
+}
@@ -2,16 +2,22 @@ package accounting
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/abdelrahman146/kyora/internal/domain/account"
 	"github.com/abdelrahman146/kyora/internal/domain/business"
 	"github.com/abdelrahman146/kyora/internal/platform/bus"
 	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/fx"
+	"github.com/abdelrahman146/kyora/internal/platform/ocr"
 	"github.com/abdelrahman146/kyora/internal/platform/types/atomic"
 	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
 	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
@@ -28,6 +34,12 @@ type Service struct {
 	storage         *Storage
 	atomicProcessor atomic.AtomicProcessor
 	bus             *bus.Bus
+	receipts        ReceiptStore
+	ocr             ocr.Provider
+	fx              fx.Provider
+	payables        PayablesProvider
+	businesses      *business.Service
+	notification    *Notification
 }
 
 func NewService(storage *Storage, atomicProcessor atomic.AtomicProcessor, bus *bus.Bus) *Service {
@@ -38,6 +50,23 @@ func NewService(storage *Storage, atomicProcessor atomic.AtomicProcessor, bus *b
 	}
 }
 
+// SetFXProvider wires the exchange rate provider used for expenses in other currencies; with
+// nil those expenses need a manual rate.
+func (s *Service) SetFXProvider(provider fx.Provider) {
+	s.fx = provider
+}
+
+// PayablesProvider reports what a business currently owes its suppliers.
+type PayablesProvider interface {
+	SumSupplierLiabilities(ctx context.Context, actor *account.User, biz *business.Business) (decimal.Decimal, error)
+}
+
+// SetPayablesProvider wires the supplier payables kept out of the safe-to-draw amount; with nil
+// none are.
+func (s *Service) SetPayablesProvider(provider PayablesProvider) {
+	s.payables = provider
+}
+
 func (s *Service) CreateAsset(ctx context.Context, actor *account.User, biz *business.Business, req *CreateAssetRequest) (*Asset, error) {
 	asset := &Asset{
 		BusinessID: biz.ID,
@@ -52,7 +81,12 @@ func (s *Service) CreateAsset(ctx context.Context, actor *account.User, biz *bus
 	if req.Note != "" {
 		asset.Note = req.Note
 	}
-	err := s.storage.asset.CreateOne(ctx, asset)
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.asset.CreateOne(tctx, asset); err != nil {
+			return err
+		}
+		return s.recordAuditEvent(tctx, actor, biz.ID, AuditRecordAsset, asset.ID, nil, assetAuditFields(asset))
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -67,6 +101,7 @@ func (s *Service) UpdateAsset(ctx context.Context, actor *account.User, biz *bus
 	if err != nil {
 		return nil, err
 	}
+	before := assetAuditFields(asset)
 	if req.Name != "" {
 		asset.Name = req.Name
 	}
@@ -82,7 +117,12 @@ func (s *Service) UpdateAsset(ctx context.Context, actor *account.User, biz *bus
 	if req.Note != "" {
 		asset.Note = req.Note
 	}
-	err = s.storage.asset.UpdateOne(ctx, asset)
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.asset.UpdateOne(tctx, asset); err != nil {
+			return err
+		}
+		return s.recordAuditEvent(tctx, actor, biz.ID, AuditRecordAsset, asset.ID, before, assetAuditFields(asset))
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +134,12 @@ func (s *Service) DeleteAsset(ctx context.Context, actor *account.User, biz *bus
 	if err != nil {
 		return err
 	}
-	return s.storage.asset.DeleteOne(ctx, asset)
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.asset.DeleteOne(tctx, asset); err != nil {
+			return err
+		}
+		return s.recordAuditEvent(tctx, actor, biz.ID, AuditRecordAsset, asset.ID, assetAuditFields(asset), nil)
+	})
 }
 
 func (s *Service) GetAssetByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Asset, error) {
@@ -137,7 +182,15 @@ func (s *Service) CreateInvestment(ctx context.Context, actor *account.User, biz
 	if !req.InvestedAt.IsZero() {
 		investment.InvestedAt = req.InvestedAt
 	}
-	err := s.storage.investment.CreateOne(ctx, investment)
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.investment.CreateOne(tctx, investment); err != nil {
+			return err
+		}
+		if err := s.recordAuditEvent(tctx, actor, biz.ID, AuditRecordInvestment, investment.ID, nil, investmentAuditFields(investment)); err != nil {
+			return err
+		}
+		return s.postJournalEntry(tctx, investmentLedgerPosting(investment))
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -152,6 +205,7 @@ func (s *Service) UpdateInvestment(ctx context.Context, actor *account.User, biz
 	if err != nil {
 		return nil, err
 	}
+	before := investmentAuditFields(investment)
 	if !req.Amount.IsZero() {
 		investment.Amount = req.Amount
 	}
@@ -164,7 +218,15 @@ func (s *Service) UpdateInvestment(ctx context.Context, actor *account.User, biz
 	if req.Note != "" {
 		investment.Note = req.Note
 	}
-	err = s.storage.investment.UpdateOne(ctx, investment)
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.investment.UpdateOne(tctx, investment); err != nil {
+			return err
+		}
+		if err := s.recordAuditEvent(tctx, actor, biz.ID, AuditRecordInvestment, investment.ID, before, investmentAuditFields(investment)); err != nil {
+			return err
+		}
+		return s.postJournalEntry(tctx, investmentLedgerPosting(investment))
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -176,7 +238,15 @@ func (s *Service) DeleteInvestment(ctx context.Context, actor *account.User, biz
 	if err != nil {
 		return err
 	}
-	return s.storage.investment.DeleteOne(ctx, investment)
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.investment.DeleteOne(tctx, investment); err != nil {
+			return err
+		}
+		if err := s.recordAuditEvent(tctx, actor, biz.ID, AuditRecordInvestment, investment.ID, investmentAuditFields(investment), nil); err != nil {
+			return err
+		}
+		return s.removeJournalEntry(tctx, biz.ID, JournalSourceInvestment, investment.ID)
+	})
 }
 
 func (s *Service) GetInvestmentByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Investment, error) {
@@ -217,7 +287,14 @@ func (s *Service) SumInvestmentAmountByInvestor(ctx context.Context, actor *acco
 	)
 }
 
-func (s *Service) CreateWithdrawal(ctx context.Context, actor *account.User, biz *business.Business, req *CreateWithdrawalRequest) (*Withdrawal, error) {
+// CreateWithdrawal records an owner withdrawal. When the business guards withdrawals and the
+// amount exceeds the safe-to-draw amount, the withdrawal comes back with a warning, or is
+// rejected on a business that enforces the limit unless an admin overrides it.
+func (s *Service) CreateWithdrawal(ctx context.Context, actor *account.User, biz *business.Business, req *CreateWithdrawalRequest) (*Withdrawal, *SafeToDrawWarning, error) {
+	warning, err := s.checkWithdrawalGuard(ctx, actor, biz, req.Amount, req.Override)
+	if err != nil {
+		return nil, nil, err
+	}
 	withdrawal := &Withdrawal{
 		BusinessID:   biz.ID,
 		Amount:       req.Amount,
@@ -230,11 +307,50 @@ func (s *Service) CreateWithdrawal(ctx context.Context, actor *account.User, biz
 	if req.Note != "" {
 		withdrawal.Note = req.Note
 	}
-	err := s.storage.withdrawal.CreateOne(ctx, withdrawal)
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.withdrawal.CreateOne(tctx, withdrawal); err != nil {
+			return err
+		}
+		if err := s.recordAuditEvent(tctx, actor, biz.ID, AuditRecordWithdrawal, withdrawal.ID, nil, withdrawalAuditFields(withdrawal)); err != nil {
+			return err
+		}
+		return s.postJournalEntry(tctx, withdrawalLedgerPosting(withdrawal))
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return withdrawal, warning, nil
+}
+
+// checkWithdrawalGuard holds a withdrawal of amount against the all-time safe-to-draw amount,
+// safety buffer included, per the business's withdrawal guard. It returns the warning to
+// report with the withdrawal, if any.
+func (s *Service) checkWithdrawalGuard(ctx context.Context, actor *account.User, biz *business.Business, amount decimal.Decimal, override bool) (*SafeToDrawWarning, error) {
+	if biz.WithdrawalGuard != business.WithdrawalGuardWarn && biz.WithdrawalGuard != business.WithdrawalGuardEnforce {
+		return nil, nil
+	}
+	safeToDraw, err := s.ComputeSafeToDrawAmount(ctx, actor, biz, time.Time{}, time.Time{})
 	if err != nil {
 		return nil, err
 	}
-	return withdrawal, nil
+	if amount.LessThanOrEqual(safeToDraw) {
+		return nil, nil
+	}
+	warning := &SafeToDrawWarning{
+		Code:             "accounting.withdrawal_exceeds_safe_to_draw",
+		SafeToDrawAmount: safeToDraw,
+		Excess:           amount.Sub(safeToDraw),
+	}
+	if biz.WithdrawalGuard == business.WithdrawalGuardEnforce {
+		if !override {
+			return nil, ErrWithdrawalExceedsSafeToDraw(warning.SafeToDrawAmount, warning.Excess)
+		}
+		if err := actor.HasPermission(role.ActionApprove, role.ResourceAccounting); err != nil {
+			return nil, ErrWithdrawalOverrideForbidden()
+		}
+		warning.Overridden = true
+	}
+	return warning, nil
 }
 
 func (s *Service) UpdateWithdrawal(ctx context.Context, actor *account.User, biz *business.Business, id string, req *UpdateWithdrawalRequest) (*Withdrawal, error) {
@@ -245,6 +361,7 @@ func (s *Service) UpdateWithdrawal(ctx context.Context, actor *account.User, biz
 	if err != nil {
 		return nil, err
 	}
+	before := withdrawalAuditFields(withdrawal)
 	if !req.Amount.IsZero() {
 		withdrawal.Amount = req.Amount
 	}
@@ -257,7 +374,15 @@ func (s *Service) UpdateWithdrawal(ctx context.Context, actor *account.User, biz
 	if req.Note != "" {
 		withdrawal.Note = req.Note
 	}
-	err = s.storage.withdrawal.UpdateOne(ctx, withdrawal)
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.withdrawal.UpdateOne(tctx, withdrawal); err != nil {
+			return err
+		}
+		if err := s.recordAuditEvent(tctx, actor, biz.ID, AuditRecordWithdrawal, withdrawal.ID, before, withdrawalAuditFields(withdrawal)); err != nil {
+			return err
+		}
+		return s.postJournalEntry(tctx, withdrawalLedgerPosting(withdrawal))
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -269,7 +394,15 @@ func (s *Service) DeleteWithdrawal(ctx context.Context, actor *account.User, biz
 	if err != nil {
 		return err
 	}
-	return s.storage.withdrawal.DeleteOne(ctx, withdrawal)
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.withdrawal.DeleteOne(tctx, withdrawal); err != nil {
+			return err
+		}
+		if err := s.recordAuditEvent(tctx, actor, biz.ID, AuditRecordWithdrawal, withdrawal.ID, withdrawalAuditFields(withdrawal), nil); err != nil {
+			return err
+		}
+		return s.removeJournalEntry(tctx, biz.ID, JournalSourceWithdrawal, withdrawal.ID)
+	})
 }
 
 func (s *Service) GetWithdrawalByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Withdrawal, error) {
@@ -312,20 +445,23 @@ func (s *Service) SumWithdrawalAmountByWithdrawer(ctx context.Context, actor *ac
 
 // ComputeSafeToDrawAmount computes how much money can be withdrawn safely without jeopardizing operations.
 //
-// The computation is intentionally deterministic:
-// safeToDraw = totalIncome - totalCOGS - totalExpenses - totalWithdrawals - businessSafetyBuffer
+// The computation is intentionally deterministic and derived from the ledger balances within [from,to]:
+// safeToDraw = income - expenses (COGS included) - ownerDrawings - businessSafetyBuffer - supplierPayables
 //
 // Important:
-// - The calculation respects the provided date range by using expenses/withdrawals within [from,to].
-// - SafetyBuffer is treated as an explicit business setting; a value of 0 means no buffer.
-func (s *Service) ComputeSafeToDrawAmount(ctx context.Context, actor *account.User, biz *business.Business, totalIncome, totalCOGS decimal.Decimal, from, to time.Time) (decimal.Decimal, error) {
-	totalWithdrawals, err := s.SumWithdrawalsAmount(ctx, actor, biz, from, to)
+// - Income is booked when orders are fulfilled, net of VAT; returns and refunds are booked as reversals.
+// - SafetyBuffer is treated as an explicit business setting; 0 means the last 30 days of operating expenses.
+// - SupplierPayables is what is owed to suppliers today (unpaid bills, unpaid received goods), whatever the range.
+func (s *Service) ComputeSafeToDrawAmount(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) (decimal.Decimal, error) {
+	balances, err := s.ListLedgerAccountBalances(ctx, actor, biz, from, to)
 	if err != nil {
 		return decimal.Zero, err
 	}
-	totalExpenses, err := s.SumExpensesAmount(ctx, actor, biz, from, to)
-	if err != nil {
-		return decimal.Zero, err
+	netIncome, drawings := ledgerNetIncome(balances), decimal.Zero
+	for _, b := range balances {
+		if b.Account.Code == LedgerAccountOwnerDrawings {
+			drawings = b.Debit.Sub(b.Credit)
+		}
 	}
 
 	safetyBuffer := biz.SafetyBuffer
@@ -337,17 +473,28 @@ func (s *Service) ComputeSafeToDrawAmount(ctx context.Context, actor *account.Us
 			referenceEnd = referenceEnd.UTC()
 		}
 		last30Days := referenceEnd.AddDate(0, 0, -30)
-		expenseLast30Days, err := s.SumExpensesAmount(ctx, actor, biz, last30Days, referenceEnd)
+		recent, err := s.ListLedgerAccountBalances(ctx, actor, biz, last30Days, referenceEnd)
 		if err != nil {
 			return decimal.Zero, err
 		}
-		safetyBuffer = expenseLast30Days
+		for _, b := range recent {
+			if b.Account.Code == LedgerAccountOperatingExpenses || b.Account.Code == LedgerAccountTransactionFeeCost {
+				safetyBuffer = safetyBuffer.Add(b.Balance())
+			}
+		}
+	}
+	safeToDraw := netIncome.Sub(drawings).Sub(safetyBuffer)
+	if s.payables != nil {
+		payables, err := s.payables.SumSupplierLiabilities(ctx, actor, biz)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		safeToDraw = safeToDraw.Sub(payables)
 	}
-	safeToDraw := totalIncome.Sub(totalCOGS).Sub(totalExpenses).Sub(totalWithdrawals).Sub(safetyBuffer)
 	if safeToDraw.IsNegative() {
 		return decimal.Zero, nil
 	}
-	return safeToDraw, nil
+	return money.Round(safeToDraw, biz.Currency), nil
 }
 
 func (s *Service) GetExpenseByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Expense, error) {
@@ -414,11 +561,64 @@ func (s *Service) CountExpenses(ctx context.Context, actor *account.User, biz *b
 	return s.storage.expense.Count(ctx, scopes...)
 }
 
+// setExpenseAmount sets the amount and input VAT of an expense paid in currency. Expenses in
+// the business currency are stored as is. Others are converted at rate (recorded as coming
+// from source), or at the provider's rate for the day the expense occurred when rate is unset,
+// keeping what was paid alongside.
+func (s *Service) setExpenseAmount(ctx context.Context, biz *business.Business, expense *Expense, currency string, amount, vat decimal.Decimal, rate decimal.NullDecimal, source ExchangeRateSource) error {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" || strings.EqualFold(currency, biz.Currency) {
+		expense.Amount = amount
+		expense.VAT = money.Round(vat, biz.Currency)
+		expense.Currency = biz.Currency
+		expense.OriginalAmount = decimal.NullDecimal{}
+		expense.OriginalVAT = decimal.NullDecimal{}
+		expense.OriginalCurrency = sql.NullString{}
+		expense.ExchangeRate = decimal.NullDecimal{}
+		expense.ExchangeRateSource = ""
+		return nil
+	}
+	if !rate.Valid {
+		if s.fx == nil {
+			return ErrExchangeRateRequired(currency)
+		}
+		on := expense.OccurredOn
+		if on.IsZero() {
+			on = time.Now()
+		}
+		r, err := s.fx.Rate(ctx, currency, biz.Currency, on)
+		if err != nil {
+			return ErrExchangeRateUnavailable(currency, err)
+		}
+		rate, source = decimal.NewNullDecimal(r), ExchangeRateSourceAuto
+	}
+	amount = money.Round(amount, currency)
+	vat = money.Round(vat, currency)
+	if vat.IsNegative() || vat.GreaterThan(amount) {
+		return ErrExpenseInvalidVAT(vat.String(), amount.String())
+	}
+	expense.Amount = money.Round(amount.Mul(rate.Decimal), biz.Currency)
+	expense.VAT = money.Round(vat.Mul(rate.Decimal), biz.Currency)
+	expense.Currency = biz.Currency
+	expense.OriginalAmount = decimal.NewNullDecimal(amount)
+	expense.OriginalVAT = decimal.NewNullDecimal(vat)
+	expense.OriginalCurrency = sql.NullString{String: currency, Valid: true}
+	expense.ExchangeRate = rate
+	expense.ExchangeRateSource = source
+	return nil
+}
+
+// validateExpenseVAT checks the input VAT of an expense fits within its amount.
+func validateExpenseVAT(expense *Expense) error {
+	if expense.VAT.IsNegative() || expense.VAT.GreaterThan(expense.Amount) {
+		return ErrExpenseInvalidVAT(expense.VAT.String(), expense.Amount.String())
+	}
+	return nil
+}
+
 func (s *Service) CreateExpense(ctx context.Context, actor *account.User, biz *business.Business, req *CreateExpenseRequest) (*Expense, error) {
 	expense := &Expense{
 		BusinessID:         biz.ID,
-		Amount:             req.Amount,
-		Currency:           biz.Currency,
 		Category:           req.Category,
 		Note:               transformer.ToNullString(req.Note),
 		RecurringExpenseID: transformer.ToNullString(req.RecurringExpenseID),
@@ -427,7 +627,22 @@ func (s *Service) CreateExpense(ctx context.Context, actor *account.User, biz *b
 	if req.OccurredOn != nil {
 		expense.OccurredOn = req.OccurredOn.Time
 	}
-	if err := s.storage.expense.CreateOne(ctx, expense); err != nil {
+	if err := s.setExpenseAmount(ctx, biz, expense, req.Currency, req.Amount, req.VAT, req.ExchangeRate, ExchangeRateSourceManual); err != nil {
+		return nil, err
+	}
+	if err := validateExpenseVAT(expense); err != nil {
+		return nil, err
+	}
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.expense.CreateOne(tctx, expense); err != nil {
+			return err
+		}
+		if err := s.recordAuditEvent(tctx, actor, biz.ID, AuditRecordExpense, expense.ID, nil, expenseAuditFields(expense)); err != nil {
+			return err
+		}
+		return s.postJournalEntry(tctx, expenseLedgerPosting(expense))
+	})
+	if err != nil {
 		return nil, err
 	}
 	return expense, nil
@@ -438,7 +653,18 @@ func (s *Service) DeleteExpense(ctx context.Context, actor *account.User, biz *b
 	if err != nil {
 		return err
 	}
-	return s.storage.expense.DeleteOne(ctx, expense)
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.allocation.DeleteMany(tctx, s.storage.allocation.ScopeEquals(ExpenseAllocationSchema.ExpenseID, expense.ID)); err != nil {
+			return err
+		}
+		if err := s.storage.expense.DeleteOne(tctx, expense); err != nil {
+			return err
+		}
+		if err := s.recordAuditEvent(tctx, actor, biz.ID, AuditRecordExpense, expense.ID, expenseAuditFields(expense), nil); err != nil {
+			return err
+		}
+		return s.removeJournalEntry(tctx, biz.ID, JournalSourceExpense, expense.ID)
+	})
 }
 
 func (s *Service) UpdateExpense(ctx context.Context, actor *account.User, biz *business.Business, id string, req *UpdateExpenseRequest) (*Expense, error) {
@@ -446,13 +672,13 @@ func (s *Service) UpdateExpense(ctx context.Context, actor *account.User, biz *b
 	if err != nil {
 		return nil, err
 	}
-	if !req.Amount.IsZero() {
-		expense.Amount = req.Amount
-	}
+	previousAmount := expense.Amount
+	before := expenseAuditFields(expense)
 	if req.Category != "" {
 		expense.Category = req.Category
 	}
 	expense.Note = transformer.ToNullString(req.Note)
+	dateChanged := req.OccurredOn != nil && !req.OccurredOn.Time.Equal(expense.OccurredOn)
 	if req.OccurredOn != nil {
 		expense.OccurredOn = req.OccurredOn.Time
 	}
@@ -460,7 +686,50 @@ func (s *Service) UpdateExpense(ctx context.Context, actor *account.User, biz *b
 	if req.Type != "" {
 		expense.Type = req.Type
 	}
-	if err := s.storage.expense.UpdateOne(ctx, expense); err != nil {
+
+	// amounts are edited in the currency the expense was paid in
+	currency, amount, vat := expense.Currency, expense.Amount, expense.VAT
+	if expense.OriginalCurrency.Valid {
+		currency, amount, vat = expense.OriginalCurrency.String, expense.OriginalAmount.Decimal, expense.OriginalVAT.Decimal
+	}
+	rate, source := expense.ExchangeRate, expense.ExchangeRateSource
+	if req.Currency != "" && !strings.EqualFold(req.Currency, currency) {
+		currency, rate = req.Currency, decimal.NullDecimal{}
+	}
+	if dateChanged && source == ExchangeRateSourceAuto {
+		rate = decimal.NullDecimal{}
+	}
+	if req.ExchangeRate.Valid {
+		rate, source = req.ExchangeRate, ExchangeRateSourceManual
+	}
+	if !req.Amount.IsZero() {
+		amount = req.Amount
+	}
+	if req.VAT != nil {
+		vat = *req.VAT
+	}
+	if err := s.setExpenseAmount(ctx, biz, expense, currency, amount, vat, rate, source); err != nil {
+		return nil, err
+	}
+	if err := validateExpenseVAT(expense); err != nil {
+		return nil, err
+	}
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.expense.UpdateOne(tctx, expense); err != nil {
+			return err
+		}
+		if err := s.recordAuditEvent(tctx, actor, biz.ID, AuditRecordExpense, expense.ID, before, expenseAuditFields(expense)); err != nil {
+			return err
+		}
+		if err := s.postJournalEntry(tctx, expenseLedgerPosting(expense)); err != nil {
+			return err
+		}
+		if expense.Amount.Equal(previousAmount) {
+			return nil
+		}
+		return s.rescaleExpenseAllocations(tctx, expense, previousAmount)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return expense, nil
@@ -482,6 +751,7 @@ func (s *Service) UpsertTransactionFeeExpenseForOrder(
 	if businessID == "" || orderID == "" {
 		return fmt.Errorf("businessID and orderID are required")
 	}
+	amount = money.Round(amount, currency)
 	if amount.LessThanOrEqual(decimal.Zero) {
 		return nil
 	}
@@ -505,12 +775,19 @@ func (s *Service) UpsertTransactionFeeExpenseForOrder(
 			return err
 		}
 		if err == nil {
+			before := expenseAuditFields(existing)
 			existing.Amount = amount
 			existing.Currency = currency
 			existing.OccurredOn = occurredOn
 			existing.Note = transformer.ToNullString(note)
 			existing.Type = ExpenseTypeOneTime
-			return s.storage.expense.UpdateOne(tctx, existing)
+			if err := s.storage.expense.UpdateOne(tctx, existing); err != nil {
+				return err
+			}
+			if err := s.recordAuditEvent(tctx, nil, businessID, AuditRecordExpense, existing.ID, before, expenseAuditFields(existing)); err != nil {
+				return err
+			}
+			return s.postJournalEntry(tctx, expenseLedgerPosting(existing))
 		}
 
 		exp := &Expense{
@@ -534,19 +811,276 @@ func (s *Service) UpsertTransactionFeeExpenseForOrder(
 				if err2 != nil {
 					return err2
 				}
+				before := expenseAuditFields(again)
 				again.Amount = amount
 				again.Currency = currency
 				again.OccurredOn = occurredOn
 				again.Note = transformer.ToNullString(note)
 				again.Type = ExpenseTypeOneTime
-				return s.storage.expense.UpdateOne(tctx, again)
+				if err := s.storage.expense.UpdateOne(tctx, again); err != nil {
+					return err
+				}
+				if err := s.recordAuditEvent(tctx, nil, businessID, AuditRecordExpense, again.ID, before, expenseAuditFields(again)); err != nil {
+					return err
+				}
+				return s.postJournalEntry(tctx, expenseLedgerPosting(again))
 			}
 			return err
 		}
-		return nil
+		if err := s.recordAuditEvent(tctx, nil, businessID, AuditRecordExpense, exp.ID, nil, expenseAuditFields(exp)); err != nil {
+			return err
+		}
+		return s.postJournalEntry(tctx, expenseLedgerPosting(exp))
 	}, atomic.WithIsolationLevel(atomic.LevelSerializable), atomic.WithRetries(3))
 }
 
+// Expense allocation
+
+func (s *Service) GetExpenseAllocationRuleByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*ExpenseAllocationRule, error) {
+	return s.storage.allocationRule.FindOne(ctx,
+		s.storage.allocationRule.ScopeID(id),
+		s.storage.allocationRule.ScopeBusinessID(biz.ID),
+	)
+}
+
+func (s *Service) ListExpenseAllocationRules(ctx context.Context, actor *account.User, biz *business.Business, req *list.ListRequest) ([]*ExpenseAllocationRule, error) {
+	return s.storage.allocationRule.FindMany(ctx,
+		s.storage.allocationRule.ScopeBusinessID(biz.ID),
+		s.storage.allocationRule.WithPagination(req.Offset(), req.Limit()),
+		s.storage.allocationRule.WithOrderBy(req.ParsedOrderByWithDefault(ExpenseAllocationRuleSchema, []string{"name"})),
+	)
+}
+
+func (s *Service) CountExpenseAllocationRules(ctx context.Context, actor *account.User, biz *business.Business) (int64, error) {
+	return s.storage.allocationRule.Count(ctx, s.storage.allocationRule.ScopeBusinessID(biz.ID))
+}
+
+// CreateExpenseAllocationRule saves an allocation split for reuse. Percentages are checked
+// here; fixed amounts can only be checked against an expense when the rule is applied.
+func (s *Service) CreateExpenseAllocationRule(ctx context.Context, actor *account.User, biz *business.Business, req *CreateExpenseAllocationRuleRequest) (*ExpenseAllocationRule, error) {
+	if err := validateAllocationLines(req.Method, req.Lines); err != nil {
+		return nil, err
+	}
+	rule := &ExpenseAllocationRule{
+		BusinessID: biz.ID,
+		Name:       strings.TrimSpace(req.Name),
+		TargetType: req.TargetType,
+		Method:     req.Method,
+		Lines:      AllocationLines(req.Lines),
+	}
+	if err := s.storage.allocationRule.CreateOne(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateExpenseAllocationRule updates a saved rule. Expenses it was applied to keep their
+// allocations; re-apply the rule to pick up the change.
+func (s *Service) UpdateExpenseAllocationRule(ctx context.Context, actor *account.User, biz *business.Business, id string, req *UpdateExpenseAllocationRuleRequest) (*ExpenseAllocationRule, error) {
+	rule, err := s.GetExpenseAllocationRuleByID(ctx, actor, biz, id)
+	if err != nil {
+		return nil, err
+	}
+	if name := strings.TrimSpace(req.Name); name != "" {
+		rule.Name = name
+	}
+	if req.TargetType != "" {
+		rule.TargetType = req.TargetType
+	}
+	if req.Method != "" {
+		rule.Method = req.Method
+	}
+	if req.Lines != nil {
+		rule.Lines = AllocationLines(req.Lines)
+	}
+	if err := validateAllocationLines(rule.Method, rule.Lines); err != nil {
+		return nil, err
+	}
+	if err := s.storage.allocationRule.UpdateOne(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *Service) DeleteExpenseAllocationRule(ctx context.Context, actor *account.User, biz *business.Business, id string) error {
+	rule, err := s.GetExpenseAllocationRuleByID(ctx, actor, biz, id)
+	if err != nil {
+		return err
+	}
+	return s.storage.allocationRule.DeleteOne(ctx, rule)
+}
+
+// ResolveExpenseAllocation returns the target type, method and lines an allocate request
+// stands for, loading the saved rule when the request references one.
+func (s *Service) ResolveExpenseAllocation(ctx context.Context, actor *account.User, biz *business.Business, req *AllocateExpenseRequest) (*ExpenseAllocationRule, error) {
+	if req.RuleID == "" {
+		return &ExpenseAllocationRule{
+			BusinessID: biz.ID,
+			TargetType: req.TargetType,
+			Method:     req.Method,
+			Lines:      AllocationLines(req.Lines),
+		}, nil
+	}
+	rule, err := s.GetExpenseAllocationRuleByID(ctx, actor, biz, req.RuleID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrExpenseAllocationRuleNotFound(err)
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+// AllocateExpense splits an expense across orders or products according to rule, replacing any
+// previous allocation of the expense. Rules without an ID are ad-hoc splits from the request.
+// Callers are expected to have checked that the rule's targets belong to the business.
+func (s *Service) AllocateExpense(ctx context.Context, actor *account.User, biz *business.Business, expenseID string, rule *ExpenseAllocationRule) (*Expense, []*ExpenseAllocation, error) {
+	expense, err := s.GetExpenseByID(ctx, actor, biz, expenseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	allocations, err := computeAllocations(expense, rule)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.allocation.DeleteMany(tctx, s.storage.allocation.ScopeEquals(ExpenseAllocationSchema.ExpenseID, expense.ID)); err != nil {
+			return err
+		}
+		return s.storage.allocation.CreateMany(tctx, allocations)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return expense, allocations, nil
+}
+
+// ListExpenseAllocations returns the allocations of an expense, largest first.
+func (s *Service) ListExpenseAllocations(ctx context.Context, actor *account.User, biz *business.Business, expenseID string) ([]*ExpenseAllocation, error) {
+	return s.storage.allocation.FindMany(ctx,
+		s.storage.allocation.ScopeBusinessID(biz.ID),
+		s.storage.allocation.ScopeEquals(ExpenseAllocationSchema.ExpenseID, expenseID),
+		s.storage.allocation.WithOrderBy([]string{"amount DESC", "target_id"}),
+	)
+}
+
+// ClearExpenseAllocations removes every allocation of an expense, returning it to overhead.
+func (s *Service) ClearExpenseAllocations(ctx context.Context, actor *account.User, biz *business.Business, expenseID string) error {
+	expense, err := s.GetExpenseByID(ctx, actor, biz, expenseID)
+	if err != nil {
+		return err
+	}
+	return s.storage.allocation.DeleteMany(ctx,
+		s.storage.allocation.ScopeBusinessID(biz.ID),
+		s.storage.allocation.ScopeEquals(ExpenseAllocationSchema.ExpenseID, expense.ID),
+	)
+}
+
+// SumAllocatedExpensesByTarget returns the allocated amount per order or product ID for
+// expenses that occurred within the given range.
+func (s *Service) SumAllocatedExpensesByTarget(ctx context.Context, actor *account.User, biz *business.Business, targetType AllocationTargetType, from, to time.Time) (map[string]decimal.Decimal, error) {
+	return s.storage.SumAllocationsByTarget(ctx, biz.ID, targetType, from, to)
+}
+
+// rescaleExpenseAllocations keeps allocations proportional when an expense amount changes.
+func (s *Service) rescaleExpenseAllocations(ctx context.Context, expense *Expense, previousAmount decimal.Decimal) error {
+	allocations, err := s.storage.allocation.FindMany(ctx,
+		s.storage.allocation.ScopeEquals(ExpenseAllocationSchema.ExpenseID, expense.ID),
+		s.storage.allocation.WithOrderBy([]string{"id"}),
+	)
+	if err != nil || len(allocations) == 0 || previousAmount.IsZero() {
+		return err
+	}
+	allocated := decimal.Zero
+	for _, a := range allocations {
+		allocated = allocated.Add(a.Amount)
+	}
+	target := money.Round(allocated.Mul(expense.Amount).Div(previousAmount), expense.Currency)
+	distributeRounded(allocations, target, expense.Currency, func(a *ExpenseAllocation) decimal.Decimal {
+		return a.Amount.Mul(expense.Amount).Div(previousAmount)
+	})
+	return s.storage.allocation.UpdateMany(ctx, allocations)
+}
+
+// validateAllocationLines checks the shape of allocation lines independent of any expense.
+func validateAllocationLines(method AllocationMethod, lines []AllocationLine) error {
+	seen := make(map[string]struct{}, len(lines))
+	total := decimal.Zero
+	for _, line := range lines {
+		if _, ok := seen[line.TargetID]; ok {
+			return ErrAllocationDuplicateTarget(line.TargetID)
+		}
+		seen[line.TargetID] = struct{}{}
+		total = total.Add(line.Value)
+	}
+	if method == AllocationMethodPercentage && total.GreaterThan(decimal.NewFromInt(100)) {
+		return ErrAllocationPercentageExceeded(total.String())
+	}
+	return nil
+}
+
+// computeAllocations turns a rule into allocation rows for expense. Amounts are rounded to the
+// currency's minor units; for percentage rules the rounding remainder goes to the largest share,
+// so a 100% split always adds up to the expense amount.
+func computeAllocations(expense *Expense, rule *ExpenseAllocationRule) ([]*ExpenseAllocation, error) {
+	if err := validateAllocationLines(rule.Method, rule.Lines); err != nil {
+		return nil, err
+	}
+	ruleID := sql.NullString{}
+	if rule.ID != "" {
+		ruleID = sql.NullString{String: rule.ID, Valid: true}
+	}
+	allocations := make([]*ExpenseAllocation, len(rule.Lines))
+	total := decimal.Zero
+	for i, line := range rule.Lines {
+		allocations[i] = &ExpenseAllocation{
+			BusinessID: expense.BusinessID,
+			ExpenseID:  expense.ID,
+			RuleID:     ruleID,
+			TargetType: rule.TargetType,
+			TargetID:   line.TargetID,
+			Amount:     line.Value,
+			Currency:   expense.Currency,
+		}
+		total = total.Add(line.Value)
+	}
+
+	hundred := decimal.NewFromInt(100)
+	switch rule.Method {
+	case AllocationMethodPercentage:
+		target := money.Round(expense.Amount.Mul(total).Div(hundred), expense.Currency)
+		distributeRounded(allocations, target, expense.Currency, func(a *ExpenseAllocation) decimal.Decimal {
+			return expense.Amount.Mul(a.Amount).Div(hundred)
+		})
+	default:
+		for _, a := range allocations {
+			a.Amount = money.Round(a.Amount, expense.Currency)
+		}
+		if total.GreaterThan(expense.Amount) {
+			return nil, ErrAllocationAmountExceeded(total.String(), expense.Amount.String())
+		}
+	}
+	return allocations, nil
+}
+
+// distributeRounded sets each allocation to its rounded exact share and moves the rounding
+// remainder onto the largest allocation so the rounded amounts add up to target.
+func distributeRounded(allocations []*ExpenseAllocation, target decimal.Decimal, currency string, share func(*ExpenseAllocation) decimal.Decimal) {
+	if len(allocations) == 0 {
+		return
+	}
+	sum := decimal.Zero
+	largest := 0
+	for i, a := range allocations {
+		a.Amount = money.Round(share(a), currency)
+		sum = sum.Add(a.Amount)
+		if a.Amount.GreaterThan(allocations[largest].Amount) {
+			largest = i
+		}
+	}
+	allocations[largest].Amount = allocations[largest].Amount.Add(target.Sub(sum))
+}
+
 func (s *Service) GetRecurringExpenseByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*RecurringExpense, error) {
 	return s.storage.recurringExpense.FindOne(ctx,
 		s.storage.recurringExpense.ScopeID(id),
@@ -570,10 +1104,6 @@ func (s *Service) CountRecurringExpenses(ctx context.Context, actor *account.Use
 }
 
 func (s *Service) CreateRecurringExpense(ctx context.Context, actor *account.User, biz *business.Business, req *CreateRecurringExpenseRequest) (*RecurringExpense, error) {
-	if !req.Amount.GreaterThan(decimal.Zero) {
-		return nil, ErrRecurringExpenseInvalidAmount()
-	}
-
 	var recurringExpense *RecurringExpense
 	if err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
 		recurringExpense = &RecurringExpense{
@@ -588,11 +1118,18 @@ func (s *Service) CreateRecurringExpense(ctx context.Context, actor *account.Use
 		if req.RecurringEndDate != nil {
 			recurringExpense.RecurringEndDate = transformer.ToNullTime(req.RecurringEndDate.Time)
 		}
+		// the backfill records every occurrence before now; without it the scheduler starts today
+		now := time.Now()
+		if req.AutoCreateHistoricalExpenses {
+			recurringExpense.NextRecurringDate = nextRecurrenceFrom(recurringExpense, now)
+		} else {
+			recurringExpense.NextRecurringDate = nextRecurrenceFrom(recurringExpense, recurringExpenseDate(now))
+		}
 		if err := s.storage.recurringExpense.CreateOne(tctx, recurringExpense); err != nil {
 			return err
 		}
 		if req.AutoCreateHistoricalExpenses {
-			err := s.backfillPastOccurrencesForCreate(tctx, biz, recurringExpense, time.Now())
+			err := s.backfillPastOccurrencesForCreate(tctx, actor, biz, recurringExpense, now)
 			if err != nil {
 				return err
 			}
@@ -617,9 +1154,6 @@ func (s *Service) UpdateRecurringExpense(ctx context.Context, actor *account.Use
 		recurringExpense.RecurringStartDate = req.RecurringStartDate.Time
 	}
 	if !req.Amount.IsZero() {
-		if !req.Amount.GreaterThan(decimal.Zero) {
-			return nil, ErrRecurringExpenseInvalidAmount()
-		}
 		recurringExpense.Amount = req.Amount
 	}
 	if req.Category != "" {
@@ -635,7 +1169,7 @@ func (s *Service) UpdateRecurringExpense(ctx context.Context, actor *account.Use
 	return recurringExpense, nil
 }
 
-func (s *Service) backfillPastOccurrencesForCreate(ctx context.Context, biz *business.Business, re *RecurringExpense, today time.Time) error {
+func (s *Service) backfillPastOccurrencesForCreate(ctx context.Context, actor *account.User, biz *business.Business, re *RecurringExpense, today time.Time) error {
 	current := re.RecurringStartDate
 	expenses := make([]*Expense, 0)
 	for current.Before(today) {
@@ -651,7 +1185,20 @@ func (s *Service) backfillPastOccurrencesForCreate(ctx context.Context, biz *bus
 		})
 		current = re.Frequency.GetNextRecurrenceDate(current)
 	}
-	return s.storage.expense.CreateMany(ctx, expenses)
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.expense.CreateMany(tctx, expenses); err != nil {
+			return err
+		}
+		for _, e := range expenses {
+			if err := s.recordAuditEvent(tctx, actor, biz.ID, AuditRecordExpense, e.ID, nil, expenseAuditFields(e)); err != nil {
+				return err
+			}
+			if err := s.postJournalEntry(tctx, expenseLedgerPosting(e)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (s *Service) DeleteRecurringExpense(ctx context.Context, actor *account.User, biz *business.Business, id string) error {
@@ -679,6 +1226,10 @@ func (s *Service) UpdateRecurringExpenseStatus(ctx context.Context, actor *accou
 	if err := sm.TransitionTo(newStatus); err != nil {
 		return nil, err
 	}
+	// occurrences missed while not active are skipped rather than caught up on resume
+	if today := recurringExpenseDate(time.Now()); newStatus == RecurringExpenseStatusActive && recurringExpense.NextRecurringDate.Before(today) {
+		recurringExpense.NextRecurringDate = nextRecurrenceFrom(recurringExpense, today)
+	}
 	if err := s.storage.recurringExpense.UpdateOne(ctx, sm.RecurringExpense()); err != nil {
 		return nil, err
 	}
@@ -740,6 +1291,12 @@ func (s *Service) CreateNewRecurringExpenseOccurrence(ctx context.Context, actor
 		if err := s.storage.expense.CreateOne(ctx, expense); err != nil {
 			return err
 		}
+		if err := s.recordAuditEvent(ctx, actor, business.ID, AuditRecordExpense, expense.ID, nil, expenseAuditFields(expense)); err != nil {
+			return err
+		}
+		if err := s.postJournalEntry(ctx, expenseLedgerPosting(expense)); err != nil {
+			return err
+		}
 		// update recurring expense next occurrence date
 		recurringExpense.NextRecurringDate = recurringExpense.Frequency.GetNextRecurrenceDate(occurrenceDate)
 		return s.storage.recurringExpense.UpdateOne(ctx, recurringExpense)
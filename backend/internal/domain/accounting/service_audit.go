@@ -0,0 +1,205 @@
+package accounting
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// recordAuditEvent appends an event to the change history of a record, given its fields
+// before and after the change: nil before records its creation, nil after its deletion.
+// An update that changes no field records nothing. Call it with the context of the
+// transaction applying the change, so the event is stored only if the change is. A nil
+// actor records a change made by a background job.
+func (s *Service) recordAuditEvent(ctx context.Context, actor *account.User, businessID string, recordType AuditRecordType, recordID string, before, after []AuditFieldChange) error {
+	ev := &AuditEvent{
+		BusinessID: businessID,
+		RecordType: recordType,
+		RecordID:   recordID,
+	}
+	switch {
+	case before == nil:
+		ev.Type = AuditEventCreated
+		ev.Changes = diffAuditFields(nil, after)
+	case after == nil:
+		ev.Type = AuditEventDeleted
+		for _, f := range before {
+			if f.To != nil {
+				ev.Changes = append(ev.Changes, AuditFieldChange{Field: f.Field, From: f.To})
+			}
+		}
+	default:
+		ev.Type = AuditEventUpdated
+		ev.Changes = diffAuditFields(before, after)
+		if len(ev.Changes) == 0 {
+			return nil
+		}
+	}
+	if actor != nil {
+		ev.ActorID = transformer.ToNullString(actor.ID)
+	}
+	return s.storage.auditEvent.CreateOne(ctx, ev)
+}
+
+// ListRecordHistory returns the change history of an asset, expense, investment or
+// withdrawal, oldest first. The history of a deleted record stays available.
+func (s *Service) ListRecordHistory(ctx context.Context, actor *account.User, biz *business.Business, recordType AuditRecordType, recordID string) ([]*AuditEvent, error) {
+	var err error
+	switch recordType {
+	case AuditRecordAsset:
+		_, err = s.GetAssetByID(ctx, actor, biz, recordID)
+	case AuditRecordExpense:
+		_, err = s.GetExpenseByID(ctx, actor, biz, recordID)
+	case AuditRecordInvestment:
+		_, err = s.GetInvestmentByID(ctx, actor, biz, recordID)
+	case AuditRecordWithdrawal:
+		_, err = s.GetWithdrawalByID(ctx, actor, biz, recordID)
+	}
+	if err != nil && !database.IsRecordNotFound(err) {
+		return nil, err
+	}
+	scopes := []func(db *gorm.DB) *gorm.DB{
+		s.storage.auditEvent.ScopeEquals(AuditEventSchema.RecordType, recordType),
+		s.storage.auditEvent.ScopeEquals(AuditEventSchema.RecordID, recordID),
+		s.storage.auditEvent.WithOrderBy([]string{"created_at ASC", "id ASC"}),
+	}
+	// the history of a record of this business is listed wherever it was recorded, so an
+	// expense split off from another business keeps it; a record that is gone is only found
+	// through the events of this business
+	if err != nil {
+		scopes = append(scopes, s.storage.auditEvent.ScopeBusinessID(biz.ID))
+	}
+	events, findErr := s.storage.auditEvent.FindMany(ctx, scopes...)
+	if findErr != nil {
+		return nil, findErr
+	}
+	if err != nil && len(events) == 0 {
+		return nil, err
+	}
+	return events, nil
+}
+
+// diffAuditFields returns the fields that differ between two field snapshots.
+func diffAuditFields(before, after []AuditFieldChange) AuditFieldChanges {
+	prev := make(map[string]any, len(before))
+	for _, f := range before {
+		prev[f.Field] = f.To
+	}
+	var changes AuditFieldChanges
+	for _, f := range after {
+		from := prev[f.Field]
+		if reflect.DeepEqual(from, f.To) {
+			continue
+		}
+		changes = append(changes, AuditFieldChange{Field: f.Field, From: from, To: f.To})
+	}
+	return changes
+}
+
+// assetAuditFields returns the fields of an asset as they appear in its history.
+func assetAuditFields(a *Asset) []AuditFieldChange {
+	return []AuditFieldChange{
+		{Field: "name", To: a.Name},
+		{Field: "type", To: string(a.Type)},
+		{Field: "value", To: a.Value.String()},
+		{Field: "currency", To: a.Currency},
+		{Field: "purchasedAt", To: auditDate(a.PurchasedAt)},
+		{Field: "note", To: auditString(a.Note)},
+	}
+}
+
+// investmentAuditFields returns the fields of an investment as they appear in its history.
+func investmentAuditFields(inv *Investment) []AuditFieldChange {
+	return []AuditFieldChange{
+		{Field: "investorId", To: inv.InvestorID},
+		{Field: "amount", To: inv.Amount.String()},
+		{Field: "currency", To: inv.Currency},
+		{Field: "investedAt", To: auditTime(inv.InvestedAt)},
+		{Field: "note", To: auditString(inv.Note)},
+	}
+}
+
+// withdrawalAuditFields returns the fields of a withdrawal as they appear in its history.
+func withdrawalAuditFields(w *Withdrawal) []AuditFieldChange {
+	return []AuditFieldChange{
+		{Field: "withdrawerId", To: w.WithdrawerID},
+		{Field: "amount", To: w.Amount.String()},
+		{Field: "currency", To: w.Currency},
+		{Field: "withdrawnAt", To: auditTime(w.WithdrawnAt)},
+		{Field: "note", To: auditString(w.Note)},
+		{Field: "distributedFrom", To: auditNullDate(w.DistributedFrom)},
+		{Field: "distributedTo", To: auditNullDate(w.DistributedTo)},
+	}
+}
+
+// expenseAuditFields returns the fields of an expense as they appear in its history.
+func expenseAuditFields(exp *Expense) []AuditFieldChange {
+	return []AuditFieldChange{
+		{Field: "businessId", To: exp.BusinessID},
+		{Field: "category", To: string(exp.Category)},
+		{Field: "type", To: string(exp.Type)},
+		{Field: "amount", To: exp.Amount.String()},
+		{Field: "vat", To: exp.VAT.String()},
+		{Field: "currency", To: exp.Currency},
+		{Field: "originalAmount", To: auditNullDecimal(exp.OriginalAmount)},
+		{Field: "originalVat", To: auditNullDecimal(exp.OriginalVAT)},
+		{Field: "originalCurrency", To: auditNullString(exp.OriginalCurrency)},
+		{Field: "exchangeRate", To: auditNullDecimal(exp.ExchangeRate)},
+		{Field: "exchangeRateSource", To: auditString(string(exp.ExchangeRateSource))},
+		{Field: "occurredOn", To: auditDate(exp.OccurredOn)},
+		{Field: "note", To: auditNullString(exp.Note)},
+		{Field: "orderId", To: auditNullString(exp.OrderID)},
+		{Field: "recurringExpenseId", To: auditNullString(exp.RecurringExpenseID)},
+		{Field: "splitId", To: auditNullString(exp.SplitID)},
+	}
+}
+
+func auditString(v string) any {
+	if v == "" {
+		return nil
+	}
+	return v
+}
+
+func auditNullString(v sql.NullString) any {
+	if !v.Valid {
+		return nil
+	}
+	return auditString(v.String)
+}
+
+func auditNullDecimal(v decimal.NullDecimal) any {
+	if !v.Valid {
+		return nil
+	}
+	return v.Decimal.String()
+}
+
+func auditDate(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format("2006-01-02")
+}
+
+func auditNullDate(t sql.NullTime) any {
+	if !t.Valid {
+		return nil
+	}
+	return auditDate(t.Time)
+}
+
+func auditTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,430 @@
+package accounting
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/asset"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/ocr"
+	"github.com/abdelrahman146/kyora/internal/platform/types/date"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/throttle"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// ReceiptStore stores receipt files as uploaded assets of the business.
+type ReceiptStore interface {
+	StoreContent(ctx context.Context, biz *business.Business, createdByUserID, fileName, contentType string, data []byte) (*asset.Asset, error)
+}
+
+// SetReceiptIntake wires receipt storage, the OCR provider (nil disables extraction) and the
+// business lookup used by the expense inbox.
+func (s *Service) SetReceiptIntake(receipts ReceiptStore, extractor ocr.Provider, businesses *business.Service) {
+	s.receipts = receipts
+	s.ocr = extractor
+	s.businesses = businesses
+}
+
+// IntakeReceipt stores a receipt and creates a pending expense draft prefilled with what OCR
+// read off it. Extraction failures are recorded on the draft rather than failing intake, so
+// the seller can still fill the draft in by hand.
+func (s *Service) IntakeReceipt(ctx context.Context, biz *business.Business, createdByUserID string, source ExpenseDraftSource, senderEmail, fileName, contentType string, data []byte) (*ExpenseDraft, error) {
+	if s.receipts == nil {
+		return nil, ErrReceiptIntakeUnavailable()
+	}
+	receipt, err := s.receipts.StoreContent(ctx, biz, createdByUserID, fileName, contentType, data)
+	if err != nil {
+		return nil, err
+	}
+	draft := &ExpenseDraft{
+		BusinessID:     biz.ID,
+		Status:         ExpenseDraftStatusPending,
+		Source:         source,
+		SenderEmail:    senderEmail,
+		ReceiptAssetID: receipt.ID,
+		ReceiptURL:     receipt.CDNURL,
+		Currency:       biz.Currency,
+		Category:       ExpenseCategoryOther,
+	}
+	if draft.ReceiptURL == "" {
+		draft.ReceiptURL = receipt.PublicURL
+	}
+	if s.ocr != nil {
+		extracted, err := s.ocr.ExtractReceipt(ctx, data, contentType)
+		if err != nil {
+			logger.FromContext(ctx).Warn("receipt extraction failed", "businessId", biz.ID, "assetId", receipt.ID, "error", err)
+			draft.ExtractionError = err.Error()
+		} else {
+			draft.Amount = extracted.Amount
+			draft.Vendor = extracted.Vendor
+			draft.RawText = extracted.RawText
+			draft.Category = SuggestExpenseCategory(extracted.Vendor, extracted.RawText)
+			if extracted.Currency != "" {
+				draft.Currency = extracted.Currency
+			}
+			if extracted.Date != nil {
+				draft.OccurredOn = sql.NullTime{Time: *extracted.Date, Valid: true}
+			}
+		}
+	}
+	if err := s.storage.expenseDraft.CreateOne(ctx, draft); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// receiptCategoryKeywords maps words found in a receipt's vendor or text to the expense
+// category they suggest. The first category with a match wins.
+var receiptCategoryKeywords = []struct {
+	category ExpenseCategory
+	keywords []string
+}{
+	{ExpenseCategoryShipping, []string{"aramex", "dhl", "fedex", "ups", "smsa", "courier", "shipping", "postage", "royal mail"}},
+	{ExpenseCategoryMarketing, []string{"meta ads", "facebook", "instagram", "google ads", "tiktok", "snapchat", "advertis", "marketing"}},
+	{ExpenseCategorySoftware, []string{"shopify", "subscription", "software", "saas", "adobe", "canva", "google workspace", "microsoft", "zoom", "notion"}},
+	{ExpenseCategoryUtilities, []string{"electricity", "water", "dewa", "sewa", "etisalat", "du ", "stc", "internet", "utility", "telecom"}},
+	{ExpenseCategoryRent, []string{"rent", "lease", "tenancy"}},
+	{ExpenseCategoryTravel, []string{"airline", "airways", "flight", "hotel", "taxi", "uber", "careem", "fuel", "petrol", "parking"}},
+	{ExpenseCategoryOffice, []string{"stationery", "office", "printer", "ikea"}},
+	{ExpenseCategorySupplies, []string{"packaging", "supplies", "wholesale", "boxes", "labels"}},
+	{ExpenseCategoryEquipment, []string{"equipment", "laptop", "camera", "electronics"}},
+	{ExpenseCategoryMaintenance, []string{"repair", "maintenance", "cleaning"}},
+	{ExpenseCategoryInsurance, []string{"insurance", "takaful"}},
+	{ExpenseCategoryLegal, []string{"legal", "lawyer", "notary", "trade license"}},
+	{ExpenseCategoryConsulting, []string{"consulting", "consultancy", "freelance"}},
+	{ExpenseCategoryTraining, []string{"course", "training", "workshop", "udemy"}},
+}
+
+// SuggestExpenseCategory guesses the category of a receipt from its vendor and text, falling
+// back to other.
+func SuggestExpenseCategory(vendor, text string) ExpenseCategory {
+	haystack := strings.ToLower(vendor + "\n" + text)
+	for _, entry := range receiptCategoryKeywords {
+		for _, keyword := range entry.keywords {
+			if strings.Contains(haystack, keyword) {
+				return entry.category
+			}
+		}
+	}
+	return ExpenseCategoryOther
+}
+
+// ScanReceipt reads a receipt with OCR and returns the one-time expense it describes, for the
+// seller to review before creating it. Nothing is stored. Each business can scan up to 100
+// receipts an hour.
+func (s *Service) ScanReceipt(ctx context.Context, actor *account.User, biz *business.Business, contentType string, data []byte) (*ExpenseScan, error) {
+	if s.ocr == nil {
+		return nil, ErrReceiptScanUnavailable()
+	}
+	if !strings.HasPrefix(contentType, "image/") && contentType != "application/pdf" {
+		return nil, ErrReceiptScanUnsupportedType(contentType)
+	}
+	if !throttle.Allow(s.storage.cache, fmt.Sprintf("accounting:%s:receipt_scan", biz.ID), time.Hour, 100, 0) {
+		return nil, ErrReceiptScanRateLimited()
+	}
+	extracted, err := s.ocr.ExtractReceipt(ctx, data, contentType)
+	if err != nil {
+		return nil, ErrReceiptScanFailed(err)
+	}
+	scan := &ExpenseScan{
+		Expense: CreateExpenseRequest{
+			Amount:   extracted.Amount,
+			VAT:      extracted.VAT,
+			Currency: biz.Currency,
+			Category: SuggestExpenseCategory(extracted.Vendor, extracted.RawText),
+			Type:     ExpenseTypeOneTime,
+			Note:     extracted.Vendor,
+		},
+		Vendor:   extracted.Vendor,
+		Currency: biz.Currency,
+		RawText:  extracted.RawText,
+	}
+	if extracted.Currency != "" {
+		scan.Currency = extracted.Currency
+		scan.Expense.Currency = extracted.Currency
+	}
+	if extracted.Date != nil {
+		scan.Expense.OccurredOn = &date.Date{Time: *extracted.Date}
+	}
+	return scan, nil
+}
+
+// ListExpenseDrafts returns a page of the business' expense drafts, newest first unless
+// ordered otherwise. An empty status returns drafts in every status.
+func (s *Service) ListExpenseDrafts(ctx context.Context, actor *account.User, biz *business.Business, req *list.ListRequest, status ExpenseDraftStatus) ([]*ExpenseDraft, int64, error) {
+	scopes := []func(*gorm.DB) *gorm.DB{s.storage.expenseDraft.ScopeBusinessID(biz.ID)}
+	if status != "" {
+		scopes = append(scopes, s.storage.expenseDraft.ScopeEquals(ExpenseDraftSchema.Status, status))
+	}
+	total, err := s.storage.expenseDraft.Count(ctx, scopes...)
+	if err != nil {
+		return nil, 0, err
+	}
+	drafts, err := s.storage.expenseDraft.FindMany(ctx, append(scopes,
+		s.storage.expenseDraft.WithPagination(req.Offset(), req.Limit()),
+		s.storage.expenseDraft.WithOrderBy(req.ParsedOrderByWithDefault(ExpenseDraftSchema, []string{"-createdAt"})),
+	)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return drafts, total, nil
+}
+
+func (s *Service) GetExpenseDraftByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*ExpenseDraft, error) {
+	draft, err := s.storage.expenseDraft.FindOne(ctx,
+		s.storage.expenseDraft.ScopeID(id),
+		s.storage.expenseDraft.ScopeBusinessID(biz.ID),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrExpenseDraftNotFound(err)
+		}
+		return nil, err
+	}
+	return draft, nil
+}
+
+func (s *Service) getPendingExpenseDraft(ctx context.Context, actor *account.User, biz *business.Business, id string) (*ExpenseDraft, error) {
+	draft, err := s.GetExpenseDraftByID(ctx, actor, biz, id)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Status != ExpenseDraftStatusPending {
+		return nil, ErrExpenseDraftNotPending(draft.Status)
+	}
+	return draft, nil
+}
+
+// UpdateExpenseDraft corrects the fields of a pending draft.
+func (s *Service) UpdateExpenseDraft(ctx context.Context, actor *account.User, biz *business.Business, id string, req *UpdateExpenseDraftRequest) (*ExpenseDraft, error) {
+	draft, err := s.getPendingExpenseDraft(ctx, actor, biz, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Amount != nil {
+		if req.Amount.IsNegative() {
+			return nil, ErrExpenseInvalidAmount()
+		}
+		draft.Amount = *req.Amount
+	}
+	if req.Currency != "" {
+		draft.Currency = strings.ToUpper(req.Currency)
+	}
+	if req.Category != "" {
+		draft.Category = req.Category
+	}
+	if req.Vendor != nil {
+		draft.Vendor = strings.TrimSpace(*req.Vendor)
+	}
+	if req.Note != nil {
+		draft.Note = transformer.ToNullString(strings.TrimSpace(*req.Note))
+	}
+	if req.OccurredOn != nil {
+		draft.OccurredOn = sql.NullTime{Time: req.OccurredOn.Time, Valid: true}
+	}
+	if err := s.storage.expenseDraft.UpdateOne(ctx, draft); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// ConfirmExpenseDraft records the one-time expense described by a pending draft, converted
+// from the draft currency when it is not the business currency, and links it to the draft. The vendor becomes the expense note unless
+// the draft has one.
+func (s *Service) ConfirmExpenseDraft(ctx context.Context, actor *account.User, biz *business.Business, id string) (*ExpenseDraft, *Expense, error) {
+	draft, err := s.getPendingExpenseDraft(ctx, actor, biz, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !draft.Amount.IsPositive() {
+		return nil, nil, ErrExpenseDraftIncomplete()
+	}
+	note := draft.Note.String
+	if note == "" {
+		note = draft.Vendor
+	}
+	req := &CreateExpenseRequest{
+		Amount:   draft.Amount,
+		Currency: draft.Currency,
+		Category: draft.Category,
+		Type:     ExpenseTypeOneTime,
+		Note:     note,
+	}
+	if draft.OccurredOn.Valid {
+		req.OccurredOn = &date.Date{Time: draft.OccurredOn.Time}
+	}
+	var expense *Expense
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		expense, err = s.CreateExpense(tctx, actor, biz, req)
+		if err != nil {
+			return err
+		}
+		draft.Status = ExpenseDraftStatusConfirmed
+		draft.ExpenseID = transformer.ToNullString(expense.ID)
+		return s.storage.expenseDraft.UpdateOne(tctx, draft)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return draft, expense, nil
+}
+
+// DiscardExpenseDraft marks a pending draft as discarded. The receipt asset is kept.
+func (s *Service) DiscardExpenseDraft(ctx context.Context, actor *account.User, biz *business.Business, id string) (*ExpenseDraft, error) {
+	draft, err := s.getPendingExpenseDraft(ctx, actor, biz, id)
+	if err != nil {
+		return nil, err
+	}
+	draft.Status = ExpenseDraftStatusDiscarded
+	if err := s.storage.expenseDraft.UpdateOne(ctx, draft); err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+func (s *Service) GetExpenseInbox(ctx context.Context, actor *account.User, biz *business.Business) (*ExpenseInbox, error) {
+	inbox, err := s.storage.expenseInbox.FindOne(ctx, s.storage.expenseInbox.ScopeBusinessID(biz.ID))
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrExpenseInboxNotFound(err)
+		}
+		return nil, err
+	}
+	return inbox, nil
+}
+
+// EnableExpenseInbox gives the business an expense inbox, or returns the existing one.
+func (s *Service) EnableExpenseInbox(ctx context.Context, actor *account.User, biz *business.Business) (*ExpenseInbox, error) {
+	if strings.TrimSpace(viper.GetString(config.AccountingExpenseInboxDomain)) == "" {
+		return nil, ErrExpenseInboxUnavailable()
+	}
+	inbox, err := s.GetExpenseInbox(ctx, actor, biz)
+	if err == nil {
+		return inbox, nil
+	}
+	if !database.IsRecordNotFound(err) {
+		return nil, err
+	}
+	inbox = &ExpenseInbox{
+		BusinessID:      biz.ID,
+		Token:           newExpenseInboxToken(),
+		CreatedByUserID: actor.ID,
+	}
+	if err := s.storage.expenseInbox.CreateOne(ctx, inbox); err != nil {
+		if database.IsUniqueViolation(err) {
+			return s.GetExpenseInbox(ctx, actor, biz)
+		}
+		return nil, err
+	}
+	return inbox, nil
+}
+
+// RotateExpenseInbox replaces the inbox address, so mail to the previous one is dropped.
+func (s *Service) RotateExpenseInbox(ctx context.Context, actor *account.User, biz *business.Business) (*ExpenseInbox, error) {
+	inbox, err := s.GetExpenseInbox(ctx, actor, biz)
+	if err != nil {
+		return nil, err
+	}
+	inbox.Token = newExpenseInboxToken()
+	inbox.CreatedByUserID = actor.ID
+	if err := s.storage.expenseInbox.UpdateOne(ctx, inbox); err != nil {
+		return nil, err
+	}
+	return inbox, nil
+}
+
+func (s *Service) DisableExpenseInbox(ctx context.Context, actor *account.User, biz *business.Business) error {
+	inbox, err := s.GetExpenseInbox(ctx, actor, biz)
+	if err != nil {
+		return err
+	}
+	return s.storage.expenseInbox.DeleteOne(ctx, inbox)
+}
+
+// ReceiveInboundReceiptEmail turns the attachments of an email sent to an expense inbox into
+// expense drafts. Attachments that are not allowed receipt files are skipped. Each inbox
+// accepts up to 50 receipts an hour.
+func (s *Service) ReceiveInboundReceiptEmail(ctx context.Context, in *InboundReceiptEmail) ([]*ExpenseDraft, error) {
+	inbox, err := s.findInboxByRecipients(ctx, in.To)
+	if err != nil {
+		return nil, err
+	}
+	if s.businesses == nil {
+		return nil, ErrReceiptIntakeUnavailable()
+	}
+	biz, err := s.businesses.GetBusinessByIDForJobs(ctx, inbox.BusinessID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrExpenseInboxNotFound(err)
+		}
+		return nil, err
+	}
+
+	sender := ""
+	if addr, err := mail.ParseAddress(in.From); err == nil {
+		sender = strings.ToLower(addr.Address)
+	}
+	log := logger.FromContext(ctx).With("businessId", biz.ID, "inboxId", inbox.ID)
+	drafts := make([]*ExpenseDraft, 0, len(in.Attachments))
+	for _, att := range in.Attachments {
+		if !throttle.Allow(s.storage.cache, fmt.Sprintf("accounting:%s:expense_inbox", biz.ID), time.Hour, 50, 0) {
+			return drafts, ErrExpenseInboxRateLimited()
+		}
+		data, err := base64.StdEncoding.DecodeString(att.Content)
+		if err != nil {
+			log.Warn("skipping undecodable receipt attachment", "fileName", att.FileName, "error", err)
+			continue
+		}
+		draft, err := s.IntakeReceipt(ctx, biz, inbox.CreatedByUserID, ExpenseDraftSourceEmail, sender, att.FileName, strings.ToLower(att.ContentType), data)
+		if err != nil {
+			log.Warn("skipping receipt attachment", "fileName", att.FileName, "error", err)
+			continue
+		}
+		drafts = append(drafts, draft)
+	}
+	return drafts, nil
+}
+
+// findInboxByRecipients returns the inbox addressed by the first expenses+<token> recipient.
+func (s *Service) findInboxByRecipients(ctx context.Context, recipients []string) (*ExpenseInbox, error) {
+	for _, to := range recipients {
+		addr, err := mail.ParseAddress(to)
+		if err != nil {
+			continue
+		}
+		local, _, ok := strings.Cut(strings.ToLower(addr.Address), "@")
+		if !ok {
+			continue
+		}
+		prefix, token, ok := strings.Cut(local, "+")
+		if !ok || prefix != ExpenseInboxLocalPart || token == "" {
+			continue
+		}
+		inbox, err := s.storage.expenseInbox.FindOne(ctx, s.storage.expenseInbox.ScopeEquals(ExpenseInboxSchema.Token, token))
+		if err == nil {
+			return inbox, nil
+		}
+		if !database.IsRecordNotFound(err) {
+			return nil, err
+		}
+	}
+	return nil, ErrExpenseInboxNotFound(nil)
+}
+
+func newExpenseInboxToken() string {
+	// Lowercase: mail systems do not reliably preserve the case of local parts.
+	return strings.ToLower(id.Base62(24))
+}
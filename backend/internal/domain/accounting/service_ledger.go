@@ -0,0 +1,763 @@
+package accounting
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ledgerLine is a debit or credit of a posting, against an account of the default chart.
+type ledgerLine struct {
+	Account LedgerAccountCode
+	Debit   decimal.Decimal
+	Credit  decimal.Decimal
+}
+
+func debitLine(code LedgerAccountCode, amount decimal.Decimal) ledgerLine {
+	return ledgerLine{Account: code, Debit: amount}
+}
+
+func creditLine(code LedgerAccountCode, amount decimal.Decimal) ledgerLine {
+	return ledgerLine{Account: code, Credit: amount}
+}
+
+// ledgerPosting is the journal entry to book for a source record.
+type ledgerPosting struct {
+	BusinessID  string
+	SourceType  JournalSourceType
+	SourceID    string
+	Description string
+	Currency    string
+	PostedAt    time.Time
+	Lines       []ledgerLine
+}
+
+// normalizedLines drops zero lines and moves negative amounts to the other side, so every
+// line has exactly one positive side. It fails when debits and credits do not balance.
+func (p *ledgerPosting) normalizedLines() ([]ledgerLine, error) {
+	out := make([]ledgerLine, 0, len(p.Lines))
+	debits, credits := decimal.Zero, decimal.Zero
+	for _, l := range p.Lines {
+		amount := l.Debit.Sub(l.Credit)
+		switch {
+		case amount.IsPositive():
+			out = append(out, debitLine(l.Account, amount))
+			debits = debits.Add(amount)
+		case amount.IsNegative():
+			out = append(out, creditLine(l.Account, amount.Neg()))
+			credits = credits.Add(amount.Neg())
+		}
+	}
+	if !debits.Equal(credits) {
+		return nil, fmt.Errorf("unbalanced journal entry for %s %s: debits %s, credits %s", p.SourceType, p.SourceID, debits, credits)
+	}
+	return out, nil
+}
+
+// OrderSaleInput describes a fulfilled order to book as a sale.
+type OrderSaleInput struct {
+	BusinessID  string
+	OrderID     string
+	OrderNumber string
+	OrderTotal  decimal.Decimal
+	VAT         decimal.Decimal
+	COGS        decimal.Decimal
+	Currency    string
+	FulfilledAt time.Time
+}
+
+// OrderPaymentInput describes the payment of an order to book.
+type OrderPaymentInput struct {
+	BusinessID  string
+	OrderID     string
+	OrderNumber string
+	OrderTotal  decimal.Decimal
+	Currency    string
+	PaidAt      time.Time
+}
+
+// orderLabel names an order in journal entry descriptions.
+func orderLabel(orderID, orderNumber string) string {
+	if orderNumber != "" {
+		return orderNumber
+	}
+	return orderID
+}
+
+// postedAtOr returns t, or fallback when t is not set.
+func postedAtOr(t, fallback time.Time) time.Time {
+	if t.IsZero() {
+		return fallback
+	}
+	return t
+}
+
+// saleLedgerPosting recognizes the revenue of the order, net of VAT which is owed to the tax
+// authority, against a receivable, and moves its cost out of inventory.
+func saleLedgerPosting(in OrderSaleInput) *ledgerPosting {
+	return &ledgerPosting{
+		BusinessID:  in.BusinessID,
+		SourceType:  JournalSourceOrderSale,
+		SourceID:    in.OrderID,
+		Description: "Sale of order " + orderLabel(in.OrderID, in.OrderNumber),
+		Currency:    in.Currency,
+		PostedAt:    postedAtOr(in.FulfilledAt, time.Now().UTC()),
+		Lines: []ledgerLine{
+			debitLine(LedgerAccountReceivable, in.OrderTotal),
+			creditLine(LedgerAccountSales, in.OrderTotal.Sub(in.VAT)),
+			creditLine(LedgerAccountVATPayable, in.VAT),
+			debitLine(LedgerAccountCOGS, in.COGS),
+			creditLine(LedgerAccountInventory, in.COGS),
+		},
+	}
+}
+
+// paymentLedgerPosting collects the order's receivable in cash.
+func paymentLedgerPosting(in OrderPaymentInput) *ledgerPosting {
+	return &ledgerPosting{
+		BusinessID:  in.BusinessID,
+		SourceType:  JournalSourceOrderPayment,
+		SourceID:    in.OrderID,
+		Description: "Payment of order " + orderLabel(in.OrderID, in.OrderNumber),
+		Currency:    in.Currency,
+		PostedAt:    postedAtOr(in.PaidAt, time.Now().UTC()),
+		Lines: []ledgerLine{
+			debitLine(LedgerAccountCash, in.OrderTotal),
+			creditLine(LedgerAccountReceivable, in.OrderTotal),
+		},
+	}
+}
+
+// expenseLedgerPosting books an expense paid in cash. Its recoverable input VAT is reclaimed
+// against the VAT owed instead of being expensed.
+func expenseLedgerPosting(e *Expense) *ledgerPosting {
+	account := LedgerAccountOperatingExpenses
+	if e.Category == ExpenseCategoryTransactionFee {
+		account = LedgerAccountTransactionFeeCost
+	}
+	description := fmt.Sprintf("Expense (%s)", e.Category)
+	if e.Note.Valid && e.Note.String != "" {
+		description = fmt.Sprintf("%s: %s", description, e.Note.String)
+	}
+	return &ledgerPosting{
+		BusinessID:  e.BusinessID,
+		SourceType:  JournalSourceExpense,
+		SourceID:    e.ID,
+		Description: description,
+		Currency:    e.Currency,
+		PostedAt:    postedAtOr(e.OccurredOn, e.CreatedAt),
+		Lines: []ledgerLine{
+			debitLine(account, e.Amount.Sub(e.VAT)),
+			debitLine(LedgerAccountVATPayable, e.VAT),
+			creditLine(LedgerAccountCash, e.Amount),
+		},
+	}
+}
+
+func investmentLedgerPosting(i *Investment) *ledgerPosting {
+	return &ledgerPosting{
+		BusinessID:  i.BusinessID,
+		SourceType:  JournalSourceInvestment,
+		SourceID:    i.ID,
+		Description: "Owner investment",
+		Currency:    i.Currency,
+		PostedAt:    postedAtOr(i.InvestedAt, i.CreatedAt),
+		Lines: []ledgerLine{
+			debitLine(LedgerAccountCash, i.Amount),
+			creditLine(LedgerAccountOwnerCapital, i.Amount),
+		},
+	}
+}
+
+func withdrawalLedgerPosting(w *Withdrawal) *ledgerPosting {
+	return &ledgerPosting{
+		BusinessID:  w.BusinessID,
+		SourceType:  JournalSourceWithdrawal,
+		SourceID:    w.ID,
+		Description: "Owner withdrawal",
+		Currency:    w.Currency,
+		PostedAt:    postedAtOr(w.WithdrawnAt, w.CreatedAt),
+		Lines: []ledgerLine{
+			debitLine(LedgerAccountOwnerDrawings, w.Amount),
+			creditLine(LedgerAccountCash, w.Amount),
+		},
+	}
+}
+
+// ledgerBook is an open ledger within a transaction, with the accounts of its chart.
+type ledgerBook struct {
+	accounts map[LedgerAccountCode]*LedgerAccount
+	codes    map[string]LedgerAccountCode
+}
+
+// openLedger locks the ledger of the business for the rest of the transaction. On first use
+// it creates the chart of accounts and posts the records that predate the ledger, so every
+// business' books start complete. It must be called within a transaction.
+func (s *Service) openLedger(ctx context.Context, businessID string) (*ledgerBook, error) {
+	ledger, err := s.storage.LockLedger(ctx, businessID)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.storage.ledgerAccount.FindMany(ctx, s.storage.ledgerAccount.ScopeBusinessID(businessID))
+	if err != nil {
+		return nil, err
+	}
+	book := &ledgerBook{
+		accounts: make(map[LedgerAccountCode]*LedgerAccount, len(DefaultChartOfAccounts)),
+		codes:    make(map[string]LedgerAccountCode, len(DefaultChartOfAccounts)),
+	}
+	for _, a := range accounts {
+		book.accounts[a.Code] = a
+	}
+	for _, def := range DefaultChartOfAccounts {
+		if _, ok := book.accounts[def.Code]; ok {
+			continue
+		}
+		a := &LedgerAccount{BusinessID: businessID, Code: def.Code, Name: def.Name, Type: def.Type}
+		if err := s.storage.ledgerAccount.CreateOne(ctx, a); err != nil {
+			return nil, err
+		}
+		book.accounts[a.Code] = a
+	}
+	for code, a := range book.accounts {
+		book.codes[a.ID] = code
+	}
+	if !ledger.BackfilledAt.Valid {
+		if err := s.backfillLedger(ctx, book, businessID); err != nil {
+			return nil, err
+		}
+		ledger.BackfilledAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+		if err := s.storage.ledger.UpdateOne(ctx, ledger); err != nil {
+			return nil, err
+		}
+	}
+	return book, nil
+}
+
+// backfillLedger posts the orders, expenses, investments, withdrawals and order reversals
+// recorded before the business' ledger was opened.
+func (s *Service) backfillLedger(ctx context.Context, book *ledgerBook, businessID string) error {
+	sales, err := s.storage.ListFulfilledOrdersForLedger(ctx, businessID)
+	if err != nil {
+		return err
+	}
+	for _, o := range sales {
+		if err := s.writeJournalEntry(ctx, book, saleLedgerPosting(OrderSaleInput{
+			BusinessID:  businessID,
+			OrderID:     o.ID,
+			OrderNumber: o.OrderNumber,
+			OrderTotal:  o.Total,
+			VAT:         o.VAT,
+			COGS:        o.COGS,
+			Currency:    o.Currency,
+			FulfilledAt: o.PostedAt,
+		})); err != nil {
+			return err
+		}
+	}
+	payments, err := s.storage.ListPaidOrdersForLedger(ctx, businessID)
+	if err != nil {
+		return err
+	}
+	for _, o := range payments {
+		if err := s.writeJournalEntry(ctx, book, paymentLedgerPosting(OrderPaymentInput{
+			BusinessID:  businessID,
+			OrderID:     o.ID,
+			OrderNumber: o.OrderNumber,
+			OrderTotal:  o.Total,
+			Currency:    o.Currency,
+			PaidAt:      o.PostedAt,
+		})); err != nil {
+			return err
+		}
+	}
+	expenses, err := s.storage.expense.FindMany(ctx, s.storage.expense.ScopeBusinessID(businessID))
+	if err != nil {
+		return err
+	}
+	for _, e := range expenses {
+		if err := s.writeJournalEntry(ctx, book, expenseLedgerPosting(e)); err != nil {
+			return err
+		}
+	}
+	investments, err := s.storage.investment.FindMany(ctx, s.storage.investment.ScopeBusinessID(businessID))
+	if err != nil {
+		return err
+	}
+	for _, i := range investments {
+		if err := s.writeJournalEntry(ctx, book, investmentLedgerPosting(i)); err != nil {
+			return err
+		}
+	}
+	withdrawals, err := s.storage.withdrawal.FindMany(ctx, s.storage.withdrawal.ScopeBusinessID(businessID))
+	if err != nil {
+		return err
+	}
+	for _, w := range withdrawals {
+		if err := s.writeJournalEntry(ctx, book, withdrawalLedgerPosting(w)); err != nil {
+			return err
+		}
+	}
+	reversals, err := s.storage.orderReversal.FindMany(ctx, s.storage.orderReversal.ScopeBusinessID(businessID))
+	if err != nil {
+		return err
+	}
+	for _, rev := range reversals {
+		if err := s.writeOrderReversalEntry(ctx, book, rev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeJournalEntry replaces the journal entry of the posting's source with the posting. A
+// posting without any non-zero line only removes the previous entry.
+func (s *Service) writeJournalEntry(ctx context.Context, book *ledgerBook, p *ledgerPosting) error {
+	lines, err := p.normalizedLines()
+	if err != nil {
+		return err
+	}
+	if err := s.storage.DeleteJournalEntry(ctx, p.BusinessID, p.SourceType, p.SourceID); err != nil {
+		return err
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	entry := &JournalEntry{
+		BusinessID:  p.BusinessID,
+		SourceType:  p.SourceType,
+		SourceID:    p.SourceID,
+		Description: p.Description,
+		Currency:    p.Currency,
+		PostedAt:    p.PostedAt,
+	}
+	if err := s.storage.journalEntry.CreateOne(ctx, entry); err != nil {
+		return err
+	}
+	journalLines := make([]*JournalLine, 0, len(lines))
+	for _, l := range lines {
+		account, ok := book.accounts[l.Account]
+		if !ok {
+			return fmt.Errorf("ledger account %s is missing", l.Account)
+		}
+		journalLines = append(journalLines, &JournalLine{
+			BusinessID: p.BusinessID,
+			EntryID:    entry.ID,
+			AccountID:  account.ID,
+			Debit:      l.Debit,
+			Credit:     l.Credit,
+		})
+	}
+	return s.storage.journalLine.CreateMany(ctx, journalLines)
+}
+
+// postJournalEntry books a posting, replacing any earlier entry of the same source.
+func (s *Service) postJournalEntry(ctx context.Context, p *ledgerPosting) error {
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		book, err := s.openLedger(tctx, p.BusinessID)
+		if err != nil {
+			return err
+		}
+		return s.writeJournalEntry(tctx, book, p)
+	})
+}
+
+// removeJournalEntry takes the entry of a deleted source out of the books.
+func (s *Service) removeJournalEntry(ctx context.Context, businessID string, sourceType JournalSourceType, sourceID string) error {
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if _, err := s.openLedger(tctx, businessID); err != nil {
+			return err
+		}
+		return s.storage.DeleteJournalEntry(tctx, businessID, sourceType, sourceID)
+	})
+}
+
+// findOrderJournalEntry returns the entry posted from an order for the given source type, or
+// nil when there is none.
+func (s *Service) findOrderJournalEntry(ctx context.Context, businessID string, sourceType JournalSourceType, orderID string) (*JournalEntry, error) {
+	entry, err := s.storage.journalEntry.FindOne(ctx,
+		s.storage.journalEntry.ScopeBusinessID(businessID),
+		s.storage.journalEntry.ScopeEquals(JournalEntrySchema.SourceType, sourceType),
+		s.storage.journalEntry.ScopeEquals(JournalEntrySchema.SourceID, orderID),
+		s.storage.journalEntry.WithPreload(JournalEntryLinesStruct),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+// mirrorLines returns the lines of entry on the given accounts with debits and credits swapped.
+func (b *ledgerBook) mirrorLines(entry *JournalEntry, codes ...LedgerAccountCode) []ledgerLine {
+	if entry == nil {
+		return nil
+	}
+	var out []ledgerLine
+	for _, l := range entry.Lines {
+		code := b.codes[l.AccountID]
+		for _, c := range codes {
+			if code == c {
+				out = append(out, ledgerLine{Account: code, Debit: l.Credit, Credit: l.Debit})
+				break
+			}
+		}
+	}
+	return out
+}
+
+// writeOrderReversalEntry books a return or refund against what the ledger recorded for the
+// order: it takes back the booked sale, returns the goods to inventory when they were
+// restocked, and pays the booked payment and transaction fee back when it was refunded.
+// Whatever was never booked is not reversed.
+func (s *Service) writeOrderReversalEntry(ctx context.Context, book *ledgerBook, rev *OrderReversal) error {
+	sale, err := s.findOrderJournalEntry(ctx, rev.BusinessID, JournalSourceOrderSale, rev.OrderID)
+	if err != nil {
+		return err
+	}
+	lines := book.mirrorLines(sale, LedgerAccountReceivable, LedgerAccountSales, LedgerAccountVATPayable)
+	if rev.Restocked {
+		lines = append(lines, book.mirrorLines(sale, LedgerAccountCOGS, LedgerAccountInventory)...)
+	}
+	if rev.Refunded {
+		payment, err := s.findOrderJournalEntry(ctx, rev.BusinessID, JournalSourceOrderPayment, rev.OrderID)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, book.mirrorLines(payment, LedgerAccountCash, LedgerAccountReceivable)...)
+		lines = append(lines,
+			debitLine(LedgerAccountCash, rev.TransactionFee),
+			creditLine(LedgerAccountTransactionFeeCost, rev.TransactionFee),
+		)
+	}
+	return s.writeJournalEntry(ctx, book, &ledgerPosting{
+		BusinessID:  rev.BusinessID,
+		SourceType:  JournalSourceOrderReversal,
+		SourceID:    rev.OrderID,
+		Description: "Reversal of order " + rev.OrderID,
+		Currency:    rev.Currency,
+		PostedAt:    postedAtOr(rev.OccurredOn, rev.CreatedAt),
+		Lines:       lines,
+	})
+}
+
+// rewriteOrderReversalEntry rebooks the order's reversal, if it has one, after its sale or
+// payment was booked. Events are handled asynchronously, so a return can be recorded before
+// the sale it reverses.
+func (s *Service) rewriteOrderReversalEntry(ctx context.Context, book *ledgerBook, businessID, orderID string) error {
+	rev, err := s.storage.orderReversal.FindOne(ctx,
+		s.storage.orderReversal.ScopeBusinessID(businessID),
+		s.storage.orderReversal.ScopeEquals(OrderReversalSchema.OrderID, orderID),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return s.writeOrderReversalEntry(ctx, book, rev)
+}
+
+// postOrderEntry books the sale or payment of an order and rebooks its reversal.
+func (s *Service) postOrderEntry(ctx context.Context, p *ledgerPosting) error {
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		book, err := s.openLedger(tctx, p.BusinessID)
+		if err != nil {
+			return err
+		}
+		if err := s.writeJournalEntry(tctx, book, p); err != nil {
+			return err
+		}
+		return s.rewriteOrderReversalEntry(tctx, book, p.BusinessID, p.SourceID)
+	})
+}
+
+// PostOrderSale books the sale of a fulfilled order. Posting the same order again replaces
+// its entry.
+//
+// This method is intentionally internal and does not do actor permission checks.
+func (s *Service) PostOrderSale(ctx context.Context, in OrderSaleInput) error {
+	if in.BusinessID == "" || in.OrderID == "" {
+		return fmt.Errorf("businessID and orderID are required")
+	}
+	return s.postOrderEntry(ctx, saleLedgerPosting(in))
+}
+
+// PostOrderPayment books the payment of an order. Posting the same order again replaces its
+// entry.
+//
+// This method is intentionally internal and does not do actor permission checks.
+func (s *Service) PostOrderPayment(ctx context.Context, in OrderPaymentInput) error {
+	if in.BusinessID == "" || in.OrderID == "" {
+		return fmt.Errorf("businessID and orderID are required")
+	}
+	return s.postOrderEntry(ctx, paymentLedgerPosting(in))
+}
+
+// ImportedOrderInput describes a historical order imported from another store. FulfilledAt
+// and PaidAt are set when the order was fulfilled and paid there.
+type ImportedOrderInput struct {
+	BusinessID  string
+	OrderID     string
+	OrderNumber string
+	OrderTotal  decimal.Decimal
+	VAT         decimal.Decimal
+	COGS        decimal.Decimal
+	Currency    string
+	FulfilledAt *time.Time
+	PaidAt      *time.Time
+}
+
+// PostImportedOrder books the sale and payment an imported order recorded, the way the
+// ledger backfill books the orders that predate the ledger. Posting the same order again
+// replaces its entries.
+//
+// This method is intentionally internal and does not do actor permission checks.
+func (s *Service) PostImportedOrder(ctx context.Context, in ImportedOrderInput) error {
+	if in.BusinessID == "" || in.OrderID == "" {
+		return fmt.Errorf("businessID and orderID are required")
+	}
+	if in.FulfilledAt == nil && in.PaidAt == nil {
+		return nil
+	}
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		book, err := s.openLedger(tctx, in.BusinessID)
+		if err != nil {
+			return err
+		}
+		if in.FulfilledAt != nil {
+			if err := s.writeJournalEntry(tctx, book, saleLedgerPosting(OrderSaleInput{
+				BusinessID:  in.BusinessID,
+				OrderID:     in.OrderID,
+				OrderNumber: in.OrderNumber,
+				OrderTotal:  in.OrderTotal,
+				VAT:         in.VAT,
+				COGS:        in.COGS,
+				Currency:    in.Currency,
+				FulfilledAt: *in.FulfilledAt,
+			})); err != nil {
+				return err
+			}
+		}
+		if in.PaidAt != nil {
+			if err := s.writeJournalEntry(tctx, book, paymentLedgerPosting(OrderPaymentInput{
+				BusinessID:  in.BusinessID,
+				OrderID:     in.OrderID,
+				OrderNumber: in.OrderNumber,
+				OrderTotal:  in.OrderTotal,
+				Currency:    in.Currency,
+				PaidAt:      *in.PaidAt,
+			})); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListLedgerAccountBalances returns every account of the business' chart of accounts with
+// its debits and credits posted within [from, to], in chart order. A zero bound leaves that
+// side of the range open.
+func (s *Service) ListLedgerAccountBalances(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) ([]*LedgerAccountBalance, error) {
+	return s.ledgerAccountBalances(ctx, biz, false, from, to)
+}
+
+// ledgerAccountBalances returns the balances of ListLedgerAccountBalances, with the sales of
+// orders dated by their payment when cashBasis is set.
+func (s *Service) ledgerAccountBalances(ctx context.Context, biz *business.Business, cashBasis bool, from, to time.Time) ([]*LedgerAccountBalance, error) {
+	var book *ledgerBook
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		var err error
+		book, err = s.openLedger(tctx, biz.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.storage.SumJournalLinesByAccount(ctx, biz.ID, cashBasis, from, to)
+	if err != nil {
+		return nil, err
+	}
+	byAccount := make(map[string]LedgerAccountTotals, len(totals))
+	for _, t := range totals {
+		byAccount[t.AccountID] = t
+	}
+	out := make([]*LedgerAccountBalance, 0, len(DefaultChartOfAccounts))
+	for _, def := range DefaultChartOfAccounts {
+		a := book.accounts[def.Code]
+		t := byAccount[a.ID]
+		out = append(out, &LedgerAccountBalance{Account: a, Debit: t.Debit, Credit: t.Credit})
+	}
+	return out, nil
+}
+
+// ComputeTradingTotals returns the revenue and COGS recognized from orders within [from, to]
+// and the gross and net profit they leave. A zero bound leaves that side of the range open.
+// On a cash basis a sale counts when its order is paid, and not at all while it is unpaid.
+func (s *Service) ComputeTradingTotals(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) (*TradingTotals, error) {
+	balances, err := s.ledgerAccountBalances(ctx, biz, biz.CashBasis(), from, to)
+	if err != nil {
+		return nil, err
+	}
+	totals := &TradingTotals{NetProfit: ledgerNetIncome(balances)}
+	for _, b := range balances {
+		switch b.Account.Code {
+		case LedgerAccountSales:
+			totals.Revenue = b.Balance()
+		case LedgerAccountCOGS:
+			totals.COGS = b.Balance()
+		}
+	}
+	totals.GrossProfit = totals.Revenue.Sub(totals.COGS)
+	return totals, nil
+}
+
+// ledgerNetIncome is the net profit of the balances: income less expenses, COGS included.
+// Owner investments and drawings are equity and do not count.
+func ledgerNetIncome(balances []*LedgerAccountBalance) decimal.Decimal {
+	net := decimal.Zero
+	for _, b := range balances {
+		switch b.Account.Type {
+		case LedgerAccountTypeIncome:
+			net = net.Add(b.Balance())
+		case LedgerAccountTypeExpense:
+			net = net.Sub(b.Balance())
+		}
+	}
+	return net
+}
+
+// ListJournalEntriesFilter narrows the journal entries listed.
+type ListJournalEntriesFilter struct {
+	SourceType JournalSourceType
+	From       time.Time
+	To         time.Time
+}
+
+func (s *Service) journalEntryScopes(biz *business.Business, filter *ListJournalEntriesFilter) []func(db *gorm.DB) *gorm.DB {
+	scopes := []func(db *gorm.DB) *gorm.DB{
+		s.storage.journalEntry.ScopeBusinessID(biz.ID),
+	}
+	if filter != nil {
+		if filter.SourceType != "" {
+			scopes = append(scopes, s.storage.journalEntry.ScopeEquals(JournalEntrySchema.SourceType, filter.SourceType))
+		}
+		scopes = append(scopes, s.storage.journalEntry.ScopeTime(JournalEntrySchema.PostedAt, filter.From, filter.To))
+	}
+	return scopes
+}
+
+// ListJournalEntries returns a page of the business' journal entries with their lines,
+// newest first by default.
+func (s *Service) ListJournalEntries(ctx context.Context, actor *account.User, biz *business.Business, req *list.ListRequest, filter *ListJournalEntriesFilter) ([]*JournalEntry, error) {
+	if err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		_, err := s.openLedger(tctx, biz.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	scopes := append(s.journalEntryScopes(biz, filter),
+		s.storage.journalEntry.WithPreload(JournalEntryLinesStruct, JournalLineAccountStruct),
+		s.storage.journalEntry.WithPagination(req.Offset(), req.Limit()),
+		s.storage.journalEntry.WithOrderBy(req.ParsedOrderByWithDefault(JournalEntrySchema, []string{"posted_at DESC", "id DESC"})),
+	)
+	return s.storage.journalEntry.FindMany(ctx, scopes...)
+}
+
+// ListJournalEntriesForExport returns every journal entry posted within [from, to] with its
+// lines and their accounts, oldest first and with debits before credits, for exporting to an
+// accounting package. A zero bound leaves that side of the range open.
+func (s *Service) ListJournalEntriesForExport(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) ([]*JournalEntry, error) {
+	if err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		_, err := s.openLedger(tctx, biz.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	scopes := append(s.journalEntryScopes(biz, &ListJournalEntriesFilter{From: from, To: to}),
+		s.storage.journalEntry.WithPreload(JournalEntryLinesStruct, JournalLineAccountStruct),
+		s.storage.journalEntry.WithOrderBy([]string{"posted_at ASC", "id ASC"}),
+	)
+	entries, err := s.storage.journalEntry.FindMany(ctx, scopes...)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		sort.SliceStable(e.Lines, func(i, j int) bool {
+			return e.Lines[i].Debit.IsPositive() && !e.Lines[j].Debit.IsPositive()
+		})
+	}
+	return entries, nil
+}
+
+func (s *Service) CountJournalEntries(ctx context.Context, actor *account.User, biz *business.Business, filter *ListJournalEntriesFilter) (int64, error) {
+	return s.storage.journalEntry.Count(ctx, s.journalEntryScopes(biz, filter)...)
+}
+
+// VATTotals are the figures of a VAT return, taken from the journal entries posted in the
+// filing period. Supplies and purchases are net of VAT.
+type VATTotals struct {
+	TaxedSales     decimal.Decimal // Sales that charged VAT, net of returns.
+	ZeroRatedSales decimal.Decimal // Sales that charged no VAT, net of returns.
+	OutputVAT      decimal.Decimal // VAT charged on sales, net of returns.
+	TaxedPurchases decimal.Decimal // Expenses that carry recoverable VAT.
+	OtherPurchases decimal.Decimal // Expenses that carry no VAT.
+	InputVAT       decimal.Decimal // VAT recoverable on expenses.
+}
+
+// ComputeVATTotals sums the output VAT booked on order sales and their reversals, and the input
+// VAT booked on expenses, within [from, to].
+func (s *Service) ComputeVATTotals(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) (*VATTotals, error) {
+	var book *ledgerBook
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		var err error
+		book, err = s.openLedger(tctx, biz.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.storage.SumJournalLinesBySource(ctx, biz.ID, book.accounts[LedgerAccountVATPayable].ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	totals := &VATTotals{}
+	for _, row := range rows {
+		code := book.codes[row.AccountID]
+		net := row.Credit.Sub(row.Debit)
+		switch row.SourceType {
+		case JournalSourceOrderSale, JournalSourceOrderReversal:
+			switch {
+			case code == LedgerAccountVATPayable:
+				totals.OutputVAT = totals.OutputVAT.Add(net)
+			case code == LedgerAccountSales && row.Taxed:
+				totals.TaxedSales = totals.TaxedSales.Add(net)
+			case code == LedgerAccountSales:
+				totals.ZeroRatedSales = totals.ZeroRatedSales.Add(net)
+			}
+		case JournalSourceExpense:
+			switch {
+			case code == LedgerAccountVATPayable:
+				totals.InputVAT = totals.InputVAT.Sub(net)
+			case code == LedgerAccountCash:
+				// the payment side of the expense
+			case row.Taxed:
+				totals.TaxedPurchases = totals.TaxedPurchases.Sub(net)
+			default:
+				totals.OtherPurchases = totals.OtherPurchases.Sub(net)
+			}
+		}
+	}
+	return totals, nil
+}
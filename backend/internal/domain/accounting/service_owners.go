@@ -0,0 +1,229 @@
+package accounting
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
+)
+
+// ListOwnerShares returns the cap table of the business, largest share first.
+func (s *Service) ListOwnerShares(ctx context.Context, actor *account.User, biz *business.Business) ([]*OwnerShare, error) {
+	return s.storage.ownerShare.FindMany(ctx,
+		s.storage.ownerShare.ScopeBusinessID(biz.ID),
+		s.storage.ownerShare.WithOrderBy([]string{"percentage DESC", "created_at"}),
+	)
+}
+
+// GetCapTable returns the cap table of the business with what each owner invested and
+// withdrew over the life of the business.
+func (s *Service) GetCapTable(ctx context.Context, actor *account.User, biz *business.Business) ([]*CapTableEntry, error) {
+	shares, err := s.ListOwnerShares(ctx, actor, biz)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*CapTableEntry, len(shares))
+	for i, share := range shares {
+		invested, err := s.SumInvestmentAmountByInvestor(ctx, actor, biz, share.OwnerID, time.Time{}, time.Time{})
+		if err != nil {
+			return nil, err
+		}
+		withdrawn, err := s.SumWithdrawalAmountByWithdrawer(ctx, actor, biz, share.OwnerID, time.Time{}, time.Time{})
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = &CapTableEntry{Share: share, Invested: invested, Withdrawn: withdrawn}
+	}
+	return entries, nil
+}
+
+// SetCapTable replaces the cap table of the business. Owners must be users of the business'
+// workspace, listed once, with percentages adding up to at most 100.
+func (s *Service) SetCapTable(ctx context.Context, actor *account.User, biz *business.Business, lines []OwnerShareLine) ([]*CapTableEntry, error) {
+	seen := make(map[string]struct{}, len(lines))
+	ownerIDs := make([]string, 0, len(lines))
+	total := decimal.Zero
+	for _, line := range lines {
+		if _, ok := seen[line.OwnerID]; ok {
+			return nil, ErrOwnerDuplicate(line.OwnerID)
+		}
+		seen[line.OwnerID] = struct{}{}
+		ownerIDs = append(ownerIDs, line.OwnerID)
+		total = total.Add(line.Percentage)
+	}
+	if total.GreaterThan(decimal.NewFromInt(100)) {
+		return nil, ErrOwnerSharesExceeded(total.String())
+	}
+	found, err := s.storage.ExistingWorkspaceUserIDs(ctx, biz.WorkspaceID, ownerIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, ownerID := range ownerIDs {
+		if !found[ownerID] {
+			return nil, ErrOwnerNotFound(ownerID)
+		}
+	}
+
+	shares := make([]*OwnerShare, len(lines))
+	for i, line := range lines {
+		shares[i] = &OwnerShare{BusinessID: biz.ID, OwnerID: line.OwnerID, Percentage: line.Percentage}
+	}
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.ownerShare.DeleteMany(tctx, s.storage.ownerShare.ScopeBusinessID(biz.ID)); err != nil {
+			return err
+		}
+		if len(shares) == 0 {
+			return nil
+		}
+		return s.storage.ownerShare.CreateMany(tctx, shares)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCapTable(ctx, actor, biz)
+}
+
+// ProposeProfitDistribution splits percent of the net profit booked from `from` through the
+// whole `to` date between the owners of the cap table. Each owner's entitlement is their
+// share of it, rounded to the currency's minor units with the remainder on the largest share;
+// the proposal is what is left of it after the withdrawals the owner made in the period or
+// that already distribute its profit.
+func (s *Service) ProposeProfitDistribution(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time, percent decimal.Decimal) (*ProfitDistribution, error) {
+	if to.Before(from) {
+		return nil, ErrProfitDistributionInvalidPeriod()
+	}
+	hundred := decimal.NewFromInt(100)
+	if !percent.IsPositive() || percent.GreaterThan(hundred) {
+		return nil, ErrProfitDistributionInvalidPercent(percent.String())
+	}
+	shares, err := s.ListOwnerShares(ctx, actor, biz)
+	if err != nil {
+		return nil, err
+	}
+	end := to.AddDate(0, 0, 1)
+	balances, err := s.ListLedgerAccountBalances(ctx, actor, biz, from, end)
+	if err != nil {
+		return nil, err
+	}
+	withdrawn, err := s.storage.SumWithdrawalsByWithdrawerForPeriod(ctx, biz.ID, from, end, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	dist := &ProfitDistribution{
+		From:          from,
+		To:            to,
+		NetProfit:     ledgerNetIncome(balances),
+		Percent:       percent,
+		Distributable: decimal.Zero,
+		Owners:        make([]*OwnerDistribution, len(shares)),
+	}
+	if dist.NetProfit.IsPositive() {
+		dist.Distributable = money.Round(dist.NetProfit.Mul(percent).Div(hundred), biz.Currency)
+	}
+	sharesTotal, entitled, largest := decimal.Zero, decimal.Zero, 0
+	for i, share := range shares {
+		entitlement := money.Round(dist.Distributable.Mul(share.Percentage).Div(hundred), biz.Currency)
+		dist.Owners[i] = &OwnerDistribution{
+			OwnerID:     share.OwnerID,
+			Percentage:  share.Percentage,
+			Entitlement: entitlement,
+		}
+		sharesTotal = sharesTotal.Add(share.Percentage)
+		entitled = entitled.Add(entitlement)
+		if entitlement.GreaterThan(dist.Owners[largest].Entitlement) {
+			largest = i
+		}
+	}
+	if len(shares) > 0 {
+		target := money.Round(dist.Distributable.Mul(sharesTotal).Div(hundred), biz.Currency)
+		dist.Owners[largest].Entitlement = dist.Owners[largest].Entitlement.Add(target.Sub(entitled))
+	}
+	for _, o := range dist.Owners {
+		o.Withdrawn = withdrawn[o.OwnerID]
+		o.Proposed = decimal.Max(o.Entitlement.Sub(o.Withdrawn), decimal.Zero)
+	}
+	return dist, nil
+}
+
+// DistributeProfit records an approved profit distribution as one withdrawal per owner, dated
+// withdrawnAt (now when zero) and marked with the profit period it pays out. Without approved
+// lines the proposed amounts are recorded; owners proposed nothing are skipped.
+func (s *Service) DistributeProfit(ctx context.Context, actor *account.User, biz *business.Business, req *DistributeProfitRequest) (*ProfitDistribution, []*Withdrawal, error) {
+	from, to := req.From.Time, req.To.Time
+	percent := decimal.NewFromInt(100)
+	if req.Percent.Valid {
+		percent = req.Percent.Decimal
+	}
+	dist, err := s.ProposeProfitDistribution(ctx, actor, biz, from, to, percent)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(dist.Owners) == 0 {
+		return nil, nil, ErrCapTableEmpty()
+	}
+
+	lines := req.Owners
+	if len(lines) == 0 {
+		for _, o := range dist.Owners {
+			if o.Proposed.IsPositive() {
+				lines = append(lines, OwnerDistributionLine{OwnerID: o.OwnerID, Amount: o.Proposed})
+			}
+		}
+	}
+	inCapTable := make(map[string]bool, len(dist.Owners))
+	for _, o := range dist.Owners {
+		inCapTable[o.OwnerID] = true
+	}
+	seen := make(map[string]struct{}, len(lines))
+	for _, line := range lines {
+		if !inCapTable[line.OwnerID] {
+			return nil, nil, ErrOwnerNotInCapTable(line.OwnerID)
+		}
+		if _, ok := seen[line.OwnerID]; ok {
+			return nil, nil, ErrOwnerDuplicate(line.OwnerID)
+		}
+		seen[line.OwnerID] = struct{}{}
+	}
+
+	withdrawnAt := req.WithdrawnAt
+	if withdrawnAt.IsZero() {
+		withdrawnAt = time.Now().UTC()
+	}
+	note := fmt.Sprintf("Profit distribution %s to %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	withdrawals := make([]*Withdrawal, 0, len(lines))
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		for _, line := range lines {
+			w := &Withdrawal{
+				BusinessID:      biz.ID,
+				Amount:          money.Round(line.Amount, biz.Currency),
+				Currency:        biz.Currency,
+				WithdrawerID:    line.OwnerID,
+				Note:            note,
+				WithdrawnAt:     withdrawnAt,
+				DistributedFrom: sql.NullTime{Time: from, Valid: true},
+				DistributedTo:   sql.NullTime{Time: to, Valid: true},
+			}
+			if err := s.storage.withdrawal.CreateOne(tctx, w); err != nil {
+				return err
+			}
+			if err := s.recordAuditEvent(tctx, actor, biz.ID, AuditRecordWithdrawal, w.ID, nil, withdrawalAuditFields(w)); err != nil {
+				return err
+			}
+			if err := s.postJournalEntry(tctx, withdrawalLedgerPosting(w)); err != nil {
+				return err
+			}
+			withdrawals = append(withdrawals, w)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return dist, withdrawals, nil
+}
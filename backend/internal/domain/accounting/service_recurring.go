@@ -0,0 +1,182 @@
+package accounting
+
+import (
+	"context"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// SetNotification wires the emails sent by background jobs; with nil no reminders are sent.
+func (s *Service) SetNotification(n *Notification) {
+	s.notification = n
+}
+
+// recurringExpenseDate truncates t to the UTC date occurrences are scheduled on.
+func recurringExpenseDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// nextRecurrenceFrom returns the first occurrence of the recurring expense that is not before from.
+func nextRecurrenceFrom(re *RecurringExpense, from time.Time) time.Time {
+	current := re.RecurringStartDate
+	for current.Before(from) {
+		next := re.Frequency.GetNextRecurrenceDate(current)
+		if !next.After(current) {
+			break
+		}
+		current = next
+	}
+	return current
+}
+
+// GenerateRecurringExpensesOptions selects the recurring expenses GenerateDueRecurringExpenses handles.
+type GenerateRecurringExpensesOptions struct {
+	// Now is the time of the run; zero means time.Now().
+	Now time.Time
+	// BusinessID limits the run to one business; empty means all.
+	BusinessID string
+	// ReminderDays is how many days ahead owners are reminded of an upcoming occurrence; zero
+	// disables reminders.
+	ReminderDays int
+	// ReminderMinAmount is the smallest occurrence amount worth a reminder.
+	ReminderMinAmount decimal.Decimal
+}
+
+// GenerateRecurringExpensesResult reports what a run did.
+type GenerateRecurringExpensesResult struct {
+	Due      int
+	Created  int
+	Ended    int
+	Reminded int
+	Failed   int
+}
+
+// GenerateDueRecurringExpenses records the occurrences of active recurring expenses that are
+// due by today and advances their next recurring date. Missed days are caught up, so a failed
+// run can simply be repeated. Recurring expenses past their end date are ended, and owners are
+// reminded once per occurrence of large charges coming up within ReminderDays.
+// Paused, ended and canceled recurring expenses are left alone.
+func (s *Service) GenerateDueRecurringExpenses(ctx context.Context, opts GenerateRecurringExpensesOptions) (*GenerateRecurringExpensesResult, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	today := recurringExpenseDate(now)
+	horizon := today
+	if opts.ReminderDays > 0 {
+		horizon = today.AddDate(0, 0, opts.ReminderDays)
+	}
+	scopes := []func(db *gorm.DB) *gorm.DB{
+		s.storage.recurringExpense.ScopeEquals(RecurringExpenseSchema.Status, RecurringExpenseStatusActive),
+		s.storage.recurringExpense.ScopeLessThanOrEqual(RecurringExpenseSchema.NextRecurringDate, horizon),
+		s.storage.recurringExpense.WithPreload("Business"),
+		s.storage.recurringExpense.WithOrderBy([]string{RecurringExpenseSchema.NextRecurringDate.Column() + " ASC"}),
+	}
+	if opts.BusinessID != "" {
+		scopes = append(scopes, s.storage.recurringExpense.ScopeBusinessID(opts.BusinessID))
+	}
+	candidates, err := s.storage.recurringExpense.FindMany(ctx, scopes...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GenerateRecurringExpensesResult{}
+	for _, re := range candidates {
+		log := logger.FromContext(ctx).With("businessId", re.BusinessID, "recurringExpenseId", re.ID)
+		biz := re.Business
+		if biz == nil {
+			log.Warn("skipping recurring expense: business not found")
+			continue
+		}
+		// saving the recurring expense would also upsert its preloaded business
+		re.Business = nil
+
+		if re.NextRecurringDate.IsZero() {
+			// recurring expenses created before the scheduler carry on from their last occurrence
+			from := recurringExpenseDate(re.CreatedAt)
+			last, err := s.GetLastRecurringExpenseOccurance(ctx, nil, biz, re.ID)
+			if err != nil && !database.IsRecordNotFound(err) {
+				log.Error("failed to load last recurring expense occurrence", "error", err)
+				result.Failed++
+				continue
+			}
+			if last != nil {
+				from = re.Frequency.GetNextRecurrenceDate(last.OccurredOn)
+			}
+			re.NextRecurringDate = nextRecurrenceFrom(re, from)
+		}
+		if !re.NextRecurringDate.After(today) {
+			result.Due++
+		}
+
+		failed := false
+		for !re.NextRecurringDate.After(today) && !recurringExpensePastEnd(re, re.NextRecurringDate) {
+			if _, err := s.CreateNewRecurringExpenseOccurrence(ctx, nil, biz, re, re.NextRecurringDate); err != nil {
+				log.Error("failed to create recurring expense occurrence", "occurredOn", re.NextRecurringDate, "error", err)
+				failed = true
+				break
+			}
+			result.Created++
+		}
+		if failed {
+			result.Failed++
+			continue
+		}
+
+		if recurringExpensePastEnd(re, re.NextRecurringDate) {
+			sm := NewRecurringExpenseStateMachine(re)
+			if err := sm.TransitionTo(RecurringExpenseStatusEnded); err != nil {
+				log.Error("failed to end recurring expense", "error", err)
+				result.Failed++
+				continue
+			}
+			if err := s.storage.recurringExpense.UpdateOne(ctx, re); err != nil {
+				log.Error("failed to end recurring expense", "error", err)
+				result.Failed++
+				continue
+			}
+			result.Ended++
+			continue
+		}
+
+		if !s.recurringExpenseNeedsReminder(re, opts, horizon) {
+			continue
+		}
+		if err := s.notification.SendRecurringExpenseReminderEmail(ctx, biz, re, re.NextRecurringDate); err != nil {
+			result.Failed++
+			continue
+		}
+		re.ReminderSentFor = transformer.ToNullTime(re.NextRecurringDate)
+		if err := s.storage.recurringExpense.UpdateOne(ctx, re); err != nil {
+			log.Error("failed to mark recurring expense reminder as sent", "error", err)
+			result.Failed++
+			continue
+		}
+		result.Reminded++
+	}
+	return result, nil
+}
+
+// recurringExpensePastEnd reports whether an occurrence on date falls after the end date.
+func recurringExpensePastEnd(re *RecurringExpense, date time.Time) bool {
+	return re.RecurringEndDate.Valid && date.After(re.RecurringEndDate.Time)
+}
+
+// recurringExpenseNeedsReminder reports whether the owner should hear about the next
+// occurrence: reminders are on, the charge is large enough, due within the horizon and not
+// already reminded of.
+func (s *Service) recurringExpenseNeedsReminder(re *RecurringExpense, opts GenerateRecurringExpensesOptions, horizon time.Time) bool {
+	if s.notification == nil || opts.ReminderDays <= 0 {
+		return false
+	}
+	if re.Amount.LessThan(opts.ReminderMinAmount) || re.NextRecurringDate.After(horizon) {
+		return false
+	}
+	return !re.ReminderSentFor.Valid || !re.ReminderSentFor.Time.Equal(re.NextRecurringDate)
+}
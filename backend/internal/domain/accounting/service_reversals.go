@@ -0,0 +1,101 @@
+package accounting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
+)
+
+// OrderReversalInput describes a return or refund of an order to post into accounting.
+// Restocked reverses the order's COGS; Refunded reverses its transaction fee expense.
+type OrderReversalInput struct {
+	BusinessID string
+	OrderID    string
+	OrderTotal decimal.Decimal
+	COGS       decimal.Decimal
+	Currency   string
+	Restocked  bool
+	Refunded   bool
+	OccurredAt time.Time
+}
+
+// RecordOrderReversal posts the revenue reversal of a returned or refunded order, along with
+// its COGS reversal when the goods were restocked and its transaction fee reversal when the
+// payment was refunded. It is idempotent: replaying an event or receiving both the return and
+// the refund of an order never reverses anything twice. The reversal is booked in the ledger
+// against the order's booked sale and payment.
+//
+// This method is intentionally internal and does not do actor permission checks.
+func (s *Service) RecordOrderReversal(ctx context.Context, in OrderReversalInput) error {
+	if in.BusinessID == "" || in.OrderID == "" {
+		return fmt.Errorf("businessID and orderID are required")
+	}
+	if in.OccurredAt.IsZero() {
+		in.OccurredAt = time.Now().UTC()
+	}
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		rev, err := s.storage.orderReversal.FindOne(tctx,
+			s.storage.orderReversal.ScopeBusinessID(in.BusinessID),
+			s.storage.orderReversal.ScopeEquals(OrderReversalSchema.OrderID, in.OrderID),
+			s.storage.orderReversal.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil && !database.IsRecordNotFound(err) {
+			return err
+		}
+		isNew := err != nil
+		if isNew {
+			rev = &OrderReversal{
+				BusinessID: in.BusinessID,
+				OrderID:    in.OrderID,
+				Currency:   in.Currency,
+				OccurredOn: in.OccurredAt,
+			}
+		}
+		rev.Revenue = money.Round(in.OrderTotal, in.Currency)
+		if in.Restocked && !rev.Restocked {
+			rev.Restocked = true
+			rev.COGS = money.Round(in.COGS, in.Currency)
+		}
+		if in.Refunded && !rev.Refunded {
+			fee, err := s.storage.expense.FindOne(tctx,
+				s.storage.expense.ScopeBusinessID(in.BusinessID),
+				s.storage.expense.ScopeEquals(ExpenseSchema.OrderID, in.OrderID),
+				s.storage.expense.ScopeEquals(ExpenseSchema.Category, ExpenseCategoryTransactionFee),
+			)
+			if err != nil && !database.IsRecordNotFound(err) {
+				return err
+			}
+			if err == nil {
+				rev.TransactionFee = fee.Amount
+			}
+			rev.Refunded = true
+		}
+		if isNew {
+			// A concurrent handler creating the same reversal hits the unique index; the
+			// bus retries and the retry updates the row it created.
+			err = s.storage.orderReversal.CreateOne(tctx, rev)
+		} else {
+			err = s.storage.orderReversal.UpdateOne(tctx, rev)
+		}
+		if err != nil {
+			return err
+		}
+		book, err := s.openLedger(tctx, in.BusinessID)
+		if err != nil {
+			return err
+		}
+		return s.writeOrderReversalEntry(tctx, book, rev)
+	})
+}
+
+// SumOrderReversals returns the revenue, COGS and transaction fees reversed within the given
+// date range.
+func (s *Service) SumOrderReversals(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) (*OrderReversalTotals, error) {
+	return s.storage.SumOrderReversals(ctx, biz.ID, from, to)
+}
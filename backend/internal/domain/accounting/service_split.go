@@ -0,0 +1,215 @@
+package accounting
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+	"github.com/shopspring/decimal"
+)
+
+// ListExpenseSplit returns the lines the expense was split into, in the order they were
+// created. An expense that is not split is its only line.
+func (s *Service) ListExpenseSplit(ctx context.Context, actor *account.User, biz *business.Business, id string) ([]*Expense, error) {
+	expense, err := s.GetExpenseByID(ctx, actor, biz, id)
+	if err != nil {
+		return nil, err
+	}
+	if !expense.SplitID.Valid {
+		return []*Expense{expense}, nil
+	}
+	return s.storage.expense.FindMany(ctx,
+		s.storage.expense.ScopeEquals(ExpenseSchema.SplitID, expense.SplitID.String),
+		s.storage.expense.WithOrderBy([]string{"created_at", "id"}),
+	)
+}
+
+// SplitExpense splits an expense into lines with their own category and, optionally, another
+// business of the workspace. Every line is an expense of its own, so reports, budgets and the
+// ledger count the lines rather than the whole amount. The expense keeps the first line and
+// the others are created next to it; splitting a split expense again replaces all of its lines
+// with the new ones. Amounts, VAT and foreign-currency amounts are split by percentage, with
+// the rounding remainder on the largest line.
+func (s *Service) SplitExpense(ctx context.Context, actor *account.User, biz *business.Business, id string, req *SplitExpenseRequest) ([]*Expense, error) {
+	group, err := s.ListExpenseSplit(ctx, actor, biz, id)
+	if err != nil {
+		return nil, err
+	}
+	var expense *Expense
+	for _, line := range group {
+		if line.ID == id {
+			expense = line
+		}
+	}
+	if expense.OrderID.Valid {
+		return nil, ErrExpenseSplitOrderLinked()
+	}
+	groupIDs := make([]any, len(group))
+	for i, line := range group {
+		groupIDs[i] = line.ID
+	}
+	allocated, err := s.storage.allocation.Count(ctx, s.storage.allocation.ScopeIn(ExpenseAllocationSchema.ExpenseID, groupIDs))
+	if err != nil {
+		return nil, err
+	}
+	if allocated > 0 {
+		return nil, ErrExpenseSplitAllocated()
+	}
+
+	total := decimal.Zero
+	businessIDs := make([]string, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		total = total.Add(line.Percentage)
+		if line.BusinessID != "" && line.BusinessID != biz.ID {
+			businessIDs = append(businessIDs, line.BusinessID)
+		}
+	}
+	if !total.Equal(decimal.NewFromInt(100)) {
+		return nil, ErrExpenseSplitPercentage(total.String())
+	}
+	currencies, err := s.storage.WorkspaceBusinessCurrencies(ctx, biz.WorkspaceID, businessIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, businessID := range businessIDs {
+		currency, ok := currencies[businessID]
+		if !ok {
+			return nil, ErrExpenseSplitBusinessNotFound(businessID)
+		}
+		if currency != expense.Currency {
+			return nil, ErrExpenseSplitCurrencyMismatch(businessID, currency)
+		}
+	}
+
+	amount, vat := decimal.Zero, decimal.Zero
+	originalAmount, originalVAT := decimal.Zero, decimal.Zero
+	for _, line := range group {
+		amount = amount.Add(line.Amount)
+		vat = vat.Add(line.VAT)
+		originalAmount = originalAmount.Add(line.OriginalAmount.Decimal)
+		originalVAT = originalVAT.Add(line.OriginalVAT.Decimal)
+	}
+	percentages := make([]decimal.Decimal, len(req.Lines))
+	for i, line := range req.Lines {
+		percentages[i] = line.Percentage
+	}
+	amounts := splitRounded(amount, percentages, expense.Currency)
+	vats := splitRounded(vat, percentages, expense.Currency)
+	var originalAmounts, originalVATs []decimal.Decimal
+	if expense.OriginalCurrency.Valid {
+		originalAmounts = splitRounded(originalAmount, percentages, expense.OriginalCurrency.String)
+		originalVATs = splitRounded(originalVAT, percentages, expense.OriginalCurrency.String)
+	}
+
+	// the lines share the ID of the expense first split; a single line is no split at all
+	var splitID sql.NullString
+	if len(req.Lines) > 1 {
+		splitID = transformer.ToNullString(expense.ID)
+		if expense.SplitID.Valid {
+			splitID = expense.SplitID
+		}
+	}
+	before := expenseAuditFields(expense)
+	lines := make([]*Expense, len(req.Lines))
+	for i, l := range req.Lines {
+		line := expense
+		if i > 0 {
+			line = &Expense{
+				Currency:           expense.Currency,
+				OriginalCurrency:   expense.OriginalCurrency,
+				ExchangeRate:       expense.ExchangeRate,
+				ExchangeRateSource: expense.ExchangeRateSource,
+				OccurredOn:         expense.OccurredOn,
+				Type:               expense.Type,
+				Note:               expense.Note,
+			}
+		}
+		line.BusinessID = biz.ID
+		if l.BusinessID != "" {
+			line.BusinessID = l.BusinessID
+		}
+		// a recurring occurrence only stays linked to its template in the template's business
+		if i > 0 && line.BusinessID == biz.ID {
+			line.RecurringExpenseID = expense.RecurringExpenseID
+		} else if line.BusinessID != biz.ID {
+			line.RecurringExpenseID = sql.NullString{}
+		}
+		line.Category = l.Category
+		line.Amount, line.VAT = amounts[i], vats[i]
+		if expense.OriginalCurrency.Valid {
+			line.OriginalAmount = decimal.NewNullDecimal(originalAmounts[i])
+			line.OriginalVAT = decimal.NewNullDecimal(originalVATs[i])
+		}
+		line.SplitID = splitID
+		lines[i] = line
+	}
+
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		for _, line := range group {
+			if line.ID == expense.ID {
+				continue
+			}
+			if err := s.storage.expense.DeleteOne(tctx, line); err != nil {
+				return err
+			}
+			if err := s.recordAuditEvent(tctx, actor, line.BusinessID, AuditRecordExpense, line.ID, expenseAuditFields(line), nil); err != nil {
+				return err
+			}
+			if err := s.removeJournalEntry(tctx, line.BusinessID, JournalSourceExpense, line.ID); err != nil {
+				return err
+			}
+		}
+		if expense.BusinessID != biz.ID {
+			if err := s.removeJournalEntry(tctx, biz.ID, JournalSourceExpense, expense.ID); err != nil {
+				return err
+			}
+		}
+		for i, line := range lines {
+			if i == 0 {
+				if err := s.storage.expense.UpdateOne(tctx, line); err != nil {
+					return err
+				}
+				if err := s.recordAuditEvent(tctx, actor, line.BusinessID, AuditRecordExpense, line.ID, before, expenseAuditFields(line)); err != nil {
+					return err
+				}
+			} else {
+				if err := s.storage.expense.CreateOne(tctx, line); err != nil {
+					return err
+				}
+				if err := s.recordAuditEvent(tctx, actor, line.BusinessID, AuditRecordExpense, line.ID, nil, expenseAuditFields(line)); err != nil {
+					return err
+				}
+			}
+			if err := s.postJournalEntry(tctx, expenseLedgerPosting(line)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// splitRounded splits total by percentages rounded to the currency's minor units, with the
+// rounding remainder on the largest share so the shares add up to total.
+func splitRounded(total decimal.Decimal, percentages []decimal.Decimal, currency string) []decimal.Decimal {
+	hundred := decimal.NewFromInt(100)
+	shares := make([]decimal.Decimal, len(percentages))
+	sum, largest := decimal.Zero, 0
+	for i, p := range percentages {
+		shares[i] = money.Round(total.Mul(p).Div(hundred), currency)
+		sum = sum.Add(shares[i])
+		if shares[i].GreaterThan(shares[largest]) {
+			largest = i
+		}
+	}
+	if len(shares) > 0 {
+		shares[largest] = shares[largest].Add(total.Sub(sum))
+	}
+	return shares
+}
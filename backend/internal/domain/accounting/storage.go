@@ -1,26 +1,319 @@
 package accounting
 
 import (
+	"context"
+	"time"
+
 	"github.com/abdelrahman146/kyora/internal/platform/cache"
 	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm/clause"
 )
 
 type Storage struct {
+	db               *database.Database
 	cache            *cache.Cache
 	investment       *database.Repository[Investment]
 	withdrawal       *database.Repository[Withdrawal]
 	asset            *database.Repository[Asset]
 	expense          *database.Repository[Expense]
 	recurringExpense *database.Repository[RecurringExpense]
+	allocationRule   *database.Repository[ExpenseAllocationRule]
+	allocation       *database.Repository[ExpenseAllocation]
+	orderReversal    *database.Repository[OrderReversal]
+	expenseDraft     *database.Repository[ExpenseDraft]
+	expenseInbox     *database.Repository[ExpenseInbox]
+	ledger           *database.Repository[Ledger]
+	ledgerAccount    *database.Repository[LedgerAccount]
+	journalEntry     *database.Repository[JournalEntry]
+	journalLine      *database.Repository[JournalLine]
+	ownerShare       *database.Repository[OwnerShare]
+	auditEvent       *database.Repository[AuditEvent]
 }
 
 func NewStorage(db *database.Database, cache *cache.Cache) *Storage {
 	return &Storage{
+		db:               db,
 		cache:            cache,
 		investment:       database.NewRepository[Investment](db),
 		withdrawal:       database.NewRepository[Withdrawal](db),
 		asset:            database.NewRepository[Asset](db),
 		expense:          database.NewRepository[Expense](db),
 		recurringExpense: database.NewRepository[RecurringExpense](db),
+		allocationRule:   database.NewRepository[ExpenseAllocationRule](db),
+		allocation:       database.NewRepository[ExpenseAllocation](db),
+		orderReversal:    database.NewRepository[OrderReversal](db),
+		expenseDraft:     database.NewRepository[ExpenseDraft](db),
+		expenseInbox:     database.NewRepository[ExpenseInbox](db),
+		ledger:           database.NewRepository[Ledger](db),
+		ledgerAccount:    database.NewRepository[LedgerAccount](db),
+		journalEntry:     database.NewRepository[JournalEntry](db),
+		journalLine:      database.NewRepository[JournalLine](db),
+		ownerShare:       database.NewRepository[OwnerShare](db),
+		auditEvent:       database.NewRepository[AuditEvent](db),
+	}
+}
+
+// SumAllocationsByTarget returns allocated amounts per target ID for expenses of the business
+// that occurred within [from, to]. A zero bound leaves that side of the range open.
+func (s *Storage) SumAllocationsByTarget(ctx context.Context, businessID string, targetType AllocationTargetType, from, to time.Time) (map[string]decimal.Decimal, error) {
+	q := s.db.Conn(ctx).
+		Table(ExpenseAllocationTable).
+		Select("expense_allocations.target_id AS target_id, COALESCE(SUM(expense_allocations.amount), 0) AS amount").
+		Joins("JOIN expenses ON expenses.id = expense_allocations.expense_id AND expenses.deleted_at IS NULL").
+		Where("expense_allocations.business_id = ?", businessID).
+		Where("expense_allocations.target_type = ?", targetType).
+		Where("expense_allocations.deleted_at IS NULL")
+	if !from.IsZero() {
+		q = q.Where("expenses.occurred_on >= ?", from)
+	}
+	if !to.IsZero() {
+		q = q.Where("expenses.occurred_on <= ?", to)
+	}
+	var rows []struct {
+		TargetID string
+		Amount   decimal.Decimal
+	}
+	if err := q.Group("expense_allocations.target_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[string]decimal.Decimal, len(rows))
+	for _, r := range rows {
+		out[r.TargetID] = r.Amount
+	}
+	return out, nil
+}
+
+// SumOrderReversals adds up the order reversals of the business that occurred within [from, to].
+// A zero bound leaves that side of the range open.
+func (s *Storage) SumOrderReversals(ctx context.Context, businessID string, from, to time.Time) (*OrderReversalTotals, error) {
+	q := s.db.Conn(ctx).
+		Table(OrderReversalTable).
+		Select("COALESCE(SUM(revenue), 0) AS revenue, COALESCE(SUM(cogs), 0) AS cogs, COALESCE(SUM(transaction_fee), 0) AS transaction_fees").
+		Where("business_id = ?", businessID).
+		Where("deleted_at IS NULL")
+	if !from.IsZero() {
+		q = q.Where("occurred_on >= ?", from)
+	}
+	if !to.IsZero() {
+		q = q.Where("occurred_on <= ?", to)
+	}
+	totals := &OrderReversalTotals{}
+	if err := q.Scan(totals).Error; err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// LockLedger returns the ledger of the business, opening it if needed, locked for update
+// until the surrounding transaction ends. Postings of a business are serialized on it.
+func (s *Storage) LockLedger(ctx context.Context, businessID string) (*Ledger, error) {
+	err := s.db.Conn(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&Ledger{BusinessID: businessID}).Error
+	if err != nil {
+		return nil, err
+	}
+	return s.ledger.FindOne(ctx,
+		s.ledger.ScopeBusinessID(businessID),
+		s.ledger.WithLockingStrength(database.LockingStrengthUpdate),
+	)
+}
+
+// DeleteJournalEntry permanently deletes the journal entry posted from a source, with its
+// lines, so the source can be posted again.
+func (s *Storage) DeleteJournalEntry(ctx context.Context, businessID string, sourceType JournalSourceType, sourceID string) error {
+	entryIDs := s.db.Conn(ctx).
+		Unscoped().
+		Model(&JournalEntry{}).
+		Select(JournalEntrySchema.ID.Column()).
+		Where("business_id = ? AND source_type = ? AND source_id = ?", businessID, sourceType, sourceID)
+	if err := s.db.Conn(ctx).
+		Unscoped().
+		Where("entry_id IN (?)", entryIDs).
+		Delete(&JournalLine{}).Error; err != nil {
+		return err
+	}
+	return s.db.Conn(ctx).
+		Unscoped().
+		Where("business_id = ? AND source_type = ? AND source_id = ?", businessID, sourceType, sourceID).
+		Delete(&JournalEntry{}).Error
+}
+
+// LedgerAccountTotals are the debits and credits posted to an account.
+type LedgerAccountTotals struct {
+	AccountID string
+	Debit     decimal.Decimal
+	Credit    decimal.Decimal
+}
+
+// SumJournalLinesByAccount adds up the debits and credits per account of the journal entries
+// of the business posted within [from, to]. A zero bound leaves that side of the range open.
+//
+// With cashBasis set, the sale of an order is dated by the payment of the order instead, and
+// sales of orders not paid yet are left out.
+func (s *Storage) SumJournalLinesByAccount(ctx context.Context, businessID string, cashBasis bool, from, to time.Time) ([]LedgerAccountTotals, error) {
+	q := s.db.Conn(ctx).
+		Table(JournalLineTable).
+		Select("journal_lines.account_id AS account_id, COALESCE(SUM(journal_lines.debit), 0) AS debit, COALESCE(SUM(journal_lines.credit), 0) AS credit").
+		Joins("JOIN journal_entries ON journal_entries.id = journal_lines.entry_id AND journal_entries.deleted_at IS NULL").
+		Where("journal_lines.business_id = ?", businessID).
+		Where("journal_lines.deleted_at IS NULL")
+	postedAt := "journal_entries.posted_at"
+	if cashBasis {
+		q = q.Joins(`LEFT JOIN journal_entries payments ON journal_entries.source_type = ? AND payments.business_id = journal_entries.business_id
+			AND payments.source_type = ? AND payments.source_id = journal_entries.source_id AND payments.deleted_at IS NULL`,
+			JournalSourceOrderSale, JournalSourceOrderPayment).
+			Where("journal_entries.source_type <> ? OR payments.id IS NOT NULL", JournalSourceOrderSale)
+		postedAt = "COALESCE(payments.posted_at, journal_entries.posted_at)"
+	}
+	if !from.IsZero() {
+		q = q.Where(postedAt+" >= ?", from)
+	}
+	if !to.IsZero() {
+		q = q.Where(postedAt+" <= ?", to)
+	}
+	var rows []LedgerAccountTotals
+	if err := q.Group("journal_lines.account_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// JournalSourceTotals are the debits and credits posted to an account by the journal entries
+// of one source type. Taxed splits the entries that carry a line on the VAT account from
+// those that do not.
+type JournalSourceTotals struct {
+	SourceType JournalSourceType
+	AccountID  string
+	Taxed      bool
+	Debit      decimal.Decimal
+	Credit     decimal.Decimal
+}
+
+// SumJournalLinesBySource adds up the debits and credits per source type and account of the
+// journal entries of the business posted within [from, to], telling apart the entries that
+// post to vatAccountID.
+func (s *Storage) SumJournalLinesBySource(ctx context.Context, businessID, vatAccountID string, from, to time.Time) ([]JournalSourceTotals, error) {
+	lines := s.db.Conn(ctx).
+		Table(JournalLineTable).
+		Select(`journal_entries.source_type, journal_lines.account_id, journal_lines.debit, journal_lines.credit,
+			EXISTS (SELECT 1 FROM journal_lines vat WHERE vat.entry_id = journal_entries.id AND vat.account_id = ? AND vat.deleted_at IS NULL) AS taxed`, vatAccountID).
+		Joins("JOIN journal_entries ON journal_entries.id = journal_lines.entry_id AND journal_entries.deleted_at IS NULL").
+		Where("journal_lines.business_id = ?", businessID).
+		Where("journal_lines.deleted_at IS NULL").
+		Where("journal_entries.posted_at BETWEEN ? AND ?", from, to)
+	var rows []JournalSourceTotals
+	err := s.db.Conn(ctx).
+		Table("(?) AS entry_lines", lines).
+		Select("source_type, account_id, taxed, COALESCE(SUM(debit), 0) AS debit, COALESCE(SUM(credit), 0) AS credit").
+		Group("source_type, account_id, taxed").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// LedgerOrderSource is the snapshot of an order the ledger books its sale or payment from.
+type LedgerOrderSource struct {
+	ID          string
+	OrderNumber string
+	Total       decimal.Decimal
+	VAT         decimal.Decimal
+	COGS        decimal.Decimal
+	Currency    string
+	PostedAt    time.Time
+}
+
+// ListFulfilledOrdersForLedger returns the orders of the business whose sale is booked: the
+// fulfilled ones and those returned after fulfillment, dated when they were fulfilled.
+func (s *Storage) ListFulfilledOrdersForLedger(ctx context.Context, businessID string) ([]LedgerOrderSource, error) {
+	var rows []LedgerOrderSource
+	err := s.db.Conn(ctx).
+		Table("orders").
+		Select("id, order_number, total, vat, cogs, currency, COALESCE(fulfilled_at, ordered_at) AS posted_at").
+		Where("business_id = ? AND deleted_at IS NULL", businessID).
+		Where("(status = ? OR fulfilled_at IS NOT NULL)", "fulfilled").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// ListPaidOrdersForLedger returns the orders of the business that were paid, including the
+// refunded ones, dated when they were paid.
+func (s *Storage) ListPaidOrdersForLedger(ctx context.Context, businessID string) ([]LedgerOrderSource, error) {
+	var rows []LedgerOrderSource
+	err := s.db.Conn(ctx).
+		Table("orders").
+		Select("id, order_number, total, vat, cogs, currency, COALESCE(paid_at, ordered_at) AS posted_at").
+		Where("business_id = ? AND deleted_at IS NULL", businessID).
+		Where("(payment_status IN ? OR paid_at IS NOT NULL)", []string{"paid", "refunded"}).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// ExistingWorkspaceUserIDs reports which of userIDs are users of the workspace.
+func (s *Storage) ExistingWorkspaceUserIDs(ctx context.Context, workspaceID string, userIDs []string) (map[string]bool, error) {
+	found := make(map[string]bool, len(userIDs))
+	if len(userIDs) == 0 {
+		return found, nil
+	}
+	var ids []string
+	err := s.db.Conn(ctx).
+		Table("users").
+		Where("workspace_id = ? AND id IN ? AND deleted_at IS NULL", workspaceID, userIDs).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		found[id] = true
+	}
+	return found, nil
+}
+
+// WorkspaceBusinessCurrencies returns the currency of each of businessIDs that is a business of the workspace.
+func (s *Storage) WorkspaceBusinessCurrencies(ctx context.Context, workspaceID string, businessIDs []string) (map[string]string, error) {
+	found := make(map[string]string, len(businessIDs))
+	if len(businessIDs) == 0 {
+		return found, nil
+	}
+	var rows []struct {
+		ID       string
+		Currency string
+	}
+	err := s.db.Conn(ctx).
+		Table("businesses").
+		Select("id, currency").
+		Where("workspace_id = ? AND id IN ? AND deleted_at IS NULL", workspaceID, businessIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		found[r.ID] = r.Currency
+	}
+	return found, nil
+}
+
+// SumWithdrawalsByWithdrawerForPeriod adds up per withdrawer the withdrawals of the business
+// made within [from, to] or recorded as distributions of the profit of the period
+// [periodFrom, periodTo].
+func (s *Storage) SumWithdrawalsByWithdrawerForPeriod(ctx context.Context, businessID string, from, to, periodFrom, periodTo time.Time) (map[string]decimal.Decimal, error) {
+	var rows []struct {
+		WithdrawerID string
+		Amount       decimal.Decimal
+	}
+	err := s.db.Conn(ctx).
+		Table(WithdrawalTable).
+		Select("withdrawer_id, COALESCE(SUM(amount), 0) AS amount").
+		Where("business_id = ? AND deleted_at IS NULL", businessID).
+		Where("(withdrawn_at BETWEEN ? AND ?) OR (distributed_from = ? AND distributed_to = ?)", from, to, periodFrom, periodTo).
+		Group("withdrawer_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]decimal.Decimal, len(rows))
+	for _, r := range rows {
+		out[r.WithdrawerID] = r.Amount
 	}
+	return out, nil
 }
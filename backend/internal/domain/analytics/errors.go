@@ -1,6 +1,9 @@
 package analytics
 
-import "github.com/abdelrahman146/kyora/internal/platform/types/problem"
+import (
+	"github.com/abdelrahman146/kyora/internal/domain/accounting"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+)
 
 func ErrInvalidDateFormat(field string, err error) error {
 	return problem.BadRequest("invalid "+field+" date format, use YYYY-MM-DD").
@@ -27,3 +30,71 @@ func ErrAnalyticsQueryFailed(err error) error {
 		WithError(err).
 		WithCode("analytics.query_failed")
 }
+
+func ErrInvalidGoalMonth(month string, err error) error {
+	return problem.BadRequest("invalid goal month, use YYYY-MM").
+		WithError(err).
+		With("month", month).
+		WithCode("analytics.invalid_goal_month")
+}
+
+func ErrInvalidDigestWeek(week string, err error) error {
+	return problem.BadRequest("invalid digest week, use the YYYY-MM-DD date of a Monday").
+		WithError(err).
+		With("week", week).
+		WithCode("analytics.invalid_digest_week")
+}
+
+func ErrGoalTargetsRequired() error {
+	return problem.BadRequest("at least one target is required").
+		WithCode("analytics.goal_targets_required")
+}
+
+func ErrInvalidGoalTarget(metric GoalMetric) error {
+	return problem.BadRequest("goal targets must be greater than zero").
+		With("metric", metric).
+		WithCode("analytics.invalid_goal_target")
+}
+
+func ErrGoalNotFound(month string, err error) error {
+	return problem.NotFound("no goal set for this month").
+		WithError(err).
+		With("month", month).
+		WithCode("analytics.goal_not_found")
+}
+
+func ErrInvalidExchangeRate(value string) error {
+	return problem.BadRequest("invalid exchange rate, use CUR:rate pairs separated by commas with positive rates").
+		With("rate", value).
+		WithCode("analytics.invalid_exchange_rate")
+}
+
+func ErrBudgetRequired() error {
+	return problem.BadRequest("a revenue target or at least one category budget is required").
+		WithCode("analytics.budget_required")
+}
+
+func ErrInvalidBudgetCategory(category accounting.ExpenseCategory) error {
+	return problem.BadRequest("invalid budget category").
+		With("category", category).
+		WithCode("analytics.invalid_budget_category")
+}
+
+func ErrDuplicateBudgetCategory(category accounting.ExpenseCategory) error {
+	return problem.BadRequest("each category can only be budgeted once").
+		With("category", category).
+		WithCode("analytics.duplicate_budget_category")
+}
+
+func ErrInvalidBudgetAmount(field string) error {
+	return problem.BadRequest("budget amounts must be greater than zero").
+		With("field", field).
+		WithCode("analytics.invalid_budget_amount")
+}
+
+func ErrBudgetNotFound(month string, err error) error {
+	return problem.NotFound("no budget set for this month").
+		WithError(err).
+		With("month", month).
+		WithCode("analytics.budget_not_found")
+}
@@ -2,12 +2,15 @@ package analytics
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/abdelrahman146/kyora/internal/domain/account"
 	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/request"
 	"github.com/abdelrahman146/kyora/internal/platform/response"
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 )
 
 // HttpHandler handles HTTP requests for analytics domain operations.
@@ -30,24 +33,36 @@ func (h *HttpHandler) getBusinessForRequest(c *gin.Context, actor *account.User)
 
 const dateLayout = "2006-01-02"
 
-func parseDateParam(value string, field string) (time.Time, error) {
+// parseDateParam parses a YYYY-MM-DD date as local midnight in the business timezone.
+func parseDateParam(value string, field string, loc *time.Location) (time.Time, error) {
 	if value == "" {
 		return time.Time{}, nil
 	}
-	t, err := time.Parse(dateLayout, value)
+	t, err := time.ParseInLocation(dateLayout, value, loc)
 	if err != nil {
 		return time.Time{}, ErrInvalidDateFormat(field, err)
 	}
 	return t, nil
 }
 
-func defaultDateRange(from, to time.Time) (time.Time, time.Time) {
-	now := time.Now().UTC()
+// parseEndDateParam parses an inclusive end date: the result is the last instant of that
+// local day, so to=2025-01-31 covers everything that happened on the 31st.
+func parseEndDateParam(value string, field string, loc *time.Location) (time.Time, error) {
+	t, err := parseDateParam(value, field, loc)
+	if err != nil || t.IsZero() {
+		return t, err
+	}
+	return t.AddDate(0, 0, 1).Add(-time.Microsecond), nil
+}
+
+func defaultDateRange(from, to time.Time, loc *time.Location) (time.Time, time.Time) {
+	now := time.Now().In(loc)
 	if to.IsZero() {
 		to = now
 	}
 	if from.IsZero() {
-		from = to.AddDate(0, 0, -30)
+		y, m, d := to.AddDate(0, 0, -30).Date()
+		from = time.Date(y, m, d, 0, 0, 0, 0, loc)
 	}
 	return from, to
 }
@@ -131,17 +146,17 @@ func (h *HttpHandler) GetSalesAnalytics(c *gin.Context) {
 		return
 	}
 
-	from, err := parseDateParam(query.From, "from")
+	from, err := parseDateParam(query.From, "from", biz.Location())
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
-	to, err := parseDateParam(query.To, "to")
+	to, err := parseEndDateParam(query.To, "to", biz.Location())
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
-	from, to = defaultDateRange(from, to)
+	from, to = defaultDateRange(from, to, biz.Location())
 	if to.Before(from) {
 		response.Error(c, ErrInvalidDateRange(from.Format(dateLayout), to.Format(dateLayout)))
 		return
@@ -198,17 +213,17 @@ func (h *HttpHandler) GetInventoryAnalytics(c *gin.Context) {
 		return
 	}
 
-	from, err := parseDateParam(query.From, "from")
+	from, err := parseDateParam(query.From, "from", biz.Location())
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
-	to, err := parseDateParam(query.To, "to")
+	to, err := parseEndDateParam(query.To, "to", biz.Location())
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
-	from, to = defaultDateRange(from, to)
+	from, to = defaultDateRange(from, to, biz.Location())
 	if to.Before(from) {
 		response.Error(c, ErrInvalidDateRange(from.Format(dateLayout), to.Format(dateLayout)))
 		return
@@ -264,17 +279,17 @@ func (h *HttpHandler) GetCustomerAnalytics(c *gin.Context) {
 		return
 	}
 
-	from, err := parseDateParam(query.From, "from")
+	from, err := parseDateParam(query.From, "from", biz.Location())
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
-	to, err := parseDateParam(query.To, "to")
+	to, err := parseEndDateParam(query.To, "to", biz.Location())
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
-	from, to = defaultDateRange(from, to)
+	from, to = defaultDateRange(from, to, biz.Location())
 	if to.Before(from) {
 		response.Error(c, ErrInvalidDateRange(from.Format(dateLayout), to.Format(dateLayout)))
 		return
@@ -329,13 +344,13 @@ func (h *HttpHandler) GetFinancialPosition(c *gin.Context) {
 		return
 	}
 
-	asOf, err := parseDateParam(query.AsOf, "asOf")
+	asOf, err := parseEndDateParam(query.AsOf, "asOf", biz.Location())
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 	if asOf.IsZero() {
-		asOf = time.Now().UTC()
+		asOf = time.Now().In(biz.Location())
 	}
 
 	res, err := h.service.ComputeFinancialPosition(c.Request.Context(), actor, biz, asOf)
@@ -346,14 +361,20 @@ func (h *HttpHandler) GetFinancialPosition(c *gin.Context) {
 	response.SuccessJSON(c, http.StatusOK, res)
 }
 
+type profitAndLossQuery struct {
+	AsOf   string `form:"asOf" binding:"omitempty"`
+	Period string `form:"period" binding:"omitempty,oneof=all fiscal_year fiscal_quarter"`
+}
+
 // GetProfitAndLoss returns a profit and loss statement for the authenticated workspace.
 //
 // @Summary      Get profit and loss
-// @Description  Returns a profit and loss statement as of a date for the authenticated workspace
+// @Description  Returns a profit and loss statement as of a date for the authenticated workspace, from the start of the business or of the fiscal year or quarter containing the date
 // @Tags         analytics
 // @Produce      json
 // @Param        businessDescriptor path string true "Business descriptor"
 // @Param        asOf query string false "As-of date (YYYY-MM-DD). Default: today"
+// @Param        period query string false "Reporting period ending at asOf: all (default), fiscal_year or fiscal_quarter"
 // @Success      200 {object} analytics.ProfitAndLossStatement
 // @Failure      400 {object} problem.Problem
 // @Failure      401 {object} problem.Problem
@@ -368,7 +389,7 @@ func (h *HttpHandler) GetProfitAndLoss(c *gin.Context) {
 		return
 	}
 
-	var query reportAsOfQuery
+	var query profitAndLossQuery
 	if err := c.ShouldBindQuery(&query); err != nil {
 		response.Error(c, ErrInvalidQueryParams(err))
 		return
@@ -380,16 +401,23 @@ func (h *HttpHandler) GetProfitAndLoss(c *gin.Context) {
 		return
 	}
 
-	asOf, err := parseDateParam(query.AsOf, "asOf")
+	asOf, err := parseEndDateParam(query.AsOf, "asOf", biz.Location())
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 	if asOf.IsZero() {
-		asOf = time.Now().UTC()
+		asOf = time.Now().In(biz.Location())
+	}
+	var from time.Time
+	switch query.Period {
+	case "fiscal_year":
+		from = biz.ReportingCalendar().FiscalYearStartOf(asOf)
+	case "fiscal_quarter":
+		from = biz.ReportingCalendar().FiscalQuarterStartOf(asOf)
 	}
 
-	res, err := h.service.ComputeProfitAndLoss(c.Request.Context(), actor, biz, asOf)
+	res, err := h.service.ComputeProfitAndLoss(c.Request.Context(), actor, biz, from, asOf)
 	if err != nil {
 		response.Error(c, ErrAnalyticsQueryFailed(err))
 		return
@@ -431,13 +459,13 @@ func (h *HttpHandler) GetCashFlow(c *gin.Context) {
 		return
 	}
 
-	asOf, err := parseDateParam(query.AsOf, "asOf")
+	asOf, err := parseEndDateParam(query.AsOf, "asOf", biz.Location())
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 	if asOf.IsZero() {
-		asOf = time.Now().UTC()
+		asOf = time.Now().In(biz.Location())
 	}
 
 	res, err := h.service.ComputeCashFlow(c.Request.Context(), actor, biz, asOf)
@@ -447,3 +475,583 @@ func (h *HttpHandler) GetCashFlow(c *gin.Context) {
 	}
 	response.SuccessJSON(c, http.StatusOK, res)
 }
+
+type productProfitabilityQuery struct {
+	From string `form:"from" binding:"omitempty"`
+	To   string `form:"to" binding:"omitempty"`
+}
+
+// GetProductProfitability returns per-product profit, including allocated expenses, for the authenticated workspace.
+//
+// @Summary      Get product profitability
+// @Description  Returns revenue, COGS, allocated expenses and net profit per product for a date range
+// @Tags         analytics
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        from query string false "Start date (YYYY-MM-DD)"
+// @Param        to query string false "End date (YYYY-MM-DD)"
+// @Success      200 {object} analytics.ProductProfitabilityReport
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/analytics/reports/product-profitability [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetProductProfitability(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var query productProfitabilityQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, ErrInvalidQueryParams(err))
+		return
+	}
+
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	from, err := parseDateParam(query.From, "from", biz.Location())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	to, err := parseEndDateParam(query.To, "to", biz.Location())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	from, to = defaultDateRange(from, to, biz.Location())
+	if to.Before(from) {
+		response.Error(c, ErrInvalidDateRange(from.Format(dateLayout), to.Format(dateLayout)))
+		return
+	}
+
+	res, err := h.service.ComputeProductProfitability(c.Request.Context(), actor, biz, from, to)
+	if err != nil {
+		response.Error(c, ErrAnalyticsQueryFailed(err))
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, res)
+}
+
+type vatReturnQuery struct {
+	From   string `form:"from" binding:"omitempty"`
+	To     string `form:"to" binding:"omitempty"`
+	Format string `form:"format" binding:"omitempty,oneof=uk gcc"`
+}
+
+// GetVATReturn returns the VAT return of a filing period for the authenticated workspace.
+//
+// @Summary      Get VAT return
+// @Description  Returns output VAT from orders and input VAT from expenses for a filing period, laid out as a UK or GCC VAT return
+// @Tags         analytics
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        from query string false "Start of the filing period (YYYY-MM-DD). Default: start of the fiscal quarter containing to"
+// @Param        to query string false "End of the filing period (YYYY-MM-DD)"
+// @Param        format query string false "Return layout: uk or gcc (defaults from the business country)"
+// @Success      200 {object} analytics.VATReturn
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/analytics/reports/vat-return [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetVATReturn(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var query vatReturnQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, ErrInvalidQueryParams(err))
+		return
+	}
+
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	from, err := parseDateParam(query.From, "from", biz.Location())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	to, err := parseEndDateParam(query.To, "to", biz.Location())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	// returns are filed per quarter, so the period defaults to the fiscal quarter to date
+	if to.IsZero() {
+		to = time.Now().In(biz.Location())
+	}
+	if from.IsZero() {
+		from = biz.ReportingCalendar().FiscalQuarterStartOf(to)
+	}
+	if to.Before(from) {
+		response.Error(c, ErrInvalidDateRange(from.Format(dateLayout), to.Format(dateLayout)))
+		return
+	}
+	format := VATReturnFormat(query.Format)
+	if format == "" {
+		format = DefaultVATReturnFormat(biz)
+	}
+
+	res, err := h.service.ComputeVATReturn(c.Request.Context(), actor, biz, from, to, format)
+	if err != nil {
+		response.Error(c, ErrAnalyticsQueryFailed(err))
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, res)
+}
+
+// Monthly goals
+
+// ListMonthlyGoals returns the business' monthly goals, most recent month first.
+//
+// @Summary      List monthly goals
+// @Description  Returns the revenue, orders and new customers targets set per month
+// @Tags         analytics
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {array} analytics.MonthlyGoalResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/analytics/goals [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListMonthlyGoals(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	goals, err := h.service.ListMonthlyGoals(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, ErrAnalyticsQueryFailed(err))
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToMonthlyGoalResponses(goals))
+}
+
+type goalProgressQuery struct {
+	Month string `form:"month" binding:"omitempty"`
+}
+
+// GetGoalProgress returns progress against a month's goal with pace projections.
+//
+// @Summary      Get goal progress
+// @Description  Returns actuals vs. targets for a month, the month-end projection at the current pace and the daily pace still required
+// @Tags         analytics
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        month query string false "Month (YYYY-MM), defaults to the current month in the business timezone"
+// @Success      200 {object} analytics.GoalProgress
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/analytics/goals/progress [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetGoalProgress(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var query goalProgressQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, ErrInvalidQueryParams(err))
+		return
+	}
+
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	month := query.Month
+	if month == "" {
+		month = CurrentGoalMonth(biz)
+	}
+	progress, err := h.service.ComputeGoalProgress(c.Request.Context(), actor, biz, month)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, progress)
+}
+
+// UpsertMonthlyGoal sets the targets of a month.
+//
+// @Summary      Set monthly goal
+// @Description  Creates or replaces the targets of a month; omitted targets are cleared
+// @Tags         analytics
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        month path string true "Month (YYYY-MM)"
+// @Param        body body analytics.UpsertMonthlyGoalRequest true "Targets"
+// @Success      200 {object} analytics.MonthlyGoalResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/analytics/goals/{month} [put]
+// @Security     BearerAuth
+func (h *HttpHandler) UpsertMonthlyGoal(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var req UpsertMonthlyGoalRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	goal, err := h.service.UpsertMonthlyGoal(c.Request.Context(), actor, biz, c.Param("month"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToMonthlyGoalResponse(goal))
+}
+
+// DeleteMonthlyGoal removes the goal of a month.
+//
+// @Summary      Delete monthly goal
+// @Tags         analytics
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        month path string true "Month (YYYY-MM)"
+// @Success      204
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/analytics/goals/{month} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteMonthlyGoal(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.service.DeleteMonthlyGoal(c.Request.Context(), actor, biz, c.Param("month")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// Monthly budgets
+
+// ListMonthlyBudgets returns the business' monthly budgets, most recent month first.
+//
+// @Summary      List monthly budgets
+// @Description  Returns the expense category budgets and revenue target set per month
+// @Tags         analytics
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {array} analytics.MonthlyBudgetResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/analytics/budgets [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListMonthlyBudgets(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	budgets, err := h.service.ListMonthlyBudgets(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, ErrAnalyticsQueryFailed(err))
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToMonthlyBudgetResponses(budgets))
+}
+
+type budgetVsActualQuery struct {
+	Month string `form:"month" binding:"omitempty"`
+}
+
+// GetBudgetVsActual returns a month's budget against actual revenue and spending.
+//
+// @Summary      Get budget vs actual
+// @Description  Returns revenue and spending per expense category against the month's budget, with variances, burn rates and alerts
+// @Tags         analytics
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        month query string false "Month (YYYY-MM), defaults to the current month in the business timezone"
+// @Success      200 {object} analytics.BudgetVsActual
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/analytics/budgets/vs-actual [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetBudgetVsActual(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var query budgetVsActualQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, ErrInvalidQueryParams(err))
+		return
+	}
+
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	month := query.Month
+	if month == "" {
+		month = CurrentGoalMonth(biz)
+	}
+	report, err := h.service.ComputeBudgetVsActual(c.Request.Context(), actor, biz, month)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, report)
+}
+
+// UpsertMonthlyBudget sets the budget of a month.
+//
+// @Summary      Set monthly budget
+// @Description  Creates or replaces the budget of a month; omitted categories are not budgeted
+// @Tags         analytics
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        month path string true "Month (YYYY-MM)"
+// @Param        body body analytics.UpsertMonthlyBudgetRequest true "Budget"
+// @Success      200 {object} analytics.MonthlyBudgetResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/analytics/budgets/{month} [put]
+// @Security     BearerAuth
+func (h *HttpHandler) UpsertMonthlyBudget(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var req UpsertMonthlyBudgetRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	budget, err := h.service.UpsertMonthlyBudget(c.Request.Context(), actor, biz, c.Param("month"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, ToMonthlyBudgetResponse(budget))
+}
+
+// DeleteMonthlyBudget removes the budget of a month.
+//
+// @Summary      Delete monthly budget
+// @Tags         analytics
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        month path string true "Month (YYYY-MM)"
+// @Success      204
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/analytics/budgets/{month} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteMonthlyBudget(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.service.DeleteMonthlyBudget(c.Request.Context(), actor, biz, c.Param("month")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// Business comparison
+
+type businessComparisonQuery struct {
+	From            string `form:"from" binding:"omitempty"`
+	To              string `form:"to" binding:"omitempty"`
+	Currency        string `form:"currency" binding:"omitempty,len=3,alpha"`
+	Rates           string `form:"rates" binding:"omitempty"`
+	IncludeArchived bool   `form:"includeArchived"`
+}
+
+// parseExchangeRates parses "EUR:1.08,SAR:0.27" into currency -> rate.
+func parseExchangeRates(value string) (map[string]decimal.Decimal, error) {
+	rates := map[string]decimal.Decimal{}
+	if strings.TrimSpace(value) == "" {
+		return rates, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		cur, raw, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || len(cur) != 3 {
+			return nil, ErrInvalidExchangeRate(pair)
+		}
+		rate, err := decimal.NewFromString(raw)
+		if err != nil || !rate.IsPositive() {
+			return nil, ErrInvalidExchangeRate(pair)
+		}
+		rates[strings.ToUpper(cur)] = rate
+	}
+	return rates, nil
+}
+
+// CompareBusinesses returns the workspace's businesses side by side.
+//
+// @Summary      Compare businesses
+// @Description  Returns revenue, gross profit, margin, order count and average order value of every business in the workspace for the period, normalized to one currency with the given exchange rates
+// @Tags         analytics
+// @Produce      json
+// @Param        from query string false "Start date (YYYY-MM-DD, default: 30 days before to)"
+// @Param        to query string false "End date (YYYY-MM-DD, default: today)"
+// @Param        currency query string false "Comparison currency (default: the currency most businesses use)"
+// @Param        rates query string false "Exchange rates to the comparison currency, e.g. EUR:1.08,SAR:0.2667"
+// @Param        includeArchived query bool false "Include archived businesses"
+// @Success      200 {object} analytics.BusinessComparison
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/workspaces/analytics/business-comparison [get]
+// @Security     BearerAuth
+func (h *HttpHandler) CompareBusinesses(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var query businessComparisonQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, ErrInvalidQueryParams(err))
+		return
+	}
+
+	from, err := parseDateParam(query.From, "from", time.UTC)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	to, err := parseDateParam(query.To, "to", time.UTC)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -30)
+	}
+	if to.Before(from) {
+		response.Error(c, ErrInvalidDateRange(from.Format(dateLayout), to.Format(dateLayout)))
+		return
+	}
+	rates, err := parseExchangeRates(query.Rates)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	res, err := h.service.CompareBusinesses(c.Request.Context(), actor, &BusinessComparisonOptions{
+		From:            from.Format(dateLayout),
+		To:              to.Format(dateLayout),
+		Currency:        strings.ToUpper(query.Currency),
+		Rates:           rates,
+		IncludeArchived: query.IncludeArchived,
+	})
+	if err != nil {
+		response.Error(c, ErrAnalyticsQueryFailed(err))
+		return
+	}
+
+	response.SuccessJSON(c, http.StatusOK, res)
+}
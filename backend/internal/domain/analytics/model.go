@@ -22,6 +22,7 @@ type DashboardMetrics struct {
 	LiveOrderFunnel            []keyvalue.KeyValue    `json:"liveOrderFunnel"`            // This visualization gives an instant overview of your current operational workload. It shows where all non-completed orders are in the fulfillment process.
 	TopSellingProducts         []*inventory.Product   `json:"topSellingProducts"`         // This chart highlights your best-performing products over the last 30 days, helping you identify trends and make informed inventory decisions.
 	NewCustomersTimeSeries     *timeseries.TimeSeries `json:"newCustomersTimeSeries"`     // This chart tracks the number of new customers acquired each day over the past month, providing insights into customer growth trends.
+	BudgetAlerts               []BudgetAlert          `json:"budgetAlerts"`               // Expense categories of this month's budget that are over budget or burning through it too fast.
 }
 
 type SalesAnalytics struct {
@@ -99,14 +100,15 @@ type FinancialPosition struct {
 	AsOf       time.Time `json:"asOf"` // The end date of the reporting period.
 	// core totals
 	TotalAssets      decimal.Decimal `json:"totalAssets"`      // The total value of everything the business owns. (CurrentAssets + FixedAssets)
-	TotalLiabilities decimal.Decimal `json:"totalLiabilities"` // The total value of everything the business owes. For now, this is zero. because we are not tracking liabilities yet.
+	TotalLiabilities decimal.Decimal `json:"totalLiabilities"` // The total value of everything the business owes. (SupplierPayables)
 	TotalEquity      decimal.Decimal `json:"totalEquity"`      // The net value of the business (Assets - Liabilities). The value left over
 	// breakdown of assets
 	CashOnHand          decimal.Decimal `json:"cashOnHand"`          // Cash on Hand: The total cash business bank account (Revenue + Owner Investment) - (Expenses + Owner Draw + Asset Purchases)
 	TotalInventoryValue decimal.Decimal `json:"totalInventoryValue"` // The total cost value of all products available for sale.
 	CurrentAssets       decimal.Decimal `json:"currentAssets"`       // Short-term resources.  cashOnHand + totalInventoryValue
 	FixedAssets         decimal.Decimal `json:"fixedAssets"`         // Long-term resources. The total cost value of all owned assets (e.g., equipment, property)
-	// liabilities - for future use
+	// breakdown of liabilities
+	SupplierPayables decimal.Decimal `json:"supplierPayables"` // The value of goods received on purchase orders and not yet paid to suppliers.
 	// equity breakdown
 	OwnerInvestment  decimal.Decimal `json:"ownerInvestment"`  // The total amount of money the owner has invested into the business.
 	RetainedEarnings decimal.Decimal `json:"retainedEarnings"` // The cumulative net profit that has been reinvested in the business rather than distributed to the owner. (All-Time Revenue - All-Time COGS - All-Time OPEX)
@@ -116,12 +118,14 @@ type FinancialPosition struct {
 // ProfitAndLossStatement represents the financial performance of a business over a specific period. (Income Statement)
 type ProfitAndLossStatement struct {
 	BusinessID         string              `json:"businessID"`
+	From               *time.Time          `json:"from,omitempty"`     // The start of the reporting period; unset when it reaches back to the start of the business
 	AsOf               time.Time           `json:"asOf"`               // The end date of the reporting period
 	GrossProfit        decimal.Decimal     `json:"grossProfit"`        // The profit made directly from selling your products, before any other business expenses. Calculation: Revenue - Cost of Goods Sold
 	TotalExpenses      decimal.Decimal     `json:"totalExpenses"`      // The total of all operating expenses (OPEX) incurred in running the business.
 	NetProfit          decimal.Decimal     `json:"netProfit"`          // The final profit after all expenses have been deducted from gross profit. Calculation: Gross Profit - Total Expenses
-	Revenue            decimal.Decimal     `json:"revenue"`            // Total revenue generated from sales.
-	COGS               decimal.Decimal     `json:"cogs"`               // Cost of Goods Sold: The direct costs attributable to the production of the goods sold by the business.
+	Revenue            decimal.Decimal     `json:"revenue"`            // Total revenue generated from sales, net of returns and refunds.
+	Returns            decimal.Decimal     `json:"returns"`            // Revenue reversed by returned and refunded orders, already deducted from Revenue.
+	COGS               decimal.Decimal     `json:"cogs"`               // Cost of Goods Sold: The direct costs attributable to the production of the goods sold by the business, net of restocked returns.
 	ExpensesByCategory []keyvalue.KeyValue `json:"expensesByCategory"` // Breakdown of expenses by category
 }
 
@@ -134,7 +138,7 @@ type CashFlowStatement struct {
 	CashFromCustomers      decimal.Decimal `json:"cashFromCustomers"`      // Total cash received from customers (sales revenue) during the period.
 	CashFromOwner          decimal.Decimal `json:"cashFromOwner"`          // Total cash invested into the business by the owner during the period.
 	TotalCashIn            decimal.Decimal `json:"totalCashIn"`            // Total cash inflows (money coming into the business) during the period. Calculation: CashFromCustomers + CashFromOwner
-	InventoryPurchases     decimal.Decimal `json:"inventoryPurchases"`     // Total cash spent on purchasing inventory during the period.
+	InventoryPurchases     decimal.Decimal `json:"inventoryPurchases"`     // Total cash spent on purchasing inventory during the period, excluding goods received and not yet paid to suppliers.
 	OperatingExpenses      decimal.Decimal `json:"operatingExpenses"`      // Total cash spent on operating expenses (OPEX) during the period.
 	TotalBusinessOperation decimal.Decimal `json:"totalBusinessOperation"` // Total cash outflows (money going out of the business) during the period. Calculation: InventoryPurchases + OperatingExpenses
 	BusinessInvestments    decimal.Decimal `json:"businessInvestments"`    // Total cash spent on purchasing fixed assets (e.g., equipment, property) during the period.
@@ -142,3 +146,28 @@ type CashFlowStatement struct {
 	TotalCashOut           decimal.Decimal `json:"totalCashOut"`           // Total cash outflows (money going out of the business) during the period. Calculation: TotalBusinessOperation + BusinessInvestments + OwnerDraws
 	NetCashFlow            decimal.Decimal `json:"netCashFlow"`            // The total change in the business's cash balance during the period (Total Cash In - Total Cash Out). This can be positive (✅) or negative (🔻)
 }
+
+// ProductProfitability is the profit a single product made over a period once indirect costs allocated to it are included.
+type ProductProfitability struct {
+	ProductID         string          `json:"productId"`
+	ProductName       string          `json:"productName"`
+	QuantitySold      int64           `json:"quantitySold"`      // Units sold across all orders in the period.
+	Revenue           decimal.Decimal `json:"revenue"`           // Sum of order item totals (before order-level discounts, shipping and VAT).
+	COGS              decimal.Decimal `json:"cogs"`              // Cost of the units sold.
+	GrossProfit       decimal.Decimal `json:"grossProfit"`       // Revenue - COGS
+	AllocatedExpenses decimal.Decimal `json:"allocatedExpenses"` // Expenses allocated to the product directly, plus its revenue share of expenses allocated to its orders.
+	NetProfit         decimal.Decimal `json:"netProfit"`         // Gross Profit - Allocated Expenses
+}
+
+// ProductProfitabilityReport breaks profit down by product over a period, ordered by net profit.
+type ProductProfitabilityReport struct {
+	BusinessID          string                 `json:"businessID"`
+	From                time.Time              `json:"from"`
+	To                  time.Time              `json:"to"`
+	Products            []ProductProfitability `json:"products"`
+	TotalRevenue        decimal.Decimal        `json:"totalRevenue"`
+	TotalCOGS           decimal.Decimal        `json:"totalCogs"`
+	TotalExpenses       decimal.Decimal        `json:"totalExpenses"`       // All expenses that occurred in the period.
+	AllocatedExpenses   decimal.Decimal        `json:"allocatedExpenses"`   // The part of TotalExpenses attributed to products.
+	UnallocatedExpenses decimal.Decimal        `json:"unallocatedExpenses"` // General overhead not attributed to any product. (TotalExpenses - AllocatedExpenses)
+}
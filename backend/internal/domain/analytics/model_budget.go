@@ -0,0 +1,207 @@
+package analytics
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/accounting"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	MonthlyBudgetTable  = "monthly_budgets"
+	MonthlyBudgetPrefix = "bdg"
+)
+
+// BudgetLine caps the spending of one expense category for the month.
+type BudgetLine struct {
+	Category accounting.ExpenseCategory `json:"category" binding:"required"`
+	Amount   decimal.Decimal            `json:"amount" binding:"required"`
+}
+
+// BudgetLines is stored as a jsonb array on monthly budgets.
+type BudgetLines []BudgetLine
+
+func (l BudgetLines) Value() (driver.Value, error) {
+	if l == nil {
+		l = BudgetLines{}
+	}
+	b, err := json.Marshal([]BudgetLine(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (l *BudgetLines) Scan(value any) error {
+	if l == nil {
+		return problem.InternalError().WithError(errors.New("BudgetLines scan into nil receiver"))
+	}
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*l = BudgetLines{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return problem.InternalError().WithError(fmt.Errorf("BudgetLines: unsupported scan type %T", value))
+	}
+	var out []BudgetLine
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return err
+	}
+	*l = BudgetLines(out)
+	return nil
+}
+
+// MonthlyBudget holds a business' spending caps per expense category and its planned revenue
+// for one calendar month in its timezone, keyed like monthly goals. The month is unique among
+// live budgets so a deleted month can be set again.
+type MonthlyBudget struct {
+	gorm.Model
+	ID            string              `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID    string              `gorm:"column:business_id;type:text;not null;uniqueIndex:idx_business_budget_month,where:deleted_at IS NULL" json:"businessId"`
+	Business      *business.Business  `gorm:"foreignKey:BusinessID;references:ID" json:"business,omitempty"`
+	Month         string              `gorm:"column:month;type:text;not null;uniqueIndex:idx_business_budget_month,where:deleted_at IS NULL;index" json:"month"`
+	RevenueTarget decimal.NullDecimal `gorm:"column:revenue_target;type:numeric" json:"revenueTarget"`
+	Categories    BudgetLines         `gorm:"column:categories;type:jsonb;not null;default:'[]'" json:"categories"`
+}
+
+func (m *MonthlyBudget) TableName() string { return MonthlyBudgetTable }
+
+func (m *MonthlyBudget) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(MonthlyBudgetPrefix)
+	}
+	return
+}
+
+var MonthlyBudgetSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	Month      schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	Month:      schema.NewField("month", "month"),
+}
+
+// UpsertMonthlyBudgetRequest sets the budget of a month. Omitted categories are not budgeted
+// and a null revenue target is cleared; at least one of them is required.
+type UpsertMonthlyBudgetRequest struct {
+	RevenueTarget decimal.NullDecimal `json:"revenueTarget"`
+	Categories    []BudgetLine        `json:"categories" binding:"omitempty,dive"`
+}
+
+// MonthlyBudgetResponse is the API response for a monthly budget.
+type MonthlyBudgetResponse struct {
+	ID            string           `json:"id"`
+	Month         string           `json:"month"`
+	RevenueTarget *decimal.Decimal `json:"revenueTarget"`
+	Categories    []BudgetLine     `json:"categories"`
+	CreatedAt     time.Time        `json:"createdAt"`
+	UpdatedAt     time.Time        `json:"updatedAt"`
+}
+
+func ToMonthlyBudgetResponse(m *MonthlyBudget) MonthlyBudgetResponse {
+	resp := MonthlyBudgetResponse{
+		ID:         m.ID,
+		Month:      m.Month,
+		Categories: []BudgetLine(m.Categories),
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+	if resp.Categories == nil {
+		resp.Categories = []BudgetLine{}
+	}
+	if m.RevenueTarget.Valid {
+		v := m.RevenueTarget.Decimal
+		resp.RevenueTarget = &v
+	}
+	return resp
+}
+
+func ToMonthlyBudgetResponses(items []*MonthlyBudget) []MonthlyBudgetResponse {
+	out := make([]MonthlyBudgetResponse, 0, len(items))
+	for _, m := range items {
+		out = append(out, ToMonthlyBudgetResponse(m))
+	}
+	return out
+}
+
+// BudgetStatus tells how an expense category is burning through its budget.
+type BudgetStatus string
+
+const (
+	// BudgetStatusOnTrack means the month-end projection stays within the budget.
+	BudgetStatusOnTrack BudgetStatus = "on_track"
+	// BudgetStatusAtRisk means the budget is not spent yet, but will be at the current burn rate.
+	BudgetStatusAtRisk BudgetStatus = "at_risk"
+	// BudgetStatusOverBudget means the category already spent more than its budget.
+	BudgetStatusOverBudget BudgetStatus = "over_budget"
+)
+
+// BudgetCategoryVariance compares a category's spending with its budget. Variance is what is
+// left of the budget (negative once overspent); BurnRate is the average spend per elapsed day
+// and Projected extrapolates it to the full month.
+type BudgetCategoryVariance struct {
+	Category    accounting.ExpenseCategory `json:"category"`
+	Budget      decimal.Decimal            `json:"budget"`
+	Actual      decimal.Decimal            `json:"actual"`
+	Variance    decimal.Decimal            `json:"variance"`
+	PercentUsed decimal.Decimal            `json:"percentUsed"`
+	BurnRate    decimal.Decimal            `json:"burnRate"`
+	Projected   decimal.Decimal            `json:"projected"`
+	Status      BudgetStatus               `json:"status"`
+}
+
+// BudgetRevenueVariance compares the month's revenue with the budgeted revenue. Variance is
+// actual minus target, so a shortfall is negative.
+type BudgetRevenueVariance struct {
+	Target          decimal.Decimal `json:"target"`
+	Actual          decimal.Decimal `json:"actual"`
+	Variance        decimal.Decimal `json:"variance"`
+	PercentOfTarget decimal.Decimal `json:"percentOfTarget"`
+	Projected       decimal.Decimal `json:"projected"`
+	OnTrack         bool            `json:"onTrack"`
+}
+
+// BudgetAlert flags an expense category that is over budget or on pace to exceed it.
+type BudgetAlert struct {
+	Month     string                     `json:"month"`
+	Category  accounting.ExpenseCategory `json:"category"`
+	Status    BudgetStatus               `json:"status"`
+	Budget    decimal.Decimal            `json:"budget"`
+	Actual    decimal.Decimal            `json:"actual"`
+	Projected decimal.Decimal            `json:"projected"`
+}
+
+// BudgetVsActual is a month's budget against what was actually earned and spent so far.
+// ElapsedRatio is the share of the month that has passed (1 once it is over).
+type BudgetVsActual struct {
+	BusinessID    string                   `json:"businessId"`
+	Month         string                   `json:"month"`
+	Currency      string                   `json:"currency"`
+	From          time.Time                `json:"from"`
+	To            time.Time                `json:"to"`
+	DaysInMonth   int                      `json:"daysInMonth"`
+	DaysElapsed   int                      `json:"daysElapsed"`
+	ElapsedRatio  decimal.Decimal          `json:"elapsedRatio"`
+	Revenue       *BudgetRevenueVariance   `json:"revenue,omitempty"`
+	Categories    []BudgetCategoryVariance `json:"categories"`
+	TotalBudget   decimal.Decimal          `json:"totalBudget"`   // Sum of the category budgets.
+	TotalActual   decimal.Decimal          `json:"totalActual"`   // Spending of the budgeted categories.
+	TotalVariance decimal.Decimal          `json:"totalVariance"` // TotalBudget - TotalActual
+	Alerts        []BudgetAlert            `json:"alerts"`
+}
@@ -0,0 +1,60 @@
+package analytics
+
+import "github.com/shopspring/decimal"
+
+// BusinessComparison puts the sales performance of the workspace's businesses side by side.
+// Amounts of each business stay in its own currency; Normalized holds them in Currency when
+// an exchange rate is known, and Totals adds up the normalized businesses only.
+type BusinessComparison struct {
+	From         string                `json:"from"`
+	To           string                `json:"to"`
+	Currency     string                `json:"currency"`
+	Businesses   []BusinessPerformance `json:"businesses"`
+	Totals       ComparisonTotals      `json:"totals"`
+	MissingRates []string              `json:"missingRates"`
+}
+
+// BusinessPerformance is one business' sales performance over the period.
+// GrossMargin is gross profit as a percentage of revenue.
+type BusinessPerformance struct {
+	BusinessID        string                 `json:"businessId"`
+	Descriptor        string                 `json:"descriptor"`
+	Name              string                 `json:"name"`
+	Currency          string                 `json:"currency"`
+	Archived          bool                   `json:"archived"`
+	Revenue           decimal.Decimal        `json:"revenue"`
+	GrossProfit       decimal.Decimal        `json:"grossProfit"`
+	GrossMargin       decimal.Decimal        `json:"grossMargin"`
+	OrderCount        int64                  `json:"orderCount"`
+	AverageOrderValue decimal.Decimal        `json:"averageOrderValue"`
+	Normalized        *NormalizedPerformance `json:"normalized"`
+}
+
+// NormalizedPerformance is a business' amounts converted at Rate units of the comparison
+// currency per unit of the business currency.
+type NormalizedPerformance struct {
+	Rate              decimal.Decimal `json:"rate"`
+	Revenue           decimal.Decimal `json:"revenue"`
+	GrossProfit       decimal.Decimal `json:"grossProfit"`
+	AverageOrderValue decimal.Decimal `json:"averageOrderValue"`
+}
+
+// ComparisonTotals aggregates the normalized businesses in the comparison currency.
+type ComparisonTotals struct {
+	Revenue           decimal.Decimal `json:"revenue"`
+	GrossProfit       decimal.Decimal `json:"grossProfit"`
+	GrossMargin       decimal.Decimal `json:"grossMargin"`
+	OrderCount        int64           `json:"orderCount"`
+	AverageOrderValue decimal.Decimal `json:"averageOrderValue"`
+}
+
+// BusinessComparisonOptions selects the period, businesses and conversion of a comparison.
+// From and To are YYYY-MM-DD dates resolved in each business' timezone. Rates maps a currency
+// to how many units of Currency one unit of it is worth.
+type BusinessComparisonOptions struct {
+	From            string
+	To              string
+	Currency        string
+	Rates           map[string]decimal.Decimal
+	IncludeArchived bool
+}
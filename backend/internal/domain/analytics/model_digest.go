@@ -0,0 +1,85 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	WeeklyDigestDeliveryTable  = "weekly_digest_deliveries"
+	WeeklyDigestDeliveryStruct = "WeeklyDigestDelivery"
+	WeeklyDigestDeliveryPrefix = "wdd"
+	// DigestWeekLayout is the YYYY-MM-DD date of the Monday a digest week starts on.
+	DigestWeekLayout = "2006-01-02"
+)
+
+// WeeklyDigestDelivery records that a user received the digest of a week, so a rerun of
+// the job does not email them twice.
+type WeeklyDigestDelivery struct {
+	gorm.Model
+	ID          string    `gorm:"column:id;primaryKey;type:text" json:"id"`
+	WorkspaceID string    `gorm:"column:workspace_id;type:text;not null;index" json:"workspaceId"`
+	UserID      string    `gorm:"column:user_id;type:text;not null;uniqueIndex:idx_weekly_digest_user_week" json:"userId"`
+	Week        string    `gorm:"column:week;type:text;not null;uniqueIndex:idx_weekly_digest_user_week" json:"week"`
+	SentAt      time.Time `gorm:"column:sent_at;type:timestamptz;not null" json:"sentAt"`
+}
+
+func (m *WeeklyDigestDelivery) TableName() string { return WeeklyDigestDeliveryTable }
+
+func (m *WeeklyDigestDelivery) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(WeeklyDigestDeliveryPrefix)
+	}
+	return
+}
+
+var WeeklyDigestDeliverySchema = struct {
+	ID          schema.Field
+	WorkspaceID schema.Field
+	UserID      schema.Field
+	Week        schema.Field
+}{
+	ID:          schema.NewField("id", "id"),
+	WorkspaceID: schema.NewField("workspace_id", "workspaceId"),
+	UserID:      schema.NewField("user_id", "userId"),
+	Week:        schema.NewField("week", "week"),
+}
+
+// WeeklyDigest summarizes a week of activity across the businesses of a workspace.
+type WeeklyDigest struct {
+	WorkspaceID        string
+	Week               string
+	Businesses         []BusinessDigest
+	PendingInvitations int
+}
+
+// BusinessDigest is the activity of one business during a digest week, in its timezone.
+type BusinessDigest struct {
+	Business      *business.Business
+	From          time.Time
+	To            time.Time
+	Orders        int64
+	Revenue       decimal.Decimal
+	NewCustomers  int64
+	LowStockItems int64
+}
+
+// WeeklyDigestOptions selects the digests SendWeeklyDigests sends.
+type WeeklyDigestOptions struct {
+	// Week is the YYYY-MM-DD Monday the week to summarize starts on.
+	Week string
+	// WorkspaceID limits the run to one workspace; empty means all.
+	WorkspaceID string
+}
+
+// WeeklyDigestResult reports what a digest run did.
+type WeeklyDigestResult struct {
+	Sent    int
+	Skipped int
+	Failed  int
+}
@@ -0,0 +1,147 @@
+package analytics
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	MonthlyGoalTable  = "monthly_goals"
+	MonthlyGoalStruct = "MonthlyGoal"
+	MonthlyGoalPrefix = "goal"
+	// GoalMonthLayout is the YYYY-MM key goals are stored and addressed by.
+	GoalMonthLayout = "2006-01"
+)
+
+// GoalMetric is a KPI a monthly goal can target.
+type GoalMetric string
+
+const (
+	GoalMetricRevenue      GoalMetric = "revenue"
+	GoalMetricOrders       GoalMetric = "orders"
+	GoalMetricNewCustomers GoalMetric = "new_customers"
+)
+
+// MonthlyGoal holds a business' targets for one calendar month in its timezone. Unset
+// targets are not tracked. SummarySentAt marks the month-end summary email as sent.
+// The month is unique among live goals so a deleted month can be set again.
+type MonthlyGoal struct {
+	gorm.Model
+	ID                 string              `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID         string              `gorm:"column:business_id;type:text;not null;uniqueIndex:idx_business_goal_month,where:deleted_at IS NULL" json:"businessId"`
+	Business           *business.Business  `gorm:"foreignKey:BusinessID;references:ID" json:"business,omitempty"`
+	Month              string              `gorm:"column:month;type:text;not null;uniqueIndex:idx_business_goal_month,where:deleted_at IS NULL;index" json:"month"`
+	RevenueTarget      decimal.NullDecimal `gorm:"column:revenue_target;type:numeric" json:"revenueTarget"`
+	OrdersTarget       sql.NullInt64       `gorm:"column:orders_target" json:"ordersTarget"`
+	NewCustomersTarget sql.NullInt64       `gorm:"column:new_customers_target" json:"newCustomersTarget"`
+	SummarySentAt      sql.NullTime        `gorm:"column:summary_sent_at" json:"summarySentAt"`
+}
+
+func (m *MonthlyGoal) TableName() string { return MonthlyGoalTable }
+
+func (m *MonthlyGoal) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(MonthlyGoalPrefix)
+	}
+	return
+}
+
+var MonthlyGoalSchema = struct {
+	ID            schema.Field
+	BusinessID    schema.Field
+	Month         schema.Field
+	SummarySentAt schema.Field
+}{
+	ID:            schema.NewField("id", "id"),
+	BusinessID:    schema.NewField("business_id", "businessId"),
+	Month:         schema.NewField("month", "month"),
+	SummarySentAt: schema.NewField("summary_sent_at", "summarySentAt"),
+}
+
+// UpsertMonthlyGoalRequest sets the targets of a month. Omitted or null targets are cleared;
+// at least one target is required.
+type UpsertMonthlyGoalRequest struct {
+	RevenueTarget      decimal.NullDecimal `json:"revenueTarget"`
+	OrdersTarget       *int64              `json:"ordersTarget" binding:"omitempty,min=1"`
+	NewCustomersTarget *int64              `json:"newCustomersTarget" binding:"omitempty,min=1"`
+}
+
+// MonthlyGoalResponse is the API response for a monthly goal.
+type MonthlyGoalResponse struct {
+	ID                 string           `json:"id"`
+	Month              string           `json:"month"`
+	RevenueTarget      *decimal.Decimal `json:"revenueTarget"`
+	OrdersTarget       *int64           `json:"ordersTarget"`
+	NewCustomersTarget *int64           `json:"newCustomersTarget"`
+	SummarySentAt      *time.Time       `json:"summarySentAt,omitempty"`
+	CreatedAt          time.Time        `json:"createdAt"`
+	UpdatedAt          time.Time        `json:"updatedAt"`
+}
+
+func ToMonthlyGoalResponse(m *MonthlyGoal) MonthlyGoalResponse {
+	resp := MonthlyGoalResponse{
+		ID:        m.ID,
+		Month:     m.Month,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+	if m.RevenueTarget.Valid {
+		v := m.RevenueTarget.Decimal
+		resp.RevenueTarget = &v
+	}
+	if m.OrdersTarget.Valid {
+		v := m.OrdersTarget.Int64
+		resp.OrdersTarget = &v
+	}
+	if m.NewCustomersTarget.Valid {
+		v := m.NewCustomersTarget.Int64
+		resp.NewCustomersTarget = &v
+	}
+	if m.SummarySentAt.Valid {
+		t := m.SummarySentAt.Time
+		resp.SummarySentAt = &t
+	}
+	return resp
+}
+
+func ToMonthlyGoalResponses(items []*MonthlyGoal) []MonthlyGoalResponse {
+	out := make([]MonthlyGoalResponse, 0, len(items))
+	for _, m := range items {
+		out = append(out, ToMonthlyGoalResponse(m))
+	}
+	return out
+}
+
+// GoalMetricProgress compares one KPI with its target. Projected extrapolates the month's
+// pace so far to the full month; RequiredDailyPace is what is still needed per remaining day.
+type GoalMetricProgress struct {
+	Metric                   GoalMetric      `json:"metric"`
+	Target                   decimal.Decimal `json:"target"`
+	Actual                   decimal.Decimal `json:"actual"`
+	PercentOfTarget          decimal.Decimal `json:"percentOfTarget"`
+	Projected                decimal.Decimal `json:"projected"`
+	ProjectedPercentOfTarget decimal.Decimal `json:"projectedPercentOfTarget"`
+	RequiredDailyPace        decimal.Decimal `json:"requiredDailyPace"`
+	OnTrack                  bool            `json:"onTrack"`
+}
+
+// GoalProgress is a month's progress against its goal. ElapsedRatio is the share of the
+// month that has passed (1 once it is over).
+type GoalProgress struct {
+	BusinessID    string               `json:"businessId"`
+	Month         string               `json:"month"`
+	Currency      string               `json:"currency"`
+	From          time.Time            `json:"from"`
+	To            time.Time            `json:"to"`
+	DaysInMonth   int                  `json:"daysInMonth"`
+	DaysElapsed   int                  `json:"daysElapsed"`
+	DaysRemaining int                  `json:"daysRemaining"`
+	ElapsedRatio  decimal.Decimal      `json:"elapsedRatio"`
+	Metrics       []GoalMetricProgress `json:"metrics"`
+}
@@ -0,0 +1,48 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// VATReturnFormat is the layout of the boxes of a VAT return.
+type VATReturnFormat string
+
+const (
+	// VATReturnFormatUK follows the HMRC 9-box VAT return.
+	VATReturnFormatUK VATReturnFormat = "uk"
+	// VATReturnFormatGCC follows the GCC returns (UAE VAT 201, KSA VAT return): an amount and
+	// the VAT on it per line.
+	VATReturnFormatGCC VATReturnFormat = "gcc"
+)
+
+// VATReturn is the VAT owed or reclaimable for a filing period. Output VAT is booked when an
+// order is fulfilled and taken back when it is returned or refunded; input VAT is the VAT
+// recorded on expenses that occurred in the period.
+type VATReturn struct {
+	BusinessID     string          `json:"businessID"`
+	From           time.Time       `json:"from"`
+	To             time.Time       `json:"to"`
+	Currency       string          `json:"currency"`
+	Format         VATReturnFormat `json:"format"`
+	TaxedSales     decimal.Decimal `json:"taxedSales"`     // Sales that charged VAT, excluding the VAT, net of returns.
+	ZeroRatedSales decimal.Decimal `json:"zeroRatedSales"` // Sales that charged no VAT, net of returns.
+	TotalSales     decimal.Decimal `json:"totalSales"`     // TaxedSales + ZeroRatedSales
+	OutputVAT      decimal.Decimal `json:"outputVat"`      // VAT charged on sales, net of returns.
+	TaxedPurchases decimal.Decimal `json:"taxedPurchases"` // Expenses that carry recoverable VAT, excluding the VAT.
+	OtherPurchases decimal.Decimal `json:"otherPurchases"` // Expenses that carry no VAT.
+	TotalPurchases decimal.Decimal `json:"totalPurchases"` // TaxedPurchases + OtherPurchases
+	InputVAT       decimal.Decimal `json:"inputVat"`       // VAT recoverable on expenses.
+	NetVAT         decimal.Decimal `json:"netVat"`         // OutputVAT - InputVAT: owed when positive, reclaimable when negative.
+	Boxes          []VATReturnBox  `json:"boxes"`          // The totals laid out as the boxes of the return Format.
+}
+
+// VATReturnBox is one line of a VAT return. VAT is only set on GCC lines that report an
+// amount and the tax on it.
+type VATReturnBox struct {
+	Box    string           `json:"box"`
+	Label  string           `json:"label"`
+	Amount decimal.Decimal  `json:"amount"`
+	VAT    *decimal.Decimal `json:"vat,omitempty"`
+}
@@ -0,0 +1,159 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/email"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+)
+
+// Notification encapsulates email sending for analytics domain
+type Notification struct {
+	client     email.Client
+	info       email.EmailInfo
+	accountSvc *account.Service
+}
+
+// NewNotification wires the email client, defaults and account service
+func NewNotification(client email.Client, info email.EmailInfo, accountSvc *account.Service) *Notification {
+	return &Notification{client: client, info: info, accountSvc: accountSvc}
+}
+
+// SendMonthlyGoalSummaryEmail sends the workspace owner how the business did against its monthly goal
+func (n *Notification) SendMonthlyGoalSummaryEmail(ctx context.Context, biz *business.Business, progress *GoalProgress) error {
+	if n.client == nil {
+		return fmt.Errorf("email client not available")
+	}
+	logger := logger.FromContext(ctx).With("action", "sendMonthlyGoalSummary", "businessId", biz.ID, "month", progress.Month)
+	logger.Info("sending monthly goal summary email")
+
+	ws, err := n.accountSvc.GetWorkspaceByID(ctx, biz.WorkspaceID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to get workspace", "error", err)
+		return fmt.Errorf("failed to get workspace: %w", err)
+	}
+	user, err := n.accountSvc.GetUserByID(ctx, ws.OwnerID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to get workspace owner", "error", err)
+		return fmt.Errorf("failed to get workspace owner: %w", err)
+	}
+
+	achieved := 0
+	metrics := make([]map[string]any, 0, len(progress.Metrics))
+	for _, m := range progress.Metrics {
+		target, actual := m.Target.String(), m.Actual.String()
+		if m.Metric == GoalMetricRevenue {
+			target = fmt.Sprintf("%s %s", money.StringFixed(m.Target, progress.Currency), progress.Currency)
+			actual = fmt.Sprintf("%s %s", money.StringFixed(m.Actual, progress.Currency), progress.Currency)
+		}
+		reached := m.Actual.GreaterThanOrEqual(m.Target)
+		if reached {
+			achieved++
+		}
+		metrics = append(metrics, map[string]any{
+			"label":    goalMetricLabel(m.Metric),
+			"target":   target,
+			"actual":   actual,
+			"percent":  m.PercentOfTarget.StringFixed(0) + "%",
+			"achieved": reached,
+		})
+	}
+
+	monthLabel := progress.From.Format("January 2006")
+	data := map[string]any{
+		"userName":      n.getUserDisplayName(user),
+		"businessName":  biz.Name,
+		"monthLabel":    monthLabel,
+		"metrics":       metrics,
+		"achievedCount": achieved,
+		"metricCount":   len(metrics),
+		"dashboardURL":  fmt.Sprintf("%s/business/%s", n.info.BaseURL, biz.Descriptor),
+		"productName":   n.info.ProductName,
+		"supportEmail":  n.info.SupportEmail,
+		"helpURL":       n.info.HelpURL,
+		"currentYear":   fmt.Sprintf("%d", time.Now().Year()),
+	}
+	subject := fmt.Sprintf("%s: your %s goals summary", biz.Name, monthLabel)
+	from := n.info.FormattedFrom()
+	if _, err := n.client.SendTemplate(ctx, email.TemplateMonthlyGoalSummary, []string{user.Email}, from, subject, data); err != nil {
+		logger.Error("failed to send monthly goal summary email", "error", err)
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	logger.Info("monthly goal summary email sent successfully")
+	return nil
+}
+
+// SendWeeklyDigestEmail sends a workspace member the weekly digest of the workspace activity
+func (n *Notification) SendWeeklyDigestEmail(ctx context.Context, user *account.User, digest *WeeklyDigest) error {
+	if n.client == nil {
+		return fmt.Errorf("email client not available")
+	}
+	logger := logger.FromContext(ctx).With("action", "sendWeeklyDigest", "workspaceId", digest.WorkspaceID, "userId", user.ID, "week", digest.Week)
+	logger.Info("sending weekly digest email")
+
+	businesses := make([]map[string]any, 0, len(digest.Businesses))
+	for _, bd := range digest.Businesses {
+		businesses = append(businesses, map[string]any{
+			"name":          bd.Business.Name,
+			"orders":        bd.Orders,
+			"revenue":       fmt.Sprintf("%s %s", money.StringFixed(bd.Revenue, bd.Business.Currency), bd.Business.Currency),
+			"newCustomers":  bd.NewCustomers,
+			"lowStockItems": bd.LowStockItems,
+			"dashboardURL":  fmt.Sprintf("%s/business/%s", n.info.BaseURL, bd.Business.Descriptor),
+		})
+	}
+
+	start, _ := time.Parse(DigestWeekLayout, digest.Week)
+	weekLabel := fmt.Sprintf("%s – %s", start.Format("Jan 2"), start.AddDate(0, 0, 6).Format("Jan 2, 2006"))
+	data := map[string]any{
+		"userName":           n.getUserDisplayName(user),
+		"weekLabel":          weekLabel,
+		"businesses":         businesses,
+		"pendingInvitations": digest.PendingInvitations,
+		"dashboardURL":       fmt.Sprintf("%s/dashboard", n.info.BaseURL),
+		"preferencesURL":     fmt.Sprintf("%s/settings/profile", n.info.BaseURL),
+		"productName":        n.info.ProductName,
+		"supportEmail":       n.info.SupportEmail,
+		"helpURL":            n.info.HelpURL,
+		"currentYear":        fmt.Sprintf("%d", time.Now().Year()),
+	}
+	subject := fmt.Sprintf("Your weekly digest: %s", weekLabel)
+	from := n.info.FormattedFrom()
+	if _, err := n.client.SendTemplate(ctx, email.TemplateWeeklyDigest, []string{user.Email}, from, subject, data); err != nil {
+		logger.Error("failed to send weekly digest email", "error", err)
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	logger.Info("weekly digest email sent successfully")
+	return nil
+}
+
+func goalMetricLabel(metric GoalMetric) string {
+	switch metric {
+	case GoalMetricRevenue:
+		return "Revenue"
+	case GoalMetricOrders:
+		return "Orders"
+	case GoalMetricNewCustomers:
+		return "New customers"
+	default:
+		return string(metric)
+	}
+}
+
+func (n *Notification) getUserDisplayName(user *account.User) string {
+	if user.FirstName != "" {
+		if user.LastName != "" {
+			return fmt.Sprintf("%s %s", user.FirstName, user.LastName)
+		}
+		return user.FirstName
+	}
+	if user.LastName != "" {
+		return user.LastName
+	}
+	return "there"
+}
@@ -2,6 +2,7 @@ package analytics
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/abdelrahman146/kyora/internal/domain/account"
@@ -10,30 +11,44 @@ import (
 	"github.com/abdelrahman146/kyora/internal/domain/customer"
 	"github.com/abdelrahman146/kyora/internal/domain/inventory"
 	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/email"
 	"github.com/abdelrahman146/kyora/internal/platform/types/keyvalue"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
 	"github.com/shopspring/decimal"
 )
 
 type ServiceParams struct {
+	Storage    *Storage
 	Inventory  *inventory.Service
 	Orders     *order.Service
 	Accounting *accounting.Service
 	Customer   *customer.Service
+	Account    *account.Service
+	Business   *business.Service
+	Email      email.Client
 }
 
 type Service struct {
-	inventory  *inventory.Service
-	customer   *customer.Service
-	orders     *order.Service
-	accounting *accounting.Service
+	storage      *Storage
+	inventory    *inventory.Service
+	customer     *customer.Service
+	orders       *order.Service
+	accounting   *accounting.Service
+	business     *business.Service
+	account      *account.Service
+	Notification *Notification
 }
 
 func NewService(params *ServiceParams) *Service {
 	return &Service{
-		inventory:  params.Inventory,
-		orders:     params.Orders,
-		accounting: params.Accounting,
-		customer:   params.Customer,
+		storage:      params.Storage,
+		inventory:    params.Inventory,
+		orders:       params.Orders,
+		accounting:   params.Accounting,
+		customer:     params.Customer,
+		business:     params.Business,
+		account:      params.Account,
+		Notification: NewNotification(params.Email, email.NewEmail(), params.Account),
 	}
 }
 
@@ -70,11 +85,7 @@ func (s *Service) ComputeDashboardMetrics(ctx context.Context, actor *account.Us
 		return nil, err
 	}
 	// SafeToDrawAmount
-	cogs, err := s.orders.SumOrdersCOGS(ctx, actor, biz, time.Time{}, time.Time{})
-	if err != nil {
-		return nil, err
-	}
-	dashboard.SafeToDrawAmount, err = s.accounting.ComputeSafeToDrawAmount(ctx, actor, biz, dashboard.AllTimeRevenue, cogs, time.Time{}, time.Time{})
+	dashboard.SafeToDrawAmount, err = s.accounting.ComputeSafeToDrawAmount(ctx, actor, biz, time.Time{}, time.Time{})
 	if err != nil {
 		return nil, err
 	}
@@ -98,6 +109,11 @@ func (s *Service) ComputeDashboardMetrics(ctx context.Context, actor *account.Us
 	if err != nil {
 		return nil, err
 	}
+	// BudgetAlerts
+	dashboard.BudgetAlerts, err = s.ComputeBudgetAlerts(ctx, actor, biz)
+	if err != nil {
+		return nil, err
+	}
 	return dashboard, nil
 }
 
@@ -268,7 +284,7 @@ func (s *Service) ComputeCustomerAnalytics(ctx context.Context, actor *account.U
 		return nil, err
 	}
 	if uniquePurchasers > 0 {
-		analytics.AverageRevenuePerCustomer = totalRevenue.Div(decimal.NewFromInt(uniquePurchasers)).Round(2)
+		analytics.AverageRevenuePerCustomer = money.Round(totalRevenue.Div(decimal.NewFromInt(uniquePurchasers)), biz.Currency)
 	} else {
 		analytics.AverageRevenuePerCustomer = decimal.Zero
 	}
@@ -279,7 +295,7 @@ func (s *Service) ComputeCustomerAnalytics(ctx context.Context, actor *account.U
 		return nil, err
 	}
 	if newCustomers > 0 {
-		analytics.CustomerAcquisitionCost = marketingSpend.Div(decimal.NewFromInt(newCustomers)).Round(2)
+		analytics.CustomerAcquisitionCost = money.Round(marketingSpend.Div(decimal.NewFromInt(newCustomers)), biz.Currency)
 	} else {
 		analytics.CustomerAcquisitionCost = decimal.Zero
 	}
@@ -297,7 +313,7 @@ func (s *Service) ComputeCustomerAnalytics(ctx context.Context, actor *account.U
 		averageOrderFrequencey = decimal.NewFromInt(totalOrdersCount).Div(decimal.NewFromInt(uniquePurchasers))
 	}
 	// Customer Lifetime Value (CLV) = Average Order Value  x  Average Purchase Frequency
-	analytics.CustomerLifetimeValue = averageOrderValue.Mul(averageOrderFrequencey).Round(2)
+	analytics.CustomerLifetimeValue = money.Round(averageOrderValue.Mul(averageOrderFrequencey), biz.Currency)
 
 	// Average purchase frequency in the period = total orders / unique purchasers
 	analytics.AverageCustomerPurchaseFrequency = averageOrderFrequencey
@@ -347,6 +363,13 @@ func (s *Service) ComputeFinancialPosition(ctx context.Context, actor *account.U
 	}
 	financialPosition.TotalInventoryValue = invValue
 
+	// Goods received from suppliers and not yet paid
+	payables, err := s.inventory.SumSupplierLiabilities(ctx, actor, biz)
+	if err != nil {
+		return nil, err
+	}
+	financialPosition.SupplierPayables = payables
+
 	// Fixed assets purchased to date
 	fixedAssets, err := s.accounting.SumAssetsValue(ctx, actor, biz, time.Time{}, asOf)
 	if err != nil {
@@ -380,14 +403,22 @@ func (s *Service) ComputeFinancialPosition(ctx context.Context, actor *account.U
 	if err != nil {
 		return nil, err
 	}
+	reversals, err := s.accounting.SumOrderReversals(ctx, actor, biz, time.Time{}, asOf)
+	if err != nil {
+		return nil, err
+	}
+	totalRevenue = totalRevenue.Sub(reversals.Revenue)
+	totalCOGS = totalCOGS.Sub(reversals.COGS)
+	totalExpenses = totalExpenses.Sub(reversals.TransactionFees)
 
 	// Retained Earnings = All-Time Revenue - All-Time COGS - All-Time OPEX
 	financialPosition.RetainedEarnings = totalRevenue.Sub(totalCOGS).Sub(totalExpenses)
 
 	// Cash on Hand approximation:
-	// Cash = (Revenue + Owner Investment) - (Expenses + Owner Draws + Asset Purchases + Inventory Value)
+	// Cash = (Revenue + Owner Investment) - (Expenses + Owner Draws + Asset Purchases + Inventory Value - Supplier Payables)
+	// Inventory received on credit has not been paid for yet, so it did not cost cash.
 	cashInflows := totalRevenue.Add(ownerInvestment)
-	cashOutflows := totalExpenses.Add(ownerDraws).Add(fixedAssets).Add(invValue)
+	cashOutflows := totalExpenses.Add(ownerDraws).Add(fixedAssets).Add(invValue.Sub(payables))
 	financialPosition.CashOnHand = cashInflows.Sub(cashOutflows)
 
 	// Current Assets = Cash + Inventory
@@ -396,8 +427,8 @@ func (s *Service) ComputeFinancialPosition(ctx context.Context, actor *account.U
 	// Total Assets = Current Assets + Fixed Assets
 	financialPosition.TotalAssets = financialPosition.CurrentAssets.Add(financialPosition.FixedAssets)
 
-	// Liabilities are not tracked yet, set to zero
-	financialPosition.TotalLiabilities = decimal.Zero
+	// Total Liabilities = Supplier Payables
+	financialPosition.TotalLiabilities = financialPosition.SupplierPayables
 
 	// Total Equity = Assets - Liabilities
 	financialPosition.TotalEquity = financialPosition.TotalAssets.Sub(financialPosition.TotalLiabilities)
@@ -405,43 +436,57 @@ func (s *Service) ComputeFinancialPosition(ctx context.Context, actor *account.U
 	return financialPosition, nil
 }
 
-func (s *Service) ComputeProfitAndLoss(ctx context.Context, actor *account.User, biz *business.Business, asOf time.Time) (*ProfitAndLossStatement, error) {
+// ComputeProfitAndLoss returns the profit and loss of [from, asOf]; a zero from reaches back
+// to the first order.
+func (s *Service) ComputeProfitAndLoss(ctx context.Context, actor *account.User, biz *business.Business, from, asOf time.Time) (*ProfitAndLossStatement, error) {
 	statement := &ProfitAndLossStatement{
 		BusinessID: biz.ID,
 		AsOf:       asOf,
 	}
-	// Revenue up to asOf
-	revenue, err := s.orders.SumOrdersTotal(ctx, actor, biz, time.Time{}, asOf)
+	if !from.IsZero() {
+		statement.From = &from
+	}
+	// Revenue within [from, asOf]
+	revenue, err := s.orders.SumOrdersTotal(ctx, actor, biz, from, asOf)
 	if err != nil {
 		return nil, err
 	}
-	statement.Revenue = revenue
+	// Returns and refunds posted by accounting within [from, asOf]
+	reversals, err := s.accounting.SumOrderReversals(ctx, actor, biz, from, asOf)
+	if err != nil {
+		return nil, err
+	}
+	statement.Returns = reversals.Revenue
+	statement.Revenue = revenue.Sub(reversals.Revenue)
 
-	// COGS up to asOf
-	cogs, err := s.orders.SumOrdersCOGS(ctx, actor, biz, time.Time{}, asOf)
+	// COGS within [from, asOf]
+	cogs, err := s.orders.SumOrdersCOGS(ctx, actor, biz, from, asOf)
 	if err != nil {
 		return nil, err
 	}
-	statement.COGS = cogs
+	statement.COGS = cogs.Sub(reversals.COGS)
 
 	// Gross Profit = Revenue - COGS
 	statement.GrossProfit = statement.Revenue.Sub(statement.COGS)
 
-	// Operating Expenses up to asOf
-	totalExpenses, err := s.accounting.SumExpensesAmount(ctx, actor, biz, time.Time{}, asOf)
+	// Operating Expenses within [from, asOf]
+	totalExpenses, err := s.accounting.SumExpensesAmount(ctx, actor, biz, from, asOf)
 	if err != nil {
 		return nil, err
 	}
-	statement.TotalExpenses = totalExpenses
+	statement.TotalExpenses = totalExpenses.Sub(reversals.TransactionFees)
 
 	// Expense breakdown by category
 	categories := accounting.ExpenseCategoriesList()
 	breakdown := make([]keyvalue.KeyValue, 0, len(categories))
 	for _, cat := range categories {
-		amt, err := s.accounting.SumExpensesAmountByCategory(ctx, actor, biz, cat, time.Time{}, asOf)
+		amt, err := s.accounting.SumExpensesAmountByCategory(ctx, actor, biz, cat, from, asOf)
 		if err != nil {
 			return nil, err
 		}
+		if cat == accounting.ExpenseCategoryTransactionFee {
+			amt = amt.Sub(reversals.TransactionFees)
+		}
 		breakdown = append(breakdown, keyvalue.New(string(cat), amt))
 	}
 	statement.ExpensesByCategory = breakdown
@@ -457,10 +502,11 @@ func (s *Service) ComputeCashFlow(ctx context.Context, actor *account.User, biz
 		BusinessID: biz.ID,
 		AsOf:       asOf,
 	}
-	// We currently don't track liabilities or an inventory purchase ledger.
+	// We don't keep a full inventory purchase ledger.
 	// To stay consistent with ComputeFinancialPosition, we approximate cash flows on a cash-basis using:
 	// - Cash inflows: Revenue (cash from customers) + Owner investments
 	// - Cash outflows: Operating expenses + Owner draws + Fixed asset purchases + Inventory on hand (as a proxy for historical inventory purchases)
+	//   less the goods received and not yet paid to suppliers
 	// This keeps CashAtEnd aligned with FinancialPosition.CashOnHand.
 
 	// Inflows up to asOf (all-time to date)
@@ -468,7 +514,12 @@ func (s *Service) ComputeCashFlow(ctx context.Context, actor *account.User, biz
 	if err != nil {
 		return nil, err
 	}
-	statement.CashFromCustomers = revenue
+	// Refunded and returned orders give their revenue back; refunded fees come back too.
+	reversals, err := s.accounting.SumOrderReversals(ctx, actor, biz, time.Time{}, asOf)
+	if err != nil {
+		return nil, err
+	}
+	statement.CashFromCustomers = revenue.Sub(reversals.Revenue)
 
 	ownerInvestment, err := s.accounting.SumInvestmentsAmount(ctx, actor, biz, time.Time{}, asOf)
 	if err != nil {
@@ -482,14 +533,18 @@ func (s *Service) ComputeCashFlow(ctx context.Context, actor *account.User, biz
 	if err != nil {
 		return nil, err
 	}
-	statement.OperatingExpenses = totalExpenses
+	statement.OperatingExpenses = totalExpenses.Sub(reversals.TransactionFees)
 
 	// Inventory purchases proxy: current inventory value (aligns with FinancialPosition approximation)
 	invValue, err := s.inventory.SumInventoryValue(ctx, actor, biz)
 	if err != nil {
 		return nil, err
 	}
-	statement.InventoryPurchases = invValue
+	payables, err := s.inventory.SumSupplierLiabilities(ctx, actor, biz)
+	if err != nil {
+		return nil, err
+	}
+	statement.InventoryPurchases = invValue.Sub(payables)
 	statement.TotalBusinessOperation = statement.InventoryPurchases.Add(statement.OperatingExpenses)
 
 	// Investing outflows (fixed assets) up to asOf
@@ -519,3 +574,138 @@ func (s *Service) ComputeCashFlow(ctx context.Context, actor *account.User, biz
 
 	return statement, nil
 }
+
+// ComputeProductProfitability returns per-product profit for orders placed and expenses incurred within [from, to].
+//
+// Expenses allocated to a product count against it in full. Expenses allocated to an order are
+// spread over the order's products in proportion to their item totals (evenly when the order has
+// no item revenue). Sorted by net profit, highest first.
+func (s *Service) ComputeProductProfitability(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) (*ProductProfitabilityReport, error) {
+	report := &ProductProfitabilityReport{
+		BusinessID: biz.ID,
+		From:       from,
+		To:         to,
+	}
+
+	sales, err := s.orders.SumSalesByProduct(ctx, actor, biz, from, to)
+	if err != nil {
+		return nil, err
+	}
+	byProduct := make(map[string]*ProductProfitability, len(sales))
+	row := func(productID string) *ProductProfitability {
+		if p, ok := byProduct[productID]; ok {
+			return p
+		}
+		p := &ProductProfitability{ProductID: productID}
+		byProduct[productID] = p
+		return p
+	}
+	for _, ps := range sales {
+		p := row(ps.ProductID)
+		p.QuantitySold = ps.Quantity
+		p.Revenue = ps.Revenue
+		p.COGS = ps.COGS
+	}
+
+	productAllocations, err := s.accounting.SumAllocatedExpensesByTarget(ctx, actor, biz, accounting.AllocationTargetProduct, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for productID, amount := range productAllocations {
+		p := row(productID)
+		p.AllocatedExpenses = p.AllocatedExpenses.Add(amount)
+	}
+
+	orderAllocations, err := s.accounting.SumAllocatedExpensesByTarget(ctx, actor, biz, accounting.AllocationTargetOrder, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(orderAllocations) > 0 {
+		orderIDs := make([]string, 0, len(orderAllocations))
+		for orderID := range orderAllocations {
+			orderIDs = append(orderIDs, orderID)
+		}
+		shares, err := s.orders.SumItemRevenueByOrderProduct(ctx, actor, biz, orderIDs)
+		if err != nil {
+			return nil, err
+		}
+		sharesByOrder := make(map[string][]order.OrderProductRevenue, len(orderIDs))
+		for _, share := range shares {
+			sharesByOrder[share.OrderID] = append(sharesByOrder[share.OrderID], share)
+		}
+		for orderID, amount := range orderAllocations {
+			for productID, part := range splitByRevenue(amount, sharesByOrder[orderID], biz.Currency) {
+				p := row(productID)
+				p.AllocatedExpenses = p.AllocatedExpenses.Add(part)
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(byProduct))
+	for id := range byProduct {
+		ids = append(ids, id)
+	}
+	products, err := s.inventory.ListProductsByIDs(ctx, actor, biz, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, prod := range products {
+		byProduct[prod.ID].ProductName = prod.Name
+	}
+
+	report.Products = make([]ProductProfitability, 0, len(byProduct))
+	for _, p := range byProduct {
+		p.GrossProfit = p.Revenue.Sub(p.COGS)
+		p.NetProfit = p.GrossProfit.Sub(p.AllocatedExpenses)
+		report.TotalRevenue = report.TotalRevenue.Add(p.Revenue)
+		report.TotalCOGS = report.TotalCOGS.Add(p.COGS)
+		report.AllocatedExpenses = report.AllocatedExpenses.Add(p.AllocatedExpenses)
+		report.Products = append(report.Products, *p)
+	}
+	sort.Slice(report.Products, func(i, j int) bool {
+		a, b := report.Products[i], report.Products[j]
+		if !a.NetProfit.Equal(b.NetProfit) {
+			return a.NetProfit.GreaterThan(b.NetProfit)
+		}
+		return a.ProductID < b.ProductID
+	})
+
+	totalExpenses, err := s.accounting.SumExpensesAmount(ctx, actor, biz, from, to)
+	if err != nil {
+		return nil, err
+	}
+	report.TotalExpenses = totalExpenses
+	report.UnallocatedExpenses = totalExpenses.Sub(report.AllocatedExpenses)
+
+	return report, nil
+}
+
+// splitByRevenue spreads amount over the products of one order in proportion to their item revenue.
+// The rounding remainder goes to the last product so the parts add up to amount.
+func splitByRevenue(amount decimal.Decimal, shares []order.OrderProductRevenue, currency string) map[string]decimal.Decimal {
+	parts := make(map[string]decimal.Decimal, len(shares))
+	if len(shares) == 0 {
+		return parts
+	}
+	total := decimal.Zero
+	for _, share := range shares {
+		total = total.Add(share.Revenue)
+	}
+	remaining := amount
+	for i, share := range shares {
+		if i == len(shares)-1 {
+			parts[share.ProductID] = parts[share.ProductID].Add(remaining)
+			break
+		}
+		var part decimal.Decimal
+		if total.IsZero() {
+			part = amount.Div(decimal.NewFromInt(int64(len(shares))))
+		} else {
+			part = amount.Mul(share.Revenue).Div(total)
+		}
+		part = money.Round(part, currency)
+		parts[share.ProductID] = parts[share.ProductID].Add(part)
+		remaining = remaining.Sub(part)
+	}
+	return parts
+}
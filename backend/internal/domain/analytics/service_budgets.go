@@ -0,0 +1,223 @@
+package analytics
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/accounting"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
+)
+
+func (s *Service) ListMonthlyBudgets(ctx context.Context, actor *account.User, biz *business.Business) ([]*MonthlyBudget, error) {
+	return s.storage.budget.FindMany(ctx,
+		s.storage.budget.ScopeBusinessID(biz.ID),
+		s.storage.budget.WithOrderBy([]string{MonthlyBudgetSchema.Month.Column() + " DESC"}),
+	)
+}
+
+func (s *Service) GetMonthlyBudget(ctx context.Context, actor *account.User, biz *business.Business, month string) (*MonthlyBudget, error) {
+	if _, _, err := goalMonthRange(month, biz.Location()); err != nil {
+		return nil, err
+	}
+	budget, err := s.findMonthlyBudget(ctx, biz, month)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrBudgetNotFound(month, err)
+		}
+		return nil, err
+	}
+	return budget, nil
+}
+
+func (s *Service) findMonthlyBudget(ctx context.Context, biz *business.Business, month string) (*MonthlyBudget, error) {
+	return s.storage.budget.FindOne(ctx,
+		s.storage.budget.ScopeBusinessID(biz.ID),
+		s.storage.budget.ScopeEquals(MonthlyBudgetSchema.Month, month),
+	)
+}
+
+// UpsertMonthlyBudget replaces the budget of a month, creating it on first use.
+func (s *Service) UpsertMonthlyBudget(ctx context.Context, actor *account.User, biz *business.Business, month string, req *UpsertMonthlyBudgetRequest) (*MonthlyBudget, error) {
+	if _, _, err := goalMonthRange(month, biz.Location()); err != nil {
+		return nil, err
+	}
+	if !req.RevenueTarget.Valid && len(req.Categories) == 0 {
+		return nil, ErrBudgetRequired()
+	}
+	if req.RevenueTarget.Valid && !req.RevenueTarget.Decimal.IsPositive() {
+		return nil, ErrInvalidBudgetAmount("revenueTarget")
+	}
+	lines := make(BudgetLines, 0, len(req.Categories))
+	for _, line := range req.Categories {
+		if !slices.Contains(accounting.ExpenseCategoriesList(), line.Category) {
+			return nil, ErrInvalidBudgetCategory(line.Category)
+		}
+		if slices.ContainsFunc(lines, func(l BudgetLine) bool { return l.Category == line.Category }) {
+			return nil, ErrDuplicateBudgetCategory(line.Category)
+		}
+		if !line.Amount.IsPositive() {
+			return nil, ErrInvalidBudgetAmount(string(line.Category))
+		}
+		lines = append(lines, BudgetLine{Category: line.Category, Amount: money.Round(line.Amount, biz.Currency)})
+	}
+
+	budget, err := s.findMonthlyBudget(ctx, biz, month)
+	isNew := false
+	if err != nil {
+		if !database.IsRecordNotFound(err) {
+			return nil, err
+		}
+		budget = &MonthlyBudget{BusinessID: biz.ID, Month: month}
+		isNew = true
+	}
+	budget.RevenueTarget = decimal.NullDecimal{}
+	if req.RevenueTarget.Valid {
+		budget.RevenueTarget = decimal.NewNullDecimal(money.Round(req.RevenueTarget.Decimal, biz.Currency))
+	}
+	budget.Categories = lines
+
+	if isNew {
+		err = s.storage.budget.CreateOne(ctx, budget)
+	} else {
+		err = s.storage.budget.UpdateOne(ctx, budget)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return budget, nil
+}
+
+func (s *Service) DeleteMonthlyBudget(ctx context.Context, actor *account.User, biz *business.Business, month string) error {
+	budget, err := s.GetMonthlyBudget(ctx, actor, biz, month)
+	if err != nil {
+		return err
+	}
+	return s.storage.budget.DeleteOne(ctx, budget)
+}
+
+// ComputeBudgetVsActual compares the month's revenue and spending with its budget.
+func (s *Service) ComputeBudgetVsActual(ctx context.Context, actor *account.User, biz *business.Business, month string) (*BudgetVsActual, error) {
+	budget, err := s.GetMonthlyBudget(ctx, actor, biz, month)
+	if err != nil {
+		return nil, err
+	}
+	return s.computeBudgetVsActual(ctx, biz, budget, time.Now())
+}
+
+// ComputeBudgetAlerts returns the alerts of the current month's budget, none when the month
+// has no budget.
+func (s *Service) ComputeBudgetAlerts(ctx context.Context, actor *account.User, biz *business.Business) ([]BudgetAlert, error) {
+	budget, err := s.findMonthlyBudget(ctx, biz, CurrentGoalMonth(biz))
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return []BudgetAlert{}, nil
+		}
+		return nil, err
+	}
+	report, err := s.computeBudgetVsActual(ctx, biz, budget, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return report.Alerts, nil
+}
+
+// computeBudgetVsActual measures the budget's month as of now. Burn rates assume the pace so
+// far holds for the rest of the month; until any of the month has passed the projections
+// equal the actuals.
+func (s *Service) computeBudgetVsActual(ctx context.Context, biz *business.Business, budget *MonthlyBudget, now time.Time) (*BudgetVsActual, error) {
+	loc := biz.Location()
+	from, to, err := goalMonthRange(budget.Month, loc)
+	if err != nil {
+		return nil, err
+	}
+	now = now.In(loc)
+	report := &BudgetVsActual{
+		BusinessID:  biz.ID,
+		Month:       budget.Month,
+		Currency:    biz.Currency,
+		From:        from,
+		To:          to,
+		DaysInMonth: to.Day(),
+		Categories:  []BudgetCategoryVariance{},
+		Alerts:      []BudgetAlert{},
+	}
+	report.DaysElapsed, report.ElapsedRatio = monthElapsed(from, to, now)
+	project := func(actual decimal.Decimal) decimal.Decimal {
+		if report.ElapsedRatio.IsPositive() {
+			return money.Round(actual.Div(report.ElapsedRatio), biz.Currency)
+		}
+		return actual
+	}
+
+	// only the month so far counts toward the actuals
+	until := to
+	if now.Before(until) {
+		until = now
+	}
+	started := now.After(from)
+
+	if budget.RevenueTarget.Valid {
+		actual := decimal.Zero
+		if started {
+			if actual, err = s.orders.SumOrdersTotal(ctx, nil, biz, from, until); err != nil {
+				return nil, err
+			}
+		}
+		target := budget.RevenueTarget.Decimal
+		projected := project(actual)
+		report.Revenue = &BudgetRevenueVariance{
+			Target:          target,
+			Actual:          actual,
+			Variance:        actual.Sub(target),
+			PercentOfTarget: actual.Div(target).Mul(hundred).Round(2),
+			Projected:       projected,
+			OnTrack:         projected.GreaterThanOrEqual(target),
+		}
+	}
+
+	for _, line := range budget.Categories {
+		actual := decimal.Zero
+		if started {
+			if actual, err = s.accounting.SumExpensesAmountByCategory(ctx, nil, biz, line.Category, from, until); err != nil {
+				return nil, err
+			}
+		}
+		projected := project(actual)
+		v := BudgetCategoryVariance{
+			Category:    line.Category,
+			Budget:      line.Amount,
+			Actual:      actual,
+			Variance:    line.Amount.Sub(actual),
+			PercentUsed: actual.Div(line.Amount).Mul(hundred).Round(2),
+			BurnRate:    money.Round(projected.Div(decimal.NewFromInt(int64(report.DaysInMonth))), biz.Currency),
+			Projected:   projected,
+			Status:      BudgetStatusOnTrack,
+		}
+		switch {
+		case actual.GreaterThan(line.Amount):
+			v.Status = BudgetStatusOverBudget
+		case projected.GreaterThan(line.Amount):
+			v.Status = BudgetStatusAtRisk
+		}
+		report.Categories = append(report.Categories, v)
+		report.TotalBudget = report.TotalBudget.Add(v.Budget)
+		report.TotalActual = report.TotalActual.Add(v.Actual)
+		if v.Status != BudgetStatusOnTrack {
+			report.Alerts = append(report.Alerts, BudgetAlert{
+				Month:     budget.Month,
+				Category:  v.Category,
+				Status:    v.Status,
+				Budget:    v.Budget,
+				Actual:    v.Actual,
+				Projected: v.Projected,
+			})
+		}
+	}
+	report.TotalVariance = report.TotalBudget.Sub(report.TotalActual)
+	return report, nil
+}
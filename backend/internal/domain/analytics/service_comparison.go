@@ -0,0 +1,146 @@
+package analytics
+
+import (
+	"context"
+	"sort"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
+)
+
+// CompareBusinesses computes revenue, margin, order count and AOV for every business of the
+// actor's workspace. Businesses are ranked by normalized revenue; those without an exchange
+// rate to the comparison currency come last and are listed in MissingRates. Ties are
+// ordered by name.
+func (s *Service) CompareBusinesses(ctx context.Context, actor *account.User, opts *BusinessComparisonOptions) (*BusinessComparison, error) {
+	all, err := s.business.ListBusinesses(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	businesses := make([]*business.Business, 0, len(all))
+	for _, biz := range all {
+		if biz.ArchivedAt != nil && !opts.IncludeArchived {
+			continue
+		}
+		businesses = append(businesses, biz)
+	}
+
+	currency := opts.Currency
+	if currency == "" {
+		currency = predominantCurrency(businesses)
+	}
+	res := &BusinessComparison{
+		From:         opts.From,
+		To:           opts.To,
+		Currency:     currency,
+		Businesses:   make([]BusinessPerformance, 0, len(businesses)),
+		MissingRates: []string{},
+	}
+	totals := &res.Totals
+	totals.Revenue, totals.GrossProfit = decimal.Zero, decimal.Zero
+	missing := map[string]bool{}
+
+	for _, biz := range businesses {
+		perf, err := s.businessPerformance(ctx, actor, biz, opts)
+		if err != nil {
+			return nil, err
+		}
+		rate, ok := opts.Rates[biz.Currency]
+		if biz.Currency == currency {
+			rate, ok = decimal.NewFromInt(1), true
+		}
+		if ok {
+			perf.Normalized = &NormalizedPerformance{
+				Rate:              rate,
+				Revenue:           money.Round(perf.Revenue.Mul(rate), currency),
+				GrossProfit:       money.Round(perf.GrossProfit.Mul(rate), currency),
+				AverageOrderValue: money.Round(perf.AverageOrderValue.Mul(rate), currency),
+			}
+			totals.Revenue = totals.Revenue.Add(perf.Normalized.Revenue)
+			totals.GrossProfit = totals.GrossProfit.Add(perf.Normalized.GrossProfit)
+			totals.OrderCount += perf.OrderCount
+		} else if !missing[biz.Currency] {
+			missing[biz.Currency] = true
+			res.MissingRates = append(res.MissingRates, biz.Currency)
+		}
+		res.Businesses = append(res.Businesses, *perf)
+	}
+	totals.GrossMargin = marginPercent(totals.GrossProfit, totals.Revenue)
+	if totals.OrderCount > 0 {
+		totals.AverageOrderValue = money.Round(totals.Revenue.Div(decimal.NewFromInt(totals.OrderCount)), currency)
+	}
+	sort.Strings(res.MissingRates)
+
+	sort.SliceStable(res.Businesses, func(i, j int) bool {
+		a, b := res.Businesses[i].Normalized, res.Businesses[j].Normalized
+		switch {
+		case a != nil && b != nil && !a.Revenue.Equal(b.Revenue):
+			return a.Revenue.GreaterThan(b.Revenue)
+		case (a == nil) != (b == nil):
+			return a != nil
+		default:
+			return res.Businesses[i].Name < res.Businesses[j].Name
+		}
+	})
+	return res, nil
+}
+
+// businessPerformance computes a business' figures for the options' dates in its own timezone.
+func (s *Service) businessPerformance(ctx context.Context, actor *account.User, biz *business.Business, opts *BusinessComparisonOptions) (*BusinessPerformance, error) {
+	from, err := parseDateParam(opts.From, "from", biz.Location())
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseEndDateParam(opts.To, "to", biz.Location())
+	if err != nil {
+		return nil, err
+	}
+	perf := &BusinessPerformance{
+		BusinessID: biz.ID,
+		Descriptor: biz.Descriptor,
+		Name:       biz.Name,
+		Currency:   biz.Currency,
+		Archived:   biz.ArchivedAt != nil,
+	}
+	if perf.Revenue, err = s.orders.SumOrdersTotal(ctx, actor, biz, from, to); err != nil {
+		return nil, err
+	}
+	cogs, err := s.orders.SumOrdersCOGS(ctx, actor, biz, from, to)
+	if err != nil {
+		return nil, err
+	}
+	perf.GrossProfit = perf.Revenue.Sub(cogs)
+	perf.GrossMargin = marginPercent(perf.GrossProfit, perf.Revenue)
+	if perf.OrderCount, err = s.orders.CountOrdersByDateRange(ctx, actor, biz, from, to); err != nil {
+		return nil, err
+	}
+	if perf.OrderCount > 0 {
+		perf.AverageOrderValue = money.Round(perf.Revenue.Div(decimal.NewFromInt(perf.OrderCount)), biz.Currency)
+	}
+	return perf, nil
+}
+
+// marginPercent returns profit as a percentage of revenue with two decimals, or zero without revenue.
+func marginPercent(profit, revenue decimal.Decimal) decimal.Decimal {
+	if !revenue.IsPositive() {
+		return decimal.Zero
+	}
+	return profit.Div(revenue).Mul(hundred).Round(2)
+}
+
+// predominantCurrency is the currency most businesses use, ties broken alphabetically.
+func predominantCurrency(businesses []*business.Business) string {
+	counts := map[string]int{}
+	best := ""
+	for _, biz := range businesses {
+		counts[biz.Currency]++
+	}
+	for cur, n := range counts {
+		if best == "" || n > counts[best] || (n == counts[best] && cur < best) {
+			best = cur
+		}
+	}
+	return best
+}
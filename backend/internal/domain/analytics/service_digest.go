@@ -0,0 +1,141 @@
+package analytics
+
+import (
+	"context"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+)
+
+// PreviousDigestWeek returns the Monday of the last full week before now, in UTC.
+func PreviousDigestWeek(now time.Time) string {
+	now = now.UTC()
+	daysSinceMonday := (int(now.Weekday()) + 6) % 7
+	return now.AddDate(0, 0, -daysSinceMonday-7).Format(DigestWeekLayout)
+}
+
+// digestWeekRange returns the start and end of the week starting on the week Monday in loc.
+func digestWeekRange(week string, loc *time.Location) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation(DigestWeekLayout, week, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return start, start.AddDate(0, 0, 7), nil
+}
+
+// SendWeeklyDigests emails every workspace member who did not opt out a summary of the
+// week's orders, revenue, new customers, low-stock items and pending invitations across
+// the workspace businesses. Each member gets a week's digest once: reruns skip members
+// already emailed and retry failed sends.
+func (s *Service) SendWeeklyDigests(ctx context.Context, opts WeeklyDigestOptions) (*WeeklyDigestResult, error) {
+	start, err := time.Parse(DigestWeekLayout, opts.Week)
+	if err != nil {
+		return nil, ErrInvalidDigestWeek(opts.Week, err)
+	}
+	if start.Weekday() != time.Monday {
+		return nil, ErrInvalidDigestWeek(opts.Week, nil)
+	}
+	businesses, err := s.business.ListActiveBusinessesForJobs(ctx, opts.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WeeklyDigestResult{}
+	// businesses are ordered by workspace
+	for i := 0; i < len(businesses); {
+		j := i
+		for j < len(businesses) && businesses[j].WorkspaceID == businesses[i].WorkspaceID {
+			j++
+		}
+		s.sendWorkspaceDigest(ctx, businesses[i].WorkspaceID, opts.Week, businesses[i:j], result)
+		i = j
+	}
+	return result, nil
+}
+
+func (s *Service) sendWorkspaceDigest(ctx context.Context, workspaceID, week string, businesses []*business.Business, result *WeeklyDigestResult) {
+	log := logger.FromContext(ctx).With("workspaceId", workspaceID, "week", week)
+	recipients, err := s.account.ListWeeklyDigestRecipients(ctx, workspaceID)
+	if err != nil {
+		log.Error("failed to list weekly digest recipients", "error", err)
+		result.Failed++
+		return
+	}
+	pending := make([]*account.User, 0, len(recipients))
+	for _, user := range recipients {
+		_, err := s.storage.digest.FindOne(ctx,
+			s.storage.digest.ScopeEquals(WeeklyDigestDeliverySchema.UserID, user.ID),
+			s.storage.digest.ScopeEquals(WeeklyDigestDeliverySchema.Week, week),
+		)
+		switch {
+		case err == nil:
+			result.Skipped++
+		case database.IsRecordNotFound(err):
+			pending = append(pending, user)
+		default:
+			log.Error("failed to check weekly digest delivery", "userId", user.ID, "error", err)
+			result.Failed++
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	digest, err := s.computeWeeklyDigest(ctx, workspaceID, week, businesses)
+	if err != nil {
+		log.Error("failed to compute weekly digest", "error", err)
+		result.Failed += len(pending)
+		return
+	}
+	for _, user := range pending {
+		if err := s.Notification.SendWeeklyDigestEmail(ctx, user, digest); err != nil {
+			result.Failed++
+			continue
+		}
+		delivery := &WeeklyDigestDelivery{WorkspaceID: workspaceID, UserID: user.ID, Week: week, SentAt: time.Now().UTC()}
+		if err := s.storage.digest.CreateOne(ctx, delivery); err != nil {
+			log.Error("failed to record weekly digest delivery", "userId", user.ID, "error", err)
+			result.Failed++
+			continue
+		}
+		result.Sent++
+	}
+}
+
+// computeWeeklyDigest summarizes the week of each business in its own timezone.
+func (s *Service) computeWeeklyDigest(ctx context.Context, workspaceID, week string, businesses []*business.Business) (*WeeklyDigest, error) {
+	invitations, err := s.account.GetWorkspaceInvitations(ctx, workspaceID, account.InvitationStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	digest := &WeeklyDigest{
+		WorkspaceID:        workspaceID,
+		Week:               week,
+		Businesses:         make([]BusinessDigest, 0, len(businesses)),
+		PendingInvitations: len(invitations),
+	}
+	for _, biz := range businesses {
+		from, to, err := digestWeekRange(week, biz.Location())
+		if err != nil {
+			return nil, err
+		}
+		bd := BusinessDigest{Business: biz, From: from, To: to}
+		if bd.Orders, err = s.orders.CountOrdersByDateRange(ctx, nil, biz, from, to); err != nil {
+			return nil, err
+		}
+		if bd.Revenue, err = s.orders.SumOrdersTotal(ctx, nil, biz, from, to); err != nil {
+			return nil, err
+		}
+		if bd.NewCustomers, err = s.customer.CountCustomersByDateRange(ctx, nil, biz, from, to); err != nil {
+			return nil, err
+		}
+		if bd.LowStockItems, err = s.inventory.CountLowStockVariants(ctx, nil, biz); err != nil {
+			return nil, err
+		}
+		digest.Businesses = append(digest.Businesses, bd)
+	}
+	return digest, nil
+}
@@ -0,0 +1,285 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// goalMonthRange resolves a YYYY-MM month to its first and last instant in loc.
+func goalMonthRange(month string, loc *time.Location) (time.Time, time.Time, error) {
+	from, err := time.ParseInLocation(GoalMonthLayout, month, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidGoalMonth(month, err)
+	}
+	return from, from.AddDate(0, 1, 0).Add(-time.Microsecond), nil
+}
+
+// CurrentGoalMonth returns the business' current month as YYYY-MM.
+func CurrentGoalMonth(biz *business.Business) string {
+	return time.Now().In(biz.Location()).Format(GoalMonthLayout)
+}
+
+func (s *Service) ListMonthlyGoals(ctx context.Context, actor *account.User, biz *business.Business) ([]*MonthlyGoal, error) {
+	return s.storage.goal.FindMany(ctx,
+		s.storage.goal.ScopeBusinessID(biz.ID),
+		s.storage.goal.WithOrderBy([]string{MonthlyGoalSchema.Month.Column() + " DESC"}),
+	)
+}
+
+func (s *Service) GetMonthlyGoal(ctx context.Context, actor *account.User, biz *business.Business, month string) (*MonthlyGoal, error) {
+	if _, _, err := goalMonthRange(month, biz.Location()); err != nil {
+		return nil, err
+	}
+	goal, err := s.findMonthlyGoal(ctx, biz, month)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrGoalNotFound(month, err)
+		}
+		return nil, err
+	}
+	return goal, nil
+}
+
+func (s *Service) findMonthlyGoal(ctx context.Context, biz *business.Business, month string) (*MonthlyGoal, error) {
+	return s.storage.goal.FindOne(ctx,
+		s.storage.goal.ScopeBusinessID(biz.ID),
+		s.storage.goal.ScopeEquals(MonthlyGoalSchema.Month, month),
+	)
+}
+
+// UpsertMonthlyGoal replaces the targets of a month, creating its goal on first use.
+func (s *Service) UpsertMonthlyGoal(ctx context.Context, actor *account.User, biz *business.Business, month string, req *UpsertMonthlyGoalRequest) (*MonthlyGoal, error) {
+	if _, _, err := goalMonthRange(month, biz.Location()); err != nil {
+		return nil, err
+	}
+	if !req.RevenueTarget.Valid && req.OrdersTarget == nil && req.NewCustomersTarget == nil {
+		return nil, ErrGoalTargetsRequired()
+	}
+	if req.RevenueTarget.Valid && !req.RevenueTarget.Decimal.IsPositive() {
+		return nil, ErrInvalidGoalTarget(GoalMetricRevenue)
+	}
+
+	goal, err := s.findMonthlyGoal(ctx, biz, month)
+	isNew := false
+	if err != nil {
+		if !database.IsRecordNotFound(err) {
+			return nil, err
+		}
+		goal = &MonthlyGoal{BusinessID: biz.ID, Month: month}
+		isNew = true
+	}
+	goal.RevenueTarget = decimal.NullDecimal{}
+	if req.RevenueTarget.Valid {
+		goal.RevenueTarget = decimal.NewNullDecimal(money.Round(req.RevenueTarget.Decimal, biz.Currency))
+	}
+	goal.OrdersTarget = nullInt64(req.OrdersTarget)
+	goal.NewCustomersTarget = nullInt64(req.NewCustomersTarget)
+
+	if isNew {
+		err = s.storage.goal.CreateOne(ctx, goal)
+	} else {
+		err = s.storage.goal.UpdateOne(ctx, goal)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+func (s *Service) DeleteMonthlyGoal(ctx context.Context, actor *account.User, biz *business.Business, month string) error {
+	goal, err := s.GetMonthlyGoal(ctx, actor, biz, month)
+	if err != nil {
+		return err
+	}
+	return s.storage.goal.DeleteOne(ctx, goal)
+}
+
+// ComputeGoalProgress compares the month's actuals with its goal.
+func (s *Service) ComputeGoalProgress(ctx context.Context, actor *account.User, biz *business.Business, month string) (*GoalProgress, error) {
+	goal, err := s.GetMonthlyGoal(ctx, actor, biz, month)
+	if err != nil {
+		return nil, err
+	}
+	return s.computeGoalProgress(ctx, biz, goal, time.Now())
+}
+
+// computeGoalProgress measures the goal's month as of now. Projections assume the pace so far
+// holds for the rest of the month; until any of the month has passed they equal the actuals.
+func (s *Service) computeGoalProgress(ctx context.Context, biz *business.Business, goal *MonthlyGoal, now time.Time) (*GoalProgress, error) {
+	loc := biz.Location()
+	from, to, err := goalMonthRange(goal.Month, loc)
+	if err != nil {
+		return nil, err
+	}
+	now = now.In(loc)
+	daysInMonth := to.Day()
+	progress := &GoalProgress{
+		BusinessID:   biz.ID,
+		Month:        goal.Month,
+		Currency:     biz.Currency,
+		From:         from,
+		To:           to,
+		DaysInMonth:  daysInMonth,
+		ElapsedRatio: decimal.Zero,
+		Metrics:      []GoalMetricProgress{},
+	}
+	progress.DaysElapsed, progress.ElapsedRatio = monthElapsed(from, to, now)
+	progress.DaysRemaining = daysInMonth - progress.DaysElapsed
+
+	// only the month so far counts toward the actuals
+	until := to
+	if now.Before(until) {
+		until = now
+	}
+	if goal.RevenueTarget.Valid {
+		actual := decimal.Zero
+		if now.After(from) {
+			if actual, err = s.orders.SumOrdersTotal(ctx, nil, biz, from, until); err != nil {
+				return nil, err
+			}
+		}
+		progress.Metrics = append(progress.Metrics, goalMetricProgress(GoalMetricRevenue, goal.RevenueTarget.Decimal, actual, progress, 2))
+	}
+	if goal.OrdersTarget.Valid {
+		var count int64
+		if now.After(from) {
+			if count, err = s.orders.CountOrdersByDateRange(ctx, nil, biz, from, until); err != nil {
+				return nil, err
+			}
+		}
+		progress.Metrics = append(progress.Metrics, goalMetricProgress(GoalMetricOrders, decimal.NewFromInt(goal.OrdersTarget.Int64), decimal.NewFromInt(count), progress, 1))
+	}
+	if goal.NewCustomersTarget.Valid {
+		var count int64
+		if now.After(from) {
+			if count, err = s.customer.CountCustomersByDateRange(ctx, nil, biz, from, until); err != nil {
+				return nil, err
+			}
+		}
+		progress.Metrics = append(progress.Metrics, goalMetricProgress(GoalMetricNewCustomers, decimal.NewFromInt(goal.NewCustomersTarget.Int64), decimal.NewFromInt(count), progress, 1))
+	}
+	return progress, nil
+}
+
+// monthElapsed returns how many full days of the month [from, to] have passed at now, and the
+// share of the month that has passed.
+func monthElapsed(from, to, now time.Time) (int, decimal.Decimal) {
+	switch {
+	case !now.Before(to):
+		return to.Day(), decimal.NewFromInt(1)
+	case now.After(from):
+		// today is still in progress: it counts as remaining, not elapsed
+		total := to.Sub(from).Seconds()
+		return now.Day() - 1, decimal.NewFromFloat(now.Sub(from).Seconds() / total).Round(4)
+	}
+	return 0, decimal.Zero
+}
+
+func goalMetricProgress(metric GoalMetric, target, actual decimal.Decimal, progress *GoalProgress, places int32) GoalMetricProgress {
+	projected := actual
+	if progress.ElapsedRatio.IsPositive() {
+		projected = actual.Div(progress.ElapsedRatio).Round(places)
+	}
+	pace := decimal.Zero
+	if remaining := target.Sub(actual); remaining.IsPositive() && progress.DaysRemaining > 0 {
+		pace = remaining.Div(decimal.NewFromInt(int64(progress.DaysRemaining))).Round(places)
+	}
+	return GoalMetricProgress{
+		Metric:                   metric,
+		Target:                   target,
+		Actual:                   actual,
+		PercentOfTarget:          actual.Div(target).Mul(hundred).Round(2),
+		Projected:                projected,
+		ProjectedPercentOfTarget: projected.Div(target).Mul(hundred).Round(2),
+		RequiredDailyPace:        pace,
+		OnTrack:                  projected.GreaterThanOrEqual(target),
+	}
+}
+
+// MonthlyGoalSummaryOptions selects the goals SendMonthlyGoalSummaries reports on.
+type MonthlyGoalSummaryOptions struct {
+	// Month is the YYYY-MM month to summarize.
+	Month string
+	// BusinessID limits the run to one business; empty means all.
+	BusinessID string
+}
+
+// MonthlyGoalSummaryResult reports what a summary run did.
+type MonthlyGoalSummaryResult struct {
+	Sent    int
+	Pending int
+	Failed  int
+}
+
+// SendMonthlyGoalSummaries emails owners the final progress of the month's goals. Goals are
+// summarized once: a month that has not ended yet in the business timezone is left pending for
+// a later run, and failed sends are retried by the next run.
+func (s *Service) SendMonthlyGoalSummaries(ctx context.Context, opts MonthlyGoalSummaryOptions) (*MonthlyGoalSummaryResult, error) {
+	if _, err := time.Parse(GoalMonthLayout, opts.Month); err != nil {
+		return nil, ErrInvalidGoalMonth(opts.Month, err)
+	}
+	scopes := []func(db *gorm.DB) *gorm.DB{
+		s.storage.goal.ScopeEquals(MonthlyGoalSchema.Month, opts.Month),
+		s.storage.goal.ScopeIsNull(MonthlyGoalSchema.SummarySentAt),
+		s.storage.goal.WithPreload("Business"),
+	}
+	if opts.BusinessID != "" {
+		scopes = append(scopes, s.storage.goal.ScopeBusinessID(opts.BusinessID))
+	}
+	goals, err := s.storage.goal.FindMany(ctx, scopes...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MonthlyGoalSummaryResult{}
+	now := time.Now()
+	for _, goal := range goals {
+		log := logger.FromContext(ctx).With("goalId", goal.ID, "businessId", goal.BusinessID, "month", goal.Month)
+		if goal.Business == nil {
+			log.Warn("skipping goal summary: business not found")
+			continue
+		}
+		progress, err := s.computeGoalProgress(ctx, goal.Business, goal, now)
+		if err != nil {
+			log.Error("failed to compute goal progress", "error", err)
+			result.Failed++
+			continue
+		}
+		if now.Before(progress.To) {
+			result.Pending++
+			continue
+		}
+		if err := s.Notification.SendMonthlyGoalSummaryEmail(ctx, goal.Business, progress); err != nil {
+			result.Failed++
+			continue
+		}
+		// a targeted update: saving the goal would also upsert its preloaded business
+		if err := s.storage.db.Conn(ctx).Model(&MonthlyGoal{}).
+			Where("id = ?", goal.ID).
+			Update(MonthlyGoalSchema.SummarySentAt.Column(), now.UTC()).Error; err != nil {
+			log.Error("failed to mark goal summary as sent", "error", err)
+			result.Failed++
+			continue
+		}
+		result.Sent++
+	}
+	return result, nil
+}
+
+func nullInt64(v *int64) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *v, Valid: true}
+}
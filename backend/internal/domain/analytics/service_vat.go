@@ -0,0 +1,85 @@
+package analytics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultVATReturnFormat picks the return layout from the business' country: the UK 9-box
+// return for British businesses, the GCC layout otherwise.
+func DefaultVATReturnFormat(biz *business.Business) VATReturnFormat {
+	if strings.EqualFold(biz.CountryCode, "GB") {
+		return VATReturnFormatUK
+	}
+	return VATReturnFormatGCC
+}
+
+// ComputeVATReturn computes the VAT return of the filing period [from, to] from the ledger.
+func (s *Service) ComputeVATReturn(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time, format VATReturnFormat) (*VATReturn, error) {
+	totals, err := s.accounting.ComputeVATTotals(ctx, actor, biz, from, to)
+	if err != nil {
+		return nil, err
+	}
+	round := func(d decimal.Decimal) decimal.Decimal { return money.Round(d, biz.Currency) }
+	ret := &VATReturn{
+		BusinessID:     biz.ID,
+		From:           from,
+		To:             to,
+		Currency:       biz.Currency,
+		Format:         format,
+		TaxedSales:     round(totals.TaxedSales),
+		ZeroRatedSales: round(totals.ZeroRatedSales),
+		OutputVAT:      round(totals.OutputVAT),
+		TaxedPurchases: round(totals.TaxedPurchases),
+		OtherPurchases: round(totals.OtherPurchases),
+		InputVAT:       round(totals.InputVAT),
+	}
+	ret.TotalSales = ret.TaxedSales.Add(ret.ZeroRatedSales)
+	ret.TotalPurchases = ret.TaxedPurchases.Add(ret.OtherPurchases)
+	ret.NetVAT = ret.OutputVAT.Sub(ret.InputVAT)
+	if format == VATReturnFormatUK {
+		ret.Boxes = ukVATBoxes(ret)
+	} else {
+		ret.Boxes = gccVATBoxes(ret)
+	}
+	return ret, nil
+}
+
+// ukVATBoxes lays the return out as the HMRC 9-box return. Kyora does not track trade with
+// the EU, so boxes 2, 8 and 9 are zero. Box 5 is always positive, and boxes 6 and 7 are in
+// whole units as HMRC asks.
+func ukVATBoxes(ret *VATReturn) []VATReturnBox {
+	return []VATReturnBox{
+		{Box: "1", Label: "VAT due on sales and other outputs", Amount: ret.OutputVAT},
+		{Box: "2", Label: "VAT due on acquisitions from EU member states", Amount: decimal.Zero},
+		{Box: "3", Label: "Total VAT due", Amount: ret.OutputVAT},
+		{Box: "4", Label: "VAT reclaimed on purchases and other inputs", Amount: ret.InputVAT},
+		{Box: "5", Label: "Net VAT to pay or reclaim", Amount: ret.NetVAT.Abs()},
+		{Box: "6", Label: "Total value of sales and all other outputs excluding VAT", Amount: ret.TotalSales.Truncate(0)},
+		{Box: "7", Label: "Total value of purchases and all other inputs excluding VAT", Amount: ret.TotalPurchases.Truncate(0)},
+		{Box: "8", Label: "Total value of supplies of goods to EU member states excluding VAT", Amount: decimal.Zero},
+		{Box: "9", Label: "Total value of acquisitions of goods from EU member states excluding VAT", Amount: decimal.Zero},
+	}
+}
+
+// gccVATBoxes lays the return out as the GCC VAT return: standard-rated and zero-rated
+// supplies, standard-rated expenses, and the net tax of the period.
+func gccVATBoxes(ret *VATReturn) []VATReturnBox {
+	vat := func(d decimal.Decimal) *decimal.Decimal { return &d }
+	return []VATReturnBox{
+		{Box: "1", Label: "Standard rated supplies", Amount: ret.TaxedSales, VAT: vat(ret.OutputVAT)},
+		{Box: "4", Label: "Zero rated supplies", Amount: ret.ZeroRatedSales, VAT: vat(decimal.Zero)},
+		{Box: "8", Label: "Total sales", Amount: ret.TotalSales, VAT: vat(ret.OutputVAT)},
+		{Box: "9", Label: "Standard rated expenses", Amount: ret.TaxedPurchases, VAT: vat(ret.InputVAT)},
+		{Box: "11", Label: "Total expenses", Amount: ret.TaxedPurchases, VAT: vat(ret.InputVAT)},
+		{Box: "12", Label: "Total value of due tax for the period", Amount: ret.OutputVAT},
+		{Box: "13", Label: "Total value of recoverable tax for the period", Amount: ret.InputVAT},
+		{Box: "14", Label: "Payable tax for the period", Amount: ret.NetVAT},
+	}
+}
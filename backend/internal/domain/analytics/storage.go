@@ -0,0 +1,23 @@
+package analytics
+
+import (
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+)
+
+// Storage provides data access for analytics-owned records (monthly goals and budgets, digest deliveries).
+type Storage struct {
+	db     *database.Database
+	goal   *database.Repository[MonthlyGoal]
+	budget *database.Repository[MonthlyBudget]
+	digest *database.Repository[WeeklyDigestDelivery]
+}
+
+// NewStorage creates a new analytics storage instance.
+func NewStorage(db *database.Database) *Storage {
+	return &Storage{
+		db:     db,
+		goal:   database.NewRepository[MonthlyGoal](db),
+		budget: database.NewRepository[MonthlyBudget](db),
+		digest: database.NewRepository[WeeklyDigestDelivery](db),
+	}
+}
@@ -132,7 +132,8 @@ func (v *FileTypeValidator) GetMaxSize(category FileCategory) int64 {
 	return defaultMaxSizeBytes[FileCategoryOther]
 }
 
-// NeedsThumbnail returns true if the file category should generate thumbnails.
+// NeedsThumbnail returns true if the client should upload a thumbnail for the file category.
+// Image thumbnails are rendered by the server once the upload completes.
 func (v *FileTypeValidator) NeedsThumbnail(category FileCategory) bool {
-	return category == FileCategoryImage || category == FileCategoryVideo
+	return category == FileCategoryVideo
 }
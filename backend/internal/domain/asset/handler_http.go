@@ -45,8 +45,13 @@ func NewHttpHandler(svc *Service) *HttpHandler {
 // @Description  3. Client uploads entire file to URL with Content-Type header
 // @Description  4. Asset is ready immediately and can be referenced
 // @Description
+// @Description  **Image Processing:**
+// @Description  - Once a JPEG, PNG or GIF upload completes, the server renders a thumbnail (400px) and a WebP rendition (1600px)
+// @Description  - Thumbnail descriptors are only returned for videos, whose thumbnail the client uploads
+// @Description
 // @Description  **Using Uploaded Assets:**
 // @Description  - After upload completes, use the returned assetId and publicUrl in product photos or business logo
+// @Description  - Product photos attached by assetId carry thumbnailUrl, webpUrl and the image size
 // @Description  - Pass AssetReference object: `{"url": "<publicUrl>", "assetId": "<assetId>", "metadata": {"altText": "...", "caption": "..."}}`
 // @Description  - assetId is optional but enables automatic garbage collection
 // @Description
@@ -38,12 +38,26 @@ type Asset struct {
 	SizeBytes       int64              `gorm:"column:size_bytes;type:bigint;not null" json:"sizeBytes"`
 	LocalFilePath   string             `gorm:"column:local_file_path;type:text" json:"-"`
 
+	// Pixel size of images the server could decode, 0 otherwise.
+	Width  int `gorm:"column:width;type:integer;default:0" json:"width,omitempty"`
+	Height int `gorm:"column:height;type:integer;default:0" json:"height,omitempty"`
+
+	// ParentAssetID is set on thumbnails and renditions: derived copies of another asset that
+	// are deleted with it and never processed themselves.
+	ParentAssetID string `gorm:"column:parent_asset_id;type:text;default:'';index" json:"parentAssetId,omitempty"`
+
 	// Thumbnail support (tight coupling)
 	ThumbnailAssetID   *string `gorm:"column:thumbnail_asset_id;type:text;index" json:"thumbnailAssetId,omitempty"`
 	ThumbnailObjectKey string  `gorm:"column:thumbnail_object_key;type:text" json:"thumbnailObjectKey,omitempty"`
 	ThumbnailPublicURL string  `gorm:"column:thumbnail_public_url;type:text" json:"thumbnailPublicUrl,omitempty"`
 	ThumbnailCDNURL    string  `gorm:"column:thumbnail_cdn_url;type:text" json:"thumbnailCdnUrl,omitempty"`
 
+	// WebP rendition rendered by the server for images
+	WebPAssetID   *string `gorm:"column:webp_asset_id;type:text;index" json:"webpAssetId,omitempty"`
+	WebPObjectKey string  `gorm:"column:webp_object_key;type:text" json:"webpObjectKey,omitempty"`
+	WebPPublicURL string  `gorm:"column:webp_public_url;type:text" json:"webpPublicUrl,omitempty"`
+	WebPCDNURL    string  `gorm:"column:webp_cdn_url;type:text" json:"webpCdnUrl,omitempty"`
+
 	// Multipart upload tracking (S3 only)
 	UploadID       string          `gorm:"column:upload_id;type:text" json:"uploadId,omitempty"`
 	IsMultipart    bool            `gorm:"column:is_multipart;type:boolean;default:false" json:"isMultipart"`
@@ -63,10 +77,12 @@ var AssetSchema = struct {
 	ContentType        schema.Field
 	FileCategory       schema.Field
 	SizeBytes          schema.Field
+	ParentAssetID      schema.Field
 	ThumbnailAssetID   schema.Field
 	ThumbnailObjectKey schema.Field
 	ThumbnailPublicURL schema.Field
 	ThumbnailCDNURL    schema.Field
+	WebPAssetID        schema.Field
 	UploadID           schema.Field
 	IsMultipart        schema.Field
 	TotalParts         schema.Field
@@ -84,10 +100,12 @@ var AssetSchema = struct {
 	ContentType:        schema.NewField("content_type", "contentType"),
 	FileCategory:       schema.NewField("file_category", "fileCategory"),
 	SizeBytes:          schema.NewField("size_bytes", "sizeBytes"),
+	ParentAssetID:      schema.NewField("parent_asset_id", "parentAssetId"),
 	ThumbnailAssetID:   schema.NewField("thumbnail_asset_id", "thumbnailAssetId"),
 	ThumbnailObjectKey: schema.NewField("thumbnail_object_key", "thumbnailObjectKey"),
 	ThumbnailPublicURL: schema.NewField("thumbnail_public_url", "thumbnailPublicUrl"),
 	ThumbnailCDNURL:    schema.NewField("thumbnail_cdn_url", "thumbnailCdnUrl"),
+	WebPAssetID:        schema.NewField("webp_asset_id", "webpAssetId"),
 	UploadID:           schema.NewField("upload_id", "uploadId"),
 	IsMultipart:        schema.NewField("is_multipart", "isMultipart"),
 	TotalParts:         schema.NewField("total_parts", "totalParts"),
@@ -105,6 +123,38 @@ func (m *Asset) BeforeCreate(tx *gorm.DB) (err error) {
 	return nil
 }
 
+// PhotoReference returns the reference other domains store for this asset. URLs are CDN URLs
+// with the storage provider URLs as fallbacks. The WebP rendition and pixel size are included
+// once the server has processed the image.
+func (m *Asset) PhotoReference() AssetReference {
+	ref := AssetReference{
+		URL:     m.CDNURL,
+		AssetID: &m.ID,
+	}
+	if ref.URL == "" {
+		ref.URL = m.PublicURL
+	}
+	if m.PublicURL != "" && m.PublicURL != ref.URL {
+		ref.OriginalURL = &m.PublicURL
+	}
+	if m.ThumbnailCDNURL != "" {
+		ref.ThumbnailURL = &m.ThumbnailCDNURL
+		if m.ThumbnailPublicURL != "" && m.ThumbnailPublicURL != m.ThumbnailCDNURL {
+			ref.ThumbnailOriginalURL = &m.ThumbnailPublicURL
+		}
+	}
+	if m.WebPCDNURL != "" {
+		ref.WebPURL = &m.WebPCDNURL
+	} else if m.WebPPublicURL != "" {
+		ref.WebPURL = &m.WebPPublicURL
+	}
+	if m.Width > 0 && m.Height > 0 {
+		width, height := m.Width, m.Height
+		ref.Metadata = &AssetMetadata{Width: &width, Height: &height}
+	}
+	return ref
+}
+
 // AssetMetadata and AssetReference are defined in internal/platform/types/asset
 // to avoid circular dependencies with other domains.
 type AssetMetadata = asset.AssetMetadata
@@ -16,6 +16,7 @@ import (
 	"github.com/abdelrahman146/kyora/internal/platform/blob"
 	"github.com/abdelrahman146/kyora/internal/platform/config"
 	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
 	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
 	"github.com/spf13/viper"
 )
@@ -225,7 +226,7 @@ func (s *Service) generateS3MultipartUpload(ctx context.Context, asset *Asset, f
 	}
 
 	// Get public URL
-	publicURL, ok := s.blob.PublicURL(asset.ObjectKey)
+	publicURL, ok := blob.ForContext(ctx, s.blob).PublicURL(asset.ObjectKey)
 	if !ok {
 		publicURL = ""
 	}
@@ -318,6 +319,15 @@ func (s *Service) CompleteMultipartUpload(ctx context.Context, actor *account.Us
 		return err
 	}
 
+	if s.needsProcessing(asset) {
+		data, err := s.blob.Get(ctx, asset.ObjectKey)
+		if err != nil {
+			logger.FromContext(ctx).Warn("failed to read uploaded image for processing", "assetId", asset.ID, "error", err)
+			return nil
+		}
+		s.processImage(ctx, asset, data)
+	}
+
 	return nil
 }
 
@@ -381,9 +391,100 @@ func (s *Service) StoreLocalContent(ctx context.Context, assetID string, content
 		}
 	}
 
+	if s.needsProcessing(asset) {
+		data, err := os.ReadFile(asset.LocalFilePath)
+		if err != nil {
+			logger.FromContext(ctx).Warn("failed to read uploaded image for processing", "assetId", asset.ID, "error", err)
+			return nil
+		}
+		s.processImage(ctx, asset, data)
+	}
+
+	return nil
+}
+
+// StoreContent stores content the backend received itself (e.g. an emailed or uploaded
+// receipt) as a new asset of the business, writing it locally or to blob storage.
+func (s *Service) StoreContent(ctx context.Context, biz *business.Business, createdByUserID, fileName, contentType string, data []byte) (*Asset, error) {
+	if !s.validator.IsAllowed(fileName, contentType) {
+		return nil, problem.BadRequest("file type not allowed").
+			With("fileName", fileName).
+			With("contentType", contentType)
+	}
+	category := s.validator.GetCategory(contentType)
+	if maxSize := s.validator.GetMaxSize(category); int64(len(data)) > maxSize {
+		return nil, problem.BadRequest("file too large").
+			With("maxSize", maxSize).
+			With("actualSize", len(data)).
+			With("category", category)
+	}
+
+	asset := &Asset{
+		WorkspaceID:     biz.WorkspaceID,
+		BusinessID:      biz.ID,
+		CreatedByUserID: createdByUserID,
+		ContentType:     contentType,
+		FileCategory:    string(category),
+		SizeBytes:       int64(len(data)),
+	}
+	if err := asset.BeforeCreate(nil); err != nil {
+		return nil, err
+	}
+	asset.ObjectKey = s.buildObjectKey(biz.ID, asset.ID, fileName)
+
+	if err := s.putContent(ctx, asset, data); err != nil {
+		return nil, err
+	}
+	if err := s.storage.Create(ctx, asset); err != nil {
+		s.removeContent(ctx, asset)
+		return nil, err
+	}
+	s.processImage(ctx, asset, data)
+	return asset, nil
+}
+
+// putContent writes data for a new asset locally or to blob storage, depending on the
+// configured provider, and sets the asset's location and URLs.
+func (s *Service) putContent(ctx context.Context, asset *Asset, data []byte) error {
+	provider := viper.GetString(config.StorageProvider)
+	if provider == "local" || provider == "" {
+		asset.LocalFilePath = filepath.Join(s.localDir, asset.ID)
+		if err := os.MkdirAll(filepath.Dir(asset.LocalFilePath), 0755); err != nil {
+			return problem.InternalError().WithError(err)
+		}
+		if err := os.WriteFile(asset.LocalFilePath, data, 0644); err != nil {
+			return problem.InternalError().WithError(err)
+		}
+		asset.PublicURL = s.buildPublicURL(asset)
+	} else {
+		if s.blob == nil {
+			return problem.InternalError()
+		}
+		if err := s.blob.Put(ctx, asset.ObjectKey, asset.ContentType, data); err != nil {
+			return problem.InternalError().WithError(err)
+		}
+		asset.PublicURL, _ = blob.ForContext(ctx, s.blob).PublicURL(asset.ObjectKey)
+	}
+	asset.CDNURL = GenerateCDNURL(asset.PublicURL)
 	return nil
 }
 
+// removeContent deletes the stored content of an asset. Content that is already gone is not
+// an error; other failures are logged, leaving the object for storage lifecycle rules.
+func (s *Service) removeContent(ctx context.Context, asset *Asset) {
+	var err error
+	if asset.LocalFilePath != "" {
+		if err = os.Remove(asset.LocalFilePath); os.IsNotExist(err) {
+			err = nil
+		}
+	} else if s.blob != nil && asset.ObjectKey != "" {
+		err = s.blob.Delete(ctx, asset.ObjectKey)
+	}
+	if err != nil {
+		logger.FromContext(ctx).Warn("failed to remove asset content", "assetId", asset.ID, "error", err)
+	}
+}
+
 // GetPublicAsset returns an asset for public serving.
 // No authentication required - assets are public by design.
 func (s *Service) GetPublicAsset(ctx context.Context, assetID string) (*Asset, error) {
@@ -398,6 +499,58 @@ func (s *Service) GetPublicAsset(ctx context.Context, assetID string) (*Asset, e
 	return asset, nil
 }
 
+// ReadContent returns the content and content type of an asset of the business, for the
+// backend to embed it (e.g. the business logo on an invoice).
+func (s *Service) ReadContent(ctx context.Context, biz *business.Business, assetID string) ([]byte, string, error) {
+	assets, err := s.storage.ListByIDs(ctx, biz.ID, []string{assetID})
+	if err != nil {
+		return nil, "", err
+	}
+	if len(assets) == 0 {
+		return nil, "", problem.NotFound("asset not found").With("assetId", assetID)
+	}
+	a := assets[0]
+	if a.LocalFilePath != "" {
+		data, err := os.ReadFile(a.LocalFilePath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, "", ErrAssetFileNotFound()
+			}
+			return nil, "", ErrAssetReadFailed(err)
+		}
+		return data, a.ContentType, nil
+	}
+	if s.blob == nil {
+		return nil, "", ErrAssetNotAccessible()
+	}
+	data, err := s.blob.Get(ctx, a.ObjectKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, a.ContentType, nil
+}
+
+// PhotoReferencesByAssetID builds photo references for the uploaded image assets of the business
+// among assetIDs, keyed by asset ID. Unknown and non-image assets are left out. Thumbnail URLs
+// are included once the asset has a thumbnail.
+func (s *Service) PhotoReferencesByAssetID(ctx context.Context, biz *business.Business, assetIDs []string) (map[string]AssetReference, error) {
+	refs := make(map[string]AssetReference, len(assetIDs))
+	if len(assetIDs) == 0 {
+		return refs, nil
+	}
+	assets, err := s.storage.ListByIDs(ctx, biz.ID, assetIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range assets {
+		if FileCategory(a.FileCategory) != FileCategoryImage {
+			continue
+		}
+		refs[a.ID] = a.PhotoReference()
+	}
+	return refs, nil
+}
+
 // buildObjectKey creates the blob storage key for an asset.
 func (s *Service) buildObjectKey(businessID, assetID, fileName string) string {
 	if assetID == "" {
@@ -423,6 +576,7 @@ func (s *Service) generateThumbnailUpload(ctx context.Context, parentAsset *Asse
 		ContentType:     "image/jpeg", // Thumbnails are always JPEG
 		FileCategory:    string(FileCategoryImage),
 		SizeBytes:       0, // Will be determined by client after generation
+		ParentAssetID:   parentAsset.ID,
 	}
 
 	// Generate thumbnail assetId
@@ -488,7 +642,7 @@ func (s *Service) generateThumbnailUpload(ctx context.Context, parentAsset *Asse
 		return nil, problem.InternalError().WithError(err)
 	}
 
-	publicURL, ok := s.blob.PublicURL(thumbnailAsset.ObjectKey)
+	publicURL, ok := blob.ForContext(ctx, s.blob).PublicURL(thumbnailAsset.ObjectKey)
 	if !ok {
 		publicURL = ""
 	}
@@ -0,0 +1,190 @@
+package asset
+
+import (
+	"context"
+	"image"
+	"path"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/imaging"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+)
+
+const (
+	// thumbnailMaxSize bounds the width and height of image thumbnails.
+	thumbnailMaxSize = 400
+	// thumbnailQuality is the JPEG quality of image thumbnails.
+	thumbnailQuality = 80
+	// webpMaxSize bounds the width and height of the WebP rendition served in place of the
+	// original image.
+	webpMaxSize = 1600
+)
+
+// needsProcessing reports whether the server renders a thumbnail and WebP rendition for the
+// asset: uploaded images, but not the copies derived from them.
+func (s *Service) needsProcessing(asset *Asset) bool {
+	return FileCategory(asset.FileCategory) == FileCategoryImage && asset.ParentAssetID == ""
+}
+
+// processImage renders the thumbnail and WebP rendition of an uploaded image from its content
+// and links them to the asset. It is best effort: the upload has already succeeded, so
+// failures are logged and the asset is served as uploaded. Formats that cannot be decoded
+// (e.g. HEIC) are left as they are.
+func (s *Service) processImage(ctx context.Context, asset *Asset, data []byte) {
+	if !s.needsProcessing(asset) {
+		return
+	}
+	log := logger.FromContext(ctx).With("assetId", asset.ID)
+	img, _, err := imaging.Decode(data)
+	if err != nil {
+		log.Info("skipping image processing", "contentType", asset.ContentType, "error", err)
+		return
+	}
+
+	thumbImg := imaging.Fit(img, thumbnailMaxSize, thumbnailMaxSize)
+	thumbData, err := imaging.EncodeJPEG(thumbImg, thumbnailQuality)
+	if err != nil {
+		log.Error("failed to render image thumbnail", "error", err)
+		return
+	}
+	webpImg := imaging.Fit(img, webpMaxSize, webpMaxSize)
+	webpData, err := imaging.EncodeWebP(webpImg)
+	if err != nil {
+		log.Error("failed to render WebP rendition", "error", err)
+		return
+	}
+
+	previous, err := s.storage.ListDerived(ctx, asset.BusinessID, []string{asset.ID})
+	if err != nil {
+		log.Error("failed to list derived assets", "error", err)
+		return
+	}
+	thumb, err := s.storeDerived(ctx, asset, "thumb_", ".jpg", "image/jpeg", thumbData, thumbImg.Bounds())
+	if err != nil {
+		log.Error("failed to store image thumbnail", "error", err)
+		return
+	}
+	webp, err := s.storeDerived(ctx, asset, "", ".webp", "image/webp", webpData, webpImg.Bounds())
+	if err != nil {
+		log.Error("failed to store WebP rendition", "error", err)
+		s.deleteAssets(ctx, []*Asset{thumb})
+		return
+	}
+
+	b := img.Bounds()
+	asset.Width, asset.Height = b.Dx(), b.Dy()
+	asset.ThumbnailAssetID = &thumb.ID
+	asset.ThumbnailObjectKey = thumb.ObjectKey
+	asset.ThumbnailPublicURL = thumb.PublicURL
+	asset.ThumbnailCDNURL = thumb.CDNURL
+	asset.WebPAssetID = &webp.ID
+	asset.WebPObjectKey = webp.ObjectKey
+	asset.WebPPublicURL = webp.PublicURL
+	asset.WebPCDNURL = webp.CDNURL
+	if err := s.storage.Update(ctx, asset); err != nil {
+		log.Error("failed to link rendered images", "error", err)
+		s.deleteAssets(ctx, []*Asset{thumb, webp})
+		return
+	}
+	// content uploaded again replaces the copies rendered from the previous content
+	s.deleteAssets(ctx, previous)
+}
+
+// storeDerived stores a copy rendered from the parent asset, named after the parent's file.
+func (s *Service) storeDerived(ctx context.Context, parent *Asset, prefix, ext, contentType string, data []byte, bounds image.Rectangle) (*Asset, error) {
+	name := path.Base(parent.ObjectKey)
+	name = prefix + strings.TrimSuffix(name, path.Ext(name)) + ext
+	derived := &Asset{
+		WorkspaceID:     parent.WorkspaceID,
+		BusinessID:      parent.BusinessID,
+		CreatedByUserID: parent.CreatedByUserID,
+		ContentType:     contentType,
+		FileCategory:    string(FileCategoryImage),
+		SizeBytes:       int64(len(data)),
+		Width:           bounds.Dx(),
+		Height:          bounds.Dy(),
+		ParentAssetID:   parent.ID,
+	}
+	if err := derived.BeforeCreate(nil); err != nil {
+		return nil, err
+	}
+	derived.ObjectKey = s.buildObjectKey(parent.BusinessID, derived.ID, name)
+	if err := s.putContent(ctx, derived, data); err != nil {
+		return nil, err
+	}
+	if err := s.storage.Create(ctx, derived); err != nil {
+		s.removeContent(ctx, derived)
+		return nil, err
+	}
+	return derived, nil
+}
+
+// DeleteAssets deletes assets of the business along with their thumbnails and renditions,
+// removing their stored content. Unknown IDs are ignored. Callers are responsible for making
+// sure nothing references the assets anymore.
+func (s *Service) DeleteAssets(ctx context.Context, biz *business.Business, assetIDs []string) error {
+	if len(assetIDs) == 0 {
+		return nil
+	}
+	assets, err := s.storage.ListByIDs(ctx, biz.ID, assetIDs)
+	if err != nil || len(assets) == 0 {
+		return err
+	}
+	// thumbnails uploaded by clients before the server rendered them are only linked from the
+	// parent
+	var linked []string
+	for _, a := range assets {
+		if a.ThumbnailAssetID != nil {
+			linked = append(linked, *a.ThumbnailAssetID)
+		}
+	}
+	if len(linked) > 0 {
+		thumbnails, err := s.storage.ListByIDs(ctx, biz.ID, linked)
+		if err != nil {
+			return err
+		}
+		assets = append(assets, thumbnails...)
+	}
+	derived, err := s.storage.ListDerived(ctx, biz.ID, assetIDsOf(assets))
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(assets)+len(derived))
+	all := make([]*Asset, 0, len(assets)+len(derived))
+	for _, a := range append(assets, derived...) {
+		if !seen[a.ID] {
+			seen[a.ID] = true
+			all = append(all, a)
+		}
+	}
+	if err := s.storage.DeleteByIDs(ctx, biz.ID, assetIDsOf(all)); err != nil {
+		return err
+	}
+	for _, a := range all {
+		s.removeContent(ctx, a)
+	}
+	return nil
+}
+
+// deleteAssets deletes assets the service created itself, logging failures.
+func (s *Service) deleteAssets(ctx context.Context, assets []*Asset) {
+	if len(assets) == 0 {
+		return
+	}
+	if err := s.storage.DeleteByIDs(ctx, assets[0].BusinessID, assetIDsOf(assets)); err != nil {
+		logger.FromContext(ctx).Error("failed to delete derived assets", "error", err)
+		return
+	}
+	for _, a := range assets {
+		s.removeContent(ctx, a)
+	}
+}
+
+func assetIDsOf(assets []*Asset) []string {
+	ids := make([]string, len(assets))
+	for i, a := range assets {
+		ids[i] = a.ID
+	}
+	return ids
+}
@@ -66,3 +66,25 @@ func (s *Storage) UpdateSize(ctx context.Context, assetID string, sizeBytes int6
 		Where("id = ?", assetID).
 		Update("size_bytes", sizeBytes).Error
 }
+
+// ListDerived returns the thumbnails and renditions of the business' assets among parentIDs.
+func (s *Storage) ListDerived(ctx context.Context, businessID string, parentIDs []string) ([]*Asset, error) {
+	return s.asset.FindMany(ctx,
+		s.asset.ScopeBusinessID(businessID),
+		s.asset.ScopeWhere("parent_asset_id IN ?", parentIDs),
+	)
+}
+
+// DeleteByIDs deletes the assets of a business among assetIDs.
+func (s *Storage) DeleteByIDs(ctx context.Context, businessID string, assetIDs []string) error {
+	return s.asset.DeleteMany(ctx, s.asset.ScopeBusinessID(businessID), s.asset.ScopeWhere("id IN ?", assetIDs))
+}
+
+// ListByIDs returns the assets of a business among assetIDs, in no particular order.
+func (s *Storage) ListByIDs(ctx context.Context, businessID string, assetIDs []string) ([]*Asset, error) {
+	values := make([]any, len(assetIDs))
+	for i, id := range assetIDs {
+		values[i] = id
+	}
+	return s.asset.FindMany(ctx, s.asset.ScopeBusinessID(businessID), s.asset.ScopeIDs(values))
+}
@@ -0,0 +1,15 @@
+package audit
+
+import "github.com/abdelrahman146/kyora/internal/platform/types/problem"
+
+func ErrAuditInvalidQuery(err error) error {
+	return problem.BadRequest("invalid query parameters").
+		WithError(err).
+		WithCode("audit.invalid_query")
+}
+
+func ErrAuditQueryFailed(err error) error {
+	return problem.InternalError().
+		WithError(err).
+		WithCode("audit.query_failed")
+}
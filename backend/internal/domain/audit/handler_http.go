@@ -0,0 +1,81 @@
+package audit
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/platform/response"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/gin-gonic/gin"
+)
+
+// HttpHandler serves the workspace audit log.
+type HttpHandler struct {
+	service *Service
+}
+
+// NewHttpHandler creates a new audit HTTP handler.
+func NewHttpHandler(service *Service) *HttpHandler {
+	return &HttpHandler{service: service}
+}
+
+type listEntriesQuery struct {
+	Page     int       `form:"page" binding:"omitempty,min=1"`
+	PageSize int       `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	OrderBy  []string  `form:"orderBy" binding:"omitempty"`
+	Action   string    `form:"action" binding:"omitempty"`
+	ActorID  string    `form:"actorId" binding:"omitempty"`
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00" binding:"omitempty"`
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" binding:"omitempty"`
+}
+
+// ListEntries returns a paginated list of the workspace's audited requests
+//
+// @Summary      List audit log entries
+// @Description  Returns recorded sensitive requests (role changes, payment method updates, deletions) of the workspace, newest first. Bodies are redacted.
+// @Tags         workspaces
+// @Produce      json
+// @Param        page query int false "Page number"
+// @Param        pageSize query int false "Page size (max 100)"
+// @Param        orderBy query []string false "Order by fields (e.g. -occurredAt)"
+// @Param        action query string false "Audited action (e.g. resource.deleted)"
+// @Param        actorId query string false "User who made the request"
+// @Param        from query string false "RFC3339 lower bound of occurredAt"
+// @Param        to query string false "RFC3339 upper bound of occurredAt"
+// @Success      200 {object} list.ListResponse[audit.EntryResponse]
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/workspaces/audit-log [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListEntries(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query listEntriesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, ErrAuditInvalidQuery(err))
+		return
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+	filters := &ListFilters{Action: query.Action, ActorID: query.ActorID, From: query.From, To: query.To}
+
+	listReq := list.NewListRequest(query.Page, query.PageSize, query.OrderBy, "")
+	items, totalCount, err := h.service.ListEntries(c.Request.Context(), actor, listReq, filters)
+	if err != nil {
+		response.Error(c, ErrAuditQueryFailed(err))
+		return
+	}
+
+	hasMore := int64(query.Page*query.PageSize) < totalCount
+	response.SuccessJSON(c, http.StatusOK, list.NewListResponse(ToEntryResponses(items), query.Page, query.PageSize, totalCount, hasMore))
+}
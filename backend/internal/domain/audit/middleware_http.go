@@ -0,0 +1,141 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// maxCapturedBodyBytes bounds the request and response bodies kept for an entry. Larger bodies
+// are recorded without their payload.
+const maxCapturedBodyBytes = 64 << 10
+
+// teeWriter passes the response through untouched while keeping a bounded copy of its body.
+type teeWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (w *teeWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *teeWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *teeWriter) capture(data []byte) {
+	if w.overflow {
+		return
+	}
+	if w.body.Len()+len(data) > maxCapturedBodyBytes {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(data)
+}
+
+// NewMiddleware records requests matching one of rules into the audit log once they have been
+// handled. Only requests of an authenticated workspace member are recorded, including the ones
+// rejected by permission checks. Failing to store an entry is logged and never fails the request.
+//
+// Register it on the engine before the routes so every route group is covered.
+func NewMiddleware(svc *Service, rules ...Rule) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			c.Next()
+			return
+		}
+		var rule *Rule
+		for i := range rules {
+			if rules[i].matches(c.Request.Method, route) {
+				rule = &rules[i]
+				break
+			}
+		}
+		if rule == nil {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		reqBody := peekBody(c)
+		var tee *teeWriter
+		if rule.CaptureResponse {
+			tee = &teeWriter{ResponseWriter: c.Writer}
+			c.Writer = tee
+		}
+
+		c.Next()
+
+		actorVal, ok := c.Get(account.ActorKey)
+		if !ok {
+			return
+		}
+		actor, ok := actorVal.(*account.User)
+		if !ok || actor == nil {
+			return
+		}
+		params := make(map[string]string, len(c.Params))
+		for _, p := range c.Params {
+			params[p.Key] = p.Value
+		}
+		paramsJSON, _ := json.Marshal(params)
+		entry := &Entry{
+			WorkspaceID: actor.WorkspaceID,
+			ActorID:     actor.ID,
+			Action:      rule.Action,
+			Method:      c.Request.Method,
+			Route:       route,
+			Path:        c.Request.URL.Path,
+			Params:      paramsJSON,
+			StatusCode:  c.Writer.Status(),
+			RequestBody: redact(reqBody, rule.Redact),
+			IP:          c.ClientIP(),
+			UserAgent:   c.Request.UserAgent(),
+			DurationMs:  time.Since(start).Milliseconds(),
+			OccurredAt:  start.UTC(),
+		}
+		if bizVal, ok := c.Get(business.BusinessKey); ok {
+			if biz, ok := bizVal.(*business.Business); ok && biz != nil {
+				entry.BusinessID = biz.ID
+			}
+		}
+		if tee != nil && !tee.overflow {
+			entry.ResponseBody = redact(tee.body.Bytes(), rule.Redact)
+		}
+		ctx := context.WithoutCancel(c.Request.Context())
+		if err := svc.RecordEntry(ctx, entry); err != nil {
+			logger.FromContext(ctx).Error("failed to record audit entry", "error", err, "action", rule.Action, "route", route)
+		}
+	}
+}
+
+// peekBody reads up to maxCapturedBodyBytes of the request body and puts it back for the
+// handler. It returns nil when the body is larger than that.
+func peekBody(c *gin.Context) []byte {
+	if c.Request.Body == nil {
+		return nil
+	}
+	buf, err := io.ReadAll(io.LimitReader(c.Request.Body, maxCapturedBodyBytes+1))
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(buf), c.Request.Body), c.Request.Body}
+	if err != nil || len(buf) > maxCapturedBodyBytes {
+		return nil
+	}
+	return buf
+}
@@ -0,0 +1,115 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"gorm.io/gorm"
+)
+
+const (
+	EntryTable  = "audit_entries"
+	EntryStruct = "Entry"
+	EntryPrefix = "aud"
+)
+
+// Entry records one sensitive request made by a workspace member: who did what, on which
+// route, with which outcome. Bodies are stored redacted, and only when they are JSON.
+type Entry struct {
+	gorm.Model
+	ID           string          `gorm:"column:id;primaryKey;type:text" json:"id"`
+	WorkspaceID  string          `gorm:"column:workspace_id;type:text;not null;index" json:"workspaceId"`
+	ActorID      string          `gorm:"column:actor_id;type:text;not null;index" json:"actorId"`
+	BusinessID   string          `gorm:"column:business_id;type:text;index" json:"businessId,omitempty"`
+	Action       string          `gorm:"column:action;type:text;not null;index" json:"action"`
+	Method       string          `gorm:"column:method;type:text;not null" json:"method"`
+	Route        string          `gorm:"column:route;type:text;not null" json:"route"`
+	Path         string          `gorm:"column:path;type:text;not null" json:"path"`
+	Params       json.RawMessage `gorm:"column:params;type:jsonb;not null;default:'{}'" json:"params"`
+	StatusCode   int             `gorm:"column:status_code;type:int;not null" json:"statusCode"`
+	RequestBody  json.RawMessage `gorm:"column:request_body;type:jsonb" json:"requestBody,omitempty"`
+	ResponseBody json.RawMessage `gorm:"column:response_body;type:jsonb" json:"responseBody,omitempty"`
+	IP           string          `gorm:"column:ip;type:text" json:"ip"`
+	UserAgent    string          `gorm:"column:user_agent;type:text" json:"userAgent"`
+	DurationMs   int64           `gorm:"column:duration_ms;type:bigint;not null;default:0" json:"durationMs"`
+	OccurredAt   time.Time       `gorm:"column:occurred_at;type:timestamptz;not null;default:now();index" json:"occurredAt"`
+}
+
+func (m *Entry) TableName() string { return EntryTable }
+
+func (m *Entry) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(EntryPrefix)
+	}
+	return
+}
+
+var EntrySchema = struct {
+	ID          schema.Field
+	WorkspaceID schema.Field
+	ActorID     schema.Field
+	BusinessID  schema.Field
+	Action      schema.Field
+	Method      schema.Field
+	Route       schema.Field
+	StatusCode  schema.Field
+	OccurredAt  schema.Field
+	CreatedAt   schema.Field
+}{
+	ID:          schema.NewField("id", "id"),
+	WorkspaceID: schema.NewField("workspace_id", "workspaceId"),
+	ActorID:     schema.NewField("actor_id", "actorId"),
+	BusinessID:  schema.NewField("business_id", "businessId"),
+	Action:      schema.NewField("action", "action"),
+	Method:      schema.NewField("method", "method"),
+	Route:       schema.NewField("route", "route"),
+	StatusCode:  schema.NewField("status_code", "statusCode"),
+	OccurredAt:  schema.NewField("occurred_at", "occurredAt"),
+	CreatedAt:   schema.NewField("created_at", "createdAt"),
+}
+
+// EntryResponse is the API response for an audit entry.
+type EntryResponse struct {
+	ID           string          `json:"id"`
+	ActorID      string          `json:"actorId"`
+	BusinessID   string          `json:"businessId,omitempty"`
+	Action       string          `json:"action"`
+	Method       string          `json:"method"`
+	Route        string          `json:"route"`
+	Path         string          `json:"path"`
+	Params       json.RawMessage `json:"params"`
+	StatusCode   int             `json:"statusCode"`
+	RequestBody  json.RawMessage `json:"requestBody,omitempty"`
+	ResponseBody json.RawMessage `json:"responseBody,omitempty"`
+	IP           string          `json:"ip"`
+	UserAgent    string          `json:"userAgent"`
+	DurationMs   int64           `json:"durationMs"`
+	OccurredAt   time.Time       `json:"occurredAt"`
+}
+
+// ToEntryResponses converts audit entries to their API responses.
+func ToEntryResponses(items []*Entry) []EntryResponse {
+	responses := make([]EntryResponse, 0, len(items))
+	for _, m := range items {
+		responses = append(responses, EntryResponse{
+			ID:           m.ID,
+			ActorID:      m.ActorID,
+			BusinessID:   m.BusinessID,
+			Action:       m.Action,
+			Method:       m.Method,
+			Route:        m.Route,
+			Path:         m.Path,
+			Params:       m.Params,
+			StatusCode:   m.StatusCode,
+			RequestBody:  m.RequestBody,
+			ResponseBody: m.ResponseBody,
+			IP:           m.IP,
+			UserAgent:    m.UserAgent,
+			DurationMs:   m.DurationMs,
+			OccurredAt:   m.OccurredAt,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,108 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Rule selects the requests the audit middleware records. Method and Route are matched against
+// the request method and the gin route template (c.FullPath()); an empty value matches any.
+// The first matching rule wins, so specific rules go before catch-alls.
+type Rule struct {
+	// Action names the operation in the audit log, e.g. "workspace.user_role_changed".
+	Action string
+	Method string
+	Route  string
+	// CaptureResponse also stores the redacted response body.
+	CaptureResponse bool
+	// Redact lists extra body fields to mask on top of the default sensitive fields.
+	// Field names are matched case-insensitively at any depth.
+	Redact []string
+}
+
+func (r Rule) matches(method, route string) bool {
+	return (r.Method == "" || r.Method == method) && (r.Route == "" || r.Route == route)
+}
+
+// DefaultRules audits email change requests, role and permission changes, payment method updates and every deletion.
+func DefaultRules() []Rule {
+	return []Rule{
+		{Action: "user.email_change_requested", Method: http.MethodPost, Route: "/v1/users/me/email"},
+		{Action: "workspace.user_role_changed", Method: http.MethodPatch, Route: "/v1/workspaces/users/:userId/role", CaptureResponse: true},
+		{Action: "workspace.user_permissions_changed", Method: http.MethodPatch, Route: "/v1/workspaces/users/:userId/permissions", CaptureResponse: true},
+		{Action: "workspace.custom_role_created", Method: http.MethodPost, Route: "/v1/workspaces/roles", CaptureResponse: true},
+		{Action: "workspace.custom_role_updated", Method: http.MethodPatch, Route: "/v1/workspaces/roles/:roleId", CaptureResponse: true},
+		{Action: "workspace.sso_updated", Method: http.MethodPut, Route: "/v1/workspaces/sso", CaptureResponse: true},
+		{Action: "workspace.sso_domain_verified", Method: http.MethodPost, Route: "/v1/workspaces/sso/verify-domain", CaptureResponse: true},
+		{Action: "business.payment_method_updated", Method: http.MethodPatch, Route: "/v1/businesses/:businessDescriptor/payment-methods/:descriptor", CaptureResponse: true},
+		{Action: "billing.payment_method_attached", Method: http.MethodPost, Route: "/v1/billing/payment-methods/attach", Redact: []string{"paymentMethodId"}},
+		{Action: "resource.deleted", Method: http.MethodDelete},
+	}
+}
+
+// redactedValue replaces the value of every masked field.
+const redactedValue = "[REDACTED]"
+
+// sensitiveFieldMarkers mask any field whose normalized name contains one of them.
+var sensitiveFieldMarkers = []string{
+	"password", "token", "secret", "apikey", "authorization", "otp",
+	"cardnumber", "cvc", "cvv", "iban", "accountnumber",
+}
+
+// normalizeField lowercases a field name and drops separators so "api_key" matches "apiKey".
+func normalizeField(name string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+}
+
+// redact masks sensitive fields of a JSON document. It returns nil for anything that is not
+// valid JSON, so raw or binary bodies never reach the audit log.
+func redact(body []byte, extra []string) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil
+	}
+	fields := make(map[string]bool, len(extra))
+	for _, f := range extra {
+		fields[normalizeField(f)] = true
+	}
+	out, err := json.Marshal(redactValue(doc, fields))
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+func redactValue(v any, fields map[string]bool) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			if isSensitiveField(k, fields) {
+				t[k] = redactedValue
+				continue
+			}
+			t[k] = redactValue(child, fields)
+		}
+	case []any:
+		for i, child := range t {
+			t[i] = redactValue(child, fields)
+		}
+	}
+	return v
+}
+
+func isSensitiveField(name string, fields map[string]bool) bool {
+	n := normalizeField(name)
+	if fields[n] {
+		return true
+	}
+	for _, marker := range sensitiveFieldMarkers {
+		if strings.Contains(n, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"gorm.io/gorm"
+)
+
+// Service stores and lists the audit log of sensitive requests.
+type Service struct {
+	storage *Storage
+}
+
+// NewService creates the audit service.
+func NewService(storage *Storage) *Service {
+	return &Service{storage: storage}
+}
+
+// ListFilters narrows ListEntries results.
+type ListFilters struct {
+	Action  string
+	ActorID string
+	From    time.Time
+	To      time.Time
+}
+
+// RecordEntry stores an audit entry. It is called by the audit middleware and does no
+// permission checks.
+func (s *Service) RecordEntry(ctx context.Context, entry *Entry) error {
+	return s.storage.entry.CreateOne(ctx, entry)
+}
+
+// ListEntries returns a page of the actor's workspace audit log, newest first unless ordered otherwise.
+func (s *Service) ListEntries(ctx context.Context, actor *account.User, req *list.ListRequest, filters *ListFilters) ([]*Entry, int64, error) {
+	scopes := []func(*gorm.DB) *gorm.DB{
+		s.storage.entry.ScopeEquals(EntrySchema.WorkspaceID, actor.WorkspaceID),
+	}
+	if filters != nil {
+		if filters.Action != "" {
+			scopes = append(scopes, s.storage.entry.ScopeEquals(EntrySchema.Action, filters.Action))
+		}
+		if filters.ActorID != "" {
+			scopes = append(scopes, s.storage.entry.ScopeEquals(EntrySchema.ActorID, filters.ActorID))
+		}
+		scopes = append(scopes, s.storage.entry.ScopeTime(EntrySchema.OccurredAt, filters.From, filters.To))
+	}
+
+	total, err := s.storage.entry.Count(ctx, scopes...)
+	if err != nil {
+		return nil, 0, err
+	}
+	items, err := s.storage.entry.FindMany(ctx, append(scopes,
+		s.storage.entry.WithPagination(req.Offset(), req.Limit()),
+		s.storage.entry.WithOrderBy(req.ParsedOrderByWithDefault(EntrySchema, []string{"occurred_at DESC"})),
+	)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
@@ -0,0 +1,19 @@
+package audit
+
+import (
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+)
+
+// Storage provides data access for audit entries.
+type Storage struct {
+	db    *database.Database
+	entry *database.Repository[Entry]
+}
+
+// NewStorage creates a new audit storage instance.
+func NewStorage(db *database.Database) *Storage {
+	return &Storage{
+		db:    db,
+		entry: database.NewRepository[Entry](db),
+	}
+}
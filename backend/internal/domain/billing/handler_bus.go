@@ -0,0 +1,42 @@
+package billing
+
+import (
+	"context"
+
+	"github.com/abdelrahman146/kyora/internal/platform/bus"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+)
+
+// BusHandler keeps billing records in sync with account events.
+type BusHandler struct {
+	svc *Service
+}
+
+// NewBusHandler registers billing listeners on the event bus.
+func NewBusHandler(b *bus.Bus, svc *Service) {
+	h := &BusHandler{svc: svc}
+	b.Handle(bus.AccountEmailChangedTopic, "billing.sync_customer_email", h.HandleAccountEmailChanged)
+}
+
+// HandleAccountEmailChanged moves the Stripe customer email to the new email of the
+// workspace owner. Changes of other members are ignored. Stripe failures are returned so
+// the bus retries them.
+func (h *BusHandler) HandleAccountEmailChanged(event any) error {
+	e, ok := event.(*bus.AccountEmailChangedEvent)
+	if !ok {
+		logger.FromContext(context.Background()).Error("invalid event type for AccountEmailChangedEvent")
+		return nil
+	}
+	if !e.IsOwner {
+		return nil
+	}
+	if e.WorkspaceID == "" || e.NewEmail == "" {
+		logger.FromContext(e.Ctx).Error("missing required fields in AccountEmailChangedEvent", "workspaceId", e.WorkspaceID, "userId", e.UserID)
+		return nil
+	}
+	if err := h.svc.SyncCustomerEmail(e.Ctx, e.WorkspaceID, e.NewEmail); err != nil {
+		logger.FromContext(e.Ctx).Error("failed to sync Stripe customer email", "error", err, "workspaceId", e.WorkspaceID)
+		return err
+	}
+	return nil
+}
@@ -55,6 +55,7 @@ type PlanFeature struct {
 	InvoiceGeneration        bool `json:"invoiceGeneration"` // Up to here pro plan
 	ExportAnalyticsData      bool `json:"exportAnalyticsData"`
 	AIBusinessAssistant      bool `json:"aiBusinessAssistant"` // Up to here premium plan
+	SingleSignOn             bool `json:"singleSignOn"`        // workspace sign-in through the company's OIDC/SAML identity provider
 }
 
 // Scan implements the Scanner interface for JSONB deserialization
@@ -93,6 +94,7 @@ func (pf *PlanFeature) CanUseFeature(feature schema.Field) error {
 		PlanSchema.InvoiceGeneration:        pf.InvoiceGeneration,
 		PlanSchema.ExportAnalyticsData:      pf.ExportAnalyticsData,
 		PlanSchema.AIBusinessAssistant:      pf.AIBusinessAssistant,
+		PlanSchema.SingleSignOn:             pf.SingleSignOn,
 	}
 
 	enabled, ok := boolFeatures[feature]
@@ -198,6 +200,7 @@ var PlanSchema = struct {
 	InvoiceGeneration        schema.Field
 	ExportAnalyticsData      schema.Field
 	AIBusinessAssistant      schema.Field
+	SingleSignOn             schema.Field
 	// Limits (jsonb: limits)
 	MaxOrdersPerMonth schema.Field
 	MaxTeamMembers    schema.Field
@@ -230,6 +233,7 @@ var PlanSchema = struct {
 	InvoiceGeneration:        schema.NewField("features->invoiceGeneration", "features.invoiceGeneration"),
 	ExportAnalyticsData:      schema.NewField("features->exportAnalyticsData", "features.exportAnalyticsData"),
 	AIBusinessAssistant:      schema.NewField("features->aiBusinessAssistant", "features.aiBusinessAssistant"),
+	SingleSignOn:             schema.NewField("features->singleSignOn", "features.singleSignOn"),
 	// Limits
 	MaxOrdersPerMonth: schema.NewField("limits->maxOrdersPerMonth", "limits.maxOrdersPerMonth"),
 	MaxTeamMembers:    schema.NewField("limits->maxTeamMembers", "limits.maxTeamMembers"),
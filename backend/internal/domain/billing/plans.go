@@ -29,6 +29,7 @@ var plans = []Plan{
 			InvoiceGeneration:        false,
 			ExportAnalyticsData:      false,
 			AIBusinessAssistant:      false,
+			SingleSignOn:             false,
 		},
 		Limits: PlanLimit{
 			MaxOrdersPerMonth: 25,
@@ -59,6 +60,7 @@ var plans = []Plan{
 			InvoiceGeneration:        true,
 			ExportAnalyticsData:      false,
 			AIBusinessAssistant:      false,
+			SingleSignOn:             false,
 		},
 		Limits: PlanLimit{
 			MaxOrdersPerMonth: 500,
@@ -89,6 +91,7 @@ var plans = []Plan{
 			InvoiceGeneration:        true,
 			ExportAnalyticsData:      true,
 			AIBusinessAssistant:      true,
+			SingleSignOn:             true,
 		},
 		Limits: PlanLimit{
 			MaxOrdersPerMonth: -1, // Unlimited
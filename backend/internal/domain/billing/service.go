@@ -230,6 +230,46 @@ func (s *Service) CanUseFeature(ctx context.Context, workspaceID string, feature
 	return nil
 }
 
+// CanUseSingleSignOn checks that a workspace's subscription is active and its plan includes
+// single sign-on. Unlike the plan middleware it needs no actor, so SSO sign-in can check it
+// before the user is known.
+func (s *Service) CanUseSingleSignOn(ctx context.Context, workspaceID string) error {
+	sub, err := s.GetSubscriptionByWorkspaceIDSafe(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	if err := sub.IsActive(); err != nil {
+		return err
+	}
+	plan := sub.Plan
+	if plan == nil {
+		if plan, err = s.GetPlanByID(ctx, sub.PlanID); err != nil {
+			return err
+		}
+	}
+	return plan.Features.CanUseFeature(PlanSchema.SingleSignOn)
+}
+
+// CheckTeamMemberLimit checks that a workspace's subscription is active and its plan has room
+// for another team member when `used` seats are taken. Bulk invitations run it per row, where
+// the plan middleware only sees the request as a whole.
+func (s *Service) CheckTeamMemberLimit(ctx context.Context, workspaceID string, used int64) error {
+	sub, err := s.GetSubscriptionByWorkspaceIDSafe(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	if err := sub.IsActive(); err != nil {
+		return err
+	}
+	plan := sub.Plan
+	if plan == nil {
+		if plan, err = s.GetPlanByID(ctx, sub.PlanID); err != nil {
+			return err
+		}
+	}
+	return plan.Limits.CheckUsageLimit(PlanSchema.MaxTeamMembers, used)
+}
+
 // CheckUsageLimitWithCallback checks if additional usage would exceed plan limits
 // This method is designed to work with the enforce_plan_limit middleware
 func (s *Service) CheckUsageLimitWithCallback(ctx context.Context, workspaceID, limitType string, additionalUsage int64, checkFunc func(used, limit int64) error) error {
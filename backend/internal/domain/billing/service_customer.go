@@ -116,6 +116,23 @@ func (s *Service) EnsureCustomer(ctx context.Context, ws *account.Workspace) (st
 	return c.ID, nil
 }
 
+// SyncCustomerEmail sets the email of the workspace's Stripe customer. Workspaces without a
+// customer have nothing to update.
+func (s *Service) SyncCustomerEmail(ctx context.Context, workspaceID, email string) error {
+	ws, err := s.account.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	if !ws.StripeCustomerID.Valid || ws.StripeCustomerID.String == "" {
+		return nil
+	}
+	if _, err := customer.Update(ws.StripeCustomerID.String, &stripelib.CustomerParams{Email: stripelib.String(email)}); err != nil {
+		return fmt.Errorf("failed to update customer email: %w", err)
+	}
+	logger.FromContext(ctx).Info("Updated Stripe customer email", "workspace_id", ws.ID, "customer_id", ws.StripeCustomerID.String)
+	return nil
+}
+
 // AttachAndSetDefaultPaymentMethod attaches a payment method to the customer and sets it as default
 func (s *Service) AttachAndSetDefaultPaymentMethod(ctx context.Context, ws *account.Workspace, pmID string) error {
 	if pmID == "" {
@@ -239,6 +239,7 @@ func (s *Service) ensureFeatureCompatibility(currentPlan, newPlan *Plan) error {
 		"invoiceGeneration":        cur.InvoiceGeneration,
 		"exportAnalyticsData":      cur.ExportAnalyticsData,
 		"aiBusinessAssistant":      cur.AIBusinessAssistant,
+		"singleSignOn":             cur.SingleSignOn,
 	}
 	nxtMap := map[string]bool{
 		"customerManagement":       nxt.CustomerManagement,
@@ -256,6 +257,7 @@ func (s *Service) ensureFeatureCompatibility(currentPlan, newPlan *Plan) error {
 		"invoiceGeneration":        nxt.InvoiceGeneration,
 		"exportAnalyticsData":      nxt.ExportAnalyticsData,
 		"aiBusinessAssistant":      nxt.AIBusinessAssistant,
+		"singleSignOn":             nxt.SingleSignOn,
 	}
 	for k, v := range curMap {
 		if v && !nxtMap[k] {
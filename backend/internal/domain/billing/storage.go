@@ -19,13 +19,15 @@ type Storage struct {
 }
 
 func NewStorage(db *database.Database, cache *cache.Cache) *Storage {
+	// Billing records live in the home region; usage counts query the workspace region.
+	global := db.Global()
 	s := &Storage{
 		cache:         cache,
 		db:            db,
-		plan:          database.NewRepository[Plan](db),
-		subscription:  database.NewRepository[Subscription](db),
-		invoiceRecord: database.NewRepository[InvoiceRecord](db),
-		event:         database.NewRepository[StripeEvent](db),
+		plan:          database.NewRepository[Plan](global),
+		subscription:  database.NewRepository[Subscription](global),
+		invoiceRecord: database.NewRepository[InvoiceRecord](global),
+		event:         database.NewRepository[StripeEvent](global),
 	}
 
 	return s
@@ -18,6 +18,7 @@ import (
 	"github.com/abdelrahman146/kyora/internal/platform/config"
 	"github.com/abdelrahman146/kyora/internal/platform/database"
 	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/region"
 	"github.com/spf13/cast"
 	"github.com/spf13/viper"
 	stripelib "github.com/stripe/stripe-go/v83"
@@ -164,6 +165,10 @@ func (s *Service) ProcessWebhook(ctx context.Context, payload []byte, signature
 		if err := s.handleCheckoutSessionCompleted(ctx, evt.Data.Object); err != nil {
 			return err
 		}
+	case "checkout.session.async_payment_succeeded":
+		if err := s.handleOrderCheckoutPaid(ctx, evt.Data.Object); err != nil {
+			return err
+		}
 	default:
 		// unhandled types are ignored
 	}
@@ -418,6 +423,9 @@ func (s *Service) handleCheckoutSessionCompleted(ctx context.Context, raw json.R
 	if sid == "" {
 		return nil
 	}
+	if cast.ToString(obj["mode"]) == string(stripelib.CheckoutSessionModePayment) {
+		return s.handleOrderCheckoutPaid(ctx, raw)
+	}
 	sess, err := session.Get(sid, nil)
 	if err != nil {
 		return ErrWebhookProcessingFailed(err, "get_session")
@@ -459,3 +467,36 @@ func (s *Service) handleCheckoutSessionCompleted(ctx context.Context, raw json.R
 	}
 	return nil
 }
+
+// handleOrderCheckoutPaid hands a paid order payment link session over to the order domain.
+// Sessions still waiting for a delayed payment method are completed by
+// checkout.session.async_payment_succeeded instead.
+func (s *Service) handleOrderCheckoutPaid(ctx context.Context, raw json.RawMessage) error {
+	var obj struct {
+		ID            string            `json:"id"`
+		PaymentStatus string            `json:"payment_status"`
+		PaymentIntent string            `json:"payment_intent"`
+		Metadata      map[string]string `json:"metadata"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return ErrWebhookProcessingFailed(err, "parse_payload")
+	}
+	orderID, businessID := obj.Metadata["order_id"], obj.Metadata["business_id"]
+	if orderID == "" || businessID == "" {
+		return nil
+	}
+	if obj.PaymentStatus != string(stripelib.CheckoutSessionPaymentStatusPaid) {
+		return nil
+	}
+	// The order lives in the region recorded on the session; links created before regions
+	// were recorded belong to the home region.
+	ctx = region.WithRegion(ctx, obj.Metadata["region"])
+	s.bus.Emit(bus.OrderCheckoutCompletedTopic, &bus.OrderCheckoutCompletedEvent{
+		Ctx:               context.WithoutCancel(ctx),
+		BusinessID:        businessID,
+		OrderID:           orderID,
+		CheckoutSessionID: obj.ID,
+		PaymentIntentID:   obj.PaymentIntent,
+	})
+	return nil
+}
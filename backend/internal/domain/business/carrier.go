@@ -0,0 +1,195 @@
+package business
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+// CarrierCode identifies a shipping carrier integration.
+type CarrierCode string
+
+const (
+	CarrierAramex     CarrierCode = "aramex"
+	CarrierDHLExpress CarrierCode = "dhl_express"
+	CarrierMock       CarrierCode = "mock"
+)
+
+// carrierTrackingURLs are the public tracking pages of the carriers, formatted with a
+// tracking number.
+var carrierTrackingURLs = map[CarrierCode]string{
+	CarrierAramex:     "https://www.aramex.com/track/results?ShipmentNumber=%s",
+	CarrierDHLExpress: "https://www.dhl.com/en/express/tracking.html?AWB=%s",
+}
+
+// TrackingURL returns the carrier's public tracking page of a shipment, or "" when the
+// carrier has none.
+func (c CarrierCode) TrackingURL(trackingNumber string) string {
+	format, ok := carrierTrackingURLs[c]
+	if !ok || trackingNumber == "" {
+		return ""
+	}
+	return fmt.Sprintf(format, url.QueryEscape(trackingNumber))
+}
+
+// Carrier quotes and buys shipping labels from a carrier's API.
+type Carrier interface {
+	Code() CarrierCode
+	// Rates returns the services the carrier offers for the shipment with their prices.
+	Rates(ctx context.Context, shipment *Shipment) ([]CarrierRate, error)
+	// PurchaseLabel books the shipment with a service returned by Rates.
+	PurchaseLabel(ctx context.Context, shipment *Shipment, service string) (*CarrierLabel, error)
+}
+
+// ShipmentAddress is the sender or recipient of a shipment.
+type ShipmentAddress struct {
+	Name        string
+	Company     string
+	Phone       string
+	Email       string
+	Line1       string
+	City        string
+	State       string
+	PostalCode  string
+	CountryCode string
+}
+
+// ShipmentParcel is the single package of a shipment, in kilograms and centimeters.
+type ShipmentParcel struct {
+	WeightKg decimal.Decimal
+	LengthCm decimal.Decimal
+	WidthCm  decimal.Decimal
+	HeightCm decimal.Decimal
+}
+
+// ShipmentItem is a line of the shipment contents, declared for customs.
+type ShipmentItem struct {
+	Description string
+	Quantity    int
+	UnitValue   decimal.Decimal
+}
+
+// Shipment describes what a carrier is asked to move.
+type Shipment struct {
+	// Reference is shown on the label, usually the order number.
+	Reference   string
+	From        ShipmentAddress
+	To          ShipmentAddress
+	Parcel      ShipmentParcel
+	Items       []ShipmentItem
+	Description string
+	// Value and Currency declare the contents for customs.
+	Value    decimal.Decimal
+	Currency string
+	ShipDate time.Time
+}
+
+// International reports whether the shipment crosses a border.
+func (s *Shipment) International() bool {
+	return !strings.EqualFold(s.From.CountryCode, s.To.CountryCode)
+}
+
+// CarrierRate is the price of a carrier service for a shipment.
+type CarrierRate struct {
+	Carrier     CarrierCode
+	Service     string
+	ServiceName string
+	Amount      decimal.Decimal
+	Currency    string
+	// TransitDays is the estimated delivery time; zero when the carrier gives none.
+	TransitDays int
+}
+
+// CarrierLabel is a booked shipment.
+type CarrierLabel struct {
+	TrackingNumber string
+	LabelPDF       []byte
+}
+
+// maxLabelBytes bounds the label PDFs downloaded from carriers.
+const maxLabelBytes = 10 << 20
+
+// CarrierError is a request the carrier rejected, with a message meant for the seller
+// (e.g. an unsupported destination or an invalid address).
+type CarrierError struct {
+	Carrier CarrierCode
+	Message string
+}
+
+func (e *CarrierError) Error() string {
+	return string(e.Carrier) + ": " + e.Message
+}
+
+// CarriersFromConfig returns the carriers whose credentials are configured:
+// 1) Aramex when shipping.aramex.username and account_number are set
+// 2) DHL Express when shipping.dhl_express.api_key and account are set
+// 3) the mock carrier when shipping.mock_carrier is true
+func CarriersFromConfig() []Carrier {
+	var carriers []Carrier
+	if viper.GetString(config.AramexUsername) != "" && viper.GetString(config.AramexAccountNumber) != "" {
+		carriers = append(carriers, NewAramexCarrier(AramexCredentials{
+			Username:       viper.GetString(config.AramexUsername),
+			Password:       viper.GetString(config.AramexPassword),
+			AccountNumber:  viper.GetString(config.AramexAccountNumber),
+			AccountPin:     viper.GetString(config.AramexAccountPin),
+			AccountEntity:  viper.GetString(config.AramexAccountEntity),
+			AccountCountry: viper.GetString(config.AramexAccountCountryCode),
+		}, viper.GetString(config.AramexAPIBaseURL), nil))
+	}
+	if viper.GetString(config.DHLExpressAPIKey) != "" && viper.GetString(config.DHLExpressAccountNumber) != "" {
+		carriers = append(carriers, NewDHLExpressCarrier(
+			viper.GetString(config.DHLExpressAPIKey),
+			viper.GetString(config.DHLExpressAPISecret),
+			viper.GetString(config.DHLExpressAccountNumber),
+			viper.GetString(config.DHLExpressAPIBaseURL),
+			nil,
+		))
+	}
+	if viper.GetBool(config.ShippingMockCarrier) {
+		carriers = append(carriers, &MockCarrier{})
+	}
+	return carriers
+}
+
+// SetCarriers wires the carriers offered for shipping labels. Without any, rate and label
+// requests fail with business.carrier_not_configured.
+func (s *Service) SetCarriers(carriers ...Carrier) {
+	s.carriers = make(map[CarrierCode]Carrier, len(carriers))
+	for _, c := range carriers {
+		s.carriers[c.Code()] = c
+	}
+}
+
+// ListCarriers returns the codes of the configured carriers.
+func (s *Service) ListCarriers() []CarrierCode {
+	codes := make([]CarrierCode, 0, len(s.carriers))
+	for code := range s.carriers {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
+
+func (s *Service) carrier(code CarrierCode) (Carrier, error) {
+	c, ok := s.carriers[code]
+	if !ok {
+		return nil, ErrCarrierNotConfigured(code)
+	}
+	return c, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,365 @@
+package business
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const defaultAramexAPIBaseURL = "https://ws.aramex.net/ShippingAPI.V2"
+
+// Aramex product types offered as services. Domestic shipments use the DOM product group,
+// cross-border ones EXP.
+var (
+	aramexDomesticServices      = map[string]string{"ONP": "Overnight Parcel"}
+	aramexInternationalServices = map[string]string{"PPX": "Priority Parcel Express", "EPX": "Economy Parcel Express"}
+)
+
+// AramexCredentials identify the shipper account used with the Aramex API.
+type AramexCredentials struct {
+	Username       string
+	Password       string
+	AccountNumber  string
+	AccountPin     string
+	AccountEntity  string
+	AccountCountry string
+}
+
+// AramexCarrier talks to the Aramex JSON shipping services (rate calculator and shipping).
+type AramexCarrier struct {
+	credentials AramexCredentials
+	baseURL     string
+	httpClient  *http.Client
+}
+
+// NewAramexCarrier creates an Aramex carrier; an empty baseURL uses the production API.
+func NewAramexCarrier(credentials AramexCredentials, baseURL string, httpClient *http.Client) *AramexCarrier {
+	if baseURL == "" {
+		baseURL = defaultAramexAPIBaseURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &AramexCarrier{credentials: credentials, baseURL: strings.TrimRight(baseURL, "/"), httpClient: httpClient}
+}
+
+func (c *AramexCarrier) Code() CarrierCode { return CarrierAramex }
+
+type aramexClientInfo struct {
+	UserName           string
+	Password           string
+	Version            string
+	AccountNumber      string
+	AccountPin         string
+	AccountEntity      string
+	AccountCountryCode string
+	Source             int
+}
+
+type aramexAddress struct {
+	Line1               string
+	City                string
+	StateOrProvinceCode string `json:",omitempty"`
+	PostCode            string
+	CountryCode         string
+}
+
+type aramexContact struct {
+	PersonName   string
+	CompanyName  string
+	PhoneNumber1 string
+	CellPhone    string
+	EmailAddress string
+}
+
+type aramexParty struct {
+	Reference1    string `json:",omitempty"`
+	AccountNumber string `json:",omitempty"`
+	PartyAddress  aramexAddress
+	Contact       aramexContact
+}
+
+type aramexMeasure struct {
+	Unit  string
+	Value float64
+}
+
+type aramexDimensions struct {
+	Length float64
+	Width  float64
+	Height float64
+	Unit   string
+}
+
+type aramexMoney struct {
+	CurrencyCode string
+	Value        float64
+}
+
+type aramexDetails struct {
+	Dimensions         aramexDimensions
+	ActualWeight       aramexMeasure
+	ChargeableWeight   aramexMeasure
+	NumberOfPieces     int
+	ProductGroup       string
+	ProductType        string
+	PaymentType        string
+	DescriptionOfGoods string       `json:",omitempty"`
+	GoodsOriginCountry string       `json:",omitempty"`
+	CustomsValueAmount *aramexMoney `json:",omitempty"`
+}
+
+type aramexNotification struct {
+	Code    string
+	Message string
+}
+
+type aramexRateRequest struct {
+	ClientInfo            aramexClientInfo
+	Transaction           map[string]string
+	OriginAddress         aramexAddress
+	DestinationAddress    aramexAddress
+	ShipmentDetails       aramexDetails
+	PreferredCurrencyCode string
+}
+
+type aramexRateResponse struct {
+	HasErrors     bool
+	Notifications []aramexNotification
+	TotalAmount   aramexMoney
+}
+
+type aramexShipment struct {
+	Reference1       string
+	Shipper          aramexParty
+	Consignee        aramexParty
+	ShippingDateTime string
+	DueDate          string
+	Details          aramexDetails
+}
+
+type aramexShipmentRequest struct {
+	ClientInfo  aramexClientInfo
+	Transaction map[string]string
+	LabelInfo   map[string]any
+	Shipments   []aramexShipment
+}
+
+type aramexShipmentResponse struct {
+	HasErrors     bool
+	Notifications []aramexNotification
+	Shipments     []struct {
+		ID            string
+		HasErrors     bool
+		Notifications []aramexNotification
+		ShipmentLabel struct {
+			LabelURL string
+		}
+	}
+}
+
+func (c *AramexCarrier) Rates(ctx context.Context, shipment *Shipment) ([]CarrierRate, error) {
+	group, services := c.services(shipment)
+	rates := make([]CarrierRate, 0, len(services))
+	for _, product := range sortedKeys(services) {
+		var out aramexRateResponse
+		err := c.post(ctx, "/RateCalculator/Service_1_0.svc/json/CalculateRate", aramexRateRequest{
+			ClientInfo:            c.clientInfo(),
+			Transaction:           map[string]string{"Reference1": shipment.Reference},
+			OriginAddress:         aramexAddressOf(shipment.From),
+			DestinationAddress:    aramexAddressOf(shipment.To),
+			ShipmentDetails:       c.details(shipment, group, product),
+			PreferredCurrencyCode: shipment.Currency,
+		}, &out)
+		if err != nil {
+			return nil, err
+		}
+		if out.HasErrors {
+			// A product the lane does not support is skipped rather than failing the quote.
+			continue
+		}
+		rates = append(rates, CarrierRate{
+			Carrier:     CarrierAramex,
+			Service:     product,
+			ServiceName: services[product],
+			Amount:      decimal.NewFromFloat(out.TotalAmount.Value),
+			Currency:    strings.ToUpper(out.TotalAmount.CurrencyCode),
+		})
+	}
+	if len(rates) == 0 {
+		return nil, &CarrierError{Carrier: CarrierAramex, Message: "no Aramex service is available for this shipment"}
+	}
+	return rates, nil
+}
+
+func (c *AramexCarrier) PurchaseLabel(ctx context.Context, shipment *Shipment, service string) (*CarrierLabel, error) {
+	group, services := c.services(shipment)
+	if _, ok := services[service]; !ok {
+		return nil, &CarrierError{Carrier: CarrierAramex, Message: fmt.Sprintf("service %q is not available for this shipment", service)}
+	}
+	shipDate := aramexDate(shipment.ShipDate)
+	var out aramexShipmentResponse
+	err := c.post(ctx, "/Shipping/Service_1_0.svc/json/CreateShipments", aramexShipmentRequest{
+		ClientInfo:  c.clientInfo(),
+		Transaction: map[string]string{"Reference1": shipment.Reference},
+		LabelInfo:   map[string]any{"ReportID": 9201, "ReportType": "URL"},
+		Shipments: []aramexShipment{{
+			Reference1: shipment.Reference,
+			Shipper: aramexParty{
+				AccountNumber: c.credentials.AccountNumber,
+				PartyAddress:  aramexAddressOf(shipment.From),
+				Contact:       aramexContactOf(shipment.From),
+			},
+			Consignee: aramexParty{
+				PartyAddress: aramexAddressOf(shipment.To),
+				Contact:      aramexContactOf(shipment.To),
+			},
+			ShippingDateTime: shipDate,
+			DueDate:          shipDate,
+			Details:          c.details(shipment, group, service),
+		}},
+	}, &out)
+	if err != nil {
+		return nil, err
+	}
+	if out.HasErrors || len(out.Shipments) == 0 || out.Shipments[0].HasErrors {
+		notes := out.Notifications
+		if len(out.Shipments) > 0 {
+			notes = append(notes, out.Shipments[0].Notifications...)
+		}
+		return nil, &CarrierError{Carrier: CarrierAramex, Message: aramexMessage(notes)}
+	}
+	created := out.Shipments[0]
+	pdf, err := c.download(ctx, created.ShipmentLabel.LabelURL)
+	if err != nil {
+		return nil, err
+	}
+	return &CarrierLabel{TrackingNumber: created.ID, LabelPDF: pdf}, nil
+}
+
+func (c *AramexCarrier) services(shipment *Shipment) (string, map[string]string) {
+	if shipment.International() {
+		return "EXP", aramexInternationalServices
+	}
+	return "DOM", aramexDomesticServices
+}
+
+func (c *AramexCarrier) clientInfo() aramexClientInfo {
+	return aramexClientInfo{
+		UserName:           c.credentials.Username,
+		Password:           c.credentials.Password,
+		Version:            "v1.0",
+		AccountNumber:      c.credentials.AccountNumber,
+		AccountPin:         c.credentials.AccountPin,
+		AccountEntity:      c.credentials.AccountEntity,
+		AccountCountryCode: c.credentials.AccountCountry,
+		Source:             24,
+	}
+}
+
+func (c *AramexCarrier) details(shipment *Shipment, group, product string) aramexDetails {
+	weight := aramexMeasure{Unit: "KG", Value: shipment.Parcel.WeightKg.InexactFloat64()}
+	d := aramexDetails{
+		Dimensions: aramexDimensions{
+			Length: shipment.Parcel.LengthCm.InexactFloat64(),
+			Width:  shipment.Parcel.WidthCm.InexactFloat64(),
+			Height: shipment.Parcel.HeightCm.InexactFloat64(),
+			Unit:   "CM",
+		},
+		ActualWeight:       weight,
+		ChargeableWeight:   weight,
+		NumberOfPieces:     1,
+		ProductGroup:       group,
+		ProductType:        product,
+		PaymentType:        "P",
+		DescriptionOfGoods: shipment.Description,
+		GoodsOriginCountry: shipment.From.CountryCode,
+	}
+	if group == "EXP" {
+		d.CustomsValueAmount = &aramexMoney{CurrencyCode: shipment.Currency, Value: shipment.Value.InexactFloat64()}
+	}
+	return d
+}
+
+func (c *AramexCarrier) post(ctx context.Context, path string, payload, out any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("aramex returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode aramex response: %w", err)
+	}
+	return nil
+}
+
+func (c *AramexCarrier) download(ctx context.Context, url string) ([]byte, error) {
+	if url == "" {
+		return nil, &CarrierError{Carrier: CarrierAramex, Message: "Aramex returned no label"}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("aramex label download returned status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxLabelBytes))
+}
+
+func aramexAddressOf(a ShipmentAddress) aramexAddress {
+	return aramexAddress{Line1: a.Line1, City: a.City, StateOrProvinceCode: a.State, PostCode: a.PostalCode, CountryCode: a.CountryCode}
+}
+
+func aramexContactOf(a ShipmentAddress) aramexContact {
+	company := a.Company
+	if company == "" {
+		company = a.Name
+	}
+	return aramexContact{PersonName: a.Name, CompanyName: company, PhoneNumber1: a.Phone, CellPhone: a.Phone, EmailAddress: a.Email}
+}
+
+// aramexDate formats a time the way the Aramex WCF services expect ("/Date(<ms>)/").
+func aramexDate(t time.Time) string {
+	return fmt.Sprintf("/Date(%d)/", t.UnixMilli())
+}
+
+func aramexMessage(notes []aramexNotification) string {
+	msgs := make([]string, 0, len(notes))
+	for _, n := range notes {
+		msgs = append(msgs, strings.TrimSpace(n.Code+" "+n.Message))
+	}
+	if len(msgs) == 0 {
+		return "Aramex rejected the shipment"
+	}
+	return strings.Join(msgs, "; ")
+}
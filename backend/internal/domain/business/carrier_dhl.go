@@ -0,0 +1,323 @@
+package business
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const defaultDHLExpressAPIBaseURL = "https://express.api.dhl.com/mydhlapi"
+
+// DHLExpressCarrier talks to the MyDHL API (DHL Express) with basic authentication.
+type DHLExpressCarrier struct {
+	apiKey        string
+	apiSecret     string
+	accountNumber string
+	baseURL       string
+	httpClient    *http.Client
+}
+
+// NewDHLExpressCarrier creates a DHL Express carrier; an empty baseURL uses the production API.
+func NewDHLExpressCarrier(apiKey, apiSecret, accountNumber, baseURL string, httpClient *http.Client) *DHLExpressCarrier {
+	if baseURL == "" {
+		baseURL = defaultDHLExpressAPIBaseURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &DHLExpressCarrier{
+		apiKey:        apiKey,
+		apiSecret:     apiSecret,
+		accountNumber: accountNumber,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		httpClient:    httpClient,
+	}
+}
+
+func (c *DHLExpressCarrier) Code() CarrierCode { return CarrierDHLExpress }
+
+type dhlPrice struct {
+	CurrencyType  string  `json:"currencyType"`
+	PriceCurrency string  `json:"priceCurrency"`
+	Price         float64 `json:"price"`
+}
+
+type dhlRatesResponse struct {
+	Products []struct {
+		ProductName          string     `json:"productName"`
+		ProductCode          string     `json:"productCode"`
+		TotalPrice           []dhlPrice `json:"totalPrice"`
+		DeliveryCapabilities struct {
+			TotalTransitDays int `json:"totalTransitDays"`
+		} `json:"deliveryCapabilities"`
+	} `json:"products"`
+}
+
+type dhlPostalAddress struct {
+	PostalCode   string `json:"postalCode,omitempty"`
+	CityName     string `json:"cityName"`
+	CountryCode  string `json:"countryCode"`
+	ProvinceCode string `json:"provinceCode,omitempty"`
+	AddressLine1 string `json:"addressLine1"`
+}
+
+type dhlContact struct {
+	Phone       string `json:"phone"`
+	CompanyName string `json:"companyName"`
+	FullName    string `json:"fullName"`
+	Email       string `json:"email,omitempty"`
+}
+
+type dhlParty struct {
+	PostalAddress      dhlPostalAddress `json:"postalAddress"`
+	ContactInformation dhlContact       `json:"contactInformation"`
+}
+
+type dhlPackage struct {
+	Weight     float64            `json:"weight"`
+	Dimensions map[string]float64 `json:"dimensions"`
+}
+
+type dhlLineItem struct {
+	Number              int            `json:"number"`
+	Description         string         `json:"description"`
+	Price               float64        `json:"price"`
+	Quantity            map[string]any `json:"quantity"`
+	Weight              map[string]any `json:"weight"`
+	ManufacturerCountry string         `json:"manufacturerCountry"`
+}
+
+type dhlShipmentRequest struct {
+	PlannedShippingDateAndTime string              `json:"plannedShippingDateAndTime"`
+	Pickup                     map[string]bool     `json:"pickup"`
+	ProductCode                string              `json:"productCode"`
+	Accounts                   []map[string]string `json:"accounts"`
+	OutputImageProperties      map[string]any      `json:"outputImageProperties"`
+	CustomerDetails            map[string]dhlParty `json:"customerDetails"`
+	Content                    map[string]any      `json:"content"`
+	CustomerReferences         []map[string]string `json:"customerReferences,omitempty"`
+}
+
+type dhlShipmentResponse struct {
+	ShipmentTrackingNumber string `json:"shipmentTrackingNumber"`
+	Documents              []struct {
+		ImageFormat string `json:"imageFormat"`
+		Content     string `json:"content"`
+		TypeCode    string `json:"typeCode"`
+	} `json:"documents"`
+}
+
+type dhlErrorResponse struct {
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+func (c *DHLExpressCarrier) Rates(ctx context.Context, shipment *Shipment) ([]CarrierRate, error) {
+	q := url.Values{}
+	q.Set("accountNumber", c.accountNumber)
+	q.Set("originCountryCode", shipment.From.CountryCode)
+	q.Set("originCityName", shipment.From.City)
+	q.Set("originPostalCode", shipment.From.PostalCode)
+	q.Set("destinationCountryCode", shipment.To.CountryCode)
+	q.Set("destinationCityName", shipment.To.City)
+	q.Set("destinationPostalCode", shipment.To.PostalCode)
+	q.Set("weight", shipment.Parcel.WeightKg.String())
+	q.Set("length", shipment.Parcel.LengthCm.String())
+	q.Set("width", shipment.Parcel.WidthCm.String())
+	q.Set("height", shipment.Parcel.HeightCm.String())
+	q.Set("plannedShippingDate", shipment.ShipDate.UTC().Format("2006-01-02"))
+	q.Set("isCustomsDeclarable", fmt.Sprint(shipment.International()))
+	q.Set("unitOfMeasurement", "metric")
+
+	var out dhlRatesResponse
+	if err := c.do(ctx, http.MethodGet, "/rates?"+q.Encode(), nil, &out); err != nil {
+		return nil, err
+	}
+	rates := make([]CarrierRate, 0, len(out.Products))
+	for _, p := range out.Products {
+		price, ok := dhlBillingPrice(p.TotalPrice)
+		if !ok {
+			continue
+		}
+		rates = append(rates, CarrierRate{
+			Carrier:     CarrierDHLExpress,
+			Service:     p.ProductCode,
+			ServiceName: p.ProductName,
+			Amount:      decimal.NewFromFloat(price.Price),
+			Currency:    strings.ToUpper(price.PriceCurrency),
+			TransitDays: p.DeliveryCapabilities.TotalTransitDays,
+		})
+	}
+	if len(rates) == 0 {
+		return nil, &CarrierError{Carrier: CarrierDHLExpress, Message: "no DHL Express product is available for this shipment"}
+	}
+	return rates, nil
+}
+
+func (c *DHLExpressCarrier) PurchaseLabel(ctx context.Context, shipment *Shipment, service string) (*CarrierLabel, error) {
+	content := map[string]any{
+		"packages": []dhlPackage{{
+			Weight: shipment.Parcel.WeightKg.InexactFloat64(),
+			Dimensions: map[string]float64{
+				"length": shipment.Parcel.LengthCm.InexactFloat64(),
+				"width":  shipment.Parcel.WidthCm.InexactFloat64(),
+				"height": shipment.Parcel.HeightCm.InexactFloat64(),
+			},
+		}},
+		"isCustomsDeclarable":   shipment.International(),
+		"declaredValue":         shipment.Value.InexactFloat64(),
+		"declaredValueCurrency": shipment.Currency,
+		"description":           shipment.Description,
+		"incoterm":              "DAP",
+		"unitOfMeasurement":     "metric",
+	}
+	if shipment.International() {
+		content["exportDeclaration"] = map[string]any{
+			"lineItems": c.lineItems(shipment),
+			"invoice": map[string]string{
+				"number": shipment.Reference,
+				"date":   shipment.ShipDate.UTC().Format("2006-01-02"),
+			},
+		}
+	}
+	req := dhlShipmentRequest{
+		PlannedShippingDateAndTime: shipment.ShipDate.UTC().Format("2006-01-02T15:04:05") + " GMT+00:00",
+		Pickup:                     map[string]bool{"isRequested": false},
+		ProductCode:                service,
+		Accounts:                   []map[string]string{{"typeCode": "shipper", "number": c.accountNumber}},
+		OutputImageProperties: map[string]any{
+			"encodingFormat": "pdf",
+			"imageOptions":   []map[string]any{{"typeCode": "label", "templateName": "ECOM26_84_001"}},
+		},
+		CustomerDetails: map[string]dhlParty{
+			"shipperDetails":  dhlPartyOf(shipment.From),
+			"receiverDetails": dhlPartyOf(shipment.To),
+		},
+		Content: content,
+	}
+	if shipment.Reference != "" {
+		req.CustomerReferences = []map[string]string{{"value": shipment.Reference, "typeCode": "CU"}}
+	}
+	var out dhlShipmentResponse
+	if err := c.do(ctx, http.MethodPost, "/shipments", req, &out); err != nil {
+		return nil, err
+	}
+	for _, doc := range out.Documents {
+		if doc.TypeCode != "label" {
+			continue
+		}
+		pdf, err := base64.StdEncoding.DecodeString(doc.Content)
+		if err != nil {
+			return nil, fmt.Errorf("decode dhl label: %w", err)
+		}
+		return &CarrierLabel{TrackingNumber: out.ShipmentTrackingNumber, LabelPDF: pdf}, nil
+	}
+	return nil, &CarrierError{Carrier: CarrierDHLExpress, Message: "DHL Express returned no label"}
+}
+
+// lineItems declares the shipment items for customs, spreading the parcel weight over the
+// units.
+func (c *DHLExpressCarrier) lineItems(shipment *Shipment) []dhlLineItem {
+	units := 0
+	for _, it := range shipment.Items {
+		units += it.Quantity
+	}
+	if units == 0 {
+		units = 1
+	}
+	unitWeight := shipment.Parcel.WeightKg.Div(decimal.NewFromInt(int64(units))).Round(3)
+	items := make([]dhlLineItem, 0, len(shipment.Items))
+	for i, it := range shipment.Items {
+		weight := unitWeight.Mul(decimal.NewFromInt(int64(it.Quantity))).InexactFloat64()
+		items = append(items, dhlLineItem{
+			Number:              i + 1,
+			Description:         it.Description,
+			Price:               it.UnitValue.InexactFloat64(),
+			Quantity:            map[string]any{"value": it.Quantity, "unitOfMeasurement": "PCS"},
+			Weight:              map[string]any{"netValue": weight, "grossValue": weight},
+			ManufacturerCountry: shipment.From.CountryCode,
+		})
+	}
+	return items
+}
+
+func (c *DHLExpressCarrier) do(ctx context.Context, method, path string, payload, out any) error {
+	var body io.Reader
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.apiKey, c.apiSecret)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxLabelBytes))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+		var e dhlErrorResponse
+		if json.Unmarshal(data, &e) == nil && (e.Detail != "" || e.Title != "") {
+			return &CarrierError{Carrier: CarrierDHLExpress, Message: strings.TrimSpace(e.Title + ": " + e.Detail)}
+		}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("dhl express returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode dhl express response: %w", err)
+	}
+	return nil
+}
+
+func dhlPartyOf(a ShipmentAddress) dhlParty {
+	company := a.Company
+	if company == "" {
+		company = a.Name
+	}
+	return dhlParty{
+		PostalAddress: dhlPostalAddress{
+			PostalCode:   a.PostalCode,
+			CityName:     a.City,
+			CountryCode:  a.CountryCode,
+			ProvinceCode: a.State,
+			AddressLine1: a.Line1,
+		},
+		ContactInformation: dhlContact{Phone: a.Phone, CompanyName: company, FullName: a.Name, Email: a.Email},
+	}
+}
+
+// dhlBillingPrice picks the price in the billing currency of the account.
+func dhlBillingPrice(prices []dhlPrice) (dhlPrice, bool) {
+	for _, p := range prices {
+		if p.CurrencyType == "BILLC" {
+			return p, true
+		}
+	}
+	if len(prices) > 0 {
+		return prices[0], true
+	}
+	return dhlPrice{}, false
+}
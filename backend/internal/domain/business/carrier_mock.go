@@ -0,0 +1,48 @@
+package business
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/abdelrahman146/kyora/internal/platform/pdf"
+	"github.com/shopspring/decimal"
+)
+
+// MockCarrier quotes fixed prices and issues printable fake labels, for tests and local
+// development. Err, when set, fails every request.
+type MockCarrier struct {
+	Err error
+
+	sequence atomic.Int64
+}
+
+func (m *MockCarrier) Code() CarrierCode { return CarrierMock }
+
+// Rates prices a standard service at 10 plus 2 per kilogram, and an express one at twice that.
+func (m *MockCarrier) Rates(ctx context.Context, shipment *Shipment) ([]CarrierRate, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	standard := decimal.NewFromInt(10).Add(shipment.Parcel.WeightKg.Mul(decimal.NewFromInt(2))).Round(2)
+	return []CarrierRate{
+		{Carrier: CarrierMock, Service: "standard", ServiceName: "Mock Standard", Amount: standard, Currency: shipment.Currency, TransitDays: 3},
+		{Carrier: CarrierMock, Service: "express", ServiceName: "Mock Express", Amount: standard.Mul(decimal.NewFromInt(2)), Currency: shipment.Currency, TransitDays: 1},
+	}, nil
+}
+
+func (m *MockCarrier) PurchaseLabel(ctx context.Context, shipment *Shipment, service string) (*CarrierLabel, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if service != "standard" && service != "express" {
+		return nil, &CarrierError{Carrier: CarrierMock, Message: fmt.Sprintf("unknown service %q", service)}
+	}
+	tracking := fmt.Sprintf("MOCK%010d", m.sequence.Add(1))
+	doc := pdf.New("Shipping label " + tracking)
+	doc.Text(40, 60, pdf.Bold, 18, pdf.AlignLeft, "MOCK CARRIER - "+service)
+	doc.Text(40, 90, pdf.Regular, 12, pdf.AlignLeft, "Tracking: "+tracking)
+	doc.Text(40, 110, pdf.Regular, 12, pdf.AlignLeft, "Reference: "+shipment.Reference)
+	doc.Text(40, 140, pdf.Regular, 12, pdf.AlignLeft, "To: "+shipment.To.Name+", "+shipment.To.City+" "+shipment.To.CountryCode)
+	return &CarrierLabel{TrackingNumber: tracking, LabelPDF: doc.Bytes()}, nil
+}
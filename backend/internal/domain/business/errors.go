@@ -54,6 +54,18 @@ func ErrInvalidCurrency() error {
 	return problem.BadRequest("invalid currency").With("field", "currency").WithCode("business.invalid_currency")
 }
 
+func ErrInvalidMinMarginPercent() error {
+	return problem.BadRequest("minMarginPercent must be below 100").With("field", "minMarginPercent").WithCode("business.invalid_min_margin_percent")
+}
+
+func ErrInvalidSKUPattern(reason string) error {
+	return problem.BadRequest("invalid skuPattern: "+reason).With("field", "skuPattern").WithCode("business.invalid_sku_pattern")
+}
+
+func ErrInvalidTimezone(tz string) error {
+	return problem.BadRequest("invalid timezone, use an IANA name such as Asia/Dubai").With("field", "timezone").With("timezone", tz).WithCode("business.invalid_timezone")
+}
+
 func ErrCountriesRequired() error {
 	return problem.BadRequest("countries is required").With("field", "countries").WithCode("business.countries_required")
 }
@@ -78,14 +90,51 @@ func ErrShippingZoneNameTooLong() error {
 	return problem.BadRequest("name is too long").With("field", "name").WithCode("business.shipping_zone_name_too_long")
 }
 
-func ErrShippingCostNegative() error {
-	return problem.BadRequest("shippingCost cannot be negative").With("field", "shippingCost").WithCode("business.shipping_cost_negative")
+func ErrShippingZoneNameEmpty() error {
+	return problem.BadRequest("name cannot be empty").With("field", "name").WithCode("business.shipping_zone_name_empty")
 }
 
-func ErrFreeShippingThresholdNegative() error {
-	return problem.BadRequest("freeShippingThreshold cannot be negative").With("field", "freeShippingThreshold").WithCode("business.free_shipping_threshold_negative")
+// ErrBusinessVersionConflict indicates the business changed since the client (or this request) loaded it.
+func ErrBusinessVersionConflict(businessID string, err error) error {
+	return problem.Conflict("business was modified by another request").WithError(err).With("businessId", businessID).WithCode("business.version_conflict")
 }
 
-func ErrShippingZoneNameEmpty() error {
-	return problem.BadRequest("name cannot be empty").With("field", "name").WithCode("business.shipping_zone_name_empty")
+// ErrCarrierNotConfigured indicates a carrier that is not set up on this deployment.
+func ErrCarrierNotConfigured(carrier CarrierCode) error {
+	return problem.BadRequest("shipping carrier is not available").With("carrier", carrier).WithCode("business.carrier_not_configured")
+}
+
+// ErrCarrierRejected indicates the carrier refused a rate or label request, e.g. for an
+// unsupported destination or an invalid address.
+func ErrCarrierRejected(carrier CarrierCode, message string, err error) error {
+	return problem.UnprocessableEntity(message).WithError(err).With("carrier", carrier).WithCode("business.carrier_rejected")
+}
+
+// ErrCarrierRequestFailed indicates the carrier API could not be reached or answered unexpectedly.
+func ErrCarrierRequestFailed(carrier CarrierCode, err error) error {
+	return problem.InternalError().WithError(err).With("carrier", carrier).WithCode("business.carrier_request_failed")
+}
+
+// ErrCarrierServiceUnavailable indicates the requested service is not offered for the shipment.
+func ErrCarrierServiceUnavailable(carrier CarrierCode, service string) error {
+	return problem.BadRequest("shipping service is not available for this shipment").With("carrier", carrier).With("service", service).WithCode("business.carrier_service_unavailable")
+}
+
+func ErrShippingLabelNotFound(labelID string, err error) error {
+	return problem.NotFound("shipping label not found").WithError(err).With("labelId", labelID).WithCode("business.shipping_label_not_found")
+}
+
+// ErrDeliveryWindowNotFound indicates that a delivery window with the given id doesn't exist (in this business)
+func ErrDeliveryWindowNotFound(windowID string, err error) error {
+	return problem.NotFound("delivery window not found").WithError(err).With("windowId", windowID).WithCode("business.delivery_window_not_found")
+}
+
+// ErrInvalidDeliveryWindow indicates a delivery window with a malformed time or an end before its start
+func ErrInvalidDeliveryWindow(field, reason string) error {
+	return problem.BadRequest("invalid delivery window").With("field", field).With("reason", reason).WithCode("business.invalid_delivery_window")
+}
+
+// ErrDeliveryWindowOverlap indicates a delivery window sharing time with another window of the same weekday
+func ErrDeliveryWindowOverlap(otherWindowID string) error {
+	return problem.Conflict("delivery window overlaps another window of the same day").With("overlapsWindowId", otherWindowID).WithCode("business.delivery_window_overlap")
 }
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
 	"github.com/abdelrahman146/kyora/internal/platform/database"
 	"github.com/abdelrahman146/kyora/internal/platform/request"
 	"github.com/abdelrahman146/kyora/internal/platform/response"
@@ -174,6 +175,22 @@ func (h *HttpHandler) ListShippingZones(c *gin.Context) {
 	response.SuccessJSON(c, http.StatusOK, resp)
 }
 
+// ListShippingCarriers returns the carriers shipping labels can be bought from.
+//
+// @Summary      List shipping carriers
+// @Description  Returns the codes of the carriers configured on this deployment (e.g. aramex, dhl_express)
+// @Tags         business
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {array} string
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/shipping-carriers [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListShippingCarriers(c *gin.Context) {
+	response.SuccessJSON(c, http.StatusOK, h.svc.ListCarriers())
+}
+
 // ListPaymentMethods returns all payment methods (global catalog + business overrides).
 //
 // @Summary      List payment methods
@@ -429,6 +446,157 @@ func (h *HttpHandler) DeleteShippingZone(c *gin.Context) {
 	response.SuccessEmpty(c, http.StatusNoContent)
 }
 
+// ListDeliveryWindows returns the delivery windows of a business.
+//
+// @Summary      List delivery windows
+// @Description  Returns the weekly delivery windows of the business, by weekday and start time
+// @Tags         business
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {array} business.DeliveryWindowResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/delivery-windows [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListDeliveryWindows(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	windows, err := h.svc.ListDeliveryWindows(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToDeliveryWindowResponses(windows))
+}
+
+// CreateDeliveryWindow creates a delivery window.
+//
+// @Summary      Create delivery window
+// @Description  Creates a weekly delivery window (weekday, HH:MM times in the business timezone and order capacity)
+// @Tags         business
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        request body business.CreateDeliveryWindowRequest true "Create delivery window"
+// @Success      201 {object} business.DeliveryWindowResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      429 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/delivery-windows [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CreateDeliveryWindow(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req CreateDeliveryWindowRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+	w, err := h.svc.CreateDeliveryWindow(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, ToDeliveryWindowResponse(w))
+}
+
+// UpdateDeliveryWindow updates a delivery window.
+//
+// @Summary      Update delivery window
+// @Description  Updates a delivery window; orders already booked on it keep their times
+// @Tags         business
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        windowId path string true "Delivery window ID"
+// @Param        request body business.UpdateDeliveryWindowRequest true "Update delivery window"
+// @Success      200 {object} business.DeliveryWindowResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      429 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/delivery-windows/{windowId} [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdateDeliveryWindow(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req UpdateDeliveryWindowRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+	w, err := h.svc.UpdateDeliveryWindow(c.Request.Context(), actor, biz, strings.TrimSpace(c.Param("windowId")), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToDeliveryWindowResponse(w))
+}
+
+// DeleteDeliveryWindow deletes a delivery window.
+//
+// @Summary      Delete delivery window
+// @Description  Deletes a delivery window; orders already booked on it stay scheduled
+// @Tags         business
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        windowId path string true "Delivery window ID"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      429 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/delivery-windows/{windowId} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteDeliveryWindow(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.svc.DeleteDeliveryWindow(c.Request.Context(), actor, biz, strings.TrimSpace(c.Param("windowId"))); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
 // UpdateBusiness updates a business (scoped to workspace).
 //
 // @Summary      Update business
@@ -584,17 +752,65 @@ func (h *HttpHandler) UnarchiveBusiness(c *gin.Context) {
 	response.SuccessEmpty(c, http.StatusNoContent)
 }
 
+// IssueDeleteConfirmation issues the token required to delete a business.
+//
+// @Summary      Request business deletion confirmation
+// @Description  Issues a short-lived, single-use confirmation token for deleting the business. Pass it back with the business descriptor as confirmName to DELETE /v1/businesses/{businessDescriptor}.
+// @Tags         business
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      201 {object} auth.Confirmation
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/delete-confirmation [post]
+// @Security     BearerAuth
+func (h *HttpHandler) IssueDeleteConfirmation(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	descriptor := strings.TrimSpace(c.Param("businessDescriptor"))
+	if descriptor == "" {
+		response.Error(c, problem.BadRequest("businessDescriptor is required"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	current, err := h.svc.GetBusinessByDescriptor(ctx, actor, descriptor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if current == nil {
+		response.Error(c, ErrBusinessNotFound(descriptor, nil))
+		return
+	}
+
+	confirmation, err := h.svc.IssueDeleteConfirmation(ctx, actor, current.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, confirmation)
+}
+
 // DeleteBusiness deletes a business by ID (scoped to workspace).
 //
 // @Summary      Delete business
-// @Description  Deletes a business by descriptor in the authenticated workspace
+// @Description  Deletes a business by descriptor in the authenticated workspace. Requires a confirmation token from POST /v1/businesses/{businessDescriptor}/delete-confirmation and the business descriptor as confirmName.
 // @Tags         business
+// @Accept       json
 // @Produce      json
 // @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body auth.ConfirmRequest true "Deletion confirmation"
 // @Success      204
 // @Failure      401 {object} problem.Problem
 // @Failure      403 {object} problem.Problem
 // @Failure      404 {object} problem.Problem
+// @Failure      428 {object} problem.Problem
 // @Failure      500 {object} problem.Problem
 // @Router       /v1/businesses/{businessDescriptor} [delete]
 // @Security     BearerAuth
@@ -609,6 +825,14 @@ func (h *HttpHandler) DeleteBusiness(c *gin.Context) {
 		response.Error(c, problem.BadRequest("businessDescriptor is required"))
 		return
 	}
+	// Without a body the service answers 428 so clients know to request a confirmation first.
+	var confirm *auth.ConfirmRequest
+	if c.Request.ContentLength != 0 {
+		confirm = &auth.ConfirmRequest{}
+		if err := request.ValidBody(c, confirm); err != nil {
+			return
+		}
+	}
 
 	ctx := c.Request.Context()
 	current, err := h.svc.GetBusinessByDescriptor(ctx, actor, descriptor)
@@ -621,7 +845,7 @@ func (h *HttpHandler) DeleteBusiness(c *gin.Context) {
 		return
 	}
 
-	if err := h.svc.DeleteBusiness(ctx, actor, current.ID); err != nil {
+	if err := h.svc.DeleteBusiness(ctx, actor, current.ID, confirm); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -4,13 +4,17 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
 	"github.com/abdelrahman146/kyora/internal/platform/types/asset"
 	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
 	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/types/timeseries"
 	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
@@ -61,8 +65,132 @@ func (t *StorefrontTheme) Scan(value any) error {
 	}
 }
 
+// CostingMethod is how the cost of sold stock is taken from a variant's purchase cost layers.
+//
+//	weighted_average  every unit costs the running average of the units in stock
+//	fifo              units are costed from the oldest layers still in stock
+type CostingMethod string
+
+const (
+	CostingMethodWeightedAverage CostingMethod = "weighted_average"
+	CostingMethodFIFO            CostingMethod = "fifo"
+)
+
+// AccountingBasis is when sales count in the business's reports.
+//
+//	accrual  an order counts when it is placed, whether or not it has been paid yet
+//	cash     an order counts when it is paid; unpaid orders do not count
+//
+// Expenses are recorded when they are paid, so they count on their date on either basis.
+type AccountingBasis string
+
+const (
+	AccountingBasisAccrual AccountingBasis = "accrual"
+	AccountingBasisCash    AccountingBasis = "cash"
+)
+
+// WithdrawalGuard is how owner withdrawals are held against the safe-to-draw amount.
+//
+//	off      withdrawals are not checked
+//	warn     a withdrawal above safe to draw is recorded with a warning
+//	enforce  a withdrawal above safe to draw is rejected unless an admin overrides it
+type WithdrawalGuard string
+
+const (
+	WithdrawalGuardOff     WithdrawalGuard = "off"
+	WithdrawalGuardWarn    WithdrawalGuard = "warn"
+	WithdrawalGuardEnforce WithdrawalGuard = "enforce"
+)
+
+// SKUPattern builds the SKUs given to variants created without one: Prefix, the category code
+// when IncludeCategory is set and a running number padded to SequenceDigits, joined by "-"
+// (e.g. KYR-TOP-0042). The zero pattern keeps the default random SKUs.
+type SKUPattern struct {
+	Prefix          string `json:"prefix"`
+	IncludeCategory bool   `json:"includeCategory"`
+	SequenceDigits  int    `json:"sequenceDigits"`
+}
+
+// MaxSKUPrefixLength and MaxSKUSequenceDigits bound an SKUPattern.
+const (
+	MaxSKUPrefixLength   = 10
+	MaxSKUSequenceDigits = 10
+)
+
+var skuPrefixRegex = regexp.MustCompile(`^[A-Z0-9]*$`)
+
+// Normalize upper-cases and trims the prefix and checks the pattern's bounds. A prefix or
+// category setting without a sequence is rejected since SKUs would not be unique.
+func (p SKUPattern) Normalize() (SKUPattern, error) {
+	p.Prefix = strings.ToUpper(strings.TrimSpace(p.Prefix))
+	switch {
+	case len(p.Prefix) > MaxSKUPrefixLength:
+		return p, ErrInvalidSKUPattern(fmt.Sprintf("prefix must be at most %d characters", MaxSKUPrefixLength))
+	case !skuPrefixRegex.MatchString(p.Prefix):
+		return p, ErrInvalidSKUPattern("prefix may only contain letters and digits")
+	case p.SequenceDigits < 0 || p.SequenceDigits > MaxSKUSequenceDigits:
+		return p, ErrInvalidSKUPattern(fmt.Sprintf("sequenceDigits must be between 0 and %d", MaxSKUSequenceDigits))
+	case p.SequenceDigits == 0 && (p.Prefix != "" || p.IncludeCategory):
+		return p, ErrInvalidSKUPattern("sequenceDigits is required")
+	}
+	return p, nil
+}
+
+// Enabled reports whether SKUs are generated from the pattern.
+func (p SKUPattern) Enabled() bool {
+	return p.SequenceDigits > 0
+}
+
+// Stem is the part of the SKU before the running number. Each stem has its own sequence.
+func (p SKUPattern) Stem(categoryCode string) string {
+	parts := make([]string, 0, 2)
+	if p.Prefix != "" {
+		parts = append(parts, p.Prefix)
+	}
+	if p.IncludeCategory && categoryCode != "" {
+		parts = append(parts, categoryCode)
+	}
+	return strings.Join(parts, "-")
+}
+
+// Format returns the SKU of number n under stem.
+func (p SKUPattern) Format(stem string, n int) string {
+	seq := fmt.Sprintf("%0*d", p.SequenceDigits, n)
+	if stem == "" {
+		return seq
+	}
+	return stem + "-" + seq
+}
+
+func (p SKUPattern) Value() (driver.Value, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (p *SKUPattern) Scan(value any) error {
+	if p == nil {
+		return problem.InternalError().WithError(errors.New("SKUPattern scan into nil receiver"))
+	}
+	if value == nil {
+		*p = SKUPattern{}
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return problem.InternalError().WithError(errors.New("unexpected scan type for SKUPattern"))
+	}
+}
+
 type Business struct {
 	gorm.Model
+	database.Versioned
 	ID          string                `gorm:"column:id;primaryKey;type:text" json:"id"`
 	Descriptor  string                `gorm:"column:descriptor;type:text;uniqueIndex:idx_workspace_id_descriptor" json:"descriptor"`
 	WorkspaceID string                `gorm:"column:workspace_id;type:text;uniqueIndex:idx_workspace_id_descriptor" json:"workspaceId"`
@@ -72,11 +200,23 @@ type Business struct {
 	Logo        *asset.AssetReference `gorm:"column:logo;type:jsonb" json:"logo,omitempty"`
 	CountryCode string                `gorm:"column:country_code;type:text" json:"countryCode"`
 	Currency    string                `gorm:"column:currency;type:text" json:"currency"`
+	Timezone    string                `gorm:"column:timezone;type:text;not null;default:'UTC'" json:"timezone"`
+	// FiscalYearStartMonth is the month (1-12) the business's fiscal year starts in. Reports
+	// bucket quarters and years by it, in Timezone.
+	FiscalYearStartMonth int `gorm:"column:fiscal_year_start_month;type:int;not null;default:1" json:"fiscalYearStartMonth"`
 
 	// Public storefront configuration.
 	StorefrontPublicID string          `gorm:"column:storefront_public_id;type:text;uniqueIndex" json:"storefrontPublicId"`
 	StorefrontEnabled  bool            `gorm:"column:storefront_enabled;type:boolean;not null;default:false" json:"storefrontEnabled"`
 	StorefrontTheme    StorefrontTheme `gorm:"column:storefront_theme;type:jsonb;not null;default:'{}'" json:"storefrontTheme"`
+	// Coming-soon mode keeps an enabled storefront private: only visitors presenting
+	// StorefrontAccessCode can browse or order, everyone else gets the coming-soon message.
+	StorefrontComingSoon        bool   `gorm:"column:storefront_coming_soon;type:boolean;not null;default:false" json:"storefrontComingSoon"`
+	StorefrontComingSoonMessage string `gorm:"column:storefront_coming_soon_message;type:text" json:"storefrontComingSoonMessage"`
+	StorefrontAccessCode        string `gorm:"column:storefront_access_code;type:text" json:"-"`
+	// StorefrontReviewRequests emails customers a request to review their products once an
+	// order is fulfilled.
+	StorefrontReviewRequests bool `gorm:"column:storefront_review_requests;type:boolean;not null;default:false" json:"storefrontReviewRequests"`
 
 	// Public business details for storefront.
 	SupportEmail   string          `gorm:"column:support_email;type:text" json:"supportEmail"`
@@ -90,15 +230,62 @@ type Business struct {
 	XURL           string          `gorm:"column:x_url;type:text" json:"xUrl"`
 	SnapchatURL    string          `gorm:"column:snapchat_url;type:text" json:"snapchatUrl"`
 	VatRate        decimal.Decimal `gorm:"column:vat_rate;type:numeric;not null;default:0" json:"vatRate"`
-	SafetyBuffer   decimal.Decimal `gorm:"column:safety_buffer;type:numeric;not null;default:0" json:"safetyBuffer"`
-	EstablishedAt  time.Time       `gorm:"column:established_at;type:date;default:now()" json:"establishedAt,omitempty"`
-	ArchivedAt     *time.Time      `gorm:"column:archived_at;type:timestamp with time zone" json:"archivedAt,omitempty"`
+	// PricesIncludeVat marks product prices as VAT-inclusive (gross): order VAT is then the
+	// tax component already contained in the subtotal instead of being added on top of it.
+	PricesIncludeVat bool            `gorm:"column:prices_include_vat;type:boolean;not null;default:false" json:"pricesIncludeVat"`
+	SafetyBuffer     decimal.Decimal `gorm:"column:safety_buffer;type:numeric;not null;default:0" json:"safetyBuffer"`
+	// MinMarginPercent is the gross margin (percent of the sale price) below which a variant price
+	// change raises a pricing review task. Zero disables the alert.
+	MinMarginPercent decimal.Decimal `gorm:"column:min_margin_percent;type:numeric;not null;default:0" json:"minMarginPercent"`
+	// CostingMethod values the stock an order takes out of the inventory, which becomes the
+	// order's COGS.
+	CostingMethod CostingMethod `gorm:"column:costing_method;type:text;not null;default:'weighted_average'" json:"costingMethod"`
+	// AccountingBasis decides whether the summary, P&L and sales analytics count orders by the
+	// date they were placed or the date they were paid.
+	AccountingBasis AccountingBasis `gorm:"column:accounting_basis;type:text;not null;default:'accrual'" json:"accountingBasis"`
+	// WithdrawalGuard checks new owner withdrawals against the safe-to-draw amount, which keeps
+	// SafetyBuffer in the business.
+	WithdrawalGuard WithdrawalGuard `gorm:"column:withdrawal_guard;type:text;not null;default:'off'" json:"withdrawalGuard"`
+	// SKUPattern generates the SKUs of variants created without one.
+	SKUPattern SKUPattern `gorm:"column:sku_pattern;type:jsonb;not null;default:'{}'" json:"skuPattern"`
+	// PendingOrderTTLHours is how long an order may stay pending before the expiry job cancels
+	// it and puts its items back in stock. Zero keeps pending orders indefinitely.
+	PendingOrderTTLHours int        `gorm:"column:pending_order_ttl_hours;type:int;not null;default:0" json:"pendingOrderTtlHours"`
+	EstablishedAt        time.Time  `gorm:"column:established_at;type:date;default:now()" json:"establishedAt,omitempty"`
+	ArchivedAt           *time.Time `gorm:"column:archived_at;type:timestamp with time zone" json:"archivedAt,omitempty"`
 }
 
 func (m *Business) TableName() string {
 	return BusinessTable
 }
 
+// CashBasis reports whether the business counts sales when they are paid.
+func (m *Business) CashBasis() bool {
+	return m != nil && m.AccountingBasis == AccountingBasisCash
+}
+
+// ReportingCalendar is the calendar the business's reports are bucketed in: its timezone and
+// fiscal year.
+func (m *Business) ReportingCalendar() timeseries.Calendar {
+	cal := timeseries.Calendar{Location: m.Location(), FiscalYearStart: time.January}
+	if m != nil && m.FiscalYearStartMonth >= 1 && m.FiscalYearStartMonth <= 12 {
+		cal.FiscalYearStart = time.Month(m.FiscalYearStartMonth)
+	}
+	return cal
+}
+
+// Location returns the business timezone, falling back to UTC when it is unset or unknown.
+func (m *Business) Location() *time.Location {
+	if m == nil || m.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(m.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
 func (m *Business) BeforeCreate(tx *gorm.DB) (err error) {
 	if m.ID == "" {
 		m.ID = id.KsuidWithPrefix(BusinessPrefix)
@@ -133,6 +320,7 @@ var BusinessSchema = struct {
 	SnapchatURL        schema.Field
 	VatRate            schema.Field
 	SafetyBuffer       schema.Field
+	MinMarginPercent   schema.Field
 	EstablishedAt      schema.Field
 	ArchivedAt         schema.Field
 	CreatedAt          schema.Field
@@ -162,6 +350,7 @@ var BusinessSchema = struct {
 	SnapchatURL:        schema.NewField("snapchat_url", "snapchatUrl"),
 	VatRate:            schema.NewField("vat_rate", "vatRate"),
 	SafetyBuffer:       schema.NewField("safety_buffer", "safetyBuffer"),
+	MinMarginPercent:   schema.NewField("min_margin_percent", "minMarginPercent"),
 	EstablishedAt:      schema.NewField("established_at", "establishedAt"),
 	ArchivedAt:         schema.NewField("archived_at", "archivedAt"),
 	CreatedAt:          schema.NewField("created_at", "createdAt"),
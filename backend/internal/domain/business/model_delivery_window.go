@@ -0,0 +1,73 @@
+package business
+
+import (
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"gorm.io/gorm"
+)
+
+const (
+	DeliveryWindowTable  = "delivery_windows"
+	DeliveryWindowStruct = "DeliveryWindow"
+	DeliveryWindowPrefix = "dwin"
+)
+
+// deliveryClockLayout is the "HH:MM" format of delivery window times.
+const deliveryClockLayout = "15:04"
+
+// DeliveryWindow is a weekly delivery slot of a local-delivery business, e.g. Saturdays
+// 09:00-12:00 for up to 10 orders. Orders book a window on a given date; times are in the
+// business timezone.
+type DeliveryWindow struct {
+	gorm.Model
+	ID         string `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	Weekday    int    `gorm:"column:weekday;type:int;not null" json:"weekday"`
+	StartTime  string `gorm:"column:start_time;type:text;not null" json:"startTime"`
+	EndTime    string `gorm:"column:end_time;type:text;not null" json:"endTime"`
+	Capacity   int    `gorm:"column:capacity;type:int;not null" json:"capacity"`
+}
+
+func (m *DeliveryWindow) TableName() string { return DeliveryWindowTable }
+
+func (m *DeliveryWindow) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(DeliveryWindowPrefix)
+	}
+	return nil
+}
+
+// Covers reports whether the window is offered on the weekday of date.
+func (m *DeliveryWindow) Covers(date time.Time) bool {
+	return int(date.Weekday()) == m.Weekday
+}
+
+// overlaps reports whether two windows of the same weekday share any time. "HH:MM"
+// strings compare in clock order.
+func (m *DeliveryWindow) overlaps(other *DeliveryWindow) bool {
+	return m.Weekday == other.Weekday && m.StartTime < other.EndTime && other.StartTime < m.EndTime
+}
+
+var DeliveryWindowSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	Weekday    schema.Field
+	StartTime  schema.Field
+	EndTime    schema.Field
+	Capacity   schema.Field
+	CreatedAt  schema.Field
+	UpdatedAt  schema.Field
+	DeletedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	Weekday:    schema.NewField("weekday", "weekday"),
+	StartTime:  schema.NewField("start_time", "startTime"),
+	EndTime:    schema.NewField("end_time", "endTime"),
+	Capacity:   schema.NewField("capacity", "capacity"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+	UpdatedAt:  schema.NewField("updated_at", "updatedAt"),
+	DeletedAt:  schema.NewField("deleted_at", "deletedAt"),
+}
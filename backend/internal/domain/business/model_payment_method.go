@@ -60,26 +60,16 @@ var BusinessPaymentMethodSchema = struct {
 
 type UpdateBusinessPaymentMethodRequest struct {
 	Enabled    *bool               `json:"enabled" binding:"omitempty"`
-	FeePercent decimal.NullDecimal `json:"feePercent" binding:"omitempty"`
-	FeeFixed   decimal.NullDecimal `json:"feeFixed" binding:"omitempty"`
+	FeePercent decimal.NullDecimal `json:"feePercent" binding:"omitempty,dgte=0"`
+	FeeFixed   decimal.NullDecimal `json:"feeFixed" binding:"omitempty,dgte=0"`
 }
 
 func (r *UpdateBusinessPaymentMethodRequest) Validate() error {
 	if r == nil {
 		return problem.BadRequest("request is required")
 	}
-	if r.FeePercent.Valid {
-		if r.FeePercent.Decimal.LessThan(decimal.Zero) {
-			return problem.BadRequest("feePercent cannot be negative").With("field", "feePercent")
-		}
-		if r.FeePercent.Decimal.GreaterThan(decimal.NewFromInt(1)) {
-			return problem.BadRequest("feePercent must be between 0 and 1").With("field", "feePercent")
-		}
-	}
-	if r.FeeFixed.Valid {
-		if r.FeeFixed.Decimal.LessThan(decimal.Zero) {
-			return problem.BadRequest("feeFixed cannot be negative").With("field", "feeFixed")
-		}
+	if r.FeePercent.Valid && r.FeePercent.Decimal.GreaterThan(decimal.NewFromInt(1)) {
+		return problem.BadRequest("feePercent must be between 0 and 1").With("field", "feePercent")
 	}
 	return nil
 }
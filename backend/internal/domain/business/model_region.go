@@ -0,0 +1,35 @@
+package business
+
+import (
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+)
+
+const (
+	BusinessRegionTable = "business_regions"
+)
+
+// BusinessRegion records the region that holds a business' data. It is control-plane data
+// kept in the home region, so requests that only carry a storefront public ID or a business
+// ID (public storefront routes, payment webhooks, jobs) can be routed before the business is
+// read. Rows are added the first time a business is looked up; storefront public IDs never
+// change and workspaces never move, so rows never go stale.
+type BusinessRegion struct {
+	BusinessID         string    `gorm:"column:business_id;primaryKey;type:text" json:"businessId"`
+	StorefrontPublicID string    `gorm:"column:storefront_public_id;type:text;not null;uniqueIndex" json:"storefrontPublicId"`
+	Region             string    `gorm:"column:region;type:text;not null" json:"region"`
+	CreatedAt          time.Time `gorm:"column:created_at" json:"createdAt"`
+}
+
+func (m *BusinessRegion) TableName() string { return BusinessRegionTable }
+
+var BusinessRegionSchema = struct {
+	BusinessID         schema.Field
+	StorefrontPublicID schema.Field
+	Region             schema.Field
+}{
+	BusinessID:         schema.NewField("business_id", "businessId"),
+	StorefrontPublicID: schema.NewField("storefront_public_id", "storefrontPublicId"),
+	Region:             schema.NewField("region", "region"),
+}
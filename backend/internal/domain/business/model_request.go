@@ -14,6 +14,7 @@ type CreateBusinessInput struct {
 	Descriptor        string                `form:"descriptor" json:"descriptor" binding:"required"`
 	CountryCode       string                `form:"countryCode" json:"countryCode" binding:"required,len=2"`
 	Currency          string                `form:"currency" json:"currency" binding:"required,len=3"`
+	Timezone          string                `form:"timezone" json:"timezone" binding:"omitempty"`
 	StorefrontEnabled bool                  `form:"storefrontEnabled" json:"storefrontEnabled" binding:"omitempty"`
 	StorefrontTheme   StorefrontTheme       `form:"storefrontTheme" json:"storefrontTheme" binding:"omitempty"`
 	SupportEmail      string                `form:"supportEmail" json:"supportEmail" binding:"omitempty,email"`
@@ -26,8 +27,9 @@ type CreateBusinessInput struct {
 	TikTokURL         string                `form:"tiktokUrl" json:"tiktokUrl" binding:"omitempty,url"`
 	XURL              string                `form:"xUrl" json:"xUrl" binding:"omitempty,url"`
 	SnapchatURL       string                `form:"snapchatUrl" json:"snapchatUrl" binding:"omitempty,url"`
-	VatRate           decimal.Decimal       `form:"vatRate" json:"vatRate" binding:"omitempty"`
-	SafetyBuffer      decimal.Decimal       `form:"safetyBuffer" json:"safetyBuffer" binding:"omitempty"`
+	VatRate           decimal.Decimal       `form:"vatRate" json:"vatRate" binding:"omitempty,dgte=0"`
+	PricesIncludeVat  bool                  `form:"pricesIncludeVat" json:"pricesIncludeVat" binding:"omitempty"`
+	SafetyBuffer      decimal.Decimal       `form:"safetyBuffer" json:"safetyBuffer" binding:"omitempty,dgte=0"`
 	EstablishedAt     date.Date             `form:"establishedAt" json:"establishedAt" binding:"omitempty"`
 }
 
@@ -39,35 +41,70 @@ type UpdateBusinessInput struct {
 	Descriptor        *string               `form:"descriptor" json:"descriptor" binding:"omitempty"`
 	CountryCode       *string               `form:"countryCode" json:"countryCode" binding:"omitempty,len=2"`
 	Currency          *string               `form:"currency" json:"currency" binding:"omitempty,len=3"`
+	Timezone          *string               `form:"timezone" json:"timezone" binding:"omitempty"`
 	StorefrontEnabled *bool                 `form:"storefrontEnabled" json:"storefrontEnabled" binding:"omitempty"`
 	StorefrontTheme   *StorefrontTheme      `form:"storefrontTheme" json:"storefrontTheme" binding:"omitempty"`
-	SupportEmail      *string               `form:"supportEmail" json:"supportEmail" binding:"omitempty,email"`
-	PhoneNumber       *string               `form:"phoneNumber" json:"phoneNumber" binding:"omitempty"`
-	WhatsappNumber    *string               `form:"whatsappNumber" json:"whatsappNumber" binding:"omitempty"`
-	Address           *string               `form:"address" json:"address" binding:"omitempty"`
-	WebsiteURL        *string               `form:"websiteUrl" json:"websiteUrl" binding:"omitempty,url"`
-	InstagramURL      *string               `form:"instagramUrl" json:"instagramUrl" binding:"omitempty,url"`
-	FacebookURL       *string               `form:"facebookUrl" json:"facebookUrl" binding:"omitempty,url"`
-	TikTokURL         *string               `form:"tiktokUrl" json:"tiktokUrl" binding:"omitempty,url"`
-	XURL              *string               `form:"xUrl" json:"xUrl" binding:"omitempty,url"`
-	SnapchatURL       *string               `form:"snapchatUrl" json:"snapchatUrl" binding:"omitempty,url"`
-	VatRate           decimal.NullDecimal   `form:"vatRate" json:"vatRate" binding:"omitempty"`
-	SafetyBuffer      decimal.NullDecimal   `form:"safetyBuffer" json:"safetyBuffer" binding:"omitempty"`
-	EstablishedAt     *date.Date            `form:"establishedAt" json:"establishedAt" binding:"omitempty"`
+	// StorefrontComingSoon hides the storefront behind StorefrontAccessCode; an empty access
+	// code clears it.
+	StorefrontComingSoon        *bool               `form:"storefrontComingSoon" json:"storefrontComingSoon" binding:"omitempty"`
+	StorefrontComingSoonMessage *string             `form:"storefrontComingSoonMessage" json:"storefrontComingSoonMessage" binding:"omitempty,max=500"`
+	StorefrontAccessCode        *string             `form:"storefrontAccessCode" json:"storefrontAccessCode" binding:"omitempty,min=4,max=64"`
+	StorefrontReviewRequests    *bool               `form:"storefrontReviewRequests" json:"storefrontReviewRequests" binding:"omitempty"`
+	SupportEmail                *string             `form:"supportEmail" json:"supportEmail" binding:"omitempty,email"`
+	PhoneNumber                 *string             `form:"phoneNumber" json:"phoneNumber" binding:"omitempty"`
+	WhatsappNumber              *string             `form:"whatsappNumber" json:"whatsappNumber" binding:"omitempty"`
+	Address                     *string             `form:"address" json:"address" binding:"omitempty"`
+	WebsiteURL                  *string             `form:"websiteUrl" json:"websiteUrl" binding:"omitempty,url"`
+	InstagramURL                *string             `form:"instagramUrl" json:"instagramUrl" binding:"omitempty,url"`
+	FacebookURL                 *string             `form:"facebookUrl" json:"facebookUrl" binding:"omitempty,url"`
+	TikTokURL                   *string             `form:"tiktokUrl" json:"tiktokUrl" binding:"omitempty,url"`
+	XURL                        *string             `form:"xUrl" json:"xUrl" binding:"omitempty,url"`
+	SnapchatURL                 *string             `form:"snapchatUrl" json:"snapchatUrl" binding:"omitempty,url"`
+	VatRate                     decimal.NullDecimal `form:"vatRate" json:"vatRate" binding:"omitempty,dgte=0"`
+	PricesIncludeVat            *bool               `form:"pricesIncludeVat" json:"pricesIncludeVat" binding:"omitempty"`
+	SafetyBuffer                decimal.NullDecimal `form:"safetyBuffer" json:"safetyBuffer" binding:"omitempty,dgte=0"`
+	MinMarginPercent            decimal.NullDecimal `form:"minMarginPercent" json:"minMarginPercent" binding:"omitempty,dgte=0"`
+	CostingMethod               *CostingMethod      `form:"costingMethod" json:"costingMethod" binding:"omitempty,oneof=weighted_average fifo"`
+	FiscalYearStartMonth        *int                `form:"fiscalYearStartMonth" json:"fiscalYearStartMonth" binding:"omitempty,min=1,max=12"`
+	AccountingBasis             *AccountingBasis    `form:"accountingBasis" json:"accountingBasis" binding:"omitempty,oneof=accrual cash"`
+	WithdrawalGuard             *WithdrawalGuard    `form:"withdrawalGuard" json:"withdrawalGuard" binding:"omitempty,oneof=off warn enforce"`
+	SKUPattern                  *SKUPattern         `form:"skuPattern" json:"skuPattern" binding:"omitempty"`
+	PendingOrderTTLHours        *int                `form:"pendingOrderTtlHours" json:"pendingOrderTtlHours" binding:"omitempty,min=0,max=8760"`
+	EstablishedAt               *date.Date          `form:"establishedAt" json:"establishedAt" binding:"omitempty"`
+	// Version is the business version the client last read. When set, the update is rejected
+	// with 409 if the business has changed since.
+	Version *int64 `form:"version" json:"version,omitempty" binding:"omitempty,min=1"`
 }
 
 // CreateShippingZoneRequest represents the request to create a shipping zone.
 type CreateShippingZoneRequest struct {
 	Name                  string          `json:"name" binding:"required"`
 	Countries             []string        `json:"countries" binding:"required,min=1,max=50,dive,required,len=2"`
-	ShippingCost          decimal.Decimal `json:"shippingCost" binding:"omitempty"`
-	FreeShippingThreshold decimal.Decimal `json:"freeShippingThreshold" binding:"omitempty"`
+	ShippingCost          decimal.Decimal `json:"shippingCost" binding:"omitempty,dgte=0"`
+	FreeShippingThreshold decimal.Decimal `json:"freeShippingThreshold" binding:"omitempty,dgte=0"`
 }
 
 // UpdateShippingZoneRequest represents the request to update a shipping zone.
 type UpdateShippingZoneRequest struct {
 	Name                  *string             `json:"name" binding:"omitempty"`
 	Countries             []string            `json:"countries" binding:"omitempty,min=1,max=50,dive,required,len=2"`
-	ShippingCost          decimal.NullDecimal `json:"shippingCost" binding:"omitempty"`
-	FreeShippingThreshold decimal.NullDecimal `json:"freeShippingThreshold" binding:"omitempty"`
+	ShippingCost          decimal.NullDecimal `json:"shippingCost" binding:"omitempty,dgte=0"`
+	FreeShippingThreshold decimal.NullDecimal `json:"freeShippingThreshold" binding:"omitempty,dgte=0"`
+}
+
+// CreateDeliveryWindowRequest represents the request to create a delivery window.
+// Weekday is 0 (Sunday) to 6 (Saturday); times are "HH:MM".
+type CreateDeliveryWindowRequest struct {
+	Weekday   *int   `json:"weekday" binding:"required,min=0,max=6"`
+	StartTime string `json:"startTime" binding:"required"`
+	EndTime   string `json:"endTime" binding:"required"`
+	Capacity  int    `json:"capacity" binding:"required,min=1,max=1000"`
+}
+
+// UpdateDeliveryWindowRequest represents the request to update a delivery window.
+type UpdateDeliveryWindowRequest struct {
+	Weekday   *int    `json:"weekday" binding:"omitempty,min=0,max=6"`
+	StartTime *string `json:"startTime" binding:"omitempty"`
+	EndTime   *string `json:"endTime" binding:"omitempty"`
+	Capacity  *int    `json:"capacity" binding:"omitempty,min=1,max=1000"`
 }
@@ -4,69 +4,98 @@ import (
 	"time"
 
 	"github.com/abdelrahman146/kyora/internal/platform/types/asset"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
 )
 
 // BusinessResponse is the API response for Business entity
 type BusinessResponse struct {
-	ID                 string                `json:"id"`
-	WorkspaceID        string                `json:"workspaceId"`
-	Descriptor         string                `json:"descriptor"`
-	Name               string                `json:"name"`
-	Brand              string                `json:"brand"`
-	Logo               *asset.AssetReference `json:"logo,omitempty"`
-	CountryCode        string                `json:"countryCode"`
-	Currency           string                `json:"currency"`
-	StorefrontPublicID string                `json:"storefrontPublicId"`
-	StorefrontEnabled  bool                  `json:"storefrontEnabled"`
-	StorefrontTheme    StorefrontTheme       `json:"storefrontTheme"`
-	SupportEmail       string                `json:"supportEmail"`
-	PhoneNumber        string                `json:"phoneNumber"`
-	WhatsappNumber     string                `json:"whatsappNumber"`
-	Address            string                `json:"address"`
-	WebsiteURL         string                `json:"websiteUrl"`
-	InstagramURL       string                `json:"instagramUrl"`
-	FacebookURL        string                `json:"facebookUrl"`
-	TikTokURL          string                `json:"tiktokUrl"`
-	XURL               string                `json:"xUrl"`
-	SnapchatURL        string                `json:"snapchatUrl"`
-	VatRate            string                `json:"vatRate"`
-	SafetyBuffer       string                `json:"safetyBuffer"`
-	EstablishedAt      time.Time             `json:"establishedAt"`
-	ArchivedAt         *time.Time            `json:"archivedAt,omitempty"`
-	CreatedAt          time.Time             `json:"createdAt"`
-	UpdatedAt          time.Time             `json:"updatedAt"`
+	ID                          string                `json:"id"`
+	Version                     int64                 `json:"version"`
+	WorkspaceID                 string                `json:"workspaceId"`
+	Descriptor                  string                `json:"descriptor"`
+	Name                        string                `json:"name"`
+	Brand                       string                `json:"brand"`
+	Logo                        *asset.AssetReference `json:"logo,omitempty"`
+	CountryCode                 string                `json:"countryCode"`
+	Currency                    string                `json:"currency"`
+	Timezone                    string                `json:"timezone"`
+	FiscalYearStartMonth        int                   `json:"fiscalYearStartMonth"`
+	StorefrontPublicID          string                `json:"storefrontPublicId"`
+	StorefrontEnabled           bool                  `json:"storefrontEnabled"`
+	StorefrontTheme             StorefrontTheme       `json:"storefrontTheme"`
+	StorefrontComingSoon        bool                  `json:"storefrontComingSoon"`
+	StorefrontComingSoonMessage string                `json:"storefrontComingSoonMessage"`
+	StorefrontAccessCode        string                `json:"storefrontAccessCode"`
+	StorefrontReviewRequests    bool                  `json:"storefrontReviewRequests"`
+	SupportEmail                string                `json:"supportEmail"`
+	PhoneNumber                 string                `json:"phoneNumber"`
+	WhatsappNumber              string                `json:"whatsappNumber"`
+	Address                     string                `json:"address"`
+	WebsiteURL                  string                `json:"websiteUrl"`
+	InstagramURL                string                `json:"instagramUrl"`
+	FacebookURL                 string                `json:"facebookUrl"`
+	TikTokURL                   string                `json:"tiktokUrl"`
+	XURL                        string                `json:"xUrl"`
+	SnapchatURL                 string                `json:"snapchatUrl"`
+	VatRate                     string                `json:"vatRate"`
+	PricesIncludeVat            bool                  `json:"pricesIncludeVat"`
+	SafetyBuffer                string                `json:"safetyBuffer"`
+	MinMarginPercent            string                `json:"minMarginPercent"`
+	CostingMethod               CostingMethod         `json:"costingMethod"`
+	AccountingBasis             AccountingBasis       `json:"accountingBasis"`
+	WithdrawalGuard             WithdrawalGuard       `json:"withdrawalGuard"`
+	SKUPattern                  SKUPattern            `json:"skuPattern"`
+	PendingOrderTTLHours        int                   `json:"pendingOrderTtlHours"`
+	EstablishedAt               time.Time             `json:"establishedAt"`
+	ArchivedAt                  *time.Time            `json:"archivedAt,omitempty"`
+	CreatedAt                   time.Time             `json:"createdAt"`
+	UpdatedAt                   time.Time             `json:"updatedAt"`
 }
 
 // ToBusinessResponse converts Business model to BusinessResponse
 func ToBusinessResponse(b *Business) BusinessResponse {
 	return BusinessResponse{
-		ID:                 b.ID,
-		WorkspaceID:        b.WorkspaceID,
-		Descriptor:         b.Descriptor,
-		Name:               b.Name,
-		Brand:              b.Brand,
-		Logo:               b.Logo,
-		CountryCode:        b.CountryCode,
-		Currency:           b.Currency,
-		StorefrontPublicID: b.StorefrontPublicID,
-		StorefrontEnabled:  b.StorefrontEnabled,
-		StorefrontTheme:    b.StorefrontTheme,
-		SupportEmail:       b.SupportEmail,
-		PhoneNumber:        b.PhoneNumber,
-		WhatsappNumber:     b.WhatsappNumber,
-		Address:            b.Address,
-		WebsiteURL:         b.WebsiteURL,
-		InstagramURL:       b.InstagramURL,
-		FacebookURL:        b.FacebookURL,
-		TikTokURL:          b.TikTokURL,
-		XURL:               b.XURL,
-		SnapchatURL:        b.SnapchatURL,
-		VatRate:            b.VatRate.String(),
-		SafetyBuffer:       b.SafetyBuffer.StringFixed(2),
-		EstablishedAt:      b.EstablishedAt,
-		ArchivedAt:         b.ArchivedAt,
-		CreatedAt:          b.CreatedAt,
-		UpdatedAt:          b.UpdatedAt,
+		ID:                          b.ID,
+		Version:                     b.Version,
+		WorkspaceID:                 b.WorkspaceID,
+		Descriptor:                  b.Descriptor,
+		Name:                        b.Name,
+		Brand:                       b.Brand,
+		Logo:                        b.Logo,
+		CountryCode:                 b.CountryCode,
+		Currency:                    b.Currency,
+		Timezone:                    b.Timezone,
+		FiscalYearStartMonth:        b.FiscalYearStartMonth,
+		StorefrontPublicID:          b.StorefrontPublicID,
+		StorefrontEnabled:           b.StorefrontEnabled,
+		StorefrontTheme:             b.StorefrontTheme,
+		StorefrontComingSoon:        b.StorefrontComingSoon,
+		StorefrontComingSoonMessage: b.StorefrontComingSoonMessage,
+		StorefrontAccessCode:        b.StorefrontAccessCode,
+		StorefrontReviewRequests:    b.StorefrontReviewRequests,
+		SupportEmail:                b.SupportEmail,
+		PhoneNumber:                 b.PhoneNumber,
+		WhatsappNumber:              b.WhatsappNumber,
+		Address:                     b.Address,
+		WebsiteURL:                  b.WebsiteURL,
+		InstagramURL:                b.InstagramURL,
+		FacebookURL:                 b.FacebookURL,
+		TikTokURL:                   b.TikTokURL,
+		XURL:                        b.XURL,
+		SnapchatURL:                 b.SnapchatURL,
+		VatRate:                     b.VatRate.String(),
+		PricesIncludeVat:            b.PricesIncludeVat,
+		SafetyBuffer:                money.StringFixed(b.SafetyBuffer, b.Currency),
+		MinMarginPercent:            b.MinMarginPercent.String(),
+		CostingMethod:               b.CostingMethod,
+		AccountingBasis:             b.AccountingBasis,
+		WithdrawalGuard:             b.WithdrawalGuard,
+		SKUPattern:                  b.SKUPattern,
+		PendingOrderTTLHours:        b.PendingOrderTTLHours,
+		EstablishedAt:               b.EstablishedAt,
+		ArchivedAt:                  b.ArchivedAt,
+		CreatedAt:                   b.CreatedAt,
+		UpdatedAt:                   b.UpdatedAt,
 	}
 }
 
@@ -111,6 +140,104 @@ func ToShippingZoneResponses(zones []ShippingZone) []ShippingZoneResponse {
 	return responses
 }
 
+// DeliveryWindowResponse is the API response for DeliveryWindow entity
+type DeliveryWindowResponse struct {
+	ID        string    `json:"id"`
+	Weekday   int       `json:"weekday"`
+	StartTime string    `json:"startTime"`
+	EndTime   string    `json:"endTime"`
+	Capacity  int       `json:"capacity"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ToDeliveryWindowResponse converts DeliveryWindow model to DeliveryWindowResponse
+func ToDeliveryWindowResponse(w *DeliveryWindow) DeliveryWindowResponse {
+	return DeliveryWindowResponse{
+		ID:        w.ID,
+		Weekday:   w.Weekday,
+		StartTime: w.StartTime,
+		EndTime:   w.EndTime,
+		Capacity:  w.Capacity,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+// ToDeliveryWindowResponses converts a slice of DeliveryWindow models to responses
+func ToDeliveryWindowResponses(windows []*DeliveryWindow) []DeliveryWindowResponse {
+	responses := make([]DeliveryWindowResponse, len(windows))
+	for i, w := range windows {
+		responses[i] = ToDeliveryWindowResponse(w)
+	}
+	return responses
+}
+
+// CarrierRateResponse is a carrier service quoted for a shipment
+type CarrierRateResponse struct {
+	Carrier     CarrierCode `json:"carrier"`
+	Service     string      `json:"service"`
+	ServiceName string      `json:"serviceName"`
+	Amount      string      `json:"amount"`
+	Currency    string      `json:"currency"`
+	TransitDays int         `json:"transitDays,omitempty"`
+}
+
+// ToCarrierRateResponses converts carrier rates to responses
+func ToCarrierRateResponses(rates []CarrierRate) []CarrierRateResponse {
+	responses := make([]CarrierRateResponse, len(rates))
+	for i, r := range rates {
+		responses[i] = CarrierRateResponse{
+			Carrier:     r.Carrier,
+			Service:     r.Service,
+			ServiceName: r.ServiceName,
+			Amount:      r.Amount.String(),
+			Currency:    r.Currency,
+			TransitDays: r.TransitDays,
+		}
+	}
+	return responses
+}
+
+// ShippingLabelResponse is the API response for ShippingLabel entity; the PDF is downloaded separately
+type ShippingLabelResponse struct {
+	ID             string      `json:"id"`
+	OrderID        string      `json:"orderId"`
+	Carrier        CarrierCode `json:"carrier"`
+	Service        string      `json:"service"`
+	TrackingNumber string      `json:"trackingNumber"`
+	TrackingURL    string      `json:"trackingUrl,omitempty"`
+	TransitDays    int         `json:"transitDays,omitempty"`
+	Amount         string      `json:"amount"`
+	Currency       string      `json:"currency"`
+	CreatedAt      time.Time   `json:"createdAt"`
+}
+
+// ToShippingLabelResponse converts ShippingLabel model to ShippingLabelResponse
+func ToShippingLabelResponse(l *ShippingLabel) ShippingLabelResponse {
+	return ShippingLabelResponse{
+		ID:             l.ID,
+		OrderID:        l.OrderID,
+		Carrier:        l.Carrier,
+		Service:        l.Service,
+		TrackingNumber: l.TrackingNumber,
+		TrackingURL:    l.Carrier.TrackingURL(l.TrackingNumber),
+		TransitDays:    l.TransitDays,
+		Amount:         l.Amount.String(),
+		Currency:       l.Currency,
+		CreatedAt:      l.CreatedAt,
+	}
+}
+
+// ToShippingLabelResponses converts a slice of ShippingLabel models to responses
+func ToShippingLabelResponses(labels []*ShippingLabel) []ShippingLabelResponse {
+	responses := make([]ShippingLabelResponse, len(labels))
+	for i, l := range labels {
+		responses[i] = ToShippingLabelResponse(l)
+	}
+	return responses
+}
+
 // PaymentMethodResponse is the API response for payment methods
 type PaymentMethodResponse struct {
 	Descriptor        string `json:"descriptor"`
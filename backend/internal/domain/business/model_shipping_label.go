@@ -0,0 +1,99 @@
+package business
+
+import (
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	ShippingLabelTable  = "shipping_labels"
+	ShippingLabelStruct = "ShippingLabel"
+	ShippingLabelPrefix = "slbl"
+)
+
+// ShippingLabel is a label bought from a carrier for an order shipment. It lives next to
+// the shipping zones; the order is referenced by ID only, as the business domain does not
+// know about orders.
+type ShippingLabel struct {
+	gorm.Model
+	ID             string          `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID     string          `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	Business       *Business       `gorm:"foreignKey:BusinessID;references:ID" json:"business,omitempty"`
+	OrderID        string          `gorm:"column:order_id;type:text;not null;index" json:"orderId"`
+	Carrier        CarrierCode     `gorm:"column:carrier;type:text;not null" json:"carrier"`
+	Service        string          `gorm:"column:service;type:text;not null" json:"service"`
+	TrackingNumber string          `gorm:"column:tracking_number;type:text;not null" json:"trackingNumber"`
+	TransitDays    int             `gorm:"column:transit_days;type:int;not null;default:0" json:"transitDays"`
+	Amount         decimal.Decimal `gorm:"column:amount;type:numeric;not null;default:0" json:"amount"`
+	Currency       string          `gorm:"column:currency;type:text;not null" json:"currency"`
+	// LabelPDF is the printable label as returned by the carrier. List queries leave it out.
+	LabelPDF []byte `gorm:"column:label_pdf;type:bytea" json:"-"`
+}
+
+func (m *ShippingLabel) TableName() string { return ShippingLabelTable }
+
+func (m *ShippingLabel) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(ShippingLabelPrefix)
+	}
+	return nil
+}
+
+var ShippingLabelSchema = struct {
+	ID             schema.Field
+	BusinessID     schema.Field
+	OrderID        schema.Field
+	Carrier        schema.Field
+	Service        schema.Field
+	TrackingNumber schema.Field
+	TransitDays    schema.Field
+	Amount         schema.Field
+	Currency       schema.Field
+	LabelPDF       schema.Field
+	CreatedAt      schema.Field
+	UpdatedAt      schema.Field
+	DeletedAt      schema.Field
+}{
+	ID:             schema.NewField("id", "id"),
+	BusinessID:     schema.NewField("business_id", "businessId"),
+	OrderID:        schema.NewField("order_id", "orderId"),
+	Carrier:        schema.NewField("carrier", "carrier"),
+	Service:        schema.NewField("service", "service"),
+	TrackingNumber: schema.NewField("tracking_number", "trackingNumber"),
+	TransitDays:    schema.NewField("transit_days", "transitDays"),
+	Amount:         schema.NewField("amount", "amount"),
+	Currency:       schema.NewField("currency", "currency"),
+	LabelPDF:       schema.NewField("label_pdf", "labelPdf"),
+	CreatedAt:      schema.NewField("created_at", "createdAt"),
+	UpdatedAt:      schema.NewField("updated_at", "updatedAt"),
+	DeletedAt:      schema.NewField("deleted_at", "deletedAt"),
+}
+
+// shippingLabelListColumns are the label columns without the PDF.
+var shippingLabelListColumns = schema.Columns(ShippingLabelTable,
+	ShippingLabelSchema.ID,
+	ShippingLabelSchema.BusinessID,
+	ShippingLabelSchema.OrderID,
+	ShippingLabelSchema.Carrier,
+	ShippingLabelSchema.Service,
+	ShippingLabelSchema.TrackingNumber,
+	ShippingLabelSchema.Amount,
+	ShippingLabelSchema.Currency,
+	ShippingLabelSchema.CreatedAt,
+	ShippingLabelSchema.UpdatedAt,
+	ShippingLabelSchema.DeletedAt,
+)
+
+// EstimatedDeliveryAt is when the carrier expects to deliver the shipment, counted from the
+// label purchase. It is nil when the carrier gave no transit time.
+func (m *ShippingLabel) EstimatedDeliveryAt() *time.Time {
+	if m.TransitDays <= 0 {
+		return nil
+	}
+	eta := m.CreatedAt.AddDate(0, 0, m.TransitDays)
+	return &eta
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
 	"github.com/abdelrahman146/kyora/internal/platform/bus"
 	"github.com/abdelrahman146/kyora/internal/platform/database"
 	"github.com/abdelrahman146/kyora/internal/platform/types/atomic"
@@ -15,12 +16,14 @@ import (
 	"github.com/abdelrahman146/kyora/internal/platform/utils/throttle"
 	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
 	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
 type Service struct {
 	storage         *Storage
 	atomicProcessor atomic.AtomicProcessor
 	bus             *bus.Bus
+	carriers        map[CarrierCode]Carrier
 }
 
 func NewService(storage *Storage, atomicProcessor atomic.AtomicProcessor, bus *bus.Bus) *Service {
@@ -52,9 +55,21 @@ func normalizeCurrency(v string) string {
 	return strings.TrimSpace(strings.ToUpper(v))
 }
 
+// normalizeTimezone validates an IANA timezone name; empty defaults to UTC.
+func normalizeTimezone(v string) (string, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return "UTC", nil
+	}
+	if _, err := time.LoadLocation(v); err != nil {
+		return "", ErrInvalidTimezone(v)
+	}
+	return v, nil
+}
+
 func (s *Service) GetBusinessByID(ctx context.Context, actor *account.User, id string) (*Business, error) {
 	workspaceID := actor.WorkspaceID
-	if err := actor.Role.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
+	if err := actor.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
 		return nil, err
 	}
 	return s.storage.business.FindOne(ctx,
@@ -64,7 +79,7 @@ func (s *Service) GetBusinessByID(ctx context.Context, actor *account.User, id s
 }
 
 func (s *Service) GetBusinessByDescriptor(ctx context.Context, actor *account.User, descriptor string) (*Business, error) {
-	if err := actor.Role.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
+	if err := actor.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
 		return nil, err
 	}
 	norm, err := normalizeBusinessDescriptor(descriptor)
@@ -110,14 +125,14 @@ func (s *Service) GetBusinessByStorefrontPublicID(ctx context.Context, storefron
 }
 
 func (s *Service) ListBusinesses(ctx context.Context, actor *account.User) ([]*Business, error) {
-	if err := actor.Role.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
+	if err := actor.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
 		return nil, err
 	}
 	return s.storage.business.FindMany(ctx, s.storage.business.ScopeWorkspaceID(actor.WorkspaceID))
 }
 
 func (s *Service) CreateBusiness(ctx context.Context, actor *account.User, input *CreateBusinessInput) (*Business, error) {
-	if err := actor.Role.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
+	if err := actor.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
 		return nil, err
 	}
 	if input == nil {
@@ -135,6 +150,10 @@ func (s *Service) CreateBusiness(ctx context.Context, actor *account.User, input
 	if len(currency) != 3 {
 		return nil, ErrInvalidCurrency()
 	}
+	timezone, err := normalizeTimezone(input.Timezone)
+	if err != nil {
+		return nil, err
+	}
 	available, err := s.IsBusinessDescriptorAvailable(ctx, actor, normDescriptor)
 	if err != nil {
 		return nil, err
@@ -153,7 +172,12 @@ func (s *Service) CreateBusiness(ctx context.Context, actor *account.User, input
 			Logo:              input.Logo,
 			CountryCode:       country,
 			VatRate:           input.VatRate,
+			PricesIncludeVat:  input.PricesIncludeVat,
+			CostingMethod:     CostingMethodWeightedAverage,
+			AccountingBasis:   AccountingBasisAccrual,
+			WithdrawalGuard:   WithdrawalGuardOff,
 			Currency:          currency,
+			Timezone:          timezone,
 			StorefrontEnabled: input.StorefrontEnabled,
 			StorefrontTheme:   input.StorefrontTheme,
 			SupportEmail:      strings.TrimSpace(input.SupportEmail),
@@ -222,7 +246,7 @@ func (s *Service) normalizeZoneCountries(in []string) (CountryCodeList, error) {
 }
 
 func (s *Service) ListShippingZones(ctx context.Context, actor *account.User, biz *Business) ([]*ShippingZone, error) {
-	if err := actor.Role.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
+	if err := actor.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
 		return nil, err
 	}
 	return s.storage.ListShippingZones(ctx, biz.ID)
@@ -239,7 +263,7 @@ func (s *Service) ListShippingZonesPublic(ctx context.Context, biz *Business) ([
 }
 
 func (s *Service) GetShippingZoneByID(ctx context.Context, actor *account.User, biz *Business, zoneID string) (*ShippingZone, error) {
-	if err := actor.Role.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
+	if err := actor.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
 		return nil, err
 	}
 	if zoneID == "" {
@@ -252,8 +276,21 @@ func (s *Service) GetShippingZoneByID(ctx context.Context, actor *account.User,
 	return zone, nil
 }
 
+// GetShippingZoneByIDForJobs returns a shipping zone of the business without an actor, for
+// background jobs.
+func (s *Service) GetShippingZoneByIDForJobs(ctx context.Context, biz *Business, zoneID string) (*ShippingZone, error) {
+	if zoneID == "" {
+		return nil, ErrZoneIdRequired()
+	}
+	zone, err := s.storage.GetShippingZoneByID(ctx, biz.ID, zoneID)
+	if err != nil {
+		return nil, ErrShippingZoneNotFound(zoneID, err)
+	}
+	return zone, nil
+}
+
 func (s *Service) CreateShippingZone(ctx context.Context, actor *account.User, biz *Business, req *CreateShippingZoneRequest) (*ShippingZone, error) {
-	if err := actor.Role.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
+	if err := actor.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
 		return nil, err
 	}
 	if !throttle.Allow(s.storage.cache, fmt.Sprintf("rl:shipping_zone:create:%s:%s", biz.ID, actor.ID), time.Minute, 60, 1*time.Second) {
@@ -269,12 +306,6 @@ func (s *Service) CreateShippingZone(ctx context.Context, actor *account.User, b
 	if len(name) > 80 {
 		return nil, ErrShippingZoneNameTooLong()
 	}
-	if req.ShippingCost.LessThan(decimal.Zero) {
-		return nil, ErrShippingCostNegative()
-	}
-	if req.FreeShippingThreshold.LessThan(decimal.Zero) {
-		return nil, ErrFreeShippingThresholdNegative()
-	}
 	countries, err := s.normalizeZoneCountries(req.Countries)
 	if err != nil {
 		return nil, err
@@ -297,7 +328,7 @@ func (s *Service) CreateShippingZone(ctx context.Context, actor *account.User, b
 }
 
 func (s *Service) UpdateShippingZone(ctx context.Context, actor *account.User, biz *Business, zoneID string, req *UpdateShippingZoneRequest) (*ShippingZone, error) {
-	if err := actor.Role.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
+	if err := actor.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
 		return nil, err
 	}
 	if !throttle.Allow(s.storage.cache, fmt.Sprintf("rl:shipping_zone:update:%s:%s", biz.ID, actor.ID), time.Minute, 120, 1*time.Second) {
@@ -328,15 +359,9 @@ func (s *Service) UpdateShippingZone(ctx context.Context, actor *account.User, b
 		zone.Countries = countries
 	}
 	if req.ShippingCost.Valid {
-		if req.ShippingCost.Decimal.LessThan(decimal.Zero) {
-			return nil, ErrShippingCostNegative()
-		}
 		zone.ShippingCost = transformer.FromNullDecimal(req.ShippingCost)
 	}
 	if req.FreeShippingThreshold.Valid {
-		if req.FreeShippingThreshold.Decimal.LessThan(decimal.Zero) {
-			return nil, ErrFreeShippingThresholdNegative()
-		}
 		zone.FreeShippingThreshold = transformer.FromNullDecimal(req.FreeShippingThreshold)
 	}
 	zone.Currency = biz.Currency
@@ -350,7 +375,7 @@ func (s *Service) UpdateShippingZone(ctx context.Context, actor *account.User, b
 }
 
 func (s *Service) DeleteShippingZone(ctx context.Context, actor *account.User, biz *Business, zoneID string) error {
-	if err := actor.Role.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
+	if err := actor.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
 		return err
 	}
 	if !throttle.Allow(s.storage.cache, fmt.Sprintf("rl:shipping_zone:delete:%s:%s", biz.ID, actor.ID), time.Minute, 60, 1*time.Second) {
@@ -364,7 +389,7 @@ func (s *Service) DeleteShippingZone(ctx context.Context, actor *account.User, b
 }
 
 func (s *Service) ArchiveBusiness(ctx context.Context, actor *account.User, id string) error {
-	if err := actor.Role.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
+	if err := actor.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
 		return err
 	}
 	business, err := s.GetBusinessByID(ctx, actor, id)
@@ -383,7 +408,7 @@ func (s *Service) ArchiveBusiness(ctx context.Context, actor *account.User, id s
 }
 
 func (s *Service) UnarchiveBusiness(ctx context.Context, actor *account.User, id string) error {
-	if err := actor.Role.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
+	if err := actor.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
 		return err
 	}
 	business, err := s.GetBusinessByID(ctx, actor, id)
@@ -399,7 +424,7 @@ func (s *Service) UnarchiveBusiness(ctx context.Context, actor *account.User, id
 }
 
 func (s *Service) UpdateBusiness(ctx context.Context, actor *account.User, id string, input *UpdateBusinessInput) (*Business, error) {
-	if err := actor.Role.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
+	if err := actor.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
 		return nil, err
 	}
 	if input == nil {
@@ -409,6 +434,9 @@ func (s *Service) UpdateBusiness(ctx context.Context, actor *account.User, id st
 	if err != nil {
 		return nil, err
 	}
+	if input.Version != nil && *input.Version != business.Version {
+		return nil, ErrBusinessVersionConflict(business.ID, nil)
+	}
 	if input.Name != nil {
 		business.Name = strings.TrimSpace(*input.Name)
 	}
@@ -449,12 +477,50 @@ func (s *Service) UpdateBusiness(ctx context.Context, actor *account.User, id st
 		}
 		business.Currency = cur
 	}
+	if input.Timezone != nil {
+		tz, err := normalizeTimezone(*input.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		business.Timezone = tz
+	}
+	if input.FiscalYearStartMonth != nil {
+		business.FiscalYearStartMonth = *input.FiscalYearStartMonth
+	}
 	if input.VatRate.Valid {
 		business.VatRate = transformer.FromNullDecimal(input.VatRate)
 	}
+	if input.PricesIncludeVat != nil {
+		business.PricesIncludeVat = *input.PricesIncludeVat
+	}
 	if input.SafetyBuffer.Valid {
 		business.SafetyBuffer = transformer.FromNullDecimal(input.SafetyBuffer)
 	}
+	if input.MinMarginPercent.Valid {
+		if input.MinMarginPercent.Decimal.GreaterThanOrEqual(decimal.NewFromInt(100)) {
+			return nil, ErrInvalidMinMarginPercent()
+		}
+		business.MinMarginPercent = input.MinMarginPercent.Decimal
+	}
+	if input.CostingMethod != nil {
+		business.CostingMethod = *input.CostingMethod
+	}
+	if input.AccountingBasis != nil {
+		business.AccountingBasis = *input.AccountingBasis
+	}
+	if input.WithdrawalGuard != nil {
+		business.WithdrawalGuard = *input.WithdrawalGuard
+	}
+	if input.SKUPattern != nil {
+		pattern, err := input.SKUPattern.Normalize()
+		if err != nil {
+			return nil, err
+		}
+		business.SKUPattern = pattern
+	}
+	if input.PendingOrderTTLHours != nil {
+		business.PendingOrderTTLHours = *input.PendingOrderTTLHours
+	}
 	if input.EstablishedAt != nil {
 		business.EstablishedAt = input.EstablishedAt.Time
 	}
@@ -464,6 +530,18 @@ func (s *Service) UpdateBusiness(ctx context.Context, actor *account.User, id st
 	if input.StorefrontTheme != nil {
 		business.StorefrontTheme = *input.StorefrontTheme
 	}
+	if input.StorefrontComingSoon != nil {
+		business.StorefrontComingSoon = *input.StorefrontComingSoon
+	}
+	if input.StorefrontComingSoonMessage != nil {
+		business.StorefrontComingSoonMessage = strings.TrimSpace(*input.StorefrontComingSoonMessage)
+	}
+	if input.StorefrontAccessCode != nil {
+		business.StorefrontAccessCode = strings.TrimSpace(*input.StorefrontAccessCode)
+	}
+	if input.StorefrontReviewRequests != nil {
+		business.StorefrontReviewRequests = *input.StorefrontReviewRequests
+	}
 	if input.SupportEmail != nil {
 		business.SupportEmail = strings.TrimSpace(*input.SupportEmail)
 	}
@@ -495,25 +573,56 @@ func (s *Service) UpdateBusiness(ctx context.Context, actor *account.User, id st
 		business.SnapchatURL = strings.TrimSpace(*input.SnapchatURL)
 	}
 	err = s.storage.business.UpdateOne(ctx, business)
+	if database.IsVersionConflict(err) {
+		return nil, ErrBusinessVersionConflict(business.ID, err)
+	}
 	if err != nil {
 		return nil, err
 	}
 	return business, nil
 }
 
-func (s *Service) DeleteBusiness(ctx context.Context, actor *account.User, id string) error {
-	if err := actor.Role.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
+// deleteConfirmationSubject is what a business deletion token confirms. Callers echo the
+// business descriptor back as the resource name.
+func deleteConfirmationSubject(actor *account.User, business *Business) auth.ConfirmationSubject {
+	return auth.ConfirmationSubject{
+		Action:       "business.delete",
+		ResourceID:   business.ID,
+		ResourceName: business.Descriptor,
+		ActorID:      actor.ID,
+	}
+}
+
+// IssueDeleteConfirmation issues the short-lived token DeleteBusiness requires.
+func (s *Service) IssueDeleteConfirmation(ctx context.Context, actor *account.User, id string) (*auth.Confirmation, error) {
+	if err := actor.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
+		return nil, err
+	}
+	business, err := s.GetBusinessByID(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.storage.confirmations.Issue(deleteConfirmationSubject(actor, business))
+}
+
+// DeleteBusiness deletes a business once confirm carries a token issued by
+// IssueDeleteConfirmation to the same actor and the business descriptor.
+func (s *Service) DeleteBusiness(ctx context.Context, actor *account.User, id string, confirm *auth.ConfirmRequest) error {
+	if err := actor.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
 		return err
 	}
 	business, err := s.GetBusinessByID(ctx, actor, id)
 	if err != nil {
 		return err
 	}
+	if err := s.storage.confirmations.Redeem(deleteConfirmationSubject(actor, business), confirm); err != nil {
+		return err
+	}
 	return s.storage.business.DeleteOne(ctx, business)
 }
 
 func (s *Service) IsBusinessDescriptorAvailable(ctx context.Context, actor *account.User, descriptor string) (bool, error) {
-	if err := actor.Role.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
+	if err := actor.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
 		return false, err
 	}
 	norm, err := normalizeBusinessDescriptor(descriptor)
@@ -534,14 +643,14 @@ func (s *Service) IsBusinessDescriptorAvailable(ctx context.Context, actor *acco
 }
 
 func (s *Service) CountBusinesses(ctx context.Context, actor *account.User) (int64, error) {
-	if err := actor.Role.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
+	if err := actor.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
 		return 0, err
 	}
 	return s.storage.business.Count(ctx, s.storage.business.ScopeWorkspaceID(actor.WorkspaceID))
 }
 
 func (s *Service) CountActiveBusinesses(ctx context.Context, actor *account.User) (int64, error) {
-	if err := actor.Role.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
+	if err := actor.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
 		return 0, err
 	}
 	return s.storage.business.Count(ctx,
@@ -550,6 +659,26 @@ func (s *Service) CountActiveBusinesses(ctx context.Context, actor *account.User
 	)
 }
 
+// ListActiveBusinessesForJobs returns the non-archived businesses of every workspace, or of
+// one workspace when workspaceID is set, ordered by workspace. It is for background jobs that
+// run without an actor.
+func (s *Service) ListActiveBusinessesForJobs(ctx context.Context, workspaceID string) ([]*Business, error) {
+	scopes := []func(db *gorm.DB) *gorm.DB{
+		s.storage.business.ScopeIsNull(BusinessSchema.ArchivedAt),
+		s.storage.business.WithOrderBy([]string{"workspace_id ASC", "created_at ASC"}),
+	}
+	if workspaceID != "" {
+		scopes = append(scopes, s.storage.business.ScopeWorkspaceID(workspaceID))
+	}
+	return s.storage.business.FindMany(ctx, scopes...)
+}
+
+// GetBusinessByIDForJobs returns a business by ID without an actor, for event handlers and
+// background jobs.
+func (s *Service) GetBusinessByIDForJobs(ctx context.Context, id string) (*Business, error) {
+	return s.storage.business.FindOne(ctx, s.storage.business.ScopeID(id))
+}
+
 func (s *Service) MaxBusinessesEnforceFunc(ctx context.Context, actor *account.User, businessID string) (int64, error) {
 	return s.CountActiveBusinesses(ctx, actor)
 }
@@ -0,0 +1,148 @@
+package business
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/throttle"
+)
+
+// normalizeDeliveryClock parses an "HH:MM" time and returns it zero-padded ("9:00" → "09:00").
+func normalizeDeliveryClock(field, value string) (string, error) {
+	t, err := time.Parse(deliveryClockLayout, strings.TrimSpace(value))
+	if err != nil {
+		return "", ErrInvalidDeliveryWindow(field, "use HH:MM")
+	}
+	return t.Format(deliveryClockLayout), nil
+}
+
+// validateDeliveryWindow checks the times of w and that it does not overlap the other
+// windows of the business.
+func (s *Service) validateDeliveryWindow(ctx context.Context, w *DeliveryWindow) error {
+	var err error
+	if w.StartTime, err = normalizeDeliveryClock("startTime", w.StartTime); err != nil {
+		return err
+	}
+	if w.EndTime, err = normalizeDeliveryClock("endTime", w.EndTime); err != nil {
+		return err
+	}
+	if w.EndTime <= w.StartTime {
+		return ErrInvalidDeliveryWindow("endTime", "must be after startTime")
+	}
+	others, err := s.storage.ListDeliveryWindows(ctx, w.BusinessID)
+	if err != nil {
+		return err
+	}
+	for _, other := range others {
+		if other.ID != w.ID && w.overlaps(other) {
+			return ErrDeliveryWindowOverlap(other.ID)
+		}
+	}
+	return nil
+}
+
+func (s *Service) ListDeliveryWindows(ctx context.Context, actor *account.User, biz *Business) ([]*DeliveryWindow, error) {
+	if err := actor.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
+		return nil, err
+	}
+	return s.storage.ListDeliveryWindows(ctx, biz.ID)
+}
+
+// ListDeliveryWindowsForOrders returns the delivery windows of a business to actors that
+// schedule orders, who may not view the business settings.
+func (s *Service) ListDeliveryWindowsForOrders(ctx context.Context, biz *Business) ([]*DeliveryWindow, error) {
+	return s.storage.ListDeliveryWindows(ctx, biz.ID)
+}
+
+// GetDeliveryWindowForOrders returns a delivery window of the business to actors that
+// schedule orders.
+func (s *Service) GetDeliveryWindowForOrders(ctx context.Context, biz *Business, windowID string) (*DeliveryWindow, error) {
+	w, err := s.storage.GetDeliveryWindowByID(ctx, biz.ID, windowID)
+	if err != nil {
+		return nil, ErrDeliveryWindowNotFound(windowID, err)
+	}
+	return w, nil
+}
+
+func (s *Service) CreateDeliveryWindow(ctx context.Context, actor *account.User, biz *Business, req *CreateDeliveryWindowRequest) (*DeliveryWindow, error) {
+	if err := actor.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
+		return nil, err
+	}
+	if !throttle.Allow(s.storage.cache, fmt.Sprintf("rl:delivery_window:create:%s:%s", biz.ID, actor.ID), time.Minute, 60, 250*time.Millisecond) {
+		return nil, ErrBusinessRateLimited()
+	}
+	if req == nil || req.Weekday == nil {
+		return nil, ErrBusinessInputRequired()
+	}
+	w := &DeliveryWindow{
+		BusinessID: biz.ID,
+		Weekday:    *req.Weekday,
+		StartTime:  req.StartTime,
+		EndTime:    req.EndTime,
+		Capacity:   req.Capacity,
+	}
+	if err := s.validateDeliveryWindow(ctx, w); err != nil {
+		return nil, err
+	}
+	if err := s.storage.windows.CreateOne(ctx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// UpdateDeliveryWindow changes a delivery window. Orders already booked on it keep the
+// times they were booked with.
+func (s *Service) UpdateDeliveryWindow(ctx context.Context, actor *account.User, biz *Business, windowID string, req *UpdateDeliveryWindowRequest) (*DeliveryWindow, error) {
+	if err := actor.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
+		return nil, err
+	}
+	if !throttle.Allow(s.storage.cache, fmt.Sprintf("rl:delivery_window:update:%s:%s", biz.ID, actor.ID), time.Minute, 120, 250*time.Millisecond) {
+		return nil, ErrBusinessRateLimited()
+	}
+	if req == nil {
+		return nil, ErrBusinessInputRequired()
+	}
+	w, err := s.storage.GetDeliveryWindowByID(ctx, biz.ID, windowID)
+	if err != nil {
+		return nil, ErrDeliveryWindowNotFound(windowID, err)
+	}
+	if req.Weekday != nil {
+		w.Weekday = *req.Weekday
+	}
+	if req.StartTime != nil {
+		w.StartTime = *req.StartTime
+	}
+	if req.EndTime != nil {
+		w.EndTime = *req.EndTime
+	}
+	if req.Capacity != nil {
+		w.Capacity = *req.Capacity
+	}
+	if err := s.validateDeliveryWindow(ctx, w); err != nil {
+		return nil, err
+	}
+	if err := s.storage.windows.UpdateOne(ctx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// DeleteDeliveryWindow removes a delivery window. Orders already booked on it stay
+// scheduled.
+func (s *Service) DeleteDeliveryWindow(ctx context.Context, actor *account.User, biz *Business, windowID string) error {
+	if err := actor.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
+		return err
+	}
+	if !throttle.Allow(s.storage.cache, fmt.Sprintf("rl:delivery_window:delete:%s:%s", biz.ID, actor.ID), time.Minute, 60, 250*time.Millisecond) {
+		return ErrBusinessRateLimited()
+	}
+	w, err := s.storage.GetDeliveryWindowByID(ctx, biz.ID, windowID)
+	if err != nil {
+		return ErrDeliveryWindowNotFound(windowID, err)
+	}
+	return s.storage.windows.DeleteOne(ctx, w)
+}
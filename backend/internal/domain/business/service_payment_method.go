@@ -27,7 +27,7 @@ type BusinessPaymentMethodView struct {
 }
 
 func (s *Service) ListPaymentMethods(ctx context.Context, actor *account.User, biz *Business) ([]BusinessPaymentMethodView, error) {
-	if err := actor.Role.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
+	if err := actor.HasPermission(role.ActionView, role.ResourceBusiness); err != nil {
 		return nil, err
 	}
 	rows, err := s.storage.ListBusinessPaymentMethods(ctx, biz.ID)
@@ -63,7 +63,7 @@ func (s *Service) ListPaymentMethods(ctx context.Context, actor *account.User, b
 }
 
 func (s *Service) UpdatePaymentMethod(ctx context.Context, actor *account.User, biz *Business, descriptor string, req *UpdateBusinessPaymentMethodRequest) (*BusinessPaymentMethodView, error) {
-	if err := actor.Role.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
+	if err := actor.HasPermission(role.ActionManage, role.ResourceBusiness); err != nil {
 		return nil, err
 	}
 	if !throttle.Allow(s.storage.cache, fmt.Sprintf("rl:payment_method:update:%s:%s", biz.ID, actor.ID), time.Minute, 120, 250*time.Millisecond) {
@@ -0,0 +1,67 @@
+package business
+
+import (
+	"context"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/region"
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ResolveStorefrontRegion returns the region holding the business of a storefront public ID,
+// for unauthenticated storefront requests that must be routed before the business is read.
+// It returns a record not found error when no region has the storefront.
+func (s *Service) ResolveStorefrontRegion(ctx context.Context, storefrontPublicID string) (string, error) {
+	return s.resolveRegion(ctx, BusinessRegionSchema.StorefrontPublicID, BusinessSchema.StorefrontPublicID, strings.TrimSpace(storefrontPublicID))
+}
+
+// ResolveBusinessRegion returns the region holding a business' data, for webhooks and jobs
+// that only know the business ID. It returns a record not found error when no region has
+// the business.
+func (s *Service) ResolveBusinessRegion(ctx context.Context, businessID string) (string, error) {
+	return s.resolveRegion(ctx, BusinessRegionSchema.BusinessID, BusinessSchema.ID, strings.TrimSpace(businessID))
+}
+
+// resolveRegion looks the business up in the region index and, on a miss, in every region,
+// recording the region it was found in. With a single region there is nothing to look up.
+func (s *Service) resolveRegion(ctx context.Context, indexField, businessField schema.Field, value string) (string, error) {
+	regions := s.storage.db.Regions()
+	if len(regions) == 1 {
+		return regions[0], nil
+	}
+	if value == "" {
+		return "", gorm.ErrRecordNotFound
+	}
+	entry, err := s.storage.regions.FindOne(ctx, s.storage.regions.ScopeEquals(indexField, value))
+	if err == nil {
+		return entry.Region, nil
+	}
+	if !database.IsRecordNotFound(err) {
+		return "", err
+	}
+	for _, name := range regions {
+		biz, err := s.storage.business.FindOne(region.WithRegion(ctx, name),
+			s.storage.business.ScopeEquals(businessField, value),
+			s.storage.business.WithSelect(BusinessSchema.ID.Column(), BusinessSchema.StorefrontPublicID.Column()),
+		)
+		if database.IsRecordNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		entry := &BusinessRegion{BusinessID: biz.ID, StorefrontPublicID: biz.StorefrontPublicID, Region: name}
+		if err := s.storage.regions.CreateOne(ctx, entry, func(db *gorm.DB) *gorm.DB {
+			return db.Clauses(clause.OnConflict{DoNothing: true})
+		}); err != nil {
+			// The lookup is still correct; the next one scans the regions again.
+			logger.FromContext(ctx).Warn("failed to record business region", "error", err, "businessId", biz.ID, "region", name)
+		}
+		return name, nil
+	}
+	return "", gorm.ErrRecordNotFound
+}
@@ -0,0 +1,113 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+)
+
+// QuoteShippingRates asks the carriers for the prices of the shipment. An empty carrier
+// asks every configured carrier; carriers that fail are left out unless none answers.
+func (s *Service) QuoteShippingRates(ctx context.Context, biz *Business, carrier CarrierCode, shipment *Shipment) ([]CarrierRate, error) {
+	if carrier != "" {
+		c, err := s.carrier(carrier)
+		if err != nil {
+			return nil, err
+		}
+		rates, err := c.Rates(ctx, shipment)
+		if err != nil {
+			return nil, carrierError(carrier, err)
+		}
+		return rates, nil
+	}
+	if len(s.carriers) == 0 {
+		return nil, ErrCarrierNotConfigured("")
+	}
+	var rates []CarrierRate
+	var firstErr error
+	for _, code := range s.ListCarriers() {
+		r, err := s.carriers[code].Rates(ctx, shipment)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = carrierError(code, err)
+			}
+			continue
+		}
+		rates = append(rates, r...)
+	}
+	if len(rates) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return rates, nil
+}
+
+// PurchaseShippingLabel books the shipment with a carrier service and stores the label for
+// the order. The service is re-quoted first, so the stored amount is the price at purchase.
+func (s *Service) PurchaseShippingLabel(ctx context.Context, biz *Business, orderID string, carrier CarrierCode, service string, shipment *Shipment) (*ShippingLabel, error) {
+	c, err := s.carrier(carrier)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := c.Rates(ctx, shipment)
+	if err != nil {
+		return nil, carrierError(carrier, err)
+	}
+	var rate *CarrierRate
+	for i := range rates {
+		if strings.EqualFold(rates[i].Service, service) {
+			rate = &rates[i]
+			break
+		}
+	}
+	if rate == nil {
+		return nil, ErrCarrierServiceUnavailable(carrier, service)
+	}
+	booked, err := c.PurchaseLabel(ctx, shipment, rate.Service)
+	if err != nil {
+		return nil, carrierError(carrier, err)
+	}
+	label := &ShippingLabel{
+		BusinessID:     biz.ID,
+		OrderID:        orderID,
+		Carrier:        carrier,
+		Service:        rate.Service,
+		TrackingNumber: booked.TrackingNumber,
+		TransitDays:    rate.TransitDays,
+		Amount:         rate.Amount,
+		Currency:       rate.Currency,
+		LabelPDF:       booked.LabelPDF,
+	}
+	if err := s.storage.CreateShippingLabel(ctx, label); err != nil {
+		return nil, err
+	}
+	return label, nil
+}
+
+// ListShippingLabels returns the labels bought for an order, newest first, without PDFs.
+func (s *Service) ListShippingLabels(ctx context.Context, biz *Business, orderID string) ([]*ShippingLabel, error) {
+	return s.storage.ListShippingLabels(ctx, biz.ID, orderID)
+}
+
+// GetShippingLabel returns a label of an order with its PDF.
+func (s *Service) GetShippingLabel(ctx context.Context, biz *Business, orderID, labelID string) (*ShippingLabel, error) {
+	label, err := s.storage.GetShippingLabel(ctx, biz.ID, orderID, labelID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrShippingLabelNotFound(labelID, err)
+		}
+		return nil, err
+	}
+	return label, nil
+}
+
+// carrierError maps a carrier failure to a problem: rejections carry the carrier's
+// message, anything else is an internal error.
+func carrierError(carrier CarrierCode, err error) error {
+	var rejected *CarrierError
+	if errors.As(err, &rejected) {
+		return ErrCarrierRejected(carrier, rejected.Message, err)
+	}
+	return ErrCarrierRequestFailed(carrier, err)
+}
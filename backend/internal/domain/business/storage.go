@@ -3,24 +3,38 @@ package business
 import (
 	"context"
 
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
 	"github.com/abdelrahman146/kyora/internal/platform/cache"
 	"github.com/abdelrahman146/kyora/internal/platform/database"
 	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
 )
 
 type Storage struct {
+	db       *database.Database
 	cache    *cache.Cache
 	business *database.Repository[Business]
 	zone     *database.Repository[ShippingZone]
 	payment  *database.Repository[BusinessPaymentMethod]
+	labels   *database.Repository[ShippingLabel]
+	windows  *database.Repository[DeliveryWindow]
+	// regions is the home-region index of where each business' data lives.
+	regions *database.Repository[BusinessRegion]
+
+	confirmations *auth.Confirmations
 }
 
 func NewStorage(db *database.Database, cache *cache.Cache) *Storage {
 	return &Storage{
+		db:       db,
 		cache:    cache,
 		business: database.NewRepository[Business](db),
 		zone:     database.NewRepository[ShippingZone](db),
 		payment:  database.NewRepository[BusinessPaymentMethod](db),
+		labels:   database.NewRepository[ShippingLabel](db),
+		windows:  database.NewRepository[DeliveryWindow](db),
+		regions:  database.NewRepository[BusinessRegion](db.Global()),
+
+		confirmations: auth.NewConfirmations(cache),
 	}
 }
 
@@ -85,3 +99,36 @@ func (s *Storage) UpsertBusinessPaymentMethod(ctx context.Context, pm *BusinessP
 	// Best-effort create.
 	return s.payment.CreateOne(ctx, pm)
 }
+
+func (s *Storage) CreateShippingLabel(ctx context.Context, label *ShippingLabel) error {
+	return s.labels.CreateOne(ctx, label)
+}
+
+// ListShippingLabels returns the labels of an order, newest first, without their PDFs.
+func (s *Storage) ListShippingLabels(ctx context.Context, businessID, orderID string) ([]*ShippingLabel, error) {
+	return s.labels.FindMany(ctx,
+		s.labels.ScopeBusinessID(businessID),
+		s.labels.ScopeEquals(ShippingLabelSchema.OrderID, orderID),
+		s.labels.WithSelect(shippingLabelListColumns...),
+		s.labels.WithOrderBy([]string{"created_at DESC"}),
+	)
+}
+
+func (s *Storage) GetShippingLabel(ctx context.Context, businessID, orderID, labelID string) (*ShippingLabel, error) {
+	return s.labels.FindByID(ctx, labelID,
+		s.labels.ScopeBusinessID(businessID),
+		s.labels.ScopeEquals(ShippingLabelSchema.OrderID, orderID),
+	)
+}
+
+// ListDeliveryWindows returns the delivery windows of a business in weekly order.
+func (s *Storage) ListDeliveryWindows(ctx context.Context, businessID string) ([]*DeliveryWindow, error) {
+	return s.windows.FindMany(ctx,
+		s.windows.ScopeBusinessID(businessID),
+		s.windows.WithOrderBy([]string{"weekday ASC", "start_time ASC"}),
+	)
+}
+
+func (s *Storage) GetDeliveryWindowByID(ctx context.Context, businessID, windowID string) (*DeliveryWindow, error) {
+	return s.windows.FindByID(ctx, windowID, s.windows.ScopeBusinessID(businessID))
+}
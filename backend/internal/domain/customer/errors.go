@@ -13,6 +13,14 @@ func ErrCustomerDuplicateEmail(err error) *problem.Problem {
 	return problem.Conflict("customer with this email already exists").WithError(err).WithCode("customer.duplicate_email")
 }
 
+// ErrCustomerPossibleDuplicates lists the existing customers a new customer looks like. The
+// client confirms with allowDuplicate to create it anyway.
+func ErrCustomerPossibleDuplicates(duplicates []*PossibleDuplicate) *problem.Problem {
+	return problem.Conflict("customer looks like an existing customer").
+		With("duplicates", ToPossibleDuplicateResponses(duplicates)).
+		WithCode("customer.possible_duplicate")
+}
+
 func ErrCustomerInvalidData(message string) *problem.Problem {
 	return problem.BadRequest(message).WithCode("customer.invalid_data")
 }
@@ -1,6 +1,7 @@
 package customer
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/abdelrahman146/kyora/internal/domain/account"
@@ -9,6 +10,7 @@ import (
 	"github.com/abdelrahman146/kyora/internal/platform/request"
 	"github.com/abdelrahman146/kyora/internal/platform/response"
 	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
 	"github.com/gin-gonic/gin"
 )
 
@@ -32,13 +34,25 @@ func (h *HttpHandler) getBusinessForWorkspace(c *gin.Context, actor *account.Use
 // Customer endpoints
 
 type listCustomersQuery struct {
-	Page            int      `form:"page" binding:"omitempty,min=1"`
-	PageSize        int      `form:"pageSize" binding:"omitempty,min=1,max=100"`
-	OrderBy         []string `form:"orderBy" binding:"omitempty"`
-	SearchTerm      string   `form:"search" binding:"omitempty"`
-	CountryCode     string   `form:"countryCode" binding:"omitempty"`
-	HasOrders       *bool    `form:"hasOrders" binding:"omitempty"`
-	SocialPlatforms []string `form:"socialPlatforms" binding:"omitempty"`
+	Page             int            `form:"page" binding:"omitempty,min=1"`
+	PageSize         int            `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	OrderBy          []string       `form:"orderBy" binding:"omitempty"`
+	SearchTerm       string         `form:"search" binding:"omitempty"`
+	CountryCode      string         `form:"countryCode" binding:"omitempty"`
+	HasOrders        *bool          `form:"hasOrders" binding:"omitempty"`
+	SocialPlatforms  []string       `form:"socialPlatforms" binding:"omitempty"`
+	PriceListID      string         `form:"priceListId" binding:"omitempty"`
+	MarketingConsent ConsentChannel `form:"marketingConsent" binding:"omitempty,oneof=email whatsapp sms"`
+}
+
+// queryError keeps domain problems (e.g. an unknown price list) and reports anything else as
+// a failed customer query.
+func queryError(err error) error {
+	var p *problem.Problem
+	if errors.As(err, &p) {
+		return err
+	}
+	return ErrCustomerQueryFailed(err)
 }
 
 // ListCustomers returns a paginated list of customers
@@ -55,6 +69,8 @@ type listCustomersQuery struct {
 // @Param        countryCode query string false "Filter by country code (e.g., US, AE)"
 // @Param        hasOrders query bool false "Filter by customers with or without orders"
 // @Param        socialPlatforms query []string false "Filter by social media platforms (instagram, tiktok, facebook, x, snapchat, whatsapp)"
+// @Param        priceListId query string false "Filter by assigned price list"
+// @Param        marketingConsent query string false "Only customers who can be sent marketing on the channel (email, whatsapp, sms)"
 // @Success      200 {object} list.ListResponse[customer.CustomerResponse]
 // @Failure      401 {object} problem.Problem
 // @Failure      500 {object} problem.Problem
@@ -97,9 +113,11 @@ func (h *HttpHandler) ListCustomers(c *gin.Context) {
 	listReq := list.NewListRequest(query.Page, query.PageSize, query.OrderBy, query.SearchTerm)
 
 	filters := &ListCustomersFilters{
-		CountryCode:     query.CountryCode,
-		HasOrders:       query.HasOrders,
-		SocialPlatforms: query.SocialPlatforms,
+		CountryCode:      query.CountryCode,
+		HasOrders:        query.HasOrders,
+		SocialPlatforms:  query.SocialPlatforms,
+		PriceListID:      query.PriceListID,
+		MarketingConsent: query.MarketingConsent,
 	}
 
 	customers, totalCount, err := h.service.ListCustomers(c.Request.Context(), actor, biz, listReq, filters)
@@ -178,7 +196,7 @@ func (h *HttpHandler) GetCustomer(c *gin.Context) {
 // CreateCustomer creates a new customer
 //
 // @Summary      Create customer
-// @Description  Creates a new customer for the authenticated workspace
+// @Description  Creates a new customer for the authenticated workspace. When the customer shares the email or a phone number of an existing customer, or has a similar name, it answers 409 customer.possible_duplicate listing those customers under "duplicates"; send allowDuplicate to create it anyway.
 // @Tags         customer
 // @Accept       json
 // @Produce      json
@@ -215,7 +233,7 @@ func (h *HttpHandler) CreateCustomer(c *gin.Context) {
 			response.Error(c, ErrCustomerDuplicateEmail(err))
 			return
 		}
-		response.Error(c, ErrCustomerQueryFailed(err))
+		response.Error(c, queryError(err))
 		return
 	}
 
@@ -277,7 +295,7 @@ func (h *HttpHandler) UpdateCustomer(c *gin.Context) {
 			response.Error(c, ErrCustomerDuplicateEmail(err))
 			return
 		}
-		response.Error(c, ErrCustomerQueryFailed(err))
+		response.Error(c, queryError(err))
 		return
 	}
 
@@ -719,3 +737,55 @@ func (h *HttpHandler) DeleteCustomerNote(c *gin.Context) {
 
 	response.SuccessEmpty(c, http.StatusNoContent)
 }
+
+// AssignPriceList assigns a price list to customers
+//
+// @Summary      Assign price list to customers
+// @Description  Sets (or clears, with a null priceListId) the price list of the listed customers, or of every customer matching the segment filters when no customerIds are given
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        request body AssignPriceListRequest true "Price list and targeted customers"
+// @Success      200 {object} customer.AssignPriceListResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/customers/price-list [put]
+// @Security     BearerAuth
+func (h *HttpHandler) AssignPriceList(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var req AssignPriceListRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+
+	biz, err := h.getBusinessForWorkspace(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	priceListID := ""
+	if req.PriceListID != nil {
+		priceListID = *req.PriceListID
+	}
+	filters := &ListCustomersFilters{
+		CountryCode:     req.CountryCode,
+		HasOrders:       req.HasOrders,
+		SocialPlatforms: req.SocialPlatforms,
+	}
+	updated, err := h.service.AssignPriceList(c.Request.Context(), actor, biz, priceListID, req.CustomerIDs, filters)
+	if err != nil {
+		response.Error(c, queryError(err))
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, AssignPriceListResponse{Updated: updated})
+}
@@ -1,6 +1,7 @@
 package customer
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/abdelrahman146/kyora/internal/domain/business"
@@ -28,26 +29,42 @@ const (
 	CustomerNotesStruct = "Notes"
 )
 
+// Customer is a buyer of the business. PriceListID is the customer's pricing tier
+// (an inventory price list); when empty the business' default price list applies.
+// ErasedAt is set once the customer's personal data was erased on request.
+// Marketing consent is kept per channel with when and how it was last given or withdrawn;
+// see CanMarket.
 type Customer struct {
 	gorm.Model
-	ID                string             `gorm:"column:id;primaryKey;type:text" json:"id"`
-	BusinessID        string             `gorm:"column:business_id;type:text;not null;index;uniqueIndex:idx_business_email" json:"businessId"`
-	Business          *business.Business `gorm:"foreignKey:BusinessID;references:ID" json:"business,omitempty"`
-	Name              string             `gorm:"column:name;type:text;not null" json:"name"`
-	CountryCode       string             `gorm:"column:country_code;type:text;not null" json:"countryCode"`
-	Gender            CustomerGender     `gorm:"column:gender;type:text" json:"gender,omitempty"`
-	Email             nullable.String    `gorm:"column:email;type:text;uniqueIndex:idx_business_email" json:"email,omitempty"`
-	PhoneNumber       nullable.String    `gorm:"column:phone_number;type:text" json:"phoneNumber,omitempty"`
-	PhoneCode         nullable.String    `gorm:"column:phone_code;type:text" json:"phoneCode,omitempty"`
-	TikTokUsername    nullable.String    `gorm:"column:tiktok_username;type:text" json:"tiktokUsername,omitempty"`
-	InstagramUsername nullable.String    `gorm:"column:instagram_username;type:text" json:"instagramUsername,omitempty"`
-	FacebookUsername  nullable.String    `gorm:"column:facebook_username;type:text" json:"facebookUsername,omitempty"`
-	XUsername         nullable.String    `gorm:"column:x_username;type:text" json:"xUsername,omitempty"`
-	SnapchatUsername  nullable.String    `gorm:"column:snapchat_username;type:text" json:"snapchatUsername,omitempty"`
-	WhatsappNumber    nullable.String    `gorm:"column:whatsapp_number;type:text" json:"whatsappNumber,omitempty"`
-	JoinedAt          time.Time          `gorm:"column:joined_at;type:timestamptz;not null;default:now()" json:"joinedAt"`
-	Addresses         []*CustomerAddress `gorm:"foreignKey:CustomerID;references:ID" json:"addresses,omitempty"`
-	Notes             []*CustomerNote    `gorm:"foreignKey:CustomerID;references:ID" json:"notes,omitempty"`
+	ID                             string             `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID                     string             `gorm:"column:business_id;type:text;not null;index;uniqueIndex:idx_business_email" json:"businessId"`
+	Business                       *business.Business `gorm:"foreignKey:BusinessID;references:ID" json:"business,omitempty"`
+	Name                           string             `gorm:"column:name;type:text;not null" json:"name"`
+	CountryCode                    string             `gorm:"column:country_code;type:text;not null" json:"countryCode"`
+	Gender                         CustomerGender     `gorm:"column:gender;type:text" json:"gender,omitempty"`
+	Email                          nullable.String    `gorm:"column:email;type:text;uniqueIndex:idx_business_email" json:"email,omitempty"`
+	PhoneNumber                    nullable.String    `gorm:"column:phone_number;type:text" json:"phoneNumber,omitempty"`
+	PhoneCode                      nullable.String    `gorm:"column:phone_code;type:text" json:"phoneCode,omitempty"`
+	TikTokUsername                 nullable.String    `gorm:"column:tiktok_username;type:text" json:"tiktokUsername,omitempty"`
+	InstagramUsername              nullable.String    `gorm:"column:instagram_username;type:text" json:"instagramUsername,omitempty"`
+	FacebookUsername               nullable.String    `gorm:"column:facebook_username;type:text" json:"facebookUsername,omitempty"`
+	XUsername                      nullable.String    `gorm:"column:x_username;type:text" json:"xUsername,omitempty"`
+	SnapchatUsername               nullable.String    `gorm:"column:snapchat_username;type:text" json:"snapchatUsername,omitempty"`
+	WhatsappNumber                 nullable.String    `gorm:"column:whatsapp_number;type:text" json:"whatsappNumber,omitempty"`
+	PriceListID                    nullable.String    `gorm:"column:price_list_id;type:text;index" json:"priceListId,omitempty"`
+	EmailMarketingConsent          bool               `gorm:"column:email_marketing_consent;not null;default:false" json:"emailMarketingConsent"`
+	EmailMarketingConsentAt        sql.NullTime       `gorm:"column:email_marketing_consent_at" json:"emailMarketingConsentAt"`
+	EmailMarketingConsentSource    ConsentSource      `gorm:"column:email_marketing_consent_source;type:text" json:"emailMarketingConsentSource,omitempty"`
+	WhatsappMarketingConsent       bool               `gorm:"column:whatsapp_marketing_consent;not null;default:false" json:"whatsappMarketingConsent"`
+	WhatsappMarketingConsentAt     sql.NullTime       `gorm:"column:whatsapp_marketing_consent_at" json:"whatsappMarketingConsentAt"`
+	WhatsappMarketingConsentSource ConsentSource      `gorm:"column:whatsapp_marketing_consent_source;type:text" json:"whatsappMarketingConsentSource,omitempty"`
+	SMSMarketingConsent            bool               `gorm:"column:sms_marketing_consent;not null;default:false" json:"smsMarketingConsent"`
+	SMSMarketingConsentAt          sql.NullTime       `gorm:"column:sms_marketing_consent_at" json:"smsMarketingConsentAt"`
+	SMSMarketingConsentSource      ConsentSource      `gorm:"column:sms_marketing_consent_source;type:text" json:"smsMarketingConsentSource,omitempty"`
+	JoinedAt                       time.Time          `gorm:"column:joined_at;type:timestamptz;not null;default:now()" json:"joinedAt"`
+	ErasedAt                       sql.NullTime       `gorm:"column:erased_at" json:"erasedAt"`
+	Addresses                      []*CustomerAddress `gorm:"foreignKey:CustomerID;references:ID" json:"addresses,omitempty"`
+	Notes                          []*CustomerNote    `gorm:"foreignKey:CustomerID;references:ID" json:"notes,omitempty"`
 }
 
 func (m *Customer) TableName() string {
@@ -62,43 +79,121 @@ func (m *Customer) BeforeCreate(tx *gorm.DB) (err error) {
 }
 
 var CustomerSchema = struct {
-	ID                schema.Field
-	BusinessID        schema.Field
-	Name              schema.Field
-	CountryCode       schema.Field
-	Gender            schema.Field
-	Email             schema.Field
-	PhoneNumber       schema.Field
-	PhoneCode         schema.Field
-	TikTokUsername    schema.Field
-	InstagramUsername schema.Field
-	FacebookUsername  schema.Field
-	XUsername         schema.Field
-	SnapchatUsername  schema.Field
-	WhatsappNumber    schema.Field
-	JoinedAt          schema.Field
-	CreatedAt         schema.Field
-	UpdatedAt         schema.Field
-	DeletedAt         schema.Field
+	ID                       schema.Field
+	BusinessID               schema.Field
+	Name                     schema.Field
+	CountryCode              schema.Field
+	Gender                   schema.Field
+	Email                    schema.Field
+	PhoneNumber              schema.Field
+	PhoneCode                schema.Field
+	TikTokUsername           schema.Field
+	InstagramUsername        schema.Field
+	FacebookUsername         schema.Field
+	XUsername                schema.Field
+	SnapchatUsername         schema.Field
+	WhatsappNumber           schema.Field
+	PriceListID              schema.Field
+	EmailMarketingConsent    schema.Field
+	WhatsappMarketingConsent schema.Field
+	SMSMarketingConsent      schema.Field
+	JoinedAt                 schema.Field
+	ErasedAt                 schema.Field
+	CreatedAt                schema.Field
+	UpdatedAt                schema.Field
+	DeletedAt                schema.Field
 }{
-	ID:                schema.NewField("id", "id"),
-	BusinessID:        schema.NewField("business_id", "businessId"),
-	Name:              schema.NewField("name", "name"),
-	CountryCode:       schema.NewField("country_code", "countryCode"),
-	Gender:            schema.NewField("gender", "gender"),
-	Email:             schema.NewField("email", "email"),
-	PhoneNumber:       schema.NewField("phone_number", "phoneNumber"),
-	PhoneCode:         schema.NewField("phone_code", "phoneCode"),
-	TikTokUsername:    schema.NewField("tiktok_username", "tiktokUsername"),
-	InstagramUsername: schema.NewField("instagram_username", "instagramUsername"),
-	FacebookUsername:  schema.NewField("facebook_username", "facebookUsername"),
-	XUsername:         schema.NewField("x_username", "xUsername"),
-	SnapchatUsername:  schema.NewField("snapchat_username", "snapchatUsername"),
-	WhatsappNumber:    schema.NewField("whatsapp_number", "whatsappNumber"),
-	JoinedAt:          schema.NewField("joined_at", "joinedAt"),
-	CreatedAt:         schema.NewField("created_at", "createdAt"),
-	UpdatedAt:         schema.NewField("updated_at", "updatedAt"),
-	DeletedAt:         schema.NewField("deleted_at", "deletedAt"),
+	ID:                       schema.NewField("id", "id"),
+	BusinessID:               schema.NewField("business_id", "businessId"),
+	Name:                     schema.NewField("name", "name"),
+	CountryCode:              schema.NewField("country_code", "countryCode"),
+	Gender:                   schema.NewField("gender", "gender"),
+	Email:                    schema.NewField("email", "email"),
+	PhoneNumber:              schema.NewField("phone_number", "phoneNumber"),
+	PhoneCode:                schema.NewField("phone_code", "phoneCode"),
+	TikTokUsername:           schema.NewField("tiktok_username", "tiktokUsername"),
+	InstagramUsername:        schema.NewField("instagram_username", "instagramUsername"),
+	FacebookUsername:         schema.NewField("facebook_username", "facebookUsername"),
+	XUsername:                schema.NewField("x_username", "xUsername"),
+	SnapchatUsername:         schema.NewField("snapchat_username", "snapchatUsername"),
+	WhatsappNumber:           schema.NewField("whatsapp_number", "whatsappNumber"),
+	PriceListID:              schema.NewField("price_list_id", "priceListId"),
+	EmailMarketingConsent:    schema.NewField("email_marketing_consent", "emailMarketingConsent"),
+	WhatsappMarketingConsent: schema.NewField("whatsapp_marketing_consent", "whatsappMarketingConsent"),
+	SMSMarketingConsent:      schema.NewField("sms_marketing_consent", "smsMarketingConsent"),
+	JoinedAt:                 schema.NewField("joined_at", "joinedAt"),
+	ErasedAt:                 schema.NewField("erased_at", "erasedAt"),
+	CreatedAt:                schema.NewField("created_at", "createdAt"),
+	UpdatedAt:                schema.NewField("updated_at", "updatedAt"),
+	DeletedAt:                schema.NewField("deleted_at", "deletedAt"),
+}
+
+// ConsentChannel is a channel customers can agree to receive marketing on.
+type ConsentChannel string
+
+const (
+	ConsentChannelEmail    ConsentChannel = "email"
+	ConsentChannelWhatsapp ConsentChannel = "whatsapp"
+	ConsentChannelSMS      ConsentChannel = "sms"
+)
+
+// ConsentSource is how a marketing consent was given or withdrawn.
+type ConsentSource string
+
+const (
+	ConsentSourceInPerson   ConsentSource = "in_person"
+	ConsentSourcePhone      ConsentSource = "phone"
+	ConsentSourceMessage    ConsentSource = "message"
+	ConsentSourceStorefront ConsentSource = "storefront"
+	ConsentSourceImport     ConsentSource = "import"
+	ConsentSourceErasure    ConsentSource = "erasure"
+	ConsentSourceOther      ConsentSource = "other"
+)
+
+// consentFields returns the consent flag, timestamp and source of a channel.
+func (m *Customer) consentFields(channel ConsentChannel) (*bool, *sql.NullTime, *ConsentSource) {
+	switch channel {
+	case ConsentChannelEmail:
+		return &m.EmailMarketingConsent, &m.EmailMarketingConsentAt, &m.EmailMarketingConsentSource
+	case ConsentChannelWhatsapp:
+		return &m.WhatsappMarketingConsent, &m.WhatsappMarketingConsentAt, &m.WhatsappMarketingConsentSource
+	case ConsentChannelSMS:
+		return &m.SMSMarketingConsent, &m.SMSMarketingConsentAt, &m.SMSMarketingConsentSource
+	}
+	return nil, nil, nil
+}
+
+// SetMarketingConsent records the customer's consent on a channel. Repeating the current
+// answer keeps the time and source it was first recorded with.
+func (m *Customer) SetMarketingConsent(channel ConsentChannel, granted bool, source ConsentSource, at time.Time) {
+	flag, recordedAt, recordedSource := m.consentFields(channel)
+	if flag == nil || (*flag == granted && recordedAt.Valid) {
+		return
+	}
+	if source == "" {
+		source = ConsentSourceOther
+	}
+	*flag = granted
+	*recordedAt = sql.NullTime{Time: at, Valid: true}
+	*recordedSource = source
+}
+
+// CanMarket reports whether marketing may be sent to the customer on a channel: they agreed
+// to it, were not erased, and have contact details for it. Every marketing send must check it;
+// transactional messages (order updates, digital deliveries) do not need consent.
+func (m *Customer) CanMarket(channel ConsentChannel) bool {
+	flag, _, _ := m.consentFields(channel)
+	if flag == nil || !*flag || m.ErasedAt.Valid {
+		return false
+	}
+	switch channel {
+	case ConsentChannelEmail:
+		return m.Email.Valid && m.Email.String != ""
+	case ConsentChannelWhatsapp:
+		return (m.WhatsappNumber.Valid && m.WhatsappNumber.String != "") || (m.PhoneNumber.Valid && m.PhoneNumber.String != "")
+	default:
+		return m.PhoneNumber.Valid && m.PhoneNumber.String != ""
+	}
 }
 
 // Request types moved to model_request.go
@@ -6,36 +6,63 @@ import (
 
 // CreateCustomerRequest is the request DTO for creating a customer.
 type CreateCustomerRequest struct {
-	Name              string         `json:"name" binding:"required"`
-	Gender            CustomerGender `json:"gender" binding:"omitempty,oneof=male female other"`
-	CountryCode       string         `json:"countryCode" binding:"required,len=2"`
-	Email             string         `json:"email" binding:"omitempty,email"`
-	PhoneNumber       string         `json:"phoneNumber" binding:"required"`
-	PhoneCode         string         `json:"phoneCode" binding:"required"`
-	TikTokUsername    string         `json:"tiktokUsername" binding:"omitempty"`
-	InstagramUsername string         `json:"instagramUsername" binding:"omitempty"`
-	FacebookUsername  string         `json:"facebookUsername" binding:"omitempty"`
-	XUsername         string         `json:"xUsername" binding:"omitempty"`
-	SnapchatUsername  string         `json:"snapchatUsername" binding:"omitempty"`
-	WhatsappNumber    string         `json:"whatsappNumber" binding:"omitempty"`
-	JoinedAt          time.Time      `json:"joinedAt" binding:"omitempty"`
+	Name              string                   `json:"name" binding:"required"`
+	Gender            CustomerGender           `json:"gender" binding:"omitempty,oneof=male female other"`
+	CountryCode       string                   `json:"countryCode" binding:"required,len=2"`
+	Email             string                   `json:"email" binding:"omitempty,email"`
+	PhoneNumber       string                   `json:"phoneNumber" binding:"required"`
+	PhoneCode         string                   `json:"phoneCode" binding:"required"`
+	TikTokUsername    string                   `json:"tiktokUsername" binding:"omitempty"`
+	InstagramUsername string                   `json:"instagramUsername" binding:"omitempty"`
+	FacebookUsername  string                   `json:"facebookUsername" binding:"omitempty"`
+	XUsername         string                   `json:"xUsername" binding:"omitempty"`
+	SnapchatUsername  string                   `json:"snapchatUsername" binding:"omitempty"`
+	WhatsappNumber    string                   `json:"whatsappNumber" binding:"omitempty"`
+	JoinedAt          time.Time                `json:"joinedAt" binding:"omitempty"`
+	PriceListID       string                   `json:"priceListId" binding:"omitempty"`
+	MarketingConsent  *MarketingConsentRequest `json:"marketingConsent" binding:"omitempty"`
+	// AllowDuplicate creates the customer even when it looks like an existing one.
+	AllowDuplicate bool `json:"allowDuplicate" binding:"omitempty"`
 }
 
 // UpdateCustomerRequest is the request DTO for updating a customer.
+// An empty priceListId removes the customer's price list assignment.
 type UpdateCustomerRequest struct {
-	Name              string         `json:"name" binding:"omitempty"`
-	Gender            CustomerGender `json:"gender" binding:"omitempty,oneof=male female other"`
-	CountryCode       string         `json:"countryCode" binding:"omitempty,len=2"`
-	Email             string         `json:"email" binding:"omitempty,email"`
-	PhoneNumber       string         `json:"phoneNumber" binding:"omitempty"`
-	PhoneCode         string         `json:"phoneCode" binding:"omitempty"`
-	TikTokUsername    string         `json:"tiktokUsername" binding:"omitempty"`
-	InstagramUsername string         `json:"instagramUsername" binding:"omitempty"`
-	FacebookUsername  string         `json:"facebookUsername" binding:"omitempty"`
-	XUsername         string         `json:"xUsername" binding:"omitempty"`
-	SnapchatUsername  string         `json:"snapchatUsername" binding:"omitempty"`
-	WhatsappNumber    string         `json:"whatsappNumber" binding:"omitempty"`
-	JoinedAt          time.Time      `json:"joinedAt" binding:"omitempty"`
+	Name              string                   `json:"name" binding:"omitempty"`
+	Gender            CustomerGender           `json:"gender" binding:"omitempty,oneof=male female other"`
+	CountryCode       string                   `json:"countryCode" binding:"omitempty,len=2"`
+	Email             string                   `json:"email" binding:"omitempty,email"`
+	PhoneNumber       string                   `json:"phoneNumber" binding:"omitempty"`
+	PhoneCode         string                   `json:"phoneCode" binding:"omitempty"`
+	TikTokUsername    string                   `json:"tiktokUsername" binding:"omitempty"`
+	InstagramUsername string                   `json:"instagramUsername" binding:"omitempty"`
+	FacebookUsername  string                   `json:"facebookUsername" binding:"omitempty"`
+	XUsername         string                   `json:"xUsername" binding:"omitempty"`
+	SnapchatUsername  string                   `json:"snapchatUsername" binding:"omitempty"`
+	WhatsappNumber    string                   `json:"whatsappNumber" binding:"omitempty"`
+	JoinedAt          time.Time                `json:"joinedAt" binding:"omitempty"`
+	PriceListID       *string                  `json:"priceListId" binding:"omitempty"`
+	MarketingConsent  *MarketingConsentRequest `json:"marketingConsent" binding:"omitempty"`
+}
+
+// MarketingConsentRequest gives or withdraws marketing consent per channel; omitted channels
+// are left as they are. Source says how the customer answered and defaults to "other".
+type MarketingConsentRequest struct {
+	Email    *bool         `json:"email"`
+	Whatsapp *bool         `json:"whatsapp"`
+	SMS      *bool         `json:"sms"`
+	Source   ConsentSource `json:"source" binding:"omitempty,oneof=in_person phone message storefront import other"`
+}
+
+// AssignPriceListRequest assigns a price list to a set of customers: the listed IDs, or every
+// customer matching the segment filters (same semantics as the list filters).
+type AssignPriceListRequest struct {
+	// PriceListID to assign; null or empty removes the assignment.
+	PriceListID     *string  `json:"priceListId"`
+	CustomerIDs     []string `json:"customerIds" binding:"omitempty,max=1000"`
+	CountryCode     string   `json:"countryCode" binding:"omitempty,len=2"`
+	HasOrders       *bool    `json:"hasOrders" binding:"omitempty"`
+	SocialPlatforms []string `json:"socialPlatforms" binding:"omitempty,dive,oneof=instagram tiktok facebook x snapchat whatsapp"`
 }
 
 // CreateCustomerAddressRequest is the request DTO for creating a customer address.
@@ -6,30 +6,37 @@ import (
 
 // CustomerResponse is the API response for Customer entity
 type CustomerResponse struct {
-	ID                string         `json:"id"`
-	BusinessID        string         `json:"businessId"`
-	Name              string         `json:"name"`
-	CountryCode       string         `json:"countryCode"`
-	Gender            CustomerGender `json:"gender"`
-	Email             string         `json:"email"`
-	PhoneNumber       string         `json:"phoneNumber,omitempty"`
-	PhoneCode         string         `json:"phoneCode,omitempty"`
-	TikTokUsername    string         `json:"tiktokUsername,omitempty"`
-	InstagramUsername string         `json:"instagramUsername,omitempty"`
-	FacebookUsername  string         `json:"facebookUsername,omitempty"`
-	XUsername         string         `json:"xUsername,omitempty"`
-	SnapchatUsername  string         `json:"snapchatUsername,omitempty"`
-	WhatsappNumber    string         `json:"whatsappNumber,omitempty"`
-	JoinedAt          time.Time      `json:"joinedAt"`
-	OrdersCount       int            `json:"ordersCount"`
-	TotalSpent        float64        `json:"totalSpent"`
-	AvatarUrl         *string        `json:"avatarUrl,omitempty"`
-	CreatedAt         time.Time      `json:"createdAt"`
-	UpdatedAt         time.Time      `json:"updatedAt"`
+	ID                string                    `json:"id"`
+	BusinessID        string                    `json:"businessId"`
+	Name              string                    `json:"name"`
+	CountryCode       string                    `json:"countryCode"`
+	Gender            CustomerGender            `json:"gender"`
+	Email             string                    `json:"email"`
+	PhoneNumber       string                    `json:"phoneNumber,omitempty"`
+	PhoneCode         string                    `json:"phoneCode,omitempty"`
+	TikTokUsername    string                    `json:"tiktokUsername,omitempty"`
+	InstagramUsername string                    `json:"instagramUsername,omitempty"`
+	FacebookUsername  string                    `json:"facebookUsername,omitempty"`
+	XUsername         string                    `json:"xUsername,omitempty"`
+	SnapchatUsername  string                    `json:"snapchatUsername,omitempty"`
+	WhatsappNumber    string                    `json:"whatsappNumber,omitempty"`
+	PriceListID       string                    `json:"priceListId,omitempty"`
+	JoinedAt          time.Time                 `json:"joinedAt"`
+	ErasedAt          *time.Time                `json:"erasedAt,omitempty"`
+	MarketingConsent  MarketingConsentsResponse `json:"marketingConsent"`
+	OrdersCount       int                       `json:"ordersCount"`
+	TotalSpent        float64                   `json:"totalSpent"`
+	AvatarUrl         *string                   `json:"avatarUrl,omitempty"`
+	CreatedAt         time.Time                 `json:"createdAt"`
+	UpdatedAt         time.Time                 `json:"updatedAt"`
 }
 
 // ToCustomerResponse converts Customer model to CustomerResponse
 func ToCustomerResponse(c *Customer, ordersCount int, totalSpent float64) CustomerResponse {
+	var erasedAt *time.Time
+	if c.ErasedAt.Valid {
+		erasedAt = &c.ErasedAt.Time
+	}
 	return CustomerResponse{
 		ID:                c.ID,
 		BusinessID:        c.BusinessID,
@@ -45,7 +52,10 @@ func ToCustomerResponse(c *Customer, ordersCount int, totalSpent float64) Custom
 		XUsername:         c.XUsername.String,
 		SnapchatUsername:  c.SnapchatUsername.String,
 		WhatsappNumber:    c.WhatsappNumber.String,
+		PriceListID:       c.PriceListID.String,
 		JoinedAt:          c.JoinedAt,
+		ErasedAt:          erasedAt,
+		MarketingConsent:  toMarketingConsentsResponse(c),
 		OrdersCount:       ordersCount,
 		TotalSpent:        totalSpent,
 		AvatarUrl:         nil,
@@ -54,6 +64,37 @@ func ToCustomerResponse(c *Customer, ordersCount int, totalSpent float64) Custom
 	}
 }
 
+// MarketingConsentResponse is the marketing consent of a customer on one channel. UpdatedAt
+// is null when the customer was never asked.
+type MarketingConsentResponse struct {
+	Granted   bool          `json:"granted"`
+	UpdatedAt *time.Time    `json:"updatedAt"`
+	Source    ConsentSource `json:"source,omitempty"`
+}
+
+// MarketingConsentsResponse is the marketing consent of a customer per channel.
+type MarketingConsentsResponse struct {
+	Email    MarketingConsentResponse `json:"email"`
+	Whatsapp MarketingConsentResponse `json:"whatsapp"`
+	SMS      MarketingConsentResponse `json:"sms"`
+}
+
+func toMarketingConsentsResponse(c *Customer) MarketingConsentsResponse {
+	channel := func(channel ConsentChannel) MarketingConsentResponse {
+		flag, at, source := c.consentFields(channel)
+		resp := MarketingConsentResponse{Granted: *flag, Source: *source}
+		if at.Valid {
+			resp.UpdatedAt = &at.Time
+		}
+		return resp
+	}
+	return MarketingConsentsResponse{
+		Email:    channel(ConsentChannelEmail),
+		Whatsapp: channel(ConsentChannelWhatsapp),
+		SMS:      channel(ConsentChannelSMS),
+	}
+}
+
 // ToCustomerResponses converts a slice of Customer models to responses
 func ToCustomerResponses(customers []*Customer, ordersCount []int, totalSpent []float64) []CustomerResponse {
 	responses := make([]CustomerResponse, len(customers))
@@ -140,3 +181,36 @@ func ToCustomerNoteResponses(notes []*CustomerNote) []CustomerNoteResponse {
 	}
 	return responses
 }
+
+// AssignPriceListResponse reports how many customers a price list assignment touched.
+type AssignPriceListResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+// PossibleDuplicateResponse is an existing customer listed by a customer.possible_duplicate conflict.
+type PossibleDuplicateResponse struct {
+	ID             string           `json:"id"`
+	Name           string           `json:"name"`
+	Email          string           `json:"email,omitempty"`
+	PhoneCode      string           `json:"phoneCode,omitempty"`
+	PhoneNumber    string           `json:"phoneNumber,omitempty"`
+	WhatsappNumber string           `json:"whatsappNumber,omitempty"`
+	MatchedOn      []DuplicateMatch `json:"matchedOn"`
+}
+
+// ToPossibleDuplicateResponses converts possible duplicates to their API responses
+func ToPossibleDuplicateResponses(duplicates []*PossibleDuplicate) []PossibleDuplicateResponse {
+	out := make([]PossibleDuplicateResponse, len(duplicates))
+	for i, d := range duplicates {
+		out[i] = PossibleDuplicateResponse{
+			ID:             d.Customer.ID,
+			Name:           d.Customer.Name,
+			Email:          d.Customer.Email.String,
+			PhoneCode:      d.Customer.PhoneCode.String,
+			PhoneNumber:    d.Customer.PhoneNumber.String,
+			WhatsappNumber: d.Customer.WhatsappNumber.String,
+			MatchedOn:      d.MatchedOn,
+		}
+	}
+	return out
+}
@@ -2,23 +2,29 @@ package customer
 
 import (
 	"context"
+	"database/sql"
 	"strings"
 	"time"
 
 	"github.com/abdelrahman146/kyora/internal/domain/account"
 	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
 	"github.com/abdelrahman146/kyora/internal/platform/bus"
+	"github.com/abdelrahman146/kyora/internal/platform/config"
 	"github.com/abdelrahman146/kyora/internal/platform/database"
 	"github.com/abdelrahman146/kyora/internal/platform/types/atomic"
 	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/abdelrahman146/kyora/internal/platform/types/nullable"
 	"github.com/abdelrahman146/kyora/internal/platform/types/timeseries"
 	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+	"github.com/spf13/viper"
 	"gorm.io/gorm"
 )
 
 type Service struct {
-	storage *Storage
-	bus     *bus.Bus
+	storage   *Storage
+	bus       *bus.Bus
+	inventory *inventory.Service
 }
 
 // UpsertCustomerByEmailInput is used by public storefront order submissions.
@@ -34,15 +40,32 @@ type UpsertCustomerByEmailInput struct {
 	XUsername         string
 	SnapchatUsername  string
 	WhatsappNumber    string
+	// EmailMarketingConsent is the checkout opt-in; leaving it unchecked keeps any earlier consent.
+	EmailMarketingConsent bool
 }
 
-func NewService(storage *Storage, atomicProcessor atomic.AtomicProcessor, bus *bus.Bus) *Service {
+func NewService(storage *Storage, atomicProcessor atomic.AtomicProcessor, bus *bus.Bus, inventory *inventory.Service) *Service {
 	return &Service{
-		bus:     bus,
-		storage: storage,
+		bus:       bus,
+		storage:   storage,
+		inventory: inventory,
 	}
 }
 
+// validatePriceList ensures priceListID, when set, is a price list of the business.
+func (s *Service) validatePriceList(ctx context.Context, actor *account.User, biz *business.Business, priceListID string) error {
+	if priceListID == "" {
+		return nil
+	}
+	if _, err := s.inventory.GetPriceListByID(ctx, actor, biz, priceListID); err != nil {
+		if database.IsRecordNotFound(err) {
+			return inventory.ErrPriceListNotFound(err).With("priceListId", priceListID)
+		}
+		return err
+	}
+	return nil
+}
+
 func (s *Service) GetCustomerByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Customer, error) {
 	return s.storage.customer.FindOne(ctx,
 		s.storage.customer.ScopeBusinessID(biz.ID),
@@ -51,6 +74,38 @@ func (s *Service) GetCustomerByID(ctx context.Context, actor *account.User, biz
 	)
 }
 
+// GetCustomerByEmail returns the customer of the business with the given email, which is
+// unique per business. Imports use it to link to customers the seller already has.
+func (s *Service) GetCustomerByEmail(ctx context.Context, actor *account.User, biz *business.Business, email string) (*Customer, error) {
+	return s.storage.customer.FindOne(ctx,
+		s.storage.customer.ScopeBusinessID(biz.ID),
+		s.storage.customer.ScopeEquals(CustomerSchema.Email, strings.TrimSpace(email)),
+	)
+}
+
+// FindCustomerByContact returns the customer of the business reached at the email or phone
+// number, for customers signing in to the storefront. Phone numbers match by their digits
+// against the phone and the WhatsApp number. Erased customers are never returned; when
+// several customers share the contact, the most recently updated one wins.
+func (s *Service) FindCustomerByContact(ctx context.Context, biz *business.Business, email, phone string) (*Customer, error) {
+	return s.storage.customer.FindOne(ctx,
+		s.storage.customer.ScopeBusinessID(biz.ID),
+		s.storage.customer.ScopeIsNull(CustomerSchema.ErasedAt),
+		s.storage.ScopeContact(strings.TrimSpace(email), PhoneDigits(phone)),
+		s.storage.customer.WithOrderBy([]string{"updated_at DESC"}),
+	)
+}
+
+// PhoneDigits strips everything but the digits from a phone number.
+func PhoneDigits(phone string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+}
+
 // GetCustomerAddressByID returns a customer address by ID after enforcing:
 // - customer exists in this business
 // - address belongs to that customer
@@ -76,6 +131,19 @@ func (s *Service) CreateCustomer(ctx context.Context, actor *account.User, biz *
 	if countryCode == "" {
 		countryCode = req.CountryCode
 	}
+	priceListID := strings.TrimSpace(req.PriceListID)
+	if err := s.validatePriceList(ctx, actor, biz, priceListID); err != nil {
+		return nil, err
+	}
+	if !req.AllowDuplicate {
+		duplicates, err := s.FindPossibleDuplicates(ctx, biz, req)
+		if err != nil {
+			return nil, err
+		}
+		if len(duplicates) > 0 {
+			return nil, ErrCustomerPossibleDuplicates(duplicates)
+		}
+	}
 	customer := &Customer{
 		BusinessID:        biz.ID,
 		Name:              req.Name,
@@ -90,7 +158,9 @@ func (s *Service) CreateCustomer(ctx context.Context, actor *account.User, biz *
 		XUsername:         transformer.ToNullableString(req.XUsername),
 		SnapchatUsername:  transformer.ToNullableString(req.SnapchatUsername),
 		WhatsappNumber:    transformer.ToNullableString(req.WhatsappNumber),
+		PriceListID:       transformer.ToNullableString(priceListID),
 	}
+	applyMarketingConsent(customer, req.MarketingConsent, time.Now().UTC())
 	err := s.storage.customer.CreateOne(ctx, customer)
 	if err != nil {
 		return nil, err
@@ -98,6 +168,78 @@ func (s *Service) CreateCustomer(ctx context.Context, actor *account.User, biz *
 	return customer, nil
 }
 
+// DuplicateMatch is what a possible duplicate customer shares with a new customer.
+type DuplicateMatch string
+
+const (
+	DuplicateMatchEmail DuplicateMatch = "email"
+	DuplicateMatchPhone DuplicateMatch = "phone"
+	DuplicateMatchName  DuplicateMatch = "name"
+)
+
+const (
+	// maxPossibleDuplicates caps how many existing customers a create conflict lists.
+	maxPossibleDuplicates = 5
+	// minDuplicatePhoneDigits keeps partial numbers from matching unrelated customers.
+	minDuplicatePhoneDigits = 6
+)
+
+// PossibleDuplicate is an existing customer that probably is the customer being created.
+type PossibleDuplicate struct {
+	Customer  *Customer
+	MatchedOn []DuplicateMatch
+}
+
+// FindPossibleDuplicates returns the existing customers of the business that share the email or
+// a phone number of req, or whose name is close to it (see config.CustomerDuplicateNameSimilarity).
+func (s *Service) FindPossibleDuplicates(ctx context.Context, biz *business.Business, req *CreateCustomerRequest) ([]*PossibleDuplicate, error) {
+	var phones []string
+	for _, phone := range []string{req.PhoneCode + req.PhoneNumber, req.WhatsappNumber} {
+		if digits := PhoneDigits(phone); len(digits) >= minDuplicatePhoneDigits {
+			phones = append(phones, digits)
+		}
+	}
+	minSimilarity := viper.GetFloat64(config.CustomerDuplicateNameSimilarity)
+	candidates, err := s.storage.FindDuplicateCandidates(ctx, biz.ID, strings.TrimSpace(req.Name), req.Email, phones, minSimilarity, maxPossibleDuplicates)
+	if err != nil || len(candidates) == 0 {
+		return nil, err
+	}
+	ids := make([]any, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.CustomerID
+	}
+	customers, err := s.storage.customer.FindMany(ctx,
+		s.storage.customer.ScopeBusinessID(biz.ID),
+		s.storage.customer.ScopeIDs(ids),
+	)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*Customer, len(customers))
+	for _, c := range customers {
+		byID[c.ID] = c
+	}
+	out := make([]*PossibleDuplicate, 0, len(candidates))
+	for _, c := range candidates {
+		cust, ok := byID[c.CustomerID]
+		if !ok {
+			continue
+		}
+		d := &PossibleDuplicate{Customer: cust}
+		if c.EmailMatch {
+			d.MatchedOn = append(d.MatchedOn, DuplicateMatchEmail)
+		}
+		if c.PhoneMatch {
+			d.MatchedOn = append(d.MatchedOn, DuplicateMatchPhone)
+		}
+		if c.NameSimilarity >= minSimilarity {
+			d.MatchedOn = append(d.MatchedOn, DuplicateMatchName)
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
 // UpsertCustomerByEmail creates or updates a customer using (businessId, email) as a natural key.
 // It is designed for unauthenticated storefront flows.
 func (s *Service) UpsertCustomerByEmail(ctx context.Context, biz *business.Business, in *UpsertCustomerByEmailInput) (*Customer, error) {
@@ -138,6 +280,9 @@ func (s *Service) UpsertCustomerByEmail(ctx context.Context, biz *business.Busin
 				WhatsappNumber:    transformer.ToNullableString(strings.TrimSpace(in.WhatsappNumber)),
 				JoinedAt:          time.Now().UTC(),
 			}
+			if in.EmailMarketingConsent {
+				cust.SetMarketingConsent(ConsentChannelEmail, true, ConsentSourceStorefront, cust.JoinedAt)
+			}
 			if err := s.storage.customer.CreateOne(ctx, cust); err != nil {
 				return nil, err
 			}
@@ -172,6 +317,9 @@ func (s *Service) UpsertCustomerByEmail(ctx context.Context, biz *business.Busin
 	if strings.TrimSpace(in.WhatsappNumber) != "" {
 		existing.WhatsappNumber = transformer.ToNullableString(strings.TrimSpace(in.WhatsappNumber))
 	}
+	if in.EmailMarketingConsent {
+		existing.SetMarketingConsent(ConsentChannelEmail, true, ConsentSourceStorefront, time.Now().UTC())
+	}
 
 	if err := s.storage.customer.UpdateOne(ctx, existing); err != nil {
 		return nil, err
@@ -220,6 +368,14 @@ func (s *Service) UpdateCustomer(ctx context.Context, actor *account.User, biz *
 	if req.WhatsappNumber != "" {
 		customer.WhatsappNumber = transformer.ToNullableString(req.WhatsappNumber)
 	}
+	if req.PriceListID != nil {
+		priceListID := strings.TrimSpace(*req.PriceListID)
+		if err := s.validatePriceList(ctx, actor, biz, priceListID); err != nil {
+			return nil, err
+		}
+		customer.PriceListID = transformer.ToNullableString(priceListID)
+	}
+	applyMarketingConsent(customer, req.MarketingConsent, time.Now().UTC())
 	err = s.storage.customer.UpdateOne(ctx, customer)
 	if err != nil {
 		return nil, err
@@ -227,6 +383,22 @@ func (s *Service) UpdateCustomer(ctx context.Context, actor *account.User, biz *
 	return customer, nil
 }
 
+// applyMarketingConsent records the consent answers of a create or update request.
+func applyMarketingConsent(c *Customer, req *MarketingConsentRequest, at time.Time) {
+	if req == nil {
+		return
+	}
+	for channel, granted := range map[ConsentChannel]*bool{
+		ConsentChannelEmail:    req.Email,
+		ConsentChannelWhatsapp: req.Whatsapp,
+		ConsentChannelSMS:      req.SMS,
+	} {
+		if granted != nil {
+			c.SetMarketingConsent(channel, *granted, req.Source, at)
+		}
+	}
+}
+
 func (s *Service) DeleteCustomer(ctx context.Context, actor *account.User, biz *business.Business, id string) error {
 	customer, err := s.GetCustomerByID(ctx, actor, biz, id)
 	if err != nil {
@@ -235,10 +407,81 @@ func (s *Service) DeleteCustomer(ctx context.Context, actor *account.User, biz *
 	return s.storage.customer.DeleteOne(ctx, customer)
 }
 
+// ErasedCustomerName replaces the name of a customer whose personal data was erased.
+const ErasedCustomerName = "Erased customer"
+
+// EraseCustomer anonymizes the personal data of a customer on their request: contact details
+// and social handles are cleared, the name is replaced, addresses lose their street, zip code
+// and phone number, notes are deleted for good and marketing consent is withdrawn. The
+// customer record stays, so the orders and totals tied to it are kept. Call it inside a
+// transaction with the erasure of the data other domains hold.
+func (s *Service) EraseCustomer(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Customer, error) {
+	customer, err := s.GetCustomerByID(ctx, actor, biz, id)
+	if err != nil {
+		return nil, err
+	}
+	customer.Name = ErasedCustomerName
+	customer.Gender = ""
+	customer.Email = nullable.String{}
+	customer.PhoneNumber = nullable.String{}
+	customer.PhoneCode = nullable.String{}
+	customer.TikTokUsername = nullable.String{}
+	customer.InstagramUsername = nullable.String{}
+	customer.FacebookUsername = nullable.String{}
+	customer.XUsername = nullable.String{}
+	customer.SnapchatUsername = nullable.String{}
+	customer.WhatsappNumber = nullable.String{}
+	if !customer.ErasedAt.Valid {
+		customer.ErasedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	}
+	for _, channel := range []ConsentChannel{ConsentChannelEmail, ConsentChannelWhatsapp, ConsentChannelSMS} {
+		customer.SetMarketingConsent(channel, false, ConsentSourceErasure, customer.ErasedAt.Time)
+	}
+	if err := s.storage.customer.UpdateOne(ctx, customer); err != nil {
+		return nil, err
+	}
+	if err := s.storage.AnonymizeAddresses(ctx, customer.ID); err != nil {
+		return nil, err
+	}
+	if err := s.storage.PurgeNotes(ctx, customer.ID); err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
 type ListCustomersFilters struct {
 	CountryCode     string
 	HasOrders       *bool
 	SocialPlatforms []string
+	PriceListID     string
+	// MarketingConsent keeps customers who can be sent marketing on the channel (see CanMarket).
+	MarketingConsent ConsentChannel
+}
+
+// segmentScopes turns the filters into WHERE scopes on customers.
+func (s *Service) segmentScopes(filters *ListCustomersFilters) []func(*gorm.DB) *gorm.DB {
+	var scopes []func(*gorm.DB) *gorm.DB
+	if filters == nil {
+		return scopes
+	}
+	if filters.CountryCode != "" {
+		scopes = append(scopes,
+			s.storage.customer.ScopeEquals(CustomerSchema.CountryCode, strings.ToUpper(filters.CountryCode)),
+		)
+	}
+	if filters.HasOrders != nil {
+		scopes = append(scopes, s.storage.ScopeHasOrders(*filters.HasOrders))
+	}
+	if len(filters.SocialPlatforms) > 0 {
+		scopes = append(scopes, s.storage.ScopeSocialPlatforms(filters.SocialPlatforms))
+	}
+	if filters.PriceListID != "" {
+		scopes = append(scopes, s.storage.customer.ScopeEquals(CustomerSchema.PriceListID, filters.PriceListID))
+	}
+	if filters.MarketingConsent != "" {
+		scopes = append(scopes, s.storage.ScopeMarketingConsent(filters.MarketingConsent))
+	}
+	return scopes
 }
 
 func (s *Service) ListCustomers(ctx context.Context, actor *account.User, biz *business.Business, req *list.ListRequest, filters *ListCustomersFilters) ([]CustomerResponse, int64, error) {
@@ -247,33 +490,25 @@ func (s *Service) ListCustomers(ctx context.Context, actor *account.User, biz *b
 	}
 
 	// Apply filters
-	if filters != nil {
-		if filters.CountryCode != "" {
-			scopes = append(scopes,
-				s.storage.customer.ScopeEquals(CustomerSchema.CountryCode, strings.ToUpper(filters.CountryCode)),
-			)
-		}
-		if filters.HasOrders != nil {
-			scopes = append(scopes, s.storage.ScopeHasOrders(*filters.HasOrders))
-		}
-		if len(filters.SocialPlatforms) > 0 {
-			scopes = append(scopes, s.storage.ScopeSocialPlatforms(filters.SocialPlatforms))
-		}
-	}
+	scopes = append(scopes, s.segmentScopes(filters)...)
 
 	// Apply search if provided
 	var listOpts []func(*gorm.DB) *gorm.DB
 	if req.SearchTerm() != "" {
 		term := req.SearchTerm()
 		like := "%" + term + "%"
+		fuzzy, fuzzyVars, err := database.FuzzyMatch(term, "customers.name")
+		if err != nil {
+			return nil, 0, ErrCustomerQueryFailed(err)
+		}
 		scopes = append(scopes,
 			s.storage.customer.ScopeWhere(
-				"(customers.search_vector @@ websearch_to_tsquery('simple', ?) OR customers.name ILIKE ? OR customers.email ILIKE ?)",
-				term, like, like,
+				"(customers.search_vector @@ websearch_to_tsquery('simple', ?) OR customers.name ILIKE ? OR customers.email ILIKE ? OR "+fuzzy+")",
+				append([]any{term, like, like}, fuzzyVars...)...,
 			),
 		)
 		if !req.HasExplicitOrderBy() {
-			rankExpr, err := database.WebSearchRankOrder(term, "customers.search_vector")
+			rankExpr, err := database.SearchRankOrder(term, []string{"customers.search_vector"}, []string{"customers.name"})
 			if err != nil {
 				return nil, 0, ErrCustomerQueryFailed(err)
 			}
@@ -561,8 +796,33 @@ func (s *Service) CountCustomerAddresses(ctx context.Context, actor *account.Use
 
 func (s *Service) ComputeCustomersTimeSeries(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) (*timeseries.TimeSeries, error) {
 	granularity := timeseries.GetTimeGranularityByDateRange(from, to)
-	return s.storage.customer.TimeSeriesCount(ctx, CustomerSchema.JoinedAt, granularity,
+	return s.storage.customer.TimeSeriesCount(ctx, CustomerSchema.JoinedAt, granularity, biz.ReportingCalendar(),
 		s.storage.customer.ScopeBusinessID(biz.ID),
 		s.storage.customer.ScopeTime(CustomerSchema.JoinedAt, from, to),
 	)
 }
+
+// AssignPriceList sets (or, with an empty priceListID, clears) the price list of the given
+// customers, or of every customer matching filters when no IDs are given. It returns the
+// number of customers updated.
+func (s *Service) AssignPriceList(ctx context.Context, actor *account.User, biz *business.Business, priceListID string, customerIDs []string, filters *ListCustomersFilters) (int64, error) {
+	priceListID = strings.TrimSpace(priceListID)
+	if err := s.validatePriceList(ctx, actor, biz, priceListID); err != nil {
+		return 0, err
+	}
+	scopes := []func(*gorm.DB) *gorm.DB{s.storage.customer.ScopeBusinessID(biz.ID)}
+	if len(customerIDs) > 0 {
+		ids := make([]any, len(customerIDs))
+		for i, id := range customerIDs {
+			ids[i] = id
+		}
+		scopes = append(scopes, s.storage.customer.ScopeIDs(ids))
+	} else {
+		segment := s.segmentScopes(filters)
+		if len(segment) == 0 {
+			return 0, ErrCustomerInvalidData("customerIds or at least one segment filter is required")
+		}
+		scopes = append(scopes, segment...)
+	}
+	return s.storage.AssignPriceList(ctx, transformer.ToNullableString(priceListID), scopes...)
+}
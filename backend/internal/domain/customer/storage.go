@@ -6,7 +6,9 @@ import (
 
 	"github.com/abdelrahman146/kyora/internal/platform/cache"
 	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/nullable"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Storage struct {
@@ -26,6 +28,7 @@ func NewStorage(db *database.Database, cache *cache.Cache) *Storage {
 		customerAddress: database.NewRepository[CustomerAddress](db),
 	}
 	ensureCustomerSearchIndexes(db)
+	database.EnsureIndexes(db, Indexes...)
 	return st
 }
 
@@ -71,23 +74,31 @@ func (s *Storage) GetCustomerAggregations(ctx context.Context, businessID string
 }
 
 func ensureCustomerSearchIndexes(db *database.Database) {
-	conn := db.GetDB()
+	// Each region has its own database with the same schema.
+	for _, conn := range db.Conns() {
+		// Weighted full-text document (most relevant fields first).
+		expr := "" +
+			"setweight(to_tsvector('simple', coalesce(name,'')), 'A') || " +
+			"setweight(to_tsvector('simple', coalesce(email,'')), 'A') || " +
+			"setweight(to_tsvector('simple', coalesce(phone_number,'')), 'B') || " +
+			"setweight(to_tsvector('simple', coalesce(whatsapp_number,'')), 'B') || " +
+			"setweight(to_tsvector('simple', coalesce(instagram_username,'')), 'C') || " +
+			"setweight(to_tsvector('simple', coalesce(tiktok_username,'')), 'C')"
 
-	// Weighted full-text document (most relevant fields first).
-	expr := "" +
-		"setweight(to_tsvector('simple', coalesce(name,'')), 'A') || " +
-		"setweight(to_tsvector('simple', coalesce(email,'')), 'A') || " +
-		"setweight(to_tsvector('simple', coalesce(phone_number,'')), 'B') || " +
-		"setweight(to_tsvector('simple', coalesce(whatsapp_number,'')), 'B') || " +
-		"setweight(to_tsvector('simple', coalesce(instagram_username,'')), 'C') || " +
-		"setweight(to_tsvector('simple', coalesce(tiktok_username,'')), 'C')"
+		database.EnsureGeneratedTSVectorColumn(conn, CustomerTable, "search_vector", expr)
 
-	database.EnsureGeneratedTSVectorColumn(conn, CustomerTable, "search_vector", expr)
-	database.EnsureGinIndex(conn, "customers_search_vector_gin_idx", CustomerTable, "search_vector")
+		// Trigram indexes speed up substring matches (ILIKE) for user-friendly partial search.
+		database.EnsureTrigramGinIndex(conn, "customers_name_trgm_idx", CustomerTable, "name")
+		database.EnsureTrigramGinIndex(conn, "customers_email_trgm_idx", CustomerTable, "email")
+		// Normalized trigram index backs the typo/accent tolerant name matching.
+		database.EnsureNormalizedTrigramIndex(conn, "customers_name_norm_trgm_idx", CustomerTable, "name")
+	}
+}
 
-	// Trigram indexes speed up substring matches (ILIKE) for user-friendly partial search.
-	database.EnsureTrigramGinIndex(conn, "customers_name_trgm_idx", CustomerTable, "name")
-	database.EnsureTrigramGinIndex(conn, "customers_email_trgm_idx", CustomerTable, "email")
+// Indexes are the secondary indexes the customer queries rely on.
+// The search_vector column is created by ensureCustomerSearchIndexes before these are ensured.
+var Indexes = []database.Index{
+	{Name: "customers_search_vector_gin_idx", Table: CustomerTable, Columns: []string{"search_vector"}, Using: "gin"},
 }
 
 // ScopeHasOrders filters customers by whether they have any non-deleted orders.
@@ -133,6 +144,81 @@ func (s *Storage) ScopeSocialPlatforms(platforms []string) func(*gorm.DB) *gorm.
 	}
 }
 
+// ScopeMarketingConsent keeps customers who can be sent marketing on the channel: they agreed
+// to it, were not erased, and have contact details for it. It matches Customer.CanMarket.
+func (s *Storage) ScopeMarketingConsent(channel ConsentChannel) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("customers.erased_at IS NULL")
+		switch channel {
+		case ConsentChannelEmail:
+			return db.Where("customers.email_marketing_consent AND customers.email IS NOT NULL AND customers.email != ''")
+		case ConsentChannelWhatsapp:
+			return db.Where("customers.whatsapp_marketing_consent AND ((customers.whatsapp_number IS NOT NULL AND customers.whatsapp_number != '') OR (customers.phone_number IS NOT NULL AND customers.phone_number != ''))")
+		case ConsentChannelSMS:
+			return db.Where("customers.sms_marketing_consent AND customers.phone_number IS NOT NULL AND customers.phone_number != ''")
+		}
+		return db.Where("FALSE")
+	}
+}
+
+// ScopeContact keeps customers reached at the email (case-insensitively) or at the phone
+// number, compared by digits against both the phone (calling code and number) and the
+// WhatsApp number. Only one of email and phone is expected to be set.
+func (s *Storage) ScopeContact(email, phoneDigits string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if email != "" {
+			return db.Where("LOWER(customers.email) = ?", strings.ToLower(email))
+		}
+		if phoneDigits != "" {
+			return db.Where("(regexp_replace(COALESCE(customers.phone_code, '') || COALESCE(customers.phone_number, ''), '\\D', '', 'g') = ? OR regexp_replace(COALESCE(customers.whatsapp_number, ''), '\\D', '', 'g') = ?)", phoneDigits, phoneDigits)
+		}
+		return db.Where("FALSE")
+	}
+}
+
+// DuplicateCandidate is an existing customer that looks like a customer about to be created,
+// with what matched.
+type DuplicateCandidate struct {
+	CustomerID     string  `gorm:"column:id"`
+	EmailMatch     bool    `gorm:"column:email_match"`
+	PhoneMatch     bool    `gorm:"column:phone_match"`
+	NameSimilarity float64 `gorm:"column:name_similarity"`
+}
+
+// FindDuplicateCandidates returns the customers of a business (erased ones excepted) that share
+// the email (case-insensitively) or one of the phone numbers (by digits, against the phone and
+// the WhatsApp number), or whose name is at least minNameSimilarity similar to name. Email and
+// phone matches come first, then the closest names.
+func (s *Storage) FindDuplicateCandidates(ctx context.Context, businessID, name, email string, phoneDigits []string, minNameSimilarity float64, limit int) ([]*DuplicateCandidate, error) {
+	nameSim, err := database.NormalizedSimilarity(name, "customers.name")
+	if err != nil {
+		return nil, err
+	}
+	emailMatch := clauseExpr("FALSE")
+	if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+		emailMatch = clauseExpr("LOWER(customers.email) = ?", email)
+	}
+	phoneMatch := clauseExpr("FALSE")
+	if len(phoneDigits) > 0 {
+		phoneMatch = clauseExpr("(regexp_replace(COALESCE(customers.phone_code, '') || COALESCE(customers.phone_number, ''), '\\D', '', 'g') IN ? OR regexp_replace(COALESCE(customers.whatsapp_number, ''), '\\D', '', 'g') IN ?)", phoneDigits, phoneDigits)
+	}
+
+	var rows []*DuplicateCandidate
+	err = s.db.Conn(ctx).
+		Table("(?) AS candidates", s.db.Conn(ctx).Model(&Customer{}).
+			Select("customers.id, customers.created_at, ? AS email_match, ? AS phone_match, ? AS name_similarity", emailMatch, phoneMatch, nameSim).
+			Where("customers.business_id = ? AND customers.erased_at IS NULL", businessID)).
+		Where("email_match OR phone_match OR name_similarity >= ?", minNameSimilarity).
+		Order("email_match DESC, phone_match DESC, name_similarity DESC, created_at DESC").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+func clauseExpr(sql string, vars ...any) clause.Expr {
+	return clause.Expr{SQL: sql, Vars: vars}
+}
+
 // WithCustomerAggregation adds a LATERAL join to compute orders count and total spent per customer.
 // This is used when sorting by ordersCount or totalSpent.
 func (s *Storage) WithCustomerAggregation() func(*gorm.DB) *gorm.DB {
@@ -148,3 +234,36 @@ func (s *Storage) WithCustomerAggregation() func(*gorm.DB) *gorm.DB {
 		) AS customer_agg ON true
 	`)
 }
+
+// AssignPriceList sets price_list_id on every customer matched by scopes and returns the
+// number of rows updated.
+func (s *Storage) AssignPriceList(ctx context.Context, priceListID nullable.String, scopes ...func(*gorm.DB) *gorm.DB) (int64, error) {
+	res := s.db.Conn(ctx).
+		Model(&Customer{}).
+		Scopes(scopes...).
+		Update(CustomerSchema.PriceListID.Column(), priceListID)
+	return res.RowsAffected, res.Error
+}
+
+// AnonymizeAddresses clears the street, zip code and phone number of every address of a
+// customer, including deleted ones orders still point to. Country, state and city are kept
+// for regional reports.
+func (s *Storage) AnonymizeAddresses(ctx context.Context, customerID string) error {
+	return s.db.Conn(ctx).
+		Unscoped().
+		Model(&CustomerAddress{}).
+		Where(CustomerAddressSchema.CustomerID.Column()+" = ?", customerID).
+		Updates(map[string]any{
+			CustomerAddressSchema.Street.Column():      nil,
+			CustomerAddressSchema.ZipCode.Column():     nil,
+			CustomerAddressSchema.PhoneNumber.Column(): "",
+		}).Error
+}
+
+// PurgeNotes permanently deletes the notes of a customer, including deleted ones.
+func (s *Storage) PurgeNotes(ctx context.Context, customerID string) error {
+	return s.db.Conn(ctx).
+		Unscoped().
+		Where(CustomerNoteSchema.CustomerID.Column()+" = ?", customerID).
+		Delete(&CustomerNote{}).Error
+}
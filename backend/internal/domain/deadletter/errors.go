@@ -0,0 +1,36 @@
+package deadletter
+
+import "github.com/abdelrahman146/kyora/internal/platform/types/problem"
+
+func ErrDeadLetterNotFound(id string, err error) error {
+	return problem.NotFound("dead letter not found").
+		With("deadLetterId", id).
+		WithError(err).
+		WithCode("deadletter.not_found")
+}
+
+func ErrDeadLetterAlreadyReplayed(id string) error {
+	return problem.Conflict("dead letter was already replayed").
+		With("deadLetterId", id).
+		WithCode("deadletter.already_replayed")
+}
+
+func ErrDeadLetterReplayFailed(id string, err error) error {
+	return problem.UnprocessableEntity("replaying the dead letter failed").
+		With("deadLetterId", id).
+		With("error", err.Error()).
+		WithError(err).
+		WithCode("deadletter.replay_failed")
+}
+
+func ErrDeadLetterInvalidQuery(err error) error {
+	return problem.BadRequest("invalid query parameters").
+		WithError(err).
+		WithCode("deadletter.invalid_query")
+}
+
+func ErrDeadLetterQueryFailed(err error) error {
+	return problem.InternalError().
+		WithError(err).
+		WithCode("deadletter.query_failed")
+}
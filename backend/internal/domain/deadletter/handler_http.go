@@ -0,0 +1,114 @@
+package deadletter
+
+import (
+	"net/http"
+
+	"github.com/abdelrahman146/kyora/internal/platform/response"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/gin-gonic/gin"
+)
+
+// HttpHandler serves the operator endpoints for inspecting and replaying dead letters.
+type HttpHandler struct {
+	service *Service
+}
+
+// NewHttpHandler creates a new dead letter HTTP handler.
+func NewHttpHandler(service *Service) *HttpHandler {
+	return &HttpHandler{service: service}
+}
+
+type listDeadLettersQuery struct {
+	Page     int      `form:"page" binding:"omitempty,min=1"`
+	PageSize int      `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	OrderBy  []string `form:"orderBy" binding:"omitempty"`
+	Status   Status   `form:"status" binding:"omitempty,oneof=pending replayed"`
+	Topic    string   `form:"topic" binding:"omitempty"`
+}
+
+// ListDeadLetters returns a paginated list of dead-lettered bus events
+//
+// @Summary      List dead letters
+// @Description  Returns bus events that handlers failed to process, newest first
+// @Tags         admin
+// @Produce      json
+// @Param        page query int false "Page number"
+// @Param        pageSize query int false "Page size (max 100)"
+// @Param        orderBy query []string false "Order by fields (e.g. -failedAt)"
+// @Param        status query string false "pending or replayed"
+// @Param        topic query string false "Bus topic"
+// @Success      200 {object} list.ListResponse[deadletter.DeadLetterResponse]
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/admin/dead-letters [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListDeadLetters(c *gin.Context) {
+	var query listDeadLettersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, ErrDeadLetterInvalidQuery(err))
+		return
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+
+	listReq := list.NewListRequest(query.Page, query.PageSize, query.OrderBy, "")
+	items, totalCount, err := h.service.ListDeadLetters(c.Request.Context(), listReq, &ListFilters{Status: query.Status, Topic: query.Topic})
+	if err != nil {
+		response.Error(c, ErrDeadLetterQueryFailed(err))
+		return
+	}
+
+	hasMore := int64(query.Page*query.PageSize) < totalCount
+	response.SuccessJSON(c, http.StatusOK, list.NewListResponse(ToDeadLetterResponses(items), query.Page, query.PageSize, totalCount, hasMore))
+}
+
+// GetDeadLetter returns a dead letter by ID
+//
+// @Summary      Get dead letter
+// @Description  Returns one dead-lettered bus event with its payload and failure details
+// @Tags         admin
+// @Produce      json
+// @Param        deadLetterId path string true "Dead letter ID"
+// @Success      200 {object} deadletter.DeadLetterResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/admin/dead-letters/{deadLetterId} [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetDeadLetter(c *gin.Context) {
+	dl, err := h.service.GetDeadLetter(c.Request.Context(), c.Param("deadLetterId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToDeadLetterResponse(dl))
+}
+
+// ReplayDeadLetter re-runs a dead letter through its handler
+//
+// @Summary      Replay dead letter
+// @Description  Runs the stored payload through its handler again and marks it replayed on success
+// @Tags         admin
+// @Produce      json
+// @Param        deadLetterId path string true "Dead letter ID"
+// @Success      200 {object} deadletter.DeadLetterResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      422 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/admin/dead-letters/{deadLetterId}/replay [post]
+// @Security     BearerAuth
+func (h *HttpHandler) ReplayDeadLetter(c *gin.Context) {
+	dl, err := h.service.ReplayDeadLetter(c.Request.Context(), c.Param("deadLetterId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToDeadLetterResponse(dl))
+}
@@ -0,0 +1,124 @@
+package deadletter
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"gorm.io/gorm"
+)
+
+const (
+	DeadLetterTable  = "bus_dead_letters"
+	DeadLetterStruct = "DeadLetter"
+	DeadLetterPrefix = "dlq"
+)
+
+// Status tracks whether a dead letter still needs attention.
+type Status string
+
+const (
+	// StatusPending dead letters have not been replayed successfully yet.
+	StatusPending Status = "pending"
+	// StatusReplayed dead letters were handled successfully on replay.
+	StatusReplayed Status = "replayed"
+)
+
+// DeadLetter is a bus event a named handler failed to process, stored with its JSON
+// payload so it can be replayed once the cause is fixed.
+type DeadLetter struct {
+	gorm.Model
+	ID              string          `gorm:"column:id;primaryKey;type:text" json:"id"`
+	Topic           string          `gorm:"column:topic;type:text;not null;index" json:"topic"`
+	Handler         string          `gorm:"column:handler;type:text;not null" json:"handler"`
+	BusinessID      string          `gorm:"column:business_id;type:text;index" json:"businessId,omitempty"`
+	Region          string          `gorm:"column:region;type:text" json:"region,omitempty"`
+	Payload         json.RawMessage `gorm:"column:payload;type:jsonb;not null;default:'{}'" json:"payload"`
+	Error           string          `gorm:"column:error;type:text;not null" json:"error"`
+	Attempts        int             `gorm:"column:attempts;type:int;not null;default:0" json:"attempts"`
+	Status          Status          `gorm:"column:status;type:text;not null;default:'pending';index" json:"status"`
+	FailedAt        time.Time       `gorm:"column:failed_at;type:timestamptz;not null;default:now()" json:"failedAt"`
+	ReplayCount     int             `gorm:"column:replay_count;type:int;not null;default:0" json:"replayCount"`
+	LastReplayError string          `gorm:"column:last_replay_error;type:text" json:"lastReplayError,omitempty"`
+	ReplayedAt      sql.NullTime    `gorm:"column:replayed_at" json:"replayedAt"`
+}
+
+func (m *DeadLetter) TableName() string { return DeadLetterTable }
+
+func (m *DeadLetter) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(DeadLetterPrefix)
+	}
+	return
+}
+
+var DeadLetterSchema = struct {
+	ID         schema.Field
+	Topic      schema.Field
+	Handler    schema.Field
+	BusinessID schema.Field
+	Status     schema.Field
+	Attempts   schema.Field
+	FailedAt   schema.Field
+	CreatedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	Topic:      schema.NewField("topic", "topic"),
+	Handler:    schema.NewField("handler", "handler"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	Status:     schema.NewField("status", "status"),
+	Attempts:   schema.NewField("attempts", "attempts"),
+	FailedAt:   schema.NewField("failed_at", "failedAt"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+}
+
+// DeadLetterResponse is the API response for a dead letter.
+type DeadLetterResponse struct {
+	ID              string          `json:"id"`
+	Topic           string          `json:"topic"`
+	Handler         string          `json:"handler"`
+	BusinessID      string          `json:"businessId,omitempty"`
+	Region          string          `json:"region,omitempty"`
+	Payload         json.RawMessage `json:"payload"`
+	Error           string          `json:"error"`
+	Attempts        int             `json:"attempts"`
+	Status          Status          `json:"status"`
+	FailedAt        time.Time       `json:"failedAt"`
+	ReplayCount     int             `json:"replayCount"`
+	LastReplayError string          `json:"lastReplayError,omitempty"`
+	ReplayedAt      *time.Time      `json:"replayedAt,omitempty"`
+}
+
+// ToDeadLetterResponse converts a DeadLetter model to its API response.
+func ToDeadLetterResponse(m *DeadLetter) DeadLetterResponse {
+	resp := DeadLetterResponse{
+		ID:              m.ID,
+		Topic:           m.Topic,
+		Handler:         m.Handler,
+		BusinessID:      m.BusinessID,
+		Region:          m.Region,
+		Payload:         m.Payload,
+		Error:           m.Error,
+		Attempts:        m.Attempts,
+		Status:          m.Status,
+		FailedAt:        m.FailedAt,
+		ReplayCount:     m.ReplayCount,
+		LastReplayError: m.LastReplayError,
+	}
+	if m.ReplayedAt.Valid {
+		t := m.ReplayedAt.Time
+		resp.ReplayedAt = &t
+	}
+	return resp
+}
+
+// ToDeadLetterResponses converts a slice of DeadLetter models to responses.
+func ToDeadLetterResponses(items []*DeadLetter) []DeadLetterResponse {
+	responses := make([]DeadLetterResponse, 0, len(items))
+	for _, m := range items {
+		responses = append(responses, ToDeadLetterResponse(m))
+	}
+	return responses
+}
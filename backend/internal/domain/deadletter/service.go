@@ -0,0 +1,128 @@
+package deadletter
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/bus"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/region"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"gorm.io/gorm"
+)
+
+// Service stores bus dead letters and replays them through the bus.
+type Service struct {
+	storage *Storage
+	bus     *bus.Bus
+}
+
+// NewService creates the dead letter service and registers it as the bus dead-letter sink.
+func NewService(storage *Storage, b *bus.Bus) *Service {
+	s := &Service{storage: storage, bus: b}
+	if b != nil {
+		b.SetDeadLetterSink(s)
+	}
+	return s
+}
+
+// ListFilters narrows ListDeadLetters results.
+type ListFilters struct {
+	Status Status
+	Topic  string
+}
+
+// StoreDeadLetter implements bus.DeadLetterSink.
+func (s *Service) StoreDeadLetter(ctx context.Context, dl bus.DeadLetter) error {
+	payload, err := json.Marshal(dl.Payload)
+	if err != nil {
+		return err
+	}
+	var ref struct {
+		BusinessID string `json:"businessId"`
+	}
+	_ = json.Unmarshal(payload, &ref)
+
+	return s.storage.deadLetter.CreateOne(ctx, &DeadLetter{
+		Topic:      string(dl.Topic),
+		Handler:    dl.Handler,
+		BusinessID: ref.BusinessID,
+		Region:     dl.Region,
+		Payload:    payload,
+		Error:      dl.Error,
+		Attempts:   dl.Attempts,
+		Status:     StatusPending,
+		FailedAt:   dl.FailedAt,
+	})
+}
+
+// ListDeadLetters returns a page of dead letters, newest first unless ordered otherwise.
+func (s *Service) ListDeadLetters(ctx context.Context, req *list.ListRequest, filters *ListFilters) ([]*DeadLetter, int64, error) {
+	var scopes []func(*gorm.DB) *gorm.DB
+	if filters != nil {
+		if filters.Status != "" {
+			scopes = append(scopes, s.storage.deadLetter.ScopeEquals(DeadLetterSchema.Status, filters.Status))
+		}
+		if filters.Topic != "" {
+			scopes = append(scopes, s.storage.deadLetter.ScopeEquals(DeadLetterSchema.Topic, filters.Topic))
+		}
+	}
+
+	total, err := s.storage.deadLetter.Count(ctx, scopes...)
+	if err != nil {
+		return nil, 0, err
+	}
+	items, err := s.storage.deadLetter.FindMany(ctx, append(scopes,
+		s.storage.deadLetter.WithPagination(req.Offset(), req.Limit()),
+		s.storage.deadLetter.WithOrderBy(req.ParsedOrderByWithDefault(DeadLetterSchema, []string{"failed_at DESC"})),
+	)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// GetDeadLetter returns a dead letter by ID.
+func (s *Service) GetDeadLetter(ctx context.Context, id string) (*DeadLetter, error) {
+	dl, err := s.storage.deadLetter.FindByID(ctx, id)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrDeadLetterNotFound(id, err)
+		}
+		return nil, ErrDeadLetterQueryFailed(err)
+	}
+	return dl, nil
+}
+
+// ReplayDeadLetter runs the stored payload through its handler again. A successful
+// replay marks the dead letter as replayed; a failed one records the error and leaves
+// it pending. Handlers must be idempotent, as the original attempts may have partially applied.
+func (s *Service) ReplayDeadLetter(ctx context.Context, id string) (*DeadLetter, error) {
+	dl, err := s.GetDeadLetter(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if dl.Status == StatusReplayed {
+		return nil, ErrDeadLetterAlreadyReplayed(id)
+	}
+
+	// Replay in the region the event was emitted in, so the handler reads the same data.
+	replayCtx := region.WithRegion(context.WithoutCancel(ctx), dl.Region)
+	replayErr := s.bus.Replay(replayCtx, bus.Topic(dl.Topic), dl.Handler, dl.Payload)
+	dl.ReplayCount++
+	if replayErr != nil {
+		dl.LastReplayError = replayErr.Error()
+	} else {
+		dl.Status = StatusReplayed
+		dl.ReplayedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	}
+	if err := s.storage.deadLetter.UpdateOne(ctx, dl); err != nil {
+		return nil, ErrDeadLetterQueryFailed(err)
+	}
+	if replayErr != nil {
+		return nil, ErrDeadLetterReplayFailed(id, replayErr)
+	}
+	return dl, nil
+}
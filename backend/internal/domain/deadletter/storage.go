@@ -0,0 +1,21 @@
+package deadletter
+
+import (
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+)
+
+// Storage provides data access for dead letters.
+type Storage struct {
+	db         *database.Database
+	deadLetter *database.Repository[DeadLetter]
+}
+
+// NewStorage creates a new dead letter storage instance. Dead letters of every region are
+// kept in the home region so operators can list them in one place.
+func NewStorage(db *database.Database) *Storage {
+	db = db.Global()
+	return &Storage{
+		db:         db,
+		deadLetter: database.NewRepository[DeadLetter](db),
+	}
+}
@@ -0,0 +1,244 @@
+package inventory
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
+)
+
+// Catalog CSV columns. One row describes one variant; rows with the same product name belong
+// to the same product. Export writes every column; import requires catalogRequiredColumns and
+// ignores unknown ones.
+const (
+	catalogColProductName        = "productName"
+	catalogColProductDescription = "productDescription"
+	catalogColCategory           = "category"
+	catalogColVariantCode        = "variantCode"
+	catalogColSKU                = "sku"
+	catalogColBarcode            = "barcode"
+	catalogColCostPrice          = "costPrice"
+	catalogColSalePrice          = "salePrice"
+	catalogColStockQuantity      = "stockQuantity"
+	catalogColStockQuantityAlert = "stockQuantityAlert"
+	catalogColCurrency           = "currency"
+)
+
+// CatalogColumns is the header of catalog CSV files, in export order.
+var CatalogColumns = []string{
+	catalogColProductName, catalogColProductDescription, catalogColCategory, catalogColVariantCode,
+	catalogColSKU, catalogColBarcode, catalogColCostPrice, catalogColSalePrice,
+	catalogColStockQuantity, catalogColStockQuantityAlert, catalogColCurrency,
+}
+
+var catalogRequiredColumns = []string{
+	catalogColProductName, catalogColCategory, catalogColVariantCode,
+	catalogColCostPrice, catalogColSalePrice, catalogColStockQuantity,
+}
+
+// maxCatalogImportRows bounds the variant rows of one import, which is validated in memory.
+const maxCatalogImportRows = 5000
+
+// CatalogImportRowError is a problem with one cell (or, without Column, one row) of an import.
+// Row is the line in the file the row starts on; the header is row 1.
+type CatalogImportRowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// catalogRow is a parsed import row. An empty SKU keeps the variant's SKU, or generates one for
+// a new variant. Without a barcode or stockQuantityAlert column those fields are left as they are.
+type catalogRow struct {
+	line               int
+	productName        string
+	productDescription string
+	category           string
+	variantCode        string
+	sku                string
+	barcode            string
+	costPrice          decimal.Decimal
+	salePrice          decimal.Decimal
+	stockQuantity      int
+	stockQuantityAlert int
+	hasBarcode         bool
+	hasStockAlert      bool
+}
+
+// RenderCatalogCSV writes variants (with Product.Category preloaded) as a catalog CSV.
+func RenderCatalogCSV(variants []*Variant) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write(CatalogColumns)
+	for _, v := range variants {
+		var productName, description, category string
+		if p := v.Product; p != nil {
+			productName, description = p.Name, p.Description
+			if p.Category != nil {
+				category = p.Category.Name
+			}
+		}
+		_ = w.Write([]string{
+			catalogText(productName),
+			catalogText(description),
+			catalogText(category),
+			catalogText(v.Code),
+			catalogText(v.SKU),
+			v.Barcode,
+			money.StringFixed(v.CostPrice, v.Currency),
+			money.StringFixed(v.SalePrice, v.Currency),
+			strconv.Itoa(v.StockQuantity),
+			strconv.Itoa(v.StockQuantityAlert),
+			v.Currency,
+		})
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// catalogText prefixes values a spreadsheet would evaluate as a formula with a quote;
+// parseCatalogCSV strips it again.
+func catalogText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func catalogUnquote(s string) string {
+	if len(s) > 1 && s[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(s[1])) {
+		return s[1:]
+	}
+	return s
+}
+
+// parseCatalogCSV reads a catalog CSV. Rows with invalid cells are reported and left out;
+// a missing header column fails the whole file.
+func parseCatalogCSV(data []byte) ([]*catalogRow, []CatalogImportRowError) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, []CatalogImportRowError{{Row: 1, Message: "file has no header row"}}
+	}
+	index := map[string]int{}
+	for i, name := range header {
+		for _, col := range CatalogColumns {
+			if strings.EqualFold(strings.TrimSpace(name), col) {
+				index[col] = i
+			}
+		}
+	}
+	var errs []CatalogImportRowError
+	for _, col := range catalogRequiredColumns {
+		if _, ok := index[col]; !ok {
+			errs = append(errs, CatalogImportRowError{Row: 1, Column: col, Message: "required column is missing"})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	var rows []*catalogRow
+	count := 0
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			row := 0
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				row = parseErr.StartLine
+			}
+			errs = append(errs, CatalogImportRowError{Row: row, Message: "malformed CSV row"})
+			continue
+		}
+		line, _ := r.FieldPos(0)
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		if count++; count > maxCatalogImportRows {
+			errs = append(errs, CatalogImportRowError{Row: line, Message: "too many rows; split the file into imports of at most " + strconv.Itoa(maxCatalogImportRows) + " rows"})
+			break
+		}
+		cell := func(col string) string {
+			i, ok := index[col]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return catalogUnquote(strings.TrimSpace(record[i]))
+		}
+		row, rowErrs := parseCatalogRow(line, cell)
+		if len(rowErrs) > 0 {
+			errs = append(errs, rowErrs...)
+			continue
+		}
+		_, row.hasBarcode = index[catalogColBarcode]
+		_, row.hasStockAlert = index[catalogColStockQuantityAlert]
+		rows = append(rows, row)
+	}
+	return rows, errs
+}
+
+func parseCatalogRow(line int, cell func(col string) string) (*catalogRow, []CatalogImportRowError) {
+	var errs []CatalogImportRowError
+	fail := func(col, msg string) {
+		errs = append(errs, CatalogImportRowError{Row: line, Column: col, Message: msg})
+	}
+	row := &catalogRow{
+		line:               line,
+		productName:        cell(catalogColProductName),
+		productDescription: cell(catalogColProductDescription),
+		category:           cell(catalogColCategory),
+		variantCode:        cell(catalogColVariantCode),
+		sku:                cell(catalogColSKU),
+	}
+	for _, required := range [][2]string{
+		{catalogColProductName, row.productName},
+		{catalogColCategory, row.category},
+		{catalogColVariantCode, row.variantCode},
+	} {
+		if required[1] == "" {
+			fail(required[0], "is required")
+		}
+	}
+	code, err := normalizeBarcode(cell(catalogColBarcode))
+	if err != nil {
+		fail(catalogColBarcode, "must be a valid EAN or UPC number")
+	}
+	row.barcode = code
+
+	price := func(col string) decimal.Decimal {
+		d, err := decimal.NewFromString(cell(col))
+		if err != nil || d.IsNegative() {
+			fail(col, "must be a number >= 0")
+		}
+		return d
+	}
+	row.costPrice = price(catalogColCostPrice)
+	row.salePrice = price(catalogColSalePrice)
+
+	quantity := func(col string, optional bool) int {
+		v := cell(col)
+		if v == "" && optional {
+			return 0
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			fail(col, "must be a whole number >= 0")
+		}
+		return n
+	}
+	row.stockQuantity = quantity(catalogColStockQuantity, false)
+	row.stockQuantityAlert = quantity(catalogColStockQuantityAlert, true)
+	return row, errs
+}
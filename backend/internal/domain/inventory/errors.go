@@ -2,6 +2,7 @@ package inventory
 
 import (
 	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/shopspring/decimal"
 )
 
 func ErrProductNotFound(err error) *problem.Problem {
@@ -17,3 +18,327 @@ func ErrVariantNotFound(err error) *problem.Problem {
 func ErrCategoryNotFound(err error) *problem.Problem {
 	return problem.NotFound("category not found").WithError(err).WithCode("inventory.category_not_found")
 }
+
+// ErrStockAdjustmentConflict indicates that a batched stock adjustment could not be applied
+// to every variant, because one was removed or its stock changed concurrently.
+func ErrStockAdjustmentConflict(expected, applied int64) *problem.Problem {
+	return problem.Conflict("stock levels changed, please retry").
+		With("expectedVariants", expected).
+		With("updatedVariants", applied).
+		WithCode("inventory.stock_adjustment_conflict")
+}
+
+// ErrProductVersionConflict indicates the product changed since the client (or this request) loaded it.
+func ErrProductVersionConflict(productID string, err error) *problem.Problem {
+	return problem.Conflict("product was modified by another request").WithError(err).With("productId", productID).WithCode("inventory.product_version_conflict")
+}
+
+// ErrPriceListNotFound indicates that a price list could not be found.
+func ErrPriceListNotFound(err error) *problem.Problem {
+	return problem.NotFound("price list not found").WithError(err).WithCode("inventory.price_list_not_found")
+}
+
+// ErrPriceListNameTaken indicates another price list of the business already uses the name.
+func ErrPriceListNameTaken(name string, err error) *problem.Problem {
+	return problem.Conflict("a price list with this name already exists").WithError(err).With("name", name).WithCode("inventory.price_list_name_taken")
+}
+
+// ErrDuplicatePriceListEntry indicates the same variant appears twice in a price list request.
+func ErrDuplicatePriceListEntry(variantID string) *problem.Problem {
+	return problem.BadRequest("variant listed more than once").With("variantId", variantID).WithCode("inventory.duplicate_price_list_entry")
+}
+
+// ErrInvalidPriceBreaks indicates quantity breaks of a price list entry that repeat a quantity
+// or do not lower the price as the quantity grows.
+func ErrInvalidPriceBreaks(variantID string) *problem.Problem {
+	return problem.BadRequest("quantity breaks must have distinct quantities and lower prices for larger quantities").With("variantId", variantID).WithCode("inventory.invalid_price_breaks")
+}
+
+// ErrInvalidPromoWindow indicates a promo that ends before it starts, or has already ended.
+func ErrInvalidPromoWindow() *problem.Problem {
+	return problem.BadRequest("promo must end after it starts and in the future").WithCode("inventory.invalid_promo_window")
+}
+
+// ErrTooManyProductPhotos indicates that adding photos would exceed the per-product limit.
+func ErrTooManyProductPhotos(limit, requested int) *problem.Problem {
+	return problem.BadRequest("too many product photos").
+		With("maxPhotos", limit).
+		With("requestedPhotos", requested).
+		WithCode("inventory.too_many_photos")
+}
+
+// ErrProductPhotoNotFound indicates that a photo id does not match any photo of the product.
+func ErrProductPhotoNotFound(photoID string) *problem.Problem {
+	return problem.NotFound("product photo not found").With("photoId", photoID).WithCode("inventory.photo_not_found")
+}
+
+// ErrInvalidPhotoOrder indicates that a reorder request does not list every photo exactly once.
+func ErrInvalidPhotoOrder() *problem.Problem {
+	return problem.BadRequest("photoIds must list every photo of the product exactly once").
+		With("field", "photoIds").
+		WithCode("inventory.invalid_photo_order")
+}
+
+// ErrPhotoAssetNotFound indicates that an asset to attach is not an uploaded image of the business.
+func ErrPhotoAssetNotFound(assetID string) *problem.Problem {
+	return problem.NotFound("photo asset not found").With("assetId", assetID).WithCode("inventory.photo_asset_not_found")
+}
+
+// ErrSupplierNotFound indicates that a supplier could not be found.
+func ErrSupplierNotFound(err error) *problem.Problem {
+	return problem.NotFound("supplier not found").WithError(err).WithCode("inventory.supplier_not_found")
+}
+
+// ErrSupplierHasOpenPurchaseOrders indicates a supplier cannot be deleted while goods are
+// still expected from it or it is still owed money.
+func ErrSupplierHasOpenPurchaseOrders(supplierID string) *problem.Problem {
+	return problem.Conflict("supplier has open purchase orders").With("supplierId", supplierID).WithCode("inventory.supplier_has_open_purchase_orders")
+}
+
+// ErrPurchaseOrderNotFound indicates that a purchase order could not be found.
+func ErrPurchaseOrderNotFound(err error) *problem.Problem {
+	return problem.NotFound("purchase order not found").WithError(err).WithCode("inventory.purchase_order_not_found")
+}
+
+// ErrDuplicatePurchaseOrderItem indicates the same variant or item appears twice in a purchase order request.
+func ErrDuplicatePurchaseOrderItem(field, id string) *problem.Problem {
+	return problem.BadRequest("item listed more than once").With(field, id).WithCode("inventory.duplicate_purchase_order_item")
+}
+
+// ErrPurchaseOrderNotReceivable indicates goods cannot be received against a purchase order
+// that is fully received or cancelled.
+func ErrPurchaseOrderNotReceivable(purchaseOrderID string, status PurchaseOrderStatus) *problem.Problem {
+	return problem.Conflict("purchase order cannot receive goods").
+		With("purchaseOrderId", purchaseOrderID).
+		With("status", status).
+		WithCode("inventory.purchase_order_not_receivable")
+}
+
+// ErrPurchaseOrderOverReceipt indicates a receipt of more units than an item still expects.
+func ErrPurchaseOrderOverReceipt(itemID string, remaining, requested int) *problem.Problem {
+	return problem.BadRequest("quantity exceeds the units still expected").
+		With("itemId", itemID).
+		With("remaining", remaining).
+		With("requested", requested).
+		WithCode("inventory.purchase_order_over_receipt")
+}
+
+// ErrPurchaseOrderItemNotFound indicates an item id that is not on the purchase order.
+func ErrPurchaseOrderItemNotFound(itemID string) *problem.Problem {
+	return problem.NotFound("purchase order item not found").With("itemId", itemID).WithCode("inventory.purchase_order_item_not_found")
+}
+
+// ErrPurchaseOrderNotCancellable indicates a purchase order that already received goods.
+func ErrPurchaseOrderNotCancellable(purchaseOrderID string, status PurchaseOrderStatus) *problem.Problem {
+	return problem.Conflict("only purchase orders with nothing received can be cancelled").
+		With("purchaseOrderId", purchaseOrderID).
+		With("status", status).
+		WithCode("inventory.purchase_order_not_cancellable")
+}
+
+// ErrPurchaseOrderOverpayment indicates a payment that would exceed the purchase order total.
+func ErrPurchaseOrderOverpayment(purchaseOrderID string, remaining, requested decimal.Decimal) *problem.Problem {
+	return problem.BadRequest("payment exceeds the unpaid amount of the purchase order").
+		With("purchaseOrderId", purchaseOrderID).
+		With("unpaid", remaining).
+		With("requested", requested).
+		WithCode("inventory.purchase_order_overpayment")
+}
+
+// ErrPurchaseOrderCancelled indicates a payment against a cancelled purchase order.
+func ErrPurchaseOrderCancelled(purchaseOrderID string) *problem.Problem {
+	return problem.Conflict("purchase order is cancelled").With("purchaseOrderId", purchaseOrderID).WithCode("inventory.purchase_order_cancelled")
+}
+
+// ErrPurchaseOrderBilled indicates a payment on a purchase order that is paid through its bills.
+func ErrPurchaseOrderBilled(purchaseOrderID string) *problem.Problem {
+	return problem.Conflict("purchase order is billed; record the payment on its bills").With("purchaseOrderId", purchaseOrderID).WithCode("inventory.purchase_order_billed")
+}
+
+// ErrSupplierHasUnpaidBills indicates a supplier cannot be deleted while it is owed money on bills.
+func ErrSupplierHasUnpaidBills(supplierID string) *problem.Problem {
+	return problem.Conflict("supplier has unpaid bills").With("supplierId", supplierID).WithCode("inventory.supplier_has_unpaid_bills")
+}
+
+// ErrSupplierBillNotFound indicates that a supplier bill could not be found.
+func ErrSupplierBillNotFound(err error) *problem.Problem {
+	return problem.NotFound("supplier bill not found").WithError(err).WithCode("inventory.supplier_bill_not_found")
+}
+
+// ErrSupplierBillPurchaseOrderMismatch indicates a bill for a purchase order of another supplier.
+func ErrSupplierBillPurchaseOrderMismatch(purchaseOrderID, supplierID string) *problem.Problem {
+	return problem.BadRequest("purchase order is not from the supplier of the bill").
+		With("purchaseOrderId", purchaseOrderID).
+		With("supplierId", supplierID).
+		WithCode("inventory.supplier_bill_purchase_order_mismatch")
+}
+
+// ErrSupplierBillExceedsPurchaseOrder indicates bills of a purchase order adding up to more than its total.
+func ErrSupplierBillExceedsPurchaseOrder(purchaseOrderID string, unbilled, requested decimal.Decimal) *problem.Problem {
+	return problem.BadRequest("bill amount exceeds the unbilled amount of the purchase order").
+		With("purchaseOrderId", purchaseOrderID).
+		With("unbilled", unbilled).
+		With("requested", requested).
+		WithCode("inventory.supplier_bill_exceeds_purchase_order")
+}
+
+// ErrSupplierBillInvalidDueDate indicates a bill due before it was issued.
+func ErrSupplierBillInvalidDueDate() *problem.Problem {
+	return problem.BadRequest("dueAt must not be before issuedAt").With("field", "dueAt").WithCode("inventory.supplier_bill_invalid_due_date")
+}
+
+// ErrSupplierBillAmountBelowPaid indicates a bill amount lowered below what was already paid on it.
+func ErrSupplierBillAmountBelowPaid(billID string, paid decimal.Decimal) *problem.Problem {
+	return problem.BadRequest("amount is below what was already paid on the bill").
+		With("billId", billID).
+		With("amountPaid", paid).
+		WithCode("inventory.supplier_bill_amount_below_paid")
+}
+
+// ErrSupplierBillOverpayment indicates a payment that would exceed what is owed on a bill.
+func ErrSupplierBillOverpayment(billID string, outstanding, requested decimal.Decimal) *problem.Problem {
+	return problem.BadRequest("payment exceeds the outstanding amount of the bill").
+		With("billId", billID).
+		With("outstanding", outstanding).
+		With("requested", requested).
+		WithCode("inventory.supplier_bill_overpayment")
+}
+
+// ErrSupplierBillHasPayments indicates a bill cannot be deleted once payments were recorded on it.
+func ErrSupplierBillHasPayments(billID string) *problem.Problem {
+	return problem.Conflict("supplier bill has payments").With("billId", billID).WithCode("inventory.supplier_bill_has_payments")
+}
+
+// ErrLocationNotFound indicates that a stock location could not be found.
+func ErrLocationNotFound(err error) *problem.Problem {
+	return problem.NotFound("location not found").WithError(err).WithCode("inventory.location_not_found")
+}
+
+// ErrLocationNameTaken indicates another location of the business already uses the name.
+func ErrLocationNameTaken(name string, err error) *problem.Problem {
+	return problem.Conflict("a location with this name already exists").WithError(err).With("name", name).WithCode("inventory.location_name_taken")
+}
+
+// ErrLocationHasStock indicates a location cannot be deleted while it still holds stock.
+func ErrLocationHasStock(locationID string, units int64) *problem.Problem {
+	return problem.Conflict("location still holds stock").
+		With("locationId", locationID).
+		With("stockUnits", units).
+		WithCode("inventory.location_has_stock")
+}
+
+// ErrDefaultLocationRequired indicates the default location cannot be deleted or unset while
+// the business has other locations.
+func ErrDefaultLocationRequired(locationID string) *problem.Problem {
+	return problem.Conflict("make another location the default first").With("locationId", locationID).WithCode("inventory.default_location_required")
+}
+
+// ErrInsufficientLocationStock indicates a stock change that would leave a variant with
+// negative stock at a location, even though the business may hold enough elsewhere.
+func ErrInsufficientLocationStock(locationID string, expected, applied int64) *problem.Problem {
+	return problem.Conflict("not enough stock at this location").
+		With("locationId", locationID).
+		With("expectedVariants", expected).
+		With("updatedVariants", applied).
+		WithCode("inventory.insufficient_location_stock")
+}
+
+// ErrSameLocationTransfer indicates a transfer whose source and destination are the same location.
+func ErrSameLocationTransfer() *problem.Problem {
+	return problem.BadRequest("source and destination locations must differ").With("field", "toLocationId").WithCode("inventory.same_location_transfer")
+}
+
+// ErrInvalidBarcode indicates a barcode that is not a valid EAN-8, UPC-A, EAN-13 or GTIN-14
+// number.
+func ErrInvalidBarcode(value string) *problem.Problem {
+	return problem.BadRequest("barcode must be a valid EAN or UPC number").With("field", "barcode").With("barcode", value).WithCode("inventory.invalid_barcode")
+}
+
+// ErrSKUPatternNotSet indicates a SKU preview for a business without a SKU pattern.
+func ErrSKUPatternNotSet() *problem.Problem {
+	return problem.BadRequest("the business has no SKU pattern; set skuPattern.sequenceDigits").WithCode("inventory.sku_pattern_not_set")
+}
+
+// ErrVariantSKUTaken indicates another variant of the business already uses the SKU.
+func ErrVariantSKUTaken(sku string, err error) *problem.Problem {
+	return problem.Conflict("a variant with this SKU already exists").WithError(err).With("sku", sku).WithCode("inventory.sku_taken")
+}
+
+// ErrVariantBarcodeTaken indicates another variant of the business already uses the barcode.
+func ErrVariantBarcodeTaken(barcode string, err error) *problem.Problem {
+	return problem.Conflict("a variant with this barcode already exists").WithError(err).With("barcode", barcode).WithCode("inventory.barcode_taken")
+}
+
+// ErrNoLabelVariants indicates a label request whose variants could not be found.
+func ErrNoLabelVariants() *problem.Problem {
+	return problem.BadRequest("none of the requested variants exist").With("field", "variantIds").WithCode("inventory.no_label_variants")
+}
+
+// ErrCatalogImportInvalid indicates a catalog import with invalid rows; nothing was imported.
+func ErrCatalogImportInvalid(errs []CatalogImportRowError) *problem.Problem {
+	return problem.UnprocessableEntity("the file has invalid rows; nothing was imported").With("errors", errs).WithCode("inventory.catalog_import_invalid")
+}
+
+// ErrBulkVariantUpdateInvalid indicates a bulk variant update with invalid changes; nothing was updated.
+func ErrBulkVariantUpdateInvalid(errs []BulkVariantChangeError) *problem.Problem {
+	return problem.UnprocessableEntity("some changes are invalid; nothing was updated").With("errors", errs).WithCode("inventory.bulk_variant_update_invalid")
+}
+
+// ErrInvalidProductOptions indicates product options with duplicate names or values.
+func ErrInvalidProductOptions(detail string) *problem.Problem {
+	return problem.BadRequest(detail).With("field", "options").WithCode("inventory.invalid_product_options")
+}
+
+// ErrVariantMatrixTooLarge indicates options whose combinations exceed the variants a product may have.
+func ErrVariantMatrixTooLarge(combinations, max int) *problem.Problem {
+	return problem.BadRequest("too many option combinations").
+		With("combinations", combinations).
+		With("max", max).
+		WithCode("inventory.variant_matrix_too_large")
+}
+
+// ErrNegativeVariantPrice indicates price modifiers that bring a generated variant below zero.
+func ErrNegativeVariantPrice(code string) *problem.Problem {
+	return problem.BadRequest("price modifiers make a variant's sale price negative").With("code", code).WithCode("inventory.negative_variant_price")
+}
+
+// ErrStocktakeNotFound indicates that a stocktake could not be found.
+func ErrStocktakeNotFound(err error) *problem.Problem {
+	return problem.NotFound("stocktake not found").WithError(err).WithCode("inventory.stocktake_not_found")
+}
+
+// ErrStocktakeInProgress indicates the location already has a stocktake counting.
+func ErrStocktakeInProgress(locationID string, err error) *problem.Problem {
+	return problem.Conflict("a stocktake is already counting this location").WithError(err).With("locationId", locationID).WithCode("inventory.stocktake_in_progress")
+}
+
+// ErrStocktakeClosed indicates a change to a stocktake that was approved or cancelled.
+func ErrStocktakeClosed(stocktakeID string, status StocktakeStatus) *problem.Problem {
+	return problem.Conflict("stocktake is no longer counting").
+		With("stocktakeId", stocktakeID).
+		With("status", status).
+		WithCode("inventory.stocktake_closed")
+}
+
+// ErrStocktakeEmpty indicates a stocktake whose scope has no variants to count.
+func ErrStocktakeEmpty() *problem.Problem {
+	return problem.BadRequest("there are no variants to count").WithCode("inventory.stocktake_empty")
+}
+
+// ErrVariantNotInStocktake indicates a count for a variant outside the stocktake's snapshot.
+func ErrVariantNotInStocktake(variantID string) *problem.Problem {
+	return problem.NotFound("variant is not part of this stocktake").With("variantId", variantID).WithCode("inventory.variant_not_in_stocktake")
+}
+
+// ErrDownloadRequiresDigitalProduct indicates a download attached to a product that is not digital.
+func ErrDownloadRequiresDigitalProduct(productType ProductType) *problem.Problem {
+	return problem.BadRequest("only digital products can have a download").
+		With("field", "download").
+		With("type", productType).
+		WithCode("inventory.download_requires_digital_product")
+}
+
+// ErrStocktakeNotCounted indicates approving a stocktake with no counted variants.
+func ErrStocktakeNotCounted(stocktakeID string) *problem.Problem {
+	return problem.BadRequest("count at least one variant before approving").With("stocktakeId", stocktakeID).WithCode("inventory.stocktake_not_counted")
+}
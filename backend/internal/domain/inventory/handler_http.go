@@ -1,7 +1,10 @@
 package inventory
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/abdelrahman146/kyora/internal/domain/account"
 	"github.com/abdelrahman146/kyora/internal/domain/business"
@@ -38,12 +41,13 @@ type listInventoryQuery struct {
 	ProductID   string   `form:"productId" binding:"omitempty"`
 	TopLimit    int      `form:"topLimit" binding:"omitempty,min=1,max=50"`
 	DetailLimit int      `form:"limit" binding:"omitempty,min=1,max=50"`
+	View        string   `form:"view" binding:"omitempty,oneof=full summary"`
 }
 
 // ListProducts returns a paginated list of products.
 //
 // @Summary      List products
-// @Description  Returns a paginated list of products for the authenticated workspace/business. Each product includes its variants. Supports filtering by category and stock status, and searching across product name, variant name, variant SKU, and category name.
+// @Description  Returns a paginated list of products for the authenticated workspace/business. Each product includes its variants. Supports filtering by category and stock status, and searching across product name, variant name, variant SKU, and category name. With view=summary each item is an inventory.ProductSummaryResponse with variant aggregates instead of full variants.
 // @Tags         inventory
 // @Produce      json
 // @Param        businessDescriptor path string true "Business descriptor"
@@ -53,6 +57,7 @@ type listInventoryQuery struct {
 // @Param        search query string false "Search term (matches product name, variant name, variant SKU, or category name)"
 // @Param        categoryId query string false "Filter by category ID"
 // @Param        stockStatus query string false "Filter by stock status (in_stock, low_stock, out_of_stock)"
+// @Param        view query string false "Response shape: full (default) or summary"
 // @Success      200 {object} list.ListResponse[inventory.ProductResponse] "Products with their variants included"
 // @Failure      401 {object} problem.Problem
 // @Failure      500 {object} problem.Problem
@@ -95,6 +100,17 @@ func (h *HttpHandler) ListProducts(c *gin.Context) {
 		StockStatus: StockStatus(query.StockStatus),
 	}
 
+	if query.View == "summary" {
+		items, total, err := h.service.ListProductSummaries(c.Request.Context(), actor, biz, listReq, filters)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		hasMore := int64(query.Page*query.PageSize) < total
+		response.SuccessJSON(c, http.StatusOK, list.NewListResponse(ToProductSummaryResponses(items), query.Page, query.PageSize, total, hasMore))
+		return
+	}
+
 	items, total, err := h.service.ListProducts(c.Request.Context(), actor, biz, listReq, filters)
 	if err != nil {
 		response.Error(c, err)
@@ -323,6 +339,176 @@ func (h *HttpHandler) DeleteProduct(c *gin.Context) {
 	response.SuccessEmpty(c, http.StatusNoContent)
 }
 
+// AddProductPhotos attaches uploaded images to a product.
+//
+// @Summary      Add product photos
+// @Description  Attaches up to 10 uploaded image assets to a product in one request, after its existing photos. Assets already attached are skipped; with setCover the first added photo becomes the cover.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        productId path string true "Product ID"
+// @Param        body body AddProductPhotosRequest true "Assets to attach"
+// @Success      200 {object} inventory.ProductResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/products/{productId}/photos [post]
+// @Security     BearerAuth
+func (h *HttpHandler) AddProductPhotos(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req AddProductPhotosRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	product, err := h.service.AddProductPhotos(c.Request.Context(), actor, biz, c.Param("productId"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	h.respondProductWithVariants(c, actor, biz, product)
+}
+
+// ReorderProductPhotos sets the order of a product's photos.
+//
+// @Summary      Reorder product photos
+// @Description  Sets the order of a product's photos. photoIds lists every photo exactly once by its assetId (or url for photos without one); the first becomes the cover.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        productId path string true "Product ID"
+// @Param        body body ReorderProductPhotosRequest true "New order"
+// @Success      200 {object} inventory.ProductResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/products/{productId}/photos/order [put]
+// @Security     BearerAuth
+func (h *HttpHandler) ReorderProductPhotos(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req ReorderProductPhotosRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	product, err := h.service.ReorderProductPhotos(c.Request.Context(), actor, biz, c.Param("productId"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	h.respondProductWithVariants(c, actor, biz, product)
+}
+
+// SetProductCoverPhoto designates a product's cover photo.
+//
+// @Summary      Set product cover photo
+// @Description  Moves a photo to the front of the product's photos, making it the cover. The photo is identified by its assetId (or url for photos without one).
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        productId path string true "Product ID"
+// @Param        body body SetProductCoverPhotoRequest true "Cover photo"
+// @Success      200 {object} inventory.ProductResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/products/{productId}/photos/cover [put]
+// @Security     BearerAuth
+func (h *HttpHandler) SetProductCoverPhoto(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req SetProductCoverPhotoRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	product, err := h.service.SetProductCoverPhoto(c.Request.Context(), actor, biz, c.Param("productId"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	h.respondProductWithVariants(c, actor, biz, product)
+}
+
+// RegenerateProductPhotoThumbnails rebuilds a product's photo thumbnails from their assets.
+//
+// @Summary      Regenerate product photo thumbnails
+// @Description  Rebuilds the URLs and thumbnail URLs of every uploaded photo of the product from its asset, keeping metadata. Photos without an asset are left unchanged.
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        productId path string true "Product ID"
+// @Success      200 {object} inventory.ProductResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/products/{productId}/photos/thumbnails [post]
+// @Security     BearerAuth
+func (h *HttpHandler) RegenerateProductPhotoThumbnails(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	product, err := h.service.RegenerateProductPhotoThumbnails(c.Request.Context(), actor, biz, c.Param("productId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	h.respondProductWithVariants(c, actor, biz, product)
+}
+
+func (h *HttpHandler) respondProductWithVariants(c *gin.Context, actor *account.User, biz *business.Business, product *Product) {
+	variants, err := h.service.GetProductVariants(c.Request.Context(), actor, biz, product.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	product.Variants = variants
+	response.SuccessJSON(c, http.StatusOK, ToProductResponse(product))
+}
+
 // ListProductVariants returns a paginated list of variants for a product.
 //
 // @Summary      List product variants
@@ -453,6 +639,67 @@ func (h *HttpHandler) ListVariants(c *gin.Context) {
 	response.SuccessJSON(c, http.StatusOK, list.NewListResponse(variantResponses, query.Page, query.PageSize, total, hasMore))
 }
 
+// ListPickerVariants returns variants for the order-creation picker with live availability.
+//
+// @Summary      List variants for order picker
+// @Description  Returns a paginated list of variants matching the search with sale price, available quantity (on-hand minus units held by open orders) and stock status
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        page query int false "Page number (default: 1)"
+// @Param        pageSize query int false "Page size (default: 20, max: 100)"
+// @Param        orderBy query []string false "Sort order (e.g., -name, createdAt)"
+// @Param        search query string false "Search term for variant name, product name, SKU, code or barcode"
+// @Success      200 {object} list.ListResponse[inventory.VariantPickerResponse]
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/variants/picker [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListPickerVariants(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query listInventoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+	if query.SearchTerm != "" {
+		term, err := list.NormalizeSearchTerm(query.SearchTerm)
+		if err != nil {
+			response.Error(c, problem.BadRequest("invalid search term"))
+			return
+		}
+		query.SearchTerm = term
+	}
+	listReq := list.NewListRequest(query.Page, query.PageSize, query.OrderBy, query.SearchTerm)
+	items, err := h.service.ListPickerVariants(c.Request.Context(), actor, biz, listReq)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	total, err := h.service.CountVariants(c.Request.Context(), actor, biz, listReq)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	hasMore := int64(query.Page*query.PageSize) < total
+	response.SuccessJSON(c, http.StatusOK, list.NewListResponse(ToVariantPickerResponses(items), query.Page, query.PageSize, total, hasMore))
+}
+
 // GetVariant returns a variant by ID.
 //
 // @Summary      Get variant
@@ -492,123 +739,396 @@ func (h *HttpHandler) GetVariant(c *gin.Context) {
 	response.SuccessJSON(c, http.StatusOK, variantResponse)
 }
 
-// CreateVariant creates a new variant.
+type searchInventoryQuery struct {
+	Query string   `form:"q" binding:"required"`
+	Types []string `form:"types" binding:"omitempty,dive,oneof=product variant"`
+	Limit int      `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// SearchInventory returns products and variants matching a query, ranked together.
 //
-// @Summary      Create variant
-// @Description  Creates a new variant under a product
+// @Summary      Search inventory
+// @Description  Searches the products and variants of the business at once and returns one list ranked by relevance, each item tagged with its type. Misspelled names and partial or mistyped SKUs still match; an exact SKU or barcode ranks first.
 // @Tags         inventory
-// @Accept       json
 // @Produce      json
 // @Param        businessDescriptor path string true "Business descriptor"
-// @Param        body body CreateVariantRequest true "Variant"
-// @Success      201 {object} inventory.VariantResponse
+// @Param        q query string true "Search term (at least 2 characters)"
+// @Param        types query []string false "Restrict to result types (product, variant)"
+// @Param        limit query int false "Maximum number of results (default: 20, max: 50)"
+// @Success      200 {object} inventory.SearchResponse
 // @Failure      400 {object} problem.Problem
 // @Failure      401 {object} problem.Problem
 // @Failure      404 {object} problem.Problem
 // @Failure      500 {object} problem.Problem
-// @Router       /v1/businesses/{businessDescriptor}/inventory/variants [post]
+// @Router       /v1/businesses/{businessDescriptor}/inventory/search [get]
 // @Security     BearerAuth
-func (h *HttpHandler) CreateVariant(c *gin.Context) {
+func (h *HttpHandler) SearchInventory(c *gin.Context) {
 	actor, err := account.ActorFromContext(c)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
-	biz, err := h.getBusinessForRequest(c, actor)
-	if err != nil {
-		response.Error(c, err)
+	var query searchInventoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
 		return
 	}
-	var req CreateVariantRequest
-	if err := request.ValidBody(c, &req); err != nil {
+	term, err := list.NormalizeSearchTerm(query.Query)
+	if err != nil || len([]rune(term)) < 2 {
+		response.Error(c, problem.BadRequest("search term must be at least 2 characters"))
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+	types := make([]SearchResultType, len(query.Types))
+	for i, t := range query.Types {
+		types[i] = SearchResultType(t)
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
 		response.Error(c, err)
 		return
 	}
-	variant, err := h.service.CreateVariant(c.Request.Context(), actor, biz, &req)
+	results, err := h.service.SearchInventory(c.Request.Context(), actor, biz, term, types, query.Limit)
 	if err != nil {
-		if database.IsRecordNotFound(err) {
-			response.Error(c, ErrProductNotFound(err).With("productId", req.ProductID))
-			return
-		}
 		response.Error(c, err)
 		return
 	}
-	variantResponse := ToVariantResponse(variant)
-	response.SuccessJSON(c, http.StatusCreated, variantResponse)
+	response.SuccessJSON(c, http.StatusOK, ToSearchResponse(term, results))
 }
 
-// UpdateVariant updates a variant.
+type lookupVariantQuery struct {
+	Code string `form:"code" binding:"required"`
+}
+
+// LookupVariant finds a variant by scanned barcode or SKU.
 //
-// @Summary      Update variant
-// @Description  Updates a variant
+// @Summary      Look up variant by barcode
+// @Description  Returns the variant whose barcode (EAN/UPC) or SKU equals code. A barcode match wins over a SKU match. Intended for scanning during packing.
 // @Tags         inventory
-// @Accept       json
 // @Produce      json
 // @Param        businessDescriptor path string true "Business descriptor"
-// @Param        variantId path string true "Variant ID"
-// @Param        body body UpdateVariantRequest true "Updates"
+// @Param        code query string true "Scanned barcode or SKU"
 // @Success      200 {object} inventory.VariantResponse
 // @Failure      400 {object} problem.Problem
 // @Failure      401 {object} problem.Problem
 // @Failure      404 {object} problem.Problem
 // @Failure      500 {object} problem.Problem
-// @Router       /v1/businesses/{businessDescriptor}/inventory/variants/{variantId} [patch]
+// @Router       /v1/businesses/{businessDescriptor}/inventory/variants/lookup [get]
 // @Security     BearerAuth
-func (h *HttpHandler) UpdateVariant(c *gin.Context) {
+func (h *HttpHandler) LookupVariant(c *gin.Context) {
 	actor, err := account.ActorFromContext(c)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
-	biz, err := h.getBusinessForRequest(c, actor)
-	if err != nil {
-		response.Error(c, err)
-		return
-	}
-	id := c.Param("variantId")
-	var req UpdateVariantRequest
-	if err := request.ValidBody(c, &req); err != nil {
-		response.Error(c, err)
+	var query lookupVariantQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
 		return
 	}
-	if err := h.service.UpdateVariant(c.Request.Context(), actor, biz, id, &req); err != nil {
-		if database.IsRecordNotFound(err) {
-			response.Error(c, ErrVariantNotFound(err).With("variantId", id))
-			return
-		}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
 		response.Error(c, err)
 		return
 	}
-	updated, err := h.service.GetVariantByID(c.Request.Context(), actor, biz, id)
+	variant, err := h.service.LookupVariant(c.Request.Context(), actor, biz, query.Code)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
-	variantResponse := ToVariantResponse(updated)
-	response.SuccessJSON(c, http.StatusOK, variantResponse)
+	response.SuccessJSON(c, http.StatusOK, ToVariantResponse(variant))
 }
 
-// DeleteVariant deletes a variant.
+// PreviewSKUs shows the next SKUs the business SKU pattern would generate.
 //
-// @Summary      Delete variant
-// @Description  Deletes a variant
+// @Summary      Preview generated SKUs
+// @Description  Returns the next count (default 1, max 20) SKUs the business SKU pattern would give variants of a product in categoryId, without taking them. prefix, includeCategory and sequenceDigits override the saved pattern to try one before saving it.
 // @Tags         inventory
 // @Produce      json
 // @Param        businessDescriptor path string true "Business descriptor"
-// @Param        variantId path string true "Variant ID"
-// @Success      204
+// @Param        categoryId query string false "Category of the product"
+// @Param        count query int false "Number of SKUs"
+// @Param        prefix query string false "Pattern prefix override"
+// @Param        includeCategory query bool false "Pattern category code override"
+// @Param        sequenceDigits query int false "Pattern sequence digits override"
+// @Success      200 {object} inventory.SKUPreviewResponse
+// @Failure      400 {object} problem.Problem
 // @Failure      401 {object} problem.Problem
 // @Failure      404 {object} problem.Problem
 // @Failure      500 {object} problem.Problem
-// @Router       /v1/businesses/{businessDescriptor}/inventory/variants/{variantId} [delete]
+// @Router       /v1/businesses/{businessDescriptor}/inventory/variants/sku-preview [get]
 // @Security     BearerAuth
-func (h *HttpHandler) DeleteVariant(c *gin.Context) {
+func (h *HttpHandler) PreviewSKUs(c *gin.Context) {
 	actor, err := account.ActorFromContext(c)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
-	biz, err := h.getBusinessForRequest(c, actor)
+	var query SKUPreviewRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	preview, err := h.service.PreviewSKUs(c.Request.Context(), actor, biz, &query)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, preview)
+}
+
+// DownloadVariantLabels renders barcode labels for the selected variants.
+//
+// @Summary      Download variant labels
+// @Description  Returns an A4 PDF of barcode labels (3 x 8 per page) for the selected variants. Each label shows the variant name, a Code 128 barcode of its barcode (or SKU when it has none) and the current price. Unknown variant IDs are skipped.
+// @Tags         inventory
+// @Accept       json
+// @Produce      application/pdf
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body VariantLabelsRequest true "Variants and copies"
+// @Success      200 {file} file
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/variants/labels [post]
+// @Security     BearerAuth
+func (h *HttpHandler) DownloadVariantLabels(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req VariantLabelsRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	variants, err := h.service.ListLabelVariants(c.Request.Context(), actor, biz, req.VariantIDs)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	copies := req.Copies
+	if copies == 0 {
+		copies = 1
+	}
+	response.SuccessFile(c, http.StatusOK, "application/pdf", "variant-labels.pdf", RenderVariantLabelsPDF(variants, copies))
+}
+
+// GenerateProductVariants sets a product's options and creates its variant matrix.
+//
+// @Summary      Generate variant matrix
+// @Description  Stores structured options (e.g. Size, Color) on the product and creates a variant for every combination of their values the product does not have yet. Variant codes join the values in option order ("M / Red"); the sale price is salePrice plus the values' priceModifier. Existing variants with a matching code take the combination's options instead of being duplicated. Up to 3 options and 200 combinations.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        productId path string true "Product ID"
+// @Param        body body GenerateVariantsRequest true "Options and base prices"
+// @Success      200 {object} inventory.GenerateVariantsResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/products/{productId}/variants/generate [post]
+// @Security     BearerAuth
+func (h *HttpHandler) GenerateProductVariants(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req GenerateVariantsRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	product, created, err := h.service.GenerateProductVariants(c.Request.Context(), actor, biz, c.Param("productId"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, GenerateVariantsResponse{
+		Product: ToProductResponse(product),
+		Created: ToVariantResponses(created),
+	})
+}
+
+// CreateVariant creates a new variant.
+//
+// @Summary      Create variant
+// @Description  Creates a new variant under a product
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body CreateVariantRequest true "Variant"
+// @Success      201 {object} inventory.VariantResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/variants [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CreateVariant(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req CreateVariantRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	variant, err := h.service.CreateVariant(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrProductNotFound(err).With("productId", req.ProductID))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	variantResponse := ToVariantResponse(variant)
+	response.SuccessJSON(c, http.StatusCreated, variantResponse)
+}
+
+// BulkUpdateVariants applies price and stock changes to many variants atomically.
+//
+// @Summary      Bulk update variants
+// @Description  Sets sale/cost prices and adjusts default-location stock of many variants in one transaction; if any change is invalid nothing is updated
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body BulkUpdateVariantsRequest true "Changes"
+// @Success      200 {array} inventory.VariantResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      422 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/variants/bulk [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) BulkUpdateVariants(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req BulkUpdateVariantsRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	variants, err := h.service.BulkUpdateVariants(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToVariantResponses(variants))
+}
+
+// UpdateVariant updates a variant.
+//
+// @Summary      Update variant
+// @Description  Updates a variant
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        variantId path string true "Variant ID"
+// @Param        body body UpdateVariantRequest true "Updates"
+// @Success      200 {object} inventory.VariantResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/variants/{variantId} [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdateVariant(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("variantId")
+	var req UpdateVariantRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.service.UpdateVariant(c.Request.Context(), actor, biz, id, &req); err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrVariantNotFound(err).With("variantId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	updated, err := h.service.GetVariantByID(c.Request.Context(), actor, biz, id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	variantResponse := ToVariantResponse(updated)
+	response.SuccessJSON(c, http.StatusOK, variantResponse)
+}
+
+// DeleteVariant deletes a variant.
+//
+// @Summary      Delete variant
+// @Description  Deletes a variant
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        variantId path string true "Variant ID"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/variants/{variantId} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteVariant(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -830,14 +1350,15 @@ func (h *HttpHandler) DeleteCategory(c *gin.Context) {
 }
 
 type inventorySummaryResponse struct {
-	ProductsCount           int64             `json:"productsCount"`
-	VariantsCount           int64             `json:"variantsCount"`
-	CategoriesCount         int64             `json:"categoriesCount"`
-	LowStockVariantsCount   int64             `json:"lowStockVariantsCount"`
-	OutOfStockVariantsCount int64             `json:"outOfStockVariantsCount"`
-	TotalStockUnits         int64             `json:"totalStockUnits"`
-	InventoryValue          decimal.Decimal   `json:"inventoryValue"`
-	TopProducts             []ProductResponse `json:"topProductsByInventoryValue"`
+	ProductsCount           int64                          `json:"productsCount"`
+	VariantsCount           int64                          `json:"variantsCount"`
+	CategoriesCount         int64                          `json:"categoriesCount"`
+	LowStockVariantsCount   int64                          `json:"lowStockVariantsCount"`
+	OutOfStockVariantsCount int64                          `json:"outOfStockVariantsCount"`
+	TotalStockUnits         int64                          `json:"totalStockUnits"`
+	InventoryValue          decimal.Decimal                `json:"inventoryValue"`
+	TopProducts             []ProductResponse              `json:"topProductsByInventoryValue"`
+	Locations               []LocationStockSummaryResponse `json:"locations"`
 }
 
 // GetInventorySummary returns inventory summary metrics.
@@ -919,6 +1440,11 @@ func (h *HttpHandler) GetInventorySummary(c *gin.Context) {
 	for i, tp := range topProducts {
 		topProductResponses[i] = tp.Product
 	}
+	locationTotals, err := h.service.ListLocationStockTotals(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
 	resp := inventorySummaryResponse{
 		ProductsCount:           productsCount,
 		VariantsCount:           variantsCount,
@@ -928,6 +1454,7 @@ func (h *HttpHandler) GetInventorySummary(c *gin.Context) {
 		TotalStockUnits:         totalUnits,
 		InventoryValue:          invValue,
 		TopProducts:             topProductResponses,
+		Locations:               ToLocationStockSummaryResponses(locationTotals),
 	}
 	response.SuccessJSON(c, http.StatusOK, resp)
 }
@@ -972,3 +1499,2165 @@ func (h *HttpHandler) GetTopProductsByInventoryValue(c *gin.Context) {
 	}
 	response.SuccessJSON(c, http.StatusOK, items)
 }
+
+type reorderSuggestionsQuery struct {
+	Days         int    `form:"days" binding:"omitempty,min=1,max=365"`
+	LeadTimeDays *int   `form:"leadTimeDays" binding:"omitempty,min=0,max=365"`
+	CoverDays    *int   `form:"coverDays" binding:"omitempty,min=0,max=365"`
+	SupplierID   string `form:"supplierId" binding:"omitempty"`
+	RunsOut      bool   `form:"runsOut"`
+	Limit        int    `form:"limit" binding:"omitempty,min=1,max=200"`
+}
+
+// GetReorderSuggestions returns the ranked purchase list.
+//
+// @Summary      Reorder suggestions
+// @Description  Ranks variants that need repurchasing by days of stock left at their recent sales velocity, with a suggested quantity covering the supplier lead time, the cover period and the reorder point, rounded up to whole reorder lots. Variants with their own lead time, reorder point or reorder quantity use them over the defaults
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        days query int false "Sales history window in days (default: 30, max: 365)"
+// @Param        leadTimeDays query int false "Supplier lead time in days for variants without their own (default: 14)"
+// @Param        coverDays query int false "Days the reordered stock should last after delivery (default: 30)"
+// @Param        supplierId query string false "Only variants bought from this preferred supplier"
+// @Param        runsOut query bool false "Only variants that sell out before a reorder placed today arrives"
+// @Param        limit query int false "Maximum suggestions (default: 50, max: 200)"
+// @Success      200 {object} inventory.ReorderSuggestionsResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/reorder-suggestions [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetReorderSuggestions(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query reorderSuggestionsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	opts := &ReorderSuggestionsOptions{WindowDays: 30, LeadTimeDays: 14, CoverDays: 30, SupplierID: query.SupplierID, RunsOutOnly: query.RunsOut, Limit: 50}
+	if query.Days > 0 {
+		opts.WindowDays = query.Days
+	}
+	if query.LeadTimeDays != nil {
+		opts.LeadTimeDays = *query.LeadTimeDays
+	}
+	if query.CoverDays != nil {
+		opts.CoverDays = *query.CoverDays
+	}
+	if query.Limit > 0 {
+		opts.Limit = query.Limit
+	}
+	items, err := h.service.ListReorderSuggestions(c.Request.Context(), actor, biz, opts)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToReorderSuggestionsResponse(opts, items))
+}
+
+type stockValuationQuery struct {
+	AsOf time.Time `form:"asOf" time_format:"2006-01-02T15:04:05Z07:00" binding:"omitempty"`
+}
+
+// GetStockValuation returns the inventory value as of a past date.
+//
+// @Summary      Historical inventory valuation
+// @Description  Values the inventory as of a date (e.g. a month close): the stock each variant held then, replayed from the stock movements recorded since, at the cost price in effect then. Defaults to now.
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        asOf query string false "RFC3339 valuation date, not in the future (default: now)"
+// @Success      200 {object} inventory.StockValuationResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/valuation [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetStockValuation(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query stockValuationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	now := time.Now().UTC()
+	asOf := query.AsOf
+	if asOf.IsZero() {
+		asOf = now
+	}
+	if asOf.After(now) {
+		response.Error(c, problem.BadRequest("asOf must not be in the future").With("field", "asOf"))
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	valuation, err := h.service.GetStockValuation(c.Request.Context(), actor, biz, asOf)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToStockValuationResponse(valuation))
+}
+
+type variantCostTrendQuery struct {
+	From time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00" binding:"omitempty"`
+	To   time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" binding:"omitempty"`
+}
+
+// GetVariantCostTrend returns a variant's price history and realized margin per month.
+//
+// @Summary      Variant cost trend
+// @Description  Returns the cost and sale price changes of a variant and its monthly sales with the margin earned at the unit cost snapshotted when each order was placed. Defaults to the last 12 months.
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        variantId path string true "Variant ID"
+// @Param        from query string false "RFC3339 start of the window (default: 12 months ago)"
+// @Param        to query string false "RFC3339 end of the window (default: now)"
+// @Success      200 {object} inventory.VariantCostTrendResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/variants/{variantId}/cost-trend [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetVariantCostTrend(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query variantCostTrendQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	to := query.To
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	from := query.From
+	if from.IsZero() {
+		from = to.AddDate(-1, 0, 0)
+	}
+	if !from.Before(to) {
+		response.Error(c, problem.BadRequest("from must be before to").With("field", "from"))
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("variantId")
+	trend, err := h.service.GetVariantCostTrend(c.Request.Context(), actor, biz, id, from, to)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrVariantNotFound(err).With("variantId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToVariantCostTrendResponse(trend))
+}
+
+// ListPriceLists returns all price lists.
+//
+// @Summary      List price lists
+// @Description  Returns all price lists of the business, without entries
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {array} inventory.PriceListResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/price-lists [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListPriceLists(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	items, err := h.service.ListPriceLists(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToPriceListResponses(items))
+}
+
+// GetPriceList returns a price list by ID with its entries.
+//
+// @Summary      Get price list
+// @Description  Returns a price list by ID including its per-variant prices
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        priceListId path string true "Price list ID"
+// @Success      200 {object} inventory.PriceListResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/price-lists/{priceListId} [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetPriceList(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("priceListId")
+	pl, err := h.service.GetPriceListByID(c.Request.Context(), actor, biz, id)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrPriceListNotFound(err).With("priceListId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToPriceListResponse(pl))
+}
+
+// CreatePriceList creates a price list.
+//
+// @Summary      Create price list
+// @Description  Creates a price list with optional per-variant prices
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body CreatePriceListRequest true "Price list"
+// @Success      201 {object} inventory.PriceListResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/price-lists [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CreatePriceList(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req CreatePriceListRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	pl, err := h.service.CreatePriceList(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, ToPriceListResponse(pl))
+}
+
+// UpdatePriceList updates a price list.
+//
+// @Summary      Update price list
+// @Description  Updates a price list; entries, when provided, replace all existing entries
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        priceListId path string true "Price list ID"
+// @Param        body body UpdatePriceListRequest true "Updates"
+// @Success      200 {object} inventory.PriceListResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/price-lists/{priceListId} [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdatePriceList(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("priceListId")
+	var req UpdatePriceListRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	pl, err := h.service.UpdatePriceList(c.Request.Context(), actor, biz, id, &req)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrPriceListNotFound(err).With("priceListId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToPriceListResponse(pl))
+}
+
+// DeletePriceList deletes a price list.
+//
+// @Summary      Delete price list
+// @Description  Deletes a price list; assigned customers fall back to the default price list
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        priceListId path string true "Price list ID"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/price-lists/{priceListId} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeletePriceList(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("priceListId")
+	if err := h.service.DeletePriceList(c.Request.Context(), actor, biz, id); err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrPriceListNotFound(err).With("priceListId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// StartFlashSale puts every variant of a category on sale until a given time.
+//
+// @Summary      Start flash sale
+// @Description  Sets a time-bound promo price percentOff below salePrice on every variant of the category, replacing existing promos
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        categoryId path string true "Category ID"
+// @Param        body body FlashSaleRequest true "Discount and sale window"
+// @Success      200 {object} inventory.FlashSaleResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/categories/{categoryId}/flash-sale [post]
+// @Security     BearerAuth
+func (h *HttpHandler) StartFlashSale(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("categoryId")
+	cat, err := h.service.GetCategoryByID(c.Request.Context(), actor, biz, id)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrCategoryNotFound(err).With("categoryId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	var req FlashSaleRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	variants, err := h.service.StartFlashSale(c.Request.Context(), actor, biz, cat, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, FlashSaleResponse{
+		CategoryID: cat.ID,
+		Variants:   ToVariantResponses(variants),
+	})
+}
+
+// EndFlashSale clears the promo price of every variant of a category.
+//
+// @Summary      End flash sale
+// @Description  Removes the promo price from every variant of the category
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        categoryId path string true "Category ID"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/categories/{categoryId}/flash-sale [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) EndFlashSale(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("categoryId")
+	cat, err := h.service.GetCategoryByID(c.Request.Context(), actor, biz, id)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrCategoryNotFound(err).With("categoryId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	if _, err := h.service.EndFlashSale(c.Request.Context(), actor, biz, cat); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// supplierResponse loads what the business owes the supplier for its response.
+func (h *HttpHandler) supplierResponse(c *gin.Context, actor *account.User, biz *business.Business, sup *Supplier) (SupplierResponse, error) {
+	payables, err := h.service.SumSupplierPayables(c.Request.Context(), actor, biz)
+	if err != nil {
+		return SupplierResponse{}, err
+	}
+	return ToSupplierResponse(sup, payables[sup.ID]), nil
+}
+
+// ListSuppliers returns all suppliers.
+//
+// @Summary      List suppliers
+// @Description  Returns all suppliers of the business with what is owed to each for received and unpaid goods
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {array} inventory.SupplierResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/suppliers [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListSuppliers(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	items, err := h.service.ListSuppliers(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	payables, err := h.service.SumSupplierPayables(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToSupplierResponses(items, payables))
+}
+
+// GetSupplier returns a supplier by ID.
+//
+// @Summary      Get supplier
+// @Description  Returns a supplier by ID with what is owed to it for received and unpaid goods
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        supplierId path string true "Supplier ID"
+// @Success      200 {object} inventory.SupplierResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/suppliers/{supplierId} [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetSupplier(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("supplierId")
+	sup, err := h.service.GetSupplierByID(c.Request.Context(), actor, biz, id)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrSupplierNotFound(err).With("supplierId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	resp, err := h.supplierResponse(c, actor, biz, sup)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, resp)
+}
+
+// CreateSupplier creates a supplier.
+//
+// @Summary      Create supplier
+// @Description  Creates a supplier the business buys stock from
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body CreateSupplierRequest true "Supplier"
+// @Success      201 {object} inventory.SupplierResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/suppliers [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CreateSupplier(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req CreateSupplierRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	sup, err := h.service.CreateSupplier(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, ToSupplierResponse(sup, decimal.Zero))
+}
+
+// UpdateSupplier updates a supplier.
+//
+// @Summary      Update supplier
+// @Description  Updates a supplier; omitted fields are kept
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        supplierId path string true "Supplier ID"
+// @Param        body body UpdateSupplierRequest true "Updates"
+// @Success      200 {object} inventory.SupplierResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/suppliers/{supplierId} [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdateSupplier(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("supplierId")
+	var req UpdateSupplierRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	sup, err := h.service.UpdateSupplier(c.Request.Context(), actor, biz, id, &req)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrSupplierNotFound(err).With("supplierId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	resp, err := h.supplierResponse(c, actor, biz, sup)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, resp)
+}
+
+// DeleteSupplier deletes a supplier.
+//
+// @Summary      Delete supplier
+// @Description  Deletes a supplier with no goods still expected and nothing owed to it
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        supplierId path string true "Supplier ID"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/suppliers/{supplierId} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteSupplier(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("supplierId")
+	if err := h.service.DeleteSupplier(c.Request.Context(), actor, biz, id); err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrSupplierNotFound(err).With("supplierId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+type listPurchaseOrdersQuery struct {
+	Page       int      `form:"page" binding:"omitempty,min=1"`
+	PageSize   int      `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	OrderBy    []string `form:"orderBy" binding:"omitempty"`
+	SupplierID string   `form:"supplierId" binding:"omitempty"`
+	Status     string   `form:"status" binding:"omitempty,oneof=ordered partially_received received cancelled"`
+}
+
+// ListPurchaseOrders returns a paginated list of purchase orders.
+//
+// @Summary      List purchase orders
+// @Description  Returns a paginated list of purchase orders, newest first, without items
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        page query int false "Page number (default: 1)"
+// @Param        pageSize query int false "Page size (default: 20, max: 100)"
+// @Param        orderBy query []string false "Sort order (e.g., -orderedAt, expectedAt)"
+// @Param        supplierId query string false "Filter by supplier ID"
+// @Param        status query string false "Filter by status (ordered, partially_received, received, cancelled)"
+// @Success      200 {object} list.ListResponse[inventory.PurchaseOrderResponse]
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/purchase-orders [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListPurchaseOrders(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query listPurchaseOrdersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+	listReq := list.NewListRequest(query.Page, query.PageSize, query.OrderBy, "")
+	items, total, err := h.service.ListPurchaseOrders(c.Request.Context(), actor, biz, listReq, &ListPurchaseOrdersFilters{
+		SupplierID: query.SupplierID,
+		Status:     PurchaseOrderStatus(query.Status),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	hasMore := int64(query.Page*query.PageSize) < total
+	response.SuccessJSON(c, http.StatusOK, list.NewListResponse(ToPurchaseOrderResponses(items), query.Page, query.PageSize, total, hasMore))
+}
+
+// GetPurchaseOrder returns a purchase order by ID with its items.
+//
+// @Summary      Get purchase order
+// @Description  Returns a purchase order by ID including its items and received quantities
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        purchaseOrderId path string true "Purchase order ID"
+// @Success      200 {object} inventory.PurchaseOrderResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/purchase-orders/{purchaseOrderId} [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetPurchaseOrder(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("purchaseOrderId")
+	po, err := h.service.GetPurchaseOrderByID(c.Request.Context(), actor, biz, id)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrPurchaseOrderNotFound(err).With("purchaseOrderId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToPurchaseOrderResponse(po))
+}
+
+// ListPurchaseOrderReceipts returns the goods received against a purchase order.
+//
+// @Summary      List purchase order receipts
+// @Description  Returns every receipt of goods against the purchase order, oldest first
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        purchaseOrderId path string true "Purchase order ID"
+// @Success      200 {array} inventory.PurchaseOrderReceiptResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/purchase-orders/{purchaseOrderId}/receipts [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListPurchaseOrderReceipts(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("purchaseOrderId")
+	po, err := h.service.GetPurchaseOrderByID(c.Request.Context(), actor, biz, id)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrPurchaseOrderNotFound(err).With("purchaseOrderId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	receipts, err := h.service.ListPurchaseOrderReceipts(c.Request.Context(), actor, biz, po)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToPurchaseOrderReceiptResponses(receipts))
+}
+
+// CreatePurchaseOrder places a purchase order with a supplier.
+//
+// @Summary      Create purchase order
+// @Description  Places an order for variants with a supplier at agreed unit costs; stock is added when goods are received
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body CreatePurchaseOrderRequest true "Purchase order"
+// @Success      201 {object} inventory.PurchaseOrderResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/purchase-orders [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CreatePurchaseOrder(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req CreatePurchaseOrderRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	po, err := h.service.CreatePurchaseOrder(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, ToPurchaseOrderResponse(po))
+}
+
+// UpdatePurchaseOrder updates the details of a purchase order.
+//
+// @Summary      Update purchase order
+// @Description  Updates the reference, expected delivery and notes of a purchase order; omitted fields are kept
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        purchaseOrderId path string true "Purchase order ID"
+// @Param        body body UpdatePurchaseOrderRequest true "Updates"
+// @Success      200 {object} inventory.PurchaseOrderResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/purchase-orders/{purchaseOrderId} [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdatePurchaseOrder(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("purchaseOrderId")
+	var req UpdatePurchaseOrderRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	po, err := h.service.UpdatePurchaseOrder(c.Request.Context(), actor, biz, id, &req)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrPurchaseOrderNotFound(err).With("purchaseOrderId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToPurchaseOrderResponse(po))
+}
+
+// ReceivePurchaseOrder receives goods against a purchase order.
+//
+// @Summary      Receive purchase order goods
+// @Description  Records units that arrived for purchase order items and adds them to stock; orders can be received over several receipts
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        purchaseOrderId path string true "Purchase order ID"
+// @Param        body body ReceivePurchaseOrderRequest true "Received items"
+// @Success      200 {object} inventory.PurchaseOrderResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/purchase-orders/{purchaseOrderId}/receive [post]
+// @Security     BearerAuth
+func (h *HttpHandler) ReceivePurchaseOrder(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("purchaseOrderId")
+	var req ReceivePurchaseOrderRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	po, err := h.service.ReceivePurchaseOrder(c.Request.Context(), actor, biz, id, &req)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrPurchaseOrderNotFound(err).With("purchaseOrderId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToPurchaseOrderResponse(po))
+}
+
+// RecordPurchaseOrderPayment records a payment to the supplier of a purchase order.
+//
+// @Summary      Record purchase order payment
+// @Description  Records a payment to the supplier; payments reduce what is owed for received goods and cannot exceed the order total
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        purchaseOrderId path string true "Purchase order ID"
+// @Param        body body PurchaseOrderPaymentRequest true "Payment"
+// @Success      200 {object} inventory.PurchaseOrderResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/purchase-orders/{purchaseOrderId}/payments [post]
+// @Security     BearerAuth
+func (h *HttpHandler) RecordPurchaseOrderPayment(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("purchaseOrderId")
+	var req PurchaseOrderPaymentRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	po, err := h.service.RecordPurchaseOrderPayment(c.Request.Context(), actor, biz, id, &req)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrPurchaseOrderNotFound(err).With("purchaseOrderId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToPurchaseOrderResponse(po))
+}
+
+// CancelPurchaseOrder cancels a purchase order nothing was received against.
+//
+// @Summary      Cancel purchase order
+// @Description  Cancels a purchase order that has not received any goods
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        purchaseOrderId path string true "Purchase order ID"
+// @Success      200 {object} inventory.PurchaseOrderResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/purchase-orders/{purchaseOrderId}/cancel [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CancelPurchaseOrder(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("purchaseOrderId")
+	po, err := h.service.CancelPurchaseOrder(c.Request.Context(), actor, biz, id)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrPurchaseOrderNotFound(err).With("purchaseOrderId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToPurchaseOrderResponse(po))
+}
+
+type listSupplierBillsQuery struct {
+	Page            int      `form:"page" binding:"omitempty,min=1"`
+	PageSize        int      `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	OrderBy         []string `form:"orderBy" binding:"omitempty"`
+	SupplierID      string   `form:"supplierId" binding:"omitempty"`
+	PurchaseOrderID string   `form:"purchaseOrderId" binding:"omitempty"`
+	PaymentStatus   string   `form:"paymentStatus" binding:"omitempty,oneof=unpaid partially_paid paid"`
+	Overdue         bool     `form:"overdue" binding:"omitempty"`
+}
+
+// ListSupplierBills returns a paginated list of supplier bills.
+//
+// @Summary      List supplier bills
+// @Description  Returns a paginated list of bills sent by suppliers, earliest due first
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        page query int false "Page number (default: 1)"
+// @Param        pageSize query int false "Page size (default: 20, max: 100)"
+// @Param        orderBy query []string false "Sort order (e.g., dueAt, -issuedAt, -amount)"
+// @Param        supplierId query string false "Filter by supplier ID"
+// @Param        purchaseOrderId query string false "Filter by purchase order ID"
+// @Param        paymentStatus query string false "Filter by payment status (unpaid, partially_paid, paid)"
+// @Param        overdue query bool false "Only bills not paid in full past their due date"
+// @Success      200 {object} list.ListResponse[inventory.SupplierBillResponse]
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/supplier-bills [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListSupplierBills(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query listSupplierBillsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+	listReq := list.NewListRequest(query.Page, query.PageSize, query.OrderBy, "")
+	items, total, err := h.service.ListSupplierBills(c.Request.Context(), actor, biz, listReq, &ListSupplierBillsFilters{
+		SupplierID:      query.SupplierID,
+		PurchaseOrderID: query.PurchaseOrderID,
+		PaymentStatus:   SupplierBillPaymentStatus(query.PaymentStatus),
+		Overdue:         query.Overdue,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	hasMore := int64(query.Page*query.PageSize) < total
+	response.SuccessJSON(c, http.StatusOK, list.NewListResponse(ToSupplierBillResponses(items), query.Page, query.PageSize, total, hasMore))
+}
+
+// GetSupplierBill returns a supplier bill by ID.
+//
+// @Summary      Get supplier bill
+// @Description  Returns a supplier bill by ID with what is still owed on it
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        billId path string true "Supplier bill ID"
+// @Success      200 {object} inventory.SupplierBillResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/supplier-bills/{billId} [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetSupplierBill(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("billId")
+	bill, err := h.service.GetSupplierBillByID(c.Request.Context(), actor, biz, id)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrSupplierBillNotFound(err).With("billId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToSupplierBillResponse(bill))
+}
+
+// CreateSupplierBill records a bill sent by a supplier.
+//
+// @Summary      Create supplier bill
+// @Description  Records a bill sent by a supplier, payable by its due date; a bill for a purchase order cannot take the order's bills past its total and starts with the payments already made on the order
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body CreateSupplierBillRequest true "Supplier bill"
+// @Success      201 {object} inventory.SupplierBillResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/supplier-bills [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CreateSupplierBill(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req CreateSupplierBillRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	bill, err := h.service.CreateSupplierBill(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, ToSupplierBillResponse(bill))
+}
+
+// UpdateSupplierBill updates a supplier bill.
+//
+// @Summary      Update supplier bill
+// @Description  Updates the reference, dates, amount or notes of a supplier bill; the amount cannot go below what was paid
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        billId path string true "Supplier bill ID"
+// @Param        body body UpdateSupplierBillRequest true "Supplier bill fields"
+// @Success      200 {object} inventory.SupplierBillResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/supplier-bills/{billId} [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdateSupplierBill(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("billId")
+	var req UpdateSupplierBillRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	bill, err := h.service.UpdateSupplierBill(c.Request.Context(), actor, biz, id, &req)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrSupplierBillNotFound(err).With("billId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToSupplierBillResponse(bill))
+}
+
+// DeleteSupplierBill deletes a supplier bill no payment was recorded on.
+//
+// @Summary      Delete supplier bill
+// @Description  Deletes a supplier bill; bills with recorded payments cannot be deleted
+// @Tags         inventory
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        billId path string true "Supplier bill ID"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/supplier-bills/{billId} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteSupplierBill(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("billId")
+	if err := h.service.DeleteSupplierBill(c.Request.Context(), actor, biz, id); err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrSupplierBillNotFound(err).With("billId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// RecordSupplierBillPayment records a payment against a supplier bill.
+//
+// @Summary      Record supplier bill payment
+// @Description  Records a payment to the supplier against a bill; payments cannot exceed what is owed on the bill and are paid on its purchase order too
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        billId path string true "Supplier bill ID"
+// @Param        body body SupplierBillPaymentRequest true "Payment"
+// @Success      200 {object} inventory.SupplierBillResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/supplier-bills/{billId}/payments [post]
+// @Security     BearerAuth
+func (h *HttpHandler) RecordSupplierBillPayment(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("billId")
+	var req SupplierBillPaymentRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	bill, err := h.service.RecordSupplierBillPayment(c.Request.Context(), actor, biz, id, &req)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrSupplierBillNotFound(err).With("billId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToSupplierBillResponse(bill))
+}
+
+// ListSupplierBillPayments returns the payments recorded on a supplier bill.
+//
+// @Summary      List supplier bill payments
+// @Description  Returns every payment recorded on the bill, oldest first
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        billId path string true "Supplier bill ID"
+// @Success      200 {array} inventory.SupplierBillPaymentResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/supplier-bills/{billId}/payments [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListSupplierBillPayments(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("billId")
+	bill, err := h.service.GetSupplierBillByID(c.Request.Context(), actor, biz, id)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrSupplierBillNotFound(err).With("billId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	payments, err := h.service.ListSupplierBillPayments(c.Request.Context(), actor, biz, bill)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToSupplierBillPaymentResponses(payments))
+}
+
+type supplierBillAgingQuery struct {
+	AsOf time.Time `form:"asOf" time_format:"2006-01-02T15:04:05Z07:00" binding:"omitempty"`
+}
+
+// GetSupplierBillAging returns what is owed on supplier bills by how long it is overdue.
+//
+// @Summary      Supplier bills aging
+// @Description  Splits what is owed on supplier bills by days past due (current, 1-30, 31-60, 61-90, over 90), in total and per supplier, with what is owed on purchase orders not billed yet. Defaults to now.
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        asOf query string false "RFC3339 aging date (default: now)"
+// @Success      200 {object} inventory.SupplierBillAgingResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/supplier-bills/aging [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetSupplierBillAging(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query supplierBillAgingQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	asOf := query.AsOf
+	if asOf.IsZero() {
+		asOf = time.Now().UTC()
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	aging, err := h.service.ComputeSupplierBillAging(c.Request.Context(), actor, biz, asOf)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToSupplierBillAgingResponse(aging))
+}
+
+// ListLocations returns all stock locations.
+//
+// @Summary      List locations
+// @Description  Returns all stock locations of the business, the default location first
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {array} inventory.LocationResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/locations [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListLocations(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	items, err := h.service.ListLocations(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToLocationResponses(items))
+}
+
+// GetLocation returns a stock location by ID.
+//
+// @Summary      Get location
+// @Description  Returns a stock location by ID
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        locationId path string true "Location ID"
+// @Success      200 {object} inventory.LocationResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/locations/{locationId} [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetLocation(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("locationId")
+	loc, err := h.service.GetLocationByID(c.Request.Context(), actor, biz, id)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrLocationNotFound(err).With("locationId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToLocationResponse(loc))
+}
+
+// CreateLocation creates a stock location.
+//
+// @Summary      Create location
+// @Description  Creates a stock location. The first location becomes the default and takes over the current stock of every variant
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body CreateLocationRequest true "Location"
+// @Success      201 {object} inventory.LocationResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/locations [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CreateLocation(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req CreateLocationRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	loc, err := h.service.CreateLocation(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, ToLocationResponse(loc))
+}
+
+// UpdateLocation updates a stock location.
+//
+// @Summary      Update location
+// @Description  Renames a stock location or makes it the default
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        locationId path string true "Location ID"
+// @Param        body body UpdateLocationRequest true "Updates"
+// @Success      200 {object} inventory.LocationResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/locations/{locationId} [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdateLocation(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("locationId")
+	var req UpdateLocationRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	loc, err := h.service.UpdateLocation(c.Request.Context(), actor, biz, id, &req)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrLocationNotFound(err).With("locationId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToLocationResponse(loc))
+}
+
+// DeleteLocation deletes an empty stock location.
+//
+// @Summary      Delete location
+// @Description  Deletes a stock location that holds no stock; the default location can only be deleted when it is the last one
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        locationId path string true "Location ID"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/locations/{locationId} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteLocation(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("locationId")
+	if err := h.service.DeleteLocation(c.Request.Context(), actor, biz, id); err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrLocationNotFound(err).With("locationId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// TransferLocationStock moves stock between two locations.
+//
+// @Summary      Transfer stock
+// @Description  Moves units of one or more variants from one location to another; variant totals are unchanged
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body LocationTransferRequest true "Transfer"
+// @Success      204
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/locations/transfers [post]
+// @Security     BearerAuth
+func (h *HttpHandler) TransferLocationStock(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req LocationTransferRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.service.TransferLocationStock(c.Request.Context(), actor, biz, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// ListVariantLocationStock returns the stock of a variant at each location.
+//
+// @Summary      Variant stock by location
+// @Description  Returns the units of a variant kept at each location, including locations that hold none
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        variantId path string true "Variant ID"
+// @Success      200 {array} inventory.LocationStockLevelResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/variants/{variantId}/locations [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListVariantLocationStock(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("variantId")
+	levels, err := h.service.ListVariantLocationStock(c.Request.Context(), actor, biz, id)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrVariantNotFound(err).With("variantId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToLocationStockLevelResponses(levels))
+}
+
+// SetVariantLocationStock sets the stock of a variant at a location.
+//
+// @Summary      Set variant stock at location
+// @Description  Sets the units of a variant kept at a location; the variant's total stock changes by the difference
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        variantId path string true "Variant ID"
+// @Param        locationId path string true "Location ID"
+// @Param        body body SetLocationStockRequest true "Quantity"
+// @Success      200 {array} inventory.LocationStockLevelResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/variants/{variantId}/locations/{locationId} [put]
+// @Security     BearerAuth
+func (h *HttpHandler) SetVariantLocationStock(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	variantID := c.Param("variantId")
+	locationID := c.Param("locationId")
+	var req SetLocationStockRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.service.SetLocationStock(c.Request.Context(), actor, biz, locationID, variantID, *req.Quantity); err != nil {
+		response.Error(c, err)
+		return
+	}
+	levels, err := h.service.ListVariantLocationStock(c.Request.Context(), actor, biz, variantID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToLocationStockLevelResponses(levels))
+}
+
+type importCatalogQuery struct {
+	DryRun bool `form:"dryRun"`
+}
+
+// ImportCatalog imports products and variants from a catalog CSV.
+//
+// @Summary      Import catalog CSV
+// @Description  Creates and updates products and variants from a CSV with one row per variant. Columns: productName, productDescription, category, variantCode, sku, barcode, costPrice, salePrice, stockQuantity, stockQuantityAlert (currency is ignored; variants use the business currency). productName, category, variantCode, costPrice, salePrice and stockQuantity are required. Products are matched by name and variants by code; missing categories are created. The whole file is validated first: with dryRun=true the result (including row-level errors) is returned without writing anything; otherwise any invalid row rejects the import with 422 and the errors, and a valid file is applied in one transaction.
+// @Tags         inventory
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        file formData file true "Catalog CSV"
+// @Param        dryRun query bool false "Only validate the file"
+// @Success      200 {object} inventory.CatalogImportResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      413 {object} problem.Problem
+// @Failure      422 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/import [post]
+// @Security     BearerAuth
+func (h *HttpHandler) ImportCatalog(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query importCatalogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	fh, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.Error(c, problem.PayloadTooLarge("request body too large").WithCode("request.body_too_large"))
+			return
+		}
+		response.Error(c, problem.BadRequest("catalog file is required").With("field", "file").WithError(err))
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+	result, err := h.service.ImportCatalog(c.Request.Context(), actor, biz, data, query.DryRun)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToCatalogImportResponse(result))
+}
+
+// ExportCatalog downloads the catalog as CSV.
+//
+// @Summary      Export catalog CSV
+// @Description  Returns every variant of the business as a catalog CSV (one row per variant, ordered by product name and variant code) in the format accepted by the import endpoint.
+// @Tags         inventory
+// @Produce      text/csv
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {file} file
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/export [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ExportCatalog(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	data, err := h.service.ExportCatalog(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessFile(c, http.StatusOK, "text/csv", biz.Descriptor+"-catalog.csv", data)
+}
+
+type listStocktakesQuery struct {
+	Page       int      `form:"page" binding:"omitempty,min=1"`
+	PageSize   int      `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	OrderBy    []string `form:"orderBy" binding:"omitempty"`
+	LocationID string   `form:"locationId" binding:"omitempty"`
+	Status     string   `form:"status" binding:"omitempty,oneof=counting approved cancelled"`
+}
+
+// ListStocktakes returns a paginated list of stocktakes.
+//
+// @Summary      List stocktakes
+// @Description  Returns a paginated list of stocktakes, newest first, without lines
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        page query int false "Page number (default: 1)"
+// @Param        pageSize query int false "Page size (default: 20, max: 100)"
+// @Param        orderBy query []string false "Sort order (e.g., -startedAt)"
+// @Param        locationId query string false "Filter by location ID"
+// @Param        status query string false "Filter by status (counting, approved, cancelled)"
+// @Success      200 {object} list.ListResponse[inventory.StocktakeResponse]
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/stocktakes [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListStocktakes(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query listStocktakesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+	listReq := list.NewListRequest(query.Page, query.PageSize, query.OrderBy, "")
+	items, total, err := h.service.ListStocktakes(c.Request.Context(), actor, biz, listReq, &ListStocktakesFilters{
+		LocationID: query.LocationID,
+		Status:     StocktakeStatus(query.Status),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	hasMore := int64(query.Page*query.PageSize) < total
+	response.SuccessJSON(c, http.StatusOK, list.NewListResponse(ToStocktakeResponses(items), query.Page, query.PageSize, total, hasMore))
+}
+
+// GetStocktake returns a stocktake by ID with its lines.
+//
+// @Summary      Get stocktake
+// @Description  Returns a stocktake by ID including every line with its expected and counted quantities, ordered by variant name
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        stocktakeId path string true "Stocktake ID"
+// @Success      200 {object} inventory.StocktakeResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/stocktakes/{stocktakeId} [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetStocktake(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("stocktakeId")
+	st, err := h.service.GetStocktakeByID(c.Request.Context(), actor, biz, id)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrStocktakeNotFound(err).With("stocktakeId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToStocktakeResponse(st))
+}
+
+// GetStocktakeVariances returns the variance report of a stocktake.
+//
+// @Summary      Get stocktake variance report
+// @Description  Summarizes counted, uncounted and differing lines with units and value over and short at the snapshot cost; variances lists the counted lines that differ, largest value first
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        stocktakeId path string true "Stocktake ID"
+// @Success      200 {object} inventory.StocktakeVarianceReportResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/stocktakes/{stocktakeId}/variances [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetStocktakeVariances(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	id := c.Param("stocktakeId")
+	report, err := h.service.GetStocktakeVarianceReport(c.Request.Context(), actor, biz, id)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrStocktakeNotFound(err).With("stocktakeId", id))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToStocktakeVarianceReportResponse(report))
+}
+
+// CreateStocktake starts a stocktake.
+//
+// @Summary      Start stocktake
+// @Description  Snapshots the expected quantity of every variant to count at the location (the default location when omitted; variant totals for businesses without locations). Without categoryId or variantIds every variant is counted. A location has at most one stocktake counting at a time.
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body CreateStocktakeRequest true "Stocktake"
+// @Success      201 {object} inventory.StocktakeResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/stocktakes [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CreateStocktake(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req CreateStocktakeRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	st, err := h.service.CreateStocktake(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, ToStocktakeResponse(st))
+}
+
+// RecordStocktakeCounts sets counted quantities entered by hand.
+//
+// @Summary      Record stocktake counts
+// @Description  Sets the counted quantity of each variant, replacing earlier counts; returns the updated lines
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        stocktakeId path string true "Stocktake ID"
+// @Param        body body RecordStocktakeCountsRequest true "Counts"
+// @Success      200 {array} inventory.StocktakeLineResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/stocktakes/{stocktakeId}/counts [put]
+// @Security     BearerAuth
+func (h *HttpHandler) RecordStocktakeCounts(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req RecordStocktakeCountsRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	lines, err := h.service.RecordStocktakeCounts(c.Request.Context(), actor, biz, c.Param("stocktakeId"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToStocktakeLineResponses(lines))
+}
+
+// ScanStocktakeItem counts a scanned item.
+//
+// @Summary      Scan stocktake item
+// @Description  Adds quantity (default 1) to the count of the variant whose barcode or SKU equals code; returns its line with the running count
+// @Tags         inventory
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        stocktakeId path string true "Stocktake ID"
+// @Param        body body ScanStocktakeRequest true "Scan"
+// @Success      200 {object} inventory.StocktakeLineResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/stocktakes/{stocktakeId}/scan [post]
+// @Security     BearerAuth
+func (h *HttpHandler) ScanStocktakeItem(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req ScanStocktakeRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	line, err := h.service.ScanStocktakeItem(c.Request.Context(), actor, biz, c.Param("stocktakeId"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToStocktakeLineResponse(line))
+}
+
+// ApproveStocktake posts a stocktake's variances to stock.
+//
+// @Summary      Approve stocktake
+// @Description  Adds the variance (counted - expected) of every counted line to the stock at the stocktake's location, keeping sales and receipts made while counting. Uncounted lines are left alone.
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        stocktakeId path string true "Stocktake ID"
+// @Success      200 {object} inventory.StocktakeResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/stocktakes/{stocktakeId}/approve [post]
+// @Security     BearerAuth
+func (h *HttpHandler) ApproveStocktake(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	st, err := h.service.ApproveStocktake(c.Request.Context(), actor, biz, c.Param("stocktakeId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToStocktakeResponse(st))
+}
+
+// CancelStocktake abandons a stocktake.
+//
+// @Summary      Cancel stocktake
+// @Description  Cancels a stocktake that is still counting; stock is not changed
+// @Tags         inventory
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        stocktakeId path string true "Stocktake ID"
+// @Success      200 {object} inventory.StocktakeResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/inventory/stocktakes/{stocktakeId}/cancel [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CancelStocktake(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	st, err := h.service.CancelStocktake(c.Request.Context(), actor, biz, c.Param("stocktakeId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToStocktakeResponse(st))
+}
@@ -0,0 +1,78 @@
+package inventory
+
+import (
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/pdf"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/barcode"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+)
+
+// Labels are laid out on A4 in a 3 x 8 grid, which matches common sticker sheets.
+const (
+	labelColumns    = 3
+	labelRows       = 8
+	labelMargin     = 20.0
+	labelPadding    = 8.0
+	labelWidth      = (pdf.PageWidth - 2*labelMargin) / labelColumns
+	labelHeight     = (pdf.PageHeight - 2*labelMargin) / labelRows
+	labelBarHeight  = 40.0
+	labelMaxModule  = 1.2
+	labelTextMaxLen = labelWidth - 2*labelPadding
+)
+
+// LabelCode is the value encoded on a variant's label: its barcode, or its SKU when it has none.
+func LabelCode(v *Variant) string {
+	if v.Barcode != "" {
+		return v.Barcode
+	}
+	return v.SKU
+}
+
+// RenderVariantLabelsPDF renders copies barcode labels per variant, each with the variant name,
+// a Code 128 symbol of its label code, the code in text and the current price.
+func RenderVariantLabelsPDF(variants []*Variant, copies int) []byte {
+	doc := pdf.New("Variant labels")
+	now := time.Now()
+	slot := 0
+	for _, v := range variants {
+		for range copies {
+			if slot == labelColumns*labelRows {
+				doc.AddPage()
+				slot = 0
+			}
+			x := labelMargin + float64(slot%labelColumns)*labelWidth
+			y := labelMargin + float64(slot/labelColumns)*labelHeight
+			drawVariantLabel(doc, x, y, v, now)
+			slot++
+		}
+	}
+	return doc.Bytes()
+}
+
+func drawVariantLabel(doc *pdf.Document, x, y float64, v *Variant, now time.Time) {
+	center := x + labelWidth/2
+	doc.Text(center, y+labelPadding+8, pdf.Bold, 8, pdf.AlignCenter, pdf.Truncate(v.Name, pdf.Bold, 8, labelTextMaxLen))
+
+	code := LabelCode(v)
+	barsTop := y + labelPadding + 14
+	if modules, err := barcode.Code128(code); err == nil {
+		module := min(labelMaxModule, labelTextMaxLen/float64(len(modules)))
+		left := center - module*float64(len(modules))/2
+		for i := 0; i < len(modules); {
+			if !modules[i] {
+				i++
+				continue
+			}
+			start := i
+			for i < len(modules) && modules[i] {
+				i++
+			}
+			doc.FillRect(left+float64(start)*module, barsTop, float64(i-start)*module, labelBarHeight, 0)
+		}
+	}
+
+	textY := barsTop + labelBarHeight + 10
+	doc.Text(x+labelPadding, textY, pdf.Regular, 7, pdf.AlignLeft, pdf.Truncate(code, pdf.Regular, 7, labelTextMaxLen*0.6))
+	doc.Text(x+labelWidth-labelPadding, textY, pdf.Bold, 8, pdf.AlignRight, money.StringFixed(v.CurrentPrice(now), v.Currency)+" "+v.Currency)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
 	"github.com/abdelrahman146/kyora/internal/platform/types/asset"
 	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
 	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
@@ -76,19 +77,43 @@ const (
 	ProductVariantsStruct = "Variants"
 )
 
+// ProductType says how a product reaches the customer. Only physical products are stocked
+// and shipped; digital products can carry a download delivered once the order is paid.
+type ProductType string
+
+const (
+	ProductTypePhysical ProductType = "physical"
+	ProductTypeDigital  ProductType = "digital"
+	ProductTypeService  ProductType = "service"
+)
+
+// TracksStock reports whether selling the product takes units out of stock.
+func (t ProductType) TracksStock() bool {
+	return t == ProductTypePhysical || t == ""
+}
+
+// RequiresShipping reports whether the product is shipped to the customer.
+func (t ProductType) RequiresShipping() bool {
+	return t == ProductTypePhysical || t == ""
+}
+
 type Product struct {
-	ID          string             `gorm:"column:id;primaryKey;type:text" json:"id"`
-	BusinessID  string             `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
-	Business    *business.Business `gorm:"foreignKey:BusinessID;references:ID" json:"business,omitempty"`
-	Name        string             `gorm:"column:name;type:text;not null" json:"name"`
-	Description string             `gorm:"column:description;type:text" json:"description"`
-	Photos      AssetReferenceList `gorm:"column:photos;type:jsonb;not null;default:'[]'" json:"photos"`
-	CategoryID  string             `gorm:"column:category_id;type:text;index" json:"categoryId"`
-	Category    *Category          `gorm:"foreignKey:CategoryID;references:ID" json:"category,omitempty"`
-	Variants    []*Variant         `gorm:"foreignKey:ProductID;references:ID;constraint:OnDelete:CASCADE;" json:"variants,omitempty"`
-	CreatedAt   time.Time          `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
-	UpdatedAt   time.Time          `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
-	DeletedAt   gorm.DeletedAt     `gorm:"column:deleted_at;type:timestamp;index" json:"deletedAt,omitempty"`
+	database.Versioned
+	ID          string                `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID  string                `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	Business    *business.Business    `gorm:"foreignKey:BusinessID;references:ID" json:"business,omitempty"`
+	Name        string                `gorm:"column:name;type:text;not null" json:"name"`
+	Description string                `gorm:"column:description;type:text" json:"description"`
+	Type        ProductType           `gorm:"column:type;type:text;not null;default:'physical';index" json:"type"`
+	Download    *asset.AssetReference `gorm:"column:download;type:jsonb" json:"download,omitempty"`
+	Photos      AssetReferenceList    `gorm:"column:photos;type:jsonb;not null;default:'[]'" json:"photos"`
+	CategoryID  string                `gorm:"column:category_id;type:text;index" json:"categoryId"`
+	Options     ProductOptions        `gorm:"column:options;type:jsonb;not null;default:'[]'" json:"options"`
+	Category    *Category             `gorm:"foreignKey:CategoryID;references:ID" json:"category,omitempty"`
+	Variants    []*Variant            `gorm:"foreignKey:ProductID;references:ID;constraint:OnDelete:CASCADE;" json:"variants,omitempty"`
+	CreatedAt   time.Time             `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time             `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt        `gorm:"column:deleted_at;type:timestamp;index" json:"deletedAt,omitempty"`
 }
 
 func (m *Product) BeforeCreate(tx *gorm.DB) (err error) {
@@ -98,6 +123,46 @@ func (m *Product) BeforeCreate(tx *gorm.DB) (err error) {
 	return
 }
 
+// PromoActive reports whether the variant's time-bound promo price applies at t.
+// A promo without a start applies immediately and one without an end until cleared.
+func (m *Variant) PromoActive(t time.Time) bool {
+	if !m.PromoPrice.Valid {
+		return false
+	}
+	if m.PromoStartsAt != nil && t.Before(*m.PromoStartsAt) {
+		return false
+	}
+	if m.PromoEndsAt != nil && !t.Before(*m.PromoEndsAt) {
+		return false
+	}
+	return true
+}
+
+// CurrentPrice is the price the variant sells at t: its promo price while active, otherwise SalePrice.
+func (m *Variant) CurrentPrice(t time.Time) decimal.Decimal {
+	if m.PromoActive(t) {
+		return m.PromoPrice.Decimal
+	}
+	return m.SalePrice
+}
+
+// CurrentCost is the unit cost of the variant's stock on hand: its landed cost once stock has
+// moved through cost layers, otherwise CostPrice.
+func (m *Variant) CurrentCost() decimal.Decimal {
+	if m.LandedCost.IsPositive() {
+		return m.LandedCost
+	}
+	return m.CostPrice
+}
+
+// ProductType is the type of the variant's product, physical when the product is not loaded.
+func (m *Variant) ProductType() ProductType {
+	if m.Product == nil || m.Product.Type == "" {
+		return ProductTypePhysical
+	}
+	return m.Product.Type
+}
+
 // Request types moved to model_request.go
 
 var ProductSchema = struct {
@@ -105,6 +170,7 @@ var ProductSchema = struct {
 	BusinessID  schema.Field
 	Name        schema.Field
 	Description schema.Field
+	Type        schema.Field
 	Photos      schema.Field
 	CategoryID  schema.Field
 	CreatedAt   schema.Field
@@ -115,6 +181,7 @@ var ProductSchema = struct {
 	BusinessID:  schema.NewField("business_id", "businessId"),
 	Name:        schema.NewField("name", "name"),
 	Description: schema.NewField("description", "description"),
+	Type:        schema.NewField("type", "type"),
 	Photos:      schema.NewField("photos", "photos"),
 	CategoryID:  schema.NewField("category_id", "categoryId"),
 	CreatedAt:   schema.NewField("created_at", "createdAt"),
@@ -122,6 +189,19 @@ var ProductSchema = struct {
 	DeletedAt:   schema.NewField("deleted_at", "deletedAt"),
 }
 
+// productSummaryColumns is the lean projection used by summary list views.
+// It omits search_vector and the free-text description.
+var productSummaryColumns = schema.Columns(ProductTable,
+	ProductSchema.ID,
+	ProductSchema.BusinessID,
+	ProductSchema.Name,
+	ProductSchema.Type,
+	ProductSchema.Photos,
+	ProductSchema.CategoryID,
+	ProductSchema.CreatedAt,
+	ProductSchema.UpdatedAt,
+)
+
 func CreateProductSKU(businessDescriptor, productName, variantCode string) string {
 	s := id.NewCodeFromString(businessDescriptor, 3)
 	s += "-" + id.NewCodeFromString(productName, 3)
@@ -141,23 +221,40 @@ const (
 )
 
 type Variant struct {
-	ID                 string             `gorm:"column:id;primaryKey;type:text" json:"id"`
-	BusinessID         string             `gorm:"column:business_id;type:text;not null;index;uniqueIndex:sku_business_idx" json:"businessId"`
-	Business           *business.Business `gorm:"foreignKey:BusinessID;references:ID" json:"business,omitempty"`
-	Name               string             `gorm:"column:name;type:text;not null" json:"name"`
-	Code               string             `gorm:"column:code;type:text;not null;uniqueIndex:code_product_idx" json:"code"`
-	ProductID          string             `gorm:"column:product_id;type:text;not null;index;uniqueIndex:code_product_idx" json:"productId"`
-	Product            *Product           `gorm:"foreignKey:ProductID;references:ID;constraint:OnDelete:CASCADE;" json:"product,omitempty"`
-	SKU                string             `gorm:"column:sku;type:text;not null;uniqueIndex:sku_business_idx" json:"sku"`
-	CostPrice          decimal.Decimal    `gorm:"column:cost_price;type:numeric;not null;default:0" json:"costPrice"`
-	SalePrice          decimal.Decimal    `gorm:"column:sale_price;type:numeric;not null;default:0" json:"salePrice"`
-	Currency           string             `gorm:"column:currency;type:text;not null;default:'USD'" json:"currency"`
-	Photos             AssetReferenceList `gorm:"column:photos;type:jsonb;not null;default:'[]'" json:"photos"`
-	StockQuantity      int                `gorm:"column:stock_quantity;type:int;not null;default:0" json:"stockQuantity"`
-	StockQuantityAlert int                `gorm:"column:stock_alert;type:int;not null;default:0" json:"stockQuantityAlert"`
-	CreatedAt          time.Time          `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
-	UpdatedAt          time.Time          `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
-	DeletedAt          gorm.DeletedAt     `gorm:"column:deleted_at;type:timestamp;index" json:"deletedAt,omitempty"`
+	ID         string             `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string             `gorm:"column:business_id;type:text;not null;index;uniqueIndex:sku_business_idx;uniqueIndex:barcode_business_idx,where:barcode <> '' AND deleted_at IS NULL" json:"businessId"`
+	Business   *business.Business `gorm:"foreignKey:BusinessID;references:ID" json:"business,omitempty"`
+	Name       string             `gorm:"column:name;type:text;not null" json:"name"`
+	Code       string             `gorm:"column:code;type:text;not null;uniqueIndex:code_product_idx" json:"code"`
+	ProductID  string             `gorm:"column:product_id;type:text;not null;index;uniqueIndex:code_product_idx" json:"productId"`
+	Product    *Product           `gorm:"foreignKey:ProductID;references:ID;constraint:OnDelete:CASCADE;" json:"product,omitempty"`
+	SKU        string             `gorm:"column:sku;type:text;not null;uniqueIndex:sku_business_idx" json:"sku"`
+	Barcode    string             `gorm:"column:barcode;type:text;not null;default:'';uniqueIndex:barcode_business_idx,where:barcode <> '' AND deleted_at IS NULL" json:"barcode"`
+	Options    VariantOptions     `gorm:"column:options;type:jsonb;not null;default:'[]'" json:"options"`
+	CostPrice  decimal.Decimal    `gorm:"column:cost_price;type:numeric;not null;default:0" json:"costPrice"`
+	// LandedCost is the unit cost of the stock on hand from its cost layers, zero until the
+	// variant's stock first moves through them. See CurrentCost.
+	LandedCost         decimal.Decimal     `gorm:"column:landed_cost;type:numeric;not null;default:0" json:"landedCost"`
+	SalePrice          decimal.Decimal     `gorm:"column:sale_price;type:numeric;not null;default:0" json:"salePrice"`
+	PromoPrice         decimal.NullDecimal `gorm:"column:promo_price;type:numeric" json:"promoPrice"`
+	PromoStartsAt      *time.Time          `gorm:"column:promo_starts_at;type:timestamp" json:"promoStartsAt,omitempty"`
+	PromoEndsAt        *time.Time          `gorm:"column:promo_ends_at;type:timestamp;index" json:"promoEndsAt,omitempty"`
+	Currency           string              `gorm:"column:currency;type:text;not null;default:'USD'" json:"currency"`
+	Photos             AssetReferenceList  `gorm:"column:photos;type:jsonb;not null;default:'[]'" json:"photos"`
+	StockQuantity      int                 `gorm:"column:stock_quantity;type:int;not null;default:0" json:"stockQuantity"`
+	StockQuantityAlert int                 `gorm:"column:stock_alert;type:int;not null;default:0" json:"stockQuantityAlert"`
+	// PreferredSupplierID is the supplier the variant is repurchased from, empty when none.
+	// LeadTimeDays is how long that supplier takes to deliver (zero uses the reorder
+	// suggestions default), ReorderPoint the stock the variant should still hold when a
+	// delivery arrives (zero uses the stock alert) and ReorderQuantity the lot it is bought in
+	// (zero for any quantity).
+	PreferredSupplierID string         `gorm:"column:preferred_supplier_id;type:text;not null;default:'';index" json:"preferredSupplierId"`
+	LeadTimeDays        int            `gorm:"column:lead_time_days;type:int;not null;default:0" json:"leadTimeDays"`
+	ReorderPoint        int            `gorm:"column:reorder_point;type:int;not null;default:0" json:"reorderPoint"`
+	ReorderQuantity     int            `gorm:"column:reorder_quantity;type:int;not null;default:0" json:"reorderQuantity"`
+	CreatedAt           time.Time      `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+	UpdatedAt           time.Time      `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
+	DeletedAt           gorm.DeletedAt `gorm:"column:deleted_at;type:timestamp;index" json:"deletedAt,omitempty"`
 }
 
 func (m *Variant) BeforeCreate(tx *gorm.DB) (err error) {
@@ -169,38 +266,90 @@ func (m *Variant) BeforeCreate(tx *gorm.DB) (err error) {
 
 // Request types moved to model_request.go
 
+// PickerVariant is a variant with the units currently held by open orders.
+// Stock is deducted when an order is created, so StockQuantity is already what can be sold.
+type PickerVariant struct {
+	Variant  *Variant
+	Reserved int
+}
+
+// ReorderSuggestion is a variant worth repurchasing with the quantity that covers the supplier
+// lead time plus the cover period at its recent sales velocity, on top of its reorder point.
+// DaysOfStock is nil for variants that did not sell in the window. ProjectedStock is what is
+// left when an order placed today is delivered, negative when the variant runs out before.
+type ReorderSuggestion struct {
+	Variant           *Variant
+	Supplier          *Supplier
+	UnitsSold         int
+	DailyVelocity     float64
+	DaysOfStock       *float64
+	LeadTimeDays      int
+	ProjectedStock    int
+	SuggestedQuantity int
+}
+
+// RunsOutWithinLeadTime reports whether the variant sells out before a reorder placed today
+// would arrive.
+func (m *ReorderSuggestion) RunsOutWithinLeadTime() bool {
+	return m.DaysOfStock != nil && *m.DaysOfStock < float64(m.LeadTimeDays)
+}
+
 var VariantSchema = struct {
-	ID                 schema.Field
-	BusinessID         schema.Field
-	Name               schema.Field
-	Code               schema.Field
-	ProductID          schema.Field
-	SKU                schema.Field
-	CostPrice          schema.Field
-	SalePrice          schema.Field
-	Currency           schema.Field
-	Photos             schema.Field
-	StockQuantity      schema.Field
-	StockQuantityAlert schema.Field
-	CreatedAt          schema.Field
-	UpdatedAt          schema.Field
-	DeletedAt          schema.Field
+	ID                  schema.Field
+	BusinessID          schema.Field
+	Name                schema.Field
+	Code                schema.Field
+	ProductID           schema.Field
+	SKU                 schema.Field
+	Barcode             schema.Field
+	CostPrice           schema.Field
+	SalePrice           schema.Field
+	PromoPrice          schema.Field
+	PromoStartsAt       schema.Field
+	PromoEndsAt         schema.Field
+	Currency            schema.Field
+	Photos              schema.Field
+	StockQuantity       schema.Field
+	StockQuantityAlert  schema.Field
+	PreferredSupplierID schema.Field
+	CreatedAt           schema.Field
+	UpdatedAt           schema.Field
+	DeletedAt           schema.Field
 }{
-	ID:                 schema.NewField("id", "id"),
-	BusinessID:         schema.NewField("business_id", "businessId"),
-	Name:               schema.NewField("name", "name"),
-	Code:               schema.NewField("code", "code"),
-	ProductID:          schema.NewField("product_id", "productId"),
-	SKU:                schema.NewField("sku", "sku"),
-	CostPrice:          schema.NewField("cost_price", "costPrice"),
-	SalePrice:          schema.NewField("sale_price", "salePrice"),
-	Currency:           schema.NewField("currency", "currency"),
-	Photos:             schema.NewField("photos", "photos"),
-	StockQuantity:      schema.NewField("stock_quantity", "stockQuantity"),
-	StockQuantityAlert: schema.NewField("stock_alert", "stockQuantityAlert"),
-	CreatedAt:          schema.NewField("created_at", "createdAt"),
-	UpdatedAt:          schema.NewField("updated_at", "updatedAt"),
-	DeletedAt:          schema.NewField("deleted_at", "deletedAt"),
+	ID:                  schema.NewField("id", "id"),
+	BusinessID:          schema.NewField("business_id", "businessId"),
+	Name:                schema.NewField("name", "name"),
+	Code:                schema.NewField("code", "code"),
+	ProductID:           schema.NewField("product_id", "productId"),
+	SKU:                 schema.NewField("sku", "sku"),
+	Barcode:             schema.NewField("barcode", "barcode"),
+	CostPrice:           schema.NewField("cost_price", "costPrice"),
+	SalePrice:           schema.NewField("sale_price", "salePrice"),
+	PromoPrice:          schema.NewField("promo_price", "promoPrice"),
+	PromoStartsAt:       schema.NewField("promo_starts_at", "promoStartsAt"),
+	PromoEndsAt:         schema.NewField("promo_ends_at", "promoEndsAt"),
+	Currency:            schema.NewField("currency", "currency"),
+	Photos:              schema.NewField("photos", "photos"),
+	StockQuantity:       schema.NewField("stock_quantity", "stockQuantity"),
+	StockQuantityAlert:  schema.NewField("stock_alert", "stockQuantityAlert"),
+	PreferredSupplierID: schema.NewField("preferred_supplier_id", "preferredSupplierId"),
+	CreatedAt:           schema.NewField("created_at", "createdAt"),
+	UpdatedAt:           schema.NewField("updated_at", "updatedAt"),
+	DeletedAt:           schema.NewField("deleted_at", "deletedAt"),
+}
+
+// variantSummaryColumns are the variant columns needed to compute product summary aggregates.
+// Preloads run as a separate query, so the columns are left unqualified.
+var variantSummaryColumns = []string{
+	VariantSchema.ID.Column(),
+	VariantSchema.ProductID.Column(),
+	VariantSchema.SalePrice.Column(),
+	VariantSchema.PromoPrice.Column(),
+	VariantSchema.PromoStartsAt.Column(),
+	VariantSchema.PromoEndsAt.Column(),
+	VariantSchema.Currency.Column(),
+	VariantSchema.StockQuantity.Column(),
+	VariantSchema.StockQuantityAlert.Column(),
 }
 
 /* Category Model */
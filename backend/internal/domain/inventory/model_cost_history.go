@@ -0,0 +1,99 @@
+package inventory
+
+import (
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+/* Variant Cost Change Model */
+//---------------------------*/
+
+const (
+	VariantCostChangeTable  = "variant_cost_changes"
+	VariantCostChangeStruct = "VariantCostChange"
+	VariantCostChangePrefix = "vcc"
+)
+
+// VariantCostChange records a variant's cost and sale price each time one of them changes.
+// The first change of a variant is its creation and has no previous prices. The margin is
+// computed from the new prices when the change is made and is null while the sale price is zero.
+type VariantCostChange struct {
+	ID                string              `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID        string              `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	ProductID         string              `gorm:"column:product_id;type:text;not null;index" json:"productId"`
+	VariantID         string              `gorm:"column:variant_id;type:text;not null;index:variant_cost_change_variant_idx,priority:1" json:"variantId"`
+	PreviousCostPrice decimal.NullDecimal `gorm:"column:previous_cost_price;type:numeric" json:"previousCostPrice"`
+	CostPrice         decimal.Decimal     `gorm:"column:cost_price;type:numeric;not null;default:0" json:"costPrice"`
+	PreviousSalePrice decimal.NullDecimal `gorm:"column:previous_sale_price;type:numeric" json:"previousSalePrice"`
+	SalePrice         decimal.Decimal     `gorm:"column:sale_price;type:numeric;not null;default:0" json:"salePrice"`
+	Currency          string              `gorm:"column:currency;type:text;not null" json:"currency"`
+	MarginPercent     decimal.NullDecimal `gorm:"column:margin_percent;type:numeric" json:"marginPercent"`
+	// BelowMinMargin is set when the change dropped the margin below the business' minimum
+	// and a margin alert was raised for it.
+	BelowMinMargin bool      `gorm:"column:below_min_margin;not null;default:false" json:"belowMinMargin"`
+	ChangedByID    string    `gorm:"column:changed_by_id;type:text" json:"changedById"`
+	ChangedAt      time.Time `gorm:"column:changed_at;type:timestamp;not null;index:variant_cost_change_variant_idx,priority:2" json:"changedAt"`
+	CreatedAt      time.Time `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+}
+
+func (m *VariantCostChange) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(VariantCostChangePrefix)
+	}
+	return
+}
+
+var VariantCostChangeSchema = struct {
+	ID            schema.Field
+	BusinessID    schema.Field
+	ProductID     schema.Field
+	VariantID     schema.Field
+	CostPrice     schema.Field
+	SalePrice     schema.Field
+	MarginPercent schema.Field
+	ChangedAt     schema.Field
+}{
+	ID:            schema.NewField("id", "id"),
+	BusinessID:    schema.NewField("business_id", "businessId"),
+	ProductID:     schema.NewField("product_id", "productId"),
+	VariantID:     schema.NewField("variant_id", "variantId"),
+	CostPrice:     schema.NewField("cost_price", "costPrice"),
+	SalePrice:     schema.NewField("sale_price", "salePrice"),
+	MarginPercent: schema.NewField("margin_percent", "marginPercent"),
+	ChangedAt:     schema.NewField("changed_at", "changedAt"),
+}
+
+// marginPercent is the gross margin of a sale price as a percentage of it, rounded to two
+// decimals. It is null when the sale price is zero.
+func marginPercent(cost, sale decimal.Decimal) decimal.NullDecimal {
+	if !sale.IsPositive() {
+		return decimal.NullDecimal{}
+	}
+	return decimal.NewNullDecimal(sale.Sub(cost).Div(sale).Mul(decimal.NewFromInt(100)).Round(2))
+}
+
+// CostTrendPeriod summarizes the units of a variant sold in one month at the unit cost and
+// price snapshotted on the order items, so margins reflect the cost effective at sale time.
+type CostTrendPeriod struct {
+	Period       time.Time
+	UnitsSold    int64
+	Revenue      decimal.Decimal
+	COGS         decimal.Decimal
+	AvgUnitCost  decimal.Decimal
+	AvgUnitPrice decimal.Decimal
+	// MarginPercent is null for periods without revenue.
+	MarginPercent decimal.NullDecimal
+}
+
+// CostTrend is a variant's price history and its realized margin per month over a window.
+type CostTrend struct {
+	Variant *Variant
+	From    time.Time
+	To      time.Time
+	Changes []*VariantCostChange
+	Periods []*CostTrendPeriod
+}
@@ -0,0 +1,85 @@
+package inventory
+
+import (
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+/* Cost Layer Model */
+//------------------*/
+
+const (
+	CostLayerTable  = "inventory_cost_layers"
+	CostLayerStruct = "CostLayer"
+	CostLayerPrefix = "icl"
+)
+
+// CostLayerSource is where the units of a cost layer came from.
+//
+//	purchase_order  received against a purchase order, at the ordered unit cost
+//	order_restock   put back in stock from a cancelled, edited or returned order, at the
+//	                cost the order item was sold at
+//	adjustment      stock found without a receipt (initial stock, manual edits, stocktake
+//	                overages), at the variant's cost when the layer was opened
+type CostLayerSource string
+
+const (
+	CostLayerSourcePurchaseOrder CostLayerSource = "purchase_order"
+	CostLayerSourceOrderRestock  CostLayerSource = "order_restock"
+	CostLayerSourceAdjustment    CostLayerSource = "adjustment"
+)
+
+// CostLayer is a batch of a variant's units that came into stock at one unit cost.
+// RemainingQuantity counts the units of the batch still in stock: stock leaves from the oldest
+// layers first, so the open layers always add up to the variant's stock.
+//
+// Under FIFO costing, sold units cost what their layers cost. Under weighted-average costing
+// the layers still track the batches, but sold units cost the variant's running LandedCost.
+type CostLayer struct {
+	ID                string          `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID        string          `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	VariantID         string          `gorm:"column:variant_id;type:text;not null;index:cost_layer_variant_idx,priority:1" json:"variantId"`
+	Source            CostLayerSource `gorm:"column:source;type:text;not null" json:"source"`
+	SourceID          string          `gorm:"column:source_id;type:text;not null;default:''" json:"sourceId"`
+	Quantity          int             `gorm:"column:quantity;type:int;not null" json:"quantity"`
+	RemainingQuantity int             `gorm:"column:remaining_quantity;type:int;not null" json:"remainingQuantity"`
+	UnitCost          decimal.Decimal `gorm:"column:unit_cost;type:numeric;not null;default:0" json:"unitCost"`
+	ReceivedAt        time.Time       `gorm:"column:received_at;type:timestamp;not null;index:cost_layer_variant_idx,priority:2" json:"receivedAt"`
+	CreatedAt         time.Time       `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+	UpdatedAt         time.Time       `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
+}
+
+func (m *CostLayer) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(CostLayerPrefix)
+	}
+	return
+}
+
+var CostLayerSchema = struct {
+	ID                schema.Field
+	BusinessID        schema.Field
+	VariantID         schema.Field
+	Source            schema.Field
+	SourceID          schema.Field
+	RemainingQuantity schema.Field
+	ReceivedAt        schema.Field
+}{
+	ID:                schema.NewField("id", "id"),
+	BusinessID:        schema.NewField("business_id", "businessId"),
+	VariantID:         schema.NewField("variant_id", "variantId"),
+	Source:            schema.NewField("source", "source"),
+	SourceID:          schema.NewField("source_id", "sourceId"),
+	RemainingQuantity: schema.NewField("remaining_quantity", "remainingQuantity"),
+	ReceivedAt:        schema.NewField("received_at", "receivedAt"),
+}
+
+// StockReceipt is a quantity of a variant coming into stock at a unit cost.
+type StockReceipt struct {
+	Quantity int
+	UnitCost decimal.Decimal
+}
@@ -0,0 +1,108 @@
+package inventory
+
+import (
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+/* Location Model */
+//-----------------*/
+
+const (
+	LocationTable  = "locations"
+	LocationStruct = "Location"
+	LocationPrefix = "loc"
+)
+
+// Location is a place the business keeps stock: a warehouse, a store, the owner's home or a
+// fulfillment partner.
+//
+// Once a business has locations, every variant's StockQuantity is the sum of its stock levels
+// across them. Stock changes that do not name a location (variant edits, purchase order
+// receipts, orders placed without one) apply to the default location. Businesses without
+// locations only track the variant totals.
+type Location struct {
+	ID         string         `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string         `gorm:"column:business_id;type:text;not null;index;uniqueIndex:location_name_business_idx" json:"businessId"`
+	Name       string         `gorm:"column:name;type:text;not null;uniqueIndex:location_name_business_idx" json:"name"`
+	Address    string         `gorm:"column:address;type:text" json:"address"`
+	IsDefault  bool           `gorm:"column:is_default;not null;default:false" json:"isDefault"`
+	CreatedAt  time.Time      `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+	UpdatedAt  time.Time      `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
+	DeletedAt  gorm.DeletedAt `gorm:"column:deleted_at;type:timestamp;index" json:"deletedAt,omitempty"`
+}
+
+func (m *Location) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(LocationPrefix)
+	}
+	return
+}
+
+var LocationSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	Name       schema.Field
+	IsDefault  schema.Field
+	CreatedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	Name:       schema.NewField("name", "name"),
+	IsDefault:  schema.NewField("is_default", "isDefault"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+}
+
+const (
+	LocationStockTable  = "location_stocks"
+	LocationStockPrefix = "lst"
+)
+
+// LocationStock is the quantity of a variant kept at a location.
+type LocationStock struct {
+	ID         string    `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string    `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	LocationID string    `gorm:"column:location_id;type:text;not null;index;uniqueIndex:location_stock_variant_idx" json:"locationId"`
+	VariantID  string    `gorm:"column:variant_id;type:text;not null;uniqueIndex:location_stock_variant_idx" json:"variantId"`
+	Quantity   int       `gorm:"column:quantity;type:int;not null;default:0" json:"quantity"`
+	CreatedAt  time.Time `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+	UpdatedAt  time.Time `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
+}
+
+func (m *LocationStock) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(LocationStockPrefix)
+	}
+	return
+}
+
+var LocationStockSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	LocationID schema.Field
+	VariantID  schema.Field
+	Quantity   schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	LocationID: schema.NewField("location_id", "locationId"),
+	VariantID:  schema.NewField("variant_id", "variantId"),
+	Quantity:   schema.NewField("quantity", "quantity"),
+}
+
+// LocationStockTotals is the stock a location holds across all variants.
+type LocationStockTotals struct {
+	Location       *Location
+	StockUnits     int64
+	InventoryValue decimal.Decimal
+}
+
+// LocationStockLevel is the quantity of a variant kept at a location.
+type LocationStockLevel struct {
+	Location *Location
+	Quantity int
+}
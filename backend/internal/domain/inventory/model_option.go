@@ -0,0 +1,143 @@
+package inventory
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/shopspring/decimal"
+)
+
+// ProductOptionValue is one choice of a product option. PriceModifier is added to the base
+// sale price of the variants generated with it and may be negative.
+type ProductOptionValue struct {
+	Value         string          `json:"value"`
+	PriceModifier decimal.Decimal `json:"priceModifier"`
+}
+
+// ProductOption is a dimension a product's variants differ along, such as Size or Color.
+type ProductOption struct {
+	Name   string               `json:"name"`
+	Values []ProductOptionValue `json:"values"`
+}
+
+// ProductOptions are a product's options, in the order variant codes list their values.
+type ProductOptions []ProductOption
+
+func (o ProductOptions) Value() (driver.Value, error) {
+	if o == nil {
+		o = ProductOptions{}
+	}
+	b, err := json.Marshal([]ProductOption(o))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (o *ProductOptions) Scan(value any) error {
+	if o == nil {
+		return problem.InternalError().WithError(errors.New("ProductOptions scan into nil receiver"))
+	}
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*o = ProductOptions{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return problem.InternalError().WithError(errors.New("unexpected scan type for ProductOptions"))
+	}
+	return json.Unmarshal(raw, (*[]ProductOption)(o))
+}
+
+// VariantOption is the value a variant takes for one option of its product.
+type VariantOption struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// VariantOptions are the option values of a variant generated from its product's options,
+// in option order. Variants created by hand have none.
+type VariantOptions []VariantOption
+
+func (o VariantOptions) Value() (driver.Value, error) {
+	if o == nil {
+		o = VariantOptions{}
+	}
+	b, err := json.Marshal([]VariantOption(o))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (o *VariantOptions) Scan(value any) error {
+	if o == nil {
+		return problem.InternalError().WithError(errors.New("VariantOptions scan into nil receiver"))
+	}
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*o = VariantOptions{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return problem.InternalError().WithError(errors.New("unexpected scan type for VariantOptions"))
+	}
+	return json.Unmarshal(raw, (*[]VariantOption)(o))
+}
+
+// variantOptionCodeSeparator joins option values into a generated variant's code.
+const variantOptionCodeSeparator = " / "
+
+// Code is the variant code of the combination, e.g. "M / Red".
+func (o VariantOptions) Code() string {
+	values := make([]string, len(o))
+	for i, v := range o {
+		values[i] = v.Value
+	}
+	return strings.Join(values, variantOptionCodeSeparator)
+}
+
+// Limits on product options; a product has at most maxVariantMatrix generated variants.
+const (
+	maxProductOptions      = 3
+	maxProductOptionValues = 50
+	maxVariantMatrix       = 200
+)
+
+// VariantMatrixCombination is one cell of a product's variant matrix with the sum of its
+// values' price modifiers.
+type VariantMatrixCombination struct {
+	Options       VariantOptions
+	PriceModifier decimal.Decimal
+}
+
+// Matrix returns the cartesian product of the options' values, varying the last option
+// fastest.
+func (o ProductOptions) Matrix() []VariantMatrixCombination {
+	if len(o) == 0 {
+		return nil
+	}
+	combos := []VariantMatrixCombination{{PriceModifier: decimal.Zero}}
+	for _, opt := range o {
+		next := make([]VariantMatrixCombination, 0, len(combos)*len(opt.Values))
+		for _, c := range combos {
+			for _, v := range opt.Values {
+				options := append(append(VariantOptions{}, c.Options...), VariantOption{Name: opt.Name, Value: v.Value})
+				next = append(next, VariantMatrixCombination{Options: options, PriceModifier: c.PriceModifier.Add(v.PriceModifier)})
+			}
+		}
+		combos = next
+	}
+	return combos
+}
@@ -0,0 +1,193 @@
+package inventory
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+/* Price List Model */
+//-------------------*/
+
+const (
+	PriceListTable         = "price_lists"
+	PriceListStruct        = "PriceList"
+	PriceListPrefix        = "prl"
+	PriceListEntriesStruct = "Entries"
+)
+
+// PriceList is a named pricing tier (retail, wholesale, VIP, ...). Customers assigned to it
+// are offered its prices instead of the variants' SalePrice when an order or quote is priced.
+//
+// A variant is priced from its entry when the list has one, otherwise from its SalePrice
+// adjusted by PercentAdjustment (e.g. -15 for 15% off). Entries can lower the price for larger
+// quantities with quantity breaks. The business' default list applies to customers without a
+// list of their own.
+type PriceList struct {
+	ID                string             `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID        string             `gorm:"column:business_id;type:text;not null;index;uniqueIndex:price_list_name_business_idx" json:"businessId"`
+	Business          *business.Business `gorm:"foreignKey:BusinessID;references:ID" json:"business,omitempty"`
+	Name              string             `gorm:"column:name;type:text;not null;uniqueIndex:price_list_name_business_idx" json:"name"`
+	Description       string             `gorm:"column:description;type:text" json:"description"`
+	IsDefault         bool               `gorm:"column:is_default;not null;default:false" json:"isDefault"`
+	PercentAdjustment decimal.Decimal    `gorm:"column:percent_adjustment;type:numeric;not null;default:0" json:"percentAdjustment"`
+	Entries           []*PriceListEntry  `gorm:"foreignKey:PriceListID;references:ID;constraint:OnDelete:CASCADE;" json:"entries,omitempty"`
+	CreatedAt         time.Time          `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+	UpdatedAt         time.Time          `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
+	DeletedAt         gorm.DeletedAt     `gorm:"column:deleted_at;type:timestamp;index" json:"deletedAt,omitempty"`
+}
+
+func (m *PriceList) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(PriceListPrefix)
+	}
+	return
+}
+
+// PriceFor returns the unit price of qty units of v under the list, rounded to currency.
+// A nil list prices every variant at its current price (see Variant.CurrentPrice). A running
+// promo also wins over the list price when it is lower.
+func (m *PriceList) PriceFor(v *Variant, qty int, currency string) decimal.Decimal {
+	current := v.CurrentPrice(time.Now())
+	if m == nil {
+		return current
+	}
+	price := v.SalePrice
+	if entry := m.entryFor(v.ID); entry != nil {
+		price = entry.PriceFor(qty)
+	} else if !m.PercentAdjustment.IsZero() {
+		factor := decimal.NewFromInt(100).Add(m.PercentAdjustment).Div(decimal.NewFromInt(100))
+		price = money.Round(v.SalePrice.Mul(factor), currency)
+	}
+	if current.LessThan(price) {
+		return current
+	}
+	return price
+}
+
+func (m *PriceList) entryFor(variantID string) *PriceListEntry {
+	for _, e := range m.Entries {
+		if e.VariantID == variantID {
+			return e
+		}
+	}
+	return nil
+}
+
+var PriceListSchema = struct {
+	ID                schema.Field
+	BusinessID        schema.Field
+	Name              schema.Field
+	IsDefault         schema.Field
+	PercentAdjustment schema.Field
+	CreatedAt         schema.Field
+	UpdatedAt         schema.Field
+	DeletedAt         schema.Field
+}{
+	ID:                schema.NewField("id", "id"),
+	BusinessID:        schema.NewField("business_id", "businessId"),
+	Name:              schema.NewField("name", "name"),
+	IsDefault:         schema.NewField("is_default", "isDefault"),
+	PercentAdjustment: schema.NewField("percent_adjustment", "percentAdjustment"),
+	CreatedAt:         schema.NewField("created_at", "createdAt"),
+	UpdatedAt:         schema.NewField("updated_at", "updatedAt"),
+	DeletedAt:         schema.NewField("deleted_at", "deletedAt"),
+}
+
+const (
+	PriceListEntryTable  = "price_list_entries"
+	PriceListEntryPrefix = "prle"
+)
+
+// PriceListEntry is the price of one variant in a price list. QuantityBreaks, ordered by
+// MinQuantity, lower the unit price of order lines of at least that many units.
+type PriceListEntry struct {
+	ID             string          `gorm:"column:id;primaryKey;type:text" json:"id"`
+	PriceListID    string          `gorm:"column:price_list_id;type:text;not null;uniqueIndex:price_list_entry_variant_idx" json:"priceListId"`
+	VariantID      string          `gorm:"column:variant_id;type:text;not null;index;uniqueIndex:price_list_entry_variant_idx" json:"variantId"`
+	Variant        *Variant        `gorm:"foreignKey:VariantID;references:ID" json:"variant,omitempty"`
+	Price          decimal.Decimal `gorm:"column:price;type:numeric;not null" json:"price"`
+	QuantityBreaks PriceBreaks     `gorm:"column:quantity_breaks;type:jsonb;not null;default:'[]'" json:"quantityBreaks"`
+	CreatedAt      time.Time       `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+	UpdatedAt      time.Time       `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
+}
+
+// PriceFor is the entry's unit price for a line of qty units: the price of the largest
+// quantity break qty reaches, otherwise Price.
+func (m *PriceListEntry) PriceFor(qty int) decimal.Decimal {
+	price := m.Price
+	for _, b := range m.QuantityBreaks {
+		if qty < b.MinQuantity {
+			break
+		}
+		price = b.Price
+	}
+	return price
+}
+
+func (m *PriceListEntry) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(PriceListEntryPrefix)
+	}
+	return
+}
+
+var PriceListEntrySchema = struct {
+	ID          schema.Field
+	PriceListID schema.Field
+	VariantID   schema.Field
+	Price       schema.Field
+}{
+	ID:          schema.NewField("id", "id"),
+	PriceListID: schema.NewField("price_list_id", "priceListId"),
+	VariantID:   schema.NewField("variant_id", "variantId"),
+	Price:       schema.NewField("price", "price"),
+}
+
+// PriceBreak is the unit price of a price list entry for lines of at least MinQuantity units.
+type PriceBreak struct {
+	MinQuantity int             `json:"minQuantity"`
+	Price       decimal.Decimal `json:"price"`
+}
+
+// PriceBreaks are the quantity breaks of a price list entry, ordered by MinQuantity.
+type PriceBreaks []PriceBreak
+
+func (b PriceBreaks) Value() (driver.Value, error) {
+	if b == nil {
+		b = PriceBreaks{}
+	}
+	raw, err := json.Marshal([]PriceBreak(b))
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+func (b *PriceBreaks) Scan(value any) error {
+	if b == nil {
+		return problem.InternalError().WithError(errors.New("PriceBreaks scan into nil receiver"))
+	}
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*b = PriceBreaks{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return problem.InternalError().WithError(errors.New("unexpected scan type for PriceBreaks"))
+	}
+	return json.Unmarshal(raw, (*[]PriceBreak)(b))
+}
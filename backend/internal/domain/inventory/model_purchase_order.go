@@ -0,0 +1,234 @@
+package inventory
+
+import (
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+/* Supplier Model */
+//-----------------*/
+
+const (
+	SupplierTable  = "suppliers"
+	SupplierStruct = "Supplier"
+	SupplierPrefix = "sup"
+)
+
+// Supplier is a vendor the business buys stock from through purchase orders.
+type Supplier struct {
+	ID          string         `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID  string         `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	Name        string         `gorm:"column:name;type:text;not null" json:"name"`
+	ContactName string         `gorm:"column:contact_name;type:text" json:"contactName"`
+	Email       string         `gorm:"column:email;type:text" json:"email"`
+	Phone       string         `gorm:"column:phone;type:text" json:"phone"`
+	Notes       string         `gorm:"column:notes;type:text" json:"notes"`
+	CreatedAt   time.Time      `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt `gorm:"column:deleted_at;type:timestamp;index" json:"deletedAt,omitempty"`
+}
+
+func (m *Supplier) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(SupplierPrefix)
+	}
+	return
+}
+
+var SupplierSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	Name       schema.Field
+	CreatedAt  schema.Field
+	UpdatedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	Name:       schema.NewField("name", "name"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+	UpdatedAt:  schema.NewField("updated_at", "updatedAt"),
+}
+
+/* Purchase Order Model */
+//-----------------------*/
+
+const (
+	PurchaseOrderTable         = "purchase_orders"
+	PurchaseOrderStruct        = "PurchaseOrder"
+	PurchaseOrderPrefix        = "po"
+	PurchaseOrderItemsStruct   = "Items"
+	PurchaseOrderSupplierField = "Supplier"
+)
+
+// PurchaseOrderStatus tracks how much of a purchase order has arrived.
+//
+//	ordered             placed with the supplier, nothing received yet
+//	partially_received  some items arrived, the rest is still expected
+//	received            every ordered unit arrived
+//	cancelled           called off before anything arrived
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderStatusOrdered           PurchaseOrderStatus = "ordered"
+	PurchaseOrderStatusPartiallyReceived PurchaseOrderStatus = "partially_received"
+	PurchaseOrderStatusReceived          PurchaseOrderStatus = "received"
+	PurchaseOrderStatusCancelled         PurchaseOrderStatus = "cancelled"
+)
+
+// receivable reports whether goods can still be received against a purchase order in status s.
+func (s PurchaseOrderStatus) receivable() bool {
+	return s == PurchaseOrderStatusOrdered || s == PurchaseOrderStatusPartiallyReceived
+}
+
+// PurchaseOrder is stock ordered from a supplier at agreed unit costs.
+//
+// Total is the value of everything ordered and ReceivedTotal the value of what arrived so far.
+// The business owes the supplier for received goods it has not paid: that outstanding amount
+// is the liability the financial reports carry. Payments beyond the received value are
+// prepayments and do not reduce other orders' balances.
+type PurchaseOrder struct {
+	ID            string               `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID    string               `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	SupplierID    string               `gorm:"column:supplier_id;type:text;not null;index" json:"supplierId"`
+	Supplier      *Supplier            `gorm:"foreignKey:SupplierID;references:ID" json:"supplier,omitempty"`
+	Reference     string               `gorm:"column:reference;type:text" json:"reference"`
+	Status        PurchaseOrderStatus  `gorm:"column:status;type:text;not null;index" json:"status"`
+	Currency      string               `gorm:"column:currency;type:text;not null" json:"currency"`
+	Total         decimal.Decimal      `gorm:"column:total;type:numeric;not null;default:0" json:"total"`
+	ReceivedTotal decimal.Decimal      `gorm:"column:received_total;type:numeric;not null;default:0" json:"receivedTotal"`
+	AmountPaid    decimal.Decimal      `gorm:"column:amount_paid;type:numeric;not null;default:0" json:"amountPaid"`
+	ExpectedAt    *time.Time           `gorm:"column:expected_at;type:timestamp" json:"expectedAt,omitempty"`
+	OrderedAt     time.Time            `gorm:"column:ordered_at;type:timestamp;not null" json:"orderedAt"`
+	ReceivedAt    *time.Time           `gorm:"column:received_at;type:timestamp" json:"receivedAt,omitempty"`
+	CancelledAt   *time.Time           `gorm:"column:cancelled_at;type:timestamp" json:"cancelledAt,omitempty"`
+	Notes         string               `gorm:"column:notes;type:text" json:"notes"`
+	Items         []*PurchaseOrderItem `gorm:"foreignKey:PurchaseOrderID;references:ID;constraint:OnDelete:CASCADE;" json:"items,omitempty"`
+	CreatedAt     time.Time            `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+	UpdatedAt     time.Time            `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
+	DeletedAt     gorm.DeletedAt       `gorm:"column:deleted_at;type:timestamp;index" json:"deletedAt,omitempty"`
+}
+
+func (m *PurchaseOrder) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(PurchaseOrderPrefix)
+	}
+	return
+}
+
+// AmountOutstanding is what the business owes the supplier for goods received and not yet paid.
+func (m *PurchaseOrder) AmountOutstanding() decimal.Decimal {
+	if m.Status == PurchaseOrderStatusCancelled {
+		return decimal.Zero
+	}
+	return decimal.Max(m.ReceivedTotal.Sub(m.AmountPaid), decimal.Zero)
+}
+
+var PurchaseOrderSchema = struct {
+	ID            schema.Field
+	BusinessID    schema.Field
+	SupplierID    schema.Field
+	Status        schema.Field
+	Total         schema.Field
+	ReceivedTotal schema.Field
+	AmountPaid    schema.Field
+	ExpectedAt    schema.Field
+	OrderedAt     schema.Field
+	CreatedAt     schema.Field
+}{
+	ID:            schema.NewField("id", "id"),
+	BusinessID:    schema.NewField("business_id", "businessId"),
+	SupplierID:    schema.NewField("supplier_id", "supplierId"),
+	Status:        schema.NewField("status", "status"),
+	Total:         schema.NewField("total", "total"),
+	ReceivedTotal: schema.NewField("received_total", "receivedTotal"),
+	AmountPaid:    schema.NewField("amount_paid", "amountPaid"),
+	ExpectedAt:    schema.NewField("expected_at", "expectedAt"),
+	OrderedAt:     schema.NewField("ordered_at", "orderedAt"),
+	CreatedAt:     schema.NewField("created_at", "createdAt"),
+}
+
+const (
+	PurchaseOrderItemTable  = "purchase_order_items"
+	PurchaseOrderItemPrefix = "poi"
+)
+
+// PurchaseOrderItem is one variant on a purchase order with the quantity ordered at UnitCost
+// and how many units arrived so far.
+type PurchaseOrderItem struct {
+	ID               string          `gorm:"column:id;primaryKey;type:text" json:"id"`
+	PurchaseOrderID  string          `gorm:"column:purchase_order_id;type:text;not null;uniqueIndex:purchase_order_item_variant_idx" json:"purchaseOrderId"`
+	VariantID        string          `gorm:"column:variant_id;type:text;not null;index;uniqueIndex:purchase_order_item_variant_idx" json:"variantId"`
+	Variant          *Variant        `gorm:"foreignKey:VariantID;references:ID" json:"variant,omitempty"`
+	Quantity         int             `gorm:"column:quantity;type:int;not null" json:"quantity"`
+	ReceivedQuantity int             `gorm:"column:received_quantity;type:int;not null;default:0" json:"receivedQuantity"`
+	UnitCost         decimal.Decimal `gorm:"column:unit_cost;type:numeric;not null" json:"unitCost"`
+	Total            decimal.Decimal `gorm:"column:total;type:numeric;not null" json:"total"`
+	CreatedAt        time.Time       `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+	UpdatedAt        time.Time       `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
+}
+
+func (m *PurchaseOrderItem) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(PurchaseOrderItemPrefix)
+	}
+	return
+}
+
+// remaining is the number of units still expected.
+func (m *PurchaseOrderItem) remaining() int {
+	return max(m.Quantity-m.ReceivedQuantity, 0)
+}
+
+var PurchaseOrderItemSchema = struct {
+	ID              schema.Field
+	PurchaseOrderID schema.Field
+	VariantID       schema.Field
+}{
+	ID:              schema.NewField("id", "id"),
+	PurchaseOrderID: schema.NewField("purchase_order_id", "purchaseOrderId"),
+	VariantID:       schema.NewField("variant_id", "variantId"),
+}
+
+const (
+	PurchaseOrderReceiptTable  = "purchase_order_receipts"
+	PurchaseOrderReceiptPrefix = "por"
+)
+
+// PurchaseOrderReceipt records units of a purchase order item that arrived and were added to
+// the variant's stock. Receipts are the inbound side of a variant's stock movements: each one
+// matches the stock increment applied with it.
+type PurchaseOrderReceipt struct {
+	ID              string          `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID      string          `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	PurchaseOrderID string          `gorm:"column:purchase_order_id;type:text;not null;index" json:"purchaseOrderId"`
+	ItemID          string          `gorm:"column:item_id;type:text;not null" json:"itemId"`
+	VariantID       string          `gorm:"column:variant_id;type:text;not null;index" json:"variantId"`
+	Quantity        int             `gorm:"column:quantity;type:int;not null" json:"quantity"`
+	UnitCost        decimal.Decimal `gorm:"column:unit_cost;type:numeric;not null" json:"unitCost"`
+	ReceivedByID    string          `gorm:"column:received_by_id;type:text" json:"receivedById"`
+	ReceivedAt      time.Time       `gorm:"column:received_at;type:timestamp;not null" json:"receivedAt"`
+	CreatedAt       time.Time       `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+}
+
+func (m *PurchaseOrderReceipt) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(PurchaseOrderReceiptPrefix)
+	}
+	return
+}
+
+var PurchaseOrderReceiptSchema = struct {
+	ID              schema.Field
+	PurchaseOrderID schema.Field
+	VariantID       schema.Field
+	ReceivedAt      schema.Field
+}{
+	ID:              schema.NewField("id", "id"),
+	PurchaseOrderID: schema.NewField("purchase_order_id", "purchaseOrderId"),
+	VariantID:       schema.NewField("variant_id", "variantId"),
+	ReceivedAt:      schema.NewField("received_at", "receivedAt"),
+}
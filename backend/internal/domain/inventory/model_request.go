@@ -1,6 +1,8 @@
 package inventory
 
 import (
+	"time"
+
 	"github.com/abdelrahman146/kyora/internal/platform/types/asset"
 	"github.com/shopspring/decimal"
 )
@@ -11,6 +13,8 @@ type CreateProductRequest struct {
 	Description string                 `json:"description" binding:"omitempty"`
 	Photos      []asset.AssetReference `json:"photos" binding:"omitempty,max=10,dive"`
 	CategoryID  string                 `json:"categoryId" binding:"required"`
+	Type        ProductType            `json:"type" binding:"omitempty,oneof=physical digital service"`
+	Download    *asset.AssetReference  `json:"download" binding:"omitempty"`
 }
 
 // UpdateProductRequest is the request DTO for updating a product.
@@ -19,6 +23,55 @@ type UpdateProductRequest struct {
 	Description string                 `json:"description" binding:"omitempty"`
 	Photos      []asset.AssetReference `json:"photos" binding:"omitempty,max=10,dive"`
 	CategoryID  string                 `json:"categoryId" binding:"omitempty"`
+	Type        ProductType            `json:"type" binding:"omitempty,oneof=physical digital service"`
+	Download    *asset.AssetReference  `json:"download" binding:"omitempty"`
+	// ClearDownload removes the product's download.
+	ClearDownload bool `json:"clearDownload"`
+	// Version is the product version the client last read. When set, the update is rejected
+	// with 409 if the product has changed since.
+	Version *int64 `json:"version,omitempty" binding:"omitempty,min=1"`
+}
+
+// AddProductPhotosRequest attaches uploaded image assets to a product, after its existing photos.
+// With SetCover the first added photo becomes the cover.
+type AddProductPhotosRequest struct {
+	AssetIDs []string `json:"assetIds" binding:"required,min=1,max=10,dive,required"`
+	SetCover bool     `json:"setCover"`
+}
+
+// ReorderProductPhotosRequest sets the order of a product's photos. PhotoIDs must list every
+// photo exactly once; the first one is the cover.
+type ReorderProductPhotosRequest struct {
+	PhotoIDs []string `json:"photoIds" binding:"required,min=1,max=10,dive,required"`
+}
+
+// SetProductCoverPhotoRequest moves a photo to the front of the product's photos.
+type SetProductCoverPhotoRequest struct {
+	PhotoID string `json:"photoId" binding:"required"`
+}
+
+// ProductOptionValueRequest is one value of a product option. PriceModifier (default 0, may be
+// negative) is added to the base sale price of variants with the value.
+type ProductOptionValueRequest struct {
+	Value         string          `json:"value" binding:"required,max=50"`
+	PriceModifier decimal.Decimal `json:"priceModifier"`
+}
+
+// ProductOptionRequest is an option such as Size or Color with its values.
+type ProductOptionRequest struct {
+	Name   string                      `json:"name" binding:"required,max=50"`
+	Values []ProductOptionValueRequest `json:"values" binding:"required,min=1,max=50,dive"`
+}
+
+// GenerateVariantsRequest sets a product's options and creates a variant for every combination
+// of their values that the product does not have yet. New variants cost CostPrice, sell at
+// SalePrice plus their values' modifiers and start with StockQuantity units.
+type GenerateVariantsRequest struct {
+	Options            []ProductOptionRequest `json:"options" binding:"required,min=1,max=3,dive"`
+	CostPrice          *decimal.Decimal       `json:"costPrice" binding:"required"`
+	SalePrice          *decimal.Decimal       `json:"salePrice" binding:"required"`
+	StockQuantity      int                    `json:"stockQuantity" binding:"omitempty,gte=0"`
+	StockQuantityAlert int                    `json:"stockQuantityAlert" binding:"omitempty,gte=0"`
 }
 
 // CreateVariantRequest is the request DTO for creating a variant.
@@ -26,23 +79,58 @@ type CreateVariantRequest struct {
 	ProductID          string                 `form:"productId" json:"productId" binding:"required"`
 	Code               string                 `form:"code" json:"code" binding:"required"`
 	SKU                string                 `form:"sku" json:"sku" binding:"omitempty"`
+	Barcode            string                 `form:"barcode" json:"barcode" binding:"omitempty"`
 	Photos             []asset.AssetReference `form:"photos" json:"photos" binding:"omitempty,max=10,dive"`
 	CostPrice          *decimal.Decimal       `form:"costPrice" json:"costPrice" binding:"required"`
 	SalePrice          *decimal.Decimal       `form:"salePrice" json:"salePrice" binding:"required"`
 	StockQuantity      *int                   `form:"stockQuantity" json:"stockQuantity" binding:"required,gte=0"`
 	StockQuantityAlert *int                   `form:"stockQuantityAlert" json:"stockQuantityAlert" binding:"required,gte=0"`
+	ReorderSettings
 }
 
 // UpdateVariantRequest is the request DTO for updating a variant.
 type UpdateVariantRequest struct {
 	Code               *string                `form:"code" json:"code" binding:"omitempty"`
 	SKU                *string                `form:"sku" json:"sku" binding:"omitempty"`
+	Barcode            *string                `form:"barcode" json:"barcode" binding:"omitempty"`
 	Photos             []asset.AssetReference `form:"photos" json:"photos" binding:"omitempty,max=10,dive"`
 	CostPrice          *decimal.Decimal       `form:"costPrice" json:"costPrice" binding:"omitempty"`
 	SalePrice          *decimal.Decimal       `form:"salePrice" json:"salePrice" binding:"omitempty"`
 	Currency           *string                `form:"currency" json:"currency" binding:"omitempty,len=3"`
 	StockQuantity      *int                   `form:"stockQuantity" json:"stockQuantity" binding:"omitempty,gte=0"`
 	StockQuantityAlert *int                   `form:"stockQuantityAlert" json:"stockQuantityAlert" binding:"omitempty,gte=0"`
+	ReorderSettings
+	// PromoPrice schedules a time-bound sale price between PromoStartsAt and PromoEndsAt
+	// (either may be omitted). ClearPromo removes the promo.
+	PromoPrice    decimal.NullDecimal `json:"promoPrice" binding:"omitempty,dgt=0"`
+	PromoStartsAt *time.Time          `json:"promoStartsAt" binding:"omitempty"`
+	PromoEndsAt   *time.Time          `json:"promoEndsAt" binding:"omitempty"`
+	ClearPromo    bool                `json:"clearPromo"`
+}
+
+// ReorderSettings links a variant to the supplier it is repurchased from. Omitted fields are
+// left unchanged; an empty PreferredSupplierID unlinks the supplier.
+type ReorderSettings struct {
+	PreferredSupplierID *string `json:"preferredSupplierId" binding:"omitempty"`
+	LeadTimeDays        *int    `json:"leadTimeDays" binding:"omitempty,gte=0,lte=365"`
+	ReorderPoint        *int    `json:"reorderPoint" binding:"omitempty,gte=0"`
+	ReorderQuantity     *int    `json:"reorderQuantity" binding:"omitempty,gte=0"`
+}
+
+// BulkUpdateVariantsRequest applies price and stock changes to many variants at once. The
+// changes are validated together and applied in one transaction.
+type BulkUpdateVariantsRequest struct {
+	Changes []BulkVariantChange `json:"changes" binding:"required,min=1,max=500,dive"`
+}
+
+// BulkVariantChange sets the prices of one variant and/or adjusts its stock. StockDelta is added
+// to the stock at the default location (negative to remove units). Omitted fields are left
+// unchanged.
+type BulkVariantChange struct {
+	VariantID  string           `json:"variantId" binding:"required"`
+	SalePrice  *decimal.Decimal `json:"salePrice" binding:"omitempty"`
+	CostPrice  *decimal.Decimal `json:"costPrice" binding:"omitempty"`
+	StockDelta *int             `json:"stockDelta" binding:"omitempty"`
 }
 
 // CreateCategoryRequest is the request DTO for creating a category.
@@ -56,3 +144,221 @@ type UpdateCategoryRequest struct {
 	Name       string `json:"name" binding:"omitempty"`
 	Descriptor string `json:"descriptor" binding:"omitempty"`
 }
+
+// PriceListEntryRequest sets the price of one variant in a price list, with optional
+// quantity breaks that lower it for larger order lines.
+type PriceListEntryRequest struct {
+	VariantID      string              `json:"variantId" binding:"required"`
+	Price          decimal.Decimal     `json:"price" binding:"required,dgt=0"`
+	QuantityBreaks []PriceBreakRequest `json:"quantityBreaks" binding:"omitempty,max=10,dive"`
+}
+
+// PriceBreakRequest prices lines of at least MinQuantity units of a price list entry.
+type PriceBreakRequest struct {
+	MinQuantity int             `json:"minQuantity" binding:"required,min=2"`
+	Price       decimal.Decimal `json:"price" binding:"required,dgt=0"`
+}
+
+// CreatePriceListRequest is the request DTO for creating a price list.
+type CreatePriceListRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"omitempty,max=500"`
+	// IsDefault makes this the list used for customers without one assigned.
+	IsDefault bool `json:"isDefault"`
+	// PercentAdjustment applies to variants without an entry, e.g. -15 for 15% off SalePrice.
+	PercentAdjustment decimal.Decimal         `json:"percentAdjustment" binding:"omitempty,dgt=-100"`
+	Entries           []PriceListEntryRequest `json:"entries" binding:"omitempty,max=1000,dive"`
+}
+
+// UpdatePriceListRequest is the request DTO for updating a price list.
+// Entries, when provided, replace all existing entries.
+type UpdatePriceListRequest struct {
+	Name              *string                 `json:"name" binding:"omitempty,min=1,max=100"`
+	Description       *string                 `json:"description" binding:"omitempty,max=500"`
+	IsDefault         *bool                   `json:"isDefault" binding:"omitempty"`
+	PercentAdjustment decimal.NullDecimal     `json:"percentAdjustment" binding:"omitempty,dgt=-100"`
+	Entries           []PriceListEntryRequest `json:"entries" binding:"omitempty,max=1000,dive"`
+}
+
+// FlashSaleRequest puts every variant of a category on sale at PercentOff below its SalePrice.
+type FlashSaleRequest struct {
+	PercentOff decimal.Decimal `json:"percentOff" binding:"required,dgt=0"`
+	// StartsAt defaults to now.
+	StartsAt *time.Time `json:"startsAt" binding:"omitempty"`
+	EndsAt   time.Time  `json:"endsAt" binding:"required"`
+}
+
+// CreateSupplierRequest is the request DTO for creating a supplier.
+type CreateSupplierRequest struct {
+	Name        string `json:"name" binding:"required,max=200"`
+	ContactName string `json:"contactName" binding:"omitempty,max=200"`
+	Email       string `json:"email" binding:"omitempty,email"`
+	Phone       string `json:"phone" binding:"omitempty,max=50"`
+	Notes       string `json:"notes" binding:"omitempty,max=2000"`
+}
+
+// UpdateSupplierRequest is the request DTO for updating a supplier; omitted fields are kept.
+type UpdateSupplierRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=200"`
+	ContactName *string `json:"contactName" binding:"omitempty,max=200"`
+	Email       *string `json:"email" binding:"omitempty,email"`
+	Phone       *string `json:"phone" binding:"omitempty,max=50"`
+	Notes       *string `json:"notes" binding:"omitempty,max=2000"`
+}
+
+// PurchaseOrderItemRequest orders Quantity units of a variant at UnitCost each.
+type PurchaseOrderItemRequest struct {
+	VariantID string          `json:"variantId" binding:"required"`
+	Quantity  int             `json:"quantity" binding:"required,min=1"`
+	UnitCost  decimal.Decimal `json:"unitCost" binding:"dgte=0"`
+}
+
+// CreatePurchaseOrderRequest is the request DTO for placing a purchase order with a supplier.
+type CreatePurchaseOrderRequest struct {
+	SupplierID string `json:"supplierId" binding:"required"`
+	// Reference is the supplier's own order or invoice number.
+	Reference string `json:"reference" binding:"omitempty,max=100"`
+	// ExpectedAt is when the supplier expects to deliver.
+	ExpectedAt *time.Time                 `json:"expectedAt" binding:"omitempty"`
+	Notes      string                     `json:"notes" binding:"omitempty,max=2000"`
+	Items      []PurchaseOrderItemRequest `json:"items" binding:"required,min=1,max=200,dive"`
+}
+
+// UpdatePurchaseOrderRequest is the request DTO for updating a purchase order; omitted fields are kept.
+type UpdatePurchaseOrderRequest struct {
+	Reference  *string    `json:"reference" binding:"omitempty,max=100"`
+	ExpectedAt *time.Time `json:"expectedAt" binding:"omitempty"`
+	Notes      *string    `json:"notes" binding:"omitempty,max=2000"`
+}
+
+// ReceivePurchaseOrderItemRequest receives Quantity units of a purchase order item.
+type ReceivePurchaseOrderItemRequest struct {
+	ItemID   string `json:"itemId" binding:"required"`
+	Quantity int    `json:"quantity" binding:"required,min=1"`
+}
+
+// ReceivePurchaseOrderRequest is the request DTO for receiving goods against a purchase order.
+type ReceivePurchaseOrderRequest struct {
+	Items []ReceivePurchaseOrderItemRequest `json:"items" binding:"required,min=1,max=200,dive"`
+	// ReceivedAt defaults to now.
+	ReceivedAt *time.Time `json:"receivedAt" binding:"omitempty"`
+}
+
+// PurchaseOrderPaymentRequest records a payment made to the supplier of a purchase order.
+type PurchaseOrderPaymentRequest struct {
+	Amount decimal.Decimal `json:"amount" binding:"required,dgt=0"`
+}
+
+// CreateSupplierBillRequest is the request DTO for recording a bill sent by a supplier.
+type CreateSupplierBillRequest struct {
+	SupplierID string `json:"supplierId" binding:"required"`
+	// PurchaseOrderID is the purchase order of the supplier the bill is for, if any.
+	PurchaseOrderID string `json:"purchaseOrderId" binding:"omitempty"`
+	// Reference is the supplier's invoice number.
+	Reference string          `json:"reference" binding:"omitempty,max=100"`
+	Amount    decimal.Decimal `json:"amount" binding:"required,dgt=0"`
+	// IssuedAt defaults to now.
+	IssuedAt *time.Time `json:"issuedAt" binding:"omitempty"`
+	DueAt    time.Time  `json:"dueAt" binding:"required"`
+	Notes    string     `json:"notes" binding:"omitempty,max=2000"`
+}
+
+// UpdateSupplierBillRequest is the request DTO for updating a supplier bill; omitted fields are kept.
+type UpdateSupplierBillRequest struct {
+	Reference *string          `json:"reference" binding:"omitempty,max=100"`
+	Amount    *decimal.Decimal `json:"amount" binding:"omitempty,dgt=0"`
+	IssuedAt  *time.Time       `json:"issuedAt" binding:"omitempty"`
+	DueAt     *time.Time       `json:"dueAt" binding:"omitempty"`
+	Notes     *string          `json:"notes" binding:"omitempty,max=2000"`
+}
+
+// SupplierBillPaymentRequest records a payment made to the supplier against a bill.
+type SupplierBillPaymentRequest struct {
+	Amount decimal.Decimal `json:"amount" binding:"required,dgt=0"`
+	// PaidAt defaults to now.
+	PaidAt *time.Time `json:"paidAt" binding:"omitempty"`
+	Note   string     `json:"note" binding:"omitempty,max=500"`
+}
+
+// CreateLocationRequest is the request DTO for creating a stock location.
+type CreateLocationRequest struct {
+	Name    string `json:"name" binding:"required,max=100"`
+	Address string `json:"address" binding:"omitempty,max=500"`
+	// IsDefault makes this the location stock changes apply to when none is named.
+	// The first location of a business is always the default.
+	IsDefault bool `json:"isDefault"`
+}
+
+// UpdateLocationRequest is the request DTO for updating a stock location; omitted fields are kept.
+type UpdateLocationRequest struct {
+	Name      *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Address   *string `json:"address" binding:"omitempty,max=500"`
+	IsDefault *bool   `json:"isDefault" binding:"omitempty"`
+}
+
+// SetLocationStockRequest sets the units of a variant kept at a location. The variant's total
+// stock changes by the difference.
+type SetLocationStockRequest struct {
+	Quantity *int `json:"quantity" binding:"required,gte=0"`
+}
+
+// LocationTransferItemRequest moves Quantity units of a variant.
+type LocationTransferItemRequest struct {
+	VariantID string `json:"variantId" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"required,min=1"`
+}
+
+// LocationTransferRequest moves stock between two locations without changing variant totals.
+type LocationTransferRequest struct {
+	FromLocationID string                        `json:"fromLocationId" binding:"required"`
+	ToLocationID   string                        `json:"toLocationId" binding:"required"`
+	Items          []LocationTransferItemRequest `json:"items" binding:"required,min=1,max=200,dive"`
+}
+
+// CreateStocktakeRequest is the request DTO for starting a stocktake. Without CategoryID or
+// VariantIDs every variant is counted.
+type CreateStocktakeRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	// LocationID is the location counted; the default location when empty. Ignored for
+	// businesses without locations.
+	LocationID string   `json:"locationId" binding:"omitempty"`
+	CategoryID string   `json:"categoryId" binding:"omitempty"`
+	VariantIDs []string `json:"variantIds" binding:"omitempty,max=500,dive,required"`
+	Notes      string   `json:"notes" binding:"omitempty,max=2000"`
+}
+
+// StocktakeCountRequest sets the counted quantity of a variant.
+type StocktakeCountRequest struct {
+	VariantID string `json:"variantId" binding:"required"`
+	Quantity  *int   `json:"quantity" binding:"required,gte=0"`
+}
+
+// RecordStocktakeCountsRequest is the request DTO for entering counts by hand. Each count
+// replaces the variant's previous one.
+type RecordStocktakeCountsRequest struct {
+	Counts []StocktakeCountRequest `json:"counts" binding:"required,min=1,max=500,dive"`
+}
+
+// ScanStocktakeRequest adds Quantity units (1 when omitted) to the count of the variant whose
+// barcode or SKU is Code.
+type ScanStocktakeRequest struct {
+	Code     string `json:"code" binding:"required,max=100"`
+	Quantity int    `json:"quantity" binding:"omitempty,min=1,max=10000"`
+}
+
+// VariantLabelsRequest selects the variants to print barcode labels for. Each variant gets
+// Copies labels (1 when omitted).
+type VariantLabelsRequest struct {
+	VariantIDs []string `json:"variantIds" binding:"required,min=1,max=200,dive,required"`
+	Copies     int      `json:"copies" binding:"omitempty,min=1,max=100"`
+}
+
+// SKUPreviewRequest asks for the next SKUs of the business SKU pattern. Prefix, IncludeCategory
+// and SequenceDigits override the saved pattern when set.
+type SKUPreviewRequest struct {
+	CategoryID      string  `form:"categoryId" json:"categoryId" binding:"omitempty"`
+	Count           int     `form:"count" json:"count" binding:"omitempty,min=1,max=20"`
+	Prefix          *string `form:"prefix" json:"prefix" binding:"omitempty"`
+	IncludeCategory *bool   `form:"includeCategory" json:"includeCategory" binding:"omitempty"`
+	SequenceDigits  *int    `form:"sequenceDigits" json:"sequenceDigits" binding:"omitempty"`
+}
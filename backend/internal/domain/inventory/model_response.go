@@ -1,8 +1,10 @@
 package inventory
 
 import (
+	"math"
 	"time"
 
+	"github.com/abdelrahman146/kyora/internal/domain/business"
 	"github.com/abdelrahman146/kyora/internal/platform/types/asset"
 	"github.com/shopspring/decimal"
 )
@@ -10,11 +12,15 @@ import (
 // ProductResponse is the API response for Product entity
 type ProductResponse struct {
 	ID          string                 `json:"id"`
+	Version     int64                  `json:"version"`
 	BusinessID  string                 `json:"businessId"`
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
+	Type        ProductType            `json:"type"`
+	Download    *asset.AssetReference  `json:"download,omitempty"`
 	Photos      []asset.AssetReference `json:"photos"`
 	CategoryID  string                 `json:"categoryId"`
+	Options     []ProductOption        `json:"options"`
 	Variants    []VariantResponse      `json:"variants,omitempty"`
 	CreatedAt   time.Time              `json:"createdAt"`
 	UpdatedAt   time.Time              `json:"updatedAt"`
@@ -27,6 +33,11 @@ func ToProductResponse(p *Product) ProductResponse {
 		photos = []asset.AssetReference(p.Photos)
 	}
 
+	options := []ProductOption{}
+	if p.Options != nil {
+		options = []ProductOption(p.Options)
+	}
+
 	var variants []VariantResponse
 	if p.Variants != nil {
 		variants = make([]VariantResponse, len(p.Variants))
@@ -37,11 +48,15 @@ func ToProductResponse(p *Product) ProductResponse {
 
 	return ProductResponse{
 		ID:          p.ID,
+		Version:     p.Version,
 		BusinessID:  p.BusinessID,
 		Name:        p.Name,
 		Description: p.Description,
+		Type:        p.Type,
+		Download:    p.Download,
 		Photos:      photos,
 		CategoryID:  p.CategoryID,
+		Options:     options,
 		Variants:    variants,
 		CreatedAt:   p.CreatedAt,
 		UpdatedAt:   p.UpdatedAt,
@@ -57,54 +72,269 @@ func ToProductResponses(products []*Product) []ProductResponse {
 	return responses
 }
 
+// ProductSummaryResponse is the lean list representation of a product.
+// It is returned by the products list when view=summary; variants are collapsed into aggregates.
+type ProductSummaryResponse struct {
+	ID            string                 `json:"id"`
+	BusinessID    string                 `json:"businessId"`
+	Name          string                 `json:"name"`
+	Photos        []asset.AssetReference `json:"photos"`
+	CategoryID    string                 `json:"categoryId"`
+	VariantsCount int                    `json:"variantsCount"`
+	TotalStock    int                    `json:"totalStock"`
+	StockStatus   StockStatus            `json:"stockStatus"`
+	MinSalePrice  decimal.Decimal        `json:"minSalePrice"`
+	MaxSalePrice  decimal.Decimal        `json:"maxSalePrice"`
+	Currency      string                 `json:"currency,omitempty"`
+	CreatedAt     time.Time              `json:"createdAt"`
+	UpdatedAt     time.Time              `json:"updatedAt"`
+}
+
+// ToProductSummaryResponse converts Product model to ProductSummaryResponse
+func ToProductSummaryResponse(p *Product) ProductSummaryResponse {
+	photos := []asset.AssetReference{}
+	if p.Photos != nil {
+		photos = []asset.AssetReference(p.Photos)
+	}
+
+	resp := ProductSummaryResponse{
+		ID:            p.ID,
+		BusinessID:    p.BusinessID,
+		Name:          p.Name,
+		Photos:        photos,
+		CategoryID:    p.CategoryID,
+		VariantsCount: len(p.Variants),
+		StockStatus:   StockStatusOutOfStock,
+		MinSalePrice:  decimal.Zero,
+		MaxSalePrice:  decimal.Zero,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+
+	lowStock := false
+	for i, v := range p.Variants {
+		resp.TotalStock += v.StockQuantity
+		if v.StockQuantity > 0 && v.StockQuantity <= v.StockQuantityAlert {
+			lowStock = true
+		}
+		if i == 0 {
+			resp.MinSalePrice = v.SalePrice
+			resp.MaxSalePrice = v.SalePrice
+			resp.Currency = v.Currency
+			continue
+		}
+		if v.SalePrice.LessThan(resp.MinSalePrice) {
+			resp.MinSalePrice = v.SalePrice
+		}
+		if v.SalePrice.GreaterThan(resp.MaxSalePrice) {
+			resp.MaxSalePrice = v.SalePrice
+		}
+	}
+	switch {
+	case resp.TotalStock == 0:
+		resp.StockStatus = StockStatusOutOfStock
+	case lowStock:
+		resp.StockStatus = StockStatusLowStock
+	default:
+		resp.StockStatus = StockStatusInStock
+	}
+
+	return resp
+}
+
+// ToProductSummaryResponses converts a slice of Product models to summary responses
+func ToProductSummaryResponses(products []*Product) []ProductSummaryResponse {
+	responses := make([]ProductSummaryResponse, len(products))
+	for i, p := range products {
+		responses[i] = ToProductSummaryResponse(p)
+	}
+	return responses
+}
+
 // VariantResponse is the API response for Variant entity
 type VariantResponse struct {
+	ID         string          `json:"id"`
+	BusinessID string          `json:"businessId"`
+	Name       string          `json:"name"`
+	Code       string          `json:"code"`
+	ProductID  string          `json:"productId"`
+	SKU        string          `json:"sku"`
+	Barcode    string          `json:"barcode"`
+	Options    []VariantOption `json:"options"`
+	CostPrice  decimal.Decimal `json:"costPrice"`
+	// LandedCost is the unit cost of the stock on hand from its purchase cost layers.
+	LandedCost    decimal.Decimal  `json:"landedCost"`
+	SalePrice     decimal.Decimal  `json:"salePrice"`
+	PromoPrice    *decimal.Decimal `json:"promoPrice,omitempty"`
+	PromoStartsAt *time.Time       `json:"promoStartsAt,omitempty"`
+	PromoEndsAt   *time.Time       `json:"promoEndsAt,omitempty"`
+	// CurrentPrice is PromoPrice while the promo runs, otherwise SalePrice.
+	CurrentPrice        decimal.Decimal        `json:"currentPrice"`
+	Currency            string                 `json:"currency"`
+	Photos              []asset.AssetReference `json:"photos"`
+	StockQuantity       int                    `json:"stockQuantity"`
+	StockQuantityAlert  int                    `json:"stockQuantityAlert"`
+	PreferredSupplierID string                 `json:"preferredSupplierId"`
+	LeadTimeDays        int                    `json:"leadTimeDays"`
+	ReorderPoint        int                    `json:"reorderPoint"`
+	ReorderQuantity     int                    `json:"reorderQuantity"`
+	CreatedAt           time.Time              `json:"createdAt"`
+	UpdatedAt           time.Time              `json:"updatedAt"`
+}
+
+// ToVariantResponse converts Variant model to VariantResponse
+func ToVariantResponse(v *Variant) VariantResponse {
+	photos := []asset.AssetReference{}
+	if v.Photos != nil {
+		photos = []asset.AssetReference(v.Photos)
+	}
+
+	var promoPrice *decimal.Decimal
+	if v.PromoPrice.Valid {
+		promoPrice = &v.PromoPrice.Decimal
+	}
+	options := []VariantOption{}
+	if v.Options != nil {
+		options = []VariantOption(v.Options)
+	}
+
+	return VariantResponse{
+		ID:                  v.ID,
+		BusinessID:          v.BusinessID,
+		Name:                v.Name,
+		Code:                v.Code,
+		ProductID:           v.ProductID,
+		SKU:                 v.SKU,
+		Barcode:             v.Barcode,
+		Options:             options,
+		CostPrice:           v.CostPrice,
+		LandedCost:          v.CurrentCost(),
+		SalePrice:           v.SalePrice,
+		PromoPrice:          promoPrice,
+		PromoStartsAt:       v.PromoStartsAt,
+		PromoEndsAt:         v.PromoEndsAt,
+		CurrentPrice:        v.CurrentPrice(time.Now()),
+		Currency:            v.Currency,
+		Photos:              photos,
+		StockQuantity:       v.StockQuantity,
+		StockQuantityAlert:  v.StockQuantityAlert,
+		PreferredSupplierID: v.PreferredSupplierID,
+		LeadTimeDays:        v.LeadTimeDays,
+		ReorderPoint:        v.ReorderPoint,
+		ReorderQuantity:     v.ReorderQuantity,
+		CreatedAt:           v.CreatedAt,
+		UpdatedAt:           v.UpdatedAt,
+	}
+}
+
+// ToVariantResponses converts a slice of Variant models to responses
+func ToVariantResponses(variants []*Variant) []VariantResponse {
+	responses := make([]VariantResponse, len(variants))
+	for i, v := range variants {
+		responses[i] = ToVariantResponse(v)
+	}
+	return responses
+}
+
+// SearchResultResponse is one match of an inventory search. Product results carry their
+// variants; variant results carry the variant and its product.
+type SearchResultResponse struct {
+	Type    SearchResultType `json:"type"`
+	Score   float64          `json:"score"`
+	Product *ProductResponse `json:"product,omitempty"`
+	Variant *VariantResponse `json:"variant,omitempty"`
+}
+
+// SearchResponse is the ranked mixed product and variant result list of an inventory search.
+type SearchResponse struct {
+	Query string                 `json:"query"`
+	Items []SearchResultResponse `json:"items"`
+}
+
+// ToSearchResponse converts inventory search results to the API response
+func ToSearchResponse(query string, results []*SearchResult) SearchResponse {
+	resp := SearchResponse{Query: query, Items: make([]SearchResultResponse, len(results))}
+	for i, r := range results {
+		item := SearchResultResponse{Type: r.Type, Score: math.Round(r.Score*10000) / 10000}
+		if r.Product != nil {
+			product := ToProductResponse(r.Product)
+			item.Product = &product
+		}
+		if r.Variant != nil {
+			variant := ToVariantResponse(r.Variant)
+			item.Variant = &variant
+		}
+		resp.Items[i] = item
+	}
+	return resp
+}
+
+// VariantPickerResponse is the lean representation of a variant for the order-creation picker.
+// Available is what can still be sold; Reserved is held by open orders; OnHand is both together.
+type VariantPickerResponse struct {
 	ID                 string                 `json:"id"`
-	BusinessID         string                 `json:"businessId"`
+	ProductID          string                 `json:"productId"`
+	ProductName        string                 `json:"productName"`
 	Name               string                 `json:"name"`
 	Code               string                 `json:"code"`
-	ProductID          string                 `json:"productId"`
 	SKU                string                 `json:"sku"`
-	CostPrice          decimal.Decimal        `json:"costPrice"`
+	Barcode            string                 `json:"barcode"`
 	SalePrice          decimal.Decimal        `json:"salePrice"`
+	CurrentPrice       decimal.Decimal        `json:"currentPrice"`
 	Currency           string                 `json:"currency"`
 	Photos             []asset.AssetReference `json:"photos"`
-	StockQuantity      int                    `json:"stockQuantity"`
+	OnHand             int                    `json:"onHand"`
+	Reserved           int                    `json:"reserved"`
+	Available          int                    `json:"available"`
 	StockQuantityAlert int                    `json:"stockQuantityAlert"`
-	CreatedAt          time.Time              `json:"createdAt"`
-	UpdatedAt          time.Time              `json:"updatedAt"`
+	StockStatus        StockStatus            `json:"stockStatus"`
 }
 
-// ToVariantResponse converts Variant model to VariantResponse
-func ToVariantResponse(v *Variant) VariantResponse {
+// ToVariantPickerResponse converts a PickerVariant to VariantPickerResponse
+func ToVariantPickerResponse(pv *PickerVariant) VariantPickerResponse {
+	v := pv.Variant
 	photos := []asset.AssetReference{}
 	if v.Photos != nil {
 		photos = []asset.AssetReference(v.Photos)
 	}
+	productName := ""
+	if v.Product != nil {
+		productName = v.Product.Name
+	}
 
-	return VariantResponse{
+	status := StockStatusInStock
+	switch {
+	case v.StockQuantity <= 0:
+		status = StockStatusOutOfStock
+	case v.StockQuantity <= v.StockQuantityAlert:
+		status = StockStatusLowStock
+	}
+
+	return VariantPickerResponse{
 		ID:                 v.ID,
-		BusinessID:         v.BusinessID,
+		ProductID:          v.ProductID,
+		ProductName:        productName,
 		Name:               v.Name,
 		Code:               v.Code,
-		ProductID:          v.ProductID,
 		SKU:                v.SKU,
-		CostPrice:          v.CostPrice,
+		Barcode:            v.Barcode,
 		SalePrice:          v.SalePrice,
+		CurrentPrice:       v.CurrentPrice(time.Now()),
 		Currency:           v.Currency,
 		Photos:             photos,
-		StockQuantity:      v.StockQuantity,
+		OnHand:             v.StockQuantity + pv.Reserved,
+		Reserved:           pv.Reserved,
+		Available:          v.StockQuantity,
 		StockQuantityAlert: v.StockQuantityAlert,
-		CreatedAt:          v.CreatedAt,
-		UpdatedAt:          v.UpdatedAt,
+		StockStatus:        status,
 	}
 }
 
-// ToVariantResponses converts a slice of Variant models to responses
-func ToVariantResponses(variants []*Variant) []VariantResponse {
-	responses := make([]VariantResponse, len(variants))
-	for i, v := range variants {
-		responses[i] = ToVariantResponse(v)
+// ToVariantPickerResponses converts a slice of PickerVariants to responses
+func ToVariantPickerResponses(items []*PickerVariant) []VariantPickerResponse {
+	responses := make([]VariantPickerResponse, len(items))
+	for i, pv := range items {
+		responses[i] = ToVariantPickerResponse(pv)
 	}
 	return responses
 }
@@ -140,6 +370,58 @@ func ToCategoryResponses(categories []*Category) []CategoryResponse {
 	return responses
 }
 
+// PriceListEntryResponse is the API response for PriceListEntry entity
+type PriceListEntryResponse struct {
+	VariantID      string          `json:"variantId"`
+	Price          decimal.Decimal `json:"price"`
+	QuantityBreaks []PriceBreak    `json:"quantityBreaks"`
+}
+
+// PriceListResponse is the API response for PriceList entity
+type PriceListResponse struct {
+	ID                string                   `json:"id"`
+	BusinessID        string                   `json:"businessId"`
+	Name              string                   `json:"name"`
+	Description       string                   `json:"description"`
+	IsDefault         bool                     `json:"isDefault"`
+	PercentAdjustment decimal.Decimal          `json:"percentAdjustment"`
+	Entries           []PriceListEntryResponse `json:"entries,omitempty"`
+	CreatedAt         time.Time                `json:"createdAt"`
+	UpdatedAt         time.Time                `json:"updatedAt"`
+}
+
+// ToPriceListResponse converts PriceList model to PriceListResponse
+func ToPriceListResponse(pl *PriceList) PriceListResponse {
+	entries := make([]PriceListEntryResponse, len(pl.Entries))
+	for i, e := range pl.Entries {
+		breaks := []PriceBreak{}
+		if e.QuantityBreaks != nil {
+			breaks = []PriceBreak(e.QuantityBreaks)
+		}
+		entries[i] = PriceListEntryResponse{VariantID: e.VariantID, Price: e.Price, QuantityBreaks: breaks}
+	}
+	return PriceListResponse{
+		ID:                pl.ID,
+		BusinessID:        pl.BusinessID,
+		Name:              pl.Name,
+		Description:       pl.Description,
+		IsDefault:         pl.IsDefault,
+		PercentAdjustment: pl.PercentAdjustment,
+		Entries:           entries,
+		CreatedAt:         pl.CreatedAt,
+		UpdatedAt:         pl.UpdatedAt,
+	}
+}
+
+// ToPriceListResponses converts a slice of PriceList models to responses
+func ToPriceListResponses(lists []*PriceList) []PriceListResponse {
+	responses := make([]PriceListResponse, len(lists))
+	for i, pl := range lists {
+		responses[i] = ToPriceListResponse(pl)
+	}
+	return responses
+}
+
 // TopProductByInventoryValue represents a product with its computed inventory value
 type TopProductByInventoryValue struct {
 	Product        ProductResponse `json:"product"`
@@ -153,3 +435,760 @@ func ToTopProductByInventoryValue(product *Product, inventoryValue decimal.Decim
 		InventoryValue: inventoryValue,
 	}
 }
+
+// FlashSaleResponse lists the variants a flash sale was applied to.
+type FlashSaleResponse struct {
+	CategoryID string            `json:"categoryId"`
+	Variants   []VariantResponse `json:"variants"`
+}
+
+// ReorderSuggestionResponse is one line of the purchase list.
+type ReorderSuggestionResponse struct {
+	VariantID          string `json:"variantId"`
+	ProductID          string `json:"productId"`
+	ProductName        string `json:"productName"`
+	Name               string `json:"name"`
+	SKU                string `json:"sku"`
+	StockQuantity      int    `json:"stockQuantity"`
+	StockQuantityAlert int    `json:"stockQuantityAlert"`
+	// SupplierID is the variant's preferred supplier, empty when it has none.
+	SupplierID            string          `json:"supplierId"`
+	SupplierName          string          `json:"supplierName"`
+	LeadTimeDays          int             `json:"leadTimeDays"`
+	ReorderPoint          int             `json:"reorderPoint"`
+	ReorderQuantity       int             `json:"reorderQuantity"`
+	UnitsSold             int             `json:"unitsSold"`
+	DailyVelocity         float64         `json:"dailyVelocity"`
+	DaysOfStock           *float64        `json:"daysOfStock"`
+	ProjectedStock        int             `json:"projectedStock"`
+	RunsOutWithinLeadTime bool            `json:"runsOutWithinLeadTime"`
+	SuggestedQuantity     int             `json:"suggestedQuantity"`
+	CostPrice             decimal.Decimal `json:"costPrice"`
+	EstimatedCost         decimal.Decimal `json:"estimatedCost"`
+	Currency              string          `json:"currency"`
+}
+
+// ReorderSuggestionsResponse is the ranked purchase list with the parameters it was computed with.
+type ReorderSuggestionsResponse struct {
+	WindowDays   int                         `json:"windowDays"`
+	LeadTimeDays int                         `json:"leadTimeDays"`
+	CoverDays    int                         `json:"coverDays"`
+	Items        []ReorderSuggestionResponse `json:"items"`
+}
+
+// ToReorderSuggestionsResponse converts reorder suggestions to the purchase list response
+func ToReorderSuggestionsResponse(opts *ReorderSuggestionsOptions, items []*ReorderSuggestion) ReorderSuggestionsResponse {
+	resp := ReorderSuggestionsResponse{
+		WindowDays:   opts.WindowDays,
+		LeadTimeDays: opts.LeadTimeDays,
+		CoverDays:    opts.CoverDays,
+		Items:        make([]ReorderSuggestionResponse, len(items)),
+	}
+	for i, sg := range items {
+		v := sg.Variant
+		productName := ""
+		if v.Product != nil {
+			productName = v.Product.Name
+		}
+		supplierName := ""
+		if sg.Supplier != nil {
+			supplierName = sg.Supplier.Name
+		}
+		var daysOfStock *float64
+		if sg.DaysOfStock != nil {
+			d := math.Round(*sg.DaysOfStock*10) / 10
+			daysOfStock = &d
+		}
+		resp.Items[i] = ReorderSuggestionResponse{
+			VariantID:             v.ID,
+			ProductID:             v.ProductID,
+			ProductName:           productName,
+			Name:                  v.Name,
+			SKU:                   v.SKU,
+			StockQuantity:         v.StockQuantity,
+			StockQuantityAlert:    v.StockQuantityAlert,
+			SupplierID:            v.PreferredSupplierID,
+			SupplierName:          supplierName,
+			LeadTimeDays:          sg.LeadTimeDays,
+			ReorderPoint:          v.ReorderPoint,
+			ReorderQuantity:       v.ReorderQuantity,
+			UnitsSold:             sg.UnitsSold,
+			DailyVelocity:         math.Round(sg.DailyVelocity*100) / 100,
+			DaysOfStock:           daysOfStock,
+			ProjectedStock:        sg.ProjectedStock,
+			RunsOutWithinLeadTime: sg.RunsOutWithinLeadTime(),
+			SuggestedQuantity:     sg.SuggestedQuantity,
+			CostPrice:             v.CostPrice,
+			EstimatedCost:         v.CostPrice.Mul(decimal.NewFromInt(int64(sg.SuggestedQuantity))),
+			Currency:              v.Currency,
+		}
+	}
+	return resp
+}
+
+// VariantCostChangeResponse is one entry of a variant's price history.
+type VariantCostChangeResponse struct {
+	ID                string              `json:"id"`
+	PreviousCostPrice decimal.NullDecimal `json:"previousCostPrice"`
+	CostPrice         decimal.Decimal     `json:"costPrice"`
+	PreviousSalePrice decimal.NullDecimal `json:"previousSalePrice"`
+	SalePrice         decimal.Decimal     `json:"salePrice"`
+	MarginPercent     decimal.NullDecimal `json:"marginPercent"`
+	BelowMinMargin    bool                `json:"belowMinMargin"`
+	ChangedByID       string              `json:"changedById,omitempty"`
+	ChangedAt         time.Time           `json:"changedAt"`
+}
+
+// CostTrendPeriodResponse is a month of sales at the cost and price effective at sale time.
+type CostTrendPeriodResponse struct {
+	Period        time.Time           `json:"period"`
+	UnitsSold     int64               `json:"unitsSold"`
+	Revenue       decimal.Decimal     `json:"revenue"`
+	COGS          decimal.Decimal     `json:"cogs"`
+	AvgUnitCost   decimal.Decimal     `json:"avgUnitCost"`
+	AvgUnitPrice  decimal.Decimal     `json:"avgUnitPrice"`
+	MarginPercent decimal.NullDecimal `json:"marginPercent"`
+}
+
+// VariantCostTrendResponse is a variant's price history and realized margin per month.
+type VariantCostTrendResponse struct {
+	VariantID     string                      `json:"variantId"`
+	ProductID     string                      `json:"productId"`
+	Name          string                      `json:"name"`
+	SKU           string                      `json:"sku"`
+	Currency      string                      `json:"currency"`
+	CostPrice     decimal.Decimal             `json:"costPrice"`
+	SalePrice     decimal.Decimal             `json:"salePrice"`
+	MarginPercent decimal.NullDecimal         `json:"marginPercent"`
+	From          time.Time                   `json:"from"`
+	To            time.Time                   `json:"to"`
+	Changes       []VariantCostChangeResponse `json:"changes"`
+	Periods       []CostTrendPeriodResponse   `json:"periods"`
+}
+
+// ToVariantCostTrendResponse converts a cost trend to its response
+func ToVariantCostTrendResponse(t *CostTrend) VariantCostTrendResponse {
+	v := t.Variant
+	resp := VariantCostTrendResponse{
+		VariantID:     v.ID,
+		ProductID:     v.ProductID,
+		Name:          v.Name,
+		SKU:           v.SKU,
+		Currency:      v.Currency,
+		CostPrice:     v.CostPrice,
+		SalePrice:     v.SalePrice,
+		MarginPercent: marginPercent(v.CostPrice, v.SalePrice),
+		From:          t.From,
+		To:            t.To,
+		Changes:       make([]VariantCostChangeResponse, len(t.Changes)),
+		Periods:       make([]CostTrendPeriodResponse, len(t.Periods)),
+	}
+	for i, c := range t.Changes {
+		resp.Changes[i] = VariantCostChangeResponse{
+			ID:                c.ID,
+			PreviousCostPrice: c.PreviousCostPrice,
+			CostPrice:         c.CostPrice,
+			PreviousSalePrice: c.PreviousSalePrice,
+			SalePrice:         c.SalePrice,
+			MarginPercent:     c.MarginPercent,
+			BelowMinMargin:    c.BelowMinMargin,
+			ChangedByID:       c.ChangedByID,
+			ChangedAt:         c.ChangedAt,
+		}
+	}
+	for i, p := range t.Periods {
+		resp.Periods[i] = CostTrendPeriodResponse{
+			Period:        p.Period,
+			UnitsSold:     p.UnitsSold,
+			Revenue:       p.Revenue,
+			COGS:          p.COGS,
+			AvgUnitCost:   p.AvgUnitCost,
+			AvgUnitPrice:  p.AvgUnitPrice,
+			MarginPercent: p.MarginPercent,
+		}
+	}
+	return resp
+}
+
+// SupplierResponse is the API response for Supplier entity
+type SupplierResponse struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ContactName string `json:"contactName"`
+	Email       string `json:"email"`
+	Phone       string `json:"phone"`
+	Notes       string `json:"notes"`
+	// AmountOutstanding is what the business owes the supplier for received and unpaid goods.
+	AmountOutstanding decimal.Decimal `json:"amountOutstanding"`
+	CreatedAt         time.Time       `json:"createdAt"`
+	UpdatedAt         time.Time       `json:"updatedAt"`
+}
+
+// ToSupplierResponse converts Supplier model to SupplierResponse
+func ToSupplierResponse(s *Supplier, outstanding decimal.Decimal) SupplierResponse {
+	return SupplierResponse{
+		ID:                s.ID,
+		Name:              s.Name,
+		ContactName:       s.ContactName,
+		Email:             s.Email,
+		Phone:             s.Phone,
+		Notes:             s.Notes,
+		AmountOutstanding: outstanding,
+		CreatedAt:         s.CreatedAt,
+		UpdatedAt:         s.UpdatedAt,
+	}
+}
+
+// ToSupplierResponses converts suppliers to responses with their outstanding amounts, keyed by supplier ID.
+func ToSupplierResponses(suppliers []*Supplier, payables map[string]decimal.Decimal) []SupplierResponse {
+	responses := make([]SupplierResponse, len(suppliers))
+	for i, s := range suppliers {
+		responses[i] = ToSupplierResponse(s, payables[s.ID])
+	}
+	return responses
+}
+
+// PurchaseOrderItemResponse is the API response for PurchaseOrderItem entity
+type PurchaseOrderItemResponse struct {
+	ID               string          `json:"id"`
+	VariantID        string          `json:"variantId"`
+	Quantity         int             `json:"quantity"`
+	ReceivedQuantity int             `json:"receivedQuantity"`
+	UnitCost         decimal.Decimal `json:"unitCost"`
+	Total            decimal.Decimal `json:"total"`
+}
+
+// PurchaseOrderResponse is the API response for PurchaseOrder entity
+type PurchaseOrderResponse struct {
+	ID                string                      `json:"id"`
+	SupplierID        string                      `json:"supplierId"`
+	SupplierName      string                      `json:"supplierName,omitempty"`
+	Reference         string                      `json:"reference"`
+	Status            PurchaseOrderStatus         `json:"status"`
+	Currency          string                      `json:"currency"`
+	Total             decimal.Decimal             `json:"total"`
+	ReceivedTotal     decimal.Decimal             `json:"receivedTotal"`
+	AmountPaid        decimal.Decimal             `json:"amountPaid"`
+	AmountOutstanding decimal.Decimal             `json:"amountOutstanding"`
+	ExpectedAt        *time.Time                  `json:"expectedAt,omitempty"`
+	OrderedAt         time.Time                   `json:"orderedAt"`
+	ReceivedAt        *time.Time                  `json:"receivedAt,omitempty"`
+	CancelledAt       *time.Time                  `json:"cancelledAt,omitempty"`
+	Notes             string                      `json:"notes"`
+	Items             []PurchaseOrderItemResponse `json:"items,omitempty"`
+	CreatedAt         time.Time                   `json:"createdAt"`
+	UpdatedAt         time.Time                   `json:"updatedAt"`
+}
+
+// ToPurchaseOrderResponse converts PurchaseOrder model to PurchaseOrderResponse
+func ToPurchaseOrderResponse(po *PurchaseOrder) PurchaseOrderResponse {
+	items := make([]PurchaseOrderItemResponse, len(po.Items))
+	for i, it := range po.Items {
+		items[i] = PurchaseOrderItemResponse{
+			ID:               it.ID,
+			VariantID:        it.VariantID,
+			Quantity:         it.Quantity,
+			ReceivedQuantity: it.ReceivedQuantity,
+			UnitCost:         it.UnitCost,
+			Total:            it.Total,
+		}
+	}
+	resp := PurchaseOrderResponse{
+		ID:                po.ID,
+		SupplierID:        po.SupplierID,
+		Reference:         po.Reference,
+		Status:            po.Status,
+		Currency:          po.Currency,
+		Total:             po.Total,
+		ReceivedTotal:     po.ReceivedTotal,
+		AmountPaid:        po.AmountPaid,
+		AmountOutstanding: po.AmountOutstanding(),
+		ExpectedAt:        po.ExpectedAt,
+		OrderedAt:         po.OrderedAt,
+		ReceivedAt:        po.ReceivedAt,
+		CancelledAt:       po.CancelledAt,
+		Notes:             po.Notes,
+		Items:             items,
+		CreatedAt:         po.CreatedAt,
+		UpdatedAt:         po.UpdatedAt,
+	}
+	if po.Supplier != nil {
+		resp.SupplierName = po.Supplier.Name
+	}
+	return resp
+}
+
+// ToPurchaseOrderResponses converts a slice of PurchaseOrder models to responses
+func ToPurchaseOrderResponses(orders []*PurchaseOrder) []PurchaseOrderResponse {
+	responses := make([]PurchaseOrderResponse, len(orders))
+	for i, po := range orders {
+		responses[i] = ToPurchaseOrderResponse(po)
+	}
+	return responses
+}
+
+// PurchaseOrderReceiptResponse is the API response for PurchaseOrderReceipt entity
+type PurchaseOrderReceiptResponse struct {
+	ID           string          `json:"id"`
+	ItemID       string          `json:"itemId"`
+	VariantID    string          `json:"variantId"`
+	Quantity     int             `json:"quantity"`
+	UnitCost     decimal.Decimal `json:"unitCost"`
+	ReceivedByID string          `json:"receivedById"`
+	ReceivedAt   time.Time       `json:"receivedAt"`
+}
+
+// ToPurchaseOrderReceiptResponses converts a slice of PurchaseOrderReceipt models to responses
+func ToPurchaseOrderReceiptResponses(receipts []*PurchaseOrderReceipt) []PurchaseOrderReceiptResponse {
+	responses := make([]PurchaseOrderReceiptResponse, len(receipts))
+	for i, r := range receipts {
+		responses[i] = PurchaseOrderReceiptResponse{
+			ID:           r.ID,
+			ItemID:       r.ItemID,
+			VariantID:    r.VariantID,
+			Quantity:     r.Quantity,
+			UnitCost:     r.UnitCost,
+			ReceivedByID: r.ReceivedByID,
+			ReceivedAt:   r.ReceivedAt,
+		}
+	}
+	return responses
+}
+
+// SupplierBillResponse is the API response for SupplierBill entity
+type SupplierBillResponse struct {
+	ID                string                    `json:"id"`
+	SupplierID        string                    `json:"supplierId"`
+	SupplierName      string                    `json:"supplierName,omitempty"`
+	PurchaseOrderID   string                    `json:"purchaseOrderId"`
+	Reference         string                    `json:"reference"`
+	Currency          string                    `json:"currency"`
+	Amount            decimal.Decimal           `json:"amount"`
+	AmountPaid        decimal.Decimal           `json:"amountPaid"`
+	AmountOutstanding decimal.Decimal           `json:"amountOutstanding"`
+	PaymentStatus     SupplierBillPaymentStatus `json:"paymentStatus"`
+	IssuedAt          time.Time                 `json:"issuedAt"`
+	DueAt             time.Time                 `json:"dueAt"`
+	PaidAt            *time.Time                `json:"paidAt,omitempty"`
+	Notes             string                    `json:"notes"`
+	CreatedAt         time.Time                 `json:"createdAt"`
+	UpdatedAt         time.Time                 `json:"updatedAt"`
+}
+
+// ToSupplierBillResponse converts SupplierBill model to SupplierBillResponse
+func ToSupplierBillResponse(b *SupplierBill) SupplierBillResponse {
+	resp := SupplierBillResponse{
+		ID:                b.ID,
+		SupplierID:        b.SupplierID,
+		PurchaseOrderID:   b.PurchaseOrderID,
+		Reference:         b.Reference,
+		Currency:          b.Currency,
+		Amount:            b.Amount,
+		AmountPaid:        b.AmountPaid,
+		AmountOutstanding: b.AmountOutstanding(),
+		PaymentStatus:     b.PaymentStatus,
+		IssuedAt:          b.IssuedAt,
+		DueAt:             b.DueAt,
+		PaidAt:            b.PaidAt,
+		Notes:             b.Notes,
+		CreatedAt:         b.CreatedAt,
+		UpdatedAt:         b.UpdatedAt,
+	}
+	if b.Supplier != nil {
+		resp.SupplierName = b.Supplier.Name
+	}
+	return resp
+}
+
+// ToSupplierBillResponses converts a slice of SupplierBill models to responses
+func ToSupplierBillResponses(bills []*SupplierBill) []SupplierBillResponse {
+	responses := make([]SupplierBillResponse, len(bills))
+	for i, b := range bills {
+		responses[i] = ToSupplierBillResponse(b)
+	}
+	return responses
+}
+
+// SupplierBillPaymentResponse is the API response for SupplierBillPayment entity
+type SupplierBillPaymentResponse struct {
+	ID           string          `json:"id"`
+	BillID       string          `json:"billId"`
+	Amount       decimal.Decimal `json:"amount"`
+	PaidAt       time.Time       `json:"paidAt"`
+	Note         string          `json:"note"`
+	RecordedByID string          `json:"recordedById"`
+}
+
+// ToSupplierBillPaymentResponses converts a slice of SupplierBillPayment models to responses
+func ToSupplierBillPaymentResponses(payments []*SupplierBillPayment) []SupplierBillPaymentResponse {
+	responses := make([]SupplierBillPaymentResponse, len(payments))
+	for i, p := range payments {
+		responses[i] = SupplierBillPaymentResponse{
+			ID:           p.ID,
+			BillID:       p.BillID,
+			Amount:       p.Amount,
+			PaidAt:       p.PaidAt,
+			Note:         p.Note,
+			RecordedByID: p.RecordedByID,
+		}
+	}
+	return responses
+}
+
+// SupplierBillAgingBucketsResponse splits what is owed on bills by days past due.
+type SupplierBillAgingBucketsResponse struct {
+	Current    decimal.Decimal `json:"current"`
+	Days1To30  decimal.Decimal `json:"days1To30"`
+	Days31To60 decimal.Decimal `json:"days31To60"`
+	Days61To90 decimal.Decimal `json:"days61To90"`
+	Over90     decimal.Decimal `json:"over90"`
+	Total      decimal.Decimal `json:"total"`
+}
+
+// SupplierBillAgingLineResponse is the aging of what is owed to one supplier.
+type SupplierBillAgingLineResponse struct {
+	SupplierID   string `json:"supplierId"`
+	SupplierName string `json:"supplierName"`
+	SupplierBillAgingBucketsResponse
+}
+
+// SupplierBillAgingResponse is the accounts payable aging report as of a date.
+type SupplierBillAgingResponse struct {
+	AsOf     time.Time `json:"asOf"`
+	Currency string    `json:"currency"`
+	SupplierBillAgingBucketsResponse
+	// UnbilledPurchaseOrders is owed for received goods of purchase orders not billed yet.
+	UnbilledPurchaseOrders decimal.Decimal                 `json:"unbilledPurchaseOrders"`
+	Suppliers              []SupplierBillAgingLineResponse `json:"suppliers"`
+}
+
+func toSupplierBillAgingBucketsResponse(b SupplierBillAgingBuckets) SupplierBillAgingBucketsResponse {
+	return SupplierBillAgingBucketsResponse{
+		Current:    b.Current,
+		Days1To30:  b.Days1To30,
+		Days31To60: b.Days31To60,
+		Days61To90: b.Days61To90,
+		Over90:     b.Over90,
+		Total:      b.Total,
+	}
+}
+
+// ToSupplierBillAgingResponse converts a bill aging report to its response
+func ToSupplierBillAgingResponse(a *SupplierBillAging) SupplierBillAgingResponse {
+	resp := SupplierBillAgingResponse{
+		AsOf:                             a.AsOf,
+		Currency:                         a.Currency,
+		SupplierBillAgingBucketsResponse: toSupplierBillAgingBucketsResponse(a.SupplierBillAgingBuckets),
+		UnbilledPurchaseOrders:           a.UnbilledPurchaseOrders,
+		Suppliers:                        make([]SupplierBillAgingLineResponse, len(a.Suppliers)),
+	}
+	for i, l := range a.Suppliers {
+		resp.Suppliers[i] = SupplierBillAgingLineResponse{
+			SupplierID:                       l.SupplierID,
+			SupplierName:                     l.SupplierName,
+			SupplierBillAgingBucketsResponse: toSupplierBillAgingBucketsResponse(l.SupplierBillAgingBuckets),
+		}
+	}
+	return resp
+}
+
+// LocationResponse is the API response for Location entity
+type LocationResponse struct {
+	ID         string    `json:"id"`
+	BusinessID string    `json:"businessId"`
+	Name       string    `json:"name"`
+	Address    string    `json:"address"`
+	IsDefault  bool      `json:"isDefault"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// ToLocationResponse converts Location model to LocationResponse
+func ToLocationResponse(loc *Location) LocationResponse {
+	return LocationResponse{
+		ID:         loc.ID,
+		BusinessID: loc.BusinessID,
+		Name:       loc.Name,
+		Address:    loc.Address,
+		IsDefault:  loc.IsDefault,
+		CreatedAt:  loc.CreatedAt,
+		UpdatedAt:  loc.UpdatedAt,
+	}
+}
+
+// ToLocationResponses converts a slice of Location models to responses
+func ToLocationResponses(locations []*Location) []LocationResponse {
+	responses := make([]LocationResponse, len(locations))
+	for i, loc := range locations {
+		responses[i] = ToLocationResponse(loc)
+	}
+	return responses
+}
+
+// LocationStockLevelResponse is the quantity of a variant kept at a location
+type LocationStockLevelResponse struct {
+	LocationID   string `json:"locationId"`
+	LocationName string `json:"locationName"`
+	IsDefault    bool   `json:"isDefault"`
+	Quantity     int    `json:"quantity"`
+}
+
+// ToLocationStockLevelResponses converts per-location stock levels to responses
+func ToLocationStockLevelResponses(levels []*LocationStockLevel) []LocationStockLevelResponse {
+	responses := make([]LocationStockLevelResponse, len(levels))
+	for i, l := range levels {
+		responses[i] = LocationStockLevelResponse{
+			LocationID:   l.Location.ID,
+			LocationName: l.Location.Name,
+			IsDefault:    l.Location.IsDefault,
+			Quantity:     l.Quantity,
+		}
+	}
+	return responses
+}
+
+// LocationStockSummaryResponse is the stock a location holds across all variants
+type LocationStockSummaryResponse struct {
+	LocationID      string          `json:"locationId"`
+	LocationName    string          `json:"locationName"`
+	IsDefault       bool            `json:"isDefault"`
+	TotalStockUnits int64           `json:"totalStockUnits"`
+	InventoryValue  decimal.Decimal `json:"inventoryValue"`
+}
+
+// ToLocationStockSummaryResponses converts per-location stock totals to responses
+func ToLocationStockSummaryResponses(totals []*LocationStockTotals) []LocationStockSummaryResponse {
+	responses := make([]LocationStockSummaryResponse, len(totals))
+	for i, t := range totals {
+		responses[i] = LocationStockSummaryResponse{
+			LocationID:      t.Location.ID,
+			LocationName:    t.Location.Name,
+			IsDefault:       t.Location.IsDefault,
+			TotalStockUnits: t.StockUnits,
+			InventoryValue:  t.InventoryValue,
+		}
+	}
+	return responses
+}
+
+// CatalogImportResponse is the outcome of a catalog import or dry run.
+type CatalogImportResponse struct {
+	DryRun            bool                    `json:"dryRun"`
+	Rows              int                     `json:"rows"`
+	CategoriesCreated int                     `json:"categoriesCreated"`
+	ProductsCreated   int                     `json:"productsCreated"`
+	ProductsUpdated   int                     `json:"productsUpdated"`
+	VariantsCreated   int                     `json:"variantsCreated"`
+	VariantsUpdated   int                     `json:"variantsUpdated"`
+	Errors            []CatalogImportRowError `json:"errors"`
+}
+
+// ToCatalogImportResponse converts an import result to its response
+func ToCatalogImportResponse(r *CatalogImportResult) CatalogImportResponse {
+	errs := r.Errors
+	if errs == nil {
+		errs = []CatalogImportRowError{}
+	}
+	return CatalogImportResponse{
+		DryRun:            r.DryRun,
+		Rows:              r.Rows,
+		CategoriesCreated: r.CategoriesCreated,
+		ProductsCreated:   r.ProductsCreated,
+		ProductsUpdated:   r.ProductsUpdated,
+		VariantsCreated:   r.VariantsCreated,
+		VariantsUpdated:   r.VariantsUpdated,
+		Errors:            errs,
+	}
+}
+
+// GenerateVariantsResponse is a product after generating its variant matrix, with the variants
+// that were created.
+type GenerateVariantsResponse struct {
+	Product ProductResponse   `json:"product"`
+	Created []VariantResponse `json:"created"`
+}
+
+// StocktakeLineResponse is the API response for StocktakeLine entity
+type StocktakeLineResponse struct {
+	ID               string          `json:"id"`
+	VariantID        string          `json:"variantId"`
+	VariantName      string          `json:"variantName"`
+	SKU              string          `json:"sku"`
+	Barcode          string          `json:"barcode"`
+	ExpectedQuantity int             `json:"expectedQuantity"`
+	CountedQuantity  *int            `json:"countedQuantity"`
+	Variance         *int            `json:"variance"`
+	UnitCost         decimal.Decimal `json:"unitCost"`
+	VarianceValue    decimal.Decimal `json:"varianceValue"`
+	AdjustedQuantity int             `json:"adjustedQuantity"`
+	CountedByID      string          `json:"countedById,omitempty"`
+	CountedAt        *time.Time      `json:"countedAt,omitempty"`
+}
+
+// ToStocktakeLineResponse converts StocktakeLine model to StocktakeLineResponse
+func ToStocktakeLineResponse(line *StocktakeLine) StocktakeLineResponse {
+	resp := StocktakeLineResponse{
+		ID:               line.ID,
+		VariantID:        line.VariantID,
+		ExpectedQuantity: line.ExpectedQuantity,
+		CountedQuantity:  line.CountedQuantity,
+		UnitCost:         line.UnitCost,
+		VarianceValue:    line.VarianceValue(),
+		AdjustedQuantity: line.AdjustedQuantity,
+		CountedByID:      line.CountedByID,
+		CountedAt:        line.CountedAt,
+	}
+	if line.CountedQuantity != nil {
+		variance := line.Variance()
+		resp.Variance = &variance
+	}
+	if line.Variant != nil {
+		resp.VariantName = line.Variant.Name
+		resp.SKU = line.Variant.SKU
+		resp.Barcode = line.Variant.Barcode
+	}
+	return resp
+}
+
+// ToStocktakeLineResponses converts a slice of StocktakeLine models to responses
+func ToStocktakeLineResponses(lines []*StocktakeLine) []StocktakeLineResponse {
+	responses := make([]StocktakeLineResponse, len(lines))
+	for i, line := range lines {
+		responses[i] = ToStocktakeLineResponse(line)
+	}
+	return responses
+}
+
+// StocktakeResponse is the API response for Stocktake entity
+type StocktakeResponse struct {
+	ID           string                  `json:"id"`
+	Name         string                  `json:"name"`
+	LocationID   string                  `json:"locationId"`
+	CategoryID   string                  `json:"categoryId"`
+	Status       StocktakeStatus         `json:"status"`
+	Notes        string                  `json:"notes"`
+	StartedByID  string                  `json:"startedById"`
+	StartedAt    time.Time               `json:"startedAt"`
+	ApprovedByID string                  `json:"approvedById,omitempty"`
+	ApprovedAt   *time.Time              `json:"approvedAt,omitempty"`
+	CancelledAt  *time.Time              `json:"cancelledAt,omitempty"`
+	Lines        []StocktakeLineResponse `json:"lines,omitempty"`
+	CreatedAt    time.Time               `json:"createdAt"`
+	UpdatedAt    time.Time               `json:"updatedAt"`
+}
+
+// ToStocktakeResponse converts Stocktake model to StocktakeResponse
+func ToStocktakeResponse(st *Stocktake) StocktakeResponse {
+	return StocktakeResponse{
+		ID:           st.ID,
+		Name:         st.Name,
+		LocationID:   st.LocationID,
+		CategoryID:   st.CategoryID,
+		Status:       st.Status,
+		Notes:        st.Notes,
+		StartedByID:  st.StartedByID,
+		StartedAt:    st.StartedAt,
+		ApprovedByID: st.ApprovedByID,
+		ApprovedAt:   st.ApprovedAt,
+		CancelledAt:  st.CancelledAt,
+		Lines:        ToStocktakeLineResponses(st.Lines),
+		CreatedAt:    st.CreatedAt,
+		UpdatedAt:    st.UpdatedAt,
+	}
+}
+
+// ToStocktakeResponses converts a slice of Stocktake models to responses
+func ToStocktakeResponses(stocktakes []*Stocktake) []StocktakeResponse {
+	responses := make([]StocktakeResponse, len(stocktakes))
+	for i, st := range stocktakes {
+		responses[i] = ToStocktakeResponse(st)
+	}
+	return responses
+}
+
+// StocktakeVarianceReportResponse summarizes the variances of a stocktake. unitsOver/valueOver
+// and unitsShort/valueShort are positive; netUnits and netValue are what approval changes.
+type StocktakeVarianceReportResponse struct {
+	StocktakeID    string                  `json:"stocktakeId"`
+	Status         StocktakeStatus         `json:"status"`
+	Lines          int                     `json:"lines"`
+	CountedLines   int                     `json:"countedLines"`
+	UncountedLines int                     `json:"uncountedLines"`
+	UnitsOver      int                     `json:"unitsOver"`
+	UnitsShort     int                     `json:"unitsShort"`
+	NetUnits       int                     `json:"netUnits"`
+	ValueOver      decimal.Decimal         `json:"valueOver"`
+	ValueShort     decimal.Decimal         `json:"valueShort"`
+	NetValue       decimal.Decimal         `json:"netValue"`
+	Variances      []StocktakeLineResponse `json:"variances"`
+}
+
+// ToStocktakeVarianceReportResponse converts a variance report to its response
+func ToStocktakeVarianceReportResponse(r *StocktakeVarianceReport) StocktakeVarianceReportResponse {
+	return StocktakeVarianceReportResponse{
+		StocktakeID:    r.Stocktake.ID,
+		Status:         r.Stocktake.Status,
+		Lines:          r.Lines,
+		CountedLines:   r.CountedLines,
+		UncountedLines: r.UncountedLines,
+		UnitsOver:      r.UnitsOver,
+		UnitsShort:     r.UnitsShort,
+		NetUnits:       r.NetUnits(),
+		ValueOver:      r.ValueOver,
+		ValueShort:     r.ValueShort,
+		NetValue:       r.NetValue(),
+		Variances:      ToStocktakeLineResponses(r.Variances),
+	}
+}
+
+// StockValuationLineResponse is the stock of one variant at the valuation date.
+type StockValuationLineResponse struct {
+	VariantID string          `json:"variantId"`
+	ProductID string          `json:"productId"`
+	Name      string          `json:"name"`
+	SKU       string          `json:"sku"`
+	Quantity  int             `json:"quantity"`
+	UnitCost  decimal.Decimal `json:"unitCost"`
+	Value     decimal.Decimal `json:"value"`
+}
+
+// StockValuationResponse is the inventory value as of a date.
+type StockValuationResponse struct {
+	AsOf time.Time `json:"asOf"`
+	// TrackedSince is when stock movements were first recorded; stock before it is incomplete.
+	TrackedSince *time.Time                   `json:"trackedSince"`
+	Currency     string                       `json:"currency"`
+	TotalUnits   int                          `json:"totalUnits"`
+	TotalValue   decimal.Decimal              `json:"totalValue"`
+	Items        []StockValuationLineResponse `json:"items"`
+}
+
+// ToStockValuationResponse converts a stock valuation to its response
+func ToStockValuationResponse(v *StockValuation) StockValuationResponse {
+	resp := StockValuationResponse{
+		AsOf:         v.AsOf,
+		TrackedSince: v.TrackedSince,
+		Currency:     v.Currency,
+		TotalUnits:   v.TotalUnits,
+		TotalValue:   v.TotalValue,
+		Items:        make([]StockValuationLineResponse, len(v.Lines)),
+	}
+	for i, l := range v.Lines {
+		resp.Items[i] = StockValuationLineResponse{
+			VariantID: l.VariantID,
+			ProductID: l.ProductID,
+			Name:      l.Name,
+			SKU:       l.SKU,
+			Quantity:  l.Quantity,
+			UnitCost:  l.UnitCost,
+			Value:     l.Value,
+		}
+	}
+	return resp
+}
+
+// SKUPreviewResponse is the pattern a SKU preview used and the next SKUs it would generate.
+type SKUPreviewResponse struct {
+	Pattern business.SKUPattern `json:"pattern"`
+	SKUs    []string            `json:"skus"`
+}
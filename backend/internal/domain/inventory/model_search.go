@@ -0,0 +1,34 @@
+package inventory
+
+// SearchResultType tags what an inventory search result is.
+type SearchResultType string
+
+const (
+	SearchResultTypeProduct SearchResultType = "product"
+	SearchResultTypeVariant SearchResultType = "variant"
+)
+
+// SearchRanking weighs the parts of an inventory search score. A match scores NameWeight times
+// its name relevance (full-text rank plus trigram similarity) plus SKUWeight times its SKU
+// similarity, where an exact SKU or barcode counts as 1 more; matches under MinScore are
+// dropped.
+type SearchRanking struct {
+	NameWeight float64
+	SKUWeight  float64
+	MinScore   float64
+}
+
+// searchHit is one ranked match of the catalog search query, before it is loaded.
+type searchHit struct {
+	Type  SearchResultType
+	ID    string
+	Score float64
+}
+
+// SearchResult is one match of an inventory search: a product, or a variant with its product.
+type SearchResult struct {
+	Type    SearchResultType
+	Score   float64
+	Product *Product
+	Variant *Variant
+}
@@ -0,0 +1,36 @@
+package inventory
+
+import (
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"gorm.io/gorm"
+)
+
+/* SKU Sequence Model */
+//---------------------*/
+
+const (
+	SKUSequenceTable  = "sku_sequences"
+	SKUSequenceStruct = "SKUSequence"
+	SKUSequencePrefix = "skq"
+)
+
+// SKUSequence is the last number given to a generated SKU of one stem (the business SKU
+// pattern's prefix and category code). Numbers are never reused, so a failed create leaves a
+// gap.
+type SKUSequence struct {
+	ID         string    `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string    `gorm:"column:business_id;type:text;not null;uniqueIndex:sku_sequence_stem_idx,priority:1" json:"businessId"`
+	Stem       string    `gorm:"column:stem;type:text;not null;uniqueIndex:sku_sequence_stem_idx,priority:2" json:"stem"`
+	LastValue  int       `gorm:"column:last_value;type:int;not null" json:"lastValue"`
+	CreatedAt  time.Time `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+	UpdatedAt  time.Time `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
+}
+
+func (m *SKUSequence) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(SKUSequencePrefix)
+	}
+	return
+}
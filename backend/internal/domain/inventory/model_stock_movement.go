@@ -0,0 +1,77 @@
+package inventory
+
+import (
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+/* Stock Movement Model */
+//----------------------*/
+
+const (
+	StockMovementTable  = "stock_movements"
+	StockMovementStruct = "StockMovement"
+	StockMovementPrefix = "stm"
+)
+
+// StockMovement records a signed change to a variant's total stock: units received, sold,
+// restocked, counted or edited. Transfers between locations leave the total unchanged and are
+// not recorded. Replaying the movements after a date backwards from the current stock gives
+// the stock the variant held on that date.
+type StockMovement struct {
+	ID         string    `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string    `gorm:"column:business_id;type:text;not null;index:stock_movement_business_idx,priority:1" json:"businessId"`
+	VariantID  string    `gorm:"column:variant_id;type:text;not null;index" json:"variantId"`
+	Quantity   int       `gorm:"column:quantity;type:int;not null" json:"quantity"`
+	OccurredAt time.Time `gorm:"column:occurred_at;type:timestamp;not null;index:stock_movement_business_idx,priority:2" json:"occurredAt"`
+	CreatedAt  time.Time `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+}
+
+func (m *StockMovement) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(StockMovementPrefix)
+	}
+	return
+}
+
+var StockMovementSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	VariantID  schema.Field
+	Quantity   schema.Field
+	OccurredAt schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	VariantID:  schema.NewField("variant_id", "variantId"),
+	Quantity:   schema.NewField("quantity", "quantity"),
+	OccurredAt: schema.NewField("occurred_at", "occurredAt"),
+}
+
+// StockValuationLine is the stock a variant held at the valuation date, valued at the cost
+// price in effect on that date.
+type StockValuationLine struct {
+	VariantID string
+	ProductID string
+	Name      string
+	SKU       string
+	Quantity  int
+	UnitCost  decimal.Decimal
+	Value     decimal.Decimal
+}
+
+// StockValuation is the inventory of a business as of a date. TrackedSince is when stock
+// movements were first recorded: stock on earlier dates is only as complete as the movements
+// recorded after it. It is nil when the business has none.
+type StockValuation struct {
+	AsOf         time.Time
+	TrackedSince *time.Time
+	Currency     string
+	TotalUnits   int
+	TotalValue   decimal.Decimal
+	Lines        []*StockValuationLine
+}
@@ -0,0 +1,163 @@
+package inventory
+
+import (
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+/* Stocktake Model */
+//------------------*/
+
+const (
+	StocktakeTable        = "stocktakes"
+	StocktakeStruct       = "Stocktake"
+	StocktakePrefix       = "stk"
+	StocktakeLinesStruct  = "Lines"
+	StocktakeLineVariants = "Lines.Variant"
+)
+
+// StocktakeStatus tracks a stocktake from counting to its outcome.
+//
+//	counting   expected quantities are snapshotted and counts are being recorded
+//	approved   variances were posted to stock as correcting adjustments
+//	cancelled  abandoned without changing stock
+type StocktakeStatus string
+
+const (
+	StocktakeStatusCounting  StocktakeStatus = "counting"
+	StocktakeStatusApproved  StocktakeStatus = "approved"
+	StocktakeStatusCancelled StocktakeStatus = "cancelled"
+)
+
+// Stocktake is a physical count of stock at one location, or of the variant totals for
+// businesses without locations. A full count covers every variant; a cycle count covers a
+// category or a hand-picked set of variants.
+//
+// Expected quantities are snapshotted when the stocktake starts. On approval each counted
+// line's variance (counted - expected) is added to the stock, so sales and receipts made while
+// counting are kept. A location has at most one stocktake counting at a time.
+type Stocktake struct {
+	ID           string           `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID   string           `gorm:"column:business_id;type:text;not null;index;uniqueIndex:stocktake_counting_location_idx,where:status = 'counting'" json:"businessId"`
+	Name         string           `gorm:"column:name;type:text;not null" json:"name"`
+	LocationID   string           `gorm:"column:location_id;type:text;not null;default:'';uniqueIndex:stocktake_counting_location_idx,where:status = 'counting'" json:"locationId"`
+	CategoryID   string           `gorm:"column:category_id;type:text;not null;default:''" json:"categoryId"`
+	Status       StocktakeStatus  `gorm:"column:status;type:text;not null;index" json:"status"`
+	Notes        string           `gorm:"column:notes;type:text" json:"notes"`
+	StartedByID  string           `gorm:"column:started_by_id;type:text" json:"startedById"`
+	StartedAt    time.Time        `gorm:"column:started_at;type:timestamp;not null" json:"startedAt"`
+	ApprovedByID string           `gorm:"column:approved_by_id;type:text" json:"approvedById"`
+	ApprovedAt   *time.Time       `gorm:"column:approved_at;type:timestamp" json:"approvedAt,omitempty"`
+	CancelledAt  *time.Time       `gorm:"column:cancelled_at;type:timestamp" json:"cancelledAt,omitempty"`
+	Lines        []*StocktakeLine `gorm:"foreignKey:StocktakeID;references:ID;constraint:OnDelete:CASCADE;" json:"lines,omitempty"`
+	CreatedAt    time.Time        `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+	UpdatedAt    time.Time        `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
+}
+
+func (m *Stocktake) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(StocktakePrefix)
+	}
+	return
+}
+
+var StocktakeSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	LocationID schema.Field
+	Status     schema.Field
+	StartedAt  schema.Field
+	CreatedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	LocationID: schema.NewField("location_id", "locationId"),
+	Status:     schema.NewField("status", "status"),
+	StartedAt:  schema.NewField("started_at", "startedAt"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+}
+
+const (
+	StocktakeLineTable  = "stocktake_lines"
+	StocktakeLinePrefix = "stl"
+)
+
+// StocktakeLine is one variant of a stocktake: the quantity expected when the stocktake
+// started and the quantity counted, nil until the variant is counted. UnitCost is the variant's
+// cost at the snapshot and values the variance.
+//
+// Approved lines are the correcting side of a variant's stock movements: AdjustedQuantity is
+// the delta posted to stock, zero for uncounted lines and lines that matched.
+type StocktakeLine struct {
+	ID               string          `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID       string          `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	StocktakeID      string          `gorm:"column:stocktake_id;type:text;not null;uniqueIndex:stocktake_line_variant_idx" json:"stocktakeId"`
+	VariantID        string          `gorm:"column:variant_id;type:text;not null;index;uniqueIndex:stocktake_line_variant_idx" json:"variantId"`
+	Variant          *Variant        `gorm:"foreignKey:VariantID;references:ID" json:"variant,omitempty"`
+	ExpectedQuantity int             `gorm:"column:expected_quantity;type:int;not null" json:"expectedQuantity"`
+	CountedQuantity  *int            `gorm:"column:counted_quantity;type:int" json:"countedQuantity"`
+	UnitCost         decimal.Decimal `gorm:"column:unit_cost;type:numeric;not null;default:0" json:"unitCost"`
+	AdjustedQuantity int             `gorm:"column:adjusted_quantity;type:int;not null;default:0" json:"adjustedQuantity"`
+	CountedByID      string          `gorm:"column:counted_by_id;type:text" json:"countedById"`
+	CountedAt        *time.Time      `gorm:"column:counted_at;type:timestamp" json:"countedAt,omitempty"`
+	CreatedAt        time.Time       `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+	UpdatedAt        time.Time       `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
+}
+
+func (m *StocktakeLine) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(StocktakeLinePrefix)
+	}
+	return
+}
+
+// Variance is the counted minus the expected quantity, zero while the line is uncounted.
+func (m *StocktakeLine) Variance() int {
+	if m.CountedQuantity == nil {
+		return 0
+	}
+	return *m.CountedQuantity - m.ExpectedQuantity
+}
+
+// VarianceValue is the variance at the line's unit cost.
+func (m *StocktakeLine) VarianceValue() decimal.Decimal {
+	return m.UnitCost.Mul(decimal.NewFromInt(int64(m.Variance())))
+}
+
+var StocktakeLineSchema = struct {
+	ID          schema.Field
+	StocktakeID schema.Field
+	VariantID   schema.Field
+}{
+	ID:          schema.NewField("id", "id"),
+	StocktakeID: schema.NewField("stocktake_id", "stocktakeId"),
+	VariantID:   schema.NewField("variant_id", "variantId"),
+}
+
+// StocktakeVarianceReport summarizes the counted lines of a stocktake that differ from the
+// snapshot. Over and short amounts are both positive; the net values are signed.
+type StocktakeVarianceReport struct {
+	Stocktake      *Stocktake
+	Lines          int
+	CountedLines   int
+	UncountedLines int
+	UnitsOver      int
+	UnitsShort     int
+	ValueOver      decimal.Decimal
+	ValueShort     decimal.Decimal
+	Variances      []*StocktakeLine
+}
+
+// NetUnits is the units the stock changes by when the stocktake is approved.
+func (r *StocktakeVarianceReport) NetUnits() int {
+	return r.UnitsOver - r.UnitsShort
+}
+
+// NetValue is the inventory value the stock changes by when the stocktake is approved.
+func (r *StocktakeVarianceReport) NetValue() decimal.Decimal {
+	return r.ValueOver.Sub(r.ValueShort)
+}
@@ -0,0 +1,191 @@
+package inventory
+
+import (
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+/* Supplier Bill Model */
+//----------------------*/
+
+const (
+	SupplierBillTable         = "supplier_bills"
+	SupplierBillStruct        = "SupplierBill"
+	SupplierBillPrefix        = "bill"
+	SupplierBillSupplierField = "Supplier"
+)
+
+// SupplierBillPaymentStatus tracks how much of a supplier bill was paid.
+//
+//	unpaid          nothing paid yet
+//	partially_paid  some of the amount was paid
+//	paid            the whole amount was paid
+type SupplierBillPaymentStatus string
+
+const (
+	SupplierBillUnpaid        SupplierBillPaymentStatus = "unpaid"
+	SupplierBillPartiallyPaid SupplierBillPaymentStatus = "partially_paid"
+	SupplierBillPaid          SupplierBillPaymentStatus = "paid"
+)
+
+// SupplierBill is an invoice a supplier sent the business, payable by DueAt.
+//
+// A bill may be for a purchase order. Once a purchase order is billed, its bills carry what is
+// owed for it: payments are recorded on the bills and reach the purchase order with them.
+// Payments made on the purchase order before it was billed are applied to its first bills.
+type SupplierBill struct {
+	ID              string                    `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID      string                    `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	SupplierID      string                    `gorm:"column:supplier_id;type:text;not null;index" json:"supplierId"`
+	Supplier        *Supplier                 `gorm:"foreignKey:SupplierID;references:ID" json:"supplier,omitempty"`
+	PurchaseOrderID string                    `gorm:"column:purchase_order_id;type:text;not null;default:'';index" json:"purchaseOrderId"`
+	Reference       string                    `gorm:"column:reference;type:text" json:"reference"`
+	Currency        string                    `gorm:"column:currency;type:text;not null" json:"currency"`
+	Amount          decimal.Decimal           `gorm:"column:amount;type:numeric;not null" json:"amount"`
+	AmountPaid      decimal.Decimal           `gorm:"column:amount_paid;type:numeric;not null;default:0" json:"amountPaid"`
+	PaymentStatus   SupplierBillPaymentStatus `gorm:"column:payment_status;type:text;not null;index" json:"paymentStatus"`
+	IssuedAt        time.Time                 `gorm:"column:issued_at;type:timestamp;not null" json:"issuedAt"`
+	DueAt           time.Time                 `gorm:"column:due_at;type:timestamp;not null;index" json:"dueAt"`
+	PaidAt          *time.Time                `gorm:"column:paid_at;type:timestamp" json:"paidAt,omitempty"`
+	Notes           string                    `gorm:"column:notes;type:text" json:"notes"`
+	CreatedAt       time.Time                 `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+	UpdatedAt       time.Time                 `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
+	DeletedAt       gorm.DeletedAt            `gorm:"column:deleted_at;type:timestamp;index" json:"deletedAt,omitempty"`
+}
+
+func (m *SupplierBill) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(SupplierBillPrefix)
+	}
+	return
+}
+
+// AmountOutstanding is what is still owed on the bill.
+func (m *SupplierBill) AmountOutstanding() decimal.Decimal {
+	return decimal.Max(m.Amount.Sub(m.AmountPaid), decimal.Zero)
+}
+
+// refreshPaymentStatus derives the payment status from the amount paid; paidAt is when the
+// bill was paid in full.
+func (m *SupplierBill) refreshPaymentStatus(paidAt time.Time) {
+	switch {
+	case m.AmountPaid.GreaterThanOrEqual(m.Amount):
+		m.PaymentStatus = SupplierBillPaid
+		if m.PaidAt == nil {
+			m.PaidAt = &paidAt
+		}
+		return
+	case m.AmountPaid.IsPositive():
+		m.PaymentStatus = SupplierBillPartiallyPaid
+	default:
+		m.PaymentStatus = SupplierBillUnpaid
+	}
+	m.PaidAt = nil
+}
+
+var SupplierBillSchema = struct {
+	ID              schema.Field
+	BusinessID      schema.Field
+	SupplierID      schema.Field
+	PurchaseOrderID schema.Field
+	Amount          schema.Field
+	AmountPaid      schema.Field
+	PaymentStatus   schema.Field
+	IssuedAt        schema.Field
+	DueAt           schema.Field
+	CreatedAt       schema.Field
+}{
+	ID:              schema.NewField("id", "id"),
+	BusinessID:      schema.NewField("business_id", "businessId"),
+	SupplierID:      schema.NewField("supplier_id", "supplierId"),
+	PurchaseOrderID: schema.NewField("purchase_order_id", "purchaseOrderId"),
+	Amount:          schema.NewField("amount", "amount"),
+	AmountPaid:      schema.NewField("amount_paid", "amountPaid"),
+	PaymentStatus:   schema.NewField("payment_status", "paymentStatus"),
+	IssuedAt:        schema.NewField("issued_at", "issuedAt"),
+	DueAt:           schema.NewField("due_at", "dueAt"),
+	CreatedAt:       schema.NewField("created_at", "createdAt"),
+}
+
+const (
+	SupplierBillPaymentTable  = "supplier_bill_payments"
+	SupplierBillPaymentPrefix = "bpay"
+)
+
+// SupplierBillPayment records a payment made to the supplier against a bill.
+type SupplierBillPayment struct {
+	ID           string          `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID   string          `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	BillID       string          `gorm:"column:bill_id;type:text;not null;index" json:"billId"`
+	Amount       decimal.Decimal `gorm:"column:amount;type:numeric;not null" json:"amount"`
+	PaidAt       time.Time       `gorm:"column:paid_at;type:timestamp;not null" json:"paidAt"`
+	Note         string          `gorm:"column:note;type:text" json:"note"`
+	RecordedByID string          `gorm:"column:recorded_by_id;type:text" json:"recordedById"`
+	CreatedAt    time.Time       `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+}
+
+func (m *SupplierBillPayment) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(SupplierBillPaymentPrefix)
+	}
+	return
+}
+
+var SupplierBillPaymentSchema = struct {
+	ID     schema.Field
+	BillID schema.Field
+	PaidAt schema.Field
+}{
+	ID:     schema.NewField("id", "id"),
+	BillID: schema.NewField("bill_id", "billId"),
+	PaidAt: schema.NewField("paid_at", "paidAt"),
+}
+
+// SupplierBillAgingBuckets splits what is owed on bills by how long it is overdue.
+type SupplierBillAgingBuckets struct {
+	Current    decimal.Decimal
+	Days1To30  decimal.Decimal
+	Days31To60 decimal.Decimal
+	Days61To90 decimal.Decimal
+	Over90     decimal.Decimal
+	Total      decimal.Decimal
+}
+
+// add puts amount in the bucket of a bill daysOverdue days past its due date.
+func (b *SupplierBillAgingBuckets) add(daysOverdue int, amount decimal.Decimal) {
+	switch {
+	case daysOverdue <= 0:
+		b.Current = b.Current.Add(amount)
+	case daysOverdue <= 30:
+		b.Days1To30 = b.Days1To30.Add(amount)
+	case daysOverdue <= 60:
+		b.Days31To60 = b.Days31To60.Add(amount)
+	case daysOverdue <= 90:
+		b.Days61To90 = b.Days61To90.Add(amount)
+	default:
+		b.Over90 = b.Over90.Add(amount)
+	}
+	b.Total = b.Total.Add(amount)
+}
+
+// SupplierBillAgingLine is the aging of what is owed to one supplier.
+type SupplierBillAgingLine struct {
+	SupplierID   string
+	SupplierName string
+	SupplierBillAgingBuckets
+}
+
+// SupplierBillAging is what the business owes on its bills as of a date, by how long it is
+// overdue. UnbilledPurchaseOrders is owed for received goods of purchase orders not billed
+// yet, which have no due date.
+type SupplierBillAging struct {
+	AsOf     time.Time
+	Currency string
+	SupplierBillAgingBuckets
+	UnbilledPurchaseOrders decimal.Decimal
+	Suppliers              []*SupplierBillAgingLine
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/abdelrahman146/kyora/internal/domain/account"
 	"github.com/abdelrahman146/kyora/internal/domain/business"
@@ -14,6 +15,7 @@ import (
 	"github.com/abdelrahman146/kyora/internal/platform/types/atomic"
 	"github.com/abdelrahman146/kyora/internal/platform/types/list"
 	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
@@ -26,6 +28,7 @@ type Service struct {
 	storage         *Storage
 	atomicProcessor atomic.AtomicProcessor
 	bus             *bus.Bus
+	photoAssets     PhotoAssets
 }
 
 func NewService(storage *Storage, atomicProcessor atomic.AtomicProcessor, bus *bus.Bus) *Service {
@@ -36,6 +39,11 @@ func NewService(storage *Storage, atomicProcessor atomic.AtomicProcessor, bus *b
 	}
 }
 
+// SetPhotoAssets wires the uploaded-asset lookup used by the product photo endpoints.
+func (s *Service) SetPhotoAssets(p PhotoAssets) {
+	s.photoAssets = p
+}
+
 func (s *Service) GetProductByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Product, error) {
 	return s.storage.products.FindOne(ctx,
 		s.storage.products.ScopeWhere("products.business_id = ?", biz.ID),
@@ -44,6 +52,34 @@ func (s *Service) GetProductByID(ctx context.Context, actor *account.User, biz *
 	)
 }
 
+// ListProductsByIDs returns the products of the business among ids, without variants.
+func (s *Service) ListProductsByIDs(ctx context.Context, actor *account.User, biz *business.Business, ids []string) ([]*Product, error) {
+	if len(ids) == 0 {
+		return []*Product{}, nil
+	}
+	values := make([]any, len(ids))
+	for i, id := range ids {
+		values[i] = id
+	}
+	return s.storage.products.FindMany(ctx,
+		s.storage.products.ScopeWhere("products.business_id = ?", biz.ID),
+		s.storage.products.ScopeIDs(values),
+	)
+}
+
+// ExistingProductIDs reports which of ids are products of the business.
+func (s *Service) ExistingProductIDs(ctx context.Context, actor *account.User, biz *business.Business, ids []string) (map[string]bool, error) {
+	products, err := s.ListProductsByIDs(ctx, actor, biz, ids)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]bool, len(products))
+	for _, p := range products {
+		found[p.ID] = true
+	}
+	return found, nil
+}
+
 func (s *Service) GetVariantByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Variant, error) {
 	return s.storage.variants.FindOne(ctx,
 		s.storage.variants.ScopeWhere("variants.business_id = ?", biz.ID),
@@ -66,6 +102,22 @@ type ListProductsFilters struct {
 }
 
 func (s *Service) ListProducts(ctx context.Context, actor *account.User, biz *business.Business, req *list.ListRequest, filters *ListProductsFilters) ([]*Product, int64, error) {
+	return s.listProducts(ctx, biz, req, filters, s.storage.products.WithPreload(ProductVariantsStruct))
+}
+
+// ListProductSummaries returns the same page as ListProducts using a lean projection:
+// product descriptions are not loaded and variants are loaded with only the price and stock
+// columns needed by ProductSummaryResponse.
+func (s *Service) ListProductSummaries(ctx context.Context, actor *account.User, biz *business.Business, req *list.ListRequest, filters *ListProductsFilters) ([]*Product, int64, error) {
+	return s.listProducts(ctx, biz, req, filters,
+		s.storage.products.WithSelect(productSummaryColumns...),
+		s.storage.products.WithPreloadSelect(ProductVariantsStruct, variantSummaryColumns...),
+	)
+}
+
+// listProducts runs the filtered, searched and sorted product page query.
+// loadOpts control which columns and associations are loaded for each product.
+func (s *Service) listProducts(ctx context.Context, biz *business.Business, req *list.ListRequest, filters *ListProductsFilters, loadOpts ...func(db *gorm.DB) *gorm.DB) ([]*Product, int64, error) {
 	// Build base scopes with qualified table name to avoid ambiguity
 	baseScopes := []func(db *gorm.DB) *gorm.DB{
 		s.storage.products.ScopeWhere("products.business_id = ?", biz.ID),
@@ -116,7 +168,10 @@ func (s *Service) ListProducts(ctx context.Context, actor *account.User, biz *bu
 		baseScopes = append(baseScopes, s.storage.ScopeProductSearch(req.SearchTerm()))
 
 		if !req.HasExplicitOrderBy() {
-			rankExpr, err := database.WebSearchRankOrder(req.SearchTerm(), "products.search_vector", "variants.search_vector", "categories.search_vector")
+			rankExpr, err := database.SearchRankOrder(req.SearchTerm(),
+				[]string{"products.search_vector", "variants.search_vector", "categories.search_vector"},
+				ProductSearchNameColumns,
+			)
 			if err != nil {
 				return nil, 0, err
 			}
@@ -178,7 +233,7 @@ func (s *Service) ListProducts(ctx context.Context, actor *account.User, biz *bu
 		findOpts = append(findOpts, s.storage.products.WithOrderBy(req.ParsedOrderBy(ProductSchema)))
 	}
 
-	findOpts = append(findOpts, s.storage.products.WithPreload(ProductVariantsStruct))
+	findOpts = append(findOpts, loadOpts...)
 
 	// Execute query
 	items, err := s.storage.products.FindMany(ctx, findOpts...)
@@ -205,23 +260,16 @@ func (s *Service) ListVariants(ctx context.Context, actor *account.User, biz *bu
 	// Add search support if search term is provided
 	if req.SearchTerm() != "" {
 		term := req.SearchTerm()
-		like := "%" + term + "%"
-
-		// Join products table for searching product names
-		baseScopes = append(baseScopes,
-			s.storage.variants.WithJoins("LEFT JOIN products ON products.id = variants.product_id AND products.deleted_at IS NULL"),
-			s.storage.variants.ScopeWhere(
-				"(variants.search_vector @@ websearch_to_tsquery('simple', ?) OR products.search_vector @@ websearch_to_tsquery('simple', ?) OR variants.sku ILIKE ? OR variants.code ILIKE ?)",
-				term,
-				term,
-				like,
-				like,
-			),
-		)
+
+		// Search scope joins products for searching product names
+		baseScopes = append(baseScopes, s.storage.ScopeVariantSearch(term))
 
 		// Add relevance ranking if no explicit order by is provided
 		if !req.HasExplicitOrderBy() {
-			rankExpr, err := database.WebSearchRankOrder(term, "variants.search_vector", "products.search_vector")
+			rankExpr, err := database.SearchRankOrder(term,
+				[]string{"variants.search_vector", "products.search_vector"},
+				VariantSearchNameColumns,
+			)
 			if err != nil {
 				return nil, err
 			}
@@ -245,6 +293,29 @@ func (s *Service) ListVariants(ctx context.Context, actor *account.User, biz *bu
 	return s.storage.variants.FindMany(ctx, findOpts...)
 }
 
+// ListPickerVariants returns variants matching the request's search together with the units
+// held by open orders, for picking items while creating an order. Search and ordering follow
+// ListVariants.
+func (s *Service) ListPickerVariants(ctx context.Context, actor *account.User, biz *business.Business, req *list.ListRequest) ([]*PickerVariant, error) {
+	variants, err := s.ListVariants(ctx, actor, biz, req)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(variants))
+	for i, v := range variants {
+		ids[i] = v.ID
+	}
+	reserved, err := s.storage.SumReservedQuantities(ctx, biz.ID, ids)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*PickerVariant, len(variants))
+	for i, v := range variants {
+		items[i] = &PickerVariant{Variant: v, Reserved: reserved[v.ID]}
+	}
+	return items, nil
+}
+
 func (s *Service) GetProductVariants(ctx context.Context, actor *account.User, biz *business.Business, productID string) ([]*Variant, error) {
 	return s.storage.variants.FindMany(ctx,
 		s.storage.variants.ScopeWhere("variants.business_id = ?", biz.ID),
@@ -293,20 +364,8 @@ func (s *Service) CountVariants(ctx context.Context, actor *account.User, biz *b
 
 	// Add search support if search term is provided
 	if req.SearchTerm() != "" {
-		term := req.SearchTerm()
-		like := "%" + term + "%"
-
-		// Join products table for searching product names
-		baseScopes = append(baseScopes,
-			s.storage.variants.WithJoins("LEFT JOIN products ON products.id = variants.product_id AND products.deleted_at IS NULL"),
-			s.storage.variants.ScopeWhere(
-				"(variants.search_vector @@ websearch_to_tsquery('simple', ?) OR products.search_vector @@ websearch_to_tsquery('simple', ?) OR variants.sku ILIKE ? OR variants.code ILIKE ?)",
-				term,
-				term,
-				like,
-				like,
-			),
-		)
+		// Search scope joins products for searching product names
+		baseScopes = append(baseScopes, s.storage.ScopeVariantSearch(req.SearchTerm()))
 	}
 
 	return s.storage.variants.Count(ctx, baseScopes...)
@@ -328,6 +387,7 @@ type CreateProductWithVariantsRequest struct {
 type CreateProductVariantRequest struct {
 	Code               string                 `json:"code" binding:"required"`
 	SKU                string                 `json:"sku" binding:"omitempty"`
+	Barcode            string                 `json:"barcode" binding:"omitempty"`
 	Photos             []asset.AssetReference `json:"photos" binding:"omitempty,max=10,dive"`
 	CostPrice          *decimal.Decimal       `json:"costPrice" binding:"required"`
 	SalePrice          *decimal.Decimal       `json:"salePrice" binding:"required"`
@@ -352,11 +412,15 @@ func (s *Service) CreateProductWithVariants(ctx context.Context, actor *account.
 			Photos:      photos,
 			CategoryID:  req.Product.CategoryID,
 		}
+		if err := applyProductType(product, req.Product.Type, req.Product.Download, false); err != nil {
+			return err
+		}
 		err = s.storage.products.CreateOne(txCtx, product)
 		if err != nil {
 			return err
 		}
 		variants := make([]*Variant, len(req.Variants))
+		seenSKUs, seenBarcodes := map[string]bool{}, map[string]bool{}
 		for i, variantReq := range req.Variants {
 			if variantReq.CostPrice == nil {
 				return problem.BadRequest("costPrice is required").With("field", "costPrice")
@@ -379,7 +443,23 @@ func (s *Service) CreateProductWithVariants(ctx context.Context, actor *account.
 			photos := AssetReferenceList(variantReq.Photos)
 			sku := strings.TrimSpace(variantReq.SKU)
 			if sku == "" {
-				sku = CreateProductSKU(biz.Descriptor, product.Name, variantReq.Code)
+				if sku, err = s.generateSKU(txCtx, biz, product, variantReq.Code); err != nil {
+					return err
+				}
+			}
+			code, err := normalizeBarcode(variantReq.Barcode)
+			if err != nil {
+				return err
+			}
+			if seenSKUs[sku] {
+				return ErrVariantSKUTaken(sku, nil)
+			}
+			if code != "" && seenBarcodes[code] {
+				return ErrVariantBarcodeTaken(code, nil)
+			}
+			seenSKUs[sku], seenBarcodes[code] = true, true
+			if err := s.ensureVariantIdentifiersFree(txCtx, biz, "", sku, code); err != nil {
+				return err
 			}
 			variants[i] = &Variant{
 				BusinessID:         biz.ID,
@@ -387,6 +467,7 @@ func (s *Service) CreateProductWithVariants(ctx context.Context, actor *account.
 				Code:               variantReq.Code,
 				Name:               fmt.Sprintf("%s - %s", product.Name, variantReq.Code),
 				SKU:                sku,
+				Barcode:            code,
 				SalePrice:          *variantReq.SalePrice,
 				CostPrice:          *variantReq.CostPrice,
 				Currency:           biz.Currency,
@@ -395,8 +476,7 @@ func (s *Service) CreateProductWithVariants(ctx context.Context, actor *account.
 				StockQuantityAlert: *variantReq.StockQuantityAlert,
 			}
 		}
-		err = s.storage.variants.CreateMany(txCtx, variants)
-		if err != nil {
+		if err := s.createVariants(txCtx, actor, biz, variants); err != nil {
 			return err
 		}
 		product.Variants = variants
@@ -439,6 +519,9 @@ func (s *Service) CreateProduct(ctx context.Context, actor *account.User, biz *b
 		Photos:      photos,
 		CategoryID:  req.CategoryID,
 	}
+	if err := applyProductType(product, req.Type, req.Download, false); err != nil {
+		return nil, err
+	}
 	err := s.storage.products.CreateOne(ctx, product)
 	if err != nil {
 		return nil, err
@@ -471,7 +554,16 @@ func (s *Service) CreateVariant(ctx context.Context, actor *account.User, biz *b
 	}
 	sku := strings.TrimSpace(req.SKU)
 	if sku == "" {
-		sku = CreateProductSKU(biz.Descriptor, product.Name, req.Code)
+		if sku, err = s.generateSKU(ctx, biz, product, req.Code); err != nil {
+			return nil, err
+		}
+	}
+	code, err := normalizeBarcode(req.Barcode)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureVariantIdentifiersFree(ctx, biz, "", sku, code); err != nil {
+		return nil, err
 	}
 	photos := AssetReferenceList(req.Photos)
 	variant := &Variant{
@@ -480,6 +572,7 @@ func (s *Service) CreateVariant(ctx context.Context, actor *account.User, biz *b
 		Code:               req.Code,
 		Name:               fmt.Sprintf("%s - %s", product.Name, req.Code),
 		SKU:                sku,
+		Barcode:            code,
 		CostPrice:          *req.CostPrice,
 		SalePrice:          *req.SalePrice,
 		Currency:           biz.Currency,
@@ -487,15 +580,74 @@ func (s *Service) CreateVariant(ctx context.Context, actor *account.User, biz *b
 		StockQuantity:      *req.StockQuantity,
 		StockQuantityAlert: *req.StockQuantityAlert,
 	}
-	err = s.storage.variants.CreateOne(ctx, variant)
+	if err := s.applyReorderSettings(ctx, actor, biz, variant, &req.ReorderSettings); err != nil {
+		return nil, err
+	}
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.variants.CreateOne(tctx, variant); err != nil {
+			return variantIdentifierConflict(err, variant.SKU, variant.Barcode)
+		}
+		opening := initialStock(variant)
+		if err := s.recordStockMovements(tctx, biz, opening); err != nil {
+			return err
+		}
+		if err := s.applyLocationStockDeltas(tctx, biz, "", opening); err != nil {
+			return err
+		}
+		return s.storage.costChanges.CreateOne(tctx, newCostChange(actor, variant, nil, time.Now()))
+	})
 	if err != nil {
 		return nil, err
 	}
 	return variant, nil
 }
 
+// createVariants inserts new variants of one product with their first cost change and opening
+// stock. It must run inside the caller's transaction.
+func (s *Service) createVariants(ctx context.Context, actor *account.User, biz *business.Business, variants []*Variant) error {
+	if err := s.storage.variants.CreateMany(ctx, variants); err != nil {
+		return variantIdentifierConflict(err, "", "")
+	}
+	now := time.Now()
+	changes := make([]*VariantCostChange, len(variants))
+	for i, v := range variants {
+		changes[i] = newCostChange(actor, v, nil, now)
+	}
+	if err := s.storage.costChanges.CreateMany(ctx, changes); err != nil {
+		return err
+	}
+	opening := initialStock(variants...)
+	if err := s.recordStockMovements(ctx, biz, opening); err != nil {
+		return err
+	}
+	return s.applyLocationStockDeltas(ctx, biz, "", opening)
+}
+
+// applyProductType sets the product's type and download from a request; empty values keep the
+// current ones, and new products default to physical. Only digital products keep a download.
+func applyProductType(product *Product, productType ProductType, download *asset.AssetReference, clearDownload bool) error {
+	if productType != "" {
+		product.Type = productType
+	} else if product.Type == "" {
+		product.Type = ProductTypePhysical
+	}
+	if clearDownload {
+		product.Download = nil
+	} else if download != nil {
+		product.Download = download
+	}
+	if product.Download != nil && product.Type != ProductTypeDigital {
+		return ErrDownloadRequiresDigitalProduct(product.Type)
+	}
+	return nil
+}
+
 func (s *Service) UpdateProduct(ctx context.Context, actor *account.User, biz *business.Business, product *Product, req *UpdateProductRequest) error {
-	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+	if req.Version != nil && *req.Version != product.Version {
+		return ErrProductVersionConflict(product.ID, nil)
+	}
+	previousPhotos := product.Photos
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
 		if req.Name != "" {
 			product.Name = req.Name
 			variants, err := s.GetProductVariants(tctx, actor, biz, product.ID)
@@ -516,14 +668,28 @@ func (s *Service) UpdateProduct(ctx context.Context, actor *account.User, biz *b
 		if req.Photos != nil {
 			product.Photos = AssetReferenceList(req.Photos)
 		}
+		if err := applyProductType(product, req.Type, req.Download, req.ClearDownload); err != nil {
+			return err
+		}
 		if req.CategoryID != "" {
 			if _, err := s.GetCategoryByID(tctx, actor, biz, req.CategoryID); err != nil {
 				return err
 			}
 			product.CategoryID = req.CategoryID
 		}
-		return s.storage.products.UpdateOne(tctx, product)
+		if err := s.storage.products.UpdateOne(tctx, product); err != nil {
+			if database.IsVersionConflict(err) {
+				return ErrProductVersionConflict(product.ID, err)
+			}
+			return err
+		}
+		return nil
 	})
+	if err != nil {
+		return err
+	}
+	s.releasePhotoAssets(ctx, biz, removedPhotos(previousPhotos, product.Photos))
+	return nil
 }
 
 func (s *Service) UpdateVariant(ctx context.Context, actor *account.User, biz *business.Business, variantID string, req *UpdateVariantRequest) error {
@@ -531,6 +697,7 @@ func (s *Service) UpdateVariant(ctx context.Context, actor *account.User, biz *b
 	if err != nil {
 		return err
 	}
+	previous := *variant
 	if req.Code != nil {
 		variant.Code = strings.TrimSpace(*req.Code)
 		product, err := s.GetProductByID(ctx, actor, biz, variant.ProductID)
@@ -542,6 +709,18 @@ func (s *Service) UpdateVariant(ctx context.Context, actor *account.User, biz *b
 	if req.SKU != nil {
 		variant.SKU = strings.TrimSpace(*req.SKU)
 	}
+	if req.Barcode != nil {
+		code, err := normalizeBarcode(*req.Barcode)
+		if err != nil {
+			return err
+		}
+		variant.Barcode = code
+	}
+	if variant.SKU != previous.SKU || variant.Barcode != previous.Barcode {
+		if err := s.ensureVariantIdentifiersFree(ctx, biz, variant.ID, variant.SKU, variant.Barcode); err != nil {
+			return err
+		}
+	}
 	if req.Photos != nil {
 		variant.Photos = AssetReferenceList(req.Photos)
 	}
@@ -566,7 +745,80 @@ func (s *Service) UpdateVariant(ctx context.Context, actor *account.User, biz *b
 	if req.StockQuantityAlert != nil {
 		variant.StockQuantityAlert = *req.StockQuantityAlert
 	}
-	return s.storage.variants.UpdateOne(ctx, variant)
+	if err := s.applyReorderSettings(ctx, actor, biz, variant, &req.ReorderSettings); err != nil {
+		return err
+	}
+	if req.ClearPromo {
+		variant.PromoPrice = decimal.NullDecimal{}
+		variant.PromoStartsAt = nil
+		variant.PromoEndsAt = nil
+	} else if req.PromoPrice.Valid {
+		if err := validatePromoWindow(req.PromoStartsAt, req.PromoEndsAt); err != nil {
+			return err
+		}
+		variant.PromoPrice = decimal.NewNullDecimal(money.Round(req.PromoPrice.Decimal, biz.Currency))
+		variant.PromoStartsAt = req.PromoStartsAt
+		variant.PromoEndsAt = req.PromoEndsAt
+	}
+	// Stock set on the variant itself is counted at the default location.
+	stockDelta := variant.StockQuantity - previous.StockQuantity
+	priceChanged := !variant.CostPrice.Equal(previous.CostPrice) || !variant.SalePrice.Equal(previous.SalePrice)
+	if stockDelta == 0 && !priceChanged {
+		if err := s.storage.variants.UpdateOne(ctx, variant); err != nil {
+			return variantIdentifierConflict(err, variant.SKU, variant.Barcode)
+		}
+		s.releasePhotoAssets(ctx, biz, removedPhotos(previous.Photos, variant.Photos))
+		return nil
+	}
+
+	var change *VariantCostChange
+	if priceChanged {
+		change = newCostChange(actor, variant, &previous, time.Now())
+		change.BelowMinMargin = crossesBelowMinMargin(biz, change)
+	}
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.variants.UpdateOne(tctx, variant); err != nil {
+			return variantIdentifierConflict(err, variant.SKU, variant.Barcode)
+		}
+		if stockDelta != 0 {
+			deltas := map[string]int{variant.ID: stockDelta}
+			if err := s.recordStockMovements(tctx, biz, deltas); err != nil {
+				return err
+			}
+			if err := s.applyLocationStockDeltas(tctx, biz, "", deltas); err != nil {
+				return err
+			}
+		}
+		if change == nil {
+			return nil
+		}
+		return s.storage.costChanges.CreateOne(tctx, change)
+	})
+	if err != nil {
+		return err
+	}
+	if change != nil && change.BelowMinMargin {
+		s.emitMarginAlert(ctx, biz, variant, change)
+	}
+	s.releasePhotoAssets(ctx, biz, removedPhotos(previous.Photos, variant.Photos))
+	return nil
+}
+
+func validatePromoWindow(startsAt, endsAt *time.Time) error {
+	if endsAt == nil {
+		return nil
+	}
+	if !endsAt.After(time.Now()) || (startsAt != nil && !endsAt.After(*startsAt)) {
+		return ErrInvalidPromoWindow()
+	}
+	return nil
+}
+
+// ApplyStockDeltas adjusts the stock of several variants at once, keyed by variant ID, at the
+// default location. It must run inside the caller's transaction so a conflict rolls back the
+// whole operation.
+func (s *Service) ApplyStockDeltas(ctx context.Context, actor *account.User, biz *business.Business, deltas map[string]int) error {
+	return s.ApplyLocationStockDeltas(ctx, actor, biz, "", deltas)
 }
 
 func (s *Service) UpdateCategory(ctx context.Context, actor *account.User, biz *business.Business, category *Category, req *UpdateCategoryRequest) error {
@@ -579,12 +831,19 @@ func (s *Service) UpdateCategory(ctx context.Context, actor *account.User, biz *
 	return s.storage.categories.UpdateOne(ctx, category)
 }
 
+// DeleteProduct deletes the product with its variants, then the uploaded photos no other
+// product or variant shows.
 func (s *Service) DeleteProduct(ctx context.Context, actor *account.User, biz *business.Business, id string) error {
-	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+	var photos AssetReferenceList
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
 		product, err := s.GetProductByID(tctx, actor, biz, id)
 		if err != nil {
 			return err
 		}
+		photos = append(photos, product.Photos...)
+		for _, v := range product.Variants {
+			photos = append(photos, v.Photos...)
+		}
 		if err := s.storage.variants.DeleteMany(tctx,
 			s.storage.variants.ScopeWhere("variants.business_id = ?", biz.ID),
 			s.storage.variants.ScopeEquals(VariantSchema.ProductID, product.ID),
@@ -593,14 +852,24 @@ func (s *Service) DeleteProduct(ctx context.Context, actor *account.User, biz *b
 		}
 		return s.storage.products.DeleteOne(tctx, product)
 	})
+	if err != nil {
+		return err
+	}
+	s.releasePhotoAssets(ctx, biz, photos)
+	return nil
 }
 
+// DeleteVariant deletes the variant, then the uploaded photos no other product or variant shows.
 func (s *Service) DeleteVariant(ctx context.Context, actor *account.User, biz *business.Business, id string) error {
 	variant, err := s.GetVariantByID(ctx, actor, biz, id)
 	if err != nil {
 		return err
 	}
-	return s.storage.variants.DeleteOne(ctx, variant)
+	if err := s.storage.variants.DeleteOne(ctx, variant); err != nil {
+		return err
+	}
+	s.releasePhotoAssets(ctx, biz, variant.Photos)
+	return nil
 }
 
 func (s *Service) DeleteCategory(ctx context.Context, actor *account.User, biz *business.Business, id string) error {
@@ -615,14 +884,16 @@ func (s *Service) CountLowStockVariants(ctx context.Context, actor *account.User
 	return s.storage.variants.Count(ctx,
 		s.storage.variants.ScopeWhere("variants.business_id = ?", biz.ID),
 		s.storage.ScopeLowStockVariants(),
+		s.storage.ScopeStockTrackedVariants(),
 	)
 }
 
-// CountOutOfStockVariants returns the number of variants with zero stock for the business.
+// CountOutOfStockVariants returns the number of stocked variants with zero stock for the business.
 func (s *Service) CountOutOfStockVariants(ctx context.Context, actor *account.User, biz *business.Business) (int64, error) {
 	return s.storage.variants.Count(ctx,
 		s.storage.variants.ScopeWhere("variants.business_id = ?", biz.ID),
 		s.storage.variants.ScopeEquals(VariantSchema.StockQuantity, 0),
+		s.storage.ScopeStockTrackedVariants(),
 	)
 }
 
@@ -0,0 +1,120 @@
+package inventory
+
+import (
+	"context"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/barcode"
+	"gorm.io/gorm"
+)
+
+// normalizeBarcode trims v and checks it is a valid EAN/UPC number. An empty barcode is allowed.
+func normalizeBarcode(v string) (string, error) {
+	v = strings.TrimSpace(v)
+	if v != "" && !barcode.ValidGTIN(v) {
+		return "", ErrInvalidBarcode(v)
+	}
+	return v, nil
+}
+
+// ensureVariantIdentifiersFree checks that no other variant of the business uses the SKU or
+// barcode. excludeID is the variant being updated, if any.
+func (s *Service) ensureVariantIdentifiersFree(ctx context.Context, biz *business.Business, excludeID, sku, code string) error {
+	byField := func(field, value string) []func(db *gorm.DB) *gorm.DB {
+		scopes := []func(db *gorm.DB) *gorm.DB{
+			s.storage.variants.ScopeBusinessID(biz.ID),
+			s.storage.variants.ScopeWhere(field+" = ?", value),
+		}
+		if excludeID != "" {
+			scopes = append(scopes, s.storage.variants.ScopeNotEquals(VariantSchema.ID, excludeID))
+		}
+		return scopes
+	}
+	if sku != "" {
+		n, err := s.storage.variants.Count(ctx, byField(VariantSchema.SKU.Column(), sku)...)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return ErrVariantSKUTaken(sku, nil)
+		}
+	}
+	if code != "" {
+		n, err := s.storage.variants.Count(ctx, byField(VariantSchema.Barcode.Column(), code)...)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return ErrVariantBarcodeTaken(code, nil)
+		}
+	}
+	return nil
+}
+
+// variantIdentifierConflict maps a unique violation on the variant SKU or barcode index to the
+// matching problem, and returns any other error unchanged.
+func variantIdentifierConflict(err error, sku, code string) error {
+	if !database.IsUniqueViolation(err) {
+		return err
+	}
+	if strings.Contains(err.Error(), "barcode_business_idx") {
+		return ErrVariantBarcodeTaken(code, err)
+	}
+	return ErrVariantSKUTaken(sku, err)
+}
+
+// LookupVariant finds the variant whose barcode or SKU equals code, for scanners. A barcode
+// match wins over a SKU match.
+func (s *Service) LookupVariant(ctx context.Context, actor *account.User, biz *business.Business, code string) (*Variant, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, ErrVariantNotFound(nil).With("code", code)
+	}
+	for _, field := range []string{"variants.barcode", "variants.sku"} {
+		variant, err := s.storage.variants.FindOne(ctx,
+			s.storage.variants.ScopeWhere("variants.business_id = ?", biz.ID),
+			s.storage.variants.ScopeWhere(field+" = ?", code),
+			s.storage.variants.WithPreload(ProductStruct),
+		)
+		if err == nil {
+			return variant, nil
+		}
+		if !database.IsRecordNotFound(err) {
+			return nil, err
+		}
+	}
+	return nil, ErrVariantNotFound(nil).With("code", code)
+}
+
+// ListLabelVariants returns the business's variants among ids, in the order requested. Unknown
+// IDs are skipped; ErrNoLabelVariants is returned when none remain.
+func (s *Service) ListLabelVariants(ctx context.Context, actor *account.User, biz *business.Business, ids []string) ([]*Variant, error) {
+	values := make([]any, len(ids))
+	for i, id := range ids {
+		values[i] = id
+	}
+	found, err := s.storage.variants.FindMany(ctx,
+		s.storage.variants.ScopeBusinessID(biz.ID),
+		s.storage.variants.ScopeIDs(values),
+	)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*Variant, len(found))
+	for _, v := range found {
+		byID[v.ID] = v
+	}
+	variants := make([]*Variant, 0, len(ids))
+	for _, id := range ids {
+		if v, ok := byID[id]; ok {
+			variants = append(variants, v)
+		}
+	}
+	if len(variants) == 0 {
+		return nil, ErrNoLabelVariants()
+	}
+	return variants, nil
+}
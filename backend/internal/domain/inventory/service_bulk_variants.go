@@ -0,0 +1,135 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+)
+
+// BulkVariantChangeError is a rejected change of a bulk variant update. Index is the position
+// of the change in the request.
+type BulkVariantChangeError struct {
+	Index     int    `json:"index"`
+	VariantID string `json:"variantId,omitempty"`
+	Field     string `json:"field,omitempty"`
+	Message   string `json:"message"`
+}
+
+// BulkUpdateVariants applies price and stock changes to many variants in one transaction. Every
+// change is validated first; when any is invalid nothing is written and the errors are returned
+// as ErrBulkVariantUpdateInvalid. Price changes are recorded in the cost history and stock
+// deltas are applied at the default location, as UpdateVariant does. It returns the updated
+// variants in request order.
+func (s *Service) BulkUpdateVariants(ctx context.Context, actor *account.User, biz *business.Business, req *BulkUpdateVariantsRequest) ([]*Variant, error) {
+	ids := make([]any, 0, len(req.Changes))
+	for _, ch := range req.Changes {
+		ids = append(ids, ch.VariantID)
+	}
+
+	var updated []*Variant
+	var alerts []*VariantCostChange
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		found, err := s.storage.variants.FindMany(tctx,
+			s.storage.variants.ScopeBusinessID(biz.ID),
+			s.storage.variants.ScopeIDs(ids),
+			s.storage.variants.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return err
+		}
+		byID := make(map[string]*Variant, len(found))
+		for _, v := range found {
+			byID[v.ID] = v
+		}
+
+		var errs []BulkVariantChangeError
+		seen := make(map[string]bool, len(req.Changes))
+		for i, ch := range req.Changes {
+			fail := func(field, message string) {
+				errs = append(errs, BulkVariantChangeError{Index: i, VariantID: ch.VariantID, Field: field, Message: message})
+			}
+			v := byID[ch.VariantID]
+			switch {
+			case v == nil:
+				fail("variantId", "variant not found")
+				continue
+			case seen[ch.VariantID]:
+				fail("variantId", "variant is changed more than once")
+				continue
+			case ch.SalePrice == nil && ch.CostPrice == nil && (ch.StockDelta == nil || *ch.StockDelta == 0):
+				fail("", "change has nothing to apply")
+				continue
+			}
+			seen[ch.VariantID] = true
+			if ch.SalePrice != nil && ch.SalePrice.IsNegative() {
+				fail("salePrice", "salePrice must be >= 0")
+			}
+			if ch.CostPrice != nil && ch.CostPrice.IsNegative() {
+				fail("costPrice", "costPrice must be >= 0")
+			}
+			if ch.StockDelta != nil && v.StockQuantity+*ch.StockDelta < 0 {
+				fail("stockDelta", "stock cannot go below zero")
+			}
+		}
+		if len(errs) > 0 {
+			return ErrBulkVariantUpdateInvalid(errs)
+		}
+
+		now := time.Now()
+		deltas := make(map[string]int)
+		var changes []*VariantCostChange
+		updated = make([]*Variant, 0, len(req.Changes))
+		for _, ch := range req.Changes {
+			v := byID[ch.VariantID]
+			previous := *v
+			if ch.SalePrice != nil {
+				v.SalePrice = *ch.SalePrice
+			}
+			if ch.CostPrice != nil {
+				v.CostPrice = *ch.CostPrice
+			}
+			if ch.StockDelta != nil && *ch.StockDelta != 0 {
+				v.StockQuantity += *ch.StockDelta
+				deltas[v.ID] = *ch.StockDelta
+			}
+			if err := s.storage.variants.UpdateOne(tctx, v); err != nil {
+				return err
+			}
+			if !v.CostPrice.Equal(previous.CostPrice) || !v.SalePrice.Equal(previous.SalePrice) {
+				change := newCostChange(actor, v, &previous, now)
+				change.BelowMinMargin = crossesBelowMinMargin(biz, change)
+				changes = append(changes, change)
+			}
+			updated = append(updated, v)
+		}
+		if len(changes) > 0 {
+			if err := s.storage.costChanges.CreateMany(tctx, changes); err != nil {
+				return err
+			}
+		}
+		if err := s.recordStockMovements(tctx, biz, deltas); err != nil {
+			return err
+		}
+		if err := s.applyLocationStockDeltas(tctx, biz, "", deltas); err != nil {
+			return err
+		}
+		alerts = changes
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*Variant, len(updated))
+	for _, v := range updated {
+		byID[v.ID] = v
+	}
+	for _, change := range alerts {
+		if change.BelowMinMargin {
+			s.emitMarginAlert(ctx, biz, byID[change.VariantID], change)
+		}
+	}
+	return updated, nil
+}
@@ -0,0 +1,369 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+)
+
+// CatalogImportResult summarizes a catalog import. With DryRun the file was only validated
+// and the counts are what an import would do. Rows counts the valid rows; VariantsUpdated
+// counts every existing variant named in the file, changed or not.
+type CatalogImportResult struct {
+	DryRun            bool
+	Rows              int
+	CategoriesCreated int
+	ProductsCreated   int
+	ProductsUpdated   int
+	VariantsCreated   int
+	VariantsUpdated   int
+	Errors            []CatalogImportRowError
+}
+
+// catalogProductPlan is the rows of one product in an import, with the existing product and
+// category they resolve to (nil when the import creates them).
+type catalogProductPlan struct {
+	name        string
+	description string
+	category    string
+	firstLine   int
+	existing    *Product
+	rows        []*catalogRow
+	variants    map[string]*Variant
+}
+
+// ImportCatalog creates and updates products and variants from a catalog CSV. The whole file
+// is validated first; when any row is invalid nothing is written and the errors are returned
+// (as the result for a dry run, otherwise as ErrCatalogImportInvalid). Valid imports are
+// applied in one transaction. Products are matched by name and variants by code; missing
+// categories are created.
+func (s *Service) ImportCatalog(ctx context.Context, actor *account.User, biz *business.Business, data []byte, dryRun bool) (*CatalogImportResult, error) {
+	rows, errs := parseCatalogCSV(data)
+	if len(rows) == 0 && len(errs) == 0 {
+		errs = append(errs, CatalogImportRowError{Row: 1, Message: "file has no rows"})
+	}
+	result := &CatalogImportResult{DryRun: dryRun, Rows: len(rows)}
+
+	plans, planErrs, err := s.planCatalogImport(ctx, biz, rows)
+	if err != nil {
+		return nil, err
+	}
+	errs = append(errs, planErrs...)
+	categories, err := s.storage.categories.FindMany(ctx, s.storage.categories.ScopeBusinessID(biz.ID))
+	if err != nil {
+		return nil, err
+	}
+	missing := map[string]bool{}
+	for _, p := range plans {
+		if findCatalogCategory(categories, p.category) == nil {
+			missing[catalogCategoryDescriptor(p.category)] = true
+		}
+		if p.existing == nil {
+			result.ProductsCreated++
+		} else {
+			result.ProductsUpdated++
+		}
+		for _, row := range p.rows {
+			if p.variants[row.variantCode] == nil {
+				result.VariantsCreated++
+			} else {
+				result.VariantsUpdated++
+			}
+		}
+	}
+	result.CategoriesCreated = len(missing)
+
+	if len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool { return errs[i].Row < errs[j].Row })
+		if !dryRun {
+			return nil, ErrCatalogImportInvalid(errs)
+		}
+		result.Errors = errs
+		return result, nil
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		for _, p := range plans {
+			category := findCatalogCategory(categories, p.category)
+			if category == nil {
+				category, err = s.CreateCategory(tctx, actor, biz, &CreateCategoryRequest{Name: p.category, Descriptor: catalogCategoryDescriptor(p.category)})
+				if err != nil {
+					return err
+				}
+				categories = append(categories, category)
+			}
+			if err := s.applyCatalogProduct(tctx, actor, biz, p, category); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// planCatalogImport groups rows by product and checks them against each other and against the
+// business's existing products and variant identifiers.
+func (s *Service) planCatalogImport(ctx context.Context, biz *business.Business, rows []*catalogRow) ([]*catalogProductPlan, []CatalogImportRowError, error) {
+	var errs []CatalogImportRowError
+	fail := func(line int, col, msg string) {
+		errs = append(errs, CatalogImportRowError{Row: line, Column: col, Message: msg})
+	}
+
+	var plans []*catalogProductPlan
+	byName := map[string]*catalogProductPlan{}
+	names := []any{}
+	for _, row := range rows {
+		key := strings.ToLower(row.productName)
+		p := byName[key]
+		if p == nil {
+			p = &catalogProductPlan{name: row.productName, description: row.productDescription, category: row.category, firstLine: row.line, variants: map[string]*Variant{}}
+			byName[key] = p
+			plans = append(plans, p)
+			names = append(names, key)
+		}
+		if !strings.EqualFold(row.category, p.category) {
+			fail(row.line, catalogColCategory, fmt.Sprintf("differs from the category of this product on row %d", p.firstLine))
+			continue
+		}
+		p.rows = append(p.rows, row)
+	}
+	if len(plans) == 0 {
+		return nil, errs, nil
+	}
+
+	existing, err := s.storage.products.FindMany(ctx,
+		s.storage.products.ScopeBusinessID(biz.ID),
+		s.storage.products.ScopeWhere("LOWER(products.name) IN ?", names),
+		s.storage.products.WithPreload(ProductVariantsStruct),
+		s.storage.products.WithOrderBy([]string{"products.created_at ASC"}),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, product := range existing {
+		p := byName[strings.ToLower(product.Name)]
+		if p.existing != nil {
+			continue
+		}
+		p.existing = product
+		for _, v := range product.Variants {
+			p.variants[v.Code] = v
+		}
+	}
+
+	// target is the variant each SKU and barcode in the file should end up on; an empty ID
+	// stands for a variant the import creates.
+	type target struct {
+		line      int
+		variantID string
+	}
+	skus, barcodes := map[string]target{}, map[string]target{}
+	for _, p := range plans {
+		codes := map[string]int{}
+		for _, row := range p.rows {
+			if line, ok := codes[row.variantCode]; ok {
+				fail(row.line, catalogColVariantCode, fmt.Sprintf("duplicates row %d", line))
+				continue
+			}
+			codes[row.variantCode] = row.line
+			variantID := ""
+			if v := p.variants[row.variantCode]; v != nil {
+				variantID = v.ID
+			}
+			if row.sku != "" {
+				if t, ok := skus[row.sku]; ok {
+					fail(row.line, catalogColSKU, fmt.Sprintf("duplicates row %d", t.line))
+				} else {
+					skus[row.sku] = target{row.line, variantID}
+				}
+			}
+			if row.barcode != "" {
+				if t, ok := barcodes[row.barcode]; ok {
+					fail(row.line, catalogColBarcode, fmt.Sprintf("duplicates row %d", t.line))
+				} else {
+					barcodes[row.barcode] = target{row.line, variantID}
+				}
+			}
+		}
+	}
+	for _, check := range []struct {
+		field   string
+		column  string
+		targets map[string]target
+	}{
+		{VariantSchema.SKU.Column(), catalogColSKU, skus},
+		{VariantSchema.Barcode.Column(), catalogColBarcode, barcodes},
+	} {
+		if len(check.targets) == 0 {
+			continue
+		}
+		values := make([]any, 0, len(check.targets))
+		for v := range check.targets {
+			values = append(values, v)
+		}
+		owners, err := s.storage.variants.FindMany(ctx,
+			s.storage.variants.ScopeBusinessID(biz.ID),
+			s.storage.variants.ScopeWhere(check.field+" IN ?", values),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, v := range owners {
+			value := v.SKU
+			if check.column == catalogColBarcode {
+				value = v.Barcode
+			}
+			if t := check.targets[value]; t.variantID != v.ID {
+				fail(t.line, check.column, "is used by variant "+v.Name)
+			}
+		}
+	}
+	return plans, errs, nil
+}
+
+// applyCatalogProduct writes one planned product and its variants.
+func (s *Service) applyCatalogProduct(ctx context.Context, actor *account.User, biz *business.Business, p *catalogProductPlan, category *Category) error {
+	if p.existing == nil {
+		req := &CreateProductWithVariantsRequest{
+			Product:  CreateProductRequest{Name: p.name, Description: p.description, CategoryID: category.ID},
+			Variants: make([]CreateProductVariantRequest, len(p.rows)),
+		}
+		for i, row := range p.rows {
+			req.Variants[i] = CreateProductVariantRequest{
+				Code:               row.variantCode,
+				SKU:                row.sku,
+				Barcode:            row.barcode,
+				CostPrice:          &row.costPrice,
+				SalePrice:          &row.salePrice,
+				StockQuantity:      &row.stockQuantity,
+				StockQuantityAlert: &row.stockQuantityAlert,
+			}
+		}
+		_, err := s.CreateProductWithVariants(ctx, actor, biz, req)
+		return err
+	}
+
+	update := &UpdateProductRequest{}
+	if p.description != "" && p.description != p.existing.Description {
+		update.Description = p.description
+	}
+	if category.ID != p.existing.CategoryID {
+		update.CategoryID = category.ID
+	}
+	if update.Description != "" || update.CategoryID != "" {
+		if err := s.UpdateProduct(ctx, actor, biz, p.existing, update); err != nil {
+			return err
+		}
+	}
+	for _, row := range p.rows {
+		v := p.variants[row.variantCode]
+		if v == nil {
+			_, err := s.CreateVariant(ctx, actor, biz, &CreateVariantRequest{
+				ProductID:          p.existing.ID,
+				Code:               row.variantCode,
+				SKU:                row.sku,
+				Barcode:            row.barcode,
+				CostPrice:          &row.costPrice,
+				SalePrice:          &row.salePrice,
+				StockQuantity:      &row.stockQuantity,
+				StockQuantityAlert: &row.stockQuantityAlert,
+			})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		req := &UpdateVariantRequest{
+			CostPrice:     &row.costPrice,
+			SalePrice:     &row.salePrice,
+			StockQuantity: &row.stockQuantity,
+		}
+		if row.sku != "" {
+			req.SKU = &row.sku
+		}
+		if row.hasBarcode {
+			req.Barcode = &row.barcode
+		}
+		if row.hasStockAlert {
+			req.StockQuantityAlert = &row.stockQuantityAlert
+		}
+		if err := s.UpdateVariant(ctx, actor, biz, v.ID, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportCatalog writes every variant of the business as a catalog CSV, ordered by product
+// name and variant code, in the format ImportCatalog reads.
+func (s *Service) ExportCatalog(ctx context.Context, actor *account.User, biz *business.Business) ([]byte, error) {
+	variants, err := s.storage.variants.FindMany(ctx,
+		s.storage.variants.ScopeBusinessID(biz.ID),
+		s.storage.variants.WithPreload("Product.Category"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	variants = withProducts(variants)
+	sort.SliceStable(variants, func(i, j int) bool {
+		a, b := strings.ToLower(variants[i].Product.Name), strings.ToLower(variants[j].Product.Name)
+		if a != b {
+			return a < b
+		}
+		if variants[i].ProductID != variants[j].ProductID {
+			return variants[i].ProductID < variants[j].ProductID
+		}
+		return variants[i].Code < variants[j].Code
+	})
+	return RenderCatalogCSV(variants)
+}
+
+// withProducts drops variants whose product was deleted.
+func withProducts(variants []*Variant) []*Variant {
+	kept := variants[:0]
+	for _, v := range variants {
+		if v.Product != nil {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// EnsureCategory returns the business's category matching name (by name or descriptor, as the
+// catalog import matches them), creating it when there is none.
+func (s *Service) EnsureCategory(ctx context.Context, actor *account.User, biz *business.Business, name string) (*Category, error) {
+	categories, err := s.storage.categories.FindMany(ctx, s.storage.categories.ScopeBusinessID(biz.ID))
+	if err != nil {
+		return nil, err
+	}
+	if category := findCatalogCategory(categories, name); category != nil {
+		return category, nil
+	}
+	return s.CreateCategory(ctx, actor, biz, &CreateCategoryRequest{Name: name, Descriptor: catalogCategoryDescriptor(name)})
+}
+
+// findCatalogCategory matches an import's category cell to a category by name or descriptor.
+func findCatalogCategory(categories []*Category, value string) *Category {
+	descriptor := catalogCategoryDescriptor(value)
+	for _, c := range categories {
+		if strings.EqualFold(c.Name, value) || c.Descriptor == descriptor {
+			return c
+		}
+	}
+	return nil
+}
+
+// catalogCategoryDescriptor derives the descriptor of a category an import creates.
+func catalogCategoryDescriptor(name string) string {
+	return strings.Join(strings.Fields(normalizeCategoryDescriptor(name)), "-")
+}
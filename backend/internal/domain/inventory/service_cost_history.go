@@ -0,0 +1,92 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/bus"
+	"github.com/shopspring/decimal"
+)
+
+// newCostChange snapshots the current prices of v as a cost change. previous is the variant as
+// it was before the change, or nil when v is being created.
+func newCostChange(actor *account.User, v *Variant, previous *Variant, at time.Time) *VariantCostChange {
+	change := &VariantCostChange{
+		BusinessID:    v.BusinessID,
+		ProductID:     v.ProductID,
+		VariantID:     v.ID,
+		CostPrice:     v.CostPrice,
+		SalePrice:     v.SalePrice,
+		Currency:      v.Currency,
+		MarginPercent: marginPercent(v.CostPrice, v.SalePrice),
+		ChangedAt:     at.UTC(),
+	}
+	if actor != nil {
+		change.ChangedByID = actor.ID
+	}
+	if previous != nil {
+		change.PreviousCostPrice = decimal.NewNullDecimal(previous.CostPrice)
+		change.PreviousSalePrice = decimal.NewNullDecimal(previous.SalePrice)
+	}
+	return change
+}
+
+// crossesBelowMinMargin reports whether change drops the variant margin below the business'
+// minimum. A margin already below it before the change does not alert again.
+func crossesBelowMinMargin(biz *business.Business, change *VariantCostChange) bool {
+	minMargin := biz.MinMarginPercent
+	if !minMargin.IsPositive() || !change.MarginPercent.Valid || !change.MarginPercent.Decimal.LessThan(minMargin) {
+		return false
+	}
+	if !change.PreviousCostPrice.Valid {
+		return false
+	}
+	before := marginPercent(change.PreviousCostPrice.Decimal, change.PreviousSalePrice.Decimal)
+	return !before.Valid || before.Decimal.GreaterThanOrEqual(minMargin)
+}
+
+// emitMarginAlert publishes inventory.margin_below_threshold for a recorded change.
+func (s *Service) emitMarginAlert(ctx context.Context, biz *business.Business, v *Variant, change *VariantCostChange) {
+	if s.bus == nil {
+		return
+	}
+	s.bus.Emit(bus.VariantMarginBelowThresholdTopic, &bus.VariantMarginBelowThresholdEvent{
+		Ctx:              context.WithoutCancel(ctx),
+		BusinessID:       biz.ID,
+		ProductID:        v.ProductID,
+		VariantID:        v.ID,
+		VariantName:      v.Name,
+		CostChangeID:     change.ID,
+		CostPrice:        change.CostPrice,
+		SalePrice:        change.SalePrice,
+		Currency:         change.Currency,
+		MarginPercent:    change.MarginPercent.Decimal,
+		ThresholdPercent: biz.MinMarginPercent,
+		ChangedAt:        change.ChangedAt,
+	})
+}
+
+// GetVariantCostTrend returns the price changes of a variant within [from, to] with its
+// monthly sales at the cost and price effective when each order was placed.
+func (s *Service) GetVariantCostTrend(ctx context.Context, actor *account.User, biz *business.Business, variantID string, from, to time.Time) (*CostTrend, error) {
+	variant, err := s.GetVariantByID(ctx, actor, biz, variantID)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := s.storage.costChanges.FindMany(ctx,
+		s.storage.costChanges.ScopeBusinessID(biz.ID),
+		s.storage.costChanges.ScopeEquals(VariantCostChangeSchema.VariantID, variant.ID),
+		s.storage.costChanges.ScopeTime(VariantCostChangeSchema.ChangedAt, from, to),
+		s.storage.costChanges.WithOrderBy([]string{"changed_at ASC"}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	periods, err := s.storage.SumVariantSalesByMonth(ctx, biz.ID, variant.ID, biz.Currency, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &CostTrend{Variant: variant, From: from, To: to, Changes: changes, Periods: periods}, nil
+}
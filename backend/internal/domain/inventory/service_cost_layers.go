@@ -0,0 +1,256 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/shopspring/decimal"
+)
+
+// landedCostPlaces is the precision landed costs are stored at. It is finer than money so
+// averaging receipts at different costs does not drift.
+const landedCostPlaces = 4
+
+// variantCost is a variant's stock at cost while it moves: its open cost layers, oldest first,
+// and the unit cost the stock on hand is valued at.
+type variantCost struct {
+	variant *Variant
+	fifo    bool
+	open    []*CostLayer
+	created []*CostLayer
+	drained map[string]*CostLayer
+	landed  decimal.Decimal
+}
+
+func (c *variantCost) onHand() int {
+	units := 0
+	for _, l := range c.open {
+		units += l.RemainingQuantity
+	}
+	return units
+}
+
+// reconcile opens or drains layers until the open layers add up to the variant's stock. Stock
+// that changed outside cost layers (initial stock, edits, stocktakes) is valued at the
+// variant's current cost.
+func (c *variantCost) reconcile(at time.Time) {
+	switch diff := c.variant.StockQuantity - c.onHand(); {
+	case diff > 0:
+		c.receive(CostLayerSourceAdjustment, "", diff, c.landed, at)
+	case diff < 0:
+		c.take(-diff)
+		c.revalue()
+	}
+}
+
+// receive opens a layer for qty units at unitCost. Under weighted-average costing the landed
+// cost becomes the average of the stock on hand and the units received.
+func (c *variantCost) receive(source CostLayerSource, sourceID string, qty int, unitCost decimal.Decimal, at time.Time) {
+	if qty <= 0 {
+		return
+	}
+	onHand := c.onHand()
+	layer := &CostLayer{
+		BusinessID:        c.variant.BusinessID,
+		VariantID:         c.variant.ID,
+		Source:            source,
+		SourceID:          sourceID,
+		Quantity:          qty,
+		RemainingQuantity: qty,
+		UnitCost:          unitCost,
+		ReceivedAt:        at,
+	}
+	c.open = append(c.open, layer)
+	c.created = append(c.created, layer)
+	if c.fifo {
+		c.revalue()
+		return
+	}
+	value := c.landed.Mul(decimal.NewFromInt(int64(onHand))).Add(unitCost.Mul(decimal.NewFromInt(int64(qty))))
+	c.landed = value.Div(decimal.NewFromInt(int64(onHand + qty))).Round(landedCostPlaces)
+}
+
+// issue takes qty units out of the oldest layers and returns their cost: what the layers cost
+// under FIFO, the landed cost under weighted average.
+func (c *variantCost) issue(qty int) decimal.Decimal {
+	layered := c.take(qty)
+	if !c.fifo {
+		return c.landed.Mul(decimal.NewFromInt(int64(qty)))
+	}
+	c.revalue()
+	return layered
+}
+
+// take drains qty units from the oldest layers and returns what they cost. Units beyond the
+// open layers are costed at the landed cost.
+func (c *variantCost) take(qty int) decimal.Decimal {
+	cost := decimal.Zero
+	for qty > 0 && len(c.open) > 0 {
+		l := c.open[0]
+		n := min(qty, l.RemainingQuantity)
+		l.RemainingQuantity -= n
+		qty -= n
+		cost = cost.Add(l.UnitCost.Mul(decimal.NewFromInt(int64(n))))
+		if l.ID != "" {
+			c.drained[l.ID] = l
+		}
+		if l.RemainingQuantity == 0 {
+			c.open = c.open[1:]
+		}
+	}
+	return cost.Add(c.landed.Mul(decimal.NewFromInt(int64(qty))))
+}
+
+// revalue sets the FIFO landed cost to the average cost of the open layers. An empty variant
+// keeps its last landed cost.
+func (c *variantCost) revalue() {
+	if !c.fifo {
+		return
+	}
+	units, value := 0, decimal.Zero
+	for _, l := range c.open {
+		units += l.RemainingQuantity
+		value = value.Add(l.UnitCost.Mul(decimal.NewFromInt(int64(l.RemainingQuantity))))
+	}
+	if units > 0 {
+		c.landed = value.Div(decimal.NewFromInt(int64(units))).Round(landedCostPlaces)
+	}
+}
+
+// loadVariantCosts locks the variants and loads their open cost layers, reconciled with the
+// stock they hold before the movement. Variants that do not exist are absent.
+func (s *Service) loadVariantCosts(ctx context.Context, biz *business.Business, variantIDs []string, at time.Time) (map[string]*variantCost, error) {
+	ids := make([]any, len(variantIDs))
+	for i, id := range variantIDs {
+		ids[i] = id
+	}
+	variants, err := s.storage.variants.FindMany(ctx,
+		s.storage.variants.ScopeBusinessID(biz.ID),
+		s.storage.variants.ScopeIDs(ids),
+		s.storage.variants.WithOrderBy([]string{"id ASC"}),
+		s.storage.variants.WithLockingStrength(database.LockingStrengthUpdate),
+	)
+	if err != nil {
+		return nil, err
+	}
+	layers, err := s.storage.costLayers.FindMany(ctx,
+		s.storage.costLayers.ScopeBusinessID(biz.ID),
+		s.storage.costLayers.ScopeIn(CostLayerSchema.VariantID, ids),
+		s.storage.costLayers.ScopeGreaterThan(CostLayerSchema.RemainingQuantity, 0),
+		s.storage.costLayers.WithOrderBy([]string{"received_at ASC", "id ASC"}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	costs := make(map[string]*variantCost, len(variants))
+	for _, v := range variants {
+		costs[v.ID] = &variantCost{
+			variant: v,
+			fifo:    biz.CostingMethod == business.CostingMethodFIFO,
+			drained: map[string]*CostLayer{},
+			landed:  v.CurrentCost(),
+		}
+	}
+	for _, l := range layers {
+		if c, ok := costs[l.VariantID]; ok {
+			c.open = append(c.open, l)
+		}
+	}
+	for _, c := range costs {
+		c.reconcile(at)
+	}
+	return costs, nil
+}
+
+// saveVariantCosts persists the layers opened and drained and the variants' new landed costs.
+func (s *Service) saveVariantCosts(ctx context.Context, biz *business.Business, costs map[string]*variantCost) error {
+	var created, drained []*CostLayer
+	for _, c := range costs {
+		created = append(created, c.created...)
+		for _, l := range c.drained {
+			drained = append(drained, l)
+		}
+	}
+	if len(created) > 0 {
+		if err := s.storage.costLayers.CreateMany(ctx, created); err != nil {
+			return err
+		}
+	}
+	if len(drained) > 0 {
+		if err := s.storage.costLayers.UpdateMany(ctx, drained); err != nil {
+			return err
+		}
+	}
+	for _, c := range costs {
+		if c.landed.Equal(c.variant.LandedCost) {
+			continue
+		}
+		if err := s.storage.SetLandedCost(ctx, biz.ID, c.variant.ID, c.landed); err != nil {
+			return err
+		}
+		c.variant.LandedCost = c.landed
+	}
+	return nil
+}
+
+// ReceiveStock puts units into stock at a location (the default location when empty) and opens
+// a cost layer for each variant at its receipt's unit cost.
+func (s *Service) ReceiveStock(ctx context.Context, actor *account.User, biz *business.Business, locationID string, source CostLayerSource, sourceID string, receipts map[string]StockReceipt) error {
+	if len(receipts) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	variantIDs := make([]string, 0, len(receipts))
+	for id := range receipts {
+		variantIDs = append(variantIDs, id)
+	}
+	costs, err := s.loadVariantCosts(ctx, biz, variantIDs, now)
+	if err != nil {
+		return err
+	}
+	deltas := make(map[string]int, len(receipts))
+	for id, r := range receipts {
+		deltas[id] = r.Quantity
+		if c, ok := costs[id]; ok {
+			c.receive(source, sourceID, r.Quantity, r.UnitCost, now)
+		}
+	}
+	if err := s.ApplyLocationStockDeltas(ctx, actor, biz, locationID, deltas); err != nil {
+		return err
+	}
+	return s.saveVariantCosts(ctx, biz, costs)
+}
+
+// IssueStock takes units out of stock at a location (the default location when empty) and
+// returns, per variant, what the units taken cost under the business' costing method.
+func (s *Service) IssueStock(ctx context.Context, actor *account.User, biz *business.Business, locationID string, quantities map[string]int) (map[string]decimal.Decimal, error) {
+	if len(quantities) == 0 {
+		return map[string]decimal.Decimal{}, nil
+	}
+	variantIDs := make([]string, 0, len(quantities))
+	for id := range quantities {
+		variantIDs = append(variantIDs, id)
+	}
+	costs, err := s.loadVariantCosts(ctx, biz, variantIDs, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	deltas := make(map[string]int, len(quantities))
+	issued := make(map[string]decimal.Decimal, len(quantities))
+	for id, qty := range quantities {
+		deltas[id] = -qty
+		if c, ok := costs[id]; ok && qty <= c.variant.StockQuantity {
+			issued[id] = c.issue(qty)
+		}
+	}
+	if err := s.ApplyLocationStockDeltas(ctx, actor, biz, locationID, deltas); err != nil {
+		return nil, err
+	}
+	if err := s.saveVariantCosts(ctx, biz, costs); err != nil {
+		return nil, err
+	}
+	return issued, nil
+}
@@ -0,0 +1,76 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
+)
+
+// StartFlashSale schedules a promo price PercentOff below SalePrice on every variant of the
+// category, replacing any promo they already had. It returns the updated variants.
+func (s *Service) StartFlashSale(ctx context.Context, actor *account.User, biz *business.Business, category *Category, req *FlashSaleRequest) ([]*Variant, error) {
+	if req.PercentOff.GreaterThanOrEqual(decimal.NewFromInt(100)) {
+		return nil, problem.BadRequest("percentOff must be less than 100").With("field", "percentOff")
+	}
+	startsAt := time.Now().UTC()
+	if req.StartsAt != nil {
+		startsAt = req.StartsAt.UTC()
+	}
+	endsAt := req.EndsAt.UTC()
+	if err := validatePromoWindow(&startsAt, &endsAt); err != nil {
+		return nil, err
+	}
+	factor := decimal.NewFromInt(100).Sub(req.PercentOff).Div(decimal.NewFromInt(100))
+
+	var variants []*Variant
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		var err error
+		variants, err = s.categoryVariants(tctx, biz, category)
+		if err != nil {
+			return err
+		}
+		for _, v := range variants {
+			v.PromoPrice = decimal.NewNullDecimal(money.Round(v.SalePrice.Mul(factor), biz.Currency))
+			v.PromoStartsAt = &startsAt
+			v.PromoEndsAt = &endsAt
+			if err := s.storage.variants.UpdateOne(tctx, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+// EndFlashSale clears the promo price of every variant of the category and returns how many
+// variants had one.
+func (s *Service) EndFlashSale(ctx context.Context, actor *account.User, biz *business.Business, category *Category) (int64, error) {
+	res := s.storage.db.Conn(ctx).
+		Model(&Variant{}).
+		Where("business_id = ? AND promo_price IS NOT NULL", biz.ID).
+		Where("product_id IN (?)", s.storage.db.Conn(ctx).Model(&Product{}).Select("id").Where("business_id = ? AND category_id = ?", biz.ID, category.ID)).
+		Updates(map[string]any{
+			VariantSchema.PromoPrice.Column():    nil,
+			VariantSchema.PromoStartsAt.Column(): nil,
+			VariantSchema.PromoEndsAt.Column():   nil,
+		})
+	return res.RowsAffected, res.Error
+}
+
+func (s *Service) categoryVariants(ctx context.Context, biz *business.Business, category *Category) ([]*Variant, error) {
+	return s.storage.variants.FindMany(ctx,
+		s.storage.variants.ScopeBusinessID(biz.ID),
+		s.storage.variants.ScopeWhere("variants.product_id IN (?)",
+			s.storage.db.Conn(ctx).Model(&Product{}).Select("id").Where("business_id = ? AND category_id = ?", biz.ID, category.ID),
+		),
+		s.storage.variants.WithOrderBy([]string{VariantSchema.Name.Column()}),
+	)
+}
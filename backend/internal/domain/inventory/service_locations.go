@@ -0,0 +1,349 @@
+package inventory
+
+import (
+	"context"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/atomic"
+)
+
+func (s *Service) ListLocations(ctx context.Context, actor *account.User, biz *business.Business) ([]*Location, error) {
+	return s.storage.locations.FindMany(ctx,
+		s.storage.locations.ScopeBusinessID(biz.ID),
+		s.storage.locations.WithOrderBy([]string{LocationSchema.IsDefault.Column() + " DESC", LocationSchema.Name.Column()}),
+	)
+}
+
+func (s *Service) GetLocationByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Location, error) {
+	return s.storage.locations.FindOne(ctx,
+		s.storage.locations.ScopeBusinessID(biz.ID),
+		s.storage.locations.ScopeID(id),
+	)
+}
+
+// resolveLocation returns the location stock changes apply to: locationID when it still exists,
+// otherwise the business' default location. It returns nil when the business has no locations,
+// meaning only variant totals are tracked.
+func (s *Service) resolveLocation(ctx context.Context, biz *business.Business, locationID string) (*Location, error) {
+	if locationID = strings.TrimSpace(locationID); locationID != "" {
+		loc, err := s.GetLocationByID(ctx, nil, biz, locationID)
+		if err == nil {
+			return loc, nil
+		}
+		if !database.IsRecordNotFound(err) {
+			return nil, err
+		}
+	}
+	loc, err := s.storage.locations.FindOne(ctx,
+		s.storage.locations.ScopeBusinessID(biz.ID),
+		s.storage.locations.ScopeEquals(LocationSchema.IsDefault, true),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return loc, nil
+}
+
+// CreateLocation adds a stock location. The first location of a business becomes its default
+// and takes over the whole current stock of every variant.
+func (s *Service) CreateLocation(ctx context.Context, actor *account.User, biz *business.Business, req *CreateLocationRequest) (*Location, error) {
+	name := strings.TrimSpace(req.Name)
+	loc := &Location{
+		BusinessID: biz.ID,
+		Name:       name,
+		Address:    strings.TrimSpace(req.Address),
+		IsDefault:  req.IsDefault,
+	}
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		existing, err := s.storage.locations.Count(tctx, s.storage.locations.ScopeBusinessID(biz.ID))
+		if err != nil {
+			return err
+		}
+		first := existing == 0
+		if first {
+			loc.IsDefault = true
+		} else if loc.IsDefault {
+			if err := s.clearDefaultLocation(tctx, biz); err != nil {
+				return err
+			}
+		}
+		if err := s.storage.locations.CreateOne(tctx, loc); err != nil {
+			if database.IsUniqueViolation(err) {
+				return ErrLocationNameTaken(name, err)
+			}
+			return err
+		}
+		if first {
+			return s.storage.SeedLocationStock(tctx, biz.ID, loc.ID)
+		}
+		return nil
+	}, atomic.WithIsolationLevel(atomic.LevelSerializable), atomic.WithRetries(3))
+	if err != nil {
+		return nil, err
+	}
+	return loc, nil
+}
+
+// UpdateLocation renames a location or makes it the default. The default cannot be unset
+// directly: another location is made the default instead.
+func (s *Service) UpdateLocation(ctx context.Context, actor *account.User, biz *business.Business, id string, req *UpdateLocationRequest) (*Location, error) {
+	var loc *Location
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		existing, err := s.GetLocationByID(tctx, actor, biz, id)
+		if err != nil {
+			return err
+		}
+		if req.Name != nil {
+			existing.Name = strings.TrimSpace(*req.Name)
+		}
+		if req.Address != nil {
+			existing.Address = strings.TrimSpace(*req.Address)
+		}
+		if req.IsDefault != nil && *req.IsDefault != existing.IsDefault {
+			if !*req.IsDefault {
+				return ErrDefaultLocationRequired(existing.ID)
+			}
+			if err := s.clearDefaultLocation(tctx, biz); err != nil {
+				return err
+			}
+			existing.IsDefault = true
+		}
+		if err := s.storage.locations.UpdateOne(tctx, existing); err != nil {
+			if database.IsUniqueViolation(err) {
+				return ErrLocationNameTaken(existing.Name, err)
+			}
+			return err
+		}
+		loc = existing
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loc, nil
+}
+
+// DeleteLocation deletes an empty location. The default location can only be deleted when it
+// is the last one, after which the business tracks variant totals only.
+func (s *Service) DeleteLocation(ctx context.Context, actor *account.User, biz *business.Business, id string) error {
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		loc, err := s.GetLocationByID(tctx, actor, biz, id)
+		if err != nil {
+			return err
+		}
+		totals, err := s.storage.SumStockByLocation(tctx, biz.ID)
+		if err != nil {
+			return err
+		}
+		if t, ok := totals[loc.ID]; ok && t.StockUnits > 0 {
+			return ErrLocationHasStock(loc.ID, t.StockUnits)
+		}
+		if loc.IsDefault {
+			count, err := s.storage.locations.Count(tctx, s.storage.locations.ScopeBusinessID(biz.ID))
+			if err != nil {
+				return err
+			}
+			if count > 1 {
+				return ErrDefaultLocationRequired(loc.ID)
+			}
+		}
+		if err := s.storage.locationStocks.DeleteMany(tctx,
+			s.storage.locationStocks.ScopeEquals(LocationStockSchema.LocationID, loc.ID),
+		); err != nil {
+			return err
+		}
+		return s.storage.locations.DeleteOne(tctx, loc)
+	}, atomic.WithIsolationLevel(atomic.LevelSerializable), atomic.WithRetries(3))
+}
+
+// getLocationForStock loads a location named in a stock change, reporting which one is missing.
+func (s *Service) getLocationForStock(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Location, error) {
+	loc, err := s.GetLocationByID(ctx, actor, biz, id)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrLocationNotFound(err).With("locationId", id)
+		}
+		return nil, err
+	}
+	return loc, nil
+}
+
+func (s *Service) clearDefaultLocation(ctx context.Context, biz *business.Business) error {
+	return s.storage.db.Conn(ctx).
+		Model(&Location{}).
+		Where("business_id = ? AND is_default = ?", biz.ID, true).
+		Update(LocationSchema.IsDefault.Column(), false).Error
+}
+
+// ApplyLocationStockDeltas adjusts the stock of several variants at once, keyed by variant ID,
+// drawing from or returning to the given location (the default location when empty or deleted).
+// It must run inside the caller's transaction so a conflict rolls back the whole operation.
+func (s *Service) ApplyLocationStockDeltas(ctx context.Context, actor *account.User, biz *business.Business, locationID string, deltas map[string]int) error {
+	applied, err := s.storage.ApplyStockDeltas(ctx, biz.ID, deltas)
+	if err != nil {
+		return err
+	}
+	if expected := int64(len(deltas)); applied != expected {
+		return ErrStockAdjustmentConflict(expected, applied)
+	}
+	if err := s.recordStockMovements(ctx, biz, deltas); err != nil {
+		return err
+	}
+	return s.applyLocationStockDeltas(ctx, biz, locationID, deltas)
+}
+
+// applyLocationStockDeltas applies deltas to the per-location levels only. Callers keep the
+// variant totals in step.
+func (s *Service) applyLocationStockDeltas(ctx context.Context, biz *business.Business, locationID string, deltas map[string]int) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+	loc, err := s.resolveLocation(ctx, biz, locationID)
+	if err != nil || loc == nil {
+		return err
+	}
+	applied, err := s.storage.ApplyLocationStockDeltas(ctx, biz.ID, loc.ID, deltas)
+	if err != nil {
+		return err
+	}
+	if expected := int64(len(deltas)); applied != expected {
+		return ErrInsufficientLocationStock(loc.ID, expected, applied)
+	}
+	return nil
+}
+
+// ListVariantLocationStock returns the units of a variant kept at each location of the
+// business, including locations that hold none.
+func (s *Service) ListVariantLocationStock(ctx context.Context, actor *account.User, biz *business.Business, variantID string) ([]*LocationStockLevel, error) {
+	if _, err := s.GetVariantByID(ctx, actor, biz, variantID); err != nil {
+		return nil, err
+	}
+	locations, err := s.ListLocations(ctx, actor, biz)
+	if err != nil {
+		return nil, err
+	}
+	stocks, err := s.storage.locationStocks.FindMany(ctx,
+		s.storage.locationStocks.ScopeBusinessID(biz.ID),
+		s.storage.locationStocks.ScopeEquals(LocationStockSchema.VariantID, variantID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	quantities := make(map[string]int, len(stocks))
+	for _, st := range stocks {
+		quantities[st.LocationID] = st.Quantity
+	}
+	levels := make([]*LocationStockLevel, len(locations))
+	for i, loc := range locations {
+		levels[i] = &LocationStockLevel{Location: loc, Quantity: quantities[loc.ID]}
+	}
+	return levels, nil
+}
+
+// SetLocationStock sets the units of a variant kept at a location, as after a stock count.
+// The variant's total stock changes by the difference.
+func (s *Service) SetLocationStock(ctx context.Context, actor *account.User, biz *business.Business, locationID, variantID string, quantity int) error {
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if _, err := s.GetVariantByID(tctx, actor, biz, variantID); err != nil {
+			if database.IsRecordNotFound(err) {
+				return ErrVariantNotFound(err).With("variantId", variantID)
+			}
+			return err
+		}
+		loc, err := s.getLocationForStock(tctx, actor, biz, locationID)
+		if err != nil {
+			return err
+		}
+		current := 0
+		stock, err := s.storage.locationStocks.FindOne(tctx,
+			s.storage.locationStocks.ScopeBusinessID(biz.ID),
+			s.storage.locationStocks.ScopeEquals(LocationStockSchema.LocationID, loc.ID),
+			s.storage.locationStocks.ScopeEquals(LocationStockSchema.VariantID, variantID),
+			s.storage.locationStocks.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err == nil {
+			current = stock.Quantity
+		} else if !database.IsRecordNotFound(err) {
+			return err
+		}
+		if quantity == current {
+			return nil
+		}
+		return s.ApplyLocationStockDeltas(tctx, actor, biz, loc.ID, map[string]int{variantID: quantity - current})
+	}, atomic.WithIsolationLevel(atomic.LevelSerializable), atomic.WithRetries(3))
+}
+
+// TransferLocationStock moves stock from one location to another. Variant totals are unchanged.
+func (s *Service) TransferLocationStock(ctx context.Context, actor *account.User, biz *business.Business, req *LocationTransferRequest) error {
+	if req.FromLocationID == req.ToLocationID {
+		return ErrSameLocationTransfer()
+	}
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		from, err := s.getLocationForStock(tctx, actor, biz, req.FromLocationID)
+		if err != nil {
+			return err
+		}
+		to, err := s.getLocationForStock(tctx, actor, biz, req.ToLocationID)
+		if err != nil {
+			return err
+		}
+		out := make(map[string]int, len(req.Items))
+		in := make(map[string]int, len(req.Items))
+		for _, item := range req.Items {
+			if _, ok := in[item.VariantID]; !ok {
+				if _, err := s.GetVariantByID(tctx, actor, biz, item.VariantID); err != nil {
+					if database.IsRecordNotFound(err) {
+						return ErrVariantNotFound(err).With("variantId", item.VariantID)
+					}
+					return err
+				}
+			}
+			out[item.VariantID] -= item.Quantity
+			in[item.VariantID] += item.Quantity
+		}
+		if err := s.applyLocationStockDeltas(tctx, biz, from.ID, out); err != nil {
+			return err
+		}
+		return s.applyLocationStockDeltas(tctx, biz, to.ID, in)
+	}, atomic.WithIsolationLevel(atomic.LevelSerializable), atomic.WithRetries(3))
+}
+
+// ListLocationStockTotals returns the units and inventory value held at each location of the
+// business, including empty locations.
+func (s *Service) ListLocationStockTotals(ctx context.Context, actor *account.User, biz *business.Business) ([]*LocationStockTotals, error) {
+	locations, err := s.ListLocations(ctx, actor, biz)
+	if err != nil || len(locations) == 0 {
+		return nil, err
+	}
+	sums, err := s.storage.SumStockByLocation(ctx, biz.ID)
+	if err != nil {
+		return nil, err
+	}
+	totals := make([]*LocationStockTotals, len(locations))
+	for i, loc := range locations {
+		t := &LocationStockTotals{Location: loc}
+		if sum, ok := sums[loc.ID]; ok {
+			t.StockUnits = sum.StockUnits
+			t.InventoryValue = sum.InventoryValue
+		}
+		totals[i] = t
+	}
+	return totals, nil
+}
+
+// initialStock returns the stock new variants start with, keyed by variant ID.
+func initialStock(variants ...*Variant) map[string]int {
+	deltas := make(map[string]int, len(variants))
+	for _, v := range variants {
+		if v.StockQuantity > 0 {
+			deltas[v.ID] = v.StockQuantity
+		}
+	}
+	return deltas
+}
@@ -0,0 +1,146 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+)
+
+// normalizeProductOptions trims option names and values and rejects duplicates, compared
+// case-insensitively.
+func normalizeProductOptions(req []ProductOptionRequest, currency string) (ProductOptions, error) {
+	options := make(ProductOptions, len(req))
+	names := map[string]bool{}
+	for i, o := range req {
+		name := strings.TrimSpace(o.Name)
+		if name == "" || names[strings.ToLower(name)] {
+			return nil, ErrInvalidProductOptions(fmt.Sprintf("option names must be unique and not blank: %q", name))
+		}
+		names[strings.ToLower(name)] = true
+		values := make([]ProductOptionValue, len(o.Values))
+		seen := map[string]bool{}
+		for j, v := range o.Values {
+			value := strings.TrimSpace(v.Value)
+			if value == "" || seen[strings.ToLower(value)] {
+				return nil, ErrInvalidProductOptions(fmt.Sprintf("values of %s must be unique and not blank: %q", name, value))
+			}
+			seen[strings.ToLower(value)] = true
+			values[j] = ProductOptionValue{Value: value, PriceModifier: money.Round(v.PriceModifier, currency)}
+		}
+		options[i] = ProductOption{Name: name, Values: values}
+	}
+	return options, nil
+}
+
+// GenerateProductVariants stores the product's options and creates the variants of every
+// combination it lacks. A combination is matched to an existing variant by its options, or
+// by code for variants created by hand, which then take the combination's options. Variants
+// whose combination was removed from the options are kept. It returns the product with all
+// its variants and the variants it created.
+func (s *Service) GenerateProductVariants(ctx context.Context, actor *account.User, biz *business.Business, productID string, req *GenerateVariantsRequest) (*Product, []*Variant, error) {
+	if req.CostPrice.IsNegative() {
+		return nil, nil, problem.BadRequest("costPrice must be >= 0").With("field", "costPrice")
+	}
+	options, err := normalizeProductOptions(req.Options, biz.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+	matrix := options.Matrix()
+	if len(matrix) > maxVariantMatrix {
+		return nil, nil, ErrVariantMatrixTooLarge(len(matrix), maxVariantMatrix)
+	}
+
+	var (
+		product *Product
+		created []*Variant
+	)
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		created = nil
+		product, err = s.storage.products.FindOne(tctx,
+			s.storage.products.ScopeBusinessID(biz.ID),
+			s.storage.products.ScopeID(productID),
+			s.storage.products.WithPreload(ProductVariantsStruct),
+			s.storage.products.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			if database.IsRecordNotFound(err) {
+				return ErrProductNotFound(err).With("productId", productID)
+			}
+			return err
+		}
+		byOptions := map[string]*Variant{}
+		byCode := map[string]*Variant{}
+		for _, v := range product.Variants {
+			if len(v.Options) > 0 {
+				byOptions[strings.ToLower(v.Options.Code())] = v
+			}
+			byCode[strings.ToLower(v.Code)] = v
+		}
+
+		for _, combo := range matrix {
+			code := combo.Options.Code()
+			key := strings.ToLower(code)
+			if byOptions[key] != nil {
+				continue
+			}
+			if v := byCode[key]; v != nil {
+				if len(v.Options) > 0 {
+					continue
+				}
+				v.Options = combo.Options
+				if err := s.storage.variants.UpdateOne(tctx, v); err != nil {
+					return err
+				}
+				continue
+			}
+			salePrice := money.Round(req.SalePrice.Add(combo.PriceModifier), biz.Currency)
+			if salePrice.IsNegative() {
+				return ErrNegativeVariantPrice(code)
+			}
+			sku, err := s.generateSKU(tctx, biz, product, code)
+			if err != nil {
+				return err
+			}
+			created = append(created, &Variant{
+				BusinessID:         biz.ID,
+				ProductID:          product.ID,
+				Code:               code,
+				Name:               fmt.Sprintf("%s - %s", product.Name, code),
+				SKU:                sku,
+				Options:            combo.Options,
+				CostPrice:          *req.CostPrice,
+				SalePrice:          salePrice,
+				Currency:           biz.Currency,
+				Photos:             AssetReferenceList{},
+				StockQuantity:      req.StockQuantity,
+				StockQuantityAlert: req.StockQuantityAlert,
+			})
+		}
+
+		product.Options = options
+		if err := s.storage.products.UpdateOne(tctx, product); err != nil {
+			if database.IsVersionConflict(err) {
+				return ErrProductVersionConflict(product.ID, err)
+			}
+			return err
+		}
+		if len(created) == 0 {
+			return nil
+		}
+		if err := s.createVariants(tctx, actor, biz, created); err != nil {
+			return err
+		}
+		product.Variants = append(product.Variants, created...)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return product, created, nil
+}
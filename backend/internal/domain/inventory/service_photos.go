@@ -0,0 +1,273 @@
+package inventory
+
+import (
+	"context"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/types/asset"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+)
+
+// MaxProductPhotos is the most photos a product can hold.
+const MaxProductPhotos = 10
+
+// PhotoAssets looks up uploaded image assets as photo references and deletes the ones no
+// longer shown. It is implemented by the asset service.
+type PhotoAssets interface {
+	PhotoReferencesByAssetID(ctx context.Context, biz *business.Business, assetIDs []string) (map[string]asset.AssetReference, error)
+	DeleteAssets(ctx context.Context, biz *business.Business, assetIDs []string) error
+}
+
+// PhotoID identifies a photo within a product: its asset ID, or its URL for photos that were
+// not uploaded through the asset endpoints.
+func PhotoID(ref asset.AssetReference) string {
+	if ref.AssetID != nil && *ref.AssetID != "" {
+		return *ref.AssetID
+	}
+	return ref.URL
+}
+
+// AddProductPhotos attaches uploaded images to the product in one call. Assets already
+// attached are skipped.
+func (s *Service) AddProductPhotos(ctx context.Context, actor *account.User, biz *business.Business, productID string, req *AddProductPhotosRequest) (*Product, error) {
+	if err := s.requirePhotoAssets(); err != nil {
+		return nil, err
+	}
+	product, err := s.findProductForPhotos(ctx, biz, productID)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(product.Photos))
+	for _, p := range product.Photos {
+		existing[PhotoID(p)] = true
+	}
+	assetIDs := make([]string, 0, len(req.AssetIDs))
+	for _, id := range req.AssetIDs {
+		if !existing[id] {
+			existing[id] = true
+			assetIDs = append(assetIDs, id)
+		}
+	}
+	if len(product.Photos)+len(assetIDs) > MaxProductPhotos {
+		return nil, ErrTooManyProductPhotos(MaxProductPhotos, len(product.Photos)+len(assetIDs))
+	}
+	refs, err := s.photoAssets.PhotoReferencesByAssetID(ctx, biz, assetIDs)
+	if err != nil {
+		return nil, err
+	}
+	added := make(AssetReferenceList, 0, len(assetIDs))
+	for _, id := range assetIDs {
+		ref, ok := refs[id]
+		if !ok {
+			return nil, ErrPhotoAssetNotFound(id)
+		}
+		added = append(added, ref)
+	}
+	if len(added) == 0 {
+		return product, nil
+	}
+	if req.SetCover {
+		product.Photos = append(added, product.Photos...)
+	} else {
+		product.Photos = append(product.Photos, added...)
+	}
+	if err := s.saveProductPhotos(ctx, product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// ReorderProductPhotos sets the order of the product's photos; the first becomes the cover.
+func (s *Service) ReorderProductPhotos(ctx context.Context, actor *account.User, biz *business.Business, productID string, req *ReorderProductPhotosRequest) (*Product, error) {
+	product, err := s.findProductForPhotos(ctx, biz, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.PhotoIDs) != len(product.Photos) {
+		return nil, ErrInvalidPhotoOrder()
+	}
+	byID := make(map[string]asset.AssetReference, len(product.Photos))
+	for _, p := range product.Photos {
+		byID[PhotoID(p)] = p
+	}
+	ordered := make(AssetReferenceList, 0, len(req.PhotoIDs))
+	for _, id := range req.PhotoIDs {
+		p, ok := byID[id]
+		if !ok {
+			return nil, ErrInvalidPhotoOrder()
+		}
+		delete(byID, id)
+		ordered = append(ordered, p)
+	}
+	product.Photos = ordered
+	if err := s.saveProductPhotos(ctx, product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// SetProductCoverPhoto moves a photo to the front, keeping the others in order.
+func (s *Service) SetProductCoverPhoto(ctx context.Context, actor *account.User, biz *business.Business, productID string, req *SetProductCoverPhotoRequest) (*Product, error) {
+	product, err := s.findProductForPhotos(ctx, biz, productID)
+	if err != nil {
+		return nil, err
+	}
+	idx := -1
+	for i, p := range product.Photos {
+		if PhotoID(p) == req.PhotoID {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil, ErrProductPhotoNotFound(req.PhotoID)
+	}
+	if idx == 0 {
+		return product, nil
+	}
+	cover := product.Photos[idx]
+	photos := make(AssetReferenceList, 0, len(product.Photos))
+	photos = append(photos, cover)
+	photos = append(photos, product.Photos[:idx]...)
+	photos = append(photos, product.Photos[idx+1:]...)
+	product.Photos = photos
+	if err := s.saveProductPhotos(ctx, product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// RegenerateProductPhotoThumbnails rebuilds the URLs, thumbnail and WebP URLs of the product's
+// uploaded photos from their assets, e.g. after thumbnails finished rendering or the CDN changed.
+// Alt texts and captions are kept, as are photos without an asset or whose asset is gone.
+func (s *Service) RegenerateProductPhotoThumbnails(ctx context.Context, actor *account.User, biz *business.Business, productID string) (*Product, error) {
+	if err := s.requirePhotoAssets(); err != nil {
+		return nil, err
+	}
+	product, err := s.findProductForPhotos(ctx, biz, productID)
+	if err != nil {
+		return nil, err
+	}
+	assetIDs := make([]string, 0, len(product.Photos))
+	for _, p := range product.Photos {
+		if p.AssetID != nil && *p.AssetID != "" {
+			assetIDs = append(assetIDs, *p.AssetID)
+		}
+	}
+	if len(assetIDs) == 0 {
+		return product, nil
+	}
+	refs, err := s.photoAssets.PhotoReferencesByAssetID(ctx, biz, assetIDs)
+	if err != nil {
+		return nil, err
+	}
+	photos := make(AssetReferenceList, 0, len(product.Photos))
+	for _, p := range product.Photos {
+		if p.AssetID != nil {
+			if ref, ok := refs[*p.AssetID]; ok {
+				if p.Metadata != nil {
+					meta := *p.Metadata
+					if ref.Metadata != nil {
+						meta.Width, meta.Height = ref.Metadata.Width, ref.Metadata.Height
+					}
+					ref.Metadata = &meta
+				}
+				p = ref
+			}
+		}
+		photos = append(photos, p)
+	}
+	product.Photos = photos
+	if err := s.saveProductPhotos(ctx, product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// releasePhotoAssets deletes the uploaded assets of photos that no product or variant of the
+// business shows anymore. It runs once the change that dropped the photos is committed, so
+// failures are logged rather than returned.
+func (s *Service) releasePhotoAssets(ctx context.Context, biz *business.Business, photos AssetReferenceList) {
+	if s.photoAssets == nil || len(photos) == 0 {
+		return
+	}
+	assetIDs := make([]string, 0, len(photos))
+	for _, p := range photos {
+		if p.AssetID != nil && *p.AssetID != "" {
+			assetIDs = append(assetIDs, *p.AssetID)
+		}
+	}
+	if len(assetIDs) == 0 {
+		return
+	}
+	log := logger.FromContext(ctx).With("businessId", biz.ID)
+	referenced, err := s.storage.ReferencedPhotoAssetIDs(ctx, biz.ID, assetIDs)
+	if err != nil {
+		log.Error("failed to check photo asset references", "error", err)
+		return
+	}
+	inUse := make(map[string]bool, len(referenced))
+	for _, id := range referenced {
+		inUse[id] = true
+	}
+	orphaned := make([]string, 0, len(assetIDs))
+	for _, id := range assetIDs {
+		if !inUse[id] {
+			inUse[id] = true
+			orphaned = append(orphaned, id)
+		}
+	}
+	if err := s.photoAssets.DeleteAssets(ctx, biz, orphaned); err != nil {
+		log.Error("failed to delete orphaned photo assets", "error", err, "assetIds", orphaned)
+	}
+}
+
+// removedPhotos returns the photos of before that after no longer holds.
+func removedPhotos(before, after AssetReferenceList) AssetReferenceList {
+	kept := make(map[string]bool, len(after))
+	for _, p := range after {
+		kept[PhotoID(p)] = true
+	}
+	var removed AssetReferenceList
+	for _, p := range before {
+		if !kept[PhotoID(p)] {
+			removed = append(removed, p)
+		}
+	}
+	return removed
+}
+
+func (s *Service) requirePhotoAssets() error {
+	if s.photoAssets == nil {
+		return problem.InternalError().With("reason", "photo assets are not configured")
+	}
+	return nil
+}
+
+// findProductForPhotos loads the product without variants so saving it only touches the product row.
+func (s *Service) findProductForPhotos(ctx context.Context, biz *business.Business, productID string) (*Product, error) {
+	product, err := s.storage.products.FindOne(ctx,
+		s.storage.products.ScopeWhere("products.business_id = ?", biz.ID),
+		s.storage.products.ScopeID(productID),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrProductNotFound(err).With("productId", productID)
+		}
+		return nil, err
+	}
+	return product, nil
+}
+
+func (s *Service) saveProductPhotos(ctx context.Context, product *Product) error {
+	if err := s.storage.products.UpdateOne(ctx, product); err != nil {
+		if database.IsVersionConflict(err) {
+			return ErrProductVersionConflict(product.ID, err)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package inventory
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
+)
+
+func (s *Service) ListPriceLists(ctx context.Context, actor *account.User, biz *business.Business) ([]*PriceList, error) {
+	return s.storage.priceLists.FindMany(ctx,
+		s.storage.priceLists.ScopeBusinessID(biz.ID),
+		s.storage.priceLists.WithOrderBy([]string{PriceListSchema.Name.Column()}),
+	)
+}
+
+func (s *Service) GetPriceListByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*PriceList, error) {
+	return s.storage.priceLists.FindOne(ctx,
+		s.storage.priceLists.ScopeBusinessID(biz.ID),
+		s.storage.priceLists.ScopeID(id),
+		s.storage.priceLists.WithPreload(PriceListEntriesStruct),
+	)
+}
+
+// ResolvePriceList returns the list, with entries, that prices items for a customer assigned
+// to priceListID. It falls back to the business' default list when priceListID is empty or no
+// longer exists, and returns nil when there is neither, meaning variants sell at SalePrice.
+func (s *Service) ResolvePriceList(ctx context.Context, biz *business.Business, priceListID string) (*PriceList, error) {
+	if priceListID = strings.TrimSpace(priceListID); priceListID != "" {
+		pl, err := s.GetPriceListByID(ctx, nil, biz, priceListID)
+		if err == nil {
+			return pl, nil
+		}
+		if !database.IsRecordNotFound(err) {
+			return nil, err
+		}
+	}
+	pl, err := s.storage.priceLists.FindOne(ctx,
+		s.storage.priceLists.ScopeBusinessID(biz.ID),
+		s.storage.priceLists.ScopeEquals(PriceListSchema.IsDefault, true),
+		s.storage.priceLists.WithPreload(PriceListEntriesStruct),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return pl, nil
+}
+
+func (s *Service) CreatePriceList(ctx context.Context, actor *account.User, biz *business.Business, req *CreatePriceListRequest) (*PriceList, error) {
+	name := strings.TrimSpace(req.Name)
+	pl := &PriceList{
+		BusinessID:        biz.ID,
+		Name:              name,
+		Description:       strings.TrimSpace(req.Description),
+		IsDefault:         req.IsDefault,
+		PercentAdjustment: req.PercentAdjustment,
+	}
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		entries, err := s.preparePriceListEntries(tctx, actor, biz, req.Entries)
+		if err != nil {
+			return err
+		}
+		if pl.IsDefault {
+			if err := s.clearDefaultPriceList(tctx, biz); err != nil {
+				return err
+			}
+		}
+		if err := s.storage.priceLists.CreateOne(tctx, pl); err != nil {
+			if database.IsUniqueViolation(err) {
+				return ErrPriceListNameTaken(name, err)
+			}
+			return err
+		}
+		for _, e := range entries {
+			e.PriceListID = pl.ID
+		}
+		if len(entries) > 0 {
+			if err := s.storage.priceListEntries.CreateMany(tctx, entries); err != nil {
+				return err
+			}
+		}
+		pl.Entries = entries
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pl, nil
+}
+
+func (s *Service) UpdatePriceList(ctx context.Context, actor *account.User, biz *business.Business, id string, req *UpdatePriceListRequest) (*PriceList, error) {
+	var pl *PriceList
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		existing, err := s.storage.priceLists.FindOne(tctx,
+			s.storage.priceLists.ScopeBusinessID(biz.ID),
+			s.storage.priceLists.ScopeID(id),
+		)
+		if err != nil {
+			return err
+		}
+		if req.Name != nil {
+			existing.Name = strings.TrimSpace(*req.Name)
+		}
+		if req.Description != nil {
+			existing.Description = strings.TrimSpace(*req.Description)
+		}
+		if req.PercentAdjustment.Valid {
+			existing.PercentAdjustment = req.PercentAdjustment.Decimal
+		}
+		if req.IsDefault != nil {
+			if *req.IsDefault && !existing.IsDefault {
+				if err := s.clearDefaultPriceList(tctx, biz); err != nil {
+					return err
+				}
+			}
+			existing.IsDefault = *req.IsDefault
+		}
+		if req.Entries != nil {
+			entries, err := s.preparePriceListEntries(tctx, actor, biz, req.Entries)
+			if err != nil {
+				return err
+			}
+			if err := s.storage.priceListEntries.DeleteMany(tctx,
+				s.storage.priceListEntries.ScopeEquals(PriceListEntrySchema.PriceListID, existing.ID),
+			); err != nil {
+				return err
+			}
+			for _, e := range entries {
+				e.PriceListID = existing.ID
+			}
+			if len(entries) > 0 {
+				if err := s.storage.priceListEntries.CreateMany(tctx, entries); err != nil {
+					return err
+				}
+			}
+		}
+		if err := s.storage.priceLists.UpdateOne(tctx, existing); err != nil {
+			if database.IsUniqueViolation(err) {
+				return ErrPriceListNameTaken(existing.Name, err)
+			}
+			return err
+		}
+		pl = existing
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPriceListByID(ctx, actor, biz, pl.ID)
+}
+
+// DeletePriceList deletes a price list and its entries. Customers still assigned to it are
+// priced from the default list from then on.
+func (s *Service) DeletePriceList(ctx context.Context, actor *account.User, biz *business.Business, id string) error {
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		pl, err := s.storage.priceLists.FindOne(tctx,
+			s.storage.priceLists.ScopeBusinessID(biz.ID),
+			s.storage.priceLists.ScopeID(id),
+		)
+		if err != nil {
+			return err
+		}
+		if err := s.storage.priceListEntries.DeleteMany(tctx,
+			s.storage.priceListEntries.ScopeEquals(PriceListEntrySchema.PriceListID, pl.ID),
+		); err != nil {
+			return err
+		}
+		return s.storage.priceLists.DeleteOne(tctx, pl)
+	})
+}
+
+func (s *Service) clearDefaultPriceList(ctx context.Context, biz *business.Business) error {
+	return s.storage.db.Conn(ctx).
+		Model(&PriceList{}).
+		Where("business_id = ? AND is_default = ?", biz.ID, true).
+		Update(PriceListSchema.IsDefault.Column(), false).Error
+}
+
+// preparePriceListEntries validates that every variant belongs to the business and appears once,
+// and that its quantity breaks lower the price as the quantity grows.
+func (s *Service) preparePriceListEntries(ctx context.Context, actor *account.User, biz *business.Business, reqEntries []PriceListEntryRequest) ([]*PriceListEntry, error) {
+	entries := make([]*PriceListEntry, 0, len(reqEntries))
+	seen := make(map[string]bool, len(reqEntries))
+	for _, re := range reqEntries {
+		variantID := strings.TrimSpace(re.VariantID)
+		if seen[variantID] {
+			return nil, ErrDuplicatePriceListEntry(variantID)
+		}
+		seen[variantID] = true
+		if _, err := s.GetVariantByID(ctx, actor, biz, variantID); err != nil {
+			if database.IsRecordNotFound(err) {
+				return nil, ErrVariantNotFound(err).With("variantId", variantID)
+			}
+			return nil, err
+		}
+		price := money.Round(re.Price, biz.Currency)
+		breaks, err := newPriceBreaks(variantID, price, re.QuantityBreaks, biz.Currency)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &PriceListEntry{
+			VariantID:      variantID,
+			Price:          price,
+			QuantityBreaks: breaks,
+		})
+	}
+	return entries, nil
+}
+
+// newPriceBreaks orders quantity breaks by quantity and checks each is priced below the
+// previous one, starting from the entry's price.
+func newPriceBreaks(variantID string, price decimal.Decimal, reqBreaks []PriceBreakRequest, currency string) (PriceBreaks, error) {
+	breaks := make(PriceBreaks, len(reqBreaks))
+	for i, rb := range reqBreaks {
+		breaks[i] = PriceBreak{MinQuantity: rb.MinQuantity, Price: money.Round(rb.Price, currency)}
+	}
+	sort.Slice(breaks, func(i, j int) bool { return breaks[i].MinQuantity < breaks[j].MinQuantity })
+	prevQty, prevPrice := 1, price
+	for _, b := range breaks {
+		if b.MinQuantity <= prevQty || !b.Price.LessThan(prevPrice) {
+			return nil, ErrInvalidPriceBreaks(variantID)
+		}
+		prevQty, prevPrice = b.MinQuantity, b.Price
+	}
+	return breaks, nil
+}
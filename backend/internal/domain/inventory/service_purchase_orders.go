@@ -0,0 +1,401 @@
+package inventory
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/atomic"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+func (s *Service) ListSuppliers(ctx context.Context, actor *account.User, biz *business.Business) ([]*Supplier, error) {
+	return s.storage.suppliers.FindMany(ctx,
+		s.storage.suppliers.ScopeBusinessID(biz.ID),
+		s.storage.suppliers.WithOrderBy([]string{SupplierSchema.Name.Column()}),
+	)
+}
+
+func (s *Service) GetSupplierByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Supplier, error) {
+	return s.storage.suppliers.FindOne(ctx,
+		s.storage.suppliers.ScopeBusinessID(biz.ID),
+		s.storage.suppliers.ScopeID(id),
+	)
+}
+
+func (s *Service) CreateSupplier(ctx context.Context, actor *account.User, biz *business.Business, req *CreateSupplierRequest) (*Supplier, error) {
+	sup := &Supplier{
+		BusinessID:  biz.ID,
+		Name:        strings.TrimSpace(req.Name),
+		ContactName: strings.TrimSpace(req.ContactName),
+		Email:       strings.TrimSpace(req.Email),
+		Phone:       strings.TrimSpace(req.Phone),
+		Notes:       strings.TrimSpace(req.Notes),
+	}
+	if err := s.storage.suppliers.CreateOne(ctx, sup); err != nil {
+		return nil, err
+	}
+	return sup, nil
+}
+
+func (s *Service) UpdateSupplier(ctx context.Context, actor *account.User, biz *business.Business, id string, req *UpdateSupplierRequest) (*Supplier, error) {
+	sup, err := s.GetSupplierByID(ctx, actor, biz, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		sup.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.ContactName != nil {
+		sup.ContactName = strings.TrimSpace(*req.ContactName)
+	}
+	if req.Email != nil {
+		sup.Email = strings.TrimSpace(*req.Email)
+	}
+	if req.Phone != nil {
+		sup.Phone = strings.TrimSpace(*req.Phone)
+	}
+	if req.Notes != nil {
+		sup.Notes = strings.TrimSpace(*req.Notes)
+	}
+	if err := s.storage.suppliers.UpdateOne(ctx, sup); err != nil {
+		return nil, err
+	}
+	return sup, nil
+}
+
+// DeleteSupplier deletes a supplier with no goods still expected and nothing owed to it.
+// Its past purchase orders and bills are kept.
+func (s *Service) DeleteSupplier(ctx context.Context, actor *account.User, biz *business.Business, id string) error {
+	sup, err := s.GetSupplierByID(ctx, actor, biz, id)
+	if err != nil {
+		return err
+	}
+	open, err := s.storage.purchaseOrders.Count(ctx,
+		s.storage.purchaseOrders.ScopeBusinessID(biz.ID),
+		s.storage.purchaseOrders.ScopeEquals(PurchaseOrderSchema.SupplierID, sup.ID),
+		s.storage.purchaseOrders.ScopeWhere("(status IN ? OR (status <> ? AND received_total > amount_paid))",
+			[]PurchaseOrderStatus{PurchaseOrderStatusOrdered, PurchaseOrderStatusPartiallyReceived},
+			PurchaseOrderStatusCancelled,
+		),
+	)
+	if err != nil {
+		return err
+	}
+	if open > 0 {
+		return ErrSupplierHasOpenPurchaseOrders(sup.ID)
+	}
+	unpaid, err := s.storage.supplierBills.Count(ctx,
+		s.storage.supplierBills.ScopeBusinessID(biz.ID),
+		s.storage.supplierBills.ScopeEquals(SupplierBillSchema.SupplierID, sup.ID),
+		s.storage.supplierBills.ScopeWhere("amount > amount_paid"),
+	)
+	if err != nil {
+		return err
+	}
+	if unpaid > 0 {
+		return ErrSupplierHasUnpaidBills(sup.ID)
+	}
+	return s.storage.suppliers.DeleteOne(ctx, sup)
+}
+
+// SumSupplierPayables returns, per supplier ID, what the business owes on unpaid bills and for
+// received and unpaid goods of purchase orders not billed yet.
+func (s *Service) SumSupplierPayables(ctx context.Context, actor *account.User, biz *business.Business) (map[string]decimal.Decimal, error) {
+	return s.storage.SumSupplierPayables(ctx, biz.ID)
+}
+
+// SumSupplierLiabilities returns the total the business owes its suppliers on unpaid bills and
+// for received and unpaid goods not billed yet. It is the liability side of the financial
+// position.
+func (s *Service) SumSupplierLiabilities(ctx context.Context, actor *account.User, biz *business.Business) (decimal.Decimal, error) {
+	payables, err := s.storage.SumSupplierPayables(ctx, biz.ID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	total := decimal.Zero
+	for _, p := range payables {
+		total = total.Add(p)
+	}
+	return total, nil
+}
+
+type ListPurchaseOrdersFilters struct {
+	SupplierID string
+	Status     PurchaseOrderStatus
+}
+
+func (s *Service) ListPurchaseOrders(ctx context.Context, actor *account.User, biz *business.Business, req *list.ListRequest, filters *ListPurchaseOrdersFilters) ([]*PurchaseOrder, int64, error) {
+	scopes := []func(db *gorm.DB) *gorm.DB{
+		s.storage.purchaseOrders.ScopeBusinessID(biz.ID),
+	}
+	if filters != nil && filters.SupplierID != "" {
+		scopes = append(scopes, s.storage.purchaseOrders.ScopeEquals(PurchaseOrderSchema.SupplierID, filters.SupplierID))
+	}
+	if filters != nil && filters.Status != "" {
+		scopes = append(scopes, s.storage.purchaseOrders.ScopeEquals(PurchaseOrderSchema.Status, filters.Status))
+	}
+	items, err := s.storage.purchaseOrders.FindMany(ctx,
+		append(scopes,
+			s.storage.purchaseOrders.WithPreload(PurchaseOrderSupplierField),
+			s.storage.purchaseOrders.WithPagination(req.Offset(), req.Limit()),
+			s.storage.purchaseOrders.WithOrderBy(req.ParsedOrderByWithDefault(PurchaseOrderSchema, []string{"-orderedAt"})),
+		)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.storage.purchaseOrders.Count(ctx, scopes...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (s *Service) GetPurchaseOrderByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*PurchaseOrder, error) {
+	return s.storage.purchaseOrders.FindOne(ctx,
+		s.storage.purchaseOrders.ScopeBusinessID(biz.ID),
+		s.storage.purchaseOrders.ScopeID(id),
+		s.storage.purchaseOrders.WithPreload(PurchaseOrderSupplierField, PurchaseOrderItemsStruct),
+	)
+}
+
+// ListPurchaseOrderReceipts returns the goods received against a purchase order, oldest first.
+func (s *Service) ListPurchaseOrderReceipts(ctx context.Context, actor *account.User, biz *business.Business, po *PurchaseOrder) ([]*PurchaseOrderReceipt, error) {
+	return s.storage.purchaseOrderReceipts.FindMany(ctx,
+		s.storage.purchaseOrderReceipts.ScopeBusinessID(biz.ID),
+		s.storage.purchaseOrderReceipts.ScopeEquals(PurchaseOrderReceiptSchema.PurchaseOrderID, po.ID),
+		s.storage.purchaseOrderReceipts.WithOrderBy([]string{"received_at ASC", "created_at ASC"}),
+	)
+}
+
+// CreatePurchaseOrder places an order with a supplier. Stock is only added when the goods are
+// received.
+func (s *Service) CreatePurchaseOrder(ctx context.Context, actor *account.User, biz *business.Business, req *CreatePurchaseOrderRequest) (*PurchaseOrder, error) {
+	sup, err := s.GetSupplierByID(ctx, actor, biz, req.SupplierID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrSupplierNotFound(err).With("supplierId", req.SupplierID)
+		}
+		return nil, err
+	}
+	po := &PurchaseOrder{
+		BusinessID: biz.ID,
+		SupplierID: sup.ID,
+		Reference:  strings.TrimSpace(req.Reference),
+		Status:     PurchaseOrderStatusOrdered,
+		Currency:   biz.Currency,
+		ExpectedAt: req.ExpectedAt,
+		OrderedAt:  time.Now().UTC(),
+		Notes:      strings.TrimSpace(req.Notes),
+	}
+	seen := make(map[string]bool, len(req.Items))
+	for _, ri := range req.Items {
+		variantID := strings.TrimSpace(ri.VariantID)
+		if seen[variantID] {
+			return nil, ErrDuplicatePurchaseOrderItem("variantId", variantID)
+		}
+		seen[variantID] = true
+		if _, err := s.GetVariantByID(ctx, actor, biz, variantID); err != nil {
+			if database.IsRecordNotFound(err) {
+				return nil, ErrVariantNotFound(err).With("variantId", variantID)
+			}
+			return nil, err
+		}
+		unitCost := money.Round(ri.UnitCost, biz.Currency)
+		item := &PurchaseOrderItem{
+			VariantID: variantID,
+			Quantity:  ri.Quantity,
+			UnitCost:  unitCost,
+			Total:     unitCost.Mul(decimal.NewFromInt(int64(ri.Quantity))),
+		}
+		po.Items = append(po.Items, item)
+		po.Total = po.Total.Add(item.Total)
+	}
+	if err := s.storage.purchaseOrders.CreateOne(ctx, po); err != nil {
+		return nil, err
+	}
+	po.Supplier = sup
+	return po, nil
+}
+
+func (s *Service) UpdatePurchaseOrder(ctx context.Context, actor *account.User, biz *business.Business, id string, req *UpdatePurchaseOrderRequest) (*PurchaseOrder, error) {
+	po, err := s.storage.purchaseOrders.FindOne(ctx,
+		s.storage.purchaseOrders.ScopeBusinessID(biz.ID),
+		s.storage.purchaseOrders.ScopeID(id),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if req.Reference != nil {
+		po.Reference = strings.TrimSpace(*req.Reference)
+	}
+	if req.ExpectedAt != nil {
+		po.ExpectedAt = req.ExpectedAt
+	}
+	if req.Notes != nil {
+		po.Notes = strings.TrimSpace(*req.Notes)
+	}
+	if err := s.storage.purchaseOrders.UpdateOne(ctx, po); err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrderByID(ctx, actor, biz, po.ID)
+}
+
+// ReceivePurchaseOrder records goods that arrived against a purchase order and adds them to
+// stock in the same transaction, in cost layers at the ordered unit costs. Items can arrive
+// over several receipts; the order is received once every ordered unit arrived. Received goods
+// are owed to the supplier until paid.
+func (s *Service) ReceivePurchaseOrder(ctx context.Context, actor *account.User, biz *business.Business, id string, req *ReceivePurchaseOrderRequest) (*PurchaseOrder, error) {
+	receivedAt := time.Now().UTC()
+	if req.ReceivedAt != nil {
+		receivedAt = req.ReceivedAt.UTC()
+	}
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		po, err := s.storage.purchaseOrders.FindOne(tctx,
+			s.storage.purchaseOrders.ScopeBusinessID(biz.ID),
+			s.storage.purchaseOrders.ScopeID(id),
+			s.storage.purchaseOrders.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return err
+		}
+		if !po.Status.receivable() {
+			return ErrPurchaseOrderNotReceivable(po.ID, po.Status)
+		}
+		items, err := s.storage.purchaseOrderItems.FindMany(tctx,
+			s.storage.purchaseOrderItems.ScopeEquals(PurchaseOrderItemSchema.PurchaseOrderID, po.ID),
+		)
+		if err != nil {
+			return err
+		}
+		byID := make(map[string]*PurchaseOrderItem, len(items))
+		for _, item := range items {
+			byID[item.ID] = item
+		}
+
+		incoming := make(map[string]StockReceipt, len(req.Items))
+		receipts := make([]*PurchaseOrderReceipt, 0, len(req.Items))
+		changed := make([]*PurchaseOrderItem, 0, len(req.Items))
+		for _, ri := range req.Items {
+			item, ok := byID[ri.ItemID]
+			if !ok {
+				return ErrPurchaseOrderItemNotFound(ri.ItemID)
+			}
+			if _, dup := incoming[item.VariantID]; dup {
+				return ErrDuplicatePurchaseOrderItem("itemId", item.ID)
+			}
+			if ri.Quantity > item.remaining() {
+				return ErrPurchaseOrderOverReceipt(item.ID, item.remaining(), ri.Quantity)
+			}
+			item.ReceivedQuantity += ri.Quantity
+			incoming[item.VariantID] = StockReceipt{Quantity: ri.Quantity, UnitCost: item.UnitCost}
+			changed = append(changed, item)
+			receipts = append(receipts, &PurchaseOrderReceipt{
+				BusinessID:      biz.ID,
+				PurchaseOrderID: po.ID,
+				ItemID:          item.ID,
+				VariantID:       item.VariantID,
+				Quantity:        ri.Quantity,
+				UnitCost:        item.UnitCost,
+				ReceivedByID:    actor.ID,
+				ReceivedAt:      receivedAt,
+			})
+			po.ReceivedTotal = po.ReceivedTotal.Add(item.UnitCost.Mul(decimal.NewFromInt(int64(ri.Quantity))))
+		}
+
+		if err := s.ReceiveStock(tctx, actor, biz, "", CostLayerSourcePurchaseOrder, po.ID, incoming); err != nil {
+			return err
+		}
+		if err := s.storage.purchaseOrderItems.UpdateMany(tctx, changed); err != nil {
+			return err
+		}
+		if err := s.storage.purchaseOrderReceipts.CreateMany(tctx, receipts); err != nil {
+			return err
+		}
+		po.Status = PurchaseOrderStatusReceived
+		po.ReceivedAt = &receivedAt
+		for _, item := range items {
+			if item.remaining() > 0 {
+				po.Status = PurchaseOrderStatusPartiallyReceived
+				po.ReceivedAt = nil
+				break
+			}
+		}
+		return s.storage.purchaseOrders.UpdateOne(tctx, po)
+	}, atomic.WithIsolationLevel(atomic.LevelSerializable), atomic.WithRetries(3))
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrderByID(ctx, actor, biz, id)
+}
+
+// RecordPurchaseOrderPayment records a payment to the supplier of a purchase order. Payments
+// can precede the goods but never exceed the order total. A billed purchase order is paid
+// through its bills.
+func (s *Service) RecordPurchaseOrderPayment(ctx context.Context, actor *account.User, biz *business.Business, id string, req *PurchaseOrderPaymentRequest) (*PurchaseOrder, error) {
+	amount := money.Round(req.Amount, biz.Currency)
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		po, err := s.storage.purchaseOrders.FindOne(tctx,
+			s.storage.purchaseOrders.ScopeBusinessID(biz.ID),
+			s.storage.purchaseOrders.ScopeID(id),
+			s.storage.purchaseOrders.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return err
+		}
+		if po.Status == PurchaseOrderStatusCancelled {
+			return ErrPurchaseOrderCancelled(po.ID)
+		}
+		billed, err := s.storage.supplierBills.Count(tctx,
+			s.storage.supplierBills.ScopeBusinessID(biz.ID),
+			s.storage.supplierBills.ScopeEquals(SupplierBillSchema.PurchaseOrderID, po.ID),
+		)
+		if err != nil {
+			return err
+		}
+		if billed > 0 {
+			return ErrPurchaseOrderBilled(po.ID)
+		}
+		if unpaid := po.Total.Sub(po.AmountPaid); amount.GreaterThan(unpaid) {
+			return ErrPurchaseOrderOverpayment(po.ID, unpaid, amount)
+		}
+		po.AmountPaid = po.AmountPaid.Add(amount)
+		return s.storage.purchaseOrders.UpdateOne(tctx, po)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrderByID(ctx, actor, biz, id)
+}
+
+// CancelPurchaseOrder calls off a purchase order nothing was received against.
+func (s *Service) CancelPurchaseOrder(ctx context.Context, actor *account.User, biz *business.Business, id string) (*PurchaseOrder, error) {
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		po, err := s.storage.purchaseOrders.FindOne(tctx,
+			s.storage.purchaseOrders.ScopeBusinessID(biz.ID),
+			s.storage.purchaseOrders.ScopeID(id),
+			s.storage.purchaseOrders.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return err
+		}
+		if po.Status != PurchaseOrderStatusOrdered {
+			return ErrPurchaseOrderNotCancellable(po.ID, po.Status)
+		}
+		now := time.Now().UTC()
+		po.Status = PurchaseOrderStatusCancelled
+		po.CancelledAt = &now
+		return s.storage.purchaseOrders.UpdateOne(tctx, po)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrderByID(ctx, actor, biz, id)
+}
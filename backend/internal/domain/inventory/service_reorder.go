@@ -0,0 +1,174 @@
+package inventory
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"gorm.io/gorm"
+)
+
+// applyReorderSettings sets the variant's preferred supplier and reorder parameters from the
+// fields present in settings. The supplier must belong to the business.
+func (s *Service) applyReorderSettings(ctx context.Context, actor *account.User, biz *business.Business, variant *Variant, settings *ReorderSettings) error {
+	if settings.PreferredSupplierID != nil {
+		supplierID := strings.TrimSpace(*settings.PreferredSupplierID)
+		if supplierID != "" {
+			if _, err := s.GetSupplierByID(ctx, actor, biz, supplierID); err != nil {
+				if database.IsRecordNotFound(err) {
+					return ErrSupplierNotFound(err).With("supplierId", supplierID)
+				}
+				return err
+			}
+		}
+		variant.PreferredSupplierID = supplierID
+	}
+	if settings.LeadTimeDays != nil {
+		variant.LeadTimeDays = *settings.LeadTimeDays
+	}
+	if settings.ReorderPoint != nil {
+		variant.ReorderPoint = *settings.ReorderPoint
+	}
+	if settings.ReorderQuantity != nil {
+		variant.ReorderQuantity = *settings.ReorderQuantity
+	}
+	return nil
+}
+
+// ReorderSuggestionsOptions tunes how reorder quantities are computed.
+type ReorderSuggestionsOptions struct {
+	// WindowDays is the sales history used to measure velocity.
+	WindowDays int
+	// LeadTimeDays is how long the supplier takes to deliver, for variants without their own.
+	LeadTimeDays int
+	// CoverDays is how long the delivered stock should last.
+	CoverDays int
+	// SupplierID keeps the variants bought from one preferred supplier.
+	SupplierID string
+	// RunsOutOnly keeps the variants that sell out before a reorder placed today arrives.
+	RunsOutOnly bool
+	Limit       int
+}
+
+// ListReorderSuggestions ranks the variants that need repurchasing: those that sold in the
+// window or sit at or below their stock alert or reorder point, and whose stock does not cover
+// their lead time plus cover period on top of the reorder point. Quantities are rounded up to
+// whole reorder lots. The variants closest to running out come first.
+func (s *Service) ListReorderSuggestions(ctx context.Context, actor *account.User, biz *business.Business, opts *ReorderSuggestionsOptions) ([]*ReorderSuggestion, error) {
+	since := time.Now().UTC().AddDate(0, 0, -opts.WindowDays)
+	sold, err := s.storage.SumSoldQuantities(ctx, biz.ID, since)
+	if err != nil {
+		return nil, err
+	}
+	soldIDs := make([]string, 0, len(sold))
+	for id := range sold {
+		soldIDs = append(soldIDs, id)
+	}
+	candidates := s.storage.variants.ScopeWhere("(variants.stock_quantity <= variants.stock_alert OR variants.stock_quantity <= variants.reorder_point)")
+	if len(soldIDs) > 0 {
+		candidates = s.storage.variants.ScopeWhere("(variants.stock_quantity <= variants.stock_alert OR variants.stock_quantity <= variants.reorder_point OR variants.id IN ?)", soldIDs)
+	}
+	scopes := []func(db *gorm.DB) *gorm.DB{
+		s.storage.variants.ScopeWhere("variants.business_id = ?", biz.ID),
+		candidates,
+		s.storage.ScopeStockTrackedVariants(),
+	}
+	if opts.SupplierID != "" {
+		scopes = append(scopes, s.storage.variants.ScopeWhere("variants.preferred_supplier_id = ?", opts.SupplierID))
+	}
+	variants, err := s.storage.variants.FindMany(ctx, append(scopes, s.storage.variants.WithPreload(ProductStruct))...)
+	if err != nil {
+		return nil, err
+	}
+	suppliers, err := s.preferredSuppliers(ctx, biz, variants)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]*ReorderSuggestion, 0, len(variants))
+	for _, v := range variants {
+		stock := max(v.StockQuantity, 0)
+		sg := &ReorderSuggestion{
+			Variant:      v,
+			Supplier:     suppliers[v.PreferredSupplierID],
+			UnitsSold:    sold[v.ID],
+			LeadTimeDays: opts.LeadTimeDays,
+		}
+		if v.LeadTimeDays > 0 {
+			sg.LeadTimeDays = v.LeadTimeDays
+		}
+		sg.DailyVelocity = float64(sg.UnitsSold) / float64(opts.WindowDays)
+		if sg.DailyVelocity > 0 {
+			days := float64(stock) / sg.DailyVelocity
+			sg.DaysOfStock = &days
+		}
+		if opts.RunsOutOnly && !sg.RunsOutWithinLeadTime() {
+			continue
+		}
+		sg.ProjectedStock = stock - int(math.Ceil(sg.DailyVelocity*float64(sg.LeadTimeDays)))
+		reorderPoint := v.ReorderPoint
+		if reorderPoint == 0 {
+			reorderPoint = v.StockQuantityAlert
+		}
+		target := int(math.Ceil(sg.DailyVelocity*float64(sg.LeadTimeDays+opts.CoverDays))) + reorderPoint
+		sg.SuggestedQuantity = target - stock
+		if sg.SuggestedQuantity <= 0 {
+			continue
+		}
+		if lot := v.ReorderQuantity; lot > 0 {
+			sg.SuggestedQuantity = (sg.SuggestedQuantity + lot - 1) / lot * lot
+		}
+		suggestions = append(suggestions, sg)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		switch {
+		case a.DaysOfStock != nil && b.DaysOfStock != nil && *a.DaysOfStock != *b.DaysOfStock:
+			return *a.DaysOfStock < *b.DaysOfStock
+		case (a.DaysOfStock == nil) != (b.DaysOfStock == nil):
+			return a.DaysOfStock != nil
+		case a.DailyVelocity != b.DailyVelocity:
+			return a.DailyVelocity > b.DailyVelocity
+		default:
+			return a.Variant.ID < b.Variant.ID
+		}
+	})
+	if opts.Limit > 0 && len(suggestions) > opts.Limit {
+		suggestions = suggestions[:opts.Limit]
+	}
+	return suggestions, nil
+}
+
+// preferredSuppliers loads the preferred suppliers of the variants by ID. Suppliers deleted
+// since they were linked are absent.
+func (s *Service) preferredSuppliers(ctx context.Context, biz *business.Business, variants []*Variant) (map[string]*Supplier, error) {
+	ids := []any{}
+	seen := map[string]bool{}
+	for _, v := range variants {
+		if v.PreferredSupplierID != "" && !seen[v.PreferredSupplierID] {
+			seen[v.PreferredSupplierID] = true
+			ids = append(ids, v.PreferredSupplierID)
+		}
+	}
+	suppliers := make(map[string]*Supplier, len(ids))
+	if len(ids) == 0 {
+		return suppliers, nil
+	}
+	found, err := s.storage.suppliers.FindMany(ctx,
+		s.storage.suppliers.ScopeBusinessID(biz.ID),
+		s.storage.suppliers.ScopeIDs(ids),
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, sup := range found {
+		suppliers[sup.ID] = sup
+	}
+	return suppliers, nil
+}
@@ -0,0 +1,92 @@
+package inventory
+
+import (
+	"context"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/spf13/viper"
+)
+
+// AllSearchResultTypes lists what inventory search returns when no type is asked for.
+var AllSearchResultTypes = []SearchResultType{SearchResultTypeProduct, SearchResultTypeVariant}
+
+// searchRanking returns the configured weights of the inventory search score.
+func searchRanking() SearchRanking {
+	return SearchRanking{
+		NameWeight: viper.GetFloat64(config.InventorySearchNameWeight),
+		SKUWeight:  viper.GetFloat64(config.InventorySearchSKUWeight),
+		MinScore:   viper.GetFloat64(config.InventorySearchMinScore),
+	}
+}
+
+// SearchInventory returns the products and variants of the business matching term, ranked
+// together, at most limit. Misspelled names and partial or mistyped SKUs still match; the
+// ranking weights come from configuration. Empty types means both.
+func (s *Service) SearchInventory(ctx context.Context, actor *account.User, biz *business.Business, term string, types []SearchResultType, limit int) ([]*SearchResult, error) {
+	if len(types) == 0 {
+		types = AllSearchResultTypes
+	}
+	hits, err := s.storage.SearchCatalog(ctx, biz.ID, term, types, searchRanking(), limit)
+	if err != nil {
+		return nil, err
+	}
+	var productIDs, variantIDs []any
+	for _, h := range hits {
+		if h.Type == SearchResultTypeProduct {
+			productIDs = append(productIDs, h.ID)
+		} else {
+			variantIDs = append(variantIDs, h.ID)
+		}
+	}
+
+	products := map[string]*Product{}
+	if len(productIDs) > 0 {
+		found, err := s.storage.products.FindMany(ctx,
+			s.storage.products.ScopeBusinessID(biz.ID),
+			s.storage.products.ScopeIDs(productIDs),
+			s.storage.products.WithPreload(ProductVariantsStruct),
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range found {
+			products[p.ID] = p
+		}
+	}
+	variants := map[string]*Variant{}
+	if len(variantIDs) > 0 {
+		found, err := s.storage.variants.FindMany(ctx,
+			s.storage.variants.ScopeBusinessID(biz.ID),
+			s.storage.variants.ScopeIDs(variantIDs),
+			s.storage.variants.WithPreload(ProductStruct),
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range found {
+			variants[v.ID] = v
+		}
+	}
+
+	results := make([]*SearchResult, 0, len(hits))
+	for _, h := range hits {
+		r := &SearchResult{Type: h.Type, Score: h.Score}
+		switch h.Type {
+		case SearchResultTypeProduct:
+			r.Product = products[h.ID]
+		case SearchResultTypeVariant:
+			r.Variant = variants[h.ID]
+			if r.Variant != nil {
+				r.Product = r.Variant.Product
+			}
+		}
+		if r.Product == nil && r.Variant == nil {
+			// deleted between the search and the load
+			continue
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
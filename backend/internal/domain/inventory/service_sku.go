@@ -0,0 +1,131 @@
+package inventory
+
+import (
+	"context"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+)
+
+// maxSKUAttempts bounds how many sequence numbers are skipped over SKUs already in use, e.g.
+// ones entered by hand that match the pattern.
+const maxSKUAttempts = 20
+
+// generateSKU returns the SKU of a variant created without one. With the business SKU pattern
+// enabled it is the next free number of the pattern's sequence, otherwise a random
+// CreateProductSKU.
+func (s *Service) generateSKU(ctx context.Context, biz *business.Business, product *Product, variantCode string) (string, error) {
+	pattern := biz.SKUPattern
+	if !pattern.Enabled() {
+		return CreateProductSKU(biz.Descriptor, product.Name, variantCode), nil
+	}
+	stem, err := s.skuStem(ctx, biz, pattern, product.CategoryID)
+	if err != nil {
+		return "", err
+	}
+	var sku string
+	for range maxSKUAttempts {
+		n, err := s.storage.NextSKUNumber(ctx, biz.ID, stem)
+		if err != nil {
+			return "", err
+		}
+		sku = pattern.Format(stem, n)
+		taken, err := s.skuTaken(ctx, biz, sku)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return sku, nil
+		}
+	}
+	return "", ErrVariantSKUTaken(sku, nil)
+}
+
+// skuStem returns the pattern's stem for a product of the category.
+func (s *Service) skuStem(ctx context.Context, biz *business.Business, pattern business.SKUPattern, categoryID string) (string, error) {
+	if !pattern.IncludeCategory || categoryID == "" {
+		return pattern.Stem(""), nil
+	}
+	category, err := s.storage.categories.FindOne(ctx,
+		s.storage.categories.ScopeBusinessID(biz.ID),
+		s.storage.categories.ScopeID(categoryID),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return "", ErrCategoryNotFound(err).With("categoryId", categoryID)
+		}
+		return "", err
+	}
+	return pattern.Stem(categoryCode(category)), nil
+}
+
+// categoryCode is the three-letter code of a category in generated SKUs.
+func categoryCode(category *Category) string {
+	return id.NewCodeFromString(category.Name, 3)
+}
+
+// skuTaken reports whether a variant of the business uses the SKU.
+func (s *Service) skuTaken(ctx context.Context, biz *business.Business, sku string) (bool, error) {
+	n, err := s.storage.variants.Count(ctx,
+		s.storage.variants.ScopeBusinessID(biz.ID),
+		s.storage.variants.ScopeEquals(VariantSchema.SKU, sku),
+	)
+	return n > 0, err
+}
+
+// PreviewSKUs returns the next SKUs the business SKU pattern would generate for a product of
+// the category, without taking them. Pattern fields set on the request override the saved
+// pattern so a new pattern can be tried before saving it. Concurrent creates may take the
+// previewed numbers first.
+func (s *Service) PreviewSKUs(ctx context.Context, actor *account.User, biz *business.Business, req *SKUPreviewRequest) (*SKUPreviewResponse, error) {
+	pattern := biz.SKUPattern
+	if req.Prefix != nil {
+		pattern.Prefix = *req.Prefix
+	}
+	if req.IncludeCategory != nil {
+		pattern.IncludeCategory = *req.IncludeCategory
+	}
+	if req.SequenceDigits != nil {
+		pattern.SequenceDigits = *req.SequenceDigits
+	}
+	pattern, err := pattern.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	if !pattern.Enabled() {
+		return nil, ErrSKUPatternNotSet()
+	}
+	count := req.Count
+	if count == 0 {
+		count = 1
+	}
+	stem, err := s.skuStem(ctx, biz, pattern, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	last := 0
+	seq, err := s.storage.skuSequences.FindOne(ctx,
+		s.storage.skuSequences.ScopeBusinessID(biz.ID),
+		s.storage.skuSequences.ScopeWhere("stem = ?", stem),
+	)
+	if err != nil && !database.IsRecordNotFound(err) {
+		return nil, err
+	}
+	if seq != nil {
+		last = seq.LastValue
+	}
+	skus := make([]string, 0, count)
+	for n := last + 1; len(skus) < count && n <= last+count+maxSKUAttempts; n++ {
+		sku := pattern.Format(stem, n)
+		taken, err := s.skuTaken(ctx, biz, sku)
+		if err != nil {
+			return nil, err
+		}
+		if !taken {
+			skus = append(skus, sku)
+		}
+	}
+	return &SKUPreviewResponse{Pattern: pattern, SKUs: skus}, nil
+}
@@ -0,0 +1,408 @@
+package inventory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/atomic"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"gorm.io/gorm"
+)
+
+// stocktakeLineBatchSize bounds the lines inserted per statement when a stocktake starts.
+const stocktakeLineBatchSize = 500
+
+type ListStocktakesFilters struct {
+	LocationID string
+	Status     StocktakeStatus
+}
+
+func (s *Service) ListStocktakes(ctx context.Context, actor *account.User, biz *business.Business, req *list.ListRequest, filters *ListStocktakesFilters) ([]*Stocktake, int64, error) {
+	scopes := []func(db *gorm.DB) *gorm.DB{
+		s.storage.stocktakes.ScopeBusinessID(biz.ID),
+	}
+	if filters != nil && filters.LocationID != "" {
+		scopes = append(scopes, s.storage.stocktakes.ScopeEquals(StocktakeSchema.LocationID, filters.LocationID))
+	}
+	if filters != nil && filters.Status != "" {
+		scopes = append(scopes, s.storage.stocktakes.ScopeEquals(StocktakeSchema.Status, filters.Status))
+	}
+	items, err := s.storage.stocktakes.FindMany(ctx,
+		append(scopes,
+			s.storage.stocktakes.WithPagination(req.Offset(), req.Limit()),
+			s.storage.stocktakes.WithOrderBy(req.ParsedOrderByWithDefault(StocktakeSchema, []string{"-startedAt"})),
+		)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.storage.stocktakes.Count(ctx, scopes...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// GetStocktakeByID returns a stocktake with its lines ordered by variant name.
+func (s *Service) GetStocktakeByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Stocktake, error) {
+	st, err := s.storage.stocktakes.FindOne(ctx,
+		s.storage.stocktakes.ScopeBusinessID(biz.ID),
+		s.storage.stocktakes.ScopeID(id),
+		s.storage.stocktakes.WithPreload(StocktakeLineVariants),
+	)
+	if err != nil {
+		return nil, err
+	}
+	sortStocktakeLines(st.Lines)
+	return st, nil
+}
+
+// CreateStocktake starts a stocktake and snapshots the expected quantity of every variant in
+// its scope: the stock held at the counted location, or the variant totals when the business
+// has no locations.
+func (s *Service) CreateStocktake(ctx context.Context, actor *account.User, biz *business.Business, req *CreateStocktakeRequest) (*Stocktake, error) {
+	var st *Stocktake
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		var loc *Location
+		var err error
+		if locationID := strings.TrimSpace(req.LocationID); locationID != "" {
+			loc, err = s.getLocationForStock(tctx, actor, biz, locationID)
+		} else {
+			loc, err = s.resolveLocation(tctx, biz, "")
+		}
+		if err != nil {
+			return err
+		}
+
+		scopes := []func(db *gorm.DB) *gorm.DB{
+			s.storage.variants.ScopeWhere("variants.business_id = ?", biz.ID),
+		}
+		categoryID := strings.TrimSpace(req.CategoryID)
+		if categoryID != "" {
+			if _, err := s.GetCategoryByID(tctx, actor, biz, categoryID); err != nil {
+				if database.IsRecordNotFound(err) {
+					return ErrCategoryNotFound(err).With("categoryId", categoryID)
+				}
+				return err
+			}
+			scopes = append(scopes, s.storage.variants.ScopeWhere(
+				"variants.product_id IN (SELECT id FROM products WHERE business_id = ? AND category_id = ? AND deleted_at IS NULL)",
+				biz.ID, categoryID,
+			))
+		}
+		if len(req.VariantIDs) > 0 {
+			ids := make([]any, len(req.VariantIDs))
+			for i, v := range req.VariantIDs {
+				ids[i] = strings.TrimSpace(v)
+			}
+			scopes = append(scopes, s.storage.variants.ScopeIDs(ids))
+		}
+		variants, err := s.storage.variants.FindMany(tctx, scopes...)
+		if err != nil {
+			return err
+		}
+		if len(variants) == 0 {
+			return ErrStocktakeEmpty()
+		}
+
+		expected := make(map[string]int, len(variants))
+		for _, v := range variants {
+			expected[v.ID] = v.StockQuantity
+		}
+		st = &Stocktake{
+			BusinessID:  biz.ID,
+			Name:        strings.TrimSpace(req.Name),
+			CategoryID:  categoryID,
+			Status:      StocktakeStatusCounting,
+			Notes:       strings.TrimSpace(req.Notes),
+			StartedByID: actor.ID,
+			StartedAt:   time.Now().UTC(),
+		}
+		if loc != nil {
+			st.LocationID = loc.ID
+			stocks, err := s.storage.locationStocks.FindMany(tctx,
+				s.storage.locationStocks.ScopeBusinessID(biz.ID),
+				s.storage.locationStocks.ScopeEquals(LocationStockSchema.LocationID, loc.ID),
+			)
+			if err != nil {
+				return err
+			}
+			for id := range expected {
+				expected[id] = 0
+			}
+			for _, stock := range stocks {
+				if _, ok := expected[stock.VariantID]; ok {
+					expected[stock.VariantID] = stock.Quantity
+				}
+			}
+		}
+		if err := s.storage.stocktakes.CreateOne(tctx, st); err != nil {
+			if database.IsUniqueViolation(err) {
+				return ErrStocktakeInProgress(st.LocationID, err)
+			}
+			return err
+		}
+
+		st.Lines = make([]*StocktakeLine, len(variants))
+		for i, v := range variants {
+			st.Lines[i] = &StocktakeLine{
+				BusinessID:       biz.ID,
+				StocktakeID:      st.ID,
+				VariantID:        v.ID,
+				Variant:          v,
+				ExpectedQuantity: expected[v.ID],
+				UnitCost:         v.CurrentCost(),
+			}
+		}
+		for start := 0; start < len(st.Lines); start += stocktakeLineBatchSize {
+			end := min(start+stocktakeLineBatchSize, len(st.Lines))
+			if err := s.storage.stocktakeLines.CreateMany(tctx, st.Lines[start:end]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortStocktakeLines(st.Lines)
+	return st, nil
+}
+
+// lockCountingStocktake loads a stocktake for update and checks it is still counting.
+func (s *Service) lockCountingStocktake(ctx context.Context, biz *business.Business, id string) (*Stocktake, error) {
+	st, err := s.storage.stocktakes.FindOne(ctx,
+		s.storage.stocktakes.ScopeBusinessID(biz.ID),
+		s.storage.stocktakes.ScopeID(id),
+		s.storage.stocktakes.WithLockingStrength(database.LockingStrengthUpdate),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrStocktakeNotFound(err).With("stocktakeId", id)
+		}
+		return nil, err
+	}
+	if st.Status != StocktakeStatusCounting {
+		return nil, ErrStocktakeClosed(st.ID, st.Status)
+	}
+	return st, nil
+}
+
+// RecordStocktakeCounts sets the counted quantities entered by hand, replacing earlier counts
+// of the same variants. It returns the updated lines.
+func (s *Service) RecordStocktakeCounts(ctx context.Context, actor *account.User, biz *business.Business, id string, req *RecordStocktakeCountsRequest) ([]*StocktakeLine, error) {
+	var changed []*StocktakeLine
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		st, err := s.lockCountingStocktake(tctx, biz, id)
+		if err != nil {
+			return err
+		}
+		ids := make([]any, len(req.Counts))
+		for i, c := range req.Counts {
+			ids[i] = strings.TrimSpace(c.VariantID)
+		}
+		lines, err := s.storage.stocktakeLines.FindMany(tctx,
+			s.storage.stocktakeLines.ScopeEquals(StocktakeLineSchema.StocktakeID, st.ID),
+			s.storage.stocktakeLines.ScopeWhere(StocktakeLineSchema.VariantID.Column()+" IN ?", ids),
+			s.storage.stocktakeLines.WithPreload(VariantStruct),
+		)
+		if err != nil {
+			return err
+		}
+		byVariant := make(map[string]*StocktakeLine, len(lines))
+		for _, line := range lines {
+			byVariant[line.VariantID] = line
+		}
+		now := time.Now().UTC()
+		changed = make([]*StocktakeLine, 0, len(req.Counts))
+		seen := make(map[string]bool, len(req.Counts))
+		for _, c := range req.Counts {
+			variantID := strings.TrimSpace(c.VariantID)
+			line := byVariant[variantID]
+			if line == nil {
+				return ErrVariantNotInStocktake(variantID)
+			}
+			quantity := *c.Quantity
+			line.CountedQuantity = &quantity
+			line.CountedByID = actor.ID
+			line.CountedAt = &now
+			if !seen[variantID] {
+				seen[variantID] = true
+				changed = append(changed, line)
+			}
+		}
+		return s.storage.stocktakeLines.UpdateMany(tctx, changed)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+// ScanStocktakeItem adds scanned units to the count of the variant whose barcode or SKU is the
+// scanned code and returns its line.
+func (s *Service) ScanStocktakeItem(ctx context.Context, actor *account.User, biz *business.Business, id string, req *ScanStocktakeRequest) (*StocktakeLine, error) {
+	quantity := req.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	var line *StocktakeLine
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		st, err := s.lockCountingStocktake(tctx, biz, id)
+		if err != nil {
+			return err
+		}
+		variant, err := s.LookupVariant(tctx, actor, biz, req.Code)
+		if err != nil {
+			return err
+		}
+		line, err = s.storage.stocktakeLines.FindOne(tctx,
+			s.storage.stocktakeLines.ScopeEquals(StocktakeLineSchema.StocktakeID, st.ID),
+			s.storage.stocktakeLines.ScopeEquals(StocktakeLineSchema.VariantID, variant.ID),
+		)
+		if err != nil {
+			if database.IsRecordNotFound(err) {
+				return ErrVariantNotInStocktake(variant.ID)
+			}
+			return err
+		}
+		counted := quantity
+		if line.CountedQuantity != nil {
+			counted += *line.CountedQuantity
+		}
+		now := time.Now().UTC()
+		line.CountedQuantity = &counted
+		line.CountedByID = actor.ID
+		line.CountedAt = &now
+		line.Variant = variant
+		return s.storage.stocktakeLines.UpdateOne(tctx, line)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+// GetStocktakeVarianceReport summarizes the counted lines that differ from the snapshot,
+// largest variance value first.
+func (s *Service) GetStocktakeVarianceReport(ctx context.Context, actor *account.User, biz *business.Business, id string) (*StocktakeVarianceReport, error) {
+	st, err := s.GetStocktakeByID(ctx, actor, biz, id)
+	if err != nil {
+		return nil, err
+	}
+	report := &StocktakeVarianceReport{Stocktake: st, Lines: len(st.Lines), Variances: []*StocktakeLine{}}
+	for _, line := range st.Lines {
+		if line.CountedQuantity == nil {
+			report.UncountedLines++
+			continue
+		}
+		report.CountedLines++
+		variance := line.Variance()
+		switch {
+		case variance > 0:
+			report.UnitsOver += variance
+			report.ValueOver = report.ValueOver.Add(line.VarianceValue())
+		case variance < 0:
+			report.UnitsShort -= variance
+			report.ValueShort = report.ValueShort.Sub(line.VarianceValue())
+		default:
+			continue
+		}
+		report.Variances = append(report.Variances, line)
+	}
+	sort.SliceStable(report.Variances, func(i, j int) bool {
+		return report.Variances[i].VarianceValue().Abs().GreaterThan(report.Variances[j].VarianceValue().Abs())
+	})
+	return report, nil
+}
+
+// ApproveStocktake posts the variance of every counted line to stock as a correcting
+// adjustment at the stocktake's location. Uncounted lines and variants deleted since the
+// snapshot are left alone.
+func (s *Service) ApproveStocktake(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Stocktake, error) {
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		st, err := s.lockCountingStocktake(tctx, biz, id)
+		if err != nil {
+			return err
+		}
+		lines, err := s.storage.stocktakeLines.FindMany(tctx,
+			s.storage.stocktakeLines.ScopeEquals(StocktakeLineSchema.StocktakeID, st.ID),
+			s.storage.stocktakeLines.WithPreload(VariantStruct),
+		)
+		if err != nil {
+			return err
+		}
+		deltas := map[string]int{}
+		adjusted := []*StocktakeLine{}
+		counted := false
+		for _, line := range lines {
+			if line.CountedQuantity == nil {
+				continue
+			}
+			counted = true
+			if line.Variant == nil || line.Variance() == 0 {
+				continue
+			}
+			line.AdjustedQuantity = line.Variance()
+			deltas[line.VariantID] = line.AdjustedQuantity
+			adjusted = append(adjusted, line)
+		}
+		if !counted {
+			return ErrStocktakeNotCounted(st.ID)
+		}
+		if err := s.ApplyLocationStockDeltas(tctx, actor, biz, st.LocationID, deltas); err != nil {
+			return err
+		}
+		if len(adjusted) > 0 {
+			if err := s.storage.stocktakeLines.UpdateMany(tctx, adjusted); err != nil {
+				return err
+			}
+		}
+		now := time.Now().UTC()
+		st.Status = StocktakeStatusApproved
+		st.ApprovedByID = actor.ID
+		st.ApprovedAt = &now
+		return s.storage.stocktakes.UpdateOne(tctx, st)
+	}, atomic.WithIsolationLevel(atomic.LevelSerializable), atomic.WithRetries(3))
+	if err != nil {
+		return nil, err
+	}
+	return s.GetStocktakeByID(ctx, actor, biz, id)
+}
+
+// CancelStocktake abandons a stocktake that is still counting without changing stock.
+func (s *Service) CancelStocktake(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Stocktake, error) {
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		st, err := s.lockCountingStocktake(tctx, biz, id)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		st.Status = StocktakeStatusCancelled
+		st.CancelledAt = &now
+		return s.storage.stocktakes.UpdateOne(tctx, st)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetStocktakeByID(ctx, actor, biz, id)
+}
+
+// sortStocktakeLines orders lines by variant name, lines of deleted variants last.
+func sortStocktakeLines(lines []*StocktakeLine) {
+	sort.SliceStable(lines, func(i, j int) bool {
+		a, b := lines[i].Variant, lines[j].Variant
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return lines[i].VariantID < lines[j].VariantID
+	})
+}
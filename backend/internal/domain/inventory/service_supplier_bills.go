@@ -0,0 +1,351 @@
+package inventory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type ListSupplierBillsFilters struct {
+	SupplierID      string
+	PurchaseOrderID string
+	PaymentStatus   SupplierBillPaymentStatus
+	// Overdue keeps the bills not paid in full whose due date has passed.
+	Overdue bool
+}
+
+func (s *Service) ListSupplierBills(ctx context.Context, actor *account.User, biz *business.Business, req *list.ListRequest, filters *ListSupplierBillsFilters) ([]*SupplierBill, int64, error) {
+	scopes := []func(db *gorm.DB) *gorm.DB{
+		s.storage.supplierBills.ScopeBusinessID(biz.ID),
+	}
+	if filters != nil && filters.SupplierID != "" {
+		scopes = append(scopes, s.storage.supplierBills.ScopeEquals(SupplierBillSchema.SupplierID, filters.SupplierID))
+	}
+	if filters != nil && filters.PurchaseOrderID != "" {
+		scopes = append(scopes, s.storage.supplierBills.ScopeEquals(SupplierBillSchema.PurchaseOrderID, filters.PurchaseOrderID))
+	}
+	if filters != nil && filters.PaymentStatus != "" {
+		scopes = append(scopes, s.storage.supplierBills.ScopeEquals(SupplierBillSchema.PaymentStatus, filters.PaymentStatus))
+	}
+	if filters != nil && filters.Overdue {
+		scopes = append(scopes, s.storage.supplierBills.ScopeWhere("payment_status <> ? AND due_at < ?", SupplierBillPaid, time.Now().UTC()))
+	}
+	items, err := s.storage.supplierBills.FindMany(ctx,
+		append(scopes,
+			s.storage.supplierBills.WithPreload(SupplierBillSupplierField),
+			s.storage.supplierBills.WithPagination(req.Offset(), req.Limit()),
+			s.storage.supplierBills.WithOrderBy(req.ParsedOrderByWithDefault(SupplierBillSchema, []string{"dueAt"})),
+		)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.storage.supplierBills.Count(ctx, scopes...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (s *Service) GetSupplierBillByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*SupplierBill, error) {
+	return s.storage.supplierBills.FindOne(ctx,
+		s.storage.supplierBills.ScopeBusinessID(biz.ID),
+		s.storage.supplierBills.ScopeID(id),
+		s.storage.supplierBills.WithPreload(SupplierBillSupplierField),
+	)
+}
+
+// ListSupplierBillPayments returns the payments recorded on a bill, oldest first.
+func (s *Service) ListSupplierBillPayments(ctx context.Context, actor *account.User, biz *business.Business, bill *SupplierBill) ([]*SupplierBillPayment, error) {
+	return s.storage.supplierBillPayments.FindMany(ctx,
+		s.storage.supplierBillPayments.ScopeBusinessID(biz.ID),
+		s.storage.supplierBillPayments.ScopeEquals(SupplierBillPaymentSchema.BillID, bill.ID),
+		s.storage.supplierBillPayments.WithOrderBy([]string{"paid_at ASC", "created_at ASC"}),
+	)
+}
+
+// CreateSupplierBill records a bill sent by a supplier. A bill for a purchase order cannot take
+// the bills of the order past its total, and starts with the payments already made on the
+// order that no other bill took.
+func (s *Service) CreateSupplierBill(ctx context.Context, actor *account.User, biz *business.Business, req *CreateSupplierBillRequest) (*SupplierBill, error) {
+	sup, err := s.GetSupplierByID(ctx, actor, biz, req.SupplierID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrSupplierNotFound(err).With("supplierId", req.SupplierID)
+		}
+		return nil, err
+	}
+	now := time.Now().UTC()
+	bill := &SupplierBill{
+		BusinessID: biz.ID,
+		SupplierID: sup.ID,
+		Reference:  strings.TrimSpace(req.Reference),
+		Currency:   biz.Currency,
+		Amount:     money.Round(req.Amount, biz.Currency),
+		IssuedAt:   now,
+		DueAt:      req.DueAt.UTC(),
+		Notes:      strings.TrimSpace(req.Notes),
+	}
+	if req.IssuedAt != nil {
+		bill.IssuedAt = req.IssuedAt.UTC()
+	}
+	if bill.DueAt.Before(bill.IssuedAt) {
+		return nil, ErrSupplierBillInvalidDueDate()
+	}
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if poID := strings.TrimSpace(req.PurchaseOrderID); poID != "" {
+			po, err := s.lockBilledPurchaseOrder(tctx, biz, poID, sup.ID)
+			if err != nil {
+				return err
+			}
+			bills, err := s.listPurchaseOrderBills(tctx, biz, po.ID)
+			if err != nil {
+				return err
+			}
+			billed, applied := decimal.Zero, decimal.Zero
+			for _, b := range bills {
+				billed = billed.Add(b.Amount)
+				applied = applied.Add(b.AmountPaid)
+			}
+			if unbilled := po.Total.Sub(billed); bill.Amount.GreaterThan(unbilled) {
+				return ErrSupplierBillExceedsPurchaseOrder(po.ID, unbilled, bill.Amount)
+			}
+			bill.PurchaseOrderID = po.ID
+			bill.AmountPaid = decimal.Min(decimal.Max(po.AmountPaid.Sub(applied), decimal.Zero), bill.Amount)
+		}
+		bill.refreshPaymentStatus(now)
+		return s.storage.supplierBills.CreateOne(tctx, bill)
+	})
+	if err != nil {
+		return nil, err
+	}
+	bill.Supplier = sup
+	return bill, nil
+}
+
+func (s *Service) UpdateSupplierBill(ctx context.Context, actor *account.User, biz *business.Business, id string, req *UpdateSupplierBillRequest) (*SupplierBill, error) {
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		bill, err := s.storage.supplierBills.FindOne(tctx,
+			s.storage.supplierBills.ScopeBusinessID(biz.ID),
+			s.storage.supplierBills.ScopeID(id),
+			s.storage.supplierBills.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return err
+		}
+		if req.Reference != nil {
+			bill.Reference = strings.TrimSpace(*req.Reference)
+		}
+		if req.IssuedAt != nil {
+			bill.IssuedAt = req.IssuedAt.UTC()
+		}
+		if req.DueAt != nil {
+			bill.DueAt = req.DueAt.UTC()
+		}
+		if bill.DueAt.Before(bill.IssuedAt) {
+			return ErrSupplierBillInvalidDueDate()
+		}
+		if req.Notes != nil {
+			bill.Notes = strings.TrimSpace(*req.Notes)
+		}
+		if req.Amount != nil {
+			amount := money.Round(*req.Amount, biz.Currency)
+			if amount.LessThan(bill.AmountPaid) {
+				return ErrSupplierBillAmountBelowPaid(bill.ID, bill.AmountPaid)
+			}
+			if bill.PurchaseOrderID != "" && !amount.Equal(bill.Amount) {
+				po, err := s.lockBilledPurchaseOrder(tctx, biz, bill.PurchaseOrderID, bill.SupplierID)
+				if err != nil {
+					return err
+				}
+				bills, err := s.listPurchaseOrderBills(tctx, biz, po.ID)
+				if err != nil {
+					return err
+				}
+				billed := decimal.Zero
+				for _, b := range bills {
+					if b.ID != bill.ID {
+						billed = billed.Add(b.Amount)
+					}
+				}
+				if unbilled := po.Total.Sub(billed); amount.GreaterThan(unbilled) {
+					return ErrSupplierBillExceedsPurchaseOrder(po.ID, unbilled, amount)
+				}
+			}
+			bill.Amount = amount
+			bill.refreshPaymentStatus(time.Now().UTC())
+		}
+		return s.storage.supplierBills.UpdateOne(tctx, bill)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetSupplierBillByID(ctx, actor, biz, id)
+}
+
+// DeleteSupplierBill deletes a bill no payment was recorded on. Payments of its purchase order
+// it started with are left to the order's other bills.
+func (s *Service) DeleteSupplierBill(ctx context.Context, actor *account.User, biz *business.Business, id string) error {
+	bill, err := s.storage.supplierBills.FindOne(ctx,
+		s.storage.supplierBills.ScopeBusinessID(biz.ID),
+		s.storage.supplierBills.ScopeID(id),
+	)
+	if err != nil {
+		return err
+	}
+	payments, err := s.storage.supplierBillPayments.Count(ctx,
+		s.storage.supplierBillPayments.ScopeBusinessID(biz.ID),
+		s.storage.supplierBillPayments.ScopeEquals(SupplierBillPaymentSchema.BillID, bill.ID),
+	)
+	if err != nil {
+		return err
+	}
+	if payments > 0 {
+		return ErrSupplierBillHasPayments(bill.ID)
+	}
+	return s.storage.supplierBills.DeleteOne(ctx, bill)
+}
+
+// RecordSupplierBillPayment records a payment to the supplier against a bill. Payments never
+// exceed what is owed on the bill; on a bill for a purchase order they are paid on the order too.
+func (s *Service) RecordSupplierBillPayment(ctx context.Context, actor *account.User, biz *business.Business, id string, req *SupplierBillPaymentRequest) (*SupplierBill, error) {
+	amount := money.Round(req.Amount, biz.Currency)
+	paidAt := time.Now().UTC()
+	if req.PaidAt != nil {
+		paidAt = req.PaidAt.UTC()
+	}
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		bill, err := s.storage.supplierBills.FindOne(tctx,
+			s.storage.supplierBills.ScopeBusinessID(biz.ID),
+			s.storage.supplierBills.ScopeID(id),
+			s.storage.supplierBills.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return err
+		}
+		if outstanding := bill.AmountOutstanding(); amount.GreaterThan(outstanding) {
+			return ErrSupplierBillOverpayment(bill.ID, outstanding, amount)
+		}
+		if bill.PurchaseOrderID != "" {
+			po, err := s.storage.purchaseOrders.FindOne(tctx,
+				s.storage.purchaseOrders.ScopeBusinessID(biz.ID),
+				s.storage.purchaseOrders.ScopeID(bill.PurchaseOrderID),
+				s.storage.purchaseOrders.WithLockingStrength(database.LockingStrengthUpdate),
+			)
+			if err != nil {
+				return err
+			}
+			if unpaid := po.Total.Sub(po.AmountPaid); amount.GreaterThan(unpaid) {
+				return ErrPurchaseOrderOverpayment(po.ID, unpaid, amount)
+			}
+			po.AmountPaid = po.AmountPaid.Add(amount)
+			if err := s.storage.purchaseOrders.UpdateOne(tctx, po); err != nil {
+				return err
+			}
+		}
+		payment := &SupplierBillPayment{
+			BusinessID: biz.ID,
+			BillID:     bill.ID,
+			Amount:     amount,
+			PaidAt:     paidAt,
+			Note:       strings.TrimSpace(req.Note),
+		}
+		if actor != nil {
+			payment.RecordedByID = actor.ID
+		}
+		if err := s.storage.supplierBillPayments.CreateOne(tctx, payment); err != nil {
+			return err
+		}
+		bill.AmountPaid = bill.AmountPaid.Add(amount)
+		bill.refreshPaymentStatus(paidAt)
+		return s.storage.supplierBills.UpdateOne(tctx, bill)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetSupplierBillByID(ctx, actor, biz, id)
+}
+
+// ComputeSupplierBillAging splits what is owed on bills as of asOf by how many days it is past
+// due: current, 1-30, 31-60, 61-90 and over 90 days, in total and per supplier.
+func (s *Service) ComputeSupplierBillAging(ctx context.Context, actor *account.User, biz *business.Business, asOf time.Time) (*SupplierBillAging, error) {
+	bills, err := s.storage.supplierBills.FindMany(ctx,
+		s.storage.supplierBills.ScopeBusinessID(biz.ID),
+		s.storage.supplierBills.ScopeWhere("amount > amount_paid"),
+		s.storage.supplierBills.WithPreload(SupplierBillSupplierField),
+	)
+	if err != nil {
+		return nil, err
+	}
+	unbilled, err := s.storage.SumUnbilledPurchaseOrderPayables(ctx, biz.ID)
+	if err != nil {
+		return nil, err
+	}
+	aging := &SupplierBillAging{AsOf: asOf, Currency: biz.Currency, UnbilledPurchaseOrders: decimal.Zero}
+	for _, p := range unbilled {
+		aging.UnbilledPurchaseOrders = aging.UnbilledPurchaseOrders.Add(p)
+	}
+	asOfDay := truncateToDay(asOf)
+	lines := make(map[string]*SupplierBillAgingLine)
+	for _, b := range bills {
+		line, ok := lines[b.SupplierID]
+		if !ok {
+			line = &SupplierBillAgingLine{SupplierID: b.SupplierID}
+			if b.Supplier != nil {
+				line.SupplierName = b.Supplier.Name
+			}
+			lines[b.SupplierID] = line
+			aging.Suppliers = append(aging.Suppliers, line)
+		}
+		daysOverdue := int(asOfDay.Sub(truncateToDay(b.DueAt)).Hours() / 24)
+		line.add(daysOverdue, b.AmountOutstanding())
+		aging.add(daysOverdue, b.AmountOutstanding())
+	}
+	sort.Slice(aging.Suppliers, func(i, j int) bool {
+		return aging.Suppliers[i].Total.GreaterThan(aging.Suppliers[j].Total)
+	})
+	return aging, nil
+}
+
+// lockBilledPurchaseOrder locks a purchase order of the supplier to bill it.
+func (s *Service) lockBilledPurchaseOrder(ctx context.Context, biz *business.Business, purchaseOrderID, supplierID string) (*PurchaseOrder, error) {
+	po, err := s.storage.purchaseOrders.FindOne(ctx,
+		s.storage.purchaseOrders.ScopeBusinessID(biz.ID),
+		s.storage.purchaseOrders.ScopeID(purchaseOrderID),
+		s.storage.purchaseOrders.WithLockingStrength(database.LockingStrengthUpdate),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrPurchaseOrderNotFound(err).With("purchaseOrderId", purchaseOrderID)
+		}
+		return nil, err
+	}
+	if po.SupplierID != supplierID {
+		return nil, ErrSupplierBillPurchaseOrderMismatch(po.ID, supplierID)
+	}
+	if po.Status == PurchaseOrderStatusCancelled {
+		return nil, ErrPurchaseOrderCancelled(po.ID)
+	}
+	return po, nil
+}
+
+func (s *Service) listPurchaseOrderBills(ctx context.Context, biz *business.Business, purchaseOrderID string) ([]*SupplierBill, error) {
+	return s.storage.supplierBills.FindMany(ctx,
+		s.storage.supplierBills.ScopeBusinessID(biz.ID),
+		s.storage.supplierBills.ScopeEquals(SupplierBillSchema.PurchaseOrderID, purchaseOrderID),
+	)
+}
+
+func truncateToDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,62 @@
+package inventory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
+)
+
+// recordStockMovements records the changes to variant totals in deltas, keyed by variant ID.
+// It must run inside the transaction that applies them.
+func (s *Service) recordStockMovements(ctx context.Context, biz *business.Business, deltas map[string]int) error {
+	now := time.Now().UTC()
+	movements := make([]*StockMovement, 0, len(deltas))
+	for variantID, qty := range deltas {
+		if qty == 0 {
+			continue
+		}
+		movements = append(movements, &StockMovement{
+			BusinessID: biz.ID,
+			VariantID:  variantID,
+			Quantity:   qty,
+			OccurredAt: now,
+		})
+	}
+	if len(movements) == 0 {
+		return nil
+	}
+	sort.Slice(movements, func(i, j int) bool { return movements[i].VariantID < movements[j].VariantID })
+	return s.storage.stockMovements.CreateMany(ctx, movements)
+}
+
+// GetStockValuation values the business' inventory as of a past date: the stock each variant
+// held then, replayed from the stock movements since, at the cost price in effect then.
+func (s *Service) GetStockValuation(ctx context.Context, actor *account.User, biz *business.Business, asOf time.Time) (*StockValuation, error) {
+	asOf = asOf.UTC()
+	lines, err := s.storage.StockAsOf(ctx, biz.ID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	trackedSince, err := s.storage.FirstStockMovementAt(ctx, biz.ID)
+	if err != nil {
+		return nil, err
+	}
+	valuation := &StockValuation{
+		AsOf:         asOf,
+		TrackedSince: trackedSince,
+		Currency:     biz.Currency,
+		TotalValue:   decimal.Zero,
+		Lines:        lines,
+	}
+	for _, l := range lines {
+		l.Value = money.Round(l.UnitCost.Mul(decimal.NewFromInt(int64(l.Quantity))), biz.Currency)
+		valuation.TotalUnits += l.Quantity
+		valuation.TotalValue = valuation.TotalValue.Add(l.Value)
+	}
+	return valuation, nil
+}
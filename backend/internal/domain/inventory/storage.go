@@ -1,59 +1,511 @@
 package inventory
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/abdelrahman146/kyora/internal/platform/cache"
 	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Storage struct {
+	db         *database.Database
 	cache      *cache.Cache
 	products   *database.Repository[Product]
 	variants   *database.Repository[Variant]
 	categories *database.Repository[Category]
+
+	priceLists       *database.Repository[PriceList]
+	priceListEntries *database.Repository[PriceListEntry]
+
+	costChanges *database.Repository[VariantCostChange]
+
+	suppliers             *database.Repository[Supplier]
+	purchaseOrders        *database.Repository[PurchaseOrder]
+	purchaseOrderItems    *database.Repository[PurchaseOrderItem]
+	purchaseOrderReceipts *database.Repository[PurchaseOrderReceipt]
+	supplierBills         *database.Repository[SupplierBill]
+	supplierBillPayments  *database.Repository[SupplierBillPayment]
+
+	locations      *database.Repository[Location]
+	locationStocks *database.Repository[LocationStock]
+
+	stocktakes     *database.Repository[Stocktake]
+	stocktakeLines *database.Repository[StocktakeLine]
+
+	costLayers     *database.Repository[CostLayer]
+	stockMovements *database.Repository[StockMovement]
+
+	skuSequences *database.Repository[SKUSequence]
 }
 
 func NewStorage(db *database.Database, cache *cache.Cache) *Storage {
 	st := &Storage{
+		db:         db,
 		cache:      cache,
 		products:   database.NewRepository[Product](db),
 		variants:   database.NewRepository[Variant](db),
 		categories: database.NewRepository[Category](db),
+
+		priceLists:       database.NewRepository[PriceList](db),
+		priceListEntries: database.NewRepository[PriceListEntry](db),
+
+		costChanges: database.NewRepository[VariantCostChange](db),
+
+		suppliers:             database.NewRepository[Supplier](db),
+		purchaseOrders:        database.NewRepository[PurchaseOrder](db),
+		purchaseOrderItems:    database.NewRepository[PurchaseOrderItem](db),
+		purchaseOrderReceipts: database.NewRepository[PurchaseOrderReceipt](db),
+		supplierBills:         database.NewRepository[SupplierBill](db),
+		supplierBillPayments:  database.NewRepository[SupplierBillPayment](db),
+
+		locations:      database.NewRepository[Location](db),
+		locationStocks: database.NewRepository[LocationStock](db),
+
+		stocktakes:     database.NewRepository[Stocktake](db),
+		stocktakeLines: database.NewRepository[StocktakeLine](db),
+
+		costLayers:     database.NewRepository[CostLayer](db),
+		stockMovements: database.NewRepository[StockMovement](db),
+
+		skuSequences: database.NewRepository[SKUSequence](db),
 	}
 	ensureInventorySearchIndexes(db)
+	database.EnsureIndexes(db, Indexes...)
 	return st
 }
 
+// Indexes are the secondary indexes the inventory queries rely on.
+// Variants are joined and preloaded by product_id on every product listing.
+var Indexes = []database.Index{
+	{Name: "idx_variants_product_id", Table: VariantTable, Columns: []string{VariantSchema.ProductID.Column()}},
+}
+
 func ensureInventorySearchIndexes(db *database.Database) {
-	conn := db.GetDB()
-
-	// Product search vector: name (weight A) + description (weight B)
-	productExpr := "" +
-		"setweight(to_tsvector('simple', coalesce(name,'')), 'A') || " +
-		"setweight(to_tsvector('simple', coalesce(description,'')), 'B')"
-	database.EnsureGeneratedTSVectorColumn(conn, ProductTable, "search_vector", productExpr)
-	database.EnsureGinIndex(conn, "products_search_vector_gin_idx", ProductTable, "search_vector")
-
-	// Variant search vector: name (weight A) + sku (weight A) + code (weight B)
-	variantExpr := "" +
-		"setweight(to_tsvector('simple', coalesce(name,'')), 'A') || " +
-		"setweight(to_tsvector('simple', coalesce(sku,'')), 'A') || " +
-		"setweight(to_tsvector('simple', coalesce(code,'')), 'B')"
-	database.EnsureGeneratedTSVectorColumn(conn, VariantTable, "search_vector", variantExpr)
-	database.EnsureGinIndex(conn, "variants_search_vector_gin_idx", VariantTable, "search_vector")
-
-	// Trigram index for fast substring lookup on variant SKU
-	database.EnsureTrigramGinIndex(conn, "variants_sku_trgm_idx", VariantTable, "sku")
-
-	// Category search vector: name (weight A) + descriptor (weight B)
-	categoryExpr := "" +
-		"setweight(to_tsvector('simple', coalesce(name,'')), 'A') || " +
-		"setweight(to_tsvector('simple', coalesce(descriptor,'')), 'B')"
-	database.EnsureGeneratedTSVectorColumn(conn, CategoryTable, "search_vector", categoryExpr)
-	database.EnsureGinIndex(conn, "categories_search_vector_gin_idx", CategoryTable, "search_vector")
+	for _, conn := range db.Conns() {
+		// Product search vector: name (weight A) + description (weight B)
+		productExpr := "" +
+			"setweight(to_tsvector('simple', coalesce(name,'')), 'A') || " +
+			"setweight(to_tsvector('simple', coalesce(description,'')), 'B')"
+		database.EnsureGeneratedTSVectorColumn(conn, ProductTable, "search_vector", productExpr)
+		database.EnsureGinIndex(conn, "products_search_vector_gin_idx", ProductTable, "search_vector")
+
+		// Variant search vector: name (weight A) + sku (weight A) + code (weight B)
+		variantExpr := "" +
+			"setweight(to_tsvector('simple', coalesce(name,'')), 'A') || " +
+			"setweight(to_tsvector('simple', coalesce(sku,'')), 'A') || " +
+			"setweight(to_tsvector('simple', coalesce(code,'')), 'B')"
+		database.EnsureGeneratedTSVectorColumn(conn, VariantTable, "search_vector", variantExpr)
+		database.EnsureGinIndex(conn, "variants_search_vector_gin_idx", VariantTable, "search_vector")
+
+		// Trigram index for fast substring lookup on variant SKU
+		database.EnsureTrigramGinIndex(conn, "variants_sku_trgm_idx", VariantTable, "sku")
+
+		// Normalized trigram indexes back the typo/accent tolerant name matching.
+		database.EnsureNormalizedTrigramIndex(conn, "products_name_norm_trgm_idx", ProductTable, "name")
+		database.EnsureNormalizedTrigramIndex(conn, "variants_name_norm_trgm_idx", VariantTable, "name")
+
+		// Category search vector: name (weight A) + descriptor (weight B)
+		categoryExpr := "" +
+			"setweight(to_tsvector('simple', coalesce(name,'')), 'A') || " +
+			"setweight(to_tsvector('simple', coalesce(descriptor,'')), 'B')"
+		database.EnsureGeneratedTSVectorColumn(conn, CategoryTable, "search_vector", categoryExpr)
+		database.EnsureGinIndex(conn, "categories_search_vector_gin_idx", CategoryTable, "search_vector")
+	}
+}
+
+// ApplyStockDeltas adds each signed delta to the stock quantity of its variant in a single
+// UPDATE ... FROM (VALUES ...) statement. Rows are updated in variant ID order so concurrent
+// orders touching the same variants acquire row locks in a consistent order.
+//
+// A variant is left untouched when it does not belong to the business, is deleted, or the
+// delta would make its stock negative; callers compare the returned row count with
+// len(deltas) to detect that.
+func (s *Storage) ApplyStockDeltas(ctx context.Context, businessID string, deltas map[string]int) (int64, error) {
+	if len(deltas) == 0 {
+		return 0, nil
+	}
+	values, args := stockDeltaValues(deltas)
+	args = append(args, businessID)
+
+	sql := fmt.Sprintf(`
+		UPDATE variants AS v
+		SET stock_quantity = v.stock_quantity + d.delta, updated_at = NOW()
+		FROM (VALUES %s) AS d(id, delta)
+		WHERE v.id = d.id
+			AND v.business_id = ?
+			AND v.deleted_at IS NULL
+			AND v.stock_quantity + d.delta >= 0
+	`, values)
+
+	res := s.db.Conn(ctx).Exec(sql, args...)
+	return res.RowsAffected, res.Error
+}
+
+// stockDeltaValues renders deltas as a VALUES list of (variant id, delta) rows in variant ID
+// order, returning the list and its bind arguments.
+func stockDeltaValues(deltas map[string]int) (string, []any) {
+	ids := make([]string, 0, len(deltas))
+	for id := range deltas {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	rows := make([]string, 0, len(ids))
+	args := make([]any, 0, len(ids)*2+2)
+	for _, id := range ids {
+		rows = append(rows, "(?::text, ?::int)")
+		args = append(args, id, deltas[id])
+	}
+	return strings.Join(rows, ", "), args
+}
+
+// SetLandedCost stores a variant's landed cost without touching the rest of the row, whose
+// stock quantity is kept by ApplyStockDeltas.
+func (s *Storage) SetLandedCost(ctx context.Context, businessID, variantID string, cost decimal.Decimal) error {
+	return s.db.Conn(ctx).
+		Model(&Variant{}).
+		Where("id = ? AND business_id = ?", variantID, businessID).
+		UpdateColumn("landed_cost", cost).Error
+}
+
+// ApplyLocationStockDeltas adds each signed delta to the stock a location holds of its variant,
+// creating the missing stock rows of incoming variants first. Like ApplyStockDeltas it updates
+// rows in variant ID order and leaves a row untouched when the delta would make it negative;
+// callers compare the returned row count with len(deltas).
+//
+// It does not touch variants.stock_quantity: callers moving stock in or out of the business
+// apply the same deltas with ApplyStockDeltas in the same transaction.
+func (s *Storage) ApplyLocationStockDeltas(ctx context.Context, businessID, locationID string, deltas map[string]int) (int64, error) {
+	if len(deltas) == 0 {
+		return 0, nil
+	}
+	var missing []*LocationStock
+	for variantID, delta := range deltas {
+		if delta >= 0 {
+			missing = append(missing, &LocationStock{BusinessID: businessID, LocationID: locationID, VariantID: variantID})
+		}
+	}
+	if len(missing) > 0 {
+		err := s.db.Conn(ctx).
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&missing).Error
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	values, args := stockDeltaValues(deltas)
+	args = append(args, businessID, locationID)
+	sql := fmt.Sprintf(`
+		UPDATE location_stocks AS ls
+		SET quantity = ls.quantity + d.delta, updated_at = NOW()
+		FROM (VALUES %s) AS d(id, delta)
+		WHERE ls.variant_id = d.id
+			AND ls.business_id = ?
+			AND ls.location_id = ?
+			AND ls.quantity + d.delta >= 0
+	`, values)
+
+	res := s.db.Conn(ctx).Exec(sql, args...)
+	return res.RowsAffected, res.Error
+}
+
+// SeedLocationStock puts the whole current stock of every variant of the business into the
+// location. It is used when the business creates its first location, so the per-location
+// levels add up to the variant totals from the start.
+func (s *Storage) SeedLocationStock(ctx context.Context, businessID, locationID string) error {
+	var variants []struct {
+		ID            string
+		StockQuantity int
+	}
+	err := s.db.Conn(ctx).
+		Model(&Variant{}).
+		Select("id, stock_quantity").
+		Where("business_id = ?", businessID).
+		Where("stock_quantity > 0").
+		Scan(&variants).Error
+	if err != nil || len(variants) == 0 {
+		return err
+	}
+	stocks := make([]*LocationStock, len(variants))
+	for i, v := range variants {
+		stocks[i] = &LocationStock{BusinessID: businessID, LocationID: locationID, VariantID: v.ID, Quantity: v.StockQuantity}
+	}
+	return s.locationStocks.CreateMany(ctx, stocks)
+}
+
+// SumStockByLocation returns, per location ID, the units held and their value at current cost
+// prices. Deleted variants are excluded and locations without stock are absent.
+func (s *Storage) SumStockByLocation(ctx context.Context, businessID string) (map[string]*LocationStockTotals, error) {
+	var rows []struct {
+		LocationID     string
+		StockUnits     int64
+		InventoryValue decimal.Decimal
+	}
+	err := s.db.Conn(ctx).
+		Table(LocationStockTable).
+		Select("location_stocks.location_id AS location_id, "+
+			"COALESCE(SUM(location_stocks.quantity), 0) AS stock_units, "+
+			"COALESCE(SUM(location_stocks.quantity * variants.cost_price), 0) AS inventory_value").
+		Joins("JOIN variants ON variants.id = location_stocks.variant_id AND variants.deleted_at IS NULL").
+		Where("location_stocks.business_id = ?", businessID).
+		Group("location_stocks.location_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	totals := make(map[string]*LocationStockTotals, len(rows))
+	for _, r := range rows {
+		totals[r.LocationID] = &LocationStockTotals{StockUnits: r.StockUnits, InventoryValue: r.InventoryValue}
+	}
+	return totals, nil
+}
+
+// ReferencedPhotoAssetIDs returns the asset IDs among assetIDs that a product or variant of the
+// business still shows as a photo.
+func (s *Storage) ReferencedPhotoAssetIDs(ctx context.Context, businessID string, assetIDs []string) ([]string, error) {
+	var ids []string
+	err := s.db.Conn(ctx).Raw(`
+		SELECT photo->>'assetId' FROM products, jsonb_array_elements(products.photos) AS photo
+		WHERE products.business_id = ? AND products.deleted_at IS NULL AND photo->>'assetId' IN ?
+		UNION
+		SELECT photo->>'assetId' FROM variants, jsonb_array_elements(variants.photos) AS photo
+		WHERE variants.business_id = ? AND variants.deleted_at IS NULL AND photo->>'assetId' IN ?
+	`, businessID, assetIDs, businessID, assetIDs).Scan(&ids).Error
+	return ids, err
+}
+
+// StockAsOf returns the variants that held stock at asOf with the quantity they held, found by
+// taking the movements recorded after asOf back out of their current stock, valued at the cost
+// price of their last cost change on or before asOf. Variants deleted after asOf are included.
+func (s *Storage) StockAsOf(ctx context.Context, businessID string, asOf time.Time) ([]*StockValuationLine, error) {
+	var lines []*StockValuationLine
+	err := s.db.Conn(ctx).Raw(`
+		WITH moved AS (
+			SELECT variant_id, SUM(quantity) AS quantity
+			FROM stock_movements
+			WHERE business_id = ? AND occurred_at > ?
+			GROUP BY variant_id
+		), cost AS (
+			SELECT DISTINCT ON (variant_id) variant_id, cost_price
+			FROM variant_cost_changes
+			WHERE business_id = ? AND changed_at <= ?
+			ORDER BY variant_id, changed_at DESC, id DESC
+		)
+		SELECT v.id AS variant_id, v.product_id, v.name, v.sku,
+			v.stock_quantity - COALESCE(m.quantity, 0) AS quantity,
+			COALESCE(c.cost_price, v.cost_price) AS unit_cost
+		FROM variants AS v
+		LEFT JOIN moved AS m ON m.variant_id = v.id
+		LEFT JOIN cost AS c ON c.variant_id = v.id
+		WHERE v.business_id = ?
+			AND v.created_at <= ?
+			AND (v.deleted_at IS NULL OR v.deleted_at > ?)
+			AND v.stock_quantity - COALESCE(m.quantity, 0) <> 0
+		ORDER BY v.name ASC, v.id ASC
+	`, businessID, asOf, businessID, asOf, businessID, asOf, asOf).Scan(&lines).Error
+	return lines, err
+}
+
+// NextSKUNumber takes the next number of the business' SKU sequence for stem, starting the
+// sequence at 1. The sequence row stays locked until the surrounding transaction ends.
+func (s *Storage) NextSKUNumber(ctx context.Context, businessID, stem string) (int, error) {
+	var next int
+	err := s.db.Conn(ctx).Raw(`
+		INSERT INTO sku_sequences (id, business_id, stem, last_value, created_at, updated_at)
+		VALUES (?, ?, ?, 1, NOW(), NOW())
+		ON CONFLICT (business_id, stem)
+		DO UPDATE SET last_value = sku_sequences.last_value + 1, updated_at = NOW()
+		RETURNING last_value
+	`, id.KsuidWithPrefix(SKUSequencePrefix), businessID, stem).Scan(&next).Error
+	return next, err
+}
+
+// FirstStockMovementAt returns when the business' first stock movement was recorded, nil when
+// it has none.
+func (s *Storage) FirstStockMovementAt(ctx context.Context, businessID string) (*time.Time, error) {
+	var row struct {
+		First *time.Time
+	}
+	err := s.db.Conn(ctx).
+		Table(StockMovementTable).
+		Select("MIN(occurred_at) AS first").
+		Where("business_id = ?", businessID).
+		Scan(&row).Error
+	return row.First, err
+}
+
+// reservingOrderStatuses are the order statuses whose items still sit on the shelf:
+// their stock was already deducted from variants.stock_quantity but has not shipped.
+// Kept in sync with order.OrderStatus (inventory cannot import the order domain).
+var reservingOrderStatuses = []string{"pending", "placed", "ready_for_shipment"}
+
+// SumReservedQuantities returns, per variant ID, the units held by open orders of the business.
+// Variants without open orders are absent from the map.
+func (s *Storage) SumReservedQuantities(ctx context.Context, businessID string, variantIDs []string) (map[string]int, error) {
+	reserved := make(map[string]int, len(variantIDs))
+	if len(variantIDs) == 0 {
+		return reserved, nil
+	}
+	var rows []struct {
+		VariantID string
+		Reserved  int
+	}
+	err := s.db.Conn(ctx).
+		Table("order_items").
+		Select("order_items.variant_id AS variant_id, COALESCE(SUM(order_items.quantity), 0) AS reserved").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("orders.business_id = ?", businessID).
+		Where("orders.status IN ?", reservingOrderStatuses).
+		Where("order_items.variant_id IN ?", variantIDs).
+		Where("order_items.deleted_at IS NULL").
+		Group("order_items.variant_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		reserved[r.VariantID] = r.Reserved
+	}
+	return reserved, nil
+}
+
+// nonSellingOrderStatuses are the order statuses whose items do not count as sales.
+// Kept in sync with order.OrderStatus (inventory cannot import the order domain).
+var nonSellingOrderStatuses = []string{"cancelled", "returned"}
+
+// SumSoldQuantities returns, per variant ID, the units the business sold in orders placed
+// since the given time. Cancelled and returned orders are excluded.
+func (s *Storage) SumSoldQuantities(ctx context.Context, businessID string, since time.Time) (map[string]int, error) {
+	var rows []struct {
+		VariantID string
+		Sold      int
+	}
+	err := s.db.Conn(ctx).
+		Table("order_items").
+		Select("order_items.variant_id AS variant_id, COALESCE(SUM(order_items.quantity), 0) AS sold").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("orders.business_id = ?", businessID).
+		Where("orders.status NOT IN ?", nonSellingOrderStatuses).
+		Where("orders.ordered_at >= ?", since).
+		Where("order_items.deleted_at IS NULL").
+		Group("order_items.variant_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	sold := make(map[string]int, len(rows))
+	for _, r := range rows {
+		sold[r.VariantID] = r.Sold
+	}
+	return sold, nil
+}
+
+// SumVariantSalesByMonth returns the units, revenue and cost of goods of a variant sold per
+// calendar month (UTC) in orders placed within [from, to]. It uses the unit cost and price
+// snapshotted on the order items; averages are rounded to the minor unit of currency. Cancelled
+// and returned orders are excluded and months without sales are absent.
+func (s *Storage) SumVariantSalesByMonth(ctx context.Context, businessID, variantID, currency string, from, to time.Time) ([]*CostTrendPeriod, error) {
+	var rows []struct {
+		Period    time.Time
+		UnitsSold int64
+		Revenue   decimal.Decimal
+		COGS      decimal.Decimal
+	}
+	err := s.db.Conn(ctx).
+		Table("order_items").
+		Select("date_trunc('month', orders.ordered_at) AS period, "+
+			"COALESCE(SUM(order_items.quantity), 0) AS units_sold, "+
+			"COALESCE(SUM(order_items.total), 0) AS revenue, "+
+			"COALESCE(SUM(order_items.total_cost), 0) AS cogs").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("orders.business_id = ?", businessID).
+		Where("orders.status NOT IN ?", nonSellingOrderStatuses).
+		Where("orders.ordered_at BETWEEN ? AND ?", from, to).
+		Where("order_items.variant_id = ?", variantID).
+		Where("order_items.deleted_at IS NULL").
+		Group("period").
+		Order("period ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	periods := make([]*CostTrendPeriod, len(rows))
+	for i, r := range rows {
+		p := &CostTrendPeriod{Period: r.Period, UnitsSold: r.UnitsSold, Revenue: r.Revenue, COGS: r.COGS}
+		if r.UnitsSold > 0 {
+			units := decimal.NewFromInt(r.UnitsSold)
+			p.AvgUnitCost = money.Round(r.COGS.Div(units), currency)
+			p.AvgUnitPrice = money.Round(r.Revenue.Div(units), currency)
+		}
+		p.MarginPercent = marginPercent(r.COGS, r.Revenue)
+		periods[i] = p
+	}
+	return periods, nil
+}
+
+// SumSupplierPayables returns, per supplier ID, what the business owes on unpaid bills and for
+// goods received on purchase orders not billed yet. Cancelled orders and suppliers owed nothing
+// are absent.
+func (s *Storage) SumSupplierPayables(ctx context.Context, businessID string) (map[string]decimal.Decimal, error) {
+	payables, err := s.SumUnbilledPurchaseOrderPayables(ctx, businessID)
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		SupplierID string
+		Payable    decimal.Decimal
+	}
+	err = s.db.Conn(ctx).
+		Model(&SupplierBill{}).
+		Select("supplier_id, COALESCE(SUM(amount - amount_paid), 0) AS payable").
+		Where("business_id = ?", businessID).
+		Where("amount > amount_paid").
+		Group("supplier_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		payables[r.SupplierID] = payables[r.SupplierID].Add(r.Payable)
+	}
+	return payables, nil
+}
+
+// SumUnbilledPurchaseOrderPayables returns, per supplier ID, what the business owes for goods
+// received on purchase orders that have no bill and not yet paid. A billed purchase order is
+// owed through its bills.
+func (s *Storage) SumUnbilledPurchaseOrderPayables(ctx context.Context, businessID string) (map[string]decimal.Decimal, error) {
+	var rows []struct {
+		SupplierID string
+		Payable    decimal.Decimal
+	}
+	err := s.db.Conn(ctx).
+		Model(&PurchaseOrder{}).
+		Select("supplier_id, COALESCE(SUM(GREATEST(received_total - amount_paid, 0)), 0) AS payable").
+		Where("business_id = ?", businessID).
+		Where("status <> ?", PurchaseOrderStatusCancelled).
+		Where("received_total > amount_paid").
+		Where("NOT EXISTS (SELECT 1 FROM supplier_bills WHERE supplier_bills.purchase_order_id = purchase_orders.id AND supplier_bills.deleted_at IS NULL)").
+		Group("supplier_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	payables := make(map[string]decimal.Decimal, len(rows))
+	for _, r := range rows {
+		payables[r.SupplierID] = r.Payable
+	}
+	return payables, nil
 }
 
 func (s *Storage) ScopeLowStockVariants() func(db *gorm.DB) *gorm.DB {
@@ -62,6 +514,14 @@ func (s *Storage) ScopeLowStockVariants() func(db *gorm.DB) *gorm.DB {
 	}
 }
 
+// ScopeStockTrackedVariants keeps the variants of physical products: digital and service
+// products hold no stock, so they never run low.
+func (s *Storage) ScopeStockTrackedVariants() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("variants.product_id IN (SELECT id FROM products WHERE type = ?)", ProductTypePhysical)
+	}
+}
+
 func (s *Storage) ScopeOutOfStockVariants() func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		return db.Where(fmt.Sprintf("%s = 0", VariantSchema.StockQuantity.Column()))
@@ -74,22 +534,66 @@ func (s *Storage) ScopeInStockVariants() func(db *gorm.DB) *gorm.DB {
 	}
 }
 
-// ScopeProductSearch applies search filter across products, variants, categories, and SKU
+// ProductSearchNameColumns are the name columns product search matches fuzzily and ranks by similarity.
+var ProductSearchNameColumns = []string{"products.name", "variants.name"}
+
+// ScopeProductSearch applies search filter across products, variants, categories, and SKU,
+// falling back to typo/accent tolerant matching on product and variant names and to partial
+// or mistyped SKUs.
 func (s *Storage) ScopeProductSearch(searchTerm string) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		if searchTerm == "" {
 			return db
 		}
-		like := "%" + searchTerm + "%"
+		fuzzy, fuzzyVars, err := database.FuzzyMatch(searchTerm, ProductSearchNameColumns...)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		sku, skuVars, err := database.TrigramMatch(searchTerm, "variants.sku")
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		vars := append(append([]any{searchTerm, searchTerm, searchTerm}, skuVars...), fuzzyVars...)
 		return db.
 			Joins("LEFT JOIN categories ON categories.id = products.category_id AND categories.deleted_at IS NULL").
 			Joins("LEFT JOIN variants ON variants.product_id = products.id AND variants.deleted_at IS NULL").
 			Where(
-				"(products.search_vector @@ websearch_to_tsquery('simple', ?) OR variants.search_vector @@ websearch_to_tsquery('simple', ?) OR categories.search_vector @@ websearch_to_tsquery('simple', ?) OR variants.sku ILIKE ?)",
-				searchTerm,
-				searchTerm,
-				searchTerm,
-				like,
+				"(products.search_vector @@ websearch_to_tsquery('simple', ?) OR variants.search_vector @@ websearch_to_tsquery('simple', ?) OR categories.search_vector @@ websearch_to_tsquery('simple', ?) OR "+sku+" OR "+fuzzy+")",
+				vars...,
+			)
+	}
+}
+
+// VariantSearchNameColumns are the name columns variant search matches fuzzily and ranks by similarity.
+var VariantSearchNameColumns = []string{"variants.name", "products.name"}
+
+// ScopeVariantSearch applies search filter across variants, their products, SKU, code and barcode,
+// falling back to typo/accent tolerant matching on variant and product names and to partial
+// or mistyped SKUs.
+func (s *Storage) ScopeVariantSearch(searchTerm string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if searchTerm == "" {
+			return db
+		}
+		fuzzy, fuzzyVars, err := database.FuzzyMatch(searchTerm, VariantSearchNameColumns...)
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		sku, skuVars, err := database.TrigramMatch(searchTerm, "variants.sku")
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		like := "%" + searchTerm + "%"
+		vars := append(append(append([]any{searchTerm, searchTerm}, skuVars...), like, searchTerm), fuzzyVars...)
+		return db.
+			Joins("LEFT JOIN products ON products.id = variants.product_id AND products.deleted_at IS NULL").
+			Where(
+				"(variants.search_vector @@ websearch_to_tsquery('simple', ?) OR products.search_vector @@ websearch_to_tsquery('simple', ?) OR "+sku+" OR variants.code ILIKE ? OR variants.barcode = ? OR "+fuzzy+")",
+				vars...,
 			)
 	}
 }
@@ -206,3 +710,88 @@ func (s *Storage) ParseProductCustomOrdering(orderBy []string) []string {
 	}
 	return customOrders
 }
+
+// SearchCatalog ranks the business's products and variants matching term together by the
+// weighted score of ranking, best first, at most limit. Names match by full text or typo and
+// accent tolerant similarity; SKUs match partially or mistyped, and barcodes exactly.
+func (s *Storage) SearchCatalog(ctx context.Context, businessID, term string, types []SearchResultType, ranking SearchRanking, limit int) ([]*searchHit, error) {
+	builders := map[SearchResultType]func(context.Context, string, string, SearchRanking) (*gorm.DB, error){
+		SearchResultTypeProduct: s.productSearchQuery,
+		SearchResultTypeVariant: s.variantSearchQuery,
+	}
+	parts := make([]string, 0, len(types))
+	args := make([]any, 0, len(types)+2)
+	for _, t := range types {
+		build, ok := builders[t]
+		if !ok {
+			continue
+		}
+		q, err := build(ctx, businessID, term, ranking)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, "?")
+		args = append(args, q)
+	}
+	if len(parts) == 0 {
+		return []*searchHit{}, nil
+	}
+	args = append(args, ranking.MinScore, limit)
+
+	var hits []*searchHit
+	err := s.db.Conn(ctx).
+		Raw("SELECT type, id, score FROM ("+strings.Join(parts, " UNION ALL ")+") AS hits WHERE score >= ? ORDER BY score DESC, type, id LIMIT ?", args...).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+func (s *Storage) productSearchQuery(ctx context.Context, businessID, term string, ranking SearchRanking) (*gorm.DB, error) {
+	rank, err := database.SearchRank(term, []string{"products.search_vector"}, []string{"products.name"})
+	if err != nil {
+		return nil, err
+	}
+	skuSim, err := database.TrigramSimilarity(term, "variants.sku")
+	if err != nil {
+		return nil, err
+	}
+	fuzzy, fuzzyVars, err := database.FuzzyMatch(term, "products.name")
+	if err != nil {
+		return nil, err
+	}
+	sku, skuVars, err := database.TrigramMatch(term, "variants.sku")
+	if err != nil {
+		return nil, err
+	}
+	// a product's SKU score is that of its closest variant SKU
+	skuScore := "COALESCE((SELECT MAX(" + skuSim.SQL + " + CASE WHEN lower(variants.sku) = lower(?) THEN 1 ELSE 0 END) " +
+		"FROM variants WHERE variants.product_id = products.id AND variants.deleted_at IS NULL), 0)"
+	scoreVars := append(append(append([]any{ranking.NameWeight}, rank.Vars...), ranking.SKUWeight), append(skuSim.Vars, term)...)
+	return s.db.Conn(ctx).
+		Table(ProductTable).
+		Select("'product' AS type, products.id AS id, (?::float8 * ("+rank.SQL+") + ?::float8 * "+skuScore+") AS score", scoreVars...).
+		Where("products.business_id = ? AND products.deleted_at IS NULL", businessID).
+		Where("(products.search_vector @@ websearch_to_tsquery('simple', ?) OR "+fuzzy+" OR EXISTS ("+
+			"SELECT 1 FROM variants WHERE variants.product_id = products.id AND variants.deleted_at IS NULL AND "+sku+"))",
+			append(append([]any{term}, fuzzyVars...), skuVars...)...), nil
+}
+
+func (s *Storage) variantSearchQuery(ctx context.Context, businessID, term string, ranking SearchRanking) (*gorm.DB, error) {
+	rank, err := database.SearchRank(term, []string{"variants.search_vector", "products.search_vector"}, VariantSearchNameColumns)
+	if err != nil {
+		return nil, err
+	}
+	skuSim, err := database.TrigramSimilarity(term, "variants.sku")
+	if err != nil {
+		return nil, err
+	}
+	scoreVars := append(append(append([]any{ranking.NameWeight}, rank.Vars...), ranking.SKUWeight), append(skuSim.Vars, term, term)...)
+	return s.db.Conn(ctx).
+		Table(VariantTable).
+		Select("'variant' AS type, variants.id AS id, (?::float8 * ("+rank.SQL+") + ?::float8 * ("+skuSim.SQL+
+			" + CASE WHEN lower(variants.sku) = lower(?) OR variants.barcode = ? THEN 1 ELSE 0 END)) AS score", scoreVars...).
+		Scopes(s.ScopeVariantSearch(term)).
+		Where("variants.business_id = ? AND variants.deleted_at IS NULL", businessID), nil
+}
@@ -1,6 +1,7 @@
 package metadata
 
 import (
+	"github.com/abdelrahman146/kyora/internal/platform/region"
 	"github.com/abdelrahman146/kyora/internal/platform/response"
 	"github.com/abdelrahman146/kyora/internal/platform/utils/country"
 	"github.com/gin-gonic/gin"
@@ -17,3 +18,11 @@ func (h *HttpHandler) ListCountries(c *gin.Context) {
 		"countries": country.Countries(),
 	})
 }
+
+// ListRegions returns the data residency regions a workspace can be created in, home first.
+func (h *HttpHandler) ListRegions(c *gin.Context) {
+	response.SuccessJSON(c, 200, gin.H{
+		"regions": region.Configured(),
+		"default": region.Home(),
+	})
+}
@@ -35,6 +35,9 @@ func ErrInvalidStage(err error, expected string) error {
 func ErrInvalidOTP(err error) error {
 	return problem.BadRequest("invalid or expired verification code").WithError(err).WithCode("onboarding.invalid_otp")
 }
+func ErrInvalidRegion(err error, name string) error {
+	return problem.BadRequest("data residency region is not available: " + name).WithError(err).WithCode("onboarding.invalid_region")
+}
 func ErrPlanNotFound(err error) error {
 	return problem.BadRequest("selected plan not found").WithError(err).WithCode("onboarding.plan_not_found")
 }
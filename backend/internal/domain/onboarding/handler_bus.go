@@ -13,16 +13,18 @@ type BusHandler struct {
 
 func NewBusHandler(b *bus.Bus, svc *Service) {
 	h := &BusHandler{svc: svc}
-	b.Listen(bus.OnboardingPaymentSucceededTopic, h.HandleOnboardingPaymentSucceeded)
+	b.Handle(bus.OnboardingPaymentSucceededTopic, "onboarding.payment_succeeded", h.HandleOnboardingPaymentSucceeded)
 }
 
-func (h *BusHandler) HandleOnboardingPaymentSucceeded(event any) {
+func (h *BusHandler) HandleOnboardingPaymentSucceeded(event any) error {
 	e, ok := event.(*bus.OnboardingPaymentSucceededEvent)
 	if !ok {
 		logger.FromContext(context.Background()).Error("invalid event type for OnboardingPaymentSucceededEvent")
-		return
+		return nil
 	}
 	if err := h.svc.MarkPaymentSucceeded(e.Ctx, e.OnboardingSessionID, e.StripeSubscriptionID); err != nil {
 		logger.FromContext(e.Ctx).Error("failed to mark onboarding session as payment succeeded", "error", err)
+		return err
 	}
+	return nil
 }
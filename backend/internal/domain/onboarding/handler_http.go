@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/platform/region"
 	"github.com/abdelrahman146/kyora/internal/platform/request"
 	"github.com/abdelrahman146/kyora/internal/platform/response"
 	"github.com/gin-gonic/gin"
@@ -154,7 +155,7 @@ func (h *HttpHandler) SetBusiness(c *gin.Context) {
 	if err := request.ValidBody(c, &req); err != nil {
 		return
 	}
-	sess, err := h.svc.SetBusinessDetails(c.Request.Context(), req.SessionToken, req.Name, req.Descriptor, req.Country, req.Currency)
+	sess, err := h.svc.SetBusinessDetails(c.Request.Context(), req.SessionToken, req.Name, req.Descriptor, req.Country, req.Currency, req.Region)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -230,6 +231,7 @@ type sessionResponse struct {
 	BusinessDescriptor string         `json:"businessDescriptor,omitempty"`
 	BusinessCountry    string         `json:"businessCountry,omitempty"`
 	BusinessCurrency   string         `json:"businessCurrency,omitempty"`
+	Region             string         `json:"region"`
 	PaymentStatus      PaymentStatus  `json:"paymentStatus"`
 	CheckoutSessionID  string         `json:"checkoutSessionId,omitempty"`
 	OTPExpiry          *string        `json:"otpExpiry,omitempty"`
@@ -282,6 +284,7 @@ func (h *HttpHandler) GetSession(c *gin.Context) {
 		BusinessDescriptor: sess.BusinessDescriptor,
 		BusinessCountry:    sess.BusinessCountry,
 		BusinessCurrency:   sess.BusinessCurrency,
+		Region:             region.Resolve(sess.Region),
 		PaymentStatus:      sess.PaymentStatus,
 		CheckoutSessionID:  sess.CheckoutSessionID,
 		OTPExpiry:          otpExpiry,
@@ -63,6 +63,7 @@ type OnboardingSession struct {
 	BusinessDescriptor string         `gorm:"column:business_descriptor;type:text" json:"businessDescriptor"`
 	BusinessCountry    string         `gorm:"column:business_country;type:text" json:"businessCountry"`
 	BusinessCurrency   string         `gorm:"column:business_currency;type:text" json:"businessCurrency"`
+	Region             string         `gorm:"column:region;type:text" json:"region"`
 	StripeCustomerID   string         `gorm:"column:stripe_customer_id;type:text" json:"stripeCustomerId"`
 	StripeSubID        string         `gorm:"column:stripe_sub_id;type:text" json:"stripeSubId"`
 	CheckoutSessionID  string         `gorm:"column:checkout_session_id;type:text" json:"checkoutSessionId"`
@@ -33,6 +33,8 @@ type businessRequest struct {
 	Descriptor   string `json:"descriptor" binding:"required"`
 	Country      string `json:"country" binding:"required,len=2"`
 	Currency     string `json:"currency" binding:"required,len=3"`
+	// Region is the data residency region the business data will live in. Defaults to the home region.
+	Region string `json:"region" binding:"omitempty,max=64"`
 }
 
 // paymentStartRequest represents the request to start payment.
@@ -13,6 +13,7 @@ import (
 	"github.com/abdelrahman146/kyora/internal/platform/database"
 	"github.com/abdelrahman146/kyora/internal/platform/email"
 	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/region"
 	pactomic "github.com/abdelrahman146/kyora/internal/platform/types/atomic"
 	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
 	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
@@ -283,8 +284,9 @@ func (s *Service) SetOAuthIdentity(ctx context.Context, token string, googleCode
 	return sess, nil
 }
 
-// SetBusinessDetails stages business data
-func (s *Service) SetBusinessDetails(ctx context.Context, token string, name, descriptor, country, currency string) (*OnboardingSession, error) {
+// SetBusinessDetails stages business data and the data residency region the workspace
+// will live in. An empty region selects the home region.
+func (s *Service) SetBusinessDetails(ctx context.Context, token string, name, descriptor, country, currency, dataRegion string) (*OnboardingSession, error) {
 	sess, err := s.storage.GetByToken(ctx, token)
 	if err != nil || sess == nil {
 		return nil, ErrSessionNotFound(err)
@@ -295,10 +297,14 @@ func (s *Service) SetBusinessDetails(ctx context.Context, token string, name, de
 	if sess.Stage != StageIdentityVerified && sess.Stage != StageBusinessStaged {
 		return nil, ErrInvalidStage(nil, string(StageIdentityVerified))
 	}
+	if !region.IsConfigured(dataRegion) {
+		return nil, ErrInvalidRegion(nil, dataRegion)
+	}
 	sess.BusinessName = name
 	sess.BusinessDescriptor = descriptor
 	sess.BusinessCountry = country
 	sess.BusinessCurrency = currency
+	sess.Region = region.Resolve(dataRegion)
 	sess.Stage = StageBusinessStaged
 	if !sess.IsPaidPlan {
 		sess.Stage = StageReadyToCommit
@@ -397,22 +403,24 @@ func (s *Service) CompleteOnboarding(ctx context.Context, token, clientIP, userA
 	var createdWorkspace *account.Workspace
 	err = s.atomicProcessor.Exec(ctx, func(txCtx context.Context) error {
 		// bootstrap workspace & owner via account service abstraction
-		u, ws, err := s.account.BootstrapWorkspaceAndOwner(txCtx, sess.FirstName, sess.LastName, sess.Email, sess.PasswordHash, sess.EmailVerified, sess.StripeCustomerID)
+		u, ws, err := s.account.BootstrapWorkspaceAndOwner(txCtx, sess.FirstName, sess.LastName, sess.Email, sess.PasswordHash, sess.EmailVerified, sess.StripeCustomerID, sess.Region)
 		if err != nil {
 			return err
 		}
 		createdUser = u
 		createdWorkspace = ws
-		// create business using business service (actor = owner)
+		// create business using business service (actor = owner), in the workspace region.
+		// Outside the home region it is not part of this transaction.
+		bizCtx := region.WithRegion(txCtx, ws.Region)
 		if s.business != nil {
-			_, err = s.business.CreateBusiness(txCtx, createdUser, &business.CreateBusinessInput{Descriptor: sess.BusinessDescriptor, Name: sess.BusinessName, CountryCode: sess.BusinessCountry, Currency: sess.BusinessCurrency})
+			_, err = s.business.CreateBusiness(bizCtx, createdUser, &business.CreateBusinessInput{Descriptor: sess.BusinessDescriptor, Name: sess.BusinessName, CountryCode: sess.BusinessCountry, Currency: sess.BusinessCurrency})
 			if err != nil {
 				return err
 			}
 		} else {
 			// fallback direct repository if service missing
 			bizRec := &business.Business{WorkspaceID: ws.ID, Name: sess.BusinessName, Descriptor: sess.BusinessDescriptor, CountryCode: sess.BusinessCountry, Currency: sess.BusinessCurrency}
-			if err := s.storage.CreateBusiness(txCtx, bizRec); err != nil {
+			if err := s.storage.CreateBusiness(bizCtx, bizRec); err != nil {
 				return err
 			}
 		}
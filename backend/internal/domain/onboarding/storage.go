@@ -25,11 +25,14 @@ type Storage struct {
 }
 
 func NewStorage(db *database.Database, c *cache.Cache) *Storage {
+	// Sessions, workspaces and users live in the home region; the business is created in
+	// the region chosen during onboarding.
+	global := db.Global()
 	return &Storage{
-		session:   database.NewRepository[OnboardingSession](db),
+		session:   database.NewRepository[OnboardingSession](global),
 		cache:     c,
-		workspace: database.NewRepository[acc.Workspace](db),
-		user:      database.NewRepository[acc.User](db),
+		workspace: database.NewRepository[acc.Workspace](global),
+		user:      database.NewRepository[acc.User](global),
 		business:  database.NewRepository[biz.Business](db),
 	}
 }
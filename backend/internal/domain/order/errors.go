@@ -119,3 +119,238 @@ func ErrOrderNoteNotFound(orderNoteID string, err error) error {
 func ErrOrderRateLimited() error {
 	return problem.TooManyRequests("too many requests").WithCode("order.rate_limited")
 }
+
+// ErrOrderVersionConflict indicates the order changed since the client (or this request) loaded it.
+func ErrOrderVersionConflict(orderID string, err error) error {
+	return problem.Conflict("order was modified by another request").WithError(err).With("orderId", orderID).WithCode("order.version_conflict")
+}
+
+// ErrQuoteNotFound indicates that a quote with the given id doesn't exist (in this business)
+func ErrQuoteNotFound(quoteID string, err error) error {
+	return problem.NotFound("quote not found").WithError(err).With("quoteId", quoteID).WithCode("order.quote_not_found")
+}
+
+// ErrQuoteNotEditable indicates a quote can no longer be changed because it was sent or answered
+func ErrQuoteNotEditable(quoteID string, status QuoteStatus) error {
+	return problem.Conflict("only draft quotes can be edited").With("quoteId", quoteID).With("status", string(status)).WithCode("order.quote_not_editable")
+}
+
+func ErrQuoteStatusUpdateNotAllowed(quoteID string, from, to QuoteStatus) error {
+	return problem.Conflict(fmt.Sprintf("cannot update quote status from %s to %s", from, to)).
+		With("quoteId", quoteID).
+		With("fromStatus", string(from)).
+		With("toStatus", string(to)).
+		WithCode("order.quote_status_update_not_allowed")
+}
+
+// ErrQuoteValidUntilInPast indicates a quote cannot be valid until a date that already passed
+func ErrQuoteValidUntilInPast(validUntil string) error {
+	return problem.BadRequest("validUntil must be in the future").With("validUntil", validUntil).WithCode("order.quote_valid_until_in_past")
+}
+
+// ErrQuoteShippingAddressRequired indicates a quote needs a shipping address before it can become an order
+func ErrQuoteShippingAddressRequired(quoteID string) error {
+	return problem.BadRequest("shipping address is required to accept the quote").With("quoteId", quoteID).WithCode("order.quote_shipping_address_required")
+}
+
+func ErrQuoteCannotBeDeleted(quoteID string, status QuoteStatus) error {
+	return problem.Conflict("cannot delete quote in its current status").
+		With("quoteId", quoteID).
+		With("status", string(status)).
+		WithCode("order.quote_cannot_delete")
+}
+
+// ErrQuoteNumberGenerationFailed indicates that generating a unique quote number failed after retries
+func ErrQuoteNumberGenerationFailed(err error) error {
+	return problem.InternalError().WithError(err).With("reason", "failed to generate a unique quote number").WithCode("order.quote_number_generation_failed")
+}
+
+// ErrInvalidStatementPeriod indicates a malformed or inverted statement period
+func ErrInvalidStatementPeriod(field, value string) error {
+	return problem.BadRequest("invalid statement period").With("field", field).With("value", value).WithCode("order.invalid_statement_period")
+}
+
+// ErrPrintStationNotFound indicates that a print station does not exist in the business
+func ErrPrintStationNotFound(stationID string, err error) error {
+	return problem.NotFound("print station not found").WithError(err).With("stationId", stationID).WithCode("order.print_station_not_found")
+}
+
+// ErrPrintJobNotFound indicates that a print job does not exist on the station
+func ErrPrintJobNotFound(jobID string, err error) error {
+	return problem.NotFound("print job not found").WithError(err).With("jobId", jobID).WithCode("order.print_job_not_found")
+}
+
+// ErrCouponNotFound indicates that a coupon does not exist in the business
+func ErrCouponNotFound(couponID string, err error) error {
+	return problem.NotFound("coupon not found").WithError(err).With("couponId", couponID).WithCode("order.coupon_not_found")
+}
+
+// ErrCouponCodeTaken indicates that another coupon of the business already uses the code
+func ErrCouponCodeTaken(code string, err error) error {
+	return problem.Conflict("coupon code is already in use").WithError(err).With("code", code).WithCode("order.coupon_code_taken")
+}
+
+// ErrInvalidCoupon indicates an invalid coupon definition, such as a percent over 100
+func ErrInvalidCoupon(field, reason string) error {
+	return problem.BadRequest("invalid coupon").With("field", field).With("reason", reason).WithCode("order.invalid_coupon")
+}
+
+// ErrCouponNotApplicable indicates a coupon code that cannot be applied to the order
+func ErrCouponNotApplicable(code, reason string) error {
+	return problem.BadRequest("coupon cannot be applied to this order").With("code", code).With("reason", reason).WithCode("order.coupon_not_applicable")
+}
+
+// ErrCouponWithManualDiscount indicates an order combining a coupon with a manual discount
+func ErrCouponWithManualDiscount() error {
+	return problem.BadRequest("a coupon cannot be combined with a manual discount").WithCode("order.coupon_with_manual_discount")
+}
+
+// ErrOrderExportNotFound indicates that an order export does not exist in the business
+func ErrOrderExportNotFound(exportID string, err error) error {
+	return problem.NotFound("order export not found").WithError(err).With("exportId", exportID).WithCode("order.export_not_found")
+}
+
+// ErrOrderExportInProgress indicates that the business already has an export being written
+func ErrOrderExportInProgress(exportID string) error {
+	return problem.Conflict("another order export is in progress").With("exportId", exportID).WithCode("order.export_in_progress")
+}
+
+// ErrOrderExportTooLarge indicates filters matching more orders than one export can hold
+func ErrOrderExportTooLarge(count int64, limit int) error {
+	return problem.BadRequest("too many orders to export; narrow the filters").With("count", count).With("limit", limit).WithCode("order.export_too_large")
+}
+
+// ErrOrderExportNotReady indicates an export whose file is not available for download
+func ErrOrderExportNotReady(exportID string, status ExportStatus) error {
+	return problem.Conflict("order export is not ready").With("exportId", exportID).With("status", status).WithCode("order.export_not_ready")
+}
+
+// ErrOrderExportExpired indicates an export whose file was already removed
+func ErrOrderExportExpired(exportID string) error {
+	return problem.NotFound("order export has expired").With("exportId", exportID).WithCode("order.export_expired")
+}
+
+// ErrInvalidExportRange indicates an export date range whose end is before its start
+func ErrInvalidExportRange() error {
+	return problem.BadRequest("to must be after from").With("field", "to").WithCode("order.invalid_export_range")
+}
+
+// ErrOrderPaymentLinkNotAllowed indicates an order that cannot be paid online in its current state
+func ErrOrderPaymentLinkNotAllowed(orderID string, status OrderStatus, paymentStatus OrderPaymentStatus) error {
+	return problem.Conflict("a payment link can only be created for an unpaid, open order").With("orderId", orderID).With("status", status).With("paymentStatus", paymentStatus).WithCode("order.payment_link_not_allowed")
+}
+
+// ErrOrderPaymentLinkFailed indicates that Stripe rejected the checkout session
+func ErrOrderPaymentLinkFailed(err error) error {
+	return problem.InternalError().With("detail", "payment link creation failed").WithError(err).WithCode("order.payment_link_failed")
+}
+
+// ErrInvalidOrderTag indicates a tag that is empty, too long or has unsupported characters,
+// or too many tags on one order
+func ErrInvalidOrderTag(tag, reason string) error {
+	return problem.BadRequest("invalid order tag").With("tag", tag).With("reason", reason).WithCode("order.invalid_tag")
+}
+
+// ErrInvalidCustomField indicates a custom field with an invalid key or value, or too many fields
+func ErrInvalidCustomField(key, reason string) error {
+	return problem.BadRequest("invalid custom field").With("key", key).With("reason", reason).WithCode("order.invalid_custom_field")
+}
+
+// ErrOrderNotShippable indicates a shipping label requested for an order that is not waiting to ship
+func ErrOrderNotShippable(orderID string, status OrderStatus) error {
+	return problem.Conflict("shipping labels can only be bought for placed orders that have not shipped yet").With("orderId", orderID).With("status", status).WithCode("order.not_shippable")
+}
+
+// ErrRecurringOrderNotFound indicates that a recurring order with the given id doesn't exist (in this business)
+func ErrRecurringOrderNotFound(recurringOrderID string, err error) error {
+	return problem.NotFound("recurring order not found").WithError(err).With("recurringOrderId", recurringOrderID).WithCode("order.recurring_order_not_found")
+}
+
+// ErrRecurringOrderStatusUpdateNotAllowed indicates a pause or resume that the recurring order's status doesn't allow
+func ErrRecurringOrderStatusUpdateNotAllowed(recurringOrderID string, from, to RecurringOrderStatus) error {
+	return problem.Conflict(fmt.Sprintf("cannot update recurring order status from %s to %s", from, to)).
+		With("recurringOrderId", recurringOrderID).
+		With("fromStatus", string(from)).
+		With("toStatus", string(to)).
+		WithCode("order.recurring_order_status_update_not_allowed")
+}
+
+// ErrRecurringOrderInvalidDateRange indicates a recurring order whose end date is before its start date
+func ErrRecurringOrderInvalidDateRange() error {
+	return problem.BadRequest("endDate must be on or after startDate").With("field", "endDate").WithCode("order.recurring_order_invalid_date_range")
+}
+
+// ErrRecurringOrderNotActive indicates an order requested from a paused or ended recurring order
+func ErrRecurringOrderNotActive(recurringOrderID string, status RecurringOrderStatus) error {
+	return problem.Conflict("only active recurring orders can create orders").With("recurringOrderId", recurringOrderID).With("status", string(status)).WithCode("order.recurring_order_not_active")
+}
+
+// ErrRecurringOrderNextRunInPast indicates a recurring order rescheduled to a date that already passed
+func ErrRecurringOrderNextRunInPast(nextRunDate string) error {
+	return problem.BadRequest("nextRunDate cannot be in the past").With("nextRunDate", nextRunDate).WithCode("order.recurring_order_next_run_in_past")
+}
+
+// ErrOrderPaymentNotFound indicates that a payment with the given id doesn't exist on the order
+func ErrOrderPaymentNotFound(paymentID string, err error) error {
+	return problem.NotFound("order payment not found").WithError(err).With("paymentId", paymentID).WithCode("order.payment_not_found")
+}
+
+// ErrOrderPaymentNotAllowed indicates a payment recorded for a cancelled, returned or refunded order
+func ErrOrderPaymentNotAllowed(orderID string, status OrderStatus, paymentStatus OrderPaymentStatus) error {
+	return problem.Conflict("payments cannot be recorded for cancelled, returned or refunded orders").With("orderId", orderID).With("status", status).With("paymentStatus", paymentStatus).WithCode("order.payment_not_allowed")
+}
+
+// ErrOrderPaymentDeleteNotAllowed indicates a payment removed from an order that is already paid or refunded
+func ErrOrderPaymentDeleteNotAllowed(orderID string, paymentStatus OrderPaymentStatus) error {
+	return problem.Conflict("payments of paid or refunded orders cannot be deleted").With("orderId", orderID).With("paymentStatus", paymentStatus).WithCode("order.payment_delete_not_allowed")
+}
+
+// ErrOrderPaymentInFuture indicates a payment dated after the time it is recorded
+func ErrOrderPaymentInFuture() error {
+	return problem.BadRequest("paidAt cannot be in the future").With("field", "paidAt").WithCode("order.payment_in_future")
+}
+
+// ErrOrderDeliveryNotSchedulable indicates a delivery slot change for an order that already left or was closed
+func ErrOrderDeliveryNotSchedulable(orderID string, status OrderStatus) error {
+	return problem.Conflict("delivery can only be scheduled for orders that have not shipped yet").With("orderId", orderID).With("status", status).WithCode("order.delivery_not_schedulable")
+}
+
+// ErrInvalidDeliveryDate indicates a delivery date in the past or on a weekday the window is not offered
+func ErrInvalidDeliveryDate(date, reason string) error {
+	return problem.BadRequest("invalid delivery date").With("field", "date").With("date", date).With("reason", reason).WithCode("order.invalid_delivery_date")
+}
+
+// ErrDeliverySlotFull indicates a delivery window that has no capacity left on the date
+func ErrDeliverySlotFull(windowID, date string, capacity int) error {
+	return problem.Conflict("delivery slot is full").With("windowId", windowID).With("date", date).With("capacity", capacity).WithCode("order.delivery_slot_full")
+}
+
+// ErrInvalidDeliverySlotsRange indicates a delivery slots query whose end is before its start or that spans too many days
+func ErrInvalidDeliverySlotsRange(maxDays int) error {
+	return problem.BadRequest(fmt.Sprintf("to must be on or after from and at most %d days later", maxDays)).With("field", "to").WithCode("order.invalid_delivery_slots_range")
+}
+
+// ErrOrderTransitionRuleNotFound indicates an order transition rule that does not exist in the business
+func ErrOrderTransitionRuleNotFound(ruleID string, err error) error {
+	return problem.NotFound("order transition rule not found").WithError(err).With("ruleId", ruleID).WithCode("order.transition_rule_not_found")
+}
+
+// ErrInvalidOrderTransitionRule indicates a transition rule whose action does not fit its statuses or lacks its message
+func ErrInvalidOrderTransitionRule(field, reason string) error {
+	return problem.BadRequest("invalid order transition rule").With("field", field).With("reason", reason).WithCode("order.invalid_transition_rule")
+}
+
+// ErrOrderTransitionRuleExists indicates a rule that runs the same action on the same transitions as another rule
+func ErrOrderTransitionRuleExists(ruleID string) error {
+	return problem.Conflict("a rule with the same action and statuses already exists").With("ruleId", ruleID).WithCode("order.transition_rule_exists")
+}
+
+// ErrOrderDownloadsUnavailable indicates downloads requested for an order that is not paid yet
+func ErrOrderDownloadsUnavailable(orderID string, paymentStatus OrderPaymentStatus) error {
+	return problem.Conflict("downloads are available once the order is paid").With("orderId", orderID).With("paymentStatus", paymentStatus).WithCode("order.downloads_unavailable")
+}
+
+// ErrOrderTransitionRequiresPayment indicates a status change blocked by a require_payment rule of the business
+func ErrOrderTransitionRequiresPayment(orderID string, status OrderStatus, ruleID string) error {
+	return problem.Conflict("the order must be paid before this status change").With("orderId", orderID).With("status", status).With("ruleId", ruleID).WithCode("order.transition_requires_payment")
+}
@@ -0,0 +1,97 @@
+package order
+
+import (
+	"context"
+
+	"github.com/abdelrahman146/kyora/internal/platform/bus"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+)
+
+// BusHandler listens for order lifecycle events that feed order-side automation, runs
+// queued order exports and records payment link payments.
+type BusHandler struct {
+	svc *Service
+}
+
+// NewBusHandler registers order listeners on the event bus.
+func NewBusHandler(b *bus.Bus, svc *Service) {
+	h := &BusHandler{svc: svc}
+	b.Handle(bus.OrderPaidTopic, "order.queue_paid_prints", h.HandleOrderPaid)
+	b.Handle(bus.OrderPaidTopic, "order.deliver_downloads", h.HandleOrderPaidDownloads)
+	b.Handle(bus.OrderExportRequestedTopic, "order.run_export", h.HandleOrderExportRequested)
+	b.Handle(bus.OrderCheckoutCompletedTopic, "order.complete_checkout", h.HandleOrderCheckoutCompleted)
+}
+
+// HandleOrderPaid queues the paid order on the business's auto-printing stations. Malformed
+// events are logged and dropped; storage failures are returned so the bus retries them.
+func (h *BusHandler) HandleOrderPaid(event any) error {
+	e, ok := event.(*bus.OrderPaidEvent)
+	if !ok {
+		logger.FromContext(context.Background()).Error("invalid event type for OrderPaidEvent")
+		return nil
+	}
+	if e.BusinessID == "" || e.OrderID == "" {
+		logger.FromContext(e.Ctx).Error("missing required fields in OrderPaidEvent", "businessId", e.BusinessID, "orderId", e.OrderID)
+		return nil
+	}
+	if err := h.svc.QueuePaidOrderPrints(e.Ctx, e.BusinessID, e.OrderID); err != nil {
+		logger.FromContext(e.Ctx).Error("failed to queue paid order prints", "error", err, "businessId", e.BusinessID, "orderId", e.OrderID)
+		return err
+	}
+	return nil
+}
+
+// HandleOrderPaidDownloads emails the customer of the paid order the files of its digital
+// items.
+func (h *BusHandler) HandleOrderPaidDownloads(event any) error {
+	e, ok := event.(*bus.OrderPaidEvent)
+	if !ok {
+		logger.FromContext(context.Background()).Error("invalid event type for OrderPaidEvent")
+		return nil
+	}
+	if e.BusinessID == "" || e.OrderID == "" {
+		logger.FromContext(e.Ctx).Error("missing required fields in OrderPaidEvent", "businessId", e.BusinessID, "orderId", e.OrderID)
+		return nil
+	}
+	if err := h.svc.DeliverOrderDownloads(e.Ctx, e.BusinessID, e.OrderID); err != nil {
+		logger.FromContext(e.Ctx).Error("failed to deliver order downloads", "error", err, "businessId", e.BusinessID, "orderId", e.OrderID)
+		return err
+	}
+	return nil
+}
+
+// HandleOrderExportRequested writes the file of a queued order export.
+func (h *BusHandler) HandleOrderExportRequested(event any) error {
+	e, ok := event.(*bus.OrderExportRequestedEvent)
+	if !ok {
+		logger.FromContext(context.Background()).Error("invalid event type for OrderExportRequestedEvent")
+		return nil
+	}
+	if e.BusinessID == "" || e.ExportID == "" {
+		logger.FromContext(e.Ctx).Error("missing required fields in OrderExportRequestedEvent", "businessId", e.BusinessID, "exportId", e.ExportID)
+		return nil
+	}
+	if err := h.svc.RunOrderExport(e.Ctx, e.BusinessID, e.ExportID); err != nil {
+		logger.FromContext(e.Ctx).Error("failed to run order export", "error", err, "businessId", e.BusinessID, "exportId", e.ExportID)
+		return err
+	}
+	return nil
+}
+
+// HandleOrderCheckoutCompleted marks the order of a paid payment link as paid.
+func (h *BusHandler) HandleOrderCheckoutCompleted(event any) error {
+	e, ok := event.(*bus.OrderCheckoutCompletedEvent)
+	if !ok {
+		logger.FromContext(context.Background()).Error("invalid event type for OrderCheckoutCompletedEvent")
+		return nil
+	}
+	if e.BusinessID == "" || e.OrderID == "" {
+		logger.FromContext(e.Ctx).Error("missing required fields in OrderCheckoutCompletedEvent", "businessId", e.BusinessID, "orderId", e.OrderID)
+		return nil
+	}
+	if err := h.svc.CompleteOrderCheckout(e.Ctx, e.BusinessID, e.OrderID, e.CheckoutSessionID, e.PaymentIntentID); err != nil {
+		logger.FromContext(e.Ctx).Error("failed to complete order checkout", "error", err, "businessId", e.BusinessID, "orderId", e.OrderID)
+		return err
+	}
+	return nil
+}
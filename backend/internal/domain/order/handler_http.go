@@ -1,15 +1,20 @@
 package order
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/abdelrahman146/kyora/internal/domain/account"
 	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
 	"github.com/abdelrahman146/kyora/internal/platform/database"
 	"github.com/abdelrahman146/kyora/internal/platform/request"
 	"github.com/abdelrahman146/kyora/internal/platform/response"
 	"github.com/abdelrahman146/kyora/internal/platform/types/list"
 	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
 	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
 	"github.com/gin-gonic/gin"
 )
@@ -29,10 +34,34 @@ func (h *HttpHandler) getBusinessForRequest(c *gin.Context, actor *account.User)
 	return business.BusinessFromContext(c)
 }
 
+// canSeeFinancials reports whether the actor may see order costs (COGS and item costs).
+func canSeeFinancials(actor *account.User) bool {
+	return actor.HasPermission(role.ActionView, role.ResourceFinancials) == nil
+}
+
+// orderResponseFor renders ord for the actor, leaving out costs they may not see.
+func orderResponseFor(actor *account.User, ord *Order) OrderResponse {
+	resp := ToOrderResponse(ord)
+	if !canSeeFinancials(actor) {
+		resp.RedactFinancials()
+	}
+	return resp
+}
+
+func orderResponsesFor(actor *account.User, orders []*Order) []OrderResponse {
+	responses := ToOrderResponses(orders)
+	if !canSeeFinancials(actor) {
+		for i := range responses {
+			responses[i].RedactFinancials()
+		}
+	}
+	return responses
+}
+
 // ListOrders returns a paginated list of orders.
 //
 // @Summary      List orders
-// @Description  Returns a paginated list of orders for the authenticated business
+// @Description  Returns a paginated list of orders for the authenticated business. With view=summary each item is an order.OrderSummaryResponse instead.
 // @Tags         order
 // @Produce      json
 // @Param        businessDescriptor path string true "Business descriptor"
@@ -45,8 +74,10 @@ func (h *HttpHandler) getBusinessForRequest(c *gin.Context, actor *account.User)
 // @Param        socialPlatforms query []string false "Filter by platform/channel (instagram, tiktok, facebook, x, snapchat, whatsapp)"
 // @Param        customerId query string false "Filter by customerId"
 // @Param        orderNumber query string false "Filter by exact orderNumber"
+// @Param        tags query []string false "Filter by tags (repeatable; orders must carry all of them)"
 // @Param        from query string false "Filter by orderedAt >= from (RFC3339)"
 // @Param        to query string false "Filter by orderedAt <= to (RFC3339)"
+// @Param        view query string false "Response shape: full (default) or summary for a lean projection without items, notes or addresses"
 // @Success      200 {object} list.ListResponse[order.OrderResponse]
 // @Failure      400 {object} problem.Problem
 // @Failure      401 {object} problem.Problem
@@ -90,6 +121,7 @@ func (h *HttpHandler) ListOrders(c *gin.Context) {
 		Channels:    query.SocialPlatforms,
 		CustomerID:  query.CustomerID,
 		OrderNumber: query.OrderNumber,
+		Tags:        query.Tags,
 		From:        query.From,
 		To:          query.To,
 	}
@@ -100,12 +132,23 @@ func (h *HttpHandler) ListOrders(c *gin.Context) {
 		filters.PaymentStatuses = append(filters.PaymentStatuses, OrderPaymentStatus(ps))
 	}
 
+	if query.View == "summary" {
+		items, total, err := h.service.ListOrderSummaries(c.Request.Context(), actor, biz, listReq, filters)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		hasMore := int64(query.Page*query.PageSize) < total
+		response.SuccessJSON(c, http.StatusOK, list.NewListResponse(ToOrderSummaryResponses(items), query.Page, query.PageSize, total, hasMore))
+		return
+	}
+
 	items, total, err := h.service.ListOrders(c.Request.Context(), actor, biz, listReq, filters)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
-	respItems := ToOrderResponses(items)
+	respItems := orderResponsesFor(actor, items)
 	hasMore := int64(query.Page*query.PageSize) < total
 	response.SuccessJSON(c, http.StatusOK, list.NewListResponse(respItems, query.Page, query.PageSize, total, hasMore))
 }
@@ -151,7 +194,7 @@ func (h *HttpHandler) GetOrder(c *gin.Context) {
 		response.Error(c, err)
 		return
 	}
-	response.SuccessJSON(c, http.StatusOK, ToOrderResponse(ord))
+	response.SuccessJSON(c, http.StatusOK, orderResponseFor(actor, ord))
 }
 
 // GetOrderByNumber returns an order by its order number.
@@ -194,7 +237,7 @@ func (h *HttpHandler) GetOrderByNumber(c *gin.Context) {
 		response.Error(c, err)
 		return
 	}
-	response.SuccessJSON(c, http.StatusOK, ToOrderResponse(ord))
+	response.SuccessJSON(c, http.StatusOK, orderResponseFor(actor, ord))
 }
 
 // PreviewOrder validates an order payload and returns computed totals without creating the order.
@@ -240,7 +283,11 @@ func (h *HttpHandler) PreviewOrder(c *gin.Context) {
 		response.Error(c, err)
 		return
 	}
-	response.SuccessJSON(c, http.StatusOK, ToOrderPreviewResponse(preview))
+	resp := ToOrderPreviewResponse(preview)
+	if !canSeeFinancials(actor) {
+		resp.RedactFinancials()
+	}
+	response.SuccessJSON(c, http.StatusOK, resp)
 }
 
 // CreateOrder creates a new order.
@@ -289,7 +336,7 @@ func (h *HttpHandler) CreateOrder(c *gin.Context) {
 		response.Error(c, loadErr)
 		return
 	}
-	response.SuccessJSON(c, http.StatusCreated, ToOrderResponse(loaded))
+	response.SuccessJSON(c, http.StatusCreated, orderResponseFor(actor, loaded))
 }
 
 // UpdateOrder updates an order.
@@ -349,7 +396,7 @@ func (h *HttpHandler) UpdateOrder(c *gin.Context) {
 		response.Error(c, loadErr)
 		return
 	}
-	response.SuccessJSON(c, http.StatusOK, ToOrderResponse(loaded))
+	response.SuccessJSON(c, http.StatusOK, orderResponseFor(actor, loaded))
 }
 
 // DeleteOrder deletes an order (restricted to safe statuses) and restocks inventory.
@@ -392,10 +439,90 @@ func (h *HttpHandler) DeleteOrder(c *gin.Context) {
 	response.SuccessEmpty(c, http.StatusNoContent)
 }
 
+// ReconcileOrderTotals recomputes recent order totals from their items.
+//
+// @Summary      Reconcile order totals
+// @Description  Recomputes subtotal, VAT, COGS and total for recent orders from their items and reports mismatches. With repair=true the stored totals are overwritten with the recomputed ones.
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body reconcileOrderTotalsRequest false "Reconciliation options"
+// @Success      200 {object} order.ReconcileOrderTotalsResult
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/reconcile-totals [post]
+// @Security     BearerAuth
+func (h *HttpHandler) ReconcileOrderTotals(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req reconcileOrderTotalsRequest
+	if c.Request.ContentLength != 0 {
+		if err := request.ValidBody(c, &req); err != nil {
+			return
+		}
+	}
+	if req.SinceDays == 0 {
+		req.SinceDays = 30
+	}
+	result, err := h.service.ReconcileOrderTotals(c.Request.Context(), ReconcileOrderTotalsOptions{
+		BusinessID: biz.ID,
+		Since:      time.Now().UTC().AddDate(0, 0, -req.SinceDays),
+		Repair:     req.Repair,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, result)
+}
+
+// ExpirePendingOrders cancels the business' stale pending orders now.
+//
+// @Summary      Expire stale pending orders
+// @Description  Cancels the orders that stayed pending longer than the business' pendingOrderTtlHours and puts their items back in stock. The orders-expire-pending job does the same for every business; nothing expires while the TTL is 0.
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {object} order.ExpirePendingOrdersResult
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/expire-pending [post]
+// @Security     BearerAuth
+func (h *HttpHandler) ExpirePendingOrders(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	result, err := h.service.ExpirePendingOrders(c.Request.Context(), ExpirePendingOrdersOptions{BusinessID: biz.ID})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, result)
+}
+
 // UpdateOrderStatus updates order lifecycle status.
 //
 // @Summary      Update order status
-// @Description  Updates the order lifecycle status using the order state machine
+// @Description  Updates the order lifecycle status using the order state machine. Returns can put the items back in stock with restock=true.
 // @Tags         order
 // @Accept       json
 // @Produce      json
@@ -431,7 +558,12 @@ func (h *HttpHandler) UpdateOrderStatus(c *gin.Context) {
 	if err := request.ValidBody(c, &req); err != nil {
 		return
 	}
-	ord, err := h.service.UpdateOrderStatus(c.Request.Context(), actor, biz, orderID, req.Status)
+	var ord *Order
+	if req.Status == OrderStatusReturned {
+		ord, err = h.service.ReturnOrder(c.Request.Context(), actor, biz, orderID, req.Restock)
+	} else {
+		ord, err = h.service.UpdateOrderStatus(c.Request.Context(), actor, biz, orderID, req.Status)
+	}
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -441,7 +573,7 @@ func (h *HttpHandler) UpdateOrderStatus(c *gin.Context) {
 		response.Error(c, loadErr)
 		return
 	}
-	response.SuccessJSON(c, http.StatusOK, ToOrderResponse(loaded))
+	response.SuccessJSON(c, http.StatusOK, orderResponseFor(actor, loaded))
 }
 
 // UpdateOrderPaymentStatus updates order payment status.
@@ -493,7 +625,7 @@ func (h *HttpHandler) UpdateOrderPaymentStatus(c *gin.Context) {
 		response.Error(c, loadErr)
 		return
 	}
-	response.SuccessJSON(c, http.StatusOK, ToOrderResponse(loaded))
+	response.SuccessJSON(c, http.StatusOK, orderResponseFor(actor, loaded))
 }
 
 // AddOrderPaymentDetails sets payment method/reference without changing payment status.
@@ -549,7 +681,49 @@ func (h *HttpHandler) AddOrderPaymentDetails(c *gin.Context) {
 		response.Error(c, loadErr)
 		return
 	}
-	response.SuccessJSON(c, http.StatusOK, ToOrderResponse(loaded))
+	response.SuccessJSON(c, http.StatusOK, orderResponseFor(actor, loaded))
+}
+
+// CreateOrderPaymentLink creates a Stripe payment link for an unpaid order.
+//
+// @Summary      Create order payment link
+// @Description  Creates a Stripe Checkout link charging the order total, valid for 24 hours. Any previous link of the order is expired. The order becomes paid by card (and placed, if pending) when the customer pays.
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        orderId path string true "Order ID"
+// @Param        body body CreateOrderPaymentLinkRequest true "Redirect URLs"
+// @Success      201 {object} order.OrderResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/{orderId}/payment-link [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CreateOrderPaymentLink(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req CreateOrderPaymentLinkRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+	ord, err := h.service.CreateOrderPaymentLink(c.Request.Context(), actor, biz, c.Param("orderId"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, orderResponseFor(actor, ord))
 }
 
 // CreateOrderNote creates a note for an order.
@@ -696,3 +870,2554 @@ func (h *HttpHandler) DeleteOrderNote(c *gin.Context) {
 	}
 	response.SuccessEmpty(c, http.StatusNoContent)
 }
+
+// quoteNotFound maps a missing quote to the domain error.
+func quoteNotFound(quoteID string, err error) error {
+	if database.IsRecordNotFound(err) {
+		return ErrQuoteNotFound(quoteID, err)
+	}
+	return err
+}
+
+// ListQuotes returns a paginated list of quotes.
+//
+// @Summary      List quotes
+// @Description  Returns a paginated list of quotes for the business (expired quotes are marked automatically)
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        page query int false "Page number (default: 1)"
+// @Param        pageSize query int false "Page size (default: 20, max: 100)"
+// @Param        orderBy query []string false "Sort order (e.g., -createdAt, -validUntil, -total)"
+// @Param        status query []string false "Filter by status (draft, sent, accepted, declined, expired; repeatable)"
+// @Param        customerId query string false "Filter by customerId"
+// @Success      200 {object} list.ListResponse[order.QuoteResponse]
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/quotes [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListQuotes(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query listQuotesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+	listReq := list.NewListRequest(query.Page, query.PageSize, query.OrderBy, "")
+	filters := &ListQuotesFilters{CustomerID: query.CustomerID}
+	for _, s := range query.Status {
+		filters.Statuses = append(filters.Statuses, QuoteStatus(s))
+	}
+
+	items, total, err := h.service.ListQuotes(c.Request.Context(), actor, biz, listReq, filters)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	hasMore := int64(query.Page*query.PageSize) < total
+	response.SuccessJSON(c, http.StatusOK, list.NewListResponse(ToQuoteResponses(items), query.Page, query.PageSize, total, hasMore))
+}
+
+// GetQuote returns a quote by ID with items.
+//
+// @Summary      Get quote
+// @Description  Returns a quote by ID including items, customer and shipping address
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        quoteId path string true "Quote ID"
+// @Success      200 {object} order.QuoteResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/quotes/{quoteId} [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetQuote(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	quoteID := c.Param("quoteId")
+	if quoteID == "" {
+		response.Error(c, problem.BadRequest("quoteId is required"))
+		return
+	}
+	q, err := h.service.GetQuoteByID(c.Request.Context(), actor, biz, quoteID)
+	if err != nil {
+		response.Error(c, quoteNotFound(quoteID, err))
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToQuoteResponse(q))
+}
+
+// DownloadQuotePDF renders a quote as a PDF document.
+//
+// @Summary      Download quote PDF
+// @Description  Renders the quote as an A4 PDF suitable for sending to the customer
+// @Tags         order
+// @Produce      application/pdf
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        quoteId path string true "Quote ID"
+// @Success      200 {file} file
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/quotes/{quoteId}/pdf [get]
+// @Security     BearerAuth
+func (h *HttpHandler) DownloadQuotePDF(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	quoteID := c.Param("quoteId")
+	if quoteID == "" {
+		response.Error(c, problem.BadRequest("quoteId is required"))
+		return
+	}
+	q, err := h.service.GetQuoteByID(c.Request.Context(), actor, biz, quoteID)
+	if err != nil {
+		response.Error(c, quoteNotFound(quoteID, err))
+		return
+	}
+	response.SuccessFile(c, http.StatusOK, "application/pdf", "quote-"+q.QuoteNumber+".pdf", RenderQuotePDF(biz, q))
+}
+
+// CreateQuote creates a draft quote.
+//
+// @Summary      Create quote
+// @Description  Creates a draft quote for a customer; prices default to the variants' sale prices
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body CreateQuoteRequest true "Quote data"
+// @Success      201 {object} order.QuoteResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/quotes [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CreateQuote(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req CreateQuoteRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+	q, err := h.service.CreateQuote(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	loaded, err := h.service.GetQuoteByID(c.Request.Context(), actor, biz, q.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, ToQuoteResponse(loaded))
+}
+
+// UpdateQuote updates a draft quote.
+//
+// @Summary      Update quote
+// @Description  Updates a draft quote; items, when provided, replace the existing lines
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        quoteId path string true "Quote ID"
+// @Param        body body UpdateQuoteRequest true "Quote updates"
+// @Success      200 {object} order.QuoteResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/quotes/{quoteId} [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdateQuote(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	quoteID := c.Param("quoteId")
+	if quoteID == "" {
+		response.Error(c, problem.BadRequest("quoteId is required"))
+		return
+	}
+	var req UpdateQuoteRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+	if _, err := h.service.UpdateQuote(c.Request.Context(), actor, biz, quoteID, &req); err != nil {
+		response.Error(c, quoteNotFound(quoteID, err))
+		return
+	}
+	loaded, err := h.service.GetQuoteByID(c.Request.Context(), actor, biz, quoteID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToQuoteResponse(loaded))
+}
+
+// SendQuote marks a draft quote as sent to the customer.
+//
+// @Summary      Send quote
+// @Description  Marks a draft quote as sent; the content is frozen from this point
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        quoteId path string true "Quote ID"
+// @Success      200 {object} order.QuoteResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/quotes/{quoteId}/send [post]
+// @Security     BearerAuth
+func (h *HttpHandler) SendQuote(c *gin.Context) {
+	h.transitionQuote(c, h.service.SendQuote)
+}
+
+// DeclineQuote records that the customer declined a sent quote.
+//
+// @Summary      Decline quote
+// @Description  Marks a sent quote as declined by the customer
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        quoteId path string true "Quote ID"
+// @Success      200 {object} order.QuoteResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/quotes/{quoteId}/decline [post]
+// @Security     BearerAuth
+func (h *HttpHandler) DeclineQuote(c *gin.Context) {
+	h.transitionQuote(c, h.service.DeclineQuote)
+}
+
+func (h *HttpHandler) transitionQuote(c *gin.Context, transition func(context.Context, *account.User, *business.Business, string) (*Quote, error)) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	quoteID := c.Param("quoteId")
+	if quoteID == "" {
+		response.Error(c, problem.BadRequest("quoteId is required"))
+		return
+	}
+	if _, err := transition(c.Request.Context(), actor, biz, quoteID); err != nil {
+		response.Error(c, quoteNotFound(quoteID, err))
+		return
+	}
+	loaded, err := h.service.GetQuoteByID(c.Request.Context(), actor, biz, quoteID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToQuoteResponse(loaded))
+}
+
+// AcceptQuote converts a sent quote into an order.
+//
+// @Summary      Accept quote
+// @Description  Marks a sent quote as accepted and creates a pending order with the quoted items and prices
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        quoteId path string true "Quote ID"
+// @Param        body body AcceptQuoteRequest true "Order details"
+// @Success      201 {object} order.AcceptQuoteResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/quotes/{quoteId}/accept [post]
+// @Security     BearerAuth
+func (h *HttpHandler) AcceptQuote(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	quoteID := c.Param("quoteId")
+	if quoteID == "" {
+		response.Error(c, problem.BadRequest("quoteId is required"))
+		return
+	}
+	var req AcceptQuoteRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+	_, ord, err := h.service.AcceptQuote(c.Request.Context(), actor, biz, quoteID, &req)
+	if err != nil {
+		response.Error(c, quoteNotFound(quoteID, err))
+		return
+	}
+	loadedQuote, err := h.service.GetQuoteByID(c.Request.Context(), actor, biz, quoteID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	loadedOrder, err := h.service.GetOrderByID(c.Request.Context(), actor, biz, ord.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, AcceptQuoteResponse{
+		Quote: ToQuoteResponse(loadedQuote),
+		Order: orderResponseFor(actor, loadedOrder),
+	})
+}
+
+// DeleteQuote deletes a quote that was not accepted.
+//
+// @Summary      Delete quote
+// @Description  Deletes a quote; accepted quotes are kept as the origin of their order
+// @Tags         order
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        quoteId path string true "Quote ID"
+// @Success      204
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/quotes/{quoteId} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteQuote(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	quoteID := c.Param("quoteId")
+	if quoteID == "" {
+		response.Error(c, problem.BadRequest("quoteId is required"))
+		return
+	}
+	if err := h.service.DeleteQuote(c.Request.Context(), actor, biz, quoteID); err != nil {
+		response.Error(c, quoteNotFound(quoteID, err))
+		return
+	}
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// GetCustomerStatement returns a customer's statement over a period.
+//
+// @Summary      Get customer statement
+// @Description  Lists a customer's orders, payments, refunds and cancellations/returns over a period with opening and closing balance. format=csv or format=pdf downloads the statement instead of returning JSON.
+// @Tags         order
+// @Produce      json
+// @Produce      text/csv
+// @Produce      application/pdf
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        customerId path string true "Customer ID"
+// @Param        from query string false "Start date (YYYY-MM-DD, defaults to the first day of the current month)"
+// @Param        to query string false "End date, inclusive (YYYY-MM-DD, defaults to today)"
+// @Param        format query string false "json (default), csv or pdf"
+// @Success      200 {object} order.CustomerStatementResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/customers/{customerId}/statement [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetCustomerStatement(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query customerStatementQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	from, to, err := statementPeriod(query.From, query.To, biz.Location())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	customerID := c.Param("customerId")
+	st, err := h.service.GetCustomerStatement(c.Request.Context(), actor, biz, customerID, from, to)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, customer.ErrCustomerNotFound(err).With("customerId", customerID))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+
+	filename := "statement-" + st.Customer.ID + "-" + from.Format(statementDateLayout) + "-" + to.Format(statementDateLayout)
+	switch query.Format {
+	case "csv":
+		response.SuccessFile(c, http.StatusOK, "text/csv", filename+".csv", RenderCustomerStatementCSV(biz, st))
+	case "pdf":
+		response.SuccessFile(c, http.StatusOK, "application/pdf", filename+".pdf", RenderCustomerStatementPDF(biz, st))
+	default:
+		response.SuccessJSON(c, http.StatusOK, ToCustomerStatementResponse(st))
+	}
+}
+
+// GetCustomerStats returns a customer's purchase stats.
+//
+// @Summary      Get customer stats
+// @Description  Returns the lifetime value, order count, average order value, first and last order dates, preferred channel and favorite products of a customer. Cancelled and returned orders are left out.
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        customerId path string true "Customer ID"
+// @Success      200 {object} order.CustomerStatsResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/customers/{customerId}/stats [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetCustomerStats(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	customerID := c.Param("customerId")
+	stats, err := h.service.GetCustomerStats(c.Request.Context(), actor, biz, customerID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, customer.ErrCustomerNotFound(err).With("customerId", customerID))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToCustomerStatsResponse(stats))
+}
+
+// GetCustomerTimeline returns a customer's activity timeline.
+//
+// @Summary      Get customer timeline
+// @Description  Returns a customer's activity, newest first, as one paginated feed: orders placed, order status changes, payments, notes, and addresses added, updated or removed.
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        customerId path string true "Customer ID"
+// @Param        page query int false "Page number (default: 1)"
+// @Param        pageSize query int false "Page size (default: 20, max: 100)"
+// @Success      200 {object} list.ListResponse[order.CustomerTimelineEntryResponse]
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/customers/{customerId}/timeline [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetCustomerTimeline(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query customerTimelineQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+	customerID := c.Param("customerId")
+	listReq := list.NewListRequest(query.Page, query.PageSize, nil, "")
+	rows, total, err := h.service.ListCustomerTimeline(c.Request.Context(), actor, biz, customerID, listReq)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, customer.ErrCustomerNotFound(err).With("customerId", customerID))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	hasMore := int64(query.Page*query.PageSize) < total
+	response.SuccessJSON(c, http.StatusOK, list.NewListResponse(ToCustomerTimelineResponses(rows), query.Page, query.PageSize, total, hasMore))
+}
+
+// ExportCustomerData downloads everything stored about a customer.
+//
+// @Summary      Export customer data
+// @Description  Downloads a JSON bundle of everything the business stores about a customer, for a data access request: the profile, addresses, notes, and the customer's orders (with items, payments and notes), quotes and recurring orders.
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        customerId path string true "Customer ID"
+// @Success      200 {object} order.CustomerDataExportResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/customers/{customerId}/data-export [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ExportCustomerData(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	customerID := c.Param("customerId")
+	export, err := h.service.ExportCustomerData(c.Request.Context(), actor, biz, customerID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, customer.ErrCustomerNotFound(err).With("customerId", customerID))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	data, err := json.Marshal(ToCustomerDataExportResponse(export))
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+	response.SuccessFile(c, http.StatusOK, "application/json", "customer-"+export.Customer.ID+"-data.json", data)
+}
+
+// EraseCustomerData anonymizes a customer's personal data.
+//
+// @Summary      Erase customer data
+// @Description  Erases the personal data of a customer on their request. The name is replaced, contact details and social handles are cleared, addresses lose their street, zip code and phone number, customer and order notes are deleted and recurring orders end. Orders keep their items, totals and payments, so revenue and reports are unchanged. This cannot be undone.
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        customerId path string true "Customer ID"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/customers/{customerId}/erase [post]
+// @Security     BearerAuth
+func (h *HttpHandler) EraseCustomerData(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	customerID := c.Param("customerId")
+	if _, err := h.service.EraseCustomerData(c.Request.Context(), actor, biz, customerID); err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, customer.ErrCustomerNotFound(err).With("customerId", customerID))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+const statementDateLayout = "2006-01-02"
+
+// statementPeriod resolves the statement dates to [local midnight of from, end of the to day],
+// defaulting to the current month so far.
+func statementPeriod(fromParam, toParam string, loc *time.Location) (time.Time, time.Time, error) {
+	now := time.Now().In(loc)
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if fromParam != "" {
+		t, err := time.ParseInLocation(statementDateLayout, fromParam, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidStatementPeriod("from", fromParam)
+		}
+		from = t
+	}
+	if toParam != "" {
+		t, err := time.ParseInLocation(statementDateLayout, toParam, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidStatementPeriod("to", toParam)
+		}
+		to = t
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, ErrInvalidStatementPeriod("to", toParam)
+	}
+	return from, to.AddDate(0, 0, 1).Add(-time.Microsecond), nil
+}
+
+func (h *HttpHandler) respondPrintDocument(c *gin.Context, ord *Order, kind PrintKind, format PrintFormat, data []byte) {
+	if format == PrintFormatHTML {
+		response.SuccessData(c, http.StatusOK, PrintContentType(format), data)
+		return
+	}
+	response.SuccessFile(c, http.StatusOK, PrintContentType(format), PrintFileName(ord, kind, format), data)
+}
+
+// PrintOrder renders an order receipt or pick ticket for an 80mm thermal printer.
+//
+// @Summary      Print order
+// @Description  Renders an order as an 80mm receipt (with prices) or pick ticket (items, SKUs and shipping address), as raw ESC/POS or compact HTML
+// @Tags         order
+// @Produce      octet-stream
+// @Produce      html
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        orderId path string true "Order ID"
+// @Param        kind query string false "receipt (default) or pick_ticket"
+// @Param        format query string false "escpos (default) or html"
+// @Success      200 {file} file
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/{orderId}/print [get]
+// @Security     BearerAuth
+func (h *HttpHandler) PrintOrder(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query printDocumentQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	orderID := c.Param("orderId")
+	ord, err := h.service.GetOrderByID(c.Request.Context(), actor, biz, orderID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrOrderNotFound(orderID, err))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	kind, format := PrintKind(query.Kind), PrintFormat(query.Format)
+	if kind == "" {
+		kind = PrintKindReceipt
+	}
+	if format == "" {
+		format = PrintFormatESCPOS
+	}
+	h.respondPrintDocument(c, ord, kind, format, RenderPrintDocument(biz, ord, kind, format))
+}
+
+// DownloadInvoicePDF renders the invoice of an order as a PDF document.
+//
+// @Summary      Download order invoice
+// @Description  Renders the order as an A4 invoice PDF with the business logo, VAT breakdown, shipping and payment reference
+// @Tags         order
+// @Produce      application/pdf
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        orderId path string true "Order ID"
+// @Success      200 {file} file
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/{orderId}/invoice.pdf [get]
+// @Security     BearerAuth
+func (h *HttpHandler) DownloadInvoicePDF(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	orderID := c.Param("orderId")
+	ord, content, err := h.service.RenderInvoice(c.Request.Context(), actor, biz, orderID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			response.Error(c, ErrOrderNotFound(orderID, err))
+			return
+		}
+		response.Error(c, err)
+		return
+	}
+	response.SuccessFile(c, http.StatusOK, "application/pdf", "invoice-"+ord.OrderNumber+".pdf", content)
+}
+
+// QueueOrderPrint queues an order on a print station.
+//
+// @Summary      Queue order print
+// @Description  Queues an order on a print station, e.g. to print an unpaid order or reprint one. Kind defaults to the station's kind.
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        orderId path string true "Order ID"
+// @Param        body body QueueOrderPrintRequest true "Station and document"
+// @Success      201 {object} order.PrintJobResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/{orderId}/print-jobs [post]
+// @Security     BearerAuth
+func (h *HttpHandler) QueueOrderPrint(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req QueueOrderPrintRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	job, err := h.service.QueueOrderPrint(c.Request.Context(), actor, biz, c.Param("orderId"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, ToPrintJobResponse(job))
+}
+
+// ListPrintStations returns the business's print stations.
+//
+// @Summary      List print stations
+// @Description  Returns the business's print stations ordered by name, with the time each last polled for jobs
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {array} order.PrintStationResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/print-stations [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListPrintStations(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	stations, err := h.service.ListPrintStations(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToPrintStationResponses(stations))
+}
+
+// CreatePrintStation registers a print station.
+//
+// @Summary      Create print station
+// @Description  Registers a printing device. With autoQueuePaid (default true) every order that becomes paid is queued on it.
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body CreatePrintStationRequest true "Station"
+// @Success      201 {object} order.PrintStationResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/print-stations [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CreatePrintStation(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req CreatePrintStationRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	station, err := h.service.CreatePrintStation(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, ToPrintStationResponse(station))
+}
+
+// UpdatePrintStation updates a print station.
+//
+// @Summary      Update print station
+// @Description  Updates a print station's name, document kind, format or automatic queueing
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        stationId path string true "Station ID"
+// @Param        body body UpdatePrintStationRequest true "Station changes"
+// @Success      200 {object} order.PrintStationResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/print-stations/{stationId} [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdatePrintStation(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req UpdatePrintStationRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	station, err := h.service.UpdatePrintStation(c.Request.Context(), actor, biz, c.Param("stationId"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToPrintStationResponse(station))
+}
+
+// DeletePrintStation deletes a print station and its jobs.
+//
+// @Summary      Delete print station
+// @Description  Deletes a print station together with its queued and printed jobs
+// @Tags         order
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        stationId path string true "Station ID"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/print-stations/{stationId} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeletePrintStation(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.service.DeletePrintStation(c.Request.Context(), actor, biz, c.Param("stationId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// PollPrintJobs returns the jobs a station still has to print.
+//
+// @Summary      Poll print jobs
+// @Description  Returns up to 50 queued jobs of the station, oldest first, and records the poll time. Stations fetch each job's content and acknowledge it once printed.
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        stationId path string true "Station ID"
+// @Success      200 {array} order.PrintJobResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/print-stations/{stationId}/jobs [get]
+// @Security     BearerAuth
+func (h *HttpHandler) PollPrintJobs(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	jobs, err := h.service.PollPrintJobs(c.Request.Context(), actor, biz, c.Param("stationId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToPrintJobResponses(jobs))
+}
+
+// GetPrintJobContent renders a print job's document.
+//
+// @Summary      Get print job content
+// @Description  Renders the job's receipt or pick ticket in the station's format, unless format overrides it
+// @Tags         order
+// @Produce      octet-stream
+// @Produce      html
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        stationId path string true "Station ID"
+// @Param        jobId path string true "Job ID"
+// @Param        format query string false "escpos or html (default: the station's format)"
+// @Success      200 {file} file
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/print-stations/{stationId}/jobs/{jobId}/content [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetPrintJobContent(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query printDocumentQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	ord, job, format, data, err := h.service.RenderPrintJob(c.Request.Context(), actor, biz, c.Param("stationId"), c.Param("jobId"), PrintFormat(query.Format))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	h.respondPrintDocument(c, ord, job.Kind, format, data)
+}
+
+// AckPrintJob marks a print job as printed.
+//
+// @Summary      Acknowledge print job
+// @Description  Marks the job as printed so it is no longer returned by polls. Acknowledging twice is a no-op.
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        stationId path string true "Station ID"
+// @Param        jobId path string true "Job ID"
+// @Success      200 {object} order.PrintJobResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/print-stations/{stationId}/jobs/{jobId}/ack [post]
+// @Security     BearerAuth
+func (h *HttpHandler) AckPrintJob(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	job, err := h.service.AckPrintJob(c.Request.Context(), actor, biz, c.Param("stationId"), c.Param("jobId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToPrintJobResponse(job))
+}
+
+// ListCoupons returns the business's coupons.
+//
+// @Summary      List coupons
+// @Description  Returns the business's coupon codes, newest first, with how often each was used
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {array} order.CouponResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/coupons [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListCoupons(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	coupons, err := h.service.ListCoupons(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToCouponResponses(coupons))
+}
+
+// GetCoupon returns a coupon by ID.
+//
+// @Summary      Get coupon
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        couponId path string true "Coupon ID"
+// @Success      200 {object} order.CouponResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/coupons/{couponId} [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetCoupon(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	coupon, err := h.service.GetCouponByID(c.Request.Context(), actor, biz, c.Param("couponId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToCouponResponse(coupon))
+}
+
+// CreateCoupon creates a coupon code.
+//
+// @Summary      Create coupon
+// @Description  Creates a percent or fixed-amount discount code. Codes are case-insensitive and stored upper-cased; limits of zero mean unlimited.
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body CreateCouponRequest true "Coupon"
+// @Success      201 {object} order.CouponResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/coupons [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CreateCoupon(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req CreateCouponRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	coupon, err := h.service.CreateCoupon(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, ToCouponResponse(coupon))
+}
+
+// UpdateCoupon updates a coupon.
+//
+// @Summary      Update coupon
+// @Description  Updates a coupon's discount, limits, validity window or active flag. Existing orders keep the discount they were given.
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        couponId path string true "Coupon ID"
+// @Param        body body UpdateCouponRequest true "Coupon changes"
+// @Success      200 {object} order.CouponResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/coupons/{couponId} [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdateCoupon(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req UpdateCouponRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	coupon, err := h.service.UpdateCoupon(c.Request.Context(), actor, biz, c.Param("couponId"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToCouponResponse(coupon))
+}
+
+// DeleteCoupon deletes a coupon.
+//
+// @Summary      Delete coupon
+// @Description  Deletes a coupon. Orders that used it keep their discount and code.
+// @Tags         order
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        couponId path string true "Coupon ID"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/coupons/{couponId} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteCoupon(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.service.DeleteCoupon(c.Request.Context(), actor, biz, c.Param("couponId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// RequestOrderExport queues an export of the business's orders.
+//
+// @Summary      Export orders
+// @Description  Queues a background export of the orders matching the filters (status, payment status, orderedAt range) to CSV or XLSX. Poll the export until it is completed, then download it from downloadUrl. One export runs per business at a time; files are kept for 7 days.
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body CreateOrderExportRequest true "Export filters"
+// @Success      202 {object} order.OrderExportResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/export [post]
+// @Security     BearerAuth
+func (h *HttpHandler) RequestOrderExport(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req CreateOrderExportRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	export, err := h.service.RequestOrderExport(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusAccepted, ToOrderExportResponse(biz, export))
+}
+
+// ListOrderExports returns the business's recent order exports.
+//
+// @Summary      List order exports
+// @Description  Returns the business's 20 most recent order exports, newest first
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {array} order.OrderExportResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/exports [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListOrderExports(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	exports, err := h.service.ListOrderExports(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToOrderExportResponses(biz, exports))
+}
+
+// GetOrderExport returns an order export by ID.
+//
+// @Summary      Get order export
+// @Description  Returns the export's status; completed exports include downloadUrl
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        exportId path string true "Export ID"
+// @Success      200 {object} order.OrderExportResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/exports/{exportId} [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetOrderExport(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	export, err := h.service.GetOrderExport(c.Request.Context(), actor, biz, c.Param("exportId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToOrderExportResponse(biz, export))
+}
+
+// DownloadOrderExport downloads the file of a completed order export.
+//
+// @Summary      Download order export
+// @Tags         order
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        exportId path string true "Export ID"
+// @Success      200 {file} file
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/exports/{exportId}/download [get]
+// @Security     BearerAuth
+func (h *HttpHandler) DownloadOrderExport(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	export, content, err := h.service.ReadOrderExportFile(c.Request.Context(), actor, biz, c.Param("exportId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessFile(c, http.StatusOK, export.Format.ContentType(), export.FileName(), content)
+}
+
+// GetOrderTimeline returns the activity timeline of an order.
+//
+// @Summary      Get order timeline
+// @Description  Returns every recorded change of the order (creation, edits, status and payment changes, notes), oldest first
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        orderId path string true "Order ID"
+// @Success      200 {array} order.OrderEventResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/{orderId}/timeline [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetOrderTimeline(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	events, err := h.service.ListOrderTimeline(c.Request.Context(), actor, biz, c.Param("orderId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToOrderEventResponses(events))
+}
+
+// GetOrderDownloads returns the files of the order's digital items.
+//
+// @Summary      Get order downloads
+// @Description  Returns the files delivered for the digital items of the order. Files are available once the order is paid.
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        orderId path string true "Order ID"
+// @Success      200 {array} order.OrderDownloadResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/{orderId}/downloads [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetOrderDownloads(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	items, err := h.service.GetOrderDownloads(c.Request.Context(), actor, biz, c.Param("orderId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToOrderDownloadResponses(items))
+}
+
+// ListOrderTags returns the tags in use by the business' orders.
+//
+// @Summary      List order tags
+// @Description  Returns the distinct tags of the business' orders with the number of orders carrying each, most used first
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {array} order.OrderTagResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/tags [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListOrderTags(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	tags, err := h.service.ListOrderTags(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToOrderTagResponses(tags))
+}
+
+// SetOrderTags replaces the tags of an order.
+//
+// @Summary      Set order tags
+// @Description  Replaces the tags of an order. Tags are lower-cased and deduplicated; an empty list clears them
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        orderId path string true "Order ID"
+// @Param        body body SetOrderTagsRequest true "Tags"
+// @Success      200 {object} order.OrderResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/{orderId}/tags [put]
+// @Security     BearerAuth
+func (h *HttpHandler) SetOrderTags(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req SetOrderTagsRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+	ord, err := h.service.SetOrderTags(c.Request.Context(), actor, biz, c.Param("orderId"), req.Tags)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, orderResponseFor(actor, ord))
+}
+
+// RenameOrderTag renames a tag on every order of the business.
+//
+// @Summary      Rename order tag
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        tag path string true "Tag"
+// @Param        body body RenameOrderTagRequest true "New name"
+// @Success      200 {object} order.RetagOrdersResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/tags/{tag} [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) RenameOrderTag(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req RenameOrderTagRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+	updated, err := h.service.RenameOrderTag(c.Request.Context(), actor, biz, c.Param("tag"), req.Name)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, RetagOrdersResponse{Updated: updated})
+}
+
+// DeleteOrderTag removes a tag from every order of the business.
+//
+// @Summary      Delete order tag
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        tag path string true "Tag"
+// @Success      200 {object} order.RetagOrdersResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/tags/{tag} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteOrderTag(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	updated, err := h.service.DeleteOrderTag(c.Request.Context(), actor, biz, c.Param("tag"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, RetagOrdersResponse{Updated: updated})
+}
+
+// GetOrderShippingRates quotes carrier rates for shipping an order.
+//
+// @Summary      Get order shipping rates
+// @Description  Asks the configured carriers (or only the given one) to price shipping the order from the business to its shipping address
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        orderId path string true "Order ID"
+// @Param        body body OrderShippingRatesRequest true "Parcel and pickup address"
+// @Success      200 {array} business.CarrierRateResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      422 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/{orderId}/shipping-rates [post]
+// @Security     BearerAuth
+func (h *HttpHandler) GetOrderShippingRates(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req OrderShippingRatesRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+	rates, err := h.service.QuoteOrderShipping(c.Request.Context(), actor, biz, c.Param("orderId"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, business.ToCarrierRateResponses(rates))
+}
+
+// PurchaseOrderShippingLabel buys a shipping label for an order.
+//
+// @Summary      Purchase order shipping label
+// @Description  Books the shipment with a carrier service returned by the rates endpoint and stores the label PDF and tracking number
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        orderId path string true "Order ID"
+// @Param        body body PurchaseOrderShippingLabelRequest true "Carrier service, parcel and pickup address"
+// @Success      201 {object} business.ShippingLabelResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      422 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/{orderId}/shipping-labels [post]
+// @Security     BearerAuth
+func (h *HttpHandler) PurchaseOrderShippingLabel(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req PurchaseOrderShippingLabelRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+	label, err := h.service.PurchaseOrderShippingLabel(c.Request.Context(), actor, biz, c.Param("orderId"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, business.ToShippingLabelResponse(label))
+}
+
+// ListOrderShippingLabels returns the shipping labels bought for an order.
+//
+// @Summary      List order shipping labels
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        orderId path string true "Order ID"
+// @Success      200 {array} business.ShippingLabelResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/{orderId}/shipping-labels [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListOrderShippingLabels(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	labels, err := h.service.ListOrderShippingLabels(c.Request.Context(), actor, biz, c.Param("orderId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, business.ToShippingLabelResponses(labels))
+}
+
+// DownloadOrderShippingLabel returns the PDF of a shipping label.
+//
+// @Summary      Download order shipping label
+// @Tags         order
+// @Produce      application/pdf
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        orderId path string true "Order ID"
+// @Param        labelId path string true "Shipping label ID"
+// @Success      200 {file} file
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/{orderId}/shipping-labels/{labelId}/label.pdf [get]
+// @Security     BearerAuth
+func (h *HttpHandler) DownloadOrderShippingLabel(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	label, err := h.service.GetOrderShippingLabel(c.Request.Context(), actor, biz, c.Param("orderId"), c.Param("labelId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessFile(c, http.StatusOK, "application/pdf", "label-"+label.TrackingNumber+".pdf", label.LabelPDF)
+}
+
+// ListOrderPayments returns the payments recorded for an order.
+//
+// @Summary      List order payments
+// @Description  Returns the deposits, instalments and balance payments recorded for an order, oldest first
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        orderId path string true "Order ID"
+// @Success      200 {array} order.OrderPaymentResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/{orderId}/payments [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListOrderPayments(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	payments, err := h.service.ListOrderPayments(c.Request.Context(), actor, biz, c.Param("orderId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToOrderPaymentResponses(payments))
+}
+
+// RecordOrderPayment records a payment received for an order.
+//
+// @Summary      Record order payment
+// @Description  Records a deposit, an instalment or the balance of an order. The order becomes paid once its payments cover the total of a placed order.
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        orderId path string true "Order ID"
+// @Param        body body RecordOrderPaymentRequest true "Payment"
+// @Success      201 {object} order.OrderResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/{orderId}/payments [post]
+// @Security     BearerAuth
+func (h *HttpHandler) RecordOrderPayment(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req RecordOrderPaymentRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+	ord, err := h.service.RecordOrderPayment(c.Request.Context(), actor, biz, c.Param("orderId"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	loaded, err := h.service.GetOrderByID(c.Request.Context(), actor, biz, ord.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, orderResponseFor(actor, loaded))
+}
+
+// DeleteOrderPayment removes a payment recorded by mistake.
+//
+// @Summary      Delete order payment
+// @Description  Removes a payment from an order that is not paid or refunded yet
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        orderId path string true "Order ID"
+// @Param        paymentId path string true "Payment ID"
+// @Success      200 {object} order.OrderResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/{orderId}/payments/{paymentId} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteOrderPayment(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	ord, err := h.service.DeleteOrderPayment(c.Request.Context(), actor, biz, c.Param("orderId"), c.Param("paymentId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	loaded, err := h.service.GetOrderByID(c.Request.Context(), actor, biz, ord.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, orderResponseFor(actor, loaded))
+}
+
+// recurringOrderNotFound maps a missing recurring order to the domain error.
+func recurringOrderNotFound(recurringOrderID string, err error) error {
+	if database.IsRecordNotFound(err) {
+		return ErrRecurringOrderNotFound(recurringOrderID, err)
+	}
+	return err
+}
+
+// ListRecurringOrders returns a paginated list of recurring orders.
+//
+// @Summary      List recurring orders
+// @Description  Returns the business' recurring orders (product subscriptions), soonest next run first unless ordered otherwise
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        page query int false "Page number (default: 1)"
+// @Param        pageSize query int false "Page size (default: 20, max: 100)"
+// @Param        orderBy query []string false "Sort order (e.g., nextRunDate, -createdAt)"
+// @Param        status query []string false "Filter by status (active, paused, ended; repeatable)"
+// @Param        customerId query string false "Filter by customerId"
+// @Success      200 {object} list.ListResponse[order.RecurringOrderResponse]
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/recurring-orders [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListRecurringOrders(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query listRecurringOrdersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+	listReq := list.NewListRequest(query.Page, query.PageSize, query.OrderBy, "")
+	filters := &ListRecurringOrdersFilters{CustomerID: query.CustomerID}
+	for _, s := range query.Status {
+		filters.Statuses = append(filters.Statuses, RecurringOrderStatus(s))
+	}
+
+	items, total, err := h.service.ListRecurringOrders(c.Request.Context(), actor, biz, listReq, filters)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	hasMore := int64(query.Page*query.PageSize) < total
+	response.SuccessJSON(c, http.StatusOK, list.NewListResponse(ToRecurringOrderResponses(items), query.Page, query.PageSize, total, hasMore))
+}
+
+// GetRecurringOrder returns a recurring order by ID.
+//
+// @Summary      Get recurring order
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        recurringOrderId path string true "Recurring order ID"
+// @Success      200 {object} order.RecurringOrderResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/recurring-orders/{recurringOrderId} [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetRecurringOrder(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	recurringOrderID := c.Param("recurringOrderId")
+	ro, err := h.service.GetRecurringOrderByID(c.Request.Context(), actor, biz, recurringOrderID)
+	if err != nil {
+		response.Error(c, recurringOrderNotFound(recurringOrderID, err))
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToRecurringOrderResponse(ro))
+}
+
+// CreateRecurringOrder sets up a recurring order.
+//
+// @Summary      Create recurring order
+// @Description  Sets up a product subscription that creates a pending order for the customer every week or month, starting on startDate (default: today)
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body CreateRecurringOrderRequest true "Recurring order"
+// @Success      201 {object} order.RecurringOrderResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/recurring-orders [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CreateRecurringOrder(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req CreateRecurringOrderRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+	ro, err := h.service.CreateRecurringOrder(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	loaded, err := h.service.GetRecurringOrderByID(c.Request.Context(), actor, biz, ro.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, ToRecurringOrderResponse(loaded))
+}
+
+// UpdateRecurringOrder updates a recurring order.
+//
+// @Summary      Update recurring order
+// @Description  Updates the template and schedule of a recurring order; items, when provided, replace the existing lines
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        recurringOrderId path string true "Recurring order ID"
+// @Param        body body UpdateRecurringOrderRequest true "Recurring order updates"
+// @Success      200 {object} order.RecurringOrderResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/recurring-orders/{recurringOrderId} [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdateRecurringOrder(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	recurringOrderID := c.Param("recurringOrderId")
+	var req UpdateRecurringOrderRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+	if _, err := h.service.UpdateRecurringOrder(c.Request.Context(), actor, biz, recurringOrderID, &req); err != nil {
+		response.Error(c, recurringOrderNotFound(recurringOrderID, err))
+		return
+	}
+	loaded, err := h.service.GetRecurringOrderByID(c.Request.Context(), actor, biz, recurringOrderID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToRecurringOrderResponse(loaded))
+}
+
+// DeleteRecurringOrder deletes a recurring order.
+//
+// @Summary      Delete recurring order
+// @Description  Deletes a recurring order; orders it already created are kept
+// @Tags         order
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        recurringOrderId path string true "Recurring order ID"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/recurring-orders/{recurringOrderId} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteRecurringOrder(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	recurringOrderID := c.Param("recurringOrderId")
+	if err := h.service.DeleteRecurringOrder(c.Request.Context(), actor, biz, recurringOrderID); err != nil {
+		response.Error(c, recurringOrderNotFound(recurringOrderID, err))
+		return
+	}
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// PauseRecurringOrder pauses an active recurring order.
+//
+// @Summary      Pause recurring order
+// @Description  Stops an active recurring order from creating orders until it is resumed
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        recurringOrderId path string true "Recurring order ID"
+// @Success      200 {object} order.RecurringOrderResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/recurring-orders/{recurringOrderId}/pause [post]
+// @Security     BearerAuth
+func (h *HttpHandler) PauseRecurringOrder(c *gin.Context) {
+	h.transitionRecurringOrder(c, h.service.PauseRecurringOrder)
+}
+
+// ResumeRecurringOrder resumes a paused recurring order.
+//
+// @Summary      Resume recurring order
+// @Description  Reactivates a paused recurring order; runs missed while paused are skipped and the next run moves to the first cadence date from today on
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        recurringOrderId path string true "Recurring order ID"
+// @Success      200 {object} order.RecurringOrderResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/recurring-orders/{recurringOrderId}/resume [post]
+// @Security     BearerAuth
+func (h *HttpHandler) ResumeRecurringOrder(c *gin.Context) {
+	h.transitionRecurringOrder(c, h.service.ResumeRecurringOrder)
+}
+
+func (h *HttpHandler) transitionRecurringOrder(c *gin.Context, transition func(context.Context, *account.User, *business.Business, string) (*RecurringOrder, error)) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	recurringOrderID := c.Param("recurringOrderId")
+	if _, err := transition(c.Request.Context(), actor, biz, recurringOrderID); err != nil {
+		response.Error(c, recurringOrderNotFound(recurringOrderID, err))
+		return
+	}
+	loaded, err := h.service.GetRecurringOrderByID(c.Request.Context(), actor, biz, recurringOrderID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToRecurringOrderResponse(loaded))
+}
+
+// GenerateRecurringOrder creates the upcoming order of a recurring order now.
+//
+// @Summary      Generate recurring order now
+// @Description  Creates the upcoming order of an active recurring order right away (stock is checked) and moves the next run one cadence step forward
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        recurringOrderId path string true "Recurring order ID"
+// @Success      201 {object} order.GenerateRecurringOrderResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/recurring-orders/{recurringOrderId}/generate [post]
+// @Security     BearerAuth
+func (h *HttpHandler) GenerateRecurringOrder(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	recurringOrderID := c.Param("recurringOrderId")
+	_, ord, err := h.service.GenerateRecurringOrder(c.Request.Context(), actor, biz, recurringOrderID)
+	if err != nil {
+		response.Error(c, recurringOrderNotFound(recurringOrderID, err))
+		return
+	}
+	loadedRecurring, err := h.service.GetRecurringOrderByID(c.Request.Context(), actor, biz, recurringOrderID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	loadedOrder, err := h.service.GetOrderByID(c.Request.Context(), actor, biz, ord.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, GenerateRecurringOrderResponse{
+		RecurringOrder: ToRecurringOrderResponse(loadedRecurring),
+		Order:          orderResponseFor(actor, loadedOrder),
+	})
+}
+
+// ListDeliverySlots returns the delivery slots of a date range with their remaining capacity.
+//
+// @Summary      List delivery slots
+// @Description  Returns, for every date of the range, the delivery windows offered that day with their booked and remaining capacity (at most 31 days)
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        from query string false "Start date (YYYY-MM-DD, defaults to today)"
+// @Param        to query string false "End date, inclusive (YYYY-MM-DD, defaults to 6 days after from)"
+// @Success      200 {array} order.DeliverySlotAvailability
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/delivery-slots [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListDeliverySlots(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query deliverySlotsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	from, err := parseDeliveryDate(query.From, time.Now().In(biz.Location()))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	to, err := parseDeliveryDate(query.To, from.AddDate(0, 0, 6))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	slots, err := h.service.ListDeliverySlots(c.Request.Context(), actor, biz, from, to)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, slots)
+}
+
+// GetDeliveryManifest returns the orders to deliver on a date, grouped by slot.
+//
+// @Summary      Get delivery manifest
+// @Description  Returns the open orders booked for delivery on a date, grouped by delivery slot in time order, with customer, address, items and amount due
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        date query string false "Delivery date (YYYY-MM-DD, defaults to today)"
+// @Success      200 {object} order.DeliveryManifest
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/delivery-manifest [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetDeliveryManifest(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query deliveryManifestQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	day, err := parseDeliveryDate(query.Date, time.Now().In(biz.Location()))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	manifest, err := h.service.GetDeliveryManifest(c.Request.Context(), actor, biz, day)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, manifest)
+}
+
+// ScheduleOrderDelivery books an order on a delivery slot.
+//
+// @Summary      Schedule order delivery
+// @Description  Books the order on a delivery window for a date, replacing any slot it was booked on. Fails when the slot is full.
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        orderId path string true "Order ID"
+// @Param        body body ScheduleOrderDeliveryRequest true "Delivery slot"
+// @Success      200 {object} order.OrderResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/{orderId}/delivery-slot [put]
+// @Security     BearerAuth
+func (h *HttpHandler) ScheduleOrderDelivery(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req ScheduleOrderDeliveryRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+	ord, err := h.service.ScheduleOrderDelivery(c.Request.Context(), actor, biz, c.Param("orderId"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	loaded, err := h.service.GetOrderByID(c.Request.Context(), actor, biz, ord.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, orderResponseFor(actor, loaded))
+}
+
+// ClearOrderDelivery takes an order off its delivery slot.
+//
+// @Summary      Clear order delivery slot
+// @Description  Takes the order off its delivery slot, freeing its seat
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        orderId path string true "Order ID"
+// @Success      200 {object} order.OrderResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/orders/{orderId}/delivery-slot [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) ClearOrderDelivery(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	ord, err := h.service.ClearOrderDelivery(c.Request.Context(), actor, biz, c.Param("orderId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	loaded, err := h.service.GetOrderByID(c.Request.Context(), actor, biz, ord.ID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, orderResponseFor(actor, loaded))
+}
+
+// ListOrderTransitionRules returns the order transition rules of the business.
+//
+// @Summary      List order transition rules
+// @Description  Returns the side effects the business runs on order status changes, by target status
+// @Tags         order
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {array} order.OrderTransitionRuleResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/order-transition-rules [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListOrderTransitionRules(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	rules, err := h.service.ListOrderTransitionRules(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToOrderTransitionRuleResponses(rules))
+}
+
+// CreateOrderTransitionRule adds an order transition rule.
+//
+// @Summary      Create order transition rule
+// @Description  Adds a side effect to order status changes into toStatus (optionally only from fromStatus): require_payment, mark_paid or notify_customer
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body CreateOrderTransitionRuleRequest true "Rule"
+// @Success      201 {object} order.OrderTransitionRuleResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/order-transition-rules [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CreateOrderTransitionRule(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req CreateOrderTransitionRuleRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+	rule, err := h.service.CreateOrderTransitionRule(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, ToOrderTransitionRuleResponse(rule))
+}
+
+// UpdateOrderTransitionRule changes the message of an order transition rule or turns it on or off.
+//
+// @Summary      Update order transition rule
+// @Description  Updates the message or the enabled flag of a rule; omitted fields are kept
+// @Tags         order
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        ruleId path string true "Rule ID"
+// @Param        body body UpdateOrderTransitionRuleRequest true "Changes"
+// @Success      200 {object} order.OrderTransitionRuleResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/order-transition-rules/{ruleId} [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdateOrderTransitionRule(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req UpdateOrderTransitionRuleRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		return
+	}
+	rule, err := h.service.UpdateOrderTransitionRule(c.Request.Context(), actor, biz, c.Param("ruleId"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToOrderTransitionRuleResponse(rule))
+}
+
+// DeleteOrderTransitionRule removes an order transition rule.
+//
+// @Summary      Delete order transition rule
+// @Description  Removes a rule; past status changes are not affected
+// @Tags         order
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        ruleId path string true "Rule ID"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/order-transition-rules/{ruleId} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteOrderTransitionRule(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := h.getBusinessForRequest(c, actor)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.service.DeleteOrderTransitionRule(c.Request.Context(), actor, biz, c.Param("ruleId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
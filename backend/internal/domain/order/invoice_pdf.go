@@ -0,0 +1,158 @@
+package order
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/pdf"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	invoiceLogoMaxWidth  = 120.0
+	invoiceLogoMaxHeight = 50.0
+)
+
+// RenderInvoicePDF renders an order (with Customer, ShippingAddress, ShippingZone and
+// Items.Product/Variant preloaded) as an A4 invoice. Amounts use the currency, VAT rate and
+// pricing mode snapshotted on the order, so an invoice never changes after the business
+// settings do. logo may be nil.
+func RenderInvoicePDF(biz *business.Business, ord *Order, logo image.Image) []byte {
+	doc := pdf.New("Invoice " + ord.OrderNumber)
+	loc := biz.Location()
+
+	y := 60.0
+	if logo != nil {
+		w, h := fitBox(logo.Bounds(), invoiceLogoMaxWidth, invoiceLogoMaxHeight)
+		doc.Image(pdfMarginX, 40, w, h, logo)
+		y = 40 + h + 18
+	}
+	doc.Text(pdfMarginX, y, pdf.Bold, 18, pdf.AlignLeft, invoiceBusinessName(biz))
+	y += 18
+	if biz.Brand != "" && biz.Brand != biz.Name {
+		doc.Text(pdfMarginX, y, pdf.Regular, 9, pdf.AlignLeft, biz.Name)
+		y += 12
+	}
+	for _, line := range businessContactLines(biz) {
+		doc.Text(pdfMarginX, y, pdf.Regular, 9, pdf.AlignLeft, line)
+		y += 12
+	}
+
+	doc.Text(pdfRight, 60, pdf.Bold, 18, pdf.AlignRight, "INVOICE")
+	metaY := 78.0
+	meta := [][2]string{
+		{"Invoice #", ord.OrderNumber},
+		{"Date", ord.OrderedAt.In(loc).Format(pdfDateLayout)},
+		{"Status", strings.ToUpper(humanize(string(ord.PaymentStatus)))},
+	}
+	if ord.PaidAt.Valid {
+		meta = append(meta, [2]string{"Paid on", ord.PaidAt.Time.In(loc).Format(pdfDateLayout)})
+	}
+	for _, kv := range meta {
+		doc.Text(pdfRight-110, metaY, pdf.Bold, 9, pdf.AlignRight, kv[0])
+		doc.Text(pdfRight, metaY, pdf.Regular, 9, pdf.AlignRight, kv[1])
+		metaY += 12
+	}
+
+	y = max(y, metaY) + 16
+	doc.Text(pdfMarginX, y, pdf.Bold, 10, pdf.AlignLeft, "Bill to")
+	y += 14
+	for _, line := range customerLines(ord.Customer, ord.ShippingAddress) {
+		doc.Text(pdfMarginX, y, pdf.Regular, 10, pdf.AlignLeft, line)
+		y += 13
+	}
+
+	y += 16
+	y = drawItemsHeader(doc, y)
+	for _, it := range ord.Items {
+		if y > pdfBottomLimit {
+			doc.AddPage()
+			y = drawItemsHeader(doc, 60)
+		}
+		doc.Text(pdfMarginX+6, y, pdf.Regular, 10, pdf.AlignLeft, pdf.Truncate(orderItemName(it), pdf.Regular, 10, pdfNameMaxWidth))
+		doc.Text(pdfQtyColumn, y, pdf.Regular, 10, pdf.AlignRight, fmt.Sprintf("%d", it.Quantity))
+		doc.Text(pdfPriceColumn, y, pdf.Regular, 10, pdf.AlignRight, formatMoney(it.UnitPrice, ord.Currency))
+		doc.Text(pdfRight-6, y, pdf.Regular, 10, pdf.AlignRight, formatMoney(it.Total, ord.Currency))
+		if sku := orderItemSKU(it); sku != "" {
+			y += 11
+			doc.Text(pdfMarginX+6, y, pdf.Regular, 8, pdf.AlignLeft, "SKU "+sku)
+		}
+		y += 18
+	}
+	doc.Line(pdfMarginX, y-8, pdfRight, y-8)
+
+	totals := invoiceTotals(ord)
+	if y+float64(len(totals)+1)*16 > pdfBottomLimit {
+		doc.AddPage()
+		y = 60
+	}
+	y += 6
+	for _, kv := range totals {
+		doc.Text(pdfPriceColumn, y, pdf.Regular, 10, pdf.AlignRight, kv[0])
+		doc.Text(pdfRight-6, y, pdf.Regular, 10, pdf.AlignRight, kv[1])
+		y += 16
+	}
+	doc.Text(pdfPriceColumn, y+2, pdf.Bold, 12, pdf.AlignRight, "Total")
+	doc.Text(pdfRight-6, y+2, pdf.Bold, 12, pdf.AlignRight, formatMoney(ord.Total, ord.Currency))
+	y += 30
+
+	payment := []string{"Method: " + humanize(string(ord.PaymentMethod))}
+	if ref := strings.TrimSpace(ord.PaymentReference.String); ord.PaymentReference.Valid && ref != "" {
+		payment = append(payment, "Reference: "+ref)
+	}
+	if y+float64(len(payment)+1)*13 > pdfBottomLimit {
+		doc.AddPage()
+		y = 60
+	}
+	doc.Text(pdfMarginX, y, pdf.Bold, 10, pdf.AlignLeft, "Payment")
+	y += 14
+	for _, line := range payment {
+		doc.Text(pdfMarginX, y, pdf.Regular, 9, pdf.AlignLeft, line)
+		y += 12
+	}
+
+	return doc.Bytes()
+}
+
+// invoiceTotals lists the rows above the invoice total. With VAT-inclusive prices the VAT is
+// already part of the subtotal, so the net amount is shown next to it for the breakdown.
+func invoiceTotals(ord *Order) [][2]string {
+	totals := [][2]string{{"Subtotal", formatMoney(ord.Subtotal, ord.Currency)}}
+	if ord.Discount.IsPositive() {
+		totals = append(totals, [2]string{"Discount", "-" + formatMoney(ord.Discount, ord.Currency)})
+	}
+	if ord.ShippingFee.IsPositive() {
+		label := "Shipping"
+		if ord.ShippingZone != nil && ord.ShippingZone.Name != "" {
+			label = "Shipping (" + ord.ShippingZone.Name + ")"
+		}
+		totals = append(totals, [2]string{pdf.Truncate(label, pdf.Regular, 10, pdfPriceColumn-pdfMarginX), formatMoney(ord.ShippingFee, ord.Currency)})
+	}
+	rate := ord.VATRate.Mul(decimal.NewFromInt(100)).String()
+	if ord.PricesIncludeVAT {
+		totals = append(totals,
+			[2]string{"Net (excl. VAT)", formatMoney(ord.Total.Sub(ord.VAT), ord.Currency)},
+			[2]string{fmt.Sprintf("VAT included (%s%%)", rate), formatMoney(ord.VAT, ord.Currency)},
+		)
+	} else {
+		totals = append(totals, [2]string{fmt.Sprintf("VAT (%s%%)", rate), formatMoney(ord.VAT, ord.Currency)})
+	}
+	return totals
+}
+
+// invoiceBusinessName prefers the brand the business trades under over its legal name.
+func invoiceBusinessName(biz *business.Business) string {
+	if brand := strings.TrimSpace(biz.Brand); brand != "" {
+		return brand
+	}
+	return biz.Name
+}
+
+// fitBox scales bounds down to fit within maxW x maxH, keeping the aspect ratio.
+func fitBox(bounds image.Rectangle, maxW, maxH float64) (float64, float64) {
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+	scale := min(maxW/w, maxH/h, 1)
+	return w * scale, h * scale
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/abdelrahman146/kyora/internal/domain/business"
 	"github.com/abdelrahman146/kyora/internal/domain/customer"
 	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/asset"
 	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
 	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
 	"github.com/shopspring/decimal"
@@ -83,6 +85,7 @@ const (
 
 type Order struct {
 	gorm.Model
+	database.Versioned
 	ID                 string                    `gorm:"column:id;primaryKey;type:text" json:"id"`
 	OrderNumber        string                    `gorm:"column:order_number;type:text;not null;uniqueIndex:order_number_business_id_idx" json:"orderNumber"`
 	BusinessID         string                    `gorm:"column:business_id;type:text;not null;index;uniqueIndex:order_number_business_id_idx" json:"businessId"`
@@ -93,33 +96,51 @@ type Order struct {
 	ShippingAddress    *customer.CustomerAddress `gorm:"foreignKey:ShippingAddressID;references:ID" json:"shippingAddress,omitempty"`
 	ShippingZoneID     *string                   `gorm:"column:shipping_zone_id;type:text;index" json:"shippingZoneId,omitempty"`
 	ShippingZone       *business.ShippingZone    `gorm:"foreignKey:ShippingZoneID;references:ID" json:"shippingZone,omitempty"`
+	LocationID         string                    `gorm:"column:location_id;type:text;index" json:"locationId,omitempty"`
 	Channel            string                    `gorm:"column:channel;type:text;not null" json:"channel"`
 	Subtotal           decimal.Decimal           `gorm:"column:subtotal;type:numeric;not null;default:0" json:"subtotal"`
 	VAT                decimal.Decimal           `gorm:"column:vat;type:numeric;not null;default:0" json:"vat"`
 	VATRate            decimal.Decimal           `gorm:"column:vat_rate;type:numeric;not null;default:0" json:"vatRate"`
+	PricesIncludeVAT   bool                      `gorm:"column:prices_include_vat;type:boolean;not null;default:false" json:"pricesIncludeVat"`
 	ShippingFee        decimal.Decimal           `gorm:"column:shipping_fee;type:numeric;not null;default:0" json:"shippingFee"`
+	ShippingExempt     bool                      `gorm:"column:shipping_exempt;type:boolean;not null;default:false" json:"shippingExempt"`
 	Discount           decimal.Decimal           `gorm:"column:discount;type:numeric;not null;default:0" json:"discount"`
 	DiscountType       DiscountType              `gorm:"column:discount_type;type:text" json:"discountType,omitempty"`
 	DiscountValue      decimal.Decimal           `gorm:"column:discount_value;type:numeric;default:0" json:"discountValue,omitempty"`
 	COGS               decimal.Decimal           `gorm:"column:cogs;type:numeric;not null;default:0" json:"cogs"`
 	Total              decimal.Decimal           `gorm:"column:total;type:numeric;not null;default:0" json:"total"`
+	AmountPaid         decimal.Decimal           `gorm:"column:amount_paid;type:numeric;not null;default:0" json:"amountPaid"`
 	Currency           string                    `gorm:"column:currency;type:text;not null" json:"currency"`
 	Status             OrderStatus               `gorm:"column:status;type:text;not null;default:'pending'" json:"status"`
 	PaymentStatus      OrderPaymentStatus        `gorm:"column:payment_status;type:text;not null;default:'pending'" json:"paymentStatus"`
 	PaymentMethod      OrderPaymentMethod        `gorm:"column:payment_method;type:text;not null;default:'bank_transfer'" json:"paymentMethod"`
 	PaymentReference   sql.NullString            `gorm:"column:payment_reference;type:text" json:"paymentReference,omitempty"`
+	CouponID           sql.NullString            `gorm:"column:coupon_id;type:text;index" json:"couponId,omitempty"`
+	CouponCode         sql.NullString            `gorm:"column:coupon_code;type:text" json:"couponCode,omitempty"`
+	Tags               OrderTags                 `gorm:"column:tags;type:jsonb;not null;default:'[]'" json:"tags"`
+	CustomFields       OrderCustomFields         `gorm:"column:custom_fields;type:jsonb;not null;default:'{}'" json:"customFields"`
+	PaymentLinkURL     sql.NullString            `gorm:"column:payment_link_url;type:text" json:"paymentLinkUrl,omitempty"`
+	CheckoutSessionID  sql.NullString            `gorm:"column:checkout_session_id;type:text" json:"checkoutSessionId,omitempty"`
+	CheckoutExpiresAt  sql.NullTime              `gorm:"column:checkout_expires_at" json:"checkoutExpiresAt"`
+	DeliveryWindowID   sql.NullString            `gorm:"column:delivery_window_id;type:text" json:"deliveryWindowId,omitempty"`
+	DeliveryDate       sql.NullTime              `gorm:"column:delivery_date;type:date" json:"deliveryDate"`
+	DeliveryStartTime  sql.NullString            `gorm:"column:delivery_start_time;type:text" json:"deliveryStartTime,omitempty"`
+	DeliveryEndTime    sql.NullString            `gorm:"column:delivery_end_time;type:text" json:"deliveryEndTime,omitempty"`
 	PlacedAt           sql.NullTime              `gorm:"column:placed_at" json:"placedAt"`
 	ReadyForShipmentAt sql.NullTime              `gorm:"column:ready_for_shipment_at" json:"readyForShipmentAt"`
 	OrderedAt          time.Time                 `gorm:"column:ordered_at;type:timestamptz;not null;default:now()" json:"orderedAt"`
 	ShippedAt          sql.NullTime              `gorm:"column:shipped_at" json:"shippedAt"`
 	FulfilledAt        sql.NullTime              `gorm:"column:fulfilled_at" json:"fulfilledAt"`
 	CancelledAt        sql.NullTime              `gorm:"column:cancelled_at" json:"cancelledAt"`
+	ExpiredAt          sql.NullTime              `gorm:"column:expired_at" json:"expiredAt"`
 	ReturnedAt         sql.NullTime              `gorm:"column:returned_at" json:"returnedAt"`
 	PaidAt             sql.NullTime              `gorm:"column:paid_at" json:"paidAt"`
 	FailedAt           sql.NullTime              `gorm:"column:failed_at" json:"failedAt"`
 	RefundedAt         sql.NullTime              `gorm:"column:refunded_at" json:"refundedAt"`
+	DownloadsSentAt    sql.NullTime              `gorm:"column:downloads_sent_at" json:"downloadsSentAt"`
 	Items              []*OrderItem              `gorm:"foreignKey:OrderID;references:ID" json:"items"`
 	Notes              []*OrderNote              `gorm:"foreignKey:OrderID;references:ID" json:"notes,omitempty"`
+	Payments           []*OrderPayment           `gorm:"foreignKey:OrderID;references:ID" json:"payments,omitempty"`
 }
 
 func (o *Order) BeforeCreate(tx *gorm.DB) (err error) {
@@ -145,6 +166,7 @@ var OrderSchema = struct {
 	CustomerID         schema.Field
 	ShippingAddressID  schema.Field
 	ShippingZoneID     schema.Field
+	LocationID         schema.Field
 	Channel            schema.Field
 	Subtotal           schema.Field
 	VAT                schema.Field
@@ -153,17 +175,23 @@ var OrderSchema = struct {
 	Discount           schema.Field
 	COGS               schema.Field
 	Total              schema.Field
+	AmountPaid         schema.Field
 	Currency           schema.Field
 	Status             schema.Field
 	PaymentStatus      schema.Field
 	PaymentMethod      schema.Field
 	PaymentReference   schema.Field
+	Tags               schema.Field
+	CustomFields       schema.Field
+	DeliveryWindowID   schema.Field
+	DeliveryDate       schema.Field
 	PlacedAt           schema.Field
 	ReadyForShipmentAt schema.Field
 	OrderedAt          schema.Field
 	ShippedAt          schema.Field
 	FulfilledAt        schema.Field
 	CancelledAt        schema.Field
+	ExpiredAt          schema.Field
 	ReturnedAt         schema.Field
 	PaidAt             schema.Field
 	FailedAt           schema.Field
@@ -178,6 +206,7 @@ var OrderSchema = struct {
 	CustomerID:         schema.NewField("customer_id", "customerId"),
 	ShippingAddressID:  schema.NewField("shipping_address_id", "shippingAddressId"),
 	ShippingZoneID:     schema.NewField("shipping_zone_id", "shippingZoneId"),
+	LocationID:         schema.NewField("location_id", "locationId"),
 	Channel:            schema.NewField("channel", "channel"),
 	Subtotal:           schema.NewField("subtotal", "subtotal"),
 	VAT:                schema.NewField("vat", "vat"),
@@ -186,17 +215,23 @@ var OrderSchema = struct {
 	Discount:           schema.NewField("discount", "discount"),
 	COGS:               schema.NewField("cogs", "cogs"),
 	Total:              schema.NewField("total", "total"),
+	AmountPaid:         schema.NewField("amount_paid", "amountPaid"),
 	Currency:           schema.NewField("currency", "currency"),
 	Status:             schema.NewField("status", "status"),
 	PaymentStatus:      schema.NewField("payment_status", "paymentStatus"),
 	PaymentMethod:      schema.NewField("payment_method", "paymentMethod"),
 	PaymentReference:   schema.NewField("payment_reference", "paymentReference"),
+	Tags:               schema.NewField("tags", "tags"),
+	CustomFields:       schema.NewField("custom_fields", "customFields"),
+	DeliveryWindowID:   schema.NewField("delivery_window_id", "deliveryWindowId"),
+	DeliveryDate:       schema.NewField("delivery_date", "deliveryDate"),
 	PlacedAt:           schema.NewField("placed_at", "placedAt"),
 	ReadyForShipmentAt: schema.NewField("ready_for_shipment_at", "readyForShipmentAt"),
 	OrderedAt:          schema.NewField("ordered_at", "orderedAt"),
 	ShippedAt:          schema.NewField("shipped_at", "shippedAt"),
 	FulfilledAt:        schema.NewField("fulfilled_at", "fulfilledAt"),
 	CancelledAt:        schema.NewField("cancelled_at", "cancelledAt"),
+	ExpiredAt:          schema.NewField("expired_at", "expiredAt"),
 	ReturnedAt:         schema.NewField("returned_at", "returnedAt"),
 	PaidAt:             schema.NewField("paid_at", "paidAt"),
 	FailedAt:           schema.NewField("failed_at", "failedAt"),
@@ -206,6 +241,26 @@ var OrderSchema = struct {
 	DeletedAt:          schema.NewField("deleted_at", "deletedAt"),
 }
 
+// orderSummaryColumns is the lean projection used by summary list views.
+// It deliberately omits search_vector and the lifecycle timestamps that are only needed on detail views.
+var orderSummaryColumns = schema.Columns(OrderTable,
+	OrderSchema.ID,
+	OrderSchema.OrderNumber,
+	OrderSchema.BusinessID,
+	OrderSchema.CustomerID,
+	OrderSchema.Channel,
+	OrderSchema.Subtotal,
+	OrderSchema.Total,
+	OrderSchema.Currency,
+	OrderSchema.Status,
+	OrderSchema.PaymentStatus,
+	OrderSchema.PaymentMethod,
+	OrderSchema.Tags,
+	OrderSchema.OrderedAt,
+	OrderSchema.CreatedAt,
+	OrderSchema.UpdatedAt,
+)
+
 const (
 	OrderItemTable  = "order_items"
 	OrderItemStruct = "Items"
@@ -214,19 +269,21 @@ const (
 
 type OrderItem struct {
 	gorm.Model
-	ID        string             `gorm:"column:id;primaryKey;type:text" json:"id"`
-	OrderID   string             `gorm:"column:order_id;type:text;not null;index" json:"orderId"`
-	Order     *Order             `gorm:"foreignKey:OrderID;references:ID;OnDelete:CASCADE" json:"order,omitempty"`
-	ProductID string             `gorm:"column:product_id;type:text;not null;index" json:"productId"`
-	Product   *inventory.Product `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
-	VariantID string             `gorm:"column:variant_id;type:text;not null;index" json:"variantId"`
-	Variant   *inventory.Variant `gorm:"foreignKey:VariantID;references:ID" json:"variant,omitempty"`
-	Quantity  int                `gorm:"column:quantity;type:int;not null;default:1" json:"quantity"`
-	Currency  string             `gorm:"column:currency;type:text;not null" json:"currency"`
-	UnitPrice decimal.Decimal    `gorm:"column:unit_price;type:numeric;not null;default:0" json:"unitPrice"`
-	UnitCost  decimal.Decimal    `gorm:"column:unit_cost;type:numeric;not null;default:0" json:"unitCost"`
-	TotalCost decimal.Decimal    `gorm:"column:total_cost;type:numeric;not null;default:0" json:"totalCost"`
-	Total     decimal.Decimal    `gorm:"column:total;type:numeric;not null;default:0" json:"total"`
+	ID          string                `gorm:"column:id;primaryKey;type:text" json:"id"`
+	OrderID     string                `gorm:"column:order_id;type:text;not null;index" json:"orderId"`
+	Order       *Order                `gorm:"foreignKey:OrderID;references:ID;OnDelete:CASCADE" json:"order,omitempty"`
+	ProductID   string                `gorm:"column:product_id;type:text;not null;index" json:"productId"`
+	Product     *inventory.Product    `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
+	VariantID   string                `gorm:"column:variant_id;type:text;not null;index" json:"variantId"`
+	Variant     *inventory.Variant    `gorm:"foreignKey:VariantID;references:ID" json:"variant,omitempty"`
+	Quantity    int                   `gorm:"column:quantity;type:int;not null;default:1" json:"quantity"`
+	ProductType inventory.ProductType `gorm:"column:product_type;type:text;not null;default:'physical'" json:"productType"`
+	Download    *asset.AssetReference `gorm:"column:download;type:jsonb" json:"download,omitempty"`
+	Currency    string                `gorm:"column:currency;type:text;not null" json:"currency"`
+	UnitPrice   decimal.Decimal       `gorm:"column:unit_price;type:numeric;not null;default:0" json:"unitPrice"`
+	UnitCost    decimal.Decimal       `gorm:"column:unit_cost;type:numeric;not null;default:0" json:"unitCost"`
+	TotalCost   decimal.Decimal       `gorm:"column:total_cost;type:numeric;not null;default:0" json:"totalCost"`
+	Total       decimal.Decimal       `gorm:"column:total;type:numeric;not null;default:0" json:"total"`
 }
 
 func (m *OrderItem) BeforeCreate(tx *gorm.DB) (err error) {
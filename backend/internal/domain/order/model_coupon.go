@@ -0,0 +1,131 @@
+package order
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	CouponTable  = "coupons"
+	CouponStruct = "Coupon"
+	CouponPrefix = "cpn"
+)
+
+// Coupon is a discount code customers give when ordering. Redeeming it applies its percent
+// or fixed discount to the order subtotal, within its validity window and usage limits.
+type Coupon struct {
+	gorm.Model
+	ID            string          `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID    string          `gorm:"column:business_id;type:text;not null;index;uniqueIndex:idx_coupons_business_code,where:deleted_at IS NULL" json:"businessId"`
+	Code          string          `gorm:"column:code;type:text;not null;uniqueIndex:idx_coupons_business_code,where:deleted_at IS NULL" json:"code"`
+	Description   string          `gorm:"column:description;type:text" json:"description,omitempty"`
+	DiscountType  DiscountType    `gorm:"column:discount_type;type:text;not null" json:"discountType"`
+	DiscountValue decimal.Decimal `gorm:"column:discount_value;type:numeric;not null" json:"discountValue"`
+	// MinSubtotal is the order subtotal the coupon requires; zero means no minimum.
+	MinSubtotal decimal.Decimal `gorm:"column:min_subtotal;type:numeric;not null;default:0" json:"minSubtotal"`
+	// MaxUses caps redemptions across all customers; zero means unlimited.
+	MaxUses int `gorm:"column:max_uses;type:int;not null;default:0" json:"maxUses"`
+	// MaxUsesPerCustomer caps redemptions by one customer; zero means unlimited.
+	MaxUsesPerCustomer int `gorm:"column:max_uses_per_customer;type:int;not null;default:0" json:"maxUsesPerCustomer"`
+	// CustomerID restricts the coupon to a single customer when set.
+	CustomerID sql.NullString `gorm:"column:customer_id;type:text;index" json:"customerId,omitempty"`
+	UsedCount  int            `gorm:"column:used_count;type:int;not null;default:0" json:"usedCount"`
+	StartsAt   sql.NullTime   `gorm:"column:starts_at" json:"startsAt"`
+	ExpiresAt  sql.NullTime   `gorm:"column:expires_at" json:"expiresAt"`
+	Active     bool           `gorm:"column:active;type:boolean;not null;default:true" json:"active"`
+}
+
+func (m *Coupon) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(CouponPrefix)
+	}
+	return
+}
+
+// Discount returns the discount the coupon gives on subtotal, never more than subtotal.
+func (m *Coupon) Discount(subtotal decimal.Decimal, currency string) decimal.Decimal {
+	return decimal.Min(discountAmount(subtotal, currency, m.DiscountType, m.DiscountValue), subtotal)
+}
+
+// IsValidAt reports whether the coupon is active and within its validity window at t.
+func (m *Coupon) IsValidAt(t time.Time) bool {
+	if !m.Active {
+		return false
+	}
+	if m.StartsAt.Valid && t.Before(m.StartsAt.Time) {
+		return false
+	}
+	return !m.ExpiresAt.Valid || t.Before(m.ExpiresAt.Time)
+}
+
+var CouponSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	Code       schema.Field
+	CustomerID schema.Field
+	UsedCount  schema.Field
+	StartsAt   schema.Field
+	ExpiresAt  schema.Field
+	Active     schema.Field
+	CreatedAt  schema.Field
+	UpdatedAt  schema.Field
+	DeletedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	Code:       schema.NewField("code", "code"),
+	CustomerID: schema.NewField("customer_id", "customerId"),
+	UsedCount:  schema.NewField("used_count", "usedCount"),
+	StartsAt:   schema.NewField("starts_at", "startsAt"),
+	ExpiresAt:  schema.NewField("expires_at", "expiresAt"),
+	Active:     schema.NewField("active", "active"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+	UpdatedAt:  schema.NewField("updated_at", "updatedAt"),
+	DeletedAt:  schema.NewField("deleted_at", "deletedAt"),
+}
+
+const (
+	CouponRedemptionTable  = "coupon_redemptions"
+	CouponRedemptionStruct = "CouponRedemption"
+	CouponRedemptionPrefix = "cpr"
+)
+
+// CouponRedemption records a coupon applied to an order. Per-customer limits count them, and
+// deleting the order releases it.
+type CouponRedemption struct {
+	ID         string          `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string          `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	CouponID   string          `gorm:"column:coupon_id;type:text;not null;index:idx_coupon_redemptions_coupon_customer" json:"couponId"`
+	CustomerID string          `gorm:"column:customer_id;type:text;not null;index:idx_coupon_redemptions_coupon_customer" json:"customerId"`
+	OrderID    string          `gorm:"column:order_id;type:text;not null;uniqueIndex" json:"orderId"`
+	Discount   decimal.Decimal `gorm:"column:discount;type:numeric;not null" json:"discount"`
+	CreatedAt  time.Time       `gorm:"column:created_at;type:timestamptz;autoCreateTime" json:"createdAt"`
+}
+
+func (m *CouponRedemption) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(CouponRedemptionPrefix)
+	}
+	return
+}
+
+var CouponRedemptionSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	CouponID   schema.Field
+	CustomerID schema.Field
+	OrderID    schema.Field
+	CreatedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	CouponID:   schema.NewField("coupon_id", "couponId"),
+	CustomerID: schema.NewField("customer_id", "customerId"),
+	OrderID:    schema.NewField("order_id", "orderId"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+}
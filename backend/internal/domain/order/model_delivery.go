@@ -0,0 +1,171 @@
+package order
+
+import (
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/platform/types/date"
+	"github.com/shopspring/decimal"
+)
+
+// deliveryDateLayout is how delivery dates are rendered.
+const deliveryDateLayout = "2006-01-02"
+
+// maxDeliverySlotsRangeDays bounds the date range of the delivery slots availability query.
+const maxDeliverySlotsRangeDays = 31
+
+// ScheduleOrderDeliveryRequest books an order on a delivery window for a date.
+type ScheduleOrderDeliveryRequest struct {
+	WindowID string     `json:"windowId" binding:"required"`
+	Date     *date.Date `json:"date" binding:"required"`
+}
+
+// hasDeliverySlot reports whether the order is booked on a delivery window.
+func (o *Order) hasDeliverySlot() bool {
+	return o.DeliveryDate.Valid
+}
+
+// canScheduleDelivery reports whether the order may still be booked on (or taken off) a
+// delivery slot: it has not left yet.
+func (o *Order) canScheduleDelivery() bool {
+	switch o.Status {
+	case OrderStatusPending, OrderStatusPlaced, OrderStatusReadyForShipment:
+		return true
+	}
+	return false
+}
+
+// deliverySlotLabel renders the booked slot for the timeline ("2026-01-31 09:00-12:00").
+func (o *Order) deliverySlotLabel() any {
+	if !o.hasDeliverySlot() {
+		return nil
+	}
+	return o.DeliveryDate.Time.Format(deliveryDateLayout) + " " + o.DeliveryStartTime.String + "-" + o.DeliveryEndTime.String
+}
+
+// OrderDeliverySlotResponse is the delivery slot an order is booked on. Times are the ones
+// of the window when the order was booked.
+type OrderDeliverySlotResponse struct {
+	Date      string `json:"date"`
+	WindowID  string `json:"windowId,omitempty"`
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+}
+
+func orderDeliverySlotResponse(ord *Order) *OrderDeliverySlotResponse {
+	if !ord.hasDeliverySlot() {
+		return nil
+	}
+	return &OrderDeliverySlotResponse{
+		Date:      ord.DeliveryDate.Time.Format(deliveryDateLayout),
+		WindowID:  ord.DeliveryWindowID.String,
+		StartTime: ord.DeliveryStartTime.String,
+		EndTime:   ord.DeliveryEndTime.String,
+	}
+}
+
+// DeliverySlotAvailability is a delivery window on a date with the orders it can still take.
+type DeliverySlotAvailability struct {
+	Date      string `json:"date"`
+	WindowID  string `json:"windowId"`
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+	Capacity  int    `json:"capacity"`
+	Booked    int    `json:"booked"`
+	Remaining int    `json:"remaining"`
+}
+
+// DeliveryManifestItem is a line of an order to deliver.
+type DeliveryManifestItem struct {
+	Name     string `json:"name"`
+	SKU      string `json:"sku,omitempty"`
+	Quantity int    `json:"quantity"`
+}
+
+// DeliveryManifestOrder is an order to deliver, with what the driver needs at the door.
+type DeliveryManifestOrder struct {
+	ID              string                            `json:"id"`
+	OrderNumber     string                            `json:"orderNumber"`
+	Status          OrderStatus                       `json:"status"`
+	CustomerName    string                            `json:"customerName,omitempty"`
+	ShippingAddress *customer.CustomerAddressResponse `json:"shippingAddress,omitempty"`
+	Items           []DeliveryManifestItem            `json:"items"`
+	Total           decimal.Decimal                   `json:"total"`
+	AmountDue       decimal.Decimal                   `json:"amountDue"`
+	Currency        string                            `json:"currency"`
+	PaymentMethod   OrderPaymentMethod                `json:"paymentMethod"`
+}
+
+// DeliveryManifestSlot is a delivery slot of the day with its orders. Capacity is zero when
+// the window was deleted after the orders were booked.
+type DeliveryManifestSlot struct {
+	WindowID  string                  `json:"windowId,omitempty"`
+	StartTime string                  `json:"startTime"`
+	EndTime   string                  `json:"endTime"`
+	Capacity  int                     `json:"capacity"`
+	Orders    []DeliveryManifestOrder `json:"orders"`
+}
+
+// DeliveryManifest lists the orders to deliver on a date, by slot.
+type DeliveryManifest struct {
+	Date   string                 `json:"date"`
+	Orders int                    `json:"orders"`
+	Slots  []DeliveryManifestSlot `json:"slots"`
+}
+
+// toDeliveryManifestOrder converts an order (with customer, address and items loaded) to
+// its manifest entry.
+func toDeliveryManifestOrder(ord *Order) DeliveryManifestOrder {
+	out := DeliveryManifestOrder{
+		ID:            ord.ID,
+		OrderNumber:   ord.OrderNumber,
+		Status:        ord.Status,
+		Items:         make([]DeliveryManifestItem, 0, len(ord.Items)),
+		Total:         ord.Total,
+		AmountDue:     ord.AmountDue(),
+		Currency:      ord.Currency,
+		PaymentMethod: ord.PaymentMethod,
+	}
+	if ord.Customer != nil {
+		out.CustomerName = ord.Customer.Name
+	}
+	if ord.ShippingAddress != nil {
+		addr := customer.ToCustomerAddressResponse(ord.ShippingAddress)
+		out.ShippingAddress = &addr
+	}
+	for _, it := range ord.Items {
+		out.Items = append(out.Items, DeliveryManifestItem{Name: orderItemName(it), SKU: orderItemSKU(it), Quantity: it.Quantity})
+	}
+	return out
+}
+
+// calendarDate truncates t to its calendar date at midnight UTC, the way date columns are
+// stored.
+func calendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// deliverySlotsQuery represents the query parameters for delivery slot availability.
+// from and to are YYYY-MM-DD dates, both inclusive.
+type deliverySlotsQuery struct {
+	From string `form:"from" binding:"omitempty"`
+	To   string `form:"to" binding:"omitempty"`
+}
+
+// deliveryManifestQuery represents the query parameters for the delivery manifest.
+type deliveryManifestQuery struct {
+	Date string `form:"date" binding:"omitempty"`
+}
+
+// parseDeliveryDate parses a YYYY-MM-DD query parameter, falling back to fallback when it
+// is empty.
+func parseDeliveryDate(param string, fallback time.Time) (time.Time, error) {
+	if param == "" {
+		return calendarDate(fallback), nil
+	}
+	t, err := time.ParseInLocation(deliveryDateLayout, param, time.UTC)
+	if err != nil {
+		return time.Time{}, ErrInvalidDeliveryDate(param, "use YYYY-MM-DD")
+	}
+	return t, nil
+}
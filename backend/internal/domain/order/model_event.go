@@ -0,0 +1,121 @@
+package order
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"gorm.io/gorm"
+)
+
+// OrderEventType is the kind of mutation an OrderEvent records.
+type OrderEventType string
+
+const (
+	OrderEventCreated               OrderEventType = "created"
+	OrderEventUpdated               OrderEventType = "updated"
+	OrderEventStatusChanged         OrderEventType = "status_changed"
+	OrderEventPaymentStatusChanged  OrderEventType = "payment_status_changed"
+	OrderEventPaymentDetailsChanged OrderEventType = "payment_details_changed"
+	OrderEventPaymentLinkCreated    OrderEventType = "payment_link_created"
+	OrderEventPaymentRecorded       OrderEventType = "payment_recorded"
+	OrderEventPaymentDeleted        OrderEventType = "payment_deleted"
+	OrderEventShippingLabelBought   OrderEventType = "shipping_label_purchased"
+	OrderEventDeliveryScheduled     OrderEventType = "delivery_scheduled"
+	OrderEventNoteAdded             OrderEventType = "note_added"
+	OrderEventNoteUpdated           OrderEventType = "note_updated"
+	OrderEventNoteDeleted           OrderEventType = "note_deleted"
+	OrderEventDeleted               OrderEventType = "deleted"
+	// OrderEventTotalsReconciled records totals repaired by the reconcile-totals job.
+	OrderEventTotalsReconciled OrderEventType = "totals_reconciled"
+	// OrderEventExpired records a stale pending order cancelled by the expiry job.
+	OrderEventExpired OrderEventType = "expired"
+)
+
+// OrderFieldChange is one field of an order changed by an event. From is omitted for
+// values that did not exist before (e.g. a new note), To for removed ones.
+type OrderFieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from,omitempty"`
+	To    any    `json:"to,omitempty"`
+}
+
+// OrderFieldChanges is the diff stored on an OrderEvent.
+type OrderFieldChanges []OrderFieldChange
+
+func (c OrderFieldChanges) Value() (driver.Value, error) {
+	if c == nil {
+		c = OrderFieldChanges{}
+	}
+	b, err := json.Marshal([]OrderFieldChange(c))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (c *OrderFieldChanges) Scan(value any) error {
+	if c == nil {
+		return problem.InternalError().WithError(errors.New("OrderFieldChanges scan into nil receiver"))
+	}
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*c = OrderFieldChanges{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return problem.InternalError().WithError(errors.New("unsupported OrderFieldChanges scan type"))
+	}
+	return json.Unmarshal(raw, (*[]OrderFieldChange)(c))
+}
+
+const (
+	OrderEventTable  = "order_events"
+	OrderEventStruct = "OrderEvent"
+	OrderEventPrefix = "oev"
+)
+
+// OrderEvent is an immutable entry of an order's activity timeline: what changed, when and
+// by whom. ActorID is empty for storefront orders and background jobs. Events are kept
+// when the order is deleted.
+type OrderEvent struct {
+	ID         string            `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string            `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	OrderID    string            `gorm:"column:order_id;type:text;not null;index:idx_order_events_order_id_created_at" json:"orderId"`
+	Type       OrderEventType    `gorm:"column:type;type:text;not null" json:"type"`
+	ActorID    sql.NullString    `gorm:"column:actor_id;type:text" json:"actorId"`
+	Changes    OrderFieldChanges `gorm:"column:changes;type:jsonb;not null;default:'[]'" json:"changes"`
+	CreatedAt  time.Time         `gorm:"column:created_at;type:timestamptz;autoCreateTime;index:idx_order_events_order_id_created_at" json:"createdAt"`
+}
+
+func (m *OrderEvent) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(OrderEventPrefix)
+	}
+	return
+}
+
+var OrderEventSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	OrderID    schema.Field
+	Type       schema.Field
+	ActorID    schema.Field
+	CreatedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	OrderID:    schema.NewField("order_id", "orderId"),
+	Type:       schema.NewField("type", "type"),
+	ActorID:    schema.NewField("actor_id", "actorId"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+}
@@ -0,0 +1,141 @@
+package order
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"gorm.io/gorm"
+)
+
+// ExportFormat is the file format of an order export.
+type ExportFormat string
+
+const (
+	ExportFormatCSV  ExportFormat = "csv"
+	ExportFormatXLSX ExportFormat = "xlsx"
+)
+
+// ContentType returns the MIME type of the export file.
+func (f ExportFormat) ContentType() string {
+	if f == ExportFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv"
+}
+
+// ExportStatus tracks an export from queueing to its file being ready.
+type ExportStatus string
+
+const (
+	ExportStatusPending    ExportStatus = "pending"
+	ExportStatusProcessing ExportStatus = "processing"
+	ExportStatusCompleted  ExportStatus = "completed"
+	ExportStatusFailed     ExportStatus = "failed"
+)
+
+// ExportFilters selects the orders of an export. Empty filters match every order.
+type ExportFilters struct {
+	Statuses        []OrderStatus        `json:"statuses,omitempty"`
+	PaymentStatuses []OrderPaymentStatus `json:"paymentStatuses,omitempty"`
+	From            *time.Time           `json:"from,omitempty"`
+	To              *time.Time           `json:"to,omitempty"`
+}
+
+func (f ExportFilters) Value() (driver.Value, error) {
+	b, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (f *ExportFilters) Scan(value any) error {
+	if f == nil {
+		return problem.InternalError().WithError(errors.New("ExportFilters scan into nil receiver"))
+	}
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*f = ExportFilters{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return problem.InternalError().WithError(errors.New("unsupported ExportFilters scan type"))
+	}
+	return json.Unmarshal(raw, f)
+}
+
+const (
+	OrderExportTable  = "order_exports"
+	OrderExportStruct = "OrderExport"
+	OrderExportPrefix = "oex"
+)
+
+// OrderExport is a background export of the business's orders to a CSV or XLSX file. The
+// file lives in OrderExportFile once the export completes and is kept for a week.
+type OrderExport struct {
+	ID            string        `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID    string        `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	RequestedByID string        `gorm:"column:requested_by_id;type:text;not null" json:"requestedById"`
+	Format        ExportFormat  `gorm:"column:format;type:text;not null" json:"format"`
+	Status        ExportStatus  `gorm:"column:status;type:text;not null;default:'pending'" json:"status"`
+	Filters       ExportFilters `gorm:"column:filters;type:jsonb;not null;default:'{}'" json:"filters"`
+	RowCount      int           `gorm:"column:row_count;type:int;not null;default:0" json:"rowCount"`
+	SizeBytes     int64         `gorm:"column:size_bytes;type:bigint;not null;default:0" json:"sizeBytes"`
+	Error         string        `gorm:"column:error;type:text" json:"error,omitempty"`
+	CompletedAt   sql.NullTime  `gorm:"column:completed_at" json:"completedAt"`
+	ExpiresAt     sql.NullTime  `gorm:"column:expires_at" json:"expiresAt"`
+	CreatedAt     time.Time     `gorm:"column:created_at;type:timestamptz;autoCreateTime" json:"createdAt"`
+	UpdatedAt     time.Time     `gorm:"column:updated_at;type:timestamptz;autoUpdateTime" json:"updatedAt"`
+}
+
+func (m *OrderExport) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(OrderExportPrefix)
+	}
+	return
+}
+
+// FileName is the download name of the export file.
+func (m *OrderExport) FileName() string {
+	return "orders-" + m.CreatedAt.UTC().Format("20060102-150405") + "." + string(m.Format)
+}
+
+var OrderExportSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	Status     schema.Field
+	CreatedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	Status:     schema.NewField("status", "status"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+}
+
+const OrderExportFileTable = "order_export_files"
+
+// OrderExportFile holds the content of a completed export, apart from OrderExport so
+// listing exports does not load files.
+type OrderExportFile struct {
+	ExportID   string `gorm:"column:export_id;primaryKey;type:text"`
+	BusinessID string `gorm:"column:business_id;type:text;not null;index"`
+	Content    []byte `gorm:"column:content;type:bytea;not null"`
+}
+
+var OrderExportFileSchema = struct {
+	ExportID   schema.Field
+	BusinessID schema.Field
+}{
+	ExportID:   schema.NewField("export_id", "exportId"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+}
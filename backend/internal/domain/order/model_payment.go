@@ -0,0 +1,134 @@
+package order
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	OrderPaymentTable  = "order_payments"
+	OrderPaymentStruct = "Payments"
+	OrderPaymentPrefix = "opay"
+)
+
+// OrderPayment is one payment received for an order: a deposit, an instalment or the
+// balance. Orders sum their payments into AmountPaid.
+type OrderPayment struct {
+	gorm.Model
+	ID         string             `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string             `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	OrderID    string             `gorm:"column:order_id;type:text;not null;index" json:"orderId"`
+	Order      *Order             `gorm:"foreignKey:OrderID;references:ID;OnDelete:CASCADE" json:"order,omitempty"`
+	Amount     decimal.Decimal    `gorm:"column:amount;type:numeric;not null" json:"amount"`
+	Currency   string             `gorm:"column:currency;type:text;not null" json:"currency"`
+	Method     OrderPaymentMethod `gorm:"column:method;type:text;not null" json:"method"`
+	Reference  sql.NullString     `gorm:"column:reference;type:text" json:"reference,omitempty"`
+	Note       sql.NullString     `gorm:"column:note;type:text" json:"note,omitempty"`
+	PaidAt     time.Time          `gorm:"column:paid_at;type:timestamptz;not null" json:"paidAt"`
+}
+
+func (m *OrderPayment) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(OrderPaymentPrefix)
+	}
+	return
+}
+
+var OrderPaymentSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	OrderID    schema.Field
+	Amount     schema.Field
+	Method     schema.Field
+	PaidAt     schema.Field
+	CreatedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	OrderID:    schema.NewField("order_id", "orderId"),
+	Amount:     schema.NewField("amount", "amount"),
+	Method:     schema.NewField("method", "method"),
+	PaidAt:     schema.NewField("paid_at", "paidAt"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+}
+
+// OrderPaymentBalance compares what was paid for an order with its total.
+type OrderPaymentBalance string
+
+const (
+	OrderPaymentBalanceUnpaid        OrderPaymentBalance = "unpaid"
+	OrderPaymentBalancePartiallyPaid OrderPaymentBalance = "partially_paid"
+	OrderPaymentBalancePaid          OrderPaymentBalance = "paid"
+	OrderPaymentBalanceOverpaid      OrderPaymentBalance = "overpaid"
+)
+
+// markedPaid reports whether the payment status says the order was paid, whatever
+// payments were recorded: orders paid online or marked paid by hand have no ledger.
+func (o *Order) markedPaid() bool {
+	return o.PaymentStatus == OrderPaymentStatusPaid || o.PaymentStatus == OrderPaymentStatusRefunded
+}
+
+// PaymentBalance derives the order's balance from its recorded payments.
+func (o *Order) PaymentBalance() OrderPaymentBalance {
+	switch {
+	case o.AmountPaid.GreaterThan(o.Total):
+		return OrderPaymentBalanceOverpaid
+	case o.AmountPaid.IsPositive() && o.AmountPaid.Equal(o.Total), o.markedPaid():
+		return OrderPaymentBalancePaid
+	case o.AmountPaid.IsPositive():
+		return OrderPaymentBalancePartiallyPaid
+	default:
+		return OrderPaymentBalanceUnpaid
+	}
+}
+
+// AmountDue is what the customer still owes; it is never negative.
+func (o *Order) AmountDue() decimal.Decimal {
+	if o.markedPaid() || o.AmountPaid.GreaterThanOrEqual(o.Total) {
+		return decimal.Zero
+	}
+	return o.Total.Sub(o.AmountPaid)
+}
+
+// OrderPaymentResponse is the API response for OrderPayment entity
+type OrderPaymentResponse struct {
+	ID        string             `json:"id"`
+	OrderID   string             `json:"orderId"`
+	Amount    decimal.Decimal    `json:"amount"`
+	Currency  string             `json:"currency"`
+	Method    OrderPaymentMethod `json:"method"`
+	Reference *string            `json:"reference,omitempty"`
+	Note      *string            `json:"note,omitempty"`
+	PaidAt    time.Time          `json:"paidAt"`
+	CreatedAt time.Time          `json:"createdAt"`
+}
+
+// ToOrderPaymentResponse converts OrderPayment model to OrderPaymentResponse
+func ToOrderPaymentResponse(p *OrderPayment) OrderPaymentResponse {
+	return OrderPaymentResponse{
+		ID:        p.ID,
+		OrderID:   p.OrderID,
+		Amount:    p.Amount,
+		Currency:  p.Currency,
+		Method:    p.Method,
+		Reference: transformer.NullStringPtr(p.Reference),
+		Note:      transformer.NullStringPtr(p.Note),
+		PaidAt:    p.PaidAt,
+		CreatedAt: p.CreatedAt,
+	}
+}
+
+// ToOrderPaymentResponses converts a slice of OrderPayment models to responses
+func ToOrderPaymentResponses(payments []*OrderPayment) []OrderPaymentResponse {
+	responses := make([]OrderPaymentResponse, len(payments))
+	for i, p := range payments {
+		responses[i] = ToOrderPaymentResponse(p)
+	}
+	return responses
+}
@@ -0,0 +1,140 @@
+package order
+
+import (
+	"database/sql"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"gorm.io/gorm"
+)
+
+// PrintKind is the document a print station prints for an order.
+type PrintKind string
+
+const (
+	// PrintKindReceipt is the customer receipt with prices and totals.
+	PrintKindReceipt PrintKind = "receipt"
+	// PrintKindPickTicket lists items, SKUs and the shipping address for packing, without prices.
+	PrintKindPickTicket PrintKind = "pick_ticket"
+)
+
+// PrintFormat is the wire format of a rendered print document.
+type PrintFormat string
+
+const (
+	// PrintFormatESCPOS is raw ESC/POS for 80mm thermal printers.
+	PrintFormatESCPOS PrintFormat = "escpos"
+	// PrintFormatHTML is a compact 80mm-wide HTML page for browser printing.
+	PrintFormatHTML PrintFormat = "html"
+)
+
+// PrintJobStatus tracks a print job from queueing to the station's acknowledgement.
+type PrintJobStatus string
+
+const (
+	PrintJobStatusQueued  PrintJobStatus = "queued"
+	PrintJobStatusPrinted PrintJobStatus = "printed"
+)
+
+// PrintJobSource records why a job was queued.
+type PrintJobSource string
+
+const (
+	// PrintJobSourcePaid jobs are queued automatically when an order becomes paid.
+	PrintJobSourcePaid PrintJobSource = "paid"
+	// PrintJobSourceManual jobs are queued by a user (first print or reprint).
+	PrintJobSourceManual PrintJobSource = "manual"
+)
+
+const (
+	PrintStationTable  = "print_stations"
+	PrintStationStruct = "Station"
+	PrintStationPrefix = "prs"
+)
+
+// PrintStation is a printing device (e.g. a warehouse packing desk) that polls for jobs.
+// With AutoQueuePaid every order that becomes paid is queued on it.
+type PrintStation struct {
+	gorm.Model
+	ID            string       `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID    string       `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	Name          string       `gorm:"column:name;type:text;not null" json:"name"`
+	Kind          PrintKind    `gorm:"column:kind;type:text;not null;default:'receipt'" json:"kind"`
+	Format        PrintFormat  `gorm:"column:format;type:text;not null;default:'escpos'" json:"format"`
+	AutoQueuePaid bool         `gorm:"column:auto_queue_paid;type:boolean;not null;default:true" json:"autoQueuePaid"`
+	LastPolledAt  sql.NullTime `gorm:"column:last_polled_at" json:"lastPolledAt"`
+}
+
+func (m *PrintStation) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(PrintStationPrefix)
+	}
+	return
+}
+
+var PrintStationSchema = struct {
+	ID            schema.Field
+	BusinessID    schema.Field
+	Name          schema.Field
+	AutoQueuePaid schema.Field
+	LastPolledAt  schema.Field
+	CreatedAt     schema.Field
+}{
+	ID:            schema.NewField("id", "id"),
+	BusinessID:    schema.NewField("business_id", "businessId"),
+	Name:          schema.NewField("name", "name"),
+	AutoQueuePaid: schema.NewField("auto_queue_paid", "autoQueuePaid"),
+	LastPolledAt:  schema.NewField("last_polled_at", "lastPolledAt"),
+	CreatedAt:     schema.NewField("created_at", "createdAt"),
+}
+
+const (
+	PrintJobTable  = "print_jobs"
+	PrintJobStruct = "PrintJob"
+	PrintJobPrefix = "prj"
+)
+
+// PrintJob is one order document waiting to be (or already) printed by a station.
+type PrintJob struct {
+	gorm.Model
+	ID          string         `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID  string         `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	StationID   string         `gorm:"column:station_id;type:text;not null" json:"stationId"`
+	Station     *PrintStation  `gorm:"foreignKey:StationID;references:ID;OnDelete:CASCADE" json:"station,omitempty"`
+	OrderID     string         `gorm:"column:order_id;type:text;not null;index" json:"orderId"`
+	Order       *Order         `gorm:"foreignKey:OrderID;references:ID;OnDelete:CASCADE" json:"order,omitempty"`
+	Kind        PrintKind      `gorm:"column:kind;type:text;not null" json:"kind"`
+	Source      PrintJobSource `gorm:"column:source;type:text;not null" json:"source"`
+	Status      PrintJobStatus `gorm:"column:status;type:text;not null;default:'queued'" json:"status"`
+	PrintedAt   sql.NullTime   `gorm:"column:printed_at" json:"printedAt"`
+	PrintedByID string         `gorm:"column:printed_by_id;type:text" json:"printedById,omitempty"`
+}
+
+func (m *PrintJob) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(PrintJobPrefix)
+	}
+	return
+}
+
+var PrintJobSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	StationID  schema.Field
+	OrderID    schema.Field
+	Kind       schema.Field
+	Source     schema.Field
+	Status     schema.Field
+	PrintedAt  schema.Field
+	CreatedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	StationID:  schema.NewField("station_id", "stationId"),
+	OrderID:    schema.NewField("order_id", "orderId"),
+	Kind:       schema.NewField("kind", "kind"),
+	Source:     schema.NewField("source", "source"),
+	Status:     schema.NewField("status", "status"),
+	PrintedAt:  schema.NewField("printed_at", "printedAt"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+}
@@ -0,0 +1,173 @@
+package order
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// QuoteStatus tracks a quote from drafting to the customer's answer.
+//
+//	draft → sent → accepted (converted to an order)
+//	             → declined
+//	             → expired (validUntil passed before an answer)
+type QuoteStatus string
+
+const (
+	QuoteStatusDraft    QuoteStatus = "draft"
+	QuoteStatusSent     QuoteStatus = "sent"
+	QuoteStatusAccepted QuoteStatus = "accepted"
+	QuoteStatusDeclined QuoteStatus = "declined"
+	QuoteStatusExpired  QuoteStatus = "expired"
+)
+
+// defaultQuoteValidity is used when a quote is created without validUntil.
+const defaultQuoteValidity = 30 * 24 * time.Hour
+
+const (
+	QuoteTable  = "quotes"
+	QuoteStruct = "Quote"
+	QuotePrefix = "quo"
+)
+
+// Quote is a priced offer (quotation / proforma) sent to a customer before an order exists.
+// Accepting it creates an order with the same items and prices.
+type Quote struct {
+	gorm.Model
+	ID                string                    `gorm:"column:id;primaryKey;type:text" json:"id"`
+	QuoteNumber       string                    `gorm:"column:quote_number;type:text;not null;uniqueIndex:quote_number_business_id_idx" json:"quoteNumber"`
+	BusinessID        string                    `gorm:"column:business_id;type:text;not null;index;uniqueIndex:quote_number_business_id_idx" json:"businessId"`
+	Business          *business.Business        `gorm:"foreignKey:BusinessID;references:ID" json:"business,omitempty"`
+	CustomerID        string                    `gorm:"column:customer_id;type:text;not null;index" json:"customerId"`
+	Customer          *customer.Customer        `gorm:"foreignKey:CustomerID;references:ID" json:"customer,omitempty"`
+	ShippingAddressID sql.NullString            `gorm:"column:shipping_address_id;type:text" json:"shippingAddressId,omitempty"`
+	ShippingAddress   *customer.CustomerAddress `gorm:"foreignKey:ShippingAddressID;references:ID" json:"shippingAddress,omitempty"`
+	Subtotal          decimal.Decimal           `gorm:"column:subtotal;type:numeric;not null;default:0" json:"subtotal"`
+	VAT               decimal.Decimal           `gorm:"column:vat;type:numeric;not null;default:0" json:"vat"`
+	VATRate           decimal.Decimal           `gorm:"column:vat_rate;type:numeric;not null;default:0" json:"vatRate"`
+	PricesIncludeVAT  bool                      `gorm:"column:prices_include_vat;type:boolean;not null;default:false" json:"pricesIncludeVat"`
+	ShippingFee       decimal.Decimal           `gorm:"column:shipping_fee;type:numeric;not null;default:0" json:"shippingFee"`
+	Discount          decimal.Decimal           `gorm:"column:discount;type:numeric;not null;default:0" json:"discount"`
+	DiscountType      DiscountType              `gorm:"column:discount_type;type:text" json:"discountType,omitempty"`
+	DiscountValue     decimal.Decimal           `gorm:"column:discount_value;type:numeric;default:0" json:"discountValue,omitempty"`
+	Total             decimal.Decimal           `gorm:"column:total;type:numeric;not null;default:0" json:"total"`
+	Currency          string                    `gorm:"column:currency;type:text;not null" json:"currency"`
+	Status            QuoteStatus               `gorm:"column:status;type:text;not null;default:'draft';index" json:"status"`
+	ValidUntil        time.Time                 `gorm:"column:valid_until;type:timestamptz;not null" json:"validUntil"`
+	Terms             string                    `gorm:"column:terms;type:text" json:"terms,omitempty"`
+	SentAt            sql.NullTime              `gorm:"column:sent_at" json:"sentAt"`
+	AcceptedAt        sql.NullTime              `gorm:"column:accepted_at" json:"acceptedAt"`
+	DeclinedAt        sql.NullTime              `gorm:"column:declined_at" json:"declinedAt"`
+	ExpiredAt         sql.NullTime              `gorm:"column:expired_at" json:"expiredAt"`
+	OrderID           sql.NullString            `gorm:"column:order_id;type:text;index" json:"orderId,omitempty"`
+	Items             []*QuoteItem              `gorm:"foreignKey:QuoteID;references:ID" json:"items"`
+}
+
+func (q *Quote) BeforeCreate(tx *gorm.DB) (err error) {
+	if q.ID == "" {
+		q.ID = id.KsuidWithPrefix(QuotePrefix)
+	}
+	return
+}
+
+// IsEditable reports whether the quote content can still change.
+func (q *Quote) IsEditable() bool {
+	return q.Status == QuoteStatusDraft
+}
+
+var QuoteSchema = struct {
+	ID                schema.Field
+	QuoteNumber       schema.Field
+	BusinessID        schema.Field
+	CustomerID        schema.Field
+	ShippingAddressID schema.Field
+	Subtotal          schema.Field
+	Total             schema.Field
+	Currency          schema.Field
+	Status            schema.Field
+	ValidUntil        schema.Field
+	SentAt            schema.Field
+	AcceptedAt        schema.Field
+	DeclinedAt        schema.Field
+	ExpiredAt         schema.Field
+	OrderID           schema.Field
+	CreatedAt         schema.Field
+	UpdatedAt         schema.Field
+	DeletedAt         schema.Field
+}{
+	ID:                schema.NewField("id", "id"),
+	QuoteNumber:       schema.NewField("quote_number", "quoteNumber"),
+	BusinessID:        schema.NewField("business_id", "businessId"),
+	CustomerID:        schema.NewField("customer_id", "customerId"),
+	ShippingAddressID: schema.NewField("shipping_address_id", "shippingAddressId"),
+	Subtotal:          schema.NewField("subtotal", "subtotal"),
+	Total:             schema.NewField("total", "total"),
+	Currency:          schema.NewField("currency", "currency"),
+	Status:            schema.NewField("status", "status"),
+	ValidUntil:        schema.NewField("valid_until", "validUntil"),
+	SentAt:            schema.NewField("sent_at", "sentAt"),
+	AcceptedAt:        schema.NewField("accepted_at", "acceptedAt"),
+	DeclinedAt:        schema.NewField("declined_at", "declinedAt"),
+	ExpiredAt:         schema.NewField("expired_at", "expiredAt"),
+	OrderID:           schema.NewField("order_id", "orderId"),
+	CreatedAt:         schema.NewField("created_at", "createdAt"),
+	UpdatedAt:         schema.NewField("updated_at", "updatedAt"),
+	DeletedAt:         schema.NewField("deleted_at", "deletedAt"),
+}
+
+const (
+	QuoteItemTable  = "quote_items"
+	QuoteItemStruct = "Items"
+	QuoteItemPrefix = "qitm"
+)
+
+// QuoteItem is one priced line of a quote. Quotes carry no cost: COGS is taken from the
+// variant when the quote becomes an order.
+type QuoteItem struct {
+	gorm.Model
+	ID        string             `gorm:"column:id;primaryKey;type:text" json:"id"`
+	QuoteID   string             `gorm:"column:quote_id;type:text;not null;index" json:"quoteId"`
+	Quote     *Quote             `gorm:"foreignKey:QuoteID;references:ID;OnDelete:CASCADE" json:"quote,omitempty"`
+	ProductID string             `gorm:"column:product_id;type:text;not null;index" json:"productId"`
+	Product   *inventory.Product `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
+	VariantID string             `gorm:"column:variant_id;type:text;not null;index" json:"variantId"`
+	Variant   *inventory.Variant `gorm:"foreignKey:VariantID;references:ID" json:"variant,omitempty"`
+	Quantity  int                `gorm:"column:quantity;type:int;not null;default:1" json:"quantity"`
+	Currency  string             `gorm:"column:currency;type:text;not null" json:"currency"`
+	UnitPrice decimal.Decimal    `gorm:"column:unit_price;type:numeric;not null;default:0" json:"unitPrice"`
+	Total     decimal.Decimal    `gorm:"column:total;type:numeric;not null;default:0" json:"total"`
+}
+
+func (m *QuoteItem) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(QuoteItemPrefix)
+	}
+	return
+}
+
+var QuoteItemSchema = struct {
+	ID        schema.Field
+	QuoteID   schema.Field
+	ProductID schema.Field
+	VariantID schema.Field
+	Quantity  schema.Field
+	UnitPrice schema.Field
+	Total     schema.Field
+	CreatedAt schema.Field
+}{
+	ID:        schema.NewField("id", "id"),
+	QuoteID:   schema.NewField("quote_id", "quoteId"),
+	ProductID: schema.NewField("product_id", "productId"),
+	VariantID: schema.NewField("variant_id", "variantId"),
+	Quantity:  schema.NewField("quantity", "quantity"),
+	UnitPrice: schema.NewField("unit_price", "unitPrice"),
+	Total:     schema.NewField("total", "total"),
+	CreatedAt: schema.NewField("created_at", "createdAt"),
+}
@@ -0,0 +1,174 @@
+package order
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"gorm.io/gorm"
+)
+
+const (
+	RecurringOrderTable  = "recurring_orders"
+	RecurringOrderStruct = "RecurringOrder"
+	RecurringOrderPrefix = "ror"
+)
+
+// RecurringOrderFrequency is how often a recurring order creates an order.
+type RecurringOrderFrequency string
+
+const (
+	RecurringOrderFrequencyWeekly  RecurringOrderFrequency = "weekly"
+	RecurringOrderFrequencyMonthly RecurringOrderFrequency = "monthly"
+)
+
+// GetNextRecurrenceDate returns the run date following from.
+func (f RecurringOrderFrequency) GetNextRecurrenceDate(from time.Time) time.Time {
+	switch f {
+	case RecurringOrderFrequencyWeekly:
+		return from.AddDate(0, 0, 7)
+	case RecurringOrderFrequencyMonthly:
+		return from.AddDate(0, 1, 0)
+	default:
+		return from
+	}
+}
+
+// RecurringOrderStatus tracks whether a recurring order still creates orders.
+//
+//	active ⇄ paused
+//	active → ended (the end date passed)
+type RecurringOrderStatus string
+
+const (
+	RecurringOrderStatusActive RecurringOrderStatus = "active"
+	RecurringOrderStatusPaused RecurringOrderStatus = "paused"
+	RecurringOrderStatusEnded  RecurringOrderStatus = "ended"
+)
+
+// RecurringOrderItem is one line of a recurring order template. Prices are not stored:
+// each generated order is priced from the customer's price list at generation time.
+type RecurringOrderItem struct {
+	VariantID string `json:"variantId"`
+	Quantity  int    `json:"quantity"`
+}
+
+// RecurringOrderItems are the lines every generated order is created with.
+type RecurringOrderItems []RecurringOrderItem
+
+func (it RecurringOrderItems) Value() (driver.Value, error) {
+	if it == nil {
+		it = RecurringOrderItems{}
+	}
+	b, err := json.Marshal([]RecurringOrderItem(it))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (it *RecurringOrderItems) Scan(value any) error {
+	if it == nil {
+		return problem.InternalError().WithError(errors.New("RecurringOrderItems scan into nil receiver"))
+	}
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*it = RecurringOrderItems{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return problem.InternalError().WithError(errors.New("unexpected scan type for RecurringOrderItems"))
+	}
+	return json.Unmarshal(raw, (*[]RecurringOrderItem)(it))
+}
+
+// RecurringOrder is an order template (a product subscription) that creates a pending order
+// for the customer on a weekly or monthly cadence. Orders are generated by the
+// orders-generate-recurring command; runs whose items are out of stock are skipped and the
+// reason is kept in LastSkipReason.
+type RecurringOrder struct {
+	gorm.Model
+	ID                string                    `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID        string                    `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	Business          *business.Business        `gorm:"foreignKey:BusinessID;references:ID" json:"business,omitempty"`
+	CustomerID        string                    `gorm:"column:customer_id;type:text;not null;index" json:"customerId"`
+	Customer          *customer.Customer        `gorm:"foreignKey:CustomerID;references:ID" json:"customer,omitempty"`
+	ShippingAddressID string                    `gorm:"column:shipping_address_id;type:text;not null" json:"shippingAddressId"`
+	ShippingAddress   *customer.CustomerAddress `gorm:"foreignKey:ShippingAddressID;references:ID" json:"shippingAddress,omitempty"`
+	ShippingZoneID    *string                   `gorm:"column:shipping_zone_id;type:text" json:"shippingZoneId,omitempty"`
+	Channel           string                    `gorm:"column:channel;type:text;not null" json:"channel"`
+	PaymentMethod     OrderPaymentMethod        `gorm:"column:payment_method;type:text;not null" json:"paymentMethod"`
+	Frequency         RecurringOrderFrequency   `gorm:"column:frequency;type:text;not null" json:"frequency"`
+	Status            RecurringOrderStatus      `gorm:"column:status;type:text;not null;default:'active'" json:"status"`
+	StartDate         time.Time                 `gorm:"column:start_date;type:date;not null" json:"startDate"`
+	EndDate           sql.NullTime              `gorm:"column:end_date;type:date" json:"endDate"`
+	NextRunDate       time.Time                 `gorm:"column:next_run_date;type:date;not null" json:"nextRunDate"`
+	Items             RecurringOrderItems       `gorm:"column:items;type:jsonb;not null;default:'[]'" json:"items"`
+	Tags              OrderTags                 `gorm:"column:tags;type:jsonb;not null;default:'[]'" json:"tags"`
+	Note              string                    `gorm:"column:note;type:text" json:"note,omitempty"`
+	OrdersCreated     int                       `gorm:"column:orders_created;type:int;not null;default:0" json:"ordersCreated"`
+	LastRunAt         sql.NullTime              `gorm:"column:last_run_at" json:"lastRunAt"`
+	LastOrderID       sql.NullString            `gorm:"column:last_order_id;type:text" json:"lastOrderId,omitempty"`
+	LastSkipReason    string                    `gorm:"column:last_skip_reason;type:text" json:"lastSkipReason,omitempty"`
+}
+
+func (r *RecurringOrder) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == "" {
+		r.ID = id.KsuidWithPrefix(RecurringOrderPrefix)
+	}
+	return
+}
+
+// scheduleAfter moves the next run to the first cadence date after today and ends the
+// recurring order once that date passes its end date.
+func (r *RecurringOrder) scheduleAfter(today time.Time) {
+	next := r.Frequency.GetNextRecurrenceDate(r.NextRunDate)
+	for !next.After(today) {
+		next = r.Frequency.GetNextRecurrenceDate(next)
+	}
+	r.NextRunDate = next
+	if r.EndDate.Valid && next.After(r.EndDate.Time) {
+		r.Status = RecurringOrderStatusEnded
+	}
+}
+
+var RecurringOrderSchema = struct {
+	ID            schema.Field
+	BusinessID    schema.Field
+	CustomerID    schema.Field
+	Frequency     schema.Field
+	Status        schema.Field
+	StartDate     schema.Field
+	EndDate       schema.Field
+	NextRunDate   schema.Field
+	OrdersCreated schema.Field
+	LastRunAt     schema.Field
+	CreatedAt     schema.Field
+	UpdatedAt     schema.Field
+	DeletedAt     schema.Field
+}{
+	ID:            schema.NewField("id", "id"),
+	BusinessID:    schema.NewField("business_id", "businessId"),
+	CustomerID:    schema.NewField("customer_id", "customerId"),
+	Frequency:     schema.NewField("frequency", "frequency"),
+	Status:        schema.NewField("status", "status"),
+	StartDate:     schema.NewField("start_date", "startDate"),
+	EndDate:       schema.NewField("end_date", "endDate"),
+	NextRunDate:   schema.NewField("next_run_date", "nextRunDate"),
+	OrdersCreated: schema.NewField("orders_created", "ordersCreated"),
+	LastRunAt:     schema.NewField("last_run_at", "lastRunAt"),
+	CreatedAt:     schema.NewField("created_at", "createdAt"),
+	UpdatedAt:     schema.NewField("updated_at", "updatedAt"),
+	DeletedAt:     schema.NewField("deleted_at", "deletedAt"),
+}
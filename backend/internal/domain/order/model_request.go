@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/types/date"
 	"github.com/shopspring/decimal"
 )
 
@@ -12,12 +14,17 @@ type CreateOrderRequest struct {
 	Channel           string          `json:"channel" binding:"required"`
 	ShippingAddressID string          `json:"shippingAddressId" binding:"required"`
 	ShippingZoneID    *string         `json:"shippingZoneId" binding:"omitempty"`
-	ShippingFee       decimal.Decimal `json:"shippingFee" binding:"omitempty"`
+	ShippingFee       decimal.Decimal `json:"shippingFee" binding:"omitempty,dgte=0"`
+	// Optional stock location the items are drawn from. Defaults to the business' default location.
+	LocationID string `json:"locationId" binding:"omitempty"`
 	// Legacy discount field (amount-based). Still supported for backward compatibility.
-	Discount decimal.Decimal `json:"discount" binding:"omitempty"`
+	Discount decimal.Decimal `json:"discount" binding:"omitempty,dgte=0"`
 	// New discount fields (preferred). When provided, these take precedence over Discount.
 	DiscountType  DiscountType    `json:"discountType" binding:"omitempty,oneof=amount percent"`
-	DiscountValue decimal.Decimal `json:"discountValue" binding:"omitempty"`
+	DiscountValue decimal.Decimal `json:"discountValue" binding:"omitempty,dgte=0"`
+	// Optional coupon code. The coupon's discount replaces the discount fields above, which
+	// must then be left empty.
+	CouponCode string `json:"couponCode" binding:"omitempty,max=50"`
 	// Optional target order status (advanced). If provided, backend will attempt to apply it atomically.
 	Status *OrderStatus `json:"status" binding:"omitempty,oneof=pending placed ready_for_shipment shipped fulfilled cancelled returned"`
 	// Optional target payment status (advanced). If provided, backend will attempt to apply it atomically.
@@ -26,22 +33,33 @@ type CreateOrderRequest struct {
 	PaymentReference sql.NullString      `json:"paymentReference" binding:"omitempty"`
 	OrderedAt        time.Time           `json:"orderedAt" binding:"omitempty"`
 	// Optional single note content. If provided, a note will be created as part of order creation.
-	Note  string                    `json:"note" binding:"omitempty"`
-	Items []*CreateOrderItemRequest `json:"items" binding:"required,dive,required"`
+	Note string `json:"note" binding:"omitempty"`
+	// Optional tags, e.g. "gift" or "wholesale". Tags are lower-cased and deduplicated.
+	Tags []string `json:"tags" binding:"omitempty"`
+	// Optional merchant-defined attributes of the order.
+	CustomFields map[string]string         `json:"customFields" binding:"omitempty"`
+	Items        []*CreateOrderItemRequest `json:"items" binding:"required,dive,required"`
 }
 
 type UpdateOrderRequest struct {
 	ShippingAddressID *string             `json:"shippingAddressId" binding:"omitempty"`
 	ShippingZoneID    *string             `json:"shippingZoneId" binding:"omitempty"`
-	ShippingFee       decimal.NullDecimal `json:"shippingFee" binding:"omitempty"`
+	ShippingFee       decimal.NullDecimal `json:"shippingFee" binding:"omitempty,dgte=0"`
 	Channel           string              `json:"channel" binding:"omitempty"`
 	// Legacy discount field (amount-based). Still supported for backward compatibility.
-	Discount decimal.NullDecimal `json:"discount" binding:"omitempty"`
+	Discount decimal.NullDecimal `json:"discount" binding:"omitempty,dgte=0"`
 	// New discount fields (preferred). When provided, these take precedence over Discount.
 	DiscountType  DiscountType              `json:"discountType" binding:"omitempty,oneof=amount percent"`
-	DiscountValue decimal.NullDecimal       `json:"discountValue" binding:"omitempty"`
+	DiscountValue decimal.NullDecimal       `json:"discountValue" binding:"omitempty,dgte=0"`
 	OrderedAt     time.Time                 `json:"orderedAt" binding:"omitempty"`
 	Items         []*CreateOrderItemRequest `json:"items,omitempty" binding:"omitempty,dive,required"`
+	// Tags replaces the order's tags when set; an empty list clears them.
+	Tags *[]string `json:"tags,omitempty" binding:"omitempty"`
+	// CustomFields is merged into the order's custom fields; a null value removes the key.
+	CustomFields map[string]*string `json:"customFields,omitempty" binding:"omitempty"`
+	// Version is the order version the client last read. When set, the update is rejected
+	// with 409 if the order has changed since.
+	Version *int64 `json:"version,omitempty" binding:"omitempty,min=1"`
 }
 
 type AddOrderPaymentDetailsRequest struct {
@@ -49,11 +67,71 @@ type AddOrderPaymentDetailsRequest struct {
 	PaymentReference sql.NullString     `json:"paymentReference" binding:"omitempty"`
 }
 
+// RecordOrderPaymentRequest records a payment received for an order. PaidAt defaults to now.
+type RecordOrderPaymentRequest struct {
+	Amount    decimal.Decimal    `json:"amount" binding:"dgt=0"`
+	Method    OrderPaymentMethod `json:"method" binding:"required,oneof=credit_card paypal bank_transfer cash_on_delivery tamara tabby"`
+	Reference string             `json:"reference" binding:"omitempty,max=255"`
+	Note      string             `json:"note" binding:"omitempty,max=500"`
+	PaidAt    *time.Time         `json:"paidAt" binding:"omitempty"`
+}
+
+// CreateOrderPaymentLinkRequest sets where Stripe Checkout sends the customer after paying
+// or abandoning the payment.
+type CreateOrderPaymentLinkRequest struct {
+	SuccessURL string `json:"successUrl" binding:"required,url"`
+	CancelURL  string `json:"cancelUrl" binding:"omitempty,url"`
+}
+
+// SetOrderTagsRequest replaces the tags of an order.
+type SetOrderTagsRequest struct {
+	Tags []string `json:"tags" binding:"omitempty"`
+}
+
+// RenameOrderTagRequest renames a tag on every order of the business.
+type RenameOrderTagRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// ShipmentParcelRequest is the package an order ships in.
+type ShipmentParcelRequest struct {
+	WeightKg decimal.Decimal `json:"weightKg" binding:"dgt=0"`
+	LengthCm decimal.Decimal `json:"lengthCm" binding:"dgt=0"`
+	WidthCm  decimal.Decimal `json:"widthCm" binding:"dgt=0"`
+	HeightCm decimal.Decimal `json:"heightCm" binding:"dgt=0"`
+}
+
+// ShipFromRequest is where the parcel is collected. The country is the business country;
+// addressLine1 and phone default to the business address and phone number.
+type ShipFromRequest struct {
+	City         string `json:"city" binding:"required"`
+	AddressLine1 string `json:"addressLine1" binding:"omitempty"`
+	PostalCode   string `json:"postalCode" binding:"omitempty"`
+	Phone        string `json:"phone" binding:"omitempty"`
+}
+
+// OrderShippingRatesRequest asks carriers to price the shipment of an order. An empty
+// carrier quotes every configured carrier.
+type OrderShippingRatesRequest struct {
+	Carrier  business.CarrierCode  `json:"carrier" binding:"omitempty"`
+	ShipFrom ShipFromRequest       `json:"shipFrom"`
+	Parcel   ShipmentParcelRequest `json:"parcel"`
+}
+
+// PurchaseOrderShippingLabelRequest buys a label with a service returned by the rates endpoint.
+type PurchaseOrderShippingLabelRequest struct {
+	Carrier  business.CarrierCode  `json:"carrier" binding:"required"`
+	Service  string                `json:"service" binding:"required"`
+	ShipFrom ShipFromRequest       `json:"shipFrom"`
+	Parcel   ShipmentParcelRequest `json:"parcel"`
+}
+
 type CreateOrderItemRequest struct {
-	VariantID string          `json:"variantId" binding:"required"`
-	Quantity  int             `json:"quantity" binding:"required,min=1"`
-	UnitPrice decimal.Decimal `json:"unitPrice" binding:"required"`
-	UnitCost  decimal.Decimal `json:"unitCost" binding:"omitempty"`
+	VariantID string `json:"variantId" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"required,min=1"`
+	// UnitPrice defaults to the customer's price list (or the variant's sale price) when omitted.
+	UnitPrice decimal.Decimal `json:"unitPrice" binding:"omitempty,dgt=0"`
+	UnitCost  decimal.Decimal `json:"unitCost" binding:"omitempty,dgte=0"`
 }
 
 type CreateOrderNoteRequest struct {
@@ -78,13 +156,25 @@ type listOrdersQuery struct {
 	SocialPlatforms []string  `form:"socialPlatforms" binding:"omitempty"`
 	CustomerID      string    `form:"customerId" binding:"omitempty"`
 	OrderNumber     string    `form:"orderNumber" binding:"omitempty"`
+	Tags            []string  `form:"tags" binding:"omitempty"`
 	From            time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00" binding:"omitempty"`
 	To              time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" binding:"omitempty"`
+	View            string    `form:"view" binding:"omitempty,oneof=full summary"`
+}
+
+// customerStatementQuery represents the query parameters for a customer statement.
+// from and to are YYYY-MM-DD dates in the business timezone, both inclusive.
+type customerStatementQuery struct {
+	From   string `form:"from" binding:"omitempty"`
+	To     string `form:"to" binding:"omitempty"`
+	Format string `form:"format" binding:"omitempty,oneof=json csv pdf"`
 }
 
 // updateOrderStatusRequest represents the request to update order status.
 type updateOrderStatusRequest struct {
 	Status OrderStatus `json:"status" binding:"required,oneof=pending placed ready_for_shipment shipped fulfilled cancelled returned"`
+	// Restock puts the items of a returned order back in stock. Ignored for other statuses.
+	Restock bool `json:"restock"`
 }
 
 // updateOrderPaymentStatusRequest represents the request to update payment status.
@@ -107,3 +197,182 @@ type createOrderNoteRequest struct {
 type updateOrderNoteRequest struct {
 	Content string `json:"content" binding:"required"`
 }
+
+// reconcileOrderTotalsRequest controls an on-demand order totals reconciliation.
+type reconcileOrderTotalsRequest struct {
+	// SinceDays limits the check to orders created in the last N days (default 30).
+	SinceDays int  `json:"sinceDays" binding:"omitempty,min=1,max=365"`
+	Repair    bool `json:"repair"`
+}
+
+// CreateQuoteRequest is the request DTO for drafting a quote.
+type CreateQuoteRequest struct {
+	CustomerID string `json:"customerId" binding:"required"`
+	// Optional shipping address; required later when the quote is accepted unless provided then.
+	ShippingAddressID string          `json:"shippingAddressId" binding:"omitempty"`
+	ShippingFee       decimal.Decimal `json:"shippingFee" binding:"omitempty,dgte=0"`
+	DiscountType      DiscountType    `json:"discountType" binding:"omitempty,oneof=amount percent"`
+	DiscountValue     decimal.Decimal `json:"discountValue" binding:"omitempty,dgte=0"`
+	// ValidUntil defaults to 30 days from now.
+	ValidUntil time.Time                 `json:"validUntil" binding:"omitempty"`
+	Terms      string                    `json:"terms" binding:"omitempty,max=5000"`
+	Items      []*CreateQuoteItemRequest `json:"items" binding:"required,min=1,max=100,dive,required"`
+}
+
+// CreateQuoteItemRequest is one quote line. UnitPrice defaults to the customer's price list
+// (or the variant's sale price).
+type CreateQuoteItemRequest struct {
+	VariantID string          `json:"variantId" binding:"required"`
+	Quantity  int             `json:"quantity" binding:"required,min=1"`
+	UnitPrice decimal.Decimal `json:"unitPrice" binding:"omitempty,dgte=0"`
+}
+
+// UpdateQuoteRequest updates a draft quote. Items, when provided, replace all lines.
+type UpdateQuoteRequest struct {
+	ShippingAddressID *string                   `json:"shippingAddressId" binding:"omitempty"`
+	ShippingFee       decimal.NullDecimal       `json:"shippingFee" binding:"omitempty,dgte=0"`
+	DiscountType      DiscountType              `json:"discountType" binding:"omitempty,oneof=amount percent"`
+	DiscountValue     decimal.NullDecimal       `json:"discountValue" binding:"omitempty,dgte=0"`
+	ValidUntil        *time.Time                `json:"validUntil" binding:"omitempty"`
+	Terms             *string                   `json:"terms" binding:"omitempty,max=5000"`
+	Items             []*CreateQuoteItemRequest `json:"items,omitempty" binding:"omitempty,min=1,max=100,dive,required"`
+}
+
+// AcceptQuoteRequest records the customer's acceptance and creates the order.
+type AcceptQuoteRequest struct {
+	Channel string `json:"channel" binding:"required"`
+	// Overrides the quote's shipping address; required when the quote has none.
+	ShippingAddressID string             `json:"shippingAddressId" binding:"omitempty"`
+	ShippingZoneID    *string            `json:"shippingZoneId" binding:"omitempty"`
+	PaymentMethod     OrderPaymentMethod `json:"paymentMethod" binding:"omitempty,oneof=credit_card paypal bank_transfer cash_on_delivery tamara tabby"`
+	Note              string             `json:"note" binding:"omitempty"`
+}
+
+// listQuotesQuery represents the query parameters for listing quotes.
+type listQuotesQuery struct {
+	Page       int      `form:"page" binding:"omitempty,min=1"`
+	PageSize   int      `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	OrderBy    []string `form:"orderBy" binding:"omitempty"`
+	Status     []string `form:"status" binding:"omitempty,dive,oneof=draft sent accepted declined expired"`
+	CustomerID string   `form:"customerId" binding:"omitempty"`
+}
+
+// CreateCouponRequest creates a discount code. Percent values are 0-100.
+type CreateCouponRequest struct {
+	Code               string          `json:"code" binding:"required,min=3,max=50"`
+	Description        string          `json:"description" binding:"omitempty,max=500"`
+	DiscountType       DiscountType    `json:"discountType" binding:"required,oneof=amount percent"`
+	DiscountValue      decimal.Decimal `json:"discountValue" binding:"required,dgt=0"`
+	MinSubtotal        decimal.Decimal `json:"minSubtotal" binding:"omitempty,dgte=0"`
+	MaxUses            int             `json:"maxUses" binding:"omitempty,min=0"`
+	MaxUsesPerCustomer int             `json:"maxUsesPerCustomer" binding:"omitempty,min=0"`
+	CustomerID         string          `json:"customerId" binding:"omitempty"`
+	StartsAt           *time.Time      `json:"startsAt" binding:"omitempty"`
+	ExpiresAt          *time.Time      `json:"expiresAt" binding:"omitempty"`
+	Active             *bool           `json:"active"`
+}
+
+// UpdateCouponRequest updates a coupon; omitted fields are kept. Orders keep the discount
+// they were given.
+type UpdateCouponRequest struct {
+	Description        *string             `json:"description" binding:"omitempty,max=500"`
+	DiscountType       DiscountType        `json:"discountType" binding:"omitempty,oneof=amount percent"`
+	DiscountValue      decimal.NullDecimal `json:"discountValue" binding:"omitempty,dgt=0"`
+	MinSubtotal        decimal.NullDecimal `json:"minSubtotal" binding:"omitempty,dgte=0"`
+	MaxUses            *int                `json:"maxUses" binding:"omitempty,min=0"`
+	MaxUsesPerCustomer *int                `json:"maxUsesPerCustomer" binding:"omitempty,min=0"`
+	StartsAt           *time.Time          `json:"startsAt" binding:"omitempty"`
+	ExpiresAt          *time.Time          `json:"expiresAt" binding:"omitempty"`
+	Active             *bool               `json:"active"`
+}
+
+// CreateOrderExportRequest queues an order export. Format defaults to csv; From and To
+// filter on orderedAt.
+type CreateOrderExportRequest struct {
+	Format        ExportFormat         `json:"format" binding:"omitempty,oneof=csv xlsx"`
+	Status        []OrderStatus        `json:"status" binding:"omitempty,dive,oneof=pending placed ready_for_shipment shipped fulfilled cancelled returned"`
+	PaymentStatus []OrderPaymentStatus `json:"paymentStatus" binding:"omitempty,dive,oneof=pending paid failed refunded"`
+	From          *time.Time           `json:"from" binding:"omitempty"`
+	To            *time.Time           `json:"to" binding:"omitempty"`
+}
+
+// CreatePrintStationRequest registers a printing device. Kind defaults to receipt, Format to
+// escpos and AutoQueuePaid to true.
+type CreatePrintStationRequest struct {
+	Name          string      `json:"name" binding:"required,max=100"`
+	Kind          PrintKind   `json:"kind" binding:"omitempty,oneof=receipt pick_ticket"`
+	Format        PrintFormat `json:"format" binding:"omitempty,oneof=escpos html"`
+	AutoQueuePaid *bool       `json:"autoQueuePaid"`
+}
+
+// UpdatePrintStationRequest updates a print station; omitted fields are kept.
+type UpdatePrintStationRequest struct {
+	Name          *string     `json:"name" binding:"omitempty,min=1,max=100"`
+	Kind          PrintKind   `json:"kind" binding:"omitempty,oneof=receipt pick_ticket"`
+	Format        PrintFormat `json:"format" binding:"omitempty,oneof=escpos html"`
+	AutoQueuePaid *bool       `json:"autoQueuePaid"`
+}
+
+// QueueOrderPrintRequest queues an order on a station. Kind defaults to the station's kind.
+type QueueOrderPrintRequest struct {
+	StationID string    `json:"stationId" binding:"required"`
+	Kind      PrintKind `json:"kind" binding:"omitempty,oneof=receipt pick_ticket"`
+}
+
+// printDocumentQuery selects the document and format of a rendered print.
+type printDocumentQuery struct {
+	Kind   string `form:"kind" binding:"omitempty,oneof=receipt pick_ticket"`
+	Format string `form:"format" binding:"omitempty,oneof=escpos html"`
+}
+
+// RecurringOrderItemRequest is one line of a recurring order template.
+type RecurringOrderItemRequest struct {
+	VariantID string `json:"variantId" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"required,min=1"`
+}
+
+// CreateRecurringOrderRequest sets up a product subscription. StartDate is the date of the
+// first generated order and defaults to today.
+type CreateRecurringOrderRequest struct {
+	CustomerID        string                       `json:"customerId" binding:"required"`
+	ShippingAddressID string                       `json:"shippingAddressId" binding:"required"`
+	ShippingZoneID    *string                      `json:"shippingZoneId" binding:"omitempty"`
+	Channel           string                       `json:"channel" binding:"required"`
+	PaymentMethod     OrderPaymentMethod           `json:"paymentMethod" binding:"omitempty,oneof=credit_card paypal bank_transfer cash_on_delivery tamara tabby"`
+	Frequency         RecurringOrderFrequency      `json:"frequency" binding:"required,oneof=weekly monthly"`
+	StartDate         *date.Date                   `json:"startDate" binding:"omitempty"`
+	EndDate           *date.Date                   `json:"endDate" binding:"omitempty"`
+	Note              string                       `json:"note" binding:"omitempty,max=2000"`
+	Tags              []string                     `json:"tags" binding:"omitempty"`
+	Items             []*RecurringOrderItemRequest `json:"items" binding:"required,min=1,max=100,dive,required"`
+}
+
+// UpdateRecurringOrderRequest updates a recurring order; omitted fields are kept. Items,
+// when provided, replace all lines. NextRunDate reschedules the next generated order.
+type UpdateRecurringOrderRequest struct {
+	ShippingAddressID *string                      `json:"shippingAddressId" binding:"omitempty"`
+	ShippingZoneID    *string                      `json:"shippingZoneId" binding:"omitempty"`
+	Channel           *string                      `json:"channel" binding:"omitempty,min=1"`
+	PaymentMethod     OrderPaymentMethod           `json:"paymentMethod" binding:"omitempty,oneof=credit_card paypal bank_transfer cash_on_delivery tamara tabby"`
+	Frequency         RecurringOrderFrequency      `json:"frequency" binding:"omitempty,oneof=weekly monthly"`
+	NextRunDate       *date.Date                   `json:"nextRunDate" binding:"omitempty"`
+	EndDate           *date.Date                   `json:"endDate" binding:"omitempty"`
+	Note              *string                      `json:"note" binding:"omitempty,max=2000"`
+	Tags              *[]string                    `json:"tags" binding:"omitempty"`
+	Items             []*RecurringOrderItemRequest `json:"items,omitempty" binding:"omitempty,min=1,max=100,dive,required"`
+}
+
+// customerTimelineQuery represents the query parameters for a customer's activity timeline.
+type customerTimelineQuery struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"pageSize" binding:"omitempty,min=1,max=100"`
+}
+
+// listRecurringOrdersQuery represents the query parameters for listing recurring orders.
+type listRecurringOrdersQuery struct {
+	Page       int      `form:"page" binding:"omitempty,min=1"`
+	PageSize   int      `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	OrderBy    []string `form:"orderBy" binding:"omitempty"`
+	Status     []string `form:"status" binding:"omitempty,dive,oneof=active paused ended"`
+	CustomerID string   `form:"customerId" binding:"omitempty"`
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/abdelrahman146/kyora/internal/domain/business"
 	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
 	"github.com/abdelrahman146/kyora/internal/platform/types/asset"
 	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
 	"github.com/shopspring/decimal"
@@ -15,6 +16,7 @@ import (
 // No DeletedAt field (GORM leakage removed)
 type OrderResponse struct {
 	ID                 string                            `json:"id"`
+	Version            int64                             `json:"version"`
 	OrderNumber        string                            `json:"orderNumber"`
 	BusinessID         string                            `json:"businessId"`
 	CustomerID         string                            `json:"customerId"`
@@ -23,53 +25,70 @@ type OrderResponse struct {
 	ShippingAddress    *customer.CustomerAddressResponse `json:"shippingAddress,omitempty"`
 	ShippingZoneID     *string                           `json:"shippingZoneId,omitempty"`
 	ShippingZone       *business.ShippingZoneResponse    `json:"shippingZone,omitempty"`
+	LocationID         string                            `json:"locationId,omitempty"`
 	Channel            string                            `json:"channel"`
 	Subtotal           decimal.Decimal                   `json:"subtotal"`
 	VAT                decimal.Decimal                   `json:"vat"`
 	VATRate            decimal.Decimal                   `json:"vatRate"`
+	PricesIncludeVAT   bool                              `json:"pricesIncludeVat"`
 	ShippingFee        decimal.Decimal                   `json:"shippingFee"`
+	ShippingExempt     bool                              `json:"shippingExempt"`
 	Discount           decimal.Decimal                   `json:"discount"`
 	DiscountType       DiscountType                      `json:"discountType,omitempty"`
 	DiscountValue      decimal.Decimal                   `json:"discountValue,omitempty"`
-	COGS               decimal.Decimal                   `json:"cogs"`
+	CouponCode         *string                           `json:"couponCode,omitempty"`
+	COGS               *decimal.Decimal                  `json:"cogs,omitempty"`
 	Total              decimal.Decimal                   `json:"total"`
+	AmountPaid         decimal.Decimal                   `json:"amountPaid"`
+	AmountDue          decimal.Decimal                   `json:"amountDue"`
 	Currency           string                            `json:"currency"`
 	Status             OrderStatus                       `json:"status"`
 	PaymentStatus      OrderPaymentStatus                `json:"paymentStatus"`
+	PaymentBalance     OrderPaymentBalance               `json:"paymentBalance"`
 	PaymentMethod      OrderPaymentMethod                `json:"paymentMethod"`
 	PaymentReference   *string                           `json:"paymentReference,omitempty"`
+	PaymentLinkURL     *string                           `json:"paymentLinkUrl,omitempty"`
+	CheckoutExpiresAt  *time.Time                        `json:"paymentLinkExpiresAt,omitempty"`
+	Tags               []string                          `json:"tags"`
+	CustomFields       map[string]string                 `json:"customFields"`
+	DeliverySlot       *OrderDeliverySlotResponse        `json:"deliverySlot,omitempty"`
 	PlacedAt           *time.Time                        `json:"placedAt,omitempty"`
 	ReadyForShipmentAt *time.Time                        `json:"readyForShipmentAt,omitempty"`
 	OrderedAt          time.Time                         `json:"orderedAt"`
 	ShippedAt          *time.Time                        `json:"shippedAt,omitempty"`
 	FulfilledAt        *time.Time                        `json:"fulfilledAt,omitempty"`
 	CancelledAt        *time.Time                        `json:"cancelledAt,omitempty"`
+	ExpiredAt          *time.Time                        `json:"expiredAt,omitempty"`
 	ReturnedAt         *time.Time                        `json:"returnedAt,omitempty"`
 	PaidAt             *time.Time                        `json:"paidAt,omitempty"`
 	FailedAt           *time.Time                        `json:"failedAt,omitempty"`
 	RefundedAt         *time.Time                        `json:"refundedAt,omitempty"`
+	DownloadsSentAt    *time.Time                        `json:"downloadsSentAt,omitempty"`
 	Items              []OrderItemResponse               `json:"items,omitempty"`
 	Notes              []OrderNoteResponse               `json:"notes,omitempty"`
+	Payments           []OrderPaymentResponse            `json:"payments,omitempty"`
 	CreatedAt          time.Time                         `json:"createdAt"`
 	UpdatedAt          time.Time                         `json:"updatedAt"`
 }
 
 // OrderItemResponse is the API response for OrderItem entity
 type OrderItemResponse struct {
-	ID        string                    `json:"id"`
-	OrderID   string                    `json:"orderId"`
-	ProductID string                    `json:"productId"`
-	VariantID string                    `json:"variantId"`
-	Quantity  int                       `json:"quantity"`
-	Currency  string                    `json:"currency"`
-	UnitPrice decimal.Decimal           `json:"unitPrice"`
-	UnitCost  decimal.Decimal           `json:"unitCost"`
-	TotalCost decimal.Decimal           `json:"totalCost"`
-	Total     decimal.Decimal           `json:"total"`
-	Product   *OrderItemProductResponse `json:"product,omitempty"`
-	Variant   *OrderItemVariantResponse `json:"variant,omitempty"`
-	CreatedAt time.Time                 `json:"createdAt"`
-	UpdatedAt time.Time                 `json:"updatedAt"`
+	ID          string                    `json:"id"`
+	OrderID     string                    `json:"orderId"`
+	ProductID   string                    `json:"productId"`
+	VariantID   string                    `json:"variantId"`
+	Quantity    int                       `json:"quantity"`
+	ProductType inventory.ProductType     `json:"productType"`
+	Download    *asset.AssetReference     `json:"download,omitempty"`
+	Currency    string                    `json:"currency"`
+	UnitPrice   decimal.Decimal           `json:"unitPrice"`
+	UnitCost    *decimal.Decimal          `json:"unitCost,omitempty"`
+	TotalCost   *decimal.Decimal          `json:"totalCost,omitempty"`
+	Total       decimal.Decimal           `json:"total"`
+	Product     *OrderItemProductResponse `json:"product,omitempty"`
+	Variant     *OrderItemVariantResponse `json:"variant,omitempty"`
+	CreatedAt   time.Time                 `json:"createdAt"`
+	UpdatedAt   time.Time                 `json:"updatedAt"`
 }
 
 // OrderItemProductResponse is a simplified product representation in order items
@@ -98,28 +117,30 @@ type OrderNoteResponse struct {
 
 // OrderPreviewResponse is the API response for order preview calculations.
 type OrderPreviewResponse struct {
-	Subtotal       decimal.Decimal            `json:"subtotal"`
-	VAT            decimal.Decimal            `json:"vat"`
-	VATRate        decimal.Decimal            `json:"vatRate"`
-	ShippingFee    decimal.Decimal            `json:"shippingFee"`
-	Discount       decimal.Decimal            `json:"discount"`
-	COGS           decimal.Decimal            `json:"cogs"`
-	Total          decimal.Decimal            `json:"total"`
-	Currency       string                     `json:"currency"`
-	ShippingZoneID *string                    `json:"shippingZoneId,omitempty"`
-	PaymentMethod  OrderPaymentMethod         `json:"paymentMethod"`
-	Items          []OrderPreviewItemResponse `json:"items"`
+	Subtotal         decimal.Decimal            `json:"subtotal"`
+	VAT              decimal.Decimal            `json:"vat"`
+	VATRate          decimal.Decimal            `json:"vatRate"`
+	PricesIncludeVAT bool                       `json:"pricesIncludeVat"`
+	ShippingFee      decimal.Decimal            `json:"shippingFee"`
+	Discount         decimal.Decimal            `json:"discount"`
+	COGS             *decimal.Decimal           `json:"cogs,omitempty"`
+	Total            decimal.Decimal            `json:"total"`
+	Currency         string                     `json:"currency"`
+	ShippingZoneID   *string                    `json:"shippingZoneId,omitempty"`
+	ShippingExempt   bool                       `json:"shippingExempt"`
+	PaymentMethod    OrderPaymentMethod         `json:"paymentMethod"`
+	Items            []OrderPreviewItemResponse `json:"items"`
 }
 
 // OrderPreviewItemResponse is the per-item breakdown in preview responses.
 type OrderPreviewItemResponse struct {
-	VariantID string          `json:"variantId"`
-	ProductID string          `json:"productId"`
-	Quantity  int             `json:"quantity"`
-	UnitPrice decimal.Decimal `json:"unitPrice"`
-	UnitCost  decimal.Decimal `json:"unitCost"`
-	Total     decimal.Decimal `json:"total"`
-	TotalCost decimal.Decimal `json:"totalCost"`
+	VariantID string           `json:"variantId"`
+	ProductID string           `json:"productId"`
+	Quantity  int              `json:"quantity"`
+	UnitPrice decimal.Decimal  `json:"unitPrice"`
+	UnitCost  *decimal.Decimal `json:"unitCost,omitempty"`
+	Total     decimal.Decimal  `json:"total"`
+	TotalCost *decimal.Decimal `json:"totalCost,omitempty"`
 }
 
 // ToOrderResponse converts Order model to OrderResponse
@@ -150,9 +171,11 @@ func ToOrderResponse(ord *Order) OrderResponse {
 
 	items := ToOrderItemResponses(ord.Items)
 	notes := ToOrderNoteResponses(ord.Notes)
+	payments := ToOrderPaymentResponses(ord.Payments)
 
 	return OrderResponse{
 		ID:                 ord.ID,
+		Version:            ord.Version,
 		OrderNumber:        ord.OrderNumber,
 		BusinessID:         ord.BusinessID,
 		CustomerID:         ord.CustomerID,
@@ -161,38 +184,98 @@ func ToOrderResponse(ord *Order) OrderResponse {
 		ShippingAddress:    shippingAddressResp,
 		ShippingZoneID:     ord.ShippingZoneID,
 		ShippingZone:       shippingZoneResp,
+		LocationID:         ord.LocationID,
 		Channel:            ord.Channel,
 		Subtotal:           ord.Subtotal,
 		VAT:                ord.VAT,
 		VATRate:            ord.VATRate,
+		PricesIncludeVAT:   ord.PricesIncludeVAT,
 		ShippingFee:        ord.ShippingFee,
+		ShippingExempt:     ord.ShippingExempt,
 		Discount:           ord.Discount,
 		DiscountType:       ord.DiscountType,
 		DiscountValue:      ord.DiscountValue,
-		COGS:               ord.COGS,
+		CouponCode:         transformer.NullStringPtr(ord.CouponCode),
+		COGS:               &ord.COGS,
 		Total:              ord.Total,
+		AmountPaid:         ord.AmountPaid,
+		AmountDue:          ord.AmountDue(),
 		Currency:           ord.Currency,
 		Status:             ord.Status,
 		PaymentStatus:      ord.PaymentStatus,
+		PaymentBalance:     ord.PaymentBalance(),
 		PaymentMethod:      ord.PaymentMethod,
 		PaymentReference:   transformer.NullStringPtr(ord.PaymentReference),
+		PaymentLinkURL:     transformer.NullStringPtr(ord.PaymentLinkURL),
+		CheckoutExpiresAt:  transformer.NullTimePtr(ord.CheckoutExpiresAt),
+		Tags:               orderTagsResponse(ord.Tags),
+		CustomFields:       orderCustomFieldsResponse(ord.CustomFields),
+		DeliverySlot:       orderDeliverySlotResponse(ord),
 		PlacedAt:           transformer.NullTimePtr(ord.PlacedAt),
 		ReadyForShipmentAt: transformer.NullTimePtr(ord.ReadyForShipmentAt),
 		OrderedAt:          ord.OrderedAt,
 		ShippedAt:          transformer.NullTimePtr(ord.ShippedAt),
 		FulfilledAt:        transformer.NullTimePtr(ord.FulfilledAt),
 		CancelledAt:        transformer.NullTimePtr(ord.CancelledAt),
+		ExpiredAt:          transformer.NullTimePtr(ord.ExpiredAt),
 		ReturnedAt:         transformer.NullTimePtr(ord.ReturnedAt),
 		PaidAt:             transformer.NullTimePtr(ord.PaidAt),
 		FailedAt:           transformer.NullTimePtr(ord.FailedAt),
 		RefundedAt:         transformer.NullTimePtr(ord.RefundedAt),
+		DownloadsSentAt:    transformer.NullTimePtr(ord.DownloadsSentAt),
 		Items:              items,
 		Notes:              notes,
+		Payments:           payments,
 		CreatedAt:          ord.CreatedAt,
 		UpdatedAt:          ord.UpdatedAt,
 	}
 }
 
+// orderTagsResponse renders missing tags as an empty list rather than null.
+func orderTagsResponse(tags OrderTags) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// orderCustomFieldsResponse renders missing custom fields as an empty object rather than null.
+func orderCustomFieldsResponse(fields OrderCustomFields) map[string]string {
+	if fields == nil {
+		return map[string]string{}
+	}
+	return fields
+}
+
+// OrderTagResponse is a tag in use by the business' orders.
+type OrderTagResponse struct {
+	Tag    string `json:"tag"`
+	Orders int64  `json:"orders"`
+}
+
+// ToOrderTagResponses converts tag counts to responses
+func ToOrderTagResponses(counts []OrderTagCount) []OrderTagResponse {
+	responses := make([]OrderTagResponse, len(counts))
+	for i, tc := range counts {
+		responses[i] = OrderTagResponse{Tag: tc.Tag, Orders: tc.Orders}
+	}
+	return responses
+}
+
+// RetagOrdersResponse reports how many orders a tag rename or removal touched.
+type RetagOrdersResponse struct {
+	Updated int `json:"updated"`
+}
+
+// RedactFinancials drops cost figures (COGS and item costs) for actors who may not see financials.
+func (r *OrderResponse) RedactFinancials() {
+	r.COGS = nil
+	for i := range r.Items {
+		r.Items[i].UnitCost = nil
+		r.Items[i].TotalCost = nil
+	}
+}
+
 // ToOrderResponses converts a slice of Order models to responses
 func ToOrderResponses(orders []*Order) []OrderResponse {
 	responses := make([]OrderResponse, len(orders))
@@ -202,6 +285,65 @@ func ToOrderResponses(orders []*Order) []OrderResponse {
 	return responses
 }
 
+// OrderSummaryResponse is the lean list representation of an order.
+// It is returned by the orders list when view=summary and carries no nested items, notes or addresses.
+type OrderSummaryResponse struct {
+	ID            string             `json:"id"`
+	OrderNumber   string             `json:"orderNumber"`
+	BusinessID    string             `json:"businessId"`
+	CustomerID    string             `json:"customerId"`
+	CustomerName  string             `json:"customerName,omitempty"`
+	Channel       string             `json:"channel"`
+	Subtotal      decimal.Decimal    `json:"subtotal"`
+	Total         decimal.Decimal    `json:"total"`
+	Currency      string             `json:"currency"`
+	Status        OrderStatus        `json:"status"`
+	PaymentStatus OrderPaymentStatus `json:"paymentStatus"`
+	PaymentMethod OrderPaymentMethod `json:"paymentMethod"`
+	Tags          []string           `json:"tags"`
+	OrderedAt     time.Time          `json:"orderedAt"`
+	CreatedAt     time.Time          `json:"createdAt"`
+	UpdatedAt     time.Time          `json:"updatedAt"`
+}
+
+// ToOrderSummaryResponse converts Order model to OrderSummaryResponse
+func ToOrderSummaryResponse(ord *Order) OrderSummaryResponse {
+	if ord == nil {
+		return OrderSummaryResponse{}
+	}
+
+	resp := OrderSummaryResponse{
+		ID:            ord.ID,
+		OrderNumber:   ord.OrderNumber,
+		BusinessID:    ord.BusinessID,
+		CustomerID:    ord.CustomerID,
+		Channel:       ord.Channel,
+		Subtotal:      ord.Subtotal,
+		Total:         ord.Total,
+		Currency:      ord.Currency,
+		Status:        ord.Status,
+		PaymentStatus: ord.PaymentStatus,
+		PaymentMethod: ord.PaymentMethod,
+		Tags:          orderTagsResponse(ord.Tags),
+		OrderedAt:     ord.OrderedAt,
+		CreatedAt:     ord.CreatedAt,
+		UpdatedAt:     ord.UpdatedAt,
+	}
+	if ord.Customer != nil {
+		resp.CustomerName = ord.Customer.Name
+	}
+	return resp
+}
+
+// ToOrderSummaryResponses converts a slice of Order models to summary responses
+func ToOrderSummaryResponses(orders []*Order) []OrderSummaryResponse {
+	responses := make([]OrderSummaryResponse, len(orders))
+	for i, ord := range orders {
+		responses[i] = ToOrderSummaryResponse(ord)
+	}
+	return responses
+}
+
 // ToOrderItemResponse converts OrderItem model to OrderItemResponse
 func ToOrderItemResponse(item *OrderItem) OrderItemResponse {
 	if item == nil {
@@ -232,20 +374,22 @@ func ToOrderItemResponse(item *OrderItem) OrderItemResponse {
 	}
 
 	return OrderItemResponse{
-		ID:        item.ID,
-		OrderID:   item.OrderID,
-		ProductID: item.ProductID,
-		VariantID: item.VariantID,
-		Quantity:  item.Quantity,
-		Currency:  item.Currency,
-		UnitPrice: item.UnitPrice,
-		UnitCost:  item.UnitCost,
-		TotalCost: item.TotalCost,
-		Total:     item.Total,
-		Product:   productResp,
-		Variant:   variantResp,
-		CreatedAt: item.CreatedAt,
-		UpdatedAt: item.UpdatedAt,
+		ID:          item.ID,
+		OrderID:     item.OrderID,
+		ProductID:   item.ProductID,
+		VariantID:   item.VariantID,
+		Quantity:    item.Quantity,
+		ProductType: item.ProductType,
+		Download:    item.Download,
+		Currency:    item.Currency,
+		UnitPrice:   item.UnitPrice,
+		UnitCost:    &item.UnitCost,
+		TotalCost:   &item.TotalCost,
+		Total:       item.Total,
+		Product:     productResp,
+		Variant:     variantResp,
+		CreatedAt:   item.CreatedAt,
+		UpdatedAt:   item.UpdatedAt,
 	}
 }
 
@@ -289,17 +433,28 @@ func ToOrderPreviewResponse(preview *OrderPreview) OrderPreviewResponse {
 	}
 
 	return OrderPreviewResponse{
-		Subtotal:       preview.Subtotal,
-		VAT:            preview.VAT,
-		VATRate:        preview.VATRate,
-		ShippingFee:    preview.ShippingFee,
-		Discount:       preview.Discount,
-		COGS:           preview.COGS,
-		Total:          preview.Total,
-		Currency:       preview.Currency,
-		ShippingZoneID: preview.ShippingZoneID,
-		PaymentMethod:  preview.PaymentMethod,
-		Items:          ToOrderPreviewItemResponses(preview.Items),
+		Subtotal:         preview.Subtotal,
+		VAT:              preview.VAT,
+		VATRate:          preview.VATRate,
+		PricesIncludeVAT: preview.PricesIncludeVAT,
+		ShippingFee:      preview.ShippingFee,
+		Discount:         preview.Discount,
+		COGS:             &preview.COGS,
+		Total:            preview.Total,
+		Currency:         preview.Currency,
+		ShippingZoneID:   preview.ShippingZoneID,
+		ShippingExempt:   preview.ShippingExempt,
+		PaymentMethod:    preview.PaymentMethod,
+		Items:            ToOrderPreviewItemResponses(preview.Items),
+	}
+}
+
+// RedactFinancials drops cost figures (COGS and item costs) for actors who may not see financials.
+func (r *OrderPreviewResponse) RedactFinancials() {
+	r.COGS = nil
+	for i := range r.Items {
+		r.Items[i].UnitCost = nil
+		r.Items[i].TotalCost = nil
 	}
 }
 
@@ -312,10 +467,681 @@ func ToOrderPreviewItemResponses(items []OrderPreviewItem) []OrderPreviewItemRes
 			ProductID: item.ProductID,
 			Quantity:  item.Quantity,
 			UnitPrice: item.UnitPrice,
-			UnitCost:  item.UnitCost,
+			UnitCost:  &item.UnitCost,
 			Total:     item.Total,
-			TotalCost: item.TotalCost,
+			TotalCost: &item.TotalCost,
 		}
 	}
 	return responses
 }
+
+// QuoteResponse is the API response for Quote entity
+type QuoteResponse struct {
+	ID                string                            `json:"id"`
+	QuoteNumber       string                            `json:"quoteNumber"`
+	BusinessID        string                            `json:"businessId"`
+	CustomerID        string                            `json:"customerId"`
+	Customer          *customer.CustomerResponse        `json:"customer,omitempty"`
+	ShippingAddressID *string                           `json:"shippingAddressId,omitempty"`
+	ShippingAddress   *customer.CustomerAddressResponse `json:"shippingAddress,omitempty"`
+	Subtotal          decimal.Decimal                   `json:"subtotal"`
+	VAT               decimal.Decimal                   `json:"vat"`
+	VATRate           decimal.Decimal                   `json:"vatRate"`
+	PricesIncludeVAT  bool                              `json:"pricesIncludeVat"`
+	ShippingFee       decimal.Decimal                   `json:"shippingFee"`
+	Discount          decimal.Decimal                   `json:"discount"`
+	DiscountType      DiscountType                      `json:"discountType,omitempty"`
+	DiscountValue     decimal.Decimal                   `json:"discountValue,omitempty"`
+	Total             decimal.Decimal                   `json:"total"`
+	Currency          string                            `json:"currency"`
+	Status            QuoteStatus                       `json:"status"`
+	ValidUntil        time.Time                         `json:"validUntil"`
+	Terms             string                            `json:"terms,omitempty"`
+	SentAt            *time.Time                        `json:"sentAt,omitempty"`
+	AcceptedAt        *time.Time                        `json:"acceptedAt,omitempty"`
+	DeclinedAt        *time.Time                        `json:"declinedAt,omitempty"`
+	ExpiredAt         *time.Time                        `json:"expiredAt,omitempty"`
+	OrderID           *string                           `json:"orderId,omitempty"`
+	Items             []QuoteItemResponse               `json:"items,omitempty"`
+	CreatedAt         time.Time                         `json:"createdAt"`
+	UpdatedAt         time.Time                         `json:"updatedAt"`
+}
+
+// QuoteItemResponse is the API response for QuoteItem entity
+type QuoteItemResponse struct {
+	ID        string                    `json:"id"`
+	QuoteID   string                    `json:"quoteId"`
+	ProductID string                    `json:"productId"`
+	VariantID string                    `json:"variantId"`
+	Quantity  int                       `json:"quantity"`
+	Currency  string                    `json:"currency"`
+	UnitPrice decimal.Decimal           `json:"unitPrice"`
+	Total     decimal.Decimal           `json:"total"`
+	Product   *OrderItemProductResponse `json:"product,omitempty"`
+	Variant   *OrderItemVariantResponse `json:"variant,omitempty"`
+}
+
+// AcceptQuoteResponse is returned when a quote is accepted and converted to an order.
+type AcceptQuoteResponse struct {
+	Quote QuoteResponse `json:"quote"`
+	Order OrderResponse `json:"order"`
+}
+
+// ToQuoteResponse converts Quote model to QuoteResponse
+func ToQuoteResponse(q *Quote) QuoteResponse {
+	if q == nil {
+		return QuoteResponse{}
+	}
+
+	var customerResp *customer.CustomerResponse
+	if q.Customer != nil {
+		resp := customer.ToCustomerResponse(q.Customer, 0, 0.0)
+		customerResp = &resp
+	}
+
+	var shippingAddressResp *customer.CustomerAddressResponse
+	if q.ShippingAddress != nil {
+		resp := customer.ToCustomerAddressResponse(q.ShippingAddress)
+		shippingAddressResp = &resp
+	}
+
+	return QuoteResponse{
+		ID:                q.ID,
+		QuoteNumber:       q.QuoteNumber,
+		BusinessID:        q.BusinessID,
+		CustomerID:        q.CustomerID,
+		Customer:          customerResp,
+		ShippingAddressID: transformer.NullStringPtr(q.ShippingAddressID),
+		ShippingAddress:   shippingAddressResp,
+		Subtotal:          q.Subtotal,
+		VAT:               q.VAT,
+		VATRate:           q.VATRate,
+		PricesIncludeVAT:  q.PricesIncludeVAT,
+		ShippingFee:       q.ShippingFee,
+		Discount:          q.Discount,
+		DiscountType:      q.DiscountType,
+		DiscountValue:     q.DiscountValue,
+		Total:             q.Total,
+		Currency:          q.Currency,
+		Status:            q.Status,
+		ValidUntil:        q.ValidUntil,
+		Terms:             q.Terms,
+		SentAt:            transformer.NullTimePtr(q.SentAt),
+		AcceptedAt:        transformer.NullTimePtr(q.AcceptedAt),
+		DeclinedAt:        transformer.NullTimePtr(q.DeclinedAt),
+		ExpiredAt:         transformer.NullTimePtr(q.ExpiredAt),
+		OrderID:           transformer.NullStringPtr(q.OrderID),
+		Items:             ToQuoteItemResponses(q.Items),
+		CreatedAt:         q.CreatedAt,
+		UpdatedAt:         q.UpdatedAt,
+	}
+}
+
+// ToQuoteResponses converts a slice of Quote models to responses
+func ToQuoteResponses(quotes []*Quote) []QuoteResponse {
+	responses := make([]QuoteResponse, len(quotes))
+	for i, q := range quotes {
+		responses[i] = ToQuoteResponse(q)
+	}
+	return responses
+}
+
+// ToQuoteItemResponses converts a slice of QuoteItem models to responses
+func ToQuoteItemResponses(items []*QuoteItem) []QuoteItemResponse {
+	responses := make([]QuoteItemResponse, len(items))
+	for i, item := range items {
+		resp := QuoteItemResponse{
+			ID:        item.ID,
+			QuoteID:   item.QuoteID,
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+			Currency:  item.Currency,
+			UnitPrice: item.UnitPrice,
+			Total:     item.Total,
+		}
+		if item.Product != nil {
+			photos := []asset.AssetReference{}
+			if item.Product.Photos != nil {
+				photos = []asset.AssetReference(item.Product.Photos)
+			}
+			resp.Product = &OrderItemProductResponse{ID: item.Product.ID, Name: item.Product.Name, Photos: photos}
+		}
+		if item.Variant != nil {
+			resp.Variant = &OrderItemVariantResponse{ID: item.Variant.ID, Name: item.Variant.Name, Code: item.Variant.Code, SKU: item.Variant.SKU}
+		}
+		responses[i] = resp
+	}
+	return responses
+}
+
+// StatementEntryResponse is one line of a customer statement.
+type StatementEntryResponse struct {
+	Date        time.Time          `json:"date"`
+	Type        StatementEntryType `json:"type"`
+	OrderID     string             `json:"orderId"`
+	OrderNumber string             `json:"orderNumber"`
+	Description string             `json:"description"`
+	Debit       decimal.Decimal    `json:"debit"`
+	Credit      decimal.Decimal    `json:"credit"`
+	Balance     decimal.Decimal    `json:"balance"`
+}
+
+// CustomerStatementResponse is the API response for a customer statement.
+type CustomerStatementResponse struct {
+	CustomerID     string                   `json:"customerId"`
+	CustomerName   string                   `json:"customerName"`
+	Currency       string                   `json:"currency"`
+	From           time.Time                `json:"from"`
+	To             time.Time                `json:"to"`
+	OpeningBalance decimal.Decimal          `json:"openingBalance"`
+	TotalOrdered   decimal.Decimal          `json:"totalOrdered"`
+	TotalPaid      decimal.Decimal          `json:"totalPaid"`
+	TotalRefunded  decimal.Decimal          `json:"totalRefunded"`
+	TotalCredited  decimal.Decimal          `json:"totalCredited"`
+	ClosingBalance decimal.Decimal          `json:"closingBalance"`
+	Entries        []StatementEntryResponse `json:"entries"`
+}
+
+// ToCustomerStatementResponse converts a CustomerStatement to its API response
+func ToCustomerStatementResponse(st *CustomerStatement) CustomerStatementResponse {
+	entries := make([]StatementEntryResponse, len(st.Entries))
+	for i, e := range st.Entries {
+		entries[i] = StatementEntryResponse{
+			Date:        e.Date,
+			Type:        e.Type,
+			OrderID:     e.OrderID,
+			OrderNumber: e.OrderNumber,
+			Description: e.Description,
+			Debit:       e.Debit,
+			Credit:      e.Credit,
+			Balance:     e.Balance,
+		}
+	}
+	return CustomerStatementResponse{
+		CustomerID:     st.Customer.ID,
+		CustomerName:   st.Customer.Name,
+		Currency:       st.Currency,
+		From:           st.From,
+		To:             st.To,
+		OpeningBalance: st.OpeningBalance,
+		TotalOrdered:   st.TotalOrdered,
+		TotalPaid:      st.TotalPaid,
+		TotalRefunded:  st.TotalRefunded,
+		TotalCredited:  st.TotalCredited,
+		ClosingBalance: st.ClosingBalance,
+		Entries:        entries,
+	}
+}
+
+// PrintStationResponse is the API response for PrintStation entity
+type PrintStationResponse struct {
+	ID            string      `json:"id"`
+	BusinessID    string      `json:"businessId"`
+	Name          string      `json:"name"`
+	Kind          PrintKind   `json:"kind"`
+	Format        PrintFormat `json:"format"`
+	AutoQueuePaid bool        `json:"autoQueuePaid"`
+	LastPolledAt  *time.Time  `json:"lastPolledAt"`
+	CreatedAt     time.Time   `json:"createdAt"`
+	UpdatedAt     time.Time   `json:"updatedAt"`
+}
+
+// ToPrintStationResponse converts PrintStation model to PrintStationResponse
+func ToPrintStationResponse(st *PrintStation) PrintStationResponse {
+	return PrintStationResponse{
+		ID:            st.ID,
+		BusinessID:    st.BusinessID,
+		Name:          st.Name,
+		Kind:          st.Kind,
+		Format:        st.Format,
+		AutoQueuePaid: st.AutoQueuePaid,
+		LastPolledAt:  transformer.NullTimePtr(st.LastPolledAt),
+		CreatedAt:     st.CreatedAt,
+		UpdatedAt:     st.UpdatedAt,
+	}
+}
+
+// ToPrintStationResponses converts a slice of PrintStation models to responses
+func ToPrintStationResponses(stations []*PrintStation) []PrintStationResponse {
+	responses := make([]PrintStationResponse, len(stations))
+	for i, st := range stations {
+		responses[i] = ToPrintStationResponse(st)
+	}
+	return responses
+}
+
+// PrintJobResponse is the API response for PrintJob entity
+type PrintJobResponse struct {
+	ID          string         `json:"id"`
+	StationID   string         `json:"stationId"`
+	OrderID     string         `json:"orderId"`
+	OrderNumber string         `json:"orderNumber,omitempty"`
+	Kind        PrintKind      `json:"kind"`
+	Source      PrintJobSource `json:"source"`
+	Status      PrintJobStatus `json:"status"`
+	PrintedAt   *time.Time     `json:"printedAt"`
+	CreatedAt   time.Time      `json:"createdAt"`
+}
+
+// ToPrintJobResponse converts PrintJob model to PrintJobResponse
+func ToPrintJobResponse(job *PrintJob) PrintJobResponse {
+	resp := PrintJobResponse{
+		ID:        job.ID,
+		StationID: job.StationID,
+		OrderID:   job.OrderID,
+		Kind:      job.Kind,
+		Source:    job.Source,
+		Status:    job.Status,
+		PrintedAt: transformer.NullTimePtr(job.PrintedAt),
+		CreatedAt: job.CreatedAt,
+	}
+	if job.Order != nil {
+		resp.OrderNumber = job.Order.OrderNumber
+	}
+	return resp
+}
+
+// ToPrintJobResponses converts a slice of PrintJob models to responses
+func ToPrintJobResponses(jobs []*PrintJob) []PrintJobResponse {
+	responses := make([]PrintJobResponse, len(jobs))
+	for i, job := range jobs {
+		responses[i] = ToPrintJobResponse(job)
+	}
+	return responses
+}
+
+// CouponResponse is the API response for Coupon entity
+type CouponResponse struct {
+	ID                 string          `json:"id"`
+	BusinessID         string          `json:"businessId"`
+	Code               string          `json:"code"`
+	Description        string          `json:"description"`
+	DiscountType       DiscountType    `json:"discountType"`
+	DiscountValue      decimal.Decimal `json:"discountValue"`
+	MinSubtotal        decimal.Decimal `json:"minSubtotal"`
+	MaxUses            int             `json:"maxUses"`
+	MaxUsesPerCustomer int             `json:"maxUsesPerCustomer"`
+	CustomerID         *string         `json:"customerId"`
+	UsedCount          int             `json:"usedCount"`
+	StartsAt           *time.Time      `json:"startsAt"`
+	ExpiresAt          *time.Time      `json:"expiresAt"`
+	Active             bool            `json:"active"`
+	CreatedAt          time.Time       `json:"createdAt"`
+	UpdatedAt          time.Time       `json:"updatedAt"`
+}
+
+// ToCouponResponse converts Coupon model to CouponResponse
+func ToCouponResponse(c *Coupon) CouponResponse {
+	return CouponResponse{
+		ID:                 c.ID,
+		BusinessID:         c.BusinessID,
+		Code:               c.Code,
+		Description:        c.Description,
+		DiscountType:       c.DiscountType,
+		DiscountValue:      c.DiscountValue,
+		MinSubtotal:        c.MinSubtotal,
+		MaxUses:            c.MaxUses,
+		MaxUsesPerCustomer: c.MaxUsesPerCustomer,
+		CustomerID:         transformer.NullStringPtr(c.CustomerID),
+		UsedCount:          c.UsedCount,
+		StartsAt:           transformer.NullTimePtr(c.StartsAt),
+		ExpiresAt:          transformer.NullTimePtr(c.ExpiresAt),
+		Active:             c.Active,
+		CreatedAt:          c.CreatedAt,
+		UpdatedAt:          c.UpdatedAt,
+	}
+}
+
+// ToCouponResponses converts a slice of Coupon models to responses
+func ToCouponResponses(coupons []*Coupon) []CouponResponse {
+	responses := make([]CouponResponse, len(coupons))
+	for i, c := range coupons {
+		responses[i] = ToCouponResponse(c)
+	}
+	return responses
+}
+
+// OrderExportResponse is the API response for OrderExport entity
+type OrderExportResponse struct {
+	ID          string        `json:"id"`
+	Format      ExportFormat  `json:"format"`
+	Status      ExportStatus  `json:"status"`
+	Filters     ExportFilters `json:"filters"`
+	RowCount    int           `json:"rowCount"`
+	SizeBytes   int64         `json:"sizeBytes"`
+	Error       string        `json:"error,omitempty"`
+	DownloadURL string        `json:"downloadUrl,omitempty"`
+	CompletedAt *time.Time    `json:"completedAt"`
+	ExpiresAt   *time.Time    `json:"expiresAt"`
+	CreatedAt   time.Time     `json:"createdAt"`
+}
+
+// ToOrderExportResponse converts OrderExport model to OrderExportResponse. Completed
+// exports link to their download endpoint.
+func ToOrderExportResponse(biz *business.Business, e *OrderExport) OrderExportResponse {
+	resp := OrderExportResponse{
+		ID:          e.ID,
+		Format:      e.Format,
+		Status:      e.Status,
+		Filters:     e.Filters,
+		RowCount:    e.RowCount,
+		SizeBytes:   e.SizeBytes,
+		Error:       e.Error,
+		CompletedAt: transformer.NullTimePtr(e.CompletedAt),
+		ExpiresAt:   transformer.NullTimePtr(e.ExpiresAt),
+		CreatedAt:   e.CreatedAt,
+	}
+	if e.Status == ExportStatusCompleted {
+		resp.DownloadURL = "/v1/businesses/" + biz.Descriptor + "/orders/exports/" + e.ID + "/download"
+	}
+	return resp
+}
+
+// ToOrderExportResponses converts a slice of OrderExport models to responses
+func ToOrderExportResponses(biz *business.Business, exports []*OrderExport) []OrderExportResponse {
+	responses := make([]OrderExportResponse, len(exports))
+	for i, e := range exports {
+		responses[i] = ToOrderExportResponse(biz, e)
+	}
+	return responses
+}
+
+// OrderEventResponse is the API response for OrderEvent entity
+type OrderEventResponse struct {
+	ID        string             `json:"id"`
+	OrderID   string             `json:"orderId"`
+	Type      OrderEventType     `json:"type"`
+	ActorID   *string            `json:"actorId"`
+	Changes   []OrderFieldChange `json:"changes"`
+	CreatedAt time.Time          `json:"createdAt"`
+}
+
+// ToOrderEventResponse converts OrderEvent model to OrderEventResponse
+func ToOrderEventResponse(e *OrderEvent) OrderEventResponse {
+	changes := []OrderFieldChange(e.Changes)
+	if changes == nil {
+		changes = []OrderFieldChange{}
+	}
+	return OrderEventResponse{
+		ID:        e.ID,
+		OrderID:   e.OrderID,
+		Type:      e.Type,
+		ActorID:   transformer.NullStringPtr(e.ActorID),
+		Changes:   changes,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+// ToOrderEventResponses converts a slice of OrderEvent models to responses
+func ToOrderEventResponses(events []*OrderEvent) []OrderEventResponse {
+	responses := make([]OrderEventResponse, len(events))
+	for i, e := range events {
+		responses[i] = ToOrderEventResponse(e)
+	}
+	return responses
+}
+
+// RecurringOrderResponse is the API response for RecurringOrder entity
+type RecurringOrderResponse struct {
+	ID                string                            `json:"id"`
+	BusinessID        string                            `json:"businessId"`
+	CustomerID        string                            `json:"customerId"`
+	Customer          *customer.CustomerResponse        `json:"customer,omitempty"`
+	ShippingAddressID string                            `json:"shippingAddressId"`
+	ShippingAddress   *customer.CustomerAddressResponse `json:"shippingAddress,omitempty"`
+	ShippingZoneID    *string                           `json:"shippingZoneId,omitempty"`
+	Channel           string                            `json:"channel"`
+	PaymentMethod     OrderPaymentMethod                `json:"paymentMethod"`
+	Frequency         RecurringOrderFrequency           `json:"frequency"`
+	Status            RecurringOrderStatus              `json:"status"`
+	StartDate         time.Time                         `json:"startDate"`
+	EndDate           *time.Time                        `json:"endDate,omitempty"`
+	NextRunDate       time.Time                         `json:"nextRunDate"`
+	Items             []RecurringOrderItem              `json:"items"`
+	Tags              []string                          `json:"tags"`
+	Note              string                            `json:"note,omitempty"`
+	OrdersCreated     int                               `json:"ordersCreated"`
+	LastRunAt         *time.Time                        `json:"lastRunAt,omitempty"`
+	LastOrderID       *string                           `json:"lastOrderId,omitempty"`
+	LastSkipReason    string                            `json:"lastSkipReason,omitempty"`
+	CreatedAt         time.Time                         `json:"createdAt"`
+	UpdatedAt         time.Time                         `json:"updatedAt"`
+}
+
+// ToRecurringOrderResponse converts RecurringOrder model to RecurringOrderResponse
+func ToRecurringOrderResponse(r *RecurringOrder) RecurringOrderResponse {
+	if r == nil {
+		return RecurringOrderResponse{}
+	}
+
+	var customerResp *customer.CustomerResponse
+	if r.Customer != nil {
+		resp := customer.ToCustomerResponse(r.Customer, 0, 0.0)
+		customerResp = &resp
+	}
+
+	var shippingAddressResp *customer.CustomerAddressResponse
+	if r.ShippingAddress != nil {
+		resp := customer.ToCustomerAddressResponse(r.ShippingAddress)
+		shippingAddressResp = &resp
+	}
+
+	items := []RecurringOrderItem(r.Items)
+	if items == nil {
+		items = []RecurringOrderItem{}
+	}
+
+	return RecurringOrderResponse{
+		ID:                r.ID,
+		BusinessID:        r.BusinessID,
+		CustomerID:        r.CustomerID,
+		Customer:          customerResp,
+		ShippingAddressID: r.ShippingAddressID,
+		ShippingAddress:   shippingAddressResp,
+		ShippingZoneID:    r.ShippingZoneID,
+		Channel:           r.Channel,
+		PaymentMethod:     r.PaymentMethod,
+		Frequency:         r.Frequency,
+		Status:            r.Status,
+		StartDate:         r.StartDate,
+		EndDate:           transformer.NullTimePtr(r.EndDate),
+		NextRunDate:       r.NextRunDate,
+		Items:             items,
+		Tags:              orderTagsResponse(r.Tags),
+		Note:              r.Note,
+		OrdersCreated:     r.OrdersCreated,
+		LastRunAt:         transformer.NullTimePtr(r.LastRunAt),
+		LastOrderID:       transformer.NullStringPtr(r.LastOrderID),
+		LastSkipReason:    r.LastSkipReason,
+		CreatedAt:         r.CreatedAt,
+		UpdatedAt:         r.UpdatedAt,
+	}
+}
+
+// ToRecurringOrderResponses converts a slice of RecurringOrder models to responses
+func ToRecurringOrderResponses(recurringOrders []*RecurringOrder) []RecurringOrderResponse {
+	responses := make([]RecurringOrderResponse, len(recurringOrders))
+	for i, r := range recurringOrders {
+		responses[i] = ToRecurringOrderResponse(r)
+	}
+	return responses
+}
+
+// GenerateRecurringOrderResponse is returned when a recurring order's next order is created on demand.
+type GenerateRecurringOrderResponse struct {
+	RecurringOrder RecurringOrderResponse `json:"recurringOrder"`
+	Order          OrderResponse          `json:"order"`
+}
+
+// OrderDownloadResponse is a file delivered for a digital item of a paid order.
+type OrderDownloadResponse struct {
+	ItemID    string               `json:"itemId"`
+	ProductID string               `json:"productId"`
+	VariantID string               `json:"variantId"`
+	Name      string               `json:"name"`
+	Download  asset.AssetReference `json:"download"`
+}
+
+// ToOrderDownloadResponses maps the downloadable items of an order to their files.
+func ToOrderDownloadResponses(items []*OrderItem) []OrderDownloadResponse {
+	responses := make([]OrderDownloadResponse, 0, len(items))
+	for _, item := range items {
+		if item.Download == nil {
+			continue
+		}
+		responses = append(responses, OrderDownloadResponse{
+			ItemID:    item.ID,
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Name:      orderItemName(item),
+			Download:  *item.Download,
+		})
+	}
+	return responses
+}
+
+// CustomerDataExportResponse is the machine-readable bundle of everything stored about a
+// customer. The customer's ordersCount and totalSpent count orders that were not cancelled
+// or returned, like the customer endpoints.
+type CustomerDataExportResponse struct {
+	ExportedAt      time.Time                          `json:"exportedAt"`
+	Customer        customer.CustomerResponse          `json:"customer"`
+	Addresses       []customer.CustomerAddressResponse `json:"addresses"`
+	Notes           []customer.CustomerNoteResponse    `json:"notes"`
+	Orders          []OrderResponse                    `json:"orders"`
+	Quotes          []QuoteResponse                    `json:"quotes"`
+	RecurringOrders []RecurringOrderResponse           `json:"recurringOrders"`
+}
+
+// ToCustomerDataExportResponse converts a customer data export to the API response
+func ToCustomerDataExportResponse(e *CustomerDataExport) CustomerDataExportResponse {
+	ordersCount := 0
+	totalSpent := decimal.Zero
+	for _, o := range e.Orders {
+		if o.Status == OrderStatusCancelled || o.Status == OrderStatusReturned {
+			continue
+		}
+		ordersCount++
+		totalSpent = totalSpent.Add(o.Total)
+	}
+	return CustomerDataExportResponse{
+		ExportedAt:      e.ExportedAt,
+		Customer:        customer.ToCustomerResponse(e.Customer, ordersCount, totalSpent.InexactFloat64()),
+		Addresses:       customer.ToCustomerAddressResponses(e.Addresses),
+		Notes:           customer.ToCustomerNoteResponses(e.Notes),
+		Orders:          ToOrderResponses(e.Orders),
+		Quotes:          ToQuoteResponses(e.Quotes),
+		RecurringOrders: ToRecurringOrderResponses(e.RecurringOrders),
+	}
+}
+
+// CustomerProductPurchaseResponse is a favorite product in customer stats.
+type CustomerProductPurchaseResponse struct {
+	ProductID   string          `json:"productId"`
+	ProductName string          `json:"productName"`
+	Quantity    int64           `json:"quantity"`
+	OrdersCount int64           `json:"ordersCount"`
+	Revenue     decimal.Decimal `json:"revenue"`
+}
+
+// CustomerStatsResponse is the API response for customer stats.
+type CustomerStatsResponse struct {
+	CustomerID        string                            `json:"customerId"`
+	Currency          string                            `json:"currency"`
+	OrdersCount       int                               `json:"ordersCount"`
+	LifetimeValue     decimal.Decimal                   `json:"lifetimeValue"`
+	AverageOrderValue decimal.Decimal                   `json:"averageOrderValue"`
+	FirstOrderAt      *time.Time                        `json:"firstOrderAt"`
+	LastOrderAt       *time.Time                        `json:"lastOrderAt"`
+	PreferredChannel  string                            `json:"preferredChannel"`
+	FavoriteProducts  []CustomerProductPurchaseResponse `json:"favoriteProducts"`
+}
+
+// ToCustomerStatsResponse converts CustomerStats to its API response
+func ToCustomerStatsResponse(st *CustomerStats) CustomerStatsResponse {
+	products := make([]CustomerProductPurchaseResponse, len(st.FavoriteProducts))
+	for i, p := range st.FavoriteProducts {
+		products[i] = CustomerProductPurchaseResponse{
+			ProductID:   p.ProductID,
+			ProductName: p.ProductName,
+			Quantity:    p.Quantity,
+			OrdersCount: p.Orders,
+			Revenue:     p.Revenue,
+		}
+	}
+	return CustomerStatsResponse{
+		CustomerID:        st.Customer.ID,
+		Currency:          st.Currency,
+		OrdersCount:       st.OrdersCount,
+		LifetimeValue:     st.LifetimeValue,
+		AverageOrderValue: st.AverageOrderValue,
+		FirstOrderAt:      st.FirstOrderAt,
+		LastOrderAt:       st.LastOrderAt,
+		PreferredChannel:  st.PreferredChannel,
+		FavoriteProducts:  products,
+	}
+}
+
+// CustomerTimelineEntryResponse is one entry of a customer's activity timeline. Which of the
+// optional fields are set depends on the type:
+//   - order_placed: orderId, orderNumber, amount (order total), currency, channel
+//   - order_status_changed: orderId, orderNumber, status (the new status)
+//   - payment_recorded: orderId, orderNumber, amount, currency, method
+//   - note_added: content
+//   - address_added, address_updated, address_removed: location (city and country)
+type CustomerTimelineEntryResponse struct {
+	Type        CustomerTimelineEntryType `json:"type"`
+	OccurredAt  time.Time                 `json:"occurredAt"`
+	ID          string                    `json:"id"`
+	OrderID     string                    `json:"orderId,omitempty"`
+	OrderNumber string                    `json:"orderNumber,omitempty"`
+	Amount      *decimal.Decimal          `json:"amount,omitempty"`
+	Currency    string                    `json:"currency,omitempty"`
+	Channel     string                    `json:"channel,omitempty"`
+	Status      string                    `json:"status,omitempty"`
+	Method      string                    `json:"method,omitempty"`
+	Content     string                    `json:"content,omitempty"`
+	Location    string                    `json:"location,omitempty"`
+}
+
+// ToCustomerTimelineResponses converts customer timeline rows to their API responses
+func ToCustomerTimelineResponses(rows []CustomerTimelineRow) []CustomerTimelineEntryResponse {
+	out := make([]CustomerTimelineEntryResponse, len(rows))
+	for i, r := range rows {
+		e := CustomerTimelineEntryResponse{
+			Type:        CustomerTimelineEntryType(r.Type),
+			OccurredAt:  r.OccurredAt,
+			ID:          r.RefID,
+			OrderID:     timelineString(r.OrderID),
+			OrderNumber: timelineString(r.OrderNumber),
+			Currency:    timelineString(r.Currency),
+		}
+		if r.Amount.Valid {
+			amount := r.Amount.Decimal
+			e.Amount = &amount
+		}
+		detail := timelineString(r.Detail)
+		switch e.Type {
+		case CustomerTimelineOrderPlaced:
+			e.Channel = detail
+		case CustomerTimelineOrderStatusChanged:
+			e.Status = detail
+		case CustomerTimelinePaymentRecorded:
+			e.Method = detail
+		case CustomerTimelineNoteAdded:
+			e.Content = detail
+		case CustomerTimelineAddressAdded, CustomerTimelineAddressUpdated, CustomerTimelineAddressRemoved:
+			e.Location = detail
+		}
+		out[i] = e
+	}
+	return out
+}
+
+func timelineString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
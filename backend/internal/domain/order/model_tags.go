@@ -0,0 +1,185 @@
+package order
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+)
+
+const (
+	maxOrderTags          = 20
+	maxOrderTagLength     = 32
+	maxCustomFields       = 30
+	maxCustomFieldKeyLen  = 40
+	maxCustomFieldValueLn = 500
+)
+
+// orderTagPattern allows letters and digits of any script, spaces, "-" and "_".
+var orderTagPattern = regexp.MustCompile(`^[\p{L}\p{N} _-]+$`)
+
+// OrderTags are the free-form labels of an order ("gift", "wholesale", ...), stored
+// normalized: trimmed, lower-cased and unique.
+type OrderTags []string
+
+func (t OrderTags) Value() (driver.Value, error) {
+	if t == nil {
+		t = OrderTags{}
+	}
+	b, err := json.Marshal([]string(t))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (t *OrderTags) Scan(value any) error {
+	if t == nil {
+		return problem.InternalError().WithError(errors.New("OrderTags scan into nil receiver"))
+	}
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*t = OrderTags{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return problem.InternalError().WithError(errors.New("unexpected scan type for OrderTags"))
+	}
+	return json.Unmarshal(raw, (*[]string)(t))
+}
+
+// Contains reports whether the (normalized) tag is set.
+func (t OrderTags) Contains(tag string) bool {
+	for _, x := range t {
+		if x == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeOrderTag trims, lower-cases and collapses the inner spaces of a tag.
+func normalizeOrderTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// validateOrderTag checks a normalized tag.
+func validateOrderTag(tag string) error {
+	if tag == "" {
+		return ErrInvalidOrderTag(tag, "empty")
+	}
+	if utf8.RuneCountInString(tag) > maxOrderTagLength {
+		return ErrInvalidOrderTag(tag, "too_long")
+	}
+	if !orderTagPattern.MatchString(tag) {
+		return ErrInvalidOrderTag(tag, "invalid_characters")
+	}
+	return nil
+}
+
+// newOrderTags normalizes and validates tags, dropping duplicates while keeping their order.
+func newOrderTags(tags []string) (OrderTags, error) {
+	out := make(OrderTags, 0, len(tags))
+	for _, raw := range tags {
+		tag := normalizeOrderTag(raw)
+		if err := validateOrderTag(tag); err != nil {
+			return nil, err
+		}
+		if !out.Contains(tag) {
+			out = append(out, tag)
+		}
+	}
+	if len(out) > maxOrderTags {
+		return nil, ErrInvalidOrderTag("", "too_many")
+	}
+	return out, nil
+}
+
+// OrderCustomFields are merchant-defined key/value attributes of an order.
+type OrderCustomFields map[string]string
+
+func (f OrderCustomFields) Value() (driver.Value, error) {
+	if f == nil {
+		f = OrderCustomFields{}
+	}
+	b, err := json.Marshal(map[string]string(f))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (f *OrderCustomFields) Scan(value any) error {
+	if f == nil {
+		return problem.InternalError().WithError(errors.New("OrderCustomFields scan into nil receiver"))
+	}
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*f = OrderCustomFields{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return problem.InternalError().WithError(errors.New("unexpected scan type for OrderCustomFields"))
+	}
+	out := map[string]string{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return err
+	}
+	*f = out
+	return nil
+}
+
+// newOrderCustomFields validates the custom fields of a new order.
+func newOrderCustomFields(fields map[string]string) (OrderCustomFields, error) {
+	patch := make(map[string]*string, len(fields))
+	for k, v := range fields {
+		patch[k] = &v
+	}
+	return mergeCustomFields(nil, patch)
+}
+
+// mergeCustomFields applies a patch to the fields: keys set to null are removed, others are
+// set. Keys are trimmed and values kept as sent.
+func mergeCustomFields(fields OrderCustomFields, patch map[string]*string) (OrderCustomFields, error) {
+	out := make(OrderCustomFields, len(fields)+len(patch))
+	for k, v := range fields {
+		out[k] = v
+	}
+	keys := make([]string, 0, len(patch))
+	for k := range patch {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, raw := range keys {
+		key := strings.TrimSpace(raw)
+		if key == "" || utf8.RuneCountInString(key) > maxCustomFieldKeyLen {
+			return nil, ErrInvalidCustomField(raw, "invalid_key")
+		}
+		value := patch[raw]
+		if value == nil {
+			delete(out, key)
+			continue
+		}
+		if utf8.RuneCountInString(*value) > maxCustomFieldValueLn {
+			return nil, ErrInvalidCustomField(key, "value_too_long")
+		}
+		out[key] = *value
+	}
+	if len(out) > maxCustomFields {
+		return nil, ErrInvalidCustomField("", "too_many")
+	}
+	return out, nil
+}
@@ -0,0 +1,165 @@
+package order
+
+import (
+	"database/sql"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"gorm.io/gorm"
+)
+
+const (
+	OrderTransitionRuleTable  = "order_transition_rules"
+	OrderTransitionRuleStruct = "OrderTransitionRule"
+	OrderTransitionRulePrefix = "otr"
+)
+
+// OrderTransitionAction is what a transition rule does when an order moves to its status.
+//
+//	require_payment  blocks the transition until the order is marked paid
+//	mark_paid        marks the order paid along with the transition
+//	notify_customer  asks the team to message the customer (a follow-up task with the text)
+type OrderTransitionAction string
+
+const (
+	OrderTransitionActionRequirePayment OrderTransitionAction = "require_payment"
+	OrderTransitionActionMarkPaid       OrderTransitionAction = "mark_paid"
+	OrderTransitionActionNotifyCustomer OrderTransitionAction = "notify_customer"
+)
+
+// paymentRuleStatuses are the statuses payment rules can be attached to: the ones whose
+// orders may change payment status.
+var paymentRuleStatuses = []OrderStatus{OrderStatusPlaced, OrderStatusReadyForShipment, OrderStatusShipped, OrderStatusFulfilled}
+
+// allowsStatus reports whether the action can be attached to transitions into status.
+func (a OrderTransitionAction) allowsStatus(status OrderStatus) bool {
+	switch a {
+	case OrderTransitionActionRequirePayment, OrderTransitionActionMarkPaid:
+		return slices.Contains(paymentRuleStatuses, status)
+	case OrderTransitionActionNotifyCustomer:
+		return status != OrderStatusPending
+	}
+	return false
+}
+
+// OrderTransitionRule is a per-business side effect of order status transitions, evaluated
+// by UpdateOrderStatus. A rule matches transitions into ToStatus, optionally only from
+// FromStatus.
+type OrderTransitionRule struct {
+	gorm.Model
+	ID         string                `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string                `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	FromStatus sql.NullString        `gorm:"column:from_status;type:text" json:"fromStatus,omitempty"`
+	ToStatus   OrderStatus           `gorm:"column:to_status;type:text;not null" json:"toStatus"`
+	Action     OrderTransitionAction `gorm:"column:action;type:text;not null" json:"action"`
+	Message    sql.NullString        `gorm:"column:message;type:text" json:"message,omitempty"`
+	Enabled    bool                  `gorm:"column:enabled;not null" json:"enabled"`
+}
+
+func (m *OrderTransitionRule) TableName() string { return OrderTransitionRuleTable }
+
+func (m *OrderTransitionRule) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(OrderTransitionRulePrefix)
+	}
+	return
+}
+
+// matches reports whether the rule applies to a transition from → to.
+func (m *OrderTransitionRule) matches(from, to OrderStatus) bool {
+	return m.Enabled && m.ToStatus == to && (!m.FromStatus.Valid || OrderStatus(m.FromStatus.String) == from)
+}
+
+// sameTrigger reports whether two rules run the same action on the same transitions.
+func (m *OrderTransitionRule) sameTrigger(other *OrderTransitionRule) bool {
+	return m.ToStatus == other.ToStatus && m.Action == other.Action && m.FromStatus.String == other.FromStatus.String
+}
+
+// renderMessage fills the placeholders of a notify_customer message:
+// {orderNumber}, {status}, {total} and {customerName}.
+func (m *OrderTransitionRule) renderMessage(ord *Order, customerName string) string {
+	return strings.NewReplacer(
+		"{orderNumber}", ord.OrderNumber,
+		"{status}", strings.ReplaceAll(string(ord.Status), "_", " "),
+		"{total}", money.StringFixed(ord.Total, ord.Currency)+" "+ord.Currency,
+		"{customerName}", customerName,
+	).Replace(m.Message.String)
+}
+
+var OrderTransitionRuleSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	FromStatus schema.Field
+	ToStatus   schema.Field
+	Action     schema.Field
+	Enabled    schema.Field
+	CreatedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	FromStatus: schema.NewField("from_status", "fromStatus"),
+	ToStatus:   schema.NewField("to_status", "toStatus"),
+	Action:     schema.NewField("action", "action"),
+	Enabled:    schema.NewField("enabled", "enabled"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+}
+
+// CreateOrderTransitionRuleRequest adds a transition rule. Message is required for
+// notify_customer rules.
+type CreateOrderTransitionRuleRequest struct {
+	FromStatus OrderStatus           `json:"fromStatus" binding:"omitempty,oneof=pending placed ready_for_shipment shipped fulfilled cancelled returned"`
+	ToStatus   OrderStatus           `json:"toStatus" binding:"required,oneof=pending placed ready_for_shipment shipped fulfilled cancelled returned"`
+	Action     OrderTransitionAction `json:"action" binding:"required,oneof=require_payment mark_paid notify_customer"`
+	Message    string                `json:"message" binding:"omitempty,max=1000"`
+	Enabled    *bool                 `json:"enabled" binding:"omitempty"`
+}
+
+// UpdateOrderTransitionRuleRequest updates a transition rule; omitted fields are kept.
+type UpdateOrderTransitionRuleRequest struct {
+	Message *string `json:"message" binding:"omitempty,max=1000"`
+	Enabled *bool   `json:"enabled" binding:"omitempty"`
+}
+
+// OrderTransitionRuleResponse is the API response for OrderTransitionRule entity
+type OrderTransitionRuleResponse struct {
+	ID         string                `json:"id"`
+	FromStatus *OrderStatus          `json:"fromStatus,omitempty"`
+	ToStatus   OrderStatus           `json:"toStatus"`
+	Action     OrderTransitionAction `json:"action"`
+	Message    string                `json:"message,omitempty"`
+	Enabled    bool                  `json:"enabled"`
+	CreatedAt  time.Time             `json:"createdAt"`
+	UpdatedAt  time.Time             `json:"updatedAt"`
+}
+
+// ToOrderTransitionRuleResponse converts OrderTransitionRule model to OrderTransitionRuleResponse
+func ToOrderTransitionRuleResponse(r *OrderTransitionRule) OrderTransitionRuleResponse {
+	var from *OrderStatus
+	if r.FromStatus.Valid {
+		s := OrderStatus(r.FromStatus.String)
+		from = &s
+	}
+	return OrderTransitionRuleResponse{
+		ID:         r.ID,
+		FromStatus: from,
+		ToStatus:   r.ToStatus,
+		Action:     r.Action,
+		Message:    r.Message.String,
+		Enabled:    r.Enabled,
+		CreatedAt:  r.CreatedAt,
+		UpdatedAt:  r.UpdatedAt,
+	}
+}
+
+// ToOrderTransitionRuleResponses converts a slice of rules to responses.
+func ToOrderTransitionRuleResponses(rules []*OrderTransitionRule) []OrderTransitionRuleResponse {
+	out := make([]OrderTransitionRuleResponse, len(rules))
+	for i, r := range rules {
+		out[i] = ToOrderTransitionRuleResponse(r)
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/email"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+)
+
+// Notification encapsulates email sending for order domain
+type Notification struct {
+	client email.Client
+	info   email.EmailInfo
+}
+
+// NewNotification wires the email client and defaults
+func NewNotification(client email.Client, info email.EmailInfo) *Notification {
+	return &Notification{client: client, info: info}
+}
+
+// SendDigitalDeliveryEmail sends the customer of a paid order the files of its digital items.
+func (n *Notification) SendDigitalDeliveryEmail(ctx context.Context, biz *business.Business, ord *Order) error {
+	if n.client == nil {
+		return fmt.Errorf("email client not available")
+	}
+	logger := logger.FromContext(ctx).With("action", "sendDigitalDelivery", "businessId", biz.ID, "orderId", ord.ID)
+	logger.Info("sending digital delivery email")
+
+	downloads := make([]map[string]any, 0, len(ord.Items))
+	for _, item := range ord.Items {
+		if item.Download == nil {
+			continue
+		}
+		downloads = append(downloads, map[string]any{
+			"name": orderItemName(item),
+			"url":  item.Download.URL,
+		})
+	}
+
+	businessName := biz.Brand
+	if businessName == "" {
+		businessName = biz.Name
+	}
+	data := map[string]any{
+		"customerName": ord.Customer.Name,
+		"businessName": businessName,
+		"orderNumber":  ord.OrderNumber,
+		"downloads":    downloads,
+		"supportEmail": biz.SupportEmail,
+		"productName":  n.info.ProductName,
+		"currentYear":  fmt.Sprintf("%d", time.Now().Year()),
+	}
+	subject := fmt.Sprintf("Your %s downloads are ready", businessName)
+	from := n.info.FormattedFrom()
+	if _, err := n.client.SendTemplate(ctx, email.TemplateDigitalDelivery, []string{ord.Customer.Email.String}, from, subject, data); err != nil {
+		logger.Error("failed to send digital delivery email", "error", err)
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	logger.Info("digital delivery email sent successfully")
+	return nil
+}
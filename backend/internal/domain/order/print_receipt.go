@@ -0,0 +1,288 @@
+package order
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+)
+
+// printColumns is the characters per line of an 80mm thermal printer in its default font
+// (576 dots / 12 dots per character).
+const printColumns = 48
+
+const printDateLayout = "02 Jan 2006 15:04"
+
+type printAlign int
+
+const (
+	printAlignLeft printAlign = iota
+	printAlignCenter
+	printAlignRight
+)
+
+// printLine is one line of a print document, independent of the output format.
+type printLine struct {
+	Text  string
+	Align printAlign
+	Bold  bool
+	Large bool
+	Rule  bool
+}
+
+// RenderPrintDocument renders an order (with Customer, ShippingAddress, Items.Variant and
+// Notes preloaded) as a receipt or pick ticket for an 80mm printer.
+func RenderPrintDocument(biz *business.Business, ord *Order, kind PrintKind, format PrintFormat) []byte {
+	var lines []printLine
+	if kind == PrintKindPickTicket {
+		lines = pickTicketLines(biz, ord)
+	} else {
+		lines = receiptLines(biz, ord)
+	}
+	if format == PrintFormatHTML {
+		return encodePrintHTML(string(kind)+" "+ord.OrderNumber, lines)
+	}
+	return encodeESCPOS(lines)
+}
+
+// PrintContentType is the response content type of a rendered print document.
+func PrintContentType(format PrintFormat) string {
+	if format == PrintFormatHTML {
+		return "text/html; charset=utf-8"
+	}
+	return "application/vnd.escpos"
+}
+
+// PrintFileName is the download name of a rendered print document.
+func PrintFileName(ord *Order, kind PrintKind, format PrintFormat) string {
+	ext := "bin"
+	if format == PrintFormatHTML {
+		ext = "html"
+	}
+	return fmt.Sprintf("%s-%s.%s", strings.ReplaceAll(string(kind), "_", "-"), ord.OrderNumber, ext)
+}
+
+func receiptLines(biz *business.Business, ord *Order) []printLine {
+	lines := []printLine{{Text: biz.Name, Align: printAlignCenter, Bold: true, Large: true}}
+	for _, l := range businessContactLines(biz) {
+		lines = append(lines, printLine{Text: l, Align: printAlignCenter})
+	}
+	lines = append(lines, printLine{Rule: true})
+	lines = append(lines, orderHeaderLines(biz, ord)...)
+	if ord.Customer != nil {
+		lines = append(lines, printLine{Text: "Customer: " + ord.Customer.Name})
+	}
+	lines = append(lines, printLine{Rule: true})
+	for _, it := range ord.Items {
+		lines = append(lines, columns(fmt.Sprintf("%d x %s", it.Quantity, orderItemName(it)), formatMoney(it.Total, ord.Currency))...)
+		if it.Quantity > 1 {
+			lines = append(lines, printLine{Text: "  @ " + formatMoney(it.UnitPrice, ord.Currency)})
+		}
+	}
+	lines = append(lines, printLine{Rule: true})
+	lines = append(lines, columns("Subtotal", formatMoney(ord.Subtotal, ord.Currency))...)
+	if ord.Discount.IsPositive() {
+		lines = append(lines, columns("Discount", "-"+formatMoney(ord.Discount, ord.Currency))...)
+	}
+	if ord.ShippingFee.IsPositive() {
+		lines = append(lines, columns("Shipping", formatMoney(ord.ShippingFee, ord.Currency))...)
+	}
+	if ord.VAT.IsPositive() {
+		label := "VAT"
+		if ord.PricesIncludeVAT {
+			// Already part of the subtotal; shown for the tax breakdown only.
+			label = "VAT (included)"
+		}
+		lines = append(lines, columns(label, formatMoney(ord.VAT, ord.Currency))...)
+	}
+	total := columns("TOTAL", formatMoney(ord.Total, ord.Currency))
+	for i := range total {
+		total[i].Bold = true
+	}
+	lines = append(lines, total...)
+	lines = append(lines,
+		printLine{Rule: true},
+		printLine{Text: "Payment: " + humanize(string(ord.PaymentMethod)) + " (" + humanize(string(ord.PaymentStatus)) + ")"},
+		printLine{},
+		printLine{Text: "Thank you!", Align: printAlignCenter, Bold: true},
+	)
+	return lines
+}
+
+func pickTicketLines(biz *business.Business, ord *Order) []printLine {
+	lines := []printLine{
+		{Text: "PICK TICKET", Align: printAlignCenter, Bold: true, Large: true},
+		{Text: biz.Name, Align: printAlignCenter},
+		{Rule: true},
+	}
+	lines = append(lines, orderHeaderLines(biz, ord)...)
+	lines = append(lines, printLine{Rule: true}, printLine{Text: "Ship to", Bold: true})
+	for _, l := range customerLines(ord.Customer, ord.ShippingAddress) {
+		lines = append(lines, wrapLine(l)...)
+	}
+	lines = append(lines, printLine{Rule: true}, printLine{Text: "Items", Bold: true})
+	units := 0
+	for _, it := range ord.Items {
+		units += it.Quantity
+		lines = append(lines, columns("[ ] "+orderItemName(it), fmt.Sprintf("x%d", it.Quantity))...)
+		if sku := orderItemSKU(it); sku != "" {
+			lines = append(lines, printLine{Text: "    SKU " + sku})
+		}
+	}
+	lines = append(lines, printLine{Rule: true}, printLine{Text: fmt.Sprintf("%d item(s), %d unit(s)", len(ord.Items), units), Bold: true})
+	if len(ord.Notes) > 0 {
+		lines = append(lines, printLine{Rule: true}, printLine{Text: "Notes", Bold: true})
+		for _, n := range ord.Notes {
+			lines = append(lines, wrapLine("- "+n.Content)...)
+		}
+	}
+	return lines
+}
+
+func orderHeaderLines(biz *business.Business, ord *Order) []printLine {
+	return []printLine{
+		{Text: "Order #" + ord.OrderNumber, Bold: true},
+		{Text: ord.OrderedAt.In(biz.Location()).Format(printDateLayout)},
+	}
+}
+
+func orderItemName(it *OrderItem) string {
+	if it.Variant != nil && it.Variant.Name != "" {
+		return it.Variant.Name
+	}
+	if it.Product != nil {
+		return it.Product.Name
+	}
+	return it.VariantID
+}
+
+func orderItemSKU(it *OrderItem) string {
+	if it.Variant != nil {
+		return it.Variant.SKU
+	}
+	return ""
+}
+
+func humanize(s string) string {
+	return strings.ReplaceAll(s, "_", " ")
+}
+
+// columns lays out a label on the left and a value on the right of one line. Labels too
+// long to fit are wrapped, with the value on the last line.
+func columns(label, value string) []printLine {
+	width := printColumns - utf8.RuneCountInString(value) - 1
+	wrapped := wrapText(label, width)
+	lines := make([]printLine, len(wrapped))
+	for i, l := range wrapped {
+		lines[i] = printLine{Text: l}
+	}
+	last := &lines[len(lines)-1]
+	last.Text += strings.Repeat(" ", printColumns-utf8.RuneCountInString(last.Text)-utf8.RuneCountInString(value)) + value
+	return lines
+}
+
+func wrapLine(text string) []printLine {
+	var lines []printLine
+	for _, l := range wrapText(text, printColumns) {
+		lines = append(lines, printLine{Text: l})
+	}
+	return lines
+}
+
+// wrapText breaks text into lines of at most width runes, splitting on spaces where possible.
+func wrapText(text string, width int) []string {
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(text) {
+		for utf8.RuneCountInString(word) > width {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			r := []rune(word)
+			lines = append(lines, string(r[:width]))
+			word = string(r[width:])
+		}
+		switch {
+		case current == "":
+			current = word
+		case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) <= width:
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	return append(lines, current)
+}
+
+// ESC/POS commands used by the encoder.
+var (
+	escposInit        = []byte{0x1b, 0x40}
+	escposAlign       = []byte{0x1b, 0x61}
+	escposBold        = []byte{0x1b, 0x45}
+	escposSize        = []byte{0x1d, 0x21}
+	escposFeedAndCut  = []byte{0x1b, 0x64, 0x04, 0x1d, 0x56, 0x42, 0x00}
+	escposDoubleSize  = byte(0x11)
+	escposDefaultSize = byte(0x00)
+)
+
+// encodeESCPOS writes lines as ESC/POS commands and text (UTF-8), ending with a partial cut.
+func encodeESCPOS(lines []printLine) []byte {
+	var b bytes.Buffer
+	b.Write(escposInit)
+	for _, l := range lines {
+		if l.Rule {
+			b.WriteString(strings.Repeat("-", printColumns))
+			b.WriteByte('\n')
+			continue
+		}
+		b.Write(append(escposAlign, byte(l.Align)))
+		b.Write(append(escposBold, boolByte(l.Bold)))
+		if l.Large {
+			b.Write(append(escposSize, escposDoubleSize))
+		}
+		b.WriteString(l.Text)
+		b.WriteByte('\n')
+		if l.Large {
+			b.Write(append(escposSize, escposDefaultSize))
+		}
+	}
+	b.Write(append(escposAlign, byte(printAlignLeft)))
+	b.Write(append(escposBold, 0))
+	b.Write(escposFeedAndCut)
+	return b.Bytes()
+}
+
+func boolByte(v bool) byte {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+var printHTMLTemplate = template.Must(template.New("print").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Title}}</title>
+<style>
+@page { size: 80mm auto; margin: 0; }
+body { width: 72mm; margin: 4mm; font: 12px/1.35 monospace; }
+p { margin: 0; white-space: pre; overflow: hidden; }
+.c { text-align: center; } .r { text-align: right; } .b { font-weight: bold; } .l { font-size: 18px; }
+hr { border: 0; border-top: 1px dashed #000; margin: 4px 0; }
+</style></head><body>
+{{range .Lines}}{{if .Rule}}<hr>{{else}}<p class="{{if eq .Align 1}}c{{else if eq .Align 2}}r{{end}}{{if .Bold}} b{{end}}{{if .Large}} l{{end}}">{{.Text}}</p>{{end}}
+{{end}}</body></html>
+`))
+
+func encodePrintHTML(title string, lines []printLine) []byte {
+	var b bytes.Buffer
+	// The template only fails on writer errors, which a bytes.Buffer never returns.
+	_ = printHTMLTemplate.Execute(&b, struct {
+		Title string
+		Lines []printLine
+	}{Title: title, Lines: lines})
+	return b.Bytes()
+}
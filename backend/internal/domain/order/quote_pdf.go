@@ -0,0 +1,184 @@
+package order
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/platform/pdf"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	pdfMarginX      = 40.0
+	pdfRight        = pdf.PageWidth - pdfMarginX
+	pdfBottomLimit  = pdf.PageHeight - 60
+	pdfDateLayout   = "02 Jan 2006"
+	pdfQtyColumn    = 370.0
+	pdfPriceColumn  = 465.0
+	pdfNameMaxWidth = 290.0
+)
+
+// RenderQuotePDF renders a quote (with Customer, ShippingAddress and Items.Product/Variant
+// preloaded) as a one-or-more page A4 PDF.
+func RenderQuotePDF(biz *business.Business, q *Quote) []byte {
+	doc := pdf.New("Quote " + q.QuoteNumber)
+	loc := biz.Location()
+
+	y := 60.0
+	doc.Text(pdfMarginX, y, pdf.Bold, 18, pdf.AlignLeft, biz.Name)
+	doc.Text(pdfRight, y, pdf.Bold, 18, pdf.AlignRight, "QUOTATION")
+	y += 18
+	for _, line := range businessContactLines(biz) {
+		doc.Text(pdfMarginX, y, pdf.Regular, 9, pdf.AlignLeft, line)
+		y += 12
+	}
+
+	metaY := 78.0
+	for _, kv := range [][2]string{
+		{"Quote #", q.QuoteNumber},
+		{"Date", q.CreatedAt.In(loc).Format(pdfDateLayout)},
+		{"Valid until", q.ValidUntil.In(loc).Format(pdfDateLayout)},
+		{"Status", strings.ToUpper(string(q.Status))},
+	} {
+		doc.Text(pdfRight-110, metaY, pdf.Bold, 9, pdf.AlignRight, kv[0])
+		doc.Text(pdfRight, metaY, pdf.Regular, 9, pdf.AlignRight, kv[1])
+		metaY += 12
+	}
+
+	y = max(y, metaY) + 16
+	doc.Text(pdfMarginX, y, pdf.Bold, 10, pdf.AlignLeft, "Prepared for")
+	y += 14
+	for _, line := range customerLines(q.Customer, q.ShippingAddress) {
+		doc.Text(pdfMarginX, y, pdf.Regular, 10, pdf.AlignLeft, line)
+		y += 13
+	}
+
+	y += 16
+	y = drawItemsHeader(doc, y)
+	for _, it := range q.Items {
+		if y > pdfBottomLimit {
+			doc.AddPage()
+			y = drawItemsHeader(doc, 60)
+		}
+		doc.Text(pdfMarginX+6, y, pdf.Regular, 10, pdf.AlignLeft, pdf.Truncate(quoteItemName(it), pdf.Regular, 10, pdfNameMaxWidth))
+		doc.Text(pdfQtyColumn, y, pdf.Regular, 10, pdf.AlignRight, fmt.Sprintf("%d", it.Quantity))
+		doc.Text(pdfPriceColumn, y, pdf.Regular, 10, pdf.AlignRight, formatMoney(it.UnitPrice, q.Currency))
+		doc.Text(pdfRight-6, y, pdf.Regular, 10, pdf.AlignRight, formatMoney(it.Total, q.Currency))
+		if sku := quoteItemSKU(it); sku != "" {
+			y += 11
+			doc.Text(pdfMarginX+6, y, pdf.Regular, 8, pdf.AlignLeft, "SKU "+sku)
+		}
+		y += 18
+	}
+	doc.Line(pdfMarginX, y-8, pdfRight, y-8)
+
+	totals := [][2]string{{"Subtotal", formatMoney(q.Subtotal, q.Currency)}}
+	if q.Discount.IsPositive() {
+		totals = append(totals, [2]string{"Discount", "-" + formatMoney(q.Discount, q.Currency)})
+	}
+	if q.ShippingFee.IsPositive() {
+		totals = append(totals, [2]string{"Shipping", formatMoney(q.ShippingFee, q.Currency)})
+	}
+	vatLabel := fmt.Sprintf("VAT (%s%%)", q.VATRate.Mul(decimal.NewFromInt(100)).String())
+	if q.PricesIncludeVAT {
+		vatLabel = fmt.Sprintf("VAT included (%s%%)", q.VATRate.Mul(decimal.NewFromInt(100)).String())
+	}
+	totals = append(totals, [2]string{vatLabel, formatMoney(q.VAT, q.Currency)})
+	if y+float64(len(totals)+1)*16 > pdfBottomLimit {
+		doc.AddPage()
+		y = 60
+	}
+	y += 6
+	for _, kv := range totals {
+		doc.Text(pdfPriceColumn, y, pdf.Regular, 10, pdf.AlignRight, kv[0])
+		doc.Text(pdfRight-6, y, pdf.Regular, 10, pdf.AlignRight, kv[1])
+		y += 16
+	}
+	doc.Text(pdfPriceColumn, y+2, pdf.Bold, 12, pdf.AlignRight, "Total")
+	doc.Text(pdfRight-6, y+2, pdf.Bold, 12, pdf.AlignRight, formatMoney(q.Total, q.Currency))
+	y += 30
+
+	if q.Terms != "" {
+		doc.Text(pdfMarginX, y, pdf.Bold, 10, pdf.AlignLeft, "Terms")
+		y += 14
+		for _, line := range pdf.Wrap(q.Terms, pdf.Regular, 9, pdfRight-pdfMarginX) {
+			if y > pdfBottomLimit {
+				doc.AddPage()
+				y = 60
+			}
+			doc.Text(pdfMarginX, y, pdf.Regular, 9, pdf.AlignLeft, line)
+			y += 12
+		}
+	}
+
+	return doc.Bytes()
+}
+
+func drawItemsHeader(doc *pdf.Document, y float64) float64 {
+	doc.FillRect(pdfMarginX, y-12, pdfRight-pdfMarginX, 18, 0.93)
+	doc.Text(pdfMarginX+6, y, pdf.Bold, 9, pdf.AlignLeft, "Item")
+	doc.Text(pdfQtyColumn, y, pdf.Bold, 9, pdf.AlignRight, "Qty")
+	doc.Text(pdfPriceColumn, y, pdf.Bold, 9, pdf.AlignRight, "Unit price")
+	doc.Text(pdfRight-6, y, pdf.Bold, 9, pdf.AlignRight, "Amount")
+	return y + 22
+}
+
+func businessContactLines(biz *business.Business) []string {
+	var lines []string
+	for _, s := range []string{biz.Address, biz.PhoneNumber, biz.SupportEmail, biz.WebsiteURL} {
+		if s = strings.TrimSpace(s); s != "" {
+			lines = append(lines, s)
+		}
+	}
+	return lines
+}
+
+func customerLines(c *customer.Customer, addr *customer.CustomerAddress) []string {
+	var lines []string
+	if c != nil {
+		lines = append(lines, c.Name)
+		if email := c.Email.ValueOrDefault(""); email != "" {
+			lines = append(lines, email)
+		}
+	}
+	if addr != nil {
+		lines = append(lines, strings.TrimSpace(addr.Street.ValueOrDefault("")))
+		lines = append(lines, strings.Join(nonEmpty(addr.City, addr.State, addr.ZipCode.ValueOrDefault(""), addr.CountryCode), ", "))
+		lines = append(lines, strings.TrimSpace(addr.PhoneCode+" "+addr.PhoneNumber))
+	}
+	return nonEmpty(lines...)
+}
+
+func nonEmpty(values ...string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func quoteItemName(it *QuoteItem) string {
+	if it.Variant != nil && it.Variant.Name != "" {
+		return it.Variant.Name
+	}
+	if it.Product != nil {
+		return it.Product.Name
+	}
+	return it.VariantID
+}
+
+func quoteItemSKU(it *QuoteItem) string {
+	if it.Variant != nil {
+		return it.Variant.SKU
+	}
+	return ""
+}
+
+func formatMoney(amount decimal.Decimal, currency string) string {
+	return money.StringFixed(amount, currency) + " " + currency
+}
@@ -16,8 +16,10 @@ import (
 	"github.com/abdelrahman146/kyora/internal/platform/types/keyvalue"
 	"github.com/abdelrahman146/kyora/internal/platform/types/list"
 	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
 	"github.com/abdelrahman146/kyora/internal/platform/types/timeseries"
 	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
 	"github.com/abdelrahman146/kyora/internal/platform/utils/throttle"
 	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
 	"github.com/shopspring/decimal"
@@ -31,6 +33,8 @@ type Service struct {
 	inventory       *inventory.Service
 	customer        *customer.Service
 	business        *business.Service
+	logos           LogoStore
+	notification    *Notification
 }
 
 func NewService(storage *Storage, atomicProcessor atomic.AtomicProcessor, bus *bus.Bus, inventory *inventory.Service, customer *customer.Service, businessSvc *business.Service) *Service {
@@ -57,7 +61,16 @@ func (s *Service) orderListPreloads() []func(*gorm.DB) *gorm.DB {
 }
 
 func (s *Service) orderDetailPreloads() []func(*gorm.DB) *gorm.DB {
-	return s.orderListPreloads()
+	return append(s.orderListPreloads(), s.storage.order.WithPreload(OrderPaymentStruct))
+}
+
+// getShippingZone loads a shipping zone of the business. Orders created by background jobs
+// (recurring orders) have no actor.
+func (s *Service) getShippingZone(ctx context.Context, actor *account.User, biz *business.Business, zoneID string) (*business.ShippingZone, error) {
+	if actor == nil {
+		return s.business.GetShippingZoneByIDForJobs(ctx, biz, zoneID)
+	}
+	return s.business.GetShippingZoneByID(ctx, actor, biz, zoneID)
 }
 
 func (s *Service) shippingFeeFromZone(subtotal, discount decimal.Decimal, zone *business.ShippingZone) decimal.Decimal {
@@ -74,21 +87,26 @@ func (s *Service) shippingFeeFromZone(subtotal, discount decimal.Decimal, zone *
 
 // computeDiscountAmount resolves the discount amount from CreateOrderRequest.
 // It prefers the new DiscountType/DiscountValue fields over the legacy Discount field.
-// Returns the computed discount amount (rounded to 2 decimals for percentage discounts).
-func (s *Service) computeDiscountAmount(subtotal decimal.Decimal, req *CreateOrderRequest) decimal.Decimal {
+// Returns the computed discount amount (rounded to the currency's minor unit for percentage discounts).
+func (s *Service) computeDiscountAmount(subtotal decimal.Decimal, currency string, req *CreateOrderRequest) decimal.Decimal {
 	// New fields take precedence.
 	if req.DiscountType != "" && !req.DiscountValue.IsZero() {
-		if req.DiscountType == DiscountTypePercent {
-			// Percent discount: subtotal * (discountValue / 100), rounded to 2 decimals.
-			return subtotal.Mul(req.DiscountValue).Div(decimal.NewFromInt(100)).Round(2)
-		}
-		// Amount discount
-		return req.DiscountValue
+		return discountAmount(subtotal, currency, req.DiscountType, req.DiscountValue)
 	}
 	// Fallback to legacy Discount field.
 	return req.Discount
 }
 
+// discountAmount applies a typed discount to subtotal.
+func discountAmount(subtotal decimal.Decimal, currency string, discountType DiscountType, value decimal.Decimal) decimal.Decimal {
+	if discountType == DiscountTypePercent {
+		// Percent discount: subtotal * (discountValue / 100), rounded to the currency's minor unit.
+		return money.Round(subtotal.Mul(value).Div(decimal.NewFromInt(100)), currency)
+	}
+	// Amount discount
+	return value
+}
+
 // PreviewOrder validates the payload and computes totals without persisting or mutating inventory.
 func (s *Service) PreviewOrder(ctx context.Context, actor *account.User, biz *business.Business, req *CreateOrderRequest) (*OrderPreview, error) {
 	// Basic abuse/double-submit protection (best-effort, cache-backed).
@@ -99,32 +117,29 @@ func (s *Service) PreviewOrder(ctx context.Context, actor *account.User, biz *bu
 	if req == nil || len(req.Items) == 0 {
 		return nil, ErrEmptyOrderItems()
 	}
-	if req.ShippingFee.LessThan(decimal.Zero) {
-		return nil, problem.BadRequest("shippingFee cannot be negative")
-	}
-	if req.DiscountValue.LessThan(decimal.Zero) {
-		return nil, problem.BadRequest("discountValue cannot be negative")
-	}
-	if req.Discount.LessThan(decimal.Zero) {
-		return nil, problem.BadRequest("discount cannot be negative")
-	}
 
-	if _, err := s.customer.GetCustomerByID(ctx, actor, biz, req.CustomerID); err != nil {
+	cust, err := s.customer.GetCustomerByID(ctx, actor, biz, req.CustomerID)
+	if err != nil {
 		return nil, err
 	}
 	addr, err := s.customer.GetCustomerAddressByID(ctx, actor, biz, req.CustomerID, req.ShippingAddressID)
 	if err != nil {
 		return nil, err
 	}
+	priceList, err := s.inventory.ResolvePriceList(ctx, biz, cust.PriceListID.String)
+	if err != nil {
+		return nil, err
+	}
 
-	orderItems, adjustments, err := s.prepareOrderItems(ctx, actor, biz, req.Items)
+	orderItems, adjustments, err := s.prepareOrderItems(ctx, actor, biz, priceList, req.Items)
 	if err != nil {
 		return nil, err
 	}
+	exempt := shippingExempt(orderItems)
 
 	var shippingZoneID *string
 	var zone *business.ShippingZone
-	if req.ShippingZoneID != nil {
+	if req.ShippingZoneID != nil && !exempt {
 		zoneVal := strings.TrimSpace(*req.ShippingZoneID)
 		if zoneVal != "" {
 			shippingZoneID = &zoneVal
@@ -162,16 +177,21 @@ func (s *Service) PreviewOrder(ctx context.Context, actor *account.User, biz *bu
 		}
 	}
 
-	subtotal := s.calculateSubtotal(orderItems)
-	cogs := s.calculateCOGS(orderItems)
+	subtotal := s.calculateSubtotal(orderItems, biz.Currency)
+	cogs := s.calculateCOGS(orderItems, biz.Currency)
 	vatRate := biz.VatRate
-	discount := s.computeDiscountAmount(subtotal, req)
-	vat := s.calculateVAT(subtotal, vatRate)
+	discount, coupon, err := s.resolveDiscount(ctx, biz, req, subtotal, false)
+	if err != nil {
+		return nil, err
+	}
+	vat := s.calculateVAT(subtotal, vatRate, biz.PricesIncludeVat, biz.Currency)
 	shippingFee := req.ShippingFee
-	if zone != nil {
+	if exempt {
+		shippingFee = decimal.Zero
+	} else if zone != nil {
 		shippingFee = s.shippingFeeFromZone(subtotal, discount, zone)
 	}
-	total := s.calculateTotal(subtotal, vat, shippingFee, discount)
+	total := s.calculateTotal(subtotal, vat, shippingFee, discount, biz.PricesIncludeVat, biz.Currency)
 
 	if err := s.ensureInventorySufficient(adjustments); err != nil {
 		return nil, err
@@ -190,19 +210,25 @@ func (s *Service) PreviewOrder(ctx context.Context, actor *account.User, biz *bu
 		}
 	}
 
-	return &OrderPreview{
-		Subtotal:       subtotal,
-		VAT:            vat,
-		VATRate:        vatRate,
-		ShippingFee:    shippingFee,
-		Discount:       discount,
-		COGS:           cogs,
-		Total:          total,
-		Currency:       biz.Currency,
-		ShippingZoneID: shippingZoneID,
-		PaymentMethod:  paymentMethod,
-		Items:          previewItems,
-	}, nil
+	preview := &OrderPreview{
+		Subtotal:         subtotal,
+		VAT:              vat,
+		VATRate:          vatRate,
+		PricesIncludeVAT: biz.PricesIncludeVat,
+		ShippingFee:      shippingFee,
+		Discount:         discount,
+		COGS:             cogs,
+		Total:            total,
+		Currency:         biz.Currency,
+		ShippingZoneID:   shippingZoneID,
+		ShippingExempt:   exempt,
+		PaymentMethod:    paymentMethod,
+		Items:            previewItems,
+	}
+	if coupon != nil {
+		preview.CouponCode = coupon.Code
+	}
+	return preview, nil
 }
 
 func (s *Service) CreateOrder(ctx context.Context, actor *account.User, biz *business.Business, req *CreateOrderRequest) (*Order, error) {
@@ -210,7 +236,27 @@ func (s *Service) CreateOrder(ctx context.Context, actor *account.User, biz *bus
 	if !throttle.Allow(s.storage.cache, fmt.Sprintf("rl:order:create:%s:%s", biz.ID, actor.ID), time.Minute, 30, 1*time.Second) {
 		return nil, ErrOrderRateLimited()
 	}
+	order, err := s.createOrder(ctx, actor, biz, req, createOrderOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// Orders recorded as already paid get the same automation (fee expense, prep task) as
+	// orders marked paid later.
+	if order.PaymentStatus == OrderPaymentStatusPaid {
+		s.emitPaidEvent(ctx, order)
+	}
+	return order, nil
+}
+
+// createOrderOptions adjust createOrder for orders not taken through the order form.
+type createOrderOptions struct {
+	// historical orders ran their course before they were recorded: their items already left
+	// the stock levels and are not taken out again, and they keep their final statuses and
+	// payment method.
+	historical bool
+}
 
+func (s *Service) createOrder(ctx context.Context, actor *account.User, biz *business.Business, req *CreateOrderRequest, opts createOrderOptions) (*Order, error) {
 	var order *Order
 	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
 		tx, _ := tctx.Value(database.TxKey).(*gorm.DB)
@@ -222,33 +268,56 @@ func (s *Service) CreateOrder(ctx context.Context, actor *account.User, biz *bus
 		if req == nil || len(req.Items) == 0 {
 			return ErrEmptyOrderItems()
 		}
-		// validate decimal fields (validator cannot handle decimal.Decimal with numeric comparisons)
-		if req.ShippingFee.LessThan(decimal.Zero) {
-			return problem.BadRequest("shippingFee cannot be negative")
-		}
-		if req.DiscountValue.LessThan(decimal.Zero) {
-			return problem.BadRequest("discountValue cannot be negative")
+		tags, err := newOrderTags(req.Tags)
+		if err != nil {
+			return err
 		}
-		if req.Discount.LessThan(decimal.Zero) {
-			return problem.BadRequest("discount cannot be negative")
+		customFields, err := newOrderCustomFields(req.CustomFields)
+		if err != nil {
+			return err
 		}
 		// ownership validation: ensure customer + address belong to this business
-		if _, err := s.customer.GetCustomerByID(tctx, actor, biz, req.CustomerID); err != nil {
+		cust, err := s.customer.GetCustomerByID(tctx, actor, biz, req.CustomerID)
+		if err != nil {
 			return err
 		}
 		addr, err := s.customer.GetCustomerAddressByID(tctx, actor, biz, req.CustomerID, req.ShippingAddressID)
 		if err != nil {
 			return err
 		}
+		priceList, err := s.inventory.ResolvePriceList(tctx, biz, cust.PriceListID.String)
+		if err != nil {
+			return err
+		}
 
-		orderItems, adjustments, err := s.prepareOrderItems(tctx, actor, biz, req.Items)
+		orderItems, adjustments, err := s.prepareOrderItems(tctx, actor, biz, priceList, req.Items)
 		if err != nil {
 			return err
 		}
 
-		// resolve optional shipping zone (validated and scoped)
+		// resolve the optional stock location (validated and scoped)
+		locationID := strings.TrimSpace(req.LocationID)
+		if locationID != "" {
+			if _, err := s.inventory.GetLocationByID(tctx, actor, biz, locationID); err != nil {
+				if database.IsRecordNotFound(err) {
+					return inventory.ErrLocationNotFound(err).With("locationId", locationID)
+				}
+				return err
+			}
+		}
+
+		// take the items out of stock first: it costs the items priced from stock
+		if !opts.historical {
+			if err := s.adjustInventoryLevels(tctx, actor, biz, locationID, adjustments); err != nil {
+				return err
+			}
+		}
+
+		// resolve optional shipping zone (validated and scoped); orders with nothing to ship
+		// have none
+		exempt := shippingExempt(orderItems)
 		var shippingZoneID *string
-		if req.ShippingZoneID != nil {
+		if req.ShippingZoneID != nil && !exempt {
 			zoneID := strings.TrimSpace(*req.ShippingZoneID)
 			if zoneID != "" {
 				shippingZoneID = &zoneID
@@ -259,7 +328,7 @@ func (s *Service) CreateOrder(ctx context.Context, actor *account.User, biz *bus
 			if s.business == nil {
 				return problem.InternalError().With("reason", "business service not configured")
 			}
-			z, err := s.business.GetShippingZoneByID(tctx, actor, biz, *shippingZoneID)
+			z, err := s.getShippingZone(tctx, actor, biz, *shippingZoneID)
 			if err != nil {
 				return err
 			}
@@ -278,8 +347,9 @@ func (s *Service) CreateOrder(ctx context.Context, actor *account.User, biz *bus
 		if paymentMethod == "" {
 			paymentMethod = OrderPaymentMethodBankTransfer
 		}
-		// Validate payment method enabled for this business.
-		if s.business != nil {
+		// Validate payment method enabled for this business. Historical orders were paid
+		// elsewhere and keep their method.
+		if s.business != nil && !opts.historical {
 			enabled, _, _, err := s.business.GetEffectivePaymentMethodFee(tctx, biz.ID, business.PaymentMethodDescriptor(paymentMethod))
 			if err != nil {
 				return err
@@ -291,18 +361,24 @@ func (s *Service) CreateOrder(ctx context.Context, actor *account.User, biz *bus
 
 		// calculate totals from items and fees and vat
 		vatRate := biz.VatRate
-		subtotal := s.calculateSubtotal(orderItems)
-		cogs := s.calculateCOGS(orderItems)
+		subtotal := s.calculateSubtotal(orderItems, biz.Currency)
+		cogs := s.calculateCOGS(orderItems, biz.Currency)
 
-		// compute discount using new fields (DiscountType/DiscountValue) or legacy Discount field
-		discount := s.computeDiscountAmount(subtotal, req)
+		// compute discount from the coupon code, the new fields (DiscountType/DiscountValue)
+		// or the legacy Discount field
+		discount, coupon, err := s.resolveDiscount(tctx, biz, req, subtotal, true)
+		if err != nil {
+			return err
+		}
 
-		vat := s.calculateVAT(subtotal, vatRate)
+		vat := s.calculateVAT(subtotal, vatRate, biz.PricesIncludeVat, biz.Currency)
 		shippingFee := req.ShippingFee
-		if zone != nil {
+		if exempt {
+			shippingFee = decimal.Zero
+		} else if zone != nil {
 			shippingFee = s.shippingFeeFromZone(subtotal, discount, zone)
 		}
-		total := s.calculateTotal(subtotal, vat, shippingFee, discount)
+		total := s.calculateTotal(subtotal, vat, shippingFee, discount, biz.PricesIncludeVat, biz.Currency)
 
 		// generate order number with retry on conflict
 		var orderNumber string
@@ -318,11 +394,14 @@ func (s *Service) CreateOrder(ctx context.Context, actor *account.User, biz *bus
 				CustomerID:        req.CustomerID,
 				ShippingAddressID: req.ShippingAddressID,
 				ShippingZoneID:    shippingZoneID,
+				LocationID:        locationID,
 				Channel:           req.Channel,
 				Subtotal:          subtotal,
 				VAT:               vat,
 				VATRate:           vatRate,
+				PricesIncludeVAT:  biz.PricesIncludeVat,
 				ShippingFee:       shippingFee,
+				ShippingExempt:    exempt,
 				Discount:          discount,
 				DiscountType:      req.DiscountType,
 				DiscountValue:     req.DiscountValue,
@@ -333,8 +412,13 @@ func (s *Service) CreateOrder(ctx context.Context, actor *account.User, biz *bus
 				PaymentStatus:     OrderPaymentStatusPending,
 				PaymentMethod:     paymentMethod,
 				PaymentReference:  req.PaymentReference,
+				Tags:              tags,
+				CustomFields:      customFields,
 				OrderNumber:       orderNumber,
 			}
+			if coupon != nil {
+				applyCouponSnapshot(order, coupon)
+			}
 			if !req.OrderedAt.IsZero() {
 				order.OrderedAt = req.OrderedAt
 			} else {
@@ -358,6 +442,11 @@ func (s *Service) CreateOrder(ctx context.Context, actor *account.User, biz *bus
 		if order == nil || order.ID == "" {
 			return ErrOrderNumberGenerationFailed(nil)
 		}
+		if coupon != nil {
+			if err := s.redeemCoupon(tctx, coupon, order); err != nil {
+				return err
+			}
+		}
 
 		// create order items records
 		for _, oi := range orderItems {
@@ -367,15 +456,13 @@ func (s *Service) CreateOrder(ctx context.Context, actor *account.User, biz *bus
 			return err
 		}
 
-		// adjust inventory levels
-		if err := s.adjustInventoryLevels(tctx, actor, biz, adjustments); err != nil {
-			return err
-		}
-
-		// Apply target status if provided (defaults: pending → target)
+		// Apply target status if provided (defaults: pending → target). Historical orders
+		// went through their lifecycle elsewhere and take their final status as is.
 		if req.Status != nil && *req.Status != OrderStatusPending {
-			sm := newOrderStateMachine(order)
-			if err := sm.transitionStateTo(*req.Status); err != nil {
+			if opts.historical {
+				order.Status = *req.Status
+				order.Status.UpdateTimestampField(order)
+			} else if err := newOrderStateMachine(order).transitionStateTo(*req.Status); err != nil {
 				return err
 			}
 			if err := s.storage.order.UpdateOne(tctx, order); err != nil {
@@ -385,8 +472,10 @@ func (s *Service) CreateOrder(ctx context.Context, actor *account.User, biz *bus
 
 		// Apply target payment status if provided (defaults: pending → target)
 		if req.PaymentStatus != nil && *req.PaymentStatus != OrderPaymentStatusPending {
-			sm := newOrderStateMachine(order)
-			if err := sm.transitionPaymentStatusTo(*req.PaymentStatus); err != nil {
+			if opts.historical {
+				order.PaymentStatus = *req.PaymentStatus
+				order.PaymentStatus.UpdateTimestampField(order)
+			} else if err := newOrderStateMachine(order).transitionPaymentStatusTo(*req.PaymentStatus); err != nil {
 				return err
 			}
 			if err := s.storage.order.UpdateOne(tctx, order); err != nil {
@@ -394,6 +483,10 @@ func (s *Service) CreateOrder(ctx context.Context, actor *account.User, biz *bus
 			}
 		}
 
+		if err := s.recordOrderEvent(tctx, actor, order, OrderEventCreated, createdOrderChanges(order)...); err != nil {
+			return err
+		}
+
 		// Create note if provided
 		if strings.TrimSpace(req.Note) != "" {
 			note := &OrderNote{
@@ -403,6 +496,9 @@ func (s *Service) CreateOrder(ctx context.Context, actor *account.User, biz *bus
 			if err := s.storage.orderNote.CreateOne(tctx, note); err != nil {
 				return err
 			}
+			if err := s.recordOrderEvent(tctx, actor, order, OrderEventNoteAdded, noteChange(note, "", note.Content)); err != nil {
+				return err
+			}
 		}
 
 		// attach items to order for return
@@ -478,23 +574,25 @@ func (s *Service) CreatePendingStorefrontOrder(
 			reqItems = append(reqItems, &CreateOrderItemRequest{
 				VariantID: vid,
 				Quantity:  qty,
-				UnitPrice: v.SalePrice,
-				UnitCost:  v.CostPrice,
+				UnitPrice: v.CurrentPrice(time.Now()),
 			})
 		}
 
-		orderItems, adjustments, err := s.prepareOrderItems(tctx, nil, biz, reqItems)
+		orderItems, adjustments, err := s.prepareOrderItems(tctx, nil, biz, nil, reqItems)
 		if err != nil {
 			return err
 		}
+		if err := s.adjustInventoryLevels(tctx, nil, biz, "", adjustments); err != nil {
+			return err
+		}
 
 		vatRate := biz.VatRate
-		subtotal := s.calculateSubtotal(orderItems)
-		cogs := s.calculateCOGS(orderItems)
-		vat := s.calculateVAT(subtotal, vatRate)
+		subtotal := s.calculateSubtotal(orderItems, biz.Currency)
+		cogs := s.calculateCOGS(orderItems, biz.Currency)
+		vat := s.calculateVAT(subtotal, vatRate, biz.PricesIncludeVat, biz.Currency)
 		shippingFee := decimal.Zero
 		discount := decimal.Zero
-		total := s.calculateTotal(subtotal, vat, shippingFee, discount)
+		total := s.calculateTotal(subtotal, vat, shippingFee, discount, biz.PricesIncludeVat, biz.Currency)
 
 		var orderNumber string
 		const maxRetries = 5
@@ -512,7 +610,9 @@ func (s *Service) CreatePendingStorefrontOrder(
 				Subtotal:          subtotal,
 				VAT:               vat,
 				VATRate:           vatRate,
+				PricesIncludeVAT:  biz.PricesIncludeVat,
 				ShippingFee:       shippingFee,
+				ShippingExempt:    shippingExempt(orderItems),
 				Discount:          discount,
 				COGS:              cogs,
 				Total:             total,
@@ -545,7 +645,7 @@ func (s *Service) CreatePendingStorefrontOrder(
 		if err := s.storage.orderItem.CreateMany(tctx, orderItems); err != nil {
 			return err
 		}
-		if err := s.adjustInventoryLevels(tctx, nil, biz, adjustments); err != nil {
+		if err := s.recordOrderEvent(tctx, nil, created, OrderEventCreated, createdOrderChanges(created)...); err != nil {
 			return err
 		}
 		if strings.TrimSpace(note) != "" {
@@ -553,6 +653,9 @@ func (s *Service) CreatePendingStorefrontOrder(
 			if err := s.storage.orderNote.CreateOne(tctx, on); err != nil {
 				return err
 			}
+			if err := s.recordOrderEvent(tctx, nil, created, OrderEventNoteAdded, noteChange(on, "", on.Content)); err != nil {
+				return err
+			}
 			created.Notes = []*OrderNote{on}
 		}
 		created.Items = orderItems
@@ -566,12 +669,9 @@ func (s *Service) CreatePendingStorefrontOrder(
 
 func (s *Service) UpdateOrder(ctx context.Context, actor *account.User, biz *business.Business, id string, req *UpdateOrderRequest) (*Order, error) {
 	var updated *Order
+	var settled bool
 	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
-		// validate decimal fields
-		if req.ShippingFee.Valid && req.ShippingFee.Decimal.LessThan(decimal.Zero) {
-			return problem.BadRequest("shippingFee cannot be negative")
-		}
-
+		updated, settled = nil, false
 		// load order with items scoped by business
 		ord, err := s.storage.order.FindByID(tctx, id,
 			s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID),
@@ -581,11 +681,29 @@ func (s *Service) UpdateOrder(ctx context.Context, actor *account.User, biz *bus
 		if err != nil {
 			return ErrOrderNotFound(id, err)
 		}
+		if req.Version != nil && *req.Version != ord.Version {
+			return ErrOrderVersionConflict(ord.ID, nil)
+		}
+		before := orderFieldValues(ord)
 
 		// Apply simple field updates
 		if req.Channel != "" {
 			ord.Channel = req.Channel
 		}
+		if req.Tags != nil {
+			tags, err := newOrderTags(*req.Tags)
+			if err != nil {
+				return err
+			}
+			ord.Tags = tags
+		}
+		if len(req.CustomFields) > 0 {
+			fields, err := mergeCustomFields(ord.CustomFields, req.CustomFields)
+			if err != nil {
+				return err
+			}
+			ord.CustomFields = fields
+		}
 
 		// Update shipping address if provided (only allowed before shipped)
 		if req.ShippingAddressID != nil {
@@ -611,14 +729,11 @@ func (s *Service) UpdateOrder(ctx context.Context, actor *account.User, biz *bus
 			if !req.DiscountValue.Valid {
 				return problem.BadRequest("discountValue is required when discountType is provided")
 			}
-			if req.DiscountValue.Decimal.LessThan(decimal.Zero) {
-				return problem.BadRequest("discountValue cannot be negative")
-			}
 
 			// Compute discount amount based on type (rounded to 2 decimals for percentage)
 			var discount decimal.Decimal
 			if req.DiscountType == DiscountTypePercent {
-				discount = ord.Subtotal.Mul(req.DiscountValue.Decimal).Div(decimal.NewFromInt(100)).Round(2)
+				discount = money.Round(ord.Subtotal.Mul(req.DiscountValue.Decimal).Div(decimal.NewFromInt(100)), ord.Currency)
 			} else {
 				discount = req.DiscountValue.Decimal
 			}
@@ -633,8 +748,11 @@ func (s *Service) UpdateOrder(ctx context.Context, actor *account.User, biz *bus
 			ord.DiscountValue = transformer.FromNullDecimal(req.Discount)
 		}
 
-		// shipping zone update takes precedence over manual shippingFee
-		if req.ShippingZoneID != nil {
+		// shipping zone update takes precedence over manual shippingFee; orders with nothing to
+		// ship keep none unless their items change
+		if ord.ShippingExempt && req.Items == nil {
+			// nothing to ship
+		} else if req.ShippingZoneID != nil {
 			zoneID := strings.TrimSpace(*req.ShippingZoneID)
 			if zoneID != "" {
 				ord.ShippingZoneID = &zoneID
@@ -682,14 +800,25 @@ func (s *Service) UpdateOrder(ctx context.Context, actor *account.User, biz *bus
 			}
 
 			// delete existing items and restock inventory to prepare for new ones
-			if err := s.deleteOrderItems(tctx, actor, biz, ord.ID); err != nil {
+			if err := s.deleteOrderItems(tctx, actor, biz, ord, true); err != nil {
+				return err
+			}
+			// create new items, priced from the customer's current price list where unpriced
+			cust, err := s.customer.GetCustomerByID(tctx, actor, biz, ord.CustomerID)
+			if err != nil {
 				return err
 			}
-			// create new items
-			orderItems, adjustments, err := s.prepareOrderItems(tctx, actor, biz, req.Items)
+			priceList, err := s.inventory.ResolvePriceList(tctx, biz, cust.PriceListID.String)
 			if err != nil {
 				return err
 			}
+			orderItems, adjustments, err := s.prepareOrderItems(tctx, actor, biz, priceList, req.Items)
+			if err != nil {
+				return err
+			}
+			if err := s.adjustInventoryLevels(tctx, actor, biz, ord.LocationID, adjustments); err != nil {
+				return err
+			}
 			for _, oi := range orderItems {
 				oi.OrderID = ord.ID
 			}
@@ -697,28 +826,50 @@ func (s *Service) UpdateOrder(ctx context.Context, actor *account.User, biz *bus
 				return err
 			}
 			ord.Items = orderItems
-			if err := s.adjustInventoryLevels(tctx, actor, biz, adjustments); err != nil {
-				return err
-			}
 			// recalculate totals
-			ord.Subtotal = s.calculateSubtotal(orderItems)
-			ord.COGS = s.calculateCOGS(orderItems)
-			ord.VAT = s.calculateVAT(ord.Subtotal, biz.VatRate)
+			ord.Subtotal = s.calculateSubtotal(orderItems, biz.Currency)
+			ord.COGS = s.calculateCOGS(orderItems, biz.Currency)
+			ord.PricesIncludeVAT = biz.PricesIncludeVat
+			ord.VAT = s.calculateVAT(ord.Subtotal, biz.VatRate, ord.PricesIncludeVAT, biz.Currency)
 			// If a shipping zone is set, recompute shipping fee from zone after recalculating subtotal/discount.
-			if ord.ShippingZoneID != nil && s.business != nil {
+			ord.ShippingExempt = shippingExempt(orderItems)
+			if ord.ShippingExempt {
+				ord.ShippingZoneID = nil
+				ord.ShippingFee = decimal.Zero
+			} else if ord.ShippingZoneID != nil && s.business != nil {
 				zone, err := s.business.GetShippingZoneByID(tctx, actor, biz, *ord.ShippingZoneID)
 				if err != nil {
 					return err
 				}
 				ord.ShippingFee = s.shippingFeeFromZone(ord.Subtotal, ord.Discount, zone)
 			}
-			ord.Total = s.calculateTotal(ord.Subtotal, ord.VAT, ord.ShippingFee, ord.Discount)
+			ord.Total = s.calculateTotal(ord.Subtotal, ord.VAT, ord.ShippingFee, ord.Discount, ord.PricesIncludeVAT, biz.Currency)
 
 		}
+		// a lower total may now be covered by the payments already recorded
+		prevPaymentStatus := ord.PaymentStatus
+		if settled, err = s.settlePayments(tctx, ord, nil); err != nil {
+			return err
+		}
 
 		if err := s.storage.order.UpdateOne(tctx, ord); err != nil {
+			if database.IsVersionConflict(err) {
+				return ErrOrderVersionConflict(ord.ID, err)
+			}
 			return err
 		}
+		if changes := diffOrderFields(before, orderFieldValues(ord)); len(changes) > 0 {
+			if err := s.recordOrderEvent(tctx, actor, ord, OrderEventUpdated, changes...); err != nil {
+				return err
+			}
+		}
+		if settled {
+			if err := s.recordOrderEvent(tctx, actor, ord, OrderEventPaymentStatusChanged,
+				OrderFieldChange{Field: "paymentStatus", From: string(prevPaymentStatus), To: string(ord.PaymentStatus)},
+			); err != nil {
+				return err
+			}
+		}
 
 		updated = ord
 		return nil
@@ -726,6 +877,9 @@ func (s *Service) UpdateOrder(ctx context.Context, actor *account.User, biz *bus
 	if err != nil {
 		return nil, err
 	}
+	if settled {
+		s.emitPaidEvent(ctx, updated)
+	}
 	return updated, nil
 }
 
@@ -759,11 +913,23 @@ func (s *Service) AddOrderPaymentDetails(ctx context.Context, actor *account.Use
 				return problem.BadRequest("payment method is disabled for this business").With("paymentMethod", req.PaymentMethod)
 			}
 		}
+		changes := []OrderFieldChange{}
+		if ord.PaymentMethod != req.PaymentMethod {
+			changes = append(changes, OrderFieldChange{Field: "paymentMethod", From: string(ord.PaymentMethod), To: string(req.PaymentMethod)})
+		}
+		if ord.PaymentReference != req.PaymentReference {
+			changes = append(changes, OrderFieldChange{Field: "paymentReference", From: transformer.NullStringPtr(ord.PaymentReference), To: transformer.NullStringPtr(req.PaymentReference)})
+		}
 		ord.PaymentMethod = req.PaymentMethod
 		ord.PaymentReference = req.PaymentReference
 		if err := s.storage.order.UpdateOne(tctx, ord); err != nil {
 			return err
 		}
+		if len(changes) > 0 {
+			if err := s.recordOrderEvent(tctx, actor, ord, OrderEventPaymentDetailsChanged, changes...); err != nil {
+				return err
+			}
+		}
 		updated = ord
 		return nil
 	}, atomic.WithIsolationLevel(atomic.LevelSerializable), atomic.WithRetries(3))
@@ -777,82 +943,159 @@ func (s *Service) AddOrderPaymentDetails(ctx context.Context, actor *account.Use
 type itemVariant struct {
 	variant *inventory.Variant
 	qty     int
+	// item is the order item the units belong to. Restocked units go back at its unit cost;
+	// with costFromStock, the item is costed from the stock issued for it.
+	item          *OrderItem
+	costFromStock bool
 }
 
-// adjustInventoryLevels decreases stock for each variant in adjustments.
-// It guards against negative stock and persists the new quantity.
-func (s *Service) adjustInventoryLevels(ctx context.Context, actor *account.User, biz *business.Business, adjustments []itemVariant) error {
-	return s.applyInventoryAdjustments(ctx, actor, biz, adjustments, -1)
+// restockCost is the unit cost the units go back into stock at: what the item was sold at,
+// or the variant's current cost for items recorded without one.
+func (adj itemVariant) restockCost() decimal.Decimal {
+	if adj.item != nil && adj.item.UnitCost.IsPositive() {
+		return adj.item.UnitCost
+	}
+	return adj.variant.CurrentCost()
 }
 
-// restockInventoryLevels increases stock for each variant in adjustments.
-// Use this when order items are removed/cancelled and stock must be returned.
-func (s *Service) restockInventoryLevels(ctx context.Context, actor *account.User, biz *business.Business, adjustments []itemVariant) error {
-	return s.applyInventoryAdjustments(ctx, actor, biz, adjustments, +1)
+// tracksStock reports whether the units move stock: digital and service products do not.
+func (adj itemVariant) tracksStock() bool {
+	if adj.item != nil {
+		return adj.item.ProductType.TracksStock()
+	}
+	return adj.variant.ProductType().TracksStock()
 }
 
-// ensureInventorySufficient validates inventory availability without mutating stock.
-func (s *Service) ensureInventorySufficient(adjustments []itemVariant) error {
+// stockTracked returns the adjustments of the units that move stock.
+func stockTracked(adjustments []itemVariant) []itemVariant {
+	tracked := make([]itemVariant, 0, len(adjustments))
 	for _, adj := range adjustments {
-		newStock := adj.variant.StockQuantity - adj.qty
-		if newStock < 0 {
-			return ErrInsufficientStock(adj.variant, adj.qty)
+		if adj.tracksStock() {
+			tracked = append(tracked, adj)
 		}
 	}
+	return tracked
+}
+
+// shippingExempt reports whether none of the items is shipped, so the order carries no
+// shipping fee.
+func shippingExempt(items []*OrderItem) bool {
+	for _, it := range items {
+		if it.ProductType.RequiresShipping() {
+			return false
+		}
+	}
+	return len(items) > 0
+}
+
+// adjustInventoryLevels decreases stock for each variant in adjustments at the order's location.
+// It guards against negative stock and costs the items marked costFromStock from the cost
+// layers the units are issued from.
+func (s *Service) adjustInventoryLevels(ctx context.Context, actor *account.User, biz *business.Business, locationID string, adjustments []itemVariant) error {
+	adjustments = stockTracked(adjustments)
+	if len(adjustments) == 0 {
+		return nil
+	}
+	// Aggregate per variant so repeated variants are validated against their combined quantity
+	// and issued once.
+	quantities := make(map[string]int, len(adjustments))
+	for _, adj := range adjustments {
+		quantities[adj.variant.ID] += adj.qty
+		if adj.variant.StockQuantity < quantities[adj.variant.ID] {
+			return ErrInsufficientStock(adj.variant, quantities[adj.variant.ID])
+		}
+	}
+	costs, err := s.inventory.IssueStock(ctx, actor, biz, locationID, quantities)
+	if err != nil {
+		return err
+	}
+	for _, adj := range adjustments {
+		adj.variant.StockQuantity -= adj.qty
+		cost, ok := costs[adj.variant.ID]
+		if !ok || !adj.costFromStock || adj.item == nil {
+			continue
+		}
+		unitCost := cost.Div(decimal.NewFromInt(int64(quantities[adj.variant.ID])))
+		adj.item.UnitCost = money.Round(unitCost, biz.Currency)
+		adj.item.TotalCost = money.Round(unitCost.Mul(decimal.NewFromInt(int64(adj.qty))), biz.Currency)
+	}
 	return nil
 }
 
-// applyInventoryAdjustments applies a signed delta to stock quantity:
-// sign -1 to decrement (allocate), +1 to increment (restock).
-func (s *Service) applyInventoryAdjustments(ctx context.Context, actor *account.User, biz *business.Business, adjustments []itemVariant, sign int) error {
+// restockInventoryLevels increases stock for each variant in adjustments at the order's location,
+// in cost layers at the cost the items were sold at.
+// Use this when order items are removed/cancelled and stock must be returned.
+func (s *Service) restockInventoryLevels(ctx context.Context, actor *account.User, biz *business.Business, ord *Order, adjustments []itemVariant) error {
+	adjustments = stockTracked(adjustments)
+	if len(adjustments) == 0 {
+		return nil
+	}
+	receipts := make(map[string]inventory.StockReceipt, len(adjustments))
 	for _, adj := range adjustments {
-		delta := adj.qty * sign
-		newStock := adj.variant.StockQuantity + delta
+		r := receipts[adj.variant.ID]
+		value := r.UnitCost.Mul(decimal.NewFromInt(int64(r.Quantity))).Add(adj.restockCost().Mul(decimal.NewFromInt(int64(adj.qty))))
+		r.Quantity += adj.qty
+		r.UnitCost = value.Div(decimal.NewFromInt(int64(r.Quantity)))
+		receipts[adj.variant.ID] = r
+	}
+	if err := s.inventory.ReceiveStock(ctx, actor, biz, ord.LocationID, inventory.CostLayerSourceOrderRestock, ord.ID, receipts); err != nil {
+		return err
+	}
+	for _, adj := range adjustments {
+		adj.variant.StockQuantity += adj.qty
+	}
+	return nil
+}
+
+// ensureInventorySufficient validates inventory availability without mutating stock.
+func (s *Service) ensureInventorySufficient(adjustments []itemVariant) error {
+	for _, adj := range stockTracked(adjustments) {
+		newStock := adj.variant.StockQuantity - adj.qty
 		if newStock < 0 {
 			return ErrInsufficientStock(adj.variant, adj.qty)
 		}
-		adj.variant.StockQuantity = newStock
-		stock := newStock
-		if err := s.inventory.UpdateVariant(ctx, actor, biz, adj.variant.ID, &inventory.UpdateVariantRequest{
-			StockQuantity: &stock,
-		}); err != nil {
-			return err
-		}
 	}
 	return nil
 }
 
-func (s *Service) calculateSubtotal(items []*OrderItem) decimal.Decimal {
+func (s *Service) calculateSubtotal(items []*OrderItem, currency string) decimal.Decimal {
 	subtotal := decimal.Zero
 	for _, it := range items {
 		subtotal = subtotal.Add(it.Total)
 	}
-	// Round to 2 decimals (money precision)
-	return subtotal.Round(2)
+	return money.Round(subtotal, currency)
 }
 
-func (s *Service) calculateCOGS(items []*OrderItem) decimal.Decimal {
+func (s *Service) calculateCOGS(items []*OrderItem, currency string) decimal.Decimal {
 	cogs := decimal.Zero
 	for _, it := range items {
 		cogs = cogs.Add(it.TotalCost)
 	}
-	// Round to 2 decimals (money precision)
-	return cogs.Round(2)
+	return money.Round(cogs, currency)
 }
 
-func (s *Service) calculateTotal(subtotal, vat, shippingFee, discount decimal.Decimal) decimal.Decimal {
-	// Round to 2 decimals (money precision)
-	return subtotal.Add(vat).Add(shippingFee).Sub(discount).Round(2)
+// calculateTotal adds VAT on top of the subtotal, unless prices already include it.
+func (s *Service) calculateTotal(subtotal, vat, shippingFee, discount decimal.Decimal, pricesIncludeVAT bool, currency string) decimal.Decimal {
+	if pricesIncludeVAT {
+		vat = decimal.Zero
+	}
+	return money.Round(subtotal.Add(vat).Add(shippingFee).Sub(discount), currency)
 }
 
-func (s *Service) calculateVAT(subtotal, vatRate decimal.Decimal) decimal.Decimal {
-	// Round to 2 decimals (money precision)
-	return subtotal.Mul(vatRate).Round(2)
+// calculateVAT returns the VAT charged on subtotal. With VAT-inclusive prices the subtotal is
+// gross, so the VAT is the tax component it contains: subtotal * rate / (1 + rate).
+func (s *Service) calculateVAT(subtotal, vatRate decimal.Decimal, pricesIncludeVAT bool, currency string) decimal.Decimal {
+	if pricesIncludeVAT {
+		return money.Round(subtotal.Mul(vatRate).Div(decimal.NewFromInt(1).Add(vatRate)), currency)
+	}
+	return money.Round(subtotal.Mul(vatRate), currency)
 }
 
-func (s *Service) deleteOrderItems(ctx context.Context, actor *account.User, biz *business.Business, orderID string) error {
+// deleteOrderItems deletes the items of an order and, with restock, puts them back in stock.
+// Items of expired orders were already restocked when the order expired.
+func (s *Service) deleteOrderItems(ctx context.Context, actor *account.User, biz *business.Business, ord *Order, restock bool) error {
 	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
-		orderItems, err := s.storage.orderItem.FindMany(tctx, s.storage.orderItem.ScopeEquals(OrderItemSchema.OrderID, orderID), s.storage.orderItem.WithPreload(inventory.VariantStruct))
+		orderItems, err := s.storage.orderItem.FindMany(tctx, s.storage.orderItem.ScopeEquals(OrderItemSchema.OrderID, ord.ID), s.storage.orderItem.WithPreload(inventory.VariantStruct))
 		if err != nil {
 			return err
 		}
@@ -861,18 +1104,25 @@ func (s *Service) deleteOrderItems(ctx context.Context, actor *account.User, biz
 			adjustments = append(adjustments, itemVariant{
 				variant: oi.Variant,
 				qty:     oi.Quantity,
+				item:    oi,
 			})
 		}
 		if err := s.storage.orderItem.DeleteMany(tctx,
-			s.storage.orderItem.ScopeEquals(OrderItemSchema.OrderID, orderID),
+			s.storage.orderItem.ScopeEquals(OrderItemSchema.OrderID, ord.ID),
 		); err != nil {
 			return err
 		}
-		return s.restockInventoryLevels(tctx, actor, biz, adjustments)
+		if !restock {
+			return nil
+		}
+		return s.restockInventoryLevels(tctx, actor, biz, ord, adjustments)
 	})
 }
 
-func (s *Service) prepareOrderItems(ctx context.Context, actor *account.User, biz *business.Business, reqItems []*CreateOrderItemRequest) ([]*OrderItem, []itemVariant, error) {
+// prepareOrderItems builds order items from the request. Items sent without a unitPrice are
+// priced from priceList (nil prices at the variant's SalePrice); items sent without a unitCost
+// are costed when adjustInventoryLevels takes them out of stock.
+func (s *Service) prepareOrderItems(ctx context.Context, actor *account.User, biz *business.Business, priceList *inventory.PriceList, reqItems []*CreateOrderItemRequest) ([]*OrderItem, []itemVariant, error) {
 	orderItems := make([]*OrderItem, 0, len(reqItems))
 	adjustments := make([]itemVariant, 0, len(reqItems))
 
@@ -884,30 +1134,40 @@ func (s *Service) prepareOrderItems(ctx context.Context, actor *account.User, bi
 		if reqItem.Quantity <= 0 {
 			return nil, nil, ErrInvalidOrderItemQuantity(reqItem.VariantID, reqItem.Quantity)
 		}
-		// validate decimal fields
-		if reqItem.UnitPrice.LessThanOrEqual(decimal.Zero) {
-			return nil, nil, problem.BadRequest("unitPrice must be greater than zero").With("variantId", reqItem.VariantID)
+		unitPrice := reqItem.UnitPrice
+		if unitPrice.IsZero() {
+			unitPrice = priceList.PriceFor(variant, reqItem.Quantity, biz.Currency)
 		}
-		if reqItem.UnitCost.LessThan(decimal.Zero) {
-			return nil, nil, problem.BadRequest("unitCost cannot be negative").With("variantId", reqItem.VariantID)
+		// Items without a unit cost are costed from the stock issued for them; until then they
+		// carry the variant's current cost as an estimate.
+		unitCost := reqItem.UnitCost
+		costFromStock := unitCost.IsZero()
+		if costFromStock {
+			unitCost = variant.CurrentCost()
 		}
 		// Create order item (round line totals to 2 decimals for money precision)
 		orderItem := &OrderItem{
-			VariantID: reqItem.VariantID,
-			ProductID: variant.ProductID,
-			Currency:  biz.Currency,
-			Quantity:  reqItem.Quantity,
-			UnitPrice: reqItem.UnitPrice.Round(2),
-			UnitCost:  reqItem.UnitCost.Round(2),
-			Total:     reqItem.UnitPrice.Mul(decimal.NewFromInt(int64(reqItem.Quantity))).Round(2),
-			TotalCost: reqItem.UnitCost.Mul(decimal.NewFromInt(int64(reqItem.Quantity))).Round(2),
+			VariantID:   reqItem.VariantID,
+			ProductID:   variant.ProductID,
+			ProductType: variant.ProductType(),
+			Currency:    biz.Currency,
+			Quantity:    reqItem.Quantity,
+			UnitPrice:   money.Round(unitPrice, biz.Currency),
+			UnitCost:    money.Round(unitCost, biz.Currency),
+			Total:       money.Round(unitPrice.Mul(decimal.NewFromInt(int64(reqItem.Quantity))), biz.Currency),
+			TotalCost:   money.Round(unitCost.Mul(decimal.NewFromInt(int64(reqItem.Quantity))), biz.Currency),
+		}
+		if orderItem.ProductType == inventory.ProductTypeDigital && variant.Product != nil {
+			orderItem.Download = variant.Product.Download
 		}
 		orderItems = append(orderItems, orderItem)
 
 		// Prepare inventory adjustment
 		adjustments = append(adjustments, itemVariant{
-			variant: variant,
-			qty:     reqItem.Quantity,
+			variant:       variant,
+			qty:           reqItem.Quantity,
+			item:          orderItem,
+			costFromStock: costFromStock,
 		})
 	}
 
@@ -915,54 +1175,128 @@ func (s *Service) prepareOrderItems(ctx context.Context, actor *account.User, bi
 }
 
 func (s *Service) UpdateOrderStatus(ctx context.Context, actor *account.User, biz *business.Business, id string, status OrderStatus) (*Order, error) {
-	order, err := s.storage.order.FindByID(ctx, id, s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID))
-	if err != nil {
-		return nil, ErrOrderNotFound(id, err)
+	if status == OrderStatusReturned {
+		return s.ReturnOrder(ctx, actor, biz, id, false)
 	}
-	sm := newOrderStateMachine(order)
-
-	if err := sm.transitionStateTo(status); err != nil {
+	var order *Order
+	var outcome *transitionOutcome
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		ord, err := s.storage.order.FindByID(tctx, id,
+			s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID),
+			s.storage.order.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return ErrOrderNotFound(id, err)
+		}
+		out, err := s.transitionOrder(tctx, actor, biz, ord, status)
+		if err != nil {
+			return err
+		}
+		if err := s.storage.order.UpdateOne(tctx, ord); err != nil {
+			return err
+		}
+		order, outcome = ord, out
+		if err := s.recordOrderEvent(tctx, actor, ord, OrderEventStatusChanged, out.changes(ord)...); err != nil {
+			return err
+		}
+		if !out.markedPaid {
+			return nil
+		}
+		return s.recordOrderEvent(tctx, actor, ord, OrderEventPaymentStatusChanged, OrderFieldChange{Field: "paymentStatus", From: string(out.prevPayment), To: string(ord.PaymentStatus)})
+	})
+	if err != nil {
 		return nil, err
 	}
-	if err := s.storage.order.UpdateOne(ctx, order); err != nil {
-		return nil, err
+	s.emitStatusEvent(ctx, order)
+	if outcome.markedPaid {
+		s.emitPaidEvent(ctx, order)
 	}
+	s.emitStatusChangedEvent(ctx, actor, order, outcome)
 	return order, nil
 }
 
-func (s *Service) UpdateOrderPaymentStatus(ctx context.Context, actor *account.User, biz *business.Business, id string, paymentStatus OrderPaymentStatus) (*Order, error) {
-	order, err := s.storage.order.FindByID(ctx, id, s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID))
+// ReturnOrder moves an order to "returned". With restock, its items are put back in stock in
+// the same transaction, which lets accounting reverse the order's COGS as well as its revenue.
+func (s *Service) ReturnOrder(ctx context.Context, actor *account.User, biz *business.Business, id string, restock bool) (*Order, error) {
+	var returned *Order
+	var outcome *transitionOutcome
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		ord, err := s.storage.order.FindByID(tctx, id,
+			s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID),
+			s.storage.order.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return ErrOrderNotFound(id, err)
+		}
+		out, err := s.transitionOrder(tctx, actor, biz, ord, OrderStatusReturned)
+		if err != nil {
+			return err
+		}
+		if err := s.storage.order.UpdateOne(tctx, ord); err != nil {
+			return err
+		}
+		changes := append(out.changes(ord), OrderFieldChange{Field: "restocked", To: restock})
+		if err := s.recordOrderEvent(tctx, actor, ord, OrderEventStatusChanged, changes...); err != nil {
+			return err
+		}
+		returned, outcome = ord, out
+		if !restock {
+			return nil
+		}
+		items, err := s.storage.orderItem.FindMany(tctx,
+			s.storage.orderItem.ScopeEquals(OrderItemSchema.OrderID, ord.ID),
+			s.storage.orderItem.WithPreload(inventory.VariantStruct),
+		)
+		if err != nil {
+			return err
+		}
+		adjustments := make([]itemVariant, 0, len(items))
+		for _, it := range items {
+			// Items of since-deleted variants have nowhere to go back to.
+			if it.Variant == nil {
+				continue
+			}
+			adjustments = append(adjustments, itemVariant{variant: it.Variant, qty: it.Quantity, item: it})
+		}
+		return s.restockInventoryLevels(tctx, actor, biz, ord, adjustments)
+	})
 	if err != nil {
-		return nil, ErrOrderNotFound(id, err)
+		return nil, err
 	}
-	prevPaymentStatus := order.PaymentStatus
-	sm := newOrderStateMachine(order)
+	s.emitReturnedEvent(ctx, returned, restock)
+	s.emitStatusChangedEvent(ctx, actor, returned, outcome)
+	return returned, nil
+}
 
-	if err := sm.transitionPaymentStatusTo(paymentStatus); err != nil {
+func (s *Service) UpdateOrderPaymentStatus(ctx context.Context, actor *account.User, biz *business.Business, id string, paymentStatus OrderPaymentStatus) (*Order, error) {
+	var order *Order
+	var prevPaymentStatus OrderPaymentStatus
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		ord, err := s.storage.order.FindByID(tctx, id,
+			s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID),
+			s.storage.order.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return ErrOrderNotFound(id, err)
+		}
+		prevPaymentStatus = ord.PaymentStatus
+		if err := newOrderStateMachine(ord).transitionPaymentStatusTo(paymentStatus); err != nil {
+			return err
+		}
+		if err := s.storage.order.UpdateOne(tctx, ord); err != nil {
+			return err
+		}
+		order = ord
+		return s.recordOrderEvent(tctx, actor, ord, OrderEventPaymentStatusChanged, OrderFieldChange{Field: "paymentStatus", From: string(prevPaymentStatus), To: string(ord.PaymentStatus)})
+	})
+	if err != nil {
 		return nil, err
 	}
-	if err := s.storage.order.UpdateOne(ctx, order); err != nil {
-		return nil, err
+	if paymentStatus == OrderPaymentStatusPaid && prevPaymentStatus != OrderPaymentStatusPaid {
+		s.emitPaidEvent(ctx, order)
 	}
-	// Emit background automation event when an order becomes paid.
-	if paymentStatus == OrderPaymentStatusPaid && prevPaymentStatus != OrderPaymentStatusPaid && s.bus != nil {
-		paidAt := time.Now().UTC()
-		if order.PaidAt.Valid {
-			paidAt = order.PaidAt.Time
-		}
-		// Detach from the request lifecycle so async handlers can complete.
-		// This preserves context values (e.g. trace ID) but prevents cancellation
-		// when the HTTP request finishes.
-		bgctx := context.WithoutCancel(ctx)
-		s.bus.Emit(bus.OrderPaymentSucceededTopic, &bus.OrderPaymentSucceededEvent{
-			Ctx:           bgctx,
-			BusinessID:    biz.ID,
-			OrderID:       order.ID,
-			PaymentMethod: string(order.PaymentMethod),
-			OrderTotal:    order.Total,
-			Currency:      order.Currency,
-			PaidAt:        paidAt,
-		})
+	if paymentStatus == OrderPaymentStatusRefunded && prevPaymentStatus != OrderPaymentStatusRefunded {
+		s.emitRefundedEvent(ctx, order)
 	}
 	return order, nil
 }
@@ -975,6 +1309,30 @@ func (s *Service) GetOrderByID(ctx context.Context, actor *account.User, biz *bu
 	return s.storage.order.FindByID(ctx, id, findOpts...)
 }
 
+// ExistingOrderIDs reports which of ids are orders of the business.
+func (s *Service) ExistingOrderIDs(ctx context.Context, actor *account.User, biz *business.Business, ids []string) (map[string]bool, error) {
+	found := make(map[string]bool, len(ids))
+	if len(ids) == 0 {
+		return found, nil
+	}
+	values := make([]any, len(ids))
+	for i, id := range ids {
+		values[i] = id
+	}
+	orders, err := s.storage.order.FindMany(ctx,
+		s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID),
+		s.storage.order.ScopeIDs(values),
+		s.storage.order.WithSelect("orders.id"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range orders {
+		found[o.ID] = true
+	}
+	return found, nil
+}
+
 func (s *Service) GetOrderByOrderNumber(ctx context.Context, actor *account.User, biz *business.Business, orderNumber string) (*Order, error) {
 	findOpts := []func(*gorm.DB) *gorm.DB{
 		s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID),
@@ -990,11 +1348,15 @@ type ListOrdersFilters struct {
 	Channels        []string
 	CustomerID      string
 	OrderNumber     string
-	From            time.Time
-	To              time.Time
+	// Tags keeps orders carrying all of the given tags.
+	Tags []string
+	From time.Time
+	To   time.Time
 }
 
-func (s *Service) ListOrders(ctx context.Context, actor *account.User, biz *business.Business, req *list.ListRequest, filters *ListOrdersFilters) ([]*Order, int64, error) {
+// orderListScopes builds the filtering scopes shared by the order list queries.
+// base scopes apply to both the page query and the total count; extra scopes only apply to the page query.
+func (s *Service) orderListScopes(biz *business.Business, req *list.ListRequest, filters *ListOrdersFilters) ([]func(db *gorm.DB) *gorm.DB, []func(db *gorm.DB) *gorm.DB, error) {
 	baseScopes := []func(db *gorm.DB) *gorm.DB{
 		s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID),
 	}
@@ -1023,6 +1385,9 @@ func (s *Service) ListOrders(ctx context.Context, actor *account.User, biz *busi
 		if filters.OrderNumber != "" {
 			baseScopes = append(baseScopes, s.storage.order.ScopeEquals(OrderSchema.OrderNumber, filters.OrderNumber))
 		}
+		if len(filters.Tags) > 0 {
+			baseScopes = append(baseScopes, s.storage.ScopeTags(filters.Tags))
+		}
 		if !filters.From.IsZero() || !filters.To.IsZero() {
 			baseScopes = append(baseScopes, s.storage.order.ScopeTime(OrderSchema.OrderedAt, filters.From, filters.To))
 		}
@@ -1036,20 +1401,58 @@ func (s *Service) ListOrders(ctx context.Context, actor *account.User, biz *busi
 			s.storage.ScopeOrderSearch(term),
 		)
 		if !req.HasExplicitOrderBy() {
-			rankExpr, err := database.WebSearchRankOrder(term, "orders.search_vector", "customers.search_vector")
+			rankExpr, err := database.SearchRankOrder(term, []string{"orders.search_vector", "customers.search_vector"}, []string{"customers.name"})
 			if err != nil {
-				return nil, 0, err
+				return nil, nil, err
 			}
 			listExtra = append(listExtra, s.storage.order.WithOrderByExpr(rankExpr))
 		}
 	}
+	listExtra = append(listExtra,
+		s.storage.order.WithPagination(req.Offset(), req.Limit()),
+		s.storage.order.WithOrderBy(req.ParsedOrderBy(OrderSchema)),
+	)
+	return baseScopes, listExtra, nil
+}
+
+func (s *Service) ListOrders(ctx context.Context, actor *account.User, biz *business.Business, req *list.ListRequest, filters *ListOrdersFilters) ([]*Order, int64, error) {
+	baseScopes, listExtra, err := s.orderListScopes(biz, req, filters)
+	if err != nil {
+		return nil, 0, err
+	}
 
 	findOpts := append([]func(*gorm.DB) *gorm.DB{}, baseScopes...)
 	findOpts = append(findOpts, listExtra...)
 	findOpts = append(findOpts, s.orderListPreloads()...)
+	items, err := s.storage.order.FindMany(ctx, findOpts...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	count, err := s.storage.order.Count(ctx, baseScopes...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, count, nil
+}
+
+// ListOrderSummaries returns the same page as ListOrders using a lean projection:
+// only the columns needed by OrderSummaryResponse are loaded, the customer is loaded
+// with its id and name only, and items, notes, addresses and zones are not preloaded.
+func (s *Service) ListOrderSummaries(ctx context.Context, actor *account.User, biz *business.Business, req *list.ListRequest, filters *ListOrdersFilters) ([]*Order, int64, error) {
+	baseScopes, listExtra, err := s.orderListScopes(biz, req, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOpts := append([]func(*gorm.DB) *gorm.DB{}, baseScopes...)
+	findOpts = append(findOpts, listExtra...)
 	findOpts = append(findOpts,
-		s.storage.order.WithPagination(req.Offset(), req.Limit()),
-		s.storage.order.WithOrderBy(req.ParsedOrderBy(OrderSchema)),
+		s.storage.order.WithSelect(orderSummaryColumns...),
+		s.storage.order.WithPreloadSelect(customer.CustomerStruct,
+			customer.CustomerSchema.ID.Column(),
+			customer.CustomerSchema.Name.Column(),
+		),
 	)
 	items, err := s.storage.order.FindMany(ctx, findOpts...)
 	if err != nil {
@@ -1092,7 +1495,14 @@ func (s *Service) DeleteOrder(ctx context.Context, actor *account.User, biz *bus
 			return ErrOrderCannotBeDeleted(order.ID, order.Status)
 		}
 		// delete order items and restock inventory
-		if err := s.deleteOrderItems(tctx, actor, biz, order.ID); err != nil {
+		if err := s.deleteOrderItems(tctx, actor, biz, order, !order.ExpiredAt.Valid); err != nil {
+			return err
+		}
+		// give back the coupon use
+		if err := s.releaseCoupon(tctx, order); err != nil {
+			return err
+		}
+		if err := s.recordOrderEvent(tctx, actor, order, OrderEventDeleted); err != nil {
 			return err
 		}
 		// delete order
@@ -1106,21 +1516,29 @@ func (s *Service) CreateOrderNote(ctx context.Context, actor *account.User, biz
 		return nil, ErrOrderRateLimited()
 	}
 
-	if _, err := s.storage.order.FindByID(ctx, orderID, s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID)); err != nil {
+	ord, err := s.storage.order.FindByID(ctx, orderID, s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID))
+	if err != nil {
 		return nil, ErrOrderNotFound(orderID, err)
 	}
 	note := &OrderNote{
 		OrderID: orderID,
 		Content: req.Content,
 	}
-	if err := s.storage.orderNote.CreateOne(ctx, note); err != nil {
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.orderNote.CreateOne(tctx, note); err != nil {
+			return err
+		}
+		return s.recordOrderEvent(tctx, actor, ord, OrderEventNoteAdded, noteChange(note, "", note.Content))
+	})
+	if err != nil {
 		return nil, err
 	}
 	return note, nil
 }
 
 func (s *Service) UpdateOrderNote(ctx context.Context, actor *account.User, biz *business.Business, orderID string, noteID string, req *UpdateOrderNoteRequest) (*OrderNote, error) {
-	if _, err := s.storage.order.FindByID(ctx, orderID, s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID)); err != nil {
+	ord, err := s.storage.order.FindByID(ctx, orderID, s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID))
+	if err != nil {
 		return nil, ErrOrderNotFound(orderID, err)
 	}
 	note, err := s.storage.orderNote.FindOne(ctx,
@@ -1130,10 +1548,19 @@ func (s *Service) UpdateOrderNote(ctx context.Context, actor *account.User, biz
 	if err != nil {
 		return nil, ErrOrderNoteNotFound(noteID, err)
 	}
+	prevContent := note.Content
 	if req.Content != "" {
 		note.Content = req.Content
 	}
-	err = s.storage.orderNote.UpdateOne(ctx, note)
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.orderNote.UpdateOne(tctx, note); err != nil {
+			return err
+		}
+		if note.Content == prevContent {
+			return nil
+		}
+		return s.recordOrderEvent(tctx, actor, ord, OrderEventNoteUpdated, noteChange(note, prevContent, note.Content))
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -1141,7 +1568,8 @@ func (s *Service) UpdateOrderNote(ctx context.Context, actor *account.User, biz
 }
 
 func (s *Service) DeleteOrderNote(ctx context.Context, actor *account.User, biz *business.Business, orderID string, noteID string) error {
-	if _, err := s.storage.order.FindByID(ctx, orderID, s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID)); err != nil {
+	ord, err := s.storage.order.FindByID(ctx, orderID, s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID))
+	if err != nil {
 		return ErrOrderNotFound(orderID, err)
 	}
 	note, err := s.storage.orderNote.FindOne(ctx,
@@ -1151,11 +1579,39 @@ func (s *Service) DeleteOrderNote(ctx context.Context, actor *account.User, biz
 	if err != nil {
 		return ErrOrderNoteNotFound(noteID, err)
 	}
-	return s.storage.orderNote.DeleteOne(ctx, note)
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.orderNote.DeleteOne(tctx, note); err != nil {
+			return err
+		}
+		return s.recordOrderEvent(tctx, actor, ord, OrderEventNoteDeleted, noteChange(note, note.Content, ""))
+	})
 }
 
+// salesScopes scope the orders of the business that count as sales within [from, to] on the
+// business's accounting basis: by the date they were placed on accrual, or by the date they
+// were paid on a cash basis, where unpaid orders do not count.
+func (s *Service) salesScopes(biz *business.Business, from, to time.Time) []func(db *gorm.DB) *gorm.DB {
+	scopes := []func(db *gorm.DB) *gorm.DB{
+		s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID),
+		s.storage.order.ScopeTime(salesDateField(biz), from, to),
+	}
+	if biz.CashBasis() {
+		scopes = append(scopes, s.storage.order.ScopeWhere("orders.paid_at IS NOT NULL"))
+	}
+	return scopes
+}
+
+// salesDateField is the order date sales are reported on for the business's accounting basis.
+func salesDateField(biz *business.Business) schema.Field {
+	if biz.CashBasis() {
+		return OrderSchema.PaidAt
+	}
+	return OrderSchema.OrderedAt
+}
+
+// SumOrdersTotal returns the sales within [from, to] on the business's accounting basis.
 func (s *Service) SumOrdersTotal(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) (decimal.Decimal, error) {
-	return s.storage.order.Sum(ctx, OrderSchema.Total, s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID), s.storage.order.ScopeTime(OrderSchema.OrderedAt, from, to))
+	return s.storage.order.Sum(ctx, OrderSchema.Total, s.salesScopes(biz, from, to)...)
 }
 
 func (s *Service) CountOpenOrders(ctx context.Context, actor *account.User, biz *business.Business) (int64, error) {
@@ -1170,15 +1626,15 @@ func (s *Service) CountOpenOrders(ctx context.Context, actor *account.User, biz
 }
 
 func (s *Service) AvgOrdersTotal(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) (decimal.Decimal, error) {
-	return s.storage.order.Avg(ctx, OrderSchema.Total, s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID), s.storage.order.ScopeTime(OrderSchema.OrderedAt, from, to))
+	return s.storage.order.Avg(ctx, OrderSchema.Total, s.salesScopes(biz, from, to)...)
 }
 
 func (s *Service) SumOrdersCOGS(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) (decimal.Decimal, error) {
-	return s.storage.order.Sum(ctx, OrderSchema.COGS, s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID), s.storage.order.ScopeTime(OrderSchema.OrderedAt, from, to))
+	return s.storage.order.Sum(ctx, OrderSchema.COGS, s.salesScopes(biz, from, to)...)
 }
 
 func (s *Service) AvgOrdersCOGS(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) (decimal.Decimal, error) {
-	return s.storage.order.Avg(ctx, OrderSchema.COGS, s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID), s.storage.order.ScopeTime(OrderSchema.OrderedAt, from, to))
+	return s.storage.order.Avg(ctx, OrderSchema.COGS, s.salesScopes(biz, from, to)...)
 }
 
 func (s *Service) TopOrdersByTotal(ctx context.Context, actor *account.User, biz *business.Business, limit int, from, to time.Time) ([]*Order, error) {
@@ -1201,16 +1657,13 @@ func (s *Service) TopOrdersByCOGS(ctx context.Context, actor *account.User, biz
 
 func (s *Service) ComputeRevenueTimeSeries(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) (*timeseries.TimeSeries, error) {
 	granularity := timeseries.GetTimeGranularityByDateRange(from, to)
-	return s.storage.order.TimeSeriesSum(ctx, OrderSchema.Total, OrderSchema.OrderedAt, granularity,
-		s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID),
-		s.storage.order.ScopeTime(OrderSchema.OrderedAt, from, to),
-	)
+	return s.storage.order.TimeSeriesSum(ctx, OrderSchema.Total, salesDateField(biz), granularity, biz.ReportingCalendar(), s.salesScopes(biz, from, to)...)
 }
 
 // ComputeOrdersCountTimeSeries returns a time series of order counts over time (bucketed by date granularity) within the given range.
 func (s *Service) ComputeOrdersCountTimeSeries(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) (*timeseries.TimeSeries, error) {
 	granularity := timeseries.GetTimeGranularityByDateRange(from, to)
-	return s.storage.order.TimeSeriesCount(ctx, OrderSchema.OrderedAt, granularity,
+	return s.storage.order.TimeSeriesCount(ctx, OrderSchema.OrderedAt, granularity, biz.ReportingCalendar(),
 		s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID),
 		s.storage.order.ScopeTime(OrderSchema.OrderedAt, from, to),
 	)
@@ -1262,6 +1715,17 @@ func (s *Service) ComputeTopSellingProducts(ctx context.Context, actor *account.
 	return products, nil
 }
 
+// SumSalesByProduct returns quantity, item revenue and COGS per product for the sales within the
+// range on the business's accounting basis.
+func (s *Service) SumSalesByProduct(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) ([]ProductSales, error) {
+	return s.storage.SumItemsByProduct(ctx, biz.ID, biz.CashBasis(), from, to)
+}
+
+// SumItemRevenueByOrderProduct returns how the item revenue of each given order splits across its products.
+func (s *Service) SumItemRevenueByOrderProduct(ctx context.Context, actor *account.User, biz *business.Business, orderIDs []string) ([]OrderProductRevenue, error) {
+	return s.storage.SumItemRevenueByOrderProduct(ctx, biz.ID, orderIDs)
+}
+
 // CountOrdersByStatus returns a breakdown of order counts by status over the given date range.
 func (s *Service) CountOrdersByStatus(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) ([]keyvalue.KeyValue, error) {
 	return s.storage.order.CountBy(ctx, OrderSchema.Status,
@@ -1273,9 +1737,9 @@ func (s *Service) CountOrdersByStatus(ctx context.Context, actor *account.User,
 // SumOrdersTotalByChannel returns revenue grouped by sales channel for the given range.
 func (s *Service) SumOrdersTotalByChannel(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) ([]keyvalue.KeyValue, error) {
 	return s.storage.order.SumBy(ctx, OrderSchema.Channel, OrderSchema.Total,
-		s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID),
-		s.storage.order.ScopeTime(OrderSchema.OrderedAt, from, to),
-		s.storage.order.WithOrderBy([]string{fmt.Sprintf("%s DESC", keyvalue.Schema.Value.Column())}),
+		append(s.salesScopes(biz, from, to),
+			s.storage.order.WithOrderBy([]string{fmt.Sprintf("%s DESC", keyvalue.Schema.Value.Column())}),
+		)...,
 	)
 }
 
@@ -1283,9 +1747,7 @@ func (s *Service) SumOrdersTotalByChannel(ctx context.Context, actor *account.Us
 func (s *Service) SumOrdersTotalByCountry(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) ([]keyvalue.KeyValue, error) {
 	// Load orders with ShippingAddress to sum in-memory for correctness and simplicity
 	orders, err := s.storage.order.FindMany(ctx,
-		s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID),
-		s.storage.order.ScopeTime(OrderSchema.OrderedAt, from, to),
-		s.storage.order.WithPreload(ShippingAddressStruct),
+		append(s.salesScopes(biz, from, to), s.storage.order.WithPreload(ShippingAddressStruct))...,
 	)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,275 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// couponCodePattern is the shape of a normalized coupon code.
+var couponCodePattern = regexp.MustCompile(`^[A-Z0-9_-]+$`)
+
+// normalizeCouponCode makes codes case-insensitive: customers type "summer10" for SUMMER10.
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (s *Service) ListCoupons(ctx context.Context, actor *account.User, biz *business.Business) ([]*Coupon, error) {
+	return s.storage.coupon.FindMany(ctx,
+		s.storage.coupon.ScopeBusinessID(biz.ID),
+		s.storage.coupon.WithOrderBy([]string{CouponSchema.CreatedAt.Column() + " DESC"}),
+	)
+}
+
+func (s *Service) GetCouponByID(ctx context.Context, actor *account.User, biz *business.Business, couponID string) (*Coupon, error) {
+	c, err := s.storage.coupon.FindOne(ctx,
+		s.storage.coupon.ScopeBusinessID(biz.ID),
+		s.storage.coupon.ScopeID(couponID),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrCouponNotFound(couponID, err)
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *Service) CreateCoupon(ctx context.Context, actor *account.User, biz *business.Business, req *CreateCouponRequest) (*Coupon, error) {
+	code := normalizeCouponCode(req.Code)
+	if !couponCodePattern.MatchString(code) {
+		return nil, ErrInvalidCoupon("code", "only letters, digits, '-' and '_' are allowed")
+	}
+	c := &Coupon{
+		BusinessID:         biz.ID,
+		Code:               code,
+		Description:        strings.TrimSpace(req.Description),
+		DiscountType:       req.DiscountType,
+		DiscountValue:      req.DiscountValue,
+		MinSubtotal:        req.MinSubtotal,
+		MaxUses:            req.MaxUses,
+		MaxUsesPerCustomer: req.MaxUsesPerCustomer,
+		StartsAt:           nullTimeFromPtr(req.StartsAt),
+		ExpiresAt:          nullTimeFromPtr(req.ExpiresAt),
+		Active:             true,
+	}
+	if req.Active != nil {
+		c.Active = *req.Active
+	}
+	if customerID := strings.TrimSpace(req.CustomerID); customerID != "" {
+		if _, err := s.customer.GetCustomerByID(ctx, actor, biz, customerID); err != nil {
+			return nil, err
+		}
+		c.CustomerID = transformer.ToNullString(customerID)
+	}
+	if err := validateCoupon(c); err != nil {
+		return nil, err
+	}
+	if err := s.storage.coupon.CreateOne(ctx, c); err != nil {
+		if database.IsUniqueViolation(err) {
+			return nil, ErrCouponCodeTaken(code, err)
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *Service) UpdateCoupon(ctx context.Context, actor *account.User, biz *business.Business, couponID string, req *UpdateCouponRequest) (*Coupon, error) {
+	c, err := s.GetCouponByID(ctx, actor, biz, couponID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Description != nil {
+		c.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.DiscountType != "" {
+		c.DiscountType = req.DiscountType
+	}
+	if req.DiscountValue.Valid {
+		c.DiscountValue = req.DiscountValue.Decimal
+	}
+	if req.MinSubtotal.Valid {
+		c.MinSubtotal = req.MinSubtotal.Decimal
+	}
+	if req.MaxUses != nil {
+		c.MaxUses = *req.MaxUses
+	}
+	if req.MaxUsesPerCustomer != nil {
+		c.MaxUsesPerCustomer = *req.MaxUsesPerCustomer
+	}
+	if req.StartsAt != nil {
+		c.StartsAt = nullTimeFromPtr(req.StartsAt)
+	}
+	if req.ExpiresAt != nil {
+		c.ExpiresAt = nullTimeFromPtr(req.ExpiresAt)
+	}
+	if req.Active != nil {
+		c.Active = *req.Active
+	}
+	if err := validateCoupon(c); err != nil {
+		return nil, err
+	}
+	if err := s.storage.coupon.UpdateOne(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DeleteCoupon removes a coupon. Orders that used it keep their discount and code.
+func (s *Service) DeleteCoupon(ctx context.Context, actor *account.User, biz *business.Business, couponID string) error {
+	c, err := s.GetCouponByID(ctx, actor, biz, couponID)
+	if err != nil {
+		return err
+	}
+	return s.storage.coupon.DeleteOne(ctx, c)
+}
+
+func validateCoupon(c *Coupon) error {
+	if c.DiscountType == DiscountTypePercent && c.DiscountValue.GreaterThan(decimal.NewFromInt(100)) {
+		return ErrInvalidCoupon("discountValue", "percent discounts cannot exceed 100")
+	}
+	if c.StartsAt.Valid && c.ExpiresAt.Valid && !c.ExpiresAt.Time.After(c.StartsAt.Time) {
+		return ErrInvalidCoupon("expiresAt", "must be after startsAt")
+	}
+	return nil
+}
+
+// applyCoupon resolves a coupon code for an order of customerID and returns the coupon with
+// the discount it gives on subtotal. Orders being created lock the coupon row, so concurrent
+// orders cannot exceed its usage limits.
+func (s *Service) applyCoupon(ctx context.Context, biz *business.Business, code, customerID string, subtotal decimal.Decimal, lock bool) (*Coupon, decimal.Decimal, error) {
+	code = normalizeCouponCode(code)
+	opts := []func(*gorm.DB) *gorm.DB{
+		s.storage.coupon.ScopeBusinessID(biz.ID),
+		s.storage.coupon.ScopeEquals(CouponSchema.Code, code),
+	}
+	if lock {
+		opts = append(opts, s.storage.coupon.WithLockingStrength(database.LockingStrengthUpdate))
+	}
+	c, err := s.storage.coupon.FindOne(ctx, opts...)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, decimal.Zero, ErrCouponNotApplicable(code, "not_found")
+		}
+		return nil, decimal.Zero, err
+	}
+	switch {
+	case !c.IsValidAt(time.Now()):
+		return nil, decimal.Zero, ErrCouponNotApplicable(code, "inactive_or_expired")
+	case c.CustomerID.Valid && c.CustomerID.String != customerID:
+		return nil, decimal.Zero, ErrCouponNotApplicable(code, "customer_not_eligible")
+	case c.MaxUses > 0 && c.UsedCount >= c.MaxUses:
+		return nil, decimal.Zero, ErrCouponNotApplicable(code, "usage_limit_reached")
+	case subtotal.LessThan(c.MinSubtotal):
+		return nil, decimal.Zero, ErrCouponNotApplicable(code, "min_subtotal_not_met")
+	}
+	if c.MaxUsesPerCustomer > 0 {
+		used, err := s.storage.couponRedemption.Count(ctx,
+			s.storage.couponRedemption.ScopeEquals(CouponRedemptionSchema.CouponID, c.ID),
+			s.storage.couponRedemption.ScopeEquals(CouponRedemptionSchema.CustomerID, customerID),
+		)
+		if err != nil {
+			return nil, decimal.Zero, err
+		}
+		if used >= int64(c.MaxUsesPerCustomer) {
+			return nil, decimal.Zero, ErrCouponNotApplicable(code, "customer_usage_limit_reached")
+		}
+	}
+	return c, c.Discount(subtotal, biz.Currency), nil
+}
+
+// redeemCoupon records the coupon as used by the order. Must run in the order's transaction,
+// after applyCoupon locked the coupon.
+func (s *Service) redeemCoupon(ctx context.Context, c *Coupon, ord *Order) error {
+	if err := s.storage.couponRedemption.CreateOne(ctx, &CouponRedemption{
+		BusinessID: ord.BusinessID,
+		CouponID:   c.ID,
+		CustomerID: ord.CustomerID,
+		OrderID:    ord.ID,
+		Discount:   ord.Discount,
+	}); err != nil {
+		return err
+	}
+	c.UsedCount++
+	return s.storage.coupon.UpdateOne(ctx, c)
+}
+
+// releaseCoupon gives back the coupon use of a deleted order.
+func (s *Service) releaseCoupon(ctx context.Context, ord *Order) error {
+	if !ord.CouponID.Valid {
+		return nil
+	}
+	redemption, err := s.storage.couponRedemption.FindOne(ctx,
+		s.storage.couponRedemption.ScopeEquals(CouponRedemptionSchema.OrderID, ord.ID),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if err := s.storage.couponRedemption.DeleteOne(ctx, redemption); err != nil {
+		return err
+	}
+	c, err := s.storage.coupon.FindOne(ctx,
+		s.storage.coupon.ScopeID(redemption.CouponID),
+		s.storage.coupon.WithLockingStrength(database.LockingStrengthUpdate),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			// deleted coupons have no usage to give back
+			return nil
+		}
+		return err
+	}
+	if c.UsedCount > 0 {
+		c.UsedCount--
+	}
+	return s.storage.coupon.UpdateOne(ctx, c)
+}
+
+// resolveDiscount computes the order discount from the request's coupon code, or from its
+// manual discount fields when no coupon is given. The two cannot be combined.
+func (s *Service) resolveDiscount(ctx context.Context, biz *business.Business, req *CreateOrderRequest, subtotal decimal.Decimal, lock bool) (decimal.Decimal, *Coupon, error) {
+	if strings.TrimSpace(req.CouponCode) == "" {
+		return s.computeDiscountAmount(subtotal, biz.Currency, req), nil, nil
+	}
+	if req.hasManualDiscount() {
+		return decimal.Zero, nil, ErrCouponWithManualDiscount()
+	}
+	c, discount, err := s.applyCoupon(ctx, biz, req.CouponCode, req.CustomerID, subtotal, lock)
+	if err != nil {
+		return decimal.Zero, nil, err
+	}
+	return discount, c, nil
+}
+
+// hasManualDiscount reports whether the order request sets a discount of its own.
+func (req *CreateOrderRequest) hasManualDiscount() bool {
+	return req.Discount.IsPositive() || (req.DiscountType != "" && !req.DiscountValue.IsZero())
+}
+
+// applyCouponSnapshot records the coupon and the discount it gave on the order, so later
+// coupon edits do not change the order.
+func applyCouponSnapshot(ord *Order, c *Coupon) {
+	ord.CouponID = transformer.ToNullString(c.ID)
+	ord.CouponCode = transformer.ToNullString(c.Code)
+	ord.DiscountType = c.DiscountType
+	ord.DiscountValue = c.DiscountValue
+}
+
+func nullTimeFromPtr(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
@@ -0,0 +1,83 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
+)
+
+// favoriteProductsLimit is the number of favorite products in customer stats.
+const favoriteProductsLimit = 5
+
+// customerStatsExcludedStatuses are the statuses of orders that do not count towards customer
+// stats, the same ones the customer list leaves out of ordersCount and totalSpent.
+var customerStatsExcludedStatuses = []any{OrderStatusCancelled, OrderStatusReturned}
+
+// CustomerStats is a customer's purchase history in figures. Cancelled and returned orders
+// are left out.
+type CustomerStats struct {
+	Customer          *customer.Customer
+	Currency          string
+	OrdersCount       int
+	LifetimeValue     decimal.Decimal
+	AverageOrderValue decimal.Decimal
+	FirstOrderAt      *time.Time
+	LastOrderAt       *time.Time
+	// PreferredChannel is the channel the customer ordered through most; ties go to the
+	// channel of the latest order. Empty when the customer has no orders.
+	PreferredChannel string
+	FavoriteProducts []CustomerProductPurchase
+}
+
+// GetCustomerStats computes the lifetime value, order count, average order value, first and
+// last order dates, preferred channel and favorite products of a customer.
+func (s *Service) GetCustomerStats(ctx context.Context, actor *account.User, biz *business.Business, customerID string) (*CustomerStats, error) {
+	cust, err := s.customer.GetCustomerByID(ctx, actor, biz, customerID)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := s.storage.order.FindMany(ctx,
+		s.storage.order.ScopeBusinessID(biz.ID),
+		s.storage.order.ScopeEquals(OrderSchema.CustomerID, cust.ID),
+		s.storage.order.ScopeNotIn(OrderSchema.Status, customerStatsExcludedStatuses),
+		s.storage.order.WithOrderBy([]string{OrderSchema.OrderedAt.Column()}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &CustomerStats{
+		Customer:          cust,
+		Currency:          biz.Currency,
+		OrdersCount:       len(orders),
+		LifetimeValue:     decimal.Zero,
+		AverageOrderValue: decimal.Zero,
+	}
+	channels := make(map[string]int)
+	for _, o := range orders {
+		stats.LifetimeValue = stats.LifetimeValue.Add(o.Total)
+		if o.Channel != "" {
+			channels[o.Channel]++
+			// orders are oldest first, so ties keep the latest channel
+			if channels[o.Channel] >= channels[stats.PreferredChannel] {
+				stats.PreferredChannel = o.Channel
+			}
+		}
+	}
+	if len(orders) > 0 {
+		first, last := orders[0].OrderedAt, orders[len(orders)-1].OrderedAt
+		stats.FirstOrderAt, stats.LastOrderAt = &first, &last
+		stats.AverageOrderValue = money.Round(stats.LifetimeValue.Div(decimal.NewFromInt(int64(len(orders)))), biz.Currency)
+	}
+
+	stats.FavoriteProducts, err = s.storage.TopCustomerProducts(ctx, biz.ID, cust.ID, customerStatsExcludedStatuses, favoriteProductsLimit)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,33 @@
+package order
+
+import (
+	"context"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+)
+
+// CustomerTimelineEntryType is the kind of activity a customer timeline entry records.
+type CustomerTimelineEntryType string
+
+const (
+	CustomerTimelineOrderPlaced        CustomerTimelineEntryType = "order_placed"
+	CustomerTimelineOrderStatusChanged CustomerTimelineEntryType = "order_status_changed"
+	CustomerTimelinePaymentRecorded    CustomerTimelineEntryType = "payment_recorded"
+	CustomerTimelineNoteAdded          CustomerTimelineEntryType = "note_added"
+	CustomerTimelineAddressAdded       CustomerTimelineEntryType = "address_added"
+	CustomerTimelineAddressUpdated     CustomerTimelineEntryType = "address_updated"
+	CustomerTimelineAddressRemoved     CustomerTimelineEntryType = "address_removed"
+)
+
+// ListCustomerTimeline returns a page of a customer's activity, newest first: orders placed,
+// order status changes, payments, customer notes and address changes, merged into one feed.
+// Deleted orders and notes are left out.
+func (s *Service) ListCustomerTimeline(ctx context.Context, actor *account.User, biz *business.Business, customerID string, req *list.ListRequest) ([]CustomerTimelineRow, int64, error) {
+	cust, err := s.customer.GetCustomerByID(ctx, actor, biz, customerID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.storage.ListCustomerTimeline(ctx, biz.ID, cust.ID, req.Offset(), req.Limit())
+}
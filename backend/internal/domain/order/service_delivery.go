@@ -0,0 +1,225 @@
+package order
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/atomic"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+)
+
+// deliveryClosedStatuses are the statuses of orders that no longer hold a delivery slot.
+var deliveryClosedStatuses = []any{OrderStatusCancelled, OrderStatusReturned}
+
+// ScheduleOrderDelivery books the order on a delivery window for a date. The window must
+// be offered on that weekday, the date must not be in the past (in the business timezone)
+// and the window must have capacity left on the date. The window times are copied to the
+// order so later changes to the window do not move booked deliveries.
+func (s *Service) ScheduleOrderDelivery(ctx context.Context, actor *account.User, biz *business.Business, orderID string, req *ScheduleOrderDeliveryRequest) (*Order, error) {
+	window, err := s.business.GetDeliveryWindowForOrders(ctx, biz, strings.TrimSpace(req.WindowID))
+	if err != nil {
+		return nil, err
+	}
+	day := calendarDate(req.Date.Time)
+	dayLabel := day.Format(deliveryDateLayout)
+	if day.Before(calendarDate(time.Now().In(biz.Location()))) {
+		return nil, ErrInvalidDeliveryDate(dayLabel, "in the past")
+	}
+	if !window.Covers(day) {
+		return nil, ErrInvalidDeliveryDate(dayLabel, "the window is offered on "+time.Weekday(window.Weekday).String()+"s")
+	}
+
+	var updated *Order
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		ord, err := s.storage.order.FindByID(tctx, orderID,
+			s.storage.order.ScopeBusinessID(biz.ID),
+			s.storage.order.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return ErrOrderNotFound(orderID, err)
+		}
+		if !ord.canScheduleDelivery() {
+			return ErrOrderDeliveryNotSchedulable(ord.ID, ord.Status)
+		}
+		if ord.DeliveryWindowID.String == window.ID && ord.hasDeliverySlot() && ord.DeliveryDate.Time.Equal(day) {
+			updated = ord
+			return nil
+		}
+		booked, err := s.storage.order.Count(tctx,
+			s.storage.order.ScopeBusinessID(biz.ID),
+			s.storage.order.ScopeEquals(OrderSchema.DeliveryWindowID, window.ID),
+			s.storage.order.ScopeEquals(OrderSchema.DeliveryDate, day),
+			s.storage.order.ScopeNotIn(OrderSchema.Status, deliveryClosedStatuses),
+			s.storage.order.ScopeWhere("orders.id <> ?", ord.ID),
+		)
+		if err != nil {
+			return err
+		}
+		if booked >= int64(window.Capacity) {
+			return ErrDeliverySlotFull(window.ID, dayLabel, window.Capacity)
+		}
+
+		from := ord.deliverySlotLabel()
+		ord.DeliveryWindowID = transformer.ToNullString(window.ID)
+		ord.DeliveryDate = transformer.ToNullTime(day)
+		ord.DeliveryStartTime = transformer.ToNullString(window.StartTime)
+		ord.DeliveryEndTime = transformer.ToNullString(window.EndTime)
+		if err := s.storage.order.UpdateOne(tctx, ord); err != nil {
+			if database.IsVersionConflict(err) {
+				return ErrOrderVersionConflict(ord.ID, err)
+			}
+			return err
+		}
+		updated = ord
+		return s.recordOrderEvent(tctx, actor, ord, OrderEventDeliveryScheduled,
+			OrderFieldChange{Field: "deliverySlot", From: from, To: ord.deliverySlotLabel()},
+		)
+	}, atomic.WithIsolationLevel(atomic.LevelSerializable), atomic.WithRetries(3))
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// ClearOrderDelivery takes the order off its delivery slot, freeing its seat.
+func (s *Service) ClearOrderDelivery(ctx context.Context, actor *account.User, biz *business.Business, orderID string) (*Order, error) {
+	var updated *Order
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		ord, err := s.storage.order.FindByID(tctx, orderID,
+			s.storage.order.ScopeBusinessID(biz.ID),
+			s.storage.order.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return ErrOrderNotFound(orderID, err)
+		}
+		updated = ord
+		if !ord.hasDeliverySlot() {
+			return nil
+		}
+		if !ord.canScheduleDelivery() {
+			return ErrOrderDeliveryNotSchedulable(ord.ID, ord.Status)
+		}
+		from := ord.deliverySlotLabel()
+		ord.DeliveryWindowID = transformer.ToNullString("")
+		ord.DeliveryDate = transformer.ToNullTime(time.Time{})
+		ord.DeliveryStartTime = transformer.ToNullString("")
+		ord.DeliveryEndTime = transformer.ToNullString("")
+		if err := s.storage.order.UpdateOne(tctx, ord); err != nil {
+			if database.IsVersionConflict(err) {
+				return ErrOrderVersionConflict(ord.ID, err)
+			}
+			return err
+		}
+		return s.recordOrderEvent(tctx, actor, ord, OrderEventDeliveryScheduled,
+			OrderFieldChange{Field: "deliverySlot", From: from},
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// ListDeliverySlots returns, for every date from from to to (inclusive), the delivery
+// windows offered that day with their booked and remaining capacity.
+func (s *Service) ListDeliverySlots(ctx context.Context, actor *account.User, biz *business.Business, from, to time.Time) ([]DeliverySlotAvailability, error) {
+	from, to = calendarDate(from), calendarDate(to)
+	if to.Before(from) || to.Sub(from) >= maxDeliverySlotsRangeDays*24*time.Hour {
+		return nil, ErrInvalidDeliverySlotsRange(maxDeliverySlotsRangeDays)
+	}
+	windows, err := s.business.ListDeliveryWindowsForOrders(ctx, biz)
+	if err != nil {
+		return nil, err
+	}
+	booked, err := s.storage.order.FindMany(ctx,
+		s.storage.order.ScopeBusinessID(biz.ID),
+		s.storage.order.ScopeBetween(OrderSchema.DeliveryDate, from, to),
+		s.storage.order.ScopeNotIn(OrderSchema.Status, deliveryClosedStatuses),
+		s.storage.order.WithSelect("orders.id", "orders.delivery_window_id", "orders.delivery_date"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(booked))
+	for _, o := range booked {
+		counts[o.DeliveryWindowID.String+"|"+o.DeliveryDate.Time.Format(deliveryDateLayout)]++
+	}
+
+	slots := []DeliverySlotAvailability{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		label := day.Format(deliveryDateLayout)
+		for _, w := range windows {
+			if !w.Covers(day) {
+				continue
+			}
+			n := counts[w.ID+"|"+label]
+			slots = append(slots, DeliverySlotAvailability{
+				Date:      label,
+				WindowID:  w.ID,
+				StartTime: w.StartTime,
+				EndTime:   w.EndTime,
+				Capacity:  w.Capacity,
+				Booked:    n,
+				Remaining: max(w.Capacity-n, 0),
+			})
+		}
+	}
+	return slots, nil
+}
+
+// GetDeliveryManifest lists the open orders to deliver on a date, grouped by slot in time
+// order, for planning the day's route.
+func (s *Service) GetDeliveryManifest(ctx context.Context, actor *account.User, biz *business.Business, day time.Time) (*DeliveryManifest, error) {
+	day = calendarDate(day)
+	orders, err := s.storage.order.FindMany(ctx,
+		s.storage.order.ScopeBusinessID(biz.ID),
+		s.storage.order.ScopeEquals(OrderSchema.DeliveryDate, day),
+		s.storage.order.ScopeNotIn(OrderSchema.Status, deliveryClosedStatuses),
+		s.storage.order.WithPreload(customer.CustomerStruct),
+		s.storage.order.WithPreload(ShippingAddressStruct),
+		s.storage.order.WithPreload(OrderItemStruct),
+		s.storage.order.WithPreload(ItemsProductStruct),
+		s.storage.order.WithPreload(ItemsVariantStruct),
+		s.storage.order.WithOrderBy([]string{"orders.delivery_start_time ASC", "orders.order_number ASC"}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := s.business.ListDeliveryWindowsForOrders(ctx, biz)
+	if err != nil {
+		return nil, err
+	}
+	capacities := make(map[string]int, len(windows))
+	for _, w := range windows {
+		capacities[w.ID] = w.Capacity
+	}
+
+	manifest := &DeliveryManifest{Date: day.Format(deliveryDateLayout), Orders: len(orders), Slots: []DeliveryManifestSlot{}}
+	index := map[string]int{}
+	for _, ord := range orders {
+		key := ord.DeliveryWindowID.String + "|" + ord.DeliveryStartTime.String + "|" + ord.DeliveryEndTime.String
+		i, ok := index[key]
+		if !ok {
+			i = len(manifest.Slots)
+			index[key] = i
+			manifest.Slots = append(manifest.Slots, DeliveryManifestSlot{
+				WindowID:  ord.DeliveryWindowID.String,
+				StartTime: ord.DeliveryStartTime.String,
+				EndTime:   ord.DeliveryEndTime.String,
+				Capacity:  capacities[ord.DeliveryWindowID.String],
+				Orders:    []DeliveryManifestOrder{},
+			})
+		}
+		manifest.Slots[i].Orders = append(manifest.Slots[i].Orders, toDeliveryManifestOrder(ord))
+	}
+	sort.SliceStable(manifest.Slots, func(a, b int) bool {
+		return manifest.Slots[a].StartTime < manifest.Slots[b].StartTime
+	})
+	return manifest, nil
+}
@@ -0,0 +1,80 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/email"
+)
+
+// SetEmailClient wires the email client used to deliver the files of digital products once
+// their order is paid. Without it, the files are only available from the order's downloads.
+func (s *Service) SetEmailClient(client email.Client) {
+	s.notification = NewNotification(client, email.NewEmail())
+}
+
+// GetOrderDownloads returns the items of the order that deliver a file. Files are only handed
+// out once the order is paid.
+func (s *Service) GetOrderDownloads(ctx context.Context, actor *account.User, biz *business.Business, orderID string) ([]*OrderItem, error) {
+	ord, err := s.GetOrderByID(ctx, actor, biz, orderID)
+	if err != nil {
+		return nil, ErrOrderNotFound(orderID, err)
+	}
+	if ord.PaymentStatus != OrderPaymentStatusPaid {
+		return nil, ErrOrderDownloadsUnavailable(ord.ID, ord.PaymentStatus)
+	}
+	return downloadItems(ord.Items), nil
+}
+
+// DeliverOrderDownloads emails the customer of a paid order the files of its digital items,
+// once per order. Orders without files or a customer email are skipped.
+func (s *Service) DeliverOrderDownloads(ctx context.Context, businessID, orderID string) error {
+	if s.notification == nil {
+		return nil
+	}
+	biz, err := s.business.GetBusinessByIDForJobs(ctx, businessID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	ord, err := s.GetOrderByID(ctx, nil, biz, orderID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if ord.PaymentStatus != OrderPaymentStatusPaid || ord.DownloadsSentAt.Valid || len(downloadItems(ord.Items)) == 0 {
+		return nil
+	}
+	if ord.Customer == nil || !ord.Customer.Email.Valid || ord.Customer.Email.String == "" {
+		return nil
+	}
+	if err := s.notification.SendDigitalDeliveryEmail(ctx, biz, ord); err != nil {
+		return err
+	}
+	// reload without associations so saving the timestamp leaves the items untouched
+	sent, err := s.storage.order.FindByID(ctx, ord.ID, s.storage.order.ScopeBusinessID(biz.ID))
+	if err != nil {
+		return err
+	}
+	sent.DownloadsSentAt = sql.NullTime{Time: time.Now(), Valid: true}
+	return s.storage.order.UpdateOne(ctx, sent)
+}
+
+// downloadItems returns the items that deliver a file.
+func downloadItems(items []*OrderItem) []*OrderItem {
+	out := make([]*OrderItem, 0, len(items))
+	for _, it := range items {
+		if it.Download != nil {
+			out = append(out, it)
+		}
+	}
+	return out
+}
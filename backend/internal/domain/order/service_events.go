@@ -0,0 +1,172 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/platform/bus"
+)
+
+// eventTime returns the recorded transition time, or now when it was not stamped.
+func eventTime(t sql.NullTime) time.Time {
+	if t.Valid {
+		return t.Time.UTC()
+	}
+	return time.Now().UTC()
+}
+
+// emitPaidEvent publishes order.paid for an order that has just become paid.
+func (s *Service) emitPaidEvent(ctx context.Context, ord *Order) {
+	if s.bus == nil {
+		return
+	}
+	// Detach from the request lifecycle so async handlers can complete.
+	// This preserves context values (e.g. trace ID) but prevents cancellation
+	// when the HTTP request finishes.
+	s.bus.Emit(bus.OrderPaidTopic, &bus.OrderPaidEvent{
+		Ctx:           context.WithoutCancel(ctx),
+		BusinessID:    ord.BusinessID,
+		OrderID:       ord.ID,
+		OrderNumber:   ord.OrderNumber,
+		PaymentMethod: string(ord.PaymentMethod),
+		OrderTotal:    ord.Total,
+		Currency:      ord.Currency,
+		PaidAt:        eventTime(ord.PaidAt),
+	})
+}
+
+// emitStatusEvent publishes order.fulfilled or order.cancelled after the matching status
+// transition has been persisted. Other statuses have no event.
+func (s *Service) emitStatusEvent(ctx context.Context, ord *Order) {
+	if s.bus == nil {
+		return
+	}
+	switch ord.Status {
+	case OrderStatusFulfilled:
+		s.bus.Emit(bus.OrderFulfilledTopic, &bus.OrderFulfilledEvent{
+			Ctx:           context.WithoutCancel(ctx),
+			BusinessID:    ord.BusinessID,
+			OrderID:       ord.ID,
+			OrderNumber:   ord.OrderNumber,
+			PaymentStatus: string(ord.PaymentStatus),
+			OrderTotal:    ord.Total,
+			VAT:           ord.VAT,
+			COGS:          ord.COGS,
+			Currency:      ord.Currency,
+			FulfilledAt:   eventTime(ord.FulfilledAt),
+		})
+	case OrderStatusCancelled:
+		s.bus.Emit(bus.OrderCancelledTopic, &bus.OrderCancelledEvent{
+			Ctx:           context.WithoutCancel(ctx),
+			BusinessID:    ord.BusinessID,
+			OrderID:       ord.ID,
+			PaymentStatus: string(ord.PaymentStatus),
+			OrderTotal:    ord.Total,
+			Currency:      ord.Currency,
+			CancelledAt:   eventTime(ord.CancelledAt),
+		})
+	}
+}
+
+// emitImportedEvent publishes order.imported for a historical order that has just been
+// imported, with the fulfillment and payment it recorded.
+func (s *Service) emitImportedEvent(ctx context.Context, ord *Order) {
+	if s.bus == nil {
+		return
+	}
+	ev := &bus.OrderImportedEvent{
+		Ctx:         context.WithoutCancel(ctx),
+		BusinessID:  ord.BusinessID,
+		OrderID:     ord.ID,
+		OrderNumber: ord.OrderNumber,
+		OrderTotal:  ord.Total,
+		VAT:         ord.VAT,
+		COGS:        ord.COGS,
+		Currency:    ord.Currency,
+	}
+	if ord.Status == OrderStatusFulfilled || ord.FulfilledAt.Valid {
+		at := postedAt(ord.FulfilledAt, ord.OrderedAt)
+		ev.FulfilledAt = &at
+	}
+	if ord.PaymentStatus == OrderPaymentStatusPaid || ord.PaymentStatus == OrderPaymentStatusRefunded || ord.PaidAt.Valid {
+		at := postedAt(ord.PaidAt, ord.OrderedAt)
+		ev.PaidAt = &at
+	}
+	s.bus.Emit(bus.OrderImportedTopic, ev)
+}
+
+// postedAt returns the recorded transition time, or fallback when it was not stamped.
+func postedAt(t sql.NullTime, fallback time.Time) time.Time {
+	if t.Valid {
+		return t.Time.UTC()
+	}
+	return fallback.UTC()
+}
+
+// emitReturnedEvent publishes order.returned for an order that has just been returned.
+func (s *Service) emitReturnedEvent(ctx context.Context, ord *Order, restocked bool) {
+	if s.bus == nil {
+		return
+	}
+	s.bus.Emit(bus.OrderReturnedTopic, &bus.OrderReturnedEvent{
+		Ctx:           context.WithoutCancel(ctx),
+		BusinessID:    ord.BusinessID,
+		OrderID:       ord.ID,
+		PaymentStatus: string(ord.PaymentStatus),
+		OrderTotal:    ord.Total,
+		COGS:          ord.COGS,
+		Currency:      ord.Currency,
+		Restocked:     restocked,
+		ReturnedAt:    eventTime(ord.ReturnedAt),
+	})
+}
+
+// emitRefundedEvent publishes order.refunded for an order whose payment has just been refunded.
+func (s *Service) emitRefundedEvent(ctx context.Context, ord *Order) {
+	if s.bus == nil {
+		return
+	}
+	s.bus.Emit(bus.OrderRefundedTopic, &bus.OrderRefundedEvent{
+		Ctx:           context.WithoutCancel(ctx),
+		BusinessID:    ord.BusinessID,
+		OrderID:       ord.ID,
+		Status:        string(ord.Status),
+		PaymentMethod: string(ord.PaymentMethod),
+		OrderTotal:    ord.Total,
+		COGS:          ord.COGS,
+		Currency:      ord.Currency,
+		RefundedAt:    eventTime(ord.RefundedAt),
+	})
+}
+
+// emitStatusChangedEvent publishes order.status_changed for a status change, with the
+// transition rules that ran. actor is nil for changes made by jobs (expiry).
+func (s *Service) emitStatusChangedEvent(ctx context.Context, actor *account.User, ord *Order, out *transitionOutcome) {
+	if s.bus == nil {
+		return
+	}
+	ev := &bus.OrderStatusChangedEvent{
+		Ctx:                    context.WithoutCancel(ctx),
+		BusinessID:             ord.BusinessID,
+		OrderID:                ord.ID,
+		OrderNumber:            ord.OrderNumber,
+		CustomerID:             ord.CustomerID,
+		CustomerWhatsappNumber: out.customerPhone,
+		FromStatus:             string(out.from),
+		ToStatus:               string(ord.Status),
+		PaymentStatus:          string(ord.PaymentStatus),
+		PaymentMethod:          string(ord.PaymentMethod),
+		OrderTotal:             ord.Total,
+		AmountPaid:             ord.AmountPaid,
+		Currency:               ord.Currency,
+		AppliedActions:         out.actions,
+		CustomerMessage:        out.customerMessage,
+		ChangedAt:              time.Now().UTC(),
+	}
+	if actor != nil {
+		ev.ActorID = actor.ID
+	}
+	s.bus.Emit(bus.OrderStatusChangedTopic, ev)
+}
@@ -0,0 +1,161 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/types/atomic"
+)
+
+const expiryBatchSize = 200
+
+// ExpirePendingOrdersOptions controls which businesses are checked for stale pending orders.
+type ExpirePendingOrdersOptions struct {
+	// BusinessID limits the run to one business; empty checks every active business.
+	BusinessID string
+	// Now is the reference time of the run; zero means time.Now().
+	Now time.Time
+}
+
+// ExpirePendingOrdersResult summarizes an expiry run.
+type ExpirePendingOrdersResult struct {
+	Businesses int `json:"businesses"`
+	Expired    int `json:"expired"`
+	Failed     int `json:"failed"`
+}
+
+// ExpirePendingOrders cancels the orders that stayed pending longer than their business'
+// PendingOrderTTLHours and puts their items back in stock, so abandoned orders stop holding
+// inventory. Businesses without a TTL are skipped. An order that fails to expire is logged
+// and retried on the next run.
+func (s *Service) ExpirePendingOrders(ctx context.Context, opts ExpirePendingOrdersOptions) (*ExpirePendingOrdersResult, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	var businesses []*business.Business
+	if opts.BusinessID != "" {
+		biz, err := s.business.GetBusinessByIDForJobs(ctx, opts.BusinessID)
+		if err != nil {
+			return nil, err
+		}
+		businesses = []*business.Business{biz}
+	} else {
+		var err error
+		businesses, err = s.business.ListActiveBusinessesForJobs(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &ExpirePendingOrdersResult{}
+	for _, biz := range businesses {
+		if biz.PendingOrderTTLHours <= 0 {
+			continue
+		}
+		result.Businesses++
+		cutoff := now.Add(-time.Duration(biz.PendingOrderTTLHours) * time.Hour)
+		if err := s.expireBusinessPendingOrders(ctx, biz, cutoff, now, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// expireBusinessPendingOrders expires the pending orders of biz created before cutoff.
+func (s *Service) expireBusinessPendingOrders(ctx context.Context, biz *business.Business, cutoff, now time.Time, result *ExpirePendingOrdersResult) error {
+	lastID := ""
+	for {
+		batch, err := s.storage.order.FindMany(ctx,
+			s.storage.order.ScopeBusinessID(biz.ID),
+			s.storage.order.ScopeEquals(OrderSchema.Status, OrderStatusPending),
+			s.storage.order.ScopeWhere("orders.created_at < ?", cutoff),
+			s.storage.order.ScopeWhere("orders.id > ?", lastID),
+			s.storage.order.WithOrderBy([]string{"orders.id ASC"}),
+			s.storage.order.WithLimit(expiryBatchSize),
+		)
+		if err != nil {
+			return err
+		}
+		for _, ord := range batch {
+			expired, err := s.expirePendingOrder(ctx, biz, ord.ID, cutoff, now)
+			if err != nil {
+				logger.FromContext(ctx).Error("failed to expire pending order", "error", err, "businessId", biz.ID, "orderId", ord.ID)
+				result.Failed++
+				continue
+			}
+			if expired != nil {
+				result.Expired++
+				// Expiry is a cancellation: consumers of order.cancelled (tasks, ledger) and
+				// order.status_changed must see it like any other.
+				s.emitStatusEvent(ctx, expired)
+				s.emitStatusChangedEvent(ctx, nil, expired, &transitionOutcome{from: OrderStatusPending, prevPayment: expired.PaymentStatus})
+			}
+		}
+		if len(batch) < expiryBatchSize {
+			return nil
+		}
+		lastID = batch[len(batch)-1].ID
+	}
+}
+
+// expirePendingOrder cancels one stale pending order under a row lock and restocks its items.
+// It returns nil when the order was meanwhile placed, cancelled or deleted.
+func (s *Service) expirePendingOrder(ctx context.Context, biz *business.Business, orderID string, cutoff, now time.Time) (*Order, error) {
+	var expired *Order
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		expired = nil
+		ord, err := s.storage.order.FindByID(tctx, orderID,
+			s.storage.order.ScopeBusinessID(biz.ID),
+			s.storage.order.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			if database.IsRecordNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		if ord.Status != OrderStatusPending || !ord.CreatedAt.Before(cutoff) {
+			return nil
+		}
+		if err := newOrderStateMachine(ord).transitionStateTo(OrderStatusCancelled); err != nil {
+			return err
+		}
+		ord.ExpiredAt = sql.NullTime{Time: now.UTC(), Valid: true}
+
+		items, err := s.storage.orderItem.FindMany(tctx,
+			s.storage.orderItem.ScopeEquals(OrderItemSchema.OrderID, ord.ID),
+			s.storage.orderItem.WithPreload(inventory.VariantStruct),
+		)
+		if err != nil {
+			return err
+		}
+		adjustments := make([]itemVariant, 0, len(items))
+		for _, oi := range items {
+			// Variants deleted since the order was created have no stock to give back.
+			if oi.Variant == nil {
+				continue
+			}
+			adjustments = append(adjustments, itemVariant{variant: oi.Variant, qty: oi.Quantity, item: oi})
+		}
+		if err := s.restockInventoryLevels(tctx, nil, biz, ord, adjustments); err != nil {
+			return err
+		}
+		if err := s.storage.order.UpdateOne(tctx, ord); err != nil {
+			return err
+		}
+		expired = ord
+		return s.recordOrderEvent(tctx, nil, ord, OrderEventExpired,
+			OrderFieldChange{Field: "status", From: string(OrderStatusPending), To: string(ord.Status)},
+		)
+	}, atomic.WithIsolationLevel(atomic.LevelSerializable), atomic.WithRetries(3))
+	if err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
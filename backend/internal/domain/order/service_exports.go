@@ -0,0 +1,340 @@
+package order
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/platform/bus"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/abdelrahman146/kyora/internal/platform/xlsx"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxExportRows bounds the orders of one export, which is built in memory.
+	maxExportRows   = 100_000
+	exportBatchSize = 500
+	// exportRetention is how long export files are kept.
+	exportRetention = 7 * 24 * time.Hour
+	// exportStaleAfter is how long an unfinished export blocks new ones; exports interrupted
+	// by a restart are never finished.
+	exportStaleAfter = time.Hour
+	// exportListLimit is the number of recent exports ListOrderExports returns.
+	exportListLimit = 20
+)
+
+// RequestOrderExport queues an export of the orders matching req and returns it pending.
+// The file is written in the background; one export per business runs at a time.
+func (s *Service) RequestOrderExport(ctx context.Context, actor *account.User, biz *business.Business, req *CreateOrderExportRequest) (*OrderExport, error) {
+	if req.From != nil && req.To != nil && req.To.Before(*req.From) {
+		return nil, ErrInvalidExportRange()
+	}
+	format := req.Format
+	if format == "" {
+		format = ExportFormatCSV
+	}
+	filters := ExportFilters{Statuses: req.Status, PaymentStatuses: req.PaymentStatus, From: req.From, To: req.To}
+
+	running, err := s.storage.orderExport.FindOne(ctx,
+		s.storage.orderExport.ScopeBusinessID(biz.ID),
+		s.storage.orderExport.ScopeIn(OrderExportSchema.Status, []any{ExportStatusPending, ExportStatusProcessing}),
+		s.storage.orderExport.ScopeGreaterThan(OrderExportSchema.CreatedAt, time.Now().Add(-exportStaleAfter)),
+	)
+	if err == nil {
+		return nil, ErrOrderExportInProgress(running.ID)
+	}
+	if !database.IsRecordNotFound(err) {
+		return nil, err
+	}
+	scopes, err := s.exportScopes(biz, filters)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.storage.order.Count(ctx, scopes...)
+	if err != nil {
+		return nil, err
+	}
+	if count > maxExportRows {
+		return nil, ErrOrderExportTooLarge(count, maxExportRows)
+	}
+	if err := s.purgeExpiredExports(ctx, biz); err != nil {
+		return nil, err
+	}
+
+	export := &OrderExport{
+		BusinessID:    biz.ID,
+		RequestedByID: actor.ID,
+		Format:        format,
+		Status:        ExportStatusPending,
+		Filters:       filters,
+	}
+	if err := s.storage.orderExport.CreateOne(ctx, export); err != nil {
+		return nil, err
+	}
+	if s.bus != nil {
+		s.bus.Emit(bus.OrderExportRequestedTopic, &bus.OrderExportRequestedEvent{
+			Ctx:        context.WithoutCancel(ctx),
+			BusinessID: biz.ID,
+			ExportID:   export.ID,
+		})
+	}
+	return export, nil
+}
+
+// ListOrderExports returns the business's most recent exports, newest first.
+func (s *Service) ListOrderExports(ctx context.Context, actor *account.User, biz *business.Business) ([]*OrderExport, error) {
+	return s.storage.orderExport.FindMany(ctx,
+		s.storage.orderExport.ScopeBusinessID(biz.ID),
+		s.storage.orderExport.WithOrderBy([]string{OrderExportSchema.CreatedAt.Column() + " DESC"}),
+		s.storage.orderExport.WithLimit(exportListLimit),
+	)
+}
+
+func (s *Service) GetOrderExport(ctx context.Context, actor *account.User, biz *business.Business, exportID string) (*OrderExport, error) {
+	export, err := s.storage.orderExport.FindOne(ctx,
+		s.storage.orderExport.ScopeBusinessID(biz.ID),
+		s.storage.orderExport.ScopeID(exportID),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrOrderExportNotFound(exportID, err)
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+// ReadOrderExportFile returns a completed export with its file content.
+func (s *Service) ReadOrderExportFile(ctx context.Context, actor *account.User, biz *business.Business, exportID string) (*OrderExport, []byte, error) {
+	export, err := s.GetOrderExport(ctx, actor, biz, exportID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if export.Status != ExportStatusCompleted {
+		return nil, nil, ErrOrderExportNotReady(export.ID, export.Status)
+	}
+	if export.ExpiresAt.Valid && time.Now().After(export.ExpiresAt.Time) {
+		return nil, nil, ErrOrderExportExpired(export.ID)
+	}
+	file, err := s.storage.orderExportFile.FindOne(ctx,
+		s.storage.orderExportFile.ScopeBusinessID(biz.ID),
+		s.storage.orderExportFile.ScopeEquals(OrderExportFileSchema.ExportID, export.ID),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, nil, ErrOrderExportExpired(export.ID)
+		}
+		return nil, nil, err
+	}
+	return export, file.Content, nil
+}
+
+// RunOrderExport writes the file of a pending export. Exports that already ran are skipped.
+// Failures are recorded on the export instead of being retried; the seller can request a
+// new export.
+func (s *Service) RunOrderExport(ctx context.Context, businessID, exportID string) error {
+	biz, err := s.business.GetBusinessByIDForJobs(ctx, businessID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	export, err := s.GetOrderExport(ctx, nil, biz, exportID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if export.Status != ExportStatusPending {
+		return nil
+	}
+	export.Status = ExportStatusProcessing
+	if err := s.storage.orderExport.UpdateOne(ctx, export); err != nil {
+		return err
+	}
+
+	content, rows, err := s.renderOrderExport(ctx, biz, export)
+	if err == nil {
+		err = s.storage.orderExportFile.CreateOne(ctx, &OrderExportFile{ExportID: export.ID, BusinessID: biz.ID, Content: content})
+	}
+	now := time.Now().UTC()
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to write order export", "error", err, "businessId", biz.ID, "exportId", export.ID)
+		export.Status = ExportStatusFailed
+		export.Error = "export failed; request a new export"
+	} else {
+		export.Status = ExportStatusCompleted
+		export.RowCount = rows
+		export.SizeBytes = int64(len(content))
+		export.CompletedAt = sql.NullTime{Time: now, Valid: true}
+		export.ExpiresAt = sql.NullTime{Time: now.Add(exportRetention), Valid: true}
+	}
+	return s.storage.orderExport.UpdateOne(ctx, export)
+}
+
+// purgeExpiredExports deletes the business's exports, and their files, older than the
+// retention period.
+func (s *Service) purgeExpiredExports(ctx context.Context, biz *business.Business) error {
+	expired, err := s.storage.orderExport.FindMany(ctx,
+		s.storage.orderExport.ScopeBusinessID(biz.ID),
+		s.storage.orderExport.ScopeLessThan(OrderExportSchema.CreatedAt, time.Now().Add(-exportRetention)),
+		s.storage.orderExport.WithSelect(OrderExportSchema.ID.Column()),
+	)
+	if err != nil || len(expired) == 0 {
+		return err
+	}
+	ids := make([]any, len(expired))
+	for i, e := range expired {
+		ids[i] = e.ID
+	}
+	if err := s.storage.orderExportFile.DeleteMany(ctx,
+		s.storage.orderExportFile.ScopeBusinessID(biz.ID),
+		s.storage.orderExportFile.ScopeIn(OrderExportFileSchema.ExportID, ids),
+	); err != nil {
+		return err
+	}
+	return s.storage.orderExport.DeleteMany(ctx,
+		s.storage.orderExport.ScopeBusinessID(biz.ID),
+		s.storage.orderExport.ScopeIn(OrderExportSchema.ID, ids),
+	)
+}
+
+func (s *Service) exportScopes(biz *business.Business, filters ExportFilters) ([]func(*gorm.DB) *gorm.DB, error) {
+	listFilters := &ListOrdersFilters{Statuses: filters.Statuses, PaymentStatuses: filters.PaymentStatuses}
+	if filters.From != nil {
+		listFilters.From = *filters.From
+	}
+	if filters.To != nil {
+		listFilters.To = *filters.To
+	}
+	scopes, _, err := s.orderListScopes(biz, list.NewListRequest(1, exportBatchSize, nil, ""), listFilters)
+	return scopes, err
+}
+
+// exportColumns are the header of export files.
+var exportColumns = []string{
+	"orderNumber", "orderedAt", "status", "paymentStatus", "paymentMethod", "paymentReference", "channel",
+	"customerName", "customerEmail", "customerPhone", "itemsQuantity",
+	"subtotal", "discount", "couponCode", "shippingFee", "vat", "total", "currency",
+}
+
+// exportNumericColumns are the columns written as numbers in XLSX files.
+var exportNumericColumns = map[int]bool{10: true, 11: true, 12: true, 14: true, 15: true, 16: true}
+
+// renderOrderExport writes the matching orders, oldest first, as the export's format and
+// returns the file with its row count.
+func (s *Service) renderOrderExport(ctx context.Context, biz *business.Business, export *OrderExport) ([]byte, int, error) {
+	var (
+		buf   bytes.Buffer
+		cw    *csv.Writer
+		sheet *xlsx.Sheet
+	)
+	write := func(row []string) {
+		if sheet == nil {
+			_ = cw.Write(row)
+			return
+		}
+		cells := make([]xlsx.Cell, len(row))
+		for i, v := range row {
+			if exportNumericColumns[i] && sheet.Rows() > 0 {
+				cells[i] = xlsx.Number(v)
+			} else {
+				cells[i] = xlsx.String(v)
+			}
+		}
+		sheet.AddRow(cells...)
+	}
+	if export.Format == ExportFormatXLSX {
+		sheet = xlsx.New("Orders")
+	} else {
+		cw = csv.NewWriter(&buf)
+	}
+	write(exportColumns)
+
+	loc := biz.Location()
+	scopes, err := s.exportScopes(biz, export.Filters)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows := 0
+	for offset := 0; ; offset += exportBatchSize {
+		orders, err := s.storage.order.FindMany(ctx, append(scopes,
+			s.storage.order.WithPreload(customer.CustomerStruct),
+			s.storage.order.WithPreload(OrderItemStruct),
+			s.storage.order.WithOrderBy([]string{"orders.ordered_at ASC", "orders.id ASC"}),
+			s.storage.order.WithPagination(offset, exportBatchSize),
+		)...)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, ord := range orders {
+			write(exportRow(ord, loc))
+			rows++
+		}
+		if len(orders) < exportBatchSize || rows >= maxExportRows {
+			break
+		}
+	}
+
+	if sheet != nil {
+		content, err := sheet.Bytes()
+		return content, rows, err
+	}
+	cw.Flush()
+	return buf.Bytes(), rows, cw.Error()
+}
+
+func exportRow(ord *Order, loc *time.Location) []string {
+	quantity := 0
+	for _, it := range ord.Items {
+		quantity += it.Quantity
+	}
+	var name, email, phone string
+	if c := ord.Customer; c != nil {
+		name = c.Name
+		email = c.Email.ValueOrDefault("")
+		phone = strings.TrimSpace(c.PhoneCode.ValueOrDefault("") + " " + c.PhoneNumber.ValueOrDefault(""))
+	}
+	return []string{
+		ord.OrderNumber,
+		ord.OrderedAt.In(loc).Format(time.RFC3339),
+		string(ord.Status),
+		string(ord.PaymentStatus),
+		string(ord.PaymentMethod),
+		exportText(ord.PaymentReference.String),
+		exportText(ord.Channel),
+		exportText(name),
+		exportText(email),
+		phone,
+		strconv.Itoa(quantity),
+		money.StringFixed(ord.Subtotal, ord.Currency),
+		money.StringFixed(ord.Discount, ord.Currency),
+		ord.CouponCode.String,
+		money.StringFixed(ord.ShippingFee, ord.Currency),
+		money.StringFixed(ord.VAT, ord.Currency),
+		money.StringFixed(ord.Total, ord.Currency),
+		ord.Currency,
+	}
+}
+
+// exportText neutralizes text that spreadsheets would run as a formula (customer names are
+// free text).
+func exportText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
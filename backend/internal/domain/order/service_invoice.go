@@ -0,0 +1,68 @@
+package order
+
+import (
+	"bytes"
+	"context"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+)
+
+// maxLogoPixels bounds the logo width and height embedded in documents, so an oversized
+// upload cannot blow up the rendered PDF.
+const maxLogoPixels = 2048
+
+// LogoStore reads uploaded assets of a business, such as its logo.
+type LogoStore interface {
+	ReadContent(ctx context.Context, biz *business.Business, assetID string) ([]byte, string, error)
+}
+
+// SetLogoStore wires the asset reader used to put the business logo on invoices. Without
+// it, invoices are rendered without a logo.
+func (s *Service) SetLogoStore(logos LogoStore) {
+	s.logos = logos
+}
+
+// RenderInvoice renders the invoice PDF of an order.
+func (s *Service) RenderInvoice(ctx context.Context, actor *account.User, biz *business.Business, orderID string) (*Order, []byte, error) {
+	ord, err := s.GetOrderByID(ctx, actor, biz, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ord, RenderInvoicePDF(biz, ord, s.businessLogo(ctx, biz)), nil
+}
+
+// businessLogo loads and decodes the uploaded logo of the business. Only logos uploaded as
+// assets of the business are used; a logo that cannot be read or decoded (e.g. SVG or
+// WebP) is left off rather than failing the document.
+func (s *Service) businessLogo(ctx context.Context, biz *business.Business) image.Image {
+	if s.logos == nil || biz.Logo == nil || biz.Logo.AssetID == nil || *biz.Logo.AssetID == "" {
+		return nil
+	}
+	log := logger.FromContext(ctx).With("businessId", biz.ID, "assetId", *biz.Logo.AssetID)
+	data, _, err := s.logos.ReadContent(ctx, biz, *biz.Logo.AssetID)
+	if err != nil {
+		log.Warn("failed to read business logo", "error", err)
+		return nil
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		log.Warn("failed to decode business logo", "error", err)
+		return nil
+	}
+	if cfg.Width > maxLogoPixels || cfg.Height > maxLogoPixels {
+		log.Warn("business logo too large to embed", "width", cfg.Width, "height", cfg.Height)
+		return nil
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		log.Warn("failed to decode business logo", "error", err)
+		return nil
+	}
+	return img
+}
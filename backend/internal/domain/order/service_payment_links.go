@@ -0,0 +1,209 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/region"
+	"github.com/abdelrahman146/kyora/internal/platform/types/atomic"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+	"github.com/shopspring/decimal"
+	stripelib "github.com/stripe/stripe-go/v83"
+	"github.com/stripe/stripe-go/v83/checkout/session"
+)
+
+// paymentLinkLifetime is how long a payment link stays payable; Stripe caps Checkout
+// sessions at 24 hours.
+const paymentLinkLifetime = 24 * time.Hour
+
+// canTakePaymentLink reports whether the customer can still be asked to pay the rest of
+// the order online.
+func (o *Order) canTakePaymentLink() bool {
+	switch o.Status {
+	case OrderStatusCancelled, OrderStatusReturned:
+		return false
+	}
+	if o.PaymentStatus != OrderPaymentStatusPending && o.PaymentStatus != OrderPaymentStatusFailed {
+		return false
+	}
+	return o.AmountDue().IsPositive()
+}
+
+// stripeUnitAmount converts an amount to the smallest currency unit Stripe expects.
+// Stripe takes three-decimal currencies in their minor unit but requires the last digit
+// to be zero, so those are rounded to the nearest ten.
+func stripeUnitAmount(amount decimal.Decimal, currency string) int64 {
+	minor := money.MinorUnits(currency)
+	units := money.Round(amount, currency).Shift(minor)
+	if minor == 3 {
+		ten := decimal.NewFromInt(10)
+		units = units.Div(ten).Round(0).Mul(ten)
+	}
+	return units.IntPart()
+}
+
+// CreateOrderPaymentLink creates a Stripe Checkout session charging the amount due and
+// stores its URL on the order. A previous link of the order is expired, so only the latest
+// one can be paid. Payment is confirmed by the checkout.session.completed webhook.
+func (s *Service) CreateOrderPaymentLink(ctx context.Context, actor *account.User, biz *business.Business, orderID string, req *CreateOrderPaymentLinkRequest) (*Order, error) {
+	ord, err := s.GetOrderByID(ctx, actor, biz, orderID)
+	if err != nil {
+		return nil, ErrOrderNotFound(orderID, err)
+	}
+	if !ord.canTakePaymentLink() {
+		return nil, ErrOrderPaymentLinkNotAllowed(ord.ID, ord.Status, ord.PaymentStatus)
+	}
+
+	if ord.CheckoutSessionID.Valid && ord.CheckoutExpiresAt.Valid && ord.CheckoutExpiresAt.Time.After(time.Now()) {
+		if _, err := session.Expire(ord.CheckoutSessionID.String, nil); err != nil {
+			logger.FromContext(ctx).Warn("failed to expire previous order payment link", "error", err, "orderId", ord.ID, "checkoutSessionId", ord.CheckoutSessionID.String)
+		}
+	}
+
+	// The webhook has no workspace to route by, so the session carries the order's region.
+	metadata := map[string]string{"order_id": ord.ID, "business_id": biz.ID, "region": region.FromContext(ctx)}
+	params := &stripelib.CheckoutSessionParams{
+		Mode: stripelib.String(string(stripelib.CheckoutSessionModePayment)),
+		LineItems: []*stripelib.CheckoutSessionLineItemParams{{
+			PriceData: &stripelib.CheckoutSessionLineItemPriceDataParams{
+				Currency:   stripelib.String(strings.ToLower(ord.Currency)),
+				UnitAmount: stripelib.Int64(stripeUnitAmount(ord.AmountDue(), ord.Currency)),
+				ProductData: &stripelib.CheckoutSessionLineItemPriceDataProductDataParams{
+					Name:        stripelib.String("Order " + ord.OrderNumber),
+					Description: stripelib.String(biz.Name),
+				},
+			},
+			Quantity: stripelib.Int64(1),
+		}},
+		SuccessURL:        stripelib.String(req.SuccessURL),
+		ClientReferenceID: stripelib.String(ord.ID),
+		ExpiresAt:         stripelib.Int64(time.Now().Add(paymentLinkLifetime).Unix()),
+		Metadata:          metadata,
+		PaymentIntentData: &stripelib.CheckoutSessionPaymentIntentDataParams{Metadata: metadata},
+	}
+	if req.CancelURL != "" {
+		params.CancelURL = stripelib.String(req.CancelURL)
+	}
+	if ord.Customer != nil && ord.Customer.Email.Valid && ord.Customer.Email.String != "" {
+		params.CustomerEmail = stripelib.String(ord.Customer.Email.String)
+	}
+	cs, err := session.New(params)
+	if err != nil {
+		return nil, ErrOrderPaymentLinkFailed(err)
+	}
+
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		locked, err := s.storage.order.FindByID(tctx, ord.ID,
+			s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID),
+			s.storage.order.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return ErrOrderNotFound(ord.ID, err)
+		}
+		if !locked.canTakePaymentLink() {
+			return ErrOrderPaymentLinkNotAllowed(locked.ID, locked.Status, locked.PaymentStatus)
+		}
+		locked.PaymentLinkURL = transformer.ToNullString(cs.URL)
+		locked.CheckoutSessionID = transformer.ToNullString(cs.ID)
+		locked.CheckoutExpiresAt = sql.NullTime{Time: time.Unix(cs.ExpiresAt, 0).UTC(), Valid: cs.ExpiresAt > 0}
+		if err := s.storage.order.UpdateOne(tctx, locked); err != nil {
+			return err
+		}
+		ord.PaymentLinkURL = locked.PaymentLinkURL
+		ord.CheckoutSessionID = locked.CheckoutSessionID
+		ord.CheckoutExpiresAt = locked.CheckoutExpiresAt
+		ord.Version = locked.Version
+		return s.recordOrderEvent(tctx, actor, locked, OrderEventPaymentLinkCreated,
+			OrderFieldChange{Field: "paymentLinkUrl", To: cs.URL},
+			OrderFieldChange{Field: "amount", To: money.StringFixed(ord.AmountDue(), locked.Currency)},
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ord, nil
+}
+
+// CompleteOrderCheckout records the card payment of the amount due once a Stripe Checkout
+// session of the order is paid, which marks the order paid. Pending orders are placed
+// first, since the customer committed to them by paying. Orders already paid, deleted,
+// cancelled or returned are left alone, so replayed webhooks are harmless; a payment for a
+// closed order is logged for the seller to refund.
+func (s *Service) CompleteOrderCheckout(ctx context.Context, businessID, orderID, sessionID, paymentIntentID string) error {
+	biz, err := s.business.GetBusinessByIDForJobs(ctx, businessID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	reference := paymentIntentID
+	if reference == "" {
+		reference = sessionID
+	}
+	log := logger.FromContext(ctx).With("businessId", businessID, "orderId", orderID, "checkoutSessionId", sessionID)
+
+	var paid *Order
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		ord, err := s.storage.order.FindByID(tctx, orderID,
+			s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID),
+			s.storage.order.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			if database.IsRecordNotFound(err) {
+				log.Warn("paid checkout session for a deleted order")
+				return nil
+			}
+			return err
+		}
+		if !ord.canTakePaymentLink() {
+			if ord.PaymentStatus != OrderPaymentStatusPaid {
+				log.Warn("paid checkout session for an order that cannot be paid", "status", ord.Status, "paymentStatus", ord.PaymentStatus)
+			}
+			return nil
+		}
+
+		if ord.Status == OrderStatusPending {
+			if err := newOrderStateMachine(ord).transitionStateTo(OrderStatusPlaced); err != nil {
+				return err
+			}
+			if err := s.recordOrderEvent(tctx, nil, ord, OrderEventStatusChanged, OrderFieldChange{Field: "status", From: string(OrderStatusPending), To: string(ord.Status)}); err != nil {
+				return err
+			}
+		}
+		payment := &OrderPayment{
+			BusinessID: ord.BusinessID,
+			OrderID:    ord.ID,
+			Amount:     ord.AmountDue(),
+			Currency:   ord.Currency,
+			Method:     OrderPaymentMethodCreditCard,
+			Reference:  transformer.ToNullString(reference),
+			PaidAt:     time.Now().UTC(),
+		}
+		settled, err := s.applyOrderPayment(tctx, nil, ord, payment)
+		if err != nil {
+			return err
+		}
+		if err := s.storage.order.UpdateOne(tctx, ord); err != nil {
+			return err
+		}
+		if settled {
+			paid = ord
+		}
+		return nil
+	}, atomic.WithIsolationLevel(atomic.LevelSerializable), atomic.WithRetries(3))
+	if err != nil {
+		return err
+	}
+	if paid != nil {
+		s.emitPaidEvent(ctx, paid)
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package order
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/atomic"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+)
+
+// canTakePayment reports whether payments can still be recorded for the order.
+func (o *Order) canTakePayment() bool {
+	switch o.Status {
+	case OrderStatusCancelled, OrderStatusReturned:
+		return false
+	}
+	return o.PaymentStatus != OrderPaymentStatusRefunded
+}
+
+// canSettle reports whether recorded payments covering the total may mark the order paid.
+// Pending orders keep their deposits until they are placed.
+func (o *Order) canSettle() bool {
+	if o.markedPaid() || !o.AmountPaid.IsPositive() || o.AmountPaid.LessThan(o.Total) {
+		return false
+	}
+	return slices.Contains([]OrderStatus{OrderStatusPlaced, OrderStatusReadyForShipment, OrderStatusShipped, OrderStatusFulfilled}, o.Status)
+}
+
+// settlePayments marks the order paid once its recorded payments cover the total, with the
+// method and reference of the settling payment, or of the latest payment when it is nil.
+// It runs whenever the payments, the status or the total of an order change, in the
+// transaction that saves the order, and reports whether the order became paid; callers
+// record the payment status change and emit the paid event.
+func (s *Service) settlePayments(ctx context.Context, ord *Order, settling *OrderPayment) (bool, error) {
+	if !ord.canSettle() {
+		return false, nil
+	}
+	if settling == nil {
+		latest, err := s.storage.orderPayment.FindOne(ctx,
+			s.storage.orderPayment.ScopeBusinessID(ord.BusinessID),
+			s.storage.orderPayment.ScopeEquals(OrderPaymentSchema.OrderID, ord.ID),
+			s.storage.orderPayment.WithOrderBy([]string{"paid_at DESC", "created_at DESC"}),
+		)
+		if err != nil {
+			if database.IsRecordNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		settling = latest
+	}
+	sm := newOrderStateMachine(ord)
+	if ord.PaymentStatus == OrderPaymentStatusFailed {
+		if err := sm.transitionPaymentStatusTo(OrderPaymentStatusPending); err != nil {
+			return false, err
+		}
+	}
+	if err := sm.transitionPaymentStatusTo(OrderPaymentStatusPaid); err != nil {
+		return false, err
+	}
+	ord.PaymentMethod = settling.Method
+	ord.PaymentReference = settling.Reference
+	return true, nil
+}
+
+// ListOrderPayments returns the payments recorded for an order, oldest first.
+func (s *Service) ListOrderPayments(ctx context.Context, actor *account.User, biz *business.Business, orderID string) ([]*OrderPayment, error) {
+	if _, err := s.storage.order.FindByID(ctx, orderID, s.storage.order.ScopeBusinessID(biz.ID)); err != nil {
+		return nil, ErrOrderNotFound(orderID, err)
+	}
+	return s.storage.orderPayment.FindMany(ctx,
+		s.storage.orderPayment.ScopeBusinessID(biz.ID),
+		s.storage.orderPayment.ScopeEquals(OrderPaymentSchema.OrderID, orderID),
+		s.storage.orderPayment.WithOrderBy([]string{"paid_at ASC", "created_at ASC"}),
+	)
+}
+
+// RecordOrderPayment records a deposit, an instalment or the balance of an order and adds it
+// to the order's AmountPaid. Once the payments cover the total of a placed order, the order
+// becomes paid with the method and reference of the settling payment.
+func (s *Service) RecordOrderPayment(ctx context.Context, actor *account.User, biz *business.Business, orderID string, req *RecordOrderPaymentRequest) (*Order, error) {
+	if req == nil {
+		return nil, problem.BadRequest("payment is required")
+	}
+	now := time.Now().UTC()
+	paidAt := now
+	if req.PaidAt != nil {
+		if req.PaidAt.After(now) {
+			return nil, ErrOrderPaymentInFuture()
+		}
+		paidAt = req.PaidAt.UTC()
+	}
+
+	var updated *Order
+	var settled bool
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		updated, settled = nil, false
+		ord, err := s.storage.order.FindByID(tctx, orderID,
+			s.storage.order.ScopeBusinessID(biz.ID),
+			s.storage.order.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return ErrOrderNotFound(orderID, err)
+		}
+		if !ord.canTakePayment() {
+			return ErrOrderPaymentNotAllowed(ord.ID, ord.Status, ord.PaymentStatus)
+		}
+		if s.business != nil {
+			enabled, _, _, err := s.business.GetEffectivePaymentMethodFee(tctx, biz.ID, business.PaymentMethodDescriptor(req.Method))
+			if err != nil {
+				return err
+			}
+			if !enabled {
+				return problem.BadRequest("payment method is disabled for this business").With("paymentMethod", req.Method)
+			}
+		}
+
+		payment := &OrderPayment{
+			BusinessID: biz.ID,
+			OrderID:    ord.ID,
+			Amount:     req.Amount,
+			Currency:   ord.Currency,
+			Method:     req.Method,
+			Reference:  transformer.ToNullString(strings.TrimSpace(req.Reference)),
+			Note:       transformer.ToNullString(strings.TrimSpace(req.Note)),
+			PaidAt:     paidAt,
+		}
+		if settled, err = s.applyOrderPayment(tctx, actor, ord, payment); err != nil {
+			return err
+		}
+		if err := s.storage.order.UpdateOne(tctx, ord); err != nil {
+			if database.IsVersionConflict(err) {
+				return ErrOrderVersionConflict(ord.ID, err)
+			}
+			return err
+		}
+		updated = ord
+		return nil
+	}, atomic.WithIsolationLevel(atomic.LevelSerializable), atomic.WithRetries(3))
+	if err != nil {
+		return nil, err
+	}
+	if settled {
+		s.emitPaidEvent(ctx, updated)
+	}
+	return updated, nil
+}
+
+// applyOrderPayment stores payment for the locked order, adds it to the amount paid and
+// settles the order when the payments now cover the total, recording the timeline events
+// of both. The caller saves the order and emits the paid event when it reports true.
+func (s *Service) applyOrderPayment(ctx context.Context, actor *account.User, ord *Order, payment *OrderPayment) (bool, error) {
+	if err := s.storage.orderPayment.CreateOne(ctx, payment); err != nil {
+		return false, err
+	}
+	prevAmountPaid := ord.AmountPaid
+	ord.AmountPaid = ord.AmountPaid.Add(payment.Amount)
+	if err := s.recordOrderEvent(ctx, actor, ord, OrderEventPaymentRecorded,
+		OrderFieldChange{Field: "payment", To: payment.ID},
+		OrderFieldChange{Field: "amountPaid", From: money.StringFixed(prevAmountPaid, ord.Currency), To: money.StringFixed(ord.AmountPaid, ord.Currency)},
+	); err != nil {
+		return false, err
+	}
+
+	prevPaymentStatus := ord.PaymentStatus
+	settled, err := s.settlePayments(ctx, ord, payment)
+	if err != nil || !settled {
+		return false, err
+	}
+	if err := s.recordOrderEvent(ctx, actor, ord, OrderEventPaymentStatusChanged,
+		OrderFieldChange{Field: "paymentStatus", From: string(prevPaymentStatus), To: string(ord.PaymentStatus)},
+	); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteOrderPayment removes a payment recorded by mistake and takes it off the order's
+// AmountPaid. Payments of paid or refunded orders are kept: change the payment status first.
+func (s *Service) DeleteOrderPayment(ctx context.Context, actor *account.User, biz *business.Business, orderID, paymentID string) (*Order, error) {
+	var updated *Order
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		ord, err := s.storage.order.FindByID(tctx, orderID,
+			s.storage.order.ScopeBusinessID(biz.ID),
+			s.storage.order.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return ErrOrderNotFound(orderID, err)
+		}
+		payment, err := s.storage.orderPayment.FindOne(tctx,
+			s.storage.orderPayment.ScopeID(paymentID),
+			s.storage.orderPayment.ScopeBusinessID(biz.ID),
+			s.storage.orderPayment.ScopeEquals(OrderPaymentSchema.OrderID, ord.ID),
+		)
+		if err != nil {
+			return ErrOrderPaymentNotFound(paymentID, err)
+		}
+		if ord.markedPaid() {
+			return ErrOrderPaymentDeleteNotAllowed(ord.ID, ord.PaymentStatus)
+		}
+		if err := s.storage.orderPayment.DeleteOne(tctx, payment); err != nil {
+			return err
+		}
+		prevAmountPaid := ord.AmountPaid
+		ord.AmountPaid = ord.AmountPaid.Sub(payment.Amount)
+		if err := s.storage.order.UpdateOne(tctx, ord); err != nil {
+			if database.IsVersionConflict(err) {
+				return ErrOrderVersionConflict(ord.ID, err)
+			}
+			return err
+		}
+		updated = ord
+		return s.recordOrderEvent(tctx, actor, ord, OrderEventPaymentDeleted,
+			OrderFieldChange{Field: "payment", From: payment.ID},
+			OrderFieldChange{Field: "amountPaid", From: money.StringFixed(prevAmountPaid, ord.Currency), To: money.StringFixed(ord.AmountPaid, ord.Currency)},
+		)
+	}, atomic.WithIsolationLevel(atomic.LevelSerializable), atomic.WithRetries(3))
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
@@ -0,0 +1,251 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"gorm.io/gorm"
+)
+
+// maxPrintJobsPerPoll bounds how many queued jobs a station receives per poll.
+const maxPrintJobsPerPoll = 50
+
+func (s *Service) ListPrintStations(ctx context.Context, actor *account.User, biz *business.Business) ([]*PrintStation, error) {
+	return s.storage.printStation.FindMany(ctx,
+		s.storage.printStation.ScopeBusinessID(biz.ID),
+		s.storage.printStation.WithOrderBy([]string{PrintStationSchema.Name.Column()}),
+	)
+}
+
+func (s *Service) GetPrintStationByID(ctx context.Context, actor *account.User, biz *business.Business, stationID string) (*PrintStation, error) {
+	station, err := s.storage.printStation.FindOne(ctx,
+		s.storage.printStation.ScopeBusinessID(biz.ID),
+		s.storage.printStation.ScopeID(stationID),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrPrintStationNotFound(stationID, err)
+		}
+		return nil, err
+	}
+	return station, nil
+}
+
+func (s *Service) CreatePrintStation(ctx context.Context, actor *account.User, biz *business.Business, req *CreatePrintStationRequest) (*PrintStation, error) {
+	station := &PrintStation{
+		BusinessID:    biz.ID,
+		Name:          strings.TrimSpace(req.Name),
+		Kind:          req.Kind,
+		Format:        req.Format,
+		AutoQueuePaid: true,
+	}
+	if station.Kind == "" {
+		station.Kind = PrintKindReceipt
+	}
+	if station.Format == "" {
+		station.Format = PrintFormatESCPOS
+	}
+	if req.AutoQueuePaid != nil {
+		station.AutoQueuePaid = *req.AutoQueuePaid
+	}
+	if err := s.storage.printStation.CreateOne(ctx, station); err != nil {
+		return nil, err
+	}
+	return station, nil
+}
+
+func (s *Service) UpdatePrintStation(ctx context.Context, actor *account.User, biz *business.Business, stationID string, req *UpdatePrintStationRequest) (*PrintStation, error) {
+	station, err := s.GetPrintStationByID(ctx, actor, biz, stationID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		station.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Kind != "" {
+		station.Kind = req.Kind
+	}
+	if req.Format != "" {
+		station.Format = req.Format
+	}
+	if req.AutoQueuePaid != nil {
+		station.AutoQueuePaid = *req.AutoQueuePaid
+	}
+	if err := s.storage.printStation.UpdateOne(ctx, station); err != nil {
+		return nil, err
+	}
+	return station, nil
+}
+
+// DeletePrintStation removes the station together with its jobs.
+func (s *Service) DeletePrintStation(ctx context.Context, actor *account.User, biz *business.Business, stationID string) error {
+	station, err := s.GetPrintStationByID(ctx, actor, biz, stationID)
+	if err != nil {
+		return err
+	}
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.printJob.DeleteMany(tctx, s.storage.printJob.ScopeEquals(PrintJobSchema.StationID, station.ID)); err != nil {
+			return err
+		}
+		return s.storage.printStation.DeleteOne(tctx, station)
+	})
+}
+
+// PollPrintJobs returns the station's queued jobs, oldest first, and records the poll so
+// users can tell whether a station is online.
+func (s *Service) PollPrintJobs(ctx context.Context, actor *account.User, biz *business.Business, stationID string) ([]*PrintJob, error) {
+	station, err := s.GetPrintStationByID(ctx, actor, biz, stationID)
+	if err != nil {
+		return nil, err
+	}
+	station.LastPolledAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	if err := s.storage.printStation.UpdateOne(ctx, station); err != nil {
+		return nil, err
+	}
+	return s.storage.printJob.FindMany(ctx,
+		s.storage.printJob.ScopeBusinessID(biz.ID),
+		s.storage.printJob.ScopeEquals(PrintJobSchema.StationID, station.ID),
+		s.storage.printJob.ScopeEquals(PrintJobSchema.Status, PrintJobStatusQueued),
+		s.printJobOrderPreload(),
+		s.storage.printJob.WithOrderBy([]string{PrintJobSchema.CreatedAt.Column(), PrintJobSchema.ID.Column()}),
+		s.storage.printJob.WithLimit(maxPrintJobsPerPoll),
+	)
+}
+
+func (s *Service) getPrintJob(ctx context.Context, biz *business.Business, stationID, jobID string) (*PrintJob, error) {
+	job, err := s.storage.printJob.FindOne(ctx,
+		s.storage.printJob.ScopeBusinessID(biz.ID),
+		s.storage.printJob.ScopeEquals(PrintJobSchema.StationID, stationID),
+		s.storage.printJob.ScopeID(jobID),
+		s.storage.printJob.WithPreload(PrintStationStruct),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrPrintJobNotFound(jobID, err)
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// RenderPrintJob renders the job's order document. An empty format uses the station's format.
+func (s *Service) RenderPrintJob(ctx context.Context, actor *account.User, biz *business.Business, stationID, jobID string, format PrintFormat) (*Order, *PrintJob, PrintFormat, []byte, error) {
+	job, err := s.getPrintJob(ctx, biz, stationID, jobID)
+	if err != nil {
+		return nil, nil, "", nil, err
+	}
+	if format == "" {
+		format = job.Station.Format
+	}
+	ord, err := s.GetOrderByID(ctx, actor, biz, job.OrderID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, nil, "", nil, ErrOrderNotFound(job.OrderID, err)
+		}
+		return nil, nil, "", nil, err
+	}
+	return ord, job, format, RenderPrintDocument(biz, ord, job.Kind, format), nil
+}
+
+// AckPrintJob marks a job as printed. Acknowledging a printed job again is a no-op.
+func (s *Service) AckPrintJob(ctx context.Context, actor *account.User, biz *business.Business, stationID, jobID string) (*PrintJob, error) {
+	job, err := s.getPrintJob(ctx, biz, stationID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status == PrintJobStatusPrinted {
+		return job, nil
+	}
+	job.Status = PrintJobStatusPrinted
+	job.PrintedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	job.PrintedByID = actor.ID
+	if err := s.storage.printJob.UpdateOne(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// QueueOrderPrint queues an order on a station, e.g. to print an unpaid order or reprint one.
+// Kind defaults to the station's kind.
+func (s *Service) QueueOrderPrint(ctx context.Context, actor *account.User, biz *business.Business, orderID string, req *QueueOrderPrintRequest) (*PrintJob, error) {
+	station, err := s.GetPrintStationByID(ctx, actor, biz, req.StationID)
+	if err != nil {
+		return nil, err
+	}
+	ord, err := s.storage.order.FindOne(ctx,
+		s.storage.order.ScopeBusinessID(biz.ID),
+		s.storage.order.ScopeID(orderID),
+		s.storage.order.WithSelect(OrderSchema.ID.Column(), OrderSchema.OrderNumber.Column()),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrOrderNotFound(orderID, err)
+		}
+		return nil, err
+	}
+	job := &PrintJob{
+		BusinessID: biz.ID,
+		StationID:  station.ID,
+		OrderID:    ord.ID,
+		Kind:       req.Kind,
+		Source:     PrintJobSourceManual,
+		Status:     PrintJobStatusQueued,
+	}
+	if job.Kind == "" {
+		job.Kind = station.Kind
+	}
+	if err := s.storage.printJob.CreateOne(ctx, job); err != nil {
+		return nil, err
+	}
+	job.Order = ord
+	return job, nil
+}
+
+// QueuePaidOrderPrints queues a paid order on every station of the business that prints
+// paid orders automatically. Stations that already have the order queued from payment are
+// skipped, so replayed events do not print twice.
+func (s *Service) QueuePaidOrderPrints(ctx context.Context, businessID, orderID string) error {
+	stations, err := s.storage.printStation.FindMany(ctx,
+		s.storage.printStation.ScopeBusinessID(businessID),
+		s.storage.printStation.ScopeEquals(PrintStationSchema.AutoQueuePaid, true),
+	)
+	if err != nil || len(stations) == 0 {
+		return err
+	}
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		for _, station := range stations {
+			_, err := s.storage.printJob.FindOne(tctx,
+				s.storage.printJob.ScopeEquals(PrintJobSchema.StationID, station.ID),
+				s.storage.printJob.ScopeEquals(PrintJobSchema.OrderID, orderID),
+				s.storage.printJob.ScopeEquals(PrintJobSchema.Source, PrintJobSourcePaid),
+			)
+			if err == nil {
+				continue
+			}
+			if !database.IsRecordNotFound(err) {
+				return err
+			}
+			if err := s.storage.printJob.CreateOne(tctx, &PrintJob{
+				BusinessID: businessID,
+				StationID:  station.ID,
+				OrderID:    orderID,
+				Kind:       station.Kind,
+				Source:     PrintJobSourcePaid,
+				Status:     PrintJobStatusQueued,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// printJobOrderPreload keeps the order preload of print jobs to the columns the queue shows.
+func (s *Service) printJobOrderPreload() func(*gorm.DB) *gorm.DB {
+	return s.storage.printJob.WithPreloadSelect(OrderStruct, OrderSchema.ID.Column(), OrderSchema.OrderNumber.Column())
+}
@@ -0,0 +1,115 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"gorm.io/gorm"
+)
+
+// CustomerDataExport is everything the business stores about a customer, for answering a
+// data access request: the profile with addresses and notes, and the customer's orders (with
+// items, payments and notes), quotes and recurring orders.
+type CustomerDataExport struct {
+	ExportedAt      time.Time
+	Customer        *customer.Customer
+	Addresses       []*customer.CustomerAddress
+	Notes           []*customer.CustomerNote
+	Orders          []*Order
+	Quotes          []*Quote
+	RecurringOrders []*RecurringOrder
+}
+
+// ExportCustomerData gathers the data the business stores about a customer.
+func (s *Service) ExportCustomerData(ctx context.Context, actor *account.User, biz *business.Business, customerID string) (*CustomerDataExport, error) {
+	cust, err := s.customer.GetCustomerByID(ctx, actor, biz, customerID)
+	if err != nil {
+		return nil, err
+	}
+	addresses, err := s.customer.ListCustomerAddresses(ctx, actor, biz, cust.ID)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.customer.ListCustomerNotes(ctx, actor, biz, cust.ID)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := s.storage.order.FindMany(ctx, append([]func(*gorm.DB) *gorm.DB{
+		s.storage.order.ScopeBusinessID(biz.ID),
+		s.storage.order.ScopeEquals(OrderSchema.CustomerID, cust.ID),
+		s.storage.order.WithOrderBy([]string{OrderSchema.OrderedAt.Column()}),
+	}, s.orderDetailPreloads()...)...)
+	if err != nil {
+		return nil, err
+	}
+	quotes, err := s.storage.quote.FindMany(ctx,
+		s.storage.quote.ScopeBusinessID(biz.ID),
+		s.storage.quote.ScopeEquals(QuoteSchema.CustomerID, cust.ID),
+		s.storage.quote.WithOrderBy([]string{QuoteSchema.CreatedAt.Column()}),
+		s.storage.quote.WithPreload(QuoteItemStruct),
+		s.storage.quote.WithPreload(ShippingAddressStruct),
+	)
+	if err != nil {
+		return nil, err
+	}
+	recurring, err := s.storage.recurringOrder.FindMany(ctx,
+		s.storage.recurringOrder.ScopeBusinessID(biz.ID),
+		s.storage.recurringOrder.ScopeEquals(RecurringOrderSchema.CustomerID, cust.ID),
+		s.storage.recurringOrder.WithOrderBy([]string{RecurringOrderSchema.CreatedAt.Column()}),
+		s.storage.recurringOrder.WithPreload(ShippingAddressStruct),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &CustomerDataExport{
+		ExportedAt:      time.Now().UTC(),
+		Customer:        cust,
+		Addresses:       addresses,
+		Notes:           notes,
+		Orders:          orders,
+		Quotes:          quotes,
+		RecurringOrders: recurring,
+	}, nil
+}
+
+// EraseCustomerData erases the personal data of a customer on their request, in one
+// transaction: the customer and their addresses are anonymized (see
+// customer.Service.EraseCustomer), the notes of their orders are deleted and removed from the
+// order timelines, and their recurring orders end with their note cleared. Orders keep their
+// items, totals and payments, so revenue, statements and reports are unchanged. Erasing again
+// is harmless.
+func (s *Service) EraseCustomerData(ctx context.Context, actor *account.User, biz *business.Business, customerID string) (*customer.Customer, error) {
+	var erased *customer.Customer
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		cust, err := s.customer.EraseCustomer(tctx, actor, biz, customerID)
+		if err != nil {
+			return err
+		}
+		if err := s.storage.PurgeCustomerOrderNotes(tctx, biz.ID, cust.ID); err != nil {
+			return err
+		}
+		recurring, err := s.storage.recurringOrder.FindMany(tctx,
+			s.storage.recurringOrder.ScopeBusinessID(biz.ID),
+			s.storage.recurringOrder.ScopeEquals(RecurringOrderSchema.CustomerID, cust.ID),
+		)
+		if err != nil {
+			return err
+		}
+		for _, ro := range recurring {
+			ro.Status = RecurringOrderStatusEnded
+			ro.Note = ""
+			if err := s.storage.recurringOrder.UpdateOne(tctx, ro); err != nil {
+				return err
+			}
+		}
+		erased = cust
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return erased, nil
+}
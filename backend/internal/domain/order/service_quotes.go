@@ -0,0 +1,470 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/atomic"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// quoteTransitions lists the status changes a user can make. Expiry is applied by the
+// system when validUntil passes and acceptance goes through AcceptQuote.
+var quoteTransitions = map[QuoteStatus][]QuoteStatus{
+	QuoteStatusDraft:    {QuoteStatusSent},
+	QuoteStatusSent:     {QuoteStatusAccepted, QuoteStatusDeclined},
+	QuoteStatusAccepted: {},
+	QuoteStatusDeclined: {},
+	QuoteStatusExpired:  {},
+}
+
+func transitionQuoteTo(q *Quote, to QuoteStatus) error {
+	if !slices.Contains(quoteTransitions[q.Status], to) {
+		return ErrQuoteStatusUpdateNotAllowed(q.ID, q.Status, to)
+	}
+	now := sql.NullTime{Time: time.Now(), Valid: true}
+	switch to {
+	case QuoteStatusSent:
+		q.SentAt = now
+	case QuoteStatusAccepted:
+		q.AcceptedAt = now
+	case QuoteStatusDeclined:
+		q.DeclinedAt = now
+	}
+	q.Status = to
+	return nil
+}
+
+// ListQuotesFilters narrows the quotes list.
+type ListQuotesFilters struct {
+	Statuses   []QuoteStatus
+	CustomerID string
+}
+
+func (s *Service) quoteDetailPreloads() []func(*gorm.DB) *gorm.DB {
+	return []func(*gorm.DB) *gorm.DB{
+		s.storage.quote.WithPreload(customer.CustomerStruct, ShippingAddressStruct, ItemsProductStruct, ItemsVariantStruct),
+	}
+}
+
+// expireQuotes applies lazy expiry so reads never report a sent quote past its validity.
+func (s *Service) expireQuotes(ctx context.Context, biz *business.Business) error {
+	return s.storage.ExpireSentQuotes(ctx, biz.ID, time.Now())
+}
+
+func (s *Service) GetQuoteByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Quote, error) {
+	if err := s.expireQuotes(ctx, biz); err != nil {
+		return nil, err
+	}
+	opts := append([]func(*gorm.DB) *gorm.DB{
+		s.storage.quote.ScopeBusinessID(biz.ID),
+		s.storage.quote.ScopeID(id),
+	}, s.quoteDetailPreloads()...)
+	return s.storage.quote.FindOne(ctx, opts...)
+}
+
+// ListQuotes returns quotes of the business, newest first by default.
+// Filtering by customer gives the quote history shown on the customer's profile.
+func (s *Service) ListQuotes(ctx context.Context, actor *account.User, biz *business.Business, req *list.ListRequest, filters *ListQuotesFilters) ([]*Quote, int64, error) {
+	if err := s.expireQuotes(ctx, biz); err != nil {
+		return nil, 0, err
+	}
+	scopes := []func(*gorm.DB) *gorm.DB{s.storage.quote.ScopeBusinessID(biz.ID)}
+	if filters != nil {
+		if filters.CustomerID != "" {
+			scopes = append(scopes, s.storage.quote.ScopeEquals(QuoteSchema.CustomerID, filters.CustomerID))
+		}
+		if len(filters.Statuses) > 0 {
+			statuses := make([]any, len(filters.Statuses))
+			for i, st := range filters.Statuses {
+				statuses[i] = st
+			}
+			scopes = append(scopes, s.storage.quote.ScopeIn(QuoteSchema.Status, statuses))
+		}
+	}
+
+	total, err := s.storage.quote.Count(ctx, scopes...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	listOpts := append(append([]func(*gorm.DB) *gorm.DB{}, scopes...),
+		s.storage.quote.WithPreload(customer.CustomerStruct),
+		s.storage.quote.WithOrderBy(req.ParsedOrderByWithDefault(QuoteSchema, []string{QuoteSchema.CreatedAt.Column() + " DESC"})),
+		s.storage.quote.WithPagination(req.Offset(), req.Limit()),
+	)
+	items, err := s.storage.quote.FindMany(ctx, listOpts...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (s *Service) CreateQuote(ctx context.Context, actor *account.User, biz *business.Business, req *CreateQuoteRequest) (*Quote, error) {
+	if req == nil || len(req.Items) == 0 {
+		return nil, ErrEmptyOrderItems()
+	}
+	validUntil := req.ValidUntil
+	if validUntil.IsZero() {
+		validUntil = time.Now().Add(defaultQuoteValidity)
+	}
+	if !validUntil.After(time.Now()) {
+		return nil, ErrQuoteValidUntilInPast(validUntil.Format(time.RFC3339))
+	}
+
+	var quote *Quote
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		tx, _ := tctx.Value(database.TxKey).(*gorm.DB)
+		if tx == nil {
+			return problem.InternalError().With("reason", "missing transaction in context")
+		}
+
+		cust, err := s.customer.GetCustomerByID(tctx, actor, biz, req.CustomerID)
+		if err != nil {
+			return err
+		}
+		shippingAddressID := strings.TrimSpace(req.ShippingAddressID)
+		if shippingAddressID != "" {
+			if _, err := s.customer.GetCustomerAddressByID(tctx, actor, biz, req.CustomerID, shippingAddressID); err != nil {
+				return err
+			}
+		}
+
+		priceList, err := s.inventory.ResolvePriceList(tctx, biz, cust.PriceListID.String)
+		if err != nil {
+			return err
+		}
+		items, err := s.prepareQuoteItems(tctx, actor, biz, priceList, req.Items)
+		if err != nil {
+			return err
+		}
+
+		q := &Quote{
+			BusinessID:        biz.ID,
+			CustomerID:        req.CustomerID,
+			ShippingAddressID: sql.NullString{String: shippingAddressID, Valid: shippingAddressID != ""},
+			ShippingFee:       money.Round(req.ShippingFee, biz.Currency),
+			DiscountType:      req.DiscountType,
+			DiscountValue:     req.DiscountValue,
+			Currency:          biz.Currency,
+			Status:            QuoteStatusDraft,
+			ValidUntil:        validUntil,
+			Terms:             strings.TrimSpace(req.Terms),
+		}
+		s.applyQuoteTotals(q, items, biz)
+
+		// generate quote number with retry on conflict
+		const maxRetries = 5
+		for i := 0; i < maxRetries; i++ {
+			q.QuoteNumber = "Q-" + id.Base62(6)
+			sp := fmt.Sprintf("sp_quote_number_%d", i)
+			if err := tx.SavePoint(sp).Error; err != nil {
+				return err
+			}
+			if err := s.storage.quote.CreateOne(tctx, q); err != nil {
+				if database.IsUniqueViolation(err) {
+					// A failed statement aborts the transaction in Postgres.
+					// Roll back to the savepoint so we can safely retry.
+					if rbErr := tx.RollbackTo(sp).Error; rbErr != nil {
+						return rbErr
+					}
+					q.ID = ""
+					continue
+				}
+				return err
+			}
+			quote = q
+			break
+		}
+		if quote == nil {
+			return ErrQuoteNumberGenerationFailed(nil)
+		}
+
+		for _, it := range items {
+			it.QuoteID = quote.ID
+		}
+		if err := s.storage.quoteItem.CreateMany(tctx, items); err != nil {
+			return err
+		}
+		quote.Items = items
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// UpdateQuote changes a draft quote and recomputes its totals.
+func (s *Service) UpdateQuote(ctx context.Context, actor *account.User, biz *business.Business, id string, req *UpdateQuoteRequest) (*Quote, error) {
+	var quote *Quote
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		q, err := s.storage.quote.FindOne(tctx,
+			s.storage.quote.ScopeBusinessID(biz.ID),
+			s.storage.quote.ScopeID(id),
+			s.storage.quote.WithPreload(QuoteItemStruct),
+			s.storage.quote.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return err
+		}
+		if !q.IsEditable() {
+			return ErrQuoteNotEditable(q.ID, q.Status)
+		}
+
+		if req.ShippingAddressID != nil {
+			addrID := strings.TrimSpace(*req.ShippingAddressID)
+			if addrID != "" {
+				if _, err := s.customer.GetCustomerAddressByID(tctx, actor, biz, q.CustomerID, addrID); err != nil {
+					return err
+				}
+			}
+			q.ShippingAddressID = sql.NullString{String: addrID, Valid: addrID != ""}
+		}
+		if req.ShippingFee.Valid {
+			q.ShippingFee = money.Round(req.ShippingFee.Decimal, biz.Currency)
+		}
+		if req.DiscountType != "" {
+			q.DiscountType = req.DiscountType
+		}
+		if req.DiscountValue.Valid {
+			q.DiscountValue = req.DiscountValue.Decimal
+		}
+		if req.ValidUntil != nil {
+			if !req.ValidUntil.After(time.Now()) {
+				return ErrQuoteValidUntilInPast(req.ValidUntil.Format(time.RFC3339))
+			}
+			q.ValidUntil = *req.ValidUntil
+		}
+		if req.Terms != nil {
+			q.Terms = strings.TrimSpace(*req.Terms)
+		}
+
+		items := q.Items
+		if len(req.Items) > 0 {
+			cust, err := s.customer.GetCustomerByID(tctx, actor, biz, q.CustomerID)
+			if err != nil {
+				return err
+			}
+			priceList, err := s.inventory.ResolvePriceList(tctx, biz, cust.PriceListID.String)
+			if err != nil {
+				return err
+			}
+			items, err = s.prepareQuoteItems(tctx, actor, biz, priceList, req.Items)
+			if err != nil {
+				return err
+			}
+			if err := s.storage.quoteItem.DeleteMany(tctx, s.storage.quoteItem.ScopeEquals(QuoteItemSchema.QuoteID, q.ID)); err != nil {
+				return err
+			}
+			for _, it := range items {
+				it.QuoteID = q.ID
+			}
+			if err := s.storage.quoteItem.CreateMany(tctx, items); err != nil {
+				return err
+			}
+		}
+		s.applyQuoteTotals(q, items, biz)
+
+		q.Items = nil
+		if err := s.storage.quote.UpdateOne(tctx, q); err != nil {
+			return err
+		}
+		q.Items = items
+		quote = q
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// SendQuote marks a draft quote as sent to the customer. From then on it is read-only.
+func (s *Service) SendQuote(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Quote, error) {
+	return s.transitionQuote(ctx, biz, id, QuoteStatusSent)
+}
+
+// DeclineQuote records that the customer turned the quote down.
+func (s *Service) DeclineQuote(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Quote, error) {
+	return s.transitionQuote(ctx, biz, id, QuoteStatusDeclined)
+}
+
+func (s *Service) transitionQuote(ctx context.Context, biz *business.Business, id string, to QuoteStatus) (*Quote, error) {
+	if err := s.expireQuotes(ctx, biz); err != nil {
+		return nil, err
+	}
+	var quote *Quote
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		q, err := s.storage.quote.FindOne(tctx,
+			s.storage.quote.ScopeBusinessID(biz.ID),
+			s.storage.quote.ScopeID(id),
+			s.storage.quote.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return err
+		}
+		if to == QuoteStatusSent && !q.ValidUntil.After(time.Now()) {
+			return ErrQuoteValidUntilInPast(q.ValidUntil.Format(time.RFC3339))
+		}
+		if err := transitionQuoteTo(q, to); err != nil {
+			return err
+		}
+		if err := s.storage.quote.UpdateOne(tctx, q); err != nil {
+			return err
+		}
+		quote = q
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// AcceptQuote converts a sent quote into a pending order with the quoted items, prices,
+// discount and shipping fee, and links the order back to the quote.
+// Stock is allocated and VAT recomputed at the business' current rate, exactly as for a new order.
+func (s *Service) AcceptQuote(ctx context.Context, actor *account.User, biz *business.Business, id string, req *AcceptQuoteRequest) (*Quote, *Order, error) {
+	if err := s.expireQuotes(ctx, biz); err != nil {
+		return nil, nil, err
+	}
+	var quote *Quote
+	var ord *Order
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		q, err := s.storage.quote.FindOne(tctx,
+			s.storage.quote.ScopeBusinessID(biz.ID),
+			s.storage.quote.ScopeID(id),
+			s.storage.quote.WithPreload(QuoteItemStruct, ItemsVariantStruct),
+			s.storage.quote.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return err
+		}
+		if err := transitionQuoteTo(q, QuoteStatusAccepted); err != nil {
+			return err
+		}
+
+		shippingAddressID := strings.TrimSpace(req.ShippingAddressID)
+		if shippingAddressID == "" {
+			shippingAddressID = q.ShippingAddressID.String
+		}
+		if shippingAddressID == "" {
+			return ErrQuoteShippingAddressRequired(q.ID)
+		}
+
+		orderReq := &CreateOrderRequest{
+			CustomerID:        q.CustomerID,
+			Channel:           req.Channel,
+			ShippingAddressID: shippingAddressID,
+			ShippingZoneID:    req.ShippingZoneID,
+			ShippingFee:       q.ShippingFee,
+			DiscountType:      q.DiscountType,
+			DiscountValue:     q.DiscountValue,
+			PaymentMethod:     req.PaymentMethod,
+			Note:              req.Note,
+			Items:             make([]*CreateOrderItemRequest, 0, len(q.Items)),
+		}
+		for _, it := range q.Items {
+			orderReq.Items = append(orderReq.Items, &CreateOrderItemRequest{
+				VariantID: it.VariantID,
+				Quantity:  it.Quantity,
+				UnitPrice: it.UnitPrice,
+			})
+		}
+		created, err := s.CreateOrder(tctx, actor, biz, orderReq)
+		if err != nil {
+			return err
+		}
+
+		q.OrderID = sql.NullString{String: created.ID, Valid: true}
+		items := q.Items
+		q.Items = nil
+		if err := s.storage.quote.UpdateOne(tctx, q); err != nil {
+			return err
+		}
+		q.Items = items
+		quote = q
+		ord = created
+		return nil
+	}, atomic.WithIsolationLevel(atomic.LevelSerializable), atomic.WithRetries(3))
+	if err != nil {
+		return nil, nil, err
+	}
+	return quote, ord, nil
+}
+
+// DeleteQuote deletes a quote that did not become an order.
+func (s *Service) DeleteQuote(ctx context.Context, actor *account.User, biz *business.Business, id string) error {
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		q, err := s.storage.quote.FindOne(tctx,
+			s.storage.quote.ScopeBusinessID(biz.ID),
+			s.storage.quote.ScopeID(id),
+		)
+		if err != nil {
+			return err
+		}
+		if q.Status == QuoteStatusAccepted {
+			return ErrQuoteCannotBeDeleted(q.ID, q.Status)
+		}
+		if err := s.storage.quoteItem.DeleteMany(tctx, s.storage.quoteItem.ScopeEquals(QuoteItemSchema.QuoteID, q.ID)); err != nil {
+			return err
+		}
+		return s.storage.quote.DeleteOne(tctx, q)
+	})
+}
+
+func (s *Service) prepareQuoteItems(ctx context.Context, actor *account.User, biz *business.Business, priceList *inventory.PriceList, reqItems []*CreateQuoteItemRequest) ([]*QuoteItem, error) {
+	items := make([]*QuoteItem, 0, len(reqItems))
+	for _, reqItem := range reqItems {
+		variant, err := s.inventory.GetVariantByID(ctx, actor, biz, reqItem.VariantID)
+		if err != nil {
+			return nil, ErrVariantNotFound(reqItem.VariantID, err)
+		}
+		if reqItem.Quantity <= 0 {
+			return nil, ErrInvalidOrderItemQuantity(reqItem.VariantID, reqItem.Quantity)
+		}
+		unitPrice := reqItem.UnitPrice
+		if unitPrice.IsZero() {
+			unitPrice = priceList.PriceFor(variant, reqItem.Quantity, biz.Currency)
+		}
+		unitPrice = money.Round(unitPrice, biz.Currency)
+		items = append(items, &QuoteItem{
+			ProductID: variant.ProductID,
+			VariantID: variant.ID,
+			Quantity:  reqItem.Quantity,
+			Currency:  biz.Currency,
+			UnitPrice: unitPrice,
+			Total:     money.Round(unitPrice.Mul(decimal.NewFromInt(int64(reqItem.Quantity))), biz.Currency),
+		})
+	}
+	return items, nil
+}
+
+// applyQuoteTotals computes subtotal, discount, VAT and total the same way orders do.
+func (s *Service) applyQuoteTotals(q *Quote, items []*QuoteItem, biz *business.Business) {
+	subtotal := decimal.Zero
+	for _, it := range items {
+		subtotal = subtotal.Add(it.Total)
+	}
+	q.Subtotal = money.Round(subtotal, biz.Currency)
+	q.Discount = s.computeDiscountAmount(q.Subtotal, biz.Currency, &CreateOrderRequest{DiscountType: q.DiscountType, DiscountValue: q.DiscountValue})
+	q.VATRate = biz.VatRate
+	q.PricesIncludeVAT = biz.PricesIncludeVat
+	q.VAT = s.calculateVAT(q.Subtotal, q.VATRate, q.PricesIncludeVAT, biz.Currency)
+	q.Total = s.calculateTotal(q.Subtotal, q.VAT, q.ShippingFee, q.Discount, q.PricesIncludeVAT, biz.Currency)
+}
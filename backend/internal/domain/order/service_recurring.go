@@ -0,0 +1,535 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/types/atomic"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"gorm.io/gorm"
+)
+
+// recurringOrderTransitions lists the status changes a user can make. Recurring orders end
+// on their own once the next run passes the end date.
+var recurringOrderTransitions = map[RecurringOrderStatus][]RecurringOrderStatus{
+	RecurringOrderStatusActive: {RecurringOrderStatusPaused},
+	RecurringOrderStatusPaused: {RecurringOrderStatusActive},
+	RecurringOrderStatusEnded:  {},
+}
+
+// recurringOrderDate truncates t to its UTC calendar date, the unit recurring orders are
+// scheduled in.
+func recurringOrderDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ListRecurringOrdersFilters narrows the recurring orders list.
+type ListRecurringOrdersFilters struct {
+	Statuses   []RecurringOrderStatus
+	CustomerID string
+}
+
+func (s *Service) GetRecurringOrderByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*RecurringOrder, error) {
+	return s.storage.recurringOrder.FindOne(ctx,
+		s.storage.recurringOrder.ScopeBusinessID(biz.ID),
+		s.storage.recurringOrder.ScopeID(id),
+		s.storage.recurringOrder.WithPreload(customer.CustomerStruct, ShippingAddressStruct),
+	)
+}
+
+// ListRecurringOrders returns recurring orders of the business, soonest next run first by
+// default. Filtering by customer gives the subscriptions shown on the customer's profile.
+func (s *Service) ListRecurringOrders(ctx context.Context, actor *account.User, biz *business.Business, req *list.ListRequest, filters *ListRecurringOrdersFilters) ([]*RecurringOrder, int64, error) {
+	scopes := []func(*gorm.DB) *gorm.DB{s.storage.recurringOrder.ScopeBusinessID(biz.ID)}
+	if filters != nil {
+		if filters.CustomerID != "" {
+			scopes = append(scopes, s.storage.recurringOrder.ScopeEquals(RecurringOrderSchema.CustomerID, filters.CustomerID))
+		}
+		if len(filters.Statuses) > 0 {
+			statuses := make([]any, len(filters.Statuses))
+			for i, st := range filters.Statuses {
+				statuses[i] = st
+			}
+			scopes = append(scopes, s.storage.recurringOrder.ScopeIn(RecurringOrderSchema.Status, statuses))
+		}
+	}
+
+	total, err := s.storage.recurringOrder.Count(ctx, scopes...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	listOpts := append(append([]func(*gorm.DB) *gorm.DB{}, scopes...),
+		s.storage.recurringOrder.WithPreload(customer.CustomerStruct),
+		s.storage.recurringOrder.WithOrderBy(req.ParsedOrderByWithDefault(RecurringOrderSchema, []string{RecurringOrderSchema.NextRunDate.Column() + " ASC"})),
+		s.storage.recurringOrder.WithPagination(req.Offset(), req.Limit()),
+	)
+	items, err := s.storage.recurringOrder.FindMany(ctx, listOpts...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// prepareRecurringOrderItems checks the template lines refer to variants of the business.
+// Stock is only checked when orders are generated.
+func (s *Service) prepareRecurringOrderItems(ctx context.Context, actor *account.User, biz *business.Business, reqItems []*RecurringOrderItemRequest) (RecurringOrderItems, error) {
+	if len(reqItems) == 0 {
+		return nil, ErrEmptyOrderItems()
+	}
+	items := make(RecurringOrderItems, 0, len(reqItems))
+	for _, it := range reqItems {
+		if it.Quantity <= 0 {
+			return nil, ErrInvalidOrderItemQuantity(it.VariantID, it.Quantity)
+		}
+		if _, err := s.inventory.GetVariantByID(ctx, actor, biz, it.VariantID); err != nil {
+			return nil, ErrVariantNotFound(it.VariantID, err)
+		}
+		items = append(items, RecurringOrderItem{VariantID: it.VariantID, Quantity: it.Quantity})
+	}
+	return items, nil
+}
+
+// normalizeShippingZoneID validates an optional shipping zone against the destination
+// address and returns nil when none is set.
+func (s *Service) normalizeShippingZoneID(ctx context.Context, actor *account.User, biz *business.Business, zoneID *string, addr *customer.CustomerAddress) (*string, error) {
+	if zoneID == nil || strings.TrimSpace(*zoneID) == "" {
+		return nil, nil
+	}
+	id := strings.TrimSpace(*zoneID)
+	z, err := s.getShippingZone(ctx, actor, biz, id)
+	if err != nil {
+		return nil, err
+	}
+	country := strings.TrimSpace(strings.ToUpper(addr.CountryCode))
+	if country == "" || !z.Countries.Contains(country) {
+		return nil, ErrShippingZoneCountryMismatch(z.ID, country)
+	}
+	return &id, nil
+}
+
+// CreateRecurringOrder sets up a product subscription for a customer. The first order is
+// generated on the start date (today by default).
+func (s *Service) CreateRecurringOrder(ctx context.Context, actor *account.User, biz *business.Business, req *CreateRecurringOrderRequest) (*RecurringOrder, error) {
+	today := recurringOrderDate(time.Now())
+	start := today
+	if req.StartDate != nil && !req.StartDate.IsZero() {
+		start = recurringOrderDate(req.StartDate.Time)
+	}
+	var end sql.NullTime
+	if req.EndDate != nil && !req.EndDate.IsZero() {
+		end = sql.NullTime{Time: recurringOrderDate(req.EndDate.Time), Valid: true}
+		if end.Time.Before(start) {
+			return nil, ErrRecurringOrderInvalidDateRange()
+		}
+	}
+	tags, err := newOrderTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.customer.GetCustomerByID(ctx, actor, biz, req.CustomerID); err != nil {
+		return nil, err
+	}
+	addr, err := s.customer.GetCustomerAddressByID(ctx, actor, biz, req.CustomerID, req.ShippingAddressID)
+	if err != nil {
+		return nil, err
+	}
+	zoneID, err := s.normalizeShippingZoneID(ctx, actor, biz, req.ShippingZoneID, addr)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.prepareRecurringOrderItems(ctx, actor, biz, req.Items)
+	if err != nil {
+		return nil, err
+	}
+	paymentMethod := req.PaymentMethod
+	if paymentMethod == "" {
+		paymentMethod = OrderPaymentMethodBankTransfer
+	}
+	nextRun := start
+	if nextRun.Before(today) {
+		nextRun = today
+	}
+
+	ro := &RecurringOrder{
+		BusinessID:        biz.ID,
+		CustomerID:        req.CustomerID,
+		ShippingAddressID: req.ShippingAddressID,
+		ShippingZoneID:    zoneID,
+		Channel:           strings.TrimSpace(req.Channel),
+		PaymentMethod:     paymentMethod,
+		Frequency:         req.Frequency,
+		Status:            RecurringOrderStatusActive,
+		StartDate:         start,
+		EndDate:           end,
+		NextRunDate:       nextRun,
+		Items:             items,
+		Tags:              tags,
+		Note:              strings.TrimSpace(req.Note),
+	}
+	if err := s.storage.recurringOrder.CreateOne(ctx, ro); err != nil {
+		return nil, err
+	}
+	return ro, nil
+}
+
+// UpdateRecurringOrder changes the template of a recurring order. Changes apply to the
+// orders generated from the next run on.
+func (s *Service) UpdateRecurringOrder(ctx context.Context, actor *account.User, biz *business.Business, id string, req *UpdateRecurringOrderRequest) (*RecurringOrder, error) {
+	var updated *RecurringOrder
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		ro, err := s.storage.recurringOrder.FindOne(tctx,
+			s.storage.recurringOrder.ScopeBusinessID(biz.ID),
+			s.storage.recurringOrder.ScopeID(id),
+			s.storage.recurringOrder.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return err
+		}
+
+		if req.ShippingAddressID != nil && strings.TrimSpace(*req.ShippingAddressID) != "" {
+			ro.ShippingAddressID = strings.TrimSpace(*req.ShippingAddressID)
+		}
+		if req.ShippingZoneID != nil {
+			ro.ShippingZoneID = req.ShippingZoneID
+		}
+		if req.ShippingAddressID != nil || req.ShippingZoneID != nil {
+			addr, err := s.customer.GetCustomerAddressByID(tctx, actor, biz, ro.CustomerID, ro.ShippingAddressID)
+			if err != nil {
+				if database.IsRecordNotFound(err) {
+					return customer.ErrCustomerAddressNotFound(err)
+				}
+				return err
+			}
+			if ro.ShippingZoneID, err = s.normalizeShippingZoneID(tctx, actor, biz, ro.ShippingZoneID, addr); err != nil {
+				return err
+			}
+		}
+		if req.Channel != nil {
+			ro.Channel = strings.TrimSpace(*req.Channel)
+		}
+		if req.PaymentMethod != "" {
+			ro.PaymentMethod = req.PaymentMethod
+		}
+		if req.Frequency != "" {
+			ro.Frequency = req.Frequency
+		}
+		if req.NextRunDate != nil && !req.NextRunDate.IsZero() {
+			next := recurringOrderDate(req.NextRunDate.Time)
+			if next.Before(recurringOrderDate(time.Now())) {
+				return ErrRecurringOrderNextRunInPast(next.Format(time.DateOnly))
+			}
+			ro.NextRunDate = next
+		}
+		if req.EndDate != nil && !req.EndDate.IsZero() {
+			end := recurringOrderDate(req.EndDate.Time)
+			if end.Before(ro.StartDate) {
+				return ErrRecurringOrderInvalidDateRange()
+			}
+			ro.EndDate = sql.NullTime{Time: end, Valid: true}
+		}
+		if req.Note != nil {
+			ro.Note = strings.TrimSpace(*req.Note)
+		}
+		if req.Tags != nil {
+			tags, err := newOrderTags(*req.Tags)
+			if err != nil {
+				return err
+			}
+			ro.Tags = tags
+		}
+		if len(req.Items) > 0 {
+			items, err := s.prepareRecurringOrderItems(tctx, actor, biz, req.Items)
+			if err != nil {
+				return err
+			}
+			ro.Items = items
+		}
+		if err := s.storage.recurringOrder.UpdateOne(tctx, ro); err != nil {
+			return err
+		}
+		updated = ro
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteRecurringOrder deletes a recurring order. Orders it generated are kept.
+func (s *Service) DeleteRecurringOrder(ctx context.Context, actor *account.User, biz *business.Business, id string) error {
+	ro, err := s.storage.recurringOrder.FindOne(ctx,
+		s.storage.recurringOrder.ScopeBusinessID(biz.ID),
+		s.storage.recurringOrder.ScopeID(id),
+	)
+	if err != nil {
+		return err
+	}
+	return s.storage.recurringOrder.DeleteOne(ctx, ro)
+}
+
+// PauseRecurringOrder stops a recurring order from generating orders until it is resumed.
+func (s *Service) PauseRecurringOrder(ctx context.Context, actor *account.User, biz *business.Business, id string) (*RecurringOrder, error) {
+	return s.updateRecurringOrderStatus(ctx, biz, id, RecurringOrderStatusPaused)
+}
+
+// ResumeRecurringOrder reactivates a paused recurring order. Runs missed while it was paused
+// are not made up for: the next run moves to the first cadence date from today on.
+func (s *Service) ResumeRecurringOrder(ctx context.Context, actor *account.User, biz *business.Business, id string) (*RecurringOrder, error) {
+	return s.updateRecurringOrderStatus(ctx, biz, id, RecurringOrderStatusActive)
+}
+
+func (s *Service) updateRecurringOrderStatus(ctx context.Context, biz *business.Business, id string, to RecurringOrderStatus) (*RecurringOrder, error) {
+	var updated *RecurringOrder
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		ro, err := s.storage.recurringOrder.FindOne(tctx,
+			s.storage.recurringOrder.ScopeBusinessID(biz.ID),
+			s.storage.recurringOrder.ScopeID(id),
+			s.storage.recurringOrder.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(recurringOrderTransitions[ro.Status], to) {
+			return ErrRecurringOrderStatusUpdateNotAllowed(ro.ID, ro.Status, to)
+		}
+		ro.Status = to
+		if to == RecurringOrderStatusActive {
+			today := recurringOrderDate(time.Now())
+			for ro.NextRunDate.Before(today) {
+				ro.NextRunDate = ro.Frequency.GetNextRecurrenceDate(ro.NextRunDate)
+			}
+			if ro.EndDate.Valid && ro.NextRunDate.After(ro.EndDate.Time) {
+				ro.Status = RecurringOrderStatusEnded
+			}
+		}
+		if err := s.storage.recurringOrder.UpdateOne(tctx, ro); err != nil {
+			return err
+		}
+		updated = ro
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// GenerateRecurringOrder creates the upcoming order of an active recurring order right away,
+// e.g. when the customer asks for an early delivery. The generated order replaces the
+// scheduled run, so the next run moves one cadence step forward.
+func (s *Service) GenerateRecurringOrder(ctx context.Context, actor *account.User, biz *business.Business, id string) (*RecurringOrder, *Order, error) {
+	return s.runRecurringOrder(ctx, actor, biz, id, recurringOrderDate(time.Now()), true)
+}
+
+// runRecurringOrder creates the order of a due run and schedules the next one. Scheduled
+// runs (onDemand false) of recurring orders that are no longer active or due, e.g. because
+// an overlapping run already handled them, are left alone.
+func (s *Service) runRecurringOrder(ctx context.Context, actor *account.User, biz *business.Business, id string, today time.Time, onDemand bool) (*RecurringOrder, *Order, error) {
+	var ro *RecurringOrder
+	var created *Order
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		locked, err := s.storage.recurringOrder.FindOne(tctx,
+			s.storage.recurringOrder.ScopeBusinessID(biz.ID),
+			s.storage.recurringOrder.ScopeID(id),
+			s.storage.recurringOrder.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			if !onDemand && database.IsRecordNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		ro = locked
+		if locked.Status != RecurringOrderStatusActive {
+			if onDemand {
+				return ErrRecurringOrderNotActive(locked.ID, locked.Status)
+			}
+			return nil
+		}
+		if !onDemand && locked.NextRunDate.After(today) {
+			return nil
+		}
+
+		orderReq, err := s.recurringOrderRequest(tctx, actor, biz, locked)
+		if err != nil {
+			return err
+		}
+		created, err = s.createOrder(tctx, actor, biz, orderReq, createOrderOptions{})
+		if err != nil {
+			return err
+		}
+
+		locked.OrdersCreated++
+		locked.LastRunAt = sql.NullTime{Time: time.Now(), Valid: true}
+		locked.LastOrderID = sql.NullString{String: created.ID, Valid: true}
+		locked.LastSkipReason = ""
+		locked.scheduleAfter(today)
+		return s.storage.recurringOrder.UpdateOne(tctx, locked)
+	}, atomic.WithIsolationLevel(atomic.LevelSerializable), atomic.WithRetries(3))
+	if err != nil {
+		return nil, nil, err
+	}
+	return ro, created, nil
+}
+
+// recurringOrderRequest builds the order of a run, priced from the customer's price list.
+// Runs whose customer, address or items no longer exist, or whose items are out of stock,
+// fail with a not found or conflict problem.
+func (s *Service) recurringOrderRequest(ctx context.Context, actor *account.User, biz *business.Business, ro *RecurringOrder) (*CreateOrderRequest, error) {
+	if _, err := s.customer.GetCustomerAddressByID(ctx, actor, biz, ro.CustomerID, ro.ShippingAddressID); err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, customer.ErrCustomerAddressNotFound(err)
+		}
+		return nil, err
+	}
+	variants := make(map[string]*inventory.Variant, len(ro.Items))
+	needed := make(map[string]int, len(ro.Items))
+	items := make([]*CreateOrderItemRequest, 0, len(ro.Items))
+	for _, it := range ro.Items {
+		v, ok := variants[it.VariantID]
+		if !ok {
+			var err error
+			v, err = s.inventory.GetVariantByID(ctx, actor, biz, it.VariantID)
+			if err != nil {
+				return nil, ErrVariantNotFound(it.VariantID, err)
+			}
+			variants[it.VariantID] = v
+		}
+		needed[it.VariantID] += it.Quantity
+		if v.ProductType().TracksStock() && v.StockQuantity < needed[it.VariantID] {
+			return nil, ErrInsufficientStock(v, needed[it.VariantID])
+		}
+		items = append(items, &CreateOrderItemRequest{
+			VariantID: it.VariantID,
+			Quantity:  it.Quantity,
+		})
+	}
+	return &CreateOrderRequest{
+		CustomerID:        ro.CustomerID,
+		Channel:           ro.Channel,
+		ShippingAddressID: ro.ShippingAddressID,
+		ShippingZoneID:    ro.ShippingZoneID,
+		PaymentMethod:     ro.PaymentMethod,
+		Note:              ro.Note,
+		Tags:              ro.Tags,
+		Items:             items,
+	}, nil
+}
+
+// skipRecurringOrderRun moves a recurring order whose due run could not create an order to
+// its next run and keeps the reason for the seller.
+func (s *Service) skipRecurringOrderRun(ctx context.Context, biz *business.Business, id string, today time.Time, reason string) error {
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		ro, err := s.storage.recurringOrder.FindOne(tctx,
+			s.storage.recurringOrder.ScopeBusinessID(biz.ID),
+			s.storage.recurringOrder.ScopeID(id),
+			s.storage.recurringOrder.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return err
+		}
+		if ro.Status != RecurringOrderStatusActive || ro.NextRunDate.After(today) {
+			return nil
+		}
+		ro.LastRunAt = sql.NullTime{Time: time.Now(), Valid: true}
+		ro.LastSkipReason = reason
+		ro.scheduleAfter(today)
+		return s.storage.recurringOrder.UpdateOne(tctx, ro)
+	})
+}
+
+// GenerateRecurringOrdersOptions controls a recurring orders run.
+type GenerateRecurringOrdersOptions struct {
+	// BusinessID limits the run to one business; empty runs every business.
+	BusinessID string
+	// Now is the time of the run (default: now). Recurring orders due on or before its date
+	// are generated.
+	Now time.Time
+}
+
+// GenerateRecurringOrdersResult summarizes a recurring orders run.
+type GenerateRecurringOrdersResult struct {
+	Due     int `json:"due"`
+	Created int `json:"created"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+// GenerateDueRecurringOrders creates the orders of every active recurring order due today.
+// Each recurring order creates at most one order per run, even after missed runs. A run that
+// cannot create its order (an item out of stock, a deleted customer or variant) is skipped
+// and the reason kept on the recurring order; unexpected failures leave it due, so the next
+// run retries it.
+//
+// This is not a multi-tenant scoped function; it should only be used by background jobs.
+func (s *Service) GenerateDueRecurringOrders(ctx context.Context, opts GenerateRecurringOrdersOptions) (*GenerateRecurringOrdersResult, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	today := recurringOrderDate(now)
+	scopes := []func(*gorm.DB) *gorm.DB{
+		s.storage.recurringOrder.ScopeEquals(RecurringOrderSchema.Status, RecurringOrderStatusActive),
+		s.storage.recurringOrder.ScopeWhere(RecurringOrderSchema.NextRunDate.Column()+" <= ?", today),
+		s.storage.recurringOrder.WithOrderBy([]string{RecurringOrderSchema.NextRunDate.Column() + " ASC"}),
+	}
+	if opts.BusinessID != "" {
+		scopes = append(scopes, s.storage.recurringOrder.ScopeBusinessID(opts.BusinessID))
+	}
+	due, err := s.storage.recurringOrder.FindMany(ctx, scopes...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GenerateRecurringOrdersResult{Due: len(due)}
+	businesses := make(map[string]*business.Business)
+	for _, ro := range due {
+		log := logger.FromContext(ctx).With("businessId", ro.BusinessID, "recurringOrderId", ro.ID)
+		biz, ok := businesses[ro.BusinessID]
+		if !ok {
+			biz, err = s.business.GetBusinessByIDForJobs(ctx, ro.BusinessID)
+			if err != nil {
+				log.Error("failed to load business of recurring order", "error", err)
+				result.Failed++
+				continue
+			}
+			businesses[ro.BusinessID] = biz
+		}
+
+		_, created, err := s.runRecurringOrder(ctx, nil, biz, ro.ID, today, false)
+		if err == nil {
+			if created != nil {
+				result.Created++
+			}
+			continue
+		}
+		var p *problem.Problem
+		if errors.As(err, &p) && p.Status < 500 {
+			if err := s.skipRecurringOrderRun(ctx, biz, ro.ID, today, p.Detail); err != nil {
+				log.Error("failed to skip recurring order run", "error", err)
+				result.Failed++
+				continue
+			}
+			log.Warn("skipped recurring order run", "reason", p.Detail)
+			result.Skipped++
+			continue
+		}
+		log.Error("failed to generate recurring order", "error", err)
+		result.Failed++
+	}
+	return result, nil
+}
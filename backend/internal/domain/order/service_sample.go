@@ -0,0 +1,63 @@
+package order
+
+import (
+	"context"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+)
+
+// CreateSampleOrder creates an order of generated sample data. Unlike CreateOrder it is
+// not throttled and does not run the paid-order automation (fee expense, preparation
+// task, print jobs), so removing the sample data leaves nothing behind.
+func (s *Service) CreateSampleOrder(ctx context.Context, actor *account.User, biz *business.Business, req *CreateOrderRequest) (*Order, error) {
+	return s.createOrder(ctx, actor, biz, req, createOrderOptions{})
+}
+
+// ImportOrder records a historical order brought over from another store. The imported stock
+// levels already account for its items, so nothing is taken out of stock, and the order takes
+// its final status and payment status directly. Like CreateSampleOrder it is not throttled and
+// runs no paid-order automation; it emits order.imported so the ledger books the sale and
+// payment it recorded.
+func (s *Service) ImportOrder(ctx context.Context, actor *account.User, biz *business.Business, req *CreateOrderRequest) (*Order, error) {
+	ord, err := s.createOrder(ctx, actor, biz, req, createOrderOptions{historical: true})
+	if err != nil {
+		return nil, err
+	}
+	s.emitImportedEvent(ctx, ord)
+	return ord, nil
+}
+
+// DeleteSampleOrders deletes orders of generated sample data whatever their status,
+// restocking their items. Orders that no longer exist are skipped.
+func (s *Service) DeleteSampleOrders(ctx context.Context, actor *account.User, biz *business.Business, ids []string) error {
+	for _, id := range ids {
+		err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+			ord, err := s.storage.order.FindByID(tctx, id,
+				s.storage.order.ScopeBusinessID(biz.ID),
+				s.storage.order.WithLockingStrength(database.LockingStrengthUpdate),
+			)
+			if err != nil {
+				if database.IsRecordNotFound(err) {
+					return nil
+				}
+				return err
+			}
+			if err := s.storage.orderNote.DeleteMany(tctx, s.storage.orderNote.ScopeEquals(OrderNoteSchema.OrderID, ord.ID)); err != nil {
+				return err
+			}
+			if err := s.storage.orderEvent.DeleteMany(tctx, s.storage.orderEvent.ScopeEquals(OrderEventSchema.OrderID, ord.ID)); err != nil {
+				return err
+			}
+			if err := s.deleteOrderItems(tctx, actor, biz, ord, !ord.ExpiredAt.Valid); err != nil {
+				return err
+			}
+			return s.storage.order.DeleteOne(tctx, ord)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
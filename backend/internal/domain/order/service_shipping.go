@@ -0,0 +1,135 @@
+package order
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+)
+
+// canShip reports whether the order is waiting to be handed to a carrier.
+func (o *Order) canShip() bool {
+	return o.Status == OrderStatusPlaced || o.Status == OrderStatusReadyForShipment
+}
+
+// QuoteOrderShipping returns carrier rates for shipping the order from the business to the
+// order's shipping address.
+func (s *Service) QuoteOrderShipping(ctx context.Context, actor *account.User, biz *business.Business, orderID string, req *OrderShippingRatesRequest) ([]business.CarrierRate, error) {
+	ord, err := s.shippableOrder(ctx, actor, biz, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return s.business.QuoteShippingRates(ctx, biz, req.Carrier, orderShipment(biz, ord, &req.ShipFrom, &req.Parcel))
+}
+
+// PurchaseOrderShippingLabel buys a shipping label for the order and records its tracking
+// number on the order timeline. The label PDF is kept with the label.
+func (s *Service) PurchaseOrderShippingLabel(ctx context.Context, actor *account.User, biz *business.Business, orderID string, req *PurchaseOrderShippingLabelRequest) (*business.ShippingLabel, error) {
+	ord, err := s.shippableOrder(ctx, actor, biz, orderID)
+	if err != nil {
+		return nil, err
+	}
+	label, err := s.business.PurchaseShippingLabel(ctx, biz, ord.ID, req.Carrier, req.Service, orderShipment(biz, ord, &req.ShipFrom, &req.Parcel))
+	if err != nil {
+		return nil, err
+	}
+	err = s.recordOrderEvent(ctx, actor, ord, OrderEventShippingLabelBought,
+		OrderFieldChange{Field: "carrier", To: string(label.Carrier)},
+		OrderFieldChange{Field: "service", To: label.Service},
+		OrderFieldChange{Field: "trackingNumber", To: label.TrackingNumber},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return label, nil
+}
+
+// ListOrderShippingLabels returns the labels bought for the order, newest first.
+func (s *Service) ListOrderShippingLabels(ctx context.Context, actor *account.User, biz *business.Business, orderID string) ([]*business.ShippingLabel, error) {
+	if _, err := s.storage.order.FindByID(ctx, orderID, s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID)); err != nil {
+		return nil, ErrOrderNotFound(orderID, err)
+	}
+	return s.business.ListShippingLabels(ctx, biz, orderID)
+}
+
+// GetOrderShippingLabel returns a label of the order with its PDF.
+func (s *Service) GetOrderShippingLabel(ctx context.Context, actor *account.User, biz *business.Business, orderID, labelID string) (*business.ShippingLabel, error) {
+	return s.business.GetShippingLabel(ctx, biz, orderID, labelID)
+}
+
+func (s *Service) shippableOrder(ctx context.Context, actor *account.User, biz *business.Business, orderID string) (*Order, error) {
+	ord, err := s.GetOrderByID(ctx, actor, biz, orderID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrOrderNotFound(orderID, err)
+		}
+		return nil, err
+	}
+	if !ord.canShip() {
+		return nil, ErrOrderNotShippable(ord.ID, ord.Status)
+	}
+	return ord, nil
+}
+
+// orderShipment describes the order as a single-parcel shipment from the business to the
+// order's shipping address, declaring its items at their sale price.
+func orderShipment(biz *business.Business, ord *Order, from *ShipFromRequest, parcel *ShipmentParcelRequest) *business.Shipment {
+	shipment := &business.Shipment{
+		Reference:   ord.OrderNumber,
+		Description: "Order " + ord.OrderNumber,
+		Value:       ord.Subtotal,
+		Currency:    ord.Currency,
+		ShipDate:    time.Now().UTC(),
+		From: business.ShipmentAddress{
+			Name:        biz.Name,
+			Company:     biz.Name,
+			Phone:       firstNonEmpty(from.Phone, biz.PhoneNumber),
+			Email:       biz.SupportEmail,
+			Line1:       firstNonEmpty(from.AddressLine1, biz.Address),
+			City:        strings.TrimSpace(from.City),
+			PostalCode:  strings.TrimSpace(from.PostalCode),
+			CountryCode: strings.ToUpper(biz.CountryCode),
+		},
+		Parcel: business.ShipmentParcel{
+			WeightKg: parcel.WeightKg,
+			LengthCm: parcel.LengthCm,
+			WidthCm:  parcel.WidthCm,
+			HeightCm: parcel.HeightCm,
+		},
+	}
+	if ord.Customer != nil {
+		shipment.To.Name = ord.Customer.Name
+		shipment.To.Email = ord.Customer.Email.String
+	}
+	if addr := ord.ShippingAddress; addr != nil {
+		shipment.To.Phone = strings.TrimSpace(addr.PhoneCode + addr.PhoneNumber)
+		shipment.To.Line1 = addr.Street.String
+		shipment.To.City = addr.City
+		shipment.To.State = addr.State
+		shipment.To.PostalCode = addr.ZipCode.String
+		shipment.To.CountryCode = strings.ToUpper(addr.CountryCode)
+	}
+	for _, it := range ord.Items {
+		description := "Item"
+		switch {
+		case it.Variant != nil && it.Variant.Name != "":
+			description = it.Variant.Name
+		case it.Product != nil && it.Product.Name != "":
+			description = it.Product.Name
+		}
+		shipment.Items = append(shipment.Items, business.ShipmentItem{Description: description, Quantity: it.Quantity, UnitValue: it.UnitPrice})
+	}
+	return shipment
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
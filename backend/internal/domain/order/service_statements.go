@@ -0,0 +1,164 @@
+package order
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/shopspring/decimal"
+)
+
+type StatementEntryType string
+
+const (
+	StatementEntryOrder   StatementEntryType = "order"
+	StatementEntryPayment StatementEntryType = "payment"
+	StatementEntryRefund  StatementEntryType = "refund"
+	// StatementEntryCredit voids the charge of a cancelled or returned order.
+	StatementEntryCredit StatementEntryType = "credit"
+)
+
+// StatementEntry is one line of a customer statement. Debits increase what the customer owes
+// (orders, refunds paid out); credits decrease it (payments, cancellations, returns).
+type StatementEntry struct {
+	Date        time.Time
+	Type        StatementEntryType
+	OrderID     string
+	OrderNumber string
+	Description string
+	Debit       decimal.Decimal
+	Credit      decimal.Decimal
+	Balance     decimal.Decimal
+}
+
+// CustomerStatement is a customer's account activity over [From, To]. OpeningBalance carries
+// everything before From; ClosingBalance is what the customer owes at To (negative when the
+// business owes the customer).
+type CustomerStatement struct {
+	Customer       *customer.Customer
+	Currency       string
+	From           time.Time
+	To             time.Time
+	OpeningBalance decimal.Decimal
+	TotalOrdered   decimal.Decimal
+	TotalPaid      decimal.Decimal
+	TotalRefunded  decimal.Decimal
+	TotalCredited  decimal.Decimal
+	ClosingBalance decimal.Decimal
+	Entries        []StatementEntry
+}
+
+// GetCustomerStatement builds the statement of a customer from their orders' lifecycle:
+// every order is charged when ordered, credited when paid, and voided when cancelled or
+// returned; refunds are debited back. Cancelled orders that were never paid are left out.
+func (s *Service) GetCustomerStatement(ctx context.Context, actor *account.User, biz *business.Business, customerID string, from, to time.Time) (*CustomerStatement, error) {
+	cust, err := s.customer.GetCustomerByID(ctx, actor, biz, customerID)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := s.storage.order.FindMany(ctx,
+		s.storage.order.ScopeBusinessID(biz.ID),
+		s.storage.order.ScopeEquals(OrderSchema.CustomerID, cust.ID),
+		s.storage.order.ScopeWhere("orders.ordered_at <= ?", to),
+		s.storage.order.WithOrderBy([]string{OrderSchema.OrderedAt.Column()}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	st := &CustomerStatement{
+		Customer:       cust,
+		Currency:       biz.Currency,
+		From:           from,
+		To:             to,
+		OpeningBalance: decimal.Zero,
+		TotalOrdered:   decimal.Zero,
+		TotalPaid:      decimal.Zero,
+		TotalRefunded:  decimal.Zero,
+		TotalCredited:  decimal.Zero,
+		Entries:        []StatementEntry{},
+	}
+	var events []StatementEntry
+	for _, o := range orders {
+		events = append(events, statementEvents(o)...)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Date.Before(events[j].Date) })
+
+	balance := decimal.Zero
+	for _, e := range events {
+		if e.Date.After(to) {
+			continue
+		}
+		balance = balance.Add(e.Debit).Sub(e.Credit)
+		if e.Date.Before(from) {
+			st.OpeningBalance = balance
+			continue
+		}
+		e.Balance = balance
+		switch e.Type {
+		case StatementEntryOrder:
+			st.TotalOrdered = st.TotalOrdered.Add(e.Debit)
+		case StatementEntryPayment:
+			st.TotalPaid = st.TotalPaid.Add(e.Credit)
+		case StatementEntryRefund:
+			st.TotalRefunded = st.TotalRefunded.Add(e.Debit)
+		case StatementEntryCredit:
+			st.TotalCredited = st.TotalCredited.Add(e.Credit)
+		}
+		st.Entries = append(st.Entries, e)
+	}
+	st.ClosingBalance = balance
+	return st, nil
+}
+
+func statementEvents(o *Order) []StatementEntry {
+	if o.Status == OrderStatusCancelled && !o.PaidAt.Valid {
+		return nil
+	}
+	entry := func(date time.Time, typ StatementEntryType, description string) StatementEntry {
+		return StatementEntry{
+			Date:        date,
+			Type:        typ,
+			OrderID:     o.ID,
+			OrderNumber: o.OrderNumber,
+			Description: description,
+			Debit:       decimal.Zero,
+			Credit:      decimal.Zero,
+		}
+	}
+
+	charge := entry(o.OrderedAt, StatementEntryOrder, "Order "+o.OrderNumber)
+	charge.Debit = o.Total
+	events := []StatementEntry{charge}
+	if o.PaidAt.Valid {
+		e := entry(o.PaidAt.Time, StatementEntryPayment, "Payment for order "+o.OrderNumber)
+		e.Credit = o.Total
+		events = append(events, e)
+	}
+	switch o.Status {
+	case OrderStatusCancelled:
+		e := entry(timestampOr(o.CancelledAt.Time, o.CancelledAt.Valid, o.UpdatedAt), StatementEntryCredit, "Order "+o.OrderNumber+" cancelled")
+		e.Credit = o.Total
+		events = append(events, e)
+	case OrderStatusReturned:
+		e := entry(timestampOr(o.ReturnedAt.Time, o.ReturnedAt.Valid, o.UpdatedAt), StatementEntryCredit, "Order "+o.OrderNumber+" returned")
+		e.Credit = o.Total
+		events = append(events, e)
+	}
+	if o.RefundedAt.Valid {
+		e := entry(o.RefundedAt.Time, StatementEntryRefund, "Refund for order "+o.OrderNumber)
+		e.Debit = o.Total
+		events = append(events, e)
+	}
+	return events
+}
+
+func timestampOr(t time.Time, valid bool, fallback time.Time) time.Time {
+	if valid {
+		return t
+	}
+	return fallback
+}
@@ -0,0 +1,121 @@
+package order
+
+import (
+	"context"
+	"slices"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+)
+
+// ListOrderTags returns the tags in use by the business' orders, most used first.
+func (s *Service) ListOrderTags(ctx context.Context, actor *account.User, biz *business.Business) ([]OrderTagCount, error) {
+	return s.storage.CountOrderTags(ctx, biz.ID)
+}
+
+// SetOrderTags replaces the tags of an order.
+func (s *Service) SetOrderTags(ctx context.Context, actor *account.User, biz *business.Business, orderID string, tags []string) (*Order, error) {
+	normalized, err := newOrderTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	var updated *Order
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		ord, err := s.storage.order.FindByID(tctx, orderID,
+			s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID),
+			s.storage.order.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return ErrOrderNotFound(orderID, err)
+		}
+		if err := s.retagOrder(tctx, actor, ord, normalized); err != nil {
+			return err
+		}
+		updated = ord
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetOrderByID(ctx, actor, biz, updated.ID)
+}
+
+// RenameOrderTag renames a tag on every order of the business carrying it. Orders that
+// already carry the new name keep a single copy. It returns the number of orders changed.
+func (s *Service) RenameOrderTag(ctx context.Context, actor *account.User, biz *business.Business, tag, name string) (int, error) {
+	from := normalizeOrderTag(tag)
+	if err := validateOrderTag(from); err != nil {
+		return 0, err
+	}
+	to := normalizeOrderTag(name)
+	if err := validateOrderTag(to); err != nil {
+		return 0, err
+	}
+	return s.updateTaggedOrders(ctx, actor, biz, from, func(tags OrderTags) OrderTags {
+		out := make(OrderTags, 0, len(tags))
+		for _, t := range tags {
+			if t == from {
+				t = to
+			}
+			if !out.Contains(t) {
+				out = append(out, t)
+			}
+		}
+		return out
+	})
+}
+
+// DeleteOrderTag removes a tag from every order of the business carrying it. It returns
+// the number of orders changed.
+func (s *Service) DeleteOrderTag(ctx context.Context, actor *account.User, biz *business.Business, tag string) (int, error) {
+	target := normalizeOrderTag(tag)
+	if err := validateOrderTag(target); err != nil {
+		return 0, err
+	}
+	return s.updateTaggedOrders(ctx, actor, biz, target, func(tags OrderTags) OrderTags {
+		return slices.DeleteFunc(slices.Clone(tags), func(t string) bool { return t == target })
+	})
+}
+
+// updateTaggedOrders rewrites the tags of every order of the business carrying tag, in
+// one transaction, recording each change on the order's timeline.
+func (s *Service) updateTaggedOrders(ctx context.Context, actor *account.User, biz *business.Business, tag string, rewrite func(OrderTags) OrderTags) (int, error) {
+	changed := 0
+	err := s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		changed = 0
+		orders, err := s.storage.order.FindMany(tctx,
+			s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID),
+			s.storage.ScopeTags([]string{tag}),
+			s.storage.order.WithOrderBy([]string{"orders.id"}),
+			s.storage.order.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return err
+		}
+		for _, ord := range orders {
+			if err := s.retagOrder(tctx, actor, ord, rewrite(ord.Tags)); err != nil {
+				return err
+			}
+			changed++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return changed, nil
+}
+
+// retagOrder stores new tags on a locked order and records the change.
+func (s *Service) retagOrder(ctx context.Context, actor *account.User, ord *Order, tags OrderTags) error {
+	if slices.Equal(ord.Tags, tags) {
+		return nil
+	}
+	change := OrderFieldChange{Field: "tags", From: append([]string{}, ord.Tags...), To: append([]string{}, tags...)}
+	ord.Tags = tags
+	if err := s.storage.order.UpdateOne(ctx, ord); err != nil {
+		return err
+	}
+	return s.recordOrderEvent(ctx, actor, ord, OrderEventUpdated, change)
+}
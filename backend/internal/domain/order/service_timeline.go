@@ -0,0 +1,118 @@
+package order
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+)
+
+// recordOrderEvent appends an event to the order's timeline. Call it with the context of
+// the transaction applying the change, so the event is stored only if the change is.
+// A nil actor records a change made by the storefront or a background job.
+func (s *Service) recordOrderEvent(ctx context.Context, actor *account.User, ord *Order, typ OrderEventType, changes ...OrderFieldChange) error {
+	ev := &OrderEvent{
+		BusinessID: ord.BusinessID,
+		OrderID:    ord.ID,
+		Type:       typ,
+		Changes:    OrderFieldChanges(changes),
+	}
+	if actor != nil {
+		ev.ActorID = transformer.ToNullString(actor.ID)
+	}
+	return s.storage.orderEvent.CreateOne(ctx, ev)
+}
+
+// ListOrderTimeline returns the events of an order, oldest first.
+func (s *Service) ListOrderTimeline(ctx context.Context, actor *account.User, biz *business.Business, orderID string) ([]*OrderEvent, error) {
+	if _, err := s.storage.order.FindByID(ctx, orderID, s.storage.order.ScopeWhere("orders.business_id = ?", biz.ID)); err != nil {
+		return nil, ErrOrderNotFound(orderID, err)
+	}
+	return s.storage.orderEvent.FindMany(ctx,
+		s.storage.orderEvent.ScopeBusinessID(biz.ID),
+		s.storage.orderEvent.ScopeEquals(OrderEventSchema.OrderID, orderID),
+		s.storage.orderEvent.WithOrderBy([]string{"created_at ASC", "id ASC"}),
+	)
+}
+
+// OrderItemSnapshot is how an order item appears in the changes of an "updated" event.
+type OrderItemSnapshot struct {
+	VariantID string `json:"variantId"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice string `json:"unitPrice"`
+}
+
+// orderFieldValues returns the editable fields of an order as they appear in timeline
+// changes. Items are only included when loaded.
+func orderFieldValues(ord *Order) []OrderFieldChange {
+	var zoneID any
+	if ord.ShippingZoneID != nil {
+		zoneID = *ord.ShippingZoneID
+	}
+	fields := []OrderFieldChange{
+		{Field: "channel", To: ord.Channel},
+		{Field: "shippingAddressId", To: ord.ShippingAddressID},
+		{Field: "shippingZoneId", To: zoneID},
+		{Field: "shippingFee", To: money.StringFixed(ord.ShippingFee, ord.Currency)},
+		{Field: "discountType", To: string(ord.DiscountType)},
+		{Field: "discountValue", To: ord.DiscountValue.String()},
+		{Field: "discount", To: money.StringFixed(ord.Discount, ord.Currency)},
+		{Field: "orderedAt", To: ord.OrderedAt.UTC().Format(time.RFC3339)},
+		{Field: "subtotal", To: money.StringFixed(ord.Subtotal, ord.Currency)},
+		{Field: "vat", To: money.StringFixed(ord.VAT, ord.Currency)},
+		{Field: "total", To: money.StringFixed(ord.Total, ord.Currency)},
+		{Field: "tags", To: append([]string{}, ord.Tags...)},
+		{Field: "customFields", To: maps.Clone(orderCustomFieldsResponse(ord.CustomFields))},
+	}
+	if ord.Items != nil {
+		items := make([]OrderItemSnapshot, 0, len(ord.Items))
+		for _, it := range ord.Items {
+			items = append(items, OrderItemSnapshot{VariantID: it.VariantID, Quantity: it.Quantity, UnitPrice: money.StringFixed(it.UnitPrice, ord.Currency)})
+		}
+		fields = append(fields, OrderFieldChange{Field: "items", To: items})
+	}
+	return fields
+}
+
+// diffOrderFields returns the fields that differ between two orderFieldValues snapshots.
+func diffOrderFields(before, after []OrderFieldChange) OrderFieldChanges {
+	prev := make(map[string]any, len(before))
+	for _, f := range before {
+		prev[f.Field] = f.To
+	}
+	var changes OrderFieldChanges
+	for _, f := range after {
+		from, ok := prev[f.Field]
+		if ok && reflect.DeepEqual(from, f.To) {
+			continue
+		}
+		changes = append(changes, OrderFieldChange{Field: f.Field, From: from, To: f.To})
+	}
+	return changes
+}
+
+// createdOrderChanges describes the state an order was created in.
+func createdOrderChanges(ord *Order) []OrderFieldChange {
+	return []OrderFieldChange{
+		{Field: "status", To: string(ord.Status)},
+		{Field: "paymentStatus", To: string(ord.PaymentStatus)},
+		{Field: "total", To: money.StringFixed(ord.Total, ord.Currency)},
+	}
+}
+
+// noteChange describes a note added (empty from), edited, or deleted (empty to).
+func noteChange(note *OrderNote, from, to string) OrderFieldChange {
+	c := OrderFieldChange{Field: "note:" + note.ID}
+	if from != "" {
+		c.From = from
+	}
+	if to != "" {
+		c.To = to
+	}
+	return c
+}
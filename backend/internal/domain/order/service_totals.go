@@ -0,0 +1,154 @@
+package order
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const reconcileBatchSize = 200
+
+// OrderTotals holds the derived monetary fields of an order.
+type OrderTotals struct {
+	Subtotal decimal.Decimal `json:"subtotal"`
+	VAT      decimal.Decimal `json:"vat"`
+	COGS     decimal.Decimal `json:"cogs"`
+	Total    decimal.Decimal `json:"total"`
+}
+
+func (t OrderTotals) equal(other OrderTotals) bool {
+	return t.Subtotal.Equal(other.Subtotal) &&
+		t.VAT.Equal(other.VAT) &&
+		t.COGS.Equal(other.COGS) &&
+		t.Total.Equal(other.Total)
+}
+
+// OrderTotalsMismatch describes an order whose stored totals differ from the ones
+// recomputed from its items.
+type OrderTotalsMismatch struct {
+	OrderID     string      `json:"orderId"`
+	OrderNumber string      `json:"orderNumber"`
+	BusinessID  string      `json:"businessId"`
+	Stored      OrderTotals `json:"stored"`
+	Expected    OrderTotals `json:"expected"`
+	Repaired    bool        `json:"repaired"`
+}
+
+// ReconcileOrderTotalsOptions controls which orders are checked and whether drift is repaired.
+type ReconcileOrderTotalsOptions struct {
+	// BusinessID limits the check to one business; empty checks every business.
+	BusinessID string
+	// Since limits the check to orders created at or after this time.
+	Since time.Time
+	// Repair overwrites the stored totals with the recomputed ones.
+	Repair bool
+}
+
+// ReconcileOrderTotalsResult summarizes a reconciliation run.
+type ReconcileOrderTotalsResult struct {
+	Checked    int                   `json:"checked"`
+	Repaired   int                   `json:"repaired"`
+	Mismatches []OrderTotalsMismatch `json:"mismatches"`
+}
+
+// expectedTotals recomputes subtotal, VAT, COGS, and total from the order items using the
+// same rules as order creation. Shipping fee, discount, VAT rate and pricing mode are inputs
+// captured on the order and are taken as stored.
+func (s *Service) expectedTotals(ord *Order) OrderTotals {
+	subtotal := s.calculateSubtotal(ord.Items, ord.Currency)
+	vat := s.calculateVAT(subtotal, ord.VATRate, ord.PricesIncludeVAT, ord.Currency)
+	return OrderTotals{
+		Subtotal: subtotal,
+		VAT:      vat,
+		COGS:     s.calculateCOGS(ord.Items, ord.Currency),
+		Total:    s.calculateTotal(subtotal, vat, ord.ShippingFee, ord.Discount, ord.PricesIncludeVAT, ord.Currency),
+	}
+}
+
+func storedTotals(ord *Order) OrderTotals {
+	return OrderTotals{Subtotal: ord.Subtotal, VAT: ord.VAT, COGS: ord.COGS, Total: ord.Total}
+}
+
+// ReconcileOrderTotals recomputes the totals of recent orders from their items and reports
+// (and optionally repairs) every order whose stored totals have drifted.
+func (s *Service) ReconcileOrderTotals(ctx context.Context, opts ReconcileOrderTotalsOptions) (*ReconcileOrderTotalsResult, error) {
+	result := &ReconcileOrderTotalsResult{Mismatches: []OrderTotalsMismatch{}}
+	lastID := ""
+	for {
+		scopes := []func(db *gorm.DB) *gorm.DB{
+			s.storage.order.ScopeWhere("orders.created_at >= ?", opts.Since),
+			s.storage.order.ScopeWhere("orders.id > ?", lastID),
+			s.storage.order.WithPreload(OrderItemStruct),
+			s.storage.order.WithOrderBy([]string{"orders.id ASC"}),
+			s.storage.order.WithLimit(reconcileBatchSize),
+		}
+		if opts.BusinessID != "" {
+			scopes = append(scopes, s.storage.order.ScopeBusinessID(opts.BusinessID))
+		}
+		batch, err := s.storage.order.FindMany(ctx, scopes...)
+		if err != nil {
+			return nil, err
+		}
+		for _, ord := range batch {
+			result.Checked++
+			expected := s.expectedTotals(ord)
+			stored := storedTotals(ord)
+			if stored.equal(expected) {
+				continue
+			}
+			mismatch := OrderTotalsMismatch{
+				OrderID:     ord.ID,
+				OrderNumber: ord.OrderNumber,
+				BusinessID:  ord.BusinessID,
+				Stored:      stored,
+				Expected:    expected,
+			}
+			slog.Warn("order totals mismatch", "orderId", ord.ID, "businessId", ord.BusinessID,
+				"storedTotal", stored.Total, "expectedTotal", expected.Total)
+			if opts.Repair {
+				if err := s.repairOrderTotals(ctx, ord.ID); err != nil {
+					return nil, err
+				}
+				mismatch.Repaired = true
+				result.Repaired++
+			}
+			result.Mismatches = append(result.Mismatches, mismatch)
+		}
+		if len(batch) < reconcileBatchSize {
+			return result, nil
+		}
+		lastID = batch[len(batch)-1].ID
+	}
+}
+
+// repairOrderTotals reloads the order under a row lock and stores the recomputed totals,
+// so a concurrent edit is never overwritten with stale values.
+func (s *Service) repairOrderTotals(ctx context.Context, orderID string) error {
+	return s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		ord, err := s.storage.order.FindByID(tctx, orderID,
+			s.storage.order.WithPreload(OrderItemStruct),
+			s.storage.order.WithLockingStrength(database.LockingStrengthUpdate),
+		)
+		if err != nil {
+			return ErrOrderNotFound(orderID, err)
+		}
+		before := orderFieldValues(ord)
+		expected := s.expectedTotals(ord)
+		ord.Subtotal = expected.Subtotal
+		ord.VAT = expected.VAT
+		ord.COGS = expected.COGS
+		ord.Total = expected.Total
+		if err := s.storage.order.UpdateOne(tctx, ord); err != nil {
+			return err
+		}
+		changes := diffOrderFields(before, orderFieldValues(ord))
+		if len(changes) == 0 {
+			return nil
+		}
+		return s.recordOrderEvent(tctx, nil, ord, OrderEventTotalsReconciled, changes...)
+	})
+}
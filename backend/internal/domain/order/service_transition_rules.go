@@ -0,0 +1,182 @@
+package order
+
+import (
+	"context"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+)
+
+// ListOrderTransitionRules returns the transition rules of the business, by target status.
+func (s *Service) ListOrderTransitionRules(ctx context.Context, actor *account.User, biz *business.Business) ([]*OrderTransitionRule, error) {
+	return s.storage.transitionRule.FindMany(ctx,
+		s.storage.transitionRule.ScopeBusinessID(biz.ID),
+		s.storage.transitionRule.WithOrderBy([]string{"to_status ASC", "created_at ASC"}),
+	)
+}
+
+// validateOrderTransitionRule checks that the action fits the statuses and that no other rule
+// of the business runs it on the same transitions.
+func (s *Service) validateOrderTransitionRule(ctx context.Context, rule *OrderTransitionRule) error {
+	if !rule.Action.allowsStatus(rule.ToStatus) {
+		return ErrInvalidOrderTransitionRule("toStatus", string(rule.Action)+" rules cannot run on "+string(rule.ToStatus)+" orders")
+	}
+	if rule.FromStatus.Valid {
+		from := &Order{Status: OrderStatus(rule.FromStatus.String)}
+		if !newOrderStateMachine(from).canTransitionStateTo(rule.ToStatus) {
+			return ErrInvalidOrderTransitionRule("fromStatus", "orders cannot move from "+rule.FromStatus.String+" to "+string(rule.ToStatus))
+		}
+	}
+	if rule.Action == OrderTransitionActionNotifyCustomer && strings.TrimSpace(rule.Message.String) == "" {
+		return ErrInvalidOrderTransitionRule("message", "required for notify_customer rules")
+	}
+	others, err := s.storage.transitionRule.FindMany(ctx,
+		s.storage.transitionRule.ScopeBusinessID(rule.BusinessID),
+		s.storage.transitionRule.ScopeEquals(OrderTransitionRuleSchema.ToStatus, rule.ToStatus),
+	)
+	if err != nil {
+		return err
+	}
+	for _, other := range others {
+		if other.ID != rule.ID && rule.sameTrigger(other) {
+			return ErrOrderTransitionRuleExists(other.ID)
+		}
+	}
+	return nil
+}
+
+func (s *Service) CreateOrderTransitionRule(ctx context.Context, actor *account.User, biz *business.Business, req *CreateOrderTransitionRuleRequest) (*OrderTransitionRule, error) {
+	rule := &OrderTransitionRule{
+		BusinessID: biz.ID,
+		FromStatus: transformer.ToNullString(string(req.FromStatus)),
+		ToStatus:   req.ToStatus,
+		Action:     req.Action,
+		Message:    transformer.ToNullString(strings.TrimSpace(req.Message)),
+		Enabled:    req.Enabled == nil || *req.Enabled,
+	}
+	if err := s.validateOrderTransitionRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	if err := s.storage.transitionRule.CreateOne(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *Service) UpdateOrderTransitionRule(ctx context.Context, actor *account.User, biz *business.Business, ruleID string, req *UpdateOrderTransitionRuleRequest) (*OrderTransitionRule, error) {
+	rule, err := s.storage.transitionRule.FindByID(ctx, ruleID, s.storage.transitionRule.ScopeBusinessID(biz.ID))
+	if err != nil {
+		return nil, ErrOrderTransitionRuleNotFound(ruleID, err)
+	}
+	if req.Message != nil {
+		rule.Message = transformer.ToNullString(strings.TrimSpace(*req.Message))
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	if err := s.validateOrderTransitionRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	if err := s.storage.transitionRule.UpdateOne(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *Service) DeleteOrderTransitionRule(ctx context.Context, actor *account.User, biz *business.Business, ruleID string) error {
+	rule, err := s.storage.transitionRule.FindByID(ctx, ruleID, s.storage.transitionRule.ScopeBusinessID(biz.ID))
+	if err != nil {
+		return ErrOrderTransitionRuleNotFound(ruleID, err)
+	}
+	return s.storage.transitionRule.DeleteOne(ctx, rule)
+}
+
+// transitionOutcome is what a status change did, including the side effects of the
+// business' transition rules.
+type transitionOutcome struct {
+	from            OrderStatus
+	actions         []string
+	markedPaid      bool
+	prevPayment     OrderPaymentStatus
+	customerMessage string
+	customerPhone   string
+}
+
+// changes are the timeline field changes of the status change.
+func (o *transitionOutcome) changes(ord *Order) []OrderFieldChange {
+	changes := []OrderFieldChange{{Field: "status", From: string(o.from), To: string(ord.Status)}}
+	if len(o.actions) > 0 {
+		changes = append(changes, OrderFieldChange{Field: "rules", To: o.actions})
+	}
+	return changes
+}
+
+// transitionOrder moves the order to status under the transition rules of the business:
+// require_payment rules block the change of unpaid orders, mark_paid rules mark the order
+// paid and notify_customer rules render their message for the status changed event. Orders
+// whose recorded payments already cover the total, such as placed orders paid by deposits
+// while pending, are settled before the rules run. It must run in the transaction that
+// saves the order.
+func (s *Service) transitionOrder(ctx context.Context, actor *account.User, biz *business.Business, ord *Order, status OrderStatus) (*transitionOutcome, error) {
+	out := &transitionOutcome{from: ord.Status, prevPayment: ord.PaymentStatus}
+	rules, err := s.storage.transitionRule.FindMany(ctx,
+		s.storage.transitionRule.ScopeBusinessID(biz.ID),
+		s.storage.transitionRule.ScopeEquals(OrderTransitionRuleSchema.ToStatus, status),
+		s.storage.transitionRule.ScopeEquals(OrderTransitionRuleSchema.Enabled, true),
+		s.storage.transitionRule.WithOrderBy([]string{"created_at ASC"}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	sm := newOrderStateMachine(ord)
+	if err := sm.transitionStateTo(status); err != nil {
+		return nil, err
+	}
+	if out.markedPaid, err = s.settlePayments(ctx, ord, nil); err != nil {
+		return nil, err
+	}
+
+	var messages []string
+	for _, rule := range rules {
+		if !rule.matches(out.from, status) {
+			continue
+		}
+		switch rule.Action {
+		case OrderTransitionActionRequirePayment:
+			if ord.PaymentStatus != OrderPaymentStatusPaid {
+				return nil, ErrOrderTransitionRequiresPayment(ord.ID, status, rule.ID)
+			}
+		case OrderTransitionActionMarkPaid:
+			// Paid and refunded orders are left alone; failed payments go back through pending.
+			if ord.PaymentStatus == OrderPaymentStatusFailed {
+				if err := sm.transitionPaymentStatusTo(OrderPaymentStatusPending); err != nil {
+					return nil, err
+				}
+			}
+			if ord.PaymentStatus != OrderPaymentStatusPending {
+				continue
+			}
+			if err := sm.transitionPaymentStatusTo(OrderPaymentStatusPaid); err != nil {
+				return nil, err
+			}
+			out.markedPaid = true
+		case OrderTransitionActionNotifyCustomer:
+			// A deleted customer still gets the message, without a name or number.
+			var name string
+			cus, err := s.customer.GetCustomerByID(ctx, actor, biz, ord.CustomerID)
+			switch {
+			case err == nil:
+				name, out.customerPhone = cus.Name, cus.WhatsappNumber.String
+			case !database.IsRecordNotFound(err):
+				return nil, err
+			}
+			messages = append(messages, rule.renderMessage(ord, name))
+		}
+		out.actions = append(out.actions, string(rule.Action))
+	}
+	out.customerMessage = strings.Join(messages, "\n\n")
+	return out, nil
+}
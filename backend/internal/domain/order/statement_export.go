@@ -0,0 +1,142 @@
+package order
+
+import (
+	"bytes"
+	"encoding/csv"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/pdf"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	stmtTypeColumn   = 120.0
+	stmtDebitColumn  = 400.0
+	stmtCreditColumn = 470.0
+	stmtDescMaxWidth = 190.0
+)
+
+// RenderCustomerStatementCSV renders a statement as CSV, framed by opening and closing balance rows.
+func RenderCustomerStatementCSV(biz *business.Business, st *CustomerStatement) []byte {
+	loc := biz.Location()
+	amount := func(d decimal.Decimal) string {
+		if d.IsZero() {
+			return ""
+		}
+		return money.StringFixed(d, st.Currency)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"date", "type", "orderNumber", "description", "debit", "credit", "balance", "currency"})
+	_ = w.Write([]string{st.From.In(loc).Format(statementDateLayout), "opening_balance", "", "Opening balance", "", "", money.StringFixed(st.OpeningBalance, st.Currency), st.Currency})
+	for _, e := range st.Entries {
+		_ = w.Write([]string{
+			e.Date.In(loc).Format(statementDateLayout),
+			string(e.Type),
+			e.OrderNumber,
+			e.Description,
+			amount(e.Debit),
+			amount(e.Credit),
+			money.StringFixed(e.Balance, st.Currency),
+			st.Currency,
+		})
+	}
+	_ = w.Write([]string{st.To.In(loc).Format(statementDateLayout), "closing_balance", "", "Closing balance", "", "", money.StringFixed(st.ClosingBalance, st.Currency), st.Currency})
+	w.Flush()
+	return buf.Bytes()
+}
+
+// RenderCustomerStatementPDF renders a statement as an A4 PDF.
+func RenderCustomerStatementPDF(biz *business.Business, st *CustomerStatement) []byte {
+	doc := pdf.New("Statement " + st.Customer.Name)
+	loc := biz.Location()
+
+	y := 60.0
+	doc.Text(pdfMarginX, y, pdf.Bold, 18, pdf.AlignLeft, biz.Name)
+	doc.Text(pdfRight, y, pdf.Bold, 18, pdf.AlignRight, "STATEMENT")
+	y += 18
+	for _, line := range businessContactLines(biz) {
+		doc.Text(pdfMarginX, y, pdf.Regular, 9, pdf.AlignLeft, line)
+		y += 12
+	}
+
+	metaY := 78.0
+	for _, kv := range [][2]string{
+		{"From", st.From.In(loc).Format(pdfDateLayout)},
+		{"To", st.To.In(loc).Format(pdfDateLayout)},
+		{"Balance due", formatMoney(st.ClosingBalance, st.Currency)},
+	} {
+		doc.Text(pdfRight-110, metaY, pdf.Bold, 9, pdf.AlignRight, kv[0])
+		doc.Text(pdfRight, metaY, pdf.Regular, 9, pdf.AlignRight, kv[1])
+		metaY += 12
+	}
+
+	y = max(y, metaY) + 16
+	doc.Text(pdfMarginX, y, pdf.Bold, 10, pdf.AlignLeft, "Statement for")
+	y += 14
+	for _, line := range customerLines(st.Customer, nil) {
+		doc.Text(pdfMarginX, y, pdf.Regular, 10, pdf.AlignLeft, line)
+		y += 13
+	}
+
+	y += 16
+	y = drawStatementHeader(doc, y)
+	doc.Text(pdfMarginX+6, y, pdf.Regular, 10, pdf.AlignLeft, st.From.In(loc).Format(pdfDateLayout))
+	doc.Text(stmtTypeColumn, y, pdf.Bold, 10, pdf.AlignLeft, "Opening balance")
+	doc.Text(pdfRight-6, y, pdf.Regular, 10, pdf.AlignRight, formatMoney(st.OpeningBalance, st.Currency))
+	y += 18
+	for _, e := range st.Entries {
+		if y > pdfBottomLimit {
+			doc.AddPage()
+			y = drawStatementHeader(doc, 60)
+		}
+		doc.Text(pdfMarginX+6, y, pdf.Regular, 10, pdf.AlignLeft, e.Date.In(loc).Format(pdfDateLayout))
+		doc.Text(stmtTypeColumn, y, pdf.Regular, 10, pdf.AlignLeft, pdf.Truncate(e.Description, pdf.Regular, 10, stmtDescMaxWidth))
+		if !e.Debit.IsZero() {
+			doc.Text(stmtDebitColumn, y, pdf.Regular, 10, pdf.AlignRight, money.StringFixed(e.Debit, st.Currency))
+		}
+		if !e.Credit.IsZero() {
+			doc.Text(stmtCreditColumn, y, pdf.Regular, 10, pdf.AlignRight, money.StringFixed(e.Credit, st.Currency))
+		}
+		doc.Text(pdfRight-6, y, pdf.Regular, 10, pdf.AlignRight, money.StringFixed(e.Balance, st.Currency))
+		y += 18
+	}
+	doc.Line(pdfMarginX, y-8, pdfRight, y-8)
+
+	totals := [][2]string{
+		{"Orders", formatMoney(st.TotalOrdered, st.Currency)},
+		{"Payments", "-" + formatMoney(st.TotalPaid, st.Currency)},
+	}
+	if st.TotalCredited.IsPositive() {
+		totals = append(totals, [2]string{"Cancellations & returns", "-" + formatMoney(st.TotalCredited, st.Currency)})
+	}
+	if st.TotalRefunded.IsPositive() {
+		totals = append(totals, [2]string{"Refunds", formatMoney(st.TotalRefunded, st.Currency)})
+	}
+	if y+float64(len(totals)+1)*16 > pdfBottomLimit {
+		doc.AddPage()
+		y = 60
+	}
+	y += 6
+	for _, kv := range totals {
+		doc.Text(stmtCreditColumn, y, pdf.Regular, 10, pdf.AlignRight, kv[0])
+		doc.Text(pdfRight-6, y, pdf.Regular, 10, pdf.AlignRight, kv[1])
+		y += 16
+	}
+	doc.Text(stmtCreditColumn, y+2, pdf.Bold, 12, pdf.AlignRight, "Balance due")
+	doc.Text(pdfRight-6, y+2, pdf.Bold, 12, pdf.AlignRight, formatMoney(st.ClosingBalance, st.Currency))
+
+	return doc.Bytes()
+}
+
+func drawStatementHeader(doc *pdf.Document, y float64) float64 {
+	doc.FillRect(pdfMarginX, y-12, pdfRight-pdfMarginX, 18, 0.93)
+	doc.Text(pdfMarginX+6, y, pdf.Bold, 9, pdf.AlignLeft, "Date")
+	doc.Text(stmtTypeColumn, y, pdf.Bold, 9, pdf.AlignLeft, "Description")
+	doc.Text(stmtDebitColumn, y, pdf.Bold, 9, pdf.AlignRight, "Debit")
+	doc.Text(stmtCreditColumn, y, pdf.Bold, 9, pdf.AlignRight, "Credit")
+	doc.Text(pdfRight-6, y, pdf.Bold, 9, pdf.AlignRight, "Balance")
+	return y + 22
+}
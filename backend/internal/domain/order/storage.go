@@ -1,55 +1,129 @@
 package order
 
 import (
+	"context"
 	"strings"
+	"time"
 
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
 	"github.com/abdelrahman146/kyora/internal/platform/cache"
 	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
 type Storage struct {
+	db        *database.Database
 	cache     *cache.Cache
 	order     *database.Repository[Order]
 	orderItem *database.Repository[OrderItem]
 	orderNote *database.Repository[OrderNote]
+	quote     *database.Repository[Quote]
+	quoteItem *database.Repository[QuoteItem]
+	coupon    *database.Repository[Coupon]
+
+	couponRedemption *database.Repository[CouponRedemption]
+
+	printStation *database.Repository[PrintStation]
+	printJob     *database.Repository[PrintJob]
+
+	orderExport     *database.Repository[OrderExport]
+	orderExportFile *database.Repository[OrderExportFile]
+
+	orderEvent *database.Repository[OrderEvent]
+
+	recurringOrder *database.Repository[RecurringOrder]
+
+	orderPayment *database.Repository[OrderPayment]
+
+	transitionRule *database.Repository[OrderTransitionRule]
 }
 
 func NewStorage(db *database.Database, cache *cache.Cache) *Storage {
 	st := &Storage{
+		db:        db,
 		cache:     cache,
-		order:     database.NewRepository[Order](db),
-		orderItem: database.NewRepository[OrderItem](db),
+		order:     database.NewRepository[Order](db).PartitionedBy(OrderSchema.BusinessID),
+		orderItem: database.NewRepository[OrderItem](db).PartitionedBy(OrderItemSchema.OrderID),
 		orderNote: database.NewRepository[OrderNote](db),
+		quote:     database.NewRepository[Quote](db),
+		quoteItem: database.NewRepository[QuoteItem](db),
+		coupon:    database.NewRepository[Coupon](db),
+
+		couponRedemption: database.NewRepository[CouponRedemption](db),
+
+		printStation: database.NewRepository[PrintStation](db),
+		printJob:     database.NewRepository[PrintJob](db),
+
+		orderExport:     database.NewRepository[OrderExport](db),
+		orderExportFile: database.NewRepository[OrderExportFile](db),
+
+		orderEvent: database.NewRepository[OrderEvent](db),
+
+		recurringOrder: database.NewRepository[RecurringOrder](db),
+
+		orderPayment: database.NewRepository[OrderPayment](db),
+
+		transitionRule: database.NewRepository[OrderTransitionRule](db),
 	}
 	ensureOrderSearchIndexes(db)
+	database.EnsureIndexes(db, Indexes...)
 	return st
 }
 
-func ensureOrderSearchIndexes(db *database.Database) {
-	conn := db.GetDB()
+// Indexes are the secondary indexes the order queries rely on.
+// Analytics and list endpoints always filter by business and an ordered_at range,
+// and order details load items by order_id. Print stations poll their queued jobs, the
+// recurring orders command looks up active templates that are due and delivery slots count
+// the orders booked on a date.
+var Indexes = []database.Index{
+	{Name: "idx_orders_business_id_ordered_at", Table: OrderTable, Columns: []string{OrderSchema.BusinessID.Column(), OrderSchema.OrderedAt.Column()}},
+	{Name: "idx_orders_business_id_delivery_date", Table: OrderTable, Columns: []string{OrderSchema.BusinessID.Column(), OrderSchema.DeliveryDate.Column()}},
+	{Name: "idx_orders_tags", Table: OrderTable, Columns: []string{OrderSchema.Tags.Column()}, Using: "gin"},
+	{Name: "idx_order_items_order_id", Table: OrderItemTable, Columns: []string{OrderItemSchema.OrderID.Column()}},
+	{Name: "idx_quotes_business_id_status_valid_until", Table: QuoteTable, Columns: []string{QuoteSchema.BusinessID.Column(), QuoteSchema.Status.Column(), QuoteSchema.ValidUntil.Column()}},
+	{Name: "idx_recurring_orders_status_next_run_date", Table: RecurringOrderTable, Columns: []string{RecurringOrderSchema.Status.Column(), RecurringOrderSchema.NextRunDate.Column()}},
+	{Name: "idx_print_jobs_station_id_status_created_at", Table: PrintJobTable, Columns: []string{PrintJobSchema.StationID.Column(), PrintJobSchema.Status.Column(), PrintJobSchema.CreatedAt.Column()}},
+}
+
+// Partitionings are the hash partitionings the db-partitions command applies to the order
+// tables of large deployments. Orders are split by business, so every business-scoped
+// query reads a single partition; items are split by order, matching how they are loaded.
+var Partitionings = []database.Partitioning{
+	{Table: OrderTable, Column: OrderSchema.BusinessID.Column(), Partitions: 16},
+	{Table: OrderItemTable, Column: OrderItemSchema.OrderID.Column(), Partitions: 16},
+}
 
-	expr := "" +
-		"setweight(to_tsvector('simple', coalesce(order_number,'')), 'A') || " +
-		"setweight(to_tsvector('simple', coalesce(channel,'')), 'B') || " +
-		"setweight(to_tsvector('simple', coalesce(currency,'')), 'C') || " +
-		"setweight(to_tsvector('simple', coalesce(payment_reference,'')), 'C')"
+func ensureOrderSearchIndexes(db *database.Database) {
+	for _, conn := range db.Conns() {
+		expr := "" +
+			"setweight(to_tsvector('simple', coalesce(order_number,'')), 'A') || " +
+			"setweight(to_tsvector('simple', coalesce(channel,'')), 'B') || " +
+			"setweight(to_tsvector('simple', coalesce(currency,'')), 'C') || " +
+			"setweight(to_tsvector('simple', coalesce(payment_reference,'')), 'C')"
 
-	database.EnsureGeneratedTSVectorColumn(conn, OrderTable, "search_vector", expr)
-	database.EnsureGinIndex(conn, "orders_search_vector_gin_idx", OrderTable, "search_vector")
+		database.EnsureGeneratedTSVectorColumn(conn, OrderTable, "search_vector", expr)
+		database.EnsureGinIndex(conn, "orders_search_vector_gin_idx", OrderTable, "search_vector")
 
-	// Trigram index for fast substring lookup on order numbers.
-	database.EnsureTrigramGinIndex(conn, "orders_order_number_trgm_idx", OrderTable, "order_number")
+		// Trigram index for fast substring lookup on order numbers.
+		database.EnsureTrigramGinIndex(conn, "orders_order_number_trgm_idx", OrderTable, "order_number")
+	}
 }
 
 // ScopeOrderSearch adds search conditions for orders, including customer search vector.
 // Requires a LEFT JOIN on customers table to be called before this scope.
+// Customer names are also matched fuzzily (typos, accents, Arabic spelling variants).
 func (s *Storage) ScopeOrderSearch(term string) func(*gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
+		fuzzy, fuzzyVars, err := database.FuzzyMatch(term, "customers.name")
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
 		like := "%" + term + "%"
 		return db.Where(
-			"(orders.search_vector @@ websearch_to_tsquery('simple', ?) OR customers.search_vector @@ websearch_to_tsquery('simple', ?) OR orders.order_number ILIKE ? OR customers.name ILIKE ? OR customers.email ILIKE ?)",
-			term, term, like, like, like,
+			"(orders.search_vector @@ websearch_to_tsquery('simple', ?) OR customers.search_vector @@ websearch_to_tsquery('simple', ?) OR orders.order_number ILIKE ? OR customers.name ILIKE ? OR customers.email ILIKE ? OR "+fuzzy+")",
+			append([]any{term, term, like, like, like}, fuzzyVars...)...,
 		)
 	}
 }
@@ -79,7 +153,258 @@ func (s *Storage) ScopeChannels(channels []string) func(*gorm.DB) *gorm.DB {
 	}
 }
 
+// ScopeTags filters orders carrying all of the given tags. Tags are normalized like
+// stored ones, so the filter is case-insensitive.
+func (s *Storage) ScopeTags(tags []string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		normalized := OrderTags{}
+		for _, t := range tags {
+			if tag := normalizeOrderTag(t); tag != "" && !normalized.Contains(tag) {
+				normalized = append(normalized, tag)
+			}
+		}
+		if len(normalized) == 0 {
+			return db
+		}
+		value, err := normalized.Value()
+		if err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		return db.Where("orders.tags @> ?::jsonb", value)
+	}
+}
+
 // WithOrderCustomerJoin adds a LEFT JOIN with customers table for search.
 func (s *Storage) WithOrderCustomerJoin() func(*gorm.DB) *gorm.DB {
 	return s.order.WithJoins("LEFT JOIN customers ON customers.id = orders.customer_id")
 }
+
+// ProductSales is what a product sold over a period, from order item totals
+// (before order-level discounts, shipping and VAT).
+type ProductSales struct {
+	ProductID string
+	Quantity  int64
+	Revenue   decimal.Decimal
+	COGS      decimal.Decimal
+}
+
+// SumItemsByProduct aggregates order items per product for orders of the business placed within
+// [from, to], or paid within it when byPaidAt is set.
+func (s *Storage) SumItemsByProduct(ctx context.Context, businessID string, byPaidAt bool, from, to time.Time) ([]ProductSales, error) {
+	q := s.db.Conn(ctx).
+		Table(OrderItemTable).
+		Select("order_items.product_id AS product_id, COALESCE(SUM(order_items.quantity), 0) AS quantity, COALESCE(SUM(order_items.total), 0) AS revenue, COALESCE(SUM(order_items.total_cost), 0) AS cogs").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("orders.business_id = ?", businessID).
+		Where("order_items.deleted_at IS NULL")
+	date := "orders.ordered_at"
+	if byPaidAt {
+		date = "orders.paid_at"
+		q = q.Where("orders.paid_at IS NOT NULL")
+	}
+	if !from.IsZero() {
+		q = q.Where(date+" >= ?", from)
+	}
+	if !to.IsZero() {
+		q = q.Where(date+" <= ?", to)
+	}
+	var rows []ProductSales
+	if err := q.Group("order_items.product_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// OrderTagCount is a tag and the number of orders carrying it.
+type OrderTagCount struct {
+	Tag    string
+	Orders int64
+}
+
+// CountOrderTags returns the tags used by the business' orders with their order counts,
+// most used first.
+func (s *Storage) CountOrderTags(ctx context.Context, businessID string) ([]OrderTagCount, error) {
+	var rows []OrderTagCount
+	err := s.db.Conn(ctx).
+		Table(OrderTable).
+		Select("t.tag AS tag, COUNT(*) AS orders").
+		Joins("CROSS JOIN LATERAL jsonb_array_elements_text(orders.tags) AS t(tag)").
+		Where("orders.business_id = ?", businessID).
+		Where("orders.deleted_at IS NULL").
+		Group("t.tag").
+		Order("orders DESC, t.tag").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// OrderProductRevenue is the item total of one product within one order.
+type OrderProductRevenue struct {
+	OrderID   string
+	ProductID string
+	Revenue   decimal.Decimal
+}
+
+// SumItemRevenueByOrderProduct returns item totals per (order, product) for the given orders of the business.
+func (s *Storage) SumItemRevenueByOrderProduct(ctx context.Context, businessID string, orderIDs []string) ([]OrderProductRevenue, error) {
+	if len(orderIDs) == 0 {
+		return nil, nil
+	}
+	var rows []OrderProductRevenue
+	err := s.db.Conn(ctx).
+		Table(OrderItemTable).
+		Select("order_items.order_id AS order_id, order_items.product_id AS product_id, COALESCE(SUM(order_items.total), 0) AS revenue").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("orders.business_id = ?", businessID).
+		Where("order_items.order_id IN ?", orderIDs).
+		Where("order_items.deleted_at IS NULL").
+		Group("order_items.order_id, order_items.product_id").
+		Order("order_items.order_id, order_items.product_id").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// ExpireSentQuotes marks sent quotes of the business whose validity ended before now as expired.
+func (s *Storage) ExpireSentQuotes(ctx context.Context, businessID string, now time.Time) error {
+	return s.db.Conn(ctx).
+		Model(&Quote{}).
+		Where("business_id = ? AND status = ? AND valid_until < ?", businessID, QuoteStatusSent, now).
+		Updates(map[string]any{
+			QuoteSchema.Status.Column():    QuoteStatusExpired,
+			QuoteSchema.ExpiredAt.Column(): now,
+		}).Error
+}
+
+// PurgeCustomerOrderNotes permanently deletes the notes of a customer's orders, including
+// deleted ones, and clears the note contents recorded on the orders' timelines.
+func (s *Storage) PurgeCustomerOrderNotes(ctx context.Context, businessID, customerID string) error {
+	orderIDs := s.db.Conn(ctx).
+		Unscoped().
+		Model(&Order{}).
+		Select(OrderSchema.ID.Column()).
+		Where(OrderSchema.BusinessID.Column()+" = ? AND "+OrderSchema.CustomerID.Column()+" = ?", businessID, customerID)
+	if err := s.db.Conn(ctx).
+		Unscoped().
+		Where(OrderNoteSchema.OrderID.Column()+" IN (?)", orderIDs).
+		Delete(&OrderNote{}).Error; err != nil {
+		return err
+	}
+	return s.db.Conn(ctx).
+		Model(&OrderEvent{}).
+		Where(OrderEventSchema.OrderID.Column()+" IN (?) AND "+OrderEventSchema.Type.Column()+" IN ?", orderIDs, []OrderEventType{OrderEventNoteAdded, OrderEventNoteUpdated, OrderEventNoteDeleted}).
+		Update("changes", OrderFieldChanges{}).Error
+}
+
+// CustomerProductPurchase is how much of a product a customer bought across their orders.
+type CustomerProductPurchase struct {
+	ProductID   string
+	ProductName string
+	Quantity    int64
+	Orders      int64
+	Revenue     decimal.Decimal
+}
+
+// TopCustomerProducts returns the products a customer bought most, by quantity, leaving out
+// orders in the excluded statuses.
+func (s *Storage) TopCustomerProducts(ctx context.Context, businessID, customerID string, excluded []any, limit int) ([]CustomerProductPurchase, error) {
+	var rows []CustomerProductPurchase
+	err := s.db.Conn(ctx).
+		Table(OrderItemTable).
+		Select("order_items.product_id AS product_id, COALESCE(MAX(products.name), '') AS product_name, COALESCE(SUM(order_items.quantity), 0) AS quantity, COUNT(DISTINCT order_items.order_id) AS orders, COALESCE(SUM(order_items.total), 0) AS revenue").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Joins("LEFT JOIN products ON products.id = order_items.product_id").
+		Where("orders.business_id = ?", businessID).
+		Where("orders.customer_id = ?", customerID).
+		Where("orders.status NOT IN ?", excluded).
+		Where("order_items.deleted_at IS NULL").
+		Group("order_items.product_id").
+		Order("quantity DESC, revenue DESC, order_items.product_id").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// customerTimelineColumns are the columns every customer timeline source selects, in this order.
+const customerTimelineColumns = "type, occurred_at, ref_id, order_id, order_number, amount, currency, detail"
+
+// CustomerTimelineRow is one entry of a customer's activity timeline. Columns a source does not
+// have are left empty.
+type CustomerTimelineRow struct {
+	Type        string
+	OccurredAt  time.Time
+	RefID       string
+	OrderID     *string
+	OrderNumber *string
+	Amount      decimal.NullDecimal
+	Currency    *string
+	Detail      *string
+}
+
+// customerTimelineSources returns one query per kind of customer activity, each selecting
+// customerTimelineColumns.
+func (s *Storage) customerTimelineSources(ctx context.Context, businessID, customerID string) []*gorm.DB {
+	customerOrders := func(table string) func(*gorm.DB) *gorm.DB {
+		return func(db *gorm.DB) *gorm.DB {
+			return db.Joins("JOIN orders ON orders.id = "+table+".order_id AND orders.deleted_at IS NULL").
+				Where("orders.business_id = ? AND orders.customer_id = ?", businessID, customerID)
+		}
+	}
+	return []*gorm.DB{
+		s.db.Conn(ctx).Table(OrderTable).
+			Select("'order_placed' AS type, orders.ordered_at AS occurred_at, orders.id AS ref_id, orders.id AS order_id, orders.order_number, orders.total AS amount, orders.currency, orders.channel AS detail").
+			Where("orders.business_id = ? AND orders.customer_id = ? AND orders.deleted_at IS NULL", businessID, customerID),
+		s.db.Conn(ctx).Table(OrderEventTable).
+			Select("'order_status_changed' AS type, order_events.created_at AS occurred_at, order_events.id AS ref_id, orders.id AS order_id, orders.order_number, NULL::numeric AS amount, NULL AS currency, "+
+				"(SELECT c->>'to' FROM jsonb_array_elements(order_events.changes) AS c WHERE c->>'field' = 'status' LIMIT 1) AS detail").
+			Scopes(customerOrders(OrderEventTable)).
+			Where("order_events.type = ?", OrderEventStatusChanged),
+		s.db.Conn(ctx).Table(OrderPaymentTable).
+			Select("'payment_recorded' AS type, order_payments.paid_at AS occurred_at, order_payments.id AS ref_id, orders.id AS order_id, orders.order_number, order_payments.amount, order_payments.currency, order_payments.method AS detail").
+			Scopes(customerOrders(OrderPaymentTable)).
+			Where("order_payments.deleted_at IS NULL"),
+		s.db.Conn(ctx).Table(customer.CustomerNoteTable).
+			Select("'note_added' AS type, customer_notes.created_at AS occurred_at, customer_notes.id AS ref_id, NULL AS order_id, NULL AS order_number, NULL::numeric AS amount, NULL AS currency, customer_notes.content AS detail").
+			Where("customer_notes.customer_id = ? AND customer_notes.deleted_at IS NULL", customerID),
+		s.customerAddressTimelineSource(ctx, customerID, "address_added", "customer_addresses.created_at", "TRUE"),
+		// Only the latest edit of an address is known: its updated_at.
+		s.customerAddressTimelineSource(ctx, customerID, "address_updated", "customer_addresses.updated_at",
+			"customer_addresses.deleted_at IS NULL AND customer_addresses.updated_at > customer_addresses.created_at + interval '1 second'"),
+		s.customerAddressTimelineSource(ctx, customerID, "address_removed", "customer_addresses.deleted_at", "customer_addresses.deleted_at IS NOT NULL"),
+	}
+}
+
+func (s *Storage) customerAddressTimelineSource(ctx context.Context, customerID, typ, occurredAt, cond string) *gorm.DB {
+	return s.db.Conn(ctx).Table(customer.CustomerAddressTable).
+		Select("'"+typ+"' AS type, "+occurredAt+" AS occurred_at, customer_addresses.id AS ref_id, NULL AS order_id, NULL AS order_number, NULL::numeric AS amount, NULL AS currency, "+
+			"concat_ws(', ', NULLIF(customer_addresses.city, ''), customer_addresses.country_code) AS detail").
+		Where("customer_addresses.customer_id = ? AND "+cond, customerID)
+}
+
+// ListCustomerTimeline returns a page of a customer's activity across orders, order status
+// changes, payments, notes and addresses, newest first, with the total number of entries.
+func (s *Storage) ListCustomerTimeline(ctx context.Context, businessID, customerID string, offset, limit int) ([]CustomerTimelineRow, int64, error) {
+	sources := s.customerTimelineSources(ctx, businessID, customerID)
+	parts := make([]string, 0, len(sources))
+	args := make([]any, 0, len(sources)+2)
+	for _, q := range sources {
+		parts = append(parts, "?")
+		args = append(args, q)
+	}
+	union := strings.Join(parts, " UNION ALL ")
+
+	var total int64
+	if err := s.db.Conn(ctx).Raw("SELECT COUNT(*) FROM ("+union+") AS timeline", args...).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []CustomerTimelineRow
+	err := s.db.Conn(ctx).
+		Raw("SELECT "+customerTimelineColumns+" FROM ("+union+") AS timeline ORDER BY occurred_at DESC, ref_id DESC, type LIMIT ? OFFSET ?", append(args, limit, offset)...).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
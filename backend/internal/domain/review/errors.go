@@ -0,0 +1,35 @@
+package review
+
+import "github.com/abdelrahman146/kyora/internal/platform/types/problem"
+
+func ErrReviewNotFound(id string, err error) error {
+	return problem.NotFound("review not found").
+		With("reviewId", id).
+		WithError(err).
+		WithCode("review.not_found")
+}
+
+func ErrReviewInvalidQuery(err error) error {
+	return problem.BadRequest("invalid query parameters").
+		WithError(err).
+		WithCode("review.invalid_query")
+}
+
+func ErrReviewQueryFailed(err error) error {
+	return problem.InternalError().
+		WithError(err).
+		WithCode("review.query_failed")
+}
+
+// ErrInvalidRating is returned when a review has no 1-5 rating or a question carries one.
+func ErrInvalidRating(kind ReviewKind, rating int) error {
+	return problem.BadRequest("reviews need a rating from 1 to 5 and questions none").
+		With("kind", kind).
+		With("rating", rating).
+		WithCode("review.invalid_rating")
+}
+
+func ErrReviewRateLimited() error {
+	return problem.TooManyRequests("rate limit exceeded").
+		WithCode("review.rate_limited")
+}
@@ -0,0 +1,39 @@
+package review
+
+import (
+	"context"
+
+	"github.com/abdelrahman146/kyora/internal/platform/bus"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+)
+
+// BusHandler listens for fulfilled orders and asks their customers for reviews.
+type BusHandler struct {
+	svc *Service
+}
+
+// NewBusHandler registers review listeners on the event bus.
+func NewBusHandler(b *bus.Bus, svc *Service) {
+	h := &BusHandler{svc: svc}
+	b.Handle(bus.OrderFulfilledTopic, "review.request_review", h.HandleOrderFulfilled)
+}
+
+// HandleOrderFulfilled sends the review request email of the fulfilled order. Malformed
+// events are logged and dropped; storage and email failures are returned so the bus
+// retries them.
+func (h *BusHandler) HandleOrderFulfilled(event any) error {
+	e, ok := event.(*bus.OrderFulfilledEvent)
+	if !ok {
+		logger.FromContext(context.Background()).Error("invalid event type for OrderFulfilledEvent")
+		return nil
+	}
+	if e.BusinessID == "" || e.OrderID == "" {
+		logger.FromContext(e.Ctx).Error("missing required fields in OrderFulfilledEvent", "businessId", e.BusinessID, "orderId", e.OrderID)
+		return nil
+	}
+	if err := h.svc.SendReviewRequest(e.Ctx, e.BusinessID, e.OrderID); err != nil {
+		logger.FromContext(e.Ctx).Error("failed to send review request", "error", err, "businessId", e.BusinessID, "orderId", e.OrderID)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package review
+
+import (
+	"net/http"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/request"
+	"github.com/abdelrahman146/kyora/internal/platform/response"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/gin-gonic/gin"
+)
+
+// HttpHandler handles the seller's review moderation requests.
+type HttpHandler struct {
+	service *Service
+}
+
+// NewHttpHandler creates a new review HTTP handler.
+func NewHttpHandler(service *Service) *HttpHandler {
+	return &HttpHandler{service: service}
+}
+
+type listReviewsQuery struct {
+	Page      int          `form:"page" binding:"omitempty,min=1"`
+	PageSize  int          `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	OrderBy   []string     `form:"orderBy" binding:"omitempty"`
+	Status    ReviewStatus `form:"status" binding:"omitempty,oneof=pending approved hidden"`
+	Kind      ReviewKind   `form:"kind" binding:"omitempty,oneof=review question"`
+	ProductID string       `form:"productId" binding:"omitempty"`
+}
+
+// ListReviews returns the moderation queue of the business
+//
+// @Summary      List reviews
+// @Description  Returns the business' product reviews and questions, newest first unless ordered otherwise
+// @Tags         review
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        page query int false "Page number"
+// @Param        pageSize query int false "Page size (max 100)"
+// @Param        orderBy query []string false "Order by fields (e.g. -createdAt, rating)"
+// @Param        status query string false "pending, approved or hidden"
+// @Param        kind query string false "review or question"
+// @Param        productId query string false "Product ID"
+// @Success      200 {object} list.ListResponse[review.ReviewResponse]
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/reviews [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListReviews(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := business.BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query listReviewsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, ErrReviewInvalidQuery(err))
+		return
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+	listReq := list.NewListRequest(query.Page, query.PageSize, query.OrderBy, "")
+	items, totalCount, err := h.service.ListReviews(c.Request.Context(), actor, biz, listReq, &ListFilters{
+		Status:    query.Status,
+		Kind:      query.Kind,
+		ProductID: query.ProductID,
+	})
+	if err != nil {
+		response.Error(c, ErrReviewQueryFailed(err))
+		return
+	}
+	hasMore := int64(query.Page*query.PageSize) < totalCount
+	response.SuccessJSON(c, http.StatusOK, list.NewListResponse(ToReviewResponses(items), query.Page, query.PageSize, totalCount, hasMore))
+}
+
+// GetReview returns a review by ID
+//
+// @Summary      Get review
+// @Tags         review
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        reviewId path string true "Review ID"
+// @Success      200 {object} review.ReviewResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/reviews/{reviewId} [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetReview(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := business.BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	r, err := h.service.GetReviewByID(c.Request.Context(), actor, biz, c.Param("reviewId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToReviewResponse(r))
+}
+
+// UpdateReview moderates a review
+//
+// @Summary      Moderate review
+// @Description  Approves, hides or re-queues a review and sets or clears the public seller reply
+// @Tags         review
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        reviewId path string true "Review ID"
+// @Param        body body UpdateReviewRequest true "Moderation"
+// @Success      200 {object} review.ReviewResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/reviews/{reviewId} [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdateReview(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := business.BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req UpdateReviewRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	r, err := h.service.UpdateReview(c.Request.Context(), actor, biz, c.Param("reviewId"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToReviewResponse(r))
+}
+
+// DeleteReview deletes a review
+//
+// @Summary      Delete review
+// @Tags         review
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        reviewId path string true "Review ID"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/reviews/{reviewId} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteReview(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := business.BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.service.DeleteReview(c.Request.Context(), actor, biz, c.Param("reviewId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
@@ -0,0 +1,126 @@
+package review
+
+import (
+	"database/sql"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/nullable"
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	ReviewTable  = "product_reviews"
+	ReviewStruct = "Review"
+	ReviewPrefix = "rev"
+
+	ReviewRequestTable  = "review_requests"
+	ReviewRequestStruct = "ReviewRequest"
+	ReviewRequestPrefix = "rrq"
+)
+
+// ReviewKind tells a rated product review from a customer question.
+type ReviewKind string
+
+const (
+	ReviewKindReview   ReviewKind = "review"
+	ReviewKindQuestion ReviewKind = "question"
+)
+
+// ReviewStatus is where a submission stands in the seller's moderation queue. Only approved
+// submissions are shown on the storefront or count towards product ratings.
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  ReviewStatus = "pending"
+	ReviewStatusApproved ReviewStatus = "approved"
+	ReviewStatusHidden   ReviewStatus = "hidden"
+)
+
+// Review is a storefront visitor's review or question about a product. Reviews carry a 1-5
+// rating; questions have none and are usually answered with a seller Reply.
+type Review struct {
+	gorm.Model
+	ID            string          `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID    string          `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	ProductID     string          `gorm:"column:product_id;type:text;not null;index" json:"productId"`
+	Kind          ReviewKind      `gorm:"column:kind;type:text;not null;default:'review'" json:"kind"`
+	Status        ReviewStatus    `gorm:"column:status;type:text;not null;default:'pending';index" json:"status"`
+	Rating        int             `gorm:"column:rating;type:int;not null;default:0" json:"rating"`
+	Title         string          `gorm:"column:title;type:text" json:"title"`
+	Body          string          `gorm:"column:body;type:text;not null" json:"body"`
+	AuthorName    string          `gorm:"column:author_name;type:text;not null" json:"authorName"`
+	AuthorEmail   string          `gorm:"column:author_email;type:text;not null" json:"authorEmail"`
+	Reply         string          `gorm:"column:reply;type:text" json:"reply"`
+	RepliedAt     sql.NullTime    `gorm:"column:replied_at" json:"repliedAt"`
+	RepliedByID   nullable.String `gorm:"column:replied_by_id;type:text" json:"repliedById,omitempty"`
+	ModeratedAt   sql.NullTime    `gorm:"column:moderated_at" json:"moderatedAt"`
+	ModeratedByID nullable.String `gorm:"column:moderated_by_id;type:text" json:"moderatedById,omitempty"`
+}
+
+func (m *Review) TableName() string { return ReviewTable }
+
+func (m *Review) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(ReviewPrefix)
+	}
+	return
+}
+
+var ReviewSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	ProductID  schema.Field
+	Kind       schema.Field
+	Status     schema.Field
+	Rating     schema.Field
+	CreatedAt  schema.Field
+	UpdatedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	ProductID:  schema.NewField("product_id", "productId"),
+	Kind:       schema.NewField("kind", "kind"),
+	Status:     schema.NewField("status", "status"),
+	Rating:     schema.NewField("rating", "rating"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+	UpdatedAt:  schema.NewField("updated_at", "updatedAt"),
+}
+
+// ReviewRequest records that the customer of a fulfilled order was asked to review its
+// products. The (business_id, order_id) unique index keeps replayed events from emailing
+// the customer twice.
+type ReviewRequest struct {
+	gorm.Model
+	ID         string `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string `gorm:"column:business_id;type:text;not null;uniqueIndex:idx_business_review_request_order" json:"businessId"`
+	OrderID    string `gorm:"column:order_id;type:text;not null;uniqueIndex:idx_business_review_request_order" json:"orderId"`
+	Email      string `gorm:"column:email;type:text;not null" json:"email"`
+}
+
+func (m *ReviewRequest) TableName() string { return ReviewRequestTable }
+
+func (m *ReviewRequest) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(ReviewRequestPrefix)
+	}
+	return
+}
+
+var ReviewRequestSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	OrderID    schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	OrderID:    schema.NewField("order_id", "orderId"),
+}
+
+// ProductRating aggregates the approved reviews of a product.
+type ProductRating struct {
+	ProductID string          `json:"-"`
+	Average   decimal.Decimal `json:"average"`
+	Count     int64           `json:"count"`
+}
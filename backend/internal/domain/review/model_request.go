@@ -0,0 +1,19 @@
+package review
+
+// SubmitReviewRequest is a storefront visitor's review or question about a product. Kind
+// defaults to review, which needs a 1-5 rating; questions have no rating.
+type SubmitReviewRequest struct {
+	Kind        ReviewKind `json:"kind" binding:"omitempty,oneof=review question"`
+	Rating      int        `json:"rating" binding:"omitempty,min=1,max=5"`
+	Title       string     `json:"title" binding:"omitempty,max=200"`
+	Body        string     `json:"body" binding:"required,max=2000"`
+	AuthorName  string     `json:"authorName" binding:"required,max=100"`
+	AuthorEmail string     `json:"authorEmail" binding:"required,email"`
+}
+
+// UpdateReviewRequest moderates a review: status approves or hides it, and reply answers it
+// publicly. An empty reply removes the answer.
+type UpdateReviewRequest struct {
+	Status *ReviewStatus `json:"status" binding:"omitempty,oneof=pending approved hidden"`
+	Reply  *string       `json:"reply" binding:"omitempty,max=2000"`
+}
@@ -0,0 +1,102 @@
+package review
+
+import "time"
+
+// ReviewResponse is the seller's view of a review, including the author's email.
+type ReviewResponse struct {
+	ID            string       `json:"id"`
+	BusinessID    string       `json:"businessId"`
+	ProductID     string       `json:"productId"`
+	Kind          ReviewKind   `json:"kind"`
+	Status        ReviewStatus `json:"status"`
+	Rating        int          `json:"rating,omitempty"`
+	Title         string       `json:"title,omitempty"`
+	Body          string       `json:"body"`
+	AuthorName    string       `json:"authorName"`
+	AuthorEmail   string       `json:"authorEmail"`
+	Reply         string       `json:"reply,omitempty"`
+	RepliedAt     *time.Time   `json:"repliedAt,omitempty"`
+	RepliedByID   string       `json:"repliedById,omitempty"`
+	ModeratedAt   *time.Time   `json:"moderatedAt,omitempty"`
+	ModeratedByID string       `json:"moderatedById,omitempty"`
+	CreatedAt     time.Time    `json:"createdAt"`
+	UpdatedAt     time.Time    `json:"updatedAt"`
+}
+
+// ToReviewResponse converts a Review model to its API response.
+func ToReviewResponse(m *Review) ReviewResponse {
+	resp := ReviewResponse{
+		ID:            m.ID,
+		BusinessID:    m.BusinessID,
+		ProductID:     m.ProductID,
+		Kind:          m.Kind,
+		Status:        m.Status,
+		Rating:        m.Rating,
+		Title:         m.Title,
+		Body:          m.Body,
+		AuthorName:    m.AuthorName,
+		AuthorEmail:   m.AuthorEmail,
+		Reply:         m.Reply,
+		RepliedByID:   m.RepliedByID.String,
+		ModeratedByID: m.ModeratedByID.String,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}
+	if m.RepliedAt.Valid {
+		t := m.RepliedAt.Time
+		resp.RepliedAt = &t
+	}
+	if m.ModeratedAt.Valid {
+		t := m.ModeratedAt.Time
+		resp.ModeratedAt = &t
+	}
+	return resp
+}
+
+// ToReviewResponses converts a slice of Review models to responses.
+func ToReviewResponses(items []*Review) []ReviewResponse {
+	responses := make([]ReviewResponse, 0, len(items))
+	for _, m := range items {
+		responses = append(responses, ToReviewResponse(m))
+	}
+	return responses
+}
+
+// PublicReviewResponse is what storefront visitors see of an approved review; the author's
+// email stays private.
+type PublicReviewResponse struct {
+	ID         string     `json:"id"`
+	ProductID  string     `json:"productId"`
+	Kind       ReviewKind `json:"kind"`
+	Rating     int        `json:"rating,omitempty"`
+	Title      string     `json:"title,omitempty"`
+	Body       string     `json:"body"`
+	AuthorName string     `json:"authorName"`
+	Reply      string     `json:"reply,omitempty"`
+	RepliedAt  *time.Time `json:"repliedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// ToPublicReviewResponses converts approved reviews to their storefront view.
+func ToPublicReviewResponses(items []*Review) []PublicReviewResponse {
+	responses := make([]PublicReviewResponse, 0, len(items))
+	for _, m := range items {
+		resp := PublicReviewResponse{
+			ID:         m.ID,
+			ProductID:  m.ProductID,
+			Kind:       m.Kind,
+			Rating:     m.Rating,
+			Title:      m.Title,
+			Body:       m.Body,
+			AuthorName: m.AuthorName,
+			Reply:      m.Reply,
+			CreatedAt:  m.CreatedAt,
+		}
+		if m.RepliedAt.Valid {
+			t := m.RepliedAt.Time
+			resp.RepliedAt = &t
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
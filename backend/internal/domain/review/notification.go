@@ -0,0 +1,76 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/abdelrahman146/kyora/internal/platform/email"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/spf13/viper"
+)
+
+// Notification encapsulates email sending for review domain
+type Notification struct {
+	client email.Client
+	info   email.EmailInfo
+}
+
+// NewNotification wires the email client and defaults
+func NewNotification(client email.Client, info email.EmailInfo) *Notification {
+	return &Notification{client: client, info: info}
+}
+
+// SendReviewRequestEmail asks the customer of a fulfilled order to review its products. Each
+// product links to its storefront page when storefront.base_url is configured.
+func (n *Notification) SendReviewRequestEmail(ctx context.Context, biz *business.Business, ord *order.Order) error {
+	if n.client == nil {
+		return fmt.Errorf("email client not available")
+	}
+	logger := logger.FromContext(ctx).With("action", "sendReviewRequest", "businessId", biz.ID, "orderId", ord.ID)
+	logger.Info("sending review request email")
+
+	baseURL := strings.TrimRight(viper.GetString(config.StorefrontBaseURL), "/")
+	seen := make(map[string]bool, len(ord.Items))
+	products := make([]map[string]any, 0, len(ord.Items))
+	for _, item := range ord.Items {
+		if item.Product == nil || seen[item.ProductID] {
+			continue
+		}
+		seen[item.ProductID] = true
+		reviewURL := ""
+		if baseURL != "" {
+			reviewURL = fmt.Sprintf("%s/%s/products/%s", baseURL, biz.StorefrontPublicID, item.ProductID)
+		}
+		products = append(products, map[string]any{
+			"name":      item.Product.Name,
+			"reviewURL": reviewURL,
+		})
+	}
+
+	businessName := biz.Brand
+	if businessName == "" {
+		businessName = biz.Name
+	}
+	data := map[string]any{
+		"customerName": ord.Customer.Name,
+		"businessName": businessName,
+		"orderNumber":  ord.OrderNumber,
+		"products":     products,
+		"supportEmail": biz.SupportEmail,
+		"productName":  n.info.ProductName,
+		"currentYear":  fmt.Sprintf("%d", time.Now().Year()),
+	}
+	subject := fmt.Sprintf("How was your %s order?", businessName)
+	from := n.info.FormattedFrom()
+	if _, err := n.client.SendTemplate(ctx, email.TemplateReviewRequest, []string{ord.Customer.Email.String}, from, subject, data); err != nil {
+		logger.Error("failed to send review request email", "error", err)
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	logger.Info("review request email sent successfully")
+	return nil
+}
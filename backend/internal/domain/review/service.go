@@ -0,0 +1,249 @@
+package review
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/email"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/throttle"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+	"gorm.io/gorm"
+)
+
+// Service manages storefront product reviews and questions, their moderation, and the
+// review requests sent after fulfillment.
+type Service struct {
+	storage      *Storage
+	business     *business.Service
+	inventory    *inventory.Service
+	orders       *order.Service
+	Notification *Notification
+}
+
+// NewService creates the review service.
+func NewService(storage *Storage, businessSvc *business.Service, inventorySvc *inventory.Service, orderSvc *order.Service, emailClient email.Client) *Service {
+	return &Service{
+		storage:      storage,
+		business:     businessSvc,
+		inventory:    inventorySvc,
+		orders:       orderSvc,
+		Notification: NewNotification(emailClient, email.NewEmail()),
+	}
+}
+
+// ListFilters narrows ListReviews results.
+type ListFilters struct {
+	Status    ReviewStatus
+	Kind      ReviewKind
+	ProductID string
+}
+
+// ListReviews returns a page of the business' reviews and questions for moderation, newest
+// first unless ordered otherwise.
+func (s *Service) ListReviews(ctx context.Context, actor *account.User, biz *business.Business, req *list.ListRequest, filters *ListFilters) ([]*Review, int64, error) {
+	scopes := []func(*gorm.DB) *gorm.DB{s.storage.review.ScopeBusinessID(biz.ID)}
+	if filters != nil {
+		if filters.Status != "" {
+			scopes = append(scopes, s.storage.review.ScopeEquals(ReviewSchema.Status, filters.Status))
+		}
+		if filters.Kind != "" {
+			scopes = append(scopes, s.storage.review.ScopeEquals(ReviewSchema.Kind, filters.Kind))
+		}
+		if filters.ProductID != "" {
+			scopes = append(scopes, s.storage.review.ScopeEquals(ReviewSchema.ProductID, filters.ProductID))
+		}
+	}
+	return s.listReviews(ctx, req, scopes)
+}
+
+// ListPublicReviews returns a page of the approved reviews and questions of a product, as
+// shown on the storefront. An empty kind returns both.
+func (s *Service) ListPublicReviews(ctx context.Context, biz *business.Business, productID string, kind ReviewKind, req *list.ListRequest) ([]*Review, int64, error) {
+	scopes := []func(*gorm.DB) *gorm.DB{
+		s.storage.review.ScopeBusinessID(biz.ID),
+		s.storage.review.ScopeEquals(ReviewSchema.ProductID, productID),
+		s.storage.review.ScopeEquals(ReviewSchema.Status, ReviewStatusApproved),
+	}
+	if kind != "" {
+		scopes = append(scopes, s.storage.review.ScopeEquals(ReviewSchema.Kind, kind))
+	}
+	return s.listReviews(ctx, req, scopes)
+}
+
+func (s *Service) listReviews(ctx context.Context, req *list.ListRequest, scopes []func(*gorm.DB) *gorm.DB) ([]*Review, int64, error) {
+	total, err := s.storage.review.Count(ctx, scopes...)
+	if err != nil {
+		return nil, 0, err
+	}
+	orderBy := []string{"product_reviews.created_at DESC"}
+	if req.HasExplicitOrderBy() {
+		orderBy = req.ParsedOrderBy(ReviewSchema)
+	}
+	items, err := s.storage.review.FindMany(ctx, append(scopes,
+		s.storage.review.WithPagination(req.Offset(), req.Limit()),
+		s.storage.review.WithOrderBy(orderBy),
+	)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (s *Service) GetReviewByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Review, error) {
+	r, err := s.storage.review.FindOne(ctx,
+		s.storage.review.ScopeBusinessID(biz.ID),
+		s.storage.review.ScopeID(id),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrReviewNotFound(id, err)
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+// SubmitReview records a storefront visitor's review or question about a product of the
+// business. Submissions wait in the moderation queue until the seller approves them.
+// Visitors are limited to 5 submissions per business every 10 minutes.
+func (s *Service) SubmitReview(ctx context.Context, biz *business.Business, productID, clientIP string, req *SubmitReviewRequest) (*Review, error) {
+	kind := req.Kind
+	if kind == "" {
+		kind = ReviewKindReview
+	}
+	if (kind == ReviewKindReview) != (req.Rating > 0) {
+		return nil, ErrInvalidRating(kind, req.Rating)
+	}
+	if _, err := s.inventory.GetProductByID(ctx, nil, biz, productID); err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, inventory.ErrProductNotFound(err).With("productId", productID)
+		}
+		return nil, err
+	}
+
+	ip := strings.TrimSpace(clientIP)
+	if ip == "" {
+		ip = "unknown"
+	}
+	throttleKey := fmt.Sprintf("storefront:%s:review:%s", biz.ID, ip)
+	if !throttle.Allow(s.storage.cache, throttleKey, 10*time.Minute, 5, time.Second) {
+		return nil, ErrReviewRateLimited()
+	}
+
+	r := &Review{
+		BusinessID:  biz.ID,
+		ProductID:   productID,
+		Kind:        kind,
+		Status:      ReviewStatusPending,
+		Rating:      req.Rating,
+		Title:       strings.TrimSpace(req.Title),
+		Body:        strings.TrimSpace(req.Body),
+		AuthorName:  strings.TrimSpace(req.AuthorName),
+		AuthorEmail: strings.ToLower(strings.TrimSpace(req.AuthorEmail)),
+	}
+	if err := s.storage.review.CreateOne(ctx, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// UpdateReview moderates a review. Status changes stamp who moderated it and when; a reply
+// stamps who answered it.
+func (s *Service) UpdateReview(ctx context.Context, actor *account.User, biz *business.Business, id string, req *UpdateReviewRequest) (*Review, error) {
+	r, err := s.GetReviewByID(ctx, actor, biz, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if req.Status != nil && *req.Status != r.Status {
+		r.Status = *req.Status
+		r.ModeratedAt = sql.NullTime{Time: now, Valid: true}
+		r.ModeratedByID = transformer.ToNullableString(actor.ID)
+	}
+	if req.Reply != nil {
+		r.Reply = strings.TrimSpace(*req.Reply)
+		if r.Reply == "" {
+			r.RepliedAt = sql.NullTime{}
+			r.RepliedByID = transformer.ToNullableString("")
+		} else {
+			r.RepliedAt = sql.NullTime{Time: now, Valid: true}
+			r.RepliedByID = transformer.ToNullableString(actor.ID)
+		}
+	}
+	if err := s.storage.review.UpdateOne(ctx, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (s *Service) DeleteReview(ctx context.Context, actor *account.User, biz *business.Business, id string) error {
+	r, err := s.GetReviewByID(ctx, actor, biz, id)
+	if err != nil {
+		return err
+	}
+	return s.storage.review.DeleteOne(ctx, r)
+}
+
+// ProductRatings returns the average rating and review count of the given products, keyed
+// by product ID. Only approved reviews count.
+func (s *Service) ProductRatings(ctx context.Context, biz *business.Business, productIDs []string) (map[string]ProductRating, error) {
+	return s.storage.ProductRatings(ctx, biz.ID, productIDs)
+}
+
+// SendReviewRequest emails the customer of a fulfilled order a request to review the
+// order's products, when the business enabled review requests and the customer has an
+// email. Each order is asked about once.
+func (s *Service) SendReviewRequest(ctx context.Context, businessID, orderID string) error {
+	biz, err := s.business.GetBusinessByIDForJobs(ctx, businessID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !biz.StorefrontReviewRequests {
+		return nil
+	}
+	ord, err := s.orders.GetOrderByID(ctx, nil, biz, orderID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if ord.Customer == nil || !ord.Customer.Email.Valid || ord.Customer.Email.String == "" {
+		return nil
+	}
+
+	_, err = s.storage.requests.FindOne(ctx,
+		s.storage.requests.ScopeBusinessID(biz.ID),
+		s.storage.requests.ScopeEquals(ReviewRequestSchema.OrderID, orderID),
+	)
+	if err == nil {
+		return nil
+	}
+	if !database.IsRecordNotFound(err) {
+		return err
+	}
+	if err := s.Notification.SendReviewRequestEmail(ctx, biz, ord); err != nil {
+		return err
+	}
+	err = s.storage.requests.CreateOne(ctx, &ReviewRequest{
+		BusinessID: biz.ID,
+		OrderID:    orderID,
+		Email:      ord.Customer.Email.String,
+	})
+	if err != nil && !database.IsUniqueViolation(err) {
+		return err
+	}
+	return nil
+}
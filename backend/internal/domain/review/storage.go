@@ -0,0 +1,52 @@
+package review
+
+import (
+	"context"
+
+	"github.com/abdelrahman146/kyora/internal/platform/cache"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+)
+
+// Storage provides data access for product reviews and review requests.
+type Storage struct {
+	db       *database.Database
+	cache    *cache.Cache
+	review   *database.Repository[Review]
+	requests *database.Repository[ReviewRequest]
+}
+
+// NewStorage creates a new review storage instance.
+func NewStorage(db *database.Database, c *cache.Cache) *Storage {
+	return &Storage{
+		db:       db,
+		cache:    c,
+		review:   database.NewRepository[Review](db),
+		requests: database.NewRepository[ReviewRequest](db),
+	}
+}
+
+// ProductRatings aggregates the approved reviews of the given products. Products without
+// approved reviews are absent from the result.
+func (s *Storage) ProductRatings(ctx context.Context, businessID string, productIDs []string) (map[string]ProductRating, error) {
+	ratings := make(map[string]ProductRating, len(productIDs))
+	if len(productIDs) == 0 {
+		return ratings, nil
+	}
+	var rows []ProductRating
+	err := s.db.Conn(ctx).
+		Table(ReviewTable).
+		Select("product_id, ROUND(AVG(rating)::numeric, 1) AS average, COUNT(*) AS count").
+		Where("business_id = ?", businessID).
+		Where("product_id IN ?", productIDs).
+		Where("kind = ? AND status = ?", ReviewKindReview, ReviewStatusApproved).
+		Where("deleted_at IS NULL").
+		Group("product_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		ratings[r.ProductID] = r
+	}
+	return ratings, nil
+}
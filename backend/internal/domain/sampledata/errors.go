@@ -0,0 +1,18 @@
+package sampledata
+
+import "github.com/abdelrahman146/kyora/internal/platform/types/problem"
+
+func ErrSampleDataNotFound(businessID string, err error) error {
+	return problem.NotFound("no sample data is loaded").
+		With("businessId", businessID).
+		WithError(err).
+		WithCode("sample_data.not_found")
+}
+
+// ErrBusinessNotEmpty is returned when sample data would be mixed with the business' own
+// products, customers or orders.
+func ErrBusinessNotEmpty(businessID string) error {
+	return problem.Conflict("sample data can only be loaded into a business without products, customers or orders").
+		With("businessId", businessID).
+		WithCode("sample_data.business_not_empty")
+}
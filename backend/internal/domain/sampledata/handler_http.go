@@ -0,0 +1,114 @@
+package sampledata
+
+import (
+	"net/http"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/response"
+	"github.com/gin-gonic/gin"
+)
+
+// HttpHandler handles the sample data requests of a business.
+type HttpHandler struct {
+	service *Service
+}
+
+// NewHttpHandler creates a new sample data HTTP handler.
+func NewHttpHandler(service *Service) *HttpHandler {
+	return &HttpHandler{service: service}
+}
+
+// GetSampleData returns the sample data loaded into the business
+//
+// @Summary      Get sample data
+// @Tags         sampledata
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {object} sampledata.SampleDataResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/sample-data [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetSampleData(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := business.BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	set, err := h.service.GetSampleData(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToSampleDataResponse(set))
+}
+
+// LoadSampleData loads sample data into the business
+//
+// @Summary      Load sample data
+// @Description  Loads sample products, customers, orders and expenses into a business without its own products, customers or orders. Idempotent: returns the existing set when sample data is already loaded.
+// @Tags         sampledata
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {object} sampledata.SampleDataResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/sample-data [post]
+// @Security     BearerAuth
+func (h *HttpHandler) LoadSampleData(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := business.BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	set, err := h.service.LoadSampleData(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToSampleDataResponse(set))
+}
+
+// RemoveSampleData removes the sample data of the business
+//
+// @Summary      Remove sample data
+// @Description  Deletes every entity loaded as sample data. The business' own data is never touched. Removing when nothing is loaded succeeds.
+// @Tags         sampledata
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/sample-data [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) RemoveSampleData(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := business.BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.service.RemoveSampleData(c.Request.Context(), actor, biz); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
@@ -0,0 +1,108 @@
+package sampledata
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"gorm.io/gorm"
+)
+
+const (
+	SampleDataSetTable  = "sample_data_sets"
+	SampleDataSetStruct = "SampleDataSet"
+	SampleDataSetPrefix = "sds"
+
+	SampleRecordTable  = "sample_records"
+	SampleRecordStruct = "SampleRecord"
+	SampleRecordPrefix = "sdr"
+)
+
+type SampleDataStatus string
+
+const (
+	SampleDataStatusLoading SampleDataStatus = "loading"
+	SampleDataStatusLoaded  SampleDataStatus = "loaded"
+)
+
+// SampleDataSet is the sample data loaded into a business. A business has at most one;
+// removing the sample data deletes it, so rows are hard-deleted and the business can load
+// sample data again.
+type SampleDataSet struct {
+	ID          string           `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID  string           `gorm:"column:business_id;type:text;not null;uniqueIndex" json:"businessId"`
+	Status      SampleDataStatus `gorm:"column:status;type:text;not null;default:'loading'" json:"status"`
+	CreatedByID string           `gorm:"column:created_by_id;type:text" json:"createdById"`
+	Categories  int              `gorm:"column:categories;not null;default:0" json:"categories"`
+	Products    int              `gorm:"column:products;not null;default:0" json:"products"`
+	Customers   int              `gorm:"column:customers;not null;default:0" json:"customers"`
+	Orders      int              `gorm:"column:orders;not null;default:0" json:"orders"`
+	Expenses    int              `gorm:"column:expenses;not null;default:0" json:"expenses"`
+	LoadedAt    sql.NullTime     `gorm:"column:loaded_at" json:"loadedAt"`
+	CreatedAt   time.Time        `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time        `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
+}
+
+func (m *SampleDataSet) TableName() string { return SampleDataSetTable }
+
+func (m *SampleDataSet) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(SampleDataSetPrefix)
+	}
+	return
+}
+
+var SampleDataSetSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	Status     schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	Status:     schema.NewField("status", "status"),
+}
+
+// RecordKind is the kind of entity a sample record points to.
+type RecordKind string
+
+const (
+	RecordKindCategory RecordKind = "category"
+	RecordKindProduct  RecordKind = "product"
+	RecordKindCustomer RecordKind = "customer"
+	RecordKindOrder    RecordKind = "order"
+	RecordKindExpense  RecordKind = "expense"
+)
+
+// SampleRecord remembers an entity created as sample data, so removal deletes exactly the
+// sample entities and never the seller's own data.
+type SampleRecord struct {
+	ID         string     `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string     `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	Kind       RecordKind `gorm:"column:kind;type:text;not null" json:"kind"`
+	RecordID   string     `gorm:"column:record_id;type:text;not null" json:"recordId"`
+	CreatedAt  time.Time  `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+}
+
+func (m *SampleRecord) TableName() string { return SampleRecordTable }
+
+func (m *SampleRecord) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(SampleRecordPrefix)
+	}
+	return
+}
+
+var SampleRecordSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	Kind       schema.Field
+	RecordID   schema.Field
+	CreatedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	Kind:       schema.NewField("kind", "kind"),
+	RecordID:   schema.NewField("record_id", "recordId"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+}
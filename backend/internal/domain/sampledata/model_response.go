@@ -0,0 +1,37 @@
+package sampledata
+
+import "time"
+
+// SampleDataResponse is the API response for the sample data of a business.
+type SampleDataResponse struct {
+	ID         string           `json:"id"`
+	BusinessID string           `json:"businessId"`
+	Status     SampleDataStatus `json:"status"`
+	Categories int              `json:"categories"`
+	Products   int              `json:"products"`
+	Customers  int              `json:"customers"`
+	Orders     int              `json:"orders"`
+	Expenses   int              `json:"expenses"`
+	LoadedAt   *time.Time       `json:"loadedAt,omitempty"`
+	CreatedAt  time.Time        `json:"createdAt"`
+}
+
+// ToSampleDataResponse converts a SampleDataSet model to its API response.
+func ToSampleDataResponse(m *SampleDataSet) SampleDataResponse {
+	resp := SampleDataResponse{
+		ID:         m.ID,
+		BusinessID: m.BusinessID,
+		Status:     m.Status,
+		Categories: m.Categories,
+		Products:   m.Products,
+		Customers:  m.Customers,
+		Orders:     m.Orders,
+		Expenses:   m.Expenses,
+		CreatedAt:  m.CreatedAt,
+	}
+	if m.LoadedAt.Valid {
+		t := m.LoadedAt.Time
+		resp.LoadedAt = &t
+	}
+	return resp
+}
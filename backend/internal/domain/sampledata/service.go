@@ -0,0 +1,327 @@
+package sampledata
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/accounting"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/types/date"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/country"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Service loads a small, fixed sample data set into a new business, so a trial can explore
+// a populated dashboard, and removes it again. It is a trimmed, service-level version of
+// the seed command.
+type Service struct {
+	storage    *Storage
+	inventory  *inventory.Service
+	customer   *customer.Service
+	orders     *order.Service
+	accounting *accounting.Service
+}
+
+// NewService creates the sample data service.
+func NewService(storage *Storage, inventorySvc *inventory.Service, customerSvc *customer.Service, orderSvc *order.Service, accountingSvc *accounting.Service) *Service {
+	return &Service{
+		storage:    storage,
+		inventory:  inventorySvc,
+		customer:   customerSvc,
+		orders:     orderSvc,
+		accounting: accountingSvc,
+	}
+}
+
+// GetSampleData returns the sample data set of the business.
+func (s *Service) GetSampleData(ctx context.Context, actor *account.User, biz *business.Business) (*SampleDataSet, error) {
+	set, err := s.storage.set.FindOne(ctx, s.storage.set.ScopeBusinessID(biz.ID))
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrSampleDataNotFound(biz.ID, err)
+		}
+		return nil, err
+	}
+	return set, nil
+}
+
+// LoadSampleData loads the sample data into the business. It is idempotent: when sample
+// data was already loaded, the existing set is returned. To keep sample data apart from
+// real data, it is only loaded into businesses without products, customers or orders.
+func (s *Service) LoadSampleData(ctx context.Context, actor *account.User, biz *business.Business) (*SampleDataSet, error) {
+	if set, err := s.GetSampleData(ctx, actor, biz); err == nil {
+		return set, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err := s.ensureEmpty(ctx, actor, biz); err != nil {
+		return nil, err
+	}
+
+	set := &SampleDataSet{BusinessID: biz.ID, Status: SampleDataStatusLoading, CreatedByID: actor.ID}
+	if err := s.storage.set.CreateOne(ctx, set); err != nil {
+		if database.IsUniqueViolation(err) {
+			// a concurrent request is loading the sample data
+			return s.GetSampleData(ctx, actor, biz)
+		}
+		return nil, err
+	}
+	if err := s.generate(ctx, actor, biz, set); err != nil {
+		if rmErr := s.RemoveSampleData(ctx, actor, biz); rmErr != nil {
+			logger.FromContext(ctx).Error("failed to clean up partially loaded sample data", "businessId", biz.ID, "error", rmErr)
+		}
+		return nil, err
+	}
+	set.Status = SampleDataStatusLoaded
+	set.LoadedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	if err := s.storage.set.UpdateOne(ctx, set); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// RemoveSampleData deletes every entity loaded as sample data and the set itself. Entities
+// the seller already deleted are skipped, and removing when nothing is loaded is a no-op.
+func (s *Service) RemoveSampleData(ctx context.Context, actor *account.User, biz *business.Business) error {
+	set, err := s.storage.set.FindOne(ctx, s.storage.set.ScopeBusinessID(biz.ID))
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	records, err := s.storage.records.FindMany(ctx, s.storage.records.ScopeBusinessID(biz.ID))
+	if err != nil {
+		return err
+	}
+	ids := make(map[RecordKind][]string)
+	for _, r := range records {
+		ids[r.Kind] = append(ids[r.Kind], r.RecordID)
+	}
+
+	// orders go first: they restock variants and reference customers
+	if err := s.orders.DeleteSampleOrders(ctx, actor, biz, ids[RecordKindOrder]); err != nil {
+		return err
+	}
+	deletes := []struct {
+		kind   RecordKind
+		delete func(context.Context, *account.User, *business.Business, string) error
+	}{
+		{RecordKindCustomer, s.customer.DeleteCustomer},
+		{RecordKindProduct, s.inventory.DeleteProduct},
+		{RecordKindCategory, s.inventory.DeleteCategory},
+		{RecordKindExpense, s.accounting.DeleteExpense},
+	}
+	for _, d := range deletes {
+		for _, id := range ids[d.kind] {
+			if err := d.delete(ctx, actor, biz, id); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		}
+	}
+	if err := s.storage.records.DeleteMany(ctx, s.storage.records.ScopeBusinessID(biz.ID)); err != nil {
+		return err
+	}
+	return s.storage.set.DeleteOne(ctx, set)
+}
+
+func (s *Service) ensureEmpty(ctx context.Context, actor *account.User, biz *business.Business) error {
+	counts := []func(context.Context, *account.User, *business.Business) (int64, error){
+		s.inventory.CountProducts,
+		s.customer.CountCustomers,
+		s.orders.CountOrders,
+	}
+	for _, count := range counts {
+		n, err := count(ctx, actor, biz)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return ErrBusinessNotEmpty(biz.ID)
+		}
+	}
+	return nil
+}
+
+func (s *Service) record(ctx context.Context, biz *business.Business, kind RecordKind, recordID string) error {
+	return s.storage.records.CreateOne(ctx, &SampleRecord{BusinessID: biz.ID, Kind: kind, RecordID: recordID})
+}
+
+var sampleCatalog = []struct {
+	category string
+	products []string
+}{
+	{category: "Apparel", products: []string{"Classic Cotton Tee", "Linen Summer Shirt"}},
+	{category: "Home & Living", products: []string{"Scented Soy Candle", "Ceramic Coffee Mug"}},
+	{category: "Beauty", products: []string{"Argan Hair Oil", "Rose Face Mist"}},
+}
+
+var sampleCustomers = []string{
+	"Layla Ibrahim", "Omar Khan", "Noor Haddad", "Sofia Silva",
+	"Hassan Okafor", "Mia Laurent", "Rafael Lopez", "Zoe Chen",
+}
+
+var sampleExpenses = []struct {
+	category accounting.ExpenseCategory
+	amount   int64
+	note     string
+}{
+	{accounting.ExpenseCategoryRent, 1200, "Sample: studio rent"},
+	{accounting.ExpenseCategoryMarketing, 350, "Sample: Instagram ads"},
+	{accounting.ExpenseCategoryShipping, 180, "Sample: courier pickups"},
+	{accounting.ExpenseCategorySoftware, 60, "Sample: design tools"},
+}
+
+const sampleOrders = 24
+
+// generate creates the sample entities, recording each one as it is created so a failed
+// load can be removed. Content is fixed rather than random so every trial sees the same
+// data; prices are in the business currency.
+func (s *Service) generate(ctx context.Context, actor *account.User, biz *business.Business, set *SampleDataSet) error {
+	suffix := strings.ToLower(set.ID[len(set.ID)-6:])
+	var variants []*inventory.Variant
+	for i, entry := range sampleCatalog {
+		cat, err := s.inventory.CreateCategory(ctx, actor, biz, &inventory.CreateCategoryRequest{
+			Name:       entry.category,
+			Descriptor: fmt.Sprintf("sample-%d-%s", i+1, suffix),
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.record(ctx, biz, RecordKindCategory, cat.ID); err != nil {
+			return err
+		}
+		set.Categories++
+		for j, name := range entry.products {
+			cost := decimal.NewFromInt(int64(12 + 9*(2*i+j)))
+			price := cost.Mul(decimal.NewFromFloat(1.8)).Round(2)
+			premium := price.Mul(decimal.NewFromFloat(1.25)).Round(2)
+			stock := 60 + 15*j
+			alert := 10
+			p, err := s.inventory.CreateProductWithVariants(ctx, actor, biz, &inventory.CreateProductWithVariantsRequest{
+				Product: inventory.CreateProductRequest{
+					Name:        name,
+					Description: "Sample product. Remove the sample data to delete it.",
+					CategoryID:  cat.ID,
+				},
+				Variants: []inventory.CreateProductVariantRequest{
+					{Code: "standard", CostPrice: &cost, SalePrice: &price, StockQuantity: &stock, StockQuantityAlert: &alert},
+					{Code: "premium", CostPrice: &cost, SalePrice: &premium, StockQuantity: &stock, StockQuantityAlert: &alert},
+				},
+			})
+			if err != nil {
+				return err
+			}
+			if err := s.record(ctx, biz, RecordKindProduct, p.ID); err != nil {
+				return err
+			}
+			set.Products++
+			variants = append(variants, p.Variants...)
+		}
+	}
+
+	countryCode := strings.ToUpper(biz.CountryCode)
+	phoneCode := country.FindByCode(countryCode).PhonePrefix
+	if phoneCode == "" {
+		phoneCode = "+1"
+	}
+	customers := make([]*customer.Customer, 0, len(sampleCustomers))
+	addresses := make([]*customer.CustomerAddress, 0, len(sampleCustomers))
+	for i, name := range sampleCustomers {
+		// no email: sample customers must never receive messages
+		c, err := s.customer.CreateCustomer(ctx, actor, biz, &customer.CreateCustomerRequest{
+			Name:           name,
+			CountryCode:    countryCode,
+			PhoneCode:      phoneCode,
+			PhoneNumber:    fmt.Sprintf("5550%05d", i+1),
+			AllowDuplicate: true,
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.record(ctx, biz, RecordKindCustomer, c.ID); err != nil {
+			return err
+		}
+		set.Customers++
+		addr, err := s.customer.CreateCustomerAddress(ctx, actor, biz, c.ID, &customer.CreateCustomerAddressRequest{
+			CountryCode: countryCode,
+			State:       "Sample",
+			City:        "Sample City",
+			Street:      fmt.Sprintf("%d Sample Street", 10+i),
+			PhoneCode:   phoneCode,
+			PhoneNumber: c.PhoneNumber.String,
+		})
+		if err != nil {
+			return err
+		}
+		customers = append(customers, c)
+		addresses = append(addresses, addr)
+	}
+
+	channels := []string{"instagram", "whatsapp", "tiktok", "facebook"}
+	placed, paid := order.OrderStatusPlaced, order.OrderPaymentStatusPaid
+	now := time.Now().UTC()
+	for i := 0; i < sampleOrders; i++ {
+		req := &order.CreateOrderRequest{
+			CustomerID:        customers[i%len(customers)].ID,
+			ShippingAddressID: addresses[i%len(addresses)].ID,
+			Channel:           channels[i%len(channels)],
+			PaymentMethod:     order.OrderPaymentMethodBankTransfer,
+			// spread over the last two months, newest first
+			OrderedAt: now.Add(-time.Duration(i*60) * time.Hour),
+			Items: []*order.CreateOrderItemRequest{
+				{VariantID: variants[i%len(variants)].ID, Quantity: 1 + i%3},
+			},
+		}
+		if i%4 == 1 {
+			req.Items = append(req.Items, &order.CreateOrderItemRequest{VariantID: variants[(i+5)%len(variants)].ID, Quantity: 1})
+		}
+		// three in four orders are paid; of the rest, half are placed and half still pending
+		switch i % 8 {
+		case 3:
+			req.Status = &placed
+		case 7:
+			// pending
+		default:
+			req.Status = &placed
+			req.PaymentStatus = &paid
+		}
+		ord, err := s.orders.CreateSampleOrder(ctx, actor, biz, req)
+		if err != nil {
+			return err
+		}
+		if err := s.record(ctx, biz, RecordKindOrder, ord.ID); err != nil {
+			return err
+		}
+		set.Orders++
+	}
+
+	for i, e := range sampleExpenses {
+		expense, err := s.accounting.CreateExpense(ctx, actor, biz, &accounting.CreateExpenseRequest{
+			Amount:     decimal.NewFromInt(e.amount),
+			Category:   e.category,
+			Type:       accounting.ExpenseTypeOneTime,
+			Note:       e.note,
+			OccurredOn: &date.Date{Time: now.AddDate(0, 0, -7*(i+1))},
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.record(ctx, biz, RecordKindExpense, expense.ID); err != nil {
+			return err
+		}
+		set.Expenses++
+	}
+	return nil
+}
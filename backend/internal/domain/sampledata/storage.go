@@ -0,0 +1,21 @@
+package sampledata
+
+import (
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+)
+
+// Storage provides data access for sample data sets.
+type Storage struct {
+	db      *database.Database
+	set     *database.Repository[SampleDataSet]
+	records *database.Repository[SampleRecord]
+}
+
+// NewStorage creates a new sample data storage instance.
+func NewStorage(db *database.Database) *Storage {
+	return &Storage{
+		db:      db,
+		set:     database.NewRepository[SampleDataSet](db),
+		records: database.NewRepository[SampleRecord](db),
+	}
+}
@@ -0,0 +1,15 @@
+package search
+
+import "github.com/abdelrahman146/kyora/internal/platform/types/problem"
+
+func ErrSearchInvalidQuery(err error) error {
+	return problem.BadRequest("invalid query parameters").
+		WithError(err).
+		WithCode("search.invalid_query")
+}
+
+func ErrSearchFailed(err error) error {
+	return problem.InternalError().
+		WithError(err).
+		WithCode("search.failed")
+}
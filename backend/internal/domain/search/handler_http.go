@@ -0,0 +1,87 @@
+package search
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/response"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/gin-gonic/gin"
+)
+
+// HttpHandler serves the global search of a business.
+type HttpHandler struct {
+	service *Service
+}
+
+// NewHttpHandler creates a new search HTTP handler.
+func NewHttpHandler(service *Service) *HttpHandler {
+	return &HttpHandler{service: service}
+}
+
+type searchQuery struct {
+	Query string   `form:"q" binding:"required"`
+	Types []string `form:"types" binding:"omitempty,dive,oneof=order customer product expense"`
+	Limit int      `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// Search returns orders, customers, products and expenses matching a query, ranked together
+//
+// @Summary      Search the business
+// @Description  Searches orders, customers, products and expenses of the business at once and returns one list ranked by relevance. Each item is tagged with its type. Types the caller cannot view are left out.
+// @Tags         search
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        q query string true "Search term (at least 2 characters)"
+// @Param        types query []string false "Restrict to result types (order, customer, product, expense)"
+// @Param        limit query int false "Maximum number of results (default: 20, max: 50)"
+// @Success      200 {object} search.SearchResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/search [get]
+// @Security     BearerAuth
+func (h *HttpHandler) Search(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := business.BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var query searchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, ErrSearchInvalidQuery(err))
+		return
+	}
+	term, err := list.NormalizeSearchTerm(query.Query)
+	if err != nil {
+		response.Error(c, ErrSearchInvalidQuery(err))
+		return
+	}
+	if len([]rune(term)) < 2 {
+		response.Error(c, ErrSearchInvalidQuery(errors.New("search term must be at least 2 characters")))
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+	types := make([]ResultType, len(query.Types))
+	for i, t := range query.Types {
+		types[i] = ResultType(t)
+	}
+
+	results, err := h.service.Search(c.Request.Context(), actor, biz, term, types, query.Limit)
+	if err != nil {
+		response.Error(c, ErrSearchFailed(err))
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToSearchResponse(term, results))
+}
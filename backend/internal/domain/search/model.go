@@ -0,0 +1,62 @@
+package search
+
+import (
+	"math"
+	"time"
+)
+
+// ResultType tags what a search result points to.
+type ResultType string
+
+const (
+	ResultTypeOrder    ResultType = "order"
+	ResultTypeCustomer ResultType = "customer"
+	ResultTypeProduct  ResultType = "product"
+	ResultTypeExpense  ResultType = "expense"
+)
+
+// AllResultTypes lists every searchable entity, in the order ties are broken.
+var AllResultTypes = []ResultType{ResultTypeOrder, ResultTypeCustomer, ResultTypeProduct, ResultTypeExpense}
+
+// Result is one match of a global search. Score combines full-text rank and trigram
+// similarity so results of different types can be ranked together; higher is better.
+type Result struct {
+	Type       ResultType
+	ID         string
+	Title      string
+	Subtitle   string
+	Score      float64
+	OccurredAt time.Time
+}
+
+// ResultResponse is the API response for a search result
+type ResultResponse struct {
+	Type       ResultType `json:"type"`
+	ID         string     `json:"id"`
+	Title      string     `json:"title"`
+	Subtitle   string     `json:"subtitle"`
+	Score      float64    `json:"score"`
+	OccurredAt time.Time  `json:"occurredAt"`
+}
+
+// SearchResponse is the ranked mixed-type result list of a query.
+type SearchResponse struct {
+	Query string           `json:"query"`
+	Items []ResultResponse `json:"items"`
+}
+
+// ToSearchResponse converts search results to the API response
+func ToSearchResponse(query string, results []*Result) SearchResponse {
+	resp := SearchResponse{Query: query, Items: make([]ResultResponse, len(results))}
+	for i, r := range results {
+		resp.Items[i] = ResultResponse{
+			Type:       r.Type,
+			ID:         r.ID,
+			Title:      r.Title,
+			Subtitle:   r.Subtitle,
+			Score:      math.Round(r.Score*10000) / 10000,
+			OccurredAt: r.OccurredAt,
+		}
+	}
+	return resp
+}
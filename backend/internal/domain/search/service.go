@@ -0,0 +1,51 @@
+package search
+
+import (
+	"context"
+	"slices"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+)
+
+// Service runs the global search of a business.
+type Service struct {
+	storage *Storage
+}
+
+// NewService creates the search service.
+func NewService(storage *Storage) *Service {
+	return &Service{storage: storage}
+}
+
+// resultResources maps each result type to the resource the actor must be able to view.
+var resultResources = map[ResultType]role.Resource{
+	ResultTypeOrder:    role.ResourceOrder,
+	ResultTypeCustomer: role.ResourceCustomer,
+	ResultTypeProduct:  role.ResourceInventory,
+	ResultTypeExpense:  role.ResourceAccounting,
+}
+
+// Search returns the best matches of term across types in the business, at most limit.
+// Types the actor cannot view are skipped rather than rejected, so a member restricted
+// from financials still searches orders, customers and products. Empty types means all.
+func (s *Service) Search(ctx context.Context, actor *account.User, biz *business.Business, term string, types []ResultType, limit int) ([]*Result, error) {
+	if len(types) == 0 {
+		types = AllResultTypes
+	}
+	allowed := make([]ResultType, 0, len(types))
+	for _, t := range AllResultTypes {
+		if !slices.Contains(types, t) {
+			continue
+		}
+		if actor.HasPermission(role.ActionView, resultResources[t]) != nil {
+			continue
+		}
+		allowed = append(allowed, t)
+	}
+	if len(allowed) == 0 {
+		return []*Result{}, nil
+	}
+	return s.storage.Search(ctx, biz.ID, term, allowed, limit)
+}
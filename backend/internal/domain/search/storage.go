@@ -0,0 +1,148 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"gorm.io/gorm"
+)
+
+// Storage runs the global search over the tables of the searchable domains. It reads them
+// directly (search cannot import every domain's storage) and relies on their search_vector
+// columns and trigram indexes.
+type Storage struct {
+	db *database.Database
+}
+
+func NewStorage(db *database.Database) *Storage {
+	return &Storage{db: db}
+}
+
+// resultColumns are the columns every per-type query selects, in this order.
+const resultColumns = "type, id, title, subtitle, score, occurred_at"
+
+// Search returns the best matches of term among the given types in the business, ranked by
+// score across types, at most limit in total. An exact order number or SKU adds 1 to the
+// score so typing it in full puts that result first.
+func (s *Storage) Search(ctx context.Context, businessID, term string, types []ResultType, limit int) ([]*Result, error) {
+	builders := map[ResultType]func(context.Context, string, string) (*gorm.DB, error){
+		ResultTypeOrder:    s.orderQuery,
+		ResultTypeCustomer: s.customerQuery,
+		ResultTypeProduct:  s.productQuery,
+		ResultTypeExpense:  s.expenseQuery,
+	}
+	parts := make([]string, 0, len(types))
+	args := make([]any, 0, len(types)+1)
+	for _, t := range types {
+		build, ok := builders[t]
+		if !ok {
+			continue
+		}
+		q, err := build(ctx, businessID, term)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, "?")
+		args = append(args, q.Order("score DESC").Limit(limit))
+	}
+	if len(parts) == 0 {
+		return []*Result{}, nil
+	}
+	args = append(args, limit)
+
+	var results []*Result
+	err := s.db.Conn(ctx).
+		Raw("SELECT "+resultColumns+" FROM ("+strings.Join(parts, " UNION ALL ")+") AS results ORDER BY score DESC, occurred_at DESC LIMIT ?", args...).
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (s *Storage) orderQuery(ctx context.Context, businessID, term string) (*gorm.DB, error) {
+	rank, err := database.SearchRank(term, []string{"orders.search_vector"}, []string{"orders.order_number", "customers.name"})
+	if err != nil {
+		return nil, err
+	}
+	fuzzy, fuzzyVars, err := database.FuzzyMatch(term, "customers.name")
+	if err != nil {
+		return nil, err
+	}
+	like := "%" + term + "%"
+	return s.db.Conn(ctx).
+		Table("orders").
+		Select("'order' AS type, orders.id AS id, orders.order_number AS title, COALESCE(customers.name, '') AS subtitle, "+
+			"("+rank.SQL+" + CASE WHEN lower(orders.order_number) = lower(?) THEN 1 ELSE 0 END) AS score, orders.ordered_at AS occurred_at",
+			append(rank.Vars, term)...).
+		Joins("LEFT JOIN customers ON customers.id = orders.customer_id AND customers.deleted_at IS NULL").
+		Where("orders.business_id = ? AND orders.deleted_at IS NULL", businessID).
+		Where("(orders.search_vector @@ websearch_to_tsquery('simple', ?) OR customers.search_vector @@ websearch_to_tsquery('simple', ?) OR orders.order_number ILIKE ? OR customers.name ILIKE ? OR customers.email ILIKE ? OR "+fuzzy+")",
+			append([]any{term, term, like, like, like}, fuzzyVars...)...), nil
+}
+
+func (s *Storage) customerQuery(ctx context.Context, businessID, term string) (*gorm.DB, error) {
+	rank, err := database.SearchRank(term, []string{"customers.search_vector"}, []string{"customers.name"})
+	if err != nil {
+		return nil, err
+	}
+	fuzzy, fuzzyVars, err := database.FuzzyMatch(term, "customers.name")
+	if err != nil {
+		return nil, err
+	}
+	like := "%" + term + "%"
+	return s.db.Conn(ctx).
+		Table("customers").
+		Select("'customer' AS type, customers.id AS id, customers.name AS title, COALESCE(NULLIF(customers.email, ''), customers.phone_number, '') AS subtitle, "+
+			"("+rank.SQL+") AS score, customers.created_at AS occurred_at",
+			rank.Vars...).
+		Where("customers.business_id = ? AND customers.deleted_at IS NULL", businessID).
+		Where("(customers.search_vector @@ websearch_to_tsquery('simple', ?) OR customers.name ILIKE ? OR customers.email ILIKE ? OR customers.phone_number ILIKE ? OR "+fuzzy+")",
+			append([]any{term, like, like, like}, fuzzyVars...)...), nil
+}
+
+func (s *Storage) productQuery(ctx context.Context, businessID, term string) (*gorm.DB, error) {
+	rank, err := database.SearchRank(term, []string{"products.search_vector"}, []string{"products.name"})
+	if err != nil {
+		return nil, err
+	}
+	fuzzy, fuzzyVars, err := database.FuzzyMatch(term, "products.name")
+	if err != nil {
+		return nil, err
+	}
+	like := "%" + term + "%"
+	skuBoost := "CASE WHEN EXISTS (SELECT 1 FROM variants WHERE variants.product_id = products.id AND variants.deleted_at IS NULL AND lower(variants.sku) = lower(?)) THEN 1 ELSE 0 END"
+	return s.db.Conn(ctx).
+		Table("products").
+		Select("'product' AS type, products.id AS id, products.name AS title, COALESCE(categories.name, '') AS subtitle, "+
+			"("+rank.SQL+" + "+skuBoost+") AS score, products.created_at AS occurred_at",
+			append(rank.Vars, term)...).
+		Joins("LEFT JOIN categories ON categories.id = products.category_id AND categories.deleted_at IS NULL").
+		Where("products.business_id = ? AND products.deleted_at IS NULL", businessID).
+		Where("(products.search_vector @@ websearch_to_tsquery('simple', ?) OR products.name ILIKE ? OR "+fuzzy+" OR EXISTS ("+
+			"SELECT 1 FROM variants WHERE variants.product_id = products.id AND variants.deleted_at IS NULL "+
+			"AND (variants.search_vector @@ websearch_to_tsquery('simple', ?) OR variants.sku ILIKE ?)))",
+			append(append([]any{term, like}, fuzzyVars...), term, like)...), nil
+}
+
+func (s *Storage) expenseQuery(ctx context.Context, businessID, term string) (*gorm.DB, error) {
+	rank, err := database.SearchRank(term, nil, []string{"expenses.note", "expenses.category"})
+	if err != nil {
+		return nil, err
+	}
+	fuzzy, fuzzyVars, err := database.FuzzyMatch(term, "expenses.note")
+	if err != nil {
+		return nil, err
+	}
+	like := "%" + term + "%"
+	return s.db.Conn(ctx).
+		Table("expenses").
+		Select("'expense' AS type, expenses.id AS id, COALESCE(NULLIF(expenses.note, ''), expenses.category) AS title, "+
+			"expenses.amount::text || ' ' || expenses.currency AS subtitle, "+
+			"("+rank.SQL+") AS score, expenses.occurred_on::timestamp AS occurred_at",
+			rank.Vars...).
+		Where("expenses.business_id = ? AND expenses.deleted_at IS NULL", businessID).
+		Where("(expenses.note ILIKE ? OR expenses.category ILIKE ? OR "+fuzzy+")",
+			append([]any{like, like}, fuzzyVars...)...), nil
+}
@@ -1,6 +1,12 @@
 package storefront
 
-import "github.com/abdelrahman146/kyora/internal/platform/types/problem"
+import (
+	"math"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+)
 
 func ErrStorefrontNotFound(storefrontPublicID string, err error) *problem.Problem {
 	return problem.NotFound("storefront not found").WithError(err).With("storefrontPublicId", storefrontPublicID).WithCode("storefront.not_found")
@@ -10,6 +16,20 @@ func ErrStorefrontDisabled(storefrontPublicID string) *problem.Problem {
 	return problem.Forbidden("storefront is disabled").With("storefrontPublicId", storefrontPublicID).WithCode("storefront.disabled")
 }
 
+// ErrStorefrontComingSoon is returned to visitors of a coming-soon storefront without a valid
+// access code. It carries what the coming-soon page needs to render.
+func ErrStorefrontComingSoon(biz *business.Business, accessCodeSent bool) *problem.Problem {
+	p := problem.Forbidden("storefront is coming soon").
+		With("storefrontPublicId", biz.StorefrontPublicID).
+		With("brand", biz.Brand).
+		With("message", biz.StorefrontComingSoonMessage).
+		WithCode("storefront.coming_soon")
+	if accessCodeSent {
+		p = p.With("header", AccessCodeHeader).WithCode("storefront.invalid_access_code")
+	}
+	return p
+}
+
 func ErrIdempotencyKeyRequired() *problem.Problem {
 	return problem.BadRequest("Idempotency-Key header is required").With("header", "Idempotency-Key").WithCode("storefront.idempotency_key_required")
 }
@@ -21,3 +41,25 @@ func ErrIdempotencyConflict() *problem.Problem {
 func ErrIdempotencyInProgress() *problem.Problem {
 	return problem.Conflict("request already in progress").WithCode("storefront.idempotency_in_progress")
 }
+
+// ErrTrackedOrderNotFound hides whether the order number or the email was wrong.
+func ErrTrackedOrderNotFound(orderNumber string, err error) *problem.Problem {
+	return problem.NotFound("order not found").WithError(err).With("orderNumber", orderNumber).WithCode("storefront.order_not_found")
+}
+
+func ErrLoginChannelUnavailable(channel LoginChannel) *problem.Problem {
+	return problem.BadRequest("login by this channel is not available").With("channel", channel).WithCode("storefront.login_channel_unavailable")
+}
+
+func ErrCustomerLoginRateLimited(retryAfter time.Duration) *problem.Problem {
+	return problem.TooManyRequests("a login code was sent recently").With("retryAfterSeconds", int(math.Ceil(retryAfter.Seconds()))).WithCode("storefront.login_rate_limited")
+}
+
+// ErrInvalidLoginCode hides whether the code, the link or the contact was wrong.
+func ErrInvalidLoginCode(err error) *problem.Problem {
+	return problem.Unauthorized("invalid or expired login code").WithError(err).WithCode("storefront.invalid_login_code")
+}
+
+func ErrCustomerUnauthorized(err error) *problem.Problem {
+	return problem.Unauthorized("unauthorized").WithError(err).WithCode("storefront.customer_unauthorized")
+}
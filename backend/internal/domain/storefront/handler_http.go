@@ -5,11 +5,17 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/abdelrahman146/kyora/internal/domain/review"
 	"github.com/abdelrahman146/kyora/internal/platform/request"
 	"github.com/abdelrahman146/kyora/internal/platform/response"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
 	"github.com/gin-gonic/gin"
 )
 
+// AccessCodeHeader carries the access code of a coming-soon storefront.
+const AccessCodeHeader = "X-Storefront-Access-Code"
+
 type HttpHandler struct {
 	service *Service
 }
@@ -23,12 +29,14 @@ func NewHttpHandler(service *Service) *HttpHandler {
 // @Description Returns public business info, categories, products, and variants for a storefront
 // @Tags storefront
 // @Param storefrontPublicId path string true "Storefront Public ID"
+// @Param X-Storefront-Access-Code header string false "Access code of a coming-soon storefront"
 // @Success 200 {object} CatalogResponse
+// @Failure 403 {object} problem.Problem
 // @Failure 404 {object} problem.Problem
 // @Router /v1/storefront/{storefrontPublicId}/catalog [get]
 func (h *HttpHandler) GetCatalog(c *gin.Context) {
 	storefrontID := c.Param("storefrontPublicId")
-	data, err := h.service.GetCatalog(c.Request.Context(), storefrontID)
+	data, err := h.service.GetCatalog(c.Request.Context(), storefrontID, accessCode(c))
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -41,12 +49,14 @@ func (h *HttpHandler) GetCatalog(c *gin.Context) {
 // @Description Returns shipping zones for the storefront (public)
 // @Tags storefront
 // @Param storefrontPublicId path string true "Storefront Public ID"
+// @Param X-Storefront-Access-Code header string false "Access code of a coming-soon storefront"
 // @Success 200 {array} storefront.PublicShippingZone
+// @Failure 403 {object} problem.Problem
 // @Failure 404 {object} problem.Problem
 // @Router /v1/storefront/{storefrontPublicId}/shipping-zones [get]
 func (h *HttpHandler) ListShippingZones(c *gin.Context) {
 	storefrontID := c.Param("storefrontPublicId")
-	data, err := h.service.ListShippingZones(c.Request.Context(), storefrontID)
+	data, err := h.service.ListShippingZones(c.Request.Context(), storefrontID, accessCode(c))
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -60,10 +70,12 @@ func (h *HttpHandler) ListShippingZones(c *gin.Context) {
 // @Tags storefront
 // @Param storefrontPublicId path string true "Storefront Public ID"
 // @Param Idempotency-Key header string true "Idempotency key"
+// @Param X-Storefront-Access-Code header string false "Access code of a coming-soon storefront"
 // @Accept json
 // @Produce json
 // @Success 201 {object} CreateOrderResponse
 // @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
 // @Failure 409 {object} problem.Problem
 // @Failure 429 {object} problem.Problem
 // @Router /v1/storefront/{storefrontPublicId}/orders [post]
@@ -85,10 +97,284 @@ func (h *HttpHandler) CreateOrder(c *gin.Context) {
 		return
 	}
 
-	out, err := h.service.CreatePendingOrder(c.Request.Context(), storefrontID, idempotencyKey, body, clientIP, &req)
+	out, err := h.service.CreatePendingOrder(c.Request.Context(), storefrontID, accessCode(c), idempotencyKey, body, clientIP, &req)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 	response.SuccessJSON(c, http.StatusCreated, out)
 }
+
+// TrackOrder godoc
+// @Summary Track a storefront order
+// @Description Returns the status, shipments and ETA of an order for its public tracking page. The email must be the one the order was placed with; otherwise the order is reported as not found.
+// @Tags storefront
+// @Param storefrontPublicId path string true "Storefront Public ID"
+// @Param orderNumber path string true "Order number"
+// @Param email query string true "Customer email of the order"
+// @Produce json
+// @Success 200 {object} TrackOrderResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 429 {object} problem.Problem
+// @Router /v1/storefront/{storefrontPublicId}/orders/{orderNumber}/track [get]
+func (h *HttpHandler) TrackOrder(c *gin.Context) {
+	out, err := h.service.TrackOrder(c.Request.Context(), c.Param("storefrontPublicId"), c.Param("orderNumber"), c.Query("email"), c.ClientIP())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	// The page carries order details and changes with every status update: keep it out of
+	// shared caches.
+	c.Header("Cache-Control", "private, no-store")
+	response.SuccessJSON(c, http.StatusOK, out)
+}
+
+type listProductReviewsQuery struct {
+	Page     int               `form:"page" binding:"omitempty,min=1"`
+	PageSize int               `form:"pageSize" binding:"omitempty,min=1,max=50"`
+	Kind     review.ReviewKind `form:"kind" binding:"omitempty,oneof=review question"`
+}
+
+// ListProductReviews godoc
+// @Summary List storefront product reviews
+// @Description Returns the approved reviews and answered questions of a product, newest first
+// @Tags storefront
+// @Param storefrontPublicId path string true "Storefront Public ID"
+// @Param productId path string true "Product ID"
+// @Param X-Storefront-Access-Code header string false "Access code of a coming-soon storefront"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size (max 50)"
+// @Param kind query string false "review or question"
+// @Success 200 {object} list.ListResponse[review.PublicReviewResponse]
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /v1/storefront/{storefrontPublicId}/products/{productId}/reviews [get]
+func (h *HttpHandler) ListProductReviews(c *gin.Context) {
+	var query listProductReviewsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, review.ErrReviewInvalidQuery(err))
+		return
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+	listReq := list.NewListRequest(query.Page, query.PageSize, nil, "")
+	items, totalCount, err := h.service.ListProductReviews(c.Request.Context(), c.Param("storefrontPublicId"), accessCode(c), c.Param("productId"), query.Kind, listReq)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	hasMore := int64(query.Page*query.PageSize) < totalCount
+	response.SuccessJSON(c, http.StatusOK, list.NewListResponse(review.ToPublicReviewResponses(items), query.Page, query.PageSize, totalCount, hasMore))
+}
+
+// SubmitProductReview godoc
+// @Summary Submit storefront product review
+// @Description Submits a review (rating 1-5) or a question (no rating) about a product; it is published once the seller approves it
+// @Tags storefront
+// @Param storefrontPublicId path string true "Storefront Public ID"
+// @Param productId path string true "Product ID"
+// @Param X-Storefront-Access-Code header string false "Access code of a coming-soon storefront"
+// @Accept json
+// @Produce json
+// @Param body body review.SubmitReviewRequest true "Review or question"
+// @Success 202 {object} review.PublicReviewResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 429 {object} problem.Problem
+// @Router /v1/storefront/{storefrontPublicId}/products/{productId}/reviews [post]
+func (h *HttpHandler) SubmitProductReview(c *gin.Context) {
+	var req review.SubmitReviewRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	r, err := h.service.SubmitProductReview(c.Request.Context(), c.Param("storefrontPublicId"), accessCode(c), c.Param("productId"), c.ClientIP(), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusAccepted, review.ToPublicReviewResponses([]*review.Review{r})[0])
+}
+
+// RequestCustomerLogin godoc
+// @Summary Request a storefront login code
+// @Description Sends a one-time login code to a customer by email (with a magic link) or, for a phone number, by WhatsApp. Answers 204 whether or not the contact belongs to a customer.
+// @Tags storefront
+// @Param storefrontPublicId path string true "Storefront Public ID"
+// @Param X-Storefront-Access-Code header string false "Access code of a coming-soon storefront"
+// @Accept json
+// @Param body body RequestCustomerLoginRequest true "Email or phone number"
+// @Success 204
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 429 {object} problem.Problem
+// @Router /v1/storefront/{storefrontPublicId}/auth/login [post]
+func (h *HttpHandler) RequestCustomerLogin(c *gin.Context) {
+	var req RequestCustomerLoginRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.service.RequestCustomerLogin(c.Request.Context(), c.Param("storefrontPublicId"), accessCode(c), c.ClientIP(), &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
+
+// VerifyCustomerLogin godoc
+// @Summary Sign in to a storefront
+// @Description Exchanges a magic link token, or a login code with the email or phone it was sent to, for a customer token
+// @Tags storefront
+// @Param storefrontPublicId path string true "Storefront Public ID"
+// @Param X-Storefront-Access-Code header string false "Access code of a coming-soon storefront"
+// @Accept json
+// @Produce json
+// @Param body body VerifyCustomerLoginRequest true "Magic link token, or code with email or phone"
+// @Success 200 {object} CustomerLoginResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 429 {object} problem.Problem
+// @Router /v1/storefront/{storefrontPublicId}/auth/verify [post]
+func (h *HttpHandler) VerifyCustomerLogin(c *gin.Context) {
+	var req VerifyCustomerLoginRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	out, err := h.service.VerifyCustomerLogin(c.Request.Context(), c.Param("storefrontPublicId"), accessCode(c), c.ClientIP(), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.Header("Cache-Control", "private, no-store")
+	response.SuccessJSON(c, http.StatusOK, out)
+}
+
+// GetMe godoc
+// @Summary Get the signed-in storefront customer
+// @Tags storefront
+// @Security BearerAuth
+// @Param storefrontPublicId path string true "Storefront Public ID"
+// @Produce json
+// @Success 200 {object} PortalCustomer
+// @Failure 401 {object} problem.Problem
+// @Router /v1/storefront/{storefrontPublicId}/me [get]
+func (h *HttpHandler) GetMe(c *gin.Context) {
+	sess, err := CustomerSessionFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, toPortalCustomer(sess.Customer))
+}
+
+type listMyOrdersQuery struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"pageSize" binding:"omitempty,min=1,max=50"`
+}
+
+// ListMyOrders godoc
+// @Summary List the signed-in customer's orders
+// @Description Returns the orders of the signed-in customer, newest first
+// @Tags storefront
+// @Security BearerAuth
+// @Param storefrontPublicId path string true "Storefront Public ID"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size (max 50)"
+// @Produce json
+// @Success 200 {object} list.ListResponse[PortalOrder]
+// @Failure 400 {object} problem.Problem
+// @Failure 401 {object} problem.Problem
+// @Router /v1/storefront/{storefrontPublicId}/me/orders [get]
+func (h *HttpHandler) ListMyOrders(c *gin.Context) {
+	sess, err := CustomerSessionFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query listMyOrdersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, problem.BadRequest("invalid query parameters").WithError(err))
+		return
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 20
+	}
+	out, err := h.service.ListMyOrders(c.Request.Context(), sess, query.Page, query.PageSize)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, out)
+}
+
+// GetMyOrder godoc
+// @Summary Get one of the signed-in customer's orders
+// @Tags storefront
+// @Security BearerAuth
+// @Param storefrontPublicId path string true "Storefront Public ID"
+// @Param orderNumber path string true "Order number"
+// @Produce json
+// @Success 200 {object} PortalOrder
+// @Failure 401 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /v1/storefront/{storefrontPublicId}/me/orders/{orderNumber} [get]
+func (h *HttpHandler) GetMyOrder(c *gin.Context) {
+	sess, err := CustomerSessionFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	out, err := h.service.GetMyOrder(c.Request.Context(), sess, c.Param("orderNumber"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, out)
+}
+
+// ListMyAddresses godoc
+// @Summary List the signed-in customer's addresses
+// @Tags storefront
+// @Security BearerAuth
+// @Param storefrontPublicId path string true "Storefront Public ID"
+// @Produce json
+// @Success 200 {array} PortalAddress
+// @Failure 401 {object} problem.Problem
+// @Router /v1/storefront/{storefrontPublicId}/me/addresses [get]
+func (h *HttpHandler) ListMyAddresses(c *gin.Context) {
+	sess, err := CustomerSessionFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	out, err := h.service.ListMyAddresses(c.Request.Context(), sess)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, out)
+}
+
+// accessCode returns the coming-soon access code sent with the request. Responses to such
+// requests are private to the visitor, so they are kept out of shared caches.
+func accessCode(c *gin.Context) string {
+	code := c.GetHeader(AccessCodeHeader)
+	if code != "" {
+		c.Header("Cache-Control", "private, no-store")
+	}
+	return code
+}
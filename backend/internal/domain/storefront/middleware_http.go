@@ -0,0 +1,67 @@
+package storefront
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/region"
+	"github.com/abdelrahman146/kyora/internal/platform/response"
+	"github.com/abdelrahman146/kyora/internal/platform/types/ctxkey"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	CustomerSessionKey = ctxkey.New("storefront_customer_session")
+)
+
+// RouteToStorefrontRegion routes the request to the region holding the storefront's business,
+// so every query of the public storefront routes reads the right database. Unknown
+// storefronts stay in the home region, where the handlers answer 404.
+func RouteToStorefrontRegion(service *Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		name, err := service.business.ResolveStorefrontRegion(ctx, c.Param("storefrontPublicId"))
+		if err != nil {
+			if !database.IsRecordNotFound(err) {
+				response.Error(c, problem.InternalError().WithError(err))
+				return
+			}
+			c.Next()
+			return
+		}
+		c.Request = c.Request.WithContext(region.WithRegion(ctx, name))
+		c.Next()
+	}
+}
+
+// EnforceCustomerAuthentication requires a storefront customer token issued for the storefront
+// of the route. Responses are private to the customer, so they are kept out of shared caches.
+func EnforceCustomerAuthentication(service *Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "private, no-store")
+		token := strings.TrimSpace(auth.JwtFromContext(c))
+		sess, err := service.AuthenticateCustomer(c.Request.Context(), c.Param("storefrontPublicId"), accessCode(c), token)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		c.Set(CustomerSessionKey, sess)
+		c.Next()
+	}
+}
+
+func CustomerSessionFromContext(c *gin.Context) (*CustomerSession, error) {
+	val, exists := c.Get(CustomerSessionKey)
+	if !exists {
+		logger.FromContext(c.Request.Context()).Error("customer session not found in context, make sure EnforceCustomerAuthentication middleware is applied")
+		return nil, problem.InternalError().WithError(errors.New("customer session not found in context"))
+	}
+	if sess, ok := val.(*CustomerSession); ok {
+		return sess, nil
+	}
+	return nil, problem.InternalError().WithError(errors.New("customer session not found in context"))
+}
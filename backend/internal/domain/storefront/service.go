@@ -3,6 +3,7 @@ package storefront
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/subtle"
 	"fmt"
 	"net/mail"
 	"strings"
@@ -12,12 +13,15 @@ import (
 	"github.com/abdelrahman146/kyora/internal/domain/customer"
 	"github.com/abdelrahman146/kyora/internal/domain/inventory"
 	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/domain/review"
 	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/email"
 	"github.com/abdelrahman146/kyora/internal/platform/types/asset"
 	"github.com/abdelrahman146/kyora/internal/platform/types/atomic"
 	"github.com/abdelrahman146/kyora/internal/platform/types/list"
 	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
 	"github.com/abdelrahman146/kyora/internal/platform/utils/throttle"
+	"github.com/abdelrahman146/kyora/internal/platform/whatsapp"
 )
 
 type Service struct {
@@ -27,9 +31,13 @@ type Service struct {
 	inventory       *inventory.Service
 	customer        *customer.Service
 	orders          *order.Service
+	reviews         *review.Service
+	emailClient     email.Client
+	emailInfo       email.EmailInfo
+	whatsapp        whatsapp.Client
 }
 
-func NewService(storage *Storage, atomicProcessor atomic.AtomicProcessor, businessSvc *business.Service, inventorySvc *inventory.Service, customerSvc *customer.Service, orderSvc *order.Service) *Service {
+func NewService(storage *Storage, atomicProcessor atomic.AtomicProcessor, businessSvc *business.Service, inventorySvc *inventory.Service, customerSvc *customer.Service, orderSvc *order.Service, reviewSvc *review.Service) *Service {
 	return &Service{
 		storage:         storage,
 		atomicProcessor: atomicProcessor,
@@ -37,6 +45,7 @@ func NewService(storage *Storage, atomicProcessor atomic.AtomicProcessor, busine
 		inventory:       inventorySvc,
 		customer:        customerSvc,
 		orders:          orderSvc,
+		reviews:         reviewSvc,
 	}
 }
 
@@ -48,6 +57,8 @@ type PublicBusiness struct {
 	Logo               *asset.AssetReference    `json:"logo,omitempty"`
 	CountryCode        string                   `json:"countryCode"`
 	Currency           string                   `json:"currency"`
+	VatRate            string                   `json:"vatRate"`
+	PricesIncludeVat   bool                     `json:"pricesIncludeVat"`
 	StorefrontPublicID string                   `json:"storefrontPublicId"`
 	StorefrontEnabled  bool                     `json:"storefrontEnabled"`
 	StorefrontTheme    business.StorefrontTheme `json:"storefrontTheme"`
@@ -64,14 +75,18 @@ type PublicBusiness struct {
 }
 
 type PublicVariant struct {
-	ID        string                       `json:"id"`
-	ProductID string                       `json:"productId"`
-	Code      string                       `json:"code"`
-	Name      string                       `json:"name"`
-	SKU       string                       `json:"sku"`
-	SalePrice string                       `json:"salePrice"`
-	Currency  string                       `json:"currency"`
-	Photos    inventory.AssetReferenceList `json:"photos"`
+	ID        string `json:"id"`
+	ProductID string `json:"productId"`
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	SKU       string `json:"sku"`
+	// SalePrice is what the variant sells for now. While a promo runs, CompareAtPrice holds
+	// the regular price and SaleEndsAt when the promo ends.
+	SalePrice      string                       `json:"salePrice"`
+	CompareAtPrice string                       `json:"compareAtPrice,omitempty"`
+	SaleEndsAt     *time.Time                   `json:"saleEndsAt,omitempty"`
+	Currency       string                       `json:"currency"`
+	Photos         inventory.AssetReferenceList `json:"photos"`
 }
 
 type PublicProduct struct {
@@ -81,6 +96,8 @@ type PublicProduct struct {
 	CategoryID  string                       `json:"categoryId"`
 	Photos      inventory.AssetReferenceList `json:"photos"`
 	Variants    []PublicVariant              `json:"variants"`
+	// Rating aggregates the product's approved reviews; absent until it has one.
+	Rating *review.ProductRating `json:"rating,omitempty"`
 }
 
 type PublicCategory struct {
@@ -104,7 +121,7 @@ type PublicShippingZone struct {
 	FreeShippingThreshold string   `json:"freeShippingThreshold"`
 }
 
-func (s *Service) GetCatalog(ctx context.Context, storefrontPublicID string) (*CatalogResponse, error) {
+func (s *Service) GetCatalog(ctx context.Context, storefrontPublicID, accessCode string) (*CatalogResponse, error) {
 	biz, err := s.business.GetBusinessByStorefrontPublicID(ctx, storefrontPublicID)
 	if err != nil {
 		return nil, ErrStorefrontNotFound(storefrontPublicID, err)
@@ -112,6 +129,9 @@ func (s *Service) GetCatalog(ctx context.Context, storefrontPublicID string) (*C
 	if !biz.StorefrontEnabled {
 		return nil, ErrStorefrontDisabled(storefrontPublicID)
 	}
+	if err := checkAccess(biz, accessCode); err != nil {
+		return nil, err
+	}
 
 	cats, err := s.inventory.ListCategories(ctx, nil, biz)
 	if err != nil {
@@ -126,22 +146,28 @@ func (s *Service) GetCatalog(ctx context.Context, storefrontPublicID string) (*C
 		return nil, err
 	}
 
+	now := time.Now()
 	variantsByProduct := map[string][]PublicVariant{}
 	for _, v := range vars {
 		photos := v.Photos
 		if photos == nil {
 			photos = inventory.AssetReferenceList{}
 		}
-		variantsByProduct[v.ProductID] = append(variantsByProduct[v.ProductID], PublicVariant{
+		pv := PublicVariant{
 			ID:        v.ID,
 			ProductID: v.ProductID,
 			Code:      v.Code,
 			Name:      v.Name,
 			SKU:       v.SKU,
-			SalePrice: v.SalePrice.String(),
+			SalePrice: v.CurrentPrice(now).String(),
 			Currency:  v.Currency,
 			Photos:    photos,
-		})
+		}
+		if v.PromoActive(now) {
+			pv.CompareAtPrice = v.SalePrice.String()
+			pv.SaleEndsAt = v.PromoEndsAt
+		}
+		variantsByProduct[v.ProductID] = append(variantsByProduct[v.ProductID], pv)
 	}
 
 	outCats := make([]PublicCategory, 0, len(cats))
@@ -149,20 +175,33 @@ func (s *Service) GetCatalog(ctx context.Context, storefrontPublicID string) (*C
 		outCats = append(outCats, PublicCategory{ID: c.ID, Name: c.Name, Descriptor: c.Descriptor})
 	}
 
+	productIDs := make([]string, 0, len(prods))
+	for _, p := range prods {
+		productIDs = append(productIDs, p.ID)
+	}
+	ratings, err := s.reviews.ProductRatings(ctx, biz, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	outProds := make([]PublicProduct, 0, len(prods))
 	for _, p := range prods {
 		photos := p.Photos
 		if photos == nil {
 			photos = inventory.AssetReferenceList{}
 		}
-		outProds = append(outProds, PublicProduct{
+		pp := PublicProduct{
 			ID:          p.ID,
 			Name:        p.Name,
 			Description: p.Description,
 			CategoryID:  p.CategoryID,
 			Photos:      photos,
 			Variants:    variantsByProduct[p.ID],
-		})
+		}
+		if rating, ok := ratings[p.ID]; ok {
+			pp.Rating = &rating
+		}
+		outProds = append(outProds, pp)
 	}
 
 	resp := &CatalogResponse{
@@ -174,6 +213,8 @@ func (s *Service) GetCatalog(ctx context.Context, storefrontPublicID string) (*C
 			Logo:               biz.Logo,
 			CountryCode:        biz.CountryCode,
 			Currency:           biz.Currency,
+			VatRate:            biz.VatRate.String(),
+			PricesIncludeVat:   biz.PricesIncludeVat,
 			StorefrontPublicID: biz.StorefrontPublicID,
 			StorefrontEnabled:  biz.StorefrontEnabled,
 			StorefrontTheme:    biz.StorefrontTheme,
@@ -194,7 +235,7 @@ func (s *Service) GetCatalog(ctx context.Context, storefrontPublicID string) (*C
 	return resp, nil
 }
 
-func (s *Service) ListShippingZones(ctx context.Context, storefrontPublicID string) ([]PublicShippingZone, error) {
+func (s *Service) ListShippingZones(ctx context.Context, storefrontPublicID, accessCode string) ([]PublicShippingZone, error) {
 	biz, err := s.business.GetBusinessByStorefrontPublicID(ctx, storefrontPublicID)
 	if err != nil {
 		return nil, ErrStorefrontNotFound(storefrontPublicID, err)
@@ -202,6 +243,9 @@ func (s *Service) ListShippingZones(ctx context.Context, storefrontPublicID stri
 	if !biz.StorefrontEnabled {
 		return nil, ErrStorefrontDisabled(storefrontPublicID)
 	}
+	if err := checkAccess(biz, accessCode); err != nil {
+		return nil, err
+	}
 
 	zones, err := s.business.ListShippingZonesPublic(ctx, biz)
 	if err != nil {
@@ -226,6 +270,50 @@ func (s *Service) ListShippingZones(ctx context.Context, storefrontPublicID stri
 	return out, nil
 }
 
+// ListProductReviews returns a page of the approved reviews and questions of a storefront
+// product.
+func (s *Service) ListProductReviews(ctx context.Context, storefrontPublicID, accessCode, productID string, kind review.ReviewKind, req *list.ListRequest) ([]*review.Review, int64, error) {
+	biz, err := s.business.GetBusinessByStorefrontPublicID(ctx, storefrontPublicID)
+	if err != nil {
+		return nil, 0, ErrStorefrontNotFound(storefrontPublicID, err)
+	}
+	if !biz.StorefrontEnabled {
+		return nil, 0, ErrStorefrontDisabled(storefrontPublicID)
+	}
+	if err := checkAccess(biz, accessCode); err != nil {
+		return nil, 0, err
+	}
+	return s.reviews.ListPublicReviews(ctx, biz, productID, kind, req)
+}
+
+// SubmitProductReview adds a visitor's review or question to the seller's moderation queue.
+func (s *Service) SubmitProductReview(ctx context.Context, storefrontPublicID, accessCode, productID, clientIP string, req *review.SubmitReviewRequest) (*review.Review, error) {
+	biz, err := s.business.GetBusinessByStorefrontPublicID(ctx, storefrontPublicID)
+	if err != nil {
+		return nil, ErrStorefrontNotFound(storefrontPublicID, err)
+	}
+	if !biz.StorefrontEnabled {
+		return nil, ErrStorefrontDisabled(storefrontPublicID)
+	}
+	if err := checkAccess(biz, accessCode); err != nil {
+		return nil, err
+	}
+	return s.reviews.SubmitReview(ctx, biz, productID, clientIP, req)
+}
+
+// checkAccess lets visitors into a coming-soon storefront only with its access code.
+func checkAccess(biz *business.Business, accessCode string) error {
+	if !biz.StorefrontComingSoon {
+		return nil
+	}
+	accessCode = strings.TrimSpace(accessCode)
+	if accessCode != "" && biz.StorefrontAccessCode != "" &&
+		subtle.ConstantTimeCompare([]byte(accessCode), []byte(biz.StorefrontAccessCode)) == 1 {
+		return nil
+	}
+	return ErrStorefrontComingSoon(biz, accessCode != "")
+}
+
 func (s *Service) listAllProducts(ctx context.Context, biz *business.Business) ([]*inventory.Product, error) {
 	const pageSize = 100
 	const maxPages = 100
@@ -275,6 +363,8 @@ type CreateOrderCustomer struct {
 	Name              string `json:"name" binding:"required"`
 	PhoneNumber       string `json:"phoneNumber" binding:"omitempty"`
 	InstagramUsername string `json:"instagramUsername" binding:"omitempty"`
+	// EmailMarketingConsent is the checkout opt-in to marketing emails.
+	EmailMarketingConsent bool `json:"emailMarketingConsent"`
 }
 
 type CreateOrderShippingAddress struct {
@@ -298,11 +388,12 @@ type CreateOrderResponse struct {
 	OrderNumber   string `json:"orderNumber"`
 	Status        string `json:"status"`
 	PaymentStatus string `json:"paymentStatus"`
+	VAT           string `json:"vat"`
 	Total         string `json:"total"`
 	Currency      string `json:"currency"`
 }
 
-func (s *Service) CreatePendingOrder(ctx context.Context, storefrontPublicID, idempotencyKey string, requestBody []byte, clientIP string, req *CreateOrderRequest) (*CreateOrderResponse, error) {
+func (s *Service) CreatePendingOrder(ctx context.Context, storefrontPublicID, accessCode, idempotencyKey string, requestBody []byte, clientIP string, req *CreateOrderRequest) (*CreateOrderResponse, error) {
 	idempotencyKey = strings.TrimSpace(idempotencyKey)
 	if idempotencyKey == "" {
 		return nil, ErrIdempotencyKeyRequired()
@@ -315,6 +406,9 @@ func (s *Service) CreatePendingOrder(ctx context.Context, storefrontPublicID, id
 	if err != nil {
 		return nil, ErrStorefrontNotFound(storefrontPublicID, err)
 	}
+	if err := checkAccess(biz, accessCode); err != nil {
+		return nil, err
+	}
 
 	h := sha256.Sum256(requestBody)
 	requestHash := Hash(h)
@@ -336,6 +430,7 @@ func (s *Service) CreatePendingOrder(ctx context.Context, storefrontPublicID, id
 			OrderNumber:   ord.OrderNumber,
 			Status:        string(ord.Status),
 			PaymentStatus: string(ord.PaymentStatus),
+			VAT:           ord.VAT.String(),
 			Total:         ord.Total.String(),
 			Currency:      ord.Currency,
 		}, nil
@@ -382,6 +477,7 @@ func (s *Service) CreatePendingOrder(ctx context.Context, storefrontPublicID, id
 					OrderNumber:   ord.OrderNumber,
 					Status:        string(ord.Status),
 					PaymentStatus: string(ord.PaymentStatus),
+					VAT:           ord.VAT.String(),
 					Total:         ord.Total.String(),
 					Currency:      ord.Currency,
 				}
@@ -396,6 +492,8 @@ func (s *Service) CreatePendingOrder(ctx context.Context, storefrontPublicID, id
 			PhoneNumber:       req.Customer.PhoneNumber,
 			PhoneCode:         req.ShippingAddress.PhoneCode,
 			InstagramUsername: req.Customer.InstagramUsername,
+
+			EmailMarketingConsent: req.Customer.EmailMarketingConsent,
 		})
 		if err != nil {
 			return err
@@ -447,6 +545,7 @@ func (s *Service) CreatePendingOrder(ctx context.Context, storefrontPublicID, id
 			OrderNumber:   ord.OrderNumber,
 			Status:        string(ord.Status),
 			PaymentStatus: string(ord.PaymentStatus),
+			VAT:           ord.VAT.String(),
 			Total:         ord.Total.String(),
 			Currency:      ord.Currency,
 		}
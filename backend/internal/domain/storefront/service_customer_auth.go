@@ -0,0 +1,337 @@
+package storefront
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/email"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/hash"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/throttle"
+	"github.com/abdelrahman146/kyora/internal/platform/whatsapp"
+	"github.com/spf13/viper"
+)
+
+// LoginChannel is how a storefront customer receives their login code.
+type LoginChannel string
+
+const (
+	LoginChannelEmail    LoginChannel = "email"
+	LoginChannelWhatsapp LoginChannel = "whatsapp"
+)
+
+const (
+	// customerLoginCooldown is how long a customer waits before asking for another code.
+	customerLoginCooldown = time.Minute
+	// customerLoginMaxAttempts is how many wrong codes burn a pending login.
+	customerLoginMaxAttempts = 5
+)
+
+// SetEmailClient wires the email client that sends login codes and magic links to customers.
+func (s *Service) SetEmailClient(client email.Client) {
+	s.emailClient = client
+	s.emailInfo = email.NewEmail()
+}
+
+// SetWhatsappClient wires the WhatsApp client that sends login codes. Without it, customers
+// can only sign in by email.
+func (s *Service) SetWhatsappClient(client whatsapp.Client) {
+	s.whatsapp = client
+}
+
+// RequestCustomerLoginRequest asks for a login code by email or, with a phone number in
+// international format, by WhatsApp.
+type RequestCustomerLoginRequest struct {
+	Email string `json:"email" binding:"omitempty,email,max=254"`
+	Phone string `json:"phone" binding:"omitempty,max=32"`
+}
+
+// VerifyCustomerLoginRequest signs a customer in with the token of a magic link, or with the
+// code they received and the email or phone number they asked for it with.
+type VerifyCustomerLoginRequest struct {
+	Token string `json:"token" binding:"omitempty,max=64"`
+	Email string `json:"email" binding:"omitempty,email,max=254"`
+	Phone string `json:"phone" binding:"omitempty,max=32"`
+	Code  string `json:"code" binding:"omitempty,len=6,numeric"`
+}
+
+// PortalCustomer is the signed-in customer's own profile.
+type PortalCustomer struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Email          string `json:"email,omitempty"`
+	CountryCode    string `json:"countryCode"`
+	PhoneCode      string `json:"phoneCode,omitempty"`
+	PhoneNumber    string `json:"phoneNumber,omitempty"`
+	WhatsappNumber string `json:"whatsappNumber,omitempty"`
+}
+
+// CustomerLoginResponse carries the customer token to send as "Authorization: Bearer <token>"
+// to the storefront's /me endpoints.
+type CustomerLoginResponse struct {
+	Token     string         `json:"token"`
+	ExpiresAt time.Time      `json:"expiresAt"`
+	Customer  PortalCustomer `json:"customer"`
+}
+
+// CustomerSession is the storefront and customer a customer token was checked against.
+type CustomerSession struct {
+	Business *business.Business
+	Customer *customer.Customer
+}
+
+func toPortalCustomer(c *customer.Customer) PortalCustomer {
+	return PortalCustomer{
+		ID:             c.ID,
+		Name:           c.Name,
+		Email:          c.Email.String,
+		CountryCode:    c.CountryCode,
+		PhoneCode:      c.PhoneCode.String,
+		PhoneNumber:    c.PhoneNumber.String,
+		WhatsappNumber: c.WhatsappNumber.String,
+	}
+}
+
+// loginContact validates that exactly one of email and phone is set and returns the channel
+// it implies with the normalized contact.
+func loginContact(emailAddr, phone string) (LoginChannel, string, error) {
+	emailAddr, phone = strings.TrimSpace(emailAddr), strings.TrimSpace(phone)
+	switch {
+	case emailAddr != "" && phone != "":
+		return "", "", problem.BadRequest("send either an email or a phone number").With("field", "email")
+	case emailAddr != "":
+		if _, err := mail.ParseAddress(emailAddr); err != nil {
+			return "", "", problem.BadRequest("invalid email").With("field", "email")
+		}
+		return LoginChannelEmail, strings.ToLower(emailAddr), nil
+	case phone != "":
+		digits := customer.PhoneDigits(phone)
+		if len(digits) < 8 || len(digits) > 15 {
+			return "", "", problem.BadRequest("invalid phone number").With("field", "phone")
+		}
+		return LoginChannelWhatsapp, digits, nil
+	}
+	return "", "", problem.BadRequest("email or phone is required").With("field", "email")
+}
+
+func (s *Service) storefrontForCustomer(ctx context.Context, storefrontPublicID, accessCode string) (*business.Business, error) {
+	biz, err := s.business.GetBusinessByStorefrontPublicID(ctx, storefrontPublicID)
+	if err != nil {
+		return nil, ErrStorefrontNotFound(storefrontPublicID, err)
+	}
+	if !biz.StorefrontEnabled {
+		return nil, ErrStorefrontDisabled(storefrontPublicID)
+	}
+	if err := checkAccess(biz, accessCode); err != nil {
+		return nil, err
+	}
+	return biz, nil
+}
+
+func (s *Service) findLoginCustomer(ctx context.Context, biz *business.Business, channel LoginChannel, contact string) (*customer.Customer, error) {
+	if channel == LoginChannelEmail {
+		return s.customer.FindCustomerByContact(ctx, biz, contact, "")
+	}
+	return s.customer.FindCustomerByContact(ctx, biz, "", contact)
+}
+
+// RequestCustomerLogin sends a one-time code to a customer of the storefront, by email (with
+// a magic link when storefront.base_url is set) or by WhatsApp. Whether the contact belongs
+// to a customer is never revealed: unknown contacts get the same answer and no message.
+func (s *Service) RequestCustomerLogin(ctx context.Context, storefrontPublicID, accessCode, clientIP string, req *RequestCustomerLoginRequest) error {
+	biz, err := s.storefrontForCustomer(ctx, storefrontPublicID, accessCode)
+	if err != nil {
+		return err
+	}
+	channel, contact, err := loginContact(req.Email, req.Phone)
+	if err != nil {
+		return err
+	}
+	if (channel == LoginChannelWhatsapp && s.whatsapp == nil) || (channel == LoginChannelEmail && s.emailClient == nil) {
+		return ErrLoginChannelUnavailable(channel)
+	}
+
+	ip := strings.TrimSpace(clientIP)
+	if ip == "" {
+		ip = "unknown"
+	}
+	if !throttle.Allow(s.storage.Cache(), fmt.Sprintf("storefront:%s:login:%s", biz.ID, ip), 10*time.Minute, 10, 0) {
+		return problem.TooManyRequests("rate limit exceeded")
+	}
+	cooldownKey := fmt.Sprintf("cd:storefront:login:%s:%s", biz.ID, contact)
+	if allowed, retryAfter := throttle.Cooldown(s.storage.Cache(), cooldownKey, customerLoginCooldown); !allowed {
+		return ErrCustomerLoginRateLimited(retryAfter)
+	}
+
+	cust, err := s.findLoginCustomer(ctx, biz, channel, contact)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	code, err := id.RandomNumber(6)
+	if err != nil {
+		return err
+	}
+	codeHash, err := hash.Password(code)
+	if err != nil {
+		return err
+	}
+	payload := &CustomerLoginPayload{BusinessID: biz.ID, CustomerID: cust.ID, Channel: channel, CodeHash: codeHash}
+	token, _, err := s.storage.CreateCustomerLogin(payload)
+	if err != nil {
+		return err
+	}
+
+	if channel == LoginChannelEmail {
+		err = s.sendLoginEmail(ctx, biz, cust, code, token)
+	} else {
+		err = s.whatsapp.SendAuthCode(ctx, "+"+contact, code)
+	}
+	if err != nil {
+		_ = s.storage.ConsumeCustomerLogin(token, payload)
+		_ = s.storage.Cache().Delete(cooldownKey)
+		logger.FromContext(ctx).Error("failed to send customer login code", "businessId", biz.ID, "customerId", cust.ID, "channel", channel, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (s *Service) sendLoginEmail(ctx context.Context, biz *business.Business, cust *customer.Customer, code, token string) error {
+	businessName := biz.Brand
+	if businessName == "" {
+		businessName = biz.Name
+	}
+	loginURL := ""
+	if base := strings.TrimRight(strings.TrimSpace(viper.GetString(config.StorefrontBaseURL)), "/"); base != "" {
+		loginURL = fmt.Sprintf("%s/%s/account/login?token=%s", base, url.PathEscape(biz.StorefrontPublicID), url.QueryEscape(token))
+	}
+	ttl := viper.GetInt(config.StorefrontCustomerLoginTTLSeconds)
+	data := map[string]any{
+		"customerName":  cust.Name,
+		"businessName":  businessName,
+		"code":          code,
+		"loginURL":      loginURL,
+		"expiryMinutes": ttl / 60,
+		"supportEmail":  biz.SupportEmail,
+		"productName":   s.emailInfo.ProductName,
+		"currentYear":   fmt.Sprintf("%d", time.Now().Year()),
+	}
+	subject := fmt.Sprintf("Your %s login code", businessName)
+	_, err := s.emailClient.SendTemplate(ctx, email.TemplateCustomerLogin, []string{cust.Email.String}, s.emailInfo.FormattedFrom(), subject, data)
+	return err
+}
+
+// VerifyCustomerLogin exchanges a magic link token, or a login code with the contact it was
+// sent to, for a customer token. Logins are single use; a pending login is dropped after
+// customerLoginMaxAttempts wrong codes.
+func (s *Service) VerifyCustomerLogin(ctx context.Context, storefrontPublicID, accessCode, clientIP string, req *VerifyCustomerLoginRequest) (*CustomerLoginResponse, error) {
+	biz, err := s.storefrontForCustomer(ctx, storefrontPublicID, accessCode)
+	if err != nil {
+		return nil, err
+	}
+	ip := strings.TrimSpace(clientIP)
+	if ip == "" {
+		ip = "unknown"
+	}
+	if !throttle.Allow(s.storage.Cache(), fmt.Sprintf("storefront:%s:login-verify:%s", biz.ID, ip), 10*time.Minute, 30, 0) {
+		return nil, problem.TooManyRequests("rate limit exceeded")
+	}
+
+	var (
+		token   string
+		payload *CustomerLoginPayload
+	)
+	if t := strings.TrimSpace(req.Token); t != "" {
+		token = t
+		payload, err = s.storage.GetCustomerLogin(token)
+		if err != nil || payload.BusinessID != biz.ID {
+			return nil, ErrInvalidLoginCode(err)
+		}
+	} else {
+		if req.Code == "" {
+			return nil, problem.BadRequest("token or code is required").With("field", "code")
+		}
+		channel, contact, err := loginContact(req.Email, req.Phone)
+		if err != nil {
+			return nil, err
+		}
+		cust, err := s.findLoginCustomer(ctx, biz, channel, contact)
+		if err != nil {
+			return nil, ErrInvalidLoginCode(err)
+		}
+		token, payload, err = s.storage.GetCustomerLoginByCustomer(biz.ID, cust.ID)
+		if err != nil {
+			return nil, ErrInvalidLoginCode(err)
+		}
+		if !hash.ValidatePassword(req.Code, payload.CodeHash) {
+			payload.Attempts++
+			if payload.Attempts >= customerLoginMaxAttempts {
+				_ = s.storage.ConsumeCustomerLogin(token, payload)
+			} else {
+				_ = s.storage.SaveCustomerLoginAttempt(token, payload)
+			}
+			return nil, ErrInvalidLoginCode(nil)
+		}
+	}
+	if time.Now().After(payload.ExpAt) {
+		_ = s.storage.ConsumeCustomerLogin(token, payload)
+		return nil, ErrInvalidLoginCode(nil)
+	}
+	if err := s.storage.ConsumeCustomerLogin(token, payload); err != nil {
+		return nil, err
+	}
+
+	cust, err := s.customer.GetCustomerByID(ctx, nil, biz, payload.CustomerID)
+	if err != nil || cust.ErasedAt.Valid {
+		return nil, ErrInvalidLoginCode(err)
+	}
+	jwt, expAt, err := auth.NewCustomerJwtToken(cust.ID, biz.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &CustomerLoginResponse{Token: jwt, ExpiresAt: expAt, Customer: toPortalCustomer(cust)}, nil
+}
+
+// AuthenticateCustomer checks a customer token against the storefront it is used on and
+// returns the signed-in customer. Tokens stop working once the customer is erased.
+func (s *Service) AuthenticateCustomer(ctx context.Context, storefrontPublicID, accessCode, token string) (*CustomerSession, error) {
+	biz, err := s.storefrontForCustomer(ctx, storefrontPublicID, accessCode)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		return nil, ErrCustomerUnauthorized(nil)
+	}
+	claims, err := auth.ParseCustomerJwtToken(token)
+	if err != nil {
+		return nil, ErrCustomerUnauthorized(err)
+	}
+	if claims.BusinessID != biz.ID {
+		return nil, ErrCustomerUnauthorized(nil)
+	}
+	cust, err := s.customer.GetCustomerByID(ctx, nil, biz, claims.CustomerID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrCustomerUnauthorized(err)
+		}
+		return nil, err
+	}
+	if cust.ErasedAt.Valid {
+		return nil, ErrCustomerUnauthorized(nil)
+	}
+	return &CustomerSession{Business: biz, Customer: cust}, nil
+}
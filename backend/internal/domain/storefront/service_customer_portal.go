@@ -0,0 +1,144 @@
+package storefront
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+)
+
+// PortalOrderItem is a line of one of the customer's orders.
+type PortalOrderItem struct {
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice string `json:"unitPrice"`
+	Total     string `json:"total"`
+}
+
+// PortalAddress is one of the customer's saved addresses.
+type PortalAddress struct {
+	ID          string `json:"id"`
+	CountryCode string `json:"countryCode"`
+	State       string `json:"state"`
+	City        string `json:"city"`
+	Street      string `json:"street,omitempty"`
+	ZipCode     string `json:"zipCode,omitempty"`
+	PhoneCode   string `json:"phoneCode"`
+	PhoneNumber string `json:"phoneNumber"`
+}
+
+// PortalOrder is the customer's view of one of their orders. It leaves out what only the
+// seller sees: costs, notes, tags and custom fields.
+type PortalOrder struct {
+	OrderNumber   string                   `json:"orderNumber"`
+	Status        order.OrderStatus        `json:"status"`
+	PaymentStatus order.OrderPaymentStatus `json:"paymentStatus"`
+	OrderedAt     time.Time                `json:"orderedAt"`
+	ShippedAt     *time.Time               `json:"shippedAt,omitempty"`
+	FulfilledAt   *time.Time               `json:"fulfilledAt,omitempty"`
+	CancelledAt   *time.Time               `json:"cancelledAt,omitempty"`
+	Currency      string                   `json:"currency"`
+	Subtotal      string                   `json:"subtotal"`
+	ShippingFee   string                   `json:"shippingFee"`
+	Discount      string                   `json:"discount"`
+	VAT           string                   `json:"vat"`
+	Total         string                   `json:"total"`
+	Items         []PortalOrderItem        `json:"items"`
+	// ShippingAddress is where the order ships to, when it ships.
+	ShippingAddress *PortalAddress `json:"shippingAddress,omitempty"`
+}
+
+func toPortalAddress(a *customer.CustomerAddress) PortalAddress {
+	return PortalAddress{
+		ID:          a.ID,
+		CountryCode: a.CountryCode,
+		State:       a.State,
+		City:        a.City,
+		Street:      a.Street.String,
+		ZipCode:     a.ZipCode.String,
+		PhoneCode:   a.PhoneCode,
+		PhoneNumber: a.PhoneNumber,
+	}
+}
+
+func toPortalOrder(o *order.Order) PortalOrder {
+	out := PortalOrder{
+		OrderNumber:   o.OrderNumber,
+		Status:        o.Status,
+		PaymentStatus: o.PaymentStatus,
+		OrderedAt:     o.OrderedAt,
+		ShippedAt:     transformer.NullTimePtr(o.ShippedAt),
+		FulfilledAt:   transformer.NullTimePtr(o.FulfilledAt),
+		CancelledAt:   transformer.NullTimePtr(o.CancelledAt),
+		Currency:      o.Currency,
+		Subtotal:      o.Subtotal.String(),
+		ShippingFee:   o.ShippingFee.String(),
+		Discount:      o.Discount.String(),
+		VAT:           o.VAT.String(),
+		Total:         o.Total.String(),
+		Items:         make([]PortalOrderItem, 0, len(o.Items)),
+	}
+	for _, it := range o.Items {
+		out.Items = append(out.Items, PortalOrderItem{
+			Name:      trackedItemName(it),
+			Quantity:  it.Quantity,
+			UnitPrice: it.UnitPrice.String(),
+			Total:     it.Total.String(),
+		})
+	}
+	if o.ShippingAddress != nil {
+		addr := toPortalAddress(o.ShippingAddress)
+		out.ShippingAddress = &addr
+	}
+	return out
+}
+
+// ListMyOrders returns the signed-in customer's orders, newest first.
+func (s *Service) ListMyOrders(ctx context.Context, sess *CustomerSession, page, pageSize int) (*list.ListResponse[PortalOrder], error) {
+	req := list.NewListRequest(page, pageSize, []string{"-orderedAt"}, "")
+	orders, total, err := s.orders.ListOrders(ctx, nil, sess.Business, req, &order.ListOrdersFilters{CustomerID: sess.Customer.ID})
+	if err != nil {
+		return nil, err
+	}
+	items := make([]PortalOrder, 0, len(orders))
+	for _, o := range orders {
+		items = append(items, toPortalOrder(o))
+	}
+	hasMore := int64(page*pageSize) < total
+	return list.NewListResponse(items, page, pageSize, total, hasMore), nil
+}
+
+// GetMyOrder returns one of the signed-in customer's orders. Orders of other customers
+// answer like unknown order numbers.
+func (s *Service) GetMyOrder(ctx context.Context, sess *CustomerSession, orderNumber string) (*PortalOrder, error) {
+	ord, err := s.orders.GetOrderByOrderNumber(ctx, nil, sess.Business, strings.TrimSpace(orderNumber))
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrTrackedOrderNotFound(orderNumber, err)
+		}
+		return nil, err
+	}
+	if ord.CustomerID != sess.Customer.ID {
+		return nil, ErrTrackedOrderNotFound(orderNumber, nil)
+	}
+	out := toPortalOrder(ord)
+	return &out, nil
+}
+
+// ListMyAddresses returns the signed-in customer's saved addresses.
+func (s *Service) ListMyAddresses(ctx context.Context, sess *CustomerSession) ([]PortalAddress, error) {
+	addresses, err := s.customer.ListCustomerAddresses(ctx, nil, sess.Business, sess.Customer.ID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]PortalAddress, 0, len(addresses))
+	for _, a := range addresses {
+		out = append(out, toPortalAddress(a))
+	}
+	return out, nil
+}
@@ -0,0 +1,163 @@
+package storefront
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/asset"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/throttle"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+)
+
+// TrackedOrderBusiness is who the customer can contact about a tracked order.
+type TrackedOrderBusiness struct {
+	Name           string                `json:"name"`
+	Brand          string                `json:"brand,omitempty"`
+	Logo           *asset.AssetReference `json:"logo,omitempty"`
+	SupportEmail   string                `json:"supportEmail,omitempty"`
+	PhoneNumber    string                `json:"phoneNumber,omitempty"`
+	WhatsappNumber string                `json:"whatsappNumber,omitempty"`
+}
+
+// TrackedOrderItem is a line of a tracked order, without prices.
+type TrackedOrderItem struct {
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+}
+
+// TrackedShipment is a carrier shipment of a tracked order.
+type TrackedShipment struct {
+	Carrier             business.CarrierCode `json:"carrier"`
+	TrackingNumber      string               `json:"trackingNumber"`
+	TrackingURL         string               `json:"trackingUrl,omitempty"`
+	CreatedAt           time.Time            `json:"createdAt"`
+	EstimatedDeliveryAt *time.Time           `json:"estimatedDeliveryAt,omitempty"`
+}
+
+// TrackOrderResponse is the public view of an order on its tracking page. It leaves out
+// prices, addresses and customer details.
+type TrackOrderResponse struct {
+	OrderNumber        string                   `json:"orderNumber"`
+	Status             order.OrderStatus        `json:"status"`
+	PaymentStatus      order.OrderPaymentStatus `json:"paymentStatus"`
+	OrderedAt          time.Time                `json:"orderedAt"`
+	PlacedAt           *time.Time               `json:"placedAt,omitempty"`
+	ReadyForShipmentAt *time.Time               `json:"readyForShipmentAt,omitempty"`
+	ShippedAt          *time.Time               `json:"shippedAt,omitempty"`
+	FulfilledAt        *time.Time               `json:"fulfilledAt,omitempty"`
+	CancelledAt        *time.Time               `json:"cancelledAt,omitempty"`
+	ReturnedAt         *time.Time               `json:"returnedAt,omitempty"`
+	// EstimatedDeliveryAt is the latest shipment's ETA while the order is on its way.
+	EstimatedDeliveryAt *time.Time           `json:"estimatedDeliveryAt,omitempty"`
+	Items               []TrackedOrderItem   `json:"items"`
+	Shipments           []TrackedShipment    `json:"shipments"`
+	Business            TrackedOrderBusiness `json:"business"`
+}
+
+// TrackOrder returns the tracking view of an order of the storefront's business. The
+// customer proves the order is theirs with the email it was placed with; a wrong email
+// answers like an unknown order number. Tracking links keep working while the storefront
+// is disabled or coming soon, since sellers send them for orders taken on any channel.
+func (s *Service) TrackOrder(ctx context.Context, storefrontPublicID, orderNumber, email, clientIP string) (*TrackOrderResponse, error) {
+	biz, err := s.business.GetBusinessByStorefrontPublicID(ctx, storefrontPublicID)
+	if err != nil {
+		return nil, ErrStorefrontNotFound(storefrontPublicID, err)
+	}
+
+	ip := strings.TrimSpace(clientIP)
+	if ip == "" {
+		ip = "unknown"
+	}
+	throttleKey := fmt.Sprintf("storefront:%s:track:%s", biz.ID, ip)
+	if !throttle.Allow(s.storage.Cache(), throttleKey, 1*time.Minute, 30, 0) {
+		return nil, problem.TooManyRequests("rate limit exceeded")
+	}
+
+	email = strings.TrimSpace(email)
+	if _, err := mail.ParseAddress(email); err != nil {
+		return nil, problem.BadRequest("invalid email").With("field", "email")
+	}
+
+	ord, err := s.orders.GetOrderByOrderNumber(ctx, nil, biz, strings.TrimSpace(orderNumber))
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrTrackedOrderNotFound(orderNumber, err)
+		}
+		return nil, err
+	}
+	if ord.Customer == nil || !ord.Customer.Email.Valid || !strings.EqualFold(strings.TrimSpace(ord.Customer.Email.String), email) {
+		return nil, ErrTrackedOrderNotFound(orderNumber, nil)
+	}
+
+	labels, err := s.orders.ListOrderShippingLabels(ctx, nil, biz, ord.ID)
+	if err != nil {
+		return nil, err
+	}
+	return toTrackOrderResponse(biz, ord, labels), nil
+}
+
+func toTrackOrderResponse(biz *business.Business, ord *order.Order, labels []*business.ShippingLabel) *TrackOrderResponse {
+	out := &TrackOrderResponse{
+		OrderNumber:        ord.OrderNumber,
+		Status:             ord.Status,
+		PaymentStatus:      ord.PaymentStatus,
+		OrderedAt:          ord.OrderedAt,
+		PlacedAt:           transformer.NullTimePtr(ord.PlacedAt),
+		ReadyForShipmentAt: transformer.NullTimePtr(ord.ReadyForShipmentAt),
+		ShippedAt:          transformer.NullTimePtr(ord.ShippedAt),
+		FulfilledAt:        transformer.NullTimePtr(ord.FulfilledAt),
+		CancelledAt:        transformer.NullTimePtr(ord.CancelledAt),
+		ReturnedAt:         transformer.NullTimePtr(ord.ReturnedAt),
+		Items:              make([]TrackedOrderItem, 0, len(ord.Items)),
+		Shipments:          make([]TrackedShipment, 0, len(labels)),
+		Business: TrackedOrderBusiness{
+			Name:           biz.Name,
+			Brand:          biz.Brand,
+			Logo:           biz.Logo,
+			SupportEmail:   biz.SupportEmail,
+			PhoneNumber:    biz.PhoneNumber,
+			WhatsappNumber: biz.WhatsappNumber,
+		},
+	}
+	for _, it := range ord.Items {
+		out.Items = append(out.Items, TrackedOrderItem{Name: trackedItemName(it), Quantity: it.Quantity})
+	}
+	// Labels are listed newest first.
+	for _, l := range labels {
+		out.Shipments = append(out.Shipments, TrackedShipment{
+			Carrier:             l.Carrier,
+			TrackingNumber:      l.TrackingNumber,
+			TrackingURL:         l.Carrier.TrackingURL(l.TrackingNumber),
+			CreatedAt:           l.CreatedAt,
+			EstimatedDeliveryAt: l.EstimatedDeliveryAt(),
+		})
+	}
+	if ord.Status == order.OrderStatusReadyForShipment || ord.Status == order.OrderStatusShipped {
+		if len(labels) > 0 {
+			out.EstimatedDeliveryAt = labels[0].EstimatedDeliveryAt()
+		}
+	}
+	return out
+}
+
+// trackedItemName names an order line after its product and variant.
+func trackedItemName(it *order.OrderItem) string {
+	name := ""
+	if it.Product != nil {
+		name = it.Product.Name
+	}
+	if it.Variant != nil && it.Variant.Name != "" {
+		if name == "" || strings.HasPrefix(it.Variant.Name, name) {
+			return it.Variant.Name
+		}
+		return name + " - " + it.Variant.Name
+	}
+	return name
+}
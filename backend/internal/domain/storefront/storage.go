@@ -2,9 +2,14 @@ package storefront
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/abdelrahman146/kyora/internal/platform/cache"
+	"github.com/abdelrahman146/kyora/internal/platform/config"
 	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/spf13/viper"
 	"gorm.io/gorm"
 )
 
@@ -36,3 +41,96 @@ func (s *Storage) GetRequestByKey(ctx context.Context, businessID, key string) (
 		s.requests.ScopeEquals(StorefrontRequestSchema.IdempotencyKey, key),
 	)
 }
+
+const (
+	customerLoginLinkPrefix = "sf:login:link:"
+	customerLoginCodePrefix = "sf:login:code:"
+)
+
+// CustomerLoginPayload is a pending storefront customer login. It is reached by its magic link
+// token, or through the customer when they type the code instead.
+type CustomerLoginPayload struct {
+	BusinessID string       `json:"businessId"`
+	CustomerID string       `json:"customerId"`
+	Channel    LoginChannel `json:"channel"`
+	CodeHash   string       `json:"codeHash"`
+	Attempts   int          `json:"attempts"`
+	ExpAt      time.Time    `json:"expAt"`
+}
+
+func customerLoginCodeKey(businessID, customerID string) string {
+	return customerLoginCodePrefix + businessID + ":" + customerID
+}
+
+// CreateCustomerLogin stores a pending login for the customer, replacing any earlier one, and
+// returns its magic link token and expiry.
+func (s *Storage) CreateCustomerLogin(payload *CustomerLoginPayload) (string, time.Time, error) {
+	token, err := id.RandomString(32)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	ttl := viper.GetInt32(config.StorefrontCustomerLoginTTLSeconds)
+	payload.Attempts = 0
+	payload.ExpAt = time.Now().Add(time.Duration(ttl) * time.Second)
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	codeKey := customerLoginCodeKey(payload.BusinessID, payload.CustomerID)
+	if previous, err := s.cache.Get(codeKey); err == nil {
+		_ = s.cache.Delete(customerLoginLinkPrefix + string(previous))
+	}
+	if err := s.cache.SetX(customerLoginLinkPrefix+token, data, ttl); err != nil {
+		return "", time.Time{}, err
+	}
+	if err := s.cache.SetX(codeKey, []byte(token), ttl); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, payload.ExpAt, nil
+}
+
+// GetCustomerLogin returns the pending login of a magic link token.
+func (s *Storage) GetCustomerLogin(token string) (*CustomerLoginPayload, error) {
+	data, err := s.cache.Get(customerLoginLinkPrefix + token)
+	if err != nil {
+		return nil, err
+	}
+	var payload CustomerLoginPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// GetCustomerLoginByCustomer returns the pending login of a customer with its token.
+func (s *Storage) GetCustomerLoginByCustomer(businessID, customerID string) (string, *CustomerLoginPayload, error) {
+	token, err := s.cache.Get(customerLoginCodeKey(businessID, customerID))
+	if err != nil {
+		return "", nil, err
+	}
+	payload, err := s.GetCustomerLogin(string(token))
+	if err != nil {
+		return "", nil, err
+	}
+	return string(token), payload, nil
+}
+
+// SaveCustomerLoginAttempt stores the updated attempt count of a pending login, keeping its
+// expiry.
+func (s *Storage) SaveCustomerLoginAttempt(token string, payload *CustomerLoginPayload) error {
+	ttl := int32(time.Until(payload.ExpAt).Seconds())
+	if ttl <= 0 {
+		return s.ConsumeCustomerLogin(token, payload)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return s.cache.SetX(customerLoginLinkPrefix+token, data, ttl)
+}
+
+// ConsumeCustomerLogin removes a pending login so it cannot be used again.
+func (s *Storage) ConsumeCustomerLogin(token string, payload *CustomerLoginPayload) error {
+	_ = s.cache.Delete(customerLoginCodeKey(payload.BusinessID, payload.CustomerID))
+	return s.cache.Delete(customerLoginLinkPrefix + token)
+}
@@ -0,0 +1,41 @@
+package storeimport
+
+import "github.com/abdelrahman146/kyora/internal/platform/types/problem"
+
+func ErrStoreImportNotFound(importID string, err error) error {
+	return problem.NotFound("store import not found").
+		With("importId", importID).
+		WithError(err).
+		WithCode("store_import.not_found")
+}
+
+// ErrStoreImportInProgress is returned when the business already has an import running.
+func ErrStoreImportInProgress(importID string) error {
+	return problem.Conflict("another store import is in progress").
+		With("importId", importID).
+		WithCode("store_import.in_progress")
+}
+
+// ErrInvalidStoreURL is returned for store addresses the importer does not connect to.
+func ErrInvalidStoreURL(reason string) error {
+	return problem.BadRequest("invalid store URL: "+reason).
+		With("field", "storeUrl").
+		WithCode("store_import.invalid_store_url")
+}
+
+// ErrMissingCredentials is returned when the credentials the platform's API needs are missing.
+func ErrMissingCredentials(platform Platform, fields ...string) error {
+	return problem.BadRequest("missing API credentials").
+		With("platform", platform).
+		With("fields", fields).
+		WithCode("store_import.missing_credentials")
+}
+
+// ErrInvalidExportFile is returned for uploaded files that are not a product export of the
+// platform.
+func ErrInvalidExportFile(platform Platform, reason string) error {
+	return problem.BadRequest(reason).
+		With("field", "file").
+		With("platform", platform).
+		WithCode("store_import.invalid_file")
+}
@@ -0,0 +1,38 @@
+package storeimport
+
+import (
+	"context"
+
+	"github.com/abdelrahman146/kyora/internal/platform/bus"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+)
+
+// BusHandler runs queued store imports.
+type BusHandler struct {
+	svc *Service
+}
+
+// NewBusHandler registers store import listeners on the event bus.
+func NewBusHandler(b *bus.Bus, svc *Service) {
+	h := &BusHandler{svc: svc}
+	b.Handle(bus.StoreImportRequestedTopic, "storeimport.run", h.HandleStoreImportRequested)
+}
+
+// HandleStoreImportRequested runs a queued store import. Malformed events are logged and
+// dropped; failures of the import are recorded on it rather than retried.
+func (h *BusHandler) HandleStoreImportRequested(event any) error {
+	e, ok := event.(*bus.StoreImportRequestedEvent)
+	if !ok {
+		logger.FromContext(context.Background()).Error("invalid event type for StoreImportRequestedEvent")
+		return nil
+	}
+	if e.BusinessID == "" || e.ImportID == "" {
+		logger.FromContext(e.Ctx).Error("missing required fields in StoreImportRequestedEvent", "businessId", e.BusinessID, "importId", e.ImportID)
+		return nil
+	}
+	if err := h.svc.RunImport(e.Ctx, e.BusinessID, e.ImportID); err != nil {
+		logger.FromContext(e.Ctx).Error("failed to run store import", "error", err, "businessId", e.BusinessID, "importId", e.ImportID)
+		return err
+	}
+	return nil
+}
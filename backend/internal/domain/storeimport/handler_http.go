@@ -0,0 +1,195 @@
+package storeimport
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/request"
+	"github.com/abdelrahman146/kyora/internal/platform/response"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// HttpHandler handles the store import requests of a business.
+type HttpHandler struct {
+	service *Service
+}
+
+// NewHttpHandler creates a new store import HTTP handler.
+func NewHttpHandler(service *Service) *HttpHandler {
+	return &HttpHandler{service: service}
+}
+
+// StartImport starts an import through the store's API
+//
+// @Summary      Import a store through its API
+// @Description  Queues a background import of the products, variants, images, customers and historical orders of a Shopify or WooCommerce store. Shopify needs the store's myshopify.com address and an Admin API access token; WooCommerce the https store address and a REST API consumer key and secret. Poll the import for its progress. Entities imported before from the same platform are skipped, so starting a new import resumes one that failed. One import runs per business at a time.
+// @Tags         storeimport
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body StartStoreImportRequest true "Store and credentials"
+// @Success      202 {object} storeimport.StoreImportResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/store-imports [post]
+// @Security     BearerAuth
+func (h *HttpHandler) StartImport(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := business.BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req StartStoreImportRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	imp, err := h.service.StartImport(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusAccepted, ToStoreImportResponse(imp))
+}
+
+// StartFileImport starts an import from a product CSV export
+//
+// @Summary      Import a store's product export
+// @Description  Queues a background import of the products, variants and images in a Shopify or WooCommerce product CSV export. Exports hold no customers or orders; import through the API for those.
+// @Tags         storeimport
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        platform formData string true "shopify or woocommerce"
+// @Param        file formData file true "Product CSV export"
+// @Success      202 {object} storeimport.StoreImportResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      409 {object} problem.Problem
+// @Failure      413 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/store-imports/file [post]
+// @Security     BearerAuth
+func (h *HttpHandler) StartFileImport(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := business.BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	fh, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.Error(c, problem.PayloadTooLarge("request body too large").WithCode("request.body_too_large"))
+			return
+		}
+		response.Error(c, problem.BadRequest("export file is required").With("field", "file").WithError(err))
+		return
+	}
+	var req StartFileImportRequest
+	if err := c.ShouldBind(&req); err != nil {
+		response.Error(c, problem.BadRequest("platform must be shopify or woocommerce").With("field", "platform").WithError(err))
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		response.Error(c, problem.InternalError().WithError(err))
+		return
+	}
+	imp, err := h.service.StartFileImport(c.Request.Context(), actor, biz, req.Platform, data)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusAccepted, ToStoreImportResponse(imp))
+}
+
+// ListImports returns the business's recent store imports
+//
+// @Summary      List store imports
+// @Description  Returns the business's 20 most recent store imports, newest first
+// @Tags         storeimport
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Success      200 {array} storeimport.StoreImportResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/store-imports [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListImports(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := business.BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	imports, err := h.service.ListImports(c.Request.Context(), actor, biz)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToStoreImportResponses(imports))
+}
+
+// GetImport returns a store import with its progress
+//
+// @Summary      Get store import
+// @Description  Returns the status, current stage and per-kind counts (imported, skipped, failed) of an import, with the first 100 entities that could not be imported.
+// @Tags         storeimport
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        importId path string true "Import ID"
+// @Success      200 {object} storeimport.StoreImportResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/store-imports/{importId} [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetImport(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := business.BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	imp, err := h.service.GetImport(c.Request.Context(), actor, biz, c.Param("importId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToStoreImportResponse(imp))
+}
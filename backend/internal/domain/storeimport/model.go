@@ -0,0 +1,275 @@
+package storeimport
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"gorm.io/gorm"
+)
+
+const (
+	StoreImportTable  = "store_imports"
+	StoreImportStruct = "StoreImport"
+	StoreImportPrefix = "sim"
+
+	StoreImportFileTable = "store_import_files"
+
+	ImportRecordTable  = "store_import_records"
+	ImportRecordStruct = "ImportRecord"
+	ImportRecordPrefix = "sir"
+)
+
+// Platform is the e-commerce platform a store is imported from.
+type Platform string
+
+const (
+	PlatformShopify     Platform = "shopify"
+	PlatformWooCommerce Platform = "woocommerce"
+)
+
+// Source is how an import reads the store: its REST API or an uploaded export file.
+type Source string
+
+const (
+	SourceAPI  Source = "api"
+	SourceFile Source = "file"
+)
+
+// ImportStatus tracks an import from queueing to completion.
+type ImportStatus string
+
+const (
+	ImportStatusPending   ImportStatus = "pending"
+	ImportStatusRunning   ImportStatus = "running"
+	ImportStatusCompleted ImportStatus = "completed"
+	ImportStatusFailed    ImportStatus = "failed"
+)
+
+// ImportStage is the kind of entity a running import is working on. Stages run in order:
+// products, customers, then orders, which need the other two.
+type ImportStage string
+
+const (
+	ImportStageProducts  ImportStage = "products"
+	ImportStageCustomers ImportStage = "customers"
+	ImportStageOrders    ImportStage = "orders"
+	ImportStageDone      ImportStage = "done"
+)
+
+// Credentials authenticate an import against the store API: an Admin API access token for
+// Shopify, a REST API consumer key and secret for WooCommerce.
+type Credentials struct {
+	AccessToken    string `json:"accessToken,omitempty"`
+	ConsumerKey    string `json:"consumerKey,omitempty"`
+	ConsumerSecret string `json:"consumerSecret,omitempty"`
+}
+
+func (c Credentials) Value() (driver.Value, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (c *Credentials) Scan(value any) error {
+	if c == nil {
+		return problem.InternalError().WithError(errors.New("Credentials scan into nil receiver"))
+	}
+	switch v := value.(type) {
+	case nil:
+		*c = Credentials{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return problem.InternalError().WithError(errors.New("unexpected scan type for Credentials"))
+	}
+}
+
+// ImportCounts is how many entities of one kind an import created, skipped because an earlier
+// import already brought them over, or failed to import.
+type ImportCounts struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"`
+	Failed   int `json:"failed"`
+}
+
+// ImportIssue is an entity of the store that could not be imported.
+type ImportIssue struct {
+	Kind       RecordKind `json:"kind"`
+	ExternalID string     `json:"externalId"`
+	Message    string     `json:"message"`
+}
+
+// ImportProgress holds the counts per kind and the first issues of an import.
+type ImportProgress struct {
+	Products  ImportCounts  `json:"products"`
+	Variants  ImportCounts  `json:"variants"`
+	Customers ImportCounts  `json:"customers"`
+	Orders    ImportCounts  `json:"orders"`
+	Issues    []ImportIssue `json:"issues"`
+}
+
+// maxImportIssues bounds the issues kept on an import; later ones are only counted.
+const maxImportIssues = 100
+
+// counts returns the counts of kind.
+func (p *ImportProgress) counts(kind RecordKind) *ImportCounts {
+	switch kind {
+	case RecordKindProduct:
+		return &p.Products
+	case RecordKindVariant:
+		return &p.Variants
+	case RecordKindCustomer:
+		return &p.Customers
+	default:
+		return &p.Orders
+	}
+}
+
+// fail counts an entity that could not be imported and keeps its issue.
+func (p *ImportProgress) fail(kind RecordKind, externalID, message string) {
+	p.counts(kind).Failed++
+	if len(p.Issues) < maxImportIssues {
+		p.Issues = append(p.Issues, ImportIssue{Kind: kind, ExternalID: externalID, Message: message})
+	}
+}
+
+func (p ImportProgress) Value() (driver.Value, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (p *ImportProgress) Scan(value any) error {
+	if p == nil {
+		return problem.InternalError().WithError(errors.New("ImportProgress scan into nil receiver"))
+	}
+	switch v := value.(type) {
+	case nil:
+		*p = ImportProgress{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return problem.InternalError().WithError(errors.New("unexpected scan type for ImportProgress"))
+	}
+}
+
+// StoreImport is a background import of a Shopify or WooCommerce store into a business.
+// API credentials are kept only until the import ends.
+type StoreImport struct {
+	ID            string         `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID    string         `gorm:"column:business_id;type:text;not null;index" json:"businessId"`
+	RequestedByID string         `gorm:"column:requested_by_id;type:text;not null" json:"requestedById"`
+	Platform      Platform       `gorm:"column:platform;type:text;not null" json:"platform"`
+	Source        Source         `gorm:"column:source;type:text;not null" json:"source"`
+	StoreURL      string         `gorm:"column:store_url;type:text" json:"storeUrl"`
+	Credentials   Credentials    `gorm:"column:credentials;type:jsonb;not null;default:'{}'" json:"-"`
+	Status        ImportStatus   `gorm:"column:status;type:text;not null;default:'pending'" json:"status"`
+	Stage         ImportStage    `gorm:"column:stage;type:text;not null;default:'products'" json:"stage"`
+	Progress      ImportProgress `gorm:"column:progress;type:jsonb;not null;default:'{}'" json:"progress"`
+	Error         string         `gorm:"column:error;type:text" json:"error,omitempty"`
+	StartedAt     sql.NullTime   `gorm:"column:started_at" json:"startedAt"`
+	CompletedAt   sql.NullTime   `gorm:"column:completed_at" json:"completedAt"`
+	CreatedAt     time.Time      `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+	UpdatedAt     time.Time      `gorm:"column:updated_at;type:timestamp;autoUpdateTime" json:"updatedAt"`
+}
+
+func (m *StoreImport) TableName() string { return StoreImportTable }
+
+func (m *StoreImport) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(StoreImportPrefix)
+	}
+	return
+}
+
+var StoreImportSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	Status     schema.Field
+	CreatedAt  schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	Status:     schema.NewField("status", "status"),
+	CreatedAt:  schema.NewField("created_at", "createdAt"),
+}
+
+// StoreImportFile holds the uploaded export file of a file import until the import ends.
+type StoreImportFile struct {
+	ImportID   string `gorm:"column:import_id;primaryKey;type:text"`
+	BusinessID string `gorm:"column:business_id;type:text;not null;index"`
+	Content    []byte `gorm:"column:content;type:bytea;not null"`
+}
+
+func (m *StoreImportFile) TableName() string { return StoreImportFileTable }
+
+var StoreImportFileSchema = struct {
+	ImportID   schema.Field
+	BusinessID schema.Field
+}{
+	ImportID:   schema.NewField("import_id", "importId"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+}
+
+// RecordKind is the kind of entity an import record points to.
+type RecordKind string
+
+const (
+	RecordKindProduct  RecordKind = "product"
+	RecordKindVariant  RecordKind = "variant"
+	RecordKindCustomer RecordKind = "customer"
+	RecordKindOrder    RecordKind = "order"
+)
+
+// ImportRecord maps an entity of the store to the Kyora entity it was imported as. Later
+// imports of the same store skip mapped entities, and orders find their variants and
+// customers through it.
+type ImportRecord struct {
+	ID         string     `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID string     `gorm:"column:business_id;type:text;not null;uniqueIndex:store_import_record_idx,priority:1" json:"businessId"`
+	Platform   Platform   `gorm:"column:platform;type:text;not null;uniqueIndex:store_import_record_idx,priority:2" json:"platform"`
+	Kind       RecordKind `gorm:"column:kind;type:text;not null;uniqueIndex:store_import_record_idx,priority:3" json:"kind"`
+	ExternalID string     `gorm:"column:external_id;type:text;not null;uniqueIndex:store_import_record_idx,priority:4" json:"externalId"`
+	RecordID   string     `gorm:"column:record_id;type:text;not null" json:"recordId"`
+	ImportID   string     `gorm:"column:import_id;type:text;not null" json:"importId"`
+	CreatedAt  time.Time  `gorm:"column:created_at;type:timestamp;autoCreateTime" json:"createdAt"`
+}
+
+func (m *ImportRecord) TableName() string { return ImportRecordTable }
+
+func (m *ImportRecord) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(ImportRecordPrefix)
+	}
+	return
+}
+
+var ImportRecordSchema = struct {
+	ID         schema.Field
+	BusinessID schema.Field
+	Platform   schema.Field
+	Kind       schema.Field
+	ExternalID schema.Field
+}{
+	ID:         schema.NewField("id", "id"),
+	BusinessID: schema.NewField("business_id", "businessId"),
+	Platform:   schema.NewField("platform", "platform"),
+	Kind:       schema.NewField("kind", "kind"),
+	ExternalID: schema.NewField("external_id", "externalId"),
+}
@@ -0,0 +1,18 @@
+package storeimport
+
+// StartStoreImportRequest starts an import through the store's API. Shopify needs the Admin
+// API access token of a custom app with read access to products, customers and orders;
+// WooCommerce a REST API consumer key and secret with read permission.
+type StartStoreImportRequest struct {
+	Platform       Platform `json:"platform" binding:"required,oneof=shopify woocommerce"`
+	StoreURL       string   `json:"storeUrl" binding:"required,max=2048"`
+	AccessToken    string   `json:"accessToken" binding:"omitempty,max=512"`
+	ConsumerKey    string   `json:"consumerKey" binding:"omitempty,max=512"`
+	ConsumerSecret string   `json:"consumerSecret" binding:"omitempty,max=512"`
+}
+
+// StartFileImportRequest is the form of an import from a product CSV export; the file is
+// sent as the "file" part.
+type StartFileImportRequest struct {
+	Platform Platform `form:"platform" binding:"required,oneof=shopify woocommerce"`
+}
@@ -0,0 +1,59 @@
+package storeimport
+
+import "time"
+
+// StoreImportResponse is the API response for a store import. Credentials are never
+// returned.
+type StoreImportResponse struct {
+	ID          string         `json:"id"`
+	BusinessID  string         `json:"businessId"`
+	Platform    Platform       `json:"platform"`
+	Source      Source         `json:"source"`
+	StoreURL    string         `json:"storeUrl,omitempty"`
+	Status      ImportStatus   `json:"status"`
+	Stage       ImportStage    `json:"stage"`
+	Progress    ImportProgress `json:"progress"`
+	Error       string         `json:"error,omitempty"`
+	StartedAt   *time.Time     `json:"startedAt,omitempty"`
+	CompletedAt *time.Time     `json:"completedAt,omitempty"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+}
+
+// ToStoreImportResponse converts a StoreImport model to its API response.
+func ToStoreImportResponse(m *StoreImport) StoreImportResponse {
+	resp := StoreImportResponse{
+		ID:         m.ID,
+		BusinessID: m.BusinessID,
+		Platform:   m.Platform,
+		Source:     m.Source,
+		StoreURL:   m.StoreURL,
+		Status:     m.Status,
+		Stage:      m.Stage,
+		Progress:   m.Progress,
+		Error:      m.Error,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+	if resp.Progress.Issues == nil {
+		resp.Progress.Issues = []ImportIssue{}
+	}
+	if m.StartedAt.Valid {
+		t := m.StartedAt.Time
+		resp.StartedAt = &t
+	}
+	if m.CompletedAt.Valid {
+		t := m.CompletedAt.Time
+		resp.CompletedAt = &t
+	}
+	return resp
+}
+
+// ToStoreImportResponses converts store imports to their API responses.
+func ToStoreImportResponses(imports []*StoreImport) []StoreImportResponse {
+	out := make([]StoreImportResponse, len(imports))
+	for i, m := range imports {
+		out[i] = ToStoreImportResponse(m)
+	}
+	return out
+}
@@ -0,0 +1,286 @@
+package storeimport
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/bus"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+	"github.com/abdelrahman146/kyora/internal/platform/safehttp"
+	"github.com/abdelrahman146/kyora/internal/platform/types/atomic"
+)
+
+const (
+	// importStaleAfter is how long an unfinished import blocks new ones; imports interrupted
+	// by a restart are never finished.
+	importStaleAfter = 6 * time.Hour
+	// importListLimit is the number of recent imports ListImports returns.
+	importListLimit = 20
+)
+
+// Service imports the products, variants, customers and historical orders of a Shopify or
+// WooCommerce store into a business, in the background. Imports are repeatable: entities
+// brought over by an earlier import of the same platform are skipped, so a failed or
+// interrupted import is resumed by starting a new one.
+type Service struct {
+	storage         *Storage
+	atomicProcessor atomic.AtomicProcessor
+	bus             *bus.Bus
+	business        *business.Service
+	inventory       *inventory.Service
+	customer        *customer.Service
+	orders          *order.Service
+}
+
+// NewService creates the store import service.
+func NewService(storage *Storage, atomicProcessor atomic.AtomicProcessor, bus *bus.Bus, businessSvc *business.Service, inventorySvc *inventory.Service, customerSvc *customer.Service, orderSvc *order.Service) *Service {
+	return &Service{
+		storage:         storage,
+		atomicProcessor: atomicProcessor,
+		bus:             bus,
+		business:        businessSvc,
+		inventory:       inventorySvc,
+		customer:        customerSvc,
+		orders:          orderSvc,
+	}
+}
+
+// StartImport queues an import through the store's API and returns it pending. The
+// credentials are kept only until the import ends.
+func (s *Service) StartImport(ctx context.Context, actor *account.User, biz *business.Business, req *StartStoreImportRequest) (*StoreImport, error) {
+	storeURL, err := normalizeStoreURL(req.Platform, req.StoreURL)
+	if err != nil {
+		return nil, err
+	}
+	creds := Credentials{
+		AccessToken:    strings.TrimSpace(req.AccessToken),
+		ConsumerKey:    strings.TrimSpace(req.ConsumerKey),
+		ConsumerSecret: strings.TrimSpace(req.ConsumerSecret),
+	}
+	if req.Platform == PlatformShopify && creds.AccessToken == "" {
+		return nil, ErrMissingCredentials(req.Platform, "accessToken")
+	}
+	if req.Platform == PlatformWooCommerce && (creds.ConsumerKey == "" || creds.ConsumerSecret == "") {
+		return nil, ErrMissingCredentials(req.Platform, "consumerKey", "consumerSecret")
+	}
+	imp := &StoreImport{
+		BusinessID:    biz.ID,
+		RequestedByID: actor.ID,
+		Platform:      req.Platform,
+		Source:        SourceAPI,
+		StoreURL:      storeURL,
+		Credentials:   creds,
+	}
+	return s.queue(ctx, biz, imp, nil)
+}
+
+// StartFileImport queues an import of the products in a product CSV export of the platform.
+// The file is checked before it is queued, so a wrong file is rejected right away.
+func (s *Service) StartFileImport(ctx context.Context, actor *account.User, biz *business.Business, platform Platform, data []byte) (*StoreImport, error) {
+	src, err := parseExportFile(platform, data)
+	if err != nil {
+		return nil, ErrInvalidExportFile(platform, err.Error())
+	}
+	if len(src.items) == 0 {
+		return nil, ErrInvalidExportFile(platform, "file has no products")
+	}
+	imp := &StoreImport{
+		BusinessID:    biz.ID,
+		RequestedByID: actor.ID,
+		Platform:      platform,
+		Source:        SourceFile,
+	}
+	return s.queue(ctx, biz, imp, data)
+}
+
+// queue saves a new import, with its file if any, and emits it for the background run. One
+// import per business runs at a time.
+func (s *Service) queue(ctx context.Context, biz *business.Business, imp *StoreImport, file []byte) (*StoreImport, error) {
+	running, err := s.storage.imports.FindOne(ctx,
+		s.storage.imports.ScopeBusinessID(biz.ID),
+		s.storage.imports.ScopeIn(StoreImportSchema.Status, []any{ImportStatusPending, ImportStatusRunning}),
+		s.storage.imports.ScopeGreaterThan(StoreImportSchema.CreatedAt, time.Now().Add(-importStaleAfter)),
+	)
+	if err == nil {
+		return nil, ErrStoreImportInProgress(running.ID)
+	}
+	if !database.IsRecordNotFound(err) {
+		return nil, err
+	}
+
+	imp.Status = ImportStatusPending
+	imp.Stage = ImportStageProducts
+	err = s.atomicProcessor.Exec(ctx, func(tctx context.Context) error {
+		if err := s.storage.imports.CreateOne(tctx, imp); err != nil {
+			return err
+		}
+		if file == nil {
+			return nil
+		}
+		return s.storage.files.CreateOne(tctx, &StoreImportFile{ImportID: imp.ID, BusinessID: biz.ID, Content: file})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.bus != nil {
+		s.bus.Emit(bus.StoreImportRequestedTopic, &bus.StoreImportRequestedEvent{
+			Ctx:        context.WithoutCancel(ctx),
+			BusinessID: biz.ID,
+			ImportID:   imp.ID,
+		})
+	}
+	return imp, nil
+}
+
+// ListImports returns the business's most recent imports, newest first.
+func (s *Service) ListImports(ctx context.Context, actor *account.User, biz *business.Business) ([]*StoreImport, error) {
+	return s.storage.imports.FindMany(ctx,
+		s.storage.imports.ScopeBusinessID(biz.ID),
+		s.storage.imports.WithOrderBy([]string{StoreImportSchema.CreatedAt.Column() + " DESC"}),
+		s.storage.imports.WithLimit(importListLimit),
+	)
+}
+
+// GetImport returns an import with its progress.
+func (s *Service) GetImport(ctx context.Context, actor *account.User, biz *business.Business, importID string) (*StoreImport, error) {
+	imp, err := s.storage.imports.FindOne(ctx,
+		s.storage.imports.ScopeBusinessID(biz.ID),
+		s.storage.imports.ScopeID(importID),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrStoreImportNotFound(importID, err)
+		}
+		return nil, err
+	}
+	return imp, nil
+}
+
+// RunImport runs a pending import. Imports that already ran are skipped. Entities that cannot
+// be imported are counted and listed on the import; failures of the whole run (the store API
+// refusing the credentials, a lost connection) are recorded on it instead of being retried.
+func (s *Service) RunImport(ctx context.Context, businessID, importID string) error {
+	biz, err := s.business.GetBusinessByIDForJobs(ctx, businessID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	imp, err := s.GetImport(ctx, nil, biz, importID)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if imp.Status != ImportStatusPending {
+		return nil
+	}
+	imp.Status = ImportStatusRunning
+	imp.StartedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	if err := s.storage.imports.UpdateOne(ctx, imp); err != nil {
+		return err
+	}
+
+	src, err := s.openSource(ctx, biz, imp)
+	if err == nil {
+		err = s.run(ctx, biz, imp, src)
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error("store import failed", "error", err, "businessId", biz.ID, "importId", imp.ID)
+		imp.Status = ImportStatusFailed
+		imp.Error = "import stopped unexpectedly; start a new import to continue where it stopped"
+		var apiErr *apiError
+		if errors.As(err, &apiErr) {
+			imp.Error = apiErr.message()
+		}
+	} else {
+		imp.Status = ImportStatusCompleted
+		imp.Stage = ImportStageDone
+	}
+	imp.Credentials = Credentials{}
+	imp.CompletedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	if err := s.storage.files.DeleteMany(ctx,
+		s.storage.files.ScopeBusinessID(biz.ID),
+		s.storage.files.ScopeEquals(StoreImportFileSchema.ImportID, imp.ID),
+	); err != nil {
+		return err
+	}
+	return s.storage.imports.UpdateOne(ctx, imp)
+}
+
+// openSource returns the source an import reads.
+func (s *Service) openSource(ctx context.Context, biz *business.Business, imp *StoreImport) (source, error) {
+	if imp.Source == SourceFile {
+		file, err := s.storage.files.FindOne(ctx,
+			s.storage.files.ScopeBusinessID(biz.ID),
+			s.storage.files.ScopeEquals(StoreImportFileSchema.ImportID, imp.ID),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return parseExportFile(imp.Platform, file.Content)
+	}
+	if imp.Platform == PlatformShopify {
+		return newShopifySource(imp.StoreURL, imp.Credentials), nil
+	}
+	return newWooSource(imp.StoreURL, imp.Credentials), nil
+}
+
+// storePort is the only port store APIs are reached on.
+const storePort = "443"
+
+// newStoreClient returns the client the store sources read with. The store URL is supplied by
+// the seller, so it only connects to public addresses on the https port, whatever the host
+// name resolves to or redirects to.
+func newStoreClient() *http.Client {
+	return safehttp.NewClient(30*time.Second, storePort)
+}
+
+// normalizeStoreURL returns the https origin of a store. Shopify stores are reached on their
+// myshopify.com domain; addresses of hosts on the server's own network are rejected.
+func normalizeStoreURL(platform Platform, raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return "", ErrInvalidStoreURL("not a valid URL")
+	}
+	if u.Scheme != "https" {
+		return "", ErrInvalidStoreURL("the store must be served over https")
+	}
+	if u.User != nil {
+		return "", ErrInvalidStoreURL("credentials belong in their own fields")
+	}
+	host := strings.ToLower(u.Hostname())
+	if net.ParseIP(host) != nil || host == "localhost" || !strings.Contains(host, ".") {
+		return "", ErrInvalidStoreURL("the store must be reached by its public domain name")
+	}
+	if platform == PlatformShopify {
+		if !strings.HasSuffix(host, ".myshopify.com") || u.Port() != "" {
+			return "", ErrInvalidStoreURL("use the store's myshopify.com address")
+		}
+		return "https://" + host, nil
+	}
+	if port := u.Port(); port != "" && port != storePort {
+		return "", ErrInvalidStoreURL("the store must be served on the standard https port")
+	}
+	// WordPress may live below a path of the domain
+	origin := "https://" + strings.ToLower(u.Host) + strings.TrimRight(u.EscapedPath(), "/")
+	return origin, nil
+}
@@ -0,0 +1,462 @@
+package storeimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/asset"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/country"
+)
+
+const (
+	// defaultCategoryName is the category of imported products the store did not categorize.
+	defaultCategoryName = "Imported"
+	// maxProductPhotos is the number of photos a product or variant keeps.
+	maxProductPhotos = 10
+	// importedOrderTag marks orders brought over from a store.
+	importedOrderTag = "imported"
+)
+
+// entityError is why an entity of the store cannot be imported, shown to the seller.
+type entityError string
+
+func (e entityError) Error() string { return string(e) }
+
+// issueMessage returns the message shown for an entity that failed to import. Storage
+// failures are not shown.
+func issueMessage(err error) string {
+	var ee entityError
+	if errors.As(err, &ee) {
+		return ee.Error()
+	}
+	var p *problem.Problem
+	if errors.As(err, &p) && p.Detail != "" {
+		return p.Detail
+	}
+	return "could not be saved"
+}
+
+// recordIndex maps the entities of the store already imported to their Kyora IDs, by kind
+// and external ID.
+type recordIndex map[RecordKind]map[string]string
+
+func (idx recordIndex) get(kind RecordKind, externalID string) (string, bool) {
+	id, ok := idx[kind][externalID]
+	return id, ok
+}
+
+func (s *Service) loadRecords(ctx context.Context, biz *business.Business, platform Platform) (recordIndex, error) {
+	records, err := s.storage.records.FindMany(ctx,
+		s.storage.records.ScopeBusinessID(biz.ID),
+		s.storage.records.ScopeEquals(ImportRecordSchema.Platform, platform),
+	)
+	if err != nil {
+		return nil, err
+	}
+	idx := recordIndex{}
+	for _, r := range records {
+		if idx[r.Kind] == nil {
+			idx[r.Kind] = map[string]string{}
+		}
+		idx[r.Kind][r.ExternalID] = r.RecordID
+	}
+	return idx, nil
+}
+
+// remember records that an entity of the store was imported as recordID.
+func (s *Service) remember(ctx context.Context, imp *StoreImport, idx recordIndex, kind RecordKind, externalID, recordID string) error {
+	if externalID == "" {
+		return nil
+	}
+	err := s.storage.records.CreateOne(ctx, &ImportRecord{
+		BusinessID: imp.BusinessID,
+		Platform:   imp.Platform,
+		Kind:       kind,
+		ExternalID: externalID,
+		RecordID:   recordID,
+		ImportID:   imp.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if idx[kind] == nil {
+		idx[kind] = map[string]string{}
+	}
+	idx[kind][externalID] = recordID
+	return nil
+}
+
+// run imports products, customers and orders, in that order since orders need both, and
+// saves the progress after every page of the store.
+func (s *Service) run(ctx context.Context, biz *business.Business, imp *StoreImport, src source) error {
+	idx, err := s.loadRecords(ctx, biz, imp.Platform)
+	if err != nil {
+		return err
+	}
+	stages := []struct {
+		stage ImportStage
+		run   func() error
+	}{
+		{ImportStageProducts, func() error {
+			return src.products(ctx, func(page []*externalProduct) error {
+				for _, p := range page {
+					if err := s.importProduct(ctx, biz, imp, idx, p); err != nil {
+						if ctx.Err() != nil {
+							return ctx.Err()
+						}
+						imp.Progress.fail(RecordKindProduct, p.ID, issueMessage(err))
+						imp.Progress.Variants.Failed += len(p.Variants)
+					}
+				}
+				return s.storage.imports.UpdateOne(ctx, imp)
+			})
+		}},
+		{ImportStageCustomers, func() error {
+			return src.customers(ctx, func(page []*externalCustomer) error {
+				for _, c := range page {
+					if err := s.importCustomer(ctx, biz, imp, idx, c); err != nil {
+						if ctx.Err() != nil {
+							return ctx.Err()
+						}
+						imp.Progress.fail(RecordKindCustomer, c.ID, issueMessage(err))
+					}
+				}
+				return s.storage.imports.UpdateOne(ctx, imp)
+			})
+		}},
+		{ImportStageOrders, func() error {
+			return src.orders(ctx, func(page []*externalOrder) error {
+				for _, o := range page {
+					if err := s.importOrder(ctx, biz, imp, idx, o); err != nil {
+						if ctx.Err() != nil {
+							return ctx.Err()
+						}
+						imp.Progress.fail(RecordKindOrder, o.ID, issueMessage(err))
+					}
+				}
+				return s.storage.imports.UpdateOne(ctx, imp)
+			})
+		}},
+	}
+	for _, st := range stages {
+		imp.Stage = st.stage
+		if err := s.storage.imports.UpdateOne(ctx, imp); err != nil {
+			return err
+		}
+		if err := st.run(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func assetReferences(urls []string) []asset.AssetReference {
+	var refs []asset.AssetReference
+	for _, u := range urls {
+		if u = strings.TrimSpace(u); u != "" && len(refs) < maxProductPhotos {
+			refs = append(refs, asset.AssetReference{URL: u})
+		}
+	}
+	return refs
+}
+
+// importProduct creates a product with its variants. Photos stay on the store's CDN. Stock
+// levels are taken as they are in the store.
+func (s *Service) importProduct(ctx context.Context, biz *business.Business, imp *StoreImport, idx recordIndex, p *externalProduct) error {
+	if _, ok := idx.get(RecordKindProduct, p.ID); ok {
+		imp.Progress.Products.Skipped++
+		imp.Progress.Variants.Skipped += len(p.Variants)
+		return nil
+	}
+	if strings.TrimSpace(p.Name) == "" {
+		return entityError("product has no name")
+	}
+	if len(p.Variants) == 0 {
+		return entityError("product has no variants")
+	}
+	categoryName := strings.TrimSpace(p.Category)
+	if categoryName == "" {
+		categoryName = defaultCategoryName
+	}
+	category, err := s.inventory.EnsureCategory(ctx, nil, biz, categoryName)
+	if err != nil {
+		return err
+	}
+	req := &inventory.CreateProductWithVariantsRequest{
+		Product: inventory.CreateProductRequest{
+			Name:        strings.TrimSpace(p.Name),
+			Description: p.Description,
+			Photos:      assetReferences(p.Images),
+			CategoryID:  category.ID,
+		},
+		Variants: make([]inventory.CreateProductVariantRequest, len(p.Variants)),
+	}
+	for i, v := range p.Variants {
+		cost, price, stock, alert := v.Cost, v.Price, v.Stock, 0
+		req.Variants[i] = inventory.CreateProductVariantRequest{
+			Code:               v.Code,
+			SKU:                strings.TrimSpace(v.SKU),
+			Barcode:            strings.TrimSpace(v.Barcode),
+			Photos:             assetReferences([]string{v.Image}),
+			CostPrice:          &cost,
+			SalePrice:          &price,
+			StockQuantity:      &stock,
+			StockQuantityAlert: &alert,
+		}
+	}
+	product, err := s.inventory.CreateProductWithVariants(ctx, nil, biz, req)
+	if err != nil {
+		return err
+	}
+	if err := s.remember(ctx, imp, idx, RecordKindProduct, p.ID, product.ID); err != nil {
+		return err
+	}
+	for i, v := range product.Variants {
+		if err := s.remember(ctx, imp, idx, RecordKindVariant, p.Variants[i].ID, v.ID); err != nil {
+			return err
+		}
+	}
+	imp.Progress.Products.Imported++
+	imp.Progress.Variants.Imported += len(product.Variants)
+	return nil
+}
+
+func (s *Service) importCustomer(ctx context.Context, biz *business.Business, imp *StoreImport, idx recordIndex, c *externalCustomer) error {
+	_, created, err := s.ensureCustomer(ctx, biz, imp, idx, c)
+	if err != nil {
+		return err
+	}
+	if !created {
+		imp.Progress.Customers.Skipped++
+	}
+	return nil
+}
+
+// ensureCustomer returns the Kyora customer of a store customer: the one it was imported as,
+// the business's customer with the same email, or a new one with the customer's address.
+func (s *Service) ensureCustomer(ctx context.Context, biz *business.Business, imp *StoreImport, idx recordIndex, c *externalCustomer) (*customer.Customer, bool, error) {
+	if id, ok := idx.get(RecordKindCustomer, c.ID); ok && c.ID != "" {
+		cust, err := s.customer.GetCustomerByID(ctx, nil, biz, id)
+		if err == nil {
+			return cust, false, nil
+		}
+		if !database.IsRecordNotFound(err) {
+			return nil, false, err
+		}
+		// deleted since it was imported: import it again
+	}
+	email := strings.ToLower(strings.TrimSpace(c.Email))
+	if email != "" {
+		cust, err := s.customer.GetCustomerByEmail(ctx, nil, biz, email)
+		if err == nil {
+			if _, ok := idx.get(RecordKindCustomer, c.ID); !ok {
+				if err := s.remember(ctx, imp, idx, RecordKindCustomer, c.ID, cust.ID); err != nil {
+					return nil, false, err
+				}
+			}
+			return cust, false, nil
+		}
+		if !database.IsRecordNotFound(err) {
+			return nil, false, err
+		}
+	}
+
+	name := strings.TrimSpace(c.Name)
+	if name == "" {
+		name = email
+	}
+	if name == "" {
+		return nil, false, entityError("customer has no name or email")
+	}
+	countryCode := strings.ToUpper(biz.CountryCode)
+	if c.Address != nil && len(strings.TrimSpace(c.Address.CountryCode)) == 2 {
+		countryCode = strings.ToUpper(strings.TrimSpace(c.Address.CountryCode))
+	}
+	phone := c.Phone
+	if phone == "" && c.Address != nil {
+		phone = c.Address.Phone
+	}
+	phoneCode, phoneNumber := splitPhone(phone, countryCode)
+	cust, err := s.customer.CreateCustomer(ctx, nil, biz, &customer.CreateCustomerRequest{
+		Name:        name,
+		CountryCode: countryCode,
+		Email:       email,
+		PhoneCode:   phoneCode,
+		PhoneNumber: phoneNumber,
+
+		MarketingConsent: importedConsent(c),
+		AllowDuplicate:   true,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if _, ok := idx.get(RecordKindCustomer, c.ID); !ok {
+		if err := s.remember(ctx, imp, idx, RecordKindCustomer, c.ID, cust.ID); err != nil {
+			return nil, false, err
+		}
+	}
+	if !c.Address.empty() {
+		if _, err := s.ensureAddress(ctx, biz, cust, c.Address); err != nil {
+			return nil, false, err
+		}
+	}
+	imp.Progress.Customers.Imported++
+	return cust, true, nil
+}
+
+// ensureAddress returns the customer's address matching a, creating it when there is none.
+// Without an address to match, the customer's first address is used.
+func (s *Service) ensureAddress(ctx context.Context, biz *business.Business, cust *customer.Customer, a *externalAddress) (*customer.CustomerAddress, error) {
+	addresses, err := s.customer.ListCustomerAddresses(ctx, nil, biz, cust.ID)
+	if err != nil {
+		return nil, err
+	}
+	if a.empty() {
+		if len(addresses) > 0 {
+			return addresses[0], nil
+		}
+		a = &externalAddress{}
+	}
+	countryCode := cust.CountryCode
+	if len(strings.TrimSpace(a.CountryCode)) == 2 {
+		countryCode = strings.ToUpper(strings.TrimSpace(a.CountryCode))
+	}
+	for _, existing := range addresses {
+		if existing.CountryCode == countryCode &&
+			strings.EqualFold(existing.Street.String, a.Street) &&
+			strings.EqualFold(existing.City, a.City) &&
+			strings.EqualFold(existing.ZipCode.String, a.ZipCode) {
+			return existing, nil
+		}
+	}
+	phoneCode, phoneNumber := splitPhone(a.Phone, countryCode)
+	if phoneNumber == "" {
+		phoneCode, phoneNumber = cust.PhoneCode.String, cust.PhoneNumber.String
+	}
+	state := a.State
+	if state == "" {
+		state = a.City
+	}
+	return s.customer.CreateCustomerAddress(ctx, nil, biz, cust.ID, &customer.CreateCustomerAddressRequest{
+		CountryCode: countryCode,
+		State:       state,
+		City:        a.City,
+		Street:      a.Street,
+		ZipCode:     a.ZipCode,
+		PhoneCode:   phoneCode,
+		PhoneNumber: phoneNumber,
+	})
+}
+
+// splitPhone splits an international number ("+971 50 123 4567") into its country calling
+// code and number. Local numbers take the calling code of countryCode.
+func splitPhone(phone, countryCode string) (string, string) {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, phone)
+	if digits == "" {
+		return "", ""
+	}
+	if strings.HasPrefix(strings.TrimSpace(phone), "+") {
+		for n := 4; n >= 1; n-- {
+			if len(digits) > n && country.FindByPhonePrefix("+"+digits[:n]).Code != "" {
+				return "+" + digits[:n], digits[n:]
+			}
+		}
+	}
+	return country.FindByCode(countryCode).PhonePrefix, strings.TrimPrefix(digits, "0")
+}
+
+// importOrder records a historical order. Its customer is imported on the way when it was a
+// guest checkout or is otherwise missing; its items must be variants the import brought over.
+func (s *Service) importOrder(ctx context.Context, biz *business.Business, imp *StoreImport, idx recordIndex, o *externalOrder) error {
+	if _, ok := idx.get(RecordKindOrder, o.ID); ok {
+		imp.Progress.Orders.Skipped++
+		return nil
+	}
+	items := make([]*order.CreateOrderItemRequest, 0, len(o.Items))
+	for _, it := range o.Items {
+		if it.Quantity <= 0 {
+			continue
+		}
+		variantID, ok := idx.get(RecordKindVariant, it.VariantID)
+		if !ok {
+			return entityError(fmt.Sprintf("product variant %s of the order was not imported", it.VariantID))
+		}
+		items = append(items, &order.CreateOrderItemRequest{VariantID: variantID, Quantity: it.Quantity, UnitPrice: it.UnitPrice})
+	}
+	if len(items) == 0 {
+		return entityError("order has no products of the store")
+	}
+	buyer := o.Customer
+	if buyer == nil {
+		buyer = &externalCustomer{}
+	}
+	cust, _, err := s.ensureCustomer(ctx, biz, imp, idx, buyer)
+	if err != nil {
+		return err
+	}
+	shipTo := o.ShippingAddress
+	if shipTo.empty() {
+		shipTo = buyer.Address
+	}
+	address, err := s.ensureAddress(ctx, biz, cust, shipTo)
+	if err != nil {
+		return err
+	}
+	status, paymentStatus := o.Status, o.PaymentStatus
+	note := fmt.Sprintf("Imported from %s order %s.", imp.Platform, o.Number)
+	if n := strings.TrimSpace(o.Note); n != "" {
+		note += "\n" + n
+	}
+	ord, err := s.orders.ImportOrder(ctx, nil, biz, &order.CreateOrderRequest{
+		CustomerID:        cust.ID,
+		ShippingAddressID: address.ID,
+		Channel:           string(imp.Platform),
+		ShippingFee:       o.ShippingFee,
+		Discount:          o.Discount,
+		Status:            &status,
+		PaymentStatus:     &paymentStatus,
+		PaymentMethod:     o.PaymentMethod,
+		OrderedAt:         o.OrderedAt,
+		Note:              note,
+		Tags:              []string{importedOrderTag},
+		Items:             items,
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.remember(ctx, imp, idx, RecordKindOrder, o.ID, ord.ID); err != nil {
+		return err
+	}
+	imp.Progress.Orders.Imported++
+	return nil
+}
+
+// importedConsent carries over the marketing subscriptions the store recorded for a customer.
+// Customers without any are imported without consent, not as having declined.
+func importedConsent(c *externalCustomer) *customer.MarketingConsentRequest {
+	if !c.EmailMarketing && !c.SMSMarketing {
+		return nil
+	}
+	req := &customer.MarketingConsentRequest{Source: customer.ConsentSourceImport}
+	if c.EmailMarketing {
+		req.Email = &c.EmailMarketing
+	}
+	if c.SMSMarketing {
+		req.SMS = &c.SMSMarketing
+	}
+	return req
+}
@@ -0,0 +1,170 @@
+package storeimport
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/shopspring/decimal"
+)
+
+// source reads the entities of a store page by page, in the platform-neutral shape below.
+// Each page is handed to fn before the next one is read, so progress is saved as the import
+// goes. Sources without customers or orders (export files) return without calling fn.
+type source interface {
+	products(ctx context.Context, fn func([]*externalProduct) error) error
+	customers(ctx context.Context, fn func([]*externalCustomer) error) error
+	orders(ctx context.Context, fn func([]*externalOrder) error) error
+}
+
+// apiError is a failed response of a store API. It is reported on the import, unlike other
+// failures, because the seller can act on it.
+type apiError struct {
+	platform Platform
+	status   int
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s returned status %d", e.platform, e.status)
+}
+
+// message explains the failure to the seller.
+func (e *apiError) message() string {
+	switch e.status {
+	case 401, 403:
+		return "the store rejected the API credentials; check they are valid and can read products, customers and orders"
+	case 404:
+		return "no store API was found at the store URL"
+	case 429:
+		return "the store API rate limit was reached; start the import again later"
+	default:
+		return fmt.Sprintf("the store API returned status %d; start the import again later", e.status)
+	}
+}
+
+type externalProduct struct {
+	ID          string
+	Name        string
+	Description string
+	Category    string
+	Images      []string
+	Variants    []*externalVariant
+}
+
+type externalVariant struct {
+	ID      string
+	Code    string
+	SKU     string
+	Barcode string
+	Price   decimal.Decimal
+	Cost    decimal.Decimal
+	Stock   int
+	Image   string
+}
+
+type externalAddress struct {
+	Street      string
+	City        string
+	State       string
+	ZipCode     string
+	CountryCode string
+	Phone       string
+}
+
+// empty reports whether the address has nothing an order can be shipped to.
+func (a *externalAddress) empty() bool {
+	return a == nil || strings.TrimSpace(a.Street+a.City+a.ZipCode) == ""
+}
+
+type externalCustomer struct {
+	// ID is empty for guest checkouts.
+	ID      string
+	Name    string
+	Email   string
+	Phone   string
+	Address *externalAddress
+	// EmailMarketing and SMSMarketing are set when the store recorded the customer's
+	// subscription to marketing on the channel.
+	EmailMarketing bool
+	SMSMarketing   bool
+}
+
+type externalOrderItem struct {
+	VariantID string
+	Quantity  int
+	UnitPrice decimal.Decimal
+}
+
+type externalOrder struct {
+	ID              string
+	Number          string
+	OrderedAt       time.Time
+	Customer        *externalCustomer
+	ShippingAddress *externalAddress
+	Items           []*externalOrderItem
+	ShippingFee     decimal.Decimal
+	Discount        decimal.Decimal
+	Status          order.OrderStatus
+	PaymentStatus   order.OrderPaymentStatus
+	PaymentMethod   order.OrderPaymentMethod
+	Note            string
+}
+
+// defaultVariantCode is the code of the single variant of products without options.
+const defaultVariantCode = "default"
+
+// variantCode returns the Kyora code of a variant from its option values, e.g. "Red / L".
+func variantCode(options ...string) string {
+	var parts []string
+	for _, o := range options {
+		if o = strings.TrimSpace(o); o != "" {
+			parts = append(parts, o)
+		}
+	}
+	if len(parts) == 0 || (len(parts) == 1 && strings.EqualFold(parts[0], "Default Title")) {
+		return defaultVariantCode
+	}
+	return strings.Join(parts, " / ")
+}
+
+var htmlTags = regexp.MustCompile(`<[^>]*>`)
+
+// plainText turns the HTML product descriptions of the platforms into plain text.
+func plainText(s string) string {
+	s = htmlTags.ReplaceAllString(s, " ")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
+
+// parseAmount reads a price of the platforms, which send amounts as strings. Missing or
+// malformed amounts are zero.
+func parseAmount(s string) decimal.Decimal {
+	d, err := decimal.NewFromString(strings.TrimSpace(s))
+	if err != nil || d.IsNegative() {
+		return decimal.Zero
+	}
+	return d
+}
+
+// paymentMethodFromGateway maps the payment gateway of an order to the closest Kyora payment
+// method. Unknown gateways are recorded as bank transfers, the order form's default.
+func paymentMethodFromGateway(gateway string) order.OrderPaymentMethod {
+	g := strings.ToLower(gateway)
+	switch {
+	case strings.Contains(g, "tabby"):
+		return order.OrderPaymentMethodTabby
+	case strings.Contains(g, "tamara"):
+		return order.OrderPaymentMethodTamara
+	case strings.Contains(g, "paypal"), strings.Contains(g, "ppcp"):
+		return order.OrderPaymentMethodPayPal
+	case g == "cod", strings.Contains(g, "cash"):
+		return order.OrderPaymentMethodCashOnDelivery
+	case strings.Contains(g, "stripe"), strings.Contains(g, "shopify_payments"), strings.Contains(g, "card"):
+		return order.OrderPaymentMethodCreditCard
+	default:
+		return order.OrderPaymentMethodBankTransfer
+	}
+}
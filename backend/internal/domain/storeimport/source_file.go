@@ -0,0 +1,221 @@
+package storeimport
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// fileSource reads the products of a product CSV export. Exports hold no customers or orders.
+type fileSource struct {
+	items []*externalProduct
+}
+
+// filePageSize is how many products of a file are imported between progress updates.
+const filePageSize = 50
+
+func (s *fileSource) products(ctx context.Context, fn func([]*externalProduct) error) error {
+	for start := 0; start < len(s.items); start += filePageSize {
+		if err := fn(s.items[start:min(start+filePageSize, len(s.items))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fileSource) customers(ctx context.Context, fn func([]*externalCustomer) error) error {
+	return nil
+}
+
+func (s *fileSource) orders(ctx context.Context, fn func([]*externalOrder) error) error {
+	return nil
+}
+
+// exportTable is a parsed CSV export whose cells are read by header name.
+type exportTable struct {
+	index   map[string]int
+	records [][]string
+}
+
+func readExportTable(data []byte) (*exportTable, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	header, err := r.Read()
+	if err != nil {
+		return nil, errors.New("file has no header row")
+	}
+	t := &exportTable{index: make(map[string]int, len(header))}
+	for i, name := range header {
+		t.index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.New("file is not a valid CSV")
+		}
+		if strings.TrimSpace(strings.Join(record, "")) != "" {
+			t.records = append(t.records, record)
+		}
+	}
+	return t, nil
+}
+
+func (t *exportTable) has(col string) bool {
+	_, ok := t.index[strings.ToLower(col)]
+	return ok
+}
+
+func (t *exportTable) cell(record []string, col string) string {
+	i, ok := t.index[strings.ToLower(col)]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// parseExportFile reads the product CSV export of the platform.
+func parseExportFile(platform Platform, data []byte) (*fileSource, error) {
+	t, err := readExportTable(data)
+	if err != nil {
+		return nil, err
+	}
+	var products []*externalProduct
+	if platform == PlatformShopify {
+		products, err = parseShopifyExport(t)
+	} else {
+		products, err = parseWooExport(t)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &fileSource{items: products}, nil
+}
+
+// parseShopifyExport reads a Shopify products CSV: rows are grouped by Handle, the first row
+// of a product carries its details, and further rows add variants or only images.
+func parseShopifyExport(t *exportTable) ([]*externalProduct, error) {
+	if !t.has("Handle") || !t.has("Variant Price") {
+		return nil, errors.New("file is not a Shopify products export: Handle and Variant Price columns are required")
+	}
+	var products []*externalProduct
+	byHandle := map[string]*externalProduct{}
+	for _, rec := range t.records {
+		handle := t.cell(rec, "Handle")
+		if handle == "" {
+			continue
+		}
+		p := byHandle[handle]
+		if p == nil {
+			p = &externalProduct{
+				ID:          handle,
+				Name:        t.cell(rec, "Title"),
+				Description: plainText(t.cell(rec, "Body (HTML)")),
+				Category:    t.cell(rec, "Type"),
+			}
+			byHandle[handle] = p
+			products = append(products, p)
+		}
+		if src := t.cell(rec, "Image Src"); src != "" {
+			p.Images = append(p.Images, src)
+		}
+		price := t.cell(rec, "Variant Price")
+		if price == "" {
+			continue
+		}
+		code := variantCode(t.cell(rec, "Option1 Value"), t.cell(rec, "Option2 Value"), t.cell(rec, "Option3 Value"))
+		stock, _ := strconv.Atoi(t.cell(rec, "Variant Inventory Qty"))
+		p.Variants = append(p.Variants, &externalVariant{
+			ID:      handle + "/" + code,
+			Code:    code,
+			SKU:     strings.TrimPrefix(t.cell(rec, "Variant SKU"), "'"),
+			Barcode: strings.TrimPrefix(t.cell(rec, "Variant Barcode"), "'"),
+			Price:   parseAmount(price),
+			Cost:    parseAmount(t.cell(rec, "Cost per item")),
+			Stock:   max(stock, 0),
+			Image:   t.cell(rec, "Variant Image"),
+		})
+	}
+	return products, nil
+}
+
+// parseWooExport reads a WooCommerce products CSV: simple products are one row, variable
+// products a parent row followed by variation rows pointing to it in Parent ("id:123").
+func parseWooExport(t *exportTable) ([]*externalProduct, error) {
+	if !t.has("ID") || !t.has("Type") || !t.has("Name") {
+		return nil, errors.New("file is not a WooCommerce products export: ID, Type and Name columns are required")
+	}
+	var products []*externalProduct
+	byID := map[string]*externalProduct{}
+	bySKU := map[string]*externalProduct{}
+	var variations [][]string
+	for _, rec := range t.records {
+		typ := strings.ToLower(t.cell(rec, "Type"))
+		if strings.Contains(typ, "variation") {
+			variations = append(variations, rec)
+			continue
+		}
+		p := &externalProduct{
+			ID:          t.cell(rec, "ID"),
+			Name:        t.cell(rec, "Name"),
+			Description: plainText(t.cell(rec, "Description")),
+		}
+		// "Clothing > Tops, Sale": the first category, without its parents
+		if cats := strings.Split(t.cell(rec, "Categories"), ","); cats[0] != "" {
+			path := strings.Split(cats[0], ">")
+			p.Category = html.UnescapeString(strings.TrimSpace(path[len(path)-1]))
+		}
+		for _, src := range strings.Split(t.cell(rec, "Images"), ",") {
+			if src = strings.TrimSpace(src); src != "" {
+				p.Images = append(p.Images, src)
+			}
+		}
+		if !strings.Contains(typ, "variable") {
+			p.Variants = []*externalVariant{wooExportVariant(t, rec, defaultVariantCode)}
+		}
+		products = append(products, p)
+		byID[p.ID] = p
+		if sku := t.cell(rec, "SKU"); sku != "" {
+			bySKU[sku] = p
+		}
+	}
+	for _, rec := range variations {
+		parent := t.cell(rec, "Parent")
+		p := bySKU[parent]
+		if id, ok := strings.CutPrefix(parent, "id:"); ok {
+			p = byID[id]
+		}
+		if p == nil {
+			continue
+		}
+		var options []string
+		for i := 1; t.has("Attribute " + strconv.Itoa(i) + " value(s)"); i++ {
+			options = append(options, t.cell(rec, "Attribute "+strconv.Itoa(i)+" value(s)"))
+		}
+		p.Variants = append(p.Variants, wooExportVariant(t, rec, variantCode(options...)))
+	}
+	return products, nil
+}
+
+func wooExportVariant(t *exportTable, rec []string, code string) *externalVariant {
+	stock, _ := strconv.Atoi(t.cell(rec, "Stock"))
+	v := &externalVariant{
+		ID:    t.cell(rec, "ID"),
+		Code:  code,
+		SKU:   t.cell(rec, "SKU"),
+		Price: parseAmount(wooPrice(t.cell(rec, "Regular price"), t.cell(rec, "Sale price"))),
+		Stock: max(stock, 0),
+	}
+	if images := strings.Split(t.cell(rec, "Images"), ","); images[0] != "" {
+		v.Image = strings.TrimSpace(images[0])
+	}
+	return v
+}
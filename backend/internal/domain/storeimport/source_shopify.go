@@ -0,0 +1,297 @@
+package storeimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+)
+
+// shopifyAPIVersion is the Admin REST API version the Shopify source reads.
+const shopifyAPIVersion = "2024-07"
+
+// shopifyPageSize is the largest page the Shopify Admin API returns.
+const shopifyPageSize = 250
+
+// maxAPIResponseBytes bounds one page of a store API response.
+const maxAPIResponseBytes = 32 << 20
+
+// shopifySource reads a store through the Shopify Admin REST API with an access token of a
+// custom app. Pages follow the cursor in the Link header.
+type shopifySource struct {
+	client  *http.Client
+	baseURL string
+	token   string
+}
+
+func newShopifySource(storeURL string, creds Credentials) *shopifySource {
+	return &shopifySource{
+		client:  newStoreClient(),
+		baseURL: storeURL + "/admin/api/" + shopifyAPIVersion,
+		token:   creds.AccessToken,
+	}
+}
+
+var shopifyNextLink = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// shopifyPages reads every page of a list endpoint, following the Link header.
+func shopifyPages[T any](ctx context.Context, s *shopifySource, path string, fn func(*T) error) error {
+	url := s.baseURL + path
+	for url != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("X-Shopify-Access-Token", s.token)
+		req.Header.Set("Accept", "application/json")
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return err
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxAPIResponseBytes))
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return &apiError{platform: PlatformShopify, status: resp.StatusCode}
+		}
+		out := new(T)
+		if err := json.Unmarshal(body, out); err != nil {
+			return fmt.Errorf("shopify returned an unreadable response: %w", err)
+		}
+		if err := fn(out); err != nil {
+			return err
+		}
+		url = ""
+		if m := shopifyNextLink.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+			url = m[1]
+		}
+	}
+	return nil
+}
+
+type shopifyAddress struct {
+	Address1    string `json:"address1"`
+	Address2    string `json:"address2"`
+	City        string `json:"city"`
+	Province    string `json:"province"`
+	Zip         string `json:"zip"`
+	CountryCode string `json:"country_code"`
+	Phone       string `json:"phone"`
+}
+
+func (a *shopifyAddress) toExternal() *externalAddress {
+	if a == nil {
+		return nil
+	}
+	return &externalAddress{
+		Street:      strings.TrimSpace(a.Address1 + " " + a.Address2),
+		City:        a.City,
+		State:       a.Province,
+		ZipCode:     a.Zip,
+		CountryCode: a.CountryCode,
+		Phone:       a.Phone,
+	}
+}
+
+type shopifyCustomer struct {
+	ID             int64           `json:"id"`
+	FirstName      string          `json:"first_name"`
+	LastName       string          `json:"last_name"`
+	Email          string          `json:"email"`
+	Phone          string          `json:"phone"`
+	DefaultAddress *shopifyAddress `json:"default_address"`
+	EmailConsent   *shopifyConsent `json:"email_marketing_consent"`
+	SMSConsent     *shopifyConsent `json:"sms_marketing_consent"`
+}
+
+type shopifyConsent struct {
+	State string `json:"state"`
+}
+
+func (c *shopifyConsent) subscribed() bool {
+	return c != nil && c.State == "subscribed"
+}
+
+func (c *shopifyCustomer) toExternal() *externalCustomer {
+	if c == nil {
+		return nil
+	}
+	return &externalCustomer{
+		ID:      strconv.FormatInt(c.ID, 10),
+		Name:    strings.TrimSpace(c.FirstName + " " + c.LastName),
+		Email:   c.Email,
+		Phone:   c.Phone,
+		Address: c.DefaultAddress.toExternal(),
+
+		EmailMarketing: c.EmailConsent.subscribed(),
+		SMSMarketing:   c.SMSConsent.subscribed(),
+	}
+}
+
+func (s *shopifySource) products(ctx context.Context, fn func([]*externalProduct) error) error {
+	type page struct {
+		Products []struct {
+			ID          int64  `json:"id"`
+			Title       string `json:"title"`
+			BodyHTML    string `json:"body_html"`
+			ProductType string `json:"product_type"`
+			Images      []struct {
+				ID  int64  `json:"id"`
+				Src string `json:"src"`
+			} `json:"images"`
+			Variants []struct {
+				ID                int64  `json:"id"`
+				Option1           string `json:"option1"`
+				Option2           string `json:"option2"`
+				Option3           string `json:"option3"`
+				SKU               string `json:"sku"`
+				Barcode           string `json:"barcode"`
+				Price             string `json:"price"`
+				InventoryQuantity int    `json:"inventory_quantity"`
+				ImageID           int64  `json:"image_id"`
+			} `json:"variants"`
+		} `json:"products"`
+	}
+	return shopifyPages(ctx, s, "/products.json?limit="+strconv.Itoa(shopifyPageSize), func(p *page) error {
+		products := make([]*externalProduct, 0, len(p.Products))
+		for _, sp := range p.Products {
+			images := make(map[int64]string, len(sp.Images))
+			ep := &externalProduct{
+				ID:          strconv.FormatInt(sp.ID, 10),
+				Name:        sp.Title,
+				Description: plainText(sp.BodyHTML),
+				Category:    sp.ProductType,
+			}
+			for _, img := range sp.Images {
+				images[img.ID] = img.Src
+				ep.Images = append(ep.Images, img.Src)
+			}
+			for _, sv := range sp.Variants {
+				ep.Variants = append(ep.Variants, &externalVariant{
+					ID:      strconv.FormatInt(sv.ID, 10),
+					Code:    variantCode(sv.Option1, sv.Option2, sv.Option3),
+					SKU:     sv.SKU,
+					Barcode: sv.Barcode,
+					Price:   parseAmount(sv.Price),
+					Stock:   max(sv.InventoryQuantity, 0),
+					Image:   images[sv.ImageID],
+				})
+			}
+			products = append(products, ep)
+		}
+		return fn(products)
+	})
+}
+
+func (s *shopifySource) customers(ctx context.Context, fn func([]*externalCustomer) error) error {
+	type page struct {
+		Customers []*shopifyCustomer `json:"customers"`
+	}
+	return shopifyPages(ctx, s, "/customers.json?limit="+strconv.Itoa(shopifyPageSize), func(p *page) error {
+		customers := make([]*externalCustomer, len(p.Customers))
+		for i, c := range p.Customers {
+			customers[i] = c.toExternal()
+		}
+		return fn(customers)
+	})
+}
+
+func (s *shopifySource) orders(ctx context.Context, fn func([]*externalOrder) error) error {
+	type page struct {
+		Orders []struct {
+			ID                  int64            `json:"id"`
+			Name                string           `json:"name"`
+			CreatedAt           time.Time        `json:"created_at"`
+			CancelledAt         *time.Time       `json:"cancelled_at"`
+			FinancialStatus     string           `json:"financial_status"`
+			FulfillmentStatus   string           `json:"fulfillment_status"`
+			PaymentGatewayNames []string         `json:"payment_gateway_names"`
+			TotalDiscounts      string           `json:"total_discounts"`
+			Note                string           `json:"note"`
+			Email               string           `json:"email"`
+			Customer            *shopifyCustomer `json:"customer"`
+			ShippingAddress     *shopifyAddress  `json:"shipping_address"`
+			BillingAddress      *shopifyAddress  `json:"billing_address"`
+			LineItems           []struct {
+				VariantID int64  `json:"variant_id"`
+				Quantity  int    `json:"quantity"`
+				Price     string `json:"price"`
+			} `json:"line_items"`
+			ShippingLines []struct {
+				Price string `json:"price"`
+			} `json:"shipping_lines"`
+		} `json:"orders"`
+	}
+	return shopifyPages(ctx, s, "/orders.json?status=any&limit="+strconv.Itoa(shopifyPageSize), func(p *page) error {
+		orders := make([]*externalOrder, 0, len(p.Orders))
+		for _, so := range p.Orders {
+			eo := &externalOrder{
+				ID:              strconv.FormatInt(so.ID, 10),
+				Number:          so.Name,
+				OrderedAt:       so.CreatedAt,
+				Customer:        so.Customer.toExternal(),
+				ShippingAddress: so.ShippingAddress.toExternal(),
+				Discount:        parseAmount(so.TotalDiscounts),
+				Status:          shopifyOrderStatus(so.CancelledAt != nil, so.FulfillmentStatus),
+				PaymentStatus:   shopifyPaymentStatus(so.FinancialStatus),
+				PaymentMethod:   paymentMethodFromGateway(strings.Join(so.PaymentGatewayNames, " ")),
+				Note:            so.Note,
+			}
+			if eo.Customer == nil {
+				eo.Customer = &externalCustomer{Email: so.Email}
+			}
+			if eo.Customer.Address == nil {
+				eo.Customer.Address = so.BillingAddress.toExternal()
+			}
+			for _, line := range so.ShippingLines {
+				eo.ShippingFee = eo.ShippingFee.Add(parseAmount(line.Price))
+			}
+			for _, li := range so.LineItems {
+				if li.VariantID == 0 {
+					// custom line items are not products of the store
+					continue
+				}
+				eo.Items = append(eo.Items, &externalOrderItem{
+					VariantID: strconv.FormatInt(li.VariantID, 10),
+					Quantity:  li.Quantity,
+					UnitPrice: parseAmount(li.Price),
+				})
+			}
+			orders = append(orders, eo)
+		}
+		return fn(orders)
+	})
+}
+
+func shopifyOrderStatus(cancelled bool, fulfillment string) order.OrderStatus {
+	switch {
+	case cancelled:
+		return order.OrderStatusCancelled
+	case fulfillment == "fulfilled":
+		return order.OrderStatusFulfilled
+	default:
+		return order.OrderStatusPlaced
+	}
+}
+
+func shopifyPaymentStatus(financial string) order.OrderPaymentStatus {
+	switch financial {
+	case "paid", "partially_refunded":
+		return order.OrderPaymentStatusPaid
+	case "refunded":
+		return order.OrderPaymentStatusRefunded
+	case "voided":
+		return order.OrderPaymentStatusFailed
+	default:
+		return order.OrderPaymentStatusPending
+	}
+}
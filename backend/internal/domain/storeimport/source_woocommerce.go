@@ -0,0 +1,338 @@
+package storeimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+)
+
+// wooPageSize is the largest page the WooCommerce REST API returns.
+const wooPageSize = 100
+
+// wooSource reads a store through the WooCommerce REST API (v3) with a consumer key and
+// secret, sent as basic auth, which WooCommerce accepts over HTTPS. Pages are numbered.
+type wooSource struct {
+	client  *http.Client
+	baseURL string
+	key     string
+	secret  string
+}
+
+func newWooSource(storeURL string, creds Credentials) *wooSource {
+	return &wooSource{
+		client:  newStoreClient(),
+		baseURL: storeURL + "/wp-json/wc/v3",
+		key:     creds.ConsumerKey,
+		secret:  creds.ConsumerSecret,
+	}
+}
+
+// get decodes one response of the API into out.
+func (s *wooSource) get(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.key, s.secret)
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxAPIResponseBytes))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &apiError{platform: PlatformWooCommerce, status: resp.StatusCode}
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("woocommerce returned an unreadable response: %w", err)
+	}
+	return nil
+}
+
+// wooPages reads the numbered pages of a list endpoint until a short page.
+func wooPages[T any](ctx context.Context, s *wooSource, path string, fn func([]T) error) error {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	for page := 1; ; page++ {
+		var items []T
+		if err := s.get(ctx, fmt.Sprintf("%s%sper_page=%d&page=%d", path, sep, wooPageSize, page), &items); err != nil {
+			return err
+		}
+		if len(items) > 0 {
+			if err := fn(items); err != nil {
+				return err
+			}
+		}
+		if len(items) < wooPageSize {
+			return nil
+		}
+	}
+}
+
+type wooAddress struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Address1  string `json:"address_1"`
+	Address2  string `json:"address_2"`
+	City      string `json:"city"`
+	State     string `json:"state"`
+	Postcode  string `json:"postcode"`
+	Country   string `json:"country"`
+	Email     string `json:"email"`
+	Phone     string `json:"phone"`
+}
+
+func (a *wooAddress) toExternal() *externalAddress {
+	return &externalAddress{
+		Street:      strings.TrimSpace(a.Address1 + " " + a.Address2),
+		City:        a.City,
+		State:       a.State,
+		ZipCode:     a.Postcode,
+		CountryCode: a.Country,
+		Phone:       a.Phone,
+	}
+}
+
+type wooImage struct {
+	Src string `json:"src"`
+}
+
+type wooProduct struct {
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	Description   string `json:"description"`
+	SKU           string `json:"sku"`
+	RegularPrice  string `json:"regular_price"`
+	Price         string `json:"price"`
+	StockQuantity *int   `json:"stock_quantity"`
+	Categories    []struct {
+		Name string `json:"name"`
+	} `json:"categories"`
+	Images []wooImage `json:"images"`
+}
+
+type wooVariation struct {
+	ID            int64     `json:"id"`
+	SKU           string    `json:"sku"`
+	RegularPrice  string    `json:"regular_price"`
+	Price         string    `json:"price"`
+	StockQuantity *int      `json:"stock_quantity"`
+	Image         *wooImage `json:"image"`
+	Attributes    []struct {
+		Option string `json:"option"`
+	} `json:"attributes"`
+}
+
+// wooPrice is the regular price of a product, which sales discount temporarily.
+func wooPrice(regular, price string) string {
+	if strings.TrimSpace(regular) != "" {
+		return regular
+	}
+	return price
+}
+
+func wooStock(q *int) int {
+	if q == nil {
+		return 0
+	}
+	return max(*q, 0)
+}
+
+func (s *wooSource) products(ctx context.Context, fn func([]*externalProduct) error) error {
+	return wooPages(ctx, s, "/products", func(items []wooProduct) error {
+		products := make([]*externalProduct, 0, len(items))
+		for _, wp := range items {
+			ep := &externalProduct{
+				ID:          strconv.FormatInt(wp.ID, 10),
+				Name:        wp.Name,
+				Description: plainText(wp.Description),
+			}
+			if len(wp.Categories) > 0 {
+				ep.Category = html.UnescapeString(wp.Categories[0].Name)
+			}
+			for _, img := range wp.Images {
+				ep.Images = append(ep.Images, img.Src)
+			}
+			if wp.Type != "variable" {
+				ep.Variants = []*externalVariant{{
+					ID:    ep.ID,
+					Code:  defaultVariantCode,
+					SKU:   wp.SKU,
+					Price: parseAmount(wooPrice(wp.RegularPrice, wp.Price)),
+					Stock: wooStock(wp.StockQuantity),
+				}}
+				products = append(products, ep)
+				continue
+			}
+			err := wooPages(ctx, s, fmt.Sprintf("/products/%d/variations", wp.ID), func(vars []wooVariation) error {
+				for _, wv := range vars {
+					options := make([]string, len(wv.Attributes))
+					for i, a := range wv.Attributes {
+						options[i] = a.Option
+					}
+					ev := &externalVariant{
+						ID:    strconv.FormatInt(wv.ID, 10),
+						Code:  variantCode(options...),
+						SKU:   wv.SKU,
+						Price: parseAmount(wooPrice(wv.RegularPrice, wv.Price)),
+						Stock: wooStock(wv.StockQuantity),
+					}
+					if wv.Image != nil {
+						ev.Image = wv.Image.Src
+					}
+					ep.Variants = append(ep.Variants, ev)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			products = append(products, ep)
+		}
+		return fn(products)
+	})
+}
+
+type wooCustomer struct {
+	ID        int64      `json:"id"`
+	Email     string     `json:"email"`
+	FirstName string     `json:"first_name"`
+	LastName  string     `json:"last_name"`
+	Billing   wooAddress `json:"billing"`
+	Shipping  wooAddress `json:"shipping"`
+}
+
+func (c *wooCustomer) toExternal() *externalCustomer {
+	ec := &externalCustomer{
+		ID:      strconv.FormatInt(c.ID, 10),
+		Name:    strings.TrimSpace(c.FirstName + " " + c.LastName),
+		Email:   c.Email,
+		Phone:   c.Billing.Phone,
+		Address: c.Shipping.toExternal(),
+	}
+	if ec.Address.empty() {
+		ec.Address = c.Billing.toExternal()
+	}
+	if ec.Name == "" {
+		ec.Name = strings.TrimSpace(c.Billing.FirstName + " " + c.Billing.LastName)
+	}
+	return ec
+}
+
+func (s *wooSource) customers(ctx context.Context, fn func([]*externalCustomer) error) error {
+	return wooPages(ctx, s, "/customers?role=all", func(items []wooCustomer) error {
+		customers := make([]*externalCustomer, len(items))
+		for i := range items {
+			customers[i] = items[i].toExternal()
+		}
+		return fn(customers)
+	})
+}
+
+func (s *wooSource) orders(ctx context.Context, fn func([]*externalOrder) error) error {
+	type wooOrder struct {
+		ID            int64      `json:"id"`
+		Number        string     `json:"number"`
+		Status        string     `json:"status"`
+		DateCreated   string     `json:"date_created_gmt"`
+		DatePaid      *string    `json:"date_paid_gmt"`
+		CustomerID    int64      `json:"customer_id"`
+		CustomerNote  string     `json:"customer_note"`
+		PaymentMethod string     `json:"payment_method"`
+		ShippingTotal string     `json:"shipping_total"`
+		DiscountTotal string     `json:"discount_total"`
+		Billing       wooAddress `json:"billing"`
+		Shipping      wooAddress `json:"shipping"`
+		LineItems     []struct {
+			ProductID   int64   `json:"product_id"`
+			VariationID int64   `json:"variation_id"`
+			Quantity    int     `json:"quantity"`
+			Price       float64 `json:"price"`
+		} `json:"line_items"`
+	}
+	return wooPages(ctx, s, "/orders?order=asc", func(items []wooOrder) error {
+		orders := make([]*externalOrder, 0, len(items))
+		for _, wo := range items {
+			orderedAt, _ := time.Parse("2006-01-02T15:04:05", wo.DateCreated)
+			eo := &externalOrder{
+				ID:              strconv.FormatInt(wo.ID, 10),
+				Number:          wo.Number,
+				OrderedAt:       orderedAt,
+				ShippingAddress: wo.Shipping.toExternal(),
+				ShippingFee:     parseAmount(wo.ShippingTotal),
+				Discount:        parseAmount(wo.DiscountTotal),
+				Status:          wooOrderStatus(wo.Status),
+				PaymentStatus:   wooPaymentStatus(wo.Status, wo.DatePaid != nil && *wo.DatePaid != ""),
+				PaymentMethod:   paymentMethodFromGateway(wo.PaymentMethod),
+				Note:            wo.CustomerNote,
+			}
+			buyer := (&wooCustomer{ID: wo.CustomerID, Email: wo.Billing.Email, Billing: wo.Billing, Shipping: wo.Shipping}).toExternal()
+			if wo.CustomerID == 0 {
+				// guest checkout
+				buyer.ID = ""
+			}
+			eo.Customer = buyer
+			if eo.ShippingAddress.empty() {
+				eo.ShippingAddress = wo.Billing.toExternal()
+			}
+			for _, li := range wo.LineItems {
+				variantID := li.VariationID
+				if variantID == 0 {
+					variantID = li.ProductID
+				}
+				if variantID == 0 {
+					continue
+				}
+				eo.Items = append(eo.Items, &externalOrderItem{
+					VariantID: strconv.FormatInt(variantID, 10),
+					Quantity:  li.Quantity,
+					UnitPrice: parseAmount(strconv.FormatFloat(li.Price, 'f', -1, 64)),
+				})
+			}
+			orders = append(orders, eo)
+		}
+		return fn(orders)
+	})
+}
+
+func wooOrderStatus(status string) order.OrderStatus {
+	switch status {
+	case "completed":
+		return order.OrderStatusFulfilled
+	case "processing":
+		return order.OrderStatusPlaced
+	case "cancelled", "refunded", "failed":
+		return order.OrderStatusCancelled
+	default:
+		// pending, on-hold and drafts are awaiting payment
+		return order.OrderStatusPending
+	}
+}
+
+func wooPaymentStatus(status string, paid bool) order.OrderPaymentStatus {
+	switch {
+	case status == "refunded":
+		return order.OrderPaymentStatusRefunded
+	case status == "failed":
+		return order.OrderPaymentStatusFailed
+	case paid:
+		return order.OrderPaymentStatusPaid
+	default:
+		return order.OrderPaymentStatusPending
+	}
+}
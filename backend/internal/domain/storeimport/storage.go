@@ -0,0 +1,23 @@
+package storeimport
+
+import (
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+)
+
+// Storage provides data access for store imports.
+type Storage struct {
+	db      *database.Database
+	imports *database.Repository[StoreImport]
+	files   *database.Repository[StoreImportFile]
+	records *database.Repository[ImportRecord]
+}
+
+// NewStorage creates a new store import storage instance.
+func NewStorage(db *database.Database) *Storage {
+	return &Storage{
+		db:      db,
+		imports: database.NewRepository[StoreImport](db),
+		files:   database.NewRepository[StoreImportFile](db),
+		records: database.NewRepository[ImportRecord](db),
+	}
+}
@@ -0,0 +1,22 @@
+package task
+
+import "github.com/abdelrahman146/kyora/internal/platform/types/problem"
+
+func ErrTaskNotFound(id string, err error) error {
+	return problem.NotFound("task not found").
+		With("taskId", id).
+		WithError(err).
+		WithCode("task.not_found")
+}
+
+func ErrTaskInvalidQuery(err error) error {
+	return problem.BadRequest("invalid query parameters").
+		WithError(err).
+		WithCode("task.invalid_query")
+}
+
+func ErrTaskQueryFailed(err error) error {
+	return problem.InternalError().
+		WithError(err).
+		WithCode("task.query_failed")
+}
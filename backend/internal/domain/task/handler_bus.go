@@ -0,0 +1,135 @@
+package task
+
+import (
+	"context"
+
+	"github.com/abdelrahman146/kyora/internal/platform/bus"
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+)
+
+// BusHandler listens for order lifecycle and inventory margin events and keeps the automated tasks in sync.
+type BusHandler struct {
+	svc *Service
+}
+
+// NewBusHandler registers task listeners on the event bus.
+func NewBusHandler(b *bus.Bus, svc *Service) {
+	h := &BusHandler{svc: svc}
+	b.Handle(bus.OrderPaidTopic, "task.prepare_order", h.HandleOrderPaid)
+	b.Handle(bus.OrderFulfilledTopic, "task.complete_prepare_order", h.HandleOrderFulfilled)
+	b.Handle(bus.OrderCancelledTopic, "task.discard_prepare_order", h.HandleOrderCancelled)
+	b.Handle(bus.VariantMarginBelowThresholdTopic, "task.review_pricing", h.HandleVariantMarginBelowThreshold)
+	b.Handle(bus.OrderStatusChangedTopic, "task.notify_customer", h.HandleOrderStatusChanged)
+}
+
+// HandleOrderPaid opens a task to prepare the paid order. Malformed events are logged and
+// dropped; storage failures are returned so the bus retries them.
+func (h *BusHandler) HandleOrderPaid(event any) error {
+	e, ok := event.(*bus.OrderPaidEvent)
+	if !ok {
+		logger.FromContext(context.Background()).Error("invalid event type for OrderPaidEvent")
+		return nil
+	}
+	if e.BusinessID == "" || e.OrderID == "" {
+		logger.FromContext(e.Ctx).Error("missing required fields in OrderPaidEvent", "businessId", e.BusinessID, "orderId", e.OrderID)
+		return nil
+	}
+	if err := h.svc.CreatePrepareOrderTask(e.Ctx, e.BusinessID, e.OrderID, e.OrderNumber, e.PaidAt); err != nil {
+		logger.FromContext(e.Ctx).Error("failed to create prepare order task", "error", err, "businessId", e.BusinessID, "orderId", e.OrderID)
+		return err
+	}
+	return nil
+}
+
+// HandleOrderFulfilled completes the order's open prepare task.
+func (h *BusHandler) HandleOrderFulfilled(event any) error {
+	e, ok := event.(*bus.OrderFulfilledEvent)
+	if !ok {
+		logger.FromContext(context.Background()).Error("invalid event type for OrderFulfilledEvent")
+		return nil
+	}
+	if e.BusinessID == "" || e.OrderID == "" {
+		logger.FromContext(e.Ctx).Error("missing required fields in OrderFulfilledEvent", "businessId", e.BusinessID, "orderId", e.OrderID)
+		return nil
+	}
+	if err := h.svc.CompleteOrderTasks(e.Ctx, e.BusinessID, e.OrderID, e.FulfilledAt); err != nil {
+		logger.FromContext(e.Ctx).Error("failed to complete prepare order task", "error", err, "businessId", e.BusinessID, "orderId", e.OrderID)
+		return err
+	}
+	return nil
+}
+
+// HandleOrderCancelled discards the order's open prepare task.
+func (h *BusHandler) HandleOrderCancelled(event any) error {
+	e, ok := event.(*bus.OrderCancelledEvent)
+	if !ok {
+		logger.FromContext(context.Background()).Error("invalid event type for OrderCancelledEvent")
+		return nil
+	}
+	if e.BusinessID == "" || e.OrderID == "" {
+		logger.FromContext(e.Ctx).Error("missing required fields in OrderCancelledEvent", "businessId", e.BusinessID, "orderId", e.OrderID)
+		return nil
+	}
+	if err := h.svc.DiscardOrderTasks(e.Ctx, e.BusinessID, e.OrderID); err != nil {
+		logger.FromContext(e.Ctx).Error("failed to discard prepare order task", "error", err, "businessId", e.BusinessID, "orderId", e.OrderID)
+		return err
+	}
+	return nil
+}
+
+// HandleVariantMarginBelowThreshold opens a task to review the pricing of the product.
+func (h *BusHandler) HandleVariantMarginBelowThreshold(event any) error {
+	e, ok := event.(*bus.VariantMarginBelowThresholdEvent)
+	if !ok {
+		logger.FromContext(context.Background()).Error("invalid event type for VariantMarginBelowThresholdEvent")
+		return nil
+	}
+	if e.BusinessID == "" || e.ProductID == "" || e.CostChangeID == "" {
+		logger.FromContext(e.Ctx).Error("missing required fields in VariantMarginBelowThresholdEvent", "businessId", e.BusinessID, "productId", e.ProductID, "costChangeId", e.CostChangeID)
+		return nil
+	}
+	alert := &MarginAlert{
+		ProductID:        e.ProductID,
+		VariantName:      e.VariantName,
+		CostChangeID:     e.CostChangeID,
+		MarginPercent:    e.MarginPercent,
+		ThresholdPercent: e.ThresholdPercent,
+		ChangedAt:        e.ChangedAt,
+	}
+	if err := h.svc.CreateReviewPricingTask(e.Ctx, e.BusinessID, alert); err != nil {
+		logger.FromContext(e.Ctx).Error("failed to create review pricing task", "error", err, "businessId", e.BusinessID, "productId", e.ProductID)
+		return err
+	}
+	return nil
+}
+
+// HandleOrderStatusChanged opens a task to message the customer when a notify_customer
+// transition rule ran. Other status changes are ignored.
+func (h *BusHandler) HandleOrderStatusChanged(event any) error {
+	e, ok := event.(*bus.OrderStatusChangedEvent)
+	if !ok {
+		logger.FromContext(context.Background()).Error("invalid event type for OrderStatusChangedEvent")
+		return nil
+	}
+	if e.CustomerMessage == "" {
+		return nil
+	}
+	if e.BusinessID == "" || e.OrderID == "" {
+		logger.FromContext(e.Ctx).Error("missing required fields in OrderStatusChangedEvent", "businessId", e.BusinessID, "orderId", e.OrderID)
+		return nil
+	}
+	n := &CustomerNotification{
+		OrderID:        e.OrderID,
+		OrderNumber:    e.OrderNumber,
+		CustomerID:     e.CustomerID,
+		WhatsappNumber: e.CustomerWhatsappNumber,
+		Status:         e.ToStatus,
+		Message:        e.CustomerMessage,
+		ChangedAt:      e.ChangedAt,
+	}
+	if err := h.svc.CreateNotifyCustomerTask(e.Ctx, e.BusinessID, n); err != nil {
+		logger.FromContext(e.Ctx).Error("failed to create notify customer task", "error", err, "businessId", e.BusinessID, "orderId", e.OrderID)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,305 @@
+package task
+
+import (
+	"net/http"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/request"
+	"github.com/abdelrahman146/kyora/internal/platform/response"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/gin-gonic/gin"
+)
+
+// HttpHandler handles HTTP requests for business team tasks.
+type HttpHandler struct {
+	service *Service
+}
+
+// NewHttpHandler creates a new task HTTP handler.
+func NewHttpHandler(service *Service) *HttpHandler {
+	return &HttpHandler{service: service}
+}
+
+type listTasksQuery struct {
+	Page       int        `form:"page" binding:"omitempty,min=1"`
+	PageSize   int        `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	OrderBy    []string   `form:"orderBy" binding:"omitempty"`
+	Status     TaskStatus `form:"status" binding:"omitempty,oneof=open done"`
+	Type       TaskType   `form:"type" binding:"omitempty,oneof=general prepare_order restock_product follow_up_customer review_pricing"`
+	AssigneeID string     `form:"assigneeId" binding:"omitempty"`
+	Unassigned bool       `form:"unassigned"`
+	OrderID    string     `form:"orderId" binding:"omitempty"`
+	ProductID  string     `form:"productId" binding:"omitempty"`
+	CustomerID string     `form:"customerId" binding:"omitempty"`
+}
+
+type listMyTasksQuery struct {
+	Page              int        `form:"page" binding:"omitempty,min=1"`
+	PageSize          int        `form:"pageSize" binding:"omitempty,min=1,max=100"`
+	OrderBy           []string   `form:"orderBy" binding:"omitempty"`
+	Status            TaskStatus `form:"status" binding:"omitempty,oneof=open done"`
+	Type              TaskType   `form:"type" binding:"omitempty,oneof=general prepare_order restock_product follow_up_customer review_pricing"`
+	IncludeUnassigned bool       `form:"includeUnassigned"`
+}
+
+func (h *HttpHandler) listTasks(c *gin.Context, actor *account.User, biz *business.Business, page, pageSize int, orderBy []string, filters *ListFilters) {
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = 20
+	}
+	listReq := list.NewListRequest(page, pageSize, orderBy, "")
+	items, totalCount, err := h.service.ListTasks(c.Request.Context(), actor, biz, listReq, filters)
+	if err != nil {
+		response.Error(c, ErrTaskQueryFailed(err))
+		return
+	}
+	hasMore := int64(page*pageSize) < totalCount
+	response.SuccessJSON(c, http.StatusOK, list.NewListResponse(ToTaskResponses(items), page, pageSize, totalCount, hasMore))
+}
+
+// ListTasks returns a paginated list of the business' tasks
+//
+// @Summary      List tasks
+// @Description  Returns the business' tasks, due soonest first unless ordered otherwise
+// @Tags         task
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        page query int false "Page number"
+// @Param        pageSize query int false "Page size (max 100)"
+// @Param        orderBy query []string false "Order by fields (e.g. dueAt, -createdAt)"
+// @Param        status query string false "open or done"
+// @Param        type query string false "general, prepare_order, restock_product or follow_up_customer"
+// @Param        assigneeId query string false "Assigned user ID"
+// @Param        unassigned query bool false "Only tasks without an assignee (or, with assigneeId, also them)"
+// @Param        orderId query string false "Linked order ID"
+// @Param        productId query string false "Linked product ID"
+// @Param        customerId query string false "Linked customer ID"
+// @Success      200 {object} list.ListResponse[task.TaskResponse]
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/tasks [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListTasks(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := business.BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query listTasksQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, ErrTaskInvalidQuery(err))
+		return
+	}
+	h.listTasks(c, actor, biz, query.Page, query.PageSize, query.OrderBy, &ListFilters{
+		Status:     query.Status,
+		Type:       query.Type,
+		AssigneeID: query.AssigneeID,
+		Unassigned: query.Unassigned,
+		OrderID:    query.OrderID,
+		ProductID:  query.ProductID,
+		CustomerID: query.CustomerID,
+	})
+}
+
+// ListMyTasks returns the tasks assigned to the authenticated user
+//
+// @Summary      List my tasks
+// @Description  Returns the caller's open tasks (or done ones with status=done), optionally including unassigned tasks anyone can pick up
+// @Tags         task
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        page query int false "Page number"
+// @Param        pageSize query int false "Page size (max 100)"
+// @Param        orderBy query []string false "Order by fields (e.g. dueAt, -createdAt)"
+// @Param        status query string false "open (default) or done"
+// @Param        type query string false "general, prepare_order, restock_product or follow_up_customer"
+// @Param        includeUnassigned query bool false "Also return unassigned tasks"
+// @Success      200 {object} list.ListResponse[task.TaskResponse]
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/tasks/mine [get]
+// @Security     BearerAuth
+func (h *HttpHandler) ListMyTasks(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := business.BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var query listMyTasksQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, ErrTaskInvalidQuery(err))
+		return
+	}
+	if query.Status == "" {
+		query.Status = TaskStatusOpen
+	}
+	h.listTasks(c, actor, biz, query.Page, query.PageSize, query.OrderBy, &ListFilters{
+		Status:     query.Status,
+		Type:       query.Type,
+		AssigneeID: actor.ID,
+		Unassigned: query.IncludeUnassigned,
+	})
+}
+
+// GetTask returns a task by ID
+//
+// @Summary      Get task
+// @Tags         task
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        taskId path string true "Task ID"
+// @Success      200 {object} task.TaskResponse
+// @Failure      401 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/tasks/{taskId} [get]
+// @Security     BearerAuth
+func (h *HttpHandler) GetTask(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := business.BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	t, err := h.service.GetTaskByID(c.Request.Context(), actor, biz, c.Param("taskId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToTaskResponse(t))
+}
+
+// CreateTask creates a task
+//
+// @Summary      Create task
+// @Description  Creates a task, optionally assigned to a workspace user and linked to an order, product or customer
+// @Tags         task
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        body body CreateTaskRequest true "Task"
+// @Success      201 {object} task.TaskResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/tasks [post]
+// @Security     BearerAuth
+func (h *HttpHandler) CreateTask(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := business.BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req CreateTaskRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	t, err := h.service.CreateTask(c.Request.Context(), actor, biz, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusCreated, ToTaskResponse(t))
+}
+
+// UpdateTask updates a task
+//
+// @Summary      Update task
+// @Description  Updates a task's details, assignee, due date or status
+// @Tags         task
+// @Accept       json
+// @Produce      json
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        taskId path string true "Task ID"
+// @Param        body body UpdateTaskRequest true "Updates"
+// @Success      200 {object} task.TaskResponse
+// @Failure      400 {object} problem.Problem
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/tasks/{taskId} [patch]
+// @Security     BearerAuth
+func (h *HttpHandler) UpdateTask(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := business.BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	var req UpdateTaskRequest
+	if err := request.ValidBody(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	t, err := h.service.UpdateTask(c.Request.Context(), actor, biz, c.Param("taskId"), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessJSON(c, http.StatusOK, ToTaskResponse(t))
+}
+
+// DeleteTask deletes a task
+//
+// @Summary      Delete task
+// @Tags         task
+// @Param        businessDescriptor path string true "Business descriptor"
+// @Param        taskId path string true "Task ID"
+// @Success      204
+// @Failure      401 {object} problem.Problem
+// @Failure      403 {object} problem.Problem
+// @Failure      404 {object} problem.Problem
+// @Failure      500 {object} problem.Problem
+// @Router       /v1/businesses/{businessDescriptor}/tasks/{taskId} [delete]
+// @Security     BearerAuth
+func (h *HttpHandler) DeleteTask(c *gin.Context) {
+	actor, err := account.ActorFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	biz, err := business.BusinessFromContext(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.service.DeleteTask(c.Request.Context(), actor, biz, c.Param("taskId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.SuccessEmpty(c, http.StatusNoContent)
+}
@@ -0,0 +1,105 @@
+package task
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/nullable"
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"gorm.io/gorm"
+)
+
+const (
+	TaskTable  = "tasks"
+	TaskStruct = "Task"
+	TaskPrefix = "tsk"
+)
+
+// TaskType tells what a task is about; typed tasks link the order, product or customer
+// they refer to.
+type TaskType string
+
+const (
+	TaskTypeGeneral          TaskType = "general"
+	TaskTypePrepareOrder     TaskType = "prepare_order"
+	TaskTypeRestockProduct   TaskType = "restock_product"
+	TaskTypeFollowUpCustomer TaskType = "follow_up_customer"
+	TaskTypeReviewPricing    TaskType = "review_pricing"
+)
+
+type TaskStatus string
+
+const (
+	TaskStatusOpen TaskStatus = "open"
+	TaskStatusDone TaskStatus = "done"
+)
+
+// Task is a piece of work for the business team, optionally assigned to a workspace user
+// and due at a given time. Tasks created by automations carry a SourceKey (e.g.
+// "order.paid:<orderId>") that is unique per business, so replayed events never create
+// the same task twice.
+type Task struct {
+	gorm.Model
+	ID            string          `gorm:"column:id;primaryKey;type:text" json:"id"`
+	BusinessID    string          `gorm:"column:business_id;type:text;not null;index;uniqueIndex:idx_business_task_source_key" json:"businessId"`
+	Type          TaskType        `gorm:"column:type;type:text;not null;default:'general'" json:"type"`
+	Status        TaskStatus      `gorm:"column:status;type:text;not null;default:'open';index" json:"status"`
+	Title         string          `gorm:"column:title;type:text;not null" json:"title"`
+	Description   string          `gorm:"column:description;type:text" json:"description"`
+	AssigneeID    nullable.String `gorm:"column:assignee_id;type:text;index" json:"assigneeId,omitempty"`
+	CreatedByID   nullable.String `gorm:"column:created_by_id;type:text" json:"createdById,omitempty"`
+	DueAt         sql.NullTime    `gorm:"column:due_at" json:"dueAt"`
+	OrderID       nullable.String `gorm:"column:order_id;type:text;index" json:"orderId,omitempty"`
+	ProductID     nullable.String `gorm:"column:product_id;type:text;index" json:"productId,omitempty"`
+	CustomerID    nullable.String `gorm:"column:customer_id;type:text;index" json:"customerId,omitempty"`
+	SourceKey     nullable.String `gorm:"column:source_key;type:text;uniqueIndex:idx_business_task_source_key" json:"sourceKey,omitempty"`
+	CompletedAt   sql.NullTime    `gorm:"column:completed_at" json:"completedAt"`
+	CompletedByID nullable.String `gorm:"column:completed_by_id;type:text" json:"completedById,omitempty"`
+}
+
+func (m *Task) TableName() string { return TaskTable }
+
+func (m *Task) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == "" {
+		m.ID = id.KsuidWithPrefix(TaskPrefix)
+	}
+	return
+}
+
+// IsOverdue reports whether an open task is past its due date at t.
+func (m *Task) IsOverdue(t time.Time) bool {
+	return m.Status == TaskStatusOpen && m.DueAt.Valid && m.DueAt.Time.Before(t)
+}
+
+var TaskSchema = struct {
+	ID          schema.Field
+	BusinessID  schema.Field
+	Type        schema.Field
+	Status      schema.Field
+	Title       schema.Field
+	AssigneeID  schema.Field
+	DueAt       schema.Field
+	OrderID     schema.Field
+	ProductID   schema.Field
+	CustomerID  schema.Field
+	SourceKey   schema.Field
+	CompletedAt schema.Field
+	CreatedAt   schema.Field
+	UpdatedAt   schema.Field
+}{
+	ID:          schema.NewField("id", "id"),
+	BusinessID:  schema.NewField("business_id", "businessId"),
+	Type:        schema.NewField("type", "type"),
+	Status:      schema.NewField("status", "status"),
+	Title:       schema.NewField("title", "title"),
+	AssigneeID:  schema.NewField("assignee_id", "assigneeId"),
+	DueAt:       schema.NewField("due_at", "dueAt"),
+	OrderID:     schema.NewField("order_id", "orderId"),
+	ProductID:   schema.NewField("product_id", "productId"),
+	CustomerID:  schema.NewField("customer_id", "customerId"),
+	SourceKey:   schema.NewField("source_key", "sourceKey"),
+	CompletedAt: schema.NewField("completed_at", "completedAt"),
+	CreatedAt:   schema.NewField("created_at", "createdAt"),
+	UpdatedAt:   schema.NewField("updated_at", "updatedAt"),
+}
@@ -0,0 +1,27 @@
+package task
+
+import "time"
+
+// CreateTaskRequest creates a task. OrderID, ProductID and CustomerID link the task to what
+// it is about and must belong to the business.
+type CreateTaskRequest struct {
+	Title       string     `json:"title" binding:"required,max=200"`
+	Description string     `json:"description" binding:"omitempty,max=2000"`
+	Type        TaskType   `json:"type" binding:"omitempty,oneof=general prepare_order restock_product follow_up_customer review_pricing"`
+	AssigneeID  string     `json:"assigneeId" binding:"omitempty"`
+	DueAt       *time.Time `json:"dueAt" binding:"omitempty"`
+	OrderID     string     `json:"orderId" binding:"omitempty"`
+	ProductID   string     `json:"productId" binding:"omitempty"`
+	CustomerID  string     `json:"customerId" binding:"omitempty"`
+}
+
+// UpdateTaskRequest updates a task. An empty assigneeId unassigns the task; clearDueAt
+// removes the due date. Setting status to done records who completed it and when.
+type UpdateTaskRequest struct {
+	Title       *string     `json:"title" binding:"omitempty,min=1,max=200"`
+	Description *string     `json:"description" binding:"omitempty,max=2000"`
+	AssigneeID  *string     `json:"assigneeId" binding:"omitempty"`
+	DueAt       *time.Time  `json:"dueAt" binding:"omitempty"`
+	ClearDueAt  bool        `json:"clearDueAt"`
+	Status      *TaskStatus `json:"status" binding:"omitempty,oneof=open done"`
+}
@@ -0,0 +1,69 @@
+package task
+
+import "time"
+
+// TaskResponse is the API response for a task. Source is "automation" for tasks created
+// from events and "manual" otherwise.
+type TaskResponse struct {
+	ID            string     `json:"id"`
+	BusinessID    string     `json:"businessId"`
+	Type          TaskType   `json:"type"`
+	Status        TaskStatus `json:"status"`
+	Title         string     `json:"title"`
+	Description   string     `json:"description"`
+	AssigneeID    string     `json:"assigneeId,omitempty"`
+	CreatedByID   string     `json:"createdById,omitempty"`
+	DueAt         *time.Time `json:"dueAt,omitempty"`
+	Overdue       bool       `json:"overdue"`
+	OrderID       string     `json:"orderId,omitempty"`
+	ProductID     string     `json:"productId,omitempty"`
+	CustomerID    string     `json:"customerId,omitempty"`
+	Source        string     `json:"source"`
+	CompletedAt   *time.Time `json:"completedAt,omitempty"`
+	CompletedByID string     `json:"completedById,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// ToTaskResponse converts a Task model to its API response.
+func ToTaskResponse(m *Task) TaskResponse {
+	resp := TaskResponse{
+		ID:            m.ID,
+		BusinessID:    m.BusinessID,
+		Type:          m.Type,
+		Status:        m.Status,
+		Title:         m.Title,
+		Description:   m.Description,
+		AssigneeID:    m.AssigneeID.String,
+		CreatedByID:   m.CreatedByID.String,
+		Overdue:       m.IsOverdue(time.Now()),
+		OrderID:       m.OrderID.String,
+		ProductID:     m.ProductID.String,
+		CustomerID:    m.CustomerID.String,
+		Source:        "manual",
+		CompletedByID: m.CompletedByID.String,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}
+	if m.SourceKey.Valid {
+		resp.Source = "automation"
+	}
+	if m.DueAt.Valid {
+		t := m.DueAt.Time
+		resp.DueAt = &t
+	}
+	if m.CompletedAt.Valid {
+		t := m.CompletedAt.Time
+		resp.CompletedAt = &t
+	}
+	return resp
+}
+
+// ToTaskResponses converts a slice of Task models to responses.
+func ToTaskResponses(items []*Task) []TaskResponse {
+	responses := make([]TaskResponse, 0, len(items))
+	for _, m := range items {
+		responses = append(responses, ToTaskResponse(m))
+	}
+	return responses
+}
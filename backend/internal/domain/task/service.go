@@ -0,0 +1,390 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/atomic"
+	"github.com/abdelrahman146/kyora/internal/platform/types/list"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// prepareOrderDueIn is how long the team has to prepare an order once it is paid.
+const prepareOrderDueIn = 24 * time.Hour
+
+// reviewPricingDueIn is how long the team has to review a product whose margin fell too low.
+const reviewPricingDueIn = 72 * time.Hour
+
+// notifyCustomerDueIn is how long the team has to send the message of a notify_customer
+// order transition rule.
+const notifyCustomerDueIn = 2 * time.Hour
+
+// Service manages business team tasks and the tasks automations create from events.
+type Service struct {
+	storage         *Storage
+	atomicProcessor atomic.AtomicProcessor
+	account         *account.Service
+	inventory       *inventory.Service
+	customer        *customer.Service
+	orders          *order.Service
+}
+
+// NewService creates the task service.
+func NewService(storage *Storage, atomicProcessor atomic.AtomicProcessor, accountSvc *account.Service, inventorySvc *inventory.Service, customerSvc *customer.Service, orderSvc *order.Service) *Service {
+	return &Service{
+		storage:         storage,
+		atomicProcessor: atomicProcessor,
+		account:         accountSvc,
+		inventory:       inventorySvc,
+		customer:        customerSvc,
+		orders:          orderSvc,
+	}
+}
+
+// ListFilters narrows ListTasks results. Unassigned matches tasks without an assignee;
+// combined with AssigneeID it matches tasks assigned to that user or to nobody.
+type ListFilters struct {
+	Status     TaskStatus
+	Type       TaskType
+	AssigneeID string
+	Unassigned bool
+	OrderID    string
+	ProductID  string
+	CustomerID string
+}
+
+// ListTasks returns a page of the business' tasks. Without an explicit order, tasks due
+// soonest come first and tasks without a due date last.
+func (s *Service) ListTasks(ctx context.Context, actor *account.User, biz *business.Business, req *list.ListRequest, filters *ListFilters) ([]*Task, int64, error) {
+	scopes := []func(*gorm.DB) *gorm.DB{s.storage.task.ScopeBusinessID(biz.ID)}
+	if filters != nil {
+		if filters.Status != "" {
+			scopes = append(scopes, s.storage.task.ScopeEquals(TaskSchema.Status, filters.Status))
+		}
+		if filters.Type != "" {
+			scopes = append(scopes, s.storage.task.ScopeEquals(TaskSchema.Type, filters.Type))
+		}
+		switch {
+		case filters.AssigneeID != "" && filters.Unassigned:
+			scopes = append(scopes, s.storage.task.ScopeWhere("(tasks.assignee_id = ? OR tasks.assignee_id IS NULL)", filters.AssigneeID))
+		case filters.AssigneeID != "":
+			scopes = append(scopes, s.storage.task.ScopeEquals(TaskSchema.AssigneeID, filters.AssigneeID))
+		case filters.Unassigned:
+			scopes = append(scopes, s.storage.task.ScopeIsNull(TaskSchema.AssigneeID))
+		}
+		if filters.OrderID != "" {
+			scopes = append(scopes, s.storage.task.ScopeEquals(TaskSchema.OrderID, filters.OrderID))
+		}
+		if filters.ProductID != "" {
+			scopes = append(scopes, s.storage.task.ScopeEquals(TaskSchema.ProductID, filters.ProductID))
+		}
+		if filters.CustomerID != "" {
+			scopes = append(scopes, s.storage.task.ScopeEquals(TaskSchema.CustomerID, filters.CustomerID))
+		}
+	}
+
+	total, err := s.storage.task.Count(ctx, scopes...)
+	if err != nil {
+		return nil, 0, err
+	}
+	orderBy := []string{"tasks.due_at ASC NULLS LAST", "tasks.created_at DESC"}
+	if req.HasExplicitOrderBy() {
+		orderBy = req.ParsedOrderBy(TaskSchema)
+	}
+	items, err := s.storage.task.FindMany(ctx, append(scopes,
+		s.storage.task.WithPagination(req.Offset(), req.Limit()),
+		s.storage.task.WithOrderBy(orderBy),
+	)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (s *Service) GetTaskByID(ctx context.Context, actor *account.User, biz *business.Business, id string) (*Task, error) {
+	t, err := s.storage.task.FindOne(ctx,
+		s.storage.task.ScopeBusinessID(biz.ID),
+		s.storage.task.ScopeID(id),
+	)
+	if err != nil {
+		if database.IsRecordNotFound(err) {
+			return nil, ErrTaskNotFound(id, err)
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *Service) CreateTask(ctx context.Context, actor *account.User, biz *business.Business, req *CreateTaskRequest) (*Task, error) {
+	assigneeID := strings.TrimSpace(req.AssigneeID)
+	if err := s.validateAssignee(ctx, biz, assigneeID); err != nil {
+		return nil, err
+	}
+	orderID, productID, customerID := strings.TrimSpace(req.OrderID), strings.TrimSpace(req.ProductID), strings.TrimSpace(req.CustomerID)
+	if err := s.validateReferences(ctx, actor, biz, orderID, productID, customerID); err != nil {
+		return nil, err
+	}
+	typ := req.Type
+	if typ == "" {
+		typ = TaskTypeGeneral
+	}
+	t := &Task{
+		BusinessID:  biz.ID,
+		Type:        typ,
+		Status:      TaskStatusOpen,
+		Title:       strings.TrimSpace(req.Title),
+		Description: strings.TrimSpace(req.Description),
+		AssigneeID:  transformer.ToNullableString(assigneeID),
+		CreatedByID: transformer.ToNullableString(actor.ID),
+		OrderID:     transformer.ToNullableString(orderID),
+		ProductID:   transformer.ToNullableString(productID),
+		CustomerID:  transformer.ToNullableString(customerID),
+	}
+	if req.DueAt != nil {
+		t.DueAt = sql.NullTime{Time: req.DueAt.UTC(), Valid: true}
+	}
+	if err := s.storage.task.CreateOne(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *Service) UpdateTask(ctx context.Context, actor *account.User, biz *business.Business, id string, req *UpdateTaskRequest) (*Task, error) {
+	t, err := s.GetTaskByID(ctx, actor, biz, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Title != nil {
+		t.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Description != nil {
+		t.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.AssigneeID != nil {
+		assigneeID := strings.TrimSpace(*req.AssigneeID)
+		if err := s.validateAssignee(ctx, biz, assigneeID); err != nil {
+			return nil, err
+		}
+		t.AssigneeID = transformer.ToNullableString(assigneeID)
+	}
+	if req.ClearDueAt {
+		t.DueAt = sql.NullTime{}
+	} else if req.DueAt != nil {
+		t.DueAt = sql.NullTime{Time: req.DueAt.UTC(), Valid: true}
+	}
+	if req.Status != nil && *req.Status != t.Status {
+		t.Status = *req.Status
+		if t.Status == TaskStatusDone {
+			t.CompletedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+			t.CompletedByID = transformer.ToNullableString(actor.ID)
+		} else {
+			t.CompletedAt = sql.NullTime{}
+			t.CompletedByID = transformer.ToNullableString("")
+		}
+	}
+	if err := s.storage.task.UpdateOne(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *Service) DeleteTask(ctx context.Context, actor *account.User, biz *business.Business, id string) error {
+	t, err := s.GetTaskByID(ctx, actor, biz, id)
+	if err != nil {
+		return err
+	}
+	return s.storage.task.DeleteOne(ctx, t)
+}
+
+// validateAssignee ensures a task is only assigned to users of the business' workspace.
+func (s *Service) validateAssignee(ctx context.Context, biz *business.Business, assigneeID string) error {
+	if assigneeID == "" {
+		return nil
+	}
+	if _, err := s.account.GetWorkspaceUserByID(ctx, biz.WorkspaceID, assigneeID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateReferences ensures the linked order, product and customer belong to the business.
+func (s *Service) validateReferences(ctx context.Context, actor *account.User, biz *business.Business, orderID, productID, customerID string) error {
+	if orderID != "" {
+		found, err := s.orders.ExistingOrderIDs(ctx, actor, biz, []string{orderID})
+		if err != nil {
+			return err
+		}
+		if !found[orderID] {
+			return order.ErrOrderNotFound(orderID, nil)
+		}
+	}
+	if productID != "" {
+		if _, err := s.inventory.GetProductByID(ctx, actor, biz, productID); err != nil {
+			if database.IsRecordNotFound(err) {
+				return inventory.ErrProductNotFound(err).With("productId", productID)
+			}
+			return err
+		}
+	}
+	if customerID != "" {
+		if _, err := s.customer.GetCustomerByID(ctx, actor, biz, customerID); err != nil {
+			if database.IsRecordNotFound(err) {
+				return customer.ErrCustomerNotFound(err).With("customerId", customerID)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func prepareOrderSourceKey(orderID string) string {
+	return "order.paid:" + orderID
+}
+
+// CreatePrepareOrderTask opens an unassigned task to prepare a newly paid order, due
+// prepareOrderDueIn after payment. It is a no-op when the order already has one.
+func (s *Service) CreatePrepareOrderTask(ctx context.Context, businessID, orderID, orderNumber string, paidAt time.Time) error {
+	title := "Prepare order"
+	if orderNumber != "" {
+		title += " " + orderNumber
+	}
+	err := s.storage.task.CreateOne(ctx, &Task{
+		BusinessID: businessID,
+		Type:       TaskTypePrepareOrder,
+		Status:     TaskStatusOpen,
+		Title:      title,
+		DueAt:      sql.NullTime{Time: paidAt.Add(prepareOrderDueIn).UTC(), Valid: true},
+		OrderID:    transformer.ToNullableString(orderID),
+		SourceKey:  transformer.ToNullableString(prepareOrderSourceKey(orderID)),
+	})
+	if err != nil && !database.IsUniqueViolation(err) {
+		return err
+	}
+	return nil
+}
+
+func notifyCustomerSourceKey(orderID, status string) string {
+	return "order.status_changed:" + orderID + ":" + status
+}
+
+// CustomerNotification is a message an order transition rule asks the team to send.
+type CustomerNotification struct {
+	OrderID        string
+	OrderNumber    string
+	CustomerID     string
+	WhatsappNumber string
+	Status         string
+	Message        string
+	ChangedAt      time.Time
+}
+
+// whatsappLink returns a wa.me link that opens a chat with number prefilled with text, or
+// "" when number has no digits.
+func whatsappLink(number, text string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, number)
+	if digits == "" {
+		return ""
+	}
+	return "https://wa.me/" + digits + "?text=" + url.QueryEscape(text)
+}
+
+// CreateNotifyCustomerTask opens an unassigned task to send the customer the message of a
+// notify_customer rule, with a WhatsApp link when the customer has a number. It is a no-op
+// when the order already has one for that status.
+func (s *Service) CreateNotifyCustomerTask(ctx context.Context, businessID string, n *CustomerNotification) error {
+	title := "Message customer"
+	if n.OrderNumber != "" {
+		title += " about order " + n.OrderNumber
+	}
+	description := n.Message
+	if link := whatsappLink(n.WhatsappNumber, n.Message); link != "" {
+		description += "\n\nWhatsApp: " + link
+	}
+	err := s.storage.task.CreateOne(ctx, &Task{
+		BusinessID:  businessID,
+		Type:        TaskTypeFollowUpCustomer,
+		Status:      TaskStatusOpen,
+		Title:       title,
+		Description: description,
+		DueAt:       sql.NullTime{Time: n.ChangedAt.Add(notifyCustomerDueIn).UTC(), Valid: true},
+		OrderID:     transformer.ToNullableString(n.OrderID),
+		CustomerID:  transformer.ToNullableString(n.CustomerID),
+		SourceKey:   transformer.ToNullableString(notifyCustomerSourceKey(n.OrderID, n.Status)),
+	})
+	if err != nil && !database.IsUniqueViolation(err) {
+		return err
+	}
+	return nil
+}
+
+func reviewPricingSourceKey(costChangeID string) string {
+	return "inventory.margin_below_threshold:" + costChangeID
+}
+
+// MarginAlert describes a variant price change that dropped its margin below the business'
+// minimum.
+type MarginAlert struct {
+	ProductID        string
+	VariantName      string
+	CostChangeID     string
+	MarginPercent    decimal.Decimal
+	ThresholdPercent decimal.Decimal
+	ChangedAt        time.Time
+}
+
+// CreateReviewPricingTask opens an unassigned task to review the pricing of the product whose
+// variant margin fell below the minimum. It is a no-op when the price change already has one.
+func (s *Service) CreateReviewPricingTask(ctx context.Context, businessID string, alert *MarginAlert) error {
+	err := s.storage.task.CreateOne(ctx, &Task{
+		BusinessID: businessID,
+		Type:       TaskTypeReviewPricing,
+		Status:     TaskStatusOpen,
+		Title:      "Review pricing of " + alert.VariantName,
+		Description: fmt.Sprintf("Margin dropped to %s%% after a price change, below the %s%% minimum.",
+			alert.MarginPercent.String(), alert.ThresholdPercent.String()),
+		DueAt:     sql.NullTime{Time: alert.ChangedAt.Add(reviewPricingDueIn).UTC(), Valid: true},
+		ProductID: transformer.ToNullableString(alert.ProductID),
+		SourceKey: transformer.ToNullableString(reviewPricingSourceKey(alert.CostChangeID)),
+	})
+	if err != nil && !database.IsUniqueViolation(err) {
+		return err
+	}
+	return nil
+}
+
+// CompleteOrderTasks marks the open automated prepare task of an order as done.
+func (s *Service) CompleteOrderTasks(ctx context.Context, businessID, orderID string, at time.Time) error {
+	return s.storage.db.Conn(ctx).
+		Model(&Task{}).
+		Where("business_id = ? AND source_key = ? AND status = ?", businessID, prepareOrderSourceKey(orderID), TaskStatusOpen).
+		Updates(map[string]any{
+			TaskSchema.Status.Column():      TaskStatusDone,
+			TaskSchema.CompletedAt.Column(): at.UTC(),
+		}).Error
+}
+
+// DiscardOrderTasks removes the open automated prepare task of an order that will not ship.
+func (s *Service) DiscardOrderTasks(ctx context.Context, businessID, orderID string) error {
+	return s.storage.task.DeleteMany(ctx,
+		s.storage.task.ScopeBusinessID(businessID),
+		s.storage.task.ScopeEquals(TaskSchema.SourceKey, prepareOrderSourceKey(orderID)),
+		s.storage.task.ScopeEquals(TaskSchema.Status, TaskStatusOpen),
+	)
+}
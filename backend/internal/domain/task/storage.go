@@ -0,0 +1,19 @@
+package task
+
+import (
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+)
+
+// Storage provides data access for tasks.
+type Storage struct {
+	db   *database.Database
+	task *database.Repository[Task]
+}
+
+// NewStorage creates a new task storage instance.
+func NewStorage(db *database.Database) *Storage {
+	return &Storage{
+		db:   db,
+		task: database.NewRepository[Task](db),
+	}
+}
@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/abdelrahman146/kyora/internal/platform/response"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// EnforceAdminToken guards operator endpoints with the static bearer token configured in
+// auth.admin_api_token. Admin endpoints are unreachable while no token is configured.
+func EnforceAdminToken(c *gin.Context) {
+	expected := viper.GetString(config.AdminAPIToken)
+	if expected == "" {
+		response.Error(c, problem.NotFound("not found").WithCode("auth.admin_disabled"))
+		return
+	}
+	token := strings.TrimPrefix(JwtFromContext(c), bearerPrefix)
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+		response.Error(c, problem.Unauthorized("unauthorized").WithCode("auth.invalid_admin_token"))
+		return
+	}
+	c.Next()
+}
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/cache"
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/spf13/viper"
+)
+
+const confirmationTokenPrefix = "confirm:"
+
+// ConfirmationSubject is the destructive operation a confirmation token is issued for.
+// A token only confirms the exact action, resource and actor it was issued to.
+type ConfirmationSubject struct {
+	// Action names the operation, e.g. "business.delete".
+	Action     string
+	ResourceID string
+	// ResourceName is what the caller must type back to confirm, e.g. the business descriptor.
+	ResourceName string
+	ActorID      string
+}
+
+// Confirmation is a short-lived, single-use token authorizing one destructive operation.
+type Confirmation struct {
+	Token        string    `json:"token"`
+	Action       string    `json:"action"`
+	ResourceID   string    `json:"resourceId"`
+	ResourceName string    `json:"resourceName"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+// ConfirmRequest is what clients send back to carry out a confirmed destructive operation.
+type ConfirmRequest struct {
+	ConfirmationToken string `json:"confirmationToken" binding:"required"`
+	// ConfirmName must repeat the resource name returned with the token.
+	ConfirmName string `json:"confirmName" binding:"required"`
+}
+
+type confirmationPayload struct {
+	Action       string    `json:"action"`
+	ResourceID   string    `json:"resourceId"`
+	ResourceName string    `json:"resourceName"`
+	ActorID      string    `json:"actorId"`
+	ExpAt        time.Time `json:"expAt"`
+}
+
+// Confirmations issues and redeems the tokens guarding destructive operations (deleting a
+// business, a workspace, wiping data). The caller first asks for a token, then passes it back
+// with the resource name within auth.destructive_confirmation_ttl_seconds, so a script or a UI
+// bug cannot destroy data with a single request.
+type Confirmations struct {
+	cache *cache.Cache
+}
+
+// NewConfirmations creates a confirmation token store backed by the cache.
+func NewConfirmations(c *cache.Cache) *Confirmations {
+	return &Confirmations{cache: c}
+}
+
+// Issue creates a confirmation token for subject.
+func (c *Confirmations) Issue(subject ConfirmationSubject) (*Confirmation, error) {
+	token, err := id.RandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	ttl := viper.GetInt32(config.DestructiveConfirmationExpirySeconds)
+	payload := confirmationPayload{
+		Action:       subject.Action,
+		ResourceID:   subject.ResourceID,
+		ResourceName: subject.ResourceName,
+		ActorID:      subject.ActorID,
+		ExpAt:        time.Now().UTC().Add(time.Duration(ttl) * time.Second),
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.cache.SetX(confirmationTokenPrefix+token, data, ttl); err != nil {
+		return nil, err
+	}
+	return &Confirmation{
+		Token:        token,
+		Action:       subject.Action,
+		ResourceID:   subject.ResourceID,
+		ResourceName: subject.ResourceName,
+		ExpiresAt:    payload.ExpAt,
+	}, nil
+}
+
+// Redeem checks that req confirms subject and consumes the token. Missing input yields 428;
+// an unknown, expired or mismatched token, or a wrong resource name, yields 403. A token is
+// consumed even when the name does not match, so every attempt needs a fresh token.
+func (c *Confirmations) Redeem(subject ConfirmationSubject, req *ConfirmRequest) error {
+	if req == nil || strings.TrimSpace(req.ConfirmationToken) == "" || strings.TrimSpace(req.ConfirmName) == "" {
+		return ErrConfirmationRequired(subject.Action)
+	}
+	key := confirmationTokenPrefix + strings.TrimSpace(req.ConfirmationToken)
+	data, err := c.cache.Get(key)
+	if err != nil {
+		if cache.IsCacheMiss(err) {
+			return ErrConfirmationInvalid()
+		}
+		return err
+	}
+	if err := c.cache.Delete(key); err != nil && !cache.IsCacheMiss(err) {
+		return err
+	}
+	var payload confirmationPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return ErrConfirmationInvalid()
+	}
+	if payload.Action != subject.Action || payload.ResourceID != subject.ResourceID ||
+		payload.ActorID != subject.ActorID || time.Now().UTC().After(payload.ExpAt) {
+		return ErrConfirmationInvalid()
+	}
+	if subtle.ConstantTimeCompare([]byte(strings.TrimSpace(req.ConfirmName)), []byte(payload.ResourceName)) != 1 {
+		return ErrConfirmationNameMismatch()
+	}
+	return nil
+}
+
+func ErrConfirmationRequired(action string) error {
+	return problem.PreconditionRequired("this operation requires a confirmation token").With("action", action).WithCode("auth.confirmation_required")
+}
+
+func ErrConfirmationInvalid() error {
+	return problem.Forbidden("confirmation token is invalid or expired").WithCode("auth.confirmation_invalid")
+}
+
+func ErrConfirmationNameMismatch() error {
+	return problem.Forbidden("confirmName does not match the resource name").With("field", "confirmName").WithCode("auth.confirmation_name_mismatch")
+}
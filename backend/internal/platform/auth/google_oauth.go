@@ -21,6 +21,30 @@ type GoogleUserInfo struct {
 	Verified   bool   `json:"verified_email"`
 }
 
+// GoogleOAuthProvider runs the Google sign-in flow: building the consent URL and turning
+// the returned authorization code into the user's profile. The default provider talks to
+// Google; tests inject a fake so OAuth success paths can run without a real provider.
+type GoogleOAuthProvider interface {
+	AuthURL(ctx context.Context, state string) (string, error)
+	ExchangeAndFetchUser(ctx context.Context, code string) (*GoogleUserInfo, error)
+}
+
+type googleOAuthProvider struct{}
+
+// NewGoogleOAuthProvider returns the provider backed by Google's OAuth endpoints,
+// configured from the google OAuth config keys.
+func NewGoogleOAuthProvider() GoogleOAuthProvider {
+	return googleOAuthProvider{}
+}
+
+func (googleOAuthProvider) AuthURL(ctx context.Context, state string) (string, error) {
+	return GoogleGetAuthURL(ctx, state)
+}
+
+func (googleOAuthProvider) ExchangeAndFetchUser(ctx context.Context, code string) (*GoogleUserInfo, error) {
+	return GoogleExchangeAndFetchUser(ctx, code)
+}
+
 func googleConfig() *oauth2.Config {
 	clientID := viper.GetString(config.GoogleOAuthClientID)
 	secret := viper.GetString(config.GoogleOAuthClientSecret)
@@ -0,0 +1,288 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/safehttp"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+// OIDCClient is a workspace's registration with its OpenID Connect identity provider.
+type OIDCClient struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	// GroupsClaim names the ID token claim listing the user's groups, e.g. "groups".
+	GroupsClaim string
+}
+
+// OIDCIdentity is the user an identity provider signed in, read from a verified ID token.
+type OIDCIdentity struct {
+	Subject    string
+	Email      string
+	GivenName  string
+	FamilyName string
+	Name       string
+	Groups     []string
+}
+
+// OIDCProvider runs the OpenID Connect authorization code flow against a workspace's identity
+// provider. The default provider discovers the provider's endpoints from its issuer; tests
+// inject a fake so SSO success paths can run without a real identity provider.
+type OIDCProvider interface {
+	AuthURL(ctx context.Context, client OIDCClient, state, nonce string) (string, error)
+	// Exchange redeems code and returns the identity of the verified ID token, which must
+	// carry nonce.
+	Exchange(ctx context.Context, client OIDCClient, code, nonce string) (*OIDCIdentity, error)
+}
+
+type oidcProvider struct {
+	httpClient *http.Client
+}
+
+// NewOIDCProvider returns the provider that talks to identity providers over HTTP. Issuers
+// are configured by workspace admins, so discovery, token and key requests only reach public
+// addresses on the https port.
+func NewOIDCProvider() OIDCProvider {
+	return oidcProvider{httpClient: safehttp.NewClient(10*time.Second, "443")}
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func (p oidcProvider) AuthURL(ctx context.Context, client OIDCClient, state, nonce string) (string, error) {
+	disc, err := p.discover(ctx, client.Issuer)
+	if err != nil {
+		return "", err
+	}
+	return oidcConfig(client, disc).AuthCodeURL(state, oauth2.AccessTypeOnline, oauth2.SetAuthURLParam("nonce", nonce)), nil
+}
+
+func (p oidcProvider) Exchange(ctx context.Context, client OIDCClient, code, nonce string) (*OIDCIdentity, error) {
+	disc, err := p.discover(ctx, client.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	tok, err := oidcConfig(client, disc).Exchange(context.WithValue(ctx, oauth2.HTTPClient, p.httpClient), code)
+	if err != nil {
+		return nil, fmt.Errorf("code exchange failed: %w", err)
+	}
+	rawIDToken, _ := tok.Extra("id_token").(string)
+	if rawIDToken == "" {
+		return nil, fmt.Errorf("token response has no id_token")
+	}
+	keys, err := p.fetchKeys(ctx, disc.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		if key, ok := keys[kid]; ok {
+			return key, nil
+		}
+		// providers with a single key may leave kid out
+		if kid == "" && len(keys) == 1 {
+			for _, key := range keys {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("no signing key %q", kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(disc.Issuer),
+		jwt.WithAudience(client.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+	if got, _ := claims["nonce"].(string); got == "" || got != nonce {
+		return nil, fmt.Errorf("invalid id_token: nonce mismatch")
+	}
+	identity := &OIDCIdentity{
+		Subject:    claimString(claims, "sub"),
+		Email:      strings.ToLower(strings.TrimSpace(claimString(claims, "email"))),
+		GivenName:  claimString(claims, "given_name"),
+		FamilyName: claimString(claims, "family_name"),
+		Name:       claimString(claims, "name"),
+	}
+	if client.GroupsClaim != "" {
+		identity.Groups = claimStrings(claims, client.GroupsClaim)
+	}
+	if identity.Email == "" {
+		return nil, fmt.Errorf("email not available from provider")
+	}
+	// an address the provider says it has not verified proves nothing about who owns it;
+	// providers that leave the claim out only hand out verified addresses
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		if !v {
+			return nil, fmt.Errorf("email not verified by provider")
+		}
+	case string:
+		if strings.EqualFold(v, "false") {
+			return nil, fmt.Errorf("email not verified by provider")
+		}
+	}
+	return identity, nil
+}
+
+func oidcConfig(client OIDCClient, disc *oidcDiscovery) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     client.ClientID,
+		ClientSecret: client.ClientSecret,
+		RedirectURL:  client.RedirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  disc.AuthorizationEndpoint,
+			TokenURL: disc.TokenEndpoint,
+		},
+	}
+}
+
+// discover reads the provider metadata published at the issuer.
+func (p oidcProvider) discover(ctx context.Context, issuer string) (*oidcDiscovery, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+	var disc oidcDiscovery
+	if err := p.getJSON(ctx, issuer+"/.well-known/openid-configuration", &disc); err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %w", err)
+	}
+	if strings.TrimSuffix(disc.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc discovery failed: issuer %q does not match %q", disc.Issuer, issuer)
+	}
+	if disc.AuthorizationEndpoint == "" || disc.TokenEndpoint == "" || disc.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc discovery failed: incomplete provider metadata")
+	}
+	return &disc, nil
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetchKeys returns the provider's signing keys by key ID. Keys of unsupported types are skipped.
+func (p oidcProvider) fetchKeys(ctx context.Context, jwksURI string) (map[string]any, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.getJSON(ctx, jwksURI, &set); err != nil {
+		return nil, fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("provider publishes no usable signing key")
+	}
+	return keys, nil
+}
+
+func (k jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func (p oidcProvider) getJSON(ctx context.Context, url string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %d %s", url, resp.StatusCode, string(b))
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(target)
+}
+
+func claimString(claims jwt.MapClaims, name string) string {
+	v, _ := claims[name].(string)
+	return v
+}
+
+// claimStrings reads a claim holding a list of strings, or a single string.
+func claimStrings(claims jwt.MapClaims, name string) []string {
+	switch v := claims[name].(type) {
+	case string:
+		return []string{v}
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/safehttp"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIdentityProvider serves discovery, token and key endpoints, issuing ID tokens with claims.
+func fakeIdentityProvider(t *testing.T, claims func(issuer string) jwt.MapClaims) (*httptest.Server, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 srv.URL,
+			"authorization_endpoint": srv.URL + "/authorize",
+			"token_endpoint":         srv.URL + "/token",
+			"jwks_uri":               srv.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kid": "k1",
+			"kty": "RSA",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims(srv.URL))
+		tok.Header["kid"] = "k1"
+		idToken, err := tok.SignedString(key)
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "at", "token_type": "Bearer", "id_token": idToken})
+	})
+	return srv, key
+}
+
+func TestOIDCProvider_Exchange(t *testing.T) {
+	validClaims := func(issuer string) jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":        issuer,
+			"aud":        "kyora",
+			"sub":        "user-1",
+			"exp":        time.Now().Add(time.Hour).Unix(),
+			"nonce":      "n1",
+			"email":      "Jane@Acme.example.com",
+			"given_name": "Jane",
+			"groups":     []string{"staff", "warehouse"},
+		}
+	}
+	srv, _ := fakeIdentityProvider(t, validClaims)
+	client := OIDCClient{Issuer: srv.URL, ClientID: "kyora", ClientSecret: "s", RedirectURL: "https://app/cb", GroupsClaim: "groups"}
+	p := oidcProvider{httpClient: srv.Client()}
+
+	authURL, err := p.AuthURL(context.Background(), client, "st", "n1")
+	require.NoError(t, err)
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	require.Equal(t, "/authorize", u.Path)
+	require.Equal(t, "st", u.Query().Get("state"))
+	require.Equal(t, "n1", u.Query().Get("nonce"))
+
+	identity, err := p.Exchange(context.Background(), client, "code", "n1")
+	require.NoError(t, err)
+	require.Equal(t, "user-1", identity.Subject)
+	require.Equal(t, "jane@acme.example.com", identity.Email)
+	require.Equal(t, "Jane", identity.GivenName)
+	require.Equal(t, []string{"staff", "warehouse"}, identity.Groups)
+
+	_, err = p.Exchange(context.Background(), client, "code", "other-nonce")
+	require.ErrorContains(t, err, "nonce mismatch")
+
+	client.ClientID = "someone-else"
+	_, err = p.Exchange(context.Background(), client, "code", "n1")
+	require.ErrorContains(t, err, "invalid id_token")
+}
+
+func TestOIDCProvider_Exchange_RejectsExpiredToken(t *testing.T) {
+	srv, _ := fakeIdentityProvider(t, func(issuer string) jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   issuer,
+			"aud":   "kyora",
+			"exp":   time.Now().Add(-time.Hour).Unix(),
+			"nonce": "n1",
+			"email": "jane@acme.example.com",
+		}
+	})
+	client := OIDCClient{Issuer: srv.URL, ClientID: "kyora", ClientSecret: "s", RedirectURL: "https://app/cb"}
+	_, err := oidcProvider{httpClient: srv.Client()}.Exchange(context.Background(), client, "code", "n1")
+	require.ErrorContains(t, err, "invalid id_token")
+}
+
+func TestNewOIDCProvider_RefusesInternalIssuer(t *testing.T) {
+	srv, _ := fakeIdentityProvider(t, func(issuer string) jwt.MapClaims { return jwt.MapClaims{} })
+	client := OIDCClient{Issuer: srv.URL, ClientID: "kyora", ClientSecret: "s", RedirectURL: "https://app/cb"}
+	_, err := NewOIDCProvider().AuthURL(context.Background(), client, "st", "n1")
+	require.ErrorIs(t, err, safehttp.ErrDisallowedAddress)
+}
+
+func TestOIDCProvider_Exchange_RejectsUnverifiedEmail(t *testing.T) {
+	for _, verified := range []any{false, "false"} {
+		srv, _ := fakeIdentityProvider(t, func(issuer string) jwt.MapClaims {
+			return jwt.MapClaims{
+				"iss":            issuer,
+				"aud":            "kyora",
+				"exp":            time.Now().Add(time.Hour).Unix(),
+				"nonce":          "n1",
+				"email":          "jane@acme.example.com",
+				"email_verified": verified,
+			}
+		})
+		client := OIDCClient{Issuer: srv.URL, ClientID: "kyora", ClientSecret: "s", RedirectURL: "https://app/cb"}
+		_, err := oidcProvider{httpClient: srv.Client()}.Exchange(context.Background(), client, "code", "n1")
+		require.ErrorContains(t, err, "email not verified", verified)
+	}
+}
@@ -14,6 +14,13 @@ type Provider interface {
 	// PresignPut returns a URL (and required headers) that allows uploading the object directly.
 	PresignPut(ctx context.Context, in PresignPutInput) (*PresignPutOutput, error)
 
+	// Put uploads an object server-side, for content the backend produces or receives itself.
+	Put(ctx context.Context, key string, contentType string, body []byte) error
+
+	// Get downloads an object the backend needs to read itself (e.g. a logo to embed in a
+	// document). Missing objects return ErrBlobObjectNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+
 	// Head returns basic metadata for an existing object.
 	Head(ctx context.Context, key string) (*ObjectInfo, error)
 
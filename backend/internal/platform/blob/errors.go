@@ -9,3 +9,7 @@ func ErrProviderNotConfigured() error {
 func ErrBlobObjectNotFound(key string) error {
 	return problem.NotFound("object not found").With("key", key).WithCode("blob.object_not_found")
 }
+
+func ErrRegionNotConfigured(region string) error {
+	return problem.InternalError().With("region", region).WithCode("blob.region_not_configured")
+}
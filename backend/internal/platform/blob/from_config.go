@@ -1,16 +1,19 @@
 package blob
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/abdelrahman146/kyora/internal/platform/region"
 	"github.com/spf13/viper"
 )
 
 // FromConfig returns a blob Provider configured from Viper settings.
 //
 // If storage.provider is "local" (or empty), it returns (nil, nil) to indicate
-// no external blob storage is configured.
+// no external blob storage is configured. When extra regions are configured it returns a
+// Router over one bucket per region.
 func FromConfig() (Provider, error) {
 	storageProvider := strings.ToLower(strings.TrimSpace(viper.GetString(config.StorageProvider)))
 	if storageProvider == "" || storageProvider == "local" {
@@ -28,5 +31,30 @@ func FromConfig() (Provider, error) {
 	if err != nil {
 		return nil, err
 	}
-	return p, nil
+
+	// Extra data residency regions each get their own bucket (same credentials).
+	regions := region.Configured()
+	if len(regions) == 1 {
+		return p, nil
+	}
+	router := NewRouter(p)
+	for _, name := range regions[1:] {
+		bucket := region.Setting(name, "storage_bucket")
+		if bucket == "" {
+			return nil, fmt.Errorf("region %s: storage_bucket is not configured", name)
+		}
+		regional, err := NewS3CompatibleProvider(S3CompatibleConfig{
+			Bucket:          bucket,
+			Region:          region.Setting(name, "storage_region"),
+			Endpoint:        region.Setting(name, "storage_endpoint"),
+			AccessKeyID:     viper.GetString(config.StorageAccessKeyID),
+			SecretAccessKey: viper.GetString(config.StorageSecretAccessKey),
+			PublicBaseURL:   region.Setting(name, "storage_public_base_url"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("region %s: %w", name, err)
+		}
+		router.AddRegion(name, regional)
+	}
+	return router, nil
 }
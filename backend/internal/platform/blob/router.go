@@ -0,0 +1,131 @@
+package blob
+
+import (
+	"context"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/region"
+)
+
+// Router sends blob operations to the bucket of the data residency region the context
+// targets (see region.FromContext). Operations for a region without a bucket fail rather
+// than falling back to another region.
+type Router struct {
+	home      string
+	providers map[string]Provider
+}
+
+// NewRouter creates a router serving the home region with home.
+func NewRouter(home Provider) *Router {
+	name := region.Home()
+	return &Router{home: name, providers: map[string]Provider{name: home}}
+}
+
+// AddRegion registers p as the provider of the named region.
+func (r *Router) AddRegion(name string, p Provider) {
+	r.providers[region.Resolve(name)] = p
+}
+
+// For returns the provider of the region ctx targets.
+func (r *Router) For(ctx context.Context) Provider {
+	name := region.FromContext(ctx)
+	if p, ok := r.providers[name]; ok {
+		return p
+	}
+	return unavailableProvider{region: name}
+}
+
+// ForContext returns the provider of the region ctx targets when p is a Router, and p
+// otherwise. Use it for PublicURL, which has no context to route on.
+func ForContext(ctx context.Context, p Provider) Provider {
+	if r, ok := p.(*Router); ok {
+		return r.For(ctx)
+	}
+	return p
+}
+
+func (r *Router) PresignPut(ctx context.Context, in PresignPutInput) (*PresignPutOutput, error) {
+	return r.For(ctx).PresignPut(ctx, in)
+}
+
+func (r *Router) Put(ctx context.Context, key string, contentType string, body []byte) error {
+	return r.For(ctx).Put(ctx, key, contentType, body)
+}
+
+func (r *Router) Get(ctx context.Context, key string) ([]byte, error) {
+	return r.For(ctx).Get(ctx, key)
+}
+
+func (r *Router) Head(ctx context.Context, key string) (*ObjectInfo, error) {
+	return r.For(ctx).Head(ctx, key)
+}
+
+func (r *Router) Delete(ctx context.Context, key string) error {
+	return r.For(ctx).Delete(ctx, key)
+}
+
+// PublicURL returns the public URL of key in the home region; see ForContext.
+func (r *Router) PublicURL(key string) (string, bool) {
+	return r.providers[r.home].PublicURL(key)
+}
+
+func (r *Router) CreateMultipartUpload(ctx context.Context, key string, contentType string) (string, error) {
+	return r.For(ctx).CreateMultipartUpload(ctx, key, contentType)
+}
+
+func (r *Router) PresignMultipartPart(ctx context.Context, key string, uploadId string, partNumber int, expiresIn time.Duration) (string, error) {
+	return r.For(ctx).PresignMultipartPart(ctx, key, uploadId, partNumber, expiresIn)
+}
+
+func (r *Router) CompleteMultipartUpload(ctx context.Context, key string, uploadId string, parts []CompletedPart) error {
+	return r.For(ctx).CompleteMultipartUpload(ctx, key, uploadId, parts)
+}
+
+func (r *Router) AbortMultipartUpload(ctx context.Context, key string, uploadId string) error {
+	return r.For(ctx).AbortMultipartUpload(ctx, key, uploadId)
+}
+
+// unavailableProvider serves a region with no configured bucket.
+type unavailableProvider struct {
+	region string
+}
+
+func (p unavailableProvider) PresignPut(context.Context, PresignPutInput) (*PresignPutOutput, error) {
+	return nil, ErrRegionNotConfigured(p.region)
+}
+
+func (p unavailableProvider) Put(context.Context, string, string, []byte) error {
+	return ErrRegionNotConfigured(p.region)
+}
+
+func (p unavailableProvider) Get(context.Context, string) ([]byte, error) {
+	return nil, ErrRegionNotConfigured(p.region)
+}
+
+func (p unavailableProvider) Head(context.Context, string) (*ObjectInfo, error) {
+	return nil, ErrRegionNotConfigured(p.region)
+}
+
+func (p unavailableProvider) Delete(context.Context, string) error {
+	return ErrRegionNotConfigured(p.region)
+}
+
+func (p unavailableProvider) PublicURL(string) (string, bool) {
+	return "", false
+}
+
+func (p unavailableProvider) CreateMultipartUpload(context.Context, string, string) (string, error) {
+	return "", ErrRegionNotConfigured(p.region)
+}
+
+func (p unavailableProvider) PresignMultipartPart(context.Context, string, string, int, time.Duration) (string, error) {
+	return "", ErrRegionNotConfigured(p.region)
+}
+
+func (p unavailableProvider) CompleteMultipartUpload(context.Context, string, string, []CompletedPart) error {
+	return ErrRegionNotConfigured(p.region)
+}
+
+func (p unavailableProvider) AbortMultipartUpload(context.Context, string, string) error {
+	return ErrRegionNotConfigured(p.region)
+}
@@ -1,8 +1,10 @@
 package blob
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
 	"net/url"
 	"strings"
 	"time"
@@ -121,6 +123,40 @@ func (p *S3CompatibleProvider) PresignPut(ctx context.Context, in PresignPutInpu
 	}, nil
 }
 
+func (p *S3CompatibleProvider) Put(ctx context.Context, key string, contentType string, body []byte) error {
+	if p == nil || p.client == nil {
+		return ErrProviderNotConfigured()
+	}
+	_, err := p.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(p.bucket),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(body),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(int64(len(body))),
+	})
+	return err
+}
+
+func (p *S3CompatibleProvider) Get(ctx context.Context, key string) ([]byte, error) {
+	if p == nil || p.client == nil {
+		return nil, ErrProviderNotConfigured()
+	}
+	if strings.TrimSpace(key) == "" {
+		return nil, errors.New("blob s3: key is required")
+	}
+
+	out, err := p.client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(p.bucket), Key: aws.String(key)})
+	if err != nil {
+		var nsk *types.NoSuchKey
+		if errors.As(err, &nsk) {
+			return nil, ErrBlobObjectNotFound(key)
+		}
+		return nil, err
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
 func (p *S3CompatibleProvider) Head(ctx context.Context, key string) (*ObjectInfo, error) {
 	if p == nil || p.client == nil {
 		return nil, ErrProviderNotConfigured()
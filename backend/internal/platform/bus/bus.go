@@ -1,10 +1,14 @@
 package bus
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"runtime/debug"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 type Event struct {
@@ -12,15 +16,23 @@ type Event struct {
 	Payload any
 }
 
+// Handler processes one event payload. A returned error (or a panic) makes the bus
+// retry the payload with backoff and, once attempts are exhausted, dead-letter it.
+type Handler func(payload any) error
+
 type Bus struct {
-	mu     sync.RWMutex
-	topics map[Topic]map[uint64]chan any
-	emitCh chan Event
-	stop   chan struct{}
-	wg     sync.WaitGroup
-	nextID atomic.Uint64
-	subBuf int
-	closed atomic.Bool
+	mu       sync.RWMutex
+	topics   map[Topic]map[uint64]*subscription
+	emitCh   chan Event
+	stop     chan struct{}
+	wg       sync.WaitGroup
+	nextID   atomic.Uint64
+	subBuf   int
+	closed   atomic.Bool
+	deadSink atomic.Pointer[deadLetterSinkHolder]
+	// pending counts emitted events not yet dispatched plus payloads queued or being
+	// handled, so Drain can tell when the bus is idle.
+	pending atomic.Int64
 }
 
 const (
@@ -28,9 +40,17 @@ const (
 	defaultSubBuffer  = 128
 )
 
+type subscription struct {
+	topic   Topic
+	name    string
+	handler Handler
+	opts    HandlerOptions
+	queue   chan any
+}
+
 func New() *Bus {
 	b := &Bus{
-		topics: make(map[Topic]map[uint64]chan any),
+		topics: make(map[Topic]map[uint64]*subscription),
 		emitCh: make(chan Event, defaultEmitBuffer),
 		stop:   make(chan struct{}),
 		subBuf: defaultSubBuffer,
@@ -40,44 +60,71 @@ func New() *Bus {
 	return b
 }
 
-// Listen subscribes to a topic and handles payloads asynchronously.
+// Listen subscribes to a topic and handles payloads asynchronously on a single worker.
+// Panics are logged and the payload is dropped; use Handle for retries and dead-lettering.
 // It returns an unsubscribe function to stop receiving events.
 func (b *Bus) Listen(topic Topic, handler func(any)) (unsubscribe func()) {
 	if handler == nil {
 		return func() {}
 	}
+	return b.subscribe(topic, "", func(payload any) error {
+		handler(payload)
+		return nil
+	}, HandlerOptions{Workers: 1, QueueSize: b.subBuf, MaxAttempts: 1})
+}
+
+// Handle subscribes a named handler to a topic with its own worker pool and bounded queue.
+//
+// Failed payloads (error or panic) are retried with exponential backoff. Payloads that
+// still fail after MaxAttempts, or that cannot be queued within EnqueueTimeout because
+// the handler is falling behind, are passed to the dead-letter sink so they can be
+// inspected and replayed by name. The name must be unique per topic.
+// It returns an unsubscribe function to stop receiving events.
+func (b *Bus) Handle(topic Topic, name string, handler Handler, opts ...HandlerOption) (unsubscribe func()) {
+	if handler == nil {
+		return func() {}
+	}
+	o := defaultHandlerOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return b.subscribe(topic, name, handler, o.normalized())
+}
 
-	ch := make(chan any, b.subBuf)
+func (b *Bus) subscribe(topic Topic, name string, handler Handler, opts HandlerOptions) func() {
+	sub := &subscription{
+		topic:   topic,
+		name:    name,
+		handler: handler,
+		opts:    opts,
+		queue:   make(chan any, opts.QueueSize),
+	}
 	id := b.nextID.Add(1)
 
 	b.mu.Lock()
 	if b.topics[topic] == nil {
-		b.topics[topic] = make(map[uint64]chan any)
+		b.topics[topic] = make(map[uint64]*subscription)
 	}
-	b.topics[topic][id] = ch
+	b.topics[topic][id] = sub
 	b.mu.Unlock()
 
-	b.wg.Add(1)
-	go func() {
-		defer b.wg.Done()
-		for payload := range ch {
-			func() {
-				defer func() {
-					if r := recover(); r != nil {
-						slog.Error("bus handler panicked", "topic", topic, "panic", r, "stack", string(debug.Stack()))
-					}
-				}()
-				handler(payload)
-			}()
-		}
-	}()
+	for range opts.Workers {
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			for payload := range sub.queue {
+				b.process(sub, payload)
+				b.pending.Add(-1)
+			}
+		}()
+	}
 
 	return func() {
 		b.mu.Lock()
 		if subs, ok := b.topics[topic]; ok {
-			if subCh, ok := subs[id]; ok {
+			if s, ok := subs[id]; ok {
 				delete(subs, id)
-				close(subCh)
+				close(s.queue)
 			}
 			if len(subs) == 0 {
 				delete(b.topics, topic)
@@ -87,6 +134,71 @@ func (b *Bus) Listen(topic Topic, handler func(any)) (unsubscribe func()) {
 	}
 }
 
+// process runs the handler with retries and dead-letters the payload when it keeps failing.
+func (b *Bus) process(sub *subscription, payload any) {
+	var err error
+	attempt := 0
+	for attempt < sub.opts.MaxAttempts {
+		attempt++
+		if err = invoke(sub, payload); err == nil {
+			return
+		}
+		if attempt == sub.opts.MaxAttempts {
+			break
+		}
+		slog.Warn("bus handler failed, retrying", "topic", sub.topic, "handler", sub.name, "attempt", attempt, "error", err)
+		select {
+		case <-time.After(sub.opts.backoff(attempt)):
+		case <-b.stop:
+			// Shutting down: keep the payload rather than waiting out the backoff.
+			b.deadLetter(sub, payload, err, attempt)
+			return
+		}
+	}
+	if sub.name == "" {
+		// Listen subscriptions are fire-and-forget and cannot be replayed.
+		slog.Error("bus handler failed", "topic", sub.topic, "error", err)
+		return
+	}
+	b.deadLetter(sub, payload, err, attempt)
+}
+
+// invoke calls the handler once, converting a panic into an error.
+func invoke(sub *subscription, payload any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("bus handler panicked", "topic", sub.topic, "handler", sub.name, "panic", r, "stack", string(debug.Stack()))
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return sub.handler(payload)
+}
+
+// Replay runs the named handler of topic once, synchronously, on a payload decoded from
+// its JSON form. It is used to re-process dead letters after the cause was fixed.
+func (b *Bus) Replay(ctx context.Context, topic Topic, name string, payload []byte) error {
+	sub := b.findSubscription(topic, name)
+	if sub == nil {
+		return fmt.Errorf("%w: %s/%s", ErrHandlerNotFound, topic, name)
+	}
+	decoded, err := DecodePayload(ctx, topic, payload)
+	if err != nil {
+		return err
+	}
+	return invoke(sub, decoded)
+}
+
+func (b *Bus) findSubscription(topic Topic, name string) *subscription {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, sub := range b.topics[topic] {
+		if sub.name == name && name != "" {
+			return sub
+		}
+	}
+	return nil
+}
+
 // Emit publishes a payload to a topic asynchronously.
 func (b *Bus) Emit(topic Topic, payload any) {
 	if b.closed.Load() {
@@ -95,28 +207,45 @@ func (b *Bus) Emit(topic Topic, payload any) {
 	ev := Event{Topic: topic, Payload: payload}
 
 	// Reliable delivery: apply backpressure instead of dropping events.
+	b.pending.Add(1)
 	select {
 	case b.emitCh <- ev:
 	case <-b.stop:
+		b.pending.Add(-1)
 	}
 }
 
-// Close gracefully stops the bus and all subscriber goroutines.
+// Drain waits until every emitted event has been handled, including the events handlers
+// emit in turn, or until timeout passes. It reports whether the bus went idle.
+// Short-lived processes (commands) call it before Close so their events are not lost.
+func (b *Bus) Drain(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for b.pending.Load() > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return true
+}
+
+// Close gracefully stops the bus and all subscriber goroutines. Events still waiting to
+// be dispatched are dropped; call Drain first to deliver them.
 func (b *Bus) Close() {
 	if !b.closed.CompareAndSwap(false, true) {
 		return
 	}
 	close(b.stop)
 
-	// Close all subscriber channels
+	// Close all subscriber queues; workers drain what is already queued.
 	b.mu.Lock()
 	for _, subs := range b.topics {
-		for id, ch := range subs {
+		for id, sub := range subs {
 			delete(subs, id)
-			close(ch)
+			close(sub.queue)
 		}
 	}
-	b.topics = make(map[Topic]map[uint64]chan any)
+	b.topics = make(map[Topic]map[uint64]*subscription)
 	b.mu.Unlock()
 
 	b.wg.Wait()
@@ -129,26 +258,67 @@ func (b *Bus) dispatch() {
 		case <-b.stop:
 			return
 		case ev := <-b.emitCh:
-			// Snapshot subscriber channels to avoid holding lock during sends
+			// Snapshot subscribers to avoid holding lock during sends
 			b.mu.RLock()
-			var targets []chan any
+			var targets []*subscription
 			if subs, ok := b.topics[ev.Topic]; ok {
-				targets = make([]chan any, 0, len(subs))
-				for _, ch := range subs {
-					targets = append(targets, ch)
+				targets = make([]*subscription, 0, len(subs))
+				for _, sub := range subs {
+					targets = append(targets, sub)
 				}
 			}
 			b.mu.RUnlock()
 
-			for _, ch := range targets {
-				func(ch chan any) {
-					defer func() { _ = recover() }() // ignore sends to channels closed by unsubscribe races
-					select {
-					case ch <- ev.Payload:
-					case <-b.stop:
-					}
-				}(ch)
+			for _, sub := range targets {
+				b.pending.Add(1)
+				if !b.enqueue(sub, ev.Payload) {
+					b.pending.Add(-1)
+				}
 			}
+			b.pending.Add(-1)
 		}
 	}
 }
+
+// enqueue hands a payload to a subscription. Listen subscriptions apply backpressure;
+// Handle subscriptions wait up to EnqueueTimeout and then dead-letter the payload so a
+// slow handler cannot stall delivery to every other subscriber. It reports whether the
+// payload was queued.
+func (b *Bus) enqueue(sub *subscription, payload any) (queued bool) {
+	defer func() { _ = recover() }() // ignore sends to queues closed by unsubscribe races
+
+	if sub.opts.EnqueueTimeout <= 0 {
+		select {
+		case sub.queue <- payload:
+			return true
+		case <-b.stop:
+			return false
+		}
+	}
+
+	select {
+	case sub.queue <- payload:
+		return true
+	default:
+	}
+	timer := time.NewTimer(sub.opts.EnqueueTimeout)
+	defer timer.Stop()
+	select {
+	case sub.queue <- payload:
+		return true
+	case <-timer.C:
+		b.deadLetter(sub, payload, ErrQueueFull, 0)
+	case <-b.stop:
+	}
+	return false
+}
+
+var (
+	// ErrQueueFull is recorded on dead letters that were never handled because the
+	// handler's queue stayed full.
+	ErrQueueFull = errors.New("bus: handler queue full")
+	// ErrHandlerNotFound is returned by Replay when no handler with that name listens on the topic.
+	ErrHandlerNotFound = errors.New("bus: handler not found")
+	// ErrUnknownTopic is returned by DecodePayload for topics without a registered payload type.
+	ErrUnknownTopic = errors.New("bus: unknown topic")
+)
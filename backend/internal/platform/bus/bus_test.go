@@ -1,7 +1,10 @@
 package bus_test
 
 import (
+	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -53,3 +56,201 @@ func TestBus_DeliversAllEvents(t *testing.T) {
 	defer mu.Unlock()
 	require.Len(t, received, n)
 }
+
+func TestBus_DrainWaitsForCascadingEvents(t *testing.T) {
+	t.Parallel()
+
+	b := bus.New()
+	defer b.Close()
+
+	var handled atomic.Int32
+	b.Handle("test.first", "cascade", func(any) error {
+		time.Sleep(20 * time.Millisecond)
+		b.Emit("test.second", 1)
+		return nil
+	})
+	b.Handle("test.second", "record", func(any) error {
+		time.Sleep(20 * time.Millisecond)
+		handled.Add(1)
+		return nil
+	})
+
+	for i := range 3 {
+		b.Emit("test.first", i)
+	}
+
+	require.True(t, b.Drain(2*time.Second))
+	require.EqualValues(t, 3, handled.Load())
+}
+
+func TestBus_DrainTimesOut(t *testing.T) {
+	t.Parallel()
+
+	b := bus.New()
+	release := make(chan struct{})
+	b.Handle("test.stuck", "stuck", func(any) error {
+		<-release
+		return nil
+	})
+	b.Emit("test.stuck", 1)
+
+	require.False(t, b.Drain(50*time.Millisecond))
+
+	close(release)
+	require.True(t, b.Drain(2*time.Second))
+	b.Close()
+}
+
+type recordingSink struct {
+	mu      sync.Mutex
+	letters []bus.DeadLetter
+	stored  chan struct{}
+}
+
+func newRecordingSink() *recordingSink {
+	return &recordingSink{stored: make(chan struct{}, 16)}
+}
+
+func (s *recordingSink) StoreDeadLetter(_ context.Context, dl bus.DeadLetter) error {
+	s.mu.Lock()
+	s.letters = append(s.letters, dl)
+	s.mu.Unlock()
+	s.stored <- struct{}{}
+	return nil
+}
+
+func (s *recordingSink) wait(t *testing.T) bus.DeadLetter {
+	t.Helper()
+	select {
+	case <-s.stored:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for dead letter")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.letters[len(s.letters)-1]
+}
+
+func TestBus_HandleRetriesUntilSuccess(t *testing.T) {
+	t.Parallel()
+
+	b := bus.New()
+	defer b.Close()
+	sink := newRecordingSink()
+	b.SetDeadLetterSink(sink)
+
+	var calls atomic.Int32
+	done := make(chan struct{})
+	b.Handle("test.retry", "flaky", func(any) error {
+		if calls.Add(1) < 3 {
+			return errors.New("transient")
+		}
+		close(done)
+		return nil
+	}, bus.WithRetry(5, time.Millisecond, 5*time.Millisecond))
+
+	b.Emit("test.retry", 1)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler never succeeded")
+	}
+	require.EqualValues(t, 3, calls.Load())
+	require.Empty(t, sink.letters)
+}
+
+func TestBus_HandleDeadLettersFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		handler     bus.Handler
+		wantErr     string
+		wantAttempt int
+	}{
+		{
+			name:        "error",
+			handler:     func(any) error { return errors.New("boom") },
+			wantErr:     "boom",
+			wantAttempt: 3,
+		},
+		{
+			name:        "panic",
+			handler:     func(any) error { panic("kaboom") },
+			wantErr:     "handler panicked: kaboom",
+			wantAttempt: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := bus.New()
+			defer b.Close()
+			sink := newRecordingSink()
+			b.SetDeadLetterSink(sink)
+
+			b.Handle("test.fail", "broken", tt.handler, bus.WithRetry(3, time.Millisecond, time.Millisecond))
+			b.Emit("test.fail", "payload")
+
+			dl := sink.wait(t)
+			require.Equal(t, bus.Topic("test.fail"), dl.Topic)
+			require.Equal(t, "broken", dl.Handler)
+			require.Equal(t, "payload", dl.Payload)
+			require.Equal(t, tt.wantErr, dl.Error)
+			require.Equal(t, tt.wantAttempt, dl.Attempts)
+		})
+	}
+}
+
+func TestBus_HandleDeadLettersWhenQueueStaysFull(t *testing.T) {
+	t.Parallel()
+
+	b := bus.New()
+	sink := newRecordingSink()
+	b.SetDeadLetterSink(sink)
+
+	release := make(chan struct{})
+	b.Handle("test.slow", "slow", func(any) error {
+		<-release
+		return nil
+	}, bus.WithWorkers(1), bus.WithQueueSize(1), bus.WithEnqueueTimeout(10*time.Millisecond))
+
+	// One payload is being handled, one waits in the queue, the third overflows.
+	for i := range 3 {
+		b.Emit("test.slow", i)
+	}
+
+	dl := sink.wait(t)
+	require.Equal(t, bus.ErrQueueFull.Error(), dl.Error)
+	require.Equal(t, 0, dl.Attempts)
+
+	close(release)
+	b.Close()
+}
+
+func TestBus_ReplayDecodesPayload(t *testing.T) {
+	t.Parallel()
+
+	b := bus.New()
+	defer b.Close()
+
+	got := make(chan *bus.OrderPaidEvent, 1)
+	b.Handle(bus.OrderPaidTopic, "record", func(payload any) error {
+		got <- payload.(*bus.OrderPaidEvent)
+		return nil
+	})
+
+	ctx := context.Background()
+	require.NoError(t, b.Replay(ctx, bus.OrderPaidTopic, "record", []byte(`{"businessId":"bus_1","orderId":"ord_1","orderTotal":"12.5","currency":"USD"}`)))
+
+	ev := <-got
+	require.Equal(t, "ord_1", ev.OrderID)
+	require.Equal(t, "12.5", ev.OrderTotal.String())
+	require.Equal(t, ctx, ev.Ctx)
+
+	err := b.Replay(ctx, bus.OrderPaidTopic, "missing", []byte(`{}`))
+	require.ErrorIs(t, err, bus.ErrHandlerNotFound)
+}
@@ -0,0 +1,74 @@
+package bus
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/region"
+)
+
+// DeadLetter is a payload a named handler could not process.
+type DeadLetter struct {
+	Topic    Topic
+	Handler  string
+	Payload  any
+	Error    string
+	Attempts int
+	FailedAt time.Time
+	// Region is the data residency region of the event context, so replays run in it.
+	Region string
+}
+
+// DeadLetterSink persists dead letters so they can be inspected and replayed.
+type DeadLetterSink interface {
+	StoreDeadLetter(ctx context.Context, dl DeadLetter) error
+}
+
+type deadLetterSinkHolder struct {
+	sink DeadLetterSink
+}
+
+// SetDeadLetterSink sets where failed payloads go. Without a sink they are only logged.
+func (b *Bus) SetDeadLetterSink(sink DeadLetterSink) {
+	b.deadSink.Store(&deadLetterSinkHolder{sink: sink})
+}
+
+func (b *Bus) deadLetter(sub *subscription, payload any, err error, attempts int) {
+	dl := DeadLetter{
+		Topic:    sub.topic,
+		Handler:  sub.name,
+		Payload:  payload,
+		Attempts: attempts,
+		FailedAt: time.Now().UTC(),
+		Region:   region.FromContext(payloadContext(payload)),
+	}
+	if err != nil {
+		dl.Error = err.Error()
+	}
+	slog.Error("bus event dead-lettered", "topic", dl.Topic, "handler", dl.Handler, "attempts", dl.Attempts, "error", dl.Error)
+
+	holder := b.deadSink.Load()
+	if holder == nil || holder.sink == nil {
+		return
+	}
+	if storeErr := holder.sink.StoreDeadLetter(context.Background(), dl); storeErr != nil {
+		slog.Error("failed to store bus dead letter", "topic", dl.Topic, "handler", dl.Handler, "error", storeErr)
+	}
+}
+
+// payloadContext returns the Ctx field of an event payload, or a background context.
+func payloadContext(payload any) context.Context {
+	v := reflect.ValueOf(payload)
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return context.Background()
+	}
+	if ctx, ok := v.FieldByName("Ctx").Interface().(context.Context); ok && ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
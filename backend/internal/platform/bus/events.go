@@ -2,6 +2,9 @@ package bus
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -11,9 +14,51 @@ type Topic string
 
 const OnboardingPaymentSucceededTopic Topic = "onboarding_payment_succeeded"
 
-// OrderPaymentSucceededTopic is emitted when an order transitions to payment status "paid".
-// Consumers should treat it as best-effort and must be idempotent.
-const OrderPaymentSucceededTopic Topic = "order_payment_succeeded"
+// Order lifecycle topics are emitted by the order service once a status transition is persisted.
+// Consumers should treat them as best-effort and must be idempotent.
+const (
+	// OrderPaidTopic is emitted when an order transitions to payment status "paid".
+	OrderPaidTopic Topic = "order.paid"
+	// OrderFulfilledTopic is emitted when an order transitions to status "fulfilled".
+	OrderFulfilledTopic Topic = "order.fulfilled"
+	// OrderCancelledTopic is emitted when an order transitions to status "cancelled".
+	OrderCancelledTopic Topic = "order.cancelled"
+	// OrderReturnedTopic is emitted when an order transitions to status "returned".
+	OrderReturnedTopic Topic = "order.returned"
+	// OrderRefundedTopic is emitted when an order transitions to payment status "refunded".
+	OrderRefundedTopic Topic = "order.refunded"
+)
+
+// OrderImportedTopic is emitted when a historical order is imported from another store. The
+// order takes its final statuses directly, so none of the lifecycle topics above are emitted
+// for it; the ledger books what it recorded instead.
+const OrderImportedTopic Topic = "order.imported"
+
+// VariantMarginBelowThresholdTopic is emitted by the inventory service when a variant price
+// change drops its margin below the business' minimum margin.
+const VariantMarginBelowThresholdTopic Topic = "inventory.margin_below_threshold"
+
+// OrderExportRequestedTopic is emitted when a seller requests an order export; the export
+// file is written in the background.
+const OrderExportRequestedTopic Topic = "order.export_requested"
+
+// StoreImportRequestedTopic is emitted when a seller starts an import from a Shopify or
+// WooCommerce store; the import runs in the background.
+const StoreImportRequestedTopic Topic = "storeimport.requested"
+
+// OrderCheckoutCompletedTopic is emitted by the billing webhook when the customer pays an
+// order's Stripe payment link; the order service marks the order paid.
+const OrderCheckoutCompletedTopic Topic = "order.checkout_completed"
+
+// OrderStatusChangedTopic is emitted for every status change, including the cancellations
+// of the expiry job, with the order snapshot and the transition rules that ran, so
+// automations can react to any transition.
+const OrderStatusChangedTopic Topic = "order.status_changed"
+
+// AccountEmailChangedTopic is emitted when a user's login email changes, after the new
+// address confirmed it or the old address undid it; billing keeps the Stripe customer
+// email of the workspace owner in sync.
+const AccountEmailChangedTopic Topic = "account.email_changed"
 
 type OnboardingPaymentSucceededEvent struct {
 	Ctx                  context.Context `json:"-"`
@@ -22,14 +67,198 @@ type OnboardingPaymentSucceededEvent struct {
 	StripeSubscriptionID string          `json:"stripeSubscriptionId"`
 }
 
-// OrderPaymentSucceededEvent is emitted when an order becomes paid.
+// OrderPaidEvent is emitted when an order becomes paid.
 // It includes the minimal order snapshot needed for downstream automation without requiring additional DB reads.
-type OrderPaymentSucceededEvent struct {
+type OrderPaidEvent struct {
 	Ctx           context.Context `json:"-"`
 	BusinessID    string          `json:"businessId"`
 	OrderID       string          `json:"orderId"`
+	OrderNumber   string          `json:"orderNumber,omitempty"`
 	PaymentMethod string          `json:"paymentMethod"`
 	OrderTotal    decimal.Decimal `json:"orderTotal"`
 	Currency      string          `json:"currency"`
 	PaidAt        time.Time       `json:"paidAt"`
 }
+
+// OrderImportedEvent is emitted when a historical order is imported. FulfilledAt and PaidAt
+// are set when the order was fulfilled and paid (including refunded) in the source store.
+type OrderImportedEvent struct {
+	Ctx         context.Context `json:"-"`
+	BusinessID  string          `json:"businessId"`
+	OrderID     string          `json:"orderId"`
+	OrderNumber string          `json:"orderNumber,omitempty"`
+	OrderTotal  decimal.Decimal `json:"orderTotal"`
+	VAT         decimal.Decimal `json:"vat"`
+	COGS        decimal.Decimal `json:"cogs"`
+	Currency    string          `json:"currency"`
+	FulfilledAt *time.Time      `json:"fulfilledAt,omitempty"`
+	PaidAt      *time.Time      `json:"paidAt,omitempty"`
+}
+
+// OrderFulfilledEvent is emitted when an order is fulfilled.
+// VAT and COGS let accounting book the sale without reading the order back.
+type OrderFulfilledEvent struct {
+	Ctx           context.Context `json:"-"`
+	BusinessID    string          `json:"businessId"`
+	OrderID       string          `json:"orderId"`
+	OrderNumber   string          `json:"orderNumber,omitempty"`
+	PaymentStatus string          `json:"paymentStatus"`
+	OrderTotal    decimal.Decimal `json:"orderTotal"`
+	VAT           decimal.Decimal `json:"vat"`
+	COGS          decimal.Decimal `json:"cogs"`
+	Currency      string          `json:"currency"`
+	FulfilledAt   time.Time       `json:"fulfilledAt"`
+}
+
+// OrderCancelledEvent is emitted when an order is cancelled.
+// PaymentStatus lets consumers decide whether a refund or fee reversal is needed.
+type OrderCancelledEvent struct {
+	Ctx           context.Context `json:"-"`
+	BusinessID    string          `json:"businessId"`
+	OrderID       string          `json:"orderId"`
+	PaymentStatus string          `json:"paymentStatus"`
+	OrderTotal    decimal.Decimal `json:"orderTotal"`
+	Currency      string          `json:"currency"`
+	CancelledAt   time.Time       `json:"cancelledAt"`
+}
+
+// OrderReturnedEvent is emitted when an order is returned.
+// Restocked reports whether the returned items were put back in stock.
+type OrderReturnedEvent struct {
+	Ctx           context.Context `json:"-"`
+	BusinessID    string          `json:"businessId"`
+	OrderID       string          `json:"orderId"`
+	PaymentStatus string          `json:"paymentStatus"`
+	OrderTotal    decimal.Decimal `json:"orderTotal"`
+	COGS          decimal.Decimal `json:"cogs"`
+	Currency      string          `json:"currency"`
+	Restocked     bool            `json:"restocked"`
+	ReturnedAt    time.Time       `json:"returnedAt"`
+}
+
+// OrderRefundedEvent is emitted when an order's payment is refunded.
+type OrderRefundedEvent struct {
+	Ctx           context.Context `json:"-"`
+	BusinessID    string          `json:"businessId"`
+	OrderID       string          `json:"orderId"`
+	Status        string          `json:"status"`
+	PaymentMethod string          `json:"paymentMethod"`
+	OrderTotal    decimal.Decimal `json:"orderTotal"`
+	COGS          decimal.Decimal `json:"cogs"`
+	Currency      string          `json:"currency"`
+	RefundedAt    time.Time       `json:"refundedAt"`
+}
+
+// VariantMarginBelowThresholdEvent is emitted when a cost or sale price change pushes a
+// variant's gross margin below the business' MinMarginPercent.
+type VariantMarginBelowThresholdEvent struct {
+	Ctx              context.Context `json:"-"`
+	BusinessID       string          `json:"businessId"`
+	ProductID        string          `json:"productId"`
+	VariantID        string          `json:"variantId"`
+	VariantName      string          `json:"variantName"`
+	CostChangeID     string          `json:"costChangeId"`
+	CostPrice        decimal.Decimal `json:"costPrice"`
+	SalePrice        decimal.Decimal `json:"salePrice"`
+	Currency         string          `json:"currency"`
+	MarginPercent    decimal.Decimal `json:"marginPercent"`
+	ThresholdPercent decimal.Decimal `json:"thresholdPercent"`
+	ChangedAt        time.Time       `json:"changedAt"`
+}
+
+// OrderExportRequestedEvent is emitted when an order export is queued.
+type OrderExportRequestedEvent struct {
+	Ctx        context.Context `json:"-"`
+	BusinessID string          `json:"businessId"`
+	ExportID   string          `json:"exportId"`
+}
+
+// StoreImportRequestedEvent is emitted when a store import is queued.
+type StoreImportRequestedEvent struct {
+	Ctx        context.Context `json:"-"`
+	BusinessID string          `json:"businessId"`
+	ImportID   string          `json:"importId"`
+}
+
+// OrderCheckoutCompletedEvent is emitted when a Stripe Checkout session of an order is paid.
+type OrderCheckoutCompletedEvent struct {
+	Ctx               context.Context `json:"-"`
+	BusinessID        string          `json:"businessId"`
+	OrderID           string          `json:"orderId"`
+	CheckoutSessionID string          `json:"checkoutSessionId"`
+	PaymentIntentID   string          `json:"paymentIntentId"`
+}
+
+// OrderStatusChangedEvent is emitted when the business changes the status of an order.
+// AppliedActions lists the transition rule actions that ran; CustomerMessage is the
+// rendered text of a notify_customer rule, empty when there is none.
+type OrderStatusChangedEvent struct {
+	Ctx                    context.Context `json:"-"`
+	BusinessID             string          `json:"businessId"`
+	OrderID                string          `json:"orderId"`
+	OrderNumber            string          `json:"orderNumber,omitempty"`
+	CustomerID             string          `json:"customerId,omitempty"`
+	CustomerWhatsappNumber string          `json:"customerWhatsappNumber,omitempty"`
+	ActorID                string          `json:"actorId,omitempty"`
+	FromStatus             string          `json:"fromStatus"`
+	ToStatus               string          `json:"toStatus"`
+	PaymentStatus          string          `json:"paymentStatus"`
+	PaymentMethod          string          `json:"paymentMethod"`
+	OrderTotal             decimal.Decimal `json:"orderTotal"`
+	AmountPaid             decimal.Decimal `json:"amountPaid"`
+	Currency               string          `json:"currency"`
+	AppliedActions         []string        `json:"appliedActions,omitempty"`
+	CustomerMessage        string          `json:"customerMessage,omitempty"`
+	ChangedAt              time.Time       `json:"changedAt"`
+}
+
+// AccountEmailChangedEvent is emitted when a user's login email changes. IsOwner tells
+// whether the user owns the workspace.
+type AccountEmailChangedEvent struct {
+	Ctx         context.Context `json:"-"`
+	UserID      string          `json:"userId"`
+	WorkspaceID string          `json:"workspaceId"`
+	IsOwner     bool            `json:"isOwner"`
+	OldEmail    string          `json:"oldEmail"`
+	NewEmail    string          `json:"newEmail"`
+	ChangedAt   time.Time       `json:"changedAt"`
+}
+
+// payloadTypes maps each topic to the event type emitted on it, so dead-lettered
+// payloads stored as JSON can be decoded again for replay.
+var payloadTypes = map[Topic]func(ctx context.Context, data []byte) (any, error){
+	OnboardingPaymentSucceededTopic:  decodeEvent[OnboardingPaymentSucceededEvent],
+	OrderPaidTopic:                   decodeEvent[OrderPaidEvent],
+	OrderFulfilledTopic:              decodeEvent[OrderFulfilledEvent],
+	OrderCancelledTopic:              decodeEvent[OrderCancelledEvent],
+	OrderReturnedTopic:               decodeEvent[OrderReturnedEvent],
+	OrderRefundedTopic:               decodeEvent[OrderRefundedEvent],
+	OrderImportedTopic:               decodeEvent[OrderImportedEvent],
+	VariantMarginBelowThresholdTopic: decodeEvent[VariantMarginBelowThresholdEvent],
+	OrderExportRequestedTopic:        decodeEvent[OrderExportRequestedEvent],
+	StoreImportRequestedTopic:        decodeEvent[StoreImportRequestedEvent],
+	OrderCheckoutCompletedTopic:      decodeEvent[OrderCheckoutCompletedEvent],
+	OrderStatusChangedTopic:          decodeEvent[OrderStatusChangedEvent],
+	AccountEmailChangedTopic:         decodeEvent[AccountEmailChangedEvent],
+}
+
+// DecodePayload decodes a JSON payload emitted on topic into its event type, with its
+// Ctx field set to ctx.
+func DecodePayload(ctx context.Context, topic Topic, data []byte) (any, error) {
+	decode, ok := payloadTypes[topic]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTopic, topic)
+	}
+	return decode(ctx, data)
+}
+
+func decodeEvent[T any](ctx context.Context, data []byte) (any, error) {
+	ev := new(T)
+	if err := json.Unmarshal(data, ev); err != nil {
+		return nil, fmt.Errorf("decode %T: %w", ev, err)
+	}
+	if f := reflect.ValueOf(ev).Elem().FieldByName("Ctx"); ctx != nil && f.IsValid() && f.CanSet() {
+		f.Set(reflect.ValueOf(ctx))
+	}
+	return ev, nil
+}
@@ -0,0 +1,91 @@
+package bus
+
+import "time"
+
+// HandlerOptions controls how a Handle subscription processes events.
+type HandlerOptions struct {
+	// Workers is the number of goroutines processing the handler's queue concurrently.
+	Workers int
+	// QueueSize bounds how many payloads may wait for a worker.
+	QueueSize int
+	// EnqueueTimeout is how long dispatch waits for room in a full queue before
+	// dead-lettering the payload.
+	EnqueueTimeout time.Duration
+	// MaxAttempts is the number of times a payload is tried before it is dead-lettered.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry; it doubles per attempt up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// HandlerOption customizes HandlerOptions.
+type HandlerOption func(*HandlerOptions)
+
+const (
+	defaultHandlerWorkers   = 4
+	defaultHandlerQueueSize = 256
+	defaultEnqueueTimeout   = time.Second
+	defaultMaxAttempts      = 5
+	defaultInitialBackoff   = 200 * time.Millisecond
+	defaultMaxBackoff       = 10 * time.Second
+)
+
+func defaultHandlerOptions() HandlerOptions {
+	return HandlerOptions{
+		Workers:        defaultHandlerWorkers,
+		QueueSize:      defaultHandlerQueueSize,
+		EnqueueTimeout: defaultEnqueueTimeout,
+		MaxAttempts:    defaultMaxAttempts,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+	}
+}
+
+// WithWorkers sets the number of concurrent workers for a handler.
+func WithWorkers(n int) HandlerOption {
+	return func(o *HandlerOptions) { o.Workers = n }
+}
+
+// WithQueueSize sets the bounded queue size for a handler.
+func WithQueueSize(n int) HandlerOption {
+	return func(o *HandlerOptions) { o.QueueSize = n }
+}
+
+// WithEnqueueTimeout sets how long dispatch waits on a full queue before dead-lettering.
+func WithEnqueueTimeout(d time.Duration) HandlerOption {
+	return func(o *HandlerOptions) { o.EnqueueTimeout = d }
+}
+
+// WithRetry sets the attempt count and backoff bounds for a handler.
+func WithRetry(maxAttempts int, initialBackoff, maxBackoff time.Duration) HandlerOption {
+	return func(o *HandlerOptions) {
+		o.MaxAttempts = maxAttempts
+		o.InitialBackoff = initialBackoff
+		o.MaxBackoff = maxBackoff
+	}
+}
+
+func (o HandlerOptions) normalized() HandlerOptions {
+	if o.Workers <= 0 {
+		o.Workers = 1
+	}
+	if o.QueueSize < 0 {
+		o.QueueSize = 0
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 1
+	}
+	if o.MaxBackoff < o.InitialBackoff {
+		o.MaxBackoff = o.InitialBackoff
+	}
+	return o
+}
+
+// backoff returns the wait after the given failed attempt (1-based).
+func (o HandlerOptions) backoff(attempt int) time.Duration {
+	d := o.InitialBackoff
+	for i := 1; i < attempt && d < o.MaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, o.MaxBackoff)
+}
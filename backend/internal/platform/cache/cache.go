@@ -50,6 +50,33 @@ func (m *Cache) AddX(key string, value []byte, expiration int32) error {
 	return m.mc.Add(item)
 }
 
+// CASItem is a cached value together with the token needed to CompareAndSwapX it.
+type CASItem struct {
+	Value []byte
+	item  *memcache.Item
+}
+
+// GetCAS retrieves a value for a later CompareAndSwapX.
+func (m *Cache) GetCAS(key string) (*CASItem, error) {
+	item, err := m.mc.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return &CASItem{Value: item.Value, item: item}, nil
+}
+
+// CompareAndSwapX replaces a value read with GetCAS only if nobody else has written it since.
+//
+// It fails with an error matched by IsCASConflict when the value changed, or by IsNotStored
+// when it was deleted or expired in the meantime. Together with AddX this gives atomic
+// read-modify-write across API instances.
+func (m *Cache) CompareAndSwapX(current *CASItem, value []byte, expiration int32) error {
+	item := *current.item
+	item.Value = value
+	item.Expiration = expiration
+	return m.mc.CompareAndSwap(&item)
+}
+
 // Set sets a value in the cache without expiration.
 func (m *Cache) Set(key string, value []byte) error {
 	item := &memcache.Item{
@@ -98,3 +125,11 @@ func (m *Cache) Increment(key string, delta uint64) error {
 func IsNotStored(err error) bool {
 	return errors.Is(err, memcache.ErrNotStored)
 }
+
+func IsCacheMiss(err error) bool {
+	return errors.Is(err, memcache.ErrCacheMiss)
+}
+
+func IsCASConflict(err error) bool {
+	return errors.Is(err, memcache.ErrCASConflict)
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -30,14 +31,26 @@ const (
 	HTTPBaseURL       = "http.base_url"
 	HTTPTraceIDHeader = "http.trace_id_header"
 	HTTPMaxBodyBytes  = "http.max_body_bytes"
+	// http response compression & caching
+	HTTPCompressionEnabled     = "http.compression.enabled"
+	HTTPCompressionMinBytes    = "http.compression.min_bytes"
+	HTTPCacheControlCatalog    = "http.cache_control.catalog"
+	HTTPCacheControlAnalytics  = "http.cache_control.analytics"
+	HTTPCacheControlStorefront = "http.cache_control.storefront"
 	// CORS configuration
 	CORSAllowedOrigins = "cors.allowed_origins"
 	// database configuration
-	DatabaseDSN          = "database.dsn"
-	DatabaseMaxOpenConns = "database.max_open_conns"
-	DatabaseMaxIdleConns = "database.max_idle_conns"
-	DatabaseMaxIdleTime  = "database.max_idle_time"
-	DatabaseLogLevel     = "database.log_level"
+	DatabaseDSN              = "database.dsn"
+	DatabaseMaxOpenConns     = "database.max_open_conns"
+	DatabaseMaxIdleConns     = "database.max_idle_conns"
+	DatabaseMaxIdleTime      = "database.max_idle_time"
+	DatabaseConnMaxLifetime  = "database.conn_max_lifetime"
+	DatabaseStatementTimeout = "database.statement_timeout" // server-side per-statement limit (0 disables)
+	DatabaseQueryTimeout     = "database.query_timeout"     // client-side context deadline per query (0 disables)
+	DatabaseLogLevel         = "database.log_level"
+	// data residency regions
+	RegionHome    = "region.home"    // name of the region served by database.dsn and storage.* (default: "default")
+	RegionRegions = "region.regions" // extra regions keyed by name: database_dsn, storage_bucket, storage_region, storage_endpoint, storage_public_base_url
 	// cache configuration
 	CacheHosts = "cache.hosts"
 	// jwt configuration
@@ -53,10 +66,23 @@ const (
 	VerifyEmailTokenExpirySeconds = "auth.verify_email_ttl_seconds"
 	// Workspace invitation configuration
 	WorkspaceInvitationTokenExpirySeconds = "auth.invitation_token_ttl_seconds"
+	// Magic link sign-in configuration
+	MagicLinkTokenExpirySeconds = "auth.magic_link_ttl_seconds"
+	// Email change configuration; the undo link sent to the old address outlives the confirmation link
+	EmailChangeTokenExpirySeconds     = "auth.email_change_ttl_seconds"
+	EmailChangeUndoTokenExpirySeconds = "auth.email_change_undo_ttl_seconds"
+	// Destructive operation confirmation configuration
+	DestructiveConfirmationExpirySeconds = "auth.destructive_confirmation_ttl_seconds"
 	// Google OAuth configuration
 	GoogleOAuthClientID     = "auth.google_oauth.client_id"
 	GoogleOAuthClientSecret = "auth.google_oauth.client_secret"
 	GoogleOAuthRedirectURL  = "auth.google_oauth.redirect_url"
+	// Enterprise SSO configuration; identity providers send users back to the redirect URL
+	SSORedirectURL        = "auth.sso.redirect_url"
+	SSOStateExpirySeconds = "auth.sso.state_ttl_seconds"
+	SSOBlockedDomains     = "auth.sso.blocked_domains" // []string - email domains no workspace may claim, on top of the built-in public mail providers
+	// operator admin API token; admin endpoints reject every request when empty
+	AdminAPIToken = "auth.admin_api_token"
 	// stripe configuration
 	StripeAPIKey        = "billing.stripe.api_key"
 	StripeWebhookSecret = "billing.stripe.webhook_secret"
@@ -95,6 +121,54 @@ const (
 
 	// inventory configuration
 	InventoryMaxPhotosPerProduct = "inventory.max_photos_per_product" // max photos per product/variant (default: 10)
+	InventorySearchNameWeight    = "inventory.search_name_weight"     // weight of name relevance in /inventory/search ranking (default: 1)
+	InventorySearchSKUWeight     = "inventory.search_sku_weight"      // weight of SKU/barcode similarity in /inventory/search ranking (default: 1)
+	InventorySearchMinScore      = "inventory.search_min_score"       // weighted score below which /inventory/search drops a match (default: 0.1)
+
+	// customer configuration
+	CustomerDuplicateNameSimilarity = "customer.duplicate_name_similarity" // name similarity (0-1) from which a new customer is flagged as a possible duplicate (default: 0.6)
+
+	// storefront configuration
+	StorefrontBaseURL = "storefront.base_url" // public storefront URL; review request emails link to <base_url>/<storefrontPublicId>/products/<productId> when set
+	// storefront customer login
+	StorefrontCustomerLoginTTLSeconds    = "storefront.customer_login_ttl_seconds"    // how long a login code / magic link stays valid (default: 900)
+	StorefrontCustomerTokenExpirySeconds = "storefront.customer_token_expiry_seconds" // lifetime of customer tokens (default: 7 days)
+
+	// WhatsApp messaging configuration
+	WhatsappProvider              = "whatsapp.provider"                // values: mock, cloud; empty disables WhatsApp messages
+	WhatsappCloudBaseURL          = "whatsapp.cloud.base_url"          // optional, defaults to https://graph.facebook.com/v20.0
+	WhatsappCloudAccessToken      = "whatsapp.cloud.access_token"      // system user access token
+	WhatsappCloudPhoneNumberID    = "whatsapp.cloud.phone_number_id"   // ID of the sending phone number
+	WhatsappCloudAuthTemplate     = "whatsapp.cloud.auth_template"     // approved authentication template for login codes
+	WhatsappCloudTemplateLanguage = "whatsapp.cloud.template_language" // language code of the template (default: en)
+
+	// receipt OCR configuration
+	OCRProvider     = "ocr.provider"      // values: mock, http; empty disables extraction
+	OCRHTTPEndpoint = "ocr.http.endpoint" // URL the http provider posts receipts to
+	OCRHTTPAPIKey   = "ocr.http.api_key"  // bearer token sent to the http provider
+
+	// foreign exchange rates configuration
+	FXProvider    = "fx.provider"      // values: mock, http; empty disables automatic rates (a manual rate is then required)
+	FXHTTPBaseURL = "fx.http.base_url" // optional, defaults to https://api.frankfurter.app
+	FXHTTPAPIKey  = "fx.http.api_key"  // optional bearer token sent to the http provider
+
+	// shipping carrier configuration; a carrier is offered once its credentials are set
+	ShippingMockCarrier      = "shipping.mock_carrier"             // registers a fake "mock" carrier (tests, local development)
+	AramexUsername           = "shipping.aramex.username"          // Aramex API user
+	AramexPassword           = "shipping.aramex.password"          // Aramex API password
+	AramexAccountNumber      = "shipping.aramex.account_number"    // shipper account number
+	AramexAccountPin         = "shipping.aramex.account_pin"       // shipper account PIN
+	AramexAccountEntity      = "shipping.aramex.account_entity"    // account entity code (e.g. DXB)
+	AramexAccountCountryCode = "shipping.aramex.account_country"   // account country code (e.g. AE)
+	AramexAPIBaseURL         = "shipping.aramex.api_base_url"      // default: https://ws.aramex.net/ShippingAPI.V2
+	DHLExpressAPIKey         = "shipping.dhl_express.api_key"      // MyDHL API key
+	DHLExpressAPISecret      = "shipping.dhl_express.api_secret"   // MyDHL API secret
+	DHLExpressAccountNumber  = "shipping.dhl_express.account"      // shipper account number
+	DHLExpressAPIBaseURL     = "shipping.dhl_express.api_base_url" // default: https://express.api.dhl.com/mydhlapi
+
+	// accounting configuration
+	AccountingExpenseInboxDomain = "accounting.expense_inbox_domain" // domain of the per-business expenses+<token>@<domain> receipt inboxes
+	AccountingExpenseInboxSecret = "accounting.expense_inbox_secret" // shared secret the inbound email webhook must send in X-Inbox-Secret
 )
 
 var configured bool
@@ -112,8 +186,21 @@ func Configure() {
 
 	// Defaults
 	viper.SetDefault(HTTPMaxBodyBytes, int64(1024*1024)) // 1 MiB default max request body
+	viper.SetDefault(HTTPCompressionEnabled, true)
+	viper.SetDefault(HTTPCompressionMinBytes, 1024) // bodies smaller than 1 KiB are sent as-is
+	// Business-scoped responses are per-user: allow caching but always revalidate via ETag.
+	viper.SetDefault(HTTPCacheControlCatalog, "private, no-cache")
+	viper.SetDefault(HTTPCacheControlAnalytics, "private, max-age=60")
+	viper.SetDefault(HTTPCacheControlStorefront, "public, max-age=60")
 	viper.SetDefault(BillingAutoSyncPlans, true)
 	viper.SetDefault(DatabaseAutoMigrate, true)
+	viper.SetDefault(DatabaseMaxOpenConns, 25)
+	viper.SetDefault(DatabaseMaxIdleConns, 10)
+	viper.SetDefault(DatabaseMaxIdleTime, 5*time.Minute)
+	viper.SetDefault(DatabaseConnMaxLifetime, 30*time.Minute)
+	viper.SetDefault(DatabaseStatementTimeout, 30*time.Second)
+	viper.SetDefault(DatabaseQueryTimeout, 30*time.Second)
+	viper.SetDefault(RegionHome, "default")
 	viper.SetDefault(StorageProvider, "local")
 	viper.SetDefault(StorageLocalPath, "./tmp/assets")
 	viper.SetDefault(StorageMultipartPartSize, 10)        // 10 MB per part
@@ -121,11 +208,28 @@ func Configure() {
 	viper.SetDefault(ThumbnailsMaxDimension, 512)         // 512px max thumbnail dimension
 	viper.SetDefault(ThumbnailsQuality, 80)               // 80% JPEG quality
 	viper.SetDefault(InventoryMaxPhotosPerProduct, 10)
+	viper.SetDefault(InventorySearchNameWeight, 1.0)
+	viper.SetDefault(InventorySearchSKUWeight, 1.0)
+	viper.SetDefault(InventorySearchMinScore, 0.1)
+	viper.SetDefault(CustomerDuplicateNameSimilarity, 0.6)
 	// CORS defaults - allow all origins in development
 	viper.SetDefault(CORSAllowedOrigins, []string{"*"})
 	// Auth defaults
 	// Refresh tokens are long-lived and rotated; keep configurable.
 	viper.SetDefault(RefreshTokenExpirySeconds, int64(30*24*60*60)) // 30 days
+	// Confirmation tokens for destructive operations are meant to be used right away.
+	viper.SetDefault(DestructiveConfirmationExpirySeconds, 5*60) // 5 minutes
+	// Magic links sign users in, so they only work shortly after they are sent.
+	viper.SetDefault(MagicLinkTokenExpirySeconds, 15*60) // 15 minutes
+	// Email changes are confirmed from the new inbox within the hour; the old address can undo
+	// them for a week, long enough to notice a takeover.
+	viper.SetDefault(EmailChangeTokenExpirySeconds, 60*60)          // 1 hour
+	viper.SetDefault(EmailChangeUndoTokenExpirySeconds, 7*24*60*60) // 7 days
+	// SSO sign-ins must come back from the identity provider shortly after they start.
+	viper.SetDefault(SSOStateExpirySeconds, 10*60) // 10 minutes
+	// Storefront customers sign in with short-lived codes and stay signed in for a week.
+	viper.SetDefault(StorefrontCustomerLoginTTLSeconds, 15*60)                // 15 minutes
+	viper.SetDefault(StorefrontCustomerTokenExpirySeconds, int64(7*24*60*60)) // 7 days
 
 	// Add current directory first
 	viper.AddConfigPath(".")
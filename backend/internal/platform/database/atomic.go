@@ -10,11 +10,13 @@ import (
 )
 
 type AtomicProcess struct {
-	tx *gorm.DB
+	db *Database
 }
 
+// NewAtomicProcess runs transactions on db, in the region the context targets (always the
+// home region for a Global view).
 func NewAtomicProcess(db *Database) *AtomicProcess {
-	return &AtomicProcess{db.GetDB()}
+	return &AtomicProcess{db: db}
 }
 
 func (u *AtomicProcess) Exec(ctx context.Context, cb func(ctx context.Context) error, opts ...atomic.AtomicProcessOption) error {
@@ -23,9 +25,16 @@ func (u *AtomicProcess) Exec(ctx context.Context, cb func(ctx context.Context) e
 	// This is critical for workflows that perform multiple domain operations in
 	// a single atomic unit (e.g. onboarding), and lets the outer transaction own
 	// retries/isolation settings.
-	if _, ok := ctx.Value(TxKey).(*gorm.DB); ok {
+	// A transaction in another region cannot be joined; a new one is started in the target
+	// region and commits independently.
+	target := u.db.targetRegion(ctx)
+	if _, ok := ctx.Value(TxKey).(*gorm.DB); ok && u.db.txRegion(ctx) == target {
 		return cb(ctx)
 	}
+	conn, err := u.db.regionConn(target)
+	if err != nil {
+		return err
+	}
 
 	options := &atomic.AtomicProcessOptions{
 		Isolation: atomic.LevelDefault,
@@ -41,11 +50,11 @@ func (u *AtomicProcess) Exec(ctx context.Context, cb func(ctx context.Context) e
 
 	var lastErr error
 	for i := 0; i < options.Retries; i++ {
-		err := u.tx.Transaction(func(tx *gorm.DB) error {
+		err := conn.Transaction(func(tx *gorm.DB) error {
 			if err := u.setupTransaction(tx, options); err != nil {
 				return err
 			}
-			return cb(context.WithValue(ctx, TxKey, tx))
+			return cb(withTx(ctx, tx, target))
 		})
 		if err == nil {
 			return nil
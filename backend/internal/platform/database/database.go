@@ -6,9 +6,11 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/abdelrahman146/kyora/internal/platform/region"
 	"github.com/abdelrahman146/kyora/internal/platform/types/ctxkey"
 	"github.com/spf13/viper"
 	"gorm.io/driver/postgres"
@@ -17,6 +19,10 @@ import (
 
 type Database struct {
 	db *gorm.DB
+	// regions holds the connection of every data residency region, shared by all views.
+	regions *regionConns
+	// global views always use the home region (see Global).
+	global bool
 }
 
 var TxKey = ctxkey.New("transaction")
@@ -26,6 +32,7 @@ func NewConnection(dsn string, logLevel string) (*Database, error) {
 	var db *gorm.DB
 	var err error
 	logger := NewSlogGormLogger(logLevel)
+	dsn = withStatementTimeout(dsn, viper.GetDuration(config.DatabaseStatementTimeout))
 	for attempts := 1; attempts <= maxAttempts; attempts++ {
 		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
 			Logger: logger,
@@ -50,32 +57,74 @@ func NewConnection(dsn string, logLevel string) (*Database, error) {
 	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
 		slog.Warn("Failed to ensure pg_trgm extension", "error", err)
 	}
+	ensureSearchNormalizeFunction(db)
 	maxOpenConns := viper.GetInt(config.DatabaseMaxOpenConns)
 	maxIdleConns := viper.GetInt(config.DatabaseMaxIdleConns)
 	maxIdleTime := viper.GetDuration(config.DatabaseMaxIdleTime)
+	maxLifetime := viper.GetDuration(config.DatabaseConnMaxLifetime)
 	sqlDB.SetMaxOpenConns(maxOpenConns)
 	sqlDB.SetMaxIdleConns(maxIdleConns)
 	sqlDB.SetConnMaxIdleTime(maxIdleTime)
-	return &Database{db: db}, nil
+	sqlDB.SetConnMaxLifetime(maxLifetime)
+	if err := registerQueryTimeout(db, viper.GetDuration(config.DatabaseQueryTimeout)); err != nil {
+		return nil, fmt.Errorf("register query timeout: %w", err)
+	}
+	home := region.Home()
+	return &Database{db: db, regions: &regionConns{home: home, conns: map[string]*gorm.DB{home: db}}}, nil
+}
+
+// withStatementTimeout sets the Postgres statement_timeout runtime parameter on the DSN,
+// so the server aborts runaway statements even if the client stops waiting. It supports
+// both URL and key=value DSNs and leaves an explicitly configured statement_timeout alone.
+func withStatementTimeout(dsn string, timeout time.Duration) string {
+	if timeout <= 0 || strings.Contains(dsn, "statement_timeout") {
+		return dsn
+	}
+	ms := timeout.Milliseconds()
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		return fmt.Sprintf("%s%sstatement_timeout=%d", dsn, sep, ms)
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s statement_timeout=%d", dsn, ms))
 }
 
 func (d *Database) GetDB() *gorm.DB {
 	return d.db
 }
 
+// CloseConnection closes the connections of every region d serves.
 func (d *Database) CloseConnection() error {
-	sqlDB, err := d.db.DB()
-	if err != nil {
-		return err
+	for _, conn := range d.Conns() {
+		sqlDB, err := conn.DB()
+		if err != nil {
+			return err
+		}
+		if err := sqlDB.Close(); err != nil {
+			return err
+		}
 	}
-	return sqlDB.Close()
+	return nil
 }
 
+// Conn returns the connection for ctx: the transaction carried by ctx when it runs in the
+// region ctx targets, otherwise the region's connection. A region without a configured
+// connection yields a connection whose every query fails with ErrRegionUnavailable, so data
+// never silently lands in another region.
 func (d *Database) Conn(ctx context.Context) *gorm.DB {
-	if tx, ok := ctx.Value(TxKey).(*gorm.DB); ok {
+	target := d.targetRegion(ctx)
+	if tx, ok := ctx.Value(TxKey).(*gorm.DB); ok && d.txRegion(ctx) == target {
 		return tx
 	}
-	return d.db.WithContext(ctx)
+	conn, err := d.regionConn(target)
+	if err != nil {
+		failed := d.db.Session(&gorm.Session{NewDB: true, Context: ctx})
+		_ = failed.AddError(err)
+		return failed
+	}
+	return conn.WithContext(ctx)
 }
 
 func (d *Database) ApplyOptions(db *gorm.DB, opts ...DatabaseOption) *gorm.DB {
@@ -85,6 +134,24 @@ func (d *Database) ApplyOptions(db *gorm.DB, opts ...DatabaseOption) *gorm.DB {
 	return db
 }
 
+// AutoMigrate migrates models in every region d serves. Foreign keys to partitioned
+// tables, which Postgres cannot declare, are enforced by triggers (see ForeignKeyTrigger).
 func (d *Database) AutoMigrate(models ...interface{}) error {
-	return d.db.AutoMigrate(models...)
+	for _, conn := range d.Conns() {
+		for _, model := range models {
+			keys, err := partitionedReferences(conn, model)
+			if err != nil {
+				return err
+			}
+			if len(keys) > 0 {
+				err = migrateReferencingPartitioned(conn, model, keys)
+			} else {
+				err = conn.AutoMigrate(model)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithStatementTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		dsn     string
+		timeout time.Duration
+		want    string
+	}{
+		{
+			name:    "url without query",
+			dsn:     "postgres://u:p@localhost:5432/kyora",
+			timeout: 30 * time.Second,
+			want:    "postgres://u:p@localhost:5432/kyora?statement_timeout=30000",
+		},
+		{
+			name:    "url with query",
+			dsn:     "postgresql://u:p@localhost/kyora?sslmode=disable",
+			timeout: 1500 * time.Millisecond,
+			want:    "postgresql://u:p@localhost/kyora?sslmode=disable&statement_timeout=1500",
+		},
+		{
+			name:    "key value",
+			dsn:     "host=localhost user=u dbname=kyora sslmode=disable",
+			timeout: 5 * time.Second,
+			want:    "host=localhost user=u dbname=kyora sslmode=disable statement_timeout=5000",
+		},
+		{
+			name:    "disabled",
+			dsn:     "host=localhost",
+			timeout: 0,
+			want:    "host=localhost",
+		},
+		{
+			name:    "explicit value kept",
+			dsn:     "postgres://localhost/kyora?statement_timeout=100",
+			timeout: 30 * time.Second,
+			want:    "postgres://localhost/kyora?statement_timeout=100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, withStatementTimeout(tt.dsn, tt.timeout))
+		})
+	}
+}
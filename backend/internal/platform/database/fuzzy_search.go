@@ -0,0 +1,175 @@
+package database
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// searchNormalizeFunc folds case, Latin accents, Arabic diacritics/tatweel and Arabic letter
+// variants (أ إ آ ٱ → ا, ى → ي, ة → ه) so "Jose" matches "José" and "احمد" matches "أَحمد".
+// It wraps unaccent in an IMMUTABLE function so it can be used in index expressions.
+const searchNormalizeFunc = "search_normalize"
+
+const searchNormalizeFuncSQL = "CREATE OR REPLACE FUNCTION search_normalize(input text) RETURNS text\n" +
+	"LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT AS $func$\n" +
+	"  SELECT translate(\n" +
+	"    regexp_replace(lower(public.unaccent('public.unaccent'::regdictionary, input)), '[\u064B-\u065F\u0670\u0640]', '', 'g'),\n" +
+	"    '\u0623\u0625\u0622\u0671\u0649\u0629',\n" +
+	"    '\u0627\u0627\u0627\u0627\u064A\u0647'\n" +
+	"  )\n" +
+	"$func$;"
+
+// ensureSearchNormalizeFunction creates the unaccent extension and the search_normalize function.
+// Failures are logged, matching how pg_trgm is ensured.
+func ensureSearchNormalizeFunction(db *gorm.DB) {
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS unaccent").Error; err != nil {
+		slog.Warn("Failed to ensure unaccent extension", "error", err)
+		return
+	}
+	if err := db.Exec(searchNormalizeFuncSQL).Error; err != nil {
+		slog.Warn("Failed to ensure search_normalize function", "error", err)
+	}
+}
+
+// EnsureNormalizedTrigramIndex ensures a GIN trigram index on search_normalize(column), which
+// serves the typo-tolerant conditions built by FuzzyMatch.
+func EnsureNormalizedTrigramIndex(db *gorm.DB, indexName, table, column string) {
+	if err := validateIdent(indexName); err != nil {
+		slog.Error("trgm: invalid index identifier", "index", indexName, "error", err)
+		return
+	}
+	if err := validateIdent(table); err != nil {
+		slog.Error("trgm: invalid table identifier", "table", table, "error", err)
+		return
+	}
+	if err := validateIdent(column); err != nil {
+		slog.Error("trgm: invalid column identifier", "column", column, "error", err)
+		return
+	}
+
+	sql := fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s USING gin (%s(%s) gin_trgm_ops);`, quoteIdent(indexName), quoteIdent(table), searchNormalizeFunc, quoteIdent(column))
+	if err := db.Exec(sql).Error; err != nil {
+		slog.Error("trgm: failed to ensure normalized trigram index", "index", indexName, "table", table, "column", column, "error", err)
+	}
+}
+
+// FuzzyMatch returns a condition (with its vars) matching term against any of the text
+// columns after normalization, either as a substring or by trigram word similarity, so
+// accents, Arabic spelling variants and small typos still match. The condition is meant
+// to be OR-ed with the full-text conditions of a search scope.
+func FuzzyMatch(term string, columns ...string) (string, []any, error) {
+	if len(columns) == 0 {
+		return "FALSE", nil, nil
+	}
+	conds := make([]string, 0, len(columns))
+	vars := make([]any, 0, len(columns)*2)
+	for _, col := range columns {
+		if err := validateQualifiedIdent(col); err != nil {
+			return "", nil, err
+		}
+		norm := fmt.Sprintf("%s(%s)", searchNormalizeFunc, col)
+		conds = append(conds, fmt.Sprintf("%s LIKE '%%' || %s(?) || '%%' OR %s(?) <%% %s", norm, searchNormalizeFunc, searchNormalizeFunc, norm))
+		vars = append(vars, term, term)
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", vars, nil
+}
+
+// SearchRankOrder returns an ORDER BY expression combining full-text rank over vectorColumns
+// with the best trigram word similarity over textColumns, so exact matches rank first and
+// typo matches still come back ordered by closeness.
+func SearchRankOrder(term string, vectorColumns []string, textColumns []string) (clause.Expr, error) {
+	rank, err := SearchRank(term, vectorColumns, textColumns)
+	if err != nil {
+		return clause.Expr{}, err
+	}
+	rank.SQL += " DESC"
+	return rank, nil
+}
+
+// SearchRank returns the relevance score SearchRankOrder sorts by, for selecting it as a
+// column (e.g. to merge results of several tables).
+func SearchRank(term string, vectorColumns []string, textColumns []string) (clause.Expr, error) {
+	for _, col := range append(append([]string{}, vectorColumns...), textColumns...) {
+		if err := validateQualifiedIdent(col); err != nil {
+			return clause.Expr{}, err
+		}
+	}
+	exprs := make([]string, 0, len(vectorColumns)+1)
+	vars := make([]any, 0, len(vectorColumns)+len(textColumns))
+	for _, col := range vectorColumns {
+		exprs = append(exprs, fmt.Sprintf("ts_rank_cd(%s, websearch_to_tsquery('simple', ?))", col))
+		vars = append(vars, term)
+	}
+	if len(textColumns) > 0 {
+		sims := make([]string, 0, len(textColumns))
+		for _, col := range textColumns {
+			sims = append(sims, fmt.Sprintf("COALESCE(word_similarity(%s(?), %s(%s)), 0)", searchNormalizeFunc, searchNormalizeFunc, col))
+			vars = append(vars, term)
+		}
+		if len(sims) == 1 {
+			exprs = append(exprs, sims[0])
+		} else {
+			exprs = append(exprs, "GREATEST("+strings.Join(sims, ", ")+")")
+		}
+	}
+
+	return clause.Expr{SQL: joinPlus(exprs), Vars: vars}, nil
+}
+
+// TrigramMatch returns a condition (with its vars) matching term against any of the columns
+// as a case-insensitive substring or by trigram word similarity, without normalization. It
+// suits codes such as SKUs, where a partial or mistyped code should still match, and can use
+// the plain trigram indexes of EnsureTrigramGinIndex.
+func TrigramMatch(term string, columns ...string) (string, []any, error) {
+	if len(columns) == 0 {
+		return "FALSE", nil, nil
+	}
+	conds := make([]string, 0, len(columns))
+	vars := make([]any, 0, len(columns)*2)
+	for _, col := range columns {
+		if err := validateQualifiedIdent(col); err != nil {
+			return "", nil, err
+		}
+		conds = append(conds, fmt.Sprintf("%s ILIKE '%%' || ? || '%%' OR ? <%% %s", col, col))
+		vars = append(vars, term, term)
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", vars, nil
+}
+
+// TrigramSimilarity returns the best trigram word similarity (0 to 1) of term to the columns,
+// without normalization, to rank the matches of TrigramMatch.
+func TrigramSimilarity(term string, columns ...string) (clause.Expr, error) {
+	if len(columns) == 0 {
+		return clause.Expr{SQL: "0"}, nil
+	}
+	sims := make([]string, 0, len(columns))
+	vars := make([]any, 0, len(columns))
+	for _, col := range columns {
+		if err := validateQualifiedIdent(col); err != nil {
+			return clause.Expr{}, err
+		}
+		sims = append(sims, fmt.Sprintf("COALESCE(word_similarity(?, %s), 0)", col))
+		vars = append(vars, term)
+	}
+	if len(sims) == 1 {
+		return clause.Expr{SQL: sims[0], Vars: vars}, nil
+	}
+	return clause.Expr{SQL: "GREATEST(" + strings.Join(sims, ", ") + ")", Vars: vars}, nil
+}
+
+// NormalizedSimilarity returns the trigram similarity (0 to 1) of term to the whole of column,
+// both normalized like FuzzyMatch. Unlike word similarity it compares full values, which
+// suits telling whether two names are the same name spelled differently.
+func NormalizedSimilarity(term, column string) (clause.Expr, error) {
+	if err := validateQualifiedIdent(column); err != nil {
+		return clause.Expr{}, err
+	}
+	return clause.Expr{
+		SQL:  fmt.Sprintf("COALESCE(similarity(%s(?), %s(%s)), 0)", searchNormalizeFunc, searchNormalizeFunc, column),
+		Vars: []any{term},
+	}, nil
+}
@@ -0,0 +1,85 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	t.Parallel()
+
+	sql, vars, err := database.FuzzyMatch("jose", "customers.name")
+	require.NoError(t, err)
+	require.Equal(t, `(search_normalize(customers.name) LIKE '%' || search_normalize(?) || '%' OR search_normalize(?) <% search_normalize(customers.name))`, sql)
+	require.Equal(t, []any{"jose", "jose"}, vars)
+
+	_, _, err = database.FuzzyMatch("jose", "customers.name; DROP TABLE customers")
+	require.Error(t, err)
+}
+
+func TestSearchRankOrder(t *testing.T) {
+	t.Parallel()
+
+	expr, err := database.SearchRankOrder("jose", []string{"customers.search_vector"}, []string{"customers.name", "customers.email"})
+	require.NoError(t, err)
+	require.Equal(t, "ts_rank_cd(customers.search_vector, websearch_to_tsquery('simple', ?)) + "+
+		"GREATEST(COALESCE(word_similarity(search_normalize(?), search_normalize(customers.name)), 0), "+
+		"COALESCE(word_similarity(search_normalize(?), search_normalize(customers.email)), 0)) DESC", expr.SQL)
+	require.Len(t, expr.Vars, 3)
+}
+
+func TestSearchRank(t *testing.T) {
+	t.Parallel()
+
+	expr, err := database.SearchRank("jose", nil, []string{"expenses.note"})
+	require.NoError(t, err)
+	require.Equal(t, "COALESCE(word_similarity(search_normalize(?), search_normalize(expenses.note)), 0)", expr.SQL)
+	require.Equal(t, []any{"jose"}, expr.Vars)
+
+	_, err = database.SearchRank("jose", []string{"orders.search_vector; --"}, nil)
+	require.Error(t, err)
+}
+
+func TestTrigramMatch(t *testing.T) {
+	t.Parallel()
+
+	sql, vars, err := database.TrigramMatch("LS-M", "variants.sku")
+	require.NoError(t, err)
+	require.Equal(t, `(variants.sku ILIKE '%' || ? || '%' OR ? <% variants.sku)`, sql)
+	require.Equal(t, []any{"LS-M", "LS-M"}, vars)
+
+	sql, vars, err = database.TrigramMatch("LS-M")
+	require.NoError(t, err)
+	require.Equal(t, "FALSE", sql)
+	require.Empty(t, vars)
+
+	_, _, err = database.TrigramMatch("LS-M", "variants.sku OR 1=1")
+	require.Error(t, err)
+}
+
+func TestTrigramSimilarity(t *testing.T) {
+	t.Parallel()
+
+	expr, err := database.TrigramSimilarity("LS-M", "variants.sku", "variants.barcode")
+	require.NoError(t, err)
+	require.Equal(t, "GREATEST(COALESCE(word_similarity(?, variants.sku), 0), COALESCE(word_similarity(?, variants.barcode), 0))", expr.SQL)
+	require.Equal(t, []any{"LS-M", "LS-M"}, expr.Vars)
+
+	expr, err = database.TrigramSimilarity("LS-M")
+	require.NoError(t, err)
+	require.Equal(t, "0", expr.SQL)
+}
+
+func TestNormalizedSimilarity(t *testing.T) {
+	t.Parallel()
+
+	expr, err := database.NormalizedSimilarity("Ahmad Ali", "customers.name")
+	require.NoError(t, err)
+	require.Equal(t, "COALESCE(similarity(search_normalize(?), search_normalize(customers.name)), 0)", expr.SQL)
+	require.Equal(t, []any{"Ahmad Ali"}, expr.Vars)
+
+	_, err = database.NormalizedSimilarity("x", "customers.name)")
+	require.Error(t, err)
+}
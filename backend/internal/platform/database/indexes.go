@@ -0,0 +1,148 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/platform/region"
+)
+
+// Index declares a secondary index a model relies on.
+//
+// Declarations live next to the storage that owns the table so query patterns and
+// their supporting indexes are reviewed together. GORM struct tags remain the place
+// for single-column and unique indexes; use Index for composite, GIN, or trigram
+// indexes that tags cannot express, or to make an existing index verifiable.
+type Index struct {
+	Name    string
+	Table   string
+	Columns []string
+	// Using is the index method ("btree" when empty, "gin", ...).
+	Using string
+	// OpClass is an optional operator class applied to every column (e.g. "gin_trgm_ops").
+	OpClass string
+}
+
+func (i Index) validate() error {
+	if err := validateIdent(i.Name); err != nil {
+		return err
+	}
+	if err := validateIdent(i.Table); err != nil {
+		return err
+	}
+	if len(i.Columns) == 0 {
+		return fmt.Errorf("index %q has no columns", i.Name)
+	}
+	for _, col := range i.Columns {
+		if err := validateIdent(col); err != nil {
+			return err
+		}
+	}
+	if i.Using != "" {
+		if err := validateIdent(i.Using); err != nil {
+			return err
+		}
+	}
+	if i.OpClass != "" {
+		if err := validateIdent(i.OpClass); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateStatement renders an idempotent CREATE INDEX statement for the declaration.
+func (i Index) CreateStatement() (string, error) {
+	if err := i.validate(); err != nil {
+		return "", err
+	}
+	cols := make([]string, len(i.Columns))
+	for idx, col := range i.Columns {
+		cols[idx] = quoteIdent(col)
+		if i.OpClass != "" {
+			cols[idx] += " " + i.OpClass
+		}
+	}
+	using := "btree"
+	if i.Using != "" {
+		using = i.Using
+	}
+	return fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s USING %s (%s);`,
+		quoteIdent(i.Name), quoteIdent(i.Table), using, strings.Join(cols, ", ")), nil
+}
+
+// EnsureIndexes makes sure the declared indexes exist in every region db serves.
+//
+// When auto-migration is enabled the indexes are created if missing. Otherwise they are
+// only verified and missing ones are logged, so operators can create them with the
+// db-indexes command during a maintenance window. Failures are logged, never fatal,
+// matching the other startup schema helpers.
+func EnsureIndexes(db *Database, indexes ...Index) {
+	for _, name := range db.Regions() {
+		ctx := region.WithRegion(context.Background(), name)
+		if !shouldAutoMigrate() {
+			missing, err := MissingIndexes(ctx, db, indexes...)
+			if err != nil {
+				slog.Error("indexes: failed to verify declared indexes", "error", err, "region", name)
+				continue
+			}
+			for _, idx := range missing {
+				slog.Warn("indexes: declared index is missing", "index", idx.Name, "table", idx.Table, "region", name)
+			}
+			continue
+		}
+		if err := CreateIndexes(ctx, db, indexes...); err != nil {
+			slog.Error("indexes: failed to ensure declared indexes", "error", err, "region", name)
+		}
+	}
+}
+
+// CreateIndexes creates the declared indexes that do not exist yet.
+func CreateIndexes(ctx context.Context, db *Database, indexes ...Index) error {
+	for _, idx := range indexes {
+		sql, err := idx.CreateStatement()
+		if err != nil {
+			return err
+		}
+		if err := db.Conn(ctx).Exec(sql).Error; err != nil {
+			return fmt.Errorf("create index %s: %w", idx.Name, err)
+		}
+	}
+	return nil
+}
+
+// MissingIndexes returns the declared indexes that are not present in the current schema.
+func MissingIndexes(ctx context.Context, db *Database, indexes ...Index) ([]Index, error) {
+	if len(indexes) == 0 {
+		return nil, nil
+	}
+	names := make([]string, 0, len(indexes))
+	for _, idx := range indexes {
+		if err := idx.validate(); err != nil {
+			return nil, err
+		}
+		names = append(names, idx.Name)
+	}
+
+	var existing []string
+	err := db.Conn(ctx).
+		Raw("SELECT indexname FROM pg_indexes WHERE schemaname = current_schema() AND indexname IN ?", names).
+		Scan(&existing).Error
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]struct{}, len(existing))
+	for _, name := range existing {
+		found[name] = struct{}{}
+	}
+
+	var missing []Index
+	for _, idx := range indexes {
+		if _, ok := found[idx.Name]; !ok {
+			missing = append(missing, idx)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,63 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexCreateStatement(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		index   database.Index
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "composite btree",
+			index: database.Index{Name: "idx_orders_business_id_ordered_at", Table: "orders", Columns: []string{"business_id", "ordered_at"}},
+			want:  `CREATE INDEX IF NOT EXISTS "idx_orders_business_id_ordered_at" ON "orders" USING btree ("business_id", "ordered_at");`,
+		},
+		{
+			name:  "gin",
+			index: database.Index{Name: "customers_search_vector_gin_idx", Table: "customers", Columns: []string{"search_vector"}, Using: "gin"},
+			want:  `CREATE INDEX IF NOT EXISTS "customers_search_vector_gin_idx" ON "customers" USING gin ("search_vector");`,
+		},
+		{
+			name:  "trigram opclass",
+			index: database.Index{Name: "customers_name_trgm_idx", Table: "customers", Columns: []string{"name"}, Using: "gin", OpClass: "gin_trgm_ops"},
+			want:  `CREATE INDEX IF NOT EXISTS "customers_name_trgm_idx" ON "customers" USING gin ("name" gin_trgm_ops);`,
+		},
+		{
+			name:    "no columns",
+			index:   database.Index{Name: "idx_empty", Table: "orders"},
+			wantErr: true,
+		},
+		{
+			name:    "injection in column",
+			index:   database.Index{Name: "idx_bad", Table: "orders", Columns: []string{"id); DROP TABLE orders; --"}},
+			wantErr: true,
+		},
+		{
+			name:    "injection in method",
+			index:   database.Index{Name: "idx_bad", Table: "orders", Columns: []string{"id"}, Using: "gin (id); --"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.index.CreateStatement()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,484 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Partitioning declares that a table is hash-partitioned on a column.
+//
+// Declarations live next to the storage that owns the table, like Index. Tables start out
+// unpartitioned; the db-partitions command converts them during a maintenance window.
+// Queries that filter on the partition column only touch one partition, so the largest
+// tenants do not slow down everyone else's queries.
+type Partitioning struct {
+	Table  string
+	Column string
+	// Partitions is the number of hash partitions (the modulus).
+	Partitions int
+}
+
+func (p Partitioning) validate() error {
+	if err := validateIdent(p.Table); err != nil {
+		return err
+	}
+	if err := validateIdent(p.Column); err != nil {
+		return err
+	}
+	if p.Partitions < 2 {
+		return fmt.Errorf("partitioning of %q needs at least 2 partitions", p.Table)
+	}
+	return nil
+}
+
+// UnpartitionedTable is the name the existing table is renamed to while its rows are
+// copied into the partitioned one.
+func (p Partitioning) UnpartitionedTable() string {
+	return p.Table + "_unpartitioned"
+}
+
+// CreateStatements renders the statements creating the partitioned table and its
+// partitions, shaped like the existing table renamed to UnpartitionedTable.
+func (p Partitioning) CreateStatements() ([]string, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	stmts := make([]string, 0, p.Partitions+1)
+	stmts = append(stmts, fmt.Sprintf(
+		`CREATE TABLE %s (LIKE %s INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING GENERATED) PARTITION BY HASH (%s);`,
+		quoteIdent(p.Table), quoteIdent(p.UnpartitionedTable()), quoteIdent(p.Column)))
+	for i := 0; i < p.Partitions; i++ {
+		stmts = append(stmts, fmt.Sprintf(`CREATE TABLE %s PARTITION OF %s FOR VALUES WITH (MODULUS %d, REMAINDER %d);`,
+			quoteIdent(fmt.Sprintf("%s_p%d", p.Table, i)), quoteIdent(p.Table), p.Partitions, i))
+	}
+	return stmts, nil
+}
+
+// UnpartitionedTables returns the declared partitionings whose table exists but is not
+// partitioned yet in the current schema.
+func UnpartitionedTables(ctx context.Context, db *Database, partitionings ...Partitioning) ([]Partitioning, error) {
+	partitioned, err := partitionedTables(db.Conn(ctx))
+	if err != nil {
+		return nil, err
+	}
+	var pending []Partitioning
+	for _, p := range partitionings {
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := partitioned[p.Table]; !ok {
+			pending = append(pending, p)
+		}
+	}
+	return pending, nil
+}
+
+// PartitionTable converts an existing table into a hash-partitioned one in a single
+// transaction: rows are copied into the partitions, and the primary key, indexes, triggers
+// and foreign keys of the table are recreated on the partitioned table. The primary key is
+// extended with the partition column, as Postgres requires. Foreign keys of other tables
+// referencing this one can no longer target the primary key, so they are replaced by
+// triggers enforcing the same reference and delete action (see ForeignKeyTrigger).
+//
+// The table stays locked while its rows are copied; run it during a maintenance window.
+// Converting an already partitioned table is a no-op.
+func PartitionTable(ctx context.Context, db *Database, p Partitioning) error {
+	stmts, err := p.CreateStatements()
+	if err != nil {
+		return err
+	}
+	return db.Conn(ctx).Transaction(func(tx *gorm.DB) error {
+		partitioned, err := partitionedTables(tx)
+		if err != nil {
+			return err
+		}
+		if _, ok := partitioned[p.Table]; ok {
+			return nil
+		}
+
+		var primaryKey []string
+		if err := tx.Raw(`SELECT a.attname FROM pg_index x
+			JOIN pg_attribute a ON a.attrelid = x.indrelid AND a.attnum = ANY(x.indkey)
+			WHERE x.indrelid = ?::regclass AND x.indisprimary
+			ORDER BY array_position(x.indkey, a.attnum)`, p.Table).Scan(&primaryKey).Error; err != nil {
+			return err
+		}
+		if !slices.Contains(primaryKey, p.Column) {
+			primaryKey = append(primaryKey, p.Column)
+		}
+		var indexDefs []string
+		if err := tx.Raw(`SELECT pg_get_indexdef(x.indexrelid) FROM pg_index x
+			WHERE x.indrelid = ?::regclass AND NOT x.indisprimary`, p.Table).Scan(&indexDefs).Error; err != nil {
+			return err
+		}
+		var triggerDefs []string
+		if err := tx.Raw(`SELECT pg_get_triggerdef(oid) FROM pg_trigger
+			WHERE tgrelid = ?::regclass AND NOT tgisinternal`, p.Table).Scan(&triggerDefs).Error; err != nil {
+			return err
+		}
+		type constraintDef struct {
+			Name string
+			Def  string
+		}
+		var foreignKeys []constraintDef
+		if err := tx.Raw(`SELECT conname AS name, pg_get_constraintdef(oid) AS def FROM pg_constraint
+			WHERE conrelid = ?::regclass AND confrelid <> conrelid AND contype = 'f'`, p.Table).Scan(&foreignKeys).Error; err != nil {
+			return err
+		}
+		var referencing []referencingKey
+		if err := tx.Raw(`SELECT c.conname AS name, (SELECT relname FROM pg_class WHERE oid = c.conrelid) AS referencing, c.confdeltype AS on_delete,
+			(SELECT string_agg(a.attname, ',' ORDER BY k.i) FROM unnest(c.conkey) WITH ORDINALITY k(n, i)
+				JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.n) AS columns,
+			(SELECT string_agg(a.attname, ',' ORDER BY k.i) FROM unnest(c.confkey) WITH ORDINALITY k(n, i)
+				JOIN pg_attribute a ON a.attrelid = c.confrelid AND a.attnum = k.n) AS references
+			FROM pg_constraint c WHERE c.confrelid = ?::regclass AND c.contype = 'f'`, p.Table).Scan(&referencing).Error; err != nil {
+			return err
+		}
+		keyTriggers := make([]ForeignKeyTrigger, 0, len(referencing))
+		for _, fk := range referencing {
+			keyTriggers = append(keyTriggers, fk.trigger(p.Table))
+		}
+		var columns []string
+		if err := tx.Raw(`SELECT column_name FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = ? AND is_generated = 'NEVER'
+			ORDER BY ordinal_position`, p.Table).Scan(&columns).Error; err != nil {
+			return err
+		}
+		if len(columns) == 0 {
+			return fmt.Errorf("partition %s: table not found", p.Table)
+		}
+
+		for _, fk := range referencing {
+			if err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT %s;`, quoteIdent(fk.Referencing), quoteIdent(fk.Name))).Error; err != nil {
+				return fmt.Errorf("partition %s: drop foreign key %s: %w", p.Table, fk.Name, err)
+			}
+		}
+		if err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s RENAME TO %s;`, quoteIdent(p.Table), quoteIdent(p.UnpartitionedTable()))).Error; err != nil {
+			return err
+		}
+		for _, stmt := range stmts {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return fmt.Errorf("partition %s: %w", p.Table, err)
+			}
+		}
+		cols := quoteIdents(columns)
+		if err := tx.Exec(fmt.Sprintf(`INSERT INTO %s (%s) SELECT %s FROM %s;`,
+			quoteIdent(p.Table), cols, cols, quoteIdent(p.UnpartitionedTable()))).Error; err != nil {
+			return fmt.Errorf("partition %s: copy rows: %w", p.Table, err)
+		}
+		if err := tx.Exec(fmt.Sprintf(`DROP TABLE %s;`, quoteIdent(p.UnpartitionedTable()))).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s ADD PRIMARY KEY (%s);`, quoteIdent(p.Table), quoteIdents(primaryKey))).Error; err != nil {
+			return fmt.Errorf("partition %s: primary key: %w", p.Table, err)
+		}
+		for _, def := range indexDefs {
+			if err := tx.Exec(def).Error; err != nil {
+				return fmt.Errorf("partition %s: recreate index: %w", p.Table, err)
+			}
+		}
+		for _, fk := range foreignKeys {
+			if err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s ADD CONSTRAINT %s %s;`, quoteIdent(p.Table), quoteIdent(fk.Name), fk.Def)).Error; err != nil {
+				return fmt.Errorf("partition %s: recreate foreign key %s: %w", p.Table, fk.Name, err)
+			}
+		}
+		for _, def := range triggerDefs {
+			if err := tx.Exec(def).Error; err != nil {
+				return fmt.Errorf("partition %s: recreate trigger: %w", p.Table, err)
+			}
+		}
+		for _, fk := range keyTriggers {
+			if err := fk.create(tx); err != nil {
+				return fmt.Errorf("partition %s: %w", p.Table, err)
+			}
+		}
+		return nil
+	})
+}
+
+// referencingKey is a foreign key of another table referencing a table being partitioned,
+// as read from pg_constraint.
+type referencingKey struct {
+	Name        string
+	Referencing string
+	OnDelete    string
+	Columns     string
+	References  string
+}
+
+// deleteActions maps pg_constraint.confdeltype to the action it stands for.
+var deleteActions = map[string]string{"c": "CASCADE", "n": "SET NULL", "d": "SET DEFAULT", "r": "RESTRICT", "a": "NO ACTION"}
+
+func (k referencingKey) trigger(table string) ForeignKeyTrigger {
+	return ForeignKeyTrigger{
+		Name:       k.Name,
+		Table:      k.Referencing,
+		Columns:    strings.Split(k.Columns, ","),
+		RefTable:   table,
+		RefColumns: strings.Split(k.References, ","),
+		OnDelete:   deleteActions[k.OnDelete],
+	}
+}
+
+// ForeignKeyTrigger enforces a foreign key referencing a partitioned table with triggers.
+// Postgres only accepts foreign keys targeting the whole primary key of a partitioned table,
+// which includes the partition column, so references by id alone are checked by a trigger
+// on the referencing table instead, and deletes of referenced rows by a trigger on the
+// partitioned table, which applies OnDelete. Like a MATCH SIMPLE foreign key, rows with a
+// null column are not checked. Postgres moves a row whose partition column changes as a
+// delete and an insert, so a referenced row that still exists after the statement counts
+// as kept.
+type ForeignKeyTrigger struct {
+	// Name is the name of the foreign key; the functions and triggers are named after it.
+	Name       string
+	Table      string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	// OnDelete is CASCADE, SET NULL or SET DEFAULT; anything else restricts deletes.
+	OnDelete string
+}
+
+// maxTriggerBaseLen keeps the function and trigger names within the 63 bytes of a Postgres
+// identifier once suffixed.
+const maxTriggerBaseLen = 56
+
+func (fk ForeignKeyTrigger) validate() error {
+	for _, ident := range append([]string{fk.Name, fk.Table, fk.RefTable}, append(fk.Columns, fk.RefColumns...)...) {
+		if err := validateIdent(ident); err != nil {
+			return err
+		}
+	}
+	if len(fk.Columns) == 0 || len(fk.Columns) != len(fk.RefColumns) {
+		return fmt.Errorf("foreign key %q: columns do not match the referenced columns", fk.Name)
+	}
+	return nil
+}
+
+// Statements renders the functions and triggers enforcing the foreign key. They replace
+// earlier versions of themselves, so running them again is safe.
+func (fk ForeignKeyTrigger) Statements() ([]string, error) {
+	if err := fk.validate(); err != nil {
+		return nil, err
+	}
+	base := fk.Name
+	if len(base) > maxTriggerBaseLen {
+		base = base[:maxTriggerBaseLen]
+	}
+	check, ref := base+"_check", base+"_ref"
+
+	// NEW."col" = ... AND ... pairs a row of one table with the rows of the other.
+	match := func(row string, cols, others []string) string {
+		conds := make([]string, len(cols))
+		for i := range cols {
+			conds[i] = fmt.Sprintf("%s = %s.%s", quoteIdent(cols[i]), row, quoteIdent(others[i]))
+		}
+		return strings.Join(conds, " AND ")
+	}
+	anyNull := make([]string, len(fk.Columns))
+	for i, c := range fk.Columns {
+		anyNull[i] = "NEW." + quoteIdent(c) + " IS NULL"
+	}
+	unchanged := make([]string, len(fk.RefColumns))
+	for i, c := range fk.RefColumns {
+		unchanged[i] = fmt.Sprintf("NEW.%s IS NOT DISTINCT FROM OLD.%s", quoteIdent(c), quoteIdent(c))
+	}
+	violation := func(msg string) string {
+		return fmt.Sprintf(`RAISE EXCEPTION '%s' USING ERRCODE = 'foreign_key_violation', CONSTRAINT = '%s';`, msg, fk.Name)
+	}
+
+	var onDelete string
+	switch strings.ToUpper(fk.OnDelete) {
+	case "CASCADE":
+		onDelete = fmt.Sprintf("DELETE FROM %s WHERE %s;", quoteIdent(fk.Table), match("OLD", fk.Columns, fk.RefColumns))
+	case "SET NULL", "SET DEFAULT":
+		value := strings.TrimPrefix(strings.ToUpper(fk.OnDelete), "SET ")
+		sets := make([]string, len(fk.Columns))
+		for i, c := range fk.Columns {
+			sets[i] = quoteIdent(c) + " = " + value
+		}
+		onDelete = fmt.Sprintf("UPDATE %s SET %s WHERE %s;", quoteIdent(fk.Table), strings.Join(sets, ", "), match("OLD", fk.Columns, fk.RefColumns))
+	}
+	restrict := fmt.Sprintf("IF EXISTS (SELECT 1 FROM %s WHERE %s) THEN %s END IF;",
+		quoteIdent(fk.Table), match("OLD", fk.Columns, fk.RefColumns),
+		violation(fmt.Sprintf(`update or delete on table "%s" violates foreign key constraint "%s" on table "%s"`, fk.RefTable, fk.Name, fk.Table)))
+	refBody := restrict
+	if onDelete != "" {
+		refBody = fmt.Sprintf("IF TG_OP = 'DELETE' THEN %s RETURN NULL; END IF; %s", onDelete, restrict)
+	}
+
+	return []string{
+		fmt.Sprintf(`CREATE OR REPLACE FUNCTION %s() RETURNS trigger LANGUAGE plpgsql AS $$ BEGIN `+
+			`IF %s THEN RETURN NULL; END IF; `+
+			`IF NOT EXISTS (SELECT 1 FROM %s WHERE %s) THEN %s END IF; `+
+			`RETURN NULL; END $$;`,
+			quoteIdent(check), strings.Join(anyNull, " OR "),
+			quoteIdent(fk.RefTable), match("NEW", fk.RefColumns, fk.Columns),
+			violation(fmt.Sprintf(`insert or update on table "%s" violates foreign key constraint "%s"`, fk.Table, fk.Name))),
+		fmt.Sprintf(`CREATE OR REPLACE TRIGGER %s AFTER INSERT OR UPDATE OF %s ON %s FOR EACH ROW EXECUTE FUNCTION %s();`,
+			quoteIdent(check), quoteIdents(fk.Columns), quoteIdent(fk.Table), quoteIdent(check)),
+		fmt.Sprintf(`CREATE OR REPLACE FUNCTION %s() RETURNS trigger LANGUAGE plpgsql AS $$ BEGIN `+
+			`IF TG_OP = 'UPDATE' AND %s THEN RETURN NULL; END IF; `+
+			`IF EXISTS (SELECT 1 FROM %s WHERE %s) THEN RETURN NULL; END IF; `+
+			`%s RETURN NULL; END $$;`,
+			quoteIdent(ref), strings.Join(unchanged, " AND "),
+			quoteIdent(fk.RefTable), match("OLD", fk.RefColumns, fk.RefColumns), refBody),
+		fmt.Sprintf(`CREATE OR REPLACE TRIGGER %s AFTER DELETE OR UPDATE OF %s ON %s FOR EACH ROW EXECUTE FUNCTION %s();`,
+			quoteIdent(ref), quoteIdents(fk.RefColumns), quoteIdent(fk.RefTable), quoteIdent(ref)),
+	}, nil
+}
+
+func (fk ForeignKeyTrigger) create(conn *gorm.DB) error {
+	stmts, err := fk.Statements()
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if err := conn.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("foreign key trigger %s: %w", fk.Name, err)
+		}
+	}
+	return nil
+}
+
+// partitionedTables returns the partitioned tables of the current schema.
+func partitionedTables(conn *gorm.DB) (map[string]struct{}, error) {
+	var tables []string
+	err := conn.Raw(`SELECT c.relname FROM pg_partitioned_table p
+		JOIN pg_class c ON c.oid = p.partrelid
+		WHERE c.relnamespace = current_schema()::regnamespace`).Scan(&tables).Error
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]struct{}, len(tables))
+	for _, t := range tables {
+		set[t] = struct{}{}
+	}
+	return set, nil
+}
+
+// partitionedReferences returns the foreign keys migrating model would create to
+// partitioned tables, which Postgres rejects when the referenced columns are not the whole
+// primary key, as the trigger enforcing each of them.
+func partitionedReferences(conn *gorm.DB, model any) ([]ForeignKeyTrigger, error) {
+	stmt := &gorm.Statement{DB: conn}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	if len(stmt.Schema.Relationships.Relations) == 0 {
+		return nil, nil
+	}
+	partitioned, err := partitionedTables(conn)
+	if err != nil || len(partitioned) == 0 {
+		return nil, err
+	}
+	var keys []ForeignKeyTrigger
+	for _, constraint := range ownConstraints(stmt.Schema) {
+		if _, ok := partitioned[constraint.ReferenceSchema.Table]; !ok {
+			continue
+		}
+		fk := ForeignKeyTrigger{
+			Name:     constraint.Name,
+			Table:    constraint.Schema.Table,
+			RefTable: constraint.ReferenceSchema.Table,
+			OnDelete: constraint.OnDelete,
+		}
+		for i := range constraint.ForeignKeys {
+			fk.Columns = append(fk.Columns, constraint.ForeignKeys[i].DBName)
+			fk.RefColumns = append(fk.RefColumns, constraint.References[i].DBName)
+		}
+		keys = append(keys, fk)
+	}
+	return keys, nil
+}
+
+// ownConstraints returns the foreign keys auto-migration creates on the table of s.
+func ownConstraints(s *schema.Schema) []*schema.Constraint {
+	var out []*schema.Constraint
+	for _, rel := range s.Relationships.Relations {
+		if rel.Field.IgnoreMigration {
+			continue
+		}
+		if constraint := rel.ParseConstraint(); constraint != nil && constraint.Schema == s {
+			out = append(out, constraint)
+		}
+	}
+	return out
+}
+
+// migrateReferencingPartitioned migrates a model referencing partitioned tables: the table
+// is migrated without foreign keys, then the foreign keys to unpartitioned tables are added
+// and those to partitioned tables are enforced by triggers.
+func migrateReferencingPartitioned(conn *gorm.DB, model any, keys []ForeignKeyTrigger) error {
+	if err := withoutForeignKeys(conn).AutoMigrate(model); err != nil {
+		return err
+	}
+	stmt := &gorm.Statement{DB: conn}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+	enforced := make(map[string]bool, len(keys))
+	for _, fk := range keys {
+		if err := fk.create(conn); err != nil {
+			return err
+		}
+		enforced[fk.Name] = true
+	}
+	migrator := conn.Migrator()
+	for _, constraint := range ownConstraints(stmt.Schema) {
+		if enforced[constraint.Name] || migrator.HasConstraint(model, constraint.Name) {
+			continue
+		}
+		if err := migrator.CreateConstraint(model, constraint.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withoutForeignKeys returns a session of conn whose migrations skip foreign keys.
+func withoutForeignKeys(conn *gorm.DB) *gorm.DB {
+	tx := conn.Session(&gorm.Session{NewDB: true})
+	cfg := *tx.Config
+	cfg.DisableForeignKeyConstraintWhenMigrating = true
+	tx.Config = &cfg
+	return tx
+}
+
+// scopePartition restricts a write of entity to its partition, so Postgres prunes the
+// other partitions instead of probing each of their primary key indexes.
+func (r *Repository[T]) scopePartition(entity *T) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if r.partitionKey == "" {
+			return db
+		}
+		if err := db.Statement.Parse(entity); err != nil {
+			_ = db.AddError(err)
+			return db
+		}
+		field := db.Statement.Schema.LookUpField(r.partitionKey)
+		if field == nil {
+			_ = db.AddError(fmt.Errorf("partition column %q not found", r.partitionKey))
+			return db
+		}
+		value, zero := field.ValueOf(db.Statement.Context, reflect.Indirect(reflect.ValueOf(entity)))
+		if zero {
+			return db
+		}
+		return db.Where(fmt.Sprintf("%s.%s = ?", db.Statement.Schema.Table, r.partitionKey), value)
+	}
+}
+
+func quoteIdents(idents []string) string {
+	quoted := make([]string, len(idents))
+	for i, ident := range idents {
+		quoted[i] = quoteIdent(ident)
+	}
+	return strings.Join(quoted, ", ")
+}
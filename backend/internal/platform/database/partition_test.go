@@ -0,0 +1,89 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitioningCreateStatements(t *testing.T) {
+	t.Parallel()
+
+	p := database.Partitioning{Table: "orders", Column: "business_id", Partitions: 3}
+	stmts, err := p.CreateStatements()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`CREATE TABLE "orders" (LIKE "orders_unpartitioned" INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING GENERATED) PARTITION BY HASH ("business_id");`,
+		`CREATE TABLE "orders_p0" PARTITION OF "orders" FOR VALUES WITH (MODULUS 3, REMAINDER 0);`,
+		`CREATE TABLE "orders_p1" PARTITION OF "orders" FOR VALUES WITH (MODULUS 3, REMAINDER 1);`,
+		`CREATE TABLE "orders_p2" PARTITION OF "orders" FOR VALUES WITH (MODULUS 3, REMAINDER 2);`,
+	}, stmts)
+}
+
+func TestPartitioningCreateStatementsRejectsInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		p    database.Partitioning
+	}{
+		{name: "injection in column", p: database.Partitioning{Table: "orders", Column: "business_id); DROP TABLE orders; --", Partitions: 4}},
+		{name: "injection in table", p: database.Partitioning{Table: "orders; --", Column: "business_id", Partitions: 4}},
+		{name: "single partition", p: database.Partitioning{Table: "orders", Column: "business_id", Partitions: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := tt.p.CreateStatements()
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestForeignKeyTriggerStatements(t *testing.T) {
+	t.Parallel()
+
+	fk := database.ForeignKeyTrigger{
+		Name:       "fk_orders_items",
+		Table:      "order_items",
+		Columns:    []string{"order_id"},
+		RefTable:   "orders",
+		RefColumns: []string{"id"},
+		OnDelete:   "CASCADE",
+	}
+	stmts, err := fk.Statements()
+	require.NoError(t, err)
+	require.Len(t, stmts, 4)
+	require.Contains(t, stmts[0], `IF NEW."order_id" IS NULL THEN RETURN NULL; END IF;`)
+	require.Contains(t, stmts[0], `IF NOT EXISTS (SELECT 1 FROM "orders" WHERE "id" = NEW."order_id")`)
+	require.Equal(t, `CREATE OR REPLACE TRIGGER "fk_orders_items_check" AFTER INSERT OR UPDATE OF "order_id" ON "order_items" FOR EACH ROW EXECUTE FUNCTION "fk_orders_items_check"();`, stmts[1])
+	require.Contains(t, stmts[2], `DELETE FROM "order_items" WHERE "order_id" = OLD."id";`)
+	require.Equal(t, `CREATE OR REPLACE TRIGGER "fk_orders_items_ref" AFTER DELETE OR UPDATE OF "id" ON "orders" FOR EACH ROW EXECUTE FUNCTION "fk_orders_items_ref"();`, stmts[3])
+
+	fk.OnDelete = ""
+	stmts, err = fk.Statements()
+	require.NoError(t, err)
+	require.NotContains(t, stmts[2], "DELETE FROM")
+	require.Contains(t, stmts[2], `IF EXISTS (SELECT 1 FROM "order_items" WHERE "order_id" = OLD."id") THEN RAISE EXCEPTION`)
+}
+
+func TestForeignKeyTriggerStatementsRejectsInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		fk   database.ForeignKeyTrigger
+	}{
+		{name: "injection in column", fk: database.ForeignKeyTrigger{Name: "fk", Table: "order_items", Columns: []string{"order_id = 1; --"}, RefTable: "orders", RefColumns: []string{"id"}}},
+		{name: "injection in name", fk: database.ForeignKeyTrigger{Name: "fk'; --", Table: "order_items", Columns: []string{"order_id"}, RefTable: "orders", RefColumns: []string{"id"}}},
+		{name: "column count mismatch", fk: database.ForeignKeyTrigger{Name: "fk", Table: "order_items", Columns: []string{"order_id"}, RefTable: "orders", RefColumns: []string{"id", "business_id"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := tt.fk.Statements()
+			require.Error(t, err)
+		})
+	}
+}
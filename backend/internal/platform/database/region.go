@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/abdelrahman146/kyora/internal/platform/region"
+	"github.com/abdelrahman146/kyora/internal/platform/types/ctxkey"
+	"gorm.io/gorm"
+)
+
+// ErrRegionUnavailable is returned for queries targeting a region with no configured connection.
+var ErrRegionUnavailable = errors.New("database: region is not configured")
+
+// txRegionKey holds the region of the transaction stored under TxKey.
+var txRegionKey = ctxkey.New("transaction_region")
+
+type regionConns struct {
+	home  string
+	conns map[string]*gorm.DB
+}
+
+// AddRegion registers regional as the connection serving the named data residency region.
+// Queries whose context targets the region (see region.WithRegion) run on it.
+func (d *Database) AddRegion(name string, regional *Database) {
+	d.regions.conns[region.Resolve(name)] = regional.db
+}
+
+// Global returns a view of d that always uses the home region, whatever region the context
+// targets. Control-plane data (users, workspaces, billing, onboarding) lives there so it can
+// be found before the workspace region is known.
+func (d *Database) Global() *Database {
+	return &Database{db: d.db, regions: d.regions, global: true}
+}
+
+// Regions returns the names of the regions d serves, home first.
+func (d *Database) Regions() []string {
+	if d.global {
+		return []string{d.regions.home}
+	}
+	names := make([]string, 0, len(d.regions.conns))
+	for name := range d.regions.conns {
+		if name != d.regions.home {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return append([]string{d.regions.home}, names...)
+}
+
+// Conns returns the connection of every region d serves, for schema helpers that must run
+// in each regional database.
+func (d *Database) Conns() []*gorm.DB {
+	names := d.Regions()
+	conns := make([]*gorm.DB, len(names))
+	for i, name := range names {
+		conns[i] = d.regions.conns[name]
+	}
+	return conns
+}
+
+// targetRegion returns the region queries on ctx go to.
+func (d *Database) targetRegion(ctx context.Context) string {
+	if d.global {
+		return d.regions.home
+	}
+	return region.FromContext(ctx)
+}
+
+// txRegion returns the region of the transaction carried by ctx. Transactions stored without
+// a region (e.g. by tests) belong to the home region.
+func (d *Database) txRegion(ctx context.Context) string {
+	if name, ok := ctx.Value(txRegionKey).(string); ok {
+		return name
+	}
+	return d.regions.home
+}
+
+func (d *Database) regionConn(name string) (*gorm.DB, error) {
+	conn, ok := d.regions.conns[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRegionUnavailable, name)
+	}
+	return conn, nil
+}
+
+// withTx returns a copy of ctx carrying tx, a transaction in the named region.
+func withTx(ctx context.Context, tx *gorm.DB, name string) context.Context {
+	return context.WithValue(context.WithValue(ctx, TxKey, tx), txRegionKey, name)
+}
+
+// ConnectRegions opens the database of every extra region configured under region.regions
+// and registers it on db. A region without a database_dsn is an error, so a misconfigured
+// region fails at startup rather than on the first request.
+func ConnectRegions(db *Database, logLevel string) error {
+	for _, name := range region.Configured() {
+		if name == db.regions.home {
+			continue
+		}
+		dsn := region.Setting(name, "database_dsn")
+		if dsn == "" {
+			return fmt.Errorf("region %s: database_dsn is not configured", name)
+		}
+		regional, err := NewConnection(dsn, logLevel)
+		if err != nil {
+			return fmt.Errorf("region %s: %w", name, err)
+		}
+		db.AddRegion(name, regional)
+	}
+	return nil
+}
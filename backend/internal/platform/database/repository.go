@@ -21,6 +21,8 @@ type DatabaseOption func(db *gorm.DB) *gorm.DB
 
 type Repository[T any] struct {
 	db *Database
+	// partitionKey is the column the table is partitioned on, if any (see PartitionedBy).
+	partitionKey string
 }
 
 func NewRepository[T any](db *Database) *Repository[T] {
@@ -32,6 +34,13 @@ func NewRepository[T any](db *Database) *Repository[T] {
 	return &Repository[T]{db: db}
 }
 
+// PartitionedBy marks the table as partitioned on field, so updates and deletes of a single
+// entity also filter on its partition column and only touch its partition.
+func (r *Repository[T]) PartitionedBy(field schema.Field) *Repository[T] {
+	r.partitionKey = field.Column()
+	return r
+}
+
 func shouldAutoMigrate() bool {
 	if viper.IsSet(config.DatabaseAutoMigrate) {
 		return viper.GetBool(config.DatabaseAutoMigrate)
@@ -63,6 +72,31 @@ func (r *Repository[T]) WithPreload(associations ...string) func(db *gorm.DB) *g
 	}
 }
 
+// WithSelect restricts the query to the provided columns.
+// Use table-qualified names (e.g., "orders.id") when the query joins other tables.
+func (r *Repository[T]) WithSelect(columns ...string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(columns) == 0 {
+			return db
+		}
+		return db.Select(columns)
+	}
+}
+
+// WithPreloadSelect preloads a single association loading only the provided columns.
+// The columns must include the keys GORM needs to stitch the association back
+// onto its parent (the association primary key and any foreign key).
+func (r *Repository[T]) WithPreloadSelect(association string, columns ...string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(columns) == 0 {
+			return db.Preload(association)
+		}
+		return db.Preload(association, func(tx *gorm.DB) *gorm.DB {
+			return tx.Select(columns)
+		})
+	}
+}
+
 func (r *Repository[T]) WithJoins(joins ...string) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		for _, join := range joins {
@@ -274,23 +308,76 @@ func (r *Repository[T]) ScopeSearchTerm(searchTerm string, fields ...schema.Fiel
 }
 
 func (r *Repository[T]) CreateOne(ctx context.Context, entity *T, opts ...func(db *gorm.DB) *gorm.DB) error {
+	initVersion(entity)
 	return r.db.Conn(ctx).Scopes(opts...).Create(entity).Error
 }
 
 func (r *Repository[T]) CreateMany(ctx context.Context, entities []*T, opts ...func(db *gorm.DB) *gorm.DB) error {
+	for _, entity := range entities {
+		initVersion(entity)
+	}
 	return r.db.Conn(ctx).Scopes(opts...).Create(&entities).Error
 }
 
+// UpdateOne saves all fields of the entity.
+// For models embedding Versioned the update is a compare-and-swap on the version column:
+// it only applies when the stored version still equals the entity's version, and returns
+// ErrVersionConflict otherwise.
+// Entities of partitioned tables are updated in place and never inserted: Save falls back
+// to an upsert on the id, which no longer matches a unique constraint once the partition
+// column joins the primary key. Updating a missing entity returns gorm.ErrRecordNotFound.
 func (r *Repository[T]) UpdateOne(ctx context.Context, entity *T, opts ...func(db *gorm.DB) *gorm.DB) error {
-	return r.db.Conn(ctx).Scopes(opts...).Save(entity).Error
+	if v, ok := any(entity).(versioned); ok {
+		return r.compareAndSwap(ctx, entity, v, opts...)
+	}
+	if r.partitionKey != "" {
+		res := r.db.Conn(ctx).Scopes(append(opts, r.scopePartition(entity))...).
+			Model(entity).
+			Select("*").
+			Updates(entity)
+		if res.Error == nil && res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return res.Error
+	}
+	return r.db.Conn(ctx).Scopes(append(opts, r.scopePartition(entity))...).Save(entity).Error
+}
+
+func (r *Repository[T]) compareAndSwap(ctx context.Context, entity *T, v versioned, opts ...func(db *gorm.DB) *gorm.DB) error {
+	expected := v.currentVersion()
+	v.setVersion(expected + 1)
+	res := r.db.Conn(ctx).Scopes(append(opts, r.scopePartition(entity))...).
+		Model(entity).
+		Where(versionColumn+" = ?", expected).
+		Select("*").
+		Updates(entity)
+	err := res.Error
+	if err == nil && res.RowsAffected == 0 {
+		err = ErrVersionConflict
+	}
+	if err != nil {
+		v.setVersion(expected)
+		return err
+	}
+	return nil
 }
 
+// UpdateMany saves all fields of the entities. Entities of partitioned tables are updated
+// one by one, like UpdateOne.
 func (r *Repository[T]) UpdateMany(ctx context.Context, entities []*T, opts ...func(db *gorm.DB) *gorm.DB) error {
+	if r.partitionKey != "" {
+		for _, entity := range entities {
+			if err := r.UpdateOne(ctx, entity, opts...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 	return r.db.Conn(ctx).Scopes(opts...).Save(&entities).Error
 }
 
 func (r *Repository[T]) DeleteOne(ctx context.Context, entity *T, opts ...func(db *gorm.DB) *gorm.DB) error {
-	return r.db.Conn(ctx).Scopes(opts...).Delete(entity).Error
+	return r.db.Conn(ctx).Scopes(append(opts, r.scopePartition(entity))...).Delete(entity).Error
 }
 
 func (r *Repository[T]) DeleteMany(ctx context.Context, opts ...func(db *gorm.DB) *gorm.DB) error {
@@ -339,24 +426,43 @@ func (r *Repository[T]) Avg(ctx context.Context, column schema.Field, opts ...fu
 	return avg, nil
 }
 
-func (r *Repository[T]) TimeSeriesSum(ctx context.Context, valueColumn schema.Field, timeColumn schema.Field, granularity timeseries.Granularity, opts ...func(db *gorm.DB) *gorm.DB) (*timeseries.TimeSeries, error) {
+// TimeSeriesSum sums valueColumn per bucket of timeColumn. Buckets start at local
+// boundaries (midnight, Monday, first of month, ...) in the calendar's time zone, and
+// quarters and years start with its fiscal year; the zero calendar means UTC calendar years.
+func (r *Repository[T]) TimeSeriesSum(ctx context.Context, valueColumn schema.Field, timeColumn schema.Field, granularity timeseries.Granularity, cal timeseries.Calendar, opts ...func(db *gorm.DB) *gorm.DB) (*timeseries.TimeSeries, error) {
 	var rows []timeseries.TimeSeriesRow
-	sel := fmt.Sprintf("date_trunc('%s', %s) AS timestamp, COALESCE(SUM(%s),0)::decimal AS value", granularity.Bucket(), timeColumn.Column(), valueColumn.Column())
+	bucket, args := timeSeriesBucket(timeColumn, granularity, cal)
+	sel := fmt.Sprintf("%s AS timestamp, COALESCE(SUM(%s),0)::decimal AS value", bucket, valueColumn.Column())
 	q := r.db.Conn(ctx).Scopes(opts...).Model(new(T))
-	if err := q.Select(sel).Group("timestamp").Order("timestamp ASC").Scan(&rows).Error; err != nil {
+	if err := q.Select(sel, args...).Group("timestamp").Order("timestamp ASC").Scan(&rows).Error; err != nil {
 		return nil, err
 	}
-	return timeseries.New(rows, granularity), nil
+	return timeseries.New(timeseries.InLocation(rows, cal.Loc()), granularity, cal), nil
 }
 
-func (r *Repository[T]) TimeSeriesCount(ctx context.Context, timeColumn schema.Field, granularity timeseries.Granularity, opts ...func(db *gorm.DB) *gorm.DB) (*timeseries.TimeSeries, error) {
+// TimeSeriesCount counts rows per bucket of timeColumn, bucketed in cal like TimeSeriesSum.
+func (r *Repository[T]) TimeSeriesCount(ctx context.Context, timeColumn schema.Field, granularity timeseries.Granularity, cal timeseries.Calendar, opts ...func(db *gorm.DB) *gorm.DB) (*timeseries.TimeSeries, error) {
 	var rows []timeseries.TimeSeriesRow
-	sel := fmt.Sprintf("date_trunc('%s', %s) AS timestamp, COUNT(*) AS value", granularity.Bucket(), timeColumn.Column())
+	bucket, args := timeSeriesBucket(timeColumn, granularity, cal)
+	sel := fmt.Sprintf("%s AS timestamp, COUNT(*) AS value", bucket)
 	q := r.db.Conn(ctx).Scopes(opts...).Model(new(T))
-	if err := q.Select(sel).Group("timestamp").Order("timestamp ASC").Scan(&rows).Error; err != nil {
+	if err := q.Select(sel, args...).Group("timestamp").Order("timestamp ASC").Scan(&rows).Error; err != nil {
 		return nil, err
 	}
-	return timeseries.New(rows, granularity), nil
+	return timeseries.New(timeseries.InLocation(rows, cal.Loc()), granularity, cal), nil
+}
+
+// timeSeriesBucket returns the SQL truncating timeColumn to the start of its bucket, with
+// its arguments. Fiscal quarters and years are truncated on the local time shifted back by
+// the months the fiscal year starts after January, then shifted forward again.
+func timeSeriesBucket(timeColumn schema.Field, granularity timeseries.Granularity, cal timeseries.Calendar) (string, []any) {
+	tz := cal.Loc().String()
+	offset := cal.FiscalOffset()
+	if offset == 0 || (granularity != timeseries.Quarterly && granularity != timeseries.Yearly) {
+		return fmt.Sprintf("date_trunc('%s', %s, ?)", granularity.Bucket(), timeColumn.Column()), []any{tz}
+	}
+	return fmt.Sprintf("((date_trunc('%s', (%s AT TIME ZONE ?) - make_interval(months => ?)) + make_interval(months => ?)) AT TIME ZONE ?)",
+		granularity.Bucket(), timeColumn.Column()), []any{tz, offset, offset, tz}
 }
 
 func (r *Repository[T]) CountBy(ctx context.Context, column schema.Field, opts ...func(db *gorm.DB) *gorm.DB) ([]keyvalue.KeyValue, error) {
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const queryTimeoutKey = "kyora:query_timeout"
+
+type queryTimeout struct {
+	parent context.Context
+	cancel context.CancelFunc
+}
+
+// registerQueryTimeout bounds every create, query, update, delete, and raw exec statement
+// with a context deadline, so a slow query releases its pool connection instead of holding
+// it until the client goes away. A shorter deadline already on the context still wins.
+//
+// Row callbacks (Row, Rows, Scan) are left alone: their result set is read after the
+// callback chain returns, so cancelling there would abort the read. They remain bounded
+// by the server-side statement_timeout.
+func registerQueryTimeout(db *gorm.DB, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+	before := func(tx *gorm.DB) {
+		parent := tx.Statement.Context
+		if parent == nil {
+			parent = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(queryTimeoutKey, queryTimeout{parent: parent, cancel: cancel})
+	}
+	after := func(tx *gorm.DB) {
+		if v, ok := tx.InstanceGet(queryTimeoutKey); ok {
+			qt := v.(queryTimeout)
+			qt.cancel()
+			// Restore the caller's context: chained statements may be executed again
+			// (e.g. Count then Find on the same query) and must not inherit a cancelled one.
+			tx.Statement.Context = qt.parent
+		}
+	}
+
+	cb := db.Callback()
+	steps := []struct {
+		name          string
+		before, after func(string, func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+	for _, step := range steps {
+		if err := step.before("kyora:timeout_before_"+step.name, before); err != nil {
+			return err
+		}
+		if err := step.after("kyora:timeout_after_"+step.name, after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
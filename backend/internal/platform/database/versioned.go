@@ -0,0 +1,37 @@
+package database
+
+import "errors"
+
+const versionColumn = "version"
+
+// ErrVersionConflict is returned by Repository.UpdateOne when a versioned entity was
+// modified by someone else since it was loaded.
+var ErrVersionConflict = errors.New("database: version conflict")
+
+// Versioned adds an optimistic-locking version column to a model. Embed it in models
+// whose concurrent updates must not silently overwrite each other; the repository then
+// bumps the version on every UpdateOne and rejects stale writes with ErrVersionConflict.
+type Versioned struct {
+	Version int64 `gorm:"column:version;type:bigint;not null;default:1" json:"version"`
+}
+
+func (v *Versioned) currentVersion() int64 { return v.Version }
+
+func (v *Versioned) setVersion(version int64) { v.Version = version }
+
+// versioned is satisfied only by models embedding Versioned.
+type versioned interface {
+	currentVersion() int64
+	setVersion(int64)
+}
+
+func initVersion(entity any) {
+	if v, ok := entity.(versioned); ok && v.currentVersion() == 0 {
+		v.setVersion(1)
+	}
+}
+
+// IsVersionConflict reports whether err is an optimistic-locking conflict.
+func IsVersionConflict(err error) bool {
+	return errors.Is(err, ErrVersionConflict)
+}
@@ -20,6 +20,9 @@ const (
 	TemplateWelcome              TemplateID = "welcome"
 	TemplateLoginNotification    TemplateID = "login_notification"
 	TemplateWorkspaceInvitation  TemplateID = "workspace_invitation"
+	TemplateEmailChangeConfirm   TemplateID = "email_change_confirmation"
+	TemplateEmailChangedNotice   TemplateID = "email_changed_notice"
+	TemplateMagicLink            TemplateID = "magic_link"
 
 	// Billing and Subscription Templates
 	TemplateSubscriptionWelcome   TemplateID = "subscription_welcome"
@@ -30,6 +33,18 @@ const (
 	TemplateSubscriptionUpdated   TemplateID = "subscription_updated"
 	TemplateInvoiceGenerated      TemplateID = "invoice_generated"
 	TemplateSubscriptionConfirmed TemplateID = "subscription_confirmed"
+
+	// Analytics Templates
+	TemplateMonthlyGoalSummary TemplateID = "monthly_goal_summary"
+	TemplateWeeklyDigest       TemplateID = "weekly_digest"
+
+	// Accounting Templates
+	TemplateRecurringExpenseReminder TemplateID = "recurring_expense_reminder"
+
+	// Storefront Templates
+	TemplateReviewRequest   TemplateID = "review_request"
+	TemplateDigitalDelivery TemplateID = "digital_delivery"
+	TemplateCustomerLogin   TemplateID = "customer_login"
 )
 
 // registry maps TemplateID to file paths within the embedded FS
@@ -42,6 +57,9 @@ var templateFiles = map[TemplateID]string{
 	TemplateWelcome:              "templates/welcome.html",
 	TemplateLoginNotification:    "templates/login_notification.html",
 	TemplateWorkspaceInvitation:  "templates/workspace_invitation.html",
+	TemplateEmailChangeConfirm:   "templates/email_change_confirmation.html",
+	TemplateEmailChangedNotice:   "templates/email_changed_notice.html",
+	TemplateMagicLink:            "templates/magic_link.html",
 
 	// Billing and Subscription Templates
 	TemplateSubscriptionWelcome:   "templates/subscription_welcome.html",
@@ -50,6 +68,18 @@ var templateFiles = map[TemplateID]string{
 	TemplateTrialEnding:           "templates/trial_ending.html",
 	TemplatePaymentSucceeded:      "templates/payment_succeeded.html",
 	TemplateSubscriptionConfirmed: "templates/subscription_confirmed.html",
+
+	// Analytics Templates
+	TemplateMonthlyGoalSummary: "templates/monthly_goal_summary.html",
+	TemplateWeeklyDigest:       "templates/weekly_digest.html",
+
+	// Accounting Templates
+	TemplateRecurringExpenseReminder: "templates/recurring_expense_reminder.html",
+
+	// Storefront Templates
+	TemplateReviewRequest:   "templates/review_request.html",
+	TemplateDigitalDelivery: "templates/digital_delivery.html",
+	TemplateCustomerLogin:   "templates/customer_login.html",
 }
 
 // subjects maps TemplateID to a default subject line
@@ -62,6 +92,9 @@ var subjects = map[TemplateID]string{
 	TemplateWelcome:              "Welcome to Kyora!",
 	TemplateLoginNotification:    "New login to your account",
 	TemplateWorkspaceInvitation:  "You've been invited to join a workspace",
+	TemplateEmailChangeConfirm:   "Confirm your new email address",
+	TemplateEmailChangedNotice:   "Your sign-in email was changed",
+	TemplateMagicLink:            "Your sign-in link",
 
 	// Billing and Subscription Templates
 	TemplateSubscriptionWelcome:   "Welcome to your subscription!",
@@ -72,6 +105,18 @@ var subjects = map[TemplateID]string{
 	TemplateSubscriptionUpdated:   "Your subscription has been updated",
 	TemplateInvoiceGenerated:      "Your invoice is ready",
 	TemplateSubscriptionConfirmed: "Subscription confirmed - You're all set!",
+
+	// Analytics Templates
+	TemplateMonthlyGoalSummary: "Your monthly goals summary",
+	TemplateWeeklyDigest:       "Your weekly digest",
+
+	// Accounting Templates
+	TemplateRecurringExpenseReminder: "A recurring expense is coming up",
+
+	// Storefront Templates
+	TemplateReviewRequest:   "How was your order?",
+	TemplateDigitalDelivery: "Your downloads are ready",
+	TemplateCustomerLogin:   "Your login code",
 }
 
 // RenderTemplate renders the embedded HTML template with provided data.
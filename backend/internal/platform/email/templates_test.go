@@ -32,3 +32,126 @@ func TestRenderTemplate_OnboardingEmailOTP_MissingCode_DoesNotError(t *testing.T
 	})
 	require.NoError(t, err)
 }
+
+func TestRenderTemplate_MonthlyGoalSummary_RendersMetrics(t *testing.T) {
+	html, err := email.RenderTemplate(email.TemplateMonthlyGoalSummary, map[string]any{
+		"currentYear":   "2025",
+		"productName":   "Kyora",
+		"userName":      "Test User",
+		"businessName":  "Acme",
+		"monthLabel":    "January 2025",
+		"achievedCount": 1,
+		"metricCount":   2,
+		"metrics": []map[string]any{
+			{"label": "Revenue", "target": "1000.00 USD", "actual": "1200.00 USD", "percent": "120%", "achieved": true},
+			{"label": "Orders", "target": "50", "actual": "20", "percent": "40%", "achieved": false},
+		},
+	})
+	require.NoError(t, err)
+	require.Contains(t, html, "January 2025")
+	require.Contains(t, html, "1200.00 USD")
+	require.Contains(t, html, "40%")
+}
+
+func TestRenderTemplate_WeeklyDigest_RendersBusinesses(t *testing.T) {
+	html, err := email.RenderTemplate(email.TemplateWeeklyDigest, map[string]any{
+		"currentYear":        "2025",
+		"productName":        "Kyora",
+		"userName":           "Test User",
+		"weekLabel":          "Jan 6 – Jan 12, 2025",
+		"pendingInvitations": 2,
+		"preferencesURL":     "https://app.kyora.com/settings/profile",
+		"businesses": []map[string]any{
+			{"name": "Acme", "orders": int64(12), "revenue": "1200.00 USD", "newCustomers": int64(3), "lowStockItems": int64(4)},
+		},
+	})
+	require.NoError(t, err)
+	require.Contains(t, html, "Jan 6 – Jan 12, 2025")
+	require.Contains(t, html, "1200.00 USD")
+	require.Contains(t, html, "<strong>2</strong> workspace invitations")
+	require.Contains(t, html, "https://app.kyora.com/settings/profile")
+}
+
+func TestRenderTemplate_ReviewRequest_RendersProducts(t *testing.T) {
+	html, err := email.RenderTemplate(email.TemplateReviewRequest, map[string]any{
+		"currentYear":  "2025",
+		"productName":  "Kyora",
+		"customerName": "Sara",
+		"businessName": "Acme",
+		"orderNumber":  "ORD-1001",
+		"products": []map[string]any{
+			{"name": "Linen Shirt", "reviewURL": "https://shop.example.com/sf_1/products/prd_1"},
+			{"name": "Canvas Tote", "reviewURL": ""},
+		},
+	})
+	require.NoError(t, err)
+	require.Contains(t, html, "ORD-1001")
+	require.Contains(t, html, "Canvas Tote")
+	require.Contains(t, html, "https://shop.example.com/sf_1/products/prd_1")
+}
+
+func TestRenderTemplate_DigitalDelivery_RendersDownloads(t *testing.T) {
+	html, err := email.RenderTemplate(email.TemplateDigitalDelivery, map[string]any{
+		"currentYear":  "2025",
+		"productName":  "Kyora",
+		"customerName": "Sara",
+		"businessName": "Acme",
+		"orderNumber":  "ORD-1002",
+		"downloads": []map[string]any{
+			{"name": "Pattern Guide", "url": "https://cdn.example.com/files/guide.pdf"},
+		},
+	})
+	require.NoError(t, err)
+	require.Contains(t, html, "ORD-1002")
+	require.Contains(t, html, "Pattern Guide")
+	require.Contains(t, html, "https://cdn.example.com/files/guide.pdf")
+}
+
+func TestRenderTemplate_CustomerLogin_RendersCodeAndLink(t *testing.T) {
+	html, err := email.RenderTemplate(email.TemplateCustomerLogin, map[string]any{
+		"currentYear":   "2025",
+		"productName":   "Kyora",
+		"customerName":  "Sara",
+		"businessName":  "Acme",
+		"code":          "482913",
+		"loginURL":      "https://shop.example.com/sf_1/account/login?token=abc",
+		"expiryMinutes": 15,
+	})
+	require.NoError(t, err)
+	require.Contains(t, html, "482913")
+	require.Contains(t, html, "https://shop.example.com/sf_1/account/login?token=abc")
+	require.Contains(t, html, "15 minutes")
+}
+
+func TestRenderTemplate_RecurringExpenseReminder_RendersCharge(t *testing.T) {
+	html, err := email.RenderTemplate(email.TemplateRecurringExpenseReminder, map[string]any{
+		"currentYear":  "2025",
+		"productName":  "Kyora",
+		"userName":     "Test User",
+		"businessName": "Acme",
+		"frequency":    "monthly",
+		"category":     "rent",
+		"amount":       "2500.00 USD",
+		"dueDate":      "March 1, 2025",
+	})
+	require.NoError(t, err)
+	require.Contains(t, html, "2500.00 USD")
+	require.Contains(t, html, "March 1, 2025")
+}
+
+func TestRenderTemplate_EmailChangedNotice_RendersUndoLink(t *testing.T) {
+	html, err := email.RenderTemplate(email.TemplateEmailChangedNotice, map[string]any{
+		"currentYear": "2025",
+		"productName": "Kyora",
+		"userName":    "Test User",
+		"oldEmail":    "old@example.com",
+		"newEmail":    "new@example.com",
+		"changeDate":  "March 1, 2025",
+		"undoURL":     "https://app.kyora.com/email-change/undo?token=abc",
+		"expiryTime":  "7 days",
+	})
+	require.NoError(t, err)
+	require.Contains(t, html, "old@example.com")
+	require.Contains(t, html, "new@example.com")
+	require.Contains(t, html, "https://app.kyora.com/email-change/undo?token=abc")
+}
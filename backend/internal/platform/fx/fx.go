@@ -0,0 +1,37 @@
+// Package fx looks up foreign exchange rates.
+package fx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+// Provider returns how many units of the to currency one unit of the from currency was worth
+// on a day.
+type Provider interface {
+	Rate(ctx context.Context, from, to string, on time.Time) (decimal.Decimal, error)
+}
+
+// New returns the Provider selected by fx.provider, or nil when automatic rates are disabled:
+// 1) "" -> nil (callers must be given a rate)
+// 2) "mock" -> Mock with fixed rates
+// 3) "http" -> HTTP, querying fx.http.base_url
+func New() (Provider, error) {
+	provider := strings.TrimSpace(viper.GetString(config.FXProvider))
+	switch provider {
+	case "":
+		return nil, nil
+	case "mock":
+		return &MockProvider{}, nil
+	case "http":
+		return NewHTTPProvider(viper.GetString(config.FXHTTPBaseURL), viper.GetString(config.FXHTTPAPIKey), nil), nil
+	default:
+		return nil, errors.New("unsupported FX provider: " + provider)
+	}
+}
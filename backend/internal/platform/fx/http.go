@@ -0,0 +1,81 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultHTTPBaseURL is the public Frankfurter API (European Central Bank reference rates).
+const DefaultHTTPBaseURL = "https://api.frankfurter.app"
+
+// HTTPProvider reads daily rates from a service speaking the Frankfurter contract:
+//
+//	GET <baseURL>/2026-01-31?from=EUR&to=AED
+//	200 {"amount": 1.0, "base": "EUR", "date": "2026-01-30", "rates": {"AED": 3.9712}}
+//
+// On days without a published rate the service answers with the closest earlier one.
+type HTTPProvider struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewHTTPProvider creates an HTTPProvider; an empty baseURL uses DefaultHTTPBaseURL and apiKey
+// is sent as a bearer token when set.
+func NewHTTPProvider(baseURL, apiKey string, httpClient *http.Client) *HTTPProvider {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		baseURL = DefaultHTTPBaseURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &HTTPProvider{baseURL: baseURL, apiKey: apiKey, httpClient: httpClient}
+}
+
+type httpRatesResponse struct {
+	Rates map[string]decimal.Decimal `json:"rates"`
+}
+
+func (p *HTTPProvider) Rate(ctx context.Context, from, to string, on time.Time) (decimal.Decimal, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	query := url.Values{"from": {from}, "to": {to}}
+	endpoint := p.baseURL + "/" + on.Format("2006-01-02") + "?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return decimal.Zero, fmt.Errorf("fx provider returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var out httpRatesResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return decimal.Zero, fmt.Errorf("decode fx response: %w", err)
+	}
+	rate, ok := out.Rates[to]
+	if !ok || !rate.IsPositive() {
+		return decimal.Zero, fmt.Errorf("fx provider has no %s/%s rate for %s", from, to, on.Format("2006-01-02"))
+	}
+	return rate, nil
+}
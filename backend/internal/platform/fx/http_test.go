@@ -0,0 +1,66 @@
+package fx_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/fx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPProviderRate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		require.Equal(t, "/2026-01-31", r.URL.Path)
+		require.Equal(t, "EUR", r.URL.Query().Get("from"))
+		require.Equal(t, "AED", r.URL.Query().Get("to"))
+		_, _ = w.Write([]byte(`{"amount":1.0,"base":"EUR","date":"2026-01-30","rates":{"AED":3.9712}}`))
+	}))
+	defer srv.Close()
+
+	on := time.Date(2026, 1, 31, 15, 0, 0, 0, time.UTC)
+	rate, err := fx.NewHTTPProvider(srv.URL+"/", "secret", srv.Client()).Rate(context.Background(), "eur", "aed", on)
+	require.NoError(t, err)
+	require.Equal(t, "3.9712", rate.String())
+}
+
+func TestHTTPProviderErrors(t *testing.T) {
+	status, body := http.StatusOK, `{"rates":{}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	p := fx.NewHTTPProvider(srv.URL, "", srv.Client())
+	on := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	_, err := p.Rate(context.Background(), "EUR", "XXX", on)
+	require.Error(t, err)
+
+	status, body = http.StatusNotFound, `{"message":"not found"}`
+	_, err = p.Rate(context.Background(), "EUR", "AED", on)
+	require.ErrorContains(t, err, "404")
+
+	status, body = http.StatusOK, `not json`
+	_, err = p.Rate(context.Background(), "EUR", "AED", on)
+	require.Error(t, err)
+}
+
+func TestMockProviderRate(t *testing.T) {
+	m := &fx.MockProvider{}
+	on := time.Now()
+
+	rate, err := m.Rate(context.Background(), "USD", "AED", on)
+	require.NoError(t, err)
+	require.Equal(t, "3.6725", rate.String())
+
+	rate, err = m.Rate(context.Background(), "EUR", "USD", on)
+	require.NoError(t, err)
+	require.Equal(t, "1.25", rate.String())
+
+	_, err = m.Rate(context.Background(), "USD", "XXX", on)
+	require.Error(t, err)
+}
@@ -0,0 +1,44 @@
+package fx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// mockUSDRates are fixed units per US dollar used by MockProvider.
+var mockUSDRates = map[string]decimal.Decimal{
+	"USD": decimal.NewFromInt(1),
+	"EUR": decimal.RequireFromString("0.8"),
+	"GBP": decimal.RequireFromString("0.75"),
+	"AED": decimal.RequireFromString("3.6725"),
+	"SAR": decimal.RequireFromString("3.75"),
+	"KWD": decimal.RequireFromString("0.3"),
+	"EGP": decimal.NewFromInt(50),
+}
+
+// MockProvider converts between a handful of currencies at fixed rates that do not depend on
+// the day. Tests set Rates ("EUR/AED" -> rate) or Err to control the answers.
+type MockProvider struct {
+	Rates map[string]decimal.Decimal
+	Err   error
+}
+
+func (m *MockProvider) Rate(ctx context.Context, from, to string, on time.Time) (decimal.Decimal, error) {
+	if m.Err != nil {
+		return decimal.Zero, m.Err
+	}
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if rate, ok := m.Rates[from+"/"+to]; ok {
+		return rate, nil
+	}
+	fromUSD, okFrom := mockUSDRates[from]
+	toUSD, okTo := mockUSDRates[to]
+	if !okFrom || !okTo {
+		return decimal.Zero, fmt.Errorf("mock fx has no %s/%s rate", from, to)
+	}
+	return toUSD.DivRound(fromUSD, 8), nil
+}
@@ -0,0 +1,302 @@
+package imaging_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/platform/imaging"
+	"github.com/stretchr/testify/require"
+)
+
+func gradient(w, h int, alpha bool) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			a := uint8(255)
+			if alpha {
+				a = uint8((x * 7) % 256)
+			}
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 3), G: uint8(y * 5), B: uint8((x + y) % 7 * 30), A: a})
+		}
+	}
+	return img
+}
+
+func flat(w, h int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, gradient(8, 4, false)))
+	img, format, err := imaging.Decode(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "png", format)
+	require.Equal(t, image.Rect(0, 0, 8, 4), img.Bounds())
+
+	_, _, err = imaging.Decode([]byte("RIFF\x00\x00\x00\x00WEBPVP8 "))
+	require.ErrorIs(t, err, imaging.ErrUnsupportedFormat)
+}
+
+func TestFit(t *testing.T) {
+	t.Parallel()
+
+	src := gradient(400, 100, false)
+	require.Equal(t, image.Rect(0, 0, 200, 50), imaging.Fit(src, 200, 200).Bounds())
+	require.Equal(t, image.Rect(0, 0, 80, 20), imaging.Fit(src, 200, 20).Bounds())
+	require.Same(t, src, imaging.Fit(src, 400, 400), "images that fit are not resampled")
+
+	// a 2x2 checkerboard averages to grey
+	board := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	board.Set(0, 0, color.White)
+	board.Set(1, 1, color.White)
+	board.Set(1, 0, color.Black)
+	board.Set(0, 1, color.Black)
+	r, g, b, a := imaging.Fit(board, 1, 1).At(0, 0).RGBA()
+	require.Equal(t, []uint32{0x8080, 0x8080, 0x8080, 0xffff}, []uint32{r, g, b, a})
+}
+
+func TestEncodeJPEG(t *testing.T) {
+	t.Parallel()
+
+	out, err := imaging.EncodeJPEG(gradient(16, 16, true), 80)
+	require.NoError(t, err)
+	img, format, err := imaging.Decode(out)
+	require.NoError(t, err)
+	require.Equal(t, "jpeg", format)
+	require.Equal(t, image.Rect(0, 0, 16, 16), img.Bounds())
+}
+
+func TestEncodeWebP(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name string
+		img  image.Image
+	}{
+		{"opaque", gradient(37, 23, false)},
+		{"alpha", gradient(600, 3, true)},
+		{"single pixel", gradient(1, 1, false)},
+		{"flat", flat(9, 4, color.NRGBA{R: 10, G: 20, B: 30, A: 255})},
+		{"offset", gradient(12, 12, false).SubImage(image.Rect(3, 2, 10, 9))},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			img := tc.img
+			out, err := imaging.EncodeWebP(img)
+			require.NoError(t, err)
+			require.Equal(t, "RIFF", string(out[:4]))
+			require.Equal(t, uint32(len(out)-8), binary.LittleEndian.Uint32(out[4:8]))
+			require.Equal(t, "WEBPVP8L", string(out[8:16]))
+			require.Zero(t, len(out)%2)
+
+			got := decodeVP8L(t, out[20:20+binary.LittleEndian.Uint32(out[16:20])])
+			b := img.Bounds()
+			require.Equal(t, b.Dx(), got.Bounds().Dx())
+			require.Equal(t, b.Dy(), got.Bounds().Dy())
+			for y := 0; y < b.Dy(); y++ {
+				for x := 0; x < b.Dx(); x++ {
+					want := color.NRGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y))
+					require.Equal(t, want, got.NRGBAAt(x, y), "pixel %d,%d", x, y)
+				}
+			}
+		})
+	}
+
+	_, err := imaging.EncodeWebP(image.NewNRGBA(image.Rect(0, 0, 20000, 1)))
+	require.Error(t, err)
+}
+
+// decodeVP8L decodes the subset of VP8L the encoder writes: subtract green and predictor
+// transforms, literal pixels, no color cache or meta prefix codes.
+func decodeVP8L(t *testing.T, data []byte) *image.NRGBA {
+	r := &bitReader{t: t, data: data}
+	require.Equal(t, uint32(0x2f), r.read(8))
+	w, h := int(r.read(14))+1, int(r.read(14))+1
+	r.read(1)
+	require.Zero(t, r.read(3), "version")
+
+	var transforms []uint32
+	var modes []uint32
+	blockBits := 0
+	for r.read(1) == 1 {
+		kind := r.read(2)
+		transforms = append(transforms, kind)
+		switch kind {
+		case 2:
+		case 0:
+			blockBits = int(r.read(3)) + 2
+			n := func(v int) int { return (v + 1<<blockBits - 1) >> blockBits }
+			modes = readEntropyImage(r, n(w)*n(h), false)
+		default:
+			t.Fatalf("unexpected transform %d", kind)
+		}
+	}
+	argb := readEntropyImage(r, w*h, true)
+
+	for i := len(transforms) - 1; i >= 0; i-- {
+		switch transforms[i] {
+		case 0:
+			bw := (w + 1<<blockBits - 1) >> blockBits
+			for y := 0; y < h; y++ {
+				for x := 0; x < w; x++ {
+					var pred uint32
+					switch mode := modes[(y>>blockBits)*bw+x>>blockBits] >> 8 & 0xff; {
+					case x == 0 && y == 0:
+						pred = 0xff000000
+					case y == 0:
+						pred = argb[y*w+x-1]
+					case x == 0:
+						pred = argb[(y-1)*w]
+					case mode == 1:
+						pred = argb[y*w+x-1]
+					default:
+						t.Fatalf("unexpected predictor %d", mode)
+					}
+					argb[y*w+x] = addPixels(argb[y*w+x], pred)
+				}
+			}
+		case 2:
+			for i, p := range argb {
+				g := p >> 8 & 0xff
+				argb[i] = p&0xff00ff00 | ((p>>16+g)&0xff)<<16 | (p+g)&0xff
+			}
+		}
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for i, p := range argb {
+		img.Pix[i*4] = uint8(p >> 16)
+		img.Pix[i*4+1] = uint8(p >> 8)
+		img.Pix[i*4+2] = uint8(p)
+		img.Pix[i*4+3] = uint8(p >> 24)
+	}
+	return img
+}
+
+func addPixels(a, b uint32) uint32 {
+	var out uint32
+	for shift := 0; shift < 32; shift += 8 {
+		out |= ((a>>shift + b>>shift) & 0xff) << shift
+	}
+	return out
+}
+
+func readEntropyImage(r *bitReader, n int, main bool) []uint32 {
+	require.Zero(r.t, r.read(1), "color cache")
+	if main {
+		require.Zero(r.t, r.read(1), "meta prefix codes")
+	}
+	var codes [5]*testCode
+	for i, size := range []int{280, 256, 256, 256, 40} {
+		codes[i] = readCode(r, size)
+	}
+	pixels := make([]uint32, n)
+	for i := range pixels {
+		g := codes[0].read(r)
+		require.Less(r.t, g, 256, "backward references are not written")
+		red, blue, alpha := codes[1].read(r), codes[2].read(r), codes[3].read(r)
+		pixels[i] = uint32(alpha)<<24 | uint32(red)<<16 | uint32(g)<<8 | uint32(blue)
+	}
+	return pixels
+}
+
+// testCode maps canonical codes, as read most significant bit first, to symbols.
+type testCode struct {
+	symbols map[[2]int]int
+	single  int
+}
+
+func (c *testCode) read(r *bitReader) int {
+	if c.symbols == nil {
+		return c.single
+	}
+	code, length := 0, 0
+	for length < 16 {
+		code = code<<1 | int(r.read(1))
+		length++
+		if s, ok := c.symbols[[2]int{length, code}]; ok {
+			return s
+		}
+	}
+	r.t.Fatal("invalid prefix code")
+	return 0
+}
+
+func readCode(r *bitReader, size int) *testCode {
+	if r.read(1) == 1 {
+		require.Zero(r.t, r.read(1), "one symbol")
+		bits := uint(1)
+		if r.read(1) == 1 {
+			bits = 8
+		}
+		return &testCode{single: int(r.read(bits))}
+	}
+	order := []int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	lengthLengths := make([]int, 19)
+	for i, n := 0, int(r.read(4))+4; i < n; i++ {
+		lengthLengths[order[i]] = int(r.read(3))
+	}
+	lengthCode := canonical(r, lengthLengths)
+	require.Zero(r.t, r.read(1), "max symbol")
+	lengths := make([]int, size)
+	for i := range lengths {
+		l := lengthCode.read(r)
+		require.Less(r.t, l, 16, "repeat codes are not written")
+		lengths[i] = l
+	}
+	return canonical(r, lengths)
+}
+
+func canonical(r *bitReader, lengths []int) *testCode {
+	used, kraft := []int{}, 0
+	for s, l := range lengths {
+		if l > 0 {
+			used = append(used, s)
+			kraft += 1 << (15 - l)
+		}
+	}
+	if len(used) == 1 {
+		return &testCode{single: used[0]}
+	}
+	require.Equal(r.t, 1<<15, kraft, "prefix codes must be complete")
+	c := &testCode{symbols: map[[2]int]int{}}
+	code := 0
+	for l := 1; l <= 15; l++ {
+		for s, sl := range lengths {
+			if sl == l {
+				c.symbols[[2]int{l, code}] = s
+				code++
+			}
+		}
+		code <<= 1
+	}
+	return c
+}
+
+type bitReader struct {
+	t    *testing.T
+	data []byte
+	pos  uint
+}
+
+func (r *bitReader) read(n uint) uint32 {
+	var v uint32
+	for i := uint(0); i < n; i++ {
+		byteIdx := r.pos >> 3
+		require.Less(r.t, int(byteIdx), len(r.data), "read past the end")
+		v |= uint32(r.data[byteIdx]>>(r.pos&7)&1) << i
+		r.pos++
+	}
+	return v
+}
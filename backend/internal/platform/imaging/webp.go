@@ -0,0 +1,329 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"math/bits"
+	"sort"
+)
+
+// maxWebPDimension is the largest width or height a WebP image can have.
+const maxWebPDimension = 1 << 14
+
+// predictorBlockBits sizes the blocks of the predictor transform (1<<9 = 512 pixels square).
+// Every block uses the same predictor, so the smallest transform image is enough.
+const predictorBlockBits = 9
+
+// predictorLeft predicts each pixel from its left neighbour.
+const predictorLeft = 1
+
+// Prefix code alphabets of a VP8L image without backward references or color cache: green,
+// red, blue, alpha and distance.
+var alphabetSizes = [5]int{256 + 24, 256, 256, 256, 40}
+
+// codeLengthOrder is the order code length code lengths are written in.
+var codeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// EncodeWebP writes img as a lossless WebP (VP8L) image. Green is subtracted from red and
+// blue, each pixel is predicted from its left neighbour, and the residuals are Huffman coded.
+func EncodeWebP(img image.Image) ([]byte, error) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 || w > maxWebPDimension || h > maxWebPDimension {
+		return nil, fmt.Errorf("imaging: cannot encode a %dx%d image as WebP", w, h)
+	}
+	nrgba := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(nrgba, nrgba.Bounds(), img, b.Min, draw.Src)
+
+	argb := make([]uint32, w*h)
+	hasAlpha := false
+	for i := range argb {
+		p := nrgba.Pix[i*4 : i*4+4]
+		r, g, bl, a := uint32(p[0]), uint32(p[1]), uint32(p[2]), uint32(p[3])
+		hasAlpha = hasAlpha || a != 0xff
+		// subtract green
+		argb[i] = a<<24 | ((r-g)&0xff)<<16 | g<<8 | (bl-g)&0xff
+	}
+	residuals := make([]uint32, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			var pred uint32
+			switch {
+			case x == 0 && y == 0:
+				pred = 0xff000000
+			case x == 0:
+				pred = argb[i-w]
+			default:
+				pred = argb[i-1]
+			}
+			residuals[i] = subPixels(argb[i], pred)
+		}
+	}
+
+	bw := &bitWriter{}
+	bw.write(0x2f, 8)
+	bw.write(uint32(w-1), 14)
+	bw.write(uint32(h-1), 14)
+	if hasAlpha {
+		bw.write(1, 1)
+	} else {
+		bw.write(0, 1)
+	}
+	bw.write(0, 3)
+
+	// subtract green transform
+	bw.write(1, 1)
+	bw.write(2, 2)
+	// predictor transform with one mode for every block
+	bw.write(1, 1)
+	bw.write(0, 2)
+	bw.write(predictorBlockBits-2, 3)
+	blocks := func(n int) int { return (n + 1<<predictorBlockBits - 1) >> predictorBlockBits }
+	modes := make([]uint32, blocks(w)*blocks(h))
+	for i := range modes {
+		modes[i] = predictorLeft << 8
+	}
+	writeEntropyImage(bw, modes, false)
+	bw.write(0, 1)
+
+	writeEntropyImage(bw, residuals, true)
+	data := bw.bytes()
+
+	var out bytes.Buffer
+	pad := len(data) & 1
+	out.WriteString("RIFF")
+	_ = binary.Write(&out, binary.LittleEndian, uint32(4+8+len(data)+pad))
+	out.WriteString("WEBPVP8L")
+	_ = binary.Write(&out, binary.LittleEndian, uint32(len(data)))
+	out.Write(data)
+	if pad == 1 {
+		out.WriteByte(0)
+	}
+	return out.Bytes(), nil
+}
+
+// subPixels subtracts each channel of b from a, modulo 256.
+func subPixels(a, b uint32) uint32 {
+	alphaGreen := 0x00ff00ff + (a & 0xff00ff00) - (b & 0xff00ff00)
+	redBlue := 0xff00ff00 + (a & 0x00ff00ff) - (b & 0x00ff00ff)
+	return alphaGreen&0xff00ff00 | redBlue&0x00ff00ff
+}
+
+// writeEntropyImage writes pixels as literals with one prefix code per channel, without color
+// cache or backward references. Only the main image carries the meta prefix code flag.
+func writeEntropyImage(bw *bitWriter, pixels []uint32, main bool) {
+	bw.write(0, 1) // no color cache
+	if main {
+		bw.write(0, 1) // one prefix code group
+	}
+	var hists [5][]uint32
+	for i, n := range alphabetSizes {
+		hists[i] = make([]uint32, n)
+	}
+	for _, p := range pixels {
+		hists[0][p>>8&0xff]++
+		hists[1][p>>16&0xff]++
+		hists[2][p&0xff]++
+		hists[3][p>>24]++
+	}
+	var codes [5]*prefixCode
+	for i, hist := range hists {
+		codes[i] = writePrefixCode(bw, hist)
+	}
+	for _, p := range pixels {
+		codes[0].put(bw, int(p>>8&0xff))
+		codes[1].put(bw, int(p>>16&0xff))
+		codes[2].put(bw, int(p&0xff))
+		codes[3].put(bw, int(p>>24))
+	}
+}
+
+// prefixCode is a canonical Huffman code. Codes are stored bit-reversed, ready to be written
+// least significant bit first. A code with a single symbol takes no bits.
+type prefixCode struct {
+	lengths []uint8
+	codes   []uint16
+	single  bool
+}
+
+func (c *prefixCode) put(bw *bitWriter, symbol int) {
+	if !c.single {
+		bw.write(uint32(c.codes[symbol]), uint(c.lengths[symbol]))
+	}
+}
+
+// writePrefixCode writes the code for the symbol counts in hist and returns it. Codes of at
+// most one symbol use the simple form; the others are written as code lengths.
+func writePrefixCode(bw *bitWriter, hist []uint32) *prefixCode {
+	used := []int{}
+	for s, n := range hist {
+		if n > 0 {
+			used = append(used, s)
+		}
+	}
+	if len(used) <= 1 {
+		symbol := 0
+		if len(used) == 1 {
+			symbol = used[0]
+		}
+		bw.write(1, 1) // simple code
+		bw.write(0, 1) // one symbol
+		if symbol < 2 {
+			bw.write(0, 1)
+			bw.write(uint32(symbol), 1)
+		} else {
+			bw.write(1, 1)
+			bw.write(uint32(symbol), 8)
+		}
+		return &prefixCode{single: true}
+	}
+
+	bw.write(0, 1) // normal code
+	code := newPrefixCode(huffmanLengths(hist, 15))
+	lengthHist := make([]uint32, len(codeLengthOrder))
+	for _, l := range code.lengths {
+		lengthHist[l]++
+	}
+	lengthCode := newPrefixCode(huffmanLengths(lengthHist, 7))
+	bw.write(uint32(len(codeLengthOrder)-4), 4)
+	for _, s := range codeLengthOrder {
+		bw.write(uint32(lengthCode.lengths[s]), 3)
+	}
+	bw.write(0, 1) // lengths of every symbol follow
+	for _, l := range code.lengths {
+		lengthCode.put(bw, int(l))
+	}
+	return code
+}
+
+// newPrefixCode assigns canonical codes to the code lengths: shorter codes first, symbols in
+// order within a length.
+func newPrefixCode(lengths []uint8) *prefixCode {
+	c := &prefixCode{lengths: lengths, codes: make([]uint16, len(lengths))}
+	var count [16]int
+	used := 0
+	for _, l := range lengths {
+		if l > 0 {
+			count[l]++
+			used++
+		}
+	}
+	c.single = used == 1
+	var next [16]int
+	code := 0
+	for l := 1; l < len(next); l++ {
+		code = (code + count[l-1]) << 1
+		next[l] = code
+	}
+	next[0] = 0
+	for s, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		c.codes[s] = uint16(bits.Reverse16(uint16(next[l])) >> (16 - l))
+		next[l]++
+	}
+	return c
+}
+
+// huffmanLengths returns Huffman code lengths of at most limit bits for the symbol counts in
+// hist. Unused symbols get length 0; a lone symbol gets length 1.
+func huffmanLengths(hist []uint32, limit int) []uint8 {
+	lengths := make([]uint8, len(hist))
+	counts := make([]uint32, len(hist))
+	copy(counts, hist)
+	for minCount := uint32(1); ; minCount *= 2 {
+		if buildHuffmanLengths(counts, lengths) <= limit {
+			return lengths
+		}
+		// flatten the distribution until the tree is shallow enough
+		for s, n := range counts {
+			if n > 0 && n < minCount {
+				counts[s] = minCount
+			}
+		}
+	}
+}
+
+// buildHuffmanLengths fills lengths with the depth of each symbol in a Huffman tree of counts
+// and returns the deepest.
+func buildHuffmanLengths(counts []uint32, lengths []uint8) int {
+	type node struct {
+		weight      uint64
+		symbol      int
+		left, right int
+	}
+	nodes := []node{}
+	for s, n := range counts {
+		lengths[s] = 0
+		if n > 0 {
+			nodes = append(nodes, node{weight: uint64(n), symbol: s, left: -1, right: -1})
+		}
+	}
+	switch len(nodes) {
+	case 0:
+		return 0
+	case 1:
+		lengths[nodes[0].symbol] = 1
+		return 1
+	}
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].weight < nodes[j].weight })
+	leaves := len(nodes)
+	// two queues: leaves in weight order, then merged nodes in creation order
+	li, ni := 0, leaves
+	pick := func() int {
+		if li < leaves && (ni >= len(nodes) || nodes[li].weight <= nodes[ni].weight) {
+			li++
+			return li - 1
+		}
+		ni++
+		return ni - 1
+	}
+	for len(nodes) < 2*leaves-1 {
+		a, b := pick(), pick()
+		nodes = append(nodes, node{weight: nodes[a].weight + nodes[b].weight, symbol: -1, left: a, right: b})
+	}
+	deepest := 0
+	var walk func(i, depth int)
+	walk = func(i, depth int) {
+		n := nodes[i]
+		if n.left < 0 {
+			lengths[n.symbol] = uint8(min(depth, 255))
+			deepest = max(deepest, depth)
+			return
+		}
+		walk(n.left, depth+1)
+		walk(n.right, depth+1)
+	}
+	walk(len(nodes)-1, 0)
+	return deepest
+}
+
+// bitWriter packs values least significant bit first, as VP8L reads them.
+type bitWriter struct {
+	buf  []byte
+	acc  uint64
+	nacc uint
+}
+
+func (w *bitWriter) write(v uint32, n uint) {
+	w.acc |= uint64(v&(1<<n-1)) << w.nacc
+	w.nacc += n
+	for w.nacc >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.nacc -= 8
+	}
+}
+
+func (w *bitWriter) bytes() []byte {
+	if w.nacc > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc, w.nacc = 0, 0
+	}
+	return w.buf
+}
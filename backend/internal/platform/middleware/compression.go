@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+// NewCompressionMiddleware compresses responses with brotli or gzip based on the
+// client's Accept-Encoding header. Responses smaller than http.compression.min_bytes,
+// bodiless responses, and responses that already carry a Content-Encoding are sent as-is.
+// The whole middleware is a pass-through when http.compression.enabled is false.
+func NewCompressionMiddleware() gin.HandlerFunc {
+	enabled := viper.GetBool(config.HTTPCompressionEnabled)
+	minBytes := viper.GetInt(config.HTTPCompressionMinBytes)
+
+	return func(c *gin.Context) {
+		if !enabled || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		c.Header("Vary", "Accept-Encoding")
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		w := newBufferedWriter(c.Writer)
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		body := w.body.Bytes()
+		if !shouldCompress(w, len(body), minBytes) {
+			w.release(body)
+			return
+		}
+		compressed, err := compress(encoding, body)
+		if err != nil {
+			w.release(body)
+			return
+		}
+		header := w.Header()
+		header.Set("Content-Encoding", encoding)
+		header.Set("Content-Length", strconv.Itoa(len(compressed)))
+		w.release(compressed)
+	}
+}
+
+func shouldCompress(w *bufferedWriter, size, minBytes int) bool {
+	if size == 0 || size < minBytes {
+		return false
+	}
+	if w.status < http.StatusOK || w.status == http.StatusNoContent || w.status == http.StatusNotModified {
+		return false
+	}
+	return w.Header().Get("Content-Encoding") == ""
+}
+
+// negotiateEncoding picks the preferred supported encoding from an Accept-Encoding
+// header, honouring q=0 exclusions. Brotli wins over gzip when both are acceptable.
+func negotiateEncoding(acceptEncoding string) string {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		accepted[name] = q > 0
+	}
+	for _, enc := range []string{encodingBrotli, encodingGzip} {
+		if ok, listed := accepted[enc]; ok || (!listed && accepted["*"]) {
+			return enc
+		}
+	}
+	return ""
+}
+
+func compress(encoding string, body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch encoding {
+	case encodingBrotli:
+		bw := brotli.NewWriterLevel(&buf, brotli.DefaultCompression)
+		if _, err = bw.Write(body); err == nil {
+			err = bw.Close()
+		}
+	default:
+		gw := gzip.NewWriter(&buf)
+		if _, err = gw.Write(body); err == nil {
+			err = gw.Close()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -45,6 +45,9 @@ func NewCORSMiddleware() gin.HandlerFunc {
 
 // NewPublicCORSMiddleware creates CORS middleware for public endpoints.
 // Always allows all origins.
+// Storefront visitors may also send the coming-soon access code header.
 func NewPublicCORSMiddleware() gin.HandlerFunc {
-	return cors.New(corsConfigForOrigins([]string{"*"}))
+	cfg := corsConfigForOrigins([]string{"*"})
+	cfg.AllowHeaders = append(cfg.AllowHeaders, "X-Storefront-Access-Code")
+	return cors.New(cfg)
 }
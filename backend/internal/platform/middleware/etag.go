@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// NewETagMiddleware adds a weak ETag to successful GET responses and answers
+// 304 Not Modified when the client's If-None-Match already matches it.
+//
+// cacheControlKey is the config key holding the Cache-Control value for the route
+// group (e.g. config.HTTPCacheControlCatalog); an empty value leaves the header unset, and a
+// Cache-Control already set by the handler is kept.
+// The ETag is computed on the uncompressed body, so register this middleware after
+// NewCompressionMiddleware.
+func NewETagMiddleware(cacheControlKey string) gin.HandlerFunc {
+	cacheControl := viper.GetString(cacheControlKey)
+
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		w := newBufferedWriter(c.Writer)
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		body := w.body.Bytes()
+		if w.status != http.StatusOK {
+			w.release(body)
+			return
+		}
+
+		etag := weakETag(body)
+		header := w.Header()
+		header.Set("ETag", etag)
+		if cacheControl != "" && header.Get("Cache-Control") == "" {
+			header.Set("Cache-Control", cacheControl)
+		}
+		if etagMatches(c.GetHeader("If-None-Match"), etag) {
+			header.Del("Content-Type")
+			header.Del("Content-Length")
+			w.status = http.StatusNotModified
+			w.release(nil)
+			return
+		}
+		w.release(body)
+	}
+}
+
+func weakETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches applies the weak comparison required for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package middleware_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/abdelrahman146/kyora/internal/platform/middleware"
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func newCachedRouter(t *testing.T, payload string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	viper.Set(config.HTTPCompressionEnabled, true)
+	viper.Set(config.HTTPCompressionMinBytes, 64)
+	viper.Set(config.HTTPCacheControlCatalog, "private, no-cache")
+	t.Cleanup(func() {
+		viper.Set(config.HTTPCompressionEnabled, nil)
+		viper.Set(config.HTTPCompressionMinBytes, nil)
+		viper.Set(config.HTTPCacheControlCatalog, nil)
+	})
+
+	r := gin.New()
+	r.Use(middleware.NewCompressionMiddleware(), middleware.NewETagMiddleware(config.HTTPCacheControlCatalog))
+	r.GET("/items", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"payload": payload})
+	})
+	r.GET("/missing", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"payload": payload})
+	})
+	return r
+}
+
+func serve(r *gin.Engine, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCompression(t *testing.T) {
+	large := strings.Repeat("kyora ", 100)
+
+	tests := []struct {
+		name           string
+		payload        string
+		acceptEncoding string
+		wantEncoding   string
+	}{
+		{name: "brotli preferred", payload: large, acceptEncoding: "gzip, deflate, br", wantEncoding: "br"},
+		{name: "gzip", payload: large, acceptEncoding: "gzip", wantEncoding: "gzip"},
+		{name: "brotli excluded by q=0", payload: large, acceptEncoding: "br;q=0, gzip;q=0.8", wantEncoding: "gzip"},
+		{name: "wildcard", payload: large, acceptEncoding: "*", wantEncoding: "br"},
+		{name: "identity only", payload: large, acceptEncoding: "identity", wantEncoding: ""},
+		{name: "below min bytes", payload: "small", acceptEncoding: "gzip, br", wantEncoding: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newCachedRouter(t, tt.payload)
+			rec := serve(r, "/items", map[string]string{"Accept-Encoding": tt.acceptEncoding})
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Equal(t, tt.wantEncoding, rec.Header().Get("Content-Encoding"))
+			require.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+
+			var body io.Reader = rec.Body
+			switch tt.wantEncoding {
+			case "gzip":
+				gr, err := gzip.NewReader(rec.Body)
+				require.NoError(t, err)
+				body = gr
+			case "br":
+				body = brotli.NewReader(rec.Body)
+			}
+			raw, err := io.ReadAll(body)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"payload":"`+tt.payload+`"}`, string(raw))
+		})
+	}
+}
+
+func TestETag(t *testing.T) {
+	r := newCachedRouter(t, "catalog")
+
+	first := serve(r, "/items", nil)
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.True(t, strings.HasPrefix(etag, `W/"`), "expected weak etag, got %q", etag)
+	require.Equal(t, "private, no-cache", first.Header().Get("Cache-Control"))
+
+	notModified := serve(r, "/items", map[string]string{"If-None-Match": etag, "Accept-Encoding": "gzip"})
+	require.Equal(t, http.StatusNotModified, notModified.Code)
+	require.Empty(t, notModified.Body.Bytes())
+	require.Empty(t, notModified.Header().Get("Content-Encoding"))
+	require.Equal(t, etag, notModified.Header().Get("ETag"))
+
+	strong := serve(r, "/items", map[string]string{"If-None-Match": strings.TrimPrefix(etag, "W/")})
+	require.Equal(t, http.StatusNotModified, strong.Code)
+
+	stale := serve(r, "/items", map[string]string{"If-None-Match": `W/"stale"`})
+	require.Equal(t, http.StatusOK, stale.Code)
+	require.JSONEq(t, `{"payload":"catalog"}`, stale.Body.String())
+
+	missing := serve(r, "/missing", nil)
+	require.Equal(t, http.StatusNotFound, missing.Code)
+	require.Empty(t, missing.Header().Get("ETag"))
+	require.Empty(t, missing.Header().Get("Cache-Control"))
+}
@@ -0,0 +1,111 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// HTTPProvider sends receipts to an OCR service speaking a small JSON contract:
+//
+//	POST <endpoint> {"contentType": "image/jpeg", "content": "<base64>"}
+//	200 {"amount": "12.50", "vat": "0.60", "currency": "AED", "date": "2026-01-31", "vendor": "...", "text": "..."}
+//
+// Every response field is optional.
+type HTTPProvider struct {
+	endpoint   string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewHTTPProvider creates an HTTPProvider; apiKey is sent as a bearer token when set.
+func NewHTTPProvider(endpoint, apiKey string, httpClient *http.Client) *HTTPProvider {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &HTTPProvider{endpoint: endpoint, apiKey: apiKey, httpClient: httpClient}
+}
+
+type httpExtractRequest struct {
+	ContentType string `json:"contentType"`
+	Content     string `json:"content"`
+}
+
+type httpExtractResponse struct {
+	Amount   string `json:"amount"`
+	VAT      string `json:"vat"`
+	Currency string `json:"currency"`
+	Date     string `json:"date"`
+	Vendor   string `json:"vendor"`
+	Text     string `json:"text"`
+}
+
+func (p *HTTPProvider) ExtractReceipt(ctx context.Context, data []byte, contentType string) (*Receipt, error) {
+	payload, err := json.Marshal(httpExtractRequest{
+		ContentType: contentType,
+		Content:     base64.StdEncoding.EncodeToString(data),
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("ocr provider returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var out httpExtractResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("decode ocr response: %w", err)
+	}
+	receipt := &Receipt{
+		Currency: strings.ToUpper(strings.TrimSpace(out.Currency)),
+		Vendor:   strings.TrimSpace(out.Vendor),
+		RawText:  out.Text,
+	}
+	if a := strings.TrimSpace(out.Amount); a != "" {
+		amount, err := decimal.NewFromString(a)
+		if err != nil {
+			return nil, fmt.Errorf("decode ocr amount %q: %w", a, err)
+		}
+		receipt.Amount = amount.Abs()
+	}
+	if v := strings.TrimSpace(out.VAT); v != "" {
+		vat, err := decimal.NewFromString(v)
+		if err != nil {
+			return nil, fmt.Errorf("decode ocr vat %q: %w", v, err)
+		}
+		receipt.VAT = vat.Abs()
+	}
+	if d := strings.TrimSpace(out.Date); d != "" {
+		date, err := time.Parse("2006-01-02", d)
+		if err != nil {
+			return nil, fmt.Errorf("decode ocr date %q: %w", d, err)
+		}
+		receipt.Date = &date
+	}
+	return receipt, nil
+}
@@ -0,0 +1,65 @@
+package ocr_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/platform/ocr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPProviderExtractReceipt(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var in struct {
+			ContentType string `json:"contentType"`
+			Content     string `json:"content"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		require.Equal(t, "image/png", in.ContentType)
+		raw, err := base64.StdEncoding.DecodeString(in.Content)
+		require.NoError(t, err)
+		require.Equal(t, "receipt", string(raw))
+		_, _ = w.Write([]byte(`{"amount":"12.50","vat":"0.60","currency":"aed","date":"2026-01-31","vendor":" Carrefour ","text":"TOTAL 12.50"}`))
+	}))
+	defer srv.Close()
+
+	receipt, err := ocr.NewHTTPProvider(srv.URL, "secret", srv.Client()).ExtractReceipt(context.Background(), []byte("receipt"), "image/png")
+	require.NoError(t, err)
+	require.Equal(t, "12.5", receipt.Amount.String())
+	require.Equal(t, "0.6", receipt.VAT.String())
+	require.Equal(t, "AED", receipt.Currency)
+	require.NotNil(t, receipt.Date)
+	require.Equal(t, "2026-01-31", receipt.Date.Format("2006-01-02"))
+	require.Equal(t, "Carrefour", receipt.Vendor)
+	require.Equal(t, "TOTAL 12.50", receipt.RawText)
+}
+
+func TestHTTPProviderPartialAndErrors(t *testing.T) {
+	status, body := http.StatusOK, `{"vendor":"Kiosk"}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	p := ocr.NewHTTPProvider(srv.URL, "", srv.Client())
+
+	receipt, err := p.ExtractReceipt(context.Background(), []byte("x"), "image/jpeg")
+	require.NoError(t, err)
+	require.True(t, receipt.Amount.IsZero())
+	require.True(t, receipt.VAT.IsZero())
+	require.Nil(t, receipt.Date)
+	require.Equal(t, "Kiosk", receipt.Vendor)
+
+	body = `{"date":"31/01/2026"}`
+	_, err = p.ExtractReceipt(context.Background(), []byte("x"), "image/jpeg")
+	require.Error(t, err)
+
+	status, body = http.StatusBadGateway, "upstream down"
+	_, err = p.ExtractReceipt(context.Background(), []byte("x"), "image/jpeg")
+	require.ErrorContains(t, err, "502")
+}
@@ -0,0 +1,21 @@
+package ocr
+
+import "context"
+
+// MockProvider returns Receipt (or an empty receipt) for every image. Tests set Receipt to
+// control what intake extracts.
+type MockProvider struct {
+	Receipt *Receipt
+	Err     error
+}
+
+func (m *MockProvider) ExtractReceipt(ctx context.Context, data []byte, contentType string) (*Receipt, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if m.Receipt == nil {
+		return &Receipt{}, nil
+	}
+	r := *m.Receipt
+	return &r, nil
+}
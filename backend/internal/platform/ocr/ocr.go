@@ -0,0 +1,51 @@
+// Package ocr extracts expense details from receipt images.
+package ocr
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+// Provider extracts the details of a receipt from its image or PDF.
+type Provider interface {
+	ExtractReceipt(ctx context.Context, data []byte, contentType string) (*Receipt, error)
+}
+
+// Receipt holds what a provider could read off a receipt. Fields it could not read are
+// left zero.
+type Receipt struct {
+	Amount   decimal.Decimal
+	VAT      decimal.Decimal // Tax included in Amount.
+	Currency string
+	Date     *time.Time
+	Vendor   string
+	RawText  string
+}
+
+// New returns the Provider selected by ocr.provider, or nil when extraction is disabled:
+// 1) "" -> nil (receipts are stored without extracted fields)
+// 2) "mock" -> Mock
+// 3) "http" -> HTTP, posting to ocr.http.endpoint
+func New() (Provider, error) {
+	provider := strings.TrimSpace(viper.GetString(config.OCRProvider))
+	switch provider {
+	case "":
+		return nil, nil
+	case "mock":
+		return &MockProvider{}, nil
+	case "http":
+		endpoint := strings.TrimSpace(viper.GetString(config.OCRHTTPEndpoint))
+		if endpoint == "" {
+			return nil, errors.New("missing OCR endpoint: set ocr.http.endpoint")
+		}
+		return NewHTTPProvider(endpoint, viper.GetString(config.OCRHTTPAPIKey), nil), nil
+	default:
+		return nil, errors.New("unsupported OCR provider: " + provider)
+	}
+}
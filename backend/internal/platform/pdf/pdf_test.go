@@ -0,0 +1,100 @@
+package pdf_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"image"
+	"image/color"
+	"io"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/platform/pdf"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument_Bytes(t *testing.T) {
+	t.Parallel()
+
+	doc := pdf.New("Quote (Q-1)")
+	doc.Text(40, 60, pdf.Bold, 18, pdf.AlignLeft, "Quote Q-1")
+	doc.Line(40, 70, 555, 70)
+	doc.AddPage()
+	doc.Text(555, 60, pdf.Regular, 10, pdf.AlignRight, `Café (50% off) \ total`)
+	out := doc.Bytes()
+
+	require.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+	require.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	require.Equal(t, 2, doc.PageCount())
+	require.Contains(t, string(out), "/Count 2")
+	require.Contains(t, string(out), `(Caf`+"\xe9"+` \(50% off\) \\ total) Tj`)
+	require.Contains(t, string(out), `/Title (Quote \(Q-1\))`)
+
+	// startxref must point at the xref table.
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	require.NotNil(t, m)
+	off, err := strconv.Atoi(string(m[1]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(out[off:], []byte("xref\n")))
+}
+
+func TestDocument_Image(t *testing.T) {
+	t.Parallel()
+
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	img.Set(1, 0, color.NRGBA{}) // transparent, blended onto white
+
+	doc := pdf.New("Logo")
+	doc.Image(40, 40, 60, 30, img)
+	out := doc.Bytes()
+
+	require.Contains(t, string(out), "/Resources << /Font << /F1 3 0 R /F2 4 0 R >> /XObject << /Im1 8 0 R >> >>")
+	require.Contains(t, string(out), "q 60 0 0 30 40 771.89 cm /Im1 Do Q")
+	require.Contains(t, string(out), "/Subtype /Image /Width 2 /Height 1 /ColorSpace /DeviceRGB")
+
+	m := regexp.MustCompile(`(?s)/Subtype /Image [^>]*/Length (\d+) >>\nstream\n(.*)\nendstream\nendobj\nxref`).FindSubmatch(out)
+	require.NotNil(t, m)
+	n, err := strconv.Atoi(string(m[1]))
+	require.NoError(t, err)
+	require.Len(t, m[2], n)
+	zr, err := zlib.NewReader(bytes.NewReader(m[2]))
+	require.NoError(t, err)
+	samples, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	require.Equal(t, []byte{255, 0, 0, 255, 255, 255}, samples)
+}
+
+func TestDocument_UnsupportedRunes(t *testing.T) {
+	t.Parallel()
+
+	doc := pdf.New("")
+	doc.Text(40, 60, pdf.Regular, 10, pdf.AlignLeft, "أحمد A")
+	require.Contains(t, string(doc.Bytes()), "(???? A) Tj")
+}
+
+func TestTextWidthAndTruncate(t *testing.T) {
+	t.Parallel()
+
+	require.InDelta(t, 5.56, pdf.TextWidth("0", pdf.Regular, 10), 0.001)
+	require.Greater(t, pdf.TextWidth("Total", pdf.Bold, 10), pdf.TextWidth("Total", pdf.Regular, 10))
+
+	long := "A very long product name that will not fit"
+	got := pdf.Truncate(long, pdf.Regular, 10, 60)
+	require.LessOrEqual(t, pdf.TextWidth(got, pdf.Regular, 10), 60.0)
+	require.Regexp(t, `\.\.\.$`, got)
+	require.Equal(t, "short", pdf.Truncate("short", pdf.Regular, 10, 60))
+}
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	lines := pdf.Wrap("Payment due within 14 days.\nPrices include delivery to Cairo and Giza.", pdf.Regular, 10, 120)
+	require.Greater(t, len(lines), 2)
+	require.Equal(t, "Payment due within 14", lines[0])
+	for _, line := range lines {
+		require.LessOrEqual(t, pdf.TextWidth(line, pdf.Regular, 10), 120.0)
+	}
+	require.Equal(t, []string{""}, pdf.Wrap("", pdf.Regular, 10, 120))
+}
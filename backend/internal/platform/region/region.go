@@ -0,0 +1,79 @@
+// Package region resolves the data residency region a request's data lives in.
+//
+// Every workspace is stamped with a region at onboarding. The region travels in the request
+// context, and the database and blob layers pick the region's connection and bucket from it.
+// The home region is served by database.dsn and storage.*; extra regions are configured under
+// region.regions.<name>.
+package region
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/abdelrahman146/kyora/internal/platform/types/ctxkey"
+	"github.com/spf13/viper"
+)
+
+var regionKey = ctxkey.New("region")
+
+// Home returns the name of the region served by the main database and storage bucket.
+func Home() string {
+	home := normalize(viper.GetString(config.RegionHome))
+	if home == "" {
+		return "default"
+	}
+	return home
+}
+
+// Configured returns the home region followed by the extra configured regions, sorted.
+func Configured() []string {
+	home := Home()
+	regions := []string{home}
+	extra := make([]string, 0)
+	for name := range viper.GetStringMap(config.RegionRegions) {
+		if name = normalize(name); name != "" && name != home {
+			extra = append(extra, name)
+		}
+	}
+	slices.Sort(extra)
+	return append(regions, extra...)
+}
+
+// IsConfigured reports whether name is the home region or an extra configured region.
+// An empty name stands for the home region.
+func IsConfigured(name string) bool {
+	return slices.Contains(Configured(), Resolve(name))
+}
+
+// Resolve normalizes a region name, mapping the empty name (workspaces created before
+// regions existed) to the home region.
+func Resolve(name string) string {
+	if name = normalize(name); name != "" {
+		return name
+	}
+	return Home()
+}
+
+// Setting returns a per-region setting of an extra region, e.g. Setting("ksa", "database_dsn").
+func Setting(name, key string) string {
+	return strings.TrimSpace(viper.GetString(config.RegionRegions + "." + normalize(name) + "." + key))
+}
+
+// WithRegion returns a copy of ctx whose data lives in the given region.
+func WithRegion(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, regionKey, Resolve(name))
+}
+
+// FromContext returns the region of ctx, or the home region when none was set.
+func FromContext(ctx context.Context) string {
+	if name, ok := ctx.Value(regionKey).(string); ok && name != "" {
+		return name
+	}
+	return Home()
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,51 @@
+package region_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/abdelrahman146/kyora/internal/platform/region"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func withRegions(t *testing.T) {
+	t.Helper()
+	viper.Set(config.RegionHome, "EU")
+	viper.Set(config.RegionRegions, map[string]any{
+		"ksa": map[string]any{"database_dsn": " postgres://ksa ", "storage_bucket": "kyora-ksa"},
+		"uae": map[string]any{"database_dsn": "postgres://uae"},
+		"eu":  map[string]any{"database_dsn": "ignored"},
+	})
+	t.Cleanup(func() {
+		viper.Set(config.RegionHome, nil)
+		viper.Set(config.RegionRegions, nil)
+	})
+}
+
+func TestConfigured(t *testing.T) {
+	withRegions(t)
+
+	require.Equal(t, "eu", region.Home())
+	require.Equal(t, []string{"eu", "ksa", "uae"}, region.Configured())
+	require.True(t, region.IsConfigured(""))
+	require.True(t, region.IsConfigured(" KSA "))
+	require.False(t, region.IsConfigured("us"))
+	require.Equal(t, "postgres://ksa", region.Setting("KSA", "database_dsn"))
+	require.Empty(t, region.Setting("uae", "storage_bucket"))
+}
+
+func TestConfiguredWithoutRegions(t *testing.T) {
+	require.Equal(t, "default", region.Home())
+	require.Equal(t, []string{"default"}, region.Configured())
+}
+
+func TestContext(t *testing.T) {
+	withRegions(t)
+
+	ctx := context.Background()
+	require.Equal(t, "eu", region.FromContext(ctx))
+	require.Equal(t, "ksa", region.FromContext(region.WithRegion(ctx, "KSA")))
+	require.Equal(t, "eu", region.FromContext(region.WithRegion(ctx, "")), "workspaces without a region live in the home region")
+}
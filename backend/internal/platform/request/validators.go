@@ -0,0 +1,65 @@
+package request
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+)
+
+// RegisterValidators adds the decimal-aware binding tags to gin's validator:
+//
+//   - dgte=N: the decimal must be greater than or equal to N
+//   - dgt=N:  the decimal must be strictly greater than N
+//
+// Both work on decimal.Decimal and decimal.NullDecimal. A decimal that was never set
+// (zero struct, or NullDecimal with Valid=false) counts as empty, so "required" and
+// "omitempty" behave as they do for other types. Call it once before serving requests.
+func RegisterValidators() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected binding validator engine")
+	}
+	v.RegisterCustomTypeFunc(decimalValue, decimal.Decimal{}, decimal.NullDecimal{})
+	if err := v.RegisterValidation("dgte", decimalCompare(func(d, bound decimal.Decimal) bool {
+		return d.GreaterThanOrEqual(bound)
+	})); err != nil {
+		return err
+	}
+	return v.RegisterValidation("dgt", decimalCompare(func(d, bound decimal.Decimal) bool {
+		return d.GreaterThan(bound)
+	}))
+}
+
+// decimalValue exposes decimals to the validator as their string form, or nil when unset.
+func decimalValue(field reflect.Value) any {
+	switch d := field.Interface().(type) {
+	case decimal.Decimal:
+		if field.IsZero() {
+			return nil
+		}
+		return d.String()
+	case decimal.NullDecimal:
+		if !d.Valid {
+			return nil
+		}
+		return d.Decimal.String()
+	}
+	return nil
+}
+
+func decimalCompare(cmp func(d, bound decimal.Decimal) bool) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		bound, err := decimal.NewFromString(fl.Param())
+		if err != nil {
+			return false
+		}
+		d, err := decimal.NewFromString(fl.Field().String())
+		if err != nil {
+			return false
+		}
+		return cmp(d, bound)
+	}
+}
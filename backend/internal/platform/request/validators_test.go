@@ -0,0 +1,46 @@
+package request_test
+
+import (
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/platform/request"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+type decimalPayload struct {
+	Price    decimal.Decimal     `binding:"required,dgt=0"`
+	Discount decimal.Decimal     `binding:"omitempty,dgte=0"`
+	Fee      decimal.NullDecimal `binding:"omitempty,dgte=0"`
+}
+
+func TestDecimalValidators(t *testing.T) {
+	require.NoError(t, request.RegisterValidators())
+
+	valid := decimal.NewFromInt(10)
+	tests := []struct {
+		name    string
+		payload decimalPayload
+		wantErr bool
+	}{
+		{name: "valid", payload: decimalPayload{Price: valid, Discount: decimal.Zero, Fee: decimal.NewNullDecimal(decimal.Zero)}},
+		{name: "unset optional fields", payload: decimalPayload{Price: valid}},
+		{name: "missing required", payload: decimalPayload{}, wantErr: true},
+		{name: "zero price", payload: decimalPayload{Price: decimal.Zero}, wantErr: true},
+		{name: "negative price", payload: decimalPayload{Price: decimal.NewFromInt(-1)}, wantErr: true},
+		{name: "negative discount", payload: decimalPayload{Price: valid, Discount: decimal.RequireFromString("-0.01")}, wantErr: true},
+		{name: "negative null decimal", payload: decimalPayload{Price: valid, Fee: decimal.NewNullDecimal(decimal.NewFromInt(-5))}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := binding.Validator.ValidateStruct(&tt.payload)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
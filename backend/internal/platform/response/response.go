@@ -2,6 +2,7 @@ package response
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/abdelrahman146/kyora/internal/platform/database"
 	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
@@ -19,6 +20,8 @@ func Error(c *gin.Context, err error) {
 			p = problem.NotFound("resource not found").WithError(err).WithCode("resource.not_found")
 		} else if database.IsUniqueViolation(err) {
 			p = problem.Conflict("resource already exists").WithError(err).WithCode("resource.conflict")
+		} else if database.IsVersionConflict(err) {
+			p = problem.Conflict("resource was modified by another request").WithError(err).WithCode("resource.version_conflict")
 		} else {
 			p = problem.InternalError().WithError(err).WithCode("generic.internal")
 		}
@@ -41,3 +44,15 @@ func SuccessEmpty(c *gin.Context, status int) {
 func SuccessText(c *gin.Context, status int, data string) {
 	c.String(status, data)
 }
+
+// SuccessFile writes data as a downloadable attachment named filename.
+func SuccessFile(c *gin.Context, status int, contentType, filename string, data []byte) {
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(status, contentType, data)
+}
+
+// SuccessData writes data inline with the given content type, e.g. a page meant to be
+// displayed or printed by the browser.
+func SuccessData(c *gin.Context, status int, contentType string, data []byte) {
+	c.Data(status, contentType, data)
+}
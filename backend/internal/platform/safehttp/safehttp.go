@@ -0,0 +1,83 @@
+// Package safehttp provides HTTP clients for requests to addresses supplied by users, such as
+// store APIs and identity providers. The clients only connect to public addresses, so a user
+// cannot make the server reach hosts on its own network.
+package safehttp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// ErrDisallowedAddress is returned when a request would connect to an address the client
+// refuses: a private, loopback, link-local or otherwise non-public IP, or a port outside
+// the allowed ones.
+var ErrDisallowedAddress = errors.New("address not allowed")
+
+// maxRedirects bounds the redirects a client follows, like the net/http default.
+const maxRedirects = 10
+
+// NewClient returns a client that only connects to public IPs, on the given ports (any port
+// when none is given). The check runs when each connection is made, after DNS resolution,
+// so a host name resolving to an internal address and a redirect to one are refused too.
+// Proxies from the environment are ignored and redirects must stay on https.
+func NewClient(timeout time.Duration, ports ...string) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   control(ports),
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if req.URL.Scheme != "https" {
+				return fmt.Errorf("%w: redirect to %s", ErrDisallowedAddress, req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+}
+
+// control vets the resolved address of each connection before it is made.
+func control(ports []string) func(network, address string, c syscall.RawConn) error {
+	return func(network, address string, _ syscall.RawConn) error {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		if len(ports) > 0 && !slices.Contains(ports, port) {
+			return fmt.Errorf("%w: port %s", ErrDisallowedAddress, port)
+		}
+		if !IsPublicIP(net.ParseIP(host)) {
+			return fmt.Errorf("%w: %s", ErrDisallowedAddress, host)
+		}
+		return nil
+	}
+}
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which net.IP does not
+// classify as private.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPublicIP reports whether ip is a global unicast address outside the private, loopback,
+// link-local and shared address ranges.
+func IsPublicIP(ip net.IP) bool {
+	if ip == nil || !ip.IsGlobalUnicast() {
+		return false
+	}
+	if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() {
+		return false
+	}
+	return !sharedAddressSpace.Contains(ip)
+}
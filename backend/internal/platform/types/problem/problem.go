@@ -144,3 +144,9 @@ func PayloadTooLarge(detail string) *Problem {
 		Status: http.StatusRequestEntityTooLarge, Title: "Payload Too Large", Detail: detail, Type: aboutBlank,
 	}
 }
+
+func PreconditionRequired(detail string) *Problem {
+	return &Problem{
+		Status: http.StatusPreconditionRequired, Title: "Precondition Required", Detail: detail, Type: aboutBlank,
+	}
+}
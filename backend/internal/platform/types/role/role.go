@@ -3,6 +3,7 @@ package role
 import (
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/abdelrahman146/kyora/internal/platform/types/problem"
 )
@@ -27,6 +28,9 @@ var RolePermissions = map[Role][]string{
 		"view:accounting",
 		"view:basic_analytics",
 		"view:basic_financial_reports",
+		"view:financials",
+		"view:task",
+		"manage:task",
 	},
 	RoleAdmin: {
 		"view:account",
@@ -41,12 +45,48 @@ var RolePermissions = map[Role][]string{
 		"manage:order",
 		"view:inventory",
 		"manage:inventory",
+		"approve:inventory",
 		"view:expense",
 		"manage:expense",
 		"view:accounting",
 		"manage:accounting",
+		"approve:accounting",
 		"view:basic_analytics",
 		"view:basic_financial_reports",
+		"view:financials",
+		"view:task",
+		"manage:task",
+	},
+}
+
+// Flag adjusts the static permissions of a RoleUser member. Admins always keep their full role.
+type Flag string
+
+const (
+	// FlagManageOperations lets a member create and update orders and inventory.
+	FlagManageOperations Flag = "manage_operations"
+	// FlagRestrictFinancials hides costs, profit, expenses and accounting from a member.
+	FlagRestrictFinancials Flag = "restrict_financials"
+)
+
+// FlagGrants are the permissions a flag adds to the member's role.
+var FlagGrants = map[Flag][]string{
+	FlagManageOperations: {
+		"manage:order",
+		"manage:inventory",
+	},
+}
+
+// FlagRevocations are the permissions a flag removes from the member's role.
+var FlagRevocations = map[Flag][]string{
+	FlagRestrictFinancials: {
+		"view:financials",
+		"view:expense",
+		"manage:expense",
+		"view:accounting",
+		"manage:accounting",
+		"view:basic_financial_reports",
+		"view:advanced_financial_reports",
 	},
 }
 
@@ -55,6 +95,9 @@ type Action string
 const (
 	ActionManage Action = "manage"
 	ActionView   Action = "view"
+	// ActionApprove signs off on changes others prepared, e.g. stocktakes or withdrawals past
+	// the safe-to-draw amount. It is granted separately from manage.
+	ActionApprove Action = "approve"
 )
 
 type Resource string
@@ -78,20 +121,80 @@ const (
 	ResourceExportAnalyticsData      Resource = "export_analytics_data"
 	ResourceDataImport               Resource = "data_import"
 	ResourceDataExport               Resource = "data_export"
+	ResourceTask                     Resource = "task"
+	// ResourceFinancials covers cost and profit figures embedded in otherwise operational
+	// responses (e.g. order COGS and item costs).
+	ResourceFinancials Resource = "financials"
 )
 
-func (r Role) HasPermission(action Action, resource Resource) error {
-	permissions, ok := RolePermissions[r]
+// PermissionMatrix lists, per domain, the actions a custom role can be granted.
+var PermissionMatrix = map[Resource][]Action{
+	ResourceAccount:          {ActionView, ActionManage},
+	ResourceBilling:          {ActionView, ActionManage},
+	ResourceBusiness:         {ActionView, ActionManage},
+	ResourceCustomer:         {ActionView, ActionManage},
+	ResourceOrder:            {ActionView, ActionManage},
+	ResourceInventory:        {ActionView, ActionManage, ActionApprove},
+	ResourceExpense:          {ActionView, ActionManage},
+	ResourceAccounting:       {ActionView, ActionManage, ActionApprove},
+	ResourceBasicAnalytics:   {ActionView},
+	ResourceFinancialReports: {ActionView},
+	ResourceFinancials:       {ActionView},
+	ResourceTask:             {ActionView, ActionManage},
+}
+
+// Permission returns the "action:resource" form permissions are stored in.
+func Permission(action Action, resource Resource) string {
+	return string(action) + ":" + string(resource)
+}
+
+// IsGrantable reports whether a permission is part of the PermissionMatrix.
+func IsGrantable(permission string) bool {
+	action, resource, ok := strings.Cut(permission, ":")
 	if !ok {
-		return UnauthorizedError(action, resource)
+		return false
+	}
+	return slices.Contains(PermissionMatrix[Resource(resource)], Action(action))
+}
+
+// Authorize checks a permission against a set of permissions, applying the member's flags on
+// top: revocations win over grants. It is the one check behind every role, custom role and
+// flag combination.
+func Authorize(permissions []string, flags []Flag, action Action, resource Resource) error {
+	permissionToCheck := Permission(action, resource)
+	for _, f := range flags {
+		if slices.Contains(FlagRevocations[f], permissionToCheck) {
+			return UnauthorizedError(action, resource)
+		}
+	}
+	for _, f := range flags {
+		if slices.Contains(FlagGrants[f], permissionToCheck) {
+			return nil
+		}
 	}
-	permissionToCheck := string(action) + ":" + string(resource)
 	if !slices.Contains(permissions, permissionToCheck) {
 		return UnauthorizedError(action, resource)
 	}
 	return nil
 }
 
+func (r Role) HasPermission(action Action, resource Resource) error {
+	permissions, ok := RolePermissions[r]
+	if !ok {
+		return UnauthorizedError(action, resource)
+	}
+	return Authorize(permissions, nil, action, resource)
+}
+
+// HasPermissionWithFlags checks a permission after applying the member's flags. Revocations
+// win over grants, and flags are ignored for admins.
+func (r Role) HasPermissionWithFlags(action Action, resource Resource, flags []Flag) error {
+	if r != RoleUser {
+		return r.HasPermission(action, resource)
+	}
+	return Authorize(RolePermissions[r], flags, action, resource)
+}
+
 func UnauthorizedError(action Action, resource Resource) error {
 	return problem.Forbidden(fmt.Sprintf("unauthorized to %s %s", action, resource)).
 		With("action", action).
@@ -0,0 +1,75 @@
+package role_test
+
+import (
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasPermissionWithFlags(t *testing.T) {
+	cases := []struct {
+		name     string
+		role     role.Role
+		flags    []role.Flag
+		action   role.Action
+		resource role.Resource
+		allowed  bool
+	}{
+		{"member without flags keeps role permissions", role.RoleUser, nil, role.ActionView, role.ResourceAccounting, true},
+		{"member cannot manage orders by default", role.RoleUser, nil, role.ActionManage, role.ResourceOrder, false},
+		{"manage operations grants orders", role.RoleUser, []role.Flag{role.FlagManageOperations}, role.ActionManage, role.ResourceOrder, true},
+		{"manage operations grants inventory", role.RoleUser, []role.Flag{role.FlagManageOperations}, role.ActionManage, role.ResourceInventory, true},
+		{"manage operations does not grant billing", role.RoleUser, []role.Flag{role.FlagManageOperations}, role.ActionManage, role.ResourceBilling, false},
+		{"restrict financials revokes accounting", role.RoleUser, []role.Flag{role.FlagRestrictFinancials}, role.ActionView, role.ResourceAccounting, false},
+		{"restrict financials revokes financial fields", role.RoleUser, []role.Flag{role.FlagManageOperations, role.FlagRestrictFinancials}, role.ActionView, role.ResourceFinancials, false},
+		{"restrict financials keeps orders", role.RoleUser, []role.Flag{role.FlagRestrictFinancials}, role.ActionView, role.ResourceOrder, true},
+		{"flags are ignored for admins", role.RoleAdmin, []role.Flag{role.FlagRestrictFinancials}, role.ActionView, role.ResourceAccounting, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.role.HasPermissionWithFlags(tc.action, tc.resource, tc.flags)
+			if tc.allowed {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestAuthorize_CustomPermissions(t *testing.T) {
+	permissions := []string{"view:order", "manage:order", "view:accounting", "approve:accounting"}
+	cases := []struct {
+		name     string
+		flags    []role.Flag
+		action   role.Action
+		resource role.Resource
+		allowed  bool
+	}{
+		{"granted permission", nil, role.ActionManage, role.ResourceOrder, true},
+		{"approve is granted on its own", nil, role.ActionApprove, role.ResourceAccounting, true},
+		{"manage does not imply approve", nil, role.ActionApprove, role.ResourceOrder, false},
+		{"missing permission", nil, role.ActionView, role.ResourceInventory, false},
+		{"flags grant on top", []role.Flag{role.FlagManageOperations}, role.ActionManage, role.ResourceInventory, true},
+		{"flags revoke granted permissions", []role.Flag{role.FlagRestrictFinancials}, role.ActionView, role.ResourceAccounting, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := role.Authorize(permissions, tc.flags, tc.action, tc.resource)
+			if tc.allowed {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestIsGrantable(t *testing.T) {
+	require.True(t, role.IsGrantable("approve:inventory"))
+	require.True(t, role.IsGrantable(role.Permission(role.ActionView, role.ResourceFinancials)))
+	require.False(t, role.IsGrantable("approve:order"))
+	require.False(t, role.IsGrantable("view:ai_business_assistant"))
+	require.False(t, role.IsGrantable("manage"))
+}
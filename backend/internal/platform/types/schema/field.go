@@ -20,6 +20,16 @@ func NewField(dbField, jsonField string) Field {
 	}
 }
 
+// Columns returns the table-qualified column names of the given fields,
+// suitable for explicit SELECT lists on queries that join other tables.
+func Columns(table string, fields ...Field) []string {
+	cols := make([]string, 0, len(fields))
+	for _, f := range fields {
+		cols = append(cols, table+"."+f.column)
+	}
+	return cols
+}
+
 var (
 	CountField = NewField("COUNT(*) as count", "count")
 )
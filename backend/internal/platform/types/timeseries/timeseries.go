@@ -54,6 +54,58 @@ func (g Granularity) Bucket() string {
 	}
 }
 
+// Calendar is the reporting calendar a series is bucketed in: the time zone of its buckets and
+// the month the fiscal year starts in, which quarterly and yearly buckets follow. The zero
+// Calendar is UTC with fiscal years that match calendar years.
+type Calendar struct {
+	Location        *time.Location
+	FiscalYearStart time.Month
+}
+
+// Loc returns the calendar's time zone, UTC when unset.
+func (c Calendar) Loc() *time.Location {
+	if c.Location == nil {
+		return time.UTC
+	}
+	return c.Location
+}
+
+// FiscalOffset is how many months after January the fiscal year starts.
+func (c Calendar) FiscalOffset() int {
+	if c.FiscalYearStart < time.January || c.FiscalYearStart > time.December {
+		return 0
+	}
+	return int(c.FiscalYearStart) - 1
+}
+
+// FiscalYearStartOf returns the local midnight the fiscal year containing t starts at.
+func (c Calendar) FiscalYearStartOf(t time.Time) time.Time {
+	year, _ := c.fiscalQuarterOf(t.In(c.Loc()))
+	return time.Date(year, time.Month(1+c.FiscalOffset()), 1, 0, 0, 0, 0, c.Loc())
+}
+
+// FiscalQuarterStartOf returns the local midnight the fiscal quarter containing t starts at.
+func (c Calendar) FiscalQuarterStartOf(t time.Time) time.Time {
+	year, q := c.fiscalQuarterOf(t.In(c.Loc()))
+	return time.Date(year, time.Month(1+c.FiscalOffset()+(q-1)*3), 1, 0, 0, 0, 0, c.Loc())
+}
+
+// fiscalQuarterOf returns the fiscal year t falls in, named by the calendar year it starts
+// in, and the quarter of that year.
+func (c Calendar) fiscalQuarterOf(t time.Time) (year int, q int) {
+	shifted := t.AddDate(0, -c.FiscalOffset(), 1-t.Day())
+	return quarterOf(shifted)
+}
+
+// fiscalYearLabel names the fiscal year starting in year: the year itself when fiscal years
+// are calendar years, FY2025/26 otherwise.
+func (c Calendar) fiscalYearLabel(year int) string {
+	if c.FiscalOffset() == 0 {
+		return fmt.Sprintf("%d", year)
+	}
+	return fmt.Sprintf("FY%d/%02d", year, (year+1)%100)
+}
+
 type TimeSeries struct {
 	Granularity Granularity     `json:"granularity"`
 	Series      []TimeSeriesRow `json:"series"`
@@ -69,16 +121,17 @@ type TimeSeriesRow struct {
 // New creates a time series with a sensible granularity based on the span
 // between 'from' and 'to'. It aligns the start to a natural boundary and fills in
 // human-friendly tick labels and corresponding values. Missing data points are
-// filled with zero values. It assumes Monday as the start of the week.
+// filled with zero values. It assumes Monday as the start of the week. Quarters and
+// years follow the fiscal year of cal.
 //
 // Examples of outputs by span:
 //   - <= 48h: "15:00 2 Jan", "16:00 2 Jan", ... (Hourly)
 //   - <= 45d: "Mon 2 Jan", "Tue 3 Jan", ... (Daily)
 //   - <= 120d: "Wk2 Jan 2025", ... (Weekly; week-of-month)
 //   - <= 2y:  "Jan 2025", "Feb 2025", ... (Monthly)
-//   - <= 5y:  "Q1 2025", "Q2 2025", ... (Quarterly)
-//   - > 5y:   "2025", "2026", ... (Yearly)
-func New(rows []TimeSeriesRow, granularity Granularity) *TimeSeries {
+//   - <= 5y:  "Q1 2025", "Q2 2025", ... (Quarterly; "Q1 FY2025/26" off calendar years)
+//   - > 5y:   "2025", "2026", ... (Yearly; "FY2025/26" off calendar years)
+func New(rows []TimeSeriesRow, granularity Granularity, cal Calendar) *TimeSeries {
 	timeSeries := &TimeSeries{
 		Granularity: granularity,
 	}
@@ -89,13 +142,21 @@ func New(rows []TimeSeriesRow, granularity Granularity) *TimeSeries {
 	for _, r := range rows {
 		timeSeries.Series = append(timeSeries.Series, TimeSeriesRow{
 			Timestamp: r.Timestamp,
-			Label:     formatLabel(r.Timestamp, granularity, includeYear),
+			Label:     formatLabel(r.Timestamp, granularity, includeYear, cal),
 			Value:     r.Value,
 		})
 	}
 	return timeSeries
 }
 
+// InLocation converts row timestamps to loc so labels reflect local wall-clock time.
+func InLocation(rows []TimeSeriesRow, loc *time.Location) []TimeSeriesRow {
+	for i := range rows {
+		rows[i].Timestamp = rows[i].Timestamp.In(loc)
+	}
+	return rows
+}
+
 // --- helpers ---
 
 func GetTimeGranularityByDateRange(from, to time.Time) Granularity {
@@ -116,7 +177,7 @@ func GetTimeGranularityByDateRange(from, to time.Time) Granularity {
 	}
 }
 
-func formatLabel(t time.Time, g Granularity, includeYear bool) string {
+func formatLabel(t time.Time, g Granularity, includeYear bool, cal Calendar) string {
 	switch g {
 	case Hourly:
 		// Always include day+month to avoid ambiguity across days
@@ -140,10 +201,11 @@ func formatLabel(t time.Time, g Granularity, includeYear bool) string {
 		}
 		return t.Format("Jan")
 	case Quarterly:
-		_, q := quarterOf(t)
-		return fmt.Sprintf("Q%d %d", q, t.Year())
+		year, q := cal.fiscalQuarterOf(t)
+		return fmt.Sprintf("Q%d %s", q, cal.fiscalYearLabel(year))
 	case Yearly:
-		return t.Format("2006")
+		year, _ := cal.fiscalQuarterOf(t)
+		return cal.fiscalYearLabel(year)
 	default:
 		return t.String()
 	}
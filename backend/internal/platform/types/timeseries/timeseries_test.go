@@ -0,0 +1,82 @@
+package timeseries_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/timeseries"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendar_FiscalPeriodStarts(t *testing.T) {
+	t.Parallel()
+
+	dubai, err := time.LoadLocation("Asia/Dubai")
+	require.NoError(t, err)
+	april := timeseries.Calendar{Location: dubai, FiscalYearStart: time.April}
+
+	tests := []struct {
+		name        string
+		cal         timeseries.Calendar
+		at          time.Time
+		wantYear    time.Time
+		wantQuarter time.Time
+	}{
+		{
+			name:        "calendar_year",
+			cal:         timeseries.Calendar{},
+			at:          time.Date(2025, 5, 20, 10, 0, 0, 0, time.UTC),
+			wantYear:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantQuarter: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "before_fiscal_start",
+			cal:         april,
+			at:          time.Date(2025, 3, 31, 12, 0, 0, 0, dubai),
+			wantYear:    time.Date(2024, 4, 1, 0, 0, 0, 0, dubai),
+			wantQuarter: time.Date(2025, 1, 1, 0, 0, 0, 0, dubai),
+		},
+		{
+			name:        "local_day_ahead_of_utc",
+			cal:         april,
+			at:          time.Date(2025, 3, 31, 21, 0, 0, 0, time.UTC), // 1 April in Dubai
+			wantYear:    time.Date(2025, 4, 1, 0, 0, 0, 0, dubai),
+			wantQuarter: time.Date(2025, 4, 1, 0, 0, 0, 0, dubai),
+		},
+		{
+			name:        "last_fiscal_quarter",
+			cal:         timeseries.Calendar{FiscalYearStart: time.July},
+			at:          time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC),
+			wantYear:    time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+			wantQuarter: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.True(t, tt.cal.FiscalYearStartOf(tt.at).Equal(tt.wantYear), "year start %s", tt.cal.FiscalYearStartOf(tt.at))
+			require.True(t, tt.cal.FiscalQuarterStartOf(tt.at).Equal(tt.wantQuarter), "quarter start %s", tt.cal.FiscalQuarterStartOf(tt.at))
+		})
+	}
+}
+
+func TestNew_FiscalLabels(t *testing.T) {
+	t.Parallel()
+
+	rows := []timeseries.TimeSeriesRow{
+		{Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Value: 1},
+		{Timestamp: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), Value: 2},
+	}
+
+	calendar := timeseries.New(rows, timeseries.Quarterly, timeseries.Calendar{})
+	require.Equal(t, "Q1 2025", calendar.Series[0].Label)
+	require.Equal(t, "Q2 2025", calendar.Series[1].Label)
+
+	fiscal := timeseries.New(rows, timeseries.Quarterly, timeseries.Calendar{FiscalYearStart: time.April})
+	require.Equal(t, "Q4 FY2024/25", fiscal.Series[0].Label)
+	require.Equal(t, "Q1 FY2025/26", fiscal.Series[1].Label)
+
+	yearly := timeseries.New(rows[1:], timeseries.Yearly, timeseries.Calendar{FiscalYearStart: time.April})
+	require.Equal(t, "FY2025/26", yearly.Series[0].Label)
+}
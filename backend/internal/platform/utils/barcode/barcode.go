@@ -0,0 +1,104 @@
+// Package barcode validates retail barcodes (EAN/UPC) and encodes Code 128 symbols for
+// printing.
+package barcode
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrUnsupportedCharacter is returned when a value cannot be encoded in Code 128 set B
+// (printable ASCII only).
+var ErrUnsupportedCharacter = errors.New("barcode: unsupported character")
+
+// ValidGTIN reports whether s is an EAN-8, UPC-A (12 digits), EAN-13 or GTIN-14 number with
+// a correct check digit.
+func ValidGTIN(s string) bool {
+	switch len(s) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return checkDigit(s[:len(s)-1]) == s[len(s)-1]
+}
+
+// checkDigit computes the GS1 check digit of digits: weights alternate 3 and 1 from the
+// rightmost digit.
+func checkDigit(digits string) byte {
+	sum := 0
+	weight := 3
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight = 4 - weight
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// Code 128 symbol values used as control characters.
+const (
+	startB = 104
+	startC = 105
+	stop   = 106
+)
+
+// patterns are the bar/space module widths of Code 128 symbol values 0..106, starting with
+// a bar. Every symbol is 11 modules wide except the stop (13, including its final bar).
+var patterns = [107]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+// Code128 encodes s as a Code 128 symbol and returns its modules from left to right, true
+// for a bar. Quiet zones are not included. Even-length all-digit values use the denser
+// set C; everything else uses set B.
+func Code128(s string) ([]bool, error) {
+	if s == "" {
+		return nil, ErrUnsupportedCharacter
+	}
+	var values []int
+	if len(s)%2 == 0 && strings.Trim(s, "0123456789") == "" {
+		values = append(values, startC)
+		for i := 0; i < len(s); i += 2 {
+			values = append(values, int(s[i]-'0')*10+int(s[i+1]-'0'))
+		}
+	} else {
+		values = append(values, startB)
+		for _, r := range s {
+			if r < 32 || r > 126 {
+				return nil, ErrUnsupportedCharacter
+			}
+			values = append(values, int(r)-32)
+		}
+	}
+	sum := values[0]
+	for i, v := range values[1:] {
+		sum += (i + 1) * v
+	}
+	values = append(values, sum%103, stop)
+
+	var modules []bool
+	for _, v := range values {
+		bar := true
+		for _, w := range patterns[v] {
+			for range int(w - '0') {
+				modules = append(modules, bar)
+			}
+			bar = !bar
+		}
+	}
+	return modules, nil
+}
@@ -0,0 +1,96 @@
+package barcode_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/platform/utils/barcode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidGTIN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{value: "4006381333931", want: true},  // EAN-13
+		{value: "036000291452", want: true},   // UPC-A
+		{value: "73513537", want: true},       // EAN-8
+		{value: "10036000291459", want: true}, // GTIN-14
+		{value: "4006381333932", want: false}, // wrong check digit
+		{value: "036000291453", want: false},
+		{value: "40063813339", want: false}, // 11 digits
+		{value: "40063813339A", want: false},
+		{value: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, barcode.ValidGTIN(tt.value))
+		})
+	}
+}
+
+// widths converts modules to the run lengths of alternating bars and spaces.
+func widths(modules []bool) string {
+	var b strings.Builder
+	run := 1
+	for i := 1; i <= len(modules); i++ {
+		if i < len(modules) && modules[i] == modules[i-1] {
+			run++
+			continue
+		}
+		b.WriteByte(byte('0' + run))
+		run = 1
+	}
+	return b.String()
+}
+
+func TestCode128(t *testing.T) {
+	t.Parallel()
+
+	t.Run("set B", func(t *testing.T) {
+		t.Parallel()
+		modules, err := barcode.Code128("SKU-1")
+		require.NoError(t, err)
+		// start + 5 characters + check symbol at 11 modules each, then the 13-module stop
+		require.Len(t, modules, 11*7+13)
+		require.True(t, modules[0])
+		require.True(t, modules[len(modules)-1])
+		w := widths(modules)
+		require.True(t, strings.HasPrefix(w, "211214"), "start B, got %s", w)
+		require.True(t, strings.HasSuffix(w, "2331112"), "stop, got %s", w)
+	})
+
+	t.Run("set C for even digit runs", func(t *testing.T) {
+		t.Parallel()
+		modules, err := barcode.Code128("4006381333931")
+		require.NoError(t, err)
+		require.Len(t, modules, 11*15+13, "odd length stays in set B")
+
+		modules, err = barcode.Code128("036000291452")
+		require.NoError(t, err)
+		require.Len(t, modules, 11*8+13)
+		require.True(t, strings.HasPrefix(widths(modules), "211232"), "start C")
+	})
+
+	t.Run("checksum", func(t *testing.T) {
+		t.Parallel()
+		// "AB" in set B: start 104 + 33*1 + 34*2 = 205, 205 mod 103 = 102.
+		modules, err := barcode.Code128("AB")
+		require.NoError(t, err)
+		w := widths(modules)
+		require.Equal(t, "411131", w[18:24])
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		t.Parallel()
+		_, err := barcode.Code128("café")
+		require.ErrorIs(t, err, barcode.ErrUnsupportedCharacter)
+		_, err = barcode.Code128("")
+		require.ErrorIs(t, err, barcode.ErrUnsupportedCharacter)
+	})
+}
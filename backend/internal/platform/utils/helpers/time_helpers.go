@@ -24,3 +24,13 @@ func CeilPositiveDaysUntil(t time.Time) int {
 	}
 	return int(math.Ceil(d.Hours() / 24))
 }
+
+// CeilPositiveMinutesUntil returns the ceiling of the remaining minutes until t.
+// It clamps negative/zero durations to 0.
+func CeilPositiveMinutesUntil(t time.Time) int {
+	d := time.Until(t)
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Minutes()))
+}
@@ -0,0 +1,39 @@
+// Package money provides currency-aware helpers for monetary amounts.
+package money
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// defaultMinorUnits is used for every currency not listed below (USD, EUR, SAR, AED, EGP, ...).
+const defaultMinorUnits int32 = 2
+
+// minorUnits lists ISO 4217 currencies whose minor unit differs from the default.
+var minorUnits = map[string]int32{
+	// No minor unit.
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	// Three decimal places.
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// MinorUnits returns the number of decimal places used by the currency.
+// Unknown or empty currencies fall back to 2.
+func MinorUnits(currency string) int32 {
+	if units, ok := minorUnits[strings.ToUpper(strings.TrimSpace(currency))]; ok {
+		return units
+	}
+	return defaultMinorUnits
+}
+
+// Round rounds amount half away from zero to the currency's minor unit.
+func Round(amount decimal.Decimal, currency string) decimal.Decimal {
+	return amount.Round(MinorUnits(currency))
+}
+
+// StringFixed formats amount with exactly the currency's number of decimal places.
+func StringFixed(amount decimal.Decimal, currency string) string {
+	return amount.StringFixed(MinorUnits(currency))
+}
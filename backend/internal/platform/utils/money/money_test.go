@@ -0,0 +1,43 @@
+package money_test
+
+import (
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/platform/utils/money"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRound(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		currency string
+		amount   string
+		want     string
+	}{
+		{currency: "USD", amount: "10.005", want: "10.01"},
+		{currency: "usd", amount: "10.004", want: "10"},
+		{currency: "JPY", amount: "1234.5", want: "1235"},
+		{currency: "KWD", amount: "1.23456", want: "1.235"},
+		{currency: "BHD", amount: "0.0004", want: "0"},
+		{currency: "", amount: "2.675", want: "2.68"},
+		{currency: "SAR", amount: "-1.005", want: "-1.01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.currency+"_"+tt.amount, func(t *testing.T) {
+			t.Parallel()
+			got := money.Round(decimal.RequireFromString(tt.amount), tt.currency)
+			require.True(t, got.Equal(decimal.RequireFromString(tt.want)), "got %s want %s", got, tt.want)
+		})
+	}
+}
+
+func TestStringFixed(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "5.000", money.StringFixed(decimal.NewFromInt(5), "KWD"))
+	require.Equal(t, "5", money.StringFixed(decimal.NewFromFloat(5.4), "JPY"))
+	require.Equal(t, "5.40", money.StringFixed(decimal.NewFromFloat(5.4), "EUR"))
+}
@@ -1,17 +1,28 @@
 package throttle
 
 import (
+	"math"
 	"time"
 
 	"github.com/abdelrahman146/kyora/internal/platform/cache"
 )
 
+// casRetries bounds how often Allow re-reads the bucket after losing a race to another caller.
+const casRetries = 8
+
 type state struct {
-	Count int   `json:"count"`
-	Last  int64 `json:"last"` // unix seconds
+	Tokens float64 `json:"tokens"`
+	Last   int64   `json:"last"` // unix milliseconds of the last refill
+	Prev   int64   `json:"prev"` // unix milliseconds of the last allowed action
 }
 
-// Allow implements a best-effort token bucket using cache with JSON state.
+// Allow implements a token bucket shared by every API instance through the cache.
+//
+// The bucket holds up to max tokens and refills at max per window; each allowed action
+// takes one token, and actions closer than minInterval to the previous allowed one are
+// denied. The bucket is read with GetCAS and written back with CompareAndSwapX (or AddX
+// when it does not exist yet), so concurrent callers on different replicas cannot both
+// spend the last token.
 //
 // It returns true when the action is allowed and false when it should be rate-limited.
 // When cache isn't configured or cache operations fail, it defaults to allowing the action
@@ -24,35 +35,75 @@ func Allow(c *cache.Cache, key string, window time.Duration, max int, minInterva
 		return true
 	}
 
-	now := time.Now()
-	var st state
-	if data, err := c.Get(key); err == nil && len(data) > 0 {
-		_ = c.Unmarshal(data, &st)
+	ttlSeconds := int32(math.Ceil(window.Seconds()))
+	if ttlSeconds <= 0 {
+		ttlSeconds = 1
 	}
 
-	if minInterval > 0 && st.Last != 0 && now.Sub(time.Unix(st.Last, 0)) < minInterval {
-		return false
-	}
+	for range casRetries {
+		current, err := c.GetCAS(key)
+		if err != nil && !cache.IsCacheMiss(err) {
+			return true
+		}
 
-	st.Count++
-	st.Last = now.Unix()
+		var st state
+		found := err == nil && len(current.Value) > 0
+		if found {
+			if err := c.Unmarshal(current.Value, &st); err != nil {
+				found = false
+				st = state{}
+			}
+		}
 
-	ttlSeconds := int32(window.Seconds())
-	if ttlSeconds <= 0 {
-		ttlSeconds = 1
-	}
+		next, allowed := take(st, found, time.Now(), window, max, minInterval)
+		if !allowed {
+			return false
+		}
+		b, err := c.Marshal(next)
+		if err != nil {
+			return true
+		}
 
-	persist := func() {
-		if b, err := c.Marshal(st); err == nil {
-			_ = c.SetX(key, b, ttlSeconds)
+		if current == nil {
+			err = c.AddX(key, b, ttlSeconds)
+		} else {
+			err = c.CompareAndSwapX(current, b, ttlSeconds)
+		}
+		switch {
+		case err == nil:
+			return true
+		case cache.IsNotStored(err) || cache.IsCASConflict(err):
+			// Another caller wrote the bucket first; re-read and decide again.
+			continue
+		default:
+			return true
 		}
 	}
+	// Persistent contention on one key means it is being hammered; deny.
+	return false
+}
 
-	if st.Count > max {
-		persist()
-		return false
+// take refills the bucket up to now and tries to spend one token.
+func take(st state, found bool, now time.Time, window time.Duration, max int, minInterval time.Duration) (state, bool) {
+	nowMs := now.UnixMilli()
+	if !found {
+		st = state{Tokens: float64(max), Last: nowMs}
 	}
 
-	persist()
-	return true
+	if minInterval > 0 && st.Prev != 0 && nowMs-st.Prev < minInterval.Milliseconds() {
+		return st, false
+	}
+
+	if elapsed := nowMs - st.Last; elapsed > 0 && window > 0 {
+		st.Tokens += float64(elapsed) * float64(max) / float64(window.Milliseconds())
+	}
+	st.Tokens = math.Min(st.Tokens, float64(max))
+	st.Last = nowMs
+
+	if st.Tokens < 1 {
+		return st, false
+	}
+	st.Tokens--
+	st.Prev = nowMs
+	return st, true
 }
@@ -0,0 +1,61 @@
+package throttle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTake(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+
+	// spend runs n attempts spaced by step and reports how many were allowed.
+	spend := func(n int, step time.Duration, window time.Duration, max int, minInterval time.Duration) int {
+		var st state
+		found := false
+		allowed := 0
+		for i := range n {
+			var ok bool
+			st, ok = take(st, found, start.Add(time.Duration(i)*step), window, max, minInterval)
+			found = true
+			if ok {
+				allowed++
+			}
+		}
+		return allowed
+	}
+
+	tests := []struct {
+		name        string
+		attempts    int
+		step        time.Duration
+		window      time.Duration
+		max         int
+		minInterval time.Duration
+		wantAllowed int
+	}{
+		{name: "burst up to max", attempts: 10, step: 0, window: time.Minute, max: 5, wantAllowed: 5},
+		{name: "refills over the window", attempts: 10, step: 12 * time.Second, window: time.Minute, max: 5, wantAllowed: 10},
+		{name: "min interval denies rapid calls", attempts: 10, step: 100 * time.Millisecond, window: time.Minute, max: 100, minInterval: time.Second, wantAllowed: 1},
+		{name: "min interval allows spaced calls", attempts: 3, step: time.Second, window: time.Minute, max: 100, minInterval: time.Second, wantAllowed: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantAllowed, spend(tt.attempts, tt.step, tt.window, tt.max, tt.minInterval))
+		})
+	}
+}
+
+func TestTake_DeniedCallsDoNotSpendTokens(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	st, ok := take(state{}, false, now, time.Minute, 1, 0)
+	require.True(t, ok)
+
+	st, ok = take(st, true, now.Add(30*time.Second), time.Minute, 1, 0)
+	require.False(t, ok)
+
+	_, ok = take(st, true, now.Add(61*time.Second), time.Minute, 1, 0)
+	require.True(t, ok)
+}
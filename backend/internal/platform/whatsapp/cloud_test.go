@@ -0,0 +1,63 @@
+package whatsapp_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/platform/whatsapp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudClientSendAuthCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v20.0/12345/messages", r.URL.Path)
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var in map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		require.Equal(t, "whatsapp", in["messaging_product"])
+		require.Equal(t, "971501234567", in["to"])
+		tpl := in["template"].(map[string]any)
+		require.Equal(t, "login_code", tpl["name"])
+		require.Equal(t, "ar", tpl["language"].(map[string]any)["code"])
+		components := tpl["components"].([]any)
+		require.Len(t, components, 2)
+		for _, c := range components {
+			params := c.(map[string]any)["parameters"].([]any)
+			require.Equal(t, "482913", params[0].(map[string]any)["text"])
+		}
+		_, _ = w.Write([]byte(`{"messages":[{"id":"wamid.1"}]}`))
+	}))
+	defer srv.Close()
+
+	client := whatsapp.NewCloudClient(whatsapp.CloudConfig{
+		BaseURL:          srv.URL + "/v20.0/",
+		AccessToken:      "secret",
+		PhoneNumberID:    "12345",
+		AuthTemplate:     "login_code",
+		TemplateLanguage: "ar",
+	}, srv.Client())
+	require.NoError(t, client.SendAuthCode(context.Background(), "+971501234567", "482913"))
+}
+
+func TestCloudClientReportsAPIErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"message":"template not found"}}`))
+	}))
+	defer srv.Close()
+
+	client := whatsapp.NewCloudClient(whatsapp.CloudConfig{BaseURL: srv.URL, AccessToken: "secret", PhoneNumberID: "1", AuthTemplate: "missing"}, srv.Client())
+	err := client.SendAuthCode(context.Background(), "971501234567", "000000")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "status 400")
+	require.Contains(t, err.Error(), "template not found")
+}
+
+func TestMockClientKeepsSentMessages(t *testing.T) {
+	client := &whatsapp.MockClient{}
+	require.NoError(t, client.SendAuthCode(context.Background(), "+971501234567", "123456"))
+	require.Equal(t, []whatsapp.SentMessage{{To: "+971501234567", Code: "123456"}}, client.Sent())
+}
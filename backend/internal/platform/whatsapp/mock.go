@@ -0,0 +1,39 @@
+package whatsapp
+
+import (
+	"context"
+	"sync"
+
+	"github.com/abdelrahman146/kyora/internal/platform/logger"
+)
+
+// SentMessage is a message the mock client accepted.
+type SentMessage struct {
+	To   string
+	Code string
+}
+
+// MockClient logs messages instead of sending them and keeps them for tests to inspect.
+type MockClient struct {
+	mu   sync.Mutex
+	sent []SentMessage
+	Err  error
+}
+
+func (m *MockClient) SendAuthCode(ctx context.Context, to, code string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.mu.Lock()
+	m.sent = append(m.sent, SentMessage{To: to, Code: code})
+	m.mu.Unlock()
+	logger.FromContext(ctx).Info("mock whatsapp auth code sent", "to", to)
+	return nil
+}
+
+// Sent returns the messages accepted so far, oldest first.
+func (m *MockClient) Sent() []SentMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]SentMessage(nil), m.sent...)
+}
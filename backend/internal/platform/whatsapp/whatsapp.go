@@ -0,0 +1,48 @@
+// Package whatsapp sends WhatsApp messages to customers.
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/spf13/viper"
+)
+
+// Client sends WhatsApp messages on behalf of the platform.
+type Client interface {
+	// SendAuthCode sends a one-time login code to the phone number (international format,
+	// with or without the leading +).
+	SendAuthCode(ctx context.Context, to, code string) error
+}
+
+// New returns the Client selected by whatsapp.provider, or nil when WhatsApp is disabled:
+// 1) "" -> nil (features that need WhatsApp report it unavailable)
+// 2) "mock" -> Mock, logging messages instead of sending them
+// 3) "cloud" -> Cloud, sending through the WhatsApp Cloud API
+func New() (Client, error) {
+	provider := strings.TrimSpace(viper.GetString(config.WhatsappProvider))
+	switch provider {
+	case "":
+		return nil, nil
+	case "mock":
+		return &MockClient{}, nil
+	case "cloud":
+		token := strings.TrimSpace(viper.GetString(config.WhatsappCloudAccessToken))
+		phoneNumberID := strings.TrimSpace(viper.GetString(config.WhatsappCloudPhoneNumberID))
+		template := strings.TrimSpace(viper.GetString(config.WhatsappCloudAuthTemplate))
+		if token == "" || phoneNumberID == "" || template == "" {
+			return nil, errors.New("missing WhatsApp Cloud API settings: set whatsapp.cloud.access_token, whatsapp.cloud.phone_number_id and whatsapp.cloud.auth_template")
+		}
+		return NewCloudClient(CloudConfig{
+			BaseURL:          viper.GetString(config.WhatsappCloudBaseURL),
+			AccessToken:      token,
+			PhoneNumberID:    phoneNumberID,
+			AuthTemplate:     template,
+			TemplateLanguage: viper.GetString(config.WhatsappCloudTemplateLanguage),
+		}, nil), nil
+	default:
+		return nil, errors.New("unsupported WhatsApp provider: " + provider)
+	}
+}
@@ -0,0 +1,132 @@
+// Package xlsx writes single-sheet Excel workbooks (exports) without external dependencies.
+//
+// Cells are either inline strings or numbers; there are no styles, formulas or shared
+// strings. That keeps files readable by Excel, LibreOffice and Google Sheets while the
+// writer stays small.
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Cell is a single worksheet value.
+type Cell struct {
+	value   string
+	numeric bool
+}
+
+// String returns a text cell.
+func String(s string) Cell {
+	return Cell{value: s}
+}
+
+// Number returns a numeric cell from its decimal representation (e.g. "12.50"). Values
+// that are not numbers are written as text.
+func Number(s string) Cell {
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return Cell{value: s}
+	}
+	return Cell{value: s, numeric: true}
+}
+
+// Sheet is a worksheet being written, one row at a time.
+type Sheet struct {
+	name string
+	rows bytes.Buffer
+	n    int
+}
+
+// New creates a workbook with a single sheet. Sheet names are cut to Excel's 31 characters.
+func New(name string) *Sheet {
+	name = strings.NewReplacer("[", "", "]", "", ":", "", "*", "", "?", "", "/", "", "\\", "").Replace(name)
+	if name == "" {
+		name = "Sheet1"
+	}
+	for utf8.RuneCountInString(name) > 31 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return &Sheet{name: name}
+}
+
+// AddRow appends a row of cells.
+func (s *Sheet) AddRow(cells ...Cell) {
+	s.n++
+	fmt.Fprintf(&s.rows, `<row r="%d">`, s.n)
+	for i, c := range cells {
+		ref := columnName(i) + strconv.Itoa(s.n)
+		if c.numeric {
+			fmt.Fprintf(&s.rows, `<c r="%s"><v>%s</v></c>`, ref, c.value)
+			continue
+		}
+		fmt.Fprintf(&s.rows, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+		_ = xml.EscapeText(&s.rows, []byte(sanitize(c.value)))
+		s.rows.WriteString(`</t></is></c>`)
+	}
+	s.rows.WriteString(`</row>`)
+}
+
+// Rows returns the number of rows written so far.
+func (s *Sheet) Rows() int {
+	return s.n
+}
+
+// Bytes renders the workbook.
+func (s *Sheet) Bytes() ([]byte, error) {
+	var name bytes.Buffer
+	_ = xml.EscapeText(&name, []byte(s.name))
+
+	files := []struct{ path, body string }{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="` + name.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+		{"xl/worksheets/sheet1.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + s.rows.String() + `</sheetData></worksheet>`},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.Create(f.path)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write([]byte(f.body)); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// columnName returns the letters of the zero-based column i (A, B, ..., Z, AA, ...).
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// sanitize drops characters XML 1.0 cannot represent, which would corrupt the sheet.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' || (r >= 0x20 && r <= 0xD7FF) || (r >= 0xE000 && r <= 0xFFFD) || (r >= 0x10000 && r <= 0x10FFFF) {
+			return r
+		}
+		return -1
+	}, s)
+}
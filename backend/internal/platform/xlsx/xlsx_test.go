@@ -0,0 +1,80 @@
+package xlsx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/platform/xlsx"
+	"github.com/stretchr/testify/require"
+)
+
+func readSheet(t *testing.T, data []byte) string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	names := map[string]bool{}
+	var sheet string
+	for _, f := range zr.File {
+		names[f.Name] = true
+		rc, err := f.Open()
+		require.NoError(t, err)
+		body, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		// every part must be well-formed XML
+		dec := xml.NewDecoder(bytes.NewReader(body))
+		for {
+			_, err := dec.Token()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err, f.Name)
+		}
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			sheet = string(body)
+		}
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		require.True(t, names[name], name)
+	}
+	return sheet
+}
+
+func TestSheet_Bytes(t *testing.T) {
+	t.Parallel()
+
+	s := xlsx.New("Orders")
+	s.AddRow(xlsx.String("orderNumber"), xlsx.String("customer"), xlsx.String("total"))
+	s.AddRow(xlsx.String("A1B2C3"), xlsx.String(`Tom & "Jerry" <co>`+"\x01"), xlsx.Number("12.50"))
+	s.AddRow(xlsx.String("D4E5F6"), xlsx.String("سارة"), xlsx.Number("n/a"))
+	require.Equal(t, 3, s.Rows())
+
+	out, err := s.Bytes()
+	require.NoError(t, err)
+	sheet := readSheet(t, out)
+
+	require.Contains(t, sheet, `<c r="C2"><v>12.50</v></c>`)
+	require.Contains(t, sheet, `Tom &amp; &#34;Jerry&#34; &lt;co&gt;</t>`)
+	require.Contains(t, sheet, `<c r="B3" t="inlineStr"><is><t xml:space="preserve">سارة</t></is></c>`)
+	require.Contains(t, sheet, `<c r="C3" t="inlineStr"><is><t xml:space="preserve">n/a</t></is></c>`)
+}
+
+func TestSheet_ColumnNames(t *testing.T) {
+	t.Parallel()
+
+	cells := make([]xlsx.Cell, 28)
+	for i := range cells {
+		cells[i] = xlsx.Number("1")
+	}
+	s := xlsx.New("a/very:long*sheet?name[that]exceeds the limit")
+	s.AddRow(cells...)
+	out, err := s.Bytes()
+	require.NoError(t, err)
+	sheet := readSheet(t, out)
+	require.Contains(t, sheet, `<c r="Z1">`)
+	require.Contains(t, sheet, `<c r="AA1">`)
+	require.Contains(t, sheet, `<c r="AB1">`)
+}
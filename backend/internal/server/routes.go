@@ -5,30 +5,58 @@ import (
 	"github.com/abdelrahman146/kyora/internal/domain/accounting"
 	"github.com/abdelrahman146/kyora/internal/domain/analytics"
 	"github.com/abdelrahman146/kyora/internal/domain/asset"
+	"github.com/abdelrahman146/kyora/internal/domain/audit"
 	"github.com/abdelrahman146/kyora/internal/domain/billing"
 	"github.com/abdelrahman146/kyora/internal/domain/business"
 	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/deadletter"
 	"github.com/abdelrahman146/kyora/internal/domain/inventory"
 	"github.com/abdelrahman146/kyora/internal/domain/metadata"
 	"github.com/abdelrahman146/kyora/internal/domain/onboarding"
 	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/domain/review"
+	"github.com/abdelrahman146/kyora/internal/domain/sampledata"
+	"github.com/abdelrahman146/kyora/internal/domain/search"
 	"github.com/abdelrahman146/kyora/internal/domain/storefront"
+	"github.com/abdelrahman146/kyora/internal/domain/storeimport"
+	"github.com/abdelrahman146/kyora/internal/domain/task"
 	"github.com/abdelrahman146/kyora/internal/platform/auth"
+	"github.com/abdelrahman146/kyora/internal/platform/config"
 	"github.com/abdelrahman146/kyora/internal/platform/middleware"
 	"github.com/abdelrahman146/kyora/internal/platform/types/role"
 	"github.com/gin-gonic/gin"
 )
 
-func registerStorefrontRoutes(r *gin.Engine, h *storefront.HttpHandler) {
+func registerStorefrontRoutes(r *gin.Engine, h *storefront.HttpHandler, svc *storefront.Service) {
 	group := r.Group("/v1/storefront")
 	{
 		// Public storefront endpoints must be callable from arbitrary storefront origins (custom domains,
 		// link-in-bio pages, local dev). These endpoints do not rely on cookies/credentials.
-		group.Use(middleware.NewPublicCORSMiddleware())
+		group.Use(
+			middleware.NewPublicCORSMiddleware(),
+			middleware.NewCompressionMiddleware(),
+			middleware.NewETagMiddleware(config.HTTPCacheControlStorefront),
+			// Storefronts of workspaces in other regions are served from their region.
+			storefront.RouteToStorefrontRegion(svc),
+		)
 
 		group.GET("/:storefrontPublicId/catalog", h.GetCatalog)
 		group.GET("/:storefrontPublicId/shipping-zones", h.ListShippingZones)
 		group.POST("/:storefrontPublicId/orders", h.CreateOrder)
+		group.GET("/:storefrontPublicId/orders/:orderNumber/track", h.TrackOrder)
+		group.GET("/:storefrontPublicId/products/:productId/reviews", h.ListProductReviews)
+		group.POST("/:storefrontPublicId/products/:productId/reviews", h.SubmitProductReview)
+		group.POST("/:storefrontPublicId/auth/login", h.RequestCustomerLogin)
+		group.POST("/:storefrontPublicId/auth/verify", h.VerifyCustomerLogin)
+
+		// Customer portal: the signed-in customer's own data.
+		me := group.Group("/:storefrontPublicId/me", storefront.EnforceCustomerAuthentication(svc))
+		{
+			me.GET("", h.GetMe)
+			me.GET("/orders", h.ListMyOrders)
+			me.GET("/orders/:orderNumber", h.GetMyOrder)
+			me.GET("/addresses", h.ListMyAddresses)
+		}
 	}
 }
 
@@ -50,6 +78,7 @@ func registerMetadataRoutes(r *gin.Engine, h *metadata.HttpHandler) {
 	group := r.Group("/v1/metadata")
 	group.Use(middleware.NewCORSMiddleware())
 	group.GET("/countries", h.ListCountries)
+	group.GET("/regions", h.ListRegions)
 }
 
 func registerAccountRoutes(r *gin.Engine, h *account.HttpHandler, accountService *account.Service, billingService *billing.Service) {
@@ -64,10 +93,16 @@ func registerAccountRoutes(r *gin.Engine, h *account.HttpHandler, accountService
 		authGroup.POST("/logout-others", h.LogoutOtherDevices)
 		authGroup.POST("/google/login", h.LoginWithGoogle)
 		authGroup.GET("/google/url", h.GetGoogleAuthURL)
+		authGroup.POST("/sso/start", h.StartSSOLogin)
+		authGroup.POST("/sso/callback", h.CompleteSSOLogin)
+		authGroup.POST("/magic-link", h.RequestMagicLink)
+		authGroup.POST("/magic-link/login", h.LoginWithMagicLink)
 		authGroup.POST("/forgot-password", h.ForgotPassword)
 		authGroup.POST("/reset-password", h.ResetPassword)
 		authGroup.POST("/verify-email/request", h.RequestEmailVerification)
 		authGroup.POST("/verify-email", h.VerifyEmail)
+		authGroup.POST("/email-change/confirm", h.ConfirmEmailChange)
+		authGroup.POST("/email-change/undo", h.UndoEmailChange)
 	}
 
 	// Public invitation acceptance endpoints (no auth required)
@@ -84,6 +119,9 @@ func registerAccountRoutes(r *gin.Engine, h *account.HttpHandler, accountService
 	{
 		userGroup.GET("/me", h.GetCurrentUser)
 		userGroup.PATCH("/me", h.UpdateCurrentUser)
+		userGroup.GET("/me/notification-preferences", h.GetNotificationPreferences)
+		userGroup.PATCH("/me/notification-preferences", h.UpdateNotificationPreferences)
+		userGroup.POST("/me/email", h.RequestEmailChange)
 	}
 
 	// Protected workspace endpoints
@@ -105,10 +143,56 @@ func registerAccountRoutes(r *gin.Engine, h *account.HttpHandler, accountService
 		workspaceGroup.PATCH("/users/:userId/role",
 			account.EnforceActorPermissions(role.ActionManage, role.ResourceAccount),
 			h.UpdateUserRole)
+		workspaceGroup.PATCH("/users/:userId/permissions",
+			account.EnforceActorPermissions(role.ActionManage, role.ResourceAccount),
+			h.UpdateUserPermissions)
 		workspaceGroup.DELETE("/users/:userId",
 			account.EnforceActorPermissions(role.ActionManage, role.ResourceAccount),
 			h.RemoveUserFromWorkspace)
 
+		// Custom roles (view to read, manage to define)
+		rolesGroup := workspaceGroup.Group("/roles")
+		{
+			rolesGroup.GET("",
+				account.EnforceActorPermissions(role.ActionView, role.ResourceAccount),
+				h.ListCustomRoles)
+			rolesGroup.GET("/permissions",
+				account.EnforceActorPermissions(role.ActionView, role.ResourceAccount),
+				h.GetPermissionMatrix)
+			rolesGroup.GET("/:roleId",
+				account.EnforceActorPermissions(role.ActionView, role.ResourceAccount),
+				h.GetCustomRole)
+			rolesGroup.POST("",
+				account.EnforceActorPermissions(role.ActionManage, role.ResourceAccount),
+				h.CreateCustomRole)
+			rolesGroup.PATCH("/:roleId",
+				account.EnforceActorPermissions(role.ActionManage, role.ResourceAccount),
+				h.UpdateCustomRole)
+			rolesGroup.DELETE("/:roleId",
+				account.EnforceActorPermissions(role.ActionManage, role.ResourceAccount),
+				h.DeleteCustomRole)
+		}
+
+		// Enterprise single sign-on (enterprise plan)
+		ssoGroup := workspaceGroup.Group("/sso",
+			billing.EnforceActiveSubscription(billingService),
+			billing.EnforcePlanFeatureRestriction(billing.PlanSchema.SingleSignOn),
+		)
+		{
+			ssoGroup.GET("",
+				account.EnforceActorPermissions(role.ActionView, role.ResourceAccount),
+				h.GetSSOConnection)
+			ssoGroup.PUT("",
+				account.EnforceActorPermissions(role.ActionManage, role.ResourceAccount),
+				h.PutSSOConnection)
+			ssoGroup.POST("/verify-domain",
+				account.EnforceActorPermissions(role.ActionManage, role.ResourceAccount),
+				h.VerifySSODomain)
+			ssoGroup.DELETE("",
+				account.EnforceActorPermissions(role.ActionManage, role.ResourceAccount),
+				h.DeleteSSOConnection)
+		}
+
 		// Invitation management (manage permission required)
 		invitationsGroup := workspaceGroup.Group("/invitations")
 		invitationsGroup.Use(account.EnforceActorPermissions(role.ActionManage, role.ResourceAccount))
@@ -117,8 +201,13 @@ func registerAccountRoutes(r *gin.Engine, h *account.HttpHandler, accountService
 				billing.EnforceActiveSubscription(billingService),
 				billing.EnforcePlanWorkspaceLimits(billing.PlanSchema.MaxTeamMembers, accountService.CountWorkspaceUsersForPlanLimit),
 				h.InviteUserToWorkspace)
+			invitationsGroup.POST("/bulk",
+				billing.EnforceActiveSubscription(billingService),
+				billing.EnforcePlanWorkspaceLimits(billing.PlanSchema.MaxTeamMembers, accountService.CountWorkspaceUsersForPlanLimit),
+				h.BulkInviteUsers)
 			invitationsGroup.GET("", h.GetWorkspaceInvitations)
 			invitationsGroup.DELETE("/:invitationId", h.RevokeInvitation)
+			invitationsGroup.POST("/:invitationId/resend", h.ResendInvitation)
 		}
 	}
 }
@@ -213,9 +302,28 @@ func registerBusinessRoutes(r *gin.Engine, h *business.HttpHandler, accountServi
 	group.PATCH("/:businessDescriptor", account.EnforceActorPermissions(role.ActionManage, role.ResourceBusiness), h.UpdateBusiness)
 	group.POST("/:businessDescriptor/archive", account.EnforceActorPermissions(role.ActionManage, role.ResourceBusiness), h.ArchiveBusiness)
 	group.POST("/:businessDescriptor/unarchive", account.EnforceActorPermissions(role.ActionManage, role.ResourceBusiness), h.UnarchiveBusiness)
+	group.POST("/:businessDescriptor/delete-confirmation", account.EnforceActorPermissions(role.ActionManage, role.ResourceBusiness), h.IssueDeleteConfirmation)
 	group.DELETE("/:businessDescriptor", account.EnforceActorPermissions(role.ActionManage, role.ResourceBusiness), h.DeleteBusiness)
 }
 
+// registerWorkspaceAnalyticsRoutes registers analytics that span every business of the workspace.
+func registerWorkspaceAnalyticsRoutes(r *gin.Engine, h *analytics.HttpHandler, accountService *account.Service) {
+	group := r.Group("/v1/workspaces/analytics")
+	group.Use(
+		middleware.NewCORSMiddleware(),
+		auth.EnforceAuthentication,
+		account.EnforceValidActor(accountService),
+		account.EnforceWorkspaceMembership(accountService),
+		middleware.NewCompressionMiddleware(),
+		middleware.NewETagMiddleware(config.HTTPCacheControlAnalytics),
+	)
+	group.GET("/business-comparison",
+		account.EnforceActorPermissions(role.ActionView, role.ResourceBusiness),
+		account.EnforceActorPermissions(role.ActionView, role.ResourceFinancials),
+		h.CompareBusinesses,
+	)
+}
+
 func registerBusinessScopedRoutes(
 	r *gin.Engine,
 	accountService *account.Service,
@@ -228,6 +336,11 @@ func registerBusinessScopedRoutes(
 	customerHandler *customer.HttpHandler,
 	inventoryHandler *inventory.HttpHandler,
 	orderHandler *order.HttpHandler,
+	taskHandler *task.HttpHandler,
+	reviewHandler *review.HttpHandler,
+	sampleDataHandler *sampledata.HttpHandler,
+	storeImportHandler *storeimport.HttpHandler,
+	searchHandler *search.HttpHandler,
 ) {
 	group := r.Group("/v1/businesses/:businessDescriptor")
 	group.Use(
@@ -255,7 +368,13 @@ func registerBusinessScopedRoutes(
 	{
 		customers.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceCustomer), customerHandler.ListCustomers)
 		customers.GET("/:customerId", account.EnforceActorPermissions(role.ActionView, role.ResourceCustomer), customerHandler.GetCustomer)
+		customers.GET("/:customerId/statement", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.GetCustomerStatement)
+		customers.GET("/:customerId/stats", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.GetCustomerStats)
+		customers.GET("/:customerId/timeline", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.GetCustomerTimeline)
+		customers.GET("/:customerId/data-export", account.EnforceActorPermissions(role.ActionManage, role.ResourceCustomer), orderHandler.ExportCustomerData)
+		customers.POST("/:customerId/erase", account.EnforceActorPermissions(role.ActionManage, role.ResourceCustomer), orderHandler.EraseCustomerData)
 		customers.POST("", account.EnforceActorPermissions(role.ActionManage, role.ResourceCustomer), customerHandler.CreateCustomer)
+		customers.PUT("/price-list", account.EnforceActorPermissions(role.ActionManage, role.ResourceCustomer), customerHandler.AssignPriceList)
 		customers.PATCH("/:customerId", account.EnforceActorPermissions(role.ActionManage, role.ResourceCustomer), customerHandler.UpdateCustomer)
 		customers.DELETE("/:customerId", account.EnforceActorPermissions(role.ActionManage, role.ResourceCustomer), customerHandler.DeleteCustomer)
 
@@ -277,9 +396,15 @@ func registerBusinessScopedRoutes(
 
 	// Inventory routes
 	inventoryGroup := group.Group("/inventory")
+	inventoryGroup.Use(middleware.NewCompressionMiddleware(), middleware.NewETagMiddleware(config.HTTPCacheControlCatalog))
 	{
+		inventoryGroup.GET("/search", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.SearchInventory)
 		inventoryGroup.GET("/summary", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.GetInventorySummary)
 		inventoryGroup.GET("/top-products", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.GetTopProductsByInventoryValue)
+		inventoryGroup.GET("/reorder-suggestions", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.GetReorderSuggestions)
+		inventoryGroup.GET("/valuation", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), account.EnforceActorPermissions(role.ActionView, role.ResourceFinancials), inventoryHandler.GetStockValuation)
+		inventoryGroup.GET("/export", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.ExportCatalog)
+		inventoryGroup.POST("/import", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.ImportCatalog)
 
 		products := inventoryGroup.Group("/products")
 		{
@@ -288,15 +413,28 @@ func registerBusinessScopedRoutes(
 			products.GET("/:productId/variants", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.ListProductVariants)
 			products.POST("", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.CreateProduct)
 			products.POST("/with-variants", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.CreateProductWithVariants)
+			products.POST("/:productId/variants/generate", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.GenerateProductVariants)
 			products.PATCH("/:productId", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.UpdateProduct)
 			products.DELETE("/:productId", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.DeleteProduct)
+			products.POST("/:productId/photos", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.AddProductPhotos)
+			products.PUT("/:productId/photos/order", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.ReorderProductPhotos)
+			products.PUT("/:productId/photos/cover", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.SetProductCoverPhoto)
+			products.POST("/:productId/photos/thumbnails", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.RegenerateProductPhotoThumbnails)
 		}
 
 		variants := inventoryGroup.Group("/variants")
 		{
 			variants.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.ListVariants)
+			variants.GET("/picker", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.ListPickerVariants)
+			variants.GET("/lookup", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.LookupVariant)
+			variants.GET("/sku-preview", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.PreviewSKUs)
+			variants.POST("/labels", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.DownloadVariantLabels)
 			variants.GET("/:variantId", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.GetVariant)
+			variants.GET("/:variantId/cost-trend", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), account.EnforceActorPermissions(role.ActionView, role.ResourceFinancials), inventoryHandler.GetVariantCostTrend)
+			variants.GET("/:variantId/locations", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.ListVariantLocationStock)
+			variants.PUT("/:variantId/locations/:locationId", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.SetVariantLocationStock)
 			variants.POST("", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.CreateVariant)
+			variants.PATCH("/bulk", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.BulkUpdateVariants)
 			variants.PATCH("/:variantId", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.UpdateVariant)
 			variants.DELETE("/:variantId", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.DeleteVariant)
 		}
@@ -308,6 +446,72 @@ func registerBusinessScopedRoutes(
 			categories.POST("", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.CreateCategory)
 			categories.PATCH("/:categoryId", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.UpdateCategory)
 			categories.DELETE("/:categoryId", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.DeleteCategory)
+			categories.POST("/:categoryId/flash-sale", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.StartFlashSale)
+			categories.DELETE("/:categoryId/flash-sale", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.EndFlashSale)
+		}
+
+		priceLists := inventoryGroup.Group("/price-lists")
+		{
+			priceLists.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.ListPriceLists)
+			priceLists.GET("/:priceListId", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.GetPriceList)
+			priceLists.POST("", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.CreatePriceList)
+			priceLists.PATCH("/:priceListId", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.UpdatePriceList)
+			priceLists.DELETE("/:priceListId", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.DeletePriceList)
+		}
+
+		suppliers := inventoryGroup.Group("/suppliers")
+		{
+			suppliers.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.ListSuppliers)
+			suppliers.GET("/:supplierId", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.GetSupplier)
+			suppliers.POST("", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.CreateSupplier)
+			suppliers.PATCH("/:supplierId", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.UpdateSupplier)
+			suppliers.DELETE("/:supplierId", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.DeleteSupplier)
+		}
+
+		purchaseOrders := inventoryGroup.Group("/purchase-orders")
+		{
+			purchaseOrders.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.ListPurchaseOrders)
+			purchaseOrders.GET("/:purchaseOrderId", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.GetPurchaseOrder)
+			purchaseOrders.GET("/:purchaseOrderId/receipts", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.ListPurchaseOrderReceipts)
+			purchaseOrders.POST("", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.CreatePurchaseOrder)
+			purchaseOrders.PATCH("/:purchaseOrderId", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.UpdatePurchaseOrder)
+			purchaseOrders.POST("/:purchaseOrderId/receive", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.ReceivePurchaseOrder)
+			purchaseOrders.POST("/:purchaseOrderId/payments", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), inventoryHandler.RecordPurchaseOrderPayment)
+			purchaseOrders.POST("/:purchaseOrderId/cancel", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.CancelPurchaseOrder)
+		}
+
+		supplierBills := inventoryGroup.Group("/supplier-bills")
+		{
+			supplierBills.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), inventoryHandler.ListSupplierBills)
+			supplierBills.GET("/aging", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), inventoryHandler.GetSupplierBillAging)
+			supplierBills.GET("/:billId", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), inventoryHandler.GetSupplierBill)
+			supplierBills.GET("/:billId/payments", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), inventoryHandler.ListSupplierBillPayments)
+			supplierBills.POST("", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), inventoryHandler.CreateSupplierBill)
+			supplierBills.PATCH("/:billId", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), inventoryHandler.UpdateSupplierBill)
+			supplierBills.DELETE("/:billId", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), inventoryHandler.DeleteSupplierBill)
+			supplierBills.POST("/:billId/payments", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), inventoryHandler.RecordSupplierBillPayment)
+		}
+
+		locations := inventoryGroup.Group("/locations")
+		{
+			locations.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.ListLocations)
+			locations.GET("/:locationId", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.GetLocation)
+			locations.POST("", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.CreateLocation)
+			locations.POST("/transfers", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.TransferLocationStock)
+			locations.PATCH("/:locationId", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.UpdateLocation)
+			locations.DELETE("/:locationId", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.DeleteLocation)
+		}
+
+		stocktakes := inventoryGroup.Group("/stocktakes")
+		{
+			stocktakes.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.ListStocktakes)
+			stocktakes.GET("/:stocktakeId", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.GetStocktake)
+			stocktakes.GET("/:stocktakeId/variances", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), inventoryHandler.GetStocktakeVariances)
+			stocktakes.POST("", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.CreateStocktake)
+			stocktakes.PUT("/:stocktakeId/counts", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.RecordStocktakeCounts)
+			stocktakes.POST("/:stocktakeId/scan", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.ScanStocktakeItem)
+			stocktakes.POST("/:stocktakeId/approve", account.EnforceActorPermissions(role.ActionApprove, role.ResourceInventory), inventoryHandler.ApproveStocktake)
+			stocktakes.POST("/:stocktakeId/cancel", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), inventoryHandler.CancelStocktake)
 		}
 	}
 
@@ -329,6 +533,25 @@ func registerBusinessScopedRoutes(
 		}
 	}
 
+	// Delivery windows (business settings)
+	deliveryWindows := group.Group("/delivery-windows")
+	{
+		deliveryWindows.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceBusiness), businessHandler.ListDeliveryWindows)
+
+		manageWindows := deliveryWindows.Group("")
+		manageWindows.Use(
+			account.EnforceActorPermissions(role.ActionManage, role.ResourceBusiness),
+			billing.EnforceActiveSubscription(billingService),
+		)
+		{
+			manageWindows.POST("", businessHandler.CreateDeliveryWindow)
+			manageWindows.PATCH("/:windowId", businessHandler.UpdateDeliveryWindow)
+			manageWindows.DELETE("/:windowId", businessHandler.DeleteDeliveryWindow)
+		}
+	}
+
+	group.GET("/shipping-carriers", account.EnforceActorPermissions(role.ActionView, role.ResourceBusiness), businessHandler.ListShippingCarriers)
+
 	// Payment methods (business settings)
 	paymentMethods := group.Group("/payment-methods")
 	{
@@ -350,6 +573,24 @@ func registerBusinessScopedRoutes(
 		orders.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.ListOrders)
 		orders.GET("/by-number/:orderNumber", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.GetOrderByNumber)
 		orders.GET("/:orderId", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.GetOrder)
+		orders.GET("/:orderId/print", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.PrintOrder)
+		orders.GET("/:orderId/invoice.pdf", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.DownloadInvoicePDF)
+		orders.GET("/:orderId/timeline", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.GetOrderTimeline)
+		orders.GET("/:orderId/downloads", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.GetOrderDownloads)
+		orders.GET("/tags", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.ListOrderTags)
+		orders.GET("/delivery-slots", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.ListDeliverySlots)
+		orders.GET("/delivery-manifest", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.GetDeliveryManifest)
+		orders.GET("/:orderId/payments", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.ListOrderPayments)
+		orders.GET("/:orderId/shipping-labels", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.ListOrderShippingLabels)
+		orders.GET("/:orderId/shipping-labels/:labelId/label.pdf", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.DownloadOrderShippingLabel)
+		orders.GET("/exports", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.ListOrderExports)
+		orders.GET("/exports/:exportId", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.GetOrderExport)
+		orders.GET("/exports/:exportId/download", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.DownloadOrderExport)
+		orders.POST("/export",
+			account.EnforceActorPermissions(role.ActionView, role.ResourceOrder),
+			billing.EnforceActiveSubscription(billingService),
+			orderHandler.RequestOrderExport,
+		)
 
 		manageOrders := orders.Group("")
 		manageOrders.Use(
@@ -359,6 +600,8 @@ func registerBusinessScopedRoutes(
 		)
 		{
 			manageOrders.POST("/preview", orderHandler.PreviewOrder)
+			manageOrders.POST("/reconcile-totals", account.EnforceActorPermissions(role.ActionManage, role.ResourceBusiness), orderHandler.ReconcileOrderTotals)
+			manageOrders.POST("/expire-pending", account.EnforceActorPermissions(role.ActionManage, role.ResourceBusiness), orderHandler.ExpirePendingOrders)
 			manageOrders.POST("",
 				billing.EnforcePlanWorkspaceLimits(billing.PlanSchema.MaxOrdersPerMonth, billingService.CountMonthlyOrdersForPlanLimit),
 				orderHandler.CreateOrder,
@@ -368,6 +611,17 @@ func registerBusinessScopedRoutes(
 			manageOrders.PATCH("/:orderId/status", orderHandler.UpdateOrderStatus)
 			manageOrders.PATCH("/:orderId/payment-status", orderHandler.UpdateOrderPaymentStatus)
 			manageOrders.PATCH("/:orderId/payment-details", orderHandler.AddOrderPaymentDetails)
+			manageOrders.POST("/:orderId/payment-link", orderHandler.CreateOrderPaymentLink)
+			manageOrders.POST("/:orderId/payments", orderHandler.RecordOrderPayment)
+			manageOrders.DELETE("/:orderId/payments/:paymentId", orderHandler.DeleteOrderPayment)
+			manageOrders.PUT("/:orderId/tags", orderHandler.SetOrderTags)
+			manageOrders.PUT("/:orderId/delivery-slot", orderHandler.ScheduleOrderDelivery)
+			manageOrders.DELETE("/:orderId/delivery-slot", orderHandler.ClearOrderDelivery)
+			manageOrders.POST("/:orderId/shipping-rates", orderHandler.GetOrderShippingRates)
+			manageOrders.POST("/:orderId/shipping-labels", orderHandler.PurchaseOrderShippingLabel)
+			manageOrders.PATCH("/tags/:tag", orderHandler.RenameOrderTag)
+			manageOrders.DELETE("/tags/:tag", orderHandler.DeleteOrderTag)
+			manageOrders.POST("/:orderId/print-jobs", orderHandler.QueueOrderPrint)
 
 			notes := manageOrders.Group("/:orderId/notes")
 			{
@@ -378,20 +632,203 @@ func registerBusinessScopedRoutes(
 		}
 	}
 
+	// Print station routes. Stations poll, render and acknowledge jobs with view access so
+	// warehouse devices can sign in as regular members.
+	printStations := group.Group("/print-stations")
+	{
+		printStations.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.ListPrintStations)
+		printStations.GET("/:stationId/jobs", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.PollPrintJobs)
+		printStations.GET("/:stationId/jobs/:jobId/content", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.GetPrintJobContent)
+		printStations.POST("/:stationId/jobs/:jobId/ack", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.AckPrintJob)
+
+		managePrintStations := printStations.Group("")
+		managePrintStations.Use(
+			account.EnforceActorPermissions(role.ActionManage, role.ResourceOrder),
+			billing.EnforceActiveSubscription(billingService),
+		)
+		{
+			managePrintStations.POST("", orderHandler.CreatePrintStation)
+			managePrintStations.PATCH("/:stationId", orderHandler.UpdatePrintStation)
+			managePrintStations.DELETE("/:stationId", orderHandler.DeletePrintStation)
+		}
+	}
+
+	// Coupon routes
+	coupons := group.Group("/coupons")
+	{
+		coupons.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.ListCoupons)
+		coupons.GET("/:couponId", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.GetCoupon)
+
+		manageCoupons := coupons.Group("")
+		manageCoupons.Use(
+			account.EnforceActorPermissions(role.ActionManage, role.ResourceOrder),
+			billing.EnforceActiveSubscription(billingService),
+		)
+		{
+			manageCoupons.POST("", orderHandler.CreateCoupon)
+			manageCoupons.PATCH("/:couponId", orderHandler.UpdateCoupon)
+			manageCoupons.DELETE("/:couponId", orderHandler.DeleteCoupon)
+		}
+	}
+
+	// Quote routes
+	quotes := group.Group("/quotes")
+	{
+		quotes.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.ListQuotes)
+		quotes.GET("/:quoteId", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.GetQuote)
+		quotes.GET("/:quoteId/pdf", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.DownloadQuotePDF)
+
+		manageQuotes := quotes.Group("")
+		manageQuotes.Use(
+			account.EnforceActorPermissions(role.ActionManage, role.ResourceOrder),
+			billing.EnforceActiveSubscription(billingService),
+			billing.EnforcePlanFeatureRestriction(billing.PlanSchema.OrderManagement),
+		)
+		{
+			manageQuotes.POST("", orderHandler.CreateQuote)
+			manageQuotes.PATCH("/:quoteId", orderHandler.UpdateQuote)
+			manageQuotes.DELETE("/:quoteId", orderHandler.DeleteQuote)
+			manageQuotes.POST("/:quoteId/send", orderHandler.SendQuote)
+			manageQuotes.POST("/:quoteId/decline", orderHandler.DeclineQuote)
+			manageQuotes.POST("/:quoteId/accept",
+				billing.EnforcePlanWorkspaceLimits(billing.PlanSchema.MaxOrdersPerMonth, billingService.CountMonthlyOrdersForPlanLimit),
+				orderHandler.AcceptQuote,
+			)
+		}
+	}
+
+	// Recurring order routes
+	recurringOrders := group.Group("/recurring-orders")
+	{
+		recurringOrders.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.ListRecurringOrders)
+		recurringOrders.GET("/:recurringOrderId", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.GetRecurringOrder)
+
+		manageRecurringOrders := recurringOrders.Group("")
+		manageRecurringOrders.Use(
+			account.EnforceActorPermissions(role.ActionManage, role.ResourceOrder),
+			billing.EnforceActiveSubscription(billingService),
+			billing.EnforcePlanFeatureRestriction(billing.PlanSchema.OrderManagement),
+		)
+		{
+			manageRecurringOrders.POST("", orderHandler.CreateRecurringOrder)
+			manageRecurringOrders.PATCH("/:recurringOrderId", orderHandler.UpdateRecurringOrder)
+			manageRecurringOrders.DELETE("/:recurringOrderId", orderHandler.DeleteRecurringOrder)
+			manageRecurringOrders.POST("/:recurringOrderId/pause", orderHandler.PauseRecurringOrder)
+			manageRecurringOrders.POST("/:recurringOrderId/resume", orderHandler.ResumeRecurringOrder)
+			manageRecurringOrders.POST("/:recurringOrderId/generate",
+				billing.EnforcePlanWorkspaceLimits(billing.PlanSchema.MaxOrdersPerMonth, billingService.CountMonthlyOrdersForPlanLimit),
+				orderHandler.GenerateRecurringOrder,
+			)
+		}
+	}
+
+	// Order transition rules (side effects of status changes)
+	transitionRules := group.Group("/order-transition-rules")
+	{
+		transitionRules.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceOrder), orderHandler.ListOrderTransitionRules)
+
+		manageTransitionRules := transitionRules.Group("")
+		manageTransitionRules.Use(
+			account.EnforceActorPermissions(role.ActionManage, role.ResourceOrder),
+			billing.EnforceActiveSubscription(billingService),
+			billing.EnforcePlanFeatureRestriction(billing.PlanSchema.OrderManagement),
+		)
+		{
+			manageTransitionRules.POST("", orderHandler.CreateOrderTransitionRule)
+			manageTransitionRules.PATCH("/:ruleId", orderHandler.UpdateOrderTransitionRule)
+			manageTransitionRules.DELETE("/:ruleId", orderHandler.DeleteOrderTransitionRule)
+		}
+	}
+
+	// Global search: results are filtered to the types the actor may view, so no single permission guards the route
+	group.GET("/search", searchHandler.Search)
+
+	// Task routes
+	tasks := group.Group("/tasks")
+	{
+		tasks.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceTask), taskHandler.ListTasks)
+		tasks.GET("/mine", account.EnforceActorPermissions(role.ActionView, role.ResourceTask), taskHandler.ListMyTasks)
+		tasks.GET("/:taskId", account.EnforceActorPermissions(role.ActionView, role.ResourceTask), taskHandler.GetTask)
+		tasks.POST("", account.EnforceActorPermissions(role.ActionManage, role.ResourceTask), taskHandler.CreateTask)
+		tasks.PATCH("/:taskId", account.EnforceActorPermissions(role.ActionManage, role.ResourceTask), taskHandler.UpdateTask)
+		tasks.DELETE("/:taskId", account.EnforceActorPermissions(role.ActionManage, role.ResourceTask), taskHandler.DeleteTask)
+	}
+
+	// Review moderation routes: reviews are product content, so they follow inventory permissions
+	reviews := group.Group("/reviews")
+	{
+		reviews.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), reviewHandler.ListReviews)
+		reviews.GET("/:reviewId", account.EnforceActorPermissions(role.ActionView, role.ResourceInventory), reviewHandler.GetReview)
+		reviews.PATCH("/:reviewId", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), reviewHandler.UpdateReview)
+		reviews.DELETE("/:reviewId", account.EnforceActorPermissions(role.ActionManage, role.ResourceInventory), reviewHandler.DeleteReview)
+	}
+
+	// Onboarding sample data routes
+	sampleData := group.Group("/sample-data")
+	{
+		sampleData.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceBusiness), sampleDataHandler.GetSampleData)
+
+		manageSampleData := sampleData.Group("")
+		manageSampleData.Use(
+			account.EnforceActorPermissions(role.ActionManage, role.ResourceBusiness),
+			billing.EnforceActiveSubscription(billingService),
+		)
+		{
+			manageSampleData.POST("", sampleDataHandler.LoadSampleData)
+			manageSampleData.DELETE("", sampleDataHandler.RemoveSampleData)
+		}
+	}
+
+	// Shopify and WooCommerce store import routes
+	storeImports := group.Group("/store-imports")
+	{
+		storeImports.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceBusiness), storeImportHandler.ListImports)
+		storeImports.GET("/:importId", account.EnforceActorPermissions(role.ActionView, role.ResourceBusiness), storeImportHandler.GetImport)
+
+		startStoreImports := storeImports.Group("")
+		startStoreImports.Use(
+			account.EnforceActorPermissions(role.ActionManage, role.ResourceBusiness),
+			billing.EnforceActiveSubscription(billingService),
+		)
+		{
+			startStoreImports.POST("", storeImportHandler.StartImport)
+			startStoreImports.POST("/file", storeImportHandler.StartFileImport)
+		}
+	}
+
 	// Analytics routes
 	analyticsGroup := group.Group("/analytics")
+	analyticsGroup.Use(middleware.NewCompressionMiddleware(), middleware.NewETagMiddleware(config.HTTPCacheControlAnalytics))
 	{
 		analyticsGroup.GET("/dashboard", account.EnforceActorPermissions(role.ActionView, role.ResourceBasicAnalytics), analyticsHandler.GetDashboardMetrics)
 		analyticsGroup.GET("/sales", account.EnforceActorPermissions(role.ActionView, role.ResourceBasicAnalytics), analyticsHandler.GetSalesAnalytics)
 		analyticsGroup.GET("/inventory", account.EnforceActorPermissions(role.ActionView, role.ResourceBasicAnalytics), analyticsHandler.GetInventoryAnalytics)
 		analyticsGroup.GET("/customers", account.EnforceActorPermissions(role.ActionView, role.ResourceBasicAnalytics), analyticsHandler.GetCustomerAnalytics)
 
+		goals := analyticsGroup.Group("/goals")
+		{
+			goals.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceBasicAnalytics), analyticsHandler.ListMonthlyGoals)
+			goals.GET("/progress", account.EnforceActorPermissions(role.ActionView, role.ResourceBasicAnalytics), analyticsHandler.GetGoalProgress)
+			goals.PUT("/:month", account.EnforceActorPermissions(role.ActionManage, role.ResourceBusiness), analyticsHandler.UpsertMonthlyGoal)
+			goals.DELETE("/:month", account.EnforceActorPermissions(role.ActionManage, role.ResourceBusiness), analyticsHandler.DeleteMonthlyGoal)
+		}
+
+		budgets := analyticsGroup.Group("/budgets")
+		{
+			budgets.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceFinancialReports), analyticsHandler.ListMonthlyBudgets)
+			budgets.GET("/vs-actual", account.EnforceActorPermissions(role.ActionView, role.ResourceFinancialReports), analyticsHandler.GetBudgetVsActual)
+			budgets.PUT("/:month", account.EnforceActorPermissions(role.ActionManage, role.ResourceBusiness), analyticsHandler.UpsertMonthlyBudget)
+			budgets.DELETE("/:month", account.EnforceActorPermissions(role.ActionManage, role.ResourceBusiness), analyticsHandler.DeleteMonthlyBudget)
+		}
+
 		reports := analyticsGroup.Group("/reports")
 		reports.Use(account.EnforceActorPermissions(role.ActionView, role.ResourceFinancialReports))
 		{
 			reports.GET("/financial-position", analyticsHandler.GetFinancialPosition)
 			reports.GET("/profit-and-loss", analyticsHandler.GetProfitAndLoss)
 			reports.GET("/cash-flow", analyticsHandler.GetCashFlow)
+			reports.GET("/product-profitability", analyticsHandler.GetProductProfitability)
+			reports.GET("/vat-return", analyticsHandler.GetVATReturn)
 		}
 	}
 
@@ -405,6 +842,7 @@ func registerBusinessScopedRoutes(
 			assets.POST("", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.CreateAsset)
 			assets.PATCH("/:assetId", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.UpdateAsset)
 			assets.DELETE("/:assetId", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.DeleteAsset)
+			assets.GET("/:assetId/history", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.GetAssetHistory)
 		}
 
 		investments := accountingGroup.Group("/investments")
@@ -414,6 +852,7 @@ func registerBusinessScopedRoutes(
 			investments.POST("", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.CreateInvestment)
 			investments.PATCH("/:investmentId", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.UpdateInvestment)
 			investments.DELETE("/:investmentId", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.DeleteInvestment)
+			investments.GET("/:investmentId/history", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.GetInvestmentHistory)
 		}
 
 		withdrawals := accountingGroup.Group("/withdrawals")
@@ -423,15 +862,37 @@ func registerBusinessScopedRoutes(
 			withdrawals.POST("", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.CreateWithdrawal)
 			withdrawals.PATCH("/:withdrawalId", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.UpdateWithdrawal)
 			withdrawals.DELETE("/:withdrawalId", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.DeleteWithdrawal)
+			withdrawals.GET("/:withdrawalId/history", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.GetWithdrawalHistory)
 		}
 
+		accountingGroup.GET("/cap-table", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.GetCapTable)
+		accountingGroup.PUT("/cap-table", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.SetCapTable)
+		accountingGroup.GET("/profit-distribution", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.GetProfitDistribution)
+		accountingGroup.POST("/profit-distribution", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.DistributeProfit)
+
 		expenses := accountingGroup.Group("/expenses")
 		{
 			expenses.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.ListExpenses)
 			expenses.GET("/:expenseId", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.GetExpense)
 			expenses.POST("", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.CreateExpense)
+			expenses.POST("/scan", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.ScanExpenseReceipt)
 			expenses.PATCH("/:expenseId", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.UpdateExpense)
 			expenses.DELETE("/:expenseId", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.DeleteExpense)
+			expenses.GET("/:expenseId/allocations", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.GetExpenseAllocations)
+			expenses.PUT("/:expenseId/allocations", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.AllocateExpense)
+			expenses.DELETE("/:expenseId/allocations", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.ClearExpenseAllocations)
+			expenses.GET("/:expenseId/split", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.GetExpenseSplit)
+			expenses.PUT("/:expenseId/split", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.SplitExpense)
+			expenses.GET("/:expenseId/history", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.GetExpenseHistory)
+		}
+
+		allocationRules := accountingGroup.Group("/expense-allocation-rules")
+		{
+			allocationRules.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.ListExpenseAllocationRules)
+			allocationRules.GET("/:ruleId", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.GetExpenseAllocationRule)
+			allocationRules.POST("", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.CreateExpenseAllocationRule)
+			allocationRules.PATCH("/:ruleId", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.UpdateExpenseAllocationRule)
+			allocationRules.DELETE("/:ruleId", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.DeleteExpenseAllocationRule)
 		}
 
 		recurring := accountingGroup.Group("/recurring-expenses")
@@ -445,11 +906,42 @@ func registerBusinessScopedRoutes(
 			recurring.GET("/:recurringExpenseId/occurrences", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.GetRecurringExpenseOccurrences)
 		}
 
+		expenseDrafts := accountingGroup.Group("/expense-drafts")
+		{
+			expenseDrafts.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.ListExpenseDrafts)
+			expenseDrafts.GET("/:draftId", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.GetExpenseDraft)
+			expenseDrafts.POST("", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.IntakeExpenseReceipt)
+			expenseDrafts.PATCH("/:draftId", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.UpdateExpenseDraft)
+			expenseDrafts.POST("/:draftId/confirm", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.ConfirmExpenseDraft)
+			expenseDrafts.POST("/:draftId/discard", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.DiscardExpenseDraft)
+		}
+
+		expenseInbox := accountingGroup.Group("/expense-inbox")
+		{
+			expenseInbox.GET("", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.GetExpenseInbox)
+			expenseInbox.POST("", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.EnableExpenseInbox)
+			expenseInbox.POST("/rotate", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.RotateExpenseInbox)
+			expenseInbox.DELETE("", account.EnforceActorPermissions(role.ActionManage, role.ResourceAccounting), accountingHandler.DisableExpenseInbox)
+		}
+
+		ledger := accountingGroup.Group("/ledger")
+		{
+			ledger.GET("/accounts", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.ListLedgerAccounts)
+			ledger.GET("/entries", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.ListJournalEntries)
+			ledger.GET("/export", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.ExportJournalEntries)
+		}
+
 		accountingGroup.GET("/summary", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.GetAccountingSummary)
 		accountingGroup.GET("/recent-activities", account.EnforceActorPermissions(role.ActionView, role.ResourceAccounting), accountingHandler.ListRecentActivities)
 	}
 }
 
+// registerExpenseInboxWebhookRoutes registers the inbound email webhook, authenticated with the
+// shared inbox secret rather than user JWTs.
+func registerExpenseInboxWebhookRoutes(r *gin.Engine, h *accounting.HttpHandler) {
+	r.POST("/v1/webhooks/expense-inbox", h.ReceiveInboundReceiptEmail)
+}
+
 func registerPublicAssetRoutes(r *gin.Engine, h *asset.HttpHandler) {
 	// Public asset serving (no auth required)
 	publicGroup := r.Group("/v1/public")
@@ -466,3 +958,30 @@ func registerPublicAssetRoutes(r *gin.Engine, h *asset.HttpHandler) {
 		internalGroup.POST("/upload/:assetId", h.UploadLocalContent)
 	}
 }
+
+// registerAuditRoutes registers the workspace audit log, readable by members who manage the workspace.
+func registerAuditRoutes(r *gin.Engine, h *audit.HttpHandler, accountService *account.Service) {
+	group := r.Group("/v1/workspaces/audit-log")
+	group.Use(
+		middleware.NewCORSMiddleware(),
+		auth.EnforceAuthentication,
+		account.EnforceValidActor(accountService),
+		account.EnforceWorkspaceMembership(accountService),
+		account.EnforceActorPermissions(role.ActionManage, role.ResourceAccount),
+	)
+	{
+		group.GET("", h.ListEntries)
+	}
+}
+
+func registerAdminRoutes(r *gin.Engine, deadLetterHandler *deadletter.HttpHandler) {
+	// Operator endpoints, authenticated with the static admin API token rather than user JWTs.
+	group := r.Group("/v1/admin")
+	group.Use(auth.EnforceAdminToken)
+	{
+		deadLetters := group.Group("/dead-letters")
+		deadLetters.GET("", deadLetterHandler.ListDeadLetters)
+		deadLetters.GET("/:deadLetterId", deadLetterHandler.GetDeadLetter)
+		deadLetters.POST("/:deadLetterId/replay", deadLetterHandler.ReplayDeadLetter)
+	}
+}
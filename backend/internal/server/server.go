@@ -14,27 +14,31 @@ import (
 	"github.com/abdelrahman146/kyora/internal/domain/accounting"
 	"github.com/abdelrahman146/kyora/internal/domain/analytics"
 	"github.com/abdelrahman146/kyora/internal/domain/asset"
+	"github.com/abdelrahman146/kyora/internal/domain/audit"
 	"github.com/abdelrahman146/kyora/internal/domain/billing"
 	"github.com/abdelrahman146/kyora/internal/domain/business"
 	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/deadletter"
 	"github.com/abdelrahman146/kyora/internal/domain/inventory"
 	"github.com/abdelrahman146/kyora/internal/domain/metadata"
 	"github.com/abdelrahman146/kyora/internal/domain/onboarding"
 	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/domain/review"
+	"github.com/abdelrahman146/kyora/internal/domain/sampledata"
+	"github.com/abdelrahman146/kyora/internal/domain/search"
 	"github.com/abdelrahman146/kyora/internal/domain/storefront"
-	"github.com/abdelrahman146/kyora/internal/platform/blob"
-	"github.com/abdelrahman146/kyora/internal/platform/bus"
+	"github.com/abdelrahman146/kyora/internal/domain/storeimport"
+	"github.com/abdelrahman146/kyora/internal/domain/task"
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
 	"github.com/abdelrahman146/kyora/internal/platform/cache"
 	"github.com/abdelrahman146/kyora/internal/platform/config"
 	"github.com/abdelrahman146/kyora/internal/platform/database"
-	"github.com/abdelrahman146/kyora/internal/platform/email"
 	"github.com/abdelrahman146/kyora/internal/platform/logger"
 	"github.com/abdelrahman146/kyora/internal/platform/middleware"
 	"github.com/abdelrahman146/kyora/internal/platform/request"
 	"github.com/abdelrahman146/kyora/internal/platform/response"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
-	"github.com/stripe/stripe-go/v83"
 )
 
 type Server struct {
@@ -53,6 +57,9 @@ type ServerConfig struct {
 	CacheHosts    []string
 	StripeKey     string
 	StripeBaseURL string
+	GoogleOAuth   auth.GoogleOAuthProvider
+	OIDC          auth.OIDCProvider
+	TXTResolver   account.TXTResolver
 }
 
 func WithDatabaseDSN(dsn string) func(*ServerConfig) {
@@ -85,6 +92,27 @@ func WithStripeBaseURL(url string) func(*ServerConfig) {
 	}
 }
 
+// WithGoogleOAuthProvider replaces the Google OAuth provider, e.g. with a test fake.
+func WithGoogleOAuthProvider(provider auth.GoogleOAuthProvider) func(*ServerConfig) {
+	return func(cfg *ServerConfig) {
+		cfg.GoogleOAuth = provider
+	}
+}
+
+// WithOIDCProvider replaces the provider used for OIDC single sign-on, e.g. with a test fake.
+func WithOIDCProvider(provider auth.OIDCProvider) func(*ServerConfig) {
+	return func(cfg *ServerConfig) {
+		cfg.OIDC = provider
+	}
+}
+
+// WithTXTResolver replaces the DNS resolver used to verify SSO domains, e.g. with a test fake.
+func WithTXTResolver(resolver account.TXTResolver) func(*ServerConfig) {
+	return func(cfg *ServerConfig) {
+		cfg.TXTResolver = resolver
+	}
+}
+
 func New(opts ...func(*ServerConfig)) (*Server, error) {
 	// Ensure config defaults are present even when running outside Cobra (e.g., tests).
 	config.Configure()
@@ -120,96 +148,24 @@ func New(opts ...func(*ServerConfig)) (*Server, error) {
 		viper.Set(config.StripeAPIKey, conf.StripeKey)
 	}
 
-	// initialize stripe client
-	stripeAPIKey := viper.GetString(config.StripeAPIKey)
-	stripe.Key = stripeAPIKey
-	stripe.SetAppInfo(&stripe.AppInfo{Name: "Kyora", Version: "1.0", URL: "https://github.com/abdelrahman146/kyora"})
-	stripeBaseURL := conf.StripeBaseURL
-	if stripeBaseURL == "" {
-		stripeBaseURL = viper.GetString(config.StripeAPIBaseURL)
-	}
-	if stripeBaseURL != "" {
-		backend := stripe.GetBackendWithConfig(stripe.APIBackend, &stripe.BackendConfig{
-			URL: &stripeBaseURL, // optional custom base URL for testing if not provided it will automatically use the default
-		})
-		stripe.SetBackend(stripe.APIBackend, backend)
-		slog.Info("Stripe client initialized", "baseURL", stripeBaseURL)
-	} else {
-		slog.Info("Stripe client initialized")
-	}
-
-	// initialize database and cache connections
-	dsn := viper.GetString(config.DatabaseDSN)
-	logLevel := viper.GetString(config.DatabaseLogLevel)
-
-	db, err := database.NewConnection(dsn, logLevel)
-	if err != nil {
-		return nil, err
-	}
-	servers := viper.GetStringSlice(config.CacheHosts)
-	cacheDB := cache.NewConnection(servers)
-	atomicProcessor := database.NewAtomicProcess(db)
-	bus := bus.New()
-	emailClient, err := email.New()
+	svcs, err := NewServices(conf)
 	if err != nil {
 		return nil, err
 	}
+	accountSvc, billingSvc, businessSvc := svcs.Account, svcs.Billing, svcs.Business
+	inventorySvc, accountingSvc, customerSvc, orderSvc := svcs.Inventory, svcs.Accounting, svcs.Customer, svcs.Order
+	storefrontSvc, auditSvc := svcs.Storefront, svcs.Audit
 
-	// asset/blob storage (uploads)
-	blobProvider, err := blob.FromConfig()
-	if err != nil {
+	// server initialization logic
+	if err := request.RegisterValidators(); err != nil {
 		return nil, err
 	}
-	assetStorage := asset.NewStorage(db, cacheDB)
-	assetSvc := asset.NewService(assetStorage, atomicProcessor, blobProvider)
-
-	// DI - create storages first
-	accountStorage := account.NewStorage(db, cacheDB)
-	billingStorage := billing.NewStorage(db, cacheDB)
-
-	// Create services with email integrations
-	accountSvc := account.NewService(accountStorage, atomicProcessor, bus, emailClient)
-
-	billingSvc := billing.NewService(billingStorage, atomicProcessor, bus, accountSvc, emailClient)
-
-	// Note: Plan auto-sync is now handled in the server command (cmd/server.go)
-	// This keeps server initialization clean and allows sync to run asynchronously
-
-	businessStorage := business.NewStorage(db, cacheDB)
-	businessSvc := business.NewService(businessStorage, atomicProcessor, bus)
-
-	inventoryStorage := inventory.NewStorage(db, cacheDB)
-	inventorySvc := inventory.NewService(inventoryStorage, atomicProcessor, bus)
-
-	accountingStorage := accounting.NewStorage(db, cacheDB)
-	accountingSvc := accounting.NewService(accountingStorage, atomicProcessor, bus)
-	accounting.NewBusHandler(bus, accountingSvc, businessSvc)
-
-	customerStorage := customer.NewStorage(db, cacheDB)
-	customerSvc := customer.NewService(customerStorage, atomicProcessor, bus)
-
-	orderStorage := order.NewStorage(db, cacheDB)
-	orderSvc := order.NewService(orderStorage, atomicProcessor, bus, inventorySvc, customerSvc, businessSvc)
-
-	storefrontStorage := storefront.NewStorage(db, cacheDB)
-	storefrontSvc := storefront.NewService(storefrontStorage, atomicProcessor, businessSvc, inventorySvc, customerSvc, orderSvc)
-
-	analyticsSvc := analytics.NewService(&analytics.ServiceParams{
-		Inventory:  inventorySvc,
-		Orders:     orderSvc,
-		Accounting: accountingSvc,
-		Customer:   customerSvc,
-	})
-
-	// onboarding routes
-	onboardingStorage := onboarding.NewStorage(db, cacheDB)
-	onboardingSvc := onboarding.NewService(onboardingStorage, atomicProcessor, accountSvc, billingSvc, businessSvc, emailClient)
-
-	// server initialization logic
 	r := gin.New()
 	r.Use(logger.Middleware())
 	r.Use(request.LimitBodySize(viper.GetInt64(config.HTTPMaxBodyBytes)))
 	r.Use(gin.Recovery())
+	// Sensitive requests are audited; registered before any route so every group is covered.
+	r.Use(audit.NewMiddleware(auditSvc, audit.DefaultRules()...))
 
 	// health endpoint
 	r.GET("/healthz", func(c *gin.Context) { response.SuccessText(c, 200, "ok") })
@@ -232,37 +188,54 @@ func New(opts ...func(*ServerConfig)) (*Server, error) {
 	registerBillingRoutes(r, billing.NewHttpHandler(billingSvc, accountSvc), accountSvc)
 
 	// Public storefront routes (no auth required)
-	registerStorefrontRoutes(r, storefront.NewHttpHandler(storefrontSvc))
+	registerStorefrontRoutes(r, storefront.NewHttpHandler(storefrontSvc), storefrontSvc)
 
 	// Register account routes with plan limit enforcement for team members
 	registerAccountRoutes(r, account.NewHttpHandler(accountSvc), accountSvc, billingSvc)
 
 	// Register onboarding routes
-	registerOnboardingRoutes(r, onboarding.NewHttpHandler(onboardingSvc))
+	registerOnboardingRoutes(r, onboarding.NewHttpHandler(svcs.Onboarding))
 
 	// Public metadata routes (no auth required)
 	registerMetadataRoutes(r, metadata.NewHttpHandler())
 
-	accountingHandler := accounting.NewHttpHandler(accountingSvc, orderSvc)
-	analyticsHandler := analytics.NewHttpHandler(analyticsSvc)
+	accountingHandler := accounting.NewHttpHandler(accountingSvc, orderSvc, inventorySvc)
+	analyticsHandler := analytics.NewHttpHandler(svcs.Analytics)
 	customerHandler := customer.NewHttpHandler(customerSvc)
 	inventoryHandler := inventory.NewHttpHandler(inventorySvc)
 	orderHandler := order.NewHttpHandler(orderSvc)
 	businessHandler := business.NewHttpHandler(businessSvc)
-	assetHandler := asset.NewHttpHandler(assetSvc)
+	assetHandler := asset.NewHttpHandler(svcs.Asset)
+	taskHandler := task.NewHttpHandler(svcs.Task)
+	reviewHandler := review.NewHttpHandler(svcs.Review)
+	sampleDataHandler := sampledata.NewHttpHandler(svcs.SampleData)
+	storeImportHandler := storeimport.NewHttpHandler(svcs.StoreImport)
+	searchHandler := search.NewHttpHandler(svcs.Search)
 
 	// Public asset serving routes (no auth required)
 	registerPublicAssetRoutes(r, assetHandler)
 
 	// Register business-scoped routes
-	registerBusinessScopedRoutes(r, accountSvc, billingSvc, businessSvc, businessHandler, assetHandler, accountingHandler, analyticsHandler, customerHandler, inventoryHandler, orderHandler)
+	registerBusinessScopedRoutes(r, accountSvc, billingSvc, businessSvc, businessHandler, assetHandler, accountingHandler, analyticsHandler, customerHandler, inventoryHandler, orderHandler, taskHandler, reviewHandler, sampleDataHandler, storeImportHandler, searchHandler)
+
+	// Inbound email webhook of the per-business expense inboxes
+	registerExpenseInboxWebhookRoutes(r, accountingHandler)
 
 	// Register business routes
 	registerBusinessRoutes(r, businessHandler, accountSvc, billingSvc, businessSvc)
 
+	// Workspace-wide analytics across businesses
+	registerWorkspaceAnalyticsRoutes(r, analyticsHandler, accountSvc)
+
 	// Customer, inventory, analytics, and accounting routes are registered under business-scoped routes.
 
-	return &Server{r: r, db: db, cacheDB: cacheDB, billingSvc: billingSvc}, nil
+	// Workspace audit log of sensitive requests
+	registerAuditRoutes(r, audit.NewHttpHandler(auditSvc), accountSvc)
+
+	// Operator routes (dead letters)
+	registerAdminRoutes(r, deadletter.NewHttpHandler(svcs.DeadLetter))
+
+	return &Server{r: r, db: svcs.DB, cacheDB: svcs.Cache, billingSvc: billingSvc}, nil
 }
 
 func (s *Server) Start() error {
@@ -0,0 +1,266 @@
+package server
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/accounting"
+	"github.com/abdelrahman146/kyora/internal/domain/analytics"
+	"github.com/abdelrahman146/kyora/internal/domain/asset"
+	"github.com/abdelrahman146/kyora/internal/domain/audit"
+	"github.com/abdelrahman146/kyora/internal/domain/billing"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/deadletter"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/onboarding"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/domain/review"
+	"github.com/abdelrahman146/kyora/internal/domain/sampledata"
+	"github.com/abdelrahman146/kyora/internal/domain/search"
+	"github.com/abdelrahman146/kyora/internal/domain/storefront"
+	"github.com/abdelrahman146/kyora/internal/domain/storeimport"
+	"github.com/abdelrahman146/kyora/internal/domain/task"
+	"github.com/abdelrahman146/kyora/internal/platform/blob"
+	"github.com/abdelrahman146/kyora/internal/platform/bus"
+	"github.com/abdelrahman146/kyora/internal/platform/cache"
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/email"
+	"github.com/abdelrahman146/kyora/internal/platform/fx"
+	"github.com/abdelrahman146/kyora/internal/platform/ocr"
+	"github.com/abdelrahman146/kyora/internal/platform/whatsapp"
+	"github.com/spf13/viper"
+	"github.com/stripe/stripe-go/v83"
+)
+
+// busDrainTimeout bounds how long Services.Close waits for queued events to be handled.
+const busDrainTimeout = time.Minute
+
+// Services are the domain services wired to each other and to the event bus, with every
+// bus handler registered. The HTTP server and the scheduled commands share this wiring so
+// events emitted by a command reach the same consumers as events emitted by a request.
+type Services struct {
+	DB      *database.Database
+	Cache   *cache.Cache
+	Bus     *bus.Bus
+	Email   email.Client
+	Account *account.Service
+	Billing *billing.Service
+
+	Business    *business.Service
+	Inventory   *inventory.Service
+	Accounting  *accounting.Service
+	Customer    *customer.Service
+	Order       *order.Service
+	Task        *task.Service
+	Review      *review.Service
+	Search      *search.Service
+	SampleData  *sampledata.Service
+	StoreImport *storeimport.Service
+	Storefront  *storefront.Service
+	Analytics   *analytics.Service
+	Onboarding  *onboarding.Service
+	Asset       *asset.Service
+	Audit       *audit.Service
+	DeadLetter  *deadletter.Service
+}
+
+// NewServices connects to the database of every region and the cache and wires the domain
+// services. Options of conf that only concern the HTTP listener are ignored.
+func NewServices(conf *ServerConfig) (*Services, error) {
+	if conf == nil {
+		conf = &ServerConfig{}
+	}
+
+	// initialize stripe client
+	stripeAPIKey := viper.GetString(config.StripeAPIKey)
+	stripe.Key = stripeAPIKey
+	stripe.SetAppInfo(&stripe.AppInfo{Name: "Kyora", Version: "1.0", URL: "https://github.com/abdelrahman146/kyora"})
+	stripeBaseURL := conf.StripeBaseURL
+	if stripeBaseURL == "" {
+		stripeBaseURL = viper.GetString(config.StripeAPIBaseURL)
+	}
+	if stripeBaseURL != "" {
+		backend := stripe.GetBackendWithConfig(stripe.APIBackend, &stripe.BackendConfig{
+			URL: &stripeBaseURL, // optional custom base URL for testing if not provided it will automatically use the default
+		})
+		stripe.SetBackend(stripe.APIBackend, backend)
+		slog.Info("Stripe client initialized", "baseURL", stripeBaseURL)
+	} else {
+		slog.Info("Stripe client initialized")
+	}
+
+	// initialize database and cache connections
+	dsn := viper.GetString(config.DatabaseDSN)
+	logLevel := viper.GetString(config.DatabaseLogLevel)
+
+	db, err := database.NewConnection(dsn, logLevel)
+	if err != nil {
+		return nil, err
+	}
+	// Workspaces whose data must stay in another region get their own database.
+	if err := database.ConnectRegions(db, logLevel); err != nil {
+		db.CloseConnection()
+		return nil, err
+	}
+	servers := viper.GetStringSlice(config.CacheHosts)
+	cacheDB := cache.NewConnection(servers)
+	svcs, err := wireServices(db, cacheDB, conf)
+	if err != nil {
+		db.CloseConnection()
+		return nil, err
+	}
+	return svcs, nil
+}
+
+func wireServices(db *database.Database, cacheDB *cache.Cache, conf *ServerConfig) (*Services, error) {
+	atomicProcessor := database.NewAtomicProcess(db)
+	// Control-plane services keep their transactions in the home region.
+	globalAtomicProcessor := database.NewAtomicProcess(db.Global())
+	bus := bus.New()
+	// Failed bus events are persisted so operators can inspect and replay them.
+	deadLetterSvc := deadletter.NewService(deadletter.NewStorage(db), bus)
+	auditSvc := audit.NewService(audit.NewStorage(db))
+	emailClient, err := email.New()
+	if err != nil {
+		return nil, err
+	}
+
+	// asset/blob storage (uploads)
+	blobProvider, err := blob.FromConfig()
+	if err != nil {
+		return nil, err
+	}
+	assetStorage := asset.NewStorage(db, cacheDB)
+	assetSvc := asset.NewService(assetStorage, atomicProcessor, blobProvider)
+
+	// DI - create storages first
+	accountStorage := account.NewStorage(db, cacheDB)
+	billingStorage := billing.NewStorage(db, cacheDB)
+
+	// Create services with email integrations
+	accountSvc := account.NewService(accountStorage, globalAtomicProcessor, bus, emailClient)
+	if conf.GoogleOAuth != nil {
+		accountSvc.SetGoogleOAuthProvider(conf.GoogleOAuth)
+	}
+	if conf.OIDC != nil {
+		accountSvc.SetOIDCProvider(conf.OIDC)
+	}
+	if conf.TXTResolver != nil {
+		accountSvc.SetTXTResolver(conf.TXTResolver)
+	}
+
+	billingSvc := billing.NewService(billingStorage, globalAtomicProcessor, bus, accountSvc, emailClient)
+	accountSvc.SetSSOEntitlement(billingSvc)
+	accountSvc.SetTeamSeatLimit(billingSvc)
+	billing.NewBusHandler(bus, billingSvc)
+
+	// Note: Plan auto-sync is now handled in the server command (cmd/server.go)
+	// This keeps server initialization clean and allows sync to run asynchronously
+
+	businessStorage := business.NewStorage(db, cacheDB)
+	businessSvc := business.NewService(businessStorage, atomicProcessor, bus)
+	businessSvc.SetCarriers(business.CarriersFromConfig()...)
+
+	inventoryStorage := inventory.NewStorage(db, cacheDB)
+	inventorySvc := inventory.NewService(inventoryStorage, atomicProcessor, bus)
+	inventorySvc.SetPhotoAssets(assetSvc)
+
+	accountingStorage := accounting.NewStorage(db, cacheDB)
+	accountingSvc := accounting.NewService(accountingStorage, atomicProcessor, bus)
+	receiptOCR, err := ocr.New()
+	if err != nil {
+		return nil, err
+	}
+	accountingSvc.SetReceiptIntake(assetSvc, receiptOCR, businessSvc)
+	fxRates, err := fx.New()
+	if err != nil {
+		return nil, err
+	}
+	accountingSvc.SetFXProvider(fxRates)
+	accountingSvc.SetPayablesProvider(inventorySvc)
+	accounting.NewBusHandler(bus, accountingSvc, businessSvc)
+
+	customerStorage := customer.NewStorage(db, cacheDB)
+	customerSvc := customer.NewService(customerStorage, atomicProcessor, bus, inventorySvc)
+
+	orderStorage := order.NewStorage(db, cacheDB)
+	orderSvc := order.NewService(orderStorage, atomicProcessor, bus, inventorySvc, customerSvc, businessSvc)
+	orderSvc.SetLogoStore(assetSvc)
+	orderSvc.SetEmailClient(emailClient)
+	order.NewBusHandler(bus, orderSvc)
+
+	taskSvc := task.NewService(task.NewStorage(db), atomicProcessor, accountSvc, inventorySvc, customerSvc, orderSvc)
+	task.NewBusHandler(bus, taskSvc)
+
+	reviewSvc := review.NewService(review.NewStorage(db, cacheDB), businessSvc, inventorySvc, orderSvc, emailClient)
+	review.NewBusHandler(bus, reviewSvc)
+
+	searchSvc := search.NewService(search.NewStorage(db))
+
+	sampleDataSvc := sampledata.NewService(sampledata.NewStorage(db), inventorySvc, customerSvc, orderSvc, accountingSvc)
+
+	storeImportSvc := storeimport.NewService(storeimport.NewStorage(db), atomicProcessor, bus, businessSvc, inventorySvc, customerSvc, orderSvc)
+	storeimport.NewBusHandler(bus, storeImportSvc)
+
+	storefrontStorage := storefront.NewStorage(db, cacheDB)
+	storefrontSvc := storefront.NewService(storefrontStorage, atomicProcessor, businessSvc, inventorySvc, customerSvc, orderSvc, reviewSvc)
+	storefrontSvc.SetEmailClient(emailClient)
+	whatsappClient, err := whatsapp.New()
+	if err != nil {
+		return nil, err
+	}
+	storefrontSvc.SetWhatsappClient(whatsappClient)
+
+	analyticsSvc := analytics.NewService(&analytics.ServiceParams{
+		Storage:    analytics.NewStorage(db),
+		Inventory:  inventorySvc,
+		Orders:     orderSvc,
+		Accounting: accountingSvc,
+		Customer:   customerSvc,
+		Account:    accountSvc,
+		Business:   businessSvc,
+		Email:      emailClient,
+	})
+
+	// onboarding routes
+	onboardingStorage := onboarding.NewStorage(db, cacheDB)
+	onboardingSvc := onboarding.NewService(onboardingStorage, globalAtomicProcessor, accountSvc, billingSvc, businessSvc, emailClient)
+
+	return &Services{
+		DB:          db,
+		Cache:       cacheDB,
+		Bus:         bus,
+		Email:       emailClient,
+		Account:     accountSvc,
+		Billing:     billingSvc,
+		Business:    businessSvc,
+		Inventory:   inventorySvc,
+		Accounting:  accountingSvc,
+		Customer:    customerSvc,
+		Order:       orderSvc,
+		Task:        taskSvc,
+		Review:      reviewSvc,
+		Search:      searchSvc,
+		SampleData:  sampleDataSvc,
+		StoreImport: storeImportSvc,
+		Storefront:  storefrontSvc,
+		Analytics:   analyticsSvc,
+		Onboarding:  onboardingSvc,
+		Asset:       assetSvc,
+		Audit:       auditSvc,
+		DeadLetter:  deadLetterSvc,
+	}, nil
+}
+
+// Close waits for the events still queued on the bus to be handled, then stops the bus and
+// closes the database connections. Commands call it before exiting.
+func (s *Services) Close() error {
+	if !s.Bus.Drain(busDrainTimeout) {
+		slog.Warn("bus events still pending at shutdown are dropped", "timeout", busDrainTimeout)
+	}
+	s.Bus.Close()
+	return s.DB.CloseConnection()
+}
@@ -0,0 +1,210 @@
+package e2e_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var customRoleTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans", "custom_roles", "user_invitations",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes",
+}
+
+// CustomRolesSuite tests workspace custom roles built from the permission matrix.
+type CustomRolesSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *CustomRolesSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *CustomRolesSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, customRoleTables...))
+}
+
+func (s *CustomRolesSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, customRoleTables...))
+}
+
+type customRolesFixture struct {
+	owner       *testutils.Owner
+	member      *account.User
+	memberToken string
+	biz         *business.Business
+	ord         *order.Order
+}
+
+func (s *CustomRolesSuite) setup(ctx context.Context) *customRolesFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	ord, err := s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: v, Quantity: 1}}, func(o *order.Order) {
+		o.Status = order.OrderStatusPlaced
+	})
+	s.Require().NoError(err)
+
+	member := &account.User{
+		WorkspaceID:     owner.Workspace.ID,
+		Role:            role.RoleUser,
+		FirstName:       "Staff",
+		LastName:        "Member",
+		Email:           fmt.Sprintf("staff-%s@example.com", owner.Workspace.ID),
+		IsEmailVerified: true,
+	}
+	s.Require().NoError(database.NewRepository[account.User](testEnv.Database).CreateOne(ctx, member))
+	token, err := auth.NewJwtToken(member.ID, member.WorkspaceID, member.AuthVersion)
+	s.Require().NoError(err)
+	return &customRolesFixture{owner: owner, member: member, memberToken: token, biz: biz, ord: ord}
+}
+
+func (s *CustomRolesSuite) do(token, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, path, payload, token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNoContent {
+		return resp.StatusCode, nil
+	}
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func (s *CustomRolesSuite) createRole(fx *customRolesFixture, name string, permissions ...string) string {
+	status, body := s.do(fx.owner.Token, "POST", "/v1/workspaces/roles", map[string]interface{}{
+		"name":        name,
+		"permissions": permissions,
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	return body["id"].(string)
+}
+
+func errorCode(body map[string]interface{}) interface{} {
+	return body["extensions"].(map[string]interface{})["code"]
+}
+
+func (s *CustomRolesSuite) TestCustomRole_GrantsAndRevokesAccess() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	statusPath := "/v1/businesses/" + fx.biz.Descriptor + "/orders/" + fx.ord.ID + "/status"
+	customersPath := "/v1/businesses/" + fx.biz.Descriptor + "/customers"
+
+	status, _ := s.do(fx.memberToken, "PATCH", statusPath, map[string]interface{}{"status": "shipped"})
+	s.Equal(http.StatusForbidden, status)
+	status, _ = s.do(fx.memberToken, "GET", customersPath, nil)
+	s.Equal(http.StatusOK, status)
+
+	roleID := s.createRole(fx, "Fulfilment", "view:order", "manage:order", "view:order")
+
+	status, body := s.do(fx.owner.Token, "PATCH", "/v1/workspaces/users/"+fx.member.ID+"/role", map[string]interface{}{
+		"role":         "user",
+		"customRoleId": roleID,
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(roleID, body["customRoleId"])
+	s.Equal("Fulfilment", body["customRoleName"])
+	s.ElementsMatch([]interface{}{"manage:order", "view:order"}, body["permissions"])
+
+	status, body = s.do(fx.memberToken, "PATCH", statusPath, map[string]interface{}{"status": "shipped"})
+	s.Equal(http.StatusOK, status, body)
+	// permissions the custom role does not list are denied
+	status, _ = s.do(fx.memberToken, "GET", customersPath, nil)
+	s.Equal(http.StatusForbidden, status)
+
+	// changes to the role apply to its members on their next request
+	status, body = s.do(fx.owner.Token, "PATCH", "/v1/workspaces/roles/"+roleID, map[string]interface{}{
+		"permissions": []string{"view:order", "view:customer"},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	status, _ = s.do(fx.memberToken, "PATCH", statusPath, map[string]interface{}{"status": "fulfilled"})
+	s.Equal(http.StatusForbidden, status)
+	status, _ = s.do(fx.memberToken, "GET", customersPath, nil)
+	s.Equal(http.StatusOK, status)
+
+	status, body = s.do(fx.owner.Token, "GET", "/v1/workspaces/users?customRoleId="+roleID, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Len(body["items"], 1)
+
+	// a role in use cannot be deleted
+	status, body = s.do(fx.owner.Token, "DELETE", "/v1/workspaces/roles/"+roleID, nil)
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("account.custom_role_in_use", errorCode(body))
+
+	// omitting customRoleId removes the custom role
+	status, body = s.do(fx.owner.Token, "PATCH", "/v1/workspaces/users/"+fx.member.ID+"/role", map[string]interface{}{"role": "user"})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Nil(body["customRoleId"])
+	status, _ = s.do(fx.owner.Token, "DELETE", "/v1/workspaces/roles/"+roleID, nil)
+	s.Equal(http.StatusNoContent, status)
+}
+
+func (s *CustomRolesSuite) TestCustomRole_Validation() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	status, body := s.do(fx.owner.Token, "POST", "/v1/workspaces/roles", map[string]interface{}{
+		"name":        "Broken",
+		"permissions": []string{"approve:order"},
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("account.invalid_permission", errorCode(body))
+
+	roleID := s.createRole(fx, "Stock Keeper", "view:inventory", "manage:inventory", "approve:inventory")
+	status, body = s.do(fx.owner.Token, "POST", "/v1/workspaces/roles", map[string]interface{}{
+		"name":        "stock keeper",
+		"permissions": []string{"view:inventory"},
+	})
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("account.custom_role_name_taken", errorCode(body))
+
+	// admins already have every permission
+	status, body = s.do(fx.owner.Token, "PATCH", "/v1/workspaces/users/"+fx.member.ID+"/role", map[string]interface{}{
+		"role":         "admin",
+		"customRoleId": roleID,
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("account.custom_role_members_only", errorCode(body))
+
+	// members cannot define roles
+	status, _ = s.do(fx.memberToken, "POST", "/v1/workspaces/roles", map[string]interface{}{
+		"name":        "Escalated",
+		"permissions": []string{"manage:account"},
+	})
+	s.Equal(http.StatusForbidden, status)
+
+	status, body = s.do(fx.memberToken, "GET", "/v1/workspaces/roles/permissions", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.NotEmpty(body["resources"])
+
+	stranger, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	status, _ = s.do(stranger.Token, "GET", "/v1/workspaces/roles/"+roleID, nil)
+	s.Equal(http.StatusNotFound, status)
+}
+
+func TestCustomRolesSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(CustomRolesSuite))
+}
@@ -0,0 +1,181 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var emailChangeTables = []string{"users", "workspaces", "user_invitations", "sessions"}
+
+// EmailChangeSuite tests changing the login email: POST /v1/users/me/email and
+// POST /v1/auth/email-change/confirm|undo
+type EmailChangeSuite struct {
+	suite.Suite
+	helper *AccountTestHelper
+}
+
+func (s *EmailChangeSuite) SetupSuite() {
+	s.helper = NewAccountTestHelper(testEnv.Database, testEnv.CacheAddr, e2eBaseURL)
+}
+
+func (s *EmailChangeSuite) SetupTest() {
+	s.NoError(testEnv.Cache.FlushAll())
+	s.NoError(testutils.TruncateTables(testEnv.Database, emailChangeTables...))
+}
+
+func (s *EmailChangeSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, emailChangeTables...))
+}
+
+func (s *EmailChangeSuite) do(token, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, path, payload, token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNoContent {
+		return resp.StatusCode, nil
+	}
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func (s *EmailChangeSuite) login(email, password string) int {
+	status, _ := s.do("", "POST", "/v1/auth/login", map[string]interface{}{"email": email, "password": password})
+	return status
+}
+
+func (s *EmailChangeSuite) TestRequestEmailChange() {
+	ctx := context.Background()
+	user, _, token, err := s.helper.CreateTestUser(ctx, "jane@example.com", "Password123!", "Jane", "Doe", role.RoleAdmin)
+	s.Require().NoError(err)
+	_, _, _, err = s.helper.CreateTestUser(ctx, "taken@example.com", "Password123!", "Tom", "Doe", role.RoleAdmin)
+	s.Require().NoError(err)
+
+	status, body := s.do(token, "POST", "/v1/users/me/email", map[string]interface{}{"newEmail": "new@example.com", "password": "wrong-password"})
+	s.Equal(http.StatusForbidden, status, body)
+	s.Equal("account.invalid_current_password", errorCode(body))
+
+	status, body = s.do(token, "POST", "/v1/users/me/email", map[string]interface{}{"newEmail": "JANE@example.com", "password": "Password123!"})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("account.email_unchanged", errorCode(body))
+
+	status, body = s.do(token, "POST", "/v1/users/me/email", map[string]interface{}{"newEmail": "taken@example.com", "password": "Password123!"})
+	s.Equal(http.StatusConflict, status, body)
+
+	status, _ = s.do(token, "POST", "/v1/users/me/email", map[string]interface{}{"newEmail": "not-an-email", "password": "Password123!"})
+	s.Equal(http.StatusBadRequest, status)
+
+	status, body = s.do(token, "POST", "/v1/users/me/email", map[string]interface{}{"newEmail": "New@Example.com", "password": "Password123!"})
+	s.Equal(http.StatusNoContent, status, body)
+
+	// nothing changes until the new address confirms
+	stored, err := s.helper.GetUser(ctx, user.ID)
+	s.Require().NoError(err)
+	s.Equal("jane@example.com", stored.Email)
+
+	status, _ = s.do("", "POST", "/v1/users/me/email", map[string]interface{}{"newEmail": "new@example.com"})
+	s.Equal(http.StatusUnauthorized, status)
+}
+
+func (s *EmailChangeSuite) TestConfirmAndUndoEmailChange() {
+	ctx := context.Background()
+	user, ws, accessToken, err := s.helper.CreateTestUser(ctx, "jane@example.com", "Password123!", "Jane", "Doe", role.RoleAdmin)
+	s.Require().NoError(err)
+	invitation, err := s.helper.CreateInvitation(ctx, ws.ID, "jane@example.com", user.ID, role.RoleAdmin, account.InvitationStatusAccepted)
+	s.Require().NoError(err)
+	other, err := s.helper.CreateInvitation(ctx, ws.ID, "someone@example.com", user.ID, role.RoleUser, account.InvitationStatusPending)
+	s.Require().NoError(err)
+	s.Require().NoError(s.helper.MarkEmailUnverified(ctx, user.ID))
+
+	token, err := s.helper.CreateEmailChangeToken(ctx, user, "new@example.com")
+	s.Require().NoError(err)
+	status, body := s.do("", "POST", "/v1/auth/email-change/confirm", map[string]interface{}{"token": token})
+	s.Require().Equal(http.StatusNoContent, status, body)
+
+	stored, err := s.helper.GetUser(ctx, user.ID)
+	s.Require().NoError(err)
+	s.Equal("new@example.com", stored.Email)
+	s.True(stored.IsEmailVerified)
+	inv, err := s.helper.GetInvitation(ctx, invitation.ID)
+	s.Require().NoError(err)
+	s.Equal("new@example.com", inv.Email)
+	inv, err = s.helper.GetInvitation(ctx, other.ID)
+	s.Require().NoError(err)
+	s.Equal("someone@example.com", inv.Email)
+
+	s.Equal(http.StatusOK, s.login("new@example.com", "Password123!"))
+	s.Equal(http.StatusUnauthorized, s.login("jane@example.com", "Password123!"))
+	// confirming keeps the user signed in
+	status, _ = s.do(accessToken, "GET", "/v1/users/me", nil)
+	s.Equal(http.StatusOK, status)
+
+	// confirmation links are single use
+	status, body = s.do("", "POST", "/v1/auth/email-change/confirm", map[string]interface{}{"token": token})
+	s.Equal(http.StatusUnauthorized, status, body)
+
+	undoToken, _, err := s.helper.Storage.CreateEmailChangeUndoToken(&account.EmailChangePayload{
+		UserID:      user.ID,
+		WorkspaceID: ws.ID,
+		OldEmail:    "jane@example.com",
+		NewEmail:    "new@example.com",
+	})
+	s.Require().NoError(err)
+	status, body = s.do("", "POST", "/v1/auth/email-change/undo", map[string]interface{}{"token": undoToken})
+	s.Require().Equal(http.StatusNoContent, status, body)
+
+	stored, err = s.helper.GetUser(ctx, user.ID)
+	s.Require().NoError(err)
+	s.Equal("jane@example.com", stored.Email)
+	inv, err = s.helper.GetInvitation(ctx, invitation.ID)
+	s.Require().NoError(err)
+	s.Equal("jane@example.com", inv.Email)
+	// undoing signs the user out everywhere
+	status, _ = s.do(accessToken, "GET", "/v1/users/me", nil)
+	s.Equal(http.StatusUnauthorized, status)
+	s.Equal(http.StatusOK, s.login("jane@example.com", "Password123!"))
+
+	status, _ = s.do("", "POST", "/v1/auth/email-change/undo", map[string]interface{}{"token": undoToken})
+	s.Equal(http.StatusUnauthorized, status)
+}
+
+func (s *EmailChangeSuite) TestConfirmRefusesStaleChanges() {
+	ctx := context.Background()
+	user, _, _, err := s.helper.CreateTestUser(ctx, "jane@example.com", "Password123!", "Jane", "Doe", role.RoleAdmin)
+	s.Require().NoError(err)
+
+	first, err := s.helper.CreateEmailChangeToken(ctx, user, "first@example.com")
+	s.Require().NoError(err)
+	second, err := s.helper.CreateEmailChangeToken(ctx, user, "second@example.com")
+	s.Require().NoError(err)
+	taken, err := s.helper.CreateEmailChangeToken(ctx, user, "taken@example.com")
+	s.Require().NoError(err)
+
+	// the address was registered after the change was requested
+	_, _, _, err = s.helper.CreateTestUser(ctx, "taken@example.com", "Password123!", "Tom", "Doe", role.RoleAdmin)
+	s.Require().NoError(err)
+	status, body := s.do("", "POST", "/v1/auth/email-change/confirm", map[string]interface{}{"token": taken})
+	s.Equal(http.StatusConflict, status, body)
+
+	status, body = s.do("", "POST", "/v1/auth/email-change/confirm", map[string]interface{}{"token": first})
+	s.Require().Equal(http.StatusNoContent, status, body)
+
+	// the second request was made for the previous email
+	status, body = s.do("", "POST", "/v1/auth/email-change/confirm", map[string]interface{}{"token": second})
+	s.Equal(http.StatusUnauthorized, status, body)
+
+	status, _ = s.do("", "POST", "/v1/auth/email-change/confirm", map[string]interface{}{"token": "unknown"})
+	s.Equal(http.StatusUnauthorized, status)
+}
+
+func TestEmailChangeSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(EmailChangeSuite))
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
 	"github.com/abdelrahman146/kyora/internal/platform/database"
 	"github.com/abdelrahman146/kyora/internal/platform/types/role"
 	"github.com/abdelrahman146/kyora/internal/tests/testutils"
@@ -30,6 +31,7 @@ func (s *GoogleOAuthSuite) SetupSuite() {
 func (s *GoogleOAuthSuite) SetupTest() {
 	err := testutils.TruncateTables(testEnv.Database, "users", "workspaces", "sessions")
 	s.NoError(err)
+	testGoogleOAuth.Reset()
 }
 
 func (s *GoogleOAuthSuite) TearDownTest() {
@@ -343,6 +345,47 @@ func (s *GoogleOAuthSuite) TestLoginWithGoogle_UserWithPassword() {
 	s.Equal(workspace.ID, fetched.WorkspaceID)
 }
 
+// TestLoginWithGoogle_Success tests OAuth login for a user whose Google email has an account
+func (s *GoogleOAuthSuite) TestLoginWithGoogle_Success() {
+	ctx := context.Background()
+	user, _, _, err := s.helper.CreateTestUser(ctx, "regular@gmail.com", "Password123!", "Regular", "User", role.RoleAdmin)
+	s.Require().NoError(err)
+	testGoogleOAuth.RegisterCode("valid_google_code", auth.GoogleUserInfo{
+		Email:      user.Email,
+		GivenName:  "Regular",
+		FamilyName: "User",
+		Verified:   true,
+	})
+
+	resp, err := s.helper.Client.Post("/v1/auth/google/login", map[string]interface{}{"code": "valid_google_code"})
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var result map[string]interface{}
+	s.NoError(testutils.DecodeJSON(resp, &result))
+	s.NotEmpty(result["token"])
+	s.NotEmpty(result["refreshToken"])
+	userData, ok := result["user"].(map[string]interface{})
+	s.Require().True(ok, "user should be an object")
+	s.Equal(user.ID, userData["id"])
+	s.Equal(user.Email, userData["email"])
+}
+
+// TestLoginWithGoogle_UnknownEmail tests OAuth login for a valid Google profile without an account
+func (s *GoogleOAuthSuite) TestLoginWithGoogle_UnknownEmail() {
+	testGoogleOAuth.RegisterCode("stranger_code", auth.GoogleUserInfo{Email: "stranger@gmail.com", Verified: true})
+
+	resp, err := s.helper.Client.Post("/v1/auth/google/login", map[string]interface{}{"code": "stranger_code"})
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	code, err := testutils.GetErrorCode(resp)
+	s.NoError(err)
+	s.Equal("account.google_no_account", code)
+}
+
 // TestLoginWithGoogle_RateLimiting tests multiple rapid requests
 func (s *GoogleOAuthSuite) TestLoginWithGoogle_RateLimiting() {
 	payload := map[string]interface{}{
@@ -383,6 +426,7 @@ func (s *GoogleInvitationAcceptanceSuite) SetupSuite() {
 func (s *GoogleInvitationAcceptanceSuite) SetupTest() {
 	err := testutils.TruncateTables(testEnv.Database, "users", "workspaces", "user_invitations", "sessions")
 	s.NoError(err)
+	testGoogleOAuth.Reset()
 }
 
 func (s *GoogleInvitationAcceptanceSuite) TearDownTest() {
@@ -549,6 +593,44 @@ func (s *GoogleInvitationAcceptanceSuite) TestAcceptInvitationWithGoogle_ValidTo
 	s.Equal(account.InvitationStatusPending, found.Status, "invitation should remain pending after failed OAuth")
 }
 
+// TestAcceptInvitationWithGoogle_Success tests that a matching Google profile accepts the invitation
+func (s *GoogleInvitationAcceptanceSuite) TestAcceptInvitationWithGoogle_Success() {
+	ctx := context.Background()
+	inviter, workspace, _, err := s.helper.CreateTestUser(ctx, "inviter@example.com", "Password123!", "Inviter", "User", role.RoleAdmin)
+	s.Require().NoError(err)
+	invitation, token, err := s.helper.CreateInvitationWithToken(ctx, workspace.ID, "newuser@gmail.com", inviter.ID, role.RoleUser)
+	s.Require().NoError(err)
+	testGoogleOAuth.RegisterCode("invitee_code", auth.GoogleUserInfo{
+		Email:      "newuser@gmail.com",
+		GivenName:  "New",
+		FamilyName: "User",
+		Verified:   true,
+	})
+
+	resp, err := s.helper.Client.Get(fmt.Sprintf("/v1/invitations/accept/google?token=%s&code=invitee_code", token))
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var result map[string]interface{}
+	s.NoError(testutils.DecodeJSON(resp, &result))
+	s.NotEmpty(result["token"])
+	s.NotEmpty(result["refreshToken"])
+
+	userRepo := database.NewRepository[account.User](testEnv.Database)
+	created, err := userRepo.FindOne(ctx, userRepo.ScopeEquals(account.UserSchema.Email, "newuser@gmail.com"))
+	s.Require().NoError(err)
+	s.Equal(workspace.ID, created.WorkspaceID)
+	s.Equal(role.RoleUser, created.Role)
+	s.Equal("New", created.FirstName)
+	s.Empty(created.Password, "Google users should not get a password")
+
+	invRepo := database.NewRepository[account.UserInvitation](testEnv.Database)
+	found, err := invRepo.FindByID(ctx, invitation.ID)
+	s.Require().NoError(err)
+	s.Equal(account.InvitationStatusAccepted, found.Status)
+}
+
 // TestAcceptInvitationWithGoogle_ExpiredToken tests expired invitation handling
 func (s *GoogleInvitationAcceptanceSuite) TestAcceptInvitationWithGoogle_ExpiredToken() {
 	ctx := context.Background()
@@ -96,6 +96,17 @@ func (h *AccountTestHelper) SetInvitationStatus(ctx context.Context, invitationI
 	return invitationRepo.UpdateOne(ctx, invitation)
 }
 
+// SetInvitationExpiry sets when an invitation's link stops working
+func (h *AccountTestHelper) SetInvitationExpiry(ctx context.Context, invitationID string, expiresAt time.Time) error {
+	invitationRepo := database.NewRepository[account.UserInvitation](h.db)
+	invitation, err := invitationRepo.FindByID(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	invitation.ExpiresAt = &expiresAt
+	return invitationRepo.UpdateOne(ctx, invitation)
+}
+
 // GetUser retrieves a user by ID using repository
 func (h *AccountTestHelper) GetUser(ctx context.Context, userID string) (*account.User, error) {
 	userRepo := database.NewRepository[account.User](h.db)
@@ -119,6 +130,16 @@ func (h *AccountTestHelper) CreatePasswordResetToken(ctx context.Context, user *
 	return testutils.CreatePasswordResetToken(ctx, h.Storage, user)
 }
 
+// CreateEmailChangeToken creates the confirmation token of a change of user's email to newEmail
+func (h *AccountTestHelper) CreateEmailChangeToken(ctx context.Context, user *account.User, newEmail string) (string, error) {
+	return testutils.CreateEmailChangeToken(ctx, h.Storage, user, newEmail)
+}
+
+// CreateMagicLinkToken creates a one-time sign-in token
+func (h *AccountTestHelper) CreateMagicLinkToken(ctx context.Context, user *account.User) (string, error) {
+	return testutils.CreateMagicLinkToken(ctx, h.Storage, user)
+}
+
 // CreateEmailVerificationToken creates an email verification token
 func (h *AccountTestHelper) CreateEmailVerificationToken(ctx context.Context, user *account.User) (string, error) {
 	return testutils.CreateEmailVerificationToken(ctx, h.Storage, user)
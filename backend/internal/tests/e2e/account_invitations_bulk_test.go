@@ -0,0 +1,288 @@
+package e2e_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var invitationBulkTables = []string{"users", "workspaces", "user_invitations", "sessions", "subscriptions", "plans"}
+
+// InvitationResendBulkSuite tests POST /v1/workspaces/invitations/bulk,
+// POST /v1/workspaces/invitations/:invitationId/resend and invitation expiry
+type InvitationResendBulkSuite struct {
+	suite.Suite
+	helper *AccountTestHelper
+}
+
+func (s *InvitationResendBulkSuite) SetupSuite() {
+	s.helper = NewAccountTestHelper(testEnv.Database, testEnv.CacheAddr, e2eBaseURL)
+}
+
+func (s *InvitationResendBulkSuite) SetupTest() {
+	s.NoError(testEnv.Cache.FlushAll())
+	s.NoError(testutils.TruncateTables(testEnv.Database, invitationBulkTables...))
+}
+
+func (s *InvitationResendBulkSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, invitationBulkTables...))
+}
+
+func (s *InvitationResendBulkSuite) do(token, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, path, payload, token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNoContent {
+		return resp.StatusCode, nil
+	}
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func (s *InvitationResendBulkSuite) owner(ctx context.Context) (*account.User, *account.Workspace, string) {
+	user, ws, token, err := s.helper.CreateTestUser(ctx, "owner@example.com", "Password123!", "Owner", "User", role.RoleAdmin)
+	s.Require().NoError(err)
+	s.Require().NoError(s.helper.CreateTestSubscription(ctx, ws.ID))
+	return user, ws, token
+}
+
+func (s *InvitationResendBulkSuite) accept(token string) (int, map[string]interface{}) {
+	return s.do("", "POST", fmt.Sprintf("/v1/invitations/accept?token=%s", token), map[string]interface{}{
+		"firstName": "New",
+		"lastName":  "User",
+		"password":  "NewUserPassword123!",
+	})
+}
+
+func (s *InvitationResendBulkSuite) TestBulkInvite_ReportsEachRow() {
+	ctx := context.Background()
+	_, ws, token := s.owner(ctx)
+
+	status, body := s.do(token, "POST", "/v1/workspaces/invitations/bulk", map[string]interface{}{
+		"expiresInDays": 3,
+		"invitations": []map[string]interface{}{
+			{"email": "ann@example.com", "role": "user"},
+			{"email": "bob@example.com", "role": "admin"},
+			{"email": "ann@example.com", "role": "user"},
+			{"email": "owner@example.com", "role": "user"},
+			{"email": "cat@example.com", "role": "admin", "customRoleId": "role_unknown"},
+		},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(float64(2), body["invited"])
+	s.Equal(float64(3), body["failed"])
+
+	results := body["results"].([]interface{})
+	s.Require().Len(results, 5)
+	expected := []struct {
+		email  string
+		status string
+		code   string
+	}{
+		{"ann@example.com", "invited", ""},
+		{"bob@example.com", "invited", ""},
+		{"ann@example.com", "failed", "account.invitation_already_exists"},
+		{"owner@example.com", "failed", "account.user_already_exists"},
+		{"cat@example.com", "failed", "account.custom_role_members_only"},
+	}
+	for i, want := range expected {
+		row := results[i].(map[string]interface{})
+		s.Equal(want.email, row["email"])
+		s.Equal(want.status, row["status"])
+		if want.code == "" {
+			invitation := row["invitation"].(map[string]interface{})
+			s.Equal("pending", invitation["status"])
+			expiresAt, err := time.Parse(time.RFC3339, invitation["expiresAt"].(string))
+			s.Require().NoError(err)
+			s.WithinDuration(time.Now().AddDate(0, 0, 3), expiresAt, time.Minute)
+			s.Nil(row["error"])
+		} else {
+			s.Equal(want.code, row["error"].(map[string]interface{})["code"])
+			s.Nil(row["invitation"])
+		}
+	}
+
+	pending, err := s.helper.ListWorkspaceInvitations(ctx, ws.ID, account.InvitationStatusPending)
+	s.Require().NoError(err)
+	s.Len(pending, 2)
+}
+
+func (s *InvitationResendBulkSuite) TestBulkInvite_StopsAtTeamMemberLimit() {
+	ctx := context.Background()
+	_, ws, token := s.owner(ctx)
+
+	// the test plan allows 10 team members and the owner takes one
+	rows := make([]map[string]interface{}, 12)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"email": fmt.Sprintf("member%d@example.com", i), "role": "user"}
+	}
+	status, body := s.do(token, "POST", "/v1/workspaces/invitations/bulk", map[string]interface{}{"invitations": rows})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(float64(9), body["invited"])
+	s.Equal(float64(3), body["failed"])
+	results := body["results"].([]interface{})
+	for _, r := range results[9:] {
+		row := r.(map[string]interface{})
+		s.Equal("failed", row["status"])
+		s.Equal("billing.feature_limit_reached", row["error"].(map[string]interface{})["code"])
+	}
+
+	pending, err := s.helper.ListWorkspaceInvitations(ctx, ws.ID, account.InvitationStatusPending)
+	s.Require().NoError(err)
+	s.Len(pending, 9)
+}
+
+func (s *InvitationResendBulkSuite) TestBulkInvite_Validation() {
+	ctx := context.Background()
+	_, _, token := s.owner(ctx)
+
+	status, _ := s.do(token, "POST", "/v1/workspaces/invitations/bulk", map[string]interface{}{"invitations": []interface{}{}})
+	s.Equal(http.StatusBadRequest, status)
+
+	tooMany := make([]map[string]interface{}, 51)
+	for i := range tooMany {
+		tooMany[i] = map[string]interface{}{"email": fmt.Sprintf("member%d@example.com", i), "role": "user"}
+	}
+	status, _ = s.do(token, "POST", "/v1/workspaces/invitations/bulk", map[string]interface{}{"invitations": tooMany})
+	s.Equal(http.StatusBadRequest, status)
+
+	status, _ = s.do(token, "POST", "/v1/workspaces/invitations/bulk", map[string]interface{}{
+		"invitations": []map[string]interface{}{{"email": "not-an-email", "role": "user"}},
+	})
+	s.Equal(http.StatusBadRequest, status)
+
+	status, _ = s.do(token, "POST", "/v1/workspaces/invitations/bulk", map[string]interface{}{
+		"invitations":   []map[string]interface{}{{"email": "ann@example.com", "role": "user"}},
+		"expiresInDays": 31,
+	})
+	s.Equal(http.StatusBadRequest, status)
+
+	status, _ = s.do("", "POST", "/v1/workspaces/invitations/bulk", map[string]interface{}{
+		"invitations": []map[string]interface{}{{"email": "ann@example.com", "role": "user"}},
+	})
+	s.Equal(http.StatusUnauthorized, status)
+}
+
+func (s *InvitationResendBulkSuite) TestInviteUser_CustomExpiry() {
+	ctx := context.Background()
+	_, _, token := s.owner(ctx)
+
+	status, body := s.do(token, "POST", "/v1/workspaces/invitations", map[string]interface{}{
+		"email":         "ann@example.com",
+		"role":          "user",
+		"expiresInDays": 14,
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	expiresAt, err := time.Parse(time.RFC3339, body["expiresAt"].(string))
+	s.Require().NoError(err)
+	s.WithinDuration(time.Now().AddDate(0, 0, 14), expiresAt, time.Minute)
+
+	status, _ = s.do(token, "POST", "/v1/workspaces/invitations", map[string]interface{}{
+		"email":         "bob@example.com",
+		"role":          "user",
+		"expiresInDays": 0.5,
+	})
+	s.Equal(http.StatusBadRequest, status)
+}
+
+func (s *InvitationResendBulkSuite) TestResendInvitation_ReplacesLink() {
+	ctx := context.Background()
+	owner, ws, token := s.owner(ctx)
+	invitation, oldToken, err := s.helper.CreateInvitationWithToken(ctx, ws.ID, "ann@example.com", owner.ID, role.RoleUser)
+	s.Require().NoError(err)
+
+	path := fmt.Sprintf("/v1/workspaces/invitations/%s/resend", invitation.ID)
+	status, body := s.do(token, "POST", path, map[string]interface{}{"expiresInDays": 2})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(invitation.ID, body["id"])
+	s.Equal("pending", body["status"])
+	expiresAt, err := time.Parse(time.RFC3339, body["expiresAt"].(string))
+	s.Require().NoError(err)
+	s.WithinDuration(time.Now().AddDate(0, 0, 2), expiresAt, time.Minute)
+
+	// resending again right away is throttled
+	status, body = s.do(token, "POST", path, nil)
+	s.Equal(http.StatusTooManyRequests, status, body)
+
+	// the link sent first no longer works
+	status, body = s.accept(oldToken)
+	s.Equal(http.StatusUnauthorized, status, body)
+	s.Equal("account.invalid_invitation_token", errorCode(body))
+
+	newToken, err := s.helper.CreateInvitationToken(ctx, invitation.ID)
+	s.Require().NoError(err)
+	status, body = s.accept(newToken)
+	s.Require().Equal(http.StatusOK, status, body)
+
+	stored, err := s.helper.GetInvitation(ctx, invitation.ID)
+	s.Require().NoError(err)
+	s.Equal(account.InvitationStatusAccepted, stored.Status)
+}
+
+func (s *InvitationResendBulkSuite) TestResendInvitation_OnlyPending() {
+	ctx := context.Background()
+	owner, ws, token := s.owner(ctx)
+	accepted, err := s.helper.CreateInvitation(ctx, ws.ID, "ann@example.com", owner.ID, role.RoleUser, account.InvitationStatusAccepted)
+	s.Require().NoError(err)
+	revoked, err := s.helper.CreateInvitation(ctx, ws.ID, "bob@example.com", owner.ID, role.RoleUser, account.InvitationStatusRevoked)
+	s.Require().NoError(err)
+
+	for _, inv := range []*account.UserInvitation{accepted, revoked} {
+		status, body := s.do(token, "POST", fmt.Sprintf("/v1/workspaces/invitations/%s/resend", inv.ID), nil)
+		s.Equal(http.StatusConflict, status, body)
+		s.Equal("account.invitation_cannot_be_resent", errorCode(body))
+	}
+
+	status, _ := s.do(token, "POST", "/v1/workspaces/invitations/inv_unknown/resend", nil)
+	s.Equal(http.StatusNotFound, status)
+
+	// invitations of other workspaces are not found
+	otherOwner, otherWs, _, err := s.helper.CreateTestUser(ctx, "other@example.com", "Password123!", "Other", "Owner", role.RoleAdmin)
+	s.Require().NoError(err)
+	foreign, err := s.helper.CreateInvitation(ctx, otherWs.ID, "cat@example.com", otherOwner.ID, role.RoleUser, account.InvitationStatusPending)
+	s.Require().NoError(err)
+	status, _ = s.do(token, "POST", fmt.Sprintf("/v1/workspaces/invitations/%s/resend", foreign.ID), nil)
+	s.Equal(http.StatusNotFound, status)
+
+	member, _, memberToken, err := s.helper.CreateTestUser(ctx, "member@example.com", "Password123!", "Member", "User", role.RoleUser)
+	s.Require().NoError(err)
+	mine, err := s.helper.CreateInvitation(ctx, member.WorkspaceID, "dan@example.com", member.ID, role.RoleUser, account.InvitationStatusPending)
+	s.Require().NoError(err)
+	status, _ = s.do(memberToken, "POST", fmt.Sprintf("/v1/workspaces/invitations/%s/resend", mine.ID), nil)
+	s.Equal(http.StatusForbidden, status)
+}
+
+func (s *InvitationResendBulkSuite) TestAcceptInvitation_RefusesExpiredLink() {
+	ctx := context.Background()
+	owner, ws, token := s.owner(ctx)
+	invitation, oldToken, err := s.helper.CreateInvitationWithToken(ctx, ws.ID, "ann@example.com", owner.ID, role.RoleUser)
+	s.Require().NoError(err)
+	s.Require().NoError(s.helper.SetInvitationExpiry(ctx, invitation.ID, time.Now().Add(-time.Hour)))
+
+	status, body := s.accept(oldToken)
+	s.Equal(http.StatusForbidden, status, body)
+	s.Equal("account.invitation_expired", errorCode(body))
+
+	// resending revives it with a fresh link
+	status, body = s.do(token, "POST", fmt.Sprintf("/v1/workspaces/invitations/%s/resend", invitation.ID), nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	newToken, err := s.helper.CreateInvitationToken(ctx, invitation.ID)
+	s.Require().NoError(err)
+	status, body = s.accept(newToken)
+	s.Equal(http.StatusOK, status, body)
+}
+
+func TestInvitationResendBulkSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(InvitationResendBulkSuite))
+}
@@ -0,0 +1,122 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+// MagicLinkSuite tests passwordless sign-in
+// POST /v1/auth/magic-link and POST /v1/auth/magic-link/login
+type MagicLinkSuite struct {
+	suite.Suite
+	helper *AccountTestHelper
+}
+
+func (s *MagicLinkSuite) SetupSuite() {
+	s.helper = NewAccountTestHelper(testEnv.Database, testEnv.CacheAddr, e2eBaseURL)
+}
+
+func (s *MagicLinkSuite) SetupTest() {
+	s.NoError(testEnv.Cache.FlushAll())
+	s.NoError(testutils.TruncateTables(testEnv.Database, "users", "workspaces", "user_invitations", "sessions"))
+}
+
+func (s *MagicLinkSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, "users", "workspaces", "user_invitations", "sessions"))
+}
+
+func (s *MagicLinkSuite) post(path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.Post(path, payload)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNoContent {
+		return resp.StatusCode, nil
+	}
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func (s *MagicLinkSuite) TestRequestMagicLink() {
+	ctx := context.Background()
+	_, _, _, err := s.helper.CreateTestUser(ctx, "jane@example.com", "Password123!", "Jane", "Doe", role.RoleAdmin)
+	s.Require().NoError(err)
+
+	status, _ := s.post("/v1/auth/magic-link", map[string]interface{}{"email": "jane@example.com"})
+	s.Equal(http.StatusNoContent, status)
+
+	// unknown emails look the same to prevent email enumeration
+	status, _ = s.post("/v1/auth/magic-link", map[string]interface{}{"email": "nobody@example.com"})
+	s.Equal(http.StatusNoContent, status)
+
+	status, _ = s.post("/v1/auth/magic-link", map[string]interface{}{"email": "not-an-email"})
+	s.Equal(http.StatusBadRequest, status)
+
+	status, body := s.post("/v1/auth/magic-link", map[string]interface{}{"email": "jane@example.com"})
+	s.Equal(http.StatusTooManyRequests, status, body)
+}
+
+func (s *MagicLinkSuite) TestLoginWithMagicLink() {
+	ctx := context.Background()
+	user, _, _, err := s.helper.CreateTestUser(ctx, "jane@example.com", "Password123!", "Jane", "Doe", role.RoleAdmin)
+	s.Require().NoError(err)
+	s.Require().NoError(s.helper.MarkEmailUnverified(ctx, user.ID))
+
+	token, err := s.helper.CreateMagicLinkToken(ctx, user)
+	s.Require().NoError(err)
+	status, body := s.post("/v1/auth/magic-link/login", map[string]interface{}{"token": token})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.NotEmpty(body["token"])
+	s.NotEmpty(body["refreshToken"])
+	s.Equal(user.ID, body["user"].(map[string]interface{})["id"])
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/users/me", nil, body["token"].(string))
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	stored, err := s.helper.GetUser(ctx, user.ID)
+	s.Require().NoError(err)
+	s.True(stored.IsEmailVerified)
+
+	// links are single use
+	status, body = s.post("/v1/auth/magic-link/login", map[string]interface{}{"token": token})
+	s.Equal(http.StatusUnauthorized, status, body)
+
+	status, _ = s.post("/v1/auth/magic-link/login", map[string]interface{}{"token": "unknown"})
+	s.Equal(http.StatusUnauthorized, status)
+
+	status, _ = s.post("/v1/auth/magic-link/login", map[string]interface{}{})
+	s.Equal(http.StatusBadRequest, status)
+}
+
+func (s *MagicLinkSuite) TestLoginWithMagicLink_RefusesLinksOfPreviousEmail() {
+	ctx := context.Background()
+	user, _, _, err := s.helper.CreateTestUser(ctx, "jane@example.com", "Password123!", "Jane", "Doe", role.RoleAdmin)
+	s.Require().NoError(err)
+	token, err := s.helper.CreateMagicLinkToken(ctx, user)
+	s.Require().NoError(err)
+
+	userRepo := database.NewRepository[account.User](testEnv.Database)
+	stored, err := s.helper.GetUser(ctx, user.ID)
+	s.Require().NoError(err)
+	stored.Email = "jane.new@example.com"
+	s.Require().NoError(userRepo.UpdateOne(ctx, stored))
+
+	status, body := s.post("/v1/auth/magic-link/login", map[string]interface{}{"token": token})
+	s.Equal(http.StatusUnauthorized, status, body)
+}
+
+func TestMagicLinkSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(MagicLinkSuite))
+}
@@ -0,0 +1,202 @@
+package e2e_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var permissionFlagTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes",
+}
+
+// PermissionFlagsSuite tests member permission flags: managing operations and hiding financials.
+type PermissionFlagsSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *PermissionFlagsSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *PermissionFlagsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, permissionFlagTables...))
+}
+
+func (s *PermissionFlagsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, permissionFlagTables...))
+}
+
+type permissionFlagsFixture struct {
+	owner       *testutils.Owner
+	member      *account.User
+	memberToken string
+	biz         *business.Business
+	ord         *order.Order
+}
+
+func (s *PermissionFlagsSuite) setup(ctx context.Context) *permissionFlagsFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	ord, err := s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: v, Quantity: 1}}, func(o *order.Order) {
+		o.Status = order.OrderStatusPlaced
+	})
+	s.Require().NoError(err)
+
+	member := &account.User{
+		WorkspaceID:     owner.Workspace.ID,
+		Role:            role.RoleUser,
+		FirstName:       "Staff",
+		LastName:        "Member",
+		Email:           fmt.Sprintf("staff-%s@example.com", owner.Workspace.ID),
+		IsEmailVerified: true,
+	}
+	s.Require().NoError(database.NewRepository[account.User](testEnv.Database).CreateOne(ctx, member))
+	token, err := auth.NewJwtToken(member.ID, member.WorkspaceID, member.AuthVersion)
+	s.Require().NoError(err)
+	return &permissionFlagsFixture{owner: owner, member: member, memberToken: token, biz: biz, ord: ord}
+}
+
+func (s *PermissionFlagsSuite) do(token, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, path, payload, token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func (s *PermissionFlagsSuite) setFlags(fx *permissionFlagsFixture, flags map[string]interface{}) map[string]interface{} {
+	status, body := s.do(fx.owner.Token, "PATCH", "/v1/workspaces/users/"+fx.member.ID+"/permissions", flags)
+	s.Require().Equal(http.StatusOK, status, body)
+	return body
+}
+
+func (s *PermissionFlagsSuite) TestManageOperations_LetsMemberUpdateOrders() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	statusPath := "/v1/businesses/" + fx.biz.Descriptor + "/orders/" + fx.ord.ID + "/status"
+
+	status, _ := s.do(fx.memberToken, "PATCH", statusPath, map[string]interface{}{"status": "shipped"})
+	s.Equal(http.StatusForbidden, status)
+
+	body := s.setFlags(fx, map[string]interface{}{"canManageOperations": true})
+	s.Equal(true, body["canManageOperations"])
+	s.Equal(false, body["restrictFinancials"])
+
+	status, body = s.do(fx.memberToken, "PATCH", statusPath, map[string]interface{}{"status": "shipped"})
+	s.Equal(http.StatusOK, status, body)
+
+	// billing and workspace management stay admin-only
+	status, _ = s.do(fx.memberToken, "PATCH", "/v1/workspaces/users/"+fx.owner.User.ID+"/permissions", map[string]interface{}{"restrictFinancials": true})
+	s.Equal(http.StatusForbidden, status)
+}
+
+func (s *PermissionFlagsSuite) TestRestrictFinancials_HidesCostsAndAccounting() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	orderPath := "/v1/businesses/" + fx.biz.Descriptor + "/orders/" + fx.ord.ID
+
+	status, body := s.do(fx.memberToken, "GET", orderPath, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Contains(body, "cogs")
+
+	s.setFlags(fx, map[string]interface{}{"restrictFinancials": true})
+
+	status, body = s.do(fx.memberToken, "GET", orderPath, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.NotContains(body, "cogs")
+	s.Equal("100", body["total"])
+	items := body["items"].([]interface{})
+	s.Require().Len(items, 1)
+	s.NotContains(items[0], "unitCost")
+	s.NotContains(items[0], "totalCost")
+
+	status, body = s.do(fx.memberToken, "GET", "/v1/businesses/"+fx.biz.Descriptor+"/orders", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.NotContains(body["items"].([]interface{})[0], "cogs")
+
+	costTrendPath := "/inventory/variants/" + fx.ord.Items[0].VariantID + "/cost-trend"
+	for _, path := range []string{"/accounting/summary", "/accounting/expenses", "/inventory/valuation", costTrendPath} {
+		status, _ = s.do(fx.memberToken, "GET", "/v1/businesses/"+fx.biz.Descriptor+path, nil)
+		s.Equal(http.StatusForbidden, status, path)
+	}
+
+	// admins always see financials
+	status, body = s.do(fx.owner.Token, "GET", orderPath, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Contains(body, "cogs")
+}
+
+func (s *PermissionFlagsSuite) TestRestrictFinancials_BlocksSupplierBills() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	billsPath := "/v1/businesses/" + fx.biz.Descriptor + "/inventory/supplier-bills"
+	s.setFlags(fx, map[string]interface{}{"canManageOperations": true})
+
+	status, body := s.do(fx.memberToken, "GET", billsPath, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	// recording bill and purchase order payments is accounting work, not operations
+	status, _ = s.do(fx.memberToken, "POST", billsPath+"/bill_missing/payments", map[string]interface{}{"amount": "10"})
+	s.Equal(http.StatusForbidden, status)
+	status, _ = s.do(fx.memberToken, "POST", "/v1/businesses/"+fx.biz.Descriptor+"/inventory/purchase-orders/po_missing/payments", map[string]interface{}{"amount": "10"})
+	s.Equal(http.StatusForbidden, status)
+
+	s.setFlags(fx, map[string]interface{}{"canManageOperations": true, "restrictFinancials": true})
+
+	for _, path := range []string{billsPath, billsPath + "/aging", billsPath + "/bill_missing/payments"} {
+		status, _ = s.do(fx.memberToken, "GET", path, nil)
+		s.Equal(http.StatusForbidden, status, path)
+	}
+
+	status, body = s.do(fx.owner.Token, "GET", billsPath+"/aging", nil)
+	s.Equal(http.StatusOK, status, body)
+}
+
+func (s *PermissionFlagsSuite) TestUpdatePermissions_Validation() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	status, body := s.do(fx.owner.Token, "PATCH", "/v1/workspaces/users/"+fx.owner.User.ID+"/permissions", map[string]interface{}{"restrictFinancials": true})
+	s.Equal(http.StatusForbidden, status, body)
+	s.Equal("account.cannot_update_own_permissions", body["extensions"].(map[string]interface{})["code"])
+
+	status, body = s.do(fx.owner.Token, "PATCH", "/v1/workspaces/users/"+fx.member.ID+"/role", map[string]interface{}{"role": "admin"})
+	s.Require().Equal(http.StatusOK, status, body)
+	status, body = s.do(fx.owner.Token, "PATCH", "/v1/workspaces/users/"+fx.member.ID+"/permissions", map[string]interface{}{"restrictFinancials": true})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("account.permission_flags_members_only", body["extensions"].(map[string]interface{})["code"])
+
+	stranger, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	status, _ = s.do(fx.owner.Token, "PATCH", "/v1/workspaces/users/"+stranger.User.ID+"/permissions", map[string]interface{}{"restrictFinancials": true})
+	s.Equal(http.StatusNotFound, status)
+}
+
+func TestPermissionFlagsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(PermissionFlagsSuite))
+}
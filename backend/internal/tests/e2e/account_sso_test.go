@@ -0,0 +1,385 @@
+package e2e_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/billing"
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+var ssoTables = []string{"users", "workspaces", "subscriptions", "plans", "custom_roles", "sso_connections", "sessions"}
+
+const ssoDomain = "acme.example.com"
+
+// SSOSuite tests enterprise single sign-on: workspace setup, the OIDC sign-in flow and
+// just-in-time provisioning.
+type SSOSuite struct {
+	suite.Suite
+	client  *testutils.HTTPClient
+	factory *testutils.Factory
+}
+
+func (s *SSOSuite) SetupSuite() {
+	s.client = testutils.NewHTTPClient(e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *SSOSuite) SetupTest() {
+	testOIDC.Reset()
+	testDNS.Reset()
+	s.NoError(testutils.TruncateTables(testEnv.Database, ssoTables...))
+}
+
+func (s *SSOSuite) TearDownTest() {
+	testOIDC.Reset()
+	testDNS.Reset()
+	s.NoError(testutils.TruncateTables(testEnv.Database, ssoTables...))
+}
+
+func (s *SSOSuite) do(token, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.client.AuthenticatedRequest(method, path, payload, token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNoContent {
+		return resp.StatusCode, nil
+	}
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+// enterpriseOwner creates a workspace subscribed to a plan that includes single sign-on.
+func (s *SSOSuite) enterpriseOwner(ctx context.Context) *testutils.Owner {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	planRepo := database.NewRepository[billing.Plan](testEnv.Database)
+	plan, err := planRepo.FindOne(ctx, planRepo.ScopeEquals(billing.PlanSchema.Descriptor, "test_enterprise_sso"))
+	if database.IsRecordNotFound(err) {
+		stripePlanID := "stripe_test_plan_enterprise_sso"
+		plan = &billing.Plan{
+			Descriptor:   "test_enterprise_sso",
+			Name:         "Test Enterprise Plan",
+			StripePlanID: &stripePlanID,
+			Price:        decimal.NewFromInt(0),
+			Currency:     "aed",
+			BillingCycle: billing.BillingCycleMonthly,
+			Features:     billing.PlanFeature{CustomerManagement: true, OrderManagement: true, SingleSignOn: true},
+			Limits:       billing.PlanLimit{MaxOrdersPerMonth: -1, MaxTeamMembers: -1, MaxBusinesses: -1},
+		}
+		err = planRepo.CreateOne(ctx, plan)
+	}
+	s.Require().NoError(err)
+	subRepo := database.NewRepository[billing.Subscription](testEnv.Database)
+	sub, err := subRepo.FindOne(ctx, subRepo.ScopeWorkspaceID(owner.Workspace.ID))
+	s.Require().NoError(err)
+	sub.PlanID = plan.ID
+	sub.Plan = nil
+	s.Require().NoError(subRepo.UpdateOne(ctx, sub))
+	return owner
+}
+
+func (s *SSOSuite) putOIDC(owner *testutils.Owner, overrides map[string]interface{}) (int, map[string]interface{}) {
+	payload := map[string]interface{}{
+		"protocol":         "oidc",
+		"domain":           ssoDomain,
+		"enabled":          true,
+		"oidcIssuer":       "https://idp.acme.example.com",
+		"oidcClientId":     "kyora",
+		"oidcClientSecret": "idp-secret",
+	}
+	for k, v := range overrides {
+		payload[k] = v
+	}
+	return s.do(owner.Token, "PUT", "/v1/workspaces/sso", payload)
+}
+
+// setUpOIDC saves the connection disabled, proves the domain with the TXT record it asks for,
+// then saves it again as requested.
+func (s *SSOSuite) setUpOIDC(owner *testutils.Owner, overrides map[string]interface{}) (int, map[string]interface{}) {
+	disabled := map[string]interface{}{"enabled": false}
+	for k, v := range overrides {
+		if k != "enabled" {
+			disabled[k] = v
+		}
+	}
+	status, body := s.putOIDC(owner, disabled)
+	if status != http.StatusOK {
+		return status, body
+	}
+	testDNS.Publish(body["verificationRecordName"].(string), body["verificationRecordValue"].(string))
+	status, body = s.do(owner.Token, "POST", "/v1/workspaces/sso/verify-domain", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	return s.putOIDC(owner, overrides)
+}
+
+// signIn runs the SSO flow for email, with the provider signing in identity.
+func (s *SSOSuite) signIn(email string, identity auth.OIDCIdentity) (int, map[string]interface{}) {
+	status, body := s.do("", "POST", "/v1/auth/sso/start", map[string]interface{}{"email": email})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Require().True(strings.HasPrefix(body["url"].(string), "https://idp.acme.example.com/authorize?"), body["url"])
+	state := body["state"].(string)
+	code := fmt.Sprintf("code-%d", time.Now().UnixNano())
+	testOIDC.RegisterCode(code, identity)
+	return s.do("", "POST", "/v1/auth/sso/callback", map[string]interface{}{"state": state, "code": code})
+}
+
+func (s *SSOSuite) TestSSO_RequiresEnterprisePlan() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+
+	status, body := s.putOIDC(owner, nil)
+	s.Equal(http.StatusForbidden, status, body)
+	s.Equal("billing.feature_not_available", errorCode(body))
+}
+
+func (s *SSOSuite) TestSSO_ProvisionsUsersWithMappedRoles() {
+	ctx := context.Background()
+	owner := s.enterpriseOwner(ctx)
+	roleID := func() string {
+		status, body := s.do(owner.Token, "POST", "/v1/workspaces/roles", map[string]interface{}{
+			"name":        "Warehouse",
+			"permissions": []string{"view:inventory", "manage:inventory"},
+		})
+		s.Require().Equal(http.StatusCreated, status, body)
+		return body["id"].(string)
+	}()
+
+	status, body := s.setUpOIDC(owner, map[string]interface{}{
+		"jitProvisioning": true,
+		"groupsClaim":     "groups",
+		"roleMappings": []map[string]interface{}{
+			{"group": "kyora-admins", "role": "admin"},
+			{"group": "warehouse", "role": "user", "customRoleId": roleID},
+		},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(true, body["hasOidcClientSecret"])
+	s.NotContains(body, "oidcClientSecret")
+	s.Equal("user", body["defaultRole"])
+
+	// the stored secret is kept when the client does not change
+	status, body = s.putOIDC(owner, map[string]interface{}{"oidcClientSecret": "", "jitProvisioning": true, "groupsClaim": "groups",
+		"roleMappings": []map[string]interface{}{{"group": "warehouse", "role": "user", "customRoleId": roleID}}})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(true, body["hasOidcClientSecret"])
+
+	status, body = s.signIn("Jane@Acme.example.com", auth.OIDCIdentity{
+		Subject: "jane", Email: "jane@acme.example.com", GivenName: "Jane", FamilyName: "Doe", Groups: []string{"staff", "warehouse"},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.NotEmpty(body["token"])
+	s.NotEmpty(body["refreshToken"])
+	user := body["user"].(map[string]interface{})
+	s.Equal(owner.Workspace.ID, user["workspaceId"])
+	s.Equal("user", user["role"])
+	s.Equal(roleID, user["customRoleId"])
+	s.Equal(true, user["isEmailVerified"])
+
+	status, me := s.do(body["token"].(string), "GET", "/v1/users/me", nil)
+	s.Require().Equal(http.StatusOK, status, me)
+	s.Equal("Jane", me["firstName"])
+
+	// returning users sign in to the same account
+	status, again := s.signIn("jane@acme.example.com", auth.OIDCIdentity{Email: "jane@acme.example.com", Groups: []string{"kyora-admins"}})
+	s.Require().Equal(http.StatusOK, status, again)
+	s.Equal(user["id"], again["user"].(map[string]interface{})["id"])
+	s.Equal("user", again["user"].(map[string]interface{})["role"])
+
+	// users without a mapped group get the default role
+	status, body = s.signIn("sam@acme.example.com", auth.OIDCIdentity{Email: "sam@acme.example.com", Name: "Sam Lee"})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("user", body["user"].(map[string]interface{})["role"])
+	s.Equal("Sam", body["user"].(map[string]interface{})["firstName"])
+	s.Nil(body["user"].(map[string]interface{})["customRoleId"])
+
+	// a member of another workspace is never linked nor duplicated, however their email is cased
+	_, err := s.factory.Workspace(ctx, func(u *account.User) { u.Email = "Lee.Chan@ACME.example.com" })
+	s.Require().NoError(err)
+	status, body = s.signIn("lee.chan@acme.example.com", auth.OIDCIdentity{Email: "lee.chan@acme.example.com"})
+	s.Equal(http.StatusForbidden, status, body)
+	s.Equal("account.sso_user_in_other_workspace", errorCode(body))
+	userRepo := database.NewRepository[account.User](testEnv.Database)
+	count, err := userRepo.Count(ctx, userRepo.ScopeWhere("LOWER(email) = ?", "lee.chan@acme.example.com"))
+	s.Require().NoError(err)
+	s.Equal(int64(1), count)
+
+	// a role provisioning still uses cannot be deleted
+	status, body = s.do(owner.Token, "DELETE", "/v1/workspaces/users/"+user["id"].(string), nil)
+	s.Require().Equal(http.StatusNoContent, status, body)
+	status, body = s.do(owner.Token, "DELETE", "/v1/workspaces/roles/"+roleID, nil)
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("account.custom_role_in_use", errorCode(body))
+}
+
+func (s *SSOSuite) TestSSO_RefusesUnknownUsers() {
+	ctx := context.Background()
+	owner := s.enterpriseOwner(ctx)
+	status, body := s.setUpOIDC(owner, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+
+	status, body = s.signIn("new@acme.example.com", auth.OIDCIdentity{Email: "new@acme.example.com"})
+	s.Equal(http.StatusForbidden, status, body)
+	s.Equal("account.sso_user_not_provisioned", errorCode(body))
+
+	status, body = s.signIn("new@acme.example.com", auth.OIDCIdentity{Email: "new@elsewhere.example.com"})
+	s.Equal(http.StatusForbidden, status, body)
+	s.Equal("account.sso_domain_mismatch", errorCode(body))
+
+	other, err := s.factory.Workspace(ctx, func(u *account.User) { u.Email = "taken@" + ssoDomain })
+	s.Require().NoError(err)
+	status, body = s.signIn(other.User.Email, auth.OIDCIdentity{Email: other.User.Email})
+	s.Equal(http.StatusForbidden, status, body)
+	s.Equal("account.sso_user_in_other_workspace", errorCode(body))
+
+	// the owner signs in to their own account
+	status, body = s.setUpOIDC(owner, map[string]interface{}{"domain": "example.com"})
+	s.Require().Equal(http.StatusOK, status, body)
+	status, body = s.do("", "POST", "/v1/auth/sso/start", map[string]interface{}{"email": owner.User.Email})
+	s.Require().Equal(http.StatusOK, status, body)
+	testOIDC.RegisterCode("owner-code", auth.OIDCIdentity{Email: owner.User.Email})
+	state := body["state"].(string)
+	status, body = s.do("", "POST", "/v1/auth/sso/callback", map[string]interface{}{"state": state, "code": "owner-code"})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(owner.User.ID, body["user"].(map[string]interface{})["id"])
+
+	// state is single use
+	status, body = s.do("", "POST", "/v1/auth/sso/callback", map[string]interface{}{"state": state, "code": "owner-code"})
+	s.Equal(http.StatusUnauthorized, status, body)
+
+	status, body = s.do("", "POST", "/v1/auth/sso/start", map[string]interface{}{"email": "someone@unknown.example.org"})
+	s.Equal(http.StatusNotFound, status, body)
+	s.Equal("account.sso_not_configured", errorCode(body))
+
+	// disabled connections do not sign anyone in
+	status, body = s.putOIDC(owner, map[string]interface{}{"domain": "example.com", "enabled": false})
+	s.Require().Equal(http.StatusOK, status, body)
+	status, _ = s.do("", "POST", "/v1/auth/sso/start", map[string]interface{}{"email": owner.User.Email})
+	s.Equal(http.StatusNotFound, status)
+}
+
+func (s *SSOSuite) TestSSO_Configuration() {
+	ctx := context.Background()
+	owner := s.enterpriseOwner(ctx)
+
+	status, body := s.do(owner.Token, "GET", "/v1/workspaces/sso", nil)
+	s.Equal(http.StatusNotFound, status, body)
+
+	status, body = s.putOIDC(owner, map[string]interface{}{"oidcClientSecret": ""})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("account.sso_invalid_config", errorCode(body))
+
+	// issuers must be public https endpoints the server may reach
+	for _, issuer := range []string{"http://idp.acme.example.com", "https://127.0.0.1", "https://localhost", "https://idp.acme.example.com:8443"} {
+		status, body = s.putOIDC(owner, map[string]interface{}{"oidcIssuer": issuer})
+		s.Equal(http.StatusBadRequest, status, issuer)
+		s.Equal("account.sso_invalid_config", errorCode(body), issuer)
+	}
+
+	// public mail domains would sign in anyone with a mailbox there
+	for _, domain := range []string{"gmail.com", "Outlook.com", "yahoo.co.uk", "hotmail.fr"} {
+		status, body = s.putOIDC(owner, map[string]interface{}{"domain": domain})
+		s.Equal(http.StatusBadRequest, status, domain)
+		s.Equal("account.sso_public_domain", errorCode(body), domain)
+	}
+
+	status, body = s.putOIDC(owner, map[string]interface{}{"defaultRole": "admin", "defaultCustomRoleId": "crl_missing"})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("account.custom_role_members_only", errorCode(body))
+
+	other := s.enterpriseOwner(ctx)
+	status, body = s.setUpOIDC(other, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(true, body["domainVerified"])
+	s.NotEmpty(body["domainVerifiedAt"])
+	status, body = s.putOIDC(owner, nil)
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("account.sso_domain_taken", errorCode(body))
+
+	// SAML cannot sign anyone in yet, so it cannot be set up either
+	status, body = s.putOIDC(owner, map[string]interface{}{"protocol": "saml", "domain": "corp.example.com"})
+	s.Equal(http.StatusUnprocessableEntity, status, body)
+	s.Equal("account.sso_protocol_not_supported", errorCode(body))
+
+	status, body = s.setUpOIDC(owner, map[string]interface{}{"domain": "corp.example.com"})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("oidc", body["protocol"])
+	s.NotContains(body, "samlEntityId")
+
+	status, _ = s.do(owner.Token, "DELETE", "/v1/workspaces/sso", nil)
+	s.Equal(http.StatusNoContent, status)
+	status, _ = s.do(owner.Token, "GET", "/v1/workspaces/sso", nil)
+	s.Equal(http.StatusNotFound, status)
+}
+
+func (s *SSOSuite) TestSSO_DomainVerification() {
+	ctx := context.Background()
+	owner := s.enterpriseOwner(ctx)
+
+	// a connection cannot be enabled before its domain is verified
+	status, body := s.putOIDC(owner, nil)
+	s.Equal(http.StatusUnprocessableEntity, status, body)
+	s.Equal("account.sso_domain_not_verified", errorCode(body))
+
+	status, body = s.putOIDC(owner, map[string]interface{}{"enabled": false})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(false, body["domainVerified"])
+	s.Equal("_kyora-verification."+ssoDomain, body["verificationRecordName"])
+	value := body["verificationRecordValue"].(string)
+	s.True(strings.HasPrefix(value, "kyora-verification="), value)
+
+	// an unverified claim does not sign anyone in
+	status, body = s.do("", "POST", "/v1/auth/sso/start", map[string]interface{}{"email": "jane@" + ssoDomain})
+	s.Equal(http.StatusNotFound, status, body)
+
+	// another workspace may claim the domain too, but only the owner of the DNS can verify it
+	squatter := s.enterpriseOwner(ctx)
+	status, body = s.putOIDC(squatter, map[string]interface{}{"enabled": false})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.NotEqual(value, body["verificationRecordValue"])
+
+	status, body = s.do(owner.Token, "POST", "/v1/workspaces/sso/verify-domain", nil)
+	s.Equal(http.StatusUnprocessableEntity, status, body)
+	s.Equal("account.sso_domain_not_verified", errorCode(body))
+
+	testDNS.Publish("_kyora-verification."+ssoDomain, "v=spf1 -all")
+	testDNS.Publish("_kyora-verification."+ssoDomain, value)
+	status, body = s.do(squatter.Token, "POST", "/v1/workspaces/sso/verify-domain", nil)
+	s.Equal(http.StatusUnprocessableEntity, status, body)
+	s.Equal("account.sso_domain_not_verified", errorCode(body))
+
+	status, body = s.do(owner.Token, "POST", "/v1/workspaces/sso/verify-domain", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(true, body["domainVerified"])
+	status, body = s.putOIDC(owner, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(true, body["enabled"])
+
+	// once verified, the domain is the owner's alone
+	status, body = s.putOIDC(squatter, map[string]interface{}{"enabled": false})
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("account.sso_domain_taken", errorCode(body))
+
+	// moving to another domain starts over with a new token
+	status, body = s.putOIDC(owner, map[string]interface{}{"domain": "corp.example.com", "enabled": false})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(false, body["domainVerified"])
+	s.NotEqual(value, body["verificationRecordValue"])
+}
+
+func TestSSOSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(SSOSuite))
+}
@@ -149,6 +149,36 @@ func (s *UserProfileSuite) TestUpdateProfile_EmptyPayload() {
 	_ = user
 }
 
+func (s *UserProfileSuite) TestNotificationPreferences() {
+	ctx := context.Background()
+
+	user, _, token, err := s.helper.CreateTestUser(ctx, "test@example.com", "Password123!", "John", "Doe", role.RoleAdmin)
+	s.NoError(err)
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/users/me/notification-preferences", nil, token)
+	s.NoError(err)
+	var prefs map[string]interface{}
+	s.NoError(testutils.DecodeJSON(resp, &prefs))
+	s.Equal(true, prefs["weeklyDigest"], "the weekly digest is on by default")
+
+	payload := map[string]interface{}{"weeklyDigest": false}
+	resp, err = s.helper.Client.AuthenticatedRequest("PATCH", "/v1/users/me/notification-preferences", payload, token)
+	s.NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.NoError(testutils.DecodeJSON(resp, &prefs))
+	s.Equal(false, prefs["weeklyDigest"])
+
+	stored, err := s.helper.GetUser(ctx, user.ID)
+	s.NoError(err)
+	s.True(stored.WeeklyDigestOptOut)
+
+	// omitted preferences are left unchanged
+	resp, err = s.helper.Client.AuthenticatedRequest("PATCH", "/v1/users/me/notification-preferences", map[string]interface{}{}, token)
+	s.NoError(err)
+	s.NoError(testutils.DecodeJSON(resp, &prefs))
+	s.Equal(false, prefs["weeklyDigest"])
+}
+
 func TestUserProfileSuite(t *testing.T) {
 	if testServer == nil {
 		t.Skip("Test server not initialized")
@@ -230,20 +260,74 @@ func (s *WorkspaceSuite) TestGetWorkspaceUsers_Success() {
 
 	s.Equal(http.StatusOK, resp.StatusCode)
 
-	var result []map[string]interface{}
+	var result map[string]interface{}
 	s.NoError(testutils.DecodeJSON(resp, &result))
 
 	// Should have 3 users (owner + 2 created)
-	s.Len(result, 3)
-
-	// Verify all users belong to same workspace
-	for _, u := range result {
-		s.Equal(workspace.ID, u["workspaceId"])
+	s.Equal(float64(3), result["totalCount"])
+	s.Equal(false, result["hasMore"])
+	items := result["items"].([]interface{})
+	s.Len(items, 3)
+
+	// Verify all users belong to same workspace, owner first
+	for _, it := range items {
+		s.Equal(workspace.ID, it.(map[string]interface{})["workspaceId"])
 	}
+	s.Equal("owner@example.com", items[0].(map[string]interface{})["email"])
 
 	_ = users
 }
 
+func (s *WorkspaceSuite) TestGetWorkspaceUsers_PaginationFiltersAndSearch() {
+	ctx := context.Background()
+
+	_, _, err := testutils.CreateWorkspaceWithUsers(ctx, testEnv.Database, "owner@example.com", "Password123!", []struct {
+		Email     string
+		Password  string
+		FirstName string
+		LastName  string
+		Role      role.Role
+	}{
+		{"user1@example.com", "Password123!", "Sara", "One", role.RoleUser},
+		{"user2@example.com", "Password123!", "Omar", "Two", role.RoleUser},
+		{"admin1@example.com", "Password123!", "Admin", "One", role.RoleAdmin},
+	})
+	s.NoError(err)
+
+	token, err := testutils.LoginAndGetToken(s.helper.Client, "owner@example.com", "Password123!")
+	s.NoError(err)
+
+	get := func(path string) map[string]interface{} {
+		resp, err := s.helper.Client.AuthenticatedRequest("GET", path, nil, token)
+		s.NoError(err)
+		defer resp.Body.Close()
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var result map[string]interface{}
+		s.NoError(testutils.DecodeJSON(resp, &result))
+		return result
+	}
+
+	page := get("/v1/workspaces/users?page=1&pageSize=2")
+	s.Equal(float64(4), page["totalCount"])
+	s.Equal(true, page["hasMore"])
+	s.Len(page["items"].([]interface{}), 2)
+
+	byRole := get("/v1/workspaces/users?role=user")
+	s.Equal(float64(2), byRole["totalCount"])
+	for _, it := range byRole["items"].([]interface{}) {
+		s.Equal("user", it.(map[string]interface{})["role"])
+	}
+
+	bySearch := get("/v1/workspaces/users?search=omar")
+	s.Equal(float64(1), bySearch["totalCount"])
+	s.Equal("user2@example.com", bySearch["items"].([]interface{})[0].(map[string]interface{})["email"])
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/workspaces/users?role=owner", nil, token)
+	s.NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
 func (s *WorkspaceSuite) TestGetWorkspaceUsers_Unauthenticated() {
 	resp, err := s.helper.Client.Get("/v1/workspaces/users")
 	s.NoError(err)
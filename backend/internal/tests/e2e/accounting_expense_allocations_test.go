@@ -0,0 +1,306 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var expenseAllocationTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"expenses", "expense_allocations", "expense_allocation_rules",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items",
+}
+
+// ExpenseAllocationsSuite tests expense allocation endpoints and the product profitability report.
+type ExpenseAllocationsSuite struct {
+	suite.Suite
+	helper  *AccountingTestHelper
+	factory *testutils.Factory
+}
+
+func (s *ExpenseAllocationsSuite) SetupSuite() {
+	s.helper = NewAccountingTestHelper(testEnv.Database, testEnv.CacheAddr, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *ExpenseAllocationsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, expenseAllocationTables...))
+}
+
+func (s *ExpenseAllocationsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, expenseAllocationTables...))
+}
+
+type allocationFixture struct {
+	owner    *testutils.Owner
+	biz      *business.Business
+	products []*inventory.Product
+	variants []*inventory.Variant
+}
+
+// setup creates a business with two products, each with one variant selling for 100 and costing 50.
+func (s *ExpenseAllocationsSuite) setup(ctx context.Context) *allocationFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	fx := &allocationFixture{owner: owner, biz: biz}
+	for range 2 {
+		prod, err := s.factory.Product(ctx, biz.ID)
+		s.Require().NoError(err)
+		v, err := s.factory.Variant(ctx, prod)
+		s.Require().NoError(err)
+		fx.products = append(fx.products, prod)
+		fx.variants = append(fx.variants, v)
+	}
+	return fx
+}
+
+func (s *ExpenseAllocationsSuite) url(fx *allocationFixture, path string) string {
+	return "/v1/businesses/" + fx.biz.Descriptor + path
+}
+
+func (s *ExpenseAllocationsSuite) createExpense(fx *allocationFixture, amount string) string {
+	payload := map[string]interface{}{
+		"category":   "marketing",
+		"type":       "one_time",
+		"amount":     amount,
+		"occurredOn": time.Now().UTC(),
+	}
+	resp, err := s.helper.Client.AuthenticatedRequest("POST", s.url(fx, "/accounting/expenses"), payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var created map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &created))
+	return created["id"].(string)
+}
+
+func (s *ExpenseAllocationsSuite) allocate(fx *allocationFixture, expenseID string, payload map[string]interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest("PUT", s.url(fx, "/accounting/expenses/"+expenseID+"/allocations"), payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func allocationAmounts(body map[string]interface{}) map[string]string {
+	out := map[string]string{}
+	for _, raw := range body["allocations"].([]interface{}) {
+		a := raw.(map[string]interface{})
+		out[a["targetId"].(string)] = a["amount"].(string)
+	}
+	return out
+}
+
+func (s *ExpenseAllocationsSuite) TestAllocateExpense_ByPercentage() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	expenseID := s.createExpense(fx, "100")
+
+	status, body := s.allocate(fx, expenseID, map[string]interface{}{
+		"targetType": "product",
+		"method":     "percentage",
+		"lines": []map[string]interface{}{
+			{"targetId": fx.products[0].ID, "value": "33.33"},
+			{"targetId": fx.products[1].ID, "value": "33.33"},
+		},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("66.66", body["allocated"])
+	s.Equal("33.34", body["unallocated"])
+	amounts := allocationAmounts(body)
+	s.Equal("33.33", amounts[fx.products[0].ID])
+	s.Equal("33.33", amounts[fx.products[1].ID])
+
+	// Re-allocating replaces the previous split.
+	status, body = s.allocate(fx, expenseID, map[string]interface{}{
+		"targetType": "product",
+		"method":     "amount",
+		"lines": []map[string]interface{}{
+			{"targetId": fx.products[0].ID, "value": "40"},
+		},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("40", body["allocated"])
+	s.Len(body["allocations"], 1)
+}
+
+func (s *ExpenseAllocationsSuite) TestAllocateExpense_ValidationErrors() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	expenseID := s.createExpense(fx, "100")
+
+	cases := []struct {
+		name    string
+		payload map[string]interface{}
+	}{
+		{"percentages over 100", map[string]interface{}{
+			"targetType": "product", "method": "percentage",
+			"lines": []map[string]interface{}{
+				{"targetId": fx.products[0].ID, "value": "60"},
+				{"targetId": fx.products[1].ID, "value": "50"},
+			},
+		}},
+		{"amounts over expense", map[string]interface{}{
+			"targetType": "product", "method": "amount",
+			"lines": []map[string]interface{}{
+				{"targetId": fx.products[0].ID, "value": "100.01"},
+			},
+		}},
+		{"duplicate target", map[string]interface{}{
+			"targetType": "product", "method": "amount",
+			"lines": []map[string]interface{}{
+				{"targetId": fx.products[0].ID, "value": "10"},
+				{"targetId": fx.products[0].ID, "value": "10"},
+			},
+		}},
+		{"unknown target", map[string]interface{}{
+			"targetType": "order", "method": "amount",
+			"lines": []map[string]interface{}{
+				{"targetId": "ord_missing", "value": "10"},
+			},
+		}},
+		{"missing rule and lines", map[string]interface{}{}},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			status, body := s.allocate(fx, expenseID, tc.payload)
+			s.Equal(http.StatusBadRequest, status, body)
+		})
+	}
+
+	getResp, err := s.helper.Client.AuthenticatedRequest("GET", s.url(fx, "/accounting/expenses/"+expenseID+"/allocations"), nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer getResp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(getResp, &body))
+	s.Empty(body["allocations"])
+	s.Equal("100", body["unallocated"])
+}
+
+func (s *ExpenseAllocationsSuite) TestAllocationRule_ReusedAndRescaledOnUpdate() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	rulePayload := map[string]interface{}{
+		"name":       "Ads split",
+		"targetType": "product",
+		"method":     "percentage",
+		"lines": []map[string]interface{}{
+			{"targetId": fx.products[0].ID, "value": "75"},
+			{"targetId": fx.products[1].ID, "value": "25"},
+		},
+	}
+	resp, err := s.helper.Client.AuthenticatedRequest("POST", s.url(fx, "/accounting/expense-allocation-rules"), rulePayload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var rule map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &rule))
+	ruleID := rule["id"].(string)
+
+	expenseID := s.createExpense(fx, "200")
+	status, body := s.allocate(fx, expenseID, map[string]interface{}{"ruleId": ruleID})
+	s.Require().Equal(http.StatusOK, status, body)
+	amounts := allocationAmounts(body)
+	s.Equal("150", amounts[fx.products[0].ID])
+	s.Equal("50", amounts[fx.products[1].ID])
+
+	updResp, err := s.helper.Client.AuthenticatedRequest("PATCH", s.url(fx, "/accounting/expenses/"+expenseID), map[string]interface{}{"amount": "100"}, fx.owner.Token)
+	s.Require().NoError(err)
+	defer updResp.Body.Close()
+	s.Require().Equal(http.StatusOK, updResp.StatusCode)
+
+	getResp, err := s.helper.Client.AuthenticatedRequest("GET", s.url(fx, "/accounting/expenses/"+expenseID+"/allocations"), nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer getResp.Body.Close()
+	s.Require().NoError(testutils.DecodeJSON(getResp, &body))
+	amounts = allocationAmounts(body)
+	s.Equal("75", amounts[fx.products[0].ID])
+	s.Equal("25", amounts[fx.products[1].ID])
+
+	delResp, err := s.helper.Client.AuthenticatedRequest("DELETE", s.url(fx, "/accounting/expense-allocation-rules/"+ruleID), nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer delResp.Body.Close()
+	s.Equal(http.StatusNoContent, delResp.StatusCode)
+
+	status, _ = s.allocate(fx, expenseID, map[string]interface{}{"ruleId": ruleID})
+	s.Equal(http.StatusNotFound, status)
+}
+
+func (s *ExpenseAllocationsSuite) TestProductProfitability_IncludesAllocatedExpenses() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	// Product A: 1 unit (revenue 100); product B: 3 units (revenue 300), all in one order.
+	ord, err := s.factory.Order(ctx, fx.biz, []testutils.OrderLine{
+		{Variant: fx.variants[0], Quantity: 1},
+		{Variant: fx.variants[1], Quantity: 3},
+	})
+	s.Require().NoError(err)
+
+	direct := s.createExpense(fx, "100")
+	status, body := s.allocate(fx, direct, map[string]interface{}{
+		"targetType": "product", "method": "percentage",
+		"lines": []map[string]interface{}{{"targetId": fx.products[0].ID, "value": "50"}},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	shipping := s.createExpense(fx, "80")
+	status, body = s.allocate(fx, shipping, map[string]interface{}{
+		"targetType": "order", "method": "amount",
+		"lines": []map[string]interface{}{{"targetId": ord.ID, "value": "80"}},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	s.createExpense(fx, "30")
+
+	today := time.Now().UTC().Format("2006-01-02")
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", s.url(fx, "/analytics/reports/product-profitability?from="+today+"&to="+today), nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var report map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &report))
+	s.Equal("400", report["totalRevenue"])
+	s.Equal("200", report["totalCogs"])
+	s.Equal("210", report["totalExpenses"])
+	s.Equal("130", report["allocatedExpenses"])
+	s.Equal("80", report["unallocatedExpenses"])
+
+	products := report["products"].([]interface{})
+	s.Require().Len(products, 2)
+	first := products[0].(map[string]interface{})
+	second := products[1].(map[string]interface{})
+
+	// Sorted by net profit: B (150 - 60 = 90) before A (50 - 70 = -20).
+	s.Equal(fx.products[1].ID, first["productId"])
+	s.Equal(fx.products[1].Name, first["productName"])
+	s.Equal(float64(3), first["quantitySold"])
+	s.Equal("150", first["grossProfit"])
+	s.Equal("60", first["allocatedExpenses"])
+	s.Equal("90", first["netProfit"])
+
+	s.Equal(fx.products[0].ID, second["productId"])
+	s.Equal("50", second["grossProfit"])
+	s.Equal("70", second["allocatedExpenses"])
+	s.Equal("-20", second["netProfit"])
+}
+
+func TestExpenseAllocationsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(ExpenseAllocationsSuite))
+}
@@ -0,0 +1,232 @@
+package e2e_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/platform/config"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/suite"
+)
+
+var expenseIntakeTables = []string{"users", "workspaces", "businesses", "expenses", "expense_drafts", "expense_inboxes", "uploaded_assets"}
+
+// ExpenseIntakeSuite tests receipt uploads, expense drafts and the expense inbox webhook.
+type ExpenseIntakeSuite struct {
+	suite.Suite
+	helper *AccountingTestHelper
+}
+
+func (s *ExpenseIntakeSuite) SetupSuite() {
+	s.helper = NewAccountingTestHelper(testEnv.Database, testEnv.CacheAddr, e2eBaseURL)
+}
+
+func (s *ExpenseIntakeSuite) SetupTest() {
+	testutils.TruncateTables(testEnv.Database, expenseIntakeTables...)
+	viper.Set(config.AccountingExpenseInboxDomain, "in.kyora.test")
+	viper.Set(config.AccountingExpenseInboxSecret, "inbox_secret")
+}
+
+func (s *ExpenseIntakeSuite) TearDownTest() {
+	testutils.TruncateTables(testEnv.Database, expenseIntakeTables...)
+	viper.Set(config.AccountingExpenseInboxDomain, nil)
+	viper.Set(config.AccountingExpenseInboxSecret, nil)
+}
+
+func (s *ExpenseIntakeSuite) upload(ws *WorkspaceUsers, token, fileName, contentType string, content []byte) *http.Response {
+	return s.uploadTo(ws, token, "/expense-drafts", fileName, contentType, content)
+}
+
+func (s *ExpenseIntakeSuite) uploadTo(ws *WorkspaceUsers, token, path, fileName, contentType string, content []byte) *http.Response {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", `form-data; name="file"; filename="`+fileName+`"`)
+	h.Set("Content-Type", contentType)
+	part, err := w.CreatePart(h)
+	s.Require().NoError(err)
+	_, err = part.Write(content)
+	s.Require().NoError(err)
+	s.Require().NoError(w.Close())
+
+	resp, err := s.helper.Client.AuthenticatedRequestRaw("POST", "/v1/businesses/"+ws.Business.Descriptor+"/accounting"+path, buf.Bytes(), map[string]string{"Content-Type": w.FormDataContentType()}, token)
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *ExpenseIntakeSuite) do(ws *WorkspaceUsers, token, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+ws.Business.Descriptor+"/accounting"+path, payload, token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *ExpenseIntakeSuite) TestUploadReviewAndConfirm() {
+	ws, err := s.helper.CreateWorkspaceWithAdminAndMemberAndBusiness(context.Background())
+	s.Require().NoError(err)
+
+	resp := s.upload(ws, ws.AdminToken, "receipt.png", "image/png", []byte("\x89PNG\r\n\x1a\nreceipt"))
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var draft map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &draft))
+	draftID := draft["id"].(string)
+	s.Equal("pending", draft["status"])
+	s.Equal("upload", draft["source"])
+	s.Equal("other", draft["category"])
+	s.NotEmpty(draft["receiptAssetId"])
+	s.NotEmpty(draft["receiptUrl"])
+
+	status, body := s.do(ws, ws.AdminToken, "POST", "/expense-drafts/"+draftID+"/confirm", nil)
+	s.Equal(http.StatusBadRequest, status, "a draft without an amount cannot be confirmed")
+	s.Equal("accounting.expense_draft_incomplete", body["extensions"].(map[string]interface{})["code"])
+
+	status, body = s.do(ws, ws.AdminToken, "PATCH", "/expense-drafts/"+draftID, map[string]interface{}{
+		"amount":     "42.75",
+		"vendor":     "Office Depot",
+		"category":   "supplies",
+		"occurredOn": "2026-02-03",
+	})
+	s.Require().Equal(http.StatusOK, status)
+	s.Equal("Office Depot", body["vendor"])
+
+	status, expense := s.do(ws, ws.AdminToken, "POST", "/expense-drafts/"+draftID+"/confirm", nil)
+	s.Require().Equal(http.StatusCreated, status)
+	s.Equal("42.75", expense["amount"])
+	s.Equal("supplies", expense["category"])
+	s.Equal("one_time", expense["type"])
+	s.Equal("Office Depot", expense["note"])
+	s.True(strings.HasPrefix(expense["occurredOn"].(string), "2026-02-03"))
+
+	status, body = s.do(ws, ws.AdminToken, "GET", "/expense-drafts/"+draftID, nil)
+	s.Require().Equal(http.StatusOK, status)
+	s.Equal("confirmed", body["status"])
+	s.Equal(expense["id"], body["expenseId"])
+
+	status, body = s.do(ws, ws.AdminToken, "POST", "/expense-drafts/"+draftID+"/discard", nil)
+	s.Equal(http.StatusConflict, status)
+	s.Equal("accounting.expense_draft_not_pending", body["extensions"].(map[string]interface{})["code"])
+
+	status, body = s.do(ws, ws.AdminToken, "GET", "/expense-drafts?status=confirmed", nil)
+	s.Require().Equal(http.StatusOK, status)
+	s.Len(body["items"], 1)
+}
+
+func (s *ExpenseIntakeSuite) TestUploadValidationAndPermissions() {
+	ws, err := s.helper.CreateWorkspaceWithAdminAndMemberAndBusiness(context.Background())
+	s.Require().NoError(err)
+
+	resp := s.upload(ws, ws.AdminToken, "receipt.exe", "application/x-msdownload", []byte("MZ"))
+	defer resp.Body.Close()
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	memberResp := s.upload(ws, ws.MemberToken, "receipt.png", "image/png", []byte("\x89PNG\r\n\x1a\nreceipt"))
+	defer memberResp.Body.Close()
+	s.Equal(http.StatusForbidden, memberResp.StatusCode)
+
+	status, _ := s.do(ws, ws.MemberToken, "GET", "/expense-drafts", nil)
+	s.Equal(http.StatusOK, status)
+}
+
+func (s *ExpenseIntakeSuite) TestScanReceipt() {
+	ws, err := s.helper.CreateWorkspaceWithAdminAndMemberAndBusiness(context.Background())
+	s.Require().NoError(err)
+
+	// the e2e server runs without an OCR provider
+	resp := s.uploadTo(ws, ws.AdminToken, "/expenses/scan", "receipt.png", "image/png", []byte("\x89PNG\r\n\x1a\nreceipt"))
+	defer resp.Body.Close()
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	s.Equal("accounting.receipt_scan_unavailable", body["extensions"].(map[string]interface{})["code"])
+
+	memberResp := s.uploadTo(ws, ws.MemberToken, "/expenses/scan", "receipt.png", "image/png", []byte("\x89PNG\r\n\x1a\nreceipt"))
+	defer memberResp.Body.Close()
+	s.Equal(http.StatusForbidden, memberResp.StatusCode)
+
+	status, _ := s.do(ws, ws.AdminToken, "POST", "/expenses/scan", nil)
+	s.Equal(http.StatusBadRequest, status, "a scan needs a receipt file")
+
+	status, body = s.do(ws, ws.AdminToken, "GET", "/expenses", nil)
+	s.Require().Equal(http.StatusOK, status)
+	s.Len(body["items"], 0, "scanning does not create expenses")
+}
+
+func (s *ExpenseIntakeSuite) TestExpenseInboxWebhook() {
+	ws, err := s.helper.CreateWorkspaceWithAdminAndMemberAndBusiness(context.Background())
+	s.Require().NoError(err)
+
+	status, _ := s.do(ws, ws.AdminToken, "GET", "/expense-inbox", nil)
+	s.Equal(http.StatusNotFound, status)
+
+	status, inbox := s.do(ws, ws.AdminToken, "POST", "/expense-inbox", nil)
+	s.Require().Equal(http.StatusOK, status)
+	address := inbox["address"].(string)
+	s.True(strings.HasPrefix(address, "expenses+"))
+	s.True(strings.HasSuffix(address, "@in.kyora.test"))
+
+	status, again := s.do(ws, ws.AdminToken, "POST", "/expense-inbox", nil)
+	s.Require().Equal(http.StatusOK, status)
+	s.Equal(address, again["address"], "enabling is idempotent")
+
+	email := map[string]interface{}{
+		"from": "Owner <owner@example.com>",
+		"to":   []string{"Receipts <" + strings.ToUpper(address) + ">"},
+		"attachments": []map[string]interface{}{
+			{"fileName": "receipt.jpg", "contentType": "image/jpeg", "content": base64.StdEncoding.EncodeToString([]byte("\xff\xd8\xffreceipt"))},
+			{"fileName": "notes.exe", "contentType": "application/x-msdownload", "content": base64.StdEncoding.EncodeToString([]byte("MZ"))},
+		},
+	}
+	resp, err := s.helper.Client.Request("POST", "/v1/webhooks/expense-inbox", email)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusUnauthorized, resp.StatusCode, "the shared secret is required")
+
+	body, err := json.Marshal(email)
+	s.Require().NoError(err)
+	resp, err = s.helper.Client.PostRaw("/v1/webhooks/expense-inbox", body, map[string]string{"Content-Type": "application/json", "X-Inbox-Secret": "inbox_secret"})
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var result map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &result))
+	s.Len(result["draftIds"], 1, "disallowed attachments are skipped")
+
+	status, drafts := s.do(ws, ws.AdminToken, "GET", "/expense-drafts", nil)
+	s.Require().Equal(http.StatusOK, status)
+	items := drafts["items"].([]interface{})
+	s.Require().Len(items, 1)
+	s.Equal("email", items[0].(map[string]interface{})["source"])
+	s.Equal("owner@example.com", items[0].(map[string]interface{})["senderEmail"])
+
+	status, rotated := s.do(ws, ws.AdminToken, "POST", "/expense-inbox/rotate", nil)
+	s.Require().Equal(http.StatusOK, status)
+	s.NotEqual(address, rotated["address"])
+
+	resp, err = s.helper.Client.PostRaw("/v1/webhooks/expense-inbox", body, map[string]string{"Content-Type": "application/json", "X-Inbox-Secret": "inbox_secret"})
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusNotFound, resp.StatusCode, "the previous address stops working after rotation")
+
+	status, _ = s.do(ws, ws.AdminToken, "DELETE", "/expense-inbox", nil)
+	s.Equal(http.StatusNoContent, status)
+}
+
+func TestExpenseIntakeSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(ExpenseIntakeSuite))
+}
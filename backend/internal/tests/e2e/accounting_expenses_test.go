@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
 	"github.com/abdelrahman146/kyora/internal/tests/testutils"
 	"github.com/stretchr/testify/suite"
 )
@@ -21,11 +23,11 @@ func (s *ExpensesSuite) SetupSuite() {
 }
 
 func (s *ExpensesSuite) SetupTest() {
-	testutils.TruncateTables(testEnv.Database, "users", "workspaces", "businesses", "expenses")
+	testutils.TruncateTables(testEnv.Database, "users", "workspaces", "businesses", "expenses", "ledgers", "ledger_accounts", "journal_entries", "journal_lines", "accounting_audit_events")
 }
 
 func (s *ExpensesSuite) TearDownTest() {
-	testutils.TruncateTables(testEnv.Database, "users", "workspaces", "businesses", "expenses")
+	testutils.TruncateTables(testEnv.Database, "users", "workspaces", "businesses", "expenses", "ledgers", "ledger_accounts", "journal_entries", "journal_lines", "accounting_audit_events")
 }
 
 func (s *ExpensesSuite) TestExpenses_CRUD_Admin() {
@@ -75,6 +77,71 @@ func (s *ExpensesSuite) TestExpenses_CRUD_Admin() {
 	s.Equal(http.StatusNoContent, delResp.StatusCode)
 }
 
+func (s *ExpensesSuite) TestExpenses_History() {
+	ctx := context.Background()
+	ws, err := s.helper.CreateWorkspaceWithAdminAndMemberAndBusiness(ctx)
+	s.Require().NoError(err)
+	base := "/v1/businesses/" + ws.Business.Descriptor + "/accounting/expenses"
+
+	createPayload := map[string]interface{}{"category": "rent", "type": "one_time", "amount": "100.00", "occurredOn": time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)}
+	resp, err := s.helper.Client.AuthenticatedRequest("POST", base, createPayload, ws.AdminToken)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var created map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &created))
+	expenseID := created["id"].(string)
+
+	// an edit that changes nothing is not recorded
+	for _, payload := range []map[string]interface{}{{"amount": "120.00", "note": "May rent"}, {"amount": "120.00", "note": "May rent"}} {
+		updResp, err := s.helper.Client.AuthenticatedRequest("PATCH", base+"/"+expenseID, payload, ws.AdminToken)
+		s.Require().NoError(err)
+		updResp.Body.Close()
+		s.Require().Equal(http.StatusOK, updResp.StatusCode)
+	}
+
+	delResp, err := s.helper.Client.AuthenticatedRequest("DELETE", base+"/"+expenseID, nil, ws.AdminToken)
+	s.Require().NoError(err)
+	delResp.Body.Close()
+	s.Require().Equal(http.StatusNoContent, delResp.StatusCode)
+
+	// the history outlives the expense and members can read it
+	histResp, err := s.helper.Client.AuthenticatedRequest("GET", base+"/"+expenseID+"/history", nil, ws.MemberToken)
+	s.Require().NoError(err)
+	defer histResp.Body.Close()
+	s.Require().Equal(http.StatusOK, histResp.StatusCode)
+	var events []struct {
+		Type    string  `json:"type"`
+		ActorID *string `json:"actorId"`
+		Changes []struct {
+			Field string      `json:"field"`
+			From  interface{} `json:"from"`
+			To    interface{} `json:"to"`
+		} `json:"changes"`
+	}
+	s.Require().NoError(testutils.DecodeJSON(histResp, &events))
+	s.Require().Len(events, 3)
+	s.Equal("created", events[0].Type)
+	s.Equal("updated", events[1].Type)
+	s.Equal("deleted", events[2].Type)
+	for _, ev := range events {
+		s.Require().NotNil(ev.ActorID)
+		s.Equal(ws.Admin.ID, *ev.ActorID)
+	}
+	changed := map[string][2]interface{}{}
+	for _, c := range events[1].Changes {
+		changed[c.Field] = [2]interface{}{c.From, c.To}
+	}
+	s.Equal([2]interface{}{"100", "120"}, changed["amount"])
+	s.Equal([2]interface{}{nil, "May rent"}, changed["note"])
+	s.NotContains(changed, "category")
+
+	missingResp, err := s.helper.Client.AuthenticatedRequest("GET", base+"/exp_missing/history", nil, ws.AdminToken)
+	s.Require().NoError(err)
+	defer missingResp.Body.Close()
+	s.Equal(http.StatusNotFound, missingResp.StatusCode)
+}
+
 func (s *ExpensesSuite) TestExpenses_Permissions_MemberCanViewButCannotManage() {
 	ctx := context.Background()
 	ws, err := s.helper.CreateWorkspaceWithAdminAndMemberAndBusiness(ctx)
@@ -114,6 +181,161 @@ func (s *ExpensesSuite) TestExpenses_Permissions_MemberCanViewButCannotManage()
 	s.Equal(http.StatusForbidden, delResp.StatusCode)
 }
 
+func (s *ExpensesSuite) TestExpenses_ForeignCurrency() {
+	ctx := context.Background()
+	ws, err := s.helper.CreateWorkspaceWithAdminAndMemberAndBusiness(ctx)
+	s.Require().NoError(err)
+	base := "/v1/businesses/" + ws.Business.Descriptor + "/accounting"
+	do := func(method, path string, payload interface{}) (int, map[string]interface{}) {
+		resp, err := s.helper.Client.AuthenticatedRequest(method, base+path, payload, ws.AdminToken)
+		s.Require().NoError(err)
+		defer resp.Body.Close()
+		var body map[string]interface{}
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+		return resp.StatusCode, body
+	}
+	now := time.Now().UTC()
+
+	// a manual rate
+	status, manual := do("POST", "/expenses", map[string]interface{}{
+		"category": "software", "type": "one_time", "amount": "100", "vat": "5",
+		"currency": "eur", "exchangeRate": "4", "occurredOn": now,
+	})
+	s.Require().Equal(http.StatusCreated, status, manual)
+	s.Equal("400", manual["amount"])
+	s.Equal("20", manual["vat"])
+	s.Equal("aed", manual["currency"])
+	s.Equal("100", manual["originalAmount"])
+	s.Equal("5", manual["originalVat"])
+	s.Equal("EUR", manual["originalCurrency"])
+	s.Equal("4", manual["exchangeRate"])
+	s.Equal("manual", manual["exchangeRateSource"])
+
+	// the rate of the day from the provider
+	status, auto := do("POST", "/expenses", map[string]interface{}{
+		"category": "shipping", "type": "one_time", "amount": "100", "currency": "USD", "occurredOn": now,
+	})
+	s.Require().Equal(http.StatusCreated, status, auto)
+	s.Equal("367.25", auto["amount"])
+	s.Equal("3.6725", auto["exchangeRate"])
+	s.Equal("auto", auto["exchangeRateSource"])
+
+	// business-currency expenses keep no original amount
+	status, local := do("POST", "/expenses", map[string]interface{}{
+		"category": "rent", "type": "one_time", "amount": "50", "currency": "AED", "occurredOn": now,
+	})
+	s.Require().Equal(http.StatusCreated, status, local)
+	s.Equal("50", local["amount"])
+	s.Nil(local["originalAmount"])
+	s.Nil(local["exchangeRate"])
+
+	status, body := do("GET", "/summary", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("817.25", body["totalExpenses"])
+
+	// edits are made in the currency paid, keeping the manual rate
+	status, body = do("PATCH", "/expenses/"+manual["id"].(string), map[string]interface{}{"amount": "200"})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("800", body["amount"])
+	s.Equal("200", body["originalAmount"])
+	s.Equal("20", body["vat"])
+	s.Equal("manual", body["exchangeRateSource"])
+
+	status, body = do("PATCH", "/expenses/"+manual["id"].(string), map[string]interface{}{"currency": "AED"})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("200", body["amount"])
+	s.Equal("5", body["vat"])
+	s.Nil(body["originalCurrency"])
+
+	status, body = do("POST", "/expenses", map[string]interface{}{
+		"category": "software", "type": "one_time", "amount": "10", "currency": "XYZ",
+	})
+	s.Equal(http.StatusUnprocessableEntity, status, body)
+	s.Equal("accounting.exchange_rate_unavailable", body["extensions"].(map[string]interface{})["code"])
+
+	status, body = do("POST", "/expenses", map[string]interface{}{
+		"category": "software", "type": "one_time", "amount": "10", "currency": "EUR", "vat": "11", "exchangeRate": "4",
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("accounting.expense_invalid_vat", body["extensions"].(map[string]interface{})["code"])
+}
+
+func (s *ExpensesSuite) TestExpenses_Split() {
+	ctx := context.Background()
+	ws, err := s.helper.CreateWorkspaceWithAdminAndMemberAndBusiness(ctx)
+	s.Require().NoError(err)
+	bizRepo := database.NewRepository[business.Business](testEnv.Database)
+	branch := &business.Business{WorkspaceID: ws.Workspace.ID, Descriptor: "branch", Name: "Branch", CountryCode: "AE", Currency: "aed", EstablishedAt: time.Now().UTC()}
+	s.Require().NoError(bizRepo.CreateOne(ctx, branch))
+	other := &business.Business{WorkspaceID: ws.Workspace.ID, Descriptor: "abroad", Name: "Abroad", CountryCode: "US", Currency: "usd", EstablishedAt: time.Now().UTC()}
+	s.Require().NoError(bizRepo.CreateOne(ctx, other))
+
+	do := func(descriptor, method, path string, payload interface{}) (int, map[string]interface{}) {
+		resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+descriptor+"/accounting"+path, payload, ws.AdminToken)
+		s.Require().NoError(err)
+		defer resp.Body.Close()
+		var body map[string]interface{}
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+		return resp.StatusCode, body
+	}
+	home := ws.Business.Descriptor
+	now := time.Now().UTC()
+	status, exp := do(home, "POST", "/expenses", map[string]interface{}{
+		"category": "rent", "type": "one_time", "amount": "100", "vat": "10", "occurredOn": now,
+	})
+	s.Require().Equal(http.StatusCreated, status, exp)
+	splitPath := "/expenses/" + exp["id"].(string) + "/split"
+
+	status, body := do(home, "PUT", splitPath, map[string]interface{}{"lines": []map[string]interface{}{
+		{"category": "rent", "percentage": "50"},
+		{"category": "utilities", "percentage": "40"},
+	}})
+	s.Equal(http.StatusBadRequest, status, body)
+	status, body = do(home, "PUT", splitPath, map[string]interface{}{"lines": []map[string]interface{}{
+		{"category": "rent", "percentage": "50"},
+		{"category": "utilities", "percentage": "50", "businessId": other.ID},
+	}})
+	s.Equal(http.StatusBadRequest, status, body, "businesses with another currency are rejected")
+
+	status, body = do(home, "PUT", splitPath, map[string]interface{}{"lines": []map[string]interface{}{
+		{"category": "rent", "percentage": "33.33"},
+		{"category": "utilities", "percentage": "33.33"},
+		{"category": "rent", "percentage": "33.34", "businessId": branch.ID},
+	}})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(exp["id"], body["splitId"])
+	s.Equal("100", body["amount"])
+	lines := body["lines"].([]interface{})
+	s.Require().Len(lines, 3)
+	s.Equal(exp["id"], lines[0].(map[string]interface{})["id"])
+	s.Equal(branch.ID, lines[2].(map[string]interface{})["businessId"])
+
+	// reports count the lines, not the whole expense
+	status, body = do(home, "GET", "/summary", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("66.66", body["totalExpenses"])
+	status, body = do("branch", "GET", "/summary", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("33.34", body["totalExpenses"])
+	status, body = do(home, "GET", "/expenses?category=utilities", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.EqualValues(1, body["totalCount"])
+
+	// splitting again replaces the lines; one line undoes the split
+	status, body = do(home, "PUT", splitPath, map[string]interface{}{"lines": []map[string]interface{}{
+		{"category": "software", "percentage": "100"},
+	}})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Nil(body["splitId"])
+	lines = body["lines"].([]interface{})
+	s.Require().Len(lines, 1)
+	s.Equal("100", lines[0].(map[string]interface{})["amount"])
+	s.Equal("10", lines[0].(map[string]interface{})["vat"])
+	status, body = do("branch", "GET", "/summary", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("0", body["totalExpenses"])
+}
+
 func TestExpensesSuite(t *testing.T) {
 	if testServer == nil {
 		t.Skip("Test server not initialized")
@@ -0,0 +1,157 @@
+package e2e_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/accounting"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+var orderReversalTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items",
+	"expenses", "order_reversals",
+}
+
+// AccountingOrderReversalsSuite tests that returned and refunded orders are taken back out of the books.
+type AccountingOrderReversalsSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *AccountingOrderReversalsSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *AccountingOrderReversalsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, orderReversalTables...))
+}
+
+func (s *AccountingOrderReversalsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, orderReversalTables...))
+}
+
+// paidOrder creates a fulfilled, paid order of two units (revenue 200, COGS 100) with a 5 transaction fee.
+func (s *AccountingOrderReversalsSuite) paidOrder(ctx context.Context, biz *business.Business) (*order.Order, *inventory.Variant) {
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	ord, err := s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: v, Quantity: 2}}, func(o *order.Order) {
+		o.Status = order.OrderStatusFulfilled
+		o.PaymentStatus = order.OrderPaymentStatusPaid
+		o.PaymentMethod = order.OrderPaymentMethodCreditCard
+		o.PaidAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	})
+	s.Require().NoError(err)
+	fee := &accounting.Expense{
+		BusinessID: biz.ID,
+		OrderID:    sql.NullString{String: ord.ID, Valid: true},
+		Amount:     decimal.NewFromInt(5),
+		Currency:   biz.Currency,
+		Category:   accounting.ExpenseCategoryTransactionFee,
+		Type:       accounting.ExpenseTypeOneTime,
+		OccurredOn: time.Now().UTC(),
+	}
+	s.Require().NoError(database.NewRepository[accounting.Expense](testEnv.Database).CreateOne(ctx, fee))
+	return ord, v
+}
+
+func (s *AccountingOrderReversalsSuite) patch(token, path string, body map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest("PATCH", path, body, token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+}
+
+// waitForReversal polls for the async reversal of the order until done accepts it.
+func (s *AccountingOrderReversalsSuite) waitForReversal(ctx context.Context, orderID string, done func(*accounting.OrderReversal) bool) *accounting.OrderReversal {
+	repo := database.NewRepository[accounting.OrderReversal](testEnv.Database)
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		rev, err := repo.FindOne(ctx, repo.ScopeEquals(accounting.OrderReversalSchema.OrderID, orderID))
+		if err == nil && done(rev) {
+			return rev
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	s.FailNow("order reversal was not recorded")
+	return nil
+}
+
+func (s *AccountingOrderReversalsSuite) TestRefundThenRestockingReturn() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	ord, v := s.paidOrder(ctx, biz)
+	orderPath := "/v1/businesses/" + biz.Descriptor + "/orders/" + ord.ID
+
+	s.patch(owner.Token, orderPath+"/payment-status", map[string]interface{}{"paymentStatus": "refunded"})
+	rev := s.waitForReversal(ctx, ord.ID, func(r *accounting.OrderReversal) bool { return r.Refunded })
+	s.Equal("200", rev.Revenue.String())
+	s.True(rev.COGS.IsZero(), "goods are not back yet")
+	s.Equal("5", rev.TransactionFee.String())
+
+	s.patch(owner.Token, orderPath+"/status", map[string]interface{}{"status": "returned", "restock": true})
+	rev = s.waitForReversal(ctx, ord.ID, func(r *accounting.OrderReversal) bool { return r.Restocked })
+	s.Equal("200", rev.Revenue.String(), "revenue is reversed once")
+	s.Equal("100", rev.COGS.String())
+	s.Equal("5", rev.TransactionFee.String())
+
+	restocked, err := database.NewRepository[inventory.Variant](testEnv.Database).FindByID(ctx, v.ID)
+	s.Require().NoError(err)
+	s.Equal(v.StockQuantity+2, restocked.StockQuantity)
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+biz.Descriptor+"/analytics/reports/profit-and-loss", nil, owner.Token)
+	s.Require().NoError(err)
+	var pnl map[string]interface{}
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Require().NoError(testutils.DecodeJSON(resp, &pnl))
+	s.Equal("0", pnl["revenue"])
+	s.Equal("200", pnl["returns"])
+	s.Equal("0", pnl["cogs"])
+	s.Equal("0", pnl["totalExpenses"])
+	s.Equal("0", pnl["netProfit"])
+}
+
+func (s *AccountingOrderReversalsSuite) TestReturnWithoutRestockKeepsCOGS() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	ord, v := s.paidOrder(ctx, biz)
+
+	s.patch(owner.Token, "/v1/businesses/"+biz.Descriptor+"/orders/"+ord.ID+"/status", map[string]interface{}{"status": "returned"})
+	rev := s.waitForReversal(ctx, ord.ID, func(*accounting.OrderReversal) bool { return true })
+	s.Equal("200", rev.Revenue.String())
+	s.True(rev.COGS.IsZero())
+	s.True(rev.TransactionFee.IsZero(), "the payment was not refunded")
+	s.False(rev.Restocked)
+
+	unchanged, err := database.NewRepository[inventory.Variant](testEnv.Database).FindByID(ctx, v.ID)
+	s.Require().NoError(err)
+	s.Equal(v.StockQuantity, unchanged.StockQuantity)
+}
+
+func TestAccountingOrderReversalsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(AccountingOrderReversalsSuite))
+}
@@ -0,0 +1,165 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+var profitDistributionTables = append([]string{"owner_shares"}, ledgerTables...)
+
+// ProfitDistributionSuite tests the cap table and the profit distribution calculator.
+type ProfitDistributionSuite struct {
+	suite.Suite
+	helper  *AccountingTestHelper
+	factory *testutils.Factory
+}
+
+func (s *ProfitDistributionSuite) SetupSuite() {
+	s.helper = NewAccountingTestHelper(testEnv.Database, testEnv.CacheAddr, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *ProfitDistributionSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, profitDistributionTables...))
+}
+
+func (s *ProfitDistributionSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, profitDistributionTables...))
+}
+
+func (s *ProfitDistributionSuite) do(token, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, path, payload, token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func (s *ProfitDistributionSuite) TestCapTableValidation() {
+	ctx := context.Background()
+	ws, err := s.helper.CreateWorkspaceWithAdminAndMemberAndBusiness(ctx)
+	s.Require().NoError(err)
+	path := "/v1/businesses/" + ws.Business.Descriptor + "/accounting/cap-table"
+
+	status, body := s.do(ws.AdminToken, "PUT", path, map[string]interface{}{"owners": []map[string]interface{}{
+		{"ownerId": ws.Admin.ID, "percentage": "70"},
+		{"ownerId": ws.Member.ID, "percentage": "40"},
+	}})
+	s.Equal(http.StatusBadRequest, status, body)
+	status, body = s.do(ws.AdminToken, "PUT", path, map[string]interface{}{"owners": []map[string]interface{}{
+		{"ownerId": ws.Admin.ID, "percentage": "30"},
+		{"ownerId": ws.Admin.ID, "percentage": "30"},
+	}})
+	s.Equal(http.StatusBadRequest, status, body)
+	status, body = s.do(ws.AdminToken, "PUT", path, map[string]interface{}{"owners": []map[string]interface{}{
+		{"ownerId": "usr_missing", "percentage": "30"},
+	}})
+	s.Equal(http.StatusBadRequest, status, body)
+
+	status, body = s.do(ws.AdminToken, "PUT", path, map[string]interface{}{"owners": []map[string]interface{}{
+		{"ownerId": ws.Member.ID, "percentage": "25"},
+		{"ownerId": ws.Admin.ID, "percentage": "50"},
+	}})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("75", body["totalPercentage"])
+	s.Equal("25", body["unassigned"])
+	owners := body["owners"].([]interface{})
+	s.Require().Len(owners, 2)
+	s.Equal(ws.Admin.ID, owners[0].(map[string]interface{})["ownerId"], "largest share first")
+
+	status, body = s.do(ws.MemberToken, "PUT", path, map[string]interface{}{"owners": []map[string]interface{}{}})
+	s.Equal(http.StatusForbidden, status, body)
+	status, body = s.do(ws.AdminToken, "PUT", path, map[string]interface{}{"owners": []map[string]interface{}{}})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Empty(body["owners"])
+}
+
+func (s *ProfitDistributionSuite) TestProposeAndDistribute() {
+	ctx := context.Background()
+	ws, err := s.helper.CreateWorkspaceWithAdminAndMemberAndBusiness(ctx)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, ws.Business.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	ord, err := s.factory.Order(ctx, ws.Business, []testutils.OrderLine{{Variant: v, Quantity: 3}}, func(o *order.Order) {
+		o.Status = order.OrderStatusFulfilled
+	})
+	s.Require().NoError(err)
+
+	base := "/v1/businesses/" + ws.Business.Descriptor + "/accounting"
+	now := time.Now().UTC()
+	today := now.Format("2006-01-02")
+	status, body := s.do(ws.AdminToken, "PUT", base+"/cap-table", map[string]interface{}{"owners": []map[string]interface{}{
+		{"ownerId": ws.Admin.ID, "percentage": "60"},
+		{"ownerId": ws.Member.ID, "percentage": "40"},
+	}})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	status, body = s.do(ws.AdminToken, "POST", base+"/expenses", map[string]interface{}{"category": "rent", "type": "one_time", "amount": "1", "occurredOn": now})
+	s.Require().Equal(http.StatusCreated, status, body)
+	// investments are equity: they do not add to the profit
+	status, body = s.do(ws.AdminToken, "POST", base+"/investments", map[string]interface{}{"investorId": ws.Admin.ID, "amount": "500", "investedAt": now})
+	s.Require().Equal(http.StatusCreated, status, body)
+	status, body = s.do(ws.AdminToken, "POST", base+"/withdrawals", map[string]interface{}{"withdrawerId": ws.Admin.ID, "amount": "0.01", "withdrawnAt": now})
+	s.Require().Equal(http.StatusCreated, status, body)
+
+	netProfit := ord.Total.Sub(ord.COGS).Sub(decimal.NewFromInt(1))
+	s.Require().True(netProfit.IsPositive())
+	status, body = s.do(ws.AdminToken, "GET", base+"/profit-distribution?from="+today+"&to="+today+"&percent=50", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(netProfit.String(), body["netProfit"])
+	distributable := decimal.RequireFromString(body["distributable"].(string))
+	s.Equal(netProfit.Div(decimal.NewFromInt(2)).Round(2).String(), distributable.String())
+	owners := body["owners"].([]interface{})
+	s.Require().Len(owners, 2)
+	admin, member := owners[0].(map[string]interface{}), owners[1].(map[string]interface{})
+	s.Equal(ws.Admin.ID, admin["ownerId"])
+	adminEntitlement := decimal.RequireFromString(admin["entitlement"].(string))
+	memberEntitlement := decimal.RequireFromString(member["entitlement"].(string))
+	s.True(adminEntitlement.Add(memberEntitlement).Equal(distributable), "entitlements add up to the distributable profit")
+	s.Equal("0.01", admin["withdrawn"])
+	s.Equal(adminEntitlement.Sub(decimal.RequireFromString("0.01")).String(), admin["proposed"])
+	s.Equal(member["entitlement"], member["proposed"])
+
+	status, body = s.do(ws.AdminToken, "POST", base+"/profit-distribution", map[string]interface{}{"from": today, "to": today, "percent": "50"})
+	s.Require().Equal(http.StatusCreated, status, body)
+	withdrawals := body["withdrawals"].([]interface{})
+	s.Require().Len(withdrawals, 2)
+	for _, raw := range withdrawals {
+		w := raw.(map[string]interface{})
+		s.NotEmpty(w["distributedFrom"])
+		s.Contains(w["note"], "Profit distribution")
+	}
+
+	// what was distributed is not proposed again
+	status, body = s.do(ws.AdminToken, "GET", base+"/profit-distribution?from="+today+"&to="+today+"&percent=50", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	for _, raw := range body["owners"].([]interface{}) {
+		s.Equal("0", raw.(map[string]interface{})["proposed"])
+	}
+	s.Equal(netProfit.String(), body["netProfit"], "drawings do not reduce the profit")
+
+	status, body = s.do(ws.AdminToken, "POST", base+"/profit-distribution", map[string]interface{}{
+		"from": today, "to": today,
+		"owners": []map[string]interface{}{{"ownerId": "usr_outsider", "amount": "1"}},
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+	status, body = s.do(ws.AdminToken, "GET", base+"/profit-distribution?from="+today+"&to="+today+"&percent=150", nil)
+	s.Equal(http.StatusBadRequest, status, body)
+}
+
+func TestProfitDistributionSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(ProfitDistributionSuite))
+}
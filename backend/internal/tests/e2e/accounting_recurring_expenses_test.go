@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/abdelrahman146/kyora/internal/domain/accounting"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
 	"github.com/abdelrahman146/kyora/internal/tests/testutils"
 	"github.com/stretchr/testify/suite"
 )
@@ -22,11 +23,11 @@ func (s *RecurringExpensesSuite) SetupSuite() {
 }
 
 func (s *RecurringExpensesSuite) SetupTest() {
-	testutils.TruncateTables(testEnv.Database, "users", "workspaces", "businesses", "recurring_expenses", "expenses")
+	testutils.TruncateTables(testEnv.Database, "users", "workspaces", "businesses", "recurring_expenses", "expenses", "ledgers", "ledger_accounts", "journal_entries", "journal_lines")
 }
 
 func (s *RecurringExpensesSuite) TearDownTest() {
-	testutils.TruncateTables(testEnv.Database, "users", "workspaces", "businesses", "recurring_expenses", "expenses")
+	testutils.TruncateTables(testEnv.Database, "users", "workspaces", "businesses", "recurring_expenses", "expenses", "ledgers", "ledger_accounts", "journal_entries", "journal_lines")
 }
 
 func (s *RecurringExpensesSuite) TestRecurringExpenses_Create_List_StatusTransition() {
@@ -133,6 +134,68 @@ func (s *RecurringExpensesSuite) TestRecurringExpenses_Permissions_MemberViewOnl
 	s.Equal(http.StatusOK, listResp.StatusCode)
 }
 
+func (s *RecurringExpensesSuite) TestRecurringExpenses_SchedulerRecordsDueOccurrences() {
+	ctx := context.Background()
+	ws, err := s.helper.CreateWorkspaceWithAdminAndMemberAndBusiness(ctx)
+	s.Require().NoError(err)
+	base := "/v1/businesses/" + ws.Business.Descriptor + "/accounting/recurring-expenses"
+	create := func(payload map[string]interface{}) map[string]interface{} {
+		resp, err := s.helper.Client.AuthenticatedRequest("POST", base, payload, ws.AdminToken)
+		s.Require().NoError(err)
+		defer resp.Body.Close()
+		s.Require().Equal(http.StatusCreated, resp.StatusCode)
+		var body map[string]interface{}
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+		return body
+	}
+	occurrences := func(id string) []interface{} {
+		resp, err := s.helper.Client.AuthenticatedRequest("GET", base+"/"+id+"/occurrences", nil, ws.AdminToken)
+		s.Require().NoError(err)
+		defer resp.Body.Close()
+		var body []interface{}
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+		return body
+	}
+
+	// without the backfill the schedule starts today
+	weekly := create(map[string]interface{}{"frequency": "weekly", "recurringStartDate": today().AddDate(0, 0, -14), "category": "rent", "amount": "900"})
+	s.True(today().Equal(dateOf(weekly["nextRecurringDate"].(string))))
+	paused := create(map[string]interface{}{"frequency": "daily", "recurringStartDate": today(), "category": "software", "amount": "5"})
+	resp, err := s.helper.Client.AuthenticatedRequest("PATCH", base+"/"+paused["id"].(string)+"/status", map[string]interface{}{"status": "paused"}, ws.AdminToken)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	ending := create(map[string]interface{}{"frequency": "weekly", "recurringStartDate": today().AddDate(0, 0, -14), "recurringEndDate": today().AddDate(0, 0, -1), "category": "rent", "amount": "50"})
+
+	// a missed run is caught up
+	s.Require().NoError(testEnv.Database.GetDB().Model(&accounting.RecurringExpense{}).
+		Where("id = ?", weekly["id"]).Update("next_recurring_date", today().AddDate(0, 0, -7)).Error)
+
+	svc := accounting.NewService(accounting.NewStorage(testEnv.Database, nil), database.NewAtomicProcess(testEnv.Database), nil)
+	opts := accounting.GenerateRecurringExpensesOptions{BusinessID: ws.Business.ID}
+	result, err := svc.GenerateDueRecurringExpenses(ctx, opts)
+	s.Require().NoError(err)
+	s.Equal(2, result.Created)
+	s.Equal(1, result.Ended)
+	s.Zero(result.Failed)
+	s.Len(occurrences(weekly["id"].(string)), 2)
+	s.Empty(occurrences(paused["id"].(string)), "paused recurring expenses are not recorded")
+	s.Empty(occurrences(ending["id"].(string)))
+
+	rexp, err := svc.GetRecurringExpenseByID(ctx, nil, ws.Business, weekly["id"].(string))
+	s.Require().NoError(err)
+	s.True(today().AddDate(0, 0, 7).Equal(rexp.NextRecurringDate.UTC()))
+	rexp, err = svc.GetRecurringExpenseByID(ctx, nil, ws.Business, ending["id"].(string))
+	s.Require().NoError(err)
+	s.Equal(accounting.RecurringExpenseStatusEnded, rexp.Status)
+
+	// running again the same day records nothing new
+	result, err = svc.GenerateDueRecurringExpenses(ctx, opts)
+	s.Require().NoError(err)
+	s.Zero(result.Created)
+	s.Len(occurrences(weekly["id"].(string)), 2)
+}
+
 func TestRecurringExpensesSuite(t *testing.T) {
 	if testServer == nil {
 		t.Skip("Test server not initialized")
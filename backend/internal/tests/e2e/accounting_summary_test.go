@@ -126,6 +126,11 @@ func (s *SummarySuite) TestSummary_ComputedFields_WithInvestmentsWithdrawalsExpe
 	s.Equal("1000", body["totalInvestments"])
 	s.Equal("50", body["totalWithdrawals"])
 	s.Equal("100", body["totalExpenses"])
+	// Revenue and COGS are recognized from the fulfilled order without manual entry
+	s.Equal("1000", body["totalRevenue"])
+	s.Equal("200", body["totalCogs"])
+	s.Equal("800", body["grossProfit"])
+	s.Equal("700", body["netProfit"])
 	// If SafetyBuffer is not set, it defaults to last-30-days expenses
 	// 1000 - 200 - 100 - 50 - 100 = 550
 	s.Equal("550", body["safeToDrawAmount"])
@@ -21,11 +21,11 @@ func (s *WithdrawalsSuite) SetupSuite() {
 }
 
 func (s *WithdrawalsSuite) SetupTest() {
-	testutils.TruncateTables(testEnv.Database, "users", "workspaces", "businesses", "withdrawals")
+	testutils.TruncateTables(testEnv.Database, "users", "workspaces", "businesses", "withdrawals", "ledgers", "ledger_accounts", "journal_entries", "journal_lines")
 }
 
 func (s *WithdrawalsSuite) TearDownTest() {
-	testutils.TruncateTables(testEnv.Database, "users", "workspaces", "businesses", "withdrawals")
+	testutils.TruncateTables(testEnv.Database, "users", "workspaces", "businesses", "withdrawals", "ledgers", "ledger_accounts", "journal_entries", "journal_lines")
 }
 
 func (s *WithdrawalsSuite) TestWithdrawals_CRUD_Admin() {
@@ -107,6 +107,57 @@ func (s *WithdrawalsSuite) TestWithdrawals_Permissions_MemberCanViewButCannotMan
 	s.Equal(http.StatusOK, getResp.StatusCode)
 }
 
+func (s *WithdrawalsSuite) TestWithdrawals_SafeToDrawGuard() {
+	ctx := context.Background()
+	ws, err := s.helper.CreateWorkspaceWithAdminAndMemberAndBusiness(ctx)
+	s.NoError(err)
+
+	setGuard := func(guard string) {
+		resp, err := s.helper.Client.AuthenticatedRequest("PATCH", "/v1/businesses/"+ws.Business.Descriptor, map[string]interface{}{"withdrawalGuard": guard}, ws.AdminToken)
+		s.NoError(err)
+		defer resp.Body.Close()
+		s.Require().Equal(http.StatusOK, resp.StatusCode)
+	}
+	withdraw := func(payload map[string]interface{}) (int, map[string]interface{}) {
+		payload["withdrawerId"] = ws.Admin.ID
+		resp, err := s.helper.Client.AuthenticatedRequest("POST", "/v1/businesses/"+ws.Business.Descriptor+"/accounting/withdrawals", payload, ws.AdminToken)
+		s.NoError(err)
+		defer resp.Body.Close()
+		var body map[string]interface{}
+		s.NoError(testutils.DecodeJSON(resp, &body))
+		return resp.StatusCode, body
+	}
+
+	// no sales yet, so nothing is safe to draw; the guard is off by default
+	status, body := withdraw(map[string]interface{}{"amount": "100.00"})
+	s.Equal(http.StatusCreated, status)
+	s.NotContains(body, "warning")
+
+	setGuard("warn")
+	status, body = withdraw(map[string]interface{}{"amount": "200.00"})
+	s.Equal(http.StatusCreated, status)
+	s.Require().Contains(body, "warning")
+	warning := body["warning"].(map[string]interface{})
+	s.Equal("accounting.withdrawal_exceeds_safe_to_draw", warning["code"])
+	s.Equal("0", warning["safeToDrawAmount"])
+	s.Equal("200", warning["excess"])
+	s.Equal(false, warning["overridden"])
+
+	setGuard("enforce")
+	status, body = withdraw(map[string]interface{}{"amount": "50.00"})
+	s.Equal(http.StatusUnprocessableEntity, status)
+	s.Equal("accounting.withdrawal_exceeds_safe_to_draw", body["extensions"].(map[string]interface{})["code"])
+
+	status, body = withdraw(map[string]interface{}{"amount": "50.00", "override": true})
+	s.Equal(http.StatusCreated, status)
+	s.Require().Contains(body, "warning")
+	s.Equal(true, body["warning"].(map[string]interface{})["overridden"])
+
+	var count int64
+	s.NoError(testEnv.Database.Conn(ctx).Table("withdrawals").Where("business_id = ?", ws.Business.ID).Count(&count).Error)
+	s.Equal(int64(3), count)
+}
+
 func TestWithdrawalsSuite(t *testing.T) {
 	if testServer == nil {
 		t.Skip("Test server not initialized")
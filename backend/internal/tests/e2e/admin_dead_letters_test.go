@@ -0,0 +1,147 @@
+package e2e_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/deadletter"
+	"github.com/abdelrahman146/kyora/internal/platform/bus"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+// AdminDeadLettersSuite tests the /v1/admin/dead-letters endpoints.
+type AdminDeadLettersSuite struct {
+	suite.Suite
+	client *testutils.HTTPClient
+	repo   *database.Repository[deadletter.DeadLetter]
+}
+
+func (s *AdminDeadLettersSuite) SetupSuite() {
+	s.client = testutils.NewHTTPClient(e2eBaseURL)
+	s.repo = database.NewRepository[deadletter.DeadLetter](testEnv.Database)
+}
+
+func (s *AdminDeadLettersSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, deadletter.DeadLetterTable))
+}
+
+func (s *AdminDeadLettersSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, deadletter.DeadLetterTable))
+}
+
+func (s *AdminDeadLettersSuite) createDeadLetter(handler string) *deadletter.DeadLetter {
+	// A paid event without ids is dropped by the accounting handler, so replaying it succeeds
+	// without needing a business and order.
+	payload, err := json.Marshal(&bus.OrderPaidEvent{Currency: "USD"})
+	s.Require().NoError(err)
+	dl := &deadletter.DeadLetter{
+		Topic:    string(bus.OrderPaidTopic),
+		Handler:  handler,
+		Payload:  payload,
+		Error:    "connection reset",
+		Attempts: 5,
+		Status:   deadletter.StatusPending,
+		FailedAt: time.Now().UTC(),
+	}
+	s.Require().NoError(s.repo.CreateOne(context.Background(), dl))
+	return dl
+}
+
+func (s *AdminDeadLettersSuite) TestRequiresAdminToken() {
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "missing token"},
+		{name: "wrong token", token: "not_the_admin_token"},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			resp, err := s.client.AuthenticatedRequest("GET", "/v1/admin/dead-letters", nil, tt.token)
+			s.Require().NoError(err)
+			defer resp.Body.Close()
+			s.Equal(http.StatusUnauthorized, resp.StatusCode)
+		})
+	}
+}
+
+func (s *AdminDeadLettersSuite) TestListAndGet() {
+	dl := s.createDeadLetter("accounting.transaction_fee")
+
+	resp, err := s.client.AuthenticatedRequest("GET", "/v1/admin/dead-letters?status=pending", nil, e2eAdminToken)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var listResp map[string]interface{}
+	s.NoError(testutils.DecodeJSON(resp, &listResp))
+	s.Equal(float64(1), listResp["totalCount"])
+	items := listResp["items"].([]interface{})
+	s.Require().Len(items, 1)
+	item := items[0].(map[string]interface{})
+	s.Equal(dl.ID, item["id"])
+	s.Equal("order.paid", item["topic"])
+	s.Equal("accounting.transaction_fee", item["handler"])
+	s.Equal("connection reset", item["error"])
+
+	resp, err = s.client.AuthenticatedRequest("GET", fmt.Sprintf("/v1/admin/dead-letters/%s", dl.ID), nil, e2eAdminToken)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	resp, err = s.client.AuthenticatedRequest("GET", "/v1/admin/dead-letters/dlq_missing", nil, e2eAdminToken)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *AdminDeadLettersSuite) TestReplay_MarksReplayed() {
+	dl := s.createDeadLetter("accounting.transaction_fee")
+
+	resp, err := s.client.AuthenticatedRequest("POST", fmt.Sprintf("/v1/admin/dead-letters/%s/replay", dl.ID), nil, e2eAdminToken)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var result map[string]interface{}
+	s.NoError(testutils.DecodeJSON(resp, &result))
+	s.Equal("replayed", result["status"])
+	s.Equal(float64(1), result["replayCount"])
+	s.NotEmpty(result["replayedAt"])
+
+	resp, err = s.client.AuthenticatedRequest("POST", fmt.Sprintf("/v1/admin/dead-letters/%s/replay", dl.ID), nil, e2eAdminToken)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusConflict, resp.StatusCode)
+}
+
+func (s *AdminDeadLettersSuite) TestReplay_UnknownHandlerStaysPending() {
+	dl := s.createDeadLetter("no.such_handler")
+
+	resp, err := s.client.AuthenticatedRequest("POST", fmt.Sprintf("/v1/admin/dead-letters/%s/replay", dl.ID), nil, e2eAdminToken)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode)
+	code, err := testutils.GetErrorCode(resp)
+	s.NoError(err)
+	s.Equal("deadletter.replay_failed", code)
+
+	stored, err := s.repo.FindByID(context.Background(), dl.ID)
+	s.Require().NoError(err)
+	s.Equal(deadletter.StatusPending, stored.Status)
+	s.Equal(1, stored.ReplayCount)
+	s.NotEmpty(stored.LastReplayError)
+}
+
+func TestAdminDeadLettersSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(AdminDeadLettersSuite))
+}
@@ -0,0 +1,199 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var budgetTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items",
+	"expenses", "ledgers", "ledger_accounts", "journal_entries", "journal_lines",
+	"monthly_budgets",
+}
+
+// AnalyticsBudgetsSuite tests monthly budgets and budget vs actual tracking.
+type AnalyticsBudgetsSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *AnalyticsBudgetsSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *AnalyticsBudgetsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, budgetTables...))
+}
+
+func (s *AnalyticsBudgetsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, budgetTables...))
+}
+
+func (s *AnalyticsBudgetsSuite) do(biz *business.Business, token, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+biz.Descriptor+path, payload, token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *AnalyticsBudgetsSuite) category(body map[string]interface{}, name string) map[string]interface{} {
+	for _, c := range body["categories"].([]interface{}) {
+		if cc := c.(map[string]interface{}); cc["category"] == name {
+			return cc
+		}
+	}
+	return nil
+}
+
+func (s *AnalyticsBudgetsSuite) TestBudgetVsActual_CurrentMonth() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	_, err = s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: v, Quantity: 1}})
+	s.Require().NoError(err)
+
+	month := time.Now().UTC().Format("2006-01")
+	status, body := s.do(biz, owner.Token, "PUT", "/analytics/budgets/"+month, map[string]interface{}{
+		"revenueTarget": "1000",
+		"categories": []map[string]interface{}{
+			{"category": "software", "amount": "50"},
+			{"category": "rent", "amount": "100000"},
+		},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(month, body["month"])
+	s.Equal("1000", body["revenueTarget"])
+	s.Len(body["categories"], 2)
+
+	status, body = s.do(biz, owner.Token, "POST", "/accounting/expenses", map[string]interface{}{
+		"category": "software", "type": "one_time", "amount": "80", "occurredOn": time.Now().UTC(),
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+
+	status, body = s.do(biz, owner.Token, "GET", "/analytics/budgets/vs-actual", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(month, body["month"])
+	revenue := body["revenue"].(map[string]interface{})
+	s.Equal("100", revenue["actual"])
+	s.Equal("-900", revenue["variance"])
+	s.Equal("10", revenue["percentOfTarget"])
+
+	software := s.category(body, "software")
+	s.Require().NotNil(software)
+	s.Equal("80", software["actual"])
+	s.Equal("-30", software["variance"])
+	s.Equal("160", software["percentUsed"])
+	s.Equal("over_budget", software["status"])
+	rent := s.category(body, "rent")
+	s.Require().NotNil(rent)
+	s.Equal("0", rent["actual"])
+	s.Equal("on_track", rent["status"])
+	s.Equal("100050", body["totalBudget"])
+	s.Equal("80", body["totalActual"])
+
+	alerts := body["alerts"].([]interface{})
+	s.Require().Len(alerts, 1)
+	s.Equal("software", alerts[0].(map[string]interface{})["category"])
+
+	// the dashboard surfaces the current month's alerts
+	status, body = s.do(biz, owner.Token, "GET", "/analytics/dashboard", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Len(body["budgetAlerts"], 1)
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+biz.Descriptor+"/analytics/budgets", nil, owner.Token)
+	s.Require().NoError(err)
+	var budgets []map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &budgets))
+	resp.Body.Close()
+	s.Require().Len(budgets, 1)
+
+	status, _ = s.do(biz, owner.Token, "DELETE", "/analytics/budgets/"+month, nil)
+	s.Equal(http.StatusNoContent, status)
+	status, body = s.do(biz, owner.Token, "GET", "/analytics/budgets/vs-actual", nil)
+	s.Equal(http.StatusNotFound, status)
+	s.Equal("analytics.budget_not_found", body["extensions"].(map[string]interface{})["code"])
+	status, body = s.do(biz, owner.Token, "GET", "/analytics/dashboard", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Len(body["budgetAlerts"], 0)
+}
+
+func (s *AnalyticsBudgetsSuite) TestBudgetVsActual_PastMonthIsFinal() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+
+	month := time.Now().UTC().AddDate(0, -2, 0).Format("2006-01")
+	status, body := s.do(biz, owner.Token, "PUT", "/analytics/budgets/"+month, map[string]interface{}{
+		"categories": []map[string]interface{}{{"category": "marketing", "amount": "200"}},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Nil(body["revenueTarget"])
+
+	status, body = s.do(biz, owner.Token, "GET", "/analytics/budgets/vs-actual?month="+month, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("1", body["elapsedRatio"])
+	s.Nil(body["revenue"])
+	marketing := s.category(body, "marketing")
+	s.Require().NotNil(marketing)
+	s.Equal("200", marketing["variance"])
+	s.Equal("on_track", marketing["status"])
+	s.Len(body["alerts"], 0)
+}
+
+func (s *AnalyticsBudgetsSuite) TestUpsertBudget_Validation() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	month := time.Now().UTC().Format("2006-01")
+
+	cases := []struct {
+		payload map[string]interface{}
+		code    string
+	}{
+		{map[string]interface{}{}, "analytics.budget_required"},
+		{map[string]interface{}{"categories": []map[string]interface{}{{"category": "yachts", "amount": "10"}}}, "analytics.invalid_budget_category"},
+		{map[string]interface{}{"categories": []map[string]interface{}{{"category": "rent", "amount": "10"}, {"category": "rent", "amount": "20"}}}, "analytics.duplicate_budget_category"},
+		{map[string]interface{}{"categories": []map[string]interface{}{{"category": "rent", "amount": "-5"}}}, "analytics.invalid_budget_amount"},
+		{map[string]interface{}{"revenueTarget": "0"}, "analytics.invalid_budget_amount"},
+	}
+	for _, tc := range cases {
+		status, body := s.do(biz, owner.Token, "PUT", "/analytics/budgets/"+month, tc.payload)
+		s.Equal(http.StatusBadRequest, status, body)
+		s.Equal(tc.code, body["extensions"].(map[string]interface{})["code"])
+	}
+
+	status, body := s.do(biz, owner.Token, "PUT", "/analytics/budgets/2025-13", map[string]interface{}{"revenueTarget": "10"})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("analytics.invalid_goal_month", body["extensions"].(map[string]interface{})["code"])
+}
+
+func TestAnalyticsBudgetsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(AnalyticsBudgetsSuite))
+}
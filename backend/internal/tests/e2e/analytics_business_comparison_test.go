@@ -0,0 +1,167 @@
+package e2e_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var businessComparisonTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items",
+}
+
+// AnalyticsBusinessComparisonSuite tests the side-by-side performance of a workspace's businesses.
+type AnalyticsBusinessComparisonSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *AnalyticsBusinessComparisonSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *AnalyticsBusinessComparisonSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, businessComparisonTables...))
+}
+
+func (s *AnalyticsBusinessComparisonSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, businessComparisonTables...))
+}
+
+func (s *AnalyticsBusinessComparisonSuite) get(token, query string) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/workspaces/analytics/business-comparison"+query, nil, token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+// sell creates a business with one order per quantity, each unit selling at 100 with a cost of 50.
+func (s *AnalyticsBusinessComparisonSuite) sell(ctx context.Context, workspaceID string, opt func(*business.Business), quantities ...int) *business.Business {
+	biz, err := s.factory.Business(ctx, workspaceID, opt)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	for _, q := range quantities {
+		_, err := s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: v, Quantity: q}})
+		s.Require().NoError(err)
+	}
+	return biz
+}
+
+func businessIDs(body map[string]interface{}) []string {
+	var ids []string
+	for _, b := range body["businesses"].([]interface{}) {
+		ids = append(ids, b.(map[string]interface{})["businessId"].(string))
+	}
+	return ids
+}
+
+func (s *AnalyticsBusinessComparisonSuite) TestCompare_NormalizesCurrencies() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	usd := s.sell(ctx, owner.Workspace.ID, func(b *business.Business) {}, 1, 2)
+	eur := s.sell(ctx, owner.Workspace.ID, func(b *business.Business) { b.Currency = "EUR" }, 1)
+	idle := s.sell(ctx, owner.Workspace.ID, func(b *business.Business) {})
+	archivedAt := time.Now().UTC()
+	archived := s.sell(ctx, owner.Workspace.ID, func(b *business.Business) { b.ArchivedAt = &archivedAt }, 5)
+
+	other, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	s.sell(ctx, other.Workspace.ID, func(b *business.Business) {}, 9)
+
+	status, body := s.get(owner.Token, "?rates=EUR:1.1")
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("USD", body["currency"], "defaults to the currency most businesses use")
+	s.Equal([]string{usd.ID, eur.ID, idle.ID}, businessIDs(body), "archived and other workspaces' businesses are excluded")
+	s.Empty(body["missingRates"])
+
+	first := body["businesses"].([]interface{})[0].(map[string]interface{})
+	s.Equal("300", first["revenue"])
+	s.Equal("150", first["grossProfit"])
+	s.Equal("50", first["grossMargin"])
+	s.EqualValues(2, first["orderCount"])
+	s.Equal("150", first["averageOrderValue"])
+
+	second := body["businesses"].([]interface{})[1].(map[string]interface{})
+	s.Equal("EUR", second["currency"])
+	s.Equal("100", second["revenue"])
+	normalized := second["normalized"].(map[string]interface{})
+	s.Equal("1.1", normalized["rate"])
+	s.Equal("110", normalized["revenue"])
+	s.Equal("55", normalized["grossProfit"])
+
+	totals := body["totals"].(map[string]interface{})
+	s.Equal("410", totals["revenue"])
+	s.Equal("205", totals["grossProfit"])
+	s.Equal("50", totals["grossMargin"])
+	s.EqualValues(3, totals["orderCount"])
+	s.Equal("136.67", totals["averageOrderValue"])
+
+	// without a rate the business keeps its own figures and ranks last
+	status, body = s.get(owner.Token, "?currency=eur&includeArchived=true")
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("EUR", body["currency"])
+	s.Equal([]interface{}{"USD"}, body["missingRates"])
+	ids := businessIDs(body)
+	s.Require().Len(ids, 4)
+	s.Equal(eur.ID, ids[0])
+	s.ElementsMatch([]string{usd.ID, idle.ID, archived.ID}, ids[1:])
+	for _, b := range body["businesses"].([]interface{})[1:] {
+		b := b.(map[string]interface{})
+		s.Nil(b["normalized"])
+		s.Equal(b["businessId"] == archived.ID, b["archived"])
+	}
+	s.Equal("100", body["totals"].(map[string]interface{})["revenue"])
+}
+
+func (s *AnalyticsBusinessComparisonSuite) TestCompare_Validation() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+
+	for _, q := range []string{"?rates=EUR", "?rates=EUR:-1", "?rates=EURO:1", "?from=2025-02-01&to=2025-01-01", "?to=01-01-2025"} {
+		status, body := s.get(owner.Token, q)
+		s.Equal(http.StatusBadRequest, status, q, body)
+	}
+
+	member := &account.User{
+		WorkspaceID:        owner.Workspace.ID,
+		Role:               role.RoleUser,
+		FirstName:          "Staff",
+		LastName:           "Member",
+		Email:              fmt.Sprintf("staff-%s@example.com", owner.Workspace.ID),
+		IsEmailVerified:    true,
+		RestrictFinancials: true,
+	}
+	s.Require().NoError(database.NewRepository[account.User](testEnv.Database).CreateOne(ctx, member))
+	token, err := auth.NewJwtToken(member.ID, member.WorkspaceID, member.AuthVersion)
+	s.Require().NoError(err)
+	status, _ := s.get(token, "")
+	s.Equal(http.StatusForbidden, status, "margins are financial figures")
+}
+
+func TestAnalyticsBusinessComparisonSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(AnalyticsBusinessComparisonSuite))
+}
@@ -0,0 +1,215 @@
+package e2e_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+var goalTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items",
+	"monthly_goals",
+}
+
+// AnalyticsGoalsSuite tests monthly KPI goals and progress tracking.
+type AnalyticsGoalsSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *AnalyticsGoalsSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *AnalyticsGoalsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, goalTables...))
+}
+
+func (s *AnalyticsGoalsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, goalTables...))
+}
+
+func (s *AnalyticsGoalsSuite) do(biz *business.Business, token, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+biz.Descriptor+"/analytics/goals"+path, payload, token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *AnalyticsGoalsSuite) metric(body map[string]interface{}, name string) map[string]interface{} {
+	for _, m := range body["metrics"].([]interface{}) {
+		if mm := m.(map[string]interface{}); mm["metric"] == name {
+			return mm
+		}
+	}
+	return nil
+}
+
+func (s *AnalyticsGoalsSuite) TestGoalProgress_CurrentMonth() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	_, err = s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: v, Quantity: 1}})
+	s.Require().NoError(err)
+
+	month := time.Now().UTC().Format("2006-01")
+	status, body := s.do(biz, owner.Token, "PUT", "/"+month, map[string]interface{}{
+		"revenueTarget":      "1000",
+		"ordersTarget":       10,
+		"newCustomersTarget": 5,
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(month, body["month"])
+	s.Equal("1000", body["revenueTarget"])
+
+	status, body = s.do(biz, owner.Token, "GET", "/progress", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(month, body["month"])
+	s.Equal("USD", body["currency"])
+	s.Len(body["metrics"], 3)
+
+	revenue := s.metric(body, "revenue")
+	s.Require().NotNil(revenue)
+	s.Equal("100", revenue["actual"])
+	s.Equal("10", revenue["percentOfTarget"])
+	projected, err := decimal.NewFromString(revenue["projected"].(string))
+	s.Require().NoError(err)
+	s.True(projected.GreaterThanOrEqual(decimal.NewFromInt(100)))
+
+	orders := s.metric(body, "orders")
+	s.Require().NotNil(orders)
+	s.Equal("1", orders["actual"])
+	s.Equal("10", orders["target"])
+
+	// updating replaces the targets: omitted ones stop being tracked
+	status, body = s.do(biz, owner.Token, "PUT", "/"+month, map[string]interface{}{"ordersTarget": 20})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Nil(body["revenueTarget"])
+	status, body = s.do(biz, owner.Token, "GET", "/progress?month="+month, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Len(body["metrics"], 1)
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+biz.Descriptor+"/analytics/goals", nil, owner.Token)
+	s.Require().NoError(err)
+	var goals []map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &goals))
+	resp.Body.Close()
+	s.Require().Len(goals, 1)
+	s.EqualValues(20, goals[0]["ordersTarget"])
+
+	status, _ = s.do(biz, owner.Token, "DELETE", "/"+month, nil)
+	s.Equal(http.StatusNoContent, status)
+	status, body = s.do(biz, owner.Token, "GET", "/progress", nil)
+	s.Equal(http.StatusNotFound, status)
+	s.Equal("analytics.goal_not_found", body["extensions"].(map[string]interface{})["code"])
+
+	// a deleted month can be set again
+	status, body = s.do(biz, owner.Token, "PUT", "/"+month, map[string]interface{}{"ordersTarget": 5})
+	s.Equal(http.StatusOK, status, body)
+}
+
+func (s *AnalyticsGoalsSuite) TestGoalProgress_PastMonthIsFinal() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+
+	status, body := s.do(biz, owner.Token, "PUT", "/2020-02", map[string]interface{}{"ordersTarget": 10})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	status, body = s.do(biz, owner.Token, "GET", "/progress?month=2020-02", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.EqualValues(29, body["daysInMonth"])
+	s.EqualValues(29, body["daysElapsed"])
+	s.EqualValues(0, body["daysRemaining"])
+	orders := s.metric(body, "orders")
+	s.Require().NotNil(orders)
+	s.Equal("0", orders["actual"])
+	s.Equal("0", orders["projected"])
+	s.Equal("0", orders["requiredDailyPace"])
+	s.Equal(false, orders["onTrack"])
+}
+
+func (s *AnalyticsGoalsSuite) TestMonthlyGoal_Validation() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+
+	status, body := s.do(biz, owner.Token, "PUT", "/2025-13", map[string]interface{}{"ordersTarget": 1})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("analytics.invalid_goal_month", body["extensions"].(map[string]interface{})["code"])
+
+	status, body = s.do(biz, owner.Token, "PUT", "/2025-01", map[string]interface{}{})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("analytics.goal_targets_required", body["extensions"].(map[string]interface{})["code"])
+
+	status, _ = s.do(biz, owner.Token, "PUT", "/2025-01", map[string]interface{}{"revenueTarget": "-5"})
+	s.Equal(http.StatusBadRequest, status)
+	status, _ = s.do(biz, owner.Token, "PUT", "/2025-01", map[string]interface{}{"ordersTarget": 0})
+	s.Equal(http.StatusBadRequest, status)
+
+	status, _ = s.do(biz, owner.Token, "GET", "/progress?month=January", nil)
+	s.Equal(http.StatusBadRequest, status)
+}
+
+func (s *AnalyticsGoalsSuite) TestMonthlyGoal_MemberCannotSetGoals() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	member := &account.User{
+		WorkspaceID:     owner.Workspace.ID,
+		Role:            role.RoleUser,
+		FirstName:       "Staff",
+		LastName:        "Member",
+		Email:           fmt.Sprintf("staff-%s@example.com", owner.Workspace.ID),
+		IsEmailVerified: true,
+	}
+	s.Require().NoError(database.NewRepository[account.User](testEnv.Database).CreateOne(ctx, member))
+	token, err := auth.NewJwtToken(member.ID, member.WorkspaceID, member.AuthVersion)
+	s.Require().NoError(err)
+
+	status, _ := s.do(biz, token, "PUT", "/2025-01", map[string]interface{}{"ordersTarget": 1})
+	s.Equal(http.StatusForbidden, status)
+
+	status, body := s.do(biz, owner.Token, "PUT", "/2025-01", map[string]interface{}{"ordersTarget": 1})
+	s.Require().Equal(http.StatusOK, status, body)
+	status, _ = s.do(biz, token, "GET", "/progress?month=2025-01", nil)
+	s.Equal(http.StatusOK, status)
+}
+
+func TestAnalyticsGoalsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(AnalyticsGoalsSuite))
+}
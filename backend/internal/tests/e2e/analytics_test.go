@@ -188,6 +188,127 @@ func (s *AnalyticsSuite) TestSalesAnalytics() {
 	s.Contains(result, "salesByChannel")
 }
 
+func (s *AnalyticsSuite) TestSalesAnalytics_CashBasisCountsOrdersWhenPaid() {
+	ctx := context.Background()
+
+	_, ws, token, err := testutils.CreateAuthenticatedUser(ctx, testEnv.Database, "admin@example.com", "Password123!", "Admin", "User", role.RoleAdmin)
+	s.NoError(err)
+	s.NoError(testutils.CreateTestSubscription(ctx, testEnv.Database, ws.ID))
+
+	biz, err := s.analyticsHelper.CreateTestBusiness(ctx, ws.ID, "test-biz")
+	s.NoError(err)
+
+	cat, err := s.analyticsHelper.CreateTestCategory(ctx, biz.ID, "Electronics", "electronics")
+	s.NoError(err)
+	product, err := s.analyticsHelper.CreateTestProduct(ctx, biz.ID, cat.ID, "Test Product")
+	s.NoError(err)
+	variant, err := s.analyticsHelper.CreateTestVariant(ctx, biz.ID, product.ID, "Variant 1",
+		decimal.NewFromInt(50), decimal.NewFromInt(100), 100)
+	s.NoError(err)
+
+	cust, err := s.analyticsHelper.CreateTestCustomer(ctx, biz.ID, "customer@example.com", "Customer")
+	s.NoError(err)
+	addr, err := s.analyticsHelper.CreateTestAddress(ctx, cust.ID)
+	s.NoError(err)
+
+	// placed in December, paid in January
+	placedBefore, err := s.analyticsHelper.CreateTestOrder(ctx, biz.ID, cust.ID, addr.ID, "instagram", order.OrderStatusFulfilled,
+		[]OrderItemData{{VariantID: variant.ID, Quantity: 1, UnitPrice: decimal.NewFromInt(100), UnitCost: decimal.NewFromInt(50)}},
+		time.Date(2024, 12, 28, 10, 0, 0, 0, time.UTC))
+	s.NoError(err)
+	s.NoError(testEnv.Database.Conn(ctx).Model(placedBefore).Updates(map[string]interface{}{
+		"payment_status": order.OrderPaymentStatusPaid,
+		"paid_at":        time.Date(2025, 1, 5, 10, 0, 0, 0, time.UTC),
+	}).Error)
+	// placed in January, never paid
+	_, err = s.analyticsHelper.CreateTestOrder(ctx, biz.ID, cust.ID, addr.ID, "whatsapp", order.OrderStatusFulfilled,
+		[]OrderItemData{{VariantID: variant.ID, Quantity: 2, UnitPrice: decimal.NewFromInt(100), UnitCost: decimal.NewFromInt(50)}},
+		time.Date(2025, 1, 20, 10, 0, 0, 0, time.UTC))
+	s.NoError(err)
+
+	salesRevenue := func() string {
+		resp, err := s.analyticsHelper.Client.AuthenticatedRequest("GET",
+			fmt.Sprintf("/v1/businesses/%s/analytics/sales?from=2025-01-01&to=2025-01-31", biz.Descriptor), nil, token)
+		s.NoError(err)
+		defer resp.Body.Close()
+		s.Equal(http.StatusOK, resp.StatusCode)
+		var result map[string]interface{}
+		s.NoError(testutils.DecodeJSON(resp, &result))
+		return result["totalRevenue"].(string)
+	}
+
+	// accrual counts the January order by the date it was placed
+	s.Equal("200", salesRevenue())
+
+	resp, err := s.analyticsHelper.Client.AuthenticatedRequest("PATCH", "/v1/businesses/"+biz.Descriptor, map[string]interface{}{"accountingBasis": "cash"}, token)
+	s.NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	// cash counts the December order paid in January and leaves out the unpaid one
+	s.Equal("100", salesRevenue())
+}
+
+func (s *AnalyticsSuite) TestSalesAnalytics_BucketsInBusinessTimezone() {
+	ctx := context.Background()
+
+	_, ws, token, err := testutils.CreateAuthenticatedUser(ctx, testEnv.Database, "admin@example.com", "Password123!", "Admin", "User", role.RoleAdmin)
+	s.NoError(err)
+	s.NoError(testutils.CreateTestSubscription(ctx, testEnv.Database, ws.ID))
+
+	biz, err := s.analyticsHelper.CreateTestBusiness(ctx, ws.ID, "test-biz")
+	s.NoError(err)
+	s.NoError(testEnv.Database.Conn(ctx).Model(biz).Update("timezone", "Asia/Dubai").Error)
+	dubai, err := time.LoadLocation("Asia/Dubai")
+	s.NoError(err)
+
+	cat, err := s.analyticsHelper.CreateTestCategory(ctx, biz.ID, "Electronics", "electronics")
+	s.NoError(err)
+	product, err := s.analyticsHelper.CreateTestProduct(ctx, biz.ID, cat.ID, "Test Product")
+	s.NoError(err)
+	variant, err := s.analyticsHelper.CreateTestVariant(ctx, biz.ID, product.ID, "Variant 1",
+		decimal.NewFromInt(50), decimal.NewFromInt(100), 100)
+	s.NoError(err)
+	cust, err := s.analyticsHelper.CreateTestCustomer(ctx, biz.ID, "customer@example.com", "Customer")
+	s.NoError(err)
+	addr, err := s.analyticsHelper.CreateTestAddress(ctx, cust.ID)
+	s.NoError(err)
+
+	// 21:00 UTC on the 10th is already the 11th in Dubai (UTC+4), so both orders share a day.
+	for _, orderedAt := range []time.Time{
+		time.Date(2025, 1, 10, 21, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 11, 10, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 20, 21, 0, 0, 0, time.UTC), // 21 Jan local, outside the range
+	} {
+		_, err = s.analyticsHelper.CreateTestOrder(ctx, biz.ID, cust.ID, addr.ID, "instagram", order.OrderStatusFulfilled,
+			[]OrderItemData{{VariantID: variant.ID, Quantity: 1, UnitPrice: decimal.NewFromInt(100), UnitCost: decimal.NewFromInt(50)}},
+			orderedAt)
+		s.NoError(err)
+	}
+
+	resp, err := s.analyticsHelper.Client.AuthenticatedRequest("GET",
+		fmt.Sprintf("/v1/businesses/%s/analytics/sales?from=2025-01-05&to=2025-01-20", biz.Descriptor), nil, token)
+	s.NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		TotalRevenue    string `json:"totalRevenue"`
+		RevenueOverTime struct {
+			Series []struct {
+				Timestamp time.Time `json:"timestamp"`
+				Value     float64   `json:"value"`
+			} `json:"series"`
+		} `json:"revenueOverTime"`
+	}
+	s.NoError(testutils.DecodeJSON(resp, &result))
+
+	s.Equal("200", result.TotalRevenue)
+	s.Require().Len(result.RevenueOverTime.Series, 1)
+	s.True(time.Date(2025, 1, 11, 0, 0, 0, 0, dubai).Equal(result.RevenueOverTime.Series[0].Timestamp))
+	s.Equal(float64(200), result.RevenueOverTime.Series[0].Value)
+}
+
 func (s *AnalyticsSuite) TestCustomerAnalytics() {
 	ctx := context.Background()
 
@@ -301,6 +422,81 @@ func (s *AnalyticsSuite) TestFinancialPosition() {
 	s.Equal("1000", result["ownerInvestment"])
 }
 
+func (s *AnalyticsSuite) TestReports_FollowFiscalYear() {
+	ctx := context.Background()
+
+	_, ws, token, err := testutils.CreateAuthenticatedUser(ctx, testEnv.Database, "admin@example.com", "Password123!", "Admin", "User", role.RoleAdmin)
+	s.NoError(err)
+	s.NoError(testutils.CreateTestSubscription(ctx, testEnv.Database, ws.ID))
+
+	biz, err := s.analyticsHelper.CreateTestBusiness(ctx, ws.ID, "test-biz")
+	s.NoError(err)
+
+	resp, err := s.analyticsHelper.Client.AuthenticatedRequest("PATCH", "/v1/businesses/"+biz.Descriptor, map[string]interface{}{"fiscalYearStartMonth": 4}, token)
+	s.NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	cat, err := s.analyticsHelper.CreateTestCategory(ctx, biz.ID, "Electronics", "electronics")
+	s.NoError(err)
+	product, err := s.analyticsHelper.CreateTestProduct(ctx, biz.ID, cat.ID, "Test Product")
+	s.NoError(err)
+	variant, err := s.analyticsHelper.CreateTestVariant(ctx, biz.ID, product.ID, "Variant 1",
+		decimal.NewFromInt(50), decimal.NewFromInt(100), 100)
+	s.NoError(err)
+	cust, err := s.analyticsHelper.CreateTestCustomer(ctx, biz.ID, "customer@example.com", "Customer")
+	s.NoError(err)
+	addr, err := s.analyticsHelper.CreateTestAddress(ctx, cust.ID)
+	s.NoError(err)
+
+	// March closes the fiscal year starting in April 2022; April opens the next one.
+	_, err = s.analyticsHelper.CreateTestOrder(ctx, biz.ID, cust.ID, addr.ID, "instagram", order.OrderStatusFulfilled,
+		[]OrderItemData{{VariantID: variant.ID, Quantity: 1, UnitPrice: decimal.NewFromInt(100), UnitCost: decimal.NewFromInt(50)}},
+		time.Date(2023, 3, 15, 10, 0, 0, 0, time.UTC))
+	s.NoError(err)
+	_, err = s.analyticsHelper.CreateTestOrder(ctx, biz.ID, cust.ID, addr.ID, "instagram", order.OrderStatusFulfilled,
+		[]OrderItemData{{VariantID: variant.ID, Quantity: 2, UnitPrice: decimal.NewFromInt(100), UnitCost: decimal.NewFromInt(50)}},
+		time.Date(2023, 4, 10, 10, 0, 0, 0, time.UTC))
+	s.NoError(err)
+
+	// three years are bucketed by quarter
+	resp, err = s.analyticsHelper.Client.AuthenticatedRequest("GET",
+		fmt.Sprintf("/v1/businesses/%s/analytics/sales?from=2022-01-01&to=2024-12-31", biz.Descriptor), nil, token)
+	s.NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var sales struct {
+		RevenueOverTime struct {
+			Series []struct {
+				Timestamp time.Time `json:"timestamp"`
+				Label     string    `json:"label"`
+				Value     float64   `json:"value"`
+			} `json:"series"`
+		} `json:"revenueOverTime"`
+	}
+	s.NoError(testutils.DecodeJSON(resp, &sales))
+	s.Require().Len(sales.RevenueOverTime.Series, 2)
+	s.Equal("Q4 FY2022/23", sales.RevenueOverTime.Series[0].Label)
+	s.True(sales.RevenueOverTime.Series[0].Timestamp.Equal(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)))
+	s.Equal(float64(100), sales.RevenueOverTime.Series[0].Value)
+	s.Equal("Q1 FY2023/24", sales.RevenueOverTime.Series[1].Label)
+	s.True(sales.RevenueOverTime.Series[1].Timestamp.Equal(time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)))
+	s.Equal(float64(200), sales.RevenueOverTime.Series[1].Value)
+
+	// the fiscal year to date leaves out the March order
+	plResp, err := s.analyticsHelper.Client.AuthenticatedRequest("GET",
+		fmt.Sprintf("/v1/businesses/%s/analytics/reports/profit-and-loss?asOf=2023-06-30&period=fiscal_year", biz.Descriptor), nil, token)
+	s.NoError(err)
+	defer plResp.Body.Close()
+	s.Equal(http.StatusOK, plResp.StatusCode)
+
+	var pl map[string]interface{}
+	s.NoError(testutils.DecodeJSON(plResp, &pl))
+	s.Equal("200", pl["revenue"])
+	s.Equal("2023-04-01T00:00:00Z", pl["from"])
+}
+
 func (s *AnalyticsSuite) TestProfitAndLoss() {
 	ctx := context.Background()
 
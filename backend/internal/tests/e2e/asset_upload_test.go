@@ -5,6 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"image"
+	"image/color"
+	pngenc "image/png"
 	"net/http"
 	"testing"
 	"time"
@@ -397,11 +400,11 @@ func (s *AssetUploadSuite) TestGenerateUploadURLs_ThumbnailGeneration() {
 		expectedCategory string
 	}{
 		{
-			name:             "image should have thumbnail",
+			name:             "image thumbnail is rendered by the server",
 			fileName:         "product.jpg",
 			contentType:      "image/jpeg",
 			sizeBytes:        5_000_000,
-			expectThumbnail:  true,
+			expectThumbnail:  false,
 			expectedCategory: "image",
 		},
 		{
@@ -539,7 +542,7 @@ func (s *AssetUploadSuite) TestGenerateUploadURLs_CDNUrls() {
 }
 
 // TestLocalUploadWithThumbnail tests the complete local upload flow including thumbnail upload
-// This test simulates what the frontend does: request URLs, upload main file, upload thumbnail
+// This test simulates what the frontend does for videos: request URLs, upload main file, upload thumbnail
 func (s *AssetUploadSuite) TestLocalUploadWithThumbnail() {
 	ctx := context.Background()
 
@@ -548,10 +551,10 @@ func (s *AssetUploadSuite) TestLocalUploadWithThumbnail() {
 	s.createSubscription(ctx, ws.ID)
 	s.createBusiness(ctx, ws.ID, "test-biz")
 
-	// Step 1: Request upload URLs for an image
+	// Step 1: Request upload URLs for a video
 	reqBody := asset.GenerateUploadURLsRequest{
 		Files: []asset.FileUploadRequest{
-			{FileName: "product-image.jpg", ContentType: "image/jpeg", SizeBytes: 500_000},
+			{FileName: "product-demo.mp4", ContentType: "video/mp4", SizeBytes: 500_000},
 		},
 	}
 	body, _ := json.Marshal(reqBody)
@@ -572,13 +575,13 @@ func (s *AssetUploadSuite) TestLocalUploadWithThumbnail() {
 
 	s.Len(result.Uploads, 1)
 	upload := result.Uploads[0]
-	s.NotNil(upload.Thumbnail, "Image should have thumbnail descriptor")
+	s.NotNil(upload.Thumbnail, "Video should have thumbnail descriptor")
 
-	// Step 2: Upload main image (simulate with 500KB of data)
+	// Step 2: Upload main video (simulate with 500KB of data)
 	mainFileData := bytes.Repeat([]byte("A"), 500_000)
 	mainUploadReq, err := http.NewRequest("POST", upload.URL, bytes.NewReader(mainFileData))
 	s.NoError(err)
-	mainUploadReq.Header.Set("Content-Type", "image/jpeg")
+	mainUploadReq.Header.Set("Content-Type", "video/mp4")
 
 	mainUploadResp, err := http.DefaultClient.Do(mainUploadReq)
 	s.NoError(err)
@@ -587,7 +590,7 @@ func (s *AssetUploadSuite) TestLocalUploadWithThumbnail() {
 	s.Equal(http.StatusOK, mainUploadResp.StatusCode, "Main file upload should succeed")
 
 	// Step 3: Upload thumbnail (client generates smaller thumbnail, e.g., 10KB)
-	// This simulates the frontend generating a thumbnail after resizing/compressing the image
+	// This simulates the frontend generating a thumbnail from a video frame
 	thumbnailData := bytes.Repeat([]byte("T"), 10_000) // 10KB thumbnail
 	thumbUploadReq, err := http.NewRequest("POST", upload.Thumbnail.URL, bytes.NewReader(thumbnailData))
 	s.NoError(err)
@@ -629,6 +632,77 @@ func (s *AssetUploadSuite) TestLocalUploadWithThumbnail() {
 	s.Equal(http.StatusOK, getThumbResp.StatusCode)
 }
 
+// TestLocalImageUploadRendersThumbnailAndWebP tests that uploading an image renders its
+// thumbnail and WebP rendition on the server.
+func (s *AssetUploadSuite) TestLocalImageUploadRendersThumbnailAndWebP() {
+	ctx := context.Background()
+
+	_, ws, token, err := s.helper.CreateTestUser(ctx, "render@example.com", "Password123!", "Test", "User", role.RoleAdmin)
+	s.NoError(err)
+	s.createSubscription(ctx, ws.ID)
+	s.createBusiness(ctx, ws.ID, "test-biz")
+
+	img := image.NewRGBA(image.Rect(0, 0, 800, 600))
+	for y := 0; y < 600; y++ {
+		for x := 0; x < 800; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 90, A: 255})
+		}
+	}
+	var png bytes.Buffer
+	s.Require().NoError(pngenc.Encode(&png, img))
+
+	body, _ := json.Marshal(asset.GenerateUploadURLsRequest{
+		Files: []asset.FileUploadRequest{{FileName: "shoe.png", ContentType: "image/png", SizeBytes: int64(png.Len())}},
+	})
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/v1/businesses/test-biz/assets/uploads", e2eBaseURL), bytes.NewReader(body))
+	s.Require().NoError(err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var result asset.GenerateUploadURLsResponse
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&result))
+	upload := result.Uploads[0]
+	s.Nil(upload.Thumbnail, "image thumbnails are rendered by the server")
+
+	uploadReq, err := http.NewRequest("POST", upload.URL, bytes.NewReader(png.Bytes()))
+	s.Require().NoError(err)
+	uploadReq.Header.Set("Content-Type", "image/png")
+	uploadResp, err := http.DefaultClient.Do(uploadReq)
+	s.Require().NoError(err)
+	defer uploadResp.Body.Close()
+	s.Require().Equal(http.StatusOK, uploadResp.StatusCode)
+
+	assetRepo := database.NewRepository[asset.Asset](testEnv.Database)
+	uploaded, err := assetRepo.FindByID(ctx, upload.AssetID)
+	s.Require().NoError(err)
+	s.Equal(800, uploaded.Width)
+	s.Equal(600, uploaded.Height)
+	s.Require().NotNil(uploaded.ThumbnailAssetID)
+	s.Require().NotNil(uploaded.WebPAssetID)
+
+	thumb, err := assetRepo.FindByID(ctx, *uploaded.ThumbnailAssetID)
+	s.Require().NoError(err)
+	s.Equal(uploaded.ID, thumb.ParentAssetID)
+	s.Equal("image/jpeg", thumb.ContentType)
+	s.Equal(400, thumb.Width)
+	s.Equal(300, thumb.Height)
+
+	ref := uploaded.PhotoReference()
+	s.Require().NotNil(ref.ThumbnailURL)
+	s.Require().NotNil(ref.WebPURL)
+	s.Require().NotNil(ref.Metadata)
+	s.Equal(800, *ref.Metadata.Width)
+
+	webpResp, err := http.Get(uploaded.WebPPublicURL)
+	s.Require().NoError(err)
+	defer webpResp.Body.Close()
+	s.Equal(http.StatusOK, webpResp.StatusCode)
+	s.Equal("image/webp", webpResp.Header.Get("Content-Type"))
+}
+
 func TestAssetUploadSuite(t *testing.T) {
 	suite.Run(t, new(AssetUploadSuite))
 }
@@ -0,0 +1,147 @@
+package e2e_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var auditLogTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"customers", "customer_addresses", "audit_entries",
+}
+
+// AuditLogSuite tests that sensitive requests are recorded, redacted, in the workspace audit log.
+type AuditLogSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *AuditLogSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *AuditLogSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, auditLogTables...))
+}
+
+func (s *AuditLogSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, auditLogTables...))
+}
+
+func (s *AuditLogSuite) member(ctx context.Context, workspaceID string) (*account.User, string) {
+	u := &account.User{
+		WorkspaceID:     workspaceID,
+		Role:            role.RoleUser,
+		FirstName:       "Staff",
+		LastName:        "Member",
+		Email:           fmt.Sprintf("staff-%d@example.com", time.Now().UnixNano()),
+		IsEmailVerified: true,
+	}
+	s.Require().NoError(database.NewRepository[account.User](testEnv.Database).CreateOne(ctx, u))
+	token, err := auth.NewJwtToken(u.ID, u.WorkspaceID, u.AuthVersion)
+	s.Require().NoError(err)
+	return u, token
+}
+
+func (s *AuditLogSuite) request(method, path string, body interface{}, token string) int {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, path, body, token)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+// entries waits for the audit log to hold n entries matching query and returns them.
+func (s *AuditLogSuite) entries(token, query string, n int) []map[string]interface{} {
+	var items []map[string]interface{}
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/workspaces/audit-log"+query, nil, token)
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusOK, resp.StatusCode)
+		var body struct {
+			Items []map[string]interface{} `json:"items"`
+		}
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+		if items = body.Items; len(items) >= n {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	s.Require().Len(items, n)
+	return items
+}
+
+func (s *AuditLogSuite) TestRecordsRedactedSensitiveRequests() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	member, _ := s.member(ctx, owner.Workspace.ID)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+
+	status := s.request("PATCH", "/v1/workspaces/users/"+member.ID+"/role", map[string]interface{}{"role": "admin", "password": "hunter2"}, owner.Token)
+	s.Require().Equal(http.StatusOK, status)
+	status = s.request("DELETE", "/v1/businesses/"+biz.Descriptor+"/customers/"+cust.ID, nil, owner.Token)
+	s.Require().Equal(http.StatusNoContent, status)
+	// reads are not audited
+	status = s.request("GET", "/v1/workspaces/users", nil, owner.Token)
+	s.Require().Equal(http.StatusOK, status)
+
+	items := s.entries(owner.Token, "?orderBy=occurredAt", 2)
+	roleChange := items[0]
+	s.Equal("workspace.user_role_changed", roleChange["action"])
+	s.Equal(owner.User.ID, roleChange["actorId"])
+	s.Equal("/v1/workspaces/users/:userId/role", roleChange["route"])
+	s.Equal(member.ID, roleChange["params"].(map[string]interface{})["userId"])
+	s.EqualValues(http.StatusOK, roleChange["statusCode"])
+	s.Equal(map[string]interface{}{"role": "admin", "password": "[REDACTED]"}, roleChange["requestBody"])
+	s.Equal("admin", roleChange["responseBody"].(map[string]interface{})["role"])
+
+	deletion := items[1]
+	s.Equal("resource.deleted", deletion["action"])
+	s.Equal(biz.ID, deletion["businessId"])
+	s.EqualValues(http.StatusNoContent, deletion["statusCode"])
+	s.Nil(deletion["responseBody"], "deletions do not capture responses")
+
+	s.entries(owner.Token, "?action=resource.deleted", 1)
+}
+
+func (s *AuditLogSuite) TestRecordsRejectedAttemptsAndScopesToWorkspace() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	member, memberToken := s.member(ctx, owner.Workspace.ID)
+
+	status := s.request("PATCH", "/v1/workspaces/users/"+owner.User.ID+"/role", map[string]interface{}{"role": "user"}, memberToken)
+	s.Require().Equal(http.StatusForbidden, status)
+
+	items := s.entries(owner.Token, "?actorId="+member.ID, 1)
+	s.EqualValues(http.StatusForbidden, items[0]["statusCode"])
+
+	s.Equal(http.StatusForbidden, s.request("GET", "/v1/workspaces/audit-log", nil, memberToken), "only workspace managers read the log")
+
+	other, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	s.entries(other.Token, "", 0)
+}
+
+func TestAuditLogSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(AuditLogSuite))
+}
@@ -0,0 +1,133 @@
+package e2e_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var deleteConfirmationTables = []string{"users", "workspaces", "businesses", "subscriptions", "plans"}
+
+// BusinessDeleteConfirmationSuite tests that deleting a business takes a server-issued,
+// single-use confirmation token and the business descriptor.
+type BusinessDeleteConfirmationSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *BusinessDeleteConfirmationSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *BusinessDeleteConfirmationSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, deleteConfirmationTables...))
+}
+
+func (s *BusinessDeleteConfirmationSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, deleteConfirmationTables...))
+}
+
+func (s *BusinessDeleteConfirmationSuite) issue(descriptor, token string) auth.Confirmation {
+	resp, err := s.helper.Client.AuthenticatedRequest("POST", "/v1/businesses/"+descriptor+"/delete-confirmation", nil, token)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var confirmation auth.Confirmation
+	s.Require().NoError(testutils.DecodeJSON(resp, &confirmation))
+	return confirmation
+}
+
+func (s *BusinessDeleteConfirmationSuite) deleteBusiness(descriptor string, body interface{}, token string) int {
+	resp, err := s.helper.Client.AuthenticatedRequest("DELETE", "/v1/businesses/"+descriptor, body, token)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func (s *BusinessDeleteConfirmationSuite) exists(ctx context.Context, id string) bool {
+	_, err := database.NewRepository[business.Business](testEnv.Database).FindByID(ctx, id)
+	if database.IsRecordNotFound(err) {
+		return false
+	}
+	s.Require().NoError(err)
+	return true
+}
+
+func (s *BusinessDeleteConfirmationSuite) TestDeleteRequiresConfirmation() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+
+	s.Equal(http.StatusPreconditionRequired, s.deleteBusiness(biz.Descriptor, nil, owner.Token))
+
+	confirmation := s.issue(biz.Descriptor, owner.Token)
+	s.Equal("business.delete", confirmation.Action)
+	s.Equal(biz.ID, confirmation.ResourceID)
+	s.Equal(biz.Descriptor, confirmation.ResourceName)
+	s.True(confirmation.ExpiresAt.After(time.Now()))
+	s.True(confirmation.ExpiresAt.Before(time.Now().Add(10 * time.Minute)))
+
+	// a wrong name burns the token
+	status := s.deleteBusiness(biz.Descriptor, map[string]interface{}{"confirmationToken": confirmation.Token, "confirmName": "something-else"}, owner.Token)
+	s.Equal(http.StatusForbidden, status)
+	status = s.deleteBusiness(biz.Descriptor, map[string]interface{}{"confirmationToken": confirmation.Token, "confirmName": biz.Descriptor}, owner.Token)
+	s.Equal(http.StatusForbidden, status)
+	s.True(s.exists(ctx, biz.ID))
+
+	confirmation = s.issue(biz.Descriptor, owner.Token)
+	status = s.deleteBusiness(biz.Descriptor, map[string]interface{}{"confirmationToken": confirmation.Token, "confirmName": biz.Descriptor}, owner.Token)
+	s.Equal(http.StatusNoContent, status)
+	s.False(s.exists(ctx, biz.ID))
+}
+
+func (s *BusinessDeleteConfirmationSuite) TestTokenIsBoundToBusinessAndActor() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	first, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	second, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+
+	confirmation := s.issue(first.Descriptor, owner.Token)
+	status := s.deleteBusiness(second.Descriptor, map[string]interface{}{"confirmationToken": confirmation.Token, "confirmName": second.Descriptor}, owner.Token)
+	s.Equal(http.StatusForbidden, status, "a token only confirms the business it was issued for")
+
+	other := &account.User{
+		WorkspaceID:     owner.Workspace.ID,
+		Role:            role.RoleAdmin,
+		FirstName:       "Other",
+		LastName:        "Admin",
+		Email:           fmt.Sprintf("admin-%d@example.com", time.Now().UnixNano()),
+		IsEmailVerified: true,
+	}
+	s.Require().NoError(database.NewRepository[account.User](testEnv.Database).CreateOne(ctx, other))
+	otherToken, err := auth.NewJwtToken(other.ID, other.WorkspaceID, other.AuthVersion)
+	s.Require().NoError(err)
+
+	confirmation = s.issue(first.Descriptor, owner.Token)
+	status = s.deleteBusiness(first.Descriptor, map[string]interface{}{"confirmationToken": confirmation.Token, "confirmName": first.Descriptor}, otherToken)
+	s.Equal(http.StatusForbidden, status, "a token only confirms the actor it was issued to")
+	s.True(s.exists(ctx, first.ID))
+	s.True(s.exists(ctx, second.ID))
+}
+
+func TestBusinessDeleteConfirmationSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(BusinessDeleteConfirmationSuite))
+}
@@ -190,28 +190,85 @@ func (s *BusinessPaymentMethodsSuite) TestOrderPaid_CreatesTransactionFeeExpense
 	defer paidResp.Body.Close()
 	s.Equal(http.StatusOK, paidResp.StatusCode)
 
-	// Poll DB for async bus side effect.
+	feeExpense := s.waitForFeeExpense(ctx, biz.ID, orderID)
+	s.NotNil(feeExpense, "expected transaction fee expense to be created")
+	if feeExpense != nil {
+		s.Equal(accounting.ExpenseCategoryTransactionFee, feeExpense.Category)
+		s.Equal(expectedFee.StringFixed(2), feeExpense.Amount.Round(2).StringFixed(2))
+	}
+}
+
+func (s *BusinessPaymentMethodsSuite) TestOrderCreatedPaid_CreatesTransactionFeeExpense() {
+	ctx := context.Background()
+	_, ws, token, err := s.accountHelper.CreateTestUser(ctx, "admin@example.com", "Password123!", "Admin", "User", role.RoleAdmin)
+	s.NoError(err)
+	s.NoError(s.accountHelper.CreateTestSubscription(ctx, ws.ID))
+	biz, err := s.orderHelper.CreateTestBusiness(ctx, ws.ID, "test-biz")
+	s.NoError(err)
+
+	// Enable credit card with a 2% + 1 fee.
+	pmPayload := map[string]interface{}{"enabled": true, "feePercent": 0.02, "feeFixed": 1}
+	pmResp, err := s.orderHelper.Client.AuthenticatedRequest("PATCH", "/v1/businesses/"+biz.Descriptor+"/payment-methods/credit_card", pmPayload, token)
+	s.NoError(err)
+	defer pmResp.Body.Close()
+	s.Equal(http.StatusOK, pmResp.StatusCode)
+
+	cust, addr, err := s.orderHelper.CreateTestCustomer(ctx, biz.ID, "customer@example.com", "Test Customer")
+	s.NoError(err)
+	cat, err := s.orderHelper.CreateTestCategory(ctx, biz.ID, "Electronics", "electronics")
+	s.NoError(err)
+	_, variant, err := s.orderHelper.CreateTestProduct(ctx, biz.ID, cat.ID, "Test Product", decimal.NewFromInt(0), decimal.NewFromInt(100), 10)
+	s.NoError(err)
+
+	// Record an order that was already paid when it was entered.
+	createPayload := map[string]interface{}{
+		"customerId":        cust.ID,
+		"shippingAddressId": addr.ID,
+		"channel":           "instagram",
+		"paymentMethod":     "credit_card",
+		"status":            "placed",
+		"paymentStatus":     "paid",
+		"items": []map[string]interface{}{
+			{"variantId": variant.ID, "quantity": 1, "unitPrice": 100, "unitCost": 0},
+		},
+	}
+	// Respect create-order rate limit.
+	time.Sleep(1100 * time.Millisecond)
+	createResp, err := s.orderHelper.Client.AuthenticatedRequest("POST", "/v1/businesses/"+biz.Descriptor+"/orders", createPayload, token)
+	s.NoError(err)
+	defer createResp.Body.Close()
+	s.Equal(http.StatusCreated, createResp.StatusCode)
+
+	var created map[string]interface{}
+	s.NoError(testutils.DecodeJSON(createResp, &created))
+	orderID := created["id"].(string)
+	s.Equal("paid", created["paymentStatus"])
+
+	orderTotal, err := decimal.NewFromString(fmt.Sprint(created["total"]))
+	s.NoError(err)
+	expectedFee := orderTotal.Mul(decimal.RequireFromString("0.02")).Add(decimal.NewFromInt(1)).Round(2)
+
+	feeExpense := s.waitForFeeExpense(ctx, biz.ID, orderID)
+	s.Require().NotNil(feeExpense, "expected transaction fee expense to be created")
+	s.Equal(expectedFee.StringFixed(2), feeExpense.Amount.Round(2).StringFixed(2))
+}
+
+// waitForFeeExpense polls for the transaction fee expense booked asynchronously for an order.
+func (s *BusinessPaymentMethodsSuite) waitForFeeExpense(ctx context.Context, businessID, orderID string) *accounting.Expense {
 	expRepo := database.NewRepository[accounting.Expense](testEnv.Database)
-	var feeExpense *accounting.Expense
 	deadline := time.Now().Add(2 * time.Second)
 	for time.Now().Before(deadline) {
 		exp, err := expRepo.FindOne(ctx,
-			expRepo.ScopeBusinessID(biz.ID),
+			expRepo.ScopeBusinessID(businessID),
 			expRepo.ScopeEquals(accounting.ExpenseSchema.OrderID, orderID),
 			expRepo.ScopeEquals(accounting.ExpenseSchema.Category, accounting.ExpenseCategoryTransactionFee),
 		)
 		if err == nil && exp != nil {
-			feeExpense = exp
-			break
+			return exp
 		}
 		time.Sleep(50 * time.Millisecond)
 	}
-
-	s.NotNil(feeExpense, "expected transaction fee expense to be created")
-	if feeExpense != nil {
-		s.Equal(accounting.ExpenseCategoryTransactionFee, feeExpense.Category)
-		s.Equal(expectedFee.StringFixed(2), feeExpense.Amount.Round(2).StringFixed(2))
-	}
+	return nil
 }
 
 func TestBusinessPaymentMethodsSuite(t *testing.T) {
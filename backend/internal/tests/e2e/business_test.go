@@ -500,7 +500,14 @@ func (s *BusinessSuite) TestDeleteBusiness_RemovesPermanently() {
 	s.NoError(err)
 	bizID := s.createBusiness(ctx, ws.ID, "test-business")
 
-	resp, err := s.helper.Client.AuthenticatedRequest("DELETE", "/v1/businesses/test-business", nil, token)
+	confirmResp, err := s.helper.Client.AuthenticatedRequest("POST", "/v1/businesses/test-business/delete-confirmation", nil, token)
+	s.NoError(err)
+	s.Equal(http.StatusCreated, confirmResp.StatusCode)
+	var confirmation map[string]interface{}
+	s.NoError(testutils.DecodeJSON(confirmResp, &confirmation))
+
+	payload := map[string]interface{}{"confirmationToken": confirmation["token"], "confirmName": confirmation["resourceName"]}
+	resp, err := s.helper.Client.AuthenticatedRequest("DELETE", "/v1/businesses/test-business", payload, token)
 	s.NoError(err)
 	defer resp.Body.Close()
 	s.Equal(http.StatusNoContent, resp.StatusCode)
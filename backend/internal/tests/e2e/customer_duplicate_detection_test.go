@@ -0,0 +1,129 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var customerDuplicateTables = []string{"users", "workspaces", "businesses", "subscriptions", "plans", "customers"}
+
+// CustomerDuplicateDetectionSuite tests the possible duplicate check of customer creation.
+type CustomerDuplicateDetectionSuite struct {
+	suite.Suite
+	helper  *CustomerTestHelper
+	factory *testutils.Factory
+}
+
+func (s *CustomerDuplicateDetectionSuite) SetupSuite() {
+	s.helper = NewCustomerTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *CustomerDuplicateDetectionSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, customerDuplicateTables...))
+}
+
+func (s *CustomerDuplicateDetectionSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, customerDuplicateTables...))
+}
+
+func (s *CustomerDuplicateDetectionSuite) setup(ctx context.Context) (*testutils.Owner, *business.Business, *customer.Customer) {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	existing, err := s.factory.Customer(ctx, biz.ID, func(c *customer.Customer) {
+		c.Name = "Mohamed Salah"
+		c.Email = transformer.ToNullableString("mo.salah@example.com")
+		c.PhoneCode = transformer.ToNullableString("+20")
+		c.PhoneNumber = transformer.ToNullableString("1001234567")
+	})
+	s.Require().NoError(err)
+	return owner, biz, existing
+}
+
+func (s *CustomerDuplicateDetectionSuite) create(owner *testutils.Owner, biz *business.Business, payload map[string]interface{}) (int, map[string]interface{}) {
+	body := map[string]interface{}{"countryCode": "eg", "phoneCode": "+20", "phoneNumber": "1112223334"}
+	for k, v := range payload {
+		body[k] = v
+	}
+	resp, err := s.helper.Client.AuthenticatedRequest("POST", "/v1/businesses/"+biz.Descriptor+"/customers", body, owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var out map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &out))
+	return resp.StatusCode, out
+}
+
+func (s *CustomerDuplicateDetectionSuite) requireDuplicate(status int, body map[string]interface{}, customerID string, matchedOn ...interface{}) {
+	s.Require().Equal(http.StatusConflict, status, body)
+	ext := body["extensions"].(map[string]interface{})
+	s.Equal("customer.possible_duplicate", ext["code"])
+	duplicates := ext["duplicates"].([]interface{})
+	s.Require().Len(duplicates, 1)
+	dup := duplicates[0].(map[string]interface{})
+	s.Equal(customerID, dup["id"])
+	s.ElementsMatch(matchedOn, dup["matchedOn"])
+}
+
+func (s *CustomerDuplicateDetectionSuite) TestFlagsMatchingContacts() {
+	owner, biz, existing := s.setup(context.Background())
+
+	status, body := s.create(owner, biz, map[string]interface{}{"name": "Layla Hassan", "email": "MO.Salah@example.com"})
+	s.requireDuplicate(status, body, existing.ID, "email")
+
+	status, body = s.create(owner, biz, map[string]interface{}{"name": "Layla Hassan", "phoneCode": "20", "phoneNumber": "100 123 4567"})
+	s.requireDuplicate(status, body, existing.ID, "phone")
+
+	status, body = s.create(owner, biz, map[string]interface{}{"name": "Layla Hassan", "whatsappNumber": "+20 100-123-4567"})
+	s.requireDuplicate(status, body, existing.ID, "phone")
+}
+
+func (s *CustomerDuplicateDetectionSuite) TestFlagsSimilarNames() {
+	owner, biz, existing := s.setup(context.Background())
+
+	status, body := s.create(owner, biz, map[string]interface{}{"name": "Mohammed Salah"})
+	s.requireDuplicate(status, body, existing.ID, "name")
+
+	status, body = s.create(owner, biz, map[string]interface{}{"name": "Layla Hassan"})
+	s.Equal(http.StatusCreated, status, body)
+}
+
+func (s *CustomerDuplicateDetectionSuite) TestAllowDuplicateOverrides() {
+	owner, biz, _ := s.setup(context.Background())
+
+	status, body := s.create(owner, biz, map[string]interface{}{"name": "Mohamed Salah", "phoneNumber": "1001234567", "allowDuplicate": true})
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Equal("Mohamed Salah", body["name"])
+}
+
+func (s *CustomerDuplicateDetectionSuite) TestIgnoresErasedAndOtherBusinesses() {
+	ctx := context.Background()
+	owner, biz, existing := s.setup(ctx)
+	s.Require().NoError(testEnv.Database.GetDB().Model(&customer.Customer{}).Where("id = ?", existing.ID).Update("erased_at", time.Now().UTC()).Error)
+
+	status, body := s.create(owner, biz, map[string]interface{}{"name": "Mohamed Salah", "phoneNumber": "1001234567"})
+	s.Equal(http.StatusCreated, status, body)
+
+	otherBiz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	_, err = s.factory.Customer(ctx, otherBiz.ID, func(c *customer.Customer) { c.Name = "Nour Adel" })
+	s.Require().NoError(err)
+	status, body = s.create(owner, biz, map[string]interface{}{"name": "Nour Adel"})
+	s.Equal(http.StatusCreated, status, body)
+}
+
+func TestCustomerDuplicateDetectionSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(CustomerDuplicateDetectionSuite))
+}
@@ -0,0 +1,155 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var customerConsentTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"customers", "customer_addresses", "customer_notes",
+}
+
+// CustomerMarketingConsentSuite tests recording marketing consent on customers.
+type CustomerMarketingConsentSuite struct {
+	suite.Suite
+	helper  *CustomerTestHelper
+	factory *testutils.Factory
+}
+
+func (s *CustomerMarketingConsentSuite) SetupSuite() {
+	s.helper = NewCustomerTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *CustomerMarketingConsentSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, customerConsentTables...))
+}
+
+func (s *CustomerMarketingConsentSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, customerConsentTables...))
+}
+
+type consentFixture struct {
+	owner *testutils.Owner
+	biz   *business.Business
+}
+
+func (s *CustomerMarketingConsentSuite) setup(ctx context.Context) *consentFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	return &consentFixture{owner: owner, biz: biz}
+}
+
+func (s *CustomerMarketingConsentSuite) do(fx *consentFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+"/customers"+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func consentOf(body map[string]interface{}, channel string) map[string]interface{} {
+	return body["marketingConsent"].(map[string]interface{})[channel].(map[string]interface{})
+}
+
+func (s *CustomerMarketingConsentSuite) TestConsentIsRecordedPerChannel() {
+	fx := s.setup(context.Background())
+
+	status, body := s.do(fx, "POST", "", map[string]interface{}{
+		"name":        "Sara Ali",
+		"email":       "sara@example.com",
+		"countryCode": "AE",
+		"phoneNumber": "501234567",
+		"phoneCode":   "+971",
+		"marketingConsent": map[string]interface{}{
+			"email":  true,
+			"sms":    false,
+			"source": "in_person",
+		},
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	email := consentOf(body, "email")
+	s.Equal(true, email["granted"])
+	s.Equal("in_person", email["source"])
+	s.NotNil(email["updatedAt"])
+	sms := consentOf(body, "sms")
+	s.Equal(false, sms["granted"])
+	s.NotNil(sms["updatedAt"])
+	whatsapp := consentOf(body, "whatsapp")
+	s.Equal(false, whatsapp["granted"])
+	s.Nil(whatsapp["updatedAt"])
+	customerID := body["id"].(string)
+
+	// repeating an answer keeps when and how it was first given
+	status, body = s.do(fx, "PATCH", "/"+customerID, map[string]interface{}{
+		"marketingConsent": map[string]interface{}{"email": true, "whatsapp": true, "source": "message"},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("in_person", consentOf(body, "email")["source"])
+	s.Equal(email["updatedAt"], consentOf(body, "email")["updatedAt"])
+	s.Equal(true, consentOf(body, "whatsapp")["granted"])
+	s.Equal("message", consentOf(body, "whatsapp")["source"])
+
+	status, body = s.do(fx, "PATCH", "/"+customerID, map[string]interface{}{
+		"marketingConsent": map[string]interface{}{"email": false},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(false, consentOf(body, "email")["granted"])
+	s.Equal("other", consentOf(body, "email")["source"])
+
+	dbCustomer, err := s.helper.GetCustomer(context.Background(), customerID)
+	s.Require().NoError(err)
+	s.False(dbCustomer.CanMarket(customer.ConsentChannelEmail))
+	s.True(dbCustomer.CanMarket(customer.ConsentChannelWhatsapp))
+	s.False(dbCustomer.CanMarket(customer.ConsentChannelSMS))
+}
+
+func (s *CustomerMarketingConsentSuite) TestListFiltersByMarketingConsent() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	subscribed, err := s.factory.Customer(ctx, fx.biz.ID, func(c *customer.Customer) {
+		c.SetMarketingConsent(customer.ConsentChannelEmail, true, customer.ConsentSourceStorefront, c.JoinedAt)
+	})
+	s.Require().NoError(err)
+	_, err = s.factory.Customer(ctx, fx.biz.ID)
+	s.Require().NoError(err)
+
+	status, body := s.do(fx, "GET", "?marketingConsent=email", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	items := body["items"].([]interface{})
+	s.Require().Len(items, 1)
+	s.Equal(subscribed.ID, items[0].(map[string]interface{})["id"])
+
+	status, body = s.do(fx, "GET", "?marketingConsent=fax", nil)
+	s.Equal(http.StatusBadRequest, status, body)
+}
+
+func (s *CustomerMarketingConsentSuite) TestRejectsUnknownSource() {
+	fx := s.setup(context.Background())
+	cust, err := s.factory.Customer(context.Background(), fx.biz.ID)
+	s.Require().NoError(err)
+
+	status, body := s.do(fx, "PATCH", "/"+cust.ID, map[string]interface{}{
+		"marketingConsent": map[string]interface{}{"email": true, "source": "guess"},
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+}
+
+func TestCustomerMarketingConsentSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(CustomerMarketingConsentSuite))
+}
@@ -0,0 +1,175 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/types/nullable"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var customerPrivacyTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "customer_notes",
+	"orders", "order_items", "order_notes", "order_payments", "order_events",
+	"quotes", "quote_items", "recurring_orders",
+}
+
+// CustomerPrivacySuite tests the customer data export and erasure endpoints.
+type CustomerPrivacySuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *CustomerPrivacySuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *CustomerPrivacySuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, customerPrivacyTables...))
+}
+
+func (s *CustomerPrivacySuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, customerPrivacyTables...))
+}
+
+type customerPrivacyFixture struct {
+	owner *testutils.Owner
+	biz   *business.Business
+	cust  *customer.Customer
+	addr  *customer.CustomerAddress
+	order *order.Order
+}
+
+// setup creates a customer with an address, a note and a paid order with a note.
+func (s *CustomerPrivacySuite) setup(ctx context.Context) *customerPrivacyFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID, func(c *customer.Customer) {
+		c.Name = "Mona Hassan"
+		c.PhoneNumber = nullable.NewString("1001234567")
+		c.SetMarketingConsent(customer.ConsentChannelEmail, true, customer.ConsentSourceStorefront, c.JoinedAt)
+	})
+	s.Require().NoError(err)
+	addr, err := s.factory.Address(ctx, cust.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	ord, err := s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: v, Quantity: 2}}, func(o *order.Order) {
+		o.CustomerID = cust.ID
+		o.ShippingAddressID = addr.ID
+		o.Status = order.OrderStatusFulfilled
+		o.PaymentStatus = order.OrderPaymentStatusPaid
+	})
+	s.Require().NoError(err)
+
+	db := testEnv.Database.GetDB()
+	s.Require().NoError(db.Create(&customer.CustomerNote{CustomerID: cust.ID, Content: "prefers evening delivery"}).Error)
+	s.Require().NoError(db.Create(&order.OrderNote{OrderID: ord.ID, Content: "call Mona at the gate"}).Error)
+	return &customerPrivacyFixture{owner: owner, biz: biz, cust: cust, addr: addr, order: ord}
+}
+
+func (s *CustomerPrivacySuite) do(fx *customerPrivacyFixture, method, path string) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+"/customers/"+fx.cust.ID+path, nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *CustomerPrivacySuite) TestDataExportBundlesCustomerData() {
+	fx := s.setup(context.Background())
+
+	status, body := s.do(fx, "GET", "/data-export")
+	s.Require().Equal(http.StatusOK, status, body)
+	s.NotEmpty(body["exportedAt"])
+
+	cust := body["customer"].(map[string]interface{})
+	s.Equal("Mona Hassan", cust["name"])
+	s.Equal(float64(1), cust["ordersCount"])
+	s.Len(body["addresses"], 1)
+	notes := body["notes"].([]interface{})
+	s.Require().Len(notes, 1)
+	s.Equal("prefers evening delivery", notes[0].(map[string]interface{})["content"])
+
+	orders := body["orders"].([]interface{})
+	s.Require().Len(orders, 1)
+	ord := orders[0].(map[string]interface{})
+	s.Equal(fx.order.ID, ord["id"])
+	s.Len(ord["items"], 1)
+	s.Len(ord["notes"], 1)
+	s.Empty(body["quotes"])
+	s.Empty(body["recurringOrders"])
+}
+
+func (s *CustomerPrivacySuite) TestEraseAnonymizesAndKeepsTotals() {
+	fx := s.setup(context.Background())
+
+	status, body := s.do(fx, "POST", "/erase")
+	s.Require().Equal(http.StatusNoContent, status, body)
+
+	status, body = s.do(fx, "GET", "")
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(customer.ErasedCustomerName, body["name"])
+	s.Empty(body["email"])
+	s.Empty(body["phoneNumber"])
+	s.NotEmpty(body["erasedAt"])
+	s.Equal(false, consentOf(body, "email")["granted"])
+	s.Equal("erasure", consentOf(body, "email")["source"])
+	s.Equal(float64(1), body["ordersCount"])
+	s.Equal(fx.order.Total.InexactFloat64(), body["totalSpent"])
+
+	db := testEnv.Database.GetDB()
+	var addr customer.CustomerAddress
+	s.Require().NoError(db.Where("id = ?", fx.addr.ID).First(&addr).Error)
+	s.False(addr.Street.Valid)
+	s.Empty(addr.PhoneNumber)
+	s.Equal("Cairo", addr.City)
+
+	var notes int64
+	s.Require().NoError(db.Unscoped().Model(&customer.CustomerNote{}).Where("customer_id = ?", fx.cust.ID).Count(&notes).Error)
+	s.Zero(notes)
+	s.Require().NoError(db.Unscoped().Model(&order.OrderNote{}).Where("order_id = ?", fx.order.ID).Count(&notes).Error)
+	s.Zero(notes)
+
+	var ord order.Order
+	s.Require().NoError(db.Where("id = ?", fx.order.ID).First(&ord).Error)
+	s.True(fx.order.Total.Equal(ord.Total))
+	s.Equal(order.OrderPaymentStatusPaid, ord.PaymentStatus)
+
+	// erasing again is harmless
+	status, body = s.do(fx, "POST", "/erase")
+	s.Equal(http.StatusNoContent, status, body)
+}
+
+func (s *CustomerPrivacySuite) TestUnknownCustomer() {
+	fx := s.setup(context.Background())
+	fx.cust = &customer.Customer{ID: "cus_missing"}
+
+	status, body := s.do(fx, "GET", "/data-export")
+	s.Equal(http.StatusNotFound, status, body)
+	status, body = s.do(fx, "POST", "/erase")
+	s.Equal(http.StatusNotFound, status, body)
+}
+
+func TestCustomerPrivacySuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(CustomerPrivacySuite))
+}
@@ -0,0 +1,40 @@
+package e2e_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/stretchr/testify/suite"
+)
+
+// DatabaseIndexesSuite verifies declared indexes are created at startup.
+type DatabaseIndexesSuite struct {
+	suite.Suite
+}
+
+func (s *DatabaseIndexesSuite) TestDeclaredIndexesExist() {
+	var declared []database.Index
+	declared = append(declared, order.Indexes...)
+	declared = append(declared, customer.Indexes...)
+	declared = append(declared, inventory.Indexes...)
+
+	missing, err := database.MissingIndexes(context.Background(), testEnv.Database, declared...)
+	s.NoError(err)
+	s.Empty(missing)
+}
+
+func (s *DatabaseIndexesSuite) TestCreateIndexesIsIdempotent() {
+	s.NoError(database.CreateIndexes(context.Background(), testEnv.Database, order.Indexes...))
+	s.NoError(database.CreateIndexes(context.Background(), testEnv.Database, order.Indexes...))
+}
+
+func TestDatabaseIndexesSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(DatabaseIndexesSuite))
+}
@@ -0,0 +1,99 @@
+package e2e_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/schema"
+	"github.com/stretchr/testify/suite"
+)
+
+// partitionParent is a scratch model of a table partitioned by tenant, like orders by business.
+type partitionParent struct {
+	ID       string `gorm:"column:id;primaryKey;type:text"`
+	TenantID string `gorm:"column:tenant_id;type:text;not null"`
+	Name     string `gorm:"column:name;type:text"`
+}
+
+func (partitionParent) TableName() string { return "partition_parents" }
+
+// DatabasePartitionsSuite converts scratch tables, so the shared schema is left untouched.
+type DatabasePartitionsSuite struct {
+	suite.Suite
+}
+
+func (s *DatabasePartitionsSuite) exec(sql string, args ...any) error {
+	return testEnv.Database.GetDB().Exec(sql, args...).Error
+}
+
+func (s *DatabasePartitionsSuite) SetupTest() {
+	s.TearDownTest()
+	s.Require().NoError(s.exec(`CREATE TABLE partition_parents (id text PRIMARY KEY, tenant_id text NOT NULL, name text)`))
+	s.Require().NoError(s.exec(`CREATE TABLE partition_children (id text PRIMARY KEY,
+		parent_id text CONSTRAINT fk_partition_children_parent REFERENCES partition_parents(id) ON DELETE CASCADE)`))
+	s.Require().NoError(s.exec(`CREATE TABLE partition_notes (id text PRIMARY KEY,
+		parent_id text CONSTRAINT fk_partition_notes_parent REFERENCES partition_parents(id))`))
+	s.Require().NoError(s.exec(`INSERT INTO partition_parents (id, tenant_id) VALUES ('p1', 't1'), ('p2', 't2')`))
+	s.Require().NoError(s.exec(`INSERT INTO partition_children (id, parent_id) VALUES ('c1', 'p1')`))
+
+	s.Require().NoError(database.PartitionTable(context.Background(), testEnv.Database,
+		database.Partitioning{Table: "partition_parents", Column: "tenant_id", Partitions: 2}))
+}
+
+func (s *DatabasePartitionsSuite) TearDownTest() {
+	s.NoError(s.exec(`DROP TABLE IF EXISTS partition_children, partition_notes, partition_parents`))
+	for _, fn := range []string{"fk_partition_children_parent", "fk_partition_notes_parent"} {
+		s.NoError(s.exec(`DROP FUNCTION IF EXISTS "` + fn + `_check"(), "` + fn + `_ref"()`))
+	}
+}
+
+func (s *DatabasePartitionsSuite) count(table string) int64 {
+	var n int64
+	s.Require().NoError(testEnv.Database.GetDB().Table(table).Count(&n).Error)
+	return n
+}
+
+func (s *DatabasePartitionsSuite) TestReferencesStayEnforced() {
+	err := s.exec(`INSERT INTO partition_children (id, parent_id) VALUES ('c2', 'missing')`)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "fk_partition_children_parent")
+	s.NoError(s.exec(`INSERT INTO partition_children (id, parent_id) VALUES ('c3', NULL)`))
+
+	s.Require().NoError(s.exec(`INSERT INTO partition_notes (id, parent_id) VALUES ('n1', 'p1')`))
+	err = s.exec(`DELETE FROM partition_parents WHERE id = 'p1'`)
+	s.Require().Error(err, "a referenced row cannot be deleted")
+	s.Contains(err.Error(), "fk_partition_notes_parent")
+
+	// moving a row to another partition keeps it referenced
+	s.Require().NoError(s.exec(`UPDATE partition_parents SET tenant_id = 't2' WHERE id = 'p1'`))
+	s.EqualValues(2, s.count("partition_children"))
+
+	s.Require().NoError(s.exec(`DELETE FROM partition_notes`))
+	s.Require().NoError(s.exec(`DELETE FROM partition_parents WHERE id = 'p1'`))
+	s.EqualValues(1, s.count("partition_children"), "children are deleted with their parent")
+}
+
+func (s *DatabasePartitionsSuite) TestUpdateOneUpdatesInPlace() {
+	ctx := context.Background()
+	repo := database.NewRepository[partitionParent](testEnv.Database).PartitionedBy(schema.NewField("tenant_id", "tenantId"))
+
+	p, err := repo.FindByID(ctx, "p2")
+	s.Require().NoError(err)
+	p.Name = "renamed"
+	s.Require().NoError(repo.UpdateOne(ctx, p))
+	p, err = repo.FindByID(ctx, "p2")
+	s.Require().NoError(err)
+	s.Equal("renamed", p.Name)
+
+	missing := &partitionParent{ID: "p9", TenantID: "t1"}
+	s.True(database.IsRecordNotFound(repo.UpdateOne(ctx, missing)))
+	s.EqualValues(2, s.count("partition_parents"), "a missing entity is not inserted")
+}
+
+func TestDatabasePartitionsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(DatabasePartitionsSuite))
+}
@@ -0,0 +1,196 @@
+package e2e_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var barcodeTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants", "variant_cost_changes",
+}
+
+// InventoryBarcodesSuite tests variant barcodes, scanner lookup and label printing.
+type InventoryBarcodesSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *InventoryBarcodesSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *InventoryBarcodesSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, barcodeTables...))
+}
+
+func (s *InventoryBarcodesSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, barcodeTables...))
+}
+
+type barcodeFixture struct {
+	owner   *testutils.Owner
+	biz     *business.Business
+	product *inventory.Product
+}
+
+func (s *InventoryBarcodesSuite) setup(ctx context.Context) *barcodeFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	return &barcodeFixture{owner: owner, biz: biz, product: prod}
+}
+
+func (s *InventoryBarcodesSuite) do(fx *barcodeFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *InventoryBarcodesSuite) problemCode(body map[string]interface{}) interface{} {
+	return body["extensions"].(map[string]interface{})["code"]
+}
+
+func (s *InventoryBarcodesSuite) createVariant(fx *barcodeFixture, code, sku, barcode string) (int, map[string]interface{}) {
+	return s.do(fx, "POST", "/inventory/variants", map[string]interface{}{
+		"productId":          fx.product.ID,
+		"code":               code,
+		"sku":                sku,
+		"barcode":            barcode,
+		"costPrice":          "5",
+		"salePrice":          "12.5",
+		"stockQuantity":      3,
+		"stockQuantityAlert": 1,
+	})
+}
+
+func (s *InventoryBarcodesSuite) TestBarcodeValidationAndUniqueness() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	status, body := s.createVariant(fx, "red", "TSHIRT-RED", "4006381333932")
+	s.Require().Equal(http.StatusBadRequest, status, body)
+	s.Equal("inventory.invalid_barcode", s.problemCode(body))
+
+	status, body = s.createVariant(fx, "red", "TSHIRT-RED", " 4006381333931 ")
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Equal("4006381333931", body["barcode"])
+	red := body["id"].(string)
+
+	status, body = s.createVariant(fx, "blue", "TSHIRT-BLUE", "4006381333931")
+	s.Require().Equal(http.StatusConflict, status, body)
+	s.Equal("inventory.barcode_taken", s.problemCode(body))
+
+	status, body = s.createVariant(fx, "blue", "TSHIRT-RED", "")
+	s.Require().Equal(http.StatusConflict, status, body)
+	s.Equal("inventory.sku_taken", s.problemCode(body))
+
+	// Variants without a barcode do not collide with each other.
+	status, body = s.createVariant(fx, "blue", "TSHIRT-BLUE", "")
+	s.Require().Equal(http.StatusCreated, status, body)
+	blue := body["id"].(string)
+	status, body = s.createVariant(fx, "green", "TSHIRT-GREEN", "")
+	s.Require().Equal(http.StatusCreated, status, body)
+
+	status, body = s.do(fx, "PATCH", "/inventory/variants/"+blue, map[string]interface{}{"barcode": "4006381333931"})
+	s.Require().Equal(http.StatusConflict, status, body)
+	s.Equal("inventory.barcode_taken", s.problemCode(body))
+
+	// Clearing the barcode frees it for another variant.
+	status, body = s.do(fx, "PATCH", "/inventory/variants/"+red, map[string]interface{}{"barcode": ""})
+	s.Require().Equal(http.StatusOK, status, body)
+	status, body = s.do(fx, "PATCH", "/inventory/variants/"+blue, map[string]interface{}{"barcode": "4006381333931"})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("4006381333931", body["barcode"])
+
+	// Barcodes are unique per business only.
+	other := s.setup(ctx)
+	status, body = s.createVariant(other, "red", "TSHIRT-RED", "4006381333931")
+	s.Require().Equal(http.StatusCreated, status, body)
+}
+
+func (s *InventoryBarcodesSuite) TestLookupByBarcodeOrSKU() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	status, body := s.createVariant(fx, "red", "TSHIRT-RED", "036000291452")
+	s.Require().Equal(http.StatusCreated, status, body)
+	red := body["id"].(string)
+	// A SKU that equals another variant's barcode loses to the barcode match.
+	status, body = s.createVariant(fx, "blue", "036000291452", "")
+	s.Require().Equal(http.StatusCreated, status, body)
+	blue := body["id"].(string)
+
+	status, body = s.do(fx, "GET", "/inventory/variants/lookup?code=036000291452", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(red, body["id"])
+
+	status, body = s.do(fx, "GET", "/inventory/variants/lookup?code="+url.QueryEscape("TSHIRT-RED"), nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(red, body["id"])
+
+	status, body = s.do(fx, "PATCH", "/inventory/variants/"+red, map[string]interface{}{"barcode": ""})
+	s.Require().Equal(http.StatusOK, status, body)
+	status, body = s.do(fx, "GET", "/inventory/variants/lookup?code=036000291452", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(blue, body["id"])
+
+	status, body = s.do(fx, "GET", "/inventory/variants/lookup?code=4006381333931", nil)
+	s.Require().Equal(http.StatusNotFound, status, body)
+	s.Equal("inventory.variant_not_found", s.problemCode(body))
+
+	status, _ = s.do(fx, "GET", "/inventory/variants/lookup", nil)
+	s.Equal(http.StatusBadRequest, status)
+}
+
+func (s *InventoryBarcodesSuite) TestLabelsPDF() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	status, body := s.createVariant(fx, "red", "TSHIRT-RED", "4006381333931")
+	s.Require().Equal(http.StatusCreated, status, body)
+	red := body["id"].(string)
+
+	resp, err := s.helper.Client.AuthenticatedRequest("POST", "/v1/businesses/"+fx.biz.Descriptor+"/inventory/variants/labels", map[string]interface{}{
+		"variantIds": []string{red, "var_missing"},
+		"copies":     30,
+	}, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Equal("application/pdf", resp.Header.Get("Content-Type"))
+	s.Contains(resp.Header.Get("Content-Disposition"), "variant-labels.pdf")
+	data, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	s.True(bytes.HasPrefix(data, []byte("%PDF")))
+	// 30 labels do not fit on one 24-label sheet.
+	s.Contains(string(data), "/Count 2")
+
+	status, body = s.do(fx, "POST", "/inventory/variants/labels", map[string]interface{}{"variantIds": []string{"var_missing"}})
+	s.Require().Equal(http.StatusBadRequest, status, body)
+	s.Equal("inventory.no_label_variants", s.problemCode(body))
+}
+
+func TestInventoryBarcodesSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(InventoryBarcodesSuite))
+}
@@ -0,0 +1,121 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var bulkVariantTables = append([]string{"variant_cost_changes", "stock_movements"}, inventoryTables...)
+
+// InventoryBulkVariantsSuite tests applying price and stock changes to many variants at once.
+type InventoryBulkVariantsSuite struct {
+	suite.Suite
+	helper  *InventoryTestHelper
+	factory *testutils.Factory
+}
+
+func (s *InventoryBulkVariantsSuite) SetupSuite() {
+	s.helper = NewInventoryTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *InventoryBulkVariantsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, bulkVariantTables...))
+}
+
+func (s *InventoryBulkVariantsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, bulkVariantTables...))
+}
+
+func (s *InventoryBulkVariantsSuite) variant(id string) *inventory.Variant {
+	var v inventory.Variant
+	s.Require().NoError(testEnv.Database.GetDB().Where("id = ?", id).First(&v).Error)
+	return &v
+}
+
+func (s *InventoryBulkVariantsSuite) TestBulkUpdateAppliesAllChanges() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	a, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	b, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+
+	resp, err := s.helper.Client.AuthenticatedRequest("PATCH", "/v1/businesses/"+biz.Descriptor+"/inventory/variants/bulk", map[string]interface{}{
+		"changes": []map[string]interface{}{
+			{"variantId": a.ID, "salePrice": "120", "costPrice": "60"},
+			{"variantId": b.ID, "stockDelta": -4},
+		},
+	}, owner.Token)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var body []map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	s.Require().Len(body, 2)
+	s.Equal(a.ID, body[0]["id"])
+	s.Equal(b.ID, body[1]["id"])
+
+	updatedA := s.variant(a.ID)
+	s.Equal("120", updatedA.SalePrice.String())
+	s.Equal("60", updatedA.CostPrice.String())
+	s.Equal(10, updatedA.StockQuantity)
+	s.Equal(6, s.variant(b.ID).StockQuantity)
+
+	var changes, movements int64
+	s.Require().NoError(testEnv.Database.GetDB().Table("variant_cost_changes").Where("variant_id = ?", a.ID).Count(&changes).Error)
+	s.Equal(int64(1), changes)
+	s.Require().NoError(testEnv.Database.GetDB().Table("stock_movements").Where("variant_id = ?", b.ID).Count(&movements).Error)
+	s.Equal(int64(1), movements)
+}
+
+func (s *InventoryBulkVariantsSuite) TestBulkUpdateRejectsInvalidChangesAtomically() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	a, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	b, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+
+	resp, err := s.helper.Client.AuthenticatedRequest("PATCH", "/v1/businesses/"+biz.Descriptor+"/inventory/variants/bulk", map[string]interface{}{
+		"changes": []map[string]interface{}{
+			{"variantId": a.ID, "salePrice": "150"},
+			{"variantId": b.ID, "stockDelta": -11},
+			{"variantId": "var_missing", "salePrice": "10"},
+		},
+	}, owner.Token)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusUnprocessableEntity, resp.StatusCode)
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	s.Equal("inventory.bulk_variant_update_invalid", body["extensions"].(map[string]interface{})["code"])
+	errs := body["extensions"].(map[string]interface{})["errors"].([]interface{})
+	s.Require().Len(errs, 2)
+	s.Equal(float64(1), errs[0].(map[string]interface{})["index"])
+	s.Equal("stockDelta", errs[0].(map[string]interface{})["field"])
+	s.Equal(float64(2), errs[1].(map[string]interface{})["index"])
+
+	s.Equal("100", s.variant(a.ID).SalePrice.String())
+	s.Equal(10, s.variant(b.ID).StockQuantity)
+}
+
+func TestInventoryBulkVariantsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(InventoryBulkVariantsSuite))
+}
@@ -0,0 +1,230 @@
+package e2e_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+var catalogCSVTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants", "variant_cost_changes",
+}
+
+// InventoryCatalogCSVSuite tests catalog CSV import (with dry runs) and export.
+type InventoryCatalogCSVSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *InventoryCatalogCSVSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *InventoryCatalogCSVSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, catalogCSVTables...))
+}
+
+func (s *InventoryCatalogCSVSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, catalogCSVTables...))
+}
+
+type catalogFixture struct {
+	owner *testutils.Owner
+	biz   *business.Business
+}
+
+func (s *InventoryCatalogCSVSuite) setup(ctx context.Context) *catalogFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	return &catalogFixture{owner: owner, biz: biz}
+}
+
+func (s *InventoryCatalogCSVSuite) importCSV(fx *catalogFixture, content string, dryRun bool) (int, map[string]interface{}) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile("file", "catalog.csv")
+	s.Require().NoError(err)
+	_, err = part.Write([]byte(content))
+	s.Require().NoError(err)
+	s.Require().NoError(w.Close())
+
+	path := "/v1/businesses/" + fx.biz.Descriptor + "/inventory/import"
+	if dryRun {
+		path += "?dryRun=true"
+	}
+	resp, err := s.helper.Client.AuthenticatedRequestRaw("POST", path, buf.Bytes(), map[string]string{"Content-Type": w.FormDataContentType()}, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func (s *InventoryCatalogCSVSuite) export(fx *catalogFixture) [][]string {
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+fx.biz.Descriptor+"/inventory/export", nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Contains(resp.Header.Get("Content-Type"), "text/csv")
+	data, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	s.Require().NoError(err)
+	return records
+}
+
+func (s *InventoryCatalogCSVSuite) variants(ctx context.Context, fx *catalogFixture) map[string]*inventory.Variant {
+	repo := database.NewRepository[inventory.Variant](testEnv.Database)
+	list, err := repo.FindMany(ctx, repo.ScopeBusinessID(fx.biz.ID))
+	s.Require().NoError(err)
+	bySKU := map[string]*inventory.Variant{}
+	for _, v := range list {
+		bySKU[v.SKU] = v
+	}
+	return bySKU
+}
+
+const catalogHeader = "productName,productDescription,category,variantCode,sku,barcode,costPrice,salePrice,stockQuantity,stockQuantityAlert\n"
+
+func (s *InventoryCatalogCSVSuite) TestImportCreatesAndUpdates() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	file := catalogHeader +
+		"Linen Shirt,Breathable,Summer Wear,S,SHIRT-S,4006381333931,10,25,5,1\n" +
+		"Linen Shirt,,Summer Wear,M,SHIRT-M,,10,25,7,1\n" +
+		"Canvas Tote,,Bags,One,TOTE-1,,4,12.5,20,\n"
+
+	status, body := s.importCSV(fx, file, true)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(true, body["dryRun"])
+	s.Equal(float64(3), body["rows"])
+	s.Equal(float64(2), body["categoriesCreated"])
+	s.Equal(float64(2), body["productsCreated"])
+	s.Equal(float64(3), body["variantsCreated"])
+	s.Empty(body["errors"])
+	s.Empty(s.variants(ctx, fx), "a dry run writes nothing")
+
+	status, body = s.importCSV(fx, file, false)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(false, body["dryRun"])
+	variants := s.variants(ctx, fx)
+	s.Require().Len(variants, 3)
+	s.Equal("4006381333931", variants["SHIRT-S"].Barcode)
+	s.Equal(7, variants["SHIRT-M"].StockQuantity)
+	s.True(decimal.RequireFromString("12.5").Equal(variants["TOTE-1"].SalePrice))
+	s.Equal("Linen Shirt - S", variants["SHIRT-S"].Name)
+
+	// Re-importing updates by product name and variant code and adds new variants.
+	update := catalogHeader +
+		"linen shirt,,Summer Wear,S,SHIRT-S,4006381333931,11,30,2,1\n" +
+		"Linen Shirt,,Summer Wear,L,SHIRT-L,,10,25,3,1\n"
+	status, body = s.importCSV(fx, update, false)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(float64(0), body["categoriesCreated"])
+	s.Equal(float64(1), body["productsUpdated"])
+	s.Equal(float64(1), body["variantsUpdated"])
+	s.Equal(float64(1), body["variantsCreated"])
+	variants = s.variants(ctx, fx)
+	s.Require().Len(variants, 4)
+	s.Equal(2, variants["SHIRT-S"].StockQuantity)
+	s.True(decimal.NewFromInt(30).Equal(variants["SHIRT-S"].SalePrice))
+	s.Equal(variants["SHIRT-S"].ProductID, variants["SHIRT-L"].ProductID)
+}
+
+func (s *InventoryCatalogCSVSuite) TestRowErrors() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	prod, err := s.factory.Product(ctx, fx.biz.ID)
+	s.Require().NoError(err)
+	_, err = s.factory.Variant(ctx, prod, func(v *inventory.Variant) { v.SKU = "TAKEN" })
+	s.Require().NoError(err)
+
+	file := catalogHeader +
+		"Shirt,,Tops,S,SHIRT-S,,10,25,5,1\n" +
+		"Shirt,,Tops,S,SHIRT-S2,,10,25,5,1\n" +
+		"Shirt,,Bottoms,M,SHIRT-M,,10,25,5,1\n" +
+		"Hat,,Tops,One,TAKEN,,abc,25,-1,1\n" +
+		"Cap,,Tops,One,CAP,4006381333932,1,2,3,\n"
+
+	status, body := s.importCSV(fx, file, true)
+	s.Require().Equal(http.StatusOK, status, body)
+	errs := body["errors"].([]interface{})
+	got := map[string]bool{}
+	for _, e := range errs {
+		m := e.(map[string]interface{})
+		got[fmt.Sprintf("%d:%s", int(m["row"].(float64)), m["column"])] = true
+	}
+	s.Equal(map[string]bool{
+		"3:variantCode":   true,
+		"4:category":      true,
+		"5:costPrice":     true,
+		"5:stockQuantity": true,
+		"6:barcode":       true,
+	}, got)
+
+	status, body = s.importCSV(fx, file, false)
+	s.Require().Equal(http.StatusUnprocessableEntity, status, body)
+	s.Equal("inventory.catalog_import_invalid", body["extensions"].(map[string]interface{})["code"])
+	s.Len(s.variants(ctx, fx), 1, "an invalid file imports nothing")
+
+	status, body = s.importCSV(fx, "Hat,,Tops,One,TAKEN,,1,25,1,1\n"+"x\n", true)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.NotEmpty(body["errors"], "missing header columns are reported")
+
+	status, body = s.importCSV(fx, catalogHeader+"Hat,,Tops,One,TAKEN,,1,25,1,1\n", true)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Require().Len(body["errors"], 1)
+	s.Equal("sku", body["errors"].([]interface{})[0].(map[string]interface{})["column"])
+}
+
+func (s *InventoryCatalogCSVSuite) TestExportRoundTrips() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	file := catalogHeader +
+		"Canvas Tote,,Bags,One,TOTE-1,036000291452,4,12.5,20,2\n" +
+		"=Formula,,Bags,One,FORMULA-1,,1,2,3,0\n"
+	status, body := s.importCSV(fx, file, false)
+	s.Require().Equal(http.StatusOK, status, body)
+
+	records := s.export(fx)
+	s.Require().Len(records, 3)
+	s.Equal(inventory.CatalogColumns, records[0])
+	s.Equal("'=Formula", records[1][0], "formula-like cells are escaped")
+	s.Equal("Canvas Tote", records[2][0])
+	s.Equal("036000291452", records[2][5])
+	s.Equal("12.50", records[2][7])
+
+	// Importing the export unchanged updates the same variants.
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	s.Require().NoError(w.WriteAll(records))
+	status, body = s.importCSV(fx, buf.String(), false)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(float64(0), body["productsCreated"])
+	s.Equal(float64(2), body["variantsUpdated"])
+	s.Contains(s.variants(ctx, fx), "FORMULA-1")
+}
+
+func TestInventoryCatalogCSVSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(InventoryCatalogCSVSuite))
+}
@@ -2,10 +2,13 @@ package e2e_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"testing"
 
+	"github.com/abdelrahman146/kyora/internal/domain/business"
 	"github.com/abdelrahman146/kyora/internal/platform/auth"
 	"github.com/abdelrahman146/kyora/internal/platform/types/role"
 	"github.com/abdelrahman146/kyora/internal/tests/testutils"
@@ -16,11 +19,13 @@ type InventoryCategoriesSuite struct {
 	suite.Suite
 	accountHelper   *AccountTestHelper
 	inventoryHelper *InventoryTestHelper
+	factory         *testutils.Factory
 }
 
 func (s *InventoryCategoriesSuite) SetupSuite() {
 	s.accountHelper = NewAccountTestHelper(testEnv.Database, testEnv.CacheAddr, e2eBaseURL)
 	s.inventoryHelper = NewInventoryTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
 }
 
 func (s *InventoryCategoriesSuite) resetDB() {
@@ -37,26 +42,23 @@ func (s *InventoryCategoriesSuite) TearDownTest() {
 
 func (s *InventoryCategoriesSuite) TestCreateCategory_Success_NormalizesDescriptor() {
 	ctx := context.Background()
-	_, ws, token, err := s.accountHelper.CreateTestUser(ctx, "admin@example.com", "Password123!", "Admin", "User", role.RoleAdmin)
-	s.NoError(err)
-	s.NoError(s.accountHelper.CreateTestSubscription(ctx, ws.ID))
-
-	_, err = s.inventoryHelper.CreateTestBusiness(ctx, ws.ID, "test-biz")
-	s.NoError(err)
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	_, err = s.factory.Business(ctx, owner.Workspace.ID, func(b *business.Business) { b.Descriptor = "test-biz" })
+	s.Require().NoError(err)
 
 	payload := map[string]interface{}{"name": "T-Shirts", "descriptor": "  TShirts  "}
-	resp, err := s.inventoryHelper.Client.AuthenticatedRequest("POST", "/v1/businesses/test-biz/inventory/categories", payload, token)
+	resp, err := s.inventoryHelper.Client.AuthenticatedRequest("POST", "/v1/businesses/test-biz/inventory/categories", payload, owner.Token)
 	s.NoError(err)
 	defer resp.Body.Close()
 	s.Equal(http.StatusCreated, resp.StatusCode)
 
-	var result map[string]interface{}
-	s.NoError(testutils.DecodeJSON(resp, &result))
-	s.NotEmpty(result["id"])
-	s.Equal("T-Shirts", result["name"])
-	s.Equal("tshirts", result["descriptor"])
-	s.Contains(result, "businessId")
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	testutils.AssertGoldenJSON(s.T(), "inventory/create_category", body)
 
+	var result map[string]interface{}
+	s.Require().NoError(json.Unmarshal(body, &result))
 	catID := result["id"].(string)
 	cat, err := s.inventoryHelper.GetCategory(ctx, catID)
 	s.NoError(err)
@@ -0,0 +1,183 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/task"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+var costHistoryTables = append([]string{"customers", "customer_addresses", "orders", "order_items", "tasks", "variant_cost_changes"}, inventoryTables...)
+
+// InventoryCostHistorySuite tests variant price history, the cost trend endpoint and the
+// low margin alerts raised on price changes.
+type InventoryCostHistorySuite struct {
+	suite.Suite
+	helper  *InventoryTestHelper
+	factory *testutils.Factory
+}
+
+func (s *InventoryCostHistorySuite) SetupSuite() {
+	s.helper = NewInventoryTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *InventoryCostHistorySuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, costHistoryTables...))
+}
+
+func (s *InventoryCostHistorySuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, costHistoryTables...))
+}
+
+func (s *InventoryCostHistorySuite) patchVariant(biz *business.Business, variantID string, payload map[string]interface{}, token string) {
+	resp, err := s.helper.Client.AuthenticatedRequest("PATCH", "/v1/businesses/"+biz.Descriptor+"/inventory/variants/"+variantID, payload, token)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (s *InventoryCostHistorySuite) costTrend(biz *business.Business, variantID, token string) inventory.VariantCostTrendResponse {
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+biz.Descriptor+"/inventory/variants/"+variantID+"/cost-trend", nil, token)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var body inventory.VariantCostTrendResponse
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return body
+}
+
+// pricingTasks waits for the business to hold n review pricing tasks and returns them.
+func (s *InventoryCostHistorySuite) pricingTasks(ctx context.Context, businessID string, n int) []*task.Task {
+	repo := database.NewRepository[task.Task](testEnv.Database)
+	var tasks []*task.Task
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		var err error
+		tasks, err = repo.FindMany(ctx,
+			repo.ScopeBusinessID(businessID),
+			repo.ScopeEquals(task.TaskSchema.Type, task.TaskTypeReviewPricing),
+		)
+		s.Require().NoError(err)
+		if len(tasks) >= n {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	s.Require().Len(tasks, n)
+	return tasks
+}
+
+func (s *InventoryCostHistorySuite) TestCostTrendAndMarginAlert() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID, func(b *business.Business) {
+		b.MinMarginPercent = decimal.NewFromInt(30)
+	})
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+
+	// sold at cost 50 / price 100
+	_, err = s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: v, Quantity: 2}})
+	s.Require().NoError(err)
+
+	// 50% -> 20% margin crosses the 30% minimum
+	s.patchVariant(biz, v.ID, map[string]interface{}{"costPrice": "80"}, owner.Token)
+	tasks := s.pricingTasks(ctx, biz.ID, 1)
+	s.Equal(prod.ID, tasks[0].ProductID.String)
+	s.Equal(task.TaskStatusOpen, tasks[0].Status)
+
+	// already below the minimum: no new alert
+	s.patchVariant(biz, v.ID, map[string]interface{}{"costPrice": "85"}, owner.Token)
+	// non-price updates are not recorded
+	s.patchVariant(biz, v.ID, map[string]interface{}{"stockQuantity": 7}, owner.Token)
+
+	v.CostPrice = decimal.NewFromInt(85)
+	_, err = s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: v, Quantity: 1}})
+	s.Require().NoError(err)
+
+	trend := s.costTrend(biz, v.ID, owner.Token)
+	s.Equal(v.ID, trend.VariantID)
+	s.True(trend.CostPrice.Equal(decimal.NewFromInt(85)))
+	s.True(trend.MarginPercent.Decimal.Equal(decimal.NewFromInt(15)))
+
+	s.Require().Len(trend.Changes, 2)
+	s.True(trend.Changes[0].PreviousCostPrice.Decimal.Equal(decimal.NewFromInt(50)))
+	s.True(trend.Changes[0].CostPrice.Equal(decimal.NewFromInt(80)))
+	s.True(trend.Changes[0].MarginPercent.Decimal.Equal(decimal.NewFromInt(20)))
+	s.True(trend.Changes[0].BelowMinMargin)
+	s.Equal(owner.User.ID, trend.Changes[0].ChangedByID)
+	s.False(trend.Changes[1].BelowMinMargin)
+
+	// margin at sale uses the cost snapshotted on each order
+	s.Require().Len(trend.Periods, 1)
+	p := trend.Periods[0]
+	s.EqualValues(3, p.UnitsSold)
+	s.True(p.Revenue.Equal(decimal.NewFromInt(300)), p.Revenue.String())
+	s.True(p.COGS.Equal(decimal.NewFromInt(185)), p.COGS.String())
+	s.True(p.MarginPercent.Decimal.Equal(decimal.RequireFromString("38.33")), p.MarginPercent.Decimal.String())
+
+	s.pricingTasks(ctx, biz.ID, 1)
+}
+
+func (s *InventoryCostHistorySuite) TestCreationRecordsInitialPrices() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+
+	payload := map[string]interface{}{
+		"productId":          prod.ID,
+		"code":               "RED",
+		"costPrice":          "10",
+		"salePrice":          "15",
+		"stockQuantity":      2,
+		"stockQuantityAlert": 1,
+	}
+	resp, err := s.helper.Client.AuthenticatedRequest("POST", "/v1/businesses/"+biz.Descriptor+"/inventory/variants", payload, owner.Token)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	var created map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &created))
+	variantID := created["id"].(string)
+
+	trend := s.costTrend(biz, variantID, owner.Token)
+	s.Require().Len(trend.Changes, 1)
+	s.False(trend.Changes[0].PreviousCostPrice.Valid)
+	s.True(trend.Changes[0].MarginPercent.Decimal.Equal(decimal.RequireFromString("33.33")))
+	s.Empty(trend.Periods)
+
+	// no minimum margin configured: price drops never alert
+	s.patchVariant(biz, variantID, map[string]interface{}{"salePrice": "11"}, owner.Token)
+	s.pricingTasks(ctx, biz.ID, 0)
+
+	other, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	otherBiz, err := s.factory.Business(ctx, other.Workspace.ID)
+	s.Require().NoError(err)
+	resp, err = s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+otherBiz.Descriptor+"/inventory/variants/"+variantID+"/cost-trend", nil, other.Token)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func TestInventoryCostHistorySuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(InventoryCostHistorySuite))
+}
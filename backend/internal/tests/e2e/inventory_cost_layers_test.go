@@ -0,0 +1,184 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var costLayerTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants", "variant_cost_changes", "inventory_cost_layers",
+	"suppliers", "purchase_orders", "purchase_order_items", "purchase_order_receipts",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes", "order_events",
+}
+
+// InventoryCostLayersSuite tests purchase cost layers, landed costs and the COGS of orders
+// under weighted-average and FIFO costing.
+type InventoryCostLayersSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *InventoryCostLayersSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *InventoryCostLayersSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, costLayerTables...))
+}
+
+func (s *InventoryCostLayersSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, costLayerTables...))
+}
+
+type costLayersFixture struct {
+	owner   *testutils.Owner
+	biz     *business.Business
+	cust    *customer.Customer
+	addr    *customer.CustomerAddress
+	variant *inventory.Variant
+}
+
+// setup creates a variant holding 10 units at a cost price of 50.
+func (s *InventoryCostLayersSuite) setup(ctx context.Context) *costLayersFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	addr, err := s.factory.Address(ctx, cust.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	return &costLayersFixture{owner: owner, biz: biz, cust: cust, addr: addr, variant: v}
+}
+
+func (s *InventoryCostLayersSuite) do(fx *costLayersFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+// receive orders qty units of the variant at unitCost and receives them in full.
+func (s *InventoryCostLayersSuite) receive(fx *costLayersFixture, qty int, unitCost string) {
+	status, body := s.do(fx, "POST", "/inventory/suppliers", map[string]interface{}{"name": "Acme Wholesale"})
+	s.Require().Equal(http.StatusCreated, status, body)
+	status, body = s.do(fx, "POST", "/inventory/purchase-orders", map[string]interface{}{
+		"supplierId": body["id"],
+		"items":      []map[string]interface{}{{"variantId": fx.variant.ID, "quantity": qty, "unitCost": unitCost}},
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	itemID := body["items"].([]interface{})[0].(map[string]interface{})["id"]
+	status, body = s.do(fx, "POST", "/inventory/purchase-orders/"+body["id"].(string)+"/receive", map[string]interface{}{
+		"items": []map[string]interface{}{{"itemId": itemID, "quantity": qty}},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+}
+
+// sell creates an order of qty units; unitCost is only sent when not empty.
+func (s *InventoryCostLayersSuite) sell(fx *costLayersFixture, qty int, unitCost string) map[string]interface{} {
+	item := map[string]interface{}{"variantId": fx.variant.ID, "quantity": qty, "unitPrice": "100"}
+	if unitCost != "" {
+		item["unitCost"] = unitCost
+	}
+	status, body := s.do(fx, "POST", "/orders", map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"channel":           "instagram",
+		"items":             []map[string]interface{}{item},
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	return body
+}
+
+func (s *InventoryCostLayersSuite) landedCost(fx *costLayersFixture) string {
+	status, body := s.do(fx, "GET", "/inventory/variants/"+fx.variant.ID, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	return body["landedCost"].(string)
+}
+
+func itemUnitCost(order map[string]interface{}) interface{} {
+	return order["items"].([]interface{})[0].(map[string]interface{})["unitCost"]
+}
+
+func (s *InventoryCostLayersSuite) TestWeightedAverage() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	s.Equal("50", s.landedCost(fx), "without cost layers the landed cost is the cost price")
+
+	// 10 on hand at 50 + 10 received at 80
+	s.receive(fx, 10, "80")
+	s.Equal("65", s.landedCost(fx))
+
+	order := s.sell(fx, 2, "")
+	s.Equal("65", itemUnitCost(order))
+	s.Equal("130", order["cogs"])
+	s.Equal("65", s.landedCost(fx), "sales do not move the average")
+
+	// 18 on hand at 65 + 2 received at 98
+	s.receive(fx, 2, "98")
+	s.Equal("68.3", s.landedCost(fx))
+
+	// an explicit unit cost still overrides the layers
+	order = s.sell(fx, 1, "40")
+	s.Equal("40", order["cogs"])
+}
+
+func (s *InventoryCostLayersSuite) TestFIFO() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	resp, err := s.helper.Client.AuthenticatedRequest("PATCH", "/v1/businesses/"+fx.biz.Descriptor, map[string]interface{}{"costingMethod": "fifo"}, fx.owner.Token)
+	s.Require().NoError(err)
+	var biz map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &biz))
+	resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode, biz)
+	s.Equal("fifo", biz["costingMethod"])
+
+	s.receive(fx, 10, "80")
+	s.Equal("65", s.landedCost(fx))
+
+	// the 10 opening units at 50 go first, then 2 of the received units at 80
+	order := s.sell(fx, 12, "")
+	s.Equal("660", order["cogs"])
+	s.Equal("55", itemUnitCost(order))
+	s.Equal("80", s.landedCost(fx), "only received units are left")
+
+	// deleting the order puts its units back at the cost they were sold at
+	status, body := s.do(fx, "DELETE", "/orders/"+order["id"].(string), nil)
+	s.Require().Equal(http.StatusNoContent, status, body)
+	s.Equal("65", s.landedCost(fx))
+}
+
+func (s *InventoryCostLayersSuite) TestRejectsUnknownCostingMethod() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	resp, err := s.helper.Client.AuthenticatedRequest("PATCH", "/v1/businesses/"+fx.biz.Descriptor, map[string]interface{}{"costingMethod": "lifo"}, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestInventoryCostLayersSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(InventoryCostLayersSuite))
+}
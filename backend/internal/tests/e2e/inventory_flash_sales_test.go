@@ -0,0 +1,197 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var flashSaleTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants", "price_lists", "price_list_entries",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes",
+}
+
+// InventoryFlashSalesSuite tests time-bound promo prices on variants and category flash sales.
+type InventoryFlashSalesSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *InventoryFlashSalesSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *InventoryFlashSalesSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, flashSaleTables...))
+}
+
+func (s *InventoryFlashSalesSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, flashSaleTables...))
+}
+
+type flashSaleFixture struct {
+	owner   *testutils.Owner
+	biz     *business.Business
+	cust    *customer.Customer
+	addr    *customer.CustomerAddress
+	product *inventory.Product
+	variant *inventory.Variant
+	other   *inventory.Variant
+}
+
+// setup creates a storefront-enabled business with a customer and a category holding two
+// variants selling for 100.
+func (s *InventoryFlashSalesSuite) setup(ctx context.Context) *flashSaleFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID, func(b *business.Business) { b.StorefrontEnabled = true })
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	addr, err := s.factory.Address(ctx, cust.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	other, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	return &flashSaleFixture{owner: owner, biz: biz, cust: cust, addr: addr, product: prod, variant: v, other: other}
+}
+
+func (s *InventoryFlashSalesSuite) do(fx *flashSaleFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *InventoryFlashSalesSuite) variant(fx *flashSaleFixture, id string) map[string]interface{} {
+	status, body := s.do(fx, "GET", "/inventory/variants/"+id, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	return body
+}
+
+func (s *InventoryFlashSalesSuite) orderUnitPrice(fx *flashSaleFixture, variantID string) string {
+	status, body := s.do(fx, "POST", "/orders", map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"channel":           "instagram",
+		"items":             []map[string]interface{}{{"variantId": variantID, "quantity": 1}},
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	return body["items"].([]interface{})[0].(map[string]interface{})["unitPrice"].(string)
+}
+
+func (s *InventoryFlashSalesSuite) TestVariantPromo_AppliesOnlyWithinWindow() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	status, body := s.do(fx, "PATCH", "/inventory/variants/"+fx.variant.ID, map[string]interface{}{
+		"promoPrice":    "80",
+		"promoStartsAt": time.Now().Add(time.Hour).Format(time.RFC3339),
+		"promoEndsAt":   time.Now().Add(2 * time.Hour).Format(time.RFC3339),
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	v := s.variant(fx, fx.variant.ID)
+	s.Equal("80", v["promoPrice"])
+	s.Equal("100", v["currentPrice"], "scheduled promo is not active yet")
+	s.Equal("100", s.orderUnitPrice(fx, fx.variant.ID))
+
+	status, body = s.do(fx, "PATCH", "/inventory/variants/"+fx.variant.ID, map[string]interface{}{
+		"promoPrice":  "80",
+		"promoEndsAt": time.Now().Add(time.Hour).Format(time.RFC3339),
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("80", s.variant(fx, fx.variant.ID)["currentPrice"])
+	s.Equal("80", s.orderUnitPrice(fx, fx.variant.ID))
+
+	status, body = s.do(fx, "PATCH", "/inventory/variants/"+fx.variant.ID, map[string]interface{}{"clearPromo": true})
+	s.Require().Equal(http.StatusOK, status, body)
+	v = s.variant(fx, fx.variant.ID)
+	s.Nil(v["promoPrice"])
+	s.Equal("100", v["currentPrice"])
+}
+
+func (s *InventoryFlashSalesSuite) TestVariantPromo_RejectsInvalidWindow() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	status, _ := s.do(fx, "PATCH", "/inventory/variants/"+fx.variant.ID, map[string]interface{}{
+		"promoPrice":  "80",
+		"promoEndsAt": time.Now().Add(-time.Hour).Format(time.RFC3339),
+	})
+	s.Equal(http.StatusBadRequest, status)
+}
+
+func (s *InventoryFlashSalesSuite) TestFlashSale_AppliesToCategory() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	status, body := s.do(fx, "POST", "/inventory/categories/"+fx.product.CategoryID+"/flash-sale", map[string]interface{}{
+		"percentOff": "25",
+		"endsAt":     time.Now().Add(time.Hour).Format(time.RFC3339),
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Len(body["variants"], 2)
+
+	s.Equal("75", s.variant(fx, fx.other.ID)["currentPrice"])
+	s.Equal("75", s.orderUnitPrice(fx, fx.variant.ID))
+
+	resp, err := s.helper.Client.Get("/v1/storefront/" + fx.biz.StorefrontPublicID + "/catalog")
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var catalog map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &catalog))
+	pv := catalog["products"].([]interface{})[0].(map[string]interface{})["variants"].([]interface{})[0].(map[string]interface{})
+	s.Equal("75", pv["salePrice"])
+	s.Equal("100", pv["compareAtPrice"])
+	s.NotEmpty(pv["saleEndsAt"])
+
+	status, _ = s.do(fx, "DELETE", "/inventory/categories/"+fx.product.CategoryID+"/flash-sale", nil)
+	s.Require().Equal(http.StatusNoContent, status)
+	s.Equal("100", s.variant(fx, fx.variant.ID)["currentPrice"])
+}
+
+func (s *InventoryFlashSalesSuite) TestFlashSale_Validation() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	path := "/inventory/categories/" + fx.product.CategoryID + "/flash-sale"
+	status, _ := s.do(fx, "POST", path, map[string]interface{}{
+		"percentOff": "100",
+		"endsAt":     time.Now().Add(time.Hour).Format(time.RFC3339),
+	})
+	s.Equal(http.StatusBadRequest, status)
+
+	status, _ = s.do(fx, "POST", path, map[string]interface{}{"percentOff": "10"})
+	s.Equal(http.StatusBadRequest, status)
+
+	status, _ = s.do(fx, "POST", "/inventory/categories/cat_missing/flash-sale", map[string]interface{}{
+		"percentOff": "10",
+		"endsAt":     time.Now().Add(time.Hour).Format(time.RFC3339),
+	})
+	s.Equal(http.StatusNotFound, status)
+}
+
+func TestInventoryFlashSalesSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(InventoryFlashSalesSuite))
+}
@@ -0,0 +1,271 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var locationTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants", "locations", "location_stocks",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes",
+}
+
+// InventoryLocationsSuite tests stock locations, per-location stock levels and how orders
+// draw from them.
+type InventoryLocationsSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *InventoryLocationsSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *InventoryLocationsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, locationTables...))
+}
+
+func (s *InventoryLocationsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, locationTables...))
+}
+
+type locationFixture struct {
+	owner     *testutils.Owner
+	biz       *business.Business
+	cust      *customer.Customer
+	addr      *customer.CustomerAddress
+	variant   *inventory.Variant
+	warehouse string
+	store     string
+}
+
+// setup creates a business with a variant holding 10 units, then a warehouse (the first
+// location, which takes over those units) and an empty store.
+func (s *InventoryLocationsSuite) setup(ctx context.Context) *locationFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	addr, err := s.factory.Address(ctx, cust.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	fx := &locationFixture{owner: owner, biz: biz, cust: cust, addr: addr, variant: v}
+
+	status, body := s.do(fx, "POST", "/inventory/locations", map[string]interface{}{"name": "Warehouse"})
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.True(body["isDefault"].(bool))
+	fx.warehouse = body["id"].(string)
+	status, body = s.do(fx, "POST", "/inventory/locations", map[string]interface{}{"name": "Store", "address": "1 Main St"})
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.False(body["isDefault"].(bool))
+	fx.store = body["id"].(string)
+	return fx
+}
+
+func (s *InventoryLocationsSuite) do(fx *locationFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *InventoryLocationsSuite) problemCode(body map[string]interface{}) interface{} {
+	return body["extensions"].(map[string]interface{})["code"]
+}
+
+// levels returns the variant's stock per location ID.
+func (s *InventoryLocationsSuite) levels(fx *locationFixture) map[string]int {
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+fx.biz.Descriptor+"/inventory/variants/"+fx.variant.ID+"/locations", nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var items []map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &items))
+	levels := map[string]int{}
+	for _, it := range items {
+		levels[it["locationId"].(string)] = int(it["quantity"].(float64))
+	}
+	return levels
+}
+
+func (s *InventoryLocationsSuite) stock(ctx context.Context, variantID string) int {
+	v, err := database.NewRepository[inventory.Variant](testEnv.Database).FindByID(ctx, variantID)
+	s.Require().NoError(err)
+	return v.StockQuantity
+}
+
+func (s *InventoryLocationsSuite) createOrder(fx *locationFixture, locationID string, qty int) (int, map[string]interface{}) {
+	payload := map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"channel":           "instagram",
+		"items":             []map[string]interface{}{{"variantId": fx.variant.ID, "quantity": qty}},
+	}
+	if locationID != "" {
+		payload["locationId"] = locationID
+	}
+	return s.do(fx, "POST", "/orders", payload)
+}
+
+func (s *InventoryLocationsSuite) TestFirstLocationTakesOverStock() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	s.Equal(map[string]int{fx.warehouse: 10, fx.store: 0}, s.levels(fx))
+	s.Equal(10, s.stock(ctx, fx.variant.ID))
+
+	status, body := s.do(fx, "POST", "/inventory/locations", map[string]interface{}{"name": "Warehouse"})
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("inventory.location_name_taken", s.problemCode(body))
+
+	// Stock set on the variant itself is counted at the default location.
+	status, body = s.do(fx, "PATCH", "/inventory/variants/"+fx.variant.ID, map[string]interface{}{"stockQuantity": 15})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(map[string]int{fx.warehouse: 15, fx.store: 0}, s.levels(fx))
+}
+
+func (s *InventoryLocationsSuite) TestSetAndTransferStock() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	status, body := s.do(fx, "PUT", "/inventory/variants/"+fx.variant.ID+"/locations/"+fx.store, map[string]interface{}{"quantity": 4})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(map[string]int{fx.warehouse: 10, fx.store: 4}, s.levels(fx))
+	s.Equal(14, s.stock(ctx, fx.variant.ID))
+
+	status, body = s.do(fx, "POST", "/inventory/locations/transfers", map[string]interface{}{
+		"fromLocationId": fx.warehouse,
+		"toLocationId":   fx.store,
+		"items":          []map[string]interface{}{{"variantId": fx.variant.ID, "quantity": 6}},
+	})
+	s.Require().Equal(http.StatusNoContent, status, body)
+	s.Equal(map[string]int{fx.warehouse: 4, fx.store: 10}, s.levels(fx))
+	s.Equal(14, s.stock(ctx, fx.variant.ID))
+
+	status, body = s.do(fx, "POST", "/inventory/locations/transfers", map[string]interface{}{
+		"fromLocationId": fx.warehouse,
+		"toLocationId":   fx.store,
+		"items":          []map[string]interface{}{{"variantId": fx.variant.ID, "quantity": 5}},
+	})
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("inventory.insufficient_location_stock", s.problemCode(body))
+	s.Equal(map[string]int{fx.warehouse: 4, fx.store: 10}, s.levels(fx))
+}
+
+func (s *InventoryLocationsSuite) TestOrderDecrementsChosenLocation() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	status, body := s.do(fx, "PUT", "/inventory/variants/"+fx.variant.ID+"/locations/"+fx.store, map[string]interface{}{"quantity": 3})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	status, body = s.createOrder(fx, fx.store, 2)
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Equal(fx.store, body["locationId"])
+	storeOrder := body["id"].(string)
+	s.Equal(map[string]int{fx.warehouse: 10, fx.store: 1}, s.levels(fx))
+	s.Equal(11, s.stock(ctx, fx.variant.ID))
+
+	// The business holds enough overall, but not at the store.
+	status, body = s.createOrder(fx, fx.store, 2)
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("inventory.insufficient_location_stock", s.problemCode(body))
+	s.Equal(11, s.stock(ctx, fx.variant.ID))
+
+	// Orders without a location draw from the default one.
+	status, body = s.createOrder(fx, "", 4)
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Equal(map[string]int{fx.warehouse: 6, fx.store: 1}, s.levels(fx))
+
+	status, body = s.createOrder(fx, "loc_missing", 1)
+	s.Equal(http.StatusNotFound, status, body)
+	s.Equal("inventory.location_not_found", s.problemCode(body))
+
+	// Deleting an order returns its items to the location they came from.
+	status, body = s.do(fx, "DELETE", "/orders/"+storeOrder, nil)
+	s.Require().Equal(http.StatusNoContent, status, body)
+	s.Equal(map[string]int{fx.warehouse: 6, fx.store: 3}, s.levels(fx))
+	s.Equal(9, s.stock(ctx, fx.variant.ID))
+}
+
+func (s *InventoryLocationsSuite) TestSummaryBreaksDownStockByLocation() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	status, body := s.do(fx, "PUT", "/inventory/variants/"+fx.variant.ID+"/locations/"+fx.store, map[string]interface{}{"quantity": 2})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	status, body = s.do(fx, "GET", "/inventory/summary", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.EqualValues(12, body["totalStockUnits"])
+	locations := body["locations"].([]interface{})
+	s.Require().Len(locations, 2)
+	warehouse := locations[0].(map[string]interface{})
+	s.Equal(fx.warehouse, warehouse["locationId"])
+	s.True(warehouse["isDefault"].(bool))
+	s.EqualValues(10, warehouse["totalStockUnits"])
+	s.Equal("500", warehouse["inventoryValue"])
+	store := locations[1].(map[string]interface{})
+	s.Equal(fx.store, store["locationId"])
+	s.EqualValues(2, store["totalStockUnits"])
+	s.Equal("100", store["inventoryValue"])
+}
+
+func (s *InventoryLocationsSuite) TestDeleteLocation() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	status, body := s.do(fx, "DELETE", "/inventory/locations/"+fx.warehouse, nil)
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("inventory.location_has_stock", s.problemCode(body))
+
+	status, body = s.do(fx, "PATCH", "/inventory/locations/"+fx.warehouse, map[string]interface{}{"isDefault": false})
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("inventory.default_location_required", s.problemCode(body))
+
+	status, body = s.do(fx, "PATCH", "/inventory/locations/"+fx.store, map[string]interface{}{"isDefault": true})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.True(body["isDefault"].(bool))
+	status, body = s.do(fx, "GET", "/inventory/locations/"+fx.warehouse, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.False(body["isDefault"].(bool))
+
+	status, body = s.do(fx, "DELETE", "/inventory/locations/"+fx.store, nil)
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("inventory.default_location_required", s.problemCode(body))
+
+	status, body = s.do(fx, "POST", "/inventory/locations/transfers", map[string]interface{}{
+		"fromLocationId": fx.warehouse,
+		"toLocationId":   fx.store,
+		"items":          []map[string]interface{}{{"variantId": fx.variant.ID, "quantity": 10}},
+	})
+	s.Require().Equal(http.StatusNoContent, status, body)
+	status, body = s.do(fx, "DELETE", "/inventory/locations/"+fx.warehouse, nil)
+	s.Require().Equal(http.StatusNoContent, status, body)
+	s.Equal(map[string]int{fx.store: 10}, s.levels(fx))
+}
+
+func TestInventoryLocationsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(InventoryLocationsSuite))
+}
@@ -0,0 +1,306 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var priceListTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants", "price_lists", "price_list_entries",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes",
+	"quotes", "quote_items",
+}
+
+// InventoryPriceListsSuite tests price list management, customer assignment and how orders
+// and quotes are priced from the resolved list.
+type InventoryPriceListsSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *InventoryPriceListsSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *InventoryPriceListsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, priceListTables...))
+}
+
+func (s *InventoryPriceListsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, priceListTables...))
+}
+
+type priceListFixture struct {
+	owner   *testutils.Owner
+	biz     *business.Business
+	cust    *customer.Customer
+	addr    *customer.CustomerAddress
+	variant *inventory.Variant
+	other   *inventory.Variant
+}
+
+// setup creates a business with a customer and two variants selling for 100.
+func (s *InventoryPriceListsSuite) setup(ctx context.Context) *priceListFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	addr, err := s.factory.Address(ctx, cust.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	other, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	return &priceListFixture{owner: owner, biz: biz, cust: cust, addr: addr, variant: v, other: other}
+}
+
+func (s *InventoryPriceListsSuite) do(fx *priceListFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *InventoryPriceListsSuite) createPriceList(fx *priceListFixture, payload map[string]interface{}) string {
+	status, body := s.do(fx, "POST", "/inventory/price-lists", payload)
+	s.Require().Equal(http.StatusCreated, status, body)
+	return body["id"].(string)
+}
+
+// orderUnitPrice creates an order for the fixture customer without a unitPrice and returns
+// the unit price it was given.
+func (s *InventoryPriceListsSuite) orderUnitPrice(fx *priceListFixture, variantID string) string {
+	return s.orderUnitPriceFor(fx, variantID, 1)
+}
+
+// orderUnitPriceFor is orderUnitPrice for a line of qty units.
+func (s *InventoryPriceListsSuite) orderUnitPriceFor(fx *priceListFixture, variantID string, qty int) string {
+	status, body := s.do(fx, "POST", "/orders", map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"channel":           "instagram",
+		"items":             []map[string]interface{}{{"variantId": variantID, "quantity": qty}},
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	return body["items"].([]interface{})[0].(map[string]interface{})["unitPrice"].(string)
+}
+
+func (s *InventoryPriceListsSuite) TestPriceListCRUD() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	id := s.createPriceList(fx, map[string]interface{}{
+		"name":              "Wholesale",
+		"percentAdjustment": "-20",
+		"entries":           []map[string]interface{}{{"variantId": fx.variant.ID, "price": "70"}},
+	})
+
+	status, body := s.do(fx, "GET", "/inventory/price-lists/"+id, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("Wholesale", body["name"])
+	s.Equal("-20", body["percentAdjustment"])
+	s.Len(body["entries"], 1)
+
+	status, body = s.do(fx, "PATCH", "/inventory/price-lists/"+id, map[string]interface{}{
+		"name":    "Trade",
+		"entries": []map[string]interface{}{},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("Trade", body["name"])
+	s.Empty(body["entries"])
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+fx.biz.Descriptor+"/inventory/price-lists", nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var lists []map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &lists))
+	s.Len(lists, 1)
+
+	status, _ = s.do(fx, "DELETE", "/inventory/price-lists/"+id, nil)
+	s.Equal(http.StatusNoContent, status)
+	status, _ = s.do(fx, "GET", "/inventory/price-lists/"+id, nil)
+	s.Equal(http.StatusNotFound, status)
+}
+
+func (s *InventoryPriceListsSuite) TestCreatePriceList_Validation() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	s.createPriceList(fx, map[string]interface{}{"name": "VIP"})
+	status, _ := s.do(fx, "POST", "/inventory/price-lists", map[string]interface{}{"name": "VIP"})
+	s.Equal(http.StatusConflict, status)
+
+	status, _ = s.do(fx, "POST", "/inventory/price-lists", map[string]interface{}{
+		"name": "Retail",
+		"entries": []map[string]interface{}{
+			{"variantId": fx.variant.ID, "price": "90"},
+			{"variantId": fx.variant.ID, "price": "80"},
+		},
+	})
+	s.Equal(http.StatusBadRequest, status)
+
+	status, _ = s.do(fx, "POST", "/inventory/price-lists", map[string]interface{}{
+		"name":    "Retail",
+		"entries": []map[string]interface{}{{"variantId": "var_missing", "price": "90"}},
+	})
+	s.Equal(http.StatusNotFound, status)
+}
+
+func (s *InventoryPriceListsSuite) TestDefaultPriceList_IsUnique() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	first := s.createPriceList(fx, map[string]interface{}{"name": "Retail", "isDefault": true})
+	s.createPriceList(fx, map[string]interface{}{"name": "Promo", "isDefault": true})
+
+	status, body := s.do(fx, "GET", "/inventory/price-lists/"+first, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(false, body["isDefault"])
+}
+
+func (s *InventoryPriceListsSuite) TestOrderPricing_FromAssignedList() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	s.Equal("100", s.orderUnitPrice(fx, fx.variant.ID), "no price list sells at SalePrice")
+
+	id := s.createPriceList(fx, map[string]interface{}{
+		"name":              "Wholesale",
+		"percentAdjustment": "-15",
+		"entries":           []map[string]interface{}{{"variantId": fx.variant.ID, "price": "70"}},
+	})
+	status, body := s.do(fx, "PATCH", "/customers/"+fx.cust.ID, map[string]interface{}{"priceListId": id})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(id, body["priceListId"])
+
+	s.Equal("70", s.orderUnitPrice(fx, fx.variant.ID))
+	s.Equal("85", s.orderUnitPrice(fx, fx.other.ID))
+
+	// An explicit unit price still wins.
+	status, body = s.do(fx, "POST", "/orders", map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"channel":           "instagram",
+		"items":             []map[string]interface{}{{"variantId": fx.variant.ID, "quantity": 1, "unitPrice": "95"}},
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Equal("95", body["items"].([]interface{})[0].(map[string]interface{})["unitPrice"])
+}
+
+func (s *InventoryPriceListsSuite) TestOrderPricing_QuantityBreaks() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	id := s.createPriceList(fx, map[string]interface{}{
+		"name": "Wholesale",
+		"entries": []map[string]interface{}{{
+			"variantId": fx.variant.ID,
+			"price":     "90",
+			"quantityBreaks": []map[string]interface{}{
+				{"minQuantity": 5, "price": "75"},
+				{"minQuantity": 3, "price": "80"},
+			},
+		}},
+	})
+	status, body := s.do(fx, "GET", "/inventory/price-lists/"+id, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	entry := body["entries"].([]interface{})[0].(map[string]interface{})
+	s.Equal([]interface{}{
+		map[string]interface{}{"minQuantity": float64(3), "price": "80"},
+		map[string]interface{}{"minQuantity": float64(5), "price": "75"},
+	}, entry["quantityBreaks"], "breaks are stored by quantity")
+
+	status, body = s.do(fx, "PATCH", "/customers/"+fx.cust.ID, map[string]interface{}{"priceListId": id})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	s.Equal("90", s.orderUnitPriceFor(fx, fx.variant.ID, 2))
+	s.Equal("80", s.orderUnitPriceFor(fx, fx.variant.ID, 3))
+	s.Equal("75", s.orderUnitPriceFor(fx, fx.variant.ID, 5))
+}
+
+func (s *InventoryPriceListsSuite) TestCreatePriceList_RejectsInvalidQuantityBreaks() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	for _, breaks := range [][]map[string]interface{}{
+		{{"minQuantity": 5, "price": "95"}},                                    // not below the entry price
+		{{"minQuantity": 3, "price": "80"}, {"minQuantity": 5, "price": "85"}}, // rises with quantity
+		{{"minQuantity": 3, "price": "80"}, {"minQuantity": 3, "price": "70"}}, // repeated quantity
+	} {
+		status, body := s.do(fx, "POST", "/inventory/price-lists", map[string]interface{}{
+			"name":    "Wholesale",
+			"entries": []map[string]interface{}{{"variantId": fx.variant.ID, "price": "90", "quantityBreaks": breaks}},
+		})
+		s.Require().Equal(http.StatusBadRequest, status, body)
+		s.Equal("inventory.invalid_price_breaks", body["extensions"].(map[string]interface{})["code"])
+	}
+}
+
+func (s *InventoryPriceListsSuite) TestOrderPricing_FallsBackToDefaultList() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	s.createPriceList(fx, map[string]interface{}{"name": "Retail", "isDefault": true, "percentAdjustment": "10"})
+	s.Equal("110", s.orderUnitPrice(fx, fx.variant.ID))
+
+	status, body := s.do(fx, "POST", "/quotes", map[string]interface{}{
+		"customerId": fx.cust.ID,
+		"items":      []map[string]interface{}{{"variantId": fx.variant.ID, "quantity": 2}},
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Equal("110", body["items"].([]interface{})[0].(map[string]interface{})["unitPrice"])
+}
+
+func (s *InventoryPriceListsSuite) TestAssignPriceList_BySegment() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	saudi, err := s.factory.Customer(ctx, fx.biz.ID, func(c *customer.Customer) { c.CountryCode = "SA" })
+	s.Require().NoError(err)
+
+	id := s.createPriceList(fx, map[string]interface{}{"name": "Gulf"})
+	status, body := s.do(fx, "PUT", "/customers/price-list", map[string]interface{}{"priceListId": id, "countryCode": "SA"})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(float64(1), body["updated"])
+
+	status, body = s.do(fx, "GET", "/customers?priceListId="+id, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Require().Len(body["items"], 1)
+	s.Equal(saudi.ID, body["items"].([]interface{})[0].(map[string]interface{})["id"])
+
+	// Clearing by explicit ids.
+	status, body = s.do(fx, "PUT", "/customers/price-list", map[string]interface{}{"priceListId": nil, "customerIds": []string{saudi.ID}})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(float64(1), body["updated"])
+
+	// Unknown list and missing target are rejected.
+	status, _ = s.do(fx, "PUT", "/customers/price-list", map[string]interface{}{"priceListId": "prl_missing", "countryCode": "SA"})
+	s.Equal(http.StatusNotFound, status)
+	status, _ = s.do(fx, "PUT", "/customers/price-list", map[string]interface{}{"priceListId": id})
+	s.Equal(http.StatusBadRequest, status)
+}
+
+func TestInventoryPriceListsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(InventoryPriceListsSuite))
+}
@@ -0,0 +1,211 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/asset"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/id"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+// InventoryProductPhotosSuite tests batch photo attach, reordering, cover selection, thumbnail
+// regeneration and the cleanup of photo assets products no longer show.
+type InventoryProductPhotosSuite struct {
+	suite.Suite
+	helper  *InventoryTestHelper
+	factory *testutils.Factory
+}
+
+func (s *InventoryProductPhotosSuite) SetupSuite() {
+	s.helper = NewInventoryTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *InventoryProductPhotosSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, inventoryTables...))
+}
+
+func (s *InventoryProductPhotosSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, inventoryTables...))
+}
+
+type productPhotosFixture struct {
+	owner   *testutils.Owner
+	biz     *business.Business
+	product *inventory.Product
+}
+
+func (s *InventoryProductPhotosSuite) setup(ctx context.Context) *productPhotosFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	return &productPhotosFixture{owner: owner, biz: biz, product: prod}
+}
+
+// createImage stores an uploaded image asset for the business.
+func (s *InventoryProductPhotosSuite) createImage(ctx context.Context, fx *productPhotosFixture) *asset.Asset {
+	repo := database.NewRepository[asset.Asset](testEnv.Database)
+	assetID := id.KsuidWithPrefix(asset.AssetPrefix)
+	a := &asset.Asset{
+		ID:              assetID,
+		WorkspaceID:     fx.owner.Workspace.ID,
+		BusinessID:      fx.biz.ID,
+		CreatedByUserID: fx.owner.User.ID,
+		ObjectKey:       fx.biz.ID + "/" + assetID + ".jpg",
+		PublicURL:       e2eBaseURL + "/v1/public/assets/" + assetID,
+		ContentType:     "image/jpeg",
+		FileCategory:    string(asset.FileCategoryImage),
+		SizeBytes:       1024,
+	}
+	s.Require().NoError(repo.CreateOne(ctx, a))
+	return a
+}
+
+func (s *InventoryProductPhotosSuite) do(fx *productPhotosFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+"/inventory/products/"+fx.product.ID+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func photoAssetIDs(body map[string]interface{}) []string {
+	photos := body["photos"].([]interface{})
+	ids := make([]string, 0, len(photos))
+	for _, p := range photos {
+		ids = append(ids, p.(map[string]interface{})["assetId"].(string))
+	}
+	return ids
+}
+
+func (s *InventoryProductPhotosSuite) TestAddReorderAndCover() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	a := s.createImage(ctx, fx)
+	b := s.createImage(ctx, fx)
+	c := s.createImage(ctx, fx)
+
+	status, body := s.do(fx, "POST", "/photos", map[string]interface{}{"assetIds": []string{a.ID, b.ID}})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal([]string{a.ID, b.ID}, photoAssetIDs(body))
+
+	status, body = s.do(fx, "POST", "/photos", map[string]interface{}{"assetIds": []string{c.ID, a.ID}, "setCover": true})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal([]string{c.ID, a.ID, b.ID}, photoAssetIDs(body), "already attached assets are skipped")
+
+	status, body = s.do(fx, "PUT", "/photos/order", map[string]interface{}{"photoIds": []string{b.ID, a.ID, c.ID}})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal([]string{b.ID, a.ID, c.ID}, photoAssetIDs(body))
+
+	status, body = s.do(fx, "PUT", "/photos/cover", map[string]interface{}{"photoId": c.ID})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal([]string{c.ID, b.ID, a.ID}, photoAssetIDs(body))
+
+	stored, err := s.helper.GetProduct(ctx, fx.product.ID)
+	s.Require().NoError(err)
+	s.Len(stored.Photos, 3)
+	s.Equal(c.ID, *stored.Photos[0].AssetID)
+}
+
+func (s *InventoryProductPhotosSuite) TestValidation() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	a := s.createImage(ctx, fx)
+	b := s.createImage(ctx, fx)
+
+	status, _ := s.do(fx, "POST", "/photos", map[string]interface{}{"assetIds": []string{"ast_missing"}})
+	s.Equal(http.StatusNotFound, status)
+
+	status, body := s.do(fx, "POST", "/photos", map[string]interface{}{"assetIds": []string{a.ID, b.ID}})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	status, _ = s.do(fx, "PUT", "/photos/order", map[string]interface{}{"photoIds": []string{a.ID}})
+	s.Equal(http.StatusBadRequest, status, "every photo must be listed")
+
+	status, _ = s.do(fx, "PUT", "/photos/order", map[string]interface{}{"photoIds": []string{a.ID, a.ID}})
+	s.Equal(http.StatusBadRequest, status, "photos must be listed once")
+
+	status, _ = s.do(fx, "PUT", "/photos/cover", map[string]interface{}{"photoId": "ast_missing"})
+	s.Equal(http.StatusNotFound, status)
+}
+
+func (s *InventoryProductPhotosSuite) TestRegenerateThumbnails() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	a := s.createImage(ctx, fx)
+
+	status, body := s.do(fx, "POST", "/photos", map[string]interface{}{"assetIds": []string{a.ID}})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Nil(body["photos"].([]interface{})[0].(map[string]interface{})["thumbnailUrl"])
+
+	repo := database.NewRepository[asset.Asset](testEnv.Database)
+	a.ThumbnailPublicURL = a.PublicURL + "_thumb"
+	a.ThumbnailCDNURL = a.ThumbnailPublicURL
+	s.Require().NoError(repo.UpdateOne(ctx, a))
+
+	status, body = s.do(fx, "POST", "/photos/thumbnails", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(a.ThumbnailCDNURL, body["photos"].([]interface{})[0].(map[string]interface{})["thumbnailUrl"])
+}
+
+func (s *InventoryProductPhotosSuite) assetExists(ctx context.Context, assetID string) bool {
+	_, err := database.NewRepository[asset.Asset](testEnv.Database).FindByID(ctx, assetID)
+	if database.IsRecordNotFound(err) {
+		return false
+	}
+	s.Require().NoError(err)
+	return true
+}
+
+func (s *InventoryProductPhotosSuite) TestReleasesOrphanedPhotoAssets() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	a := s.createImage(ctx, fx)
+	b := s.createImage(ctx, fx)
+	status, body := s.do(fx, "POST", "/photos", map[string]interface{}{"assetIds": []string{a.ID, b.ID}})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	// b is shown by a second product too
+	other, err := s.factory.Product(ctx, fx.biz.ID)
+	s.Require().NoError(err)
+	otherFx := *fx
+	otherFx.product = other
+	status, body = s.do(&otherFx, "POST", "/photos", map[string]interface{}{"assetIds": []string{b.ID}})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	// dropping a photo deletes its asset
+	status, body = s.do(fx, "PATCH", "", map[string]interface{}{
+		"photos": []map[string]interface{}{{"url": b.PublicURL, "assetId": b.ID}},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.False(s.assetExists(ctx, a.ID))
+	s.True(s.assetExists(ctx, b.ID))
+
+	deleteProduct := func(productID string) {
+		resp, err := s.helper.Client.AuthenticatedRequest("DELETE", "/v1/businesses/"+fx.biz.Descriptor+"/inventory/products/"+productID, nil, fx.owner.Token)
+		s.Require().NoError(err)
+		resp.Body.Close()
+		s.Require().Equal(http.StatusNoContent, resp.StatusCode)
+	}
+	deleteProduct(fx.product.ID)
+	s.True(s.assetExists(ctx, b.ID), "assets other products show are kept")
+	deleteProduct(other.ID)
+	s.False(s.assetExists(ctx, b.ID))
+}
+
+func TestInventoryProductPhotosSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(InventoryProductPhotosSuite))
+}
@@ -122,6 +122,45 @@ func (s *InventoryProductsSearchFilterSuite) TestListProducts_SearchByVariantSKU
 	s.True(found, "Should find Phone Case product through SKU search")
 }
 
+func (s *InventoryProductsSearchFilterSuite) TestListProducts_SearchToleratesTyposAndAccents() {
+	ctx := context.Background()
+	_, ws, token, err := s.accountHelper.CreateTestUser(ctx, "admin@example.com", "Password123!", "Admin", "User", role.RoleAdmin)
+	s.NoError(err)
+	s.NoError(s.accountHelper.CreateTestSubscription(ctx, ws.ID))
+	biz, err := s.inventoryHelper.CreateTestBusiness(ctx, ws.ID, "test-biz")
+	s.NoError(err)
+	cat, err := s.inventoryHelper.CreateTestCategory(ctx, biz.ID, "General", "general")
+	s.NoError(err)
+
+	for _, name := range []string{"Wireless Headphones", "Café Crème", "عطر الوَرد"} {
+		prod, err := s.inventoryHelper.CreateTestProduct(ctx, biz.ID, cat.ID, name, "")
+		s.NoError(err)
+		_, err = s.inventoryHelper.CreateTestVariant(ctx, biz.ID, prod.ID, "Default", "SKU-"+prod.ID, "USD", decimal.NewFromInt(5), decimal.NewFromInt(10), 10, 2)
+		s.NoError(err)
+	}
+
+	tests := []struct {
+		search string
+		want   string
+	}{
+		{search: "Hedphones", want: "Wireless Headphones"},
+		{search: "cafe creme", want: "Café Crème"},
+		{search: "عطر الورد", want: "عطر الوَرد"},
+	}
+	for _, tt := range tests {
+		resp, err := s.inventoryHelper.Client.AuthenticatedRequest("GET", "/v1/businesses/test-biz/inventory/products?search="+url.QueryEscape(tt.search), nil, token)
+		s.NoError(err)
+		s.Equal(http.StatusOK, resp.StatusCode)
+
+		var result map[string]interface{}
+		s.NoError(testutils.DecodeJSON(resp, &result))
+		resp.Body.Close()
+		items := result["items"].([]interface{})
+		s.Require().NotEmpty(items, "search %q should match", tt.search)
+		s.Equal(tt.want, items[0].(map[string]interface{})["name"], "search %q should rank the closest product first", tt.search)
+	}
+}
+
 func (s *InventoryProductsSearchFilterSuite) TestListProducts_FilterByCategoryID() {
 	ctx := context.Background()
 	_, ws, token, err := s.accountHelper.CreateTestUser(ctx, "admin@example.com", "Password123!", "Admin", "User", role.RoleAdmin)
@@ -256,6 +295,44 @@ func (s *InventoryProductsSearchFilterSuite) TestListProducts_CombinedSearchAndF
 	s.Equal("Wireless Keyboard", items[0].(map[string]interface{})["name"])
 }
 
+func (s *InventoryProductsSearchFilterSuite) TestListProducts_SummaryView() {
+	ctx := context.Background()
+	_, ws, token, err := s.accountHelper.CreateTestUser(ctx, "admin@example.com", "Password123!", "Admin", "User", role.RoleAdmin)
+	s.NoError(err)
+	s.NoError(s.accountHelper.CreateTestSubscription(ctx, ws.ID))
+	biz, err := s.inventoryHelper.CreateTestBusiness(ctx, ws.ID, "test-biz")
+	s.NoError(err)
+	cat, err := s.inventoryHelper.CreateTestCategory(ctx, biz.ID, "Apparel", "apparel")
+	s.NoError(err)
+
+	prod, err := s.inventoryHelper.CreateTestProduct(ctx, biz.ID, cat.ID, "T-Shirt", "Cotton tee")
+	s.NoError(err)
+	_, err = s.inventoryHelper.CreateTestVariant(ctx, biz.ID, prod.ID, "Small", "TS-S-001", "USD", decimal.NewFromInt(5), decimal.NewFromInt(20), 10, 2)
+	s.NoError(err)
+	_, err = s.inventoryHelper.CreateTestVariant(ctx, biz.ID, prod.ID, "Large", "TS-L-001", "USD", decimal.NewFromInt(6), decimal.NewFromInt(25), 2, 5)
+	s.NoError(err)
+
+	resp, err := s.inventoryHelper.Client.AuthenticatedRequest("GET", "/v1/businesses/test-biz/inventory/products?view=summary&orderBy=-stock", nil, token)
+	s.NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var result map[string]interface{}
+	s.NoError(testutils.DecodeJSON(resp, &result))
+	items := result["items"].([]interface{})
+	s.Len(items, 1)
+	item := items[0].(map[string]interface{})
+	s.Equal(prod.ID, item["id"])
+	s.Equal("T-Shirt", item["name"])
+	s.Equal(float64(2), item["variantsCount"])
+	s.Equal(float64(12), item["totalStock"])
+	s.Equal("low_stock", item["stockStatus"])
+	s.Equal("20", item["minSalePrice"])
+	s.Equal("25", item["maxSalePrice"])
+	s.NotContains(item, "variants")
+	s.NotContains(item, "description")
+}
+
 func TestInventoryProductsSearchFilterSuite(t *testing.T) {
 	suite.Run(t, new(InventoryProductsSearchFilterSuite))
 }
@@ -229,6 +229,46 @@ func (s *InventoryProductsSuite) TestUpdateProduct_RenamesVariants() {
 	s.Equal("New - RED", vRes["name"])
 }
 
+func (s *InventoryProductsSuite) TestUpdateProduct_StaleVersionConflict() {
+	ctx := context.Background()
+	_, ws, token, err := s.accountHelper.CreateTestUser(ctx, "admin@example.com", "Password123!", "Admin", "User", role.RoleAdmin)
+	s.NoError(err)
+	s.NoError(s.accountHelper.CreateTestSubscription(ctx, ws.ID))
+	biz, err := s.inventoryHelper.CreateTestBusiness(ctx, ws.ID, "test-biz")
+	s.NoError(err)
+	cat, err := s.inventoryHelper.CreateTestCategory(ctx, biz.ID, "Cat", "cat")
+	s.NoError(err)
+	prod, err := s.inventoryHelper.CreateTestProduct(ctx, biz.ID, cat.ID, "Original", "")
+	s.NoError(err)
+	path := fmt.Sprintf("/v1/businesses/test-biz/inventory/products/%s", prod.ID)
+
+	// First writer read version 1 and wins.
+	resp, err := s.inventoryHelper.Client.AuthenticatedRequest("PATCH", path, map[string]interface{}{"name": "First", "version": 1}, token)
+	s.NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+	var result map[string]interface{}
+	s.NoError(testutils.DecodeJSON(resp, &result))
+	s.Equal(float64(2), result["version"])
+
+	// Second writer also read version 1 and must not overwrite the first.
+	stale, err := s.inventoryHelper.Client.AuthenticatedRequest("PATCH", path, map[string]interface{}{"name": "Second", "version": 1}, token)
+	s.NoError(err)
+	defer stale.Body.Close()
+	s.Equal(http.StatusConflict, stale.StatusCode)
+	code, err := testutils.GetErrorCode(stale)
+	s.NoError(err)
+	s.Equal("inventory.product_version_conflict", code)
+
+	getResp, err := s.inventoryHelper.Client.AuthenticatedRequest("GET", path, nil, token)
+	s.NoError(err)
+	defer getResp.Body.Close()
+	var current map[string]interface{}
+	s.NoError(testutils.DecodeJSON(getResp, &current))
+	s.Equal("First", current["name"])
+	s.Equal(float64(2), current["version"])
+}
+
 func (s *InventoryProductsSuite) TestDeleteProduct_CascadesVariants() {
 	ctx := context.Background()
 	_, ws, token, err := s.accountHelper.CreateTestUser(ctx, "admin@example.com", "Password123!", "Admin", "User", role.RoleAdmin)
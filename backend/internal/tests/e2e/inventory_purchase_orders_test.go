@@ -0,0 +1,383 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var purchaseOrderTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"suppliers", "purchase_orders", "purchase_order_items", "purchase_order_receipts",
+	"supplier_bills", "supplier_bill_payments",
+}
+
+// InventoryPurchaseOrdersSuite tests suppliers, receiving purchase orders into stock, supplier
+// bills and the supplier payables they leave behind.
+type InventoryPurchaseOrdersSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *InventoryPurchaseOrdersSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *InventoryPurchaseOrdersSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, purchaseOrderTables...))
+}
+
+func (s *InventoryPurchaseOrdersSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, purchaseOrderTables...))
+}
+
+type purchaseOrderFixture struct {
+	owner    *testutils.Owner
+	biz      *business.Business
+	variant  *inventory.Variant
+	other    *inventory.Variant
+	supplier string
+}
+
+// setup creates a business with a supplier and two variants with 10 units in stock.
+func (s *InventoryPurchaseOrdersSuite) setup(ctx context.Context) *purchaseOrderFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	other, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	fx := &purchaseOrderFixture{owner: owner, biz: biz, variant: v, other: other}
+	status, body := s.do(fx, "POST", "/inventory/suppliers", map[string]interface{}{"name": "Acme Wholesale", "email": "sales@acme.test"})
+	s.Require().Equal(http.StatusCreated, status, body)
+	fx.supplier = body["id"].(string)
+	return fx
+}
+
+func (s *InventoryPurchaseOrdersSuite) do(fx *purchaseOrderFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *InventoryPurchaseOrdersSuite) problemCode(body map[string]interface{}) interface{} {
+	return body["extensions"].(map[string]interface{})["code"]
+}
+
+func (s *InventoryPurchaseOrdersSuite) stock(ctx context.Context, variantID string) int {
+	v, err := database.NewRepository[inventory.Variant](testEnv.Database).FindByID(ctx, variantID)
+	s.Require().NoError(err)
+	return v.StockQuantity
+}
+
+// createPurchaseOrder orders 10 units of the variant at 20 and 5 of the other at 10.
+func (s *InventoryPurchaseOrdersSuite) createPurchaseOrder(fx *purchaseOrderFixture) (string, map[string]string) {
+	status, body := s.do(fx, "POST", "/inventory/purchase-orders", map[string]interface{}{
+		"supplierId": fx.supplier,
+		"reference":  "INV-1001",
+		"expectedAt": "2030-01-15T00:00:00Z",
+		"items": []map[string]interface{}{
+			{"variantId": fx.variant.ID, "quantity": 10, "unitCost": "20"},
+			{"variantId": fx.other.ID, "quantity": 5, "unitCost": "10"},
+		},
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Equal("ordered", body["status"])
+	s.Equal("250", body["total"])
+	items := map[string]string{}
+	for _, raw := range body["items"].([]interface{}) {
+		item := raw.(map[string]interface{})
+		items[item["variantId"].(string)] = item["id"].(string)
+	}
+	return body["id"].(string), items
+}
+
+func (s *InventoryPurchaseOrdersSuite) TestCreatePurchaseOrder_Validation() {
+	fx := s.setup(context.Background())
+
+	status, body := s.do(fx, "POST", "/inventory/purchase-orders", map[string]interface{}{
+		"supplierId": "sup_missing",
+		"items":      []map[string]interface{}{{"variantId": fx.variant.ID, "quantity": 1, "unitCost": "5"}},
+	})
+	s.Equal(http.StatusNotFound, status, body)
+	s.Equal("inventory.supplier_not_found", s.problemCode(body))
+
+	status, body = s.do(fx, "POST", "/inventory/purchase-orders", map[string]interface{}{
+		"supplierId": fx.supplier,
+		"items": []map[string]interface{}{
+			{"variantId": fx.variant.ID, "quantity": 1, "unitCost": "5"},
+			{"variantId": fx.variant.ID, "quantity": 2, "unitCost": "5"},
+		},
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("inventory.duplicate_purchase_order_item", s.problemCode(body))
+}
+
+func (s *InventoryPurchaseOrdersSuite) TestReceivePurchaseOrder_PartialThenFull() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	poID, items := s.createPurchaseOrder(fx)
+	s.Equal(10, s.stock(ctx, fx.variant.ID))
+
+	status, body := s.do(fx, "POST", "/inventory/purchase-orders/"+poID+"/receive", map[string]interface{}{
+		"items": []map[string]interface{}{{"itemId": items[fx.variant.ID], "quantity": 4}},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("partially_received", body["status"])
+	s.Equal("80", body["receivedTotal"])
+	s.Equal(14, s.stock(ctx, fx.variant.ID))
+
+	status, body = s.do(fx, "POST", "/inventory/purchase-orders/"+poID+"/receive", map[string]interface{}{
+		"items": []map[string]interface{}{{"itemId": items[fx.variant.ID], "quantity": 7}},
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("inventory.purchase_order_over_receipt", s.problemCode(body))
+	s.Equal(14, s.stock(ctx, fx.variant.ID))
+
+	status, body = s.do(fx, "POST", "/inventory/purchase-orders/"+poID+"/receive", map[string]interface{}{
+		"items": []map[string]interface{}{
+			{"itemId": items[fx.variant.ID], "quantity": 6},
+			{"itemId": items[fx.other.ID], "quantity": 5},
+		},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("received", body["status"])
+	s.Equal("250", body["receivedTotal"])
+	s.NotNil(body["receivedAt"])
+	s.Equal(20, s.stock(ctx, fx.variant.ID))
+	s.Equal(15, s.stock(ctx, fx.other.ID))
+
+	status, body = s.do(fx, "POST", "/inventory/purchase-orders/"+poID+"/receive", map[string]interface{}{
+		"items": []map[string]interface{}{{"itemId": items[fx.other.ID], "quantity": 1}},
+	})
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("inventory.purchase_order_not_receivable", s.problemCode(body))
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+fx.biz.Descriptor+"/inventory/purchase-orders/"+poID+"/receipts", nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var receipts []map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &receipts))
+	s.Len(receipts, 3)
+}
+
+func (s *InventoryPurchaseOrdersSuite) TestSupplierPayables_FeedFinancialPosition() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	poID, items := s.createPurchaseOrder(fx)
+
+	// Ordered goods are not owed until they arrive.
+	status, body := s.do(fx, "GET", "/inventory/suppliers/"+fx.supplier, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("0", body["amountOutstanding"])
+
+	status, body = s.do(fx, "POST", "/inventory/purchase-orders/"+poID+"/receive", map[string]interface{}{
+		"items": []map[string]interface{}{{"itemId": items[fx.variant.ID], "quantity": 10}},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	status, body = s.do(fx, "POST", "/inventory/purchase-orders/"+poID+"/payments", map[string]interface{}{"amount": "50"})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("50", body["amountPaid"])
+	s.Equal("150", body["amountOutstanding"])
+
+	status, body = s.do(fx, "POST", "/inventory/purchase-orders/"+poID+"/payments", map[string]interface{}{"amount": "500"})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("inventory.purchase_order_overpayment", s.problemCode(body))
+
+	status, body = s.do(fx, "GET", "/inventory/suppliers/"+fx.supplier, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("150", body["amountOutstanding"])
+
+	status, body = s.do(fx, "GET", "/analytics/reports/financial-position", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("150", body["supplierPayables"])
+	s.Equal("150", body["totalLiabilities"])
+
+	// Suppliers still owed money cannot be deleted.
+	status, body = s.do(fx, "DELETE", "/inventory/suppliers/"+fx.supplier, nil)
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("inventory.supplier_has_open_purchase_orders", s.problemCode(body))
+}
+
+func (s *InventoryPurchaseOrdersSuite) TestSupplierBills_PaymentsAndAging() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	now := time.Now().UTC()
+
+	status, body := s.do(fx, "POST", "/inventory/supplier-bills", map[string]interface{}{
+		"supplierId": fx.supplier,
+		"amount":     "100",
+		"issuedAt":   now.AddDate(0, 0, -5).Format(time.RFC3339),
+		"dueAt":      now.AddDate(0, 0, -10).Format(time.RFC3339),
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("inventory.supplier_bill_invalid_due_date", s.problemCode(body))
+
+	status, body = s.do(fx, "POST", "/inventory/supplier-bills", map[string]interface{}{
+		"supplierId": fx.supplier,
+		"reference":  "BILL-1",
+		"amount":     "300",
+		"issuedAt":   now.AddDate(0, 0, -75).Format(time.RFC3339),
+		"dueAt":      now.AddDate(0, 0, -45).Format(time.RFC3339),
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Equal("unpaid", body["paymentStatus"])
+	overdue := body["id"].(string)
+
+	status, body = s.do(fx, "POST", "/inventory/supplier-bills", map[string]interface{}{
+		"supplierId": fx.supplier,
+		"amount":     "120",
+		"dueAt":      now.AddDate(0, 0, 15).Format(time.RFC3339),
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+
+	status, body = s.do(fx, "GET", "/inventory/supplier-bills/aging", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("120", body["current"])
+	s.Equal("300", body["days31To60"])
+	s.Equal("420", body["total"])
+	s.Len(body["suppliers"], 1)
+
+	status, body = s.do(fx, "POST", "/inventory/supplier-bills/"+overdue+"/payments", map[string]interface{}{"amount": "100", "note": "wire"})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("partially_paid", body["paymentStatus"])
+	s.Equal("200", body["amountOutstanding"])
+
+	status, body = s.do(fx, "POST", "/inventory/supplier-bills/"+overdue+"/payments", map[string]interface{}{"amount": "250"})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("inventory.supplier_bill_overpayment", s.problemCode(body))
+
+	status, body = s.do(fx, "GET", "/inventory/supplier-bills?overdue=true", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Len(body["items"], 1)
+
+	status, body = s.do(fx, "GET", "/inventory/suppliers/"+fx.supplier, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("320", body["amountOutstanding"])
+
+	status, body = s.do(fx, "POST", "/inventory/supplier-bills/"+overdue+"/payments", map[string]interface{}{"amount": "200"})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("paid", body["paymentStatus"])
+	s.NotNil(body["paidAt"])
+
+	// Bills with payments are kept.
+	status, body = s.do(fx, "DELETE", "/inventory/supplier-bills/"+overdue, nil)
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("inventory.supplier_bill_has_payments", s.problemCode(body))
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+fx.biz.Descriptor+"/inventory/supplier-bills/"+overdue+"/payments", nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var payments []map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &payments))
+	s.Len(payments, 2)
+
+	status, body = s.do(fx, "GET", "/inventory/supplier-bills/aging", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("0", body["days31To60"])
+	s.Equal("120", body["total"])
+}
+
+func (s *InventoryPurchaseOrdersSuite) TestSupplierBills_PurchaseOrder() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	poID, items := s.createPurchaseOrder(fx)
+	status, body := s.do(fx, "POST", "/inventory/purchase-orders/"+poID+"/receive", map[string]interface{}{
+		"items": []map[string]interface{}{{"itemId": items[fx.variant.ID], "quantity": 10}},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	status, body = s.do(fx, "POST", "/inventory/purchase-orders/"+poID+"/payments", map[string]interface{}{"amount": "50"})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	dueAt := time.Now().UTC().AddDate(0, 0, 30).Format(time.RFC3339)
+	status, body = s.do(fx, "POST", "/inventory/supplier-bills", map[string]interface{}{
+		"supplierId": fx.supplier, "purchaseOrderId": poID, "amount": "300", "dueAt": dueAt,
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("inventory.supplier_bill_exceeds_purchase_order", s.problemCode(body))
+
+	// The bill starts with what was already paid on the order.
+	status, body = s.do(fx, "POST", "/inventory/supplier-bills", map[string]interface{}{
+		"supplierId": fx.supplier, "purchaseOrderId": poID, "amount": "250", "dueAt": dueAt,
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Equal("50", body["amountPaid"])
+	s.Equal("partially_paid", body["paymentStatus"])
+	billID := body["id"].(string)
+
+	status, body = s.do(fx, "POST", "/inventory/purchase-orders/"+poID+"/payments", map[string]interface{}{"amount": "10"})
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("inventory.purchase_order_billed", s.problemCode(body))
+
+	status, body = s.do(fx, "POST", "/inventory/supplier-bills/"+billID+"/payments", map[string]interface{}{"amount": "100"})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("100", body["amountOutstanding"])
+
+	status, body = s.do(fx, "GET", "/inventory/purchase-orders/"+poID, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("150", body["amountPaid"])
+
+	// Once billed, the order is owed through its bill.
+	status, body = s.do(fx, "GET", "/analytics/reports/financial-position", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("100", body["supplierPayables"])
+
+	status, body = s.do(fx, "DELETE", "/inventory/suppliers/"+fx.supplier, nil)
+	s.Equal(http.StatusConflict, status, body)
+}
+
+func (s *InventoryPurchaseOrdersSuite) TestCancelPurchaseOrder() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	poID, items := s.createPurchaseOrder(fx)
+
+	status, body := s.do(fx, "POST", "/inventory/purchase-orders/"+poID+"/cancel", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("cancelled", body["status"])
+
+	status, body = s.do(fx, "POST", "/inventory/purchase-orders/"+poID+"/receive", map[string]interface{}{
+		"items": []map[string]interface{}{{"itemId": items[fx.variant.ID], "quantity": 1}},
+	})
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("inventory.purchase_order_not_receivable", s.problemCode(body))
+
+	// Received orders cannot be cancelled.
+	other, otherItems := s.createPurchaseOrder(fx)
+	status, body = s.do(fx, "POST", "/inventory/purchase-orders/"+other+"/receive", map[string]interface{}{
+		"items": []map[string]interface{}{{"itemId": otherItems[fx.other.ID], "quantity": 1}},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	status, body = s.do(fx, "POST", "/inventory/purchase-orders/"+other+"/cancel", nil)
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("inventory.purchase_order_not_cancellable", s.problemCode(body))
+
+	status, body = s.do(fx, "GET", "/inventory/purchase-orders?status=cancelled", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Len(body["items"], 1)
+}
+
+func TestInventoryPurchaseOrdersSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(InventoryPurchaseOrdersSuite))
+}
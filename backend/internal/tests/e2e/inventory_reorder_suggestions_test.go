@@ -0,0 +1,207 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var reorderSuggestionTables = append([]string{"customers", "customer_addresses", "orders", "order_items", "suppliers"}, inventoryTables...)
+
+// InventoryReorderSuggestionsSuite tests the ranked purchase list built from sales velocity,
+// stock, alerts, reorder points and lead times.
+type InventoryReorderSuggestionsSuite struct {
+	suite.Suite
+	helper  *InventoryTestHelper
+	factory *testutils.Factory
+}
+
+func (s *InventoryReorderSuggestionsSuite) SetupSuite() {
+	s.helper = NewInventoryTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *InventoryReorderSuggestionsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, reorderSuggestionTables...))
+}
+
+func (s *InventoryReorderSuggestionsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, reorderSuggestionTables...))
+}
+
+func (s *InventoryReorderSuggestionsSuite) TestRankedPurchaseList() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+
+	stock := func(qty, alert int) testutils.Option[inventory.Variant] {
+		return func(v *inventory.Variant) {
+			v.StockQuantity = qty
+			v.StockQuantityAlert = alert
+		}
+	}
+	fast, err := s.factory.Variant(ctx, prod, stock(5, 2))
+	s.Require().NoError(err)
+	slow, err := s.factory.Variant(ctx, prod, stock(10, 2))
+	s.Require().NoError(err)
+	belowAlert, err := s.factory.Variant(ctx, prod, stock(1, 5))
+	s.Require().NoError(err)
+
+	_, err = s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: fast, Quantity: 30}, {Variant: slow, Quantity: 3}})
+	s.Require().NoError(err)
+	_, err = s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: slow, Quantity: 100}}, func(o *order.Order) {
+		o.Status = order.OrderStatusCancelled
+	})
+	s.Require().NoError(err)
+	_, err = s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: slow, Quantity: 100}}, func(o *order.Order) {
+		o.OrderedAt = time.Now().UTC().AddDate(0, 0, -60)
+	})
+	s.Require().NoError(err)
+
+	path := "/v1/businesses/" + biz.Descriptor + "/inventory/reorder-suggestions"
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", path, nil, owner.Token)
+	s.Require().NoError(err)
+	var body inventory.ReorderSuggestionsResponse
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	s.Equal(30, body.WindowDays)
+	s.Equal(14, body.LeadTimeDays)
+	s.Equal(30, body.CoverDays)
+
+	// fast sells 1/day: 44 days of demand + alert 2 - stock 5. slow's 10 units cover its
+	// 0.1/day demand, ignoring the cancelled and out-of-window orders.
+	s.Require().Len(body.Items, 2)
+	s.Equal(fast.ID, body.Items[0].VariantID)
+	s.Equal(30, body.Items[0].UnitsSold)
+	s.Equal(1.0, body.Items[0].DailyVelocity)
+	s.Require().NotNil(body.Items[0].DaysOfStock)
+	s.Equal(5.0, *body.Items[0].DaysOfStock)
+	s.Equal(41, body.Items[0].SuggestedQuantity)
+	s.Equal("2050", body.Items[0].EstimatedCost.String())
+
+	s.Equal(belowAlert.ID, body.Items[1].VariantID)
+	s.Nil(body.Items[1].DaysOfStock, "variants without sales rank last")
+	s.Equal(4, body.Items[1].SuggestedQuantity)
+
+	resp, err = s.helper.Client.AuthenticatedRequest("GET", path+"?leadTimeDays=0&coverDays=0", nil, owner.Token)
+	s.Require().NoError(err)
+	body = inventory.ReorderSuggestionsResponse{}
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	s.Require().Len(body.Items, 1)
+	s.Equal(belowAlert.ID, body.Items[0].VariantID)
+
+	resp, err = s.helper.Client.AuthenticatedRequest("GET", path+"?days=0", nil, owner.Token)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode, "zero falls back to the default window")
+
+	resp, err = s.helper.Client.AuthenticatedRequest("GET", path+"?days=1000", nil, owner.Token)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *InventoryReorderSuggestionsSuite) TestPreferredSupplierReorderSettings() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	stock := func(qty int) testutils.Option[inventory.Variant] {
+		return func(v *inventory.Variant) {
+			v.StockQuantity = qty
+			v.StockQuantityAlert = 2
+		}
+	}
+	linked, err := s.factory.Variant(ctx, prod, stock(10))
+	s.Require().NoError(err)
+	unlinked, err := s.factory.Variant(ctx, prod, stock(40))
+	s.Require().NoError(err)
+	_, err = s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: linked, Quantity: 30}, {Variant: unlinked, Quantity: 30}})
+	s.Require().NoError(err)
+
+	base := "/v1/businesses/" + biz.Descriptor + "/inventory"
+	resp, err := s.helper.Client.AuthenticatedRequest("POST", base+"/suppliers", map[string]interface{}{"name": "Acme Wholesale"}, owner.Token)
+	s.Require().NoError(err)
+	var sup map[string]interface{}
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+	s.Require().NoError(testutils.DecodeJSON(resp, &sup))
+
+	resp, err = s.helper.Client.AuthenticatedRequest("PATCH", base+"/variants/"+linked.ID, map[string]interface{}{
+		"preferredSupplierId": sup["id"],
+		"leadTimeDays":        20,
+		"reorderPoint":        5,
+		"reorderQuantity":     24,
+	}, owner.Token)
+	s.Require().NoError(err)
+	var variant map[string]interface{}
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Require().NoError(testutils.DecodeJSON(resp, &variant))
+	s.Equal(sup["id"], variant["preferredSupplierId"])
+	s.Equal(20.0, variant["leadTimeDays"])
+
+	list := func(query string) inventory.ReorderSuggestionsResponse {
+		resp, err := s.helper.Client.AuthenticatedRequest("GET", base+"/reorder-suggestions"+query, nil, owner.Token)
+		s.Require().NoError(err)
+		var body inventory.ReorderSuggestionsResponse
+		s.Require().Equal(http.StatusOK, resp.StatusCode)
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+		return body
+	}
+
+	// both sell 1/day. linked runs out in 10 of its 20 lead days: 50 days of demand + reorder
+	// point 5 - stock 10 = 45, rounded up to two lots of 24. unlinked's 40 units outlast the
+	// default 14 days: 44 days of demand + alert 2 - stock 40.
+	body := list("")
+	s.Require().Len(body.Items, 2)
+	first := body.Items[0]
+	s.Equal(linked.ID, first.VariantID)
+	s.Equal(sup["id"], first.SupplierID)
+	s.Equal("Acme Wholesale", first.SupplierName)
+	s.Equal(20, first.LeadTimeDays)
+	s.Equal(-10, first.ProjectedStock)
+	s.True(first.RunsOutWithinLeadTime)
+	s.Equal(48, first.SuggestedQuantity)
+	second := body.Items[1]
+	s.Equal(unlinked.ID, second.VariantID)
+	s.Empty(second.SupplierID)
+	s.Equal(14, second.LeadTimeDays)
+	s.Equal(26, second.ProjectedStock)
+	s.False(second.RunsOutWithinLeadTime)
+	s.Equal(6, second.SuggestedQuantity)
+
+	body = list("?runsOut=true")
+	s.Require().Len(body.Items, 1)
+	s.Equal(linked.ID, body.Items[0].VariantID)
+
+	body = list("?supplierId=" + sup["id"].(string))
+	s.Require().Len(body.Items, 1)
+	s.Equal(linked.ID, body.Items[0].VariantID)
+
+	resp, err = s.helper.Client.AuthenticatedRequest("PATCH", base+"/variants/"+unlinked.ID, map[string]interface{}{"preferredSupplierId": "sup_missing"}, owner.Token)
+	s.Require().NoError(err)
+	var problem map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &problem))
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+	s.Equal("inventory.supplier_not_found", problem["extensions"].(map[string]interface{})["code"])
+}
+
+func TestInventoryReorderSuggestionsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(InventoryReorderSuggestionsSuite))
+}
@@ -0,0 +1,128 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+// InventorySearchSuite tests the mixed product and variant search.
+type InventorySearchSuite struct {
+	suite.Suite
+	accountHelper   *AccountTestHelper
+	inventoryHelper *InventoryTestHelper
+	token           string
+}
+
+func (s *InventorySearchSuite) SetupSuite() {
+	s.accountHelper = NewAccountTestHelper(testEnv.Database, testEnv.CacheAddr, e2eBaseURL)
+	s.inventoryHelper = NewInventoryTestHelper(testEnv.Database, e2eBaseURL)
+}
+
+func (s *InventorySearchSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, inventoryTables...))
+
+	ctx := context.Background()
+	_, ws, token, err := s.accountHelper.CreateTestUser(ctx, "admin@example.com", "Password123!", "Admin", "User", role.RoleAdmin)
+	s.Require().NoError(err)
+	s.Require().NoError(s.accountHelper.CreateTestSubscription(ctx, ws.ID))
+	biz, err := s.inventoryHelper.CreateTestBusiness(ctx, ws.ID, "test-biz")
+	s.Require().NoError(err)
+	cat, err := s.inventoryHelper.CreateTestCategory(ctx, biz.ID, "Electronics", "electronics")
+	s.Require().NoError(err)
+
+	headphones, err := s.inventoryHelper.CreateTestProduct(ctx, biz.ID, cat.ID, "Wireless Headphones", "Premium audio quality")
+	s.Require().NoError(err)
+	_, err = s.inventoryHelper.CreateTestVariant(ctx, biz.ID, headphones.ID, "Black", "WH-BLACK-001", "USD", decimal.NewFromInt(50), decimal.NewFromInt(100), 20, 5)
+	s.Require().NoError(err)
+	_, err = s.inventoryHelper.CreateTestVariant(ctx, biz.ID, headphones.ID, "White", "WH-WHITE-001", "USD", decimal.NewFromInt(50), decimal.NewFromInt(100), 20, 5)
+	s.Require().NoError(err)
+	mouse, err := s.inventoryHelper.CreateTestProduct(ctx, biz.ID, cat.ID, "Gaming Mouse", "RGB gaming mouse")
+	s.Require().NoError(err)
+	_, err = s.inventoryHelper.CreateTestVariant(ctx, biz.ID, mouse.ID, "Red", "GM-RED-001", "USD", decimal.NewFromInt(30), decimal.NewFromInt(60), 15, 3)
+	s.Require().NoError(err)
+	s.token = token
+}
+
+func (s *InventorySearchSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, inventoryTables...))
+}
+
+func (s *InventorySearchSuite) search(query string) (int, map[string]interface{}) {
+	resp, err := s.inventoryHelper.Client.AuthenticatedRequest("GET", "/v1/businesses/test-biz/inventory/search?"+query, nil, s.token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func (s *InventorySearchSuite) items(body map[string]interface{}) []map[string]interface{} {
+	raw := body["items"].([]interface{})
+	items := make([]map[string]interface{}, len(raw))
+	for i, it := range raw {
+		items[i] = it.(map[string]interface{})
+	}
+	return items
+}
+
+func (s *InventorySearchSuite) TestMisspelledNameFindsProductAndVariants() {
+	status, body := s.search("q=" + url.QueryEscape("wireles hedphones"))
+	s.Require().Equal(http.StatusOK, status, body)
+	items := s.items(body)
+	s.Require().NotEmpty(items)
+
+	types := map[string]bool{}
+	for _, it := range items {
+		types[it["type"].(string)] = true
+		product := it["product"].(map[string]interface{})
+		s.Equal("Wireless Headphones", product["name"])
+	}
+	s.True(types["product"])
+	s.True(types["variant"])
+}
+
+func (s *InventorySearchSuite) TestPartialSKUMatchesVariants() {
+	status, body := s.search("q=WH-BLA&types=variant")
+	s.Require().Equal(http.StatusOK, status, body)
+	items := s.items(body)
+	s.Require().NotEmpty(items)
+	for _, it := range items {
+		s.Equal("variant", it["type"])
+	}
+	s.Equal("WH-BLACK-001", items[0]["variant"].(map[string]interface{})["sku"])
+}
+
+func (s *InventorySearchSuite) TestExactSKURanksFirst() {
+	status, body := s.search("q=GM-RED-001")
+	s.Require().Equal(http.StatusOK, status, body)
+	items := s.items(body)
+	s.Require().NotEmpty(items)
+	s.Equal("variant", items[0]["type"])
+	s.Equal("GM-RED-001", items[0]["variant"].(map[string]interface{})["sku"])
+}
+
+func (s *InventorySearchSuite) TestLimitAndValidation() {
+	status, body := s.search("q=WH&limit=1")
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Len(s.items(body), 1)
+
+	status, _ = s.search("q=W")
+	s.Equal(http.StatusBadRequest, status)
+
+	status, _ = s.search("q=mouse&types=order")
+	s.Equal(http.StatusBadRequest, status)
+}
+
+func TestInventorySearchSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(InventorySearchSuite))
+}
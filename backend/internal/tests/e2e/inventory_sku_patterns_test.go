@@ -0,0 +1,129 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var skuPatternTables = append([]string{"variant_cost_changes", "stock_movements", "sku_sequences"}, inventoryTables...)
+
+// InventorySKUPatternsSuite tests SKUs generated from the business SKU pattern and their preview.
+type InventorySKUPatternsSuite struct {
+	suite.Suite
+	helper  *InventoryTestHelper
+	factory *testutils.Factory
+}
+
+func (s *InventorySKUPatternsSuite) SetupSuite() {
+	s.helper = NewInventoryTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *InventorySKUPatternsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, skuPatternTables...))
+}
+
+func (s *InventorySKUPatternsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, skuPatternTables...))
+}
+
+type skuPatternFixture struct {
+	owner   *testutils.Owner
+	biz     *business.Business
+	product *inventory.Product
+}
+
+func (s *InventorySKUPatternsSuite) setup(ctx context.Context) *skuPatternFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cat, err := s.factory.Category(ctx, biz.ID, func(c *inventory.Category) { c.Name = "Tops" })
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID, func(p *inventory.Product) { p.CategoryID = cat.ID })
+	s.Require().NoError(err)
+	return &skuPatternFixture{owner: owner, biz: biz, product: prod}
+}
+
+func (s *InventorySKUPatternsSuite) do(fx *skuPatternFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func (s *InventorySKUPatternsSuite) setPattern(fx *skuPatternFixture, pattern map[string]interface{}) (int, map[string]interface{}) {
+	return s.do(fx, "PATCH", "", map[string]interface{}{"skuPattern": pattern})
+}
+
+func (s *InventorySKUPatternsSuite) createVariant(fx *skuPatternFixture, code, sku string) map[string]interface{} {
+	status, body := s.do(fx, "POST", "/inventory/variants", map[string]interface{}{
+		"productId":          fx.product.ID,
+		"code":               code,
+		"sku":                sku,
+		"costPrice":          "10",
+		"salePrice":          "20",
+		"stockQuantity":      1,
+		"stockQuantityAlert": 0,
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	return body
+}
+
+func (s *InventorySKUPatternsSuite) TestVariantsTakeSequentialSKUs() {
+	fx := s.setup(context.Background())
+
+	status, body := s.setPattern(fx, map[string]interface{}{"prefix": "kyr", "includeCategory": true, "sequenceDigits": 4})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("KYR", body["skuPattern"].(map[string]interface{})["prefix"])
+
+	s.Equal("KYR-TOP-0001", s.createVariant(fx, "S", "")["sku"])
+	// a hand-typed SKU matching the next number is skipped
+	s.Equal("KYR-TOP-0002", s.createVariant(fx, "M", "KYR-TOP-0002")["sku"])
+	s.Equal("KYR-TOP-0003", s.createVariant(fx, "L", "")["sku"])
+}
+
+func (s *InventorySKUPatternsSuite) TestPreviewDoesNotTakeNumbers() {
+	fx := s.setup(context.Background())
+
+	status, body := s.do(fx, "GET", "/inventory/variants/sku-preview", nil)
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("inventory.sku_pattern_not_set", body["extensions"].(map[string]interface{})["code"])
+
+	status, body = s.do(fx, "GET", "/inventory/variants/sku-preview?prefix=ab&sequenceDigits=3&count=2&includeCategory=true&categoryId="+fx.product.CategoryID, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal([]interface{}{"AB-TOP-001", "AB-TOP-002"}, body["skus"])
+
+	status, body = s.setPattern(fx, map[string]interface{}{"prefix": "AB", "sequenceDigits": 3})
+	s.Require().Equal(http.StatusOK, status, body)
+	status, body = s.do(fx, "GET", "/inventory/variants/sku-preview", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal([]interface{}{"AB-001"}, body["skus"])
+	s.Equal("AB-001", s.createVariant(fx, "S", "")["sku"])
+}
+
+func (s *InventorySKUPatternsSuite) TestRejectsPatternWithoutSequence() {
+	fx := s.setup(context.Background())
+
+	status, body := s.setPattern(fx, map[string]interface{}{"prefix": "KYR"})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("business.invalid_sku_pattern", body["extensions"].(map[string]interface{})["code"])
+
+	status, body = s.setPattern(fx, map[string]interface{}{"prefix": "KY-R", "sequenceDigits": 4})
+	s.Equal(http.StatusBadRequest, status, body)
+}
+
+func TestInventorySKUPatternsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(InventorySKUPatternsSuite))
+}
@@ -0,0 +1,270 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var stocktakeTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants", "locations", "location_stocks",
+	"stocktakes", "stocktake_lines",
+}
+
+// InventoryStocktakesSuite tests stocktakes: snapshots, scanned and manual counts, the
+// variance report and the adjustments posted on approval.
+type InventoryStocktakesSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *InventoryStocktakesSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *InventoryStocktakesSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, stocktakeTables...))
+}
+
+func (s *InventoryStocktakesSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, stocktakeTables...))
+}
+
+type stocktakeFixture struct {
+	owner    *testutils.Owner
+	biz      *business.Business
+	shirt    *inventory.Variant
+	hat      *inventory.Variant
+	shirtCat string
+}
+
+// setup creates a business with two variants of different categories, each holding 10 units
+// at a cost of 50.
+func (s *InventoryStocktakesSuite) setup(ctx context.Context) *stocktakeFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	shirts, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	shirt, err := s.factory.Variant(ctx, shirts, func(v *inventory.Variant) { v.Barcode = "4006381333931" })
+	s.Require().NoError(err)
+	hats, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	hat, err := s.factory.Variant(ctx, hats)
+	s.Require().NoError(err)
+	return &stocktakeFixture{owner: owner, biz: biz, shirt: shirt, hat: hat, shirtCat: shirts.CategoryID}
+}
+
+func (s *InventoryStocktakesSuite) do(fx *stocktakeFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+"/inventory"+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *InventoryStocktakesSuite) problemCode(body map[string]interface{}) interface{} {
+	return body["extensions"].(map[string]interface{})["code"]
+}
+
+func (s *InventoryStocktakesSuite) start(fx *stocktakeFixture, payload map[string]interface{}) string {
+	status, body := s.do(fx, "POST", "/stocktakes", payload)
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Equal("counting", body["status"])
+	return body["id"].(string)
+}
+
+// count enters a counted quantity by hand and returns the updated line.
+func (s *InventoryStocktakesSuite) count(fx *stocktakeFixture, id, variantID string, quantity int) map[string]interface{} {
+	payload := map[string]interface{}{
+		"counts": []map[string]interface{}{{"variantId": variantID, "quantity": quantity}},
+	}
+	resp, err := s.helper.Client.AuthenticatedRequest("PUT", "/v1/businesses/"+fx.biz.Descriptor+"/inventory/stocktakes/"+id+"/counts", payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var lines []map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &lines))
+	s.Require().Len(lines, 1)
+	return lines[0]
+}
+
+func (s *InventoryStocktakesSuite) stock(ctx context.Context, variantID string) int {
+	v, err := database.NewRepository[inventory.Variant](testEnv.Database).FindByID(ctx, variantID)
+	s.Require().NoError(err)
+	return v.StockQuantity
+}
+
+// linesByVariant maps the lines of a stocktake response by variant ID.
+func linesByVariant(body map[string]interface{}) map[string]map[string]interface{} {
+	out := map[string]map[string]interface{}{}
+	for _, l := range body["lines"].([]interface{}) {
+		line := l.(map[string]interface{})
+		out[line["variantId"].(string)] = line
+	}
+	return out
+}
+
+func (s *InventoryStocktakesSuite) TestCountReportAndApprove() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	id := s.start(fx, map[string]interface{}{"name": "Year end"})
+
+	status, body := s.do(fx, "GET", "/stocktakes/"+id, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	lines := linesByVariant(body)
+	s.Require().Len(lines, 2)
+	s.Equal(float64(10), lines[fx.shirt.ID]["expectedQuantity"])
+	s.Nil(lines[fx.shirt.ID]["countedQuantity"])
+
+	// Scans add up, by barcode or SKU.
+	status, body = s.do(fx, "POST", "/stocktakes/"+id+"/scan", map[string]interface{}{"code": fx.shirt.Barcode})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(float64(1), body["countedQuantity"])
+	status, body = s.do(fx, "POST", "/stocktakes/"+id+"/scan", map[string]interface{}{"code": fx.shirt.SKU, "quantity": 6})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(float64(7), body["countedQuantity"])
+	s.Equal(float64(-3), body["variance"])
+
+	// Manual entry replaces the count.
+	s.Equal(float64(15), s.count(fx, id, fx.hat.ID, 15)["countedQuantity"])
+	s.Equal(float64(2), s.count(fx, id, fx.hat.ID, 12)["variance"])
+
+	status, body = s.do(fx, "GET", "/stocktakes/"+id+"/variances", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(float64(2), body["countedLines"])
+	s.Equal(float64(0), body["uncountedLines"])
+	s.Equal(float64(2), body["unitsOver"])
+	s.Equal(float64(3), body["unitsShort"])
+	s.Equal(float64(-1), body["netUnits"])
+	s.Equal("100", body["valueOver"])
+	s.Equal("150", body["valueShort"])
+	s.Equal("-50", body["netValue"])
+	variances := body["variances"].([]interface{})
+	s.Require().Len(variances, 2)
+	s.Equal(fx.shirt.ID, variances[0].(map[string]interface{})["variantId"], "largest value first")
+
+	// Two shirts sell while counting; approval keeps the sale and corrects by the variance.
+	repo := database.NewRepository[inventory.Variant](testEnv.Database)
+	fx.shirt.StockQuantity = 8
+	s.Require().NoError(repo.UpdateOne(ctx, fx.shirt))
+
+	status, body = s.do(fx, "POST", "/stocktakes/"+id+"/approve", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("approved", body["status"])
+	s.NotEmpty(body["approvedAt"])
+	lines = linesByVariant(body)
+	s.Equal(float64(-3), lines[fx.shirt.ID]["adjustedQuantity"])
+	s.Equal(float64(2), lines[fx.hat.ID]["adjustedQuantity"])
+	s.Equal(5, s.stock(ctx, fx.shirt.ID))
+	s.Equal(12, s.stock(ctx, fx.hat.ID))
+
+	status, body = s.do(fx, "POST", "/stocktakes/"+id+"/approve", nil)
+	s.Require().Equal(http.StatusConflict, status, body)
+	s.Equal("inventory.stocktake_closed", s.problemCode(body))
+	status, body = s.do(fx, "POST", "/stocktakes/"+id+"/scan", map[string]interface{}{"code": fx.shirt.SKU})
+	s.Require().Equal(http.StatusConflict, status, body)
+}
+
+func (s *InventoryStocktakesSuite) TestCycleCountScopeAndLifecycle() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	id := s.start(fx, map[string]interface{}{"name": "Shirts", "categoryId": fx.shirtCat})
+
+	status, body := s.do(fx, "GET", "/stocktakes/"+id, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Len(body["lines"], 1)
+
+	status, body = s.do(fx, "POST", "/stocktakes/"+id+"/scan", map[string]interface{}{"code": fx.hat.SKU})
+	s.Require().Equal(http.StatusNotFound, status, body)
+	s.Equal("inventory.variant_not_in_stocktake", s.problemCode(body))
+	status, body = s.do(fx, "POST", "/stocktakes/"+id+"/scan", map[string]interface{}{"code": "UNKNOWN"})
+	s.Require().Equal(http.StatusNotFound, status, body)
+	s.Equal("inventory.variant_not_found", s.problemCode(body))
+
+	status, body = s.do(fx, "POST", "/stocktakes", map[string]interface{}{"name": "Again"})
+	s.Require().Equal(http.StatusConflict, status, body)
+	s.Equal("inventory.stocktake_in_progress", s.problemCode(body))
+
+	status, body = s.do(fx, "POST", "/stocktakes/"+id+"/approve", nil)
+	s.Require().Equal(http.StatusBadRequest, status, body)
+	s.Equal("inventory.stocktake_not_counted", s.problemCode(body))
+
+	status, body = s.do(fx, "POST", "/stocktakes/"+id+"/cancel", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("cancelled", body["status"])
+	s.Equal(10, s.stock(ctx, fx.shirt.ID))
+
+	s.start(fx, map[string]interface{}{"name": "Hats", "variantIds": []string{fx.hat.ID}})
+	status, body = s.do(fx, "GET", "/stocktakes?status=counting", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Len(body["items"], 1)
+	status, body = s.do(fx, "GET", "/stocktakes", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Len(body["items"], 2)
+}
+
+func (s *InventoryStocktakesSuite) TestLocationCount() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	status, body := s.do(fx, "POST", "/locations", map[string]interface{}{"name": "Warehouse"})
+	s.Require().Equal(http.StatusCreated, status, body)
+	warehouse := body["id"].(string)
+	status, body = s.do(fx, "POST", "/locations", map[string]interface{}{"name": "Store"})
+	s.Require().Equal(http.StatusCreated, status, body)
+	store := body["id"].(string)
+	status, body = s.do(fx, "POST", "/locations/transfers", map[string]interface{}{
+		"fromLocationId": warehouse,
+		"toLocationId":   store,
+		"items":          []map[string]interface{}{{"variantId": fx.shirt.ID, "quantity": 4}},
+	})
+	s.Require().Equal(http.StatusNoContent, status, body)
+
+	id := s.start(fx, map[string]interface{}{"name": "Store count", "locationId": store})
+	status, body = s.do(fx, "GET", "/stocktakes/"+id, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(store, body["locationId"])
+	lines := linesByVariant(body)
+	s.Equal(float64(4), lines[fx.shirt.ID]["expectedQuantity"])
+	s.Equal(float64(0), lines[fx.hat.ID]["expectedQuantity"])
+
+	// The warehouse can be counted at the same time.
+	s.start(fx, map[string]interface{}{"name": "Warehouse count"})
+
+	s.count(fx, id, fx.shirt.ID, 3)
+	status, body = s.do(fx, "POST", "/stocktakes/"+id+"/approve", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(9, s.stock(ctx, fx.shirt.ID))
+	s.Equal(10, s.stock(ctx, fx.hat.ID), "uncounted lines are left alone")
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+fx.biz.Descriptor+"/inventory/variants/"+fx.shirt.ID+"/locations", nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var levels []map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &levels))
+	byLocation := map[string]float64{}
+	for _, l := range levels {
+		byLocation[l["locationId"].(string)] = l["quantity"].(float64)
+	}
+	s.Equal(map[string]float64{warehouse: 6, store: 3}, byLocation)
+}
+
+func TestInventoryStocktakesSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(InventoryStocktakesSuite))
+}
@@ -0,0 +1,156 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var valuationTables = append([]string{"stock_movements"}, costLayerTables...)
+
+// InventoryValuationSuite tests valuing the inventory as of a past date from the recorded
+// stock movements and cost price history.
+type InventoryValuationSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *InventoryValuationSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *InventoryValuationSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, valuationTables...))
+}
+
+func (s *InventoryValuationSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, valuationTables...))
+}
+
+type valuationFixture struct {
+	owner *testutils.Owner
+	biz   *business.Business
+	cust  *customer.Customer
+	addr  *customer.CustomerAddress
+}
+
+func (s *InventoryValuationSuite) setup(ctx context.Context) *valuationFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	addr, err := s.factory.Address(ctx, cust.ID)
+	s.Require().NoError(err)
+	return &valuationFixture{owner: owner, biz: biz, cust: cust, addr: addr}
+}
+
+func (s *InventoryValuationSuite) do(fx *valuationFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *InventoryValuationSuite) valuation(fx *valuationFixture, asOf time.Time) map[string]interface{} {
+	status, body := s.do(fx, "GET", "/inventory/valuation?asOf="+url.QueryEscape(asOf.Format(time.RFC3339Nano)), nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	return body
+}
+
+// checkpoint returns a moment strictly between the stock movements before and after it.
+func checkpoint() time.Time {
+	time.Sleep(20 * time.Millisecond)
+	at := time.Now().UTC()
+	time.Sleep(20 * time.Millisecond)
+	return at
+}
+
+func (s *InventoryValuationSuite) TestReplaysStockAndCostHistory() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	beforeCatalog := checkpoint()
+
+	prod, err := s.factory.Product(ctx, fx.biz.ID)
+	s.Require().NoError(err)
+	status, variant := s.do(fx, "POST", "/inventory/variants", map[string]interface{}{
+		"productId":          prod.ID,
+		"code":               "red",
+		"costPrice":          "50",
+		"salePrice":          "100",
+		"stockQuantity":      10,
+		"stockQuantityAlert": 0,
+	})
+	s.Require().Equal(http.StatusCreated, status, variant)
+	variantID := variant["id"].(string)
+	monthEnd := checkpoint()
+
+	status, body := s.do(fx, "PATCH", "/inventory/variants/"+variantID, map[string]interface{}{"stockQuantity": 15, "costPrice": "60"})
+	s.Require().Equal(http.StatusOK, status, body)
+	status, body = s.do(fx, "POST", "/orders", map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"channel":           "instagram",
+		"items":             []map[string]interface{}{{"variantId": variantID, "quantity": 3, "unitPrice": "100"}},
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	afterSale := checkpoint()
+
+	empty := s.valuation(fx, beforeCatalog)
+	s.Equal("0", empty["totalValue"])
+	s.Empty(empty["items"])
+	s.NotNil(empty["trackedSince"])
+
+	// the month closed with the 10 opening units at the cost price of the time
+	closing := s.valuation(fx, monthEnd)
+	s.Equal(10.0, closing["totalUnits"])
+	s.Equal("500", closing["totalValue"])
+	s.Equal("USD", closing["currency"])
+	items := closing["items"].([]interface{})
+	s.Require().Len(items, 1)
+	line := items[0].(map[string]interface{})
+	s.Equal(variantID, line["variantId"])
+	s.Equal(10.0, line["quantity"])
+	s.Equal("50", line["unitCost"])
+
+	// 15 after the edit, less 3 sold, at the new cost price
+	current := s.valuation(fx, afterSale)
+	s.Equal(12.0, current["totalUnits"])
+	s.Equal("720", current["totalValue"])
+
+	// a variant deleted after the month end still counts in the month's closing stock
+	status, body = s.do(fx, "DELETE", "/inventory/variants/"+variantID, nil)
+	s.Require().Equal(http.StatusNoContent, status, body)
+	s.Equal("500", s.valuation(fx, monthEnd)["totalValue"])
+	status, body = s.do(fx, "GET", "/inventory/valuation", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("0", body["totalValue"])
+}
+
+func (s *InventoryValuationSuite) TestRejectsFutureDate() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	status, body := s.do(fx, "GET", "/inventory/valuation?asOf="+url.QueryEscape(time.Now().Add(48*time.Hour).Format(time.RFC3339)), nil)
+	s.Equal(http.StatusBadRequest, status, body)
+}
+
+func TestInventoryValuationSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(InventoryValuationSuite))
+}
@@ -0,0 +1,186 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var variantOptionTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants", "variant_cost_changes",
+}
+
+// InventoryVariantOptionsSuite tests product options and variant matrix generation.
+type InventoryVariantOptionsSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *InventoryVariantOptionsSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *InventoryVariantOptionsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, variantOptionTables...))
+}
+
+func (s *InventoryVariantOptionsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, variantOptionTables...))
+}
+
+type variantOptionsFixture struct {
+	owner   *testutils.Owner
+	biz     *business.Business
+	product *inventory.Product
+}
+
+func (s *InventoryVariantOptionsSuite) setup(ctx context.Context) *variantOptionsFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	return &variantOptionsFixture{owner: owner, biz: biz, product: prod}
+}
+
+func (s *InventoryVariantOptionsSuite) generate(fx *variantOptionsFixture, payload map[string]interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest("POST", "/v1/businesses/"+fx.biz.Descriptor+"/inventory/products/"+fx.product.ID+"/variants/generate", payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func sizeColorOptions(sizes ...string) []map[string]interface{} {
+	sizeValues := []map[string]interface{}{}
+	for _, size := range sizes {
+		modifier := "0"
+		if size == "XL" {
+			modifier = "5"
+		}
+		sizeValues = append(sizeValues, map[string]interface{}{"value": size, "priceModifier": modifier})
+	}
+	return []map[string]interface{}{
+		{"name": "Size", "values": sizeValues},
+		{"name": "Color", "values": []map[string]interface{}{{"value": "Red"}, {"value": "Blue", "priceModifier": "-2"}}},
+	}
+}
+
+// pricesByCode maps variant code to sale price.
+func pricesByCode(variants []interface{}) map[string]string {
+	out := map[string]string{}
+	for _, v := range variants {
+		m := v.(map[string]interface{})
+		out[m["code"].(string)] = m["salePrice"].(string)
+	}
+	return out
+}
+
+func (s *InventoryVariantOptionsSuite) TestGenerateMatrix() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	status, body := s.generate(fx, map[string]interface{}{
+		"options":       sizeColorOptions("M", "XL"),
+		"costPrice":     "10",
+		"salePrice":     "30",
+		"stockQuantity": 4,
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	created := body["created"].([]interface{})
+	s.Equal(map[string]string{
+		"M / Red":   "30",
+		"M / Blue":  "28",
+		"XL / Red":  "35",
+		"XL / Blue": "33",
+	}, pricesByCode(created))
+	first := created[0].(map[string]interface{})
+	s.Equal([]interface{}{
+		map[string]interface{}{"name": "Size", "value": "M"},
+		map[string]interface{}{"name": "Color", "value": "Red"},
+	}, first["options"])
+	s.Equal(float64(4), first["stockQuantity"])
+	product := body["product"].(map[string]interface{})
+	s.Len(product["options"], 2)
+	s.Len(product["variants"], 4)
+
+	// Adding a size only creates the new combinations.
+	status, body = s.generate(fx, map[string]interface{}{
+		"options":   sizeColorOptions("M", "XL", "S"),
+		"costPrice": "10",
+		"salePrice": "30",
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(map[string]string{"S / Red": "30", "S / Blue": "28"}, pricesByCode(body["created"].([]interface{})))
+}
+
+func (s *InventoryVariantOptionsSuite) TestAdoptsVariantsWithMatchingCode() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	manual, err := s.factory.Variant(ctx, fx.product, func(v *inventory.Variant) { v.Code = "m / red" })
+	s.Require().NoError(err)
+
+	status, body := s.generate(fx, map[string]interface{}{
+		"options":   sizeColorOptions("M"),
+		"costPrice": "10",
+		"salePrice": "30",
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(map[string]string{"M / Blue": "28"}, pricesByCode(body["created"].([]interface{})))
+	for _, v := range body["product"].(map[string]interface{})["variants"].([]interface{}) {
+		m := v.(map[string]interface{})
+		if m["id"] == manual.ID {
+			s.Len(m["options"], 2, "the hand-made variant takes the combination's options")
+			s.Equal("100", m["salePrice"], "and keeps its price")
+		}
+	}
+}
+
+func (s *InventoryVariantOptionsSuite) TestValidation() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	status, body := s.generate(fx, map[string]interface{}{
+		"options": []map[string]interface{}{
+			{"name": "Size", "values": []map[string]interface{}{{"value": "M"}, {"value": " m "}}},
+		},
+		"costPrice": "10",
+		"salePrice": "30",
+	})
+	s.Require().Equal(http.StatusBadRequest, status, body)
+	s.Equal("inventory.invalid_product_options", body["extensions"].(map[string]interface{})["code"])
+
+	status, body = s.generate(fx, map[string]interface{}{
+		"options":   sizeColorOptions("M"),
+		"costPrice": "1",
+		"salePrice": "1",
+	})
+	s.Require().Equal(http.StatusBadRequest, status, body)
+	s.Equal("inventory.negative_variant_price", body["extensions"].(map[string]interface{})["code"])
+
+	values := []map[string]interface{}{}
+	for _, v := range []string{"a", "b", "c", "d", "e", "f"} {
+		values = append(values, map[string]interface{}{"value": v})
+	}
+	many := []map[string]interface{}{{"name": "A", "values": values}, {"name": "B", "values": values}, {"name": "C", "values": values}}
+	status, body = s.generate(fx, map[string]interface{}{"options": many, "costPrice": "1", "salePrice": "5"})
+	s.Require().Equal(http.StatusBadRequest, status, body)
+	s.Equal("inventory.variant_matrix_too_large", body["extensions"].(map[string]interface{})["code"])
+}
+
+func TestInventoryVariantOptionsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(InventoryVariantOptionsSuite))
+}
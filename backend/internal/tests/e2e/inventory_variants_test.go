@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
 	"github.com/abdelrahman146/kyora/internal/platform/auth"
 	"github.com/abdelrahman146/kyora/internal/platform/types/role"
 	"github.com/abdelrahman146/kyora/internal/tests/testutils"
@@ -17,15 +19,17 @@ type InventoryVariantsSuite struct {
 	suite.Suite
 	accountHelper   *AccountTestHelper
 	inventoryHelper *InventoryTestHelper
+	factory         *testutils.Factory
 }
 
 func (s *InventoryVariantsSuite) SetupSuite() {
 	s.accountHelper = NewAccountTestHelper(testEnv.Database, testEnv.CacheAddr, e2eBaseURL)
 	s.inventoryHelper = NewInventoryTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
 }
 
 func (s *InventoryVariantsSuite) resetDB() {
-	s.NoError(testutils.TruncateTables(testEnv.Database, inventoryTables...))
+	s.NoError(testutils.TruncateTables(testEnv.Database, append(inventoryTables, "orders", "order_items", "customers", "customer_addresses")...))
 }
 
 func (s *InventoryVariantsSuite) SetupTest() {
@@ -130,6 +134,70 @@ func (s *InventoryVariantsSuite) TestListVariants_Pagination() {
 	s.Equal(true, result["hasMore"])
 }
 
+func (s *InventoryVariantsSuite) TestListPickerVariants_ReportsAvailability() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID, func(p *inventory.Product) { p.Name = "Shirt" })
+	s.Require().NoError(err)
+	// Stock is already deducted for open orders, so StockQuantity is what is left to sell.
+	blue, err := s.factory.Variant(ctx, prod, func(v *inventory.Variant) {
+		v.Name, v.Code, v.StockQuantity, v.StockQuantityAlert = "Shirt - Blue", "BLUE", 3, 5
+	})
+	s.Require().NoError(err)
+	red, err := s.factory.Variant(ctx, prod, func(v *inventory.Variant) {
+		v.Name, v.Code, v.StockQuantity = "Shirt - Red", "RED", 0
+	})
+	s.Require().NoError(err)
+
+	_, err = s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: blue, Quantity: 2}, {Variant: red, Quantity: 1}})
+	s.Require().NoError(err)
+	// Shipped orders have left the shelf and no longer hold stock.
+	_, err = s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: blue, Quantity: 4}}, func(o *order.Order) {
+		o.Status = order.OrderStatusShipped
+	})
+	s.Require().NoError(err)
+
+	path := fmt.Sprintf("/v1/businesses/%s/inventory/variants/picker?orderBy=code", biz.Descriptor)
+	resp, err := s.inventoryHelper.Client.AuthenticatedRequest("GET", path, nil, owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var result map[string]interface{}
+	s.NoError(testutils.DecodeJSON(resp, &result))
+	s.Equal(float64(2), result["totalCount"])
+	items := result["items"].([]interface{})
+	s.Require().Len(items, 2)
+
+	first := items[0].(map[string]interface{})
+	s.Equal(blue.ID, first["id"])
+	s.Equal("Shirt", first["productName"])
+	s.Equal("100", first["salePrice"])
+	s.Equal(float64(5), first["onHand"])
+	s.Equal(float64(2), first["reserved"])
+	s.Equal(float64(3), first["available"])
+	s.Equal("low_stock", first["stockStatus"])
+
+	second := items[1].(map[string]interface{})
+	s.Equal(red.ID, second["id"])
+	s.Equal(float64(1), second["reserved"])
+	s.Equal(float64(0), second["available"])
+	s.Equal("out_of_stock", second["stockStatus"])
+
+	resp2, err := s.inventoryHelper.Client.AuthenticatedRequest("GET", fmt.Sprintf("/v1/businesses/%s/inventory/variants/picker?search=blue", biz.Descriptor), nil, owner.Token)
+	s.Require().NoError(err)
+	defer resp2.Body.Close()
+	s.Require().Equal(http.StatusOK, resp2.StatusCode)
+	var searched map[string]interface{}
+	s.NoError(testutils.DecodeJSON(resp2, &searched))
+	found := searched["items"].([]interface{})
+	s.Require().Len(found, 1)
+	s.Equal(blue.ID, found[0].(map[string]interface{})["id"])
+}
+
 func (s *InventoryVariantsSuite) TestUpdateVariant_UpdatesAndNormalizes() {
 	ctx := context.Background()
 	_, ws, token, err := s.accountHelper.CreateTestUser(ctx, "admin@example.com", "Password123!", "Admin", "User", role.RoleAdmin)
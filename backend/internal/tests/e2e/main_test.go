@@ -11,15 +11,23 @@ import (
 	"github.com/abdelrahman146/kyora/internal/server"
 	"github.com/abdelrahman146/kyora/internal/tests/testutils"
 	"github.com/spf13/viper"
-	"github.com/stripe/stripe-go/v83"
 )
 
 var (
 	testEnv    *testutils.Environment
 	testServer *server.Server
+	// testGoogleOAuth stands in for Google in the server; suites register codes on it.
+	testGoogleOAuth = testutils.NewFakeGoogleOAuth()
+	// testOIDC stands in for workspace identity providers; suites register codes on it.
+	testOIDC = testutils.NewFakeOIDC()
+	// testDNS answers the TXT lookups of SSO domain verification; suites publish records on it.
+	testDNS = testutils.NewFakeTXTResolver()
 )
 
-const e2eBaseURL = "http://localhost:18080"
+const (
+	e2eBaseURL    = "http://localhost:18080"
+	e2eAdminToken = "test_admin_token"
+)
 
 func TestMain(m *testing.M) {
 	fmt.Println("Setting up e2e test environment...")
@@ -29,6 +37,8 @@ func TestMain(m *testing.M) {
 	viper.Set(config.EmailProvider, "mock")
 	// JWT is required for authenticated endpoints in tests.
 	viper.Set(config.JWTSecret, "test_jwt_secret")
+	// Operator endpoints (dead letters) need a static admin token.
+	viper.Set(config.AdminAPIToken, e2eAdminToken)
 
 	// Many domains (e.g., assets) generate absolute URLs for uploads and public access.
 	// Ensure they point at the E2E server, not the default dev port.
@@ -44,6 +54,18 @@ func TestMain(m *testing.M) {
 	// Webhook secret isn't required for most E2E flows, but keep it non-empty.
 	viper.Set(config.StripeWebhookSecret, "whsec_test")
 
+	// Shipping label tests buy labels from the fake carrier.
+	viper.Set(config.ShippingMockCarrier, true)
+
+	// Storefront customers can sign in by WhatsApp through the logging client.
+	viper.Set(config.WhatsappProvider, "mock")
+
+	// SSO identity providers send users back to the portal.
+	viper.Set(config.SSORedirectURL, "http://localhost:3000/auth/sso/callback")
+
+	// Foreign-currency expenses convert at the fixed mock rates.
+	viper.Set(config.FXProvider, "mock")
+
 	// Disable automatic plan sync for test isolation
 	// Tests will create their own plans as needed
 	viper.Set(config.BillingAutoSyncPlans, false)
@@ -63,15 +85,16 @@ func TestMain(m *testing.M) {
 	// Ensure any direct stripe-go calls from tests (helpers) are routed to stripe-mock.
 	// Server initialization also configures this, but doing it here avoids accidental
 	// dependency on server boot order.
-	stripe.Key = viper.GetString(config.StripeAPIKey)
-	backend := stripe.GetBackendWithConfig(stripe.APIBackend, &stripe.BackendConfig{URL: &env.StripeMockBase})
-	stripe.SetBackend(stripe.APIBackend, backend)
+	testutils.ConfigureStripeMock(viper.GetString(config.StripeAPIKey), env.StripeMockBase)
 
 	// Create server with container-provided dependencies (DB, cache, stripe-mock)
 	testServer, err = server.New(
 		server.WithDatabaseDSN(env.DatabaseDSN),
 		server.WithCacheHosts([]string{env.CacheAddr}),
 		server.WithStripeBaseURL(env.StripeMockBase),
+		server.WithGoogleOAuthProvider(testGoogleOAuth),
+		server.WithOIDCProvider(testOIDC),
+		server.WithTXTResolver(testDNS),
 		server.WithServerAddress(":18080"), // isolate test port
 	)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/abdelrahman146/kyora/internal/platform/region"
 	"github.com/abdelrahman146/kyora/internal/tests/testutils"
 	"github.com/stretchr/testify/suite"
 )
@@ -80,6 +81,38 @@ func (s *OnboardingBusinessSuite) TestSetBusiness_PaidPlan() {
 	s.Contains(result, "stage")
 }
 
+func (s *OnboardingBusinessSuite) TestSetBusiness_Region() {
+	token, err := s.helper.CreateVerifiedSession("region@example.com", "starter")
+	s.NoError(err)
+
+	payload := map[string]interface{}{
+		"sessionToken": token,
+		"name":         "My Business",
+		"descriptor":   "my-business",
+		"country":      "AE",
+		"currency":     "AED",
+		"region":       "mars",
+	}
+	resp, err := s.client.Post("/v1/onboarding/business", payload)
+	s.NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusBadRequest, resp.StatusCode, "unconfigured regions are rejected")
+
+	payload["region"] = region.Home()
+	resp, err = s.client.Post("/v1/onboarding/business", payload)
+	s.NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	resp, err = s.client.Get("/v1/onboarding/session?sessionToken=" + token)
+	s.NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+	var result map[string]interface{}
+	s.NoError(testutils.DecodeJSON(resp, &result))
+	s.Equal(region.Home(), result["region"])
+}
+
 func (s *OnboardingBusinessSuite) TestSetBusiness_InvalidStage() {
 	token, err := s.helper.CreateOnboardingSession("wrong@example.com", "starter")
 	s.NoError(err)
@@ -4,12 +4,14 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/abdelrahman146/kyora/internal/domain/onboarding"
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
 	"github.com/abdelrahman146/kyora/internal/tests/testutils"
 	"github.com/stretchr/testify/suite"
 )
 
 // OnboardingOAuthGoogleSuite tests POST /v1/onboarding/oauth/google endpoint.
-// Note: Test environment might not have Google OAuth configured; we focus on validation and session gating.
+// Google is replaced by testGoogleOAuth, so only codes registered on it exchange successfully.
 type OnboardingOAuthGoogleSuite struct {
 	suite.Suite
 	client *testutils.HTTPClient
@@ -23,6 +25,7 @@ func (s *OnboardingOAuthGoogleSuite) SetupSuite() {
 
 func (s *OnboardingOAuthGoogleSuite) SetupTest() {
 	testutils.TruncateTables(testEnv.Database, "users", "workspaces", "onboarding_sessions", "plans", "businesses")
+	testGoogleOAuth.Reset()
 }
 
 func (s *OnboardingOAuthGoogleSuite) TearDownTest() {
@@ -104,10 +107,40 @@ func (s *OnboardingOAuthGoogleSuite) TestOAuthGoogle_InvalidCode_Rejected() {
 	s.NoError(err)
 	defer resp.Body.Close()
 
-	// Without mocking Google OAuth, this should fail with an error (4xx/5xx) but must not succeed.
+	// Unregistered codes are rejected by the fake provider and must not advance the session.
 	s.True(resp.StatusCode >= 400)
 }
 
+func (s *OnboardingOAuthGoogleSuite) TestOAuthGoogle_Success_StagesIdentity() {
+	token, err := s.helper.CreateOnboardingSession("oauthuser@example.com", "starter")
+	s.Require().NoError(err)
+	testGoogleOAuth.RegisterCode("onboarding_code", auth.GoogleUserInfo{
+		Email:      "oauthuser@example.com",
+		GivenName:  "OAuth",
+		FamilyName: "User",
+		Verified:   true,
+	})
+
+	resp, err := s.client.Post("/v1/onboarding/oauth/google", map[string]interface{}{
+		"sessionToken": token,
+		"code":         "onboarding_code",
+	})
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var result map[string]interface{}
+	s.NoError(testutils.DecodeJSON(resp, &result))
+	s.Equal(string(onboarding.StageIdentityVerified), result["stage"])
+
+	sess, err := s.helper.GetSessionModel(token)
+	s.Require().NoError(err)
+	s.Equal(onboarding.IdentityGoogle, sess.Method)
+	s.True(sess.EmailVerified)
+	s.Equal("OAuth", sess.FirstName)
+	s.NotEmpty(sess.PasswordHash)
+}
+
 func TestOnboardingOAuthGoogleSuite(t *testing.T) {
 	if testServer == nil {
 		t.Skip("Test server not initialized")
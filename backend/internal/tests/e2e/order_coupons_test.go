@@ -0,0 +1,184 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var couponTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes",
+	"coupons", "coupon_redemptions",
+}
+
+// OrderCouponsSuite tests coupon management and redemption on orders.
+type OrderCouponsSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *OrderCouponsSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *OrderCouponsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, couponTables...))
+}
+
+func (s *OrderCouponsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, couponTables...))
+}
+
+type couponFixture struct {
+	owner   *testutils.Owner
+	biz     *business.Business
+	cust    *customer.Customer
+	addr    *customer.CustomerAddress
+	variant *inventory.Variant
+}
+
+// setup creates a business with a customer and a variant selling for 100 with 10 in stock.
+func (s *OrderCouponsSuite) setup(ctx context.Context) *couponFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	addr, err := s.factory.Address(ctx, cust.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	return &couponFixture{owner: owner, biz: biz, cust: cust, addr: addr, variant: v}
+}
+
+func (s *OrderCouponsSuite) do(fx *couponFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *OrderCouponsSuite) createCoupon(fx *couponFixture, payload map[string]interface{}) map[string]interface{} {
+	status, body := s.do(fx, "POST", "/coupons", payload)
+	s.Require().Equal(http.StatusCreated, status, body)
+	return body
+}
+
+func (s *OrderCouponsSuite) orderPayload(fx *couponFixture, extra map[string]interface{}) map[string]interface{} {
+	payload := map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"channel":           "instagram",
+		"items": []map[string]interface{}{
+			{"variantId": fx.variant.ID, "quantity": 2, "unitPrice": "100", "unitCost": "50"},
+		},
+	}
+	for k, v := range extra {
+		payload[k] = v
+	}
+	return payload
+}
+
+func (s *OrderCouponsSuite) TestCreateCoupon_NormalizesCode() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	c := s.createCoupon(fx, map[string]interface{}{"code": " summer-10 ", "discountType": "percent", "discountValue": "10"})
+	s.Equal("SUMMER-10", c["code"])
+	s.Equal(true, c["active"])
+	s.Equal(float64(0), c["usedCount"])
+
+	status, body := s.do(fx, "POST", "/coupons", map[string]interface{}{"code": "Summer-10", "discountType": "amount", "discountValue": "5"})
+	s.Equal(http.StatusConflict, status, body)
+
+	status, body = s.do(fx, "POST", "/coupons", map[string]interface{}{"code": "HALF", "discountType": "percent", "discountValue": "150"})
+	s.Equal(http.StatusBadRequest, status, body)
+}
+
+func (s *OrderCouponsSuite) TestCreateOrder_AppliesCoupon() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	c := s.createCoupon(fx, map[string]interface{}{"code": "SAVE10", "discountType": "percent", "discountValue": "10"})
+
+	status, preview := s.do(fx, "POST", "/orders/preview", s.orderPayload(fx, map[string]interface{}{"couponCode": "save10"}))
+	s.Require().Equal(http.StatusOK, status, preview)
+	s.Equal("20", preview["discount"])
+	s.Equal("SAVE10", preview["couponCode"])
+
+	status, ord := s.do(fx, "POST", "/orders", s.orderPayload(fx, map[string]interface{}{"couponCode": "save10"}))
+	s.Require().Equal(http.StatusCreated, status, ord)
+	s.Equal("20", ord["discount"])
+	s.Equal("percent", ord["discountType"])
+	s.Equal("SAVE10", ord["couponCode"])
+
+	status, got := s.do(fx, "GET", "/coupons/"+c["id"].(string), nil)
+	s.Require().Equal(http.StatusOK, status, got)
+	s.Equal(float64(1), got["usedCount"])
+
+	// deleting the order gives the use back
+	status, _ = s.do(fx, "DELETE", "/orders/"+ord["id"].(string), nil)
+	s.Require().Equal(http.StatusNoContent, status)
+	_, got = s.do(fx, "GET", "/coupons/"+c["id"].(string), nil)
+	s.Equal(float64(0), got["usedCount"])
+}
+
+func (s *OrderCouponsSuite) TestCreateOrder_EnforcesLimits() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	s.createCoupon(fx, map[string]interface{}{"code": "ONCE", "discountType": "amount", "discountValue": "15", "maxUsesPerCustomer": 1})
+	s.createCoupon(fx, map[string]interface{}{"code": "BIGSPENDER", "discountType": "amount", "discountValue": "50", "minSubtotal": "500"})
+	s.createCoupon(fx, map[string]interface{}{"code": "OLD", "discountType": "amount", "discountValue": "5", "expiresAt": time.Now().Add(-time.Hour).UTC()})
+
+	status, ord := s.do(fx, "POST", "/orders", s.orderPayload(fx, map[string]interface{}{"couponCode": "ONCE"}))
+	s.Require().Equal(http.StatusCreated, status, ord)
+	s.Equal("15", ord["discount"])
+
+	for _, code := range []string{"ONCE", "BIGSPENDER", "OLD", "MISSING"} {
+		status, body := s.do(fx, "POST", "/orders", s.orderPayload(fx, map[string]interface{}{"couponCode": code}))
+		s.Equal(http.StatusBadRequest, status, code)
+		s.Equal("order.coupon_not_applicable", body["extensions"].(map[string]interface{})["code"], code)
+	}
+
+	count, err := s.helper.CountOrders(ctx, fx.biz.ID)
+	s.Require().NoError(err)
+	s.Equal(int64(1), count)
+}
+
+func (s *OrderCouponsSuite) TestCreateOrder_RejectsCouponWithManualDiscount() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	s.createCoupon(fx, map[string]interface{}{"code": "SAVE10", "discountType": "percent", "discountValue": "10"})
+
+	status, body := s.do(fx, "POST", "/orders", s.orderPayload(fx, map[string]interface{}{
+		"couponCode":    "SAVE10",
+		"discountType":  "amount",
+		"discountValue": "5",
+	}))
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("order.coupon_with_manual_discount", body["extensions"].(map[string]interface{})["code"])
+}
+
+func TestOrderCouponsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(OrderCouponsSuite))
+}
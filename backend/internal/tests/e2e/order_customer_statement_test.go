@@ -0,0 +1,203 @@
+package e2e_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var customerStatementTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes",
+}
+
+// OrderCustomerStatementSuite tests the per-customer statement endpoint and its exports.
+type OrderCustomerStatementSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *OrderCustomerStatementSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *OrderCustomerStatementSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, customerStatementTables...))
+}
+
+func (s *OrderCustomerStatementSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, customerStatementTables...))
+}
+
+type statementFixture struct {
+	owner   *testutils.Owner
+	biz     *business.Business
+	cust    *customer.Customer
+	variant *inventory.Variant
+}
+
+func (s *OrderCustomerStatementSuite) setup(ctx context.Context) *statementFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	return &statementFixture{owner: owner, biz: biz, cust: cust, variant: v}
+}
+
+func statementDay(day string) time.Time {
+	t, _ := time.Parse("2006-01-02T15:04", day)
+	return t
+}
+
+func statementAt(day string) sql.NullTime {
+	return sql.NullTime{Time: statementDay(day), Valid: true}
+}
+
+// seedOrders creates, for the fixture customer:
+//   - an unpaid order before the period (opening balance 100)
+//   - a paid order of 200 in the period
+//   - a cancelled unpaid order in the period (left out)
+//   - a paid, returned and refunded order of 100 in the period
+//   - an order after the period (left out)
+//
+// plus an order for another customer.
+func (s *OrderCustomerStatementSuite) seedOrders(ctx context.Context, fx *statementFixture) {
+	line := []testutils.OrderLine{{Variant: fx.variant, Quantity: 1}}
+	seed := func(lines []testutils.OrderLine, opts ...testutils.Option[order.Order]) {
+		opts = append([]testutils.Option[order.Order]{func(o *order.Order) { o.CustomerID = fx.cust.ID }}, opts...)
+		_, err := s.factory.Order(ctx, fx.biz, lines, opts...)
+		s.Require().NoError(err)
+	}
+
+	seed(line, func(o *order.Order) { o.OrderedAt = statementDay("2026-01-10T10:00") })
+	seed([]testutils.OrderLine{{Variant: fx.variant, Quantity: 2}}, func(o *order.Order) {
+		o.OrderedAt = statementDay("2026-02-05T10:00")
+		o.PaymentStatus = order.OrderPaymentStatusPaid
+		o.PaidAt = statementAt("2026-02-06T10:00")
+	})
+	seed(line, func(o *order.Order) {
+		o.OrderedAt = statementDay("2026-02-10T10:00")
+		o.Status = order.OrderStatusCancelled
+		o.CancelledAt = statementAt("2026-02-11T10:00")
+	})
+	seed(line, func(o *order.Order) {
+		o.OrderedAt = statementDay("2026-02-12T10:00")
+		o.Status = order.OrderStatusReturned
+		o.PaymentStatus = order.OrderPaymentStatusRefunded
+		o.PaidAt = statementAt("2026-02-13T10:00")
+		o.ReturnedAt = statementAt("2026-02-19T10:00")
+		o.RefundedAt = statementAt("2026-02-20T10:00")
+	})
+	seed(line, func(o *order.Order) { o.OrderedAt = statementDay("2026-03-01T10:00") })
+
+	_, err := s.factory.Order(ctx, fx.biz, line, func(o *order.Order) { o.OrderedAt = statementDay("2026-02-15T10:00") })
+	s.Require().NoError(err)
+}
+
+func (s *OrderCustomerStatementSuite) get(fx *statementFixture, query string) *http.Response {
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+fx.biz.Descriptor+"/customers/"+fx.cust.ID+"/statement"+query, nil, fx.owner.Token)
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *OrderCustomerStatementSuite) TestStatement_JSON() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	s.seedOrders(ctx, fx)
+
+	resp := s.get(fx, "?from=2026-02-01&to=2026-02-28")
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+
+	s.Equal(fx.cust.ID, body["customerId"])
+	s.Equal("100", body["openingBalance"])
+	s.Equal("300", body["totalOrdered"])
+	s.Equal("300", body["totalPaid"])
+	s.Equal("100", body["totalCredited"])
+	s.Equal("100", body["totalRefunded"])
+	s.Equal("100", body["closingBalance"])
+
+	entries := body["entries"].([]interface{})
+	s.Require().Len(entries, 6)
+	var types, balances []string
+	for _, e := range entries {
+		m := e.(map[string]interface{})
+		types = append(types, m["type"].(string))
+		balances = append(balances, m["balance"].(string))
+	}
+	s.Equal([]string{"order", "payment", "order", "payment", "credit", "refund"}, types)
+	s.Equal([]string{"300", "100", "200", "100", "0", "100"}, balances)
+}
+
+func (s *OrderCustomerStatementSuite) TestStatement_Exports() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	s.seedOrders(ctx, fx)
+
+	resp := s.get(fx, "?from=2026-02-01&to=2026-02-28&format=csv")
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Contains(resp.Header.Get("Content-Type"), "text/csv")
+	s.Contains(resp.Header.Get("Content-Disposition"), ".csv")
+	data, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	s.Require().Len(lines, 9)
+	s.True(strings.HasPrefix(lines[0], "date,type,orderNumber"))
+	s.Contains(lines[1], "opening_balance")
+	s.Contains(lines[8], "closing_balance")
+
+	pdfResp := s.get(fx, "?from=2026-02-01&to=2026-02-28&format=pdf")
+	defer pdfResp.Body.Close()
+	s.Require().Equal(http.StatusOK, pdfResp.StatusCode)
+	s.Equal("application/pdf", pdfResp.Header.Get("Content-Type"))
+	data, err = io.ReadAll(pdfResp.Body)
+	s.Require().NoError(err)
+	s.True(bytes.HasPrefix(data, []byte("%PDF")))
+}
+
+func (s *OrderCustomerStatementSuite) TestStatement_Validation() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	for _, query := range []string{"?from=02-01-2026", "?from=2026-02-10&to=2026-02-01", "?format=xlsx"} {
+		resp := s.get(fx, query)
+		resp.Body.Close()
+		s.Equal(http.StatusBadRequest, resp.StatusCode, query)
+	}
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+fx.biz.Descriptor+"/customers/cus_missing/statement", nil, fx.owner.Token)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func TestOrderCustomerStatementSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(OrderCustomerStatementSuite))
+}
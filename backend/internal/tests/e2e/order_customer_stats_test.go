@@ -0,0 +1,160 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var customerStatsTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items",
+}
+
+// OrderCustomerStatsSuite tests the per-customer purchase stats endpoint.
+type OrderCustomerStatsSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *OrderCustomerStatsSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *OrderCustomerStatsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, customerStatsTables...))
+}
+
+func (s *OrderCustomerStatsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, customerStatsTables...))
+}
+
+type customerStatsFixture struct {
+	owner *testutils.Owner
+	biz   *business.Business
+	cust  *customer.Customer
+	mug   *inventory.Variant
+	tee   *inventory.Variant
+}
+
+func (s *OrderCustomerStatsSuite) setup(ctx context.Context) *customerStatsFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	mugProduct, err := s.factory.Product(ctx, biz.ID, func(p *inventory.Product) { p.Name = "Mug" })
+	s.Require().NoError(err)
+	mug, err := s.factory.Variant(ctx, mugProduct)
+	s.Require().NoError(err)
+	teeProduct, err := s.factory.Product(ctx, biz.ID, func(p *inventory.Product) { p.Name = "Tee" })
+	s.Require().NoError(err)
+	tee, err := s.factory.Variant(ctx, teeProduct)
+	s.Require().NoError(err)
+	return &customerStatsFixture{owner: owner, biz: biz, cust: cust, mug: mug, tee: tee}
+}
+
+func (s *OrderCustomerStatsSuite) order(ctx context.Context, fx *customerStatsFixture, lines []testutils.OrderLine, opts ...testutils.Option[order.Order]) {
+	opts = append([]testutils.Option[order.Order]{func(o *order.Order) { o.CustomerID = fx.cust.ID }}, opts...)
+	_, err := s.factory.Order(ctx, fx.biz, lines, opts...)
+	s.Require().NoError(err)
+}
+
+func (s *OrderCustomerStatsSuite) stats(fx *customerStatsFixture, customerID string) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+fx.biz.Descriptor+"/customers/"+customerID+"/stats", nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func (s *OrderCustomerStatsSuite) TestStatsSummarizePurchaseHistory() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	first := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	last := time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC)
+
+	s.order(ctx, fx, []testutils.OrderLine{{Variant: fx.mug, Quantity: 2}}, func(o *order.Order) {
+		o.OrderedAt = first
+		o.Channel = "instagram"
+	})
+	s.order(ctx, fx, []testutils.OrderLine{{Variant: fx.mug, Quantity: 1}, {Variant: fx.tee, Quantity: 1}}, func(o *order.Order) {
+		o.OrderedAt = first.AddDate(0, 1, 0)
+		o.Channel = "whatsapp"
+	})
+	s.order(ctx, fx, []testutils.OrderLine{{Variant: fx.tee, Quantity: 1}}, func(o *order.Order) {
+		o.OrderedAt = last
+		o.Channel = "whatsapp"
+	})
+	// cancelled orders do not count
+	s.order(ctx, fx, []testutils.OrderLine{{Variant: fx.tee, Quantity: 5}}, func(o *order.Order) {
+		o.OrderedAt = last.AddDate(0, 0, 1)
+		o.Channel = "instagram"
+		o.Status = order.OrderStatusCancelled
+	})
+
+	status, body := s.stats(fx, fx.cust.ID)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(fx.cust.ID, body["customerId"])
+	s.Equal(fx.biz.Currency, body["currency"])
+	s.Equal(float64(3), body["ordersCount"])
+	s.Equal("500", body["lifetimeValue"])
+	s.Equal("166.67", body["averageOrderValue"])
+	firstAt, err := time.Parse(time.RFC3339, body["firstOrderAt"].(string))
+	s.Require().NoError(err)
+	s.True(first.Equal(firstAt), firstAt)
+	lastAt, err := time.Parse(time.RFC3339, body["lastOrderAt"].(string))
+	s.Require().NoError(err)
+	s.True(last.Equal(lastAt), lastAt)
+	s.Equal("whatsapp", body["preferredChannel"])
+
+	products := body["favoriteProducts"].([]interface{})
+	s.Require().Len(products, 2)
+	top := products[0].(map[string]interface{})
+	s.Equal(fx.mug.ProductID, top["productId"])
+	s.Equal("Mug", top["productName"])
+	s.Equal(float64(3), top["quantity"])
+	s.Equal(float64(2), top["ordersCount"])
+	s.Equal("300", top["revenue"])
+}
+
+func (s *OrderCustomerStatsSuite) TestStatsWithoutOrders() {
+	fx := s.setup(context.Background())
+
+	status, body := s.stats(fx, fx.cust.ID)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(float64(0), body["ordersCount"])
+	s.Equal("0", body["lifetimeValue"])
+	s.Equal("0", body["averageOrderValue"])
+	s.Nil(body["firstOrderAt"])
+	s.Nil(body["lastOrderAt"])
+	s.Empty(body["preferredChannel"])
+	s.Empty(body["favoriteProducts"])
+}
+
+func (s *OrderCustomerStatsSuite) TestStatsUnknownCustomer() {
+	fx := s.setup(context.Background())
+
+	status, body := s.stats(fx, "cus_missing")
+	s.Equal(http.StatusNotFound, status, body)
+}
+
+func TestOrderCustomerStatsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(OrderCustomerStatsSuite))
+}
@@ -0,0 +1,136 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var customerTimelineTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "customer_notes",
+	"orders", "order_items", "order_events", "order_payments",
+}
+
+// OrderCustomerTimelineSuite tests the per-customer activity timeline endpoint.
+type OrderCustomerTimelineSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *OrderCustomerTimelineSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *OrderCustomerTimelineSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, customerTimelineTables...))
+}
+
+func (s *OrderCustomerTimelineSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, customerTimelineTables...))
+}
+
+func (s *OrderCustomerTimelineSuite) setup(ctx context.Context) (*testutils.Owner, *business.Business, *customer.Customer) {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	return owner, biz, cust
+}
+
+func (s *OrderCustomerTimelineSuite) get(owner *testutils.Owner, biz *business.Business, customerID, query string) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+biz.Descriptor+"/customers/"+customerID+"/timeline"+query, nil, owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func (s *OrderCustomerTimelineSuite) TestMergesActivityNewestFirst() {
+	ctx := context.Background()
+	owner, biz, cust := s.setup(ctx)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+
+	_, err = s.factory.Address(ctx, cust.ID, func(a *customer.CustomerAddress) {
+		a.City = "Dubai"
+		a.CreatedAt = time.Now().UTC().Add(-3 * time.Hour)
+		a.UpdatedAt = a.CreatedAt
+	})
+	s.Require().NoError(err)
+	ord, err := s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: v, Quantity: 1}}, func(o *order.Order) {
+		o.CustomerID = cust.ID
+		o.OrderedAt = time.Now().UTC().Add(-2 * time.Hour)
+	})
+	s.Require().NoError(err)
+
+	resp, err := s.helper.Client.AuthenticatedRequest("POST", "/v1/businesses/"+biz.Descriptor+"/customers/"+cust.ID+"/notes", map[string]interface{}{"content": "Prefers evening delivery"}, owner.Token)
+	s.Require().NoError(err)
+	resp.Body.Close()
+	s.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	status, body := s.get(owner, biz, cust.ID, "")
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(float64(3), body["totalCount"])
+	items := body["items"].([]interface{})
+	s.Require().Len(items, 3)
+
+	note := items[0].(map[string]interface{})
+	s.Equal("note_added", note["type"])
+	s.Equal("Prefers evening delivery", note["content"])
+
+	placed := items[1].(map[string]interface{})
+	s.Equal("order_placed", placed["type"])
+	s.Equal(ord.ID, placed["orderId"])
+	s.Equal(ord.OrderNumber, placed["orderNumber"])
+	s.Equal(ord.Total.String(), placed["amount"])
+
+	addr := items[2].(map[string]interface{})
+	s.Equal("address_added", addr["type"])
+	s.Contains(addr["location"], "Dubai")
+
+	status, body = s.get(owner, biz, cust.ID, "?page=2&pageSize=2")
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Len(body["items"], 1)
+	s.Equal(false, body["hasMore"])
+}
+
+func (s *OrderCustomerTimelineSuite) TestUnknownCustomerAndOtherBusiness() {
+	ctx := context.Background()
+	owner, biz, _ := s.setup(ctx)
+	otherOwner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	otherBiz, err := s.factory.Business(ctx, otherOwner.Workspace.ID)
+	s.Require().NoError(err)
+	otherCust, err := s.factory.Customer(ctx, otherBiz.ID)
+	s.Require().NoError(err)
+
+	status, body := s.get(owner, biz, "cus_missing", "")
+	s.Equal(http.StatusNotFound, status, body)
+	status, body = s.get(owner, biz, otherCust.ID, "")
+	s.Equal(http.StatusNotFound, status, body)
+	status, body = s.get(owner, biz, otherCust.ID, "?pageSize=500")
+	s.Equal(http.StatusBadRequest, status, body)
+}
+
+func TestOrderCustomerTimelineSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(OrderCustomerTimelineSuite))
+}
@@ -0,0 +1,217 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var orderDeliveryTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants", "delivery_windows",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes", "order_events", "order_payments",
+}
+
+// OrderDeliverySuite tests delivery windows, delivery slot booking and the daily manifest.
+type OrderDeliverySuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *OrderDeliverySuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *OrderDeliverySuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, orderDeliveryTables...))
+}
+
+func (s *OrderDeliverySuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, orderDeliveryTables...))
+}
+
+type deliveryFixture struct {
+	owner   *testutils.Owner
+	biz     *business.Business
+	variant *inventory.Variant
+}
+
+func (s *OrderDeliverySuite) setup(ctx context.Context) deliveryFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	return deliveryFixture{owner: owner, biz: biz, variant: v}
+}
+
+func (s *OrderDeliverySuite) order(ctx context.Context, fx deliveryFixture, status order.OrderStatus) *order.Order {
+	ord, err := s.factory.Order(ctx, fx.biz, []testutils.OrderLine{{Variant: fx.variant, Quantity: 1}}, func(o *order.Order) {
+		o.Status = status
+	})
+	s.Require().NoError(err)
+	return ord
+}
+
+func (s *OrderDeliverySuite) do(fx deliveryFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func (s *OrderDeliverySuite) createWindow(fx deliveryFixture, weekday int, start, end string, capacity int) string {
+	status, body := s.do(fx, "POST", "/delivery-windows", map[string]interface{}{
+		"weekday": weekday, "startTime": start, "endTime": end, "capacity": capacity,
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	return body["id"].(string)
+}
+
+func (s *OrderDeliverySuite) problemCode(body map[string]interface{}) interface{} {
+	return body["extensions"].(map[string]interface{})["code"]
+}
+
+func (s *OrderDeliverySuite) TestDeliveryWindows_RejectOverlapAndBadTimes() {
+	fx := s.setup(context.Background())
+	s.createWindow(fx, 6, "9:00", "12:00", 5)
+
+	status, body := s.do(fx, "POST", "/delivery-windows", map[string]interface{}{
+		"weekday": 6, "startTime": "11:00", "endTime": "14:00", "capacity": 5,
+	})
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("business.delivery_window_overlap", s.problemCode(body))
+
+	status, body = s.do(fx, "POST", "/delivery-windows", map[string]interface{}{
+		"weekday": 6, "startTime": "15:00", "endTime": "13:00", "capacity": 5,
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("business.invalid_delivery_window", s.problemCode(body))
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+fx.biz.Descriptor+"/delivery-windows", nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var windows []map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &windows))
+	s.Require().Len(windows, 1)
+	s.Equal("09:00", windows[0]["startTime"])
+}
+
+func (s *OrderDeliverySuite) TestScheduleDelivery_EnforcesCapacity() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	day := today().AddDate(0, 0, 7)
+	dayLabel := day.Format("2006-01-02")
+	windowID := s.createWindow(fx, int(day.Weekday()), "09:00", "12:00", 1)
+
+	first := s.order(ctx, fx, order.OrderStatusPlaced)
+	status, body := s.do(fx, "PUT", "/orders/"+first.ID+"/delivery-slot", map[string]interface{}{"windowId": windowID, "date": dayLabel})
+	s.Require().Equal(http.StatusOK, status, body)
+	slot := body["deliverySlot"].(map[string]interface{})
+	s.Equal(dayLabel, slot["date"])
+	s.Equal("09:00", slot["startTime"])
+
+	// Booking the same slot again is a no-op.
+	status, body = s.do(fx, "PUT", "/orders/"+first.ID+"/delivery-slot", map[string]interface{}{"windowId": windowID, "date": dayLabel})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	second := s.order(ctx, fx, order.OrderStatusPending)
+	status, body = s.do(fx, "PUT", "/orders/"+second.ID+"/delivery-slot", map[string]interface{}{"windowId": windowID, "date": dayLabel})
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("order.delivery_slot_full", s.problemCode(body))
+
+	// Freeing the seat lets the other order take it.
+	status, body = s.do(fx, "DELETE", "/orders/"+first.ID+"/delivery-slot", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Nil(body["deliverySlot"])
+	status, body = s.do(fx, "PUT", "/orders/"+second.ID+"/delivery-slot", map[string]interface{}{"windowId": windowID, "date": dayLabel})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+fx.biz.Descriptor+"/orders/"+first.ID+"/timeline", nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var events []map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &events))
+	s.Contains(eventTypes(events), string(order.OrderEventDeliveryScheduled))
+}
+
+func (s *OrderDeliverySuite) TestScheduleDelivery_RejectsInvalidDates() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	day := today().AddDate(0, 0, 7)
+	windowID := s.createWindow(fx, int(day.Weekday()), "09:00", "12:00", 5)
+	ord := s.order(ctx, fx, order.OrderStatusPlaced)
+
+	status, body := s.do(fx, "PUT", "/orders/"+ord.ID+"/delivery-slot", map[string]interface{}{"windowId": windowID, "date": day.AddDate(0, 0, 1).Format("2006-01-02")})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("order.invalid_delivery_date", s.problemCode(body))
+
+	status, body = s.do(fx, "PUT", "/orders/"+ord.ID+"/delivery-slot", map[string]interface{}{"windowId": windowID, "date": day.AddDate(0, 0, -14).Format("2006-01-02")})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("order.invalid_delivery_date", s.problemCode(body))
+
+	shipped := s.order(ctx, fx, order.OrderStatusShipped)
+	status, body = s.do(fx, "PUT", "/orders/"+shipped.ID+"/delivery-slot", map[string]interface{}{"windowId": windowID, "date": day.Format("2006-01-02")})
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("order.delivery_not_schedulable", s.problemCode(body))
+}
+
+func (s *OrderDeliverySuite) TestSlotsAndManifest() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	day := today().AddDate(0, 0, 7)
+	dayLabel := day.Format("2006-01-02")
+	afternoon := s.createWindow(fx, int(day.Weekday()), "14:00", "17:00", 3)
+	morning := s.createWindow(fx, int(day.Weekday()), "09:00", "12:00", 2)
+
+	for _, windowID := range []string{afternoon, morning, morning} {
+		ord := s.order(ctx, fx, order.OrderStatusPlaced)
+		status, body := s.do(fx, "PUT", "/orders/"+ord.ID+"/delivery-slot", map[string]interface{}{"windowId": windowID, "date": dayLabel})
+		s.Require().Equal(http.StatusOK, status, body)
+	}
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+fx.biz.Descriptor+"/orders/delivery-slots?from="+dayLabel+"&to="+dayLabel, nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var slots []map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &slots))
+	s.Require().Len(slots, 2)
+	s.Equal(morning, slots[0]["windowId"])
+	s.Equal(float64(0), slots[0]["remaining"])
+	s.Equal(afternoon, slots[1]["windowId"])
+	s.Equal(float64(2), slots[1]["remaining"])
+
+	status, body := s.do(fx, "GET", "/orders/delivery-manifest?date="+dayLabel, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(float64(3), body["orders"])
+	manifestSlots := body["slots"].([]interface{})
+	s.Require().Len(manifestSlots, 2)
+	first := manifestSlots[0].(map[string]interface{})
+	s.Equal("09:00", first["startTime"])
+	s.Len(first["orders"], 2)
+
+	status, body = s.do(fx, "GET", "/orders/delivery-slots?from="+dayLabel+"&to="+day.AddDate(0, 0, 40).Format("2006-01-02"), nil)
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("order.invalid_delivery_slots_range", s.problemCode(body))
+}
+
+func TestOrderDeliverySuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(OrderDeliverySuite))
+}
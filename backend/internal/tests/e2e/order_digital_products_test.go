@@ -0,0 +1,199 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/platform/types/asset"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var digitalProductTables = append([]string{"shipping_zones", "stock_movements"}, costLayerTables...)
+
+// OrderDigitalProductsSuite tests ordering digital and service products: no stock moves, no
+// shipping fee, and downloads handed out once the order is paid.
+type OrderDigitalProductsSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *OrderDigitalProductsSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *OrderDigitalProductsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, digitalProductTables...))
+}
+
+func (s *OrderDigitalProductsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, digitalProductTables...))
+}
+
+type digitalProductFixture struct {
+	owner    *testutils.Owner
+	biz      *business.Business
+	cust     *customer.Customer
+	addr     *customer.CustomerAddress
+	physical *inventory.Variant
+	digital  *inventory.Variant
+	service  *inventory.Variant
+	zoneID   string
+}
+
+func (s *OrderDigitalProductsSuite) setup(ctx context.Context) *digitalProductFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	addr, err := s.factory.Address(ctx, cust.ID)
+	s.Require().NoError(err)
+	fx := &digitalProductFixture{owner: owner, biz: biz, cust: cust, addr: addr}
+
+	variantOf := func(opt testutils.Option[inventory.Product]) *inventory.Variant {
+		prod, err := s.factory.Product(ctx, biz.ID, opt)
+		s.Require().NoError(err)
+		v, err := s.factory.Variant(ctx, prod)
+		s.Require().NoError(err)
+		return v
+	}
+	fx.physical = variantOf(func(p *inventory.Product) { p.Type = inventory.ProductTypePhysical })
+	fx.digital = variantOf(func(p *inventory.Product) {
+		p.Type = inventory.ProductTypeDigital
+		p.Download = &asset.AssetReference{URL: "https://cdn.example.com/files/guide.pdf"}
+	})
+	fx.service = variantOf(func(p *inventory.Product) { p.Type = inventory.ProductTypeService })
+
+	status, zone := s.do(fx, "POST", "/shipping-zones", map[string]interface{}{
+		"name":         "Egypt",
+		"countries":    []string{"EG"},
+		"shippingCost": "25",
+	})
+	s.Require().Equal(http.StatusCreated, status, zone)
+	fx.zoneID = zone["id"].(string)
+	return fx
+}
+
+func (s *OrderDigitalProductsSuite) do(fx *digitalProductFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *OrderDigitalProductsSuite) stockOf(v *inventory.Variant) int {
+	var stock int
+	s.Require().NoError(testEnv.Database.GetDB().Raw("SELECT stock_quantity FROM variants WHERE id = ?", v.ID).Scan(&stock).Error)
+	return stock
+}
+
+func (s *OrderDigitalProductsSuite) createOrder(fx *digitalProductFixture, variants ...*inventory.Variant) map[string]interface{} {
+	items := make([]map[string]interface{}, len(variants))
+	for i, v := range variants {
+		items[i] = map[string]interface{}{"variantId": v.ID, "quantity": 2, "unitPrice": "100"}
+	}
+	status, body := s.do(fx, "POST", "/orders", map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"shippingZoneId":    fx.zoneID,
+		"channel":           "instagram",
+		"items":             items,
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	return body
+}
+
+func (s *OrderDigitalProductsSuite) TestDigitalOrderSkipsStockAndShipping() {
+	fx := s.setup(context.Background())
+
+	ord := s.createOrder(fx, fx.digital, fx.service)
+	s.Equal(true, ord["shippingExempt"])
+	s.Equal("0", ord["shippingFee"])
+	s.Nil(ord["shippingZoneId"])
+	s.Equal(10, s.stockOf(fx.digital))
+	s.Equal(10, s.stockOf(fx.service))
+
+	// swapping in a physical item ships the order again and takes it out of stock
+	status, body := s.do(fx, "PATCH", "/orders/"+ord["id"].(string), map[string]interface{}{
+		"shippingZoneId": fx.zoneID,
+		"items":          []map[string]interface{}{{"variantId": fx.physical.ID, "quantity": 2, "unitPrice": "100"}},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(false, body["shippingExempt"])
+	s.Equal("25", body["shippingFee"])
+	s.Equal(8, s.stockOf(fx.physical))
+
+	// deleting the order restocks the physical item only
+	status, body = s.do(fx, "DELETE", "/orders/"+ord["id"].(string), nil)
+	s.Require().Equal(http.StatusNoContent, status, body)
+	s.Equal(10, s.stockOf(fx.physical))
+	s.Equal(10, s.stockOf(fx.digital))
+}
+
+func (s *OrderDigitalProductsSuite) TestMixedOrderShipsPhysicalItems() {
+	fx := s.setup(context.Background())
+
+	ord := s.createOrder(fx, fx.physical, fx.digital)
+	s.Equal(false, ord["shippingExempt"])
+	s.Equal("25", ord["shippingFee"])
+	s.Equal(8, s.stockOf(fx.physical))
+	s.Equal(10, s.stockOf(fx.digital))
+}
+
+func (s *OrderDigitalProductsSuite) TestDownloadsAvailableOncePaid() {
+	fx := s.setup(context.Background())
+	ord := s.createOrder(fx, fx.digital, fx.service)
+	orderID := ord["id"].(string)
+
+	status, body := s.do(fx, "GET", "/orders/"+orderID+"/downloads", nil)
+	s.Equal(http.StatusConflict, status, body)
+
+	status, body = s.do(fx, "PATCH", "/orders/"+orderID+"/status", map[string]interface{}{"status": "placed"})
+	s.Require().Equal(http.StatusOK, status, body)
+	status, body = s.do(fx, "PATCH", "/orders/"+orderID+"/payment-status", map[string]interface{}{"paymentStatus": "paid"})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+fx.biz.Descriptor+"/orders/"+orderID+"/downloads", nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var downloads []map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &downloads))
+	s.Require().Len(downloads, 1)
+	s.Equal(fx.digital.ID, downloads[0]["variantId"])
+	s.Equal("https://cdn.example.com/files/guide.pdf", downloads[0]["download"].(map[string]interface{})["url"])
+}
+
+func (s *OrderDigitalProductsSuite) TestDownloadRequiresDigitalProduct() {
+	fx := s.setup(context.Background())
+	cat, err := s.factory.Category(context.Background(), fx.biz.ID)
+	s.Require().NoError(err)
+
+	status, body := s.do(fx, "POST", "/inventory/products", map[string]interface{}{
+		"name":       "Workshop",
+		"categoryId": cat.ID,
+		"type":       "service",
+		"download":   map[string]interface{}{"url": "https://cdn.example.com/files/guide.pdf"},
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("inventory.download_requires_digital_product", body["extensions"].(map[string]interface{})["code"])
+}
+
+func TestOrderDigitalProductsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(OrderDigitalProductsSuite))
+}
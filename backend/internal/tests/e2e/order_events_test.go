@@ -0,0 +1,196 @@
+package e2e_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/bus"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var orderEventsTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items", "order_events", "order_transition_rules",
+}
+
+// orderEventTopics are the lifecycle events other domains (accounting, ledger, tasks,
+// reviews) react to.
+var orderEventTopics = []bus.Topic{bus.OrderPaidTopic, bus.OrderFulfilledTopic, bus.OrderCancelledTopic}
+
+// OrderEventsSuite checks which order transitions emit order.paid, order.fulfilled and
+// order.cancelled. It drives an order service wired to its own bus so the events can be
+// counted.
+type OrderEventsSuite struct {
+	suite.Suite
+	factory *testutils.Factory
+	bus     *bus.Bus
+	svc     *order.Service
+
+	mu     sync.Mutex
+	counts map[bus.Topic]int
+}
+
+func (s *OrderEventsSuite) SetupSuite() {
+	s.factory = testutils.NewFactory(testEnv.Database)
+	s.bus = bus.New()
+	for _, topic := range orderEventTopics {
+		s.bus.Listen(topic, func(any) {
+			s.mu.Lock()
+			s.counts[topic]++
+			s.mu.Unlock()
+		})
+	}
+	db, atomicProcessor := testEnv.Database, database.NewAtomicProcess(testEnv.Database)
+	inventorySvc := inventory.NewService(inventory.NewStorage(db, testEnv.Cache), atomicProcessor, s.bus)
+	customerSvc := customer.NewService(customer.NewStorage(db, testEnv.Cache), atomicProcessor, s.bus, inventorySvc)
+	businessSvc := business.NewService(business.NewStorage(db, testEnv.Cache), atomicProcessor, s.bus)
+	s.svc = order.NewService(order.NewStorage(db, testEnv.Cache), atomicProcessor, s.bus, inventorySvc, customerSvc, businessSvc)
+}
+
+func (s *OrderEventsSuite) TearDownSuite() {
+	s.bus.Close()
+}
+
+func (s *OrderEventsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, orderEventsTables...))
+	s.mu.Lock()
+	s.counts = make(map[bus.Topic]int)
+	s.mu.Unlock()
+}
+
+func (s *OrderEventsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, orderEventsTables...))
+}
+
+func (s *OrderEventsSuite) newOrder(ctx context.Context) (*testutils.Owner, *business.Business, *order.Order) {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	product, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	variant, err := s.factory.Variant(ctx, product)
+	s.Require().NoError(err)
+	ord, err := s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: variant, Quantity: 1}})
+	s.Require().NoError(err)
+	return owner, biz, ord
+}
+
+// expectEvents waits for the wanted event counts and then checks no other event arrives.
+func (s *OrderEventsSuite) expectEvents(want map[bus.Topic]int) {
+	snapshot := func() map[bus.Topic]int {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		got := make(map[bus.Topic]int, len(s.counts))
+		for topic, n := range s.counts {
+			got[topic] = n
+		}
+		return got
+	}
+	matches := func() bool {
+		got := snapshot()
+		for _, topic := range orderEventTopics {
+			if got[topic] != want[topic] {
+				return false
+			}
+		}
+		return true
+	}
+	s.Eventually(matches, 2*time.Second, 10*time.Millisecond, "events: %v", snapshot())
+	s.Never(func() bool { return !matches() }, 300*time.Millisecond, 10*time.Millisecond, "events: %v", snapshot())
+}
+
+func (s *OrderEventsSuite) TestUpdateOrderStatus_EmitsFulfilledOnce() {
+	ctx := context.Background()
+	owner, biz, ord := s.newOrder(ctx)
+
+	for _, status := range []order.OrderStatus{order.OrderStatusPlaced, order.OrderStatusReadyForShipment, order.OrderStatusShipped} {
+		_, err := s.svc.UpdateOrderStatus(ctx, owner.User, biz, ord.ID, status)
+		s.Require().NoError(err)
+	}
+	s.expectEvents(nil)
+
+	_, err := s.svc.UpdateOrderStatus(ctx, owner.User, biz, ord.ID, order.OrderStatusFulfilled)
+	s.Require().NoError(err)
+	s.expectEvents(map[bus.Topic]int{bus.OrderFulfilledTopic: 1})
+
+	// a refused transition emits nothing
+	_, err = s.svc.UpdateOrderStatus(ctx, owner.User, biz, ord.ID, order.OrderStatusFulfilled)
+	s.Require().Error(err)
+	s.expectEvents(map[bus.Topic]int{bus.OrderFulfilledTopic: 1})
+}
+
+func (s *OrderEventsSuite) TestUpdateOrderStatus_EmitsCancelledOnce() {
+	ctx := context.Background()
+	owner, biz, ord := s.newOrder(ctx)
+
+	_, err := s.svc.UpdateOrderStatus(ctx, owner.User, biz, ord.ID, order.OrderStatusPlaced)
+	s.Require().NoError(err)
+	_, err = s.svc.UpdateOrderStatus(ctx, owner.User, biz, ord.ID, order.OrderStatusCancelled)
+	s.Require().NoError(err)
+	s.expectEvents(map[bus.Topic]int{bus.OrderCancelledTopic: 1})
+
+	_, err = s.svc.UpdateOrderStatus(ctx, owner.User, biz, ord.ID, order.OrderStatusCancelled)
+	s.Require().Error(err)
+	s.expectEvents(map[bus.Topic]int{bus.OrderCancelledTopic: 1})
+}
+
+func (s *OrderEventsSuite) TestUpdateOrderPaymentStatus_EmitsPaidOnce() {
+	ctx := context.Background()
+	owner, biz, ord := s.newOrder(ctx)
+
+	_, err := s.svc.UpdateOrderStatus(ctx, owner.User, biz, ord.ID, order.OrderStatusPlaced)
+	s.Require().NoError(err)
+	_, err = s.svc.UpdateOrderPaymentStatus(ctx, owner.User, biz, ord.ID, order.OrderPaymentStatusFailed)
+	s.Require().NoError(err)
+	_, err = s.svc.UpdateOrderPaymentStatus(ctx, owner.User, biz, ord.ID, order.OrderPaymentStatusPending)
+	s.Require().NoError(err)
+	s.expectEvents(nil)
+
+	_, err = s.svc.UpdateOrderPaymentStatus(ctx, owner.User, biz, ord.ID, order.OrderPaymentStatusPaid)
+	s.Require().NoError(err)
+	s.expectEvents(map[bus.Topic]int{bus.OrderPaidTopic: 1})
+
+	// paying twice is refused and emits nothing more; refunds are not order.paid
+	_, err = s.svc.UpdateOrderPaymentStatus(ctx, owner.User, biz, ord.ID, order.OrderPaymentStatusPaid)
+	s.Require().Error(err)
+	_, err = s.svc.UpdateOrderPaymentStatus(ctx, owner.User, biz, ord.ID, order.OrderPaymentStatusRefunded)
+	s.Require().NoError(err)
+	s.expectEvents(map[bus.Topic]int{bus.OrderPaidTopic: 1})
+}
+
+func (s *OrderEventsSuite) TestExpirePendingOrders_EmitsCancelledOnce() {
+	ctx := context.Background()
+	_, biz, ord := s.newOrder(ctx)
+
+	db := testEnv.Database.GetDB()
+	s.Require().NoError(db.Model(&business.Business{}).Where("id = ?", biz.ID).Update("pending_order_ttl_hours", 1).Error)
+	s.Require().NoError(db.Model(&order.Order{}).Where("id = ?", ord.ID).Update("created_at", time.Now().Add(-2*time.Hour)).Error)
+
+	result, err := s.svc.ExpirePendingOrders(ctx, order.ExpirePendingOrdersOptions{BusinessID: biz.ID})
+	s.Require().NoError(err)
+	s.Equal(1, result.Expired)
+	s.expectEvents(map[bus.Topic]int{bus.OrderCancelledTopic: 1})
+
+	// nothing is left to expire on the next run
+	result, err = s.svc.ExpirePendingOrders(ctx, order.ExpirePendingOrdersOptions{BusinessID: biz.ID})
+	s.Require().NoError(err)
+	s.Equal(0, result.Expired)
+	s.expectEvents(map[bus.Topic]int{bus.OrderCancelledTopic: 1})
+}
+
+func TestOrderEventsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(OrderEventsSuite))
+}
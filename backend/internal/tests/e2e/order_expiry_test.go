@@ -0,0 +1,184 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var orderExpiryTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes", "order_events",
+}
+
+// OrderExpirySuite tests the expiry of stale pending orders.
+type OrderExpirySuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *OrderExpirySuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *OrderExpirySuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, orderExpiryTables...))
+}
+
+func (s *OrderExpirySuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, orderExpiryTables...))
+}
+
+type orderExpiryFixture struct {
+	owner   *testutils.Owner
+	biz     *business.Business
+	cust    *customer.Customer
+	addr    *customer.CustomerAddress
+	variant *inventory.Variant
+}
+
+// setup creates a business with a customer and a variant with 10 in stock.
+func (s *OrderExpirySuite) setup(ctx context.Context) *orderExpiryFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	addr, err := s.factory.Address(ctx, cust.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	return &orderExpiryFixture{owner: owner, biz: biz, cust: cust, addr: addr, variant: v}
+}
+
+func (s *OrderExpirySuite) do(fx *orderExpiryFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+// createPendingOrder creates a pending order of qty items, created age ago.
+func (s *OrderExpirySuite) createPendingOrder(fx *orderExpiryFixture, qty int, age time.Duration) string {
+	status, body := s.do(fx, "POST", "/orders", map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"channel":           "whatsapp",
+		"items":             []map[string]interface{}{{"variantId": fx.variant.ID, "quantity": qty}},
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Require().Equal("pending", body["status"])
+	id := body["id"].(string)
+	s.Require().NoError(testEnv.Database.GetDB().Exec("UPDATE orders SET created_at = ? WHERE id = ?", time.Now().Add(-age), id).Error)
+	return id
+}
+
+func (s *OrderExpirySuite) stock(ctx context.Context, fx *orderExpiryFixture) int {
+	v, err := s.helper.GetVariant(ctx, fx.variant.ID)
+	s.Require().NoError(err)
+	return v.StockQuantity
+}
+
+func (s *OrderExpirySuite) TestExpirePendingOrders_CancelsStaleOrdersAndRestocks() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	status, body := s.do(fx, "PATCH", "", map[string]interface{}{"pendingOrderTtlHours": 24})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.EqualValues(24, body["pendingOrderTtlHours"])
+
+	stale := s.createPendingOrder(fx, 3, 25*time.Hour)
+	fresh := s.createPendingOrder(fx, 2, time.Hour)
+	s.Equal(5, s.stock(ctx, fx))
+
+	status, body = s.do(fx, "POST", "/orders/expire-pending", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.EqualValues(1, body["businesses"])
+	s.EqualValues(1, body["expired"])
+	s.EqualValues(0, body["failed"])
+	s.Equal(8, s.stock(ctx, fx))
+
+	status, body = s.do(fx, "GET", "/orders/"+stale, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("cancelled", body["status"])
+	s.NotNil(body["expiredAt"])
+	s.NotNil(body["cancelledAt"])
+
+	status, body = s.do(fx, "GET", "/orders/"+fresh, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("pending", body["status"])
+	s.Nil(body["expiredAt"])
+
+	// A second run finds nothing left to expire.
+	status, body = s.do(fx, "POST", "/orders/expire-pending", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.EqualValues(0, body["expired"])
+	s.Equal(8, s.stock(ctx, fx))
+
+	// Deleting the expired order does not put its items back in stock a second time.
+	status, _ = s.do(fx, "DELETE", "/orders/"+stale, nil)
+	s.Require().Equal(http.StatusNoContent, status)
+	s.Equal(8, s.stock(ctx, fx))
+}
+
+func (s *OrderExpirySuite) TestExpirePendingOrders_DisabledWithoutTTL() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	id := s.createPendingOrder(fx, 1, 30*24*time.Hour)
+
+	status, body := s.do(fx, "POST", "/orders/expire-pending", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.EqualValues(0, body["businesses"])
+	s.EqualValues(0, body["expired"])
+
+	status, body = s.do(fx, "GET", "/orders/"+id, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("pending", body["status"])
+	s.Equal(9, s.stock(ctx, fx))
+}
+
+func (s *OrderExpirySuite) TestExpirePendingOrders_LeavesPlacedOrders() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	s.Require().NoError(testEnv.Database.GetDB().Exec("UPDATE businesses SET pending_order_ttl_hours = 1 WHERE id = ?", fx.biz.ID).Error)
+
+	id := s.createPendingOrder(fx, 4, 2*time.Hour)
+	status, body := s.do(fx, "PATCH", "/orders/"+id+"/status", map[string]interface{}{"status": "placed"})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	status, body = s.do(fx, "POST", "/orders/expire-pending", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.EqualValues(0, body["expired"])
+	s.Equal(6, s.stock(ctx, fx))
+}
+
+func (s *OrderExpirySuite) TestUpdateBusiness_RejectsInvalidPendingOrderTTL() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	status, body := s.do(fx, "PATCH", "", map[string]interface{}{"pendingOrderTtlHours": -1})
+	s.Equal(http.StatusBadRequest, status, body)
+}
+
+func TestOrderExpirySuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(OrderExpirySuite))
+}
@@ -0,0 +1,186 @@
+package e2e_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var exportTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items",
+	"order_exports", "order_export_files",
+}
+
+// OrderExportsSuite tests background order exports to CSV and XLSX.
+type OrderExportsSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *OrderExportsSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *OrderExportsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, exportTables...))
+}
+
+func (s *OrderExportsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, exportTables...))
+}
+
+type exportFixture struct {
+	owner   *testutils.Owner
+	biz     *business.Business
+	variant *inventory.Variant
+}
+
+func (s *OrderExportsSuite) setup(ctx context.Context) *exportFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	return &exportFixture{owner: owner, biz: biz, variant: v}
+}
+
+func (s *OrderExportsSuite) do(fx *exportFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+// waitForExport polls the export until it leaves pending/processing.
+func (s *OrderExportsSuite) waitForExport(fx *exportFixture, exportID string) map[string]interface{} {
+	var body map[string]interface{}
+	s.Require().Eventually(func() bool {
+		var status int
+		status, body = s.do(fx, "GET", "/orders/exports/"+exportID, nil)
+		s.Require().Equal(http.StatusOK, status, body)
+		return body["status"] == "completed" || body["status"] == "failed"
+	}, 10*time.Second, 100*time.Millisecond)
+	return body
+}
+
+func (s *OrderExportsSuite) download(fx *exportFixture, url string) (*http.Response, []byte) {
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", url, nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	return resp, data
+}
+
+func (s *OrderExportsSuite) TestExportOrders_CSVWithFilters() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	lines := []testutils.OrderLine{{Variant: fx.variant, Quantity: 2}}
+	placed, err := s.factory.Order(ctx, fx.biz, lines, func(o *order.Order) { o.Status = order.OrderStatusPlaced })
+	s.Require().NoError(err)
+	_, err = s.factory.Order(ctx, fx.biz, lines)
+	s.Require().NoError(err)
+	_, err = s.factory.Order(ctx, fx.biz, lines, func(o *order.Order) {
+		o.Status = order.OrderStatusPlaced
+		o.OrderedAt = time.Now().AddDate(0, -2, 0)
+	})
+	s.Require().NoError(err)
+
+	status, export := s.do(fx, "POST", "/orders/export", map[string]interface{}{
+		"status": []string{"placed"},
+		"from":   time.Now().AddDate(0, -1, 0).UTC(),
+	})
+	s.Require().Equal(http.StatusAccepted, status, export)
+	s.Equal("pending", export["status"])
+	s.Equal("csv", export["format"])
+	s.Nil(export["downloadUrl"])
+
+	done := s.waitForExport(fx, export["id"].(string))
+	s.Require().Equal("completed", done["status"], done)
+	s.Equal(float64(1), done["rowCount"])
+
+	resp, data := s.download(fx, done["downloadUrl"].(string))
+	s.Require().Equal(http.StatusOK, resp.StatusCode, string(data))
+	s.Contains(resp.Header.Get("Content-Type"), "text/csv")
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	s.Require().NoError(err)
+	s.Require().Len(rows, 2)
+	s.Equal("orderNumber", rows[0][0])
+	s.Equal(placed.OrderNumber, rows[1][0])
+	s.Equal("placed", rows[1][2])
+	s.Equal("2", rows[1][10])
+
+	status, list := s.do(fx, "GET", "/orders/exports", nil)
+	s.Equal(http.StatusOK, status, list)
+}
+
+func (s *OrderExportsSuite) TestExportOrders_XLSX() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	_, err := s.factory.Order(ctx, fx.biz, []testutils.OrderLine{{Variant: fx.variant, Quantity: 1}})
+	s.Require().NoError(err)
+
+	status, export := s.do(fx, "POST", "/orders/export", map[string]interface{}{"format": "xlsx"})
+	s.Require().Equal(http.StatusAccepted, status, export)
+	done := s.waitForExport(fx, export["id"].(string))
+	s.Require().Equal("completed", done["status"], done)
+
+	resp, data := s.download(fx, done["downloadUrl"].(string))
+	s.Require().Equal(http.StatusOK, resp.StatusCode, string(data))
+	s.Contains(resp.Header.Get("Content-Type"), "spreadsheetml")
+	_, err = zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	s.NoError(err)
+}
+
+func (s *OrderExportsSuite) TestExportOrders_RejectsInvalidRange() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	status, body := s.do(fx, "POST", "/orders/export", map[string]interface{}{
+		"from": time.Now().UTC(),
+		"to":   time.Now().AddDate(0, 0, -1).UTC(),
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+}
+
+func (s *OrderExportsSuite) TestGetOrderExport_OtherBusinessNotFound() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	other := s.setup(ctx)
+
+	status, export := s.do(fx, "POST", "/orders/export", map[string]interface{}{})
+	s.Require().Equal(http.StatusAccepted, status, export)
+	s.waitForExport(fx, export["id"].(string))
+
+	status, body := s.do(other, "GET", "/orders/exports/"+export["id"].(string), nil)
+	s.Equal(http.StatusNotFound, status, body)
+	resp, _ := s.download(other, "/v1/businesses/"+other.biz.Descriptor+"/orders/exports/"+export["id"].(string)+"/download")
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func TestOrderExportsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(OrderExportsSuite))
+}
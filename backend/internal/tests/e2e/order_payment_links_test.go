@@ -0,0 +1,245 @@
+package e2e_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+var paymentLinkTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes",
+	"order_events", "order_payments", "stripe_events",
+}
+
+// OrderPaymentLinksSuite tests Stripe payment links for orders and their webhook.
+type OrderPaymentLinksSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *OrderPaymentLinksSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *OrderPaymentLinksSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, paymentLinkTables...))
+}
+
+func (s *OrderPaymentLinksSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, paymentLinkTables...))
+}
+
+type paymentLinkFixture struct {
+	owner   *testutils.Owner
+	biz     *business.Business
+	cust    *customer.Customer
+	addr    *customer.CustomerAddress
+	variant *inventory.Variant
+}
+
+func (s *OrderPaymentLinksSuite) setup(ctx context.Context) *paymentLinkFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	addr, err := s.factory.Address(ctx, cust.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	return &paymentLinkFixture{owner: owner, biz: biz, cust: cust, addr: addr, variant: v}
+}
+
+func (s *OrderPaymentLinksSuite) do(fx *paymentLinkFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *OrderPaymentLinksSuite) createOrder(fx *paymentLinkFixture) string {
+	status, body := s.do(fx, "POST", "/orders", map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"channel":           "instagram",
+		"items": []map[string]interface{}{
+			{"variantId": fx.variant.ID, "quantity": 1, "unitPrice": "100", "unitCost": "50"},
+		},
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	return body["id"].(string)
+}
+
+// sendCheckoutWebhook posts a signed checkout session event for the order's payment link.
+func (s *OrderPaymentLinksSuite) sendCheckoutWebhook(eventID, eventType string, fx *paymentLinkFixture, orderID, paymentStatus string) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"id":   eventID,
+		"type": eventType,
+		"data": map[string]interface{}{"object": map[string]interface{}{
+			"id":             "cs_test_" + eventID,
+			"object":         "checkout.session",
+			"mode":           "payment",
+			"payment_status": paymentStatus,
+			"payment_intent": "pi_" + eventID,
+			"metadata":       map[string]string{"order_id": orderID, "business_id": fx.biz.ID},
+		}},
+	})
+	s.Require().NoError(err)
+	resp, err := s.helper.Client.PostRaw("/webhooks/stripe", payload, map[string]string{
+		"Content-Type":     "application/json",
+		"Stripe-Signature": stripeTestSignatureHeader("whsec_test", payload, time.Now().Unix()),
+	})
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (s *OrderPaymentLinksSuite) TestCreatePaymentLink_StoresLink() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	orderID := s.createOrder(fx)
+
+	status, body := s.do(fx, "POST", "/orders/"+orderID+"/payment-link", map[string]interface{}{"successUrl": "https://shop.example.com/thanks"})
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.NotEmpty(body["paymentLinkUrl"])
+	s.Equal("pending", body["paymentStatus"])
+
+	status, body = s.do(fx, "POST", "/orders/"+orderID+"/payment-link", map[string]interface{}{"successUrl": "not a url"})
+	s.Equal(http.StatusBadRequest, status, body)
+}
+
+func (s *OrderPaymentLinksSuite) TestWebhook_MarksOrderPaid() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	orderID := s.createOrder(fx)
+	status, body := s.do(fx, "POST", "/orders/"+orderID+"/payment-link", map[string]interface{}{"successUrl": "https://shop.example.com/thanks"})
+	s.Require().Equal(http.StatusCreated, status, body)
+
+	s.sendCheckoutWebhook("evt_order_paid_1", "checkout.session.completed", fx, orderID, "paid")
+
+	var ord map[string]interface{}
+	s.Require().Eventually(func() bool {
+		_, ord = s.do(fx, "GET", "/orders/"+orderID, nil)
+		return ord["paymentStatus"] == "paid"
+	}, 5*time.Second, 100*time.Millisecond)
+	s.Equal("placed", ord["status"])
+	s.Equal("credit_card", ord["paymentMethod"])
+	s.Equal("pi_evt_order_paid_1", ord["paymentReference"])
+	s.Equal(ord["total"], ord["amountPaid"])
+	s.Equal("0", ord["amountDue"])
+	s.Require().Len(ord["payments"], 1)
+	payment := ord["payments"].([]interface{})[0].(map[string]interface{})
+	s.Equal("credit_card", payment["method"])
+	s.Equal("pi_evt_order_paid_1", payment["reference"])
+
+	// A paid order no longer takes payment links.
+	status, body = s.do(fx, "POST", "/orders/"+orderID+"/payment-link", map[string]interface{}{"successUrl": "https://shop.example.com/thanks"})
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("order.payment_link_not_allowed", body["extensions"].(map[string]interface{})["code"])
+}
+
+func (s *OrderPaymentLinksSuite) TestWebhook_ChargesAmountDueAfterDeposit() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	orderID := s.createOrder(fx)
+	status, body := s.do(fx, "POST", "/orders/"+orderID+"/payments", map[string]interface{}{"amount": "40", "method": "bank_transfer"})
+	s.Require().Equal(http.StatusCreated, status, body)
+	total := decimal.RequireFromString(body["total"].(string))
+	due := total.Sub(decimal.NewFromInt(40)).String()
+	s.Equal(due, body["amountDue"])
+	status, body = s.do(fx, "POST", "/orders/"+orderID+"/payment-link", map[string]interface{}{"successUrl": "https://shop.example.com/thanks"})
+	s.Require().Equal(http.StatusCreated, status, body)
+
+	s.sendCheckoutWebhook("evt_order_balance_1", "checkout.session.completed", fx, orderID, "paid")
+
+	var ord map[string]interface{}
+	s.Require().Eventually(func() bool {
+		_, ord = s.do(fx, "GET", "/orders/"+orderID, nil)
+		return ord["paymentStatus"] == "paid"
+	}, 5*time.Second, 100*time.Millisecond)
+	s.Equal(total.String(), ord["amountPaid"])
+	s.Equal("paid", ord["paymentBalance"])
+	s.Require().Len(ord["payments"], 2)
+	balance := ord["payments"].([]interface{})[1].(map[string]interface{})
+	s.Equal(due, balance["amount"])
+	s.Equal("credit_card", balance["method"])
+}
+
+func (s *OrderPaymentLinksSuite) TestCreatePaymentLink_RefusedWhenNothingDue() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	orderID := s.createOrder(fx)
+	_, ord := s.do(fx, "GET", "/orders/"+orderID, nil)
+	// Deposits of a pending order cover the total but keep it unpaid until it is placed.
+	status, body := s.do(fx, "POST", "/orders/"+orderID+"/payments", map[string]interface{}{"amount": ord["total"], "method": "bank_transfer"})
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Require().Equal("pending", body["paymentStatus"])
+
+	status, body = s.do(fx, "POST", "/orders/"+orderID+"/payment-link", map[string]interface{}{"successUrl": "https://shop.example.com/thanks"})
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("order.payment_link_not_allowed", body["extensions"].(map[string]interface{})["code"])
+}
+
+func (s *OrderPaymentLinksSuite) TestWebhook_IgnoresUnpaidSession() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	orderID := s.createOrder(fx)
+
+	s.sendCheckoutWebhook("evt_order_unpaid_1", "checkout.session.completed", fx, orderID, "unpaid")
+	time.Sleep(300 * time.Millisecond)
+
+	_, ord := s.do(fx, "GET", "/orders/"+orderID, nil)
+	s.Equal("pending", ord["paymentStatus"])
+	s.Equal("pending", ord["status"])
+
+	// Delayed payment methods complete later.
+	s.sendCheckoutWebhook("evt_order_async_1", "checkout.session.async_payment_succeeded", fx, orderID, "paid")
+	s.Require().Eventually(func() bool {
+		_, ord = s.do(fx, "GET", "/orders/"+orderID, nil)
+		return ord["paymentStatus"] == "paid"
+	}, 5*time.Second, 100*time.Millisecond)
+}
+
+func (s *OrderPaymentLinksSuite) TestWebhook_CancelledOrderStaysUnpaid() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	orderID := s.createOrder(fx)
+	status, body := s.do(fx, "PATCH", "/orders/"+orderID+"/status", map[string]interface{}{"status": "cancelled"})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	status, body = s.do(fx, "POST", "/orders/"+orderID+"/payment-link", map[string]interface{}{"successUrl": "https://shop.example.com/thanks"})
+	s.Equal(http.StatusConflict, status, body)
+
+	s.sendCheckoutWebhook("evt_order_cancelled_1", "checkout.session.completed", fx, orderID, "paid")
+	time.Sleep(300 * time.Millisecond)
+
+	_, ord := s.do(fx, "GET", "/orders/"+orderID, nil)
+	s.Equal("pending", ord["paymentStatus"])
+}
+
+func TestOrderPaymentLinksSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(OrderPaymentLinksSuite))
+}
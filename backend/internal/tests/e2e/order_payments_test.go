@@ -0,0 +1,237 @@
+package e2e_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var orderPaymentTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes", "order_events", "order_payments",
+}
+
+// OrderPaymentsSuite tests deposits and partial payments on orders.
+type OrderPaymentsSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *OrderPaymentsSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *OrderPaymentsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, orderPaymentTables...))
+}
+
+func (s *OrderPaymentsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, orderPaymentTables...))
+}
+
+// setup creates a business and an order of 2 x 100 in the given status.
+func (s *OrderPaymentsSuite) setup(ctx context.Context, status order.OrderStatus) (*testutils.Owner, *business.Business, *order.Order) {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	ord, err := s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: v, Quantity: 2}}, func(o *order.Order) {
+		o.Status = status
+		if status != order.OrderStatusPending {
+			o.PlacedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+		}
+	})
+	s.Require().NoError(err)
+	return owner, biz, ord
+}
+
+func (s *OrderPaymentsSuite) do(owner *testutils.Owner, biz *business.Business, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+biz.Descriptor+"/orders"+path, payload, owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func (s *OrderPaymentsSuite) TestDepositThenBalance_MarksOrderPaid() {
+	ctx := context.Background()
+	owner, biz, ord := s.setup(ctx, order.OrderStatusPlaced)
+
+	status, body := s.do(owner, biz, "POST", "/"+ord.ID+"/payments", map[string]interface{}{
+		"amount": "80", "method": "bank_transfer", "reference": "TRX-1", "note": "deposit",
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Equal("80", body["amountPaid"])
+	s.Equal("120", body["amountDue"])
+	s.Equal("partially_paid", body["paymentBalance"])
+	s.Equal("pending", body["paymentStatus"])
+	s.Len(body["payments"], 1)
+
+	status, body = s.do(owner, biz, "POST", "/"+ord.ID+"/payments", map[string]interface{}{
+		"amount": "120", "method": "cash_on_delivery", "reference": "COD-1",
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Equal("200", body["amountPaid"])
+	s.Equal("0", body["amountDue"])
+	s.Equal("paid", body["paymentBalance"])
+	s.Equal("paid", body["paymentStatus"])
+	s.Equal("cash_on_delivery", body["paymentMethod"])
+	s.Equal("COD-1", body["paymentReference"])
+	s.NotNil(body["paidAt"])
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+biz.Descriptor+"/orders/"+ord.ID+"/payments", nil, owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	var payments []map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &payments))
+	s.Require().Len(payments, 2)
+	s.Equal("80", payments[0]["amount"])
+	s.Equal("deposit", payments[0]["note"])
+
+	// Payments of a paid order are kept.
+	status, body = s.do(owner, biz, "DELETE", "/"+ord.ID+"/payments/"+payments[0]["id"].(string), nil)
+	s.Equal(http.StatusConflict, status)
+	s.Equal("order.payment_delete_not_allowed", body["extensions"].(map[string]interface{})["code"])
+}
+
+func (s *OrderPaymentsSuite) TestOverpayment() {
+	ctx := context.Background()
+	owner, biz, ord := s.setup(ctx, order.OrderStatusPlaced)
+
+	status, body := s.do(owner, biz, "POST", "/"+ord.ID+"/payments", map[string]interface{}{"amount": "250", "method": "bank_transfer"})
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Equal("overpaid", body["paymentBalance"])
+	s.Equal("0", body["amountDue"])
+	s.Equal("paid", body["paymentStatus"])
+}
+
+func (s *OrderPaymentsSuite) TestPendingOrderKeepsDepositUntilPlaced() {
+	ctx := context.Background()
+	owner, biz, ord := s.setup(ctx, order.OrderStatusPending)
+
+	status, body := s.do(owner, biz, "POST", "/"+ord.ID+"/payments", map[string]interface{}{"amount": "200", "method": "bank_transfer"})
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Equal("paid", body["paymentBalance"])
+	s.Equal("pending", body["paymentStatus"])
+
+	// Placing the order settles the deposit.
+	status, body = s.do(owner, biz, "PATCH", "/"+ord.ID+"/status", map[string]interface{}{"status": "placed"})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("placed", body["status"])
+	s.Equal("paid", body["paymentStatus"])
+	s.Equal("bank_transfer", body["paymentMethod"])
+	s.NotNil(body["paidAt"])
+}
+
+func (s *OrderPaymentsSuite) TestLowerTotalSettlesDeposit() {
+	ctx := context.Background()
+	owner, biz, ord := s.setup(ctx, order.OrderStatusPlaced)
+
+	status, body := s.do(owner, biz, "POST", "/"+ord.ID+"/payments", map[string]interface{}{"amount": "150", "method": "bank_transfer"})
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Equal("pending", body["paymentStatus"])
+
+	status, body = s.do(owner, biz, "PATCH", "/"+ord.ID, map[string]interface{}{
+		"items": []map[string]interface{}{{"variantId": ord.Items[0].VariantID, "quantity": 1, "unitPrice": "100"}},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("paid", body["paymentStatus"])
+	s.Equal("overpaid", body["paymentBalance"])
+}
+
+func (s *OrderPaymentsSuite) TestDeletePayment_RestoresBalance() {
+	ctx := context.Background()
+	owner, biz, ord := s.setup(ctx, order.OrderStatusPlaced)
+
+	status, body := s.do(owner, biz, "POST", "/"+ord.ID+"/payments", map[string]interface{}{"amount": "50", "method": "bank_transfer"})
+	s.Require().Equal(http.StatusCreated, status, body)
+	paymentID := body["payments"].([]interface{})[0].(map[string]interface{})["id"].(string)
+
+	status, body = s.do(owner, biz, "DELETE", "/"+ord.ID+"/payments/"+paymentID, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("0", body["amountPaid"])
+	s.Equal("unpaid", body["paymentBalance"])
+	s.Nil(body["payments"])
+
+	status, _ = s.do(owner, biz, "DELETE", "/"+ord.ID+"/payments/"+paymentID, nil)
+	s.Equal(http.StatusNotFound, status)
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+biz.Descriptor+"/orders/"+ord.ID+"/timeline", nil, owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var events []map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &events))
+	types := eventTypes(events)
+	s.Contains(types, "payment_recorded")
+	s.Contains(types, "payment_deleted")
+}
+
+func (s *OrderPaymentsSuite) TestRecordPayment_Validation() {
+	ctx := context.Background()
+	owner, biz, ord := s.setup(ctx, order.OrderStatusPlaced)
+
+	status, _ := s.do(owner, biz, "POST", "/"+ord.ID+"/payments", map[string]interface{}{"amount": "0", "method": "bank_transfer"})
+	s.Equal(http.StatusBadRequest, status)
+	status, _ = s.do(owner, biz, "POST", "/"+ord.ID+"/payments", map[string]interface{}{"amount": "10", "method": "cheque"})
+	s.Equal(http.StatusBadRequest, status)
+	status, body := s.do(owner, biz, "POST", "/"+ord.ID+"/payments", map[string]interface{}{
+		"amount": "10", "method": "bank_transfer", "paidAt": time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339),
+	})
+	s.Equal(http.StatusBadRequest, status)
+	s.Equal("order.payment_in_future", body["extensions"].(map[string]interface{})["code"])
+	// Credit cards are disabled by default.
+	status, _ = s.do(owner, biz, "POST", "/"+ord.ID+"/payments", map[string]interface{}{"amount": "10", "method": "credit_card"})
+	s.Equal(http.StatusBadRequest, status)
+}
+
+func (s *OrderPaymentsSuite) TestRecordPayment_RejectedForCancelledOrder() {
+	ctx := context.Background()
+	owner, biz, ord := s.setup(ctx, order.OrderStatusCancelled)
+
+	status, body := s.do(owner, biz, "POST", "/"+ord.ID+"/payments", map[string]interface{}{"amount": "10", "method": "bank_transfer"})
+	s.Equal(http.StatusConflict, status)
+	s.Equal("order.payment_not_allowed", body["extensions"].(map[string]interface{})["code"])
+}
+
+func (s *OrderPaymentsSuite) TestOrderMarkedPaidWithoutPayments() {
+	ctx := context.Background()
+	owner, biz, ord := s.setup(ctx, order.OrderStatusPlaced)
+
+	status, body := s.do(owner, biz, "PATCH", "/"+ord.ID+"/payment-status", map[string]interface{}{"paymentStatus": "paid"})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("paid", body["paymentBalance"])
+	s.Equal("0", body["amountDue"])
+	s.Equal("0", body["amountPaid"])
+}
+
+func (s *OrderPaymentsSuite) TestOtherBusinessOrderNotFound() {
+	ctx := context.Background()
+	_, _, ord := s.setup(ctx, order.OrderStatusPlaced)
+	owner, biz, _ := s.setup(ctx, order.OrderStatusPlaced)
+
+	status, _ := s.do(owner, biz, "POST", "/"+ord.ID+"/payments", map[string]interface{}{"amount": "10", "method": "bank_transfer"})
+	s.Equal(http.StatusNotFound, status)
+}
+
+func TestOrderPaymentsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(OrderPaymentsSuite))
+}
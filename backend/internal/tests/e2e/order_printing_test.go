@@ -0,0 +1,224 @@
+package e2e_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var orderPrintingTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes",
+	"tasks", "print_stations", "print_jobs",
+}
+
+// OrderPrintingSuite tests thermal receipts, pick tickets and the per-station print queue.
+type OrderPrintingSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *OrderPrintingSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *OrderPrintingSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, orderPrintingTables...))
+}
+
+func (s *OrderPrintingSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, orderPrintingTables...))
+}
+
+type orderPrintingFixture struct {
+	owner *testutils.Owner
+	biz   *business.Business
+	ord   *order.Order
+}
+
+// setup creates a business with one placed, unpaid order.
+func (s *OrderPrintingSuite) setup(ctx context.Context) *orderPrintingFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	ord, err := s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: v, Quantity: 2}}, func(o *order.Order) {
+		o.Status = order.OrderStatusPlaced
+	})
+	s.Require().NoError(err)
+	return &orderPrintingFixture{owner: owner, biz: biz, ord: ord}
+}
+
+func (s *OrderPrintingSuite) raw(fx *orderPrintingFixture, method, path string, payload interface{}) (int, http.Header, []byte) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	return resp.StatusCode, resp.Header, data
+}
+
+func (s *OrderPrintingSuite) do(fx *orderPrintingFixture, method, path string, payload interface{}, out interface{}) int {
+	status, _, data := s.raw(fx, method, path, payload)
+	if out != nil && len(data) > 0 {
+		s.Require().NoError(json.Unmarshal(data, out), string(data))
+	}
+	return status
+}
+
+func (s *OrderPrintingSuite) createStation(fx *orderPrintingFixture, payload map[string]interface{}) string {
+	var station map[string]interface{}
+	status := s.do(fx, "POST", "/print-stations", payload, &station)
+	s.Require().Equal(http.StatusCreated, status, station)
+	return station["id"].(string)
+}
+
+// waitForJobs polls the station until it has n queued jobs, since paid orders are queued by
+// an async bus handler.
+func (s *OrderPrintingSuite) waitForJobs(fx *orderPrintingFixture, stationID string, n int) []map[string]interface{} {
+	var jobs []map[string]interface{}
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		jobs = nil
+		status := s.do(fx, "GET", "/print-stations/"+stationID+"/jobs", nil, &jobs)
+		s.Require().Equal(http.StatusOK, status)
+		if len(jobs) == n {
+			return jobs
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return jobs
+}
+
+func (s *OrderPrintingSuite) TestPrintOrder_ReceiptAndPickTicket() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	status, header, data := s.raw(fx, "GET", "/orders/"+fx.ord.ID+"/print", nil)
+	s.Require().Equal(http.StatusOK, status, string(data))
+	s.Equal("application/vnd.escpos", header.Get("Content-Type"))
+	s.True(bytes.HasPrefix(data, []byte{0x1b, 0x40}), "starts with ESC @")
+	s.Contains(string(data), "Order #"+fx.ord.OrderNumber)
+	s.Contains(string(data), "TOTAL")
+
+	status, header, data = s.raw(fx, "GET", "/orders/"+fx.ord.ID+"/print?kind=pick_ticket&format=html", nil)
+	s.Require().Equal(http.StatusOK, status, string(data))
+	s.Contains(header.Get("Content-Type"), "text/html")
+	s.Contains(string(data), "PICK TICKET")
+	s.Contains(string(data), "x2")
+	s.NotContains(string(data), "TOTAL", "pick tickets carry no prices")
+
+	status, _, _ = s.raw(fx, "GET", "/orders/"+fx.ord.ID+"/print?format=pdf", nil)
+	s.Equal(http.StatusBadRequest, status)
+	status, _, _ = s.raw(fx, "GET", "/orders/ord_missing/print", nil)
+	s.Equal(http.StatusNotFound, status)
+}
+
+func (s *OrderPrintingSuite) TestDownloadInvoicePDF() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	s.Require().NoError(testEnv.Database.GetDB().Model(&order.Order{}).Where("id = ?", fx.ord.ID).
+		Update("payment_reference", "TRX-42").Error)
+
+	status, header, data := s.raw(fx, "GET", "/orders/"+fx.ord.ID+"/invoice.pdf", nil)
+	s.Require().Equal(http.StatusOK, status, string(data))
+	s.Equal("application/pdf", header.Get("Content-Type"))
+	s.Contains(header.Get("Content-Disposition"), "invoice-"+fx.ord.OrderNumber+".pdf")
+	s.True(bytes.HasPrefix(data, []byte("%PDF-")))
+	s.Contains(string(data), "(INVOICE) Tj")
+	s.Contains(string(data), "(Reference: TRX-42) Tj")
+
+	status, _, _ = s.raw(fx, "GET", "/orders/ord_missing/invoice.pdf", nil)
+	s.Equal(http.StatusNotFound, status)
+}
+
+func (s *OrderPrintingSuite) TestPaidOrder_QueuedOnAutoStations() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	packing := s.createStation(fx, map[string]interface{}{"name": "Packing desk", "kind": "pick_ticket", "format": "html"})
+	manual := s.createStation(fx, map[string]interface{}{"name": "Front desk", "autoQueuePaid": false})
+
+	var body map[string]interface{}
+	status := s.do(fx, "PATCH", "/orders/"+fx.ord.ID+"/payment-status", map[string]interface{}{"paymentStatus": "paid"}, &body)
+	s.Require().Equal(http.StatusOK, status, body)
+
+	jobs := s.waitForJobs(fx, packing, 1)
+	s.Require().Len(jobs, 1)
+	s.Equal(fx.ord.ID, jobs[0]["orderId"])
+	s.Equal(fx.ord.OrderNumber, jobs[0]["orderNumber"])
+	s.Equal("pick_ticket", jobs[0]["kind"])
+	s.Equal("paid", jobs[0]["source"])
+	jobID := jobs[0]["id"].(string)
+
+	status, header, data := s.raw(fx, "GET", "/print-stations/"+packing+"/jobs/"+jobID+"/content", nil)
+	s.Require().Equal(http.StatusOK, status, string(data))
+	s.Contains(header.Get("Content-Type"), "text/html", "defaults to the station's format")
+	s.Contains(string(data), "PICK TICKET")
+
+	status = s.do(fx, "POST", "/print-stations/"+packing+"/jobs/"+jobID+"/ack", nil, &body)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("printed", body["status"])
+	s.NotEmpty(body["printedAt"])
+	s.Empty(s.waitForJobs(fx, packing, 0))
+
+	var stations []map[string]interface{}
+	status = s.do(fx, "GET", "/print-stations", nil, &stations)
+	s.Require().Equal(http.StatusOK, status)
+	s.Require().Len(stations, 2)
+	for _, st := range stations {
+		if st["id"] == packing {
+			s.NotNil(st["lastPolledAt"])
+		}
+	}
+
+	var manualJobs []map[string]interface{}
+	status = s.do(fx, "GET", "/print-stations/"+manual+"/jobs", nil, &manualJobs)
+	s.Require().Equal(http.StatusOK, status)
+	s.Empty(manualJobs, "stations without autoQueuePaid are not fed paid orders")
+
+	status = s.do(fx, "POST", "/orders/"+fx.ord.ID+"/print-jobs", map[string]interface{}{"stationId": manual}, &body)
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Equal("receipt", body["kind"])
+	s.Equal("manual", body["source"])
+	s.Len(s.waitForJobs(fx, manual, 1), 1)
+}
+
+func (s *OrderPrintingSuite) TestPrintStations_ScopedToBusiness() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	other := s.setup(ctx)
+	station := s.createStation(other, map[string]interface{}{"name": "Other"})
+
+	status := s.do(fx, "GET", "/print-stations/"+station+"/jobs", nil, nil)
+	s.Equal(http.StatusNotFound, status)
+	status = s.do(fx, "POST", "/orders/"+fx.ord.ID+"/print-jobs", map[string]interface{}{"stationId": station}, nil)
+	s.Equal(http.StatusNotFound, status)
+
+	status = s.do(other, "DELETE", "/print-stations/"+station, nil, nil)
+	s.Equal(http.StatusNoContent, status)
+	status = s.do(other, "GET", "/print-stations/"+station+"/jobs", nil, nil)
+	s.Equal(http.StatusNotFound, status)
+}
+
+func TestOrderPrintingSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(OrderPrintingSuite))
+}
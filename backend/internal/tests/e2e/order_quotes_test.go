@@ -0,0 +1,287 @@
+package e2e_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var quoteTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes",
+	"quotes", "quote_items",
+}
+
+// OrderQuotesSuite tests quote lifecycle, PDF output and conversion to orders.
+type OrderQuotesSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *OrderQuotesSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *OrderQuotesSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, quoteTables...))
+}
+
+func (s *OrderQuotesSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, quoteTables...))
+}
+
+type quoteFixture struct {
+	owner   *testutils.Owner
+	biz     *business.Business
+	cust    *customer.Customer
+	addr    *customer.CustomerAddress
+	variant *inventory.Variant
+}
+
+// setup creates a business (14% VAT) with a customer and a variant selling for 100 with 10 in stock.
+func (s *OrderQuotesSuite) setup(ctx context.Context) *quoteFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	addr, err := s.factory.Address(ctx, cust.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	return &quoteFixture{owner: owner, biz: biz, cust: cust, addr: addr, variant: v}
+}
+
+func (s *OrderQuotesSuite) do(fx *quoteFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *OrderQuotesSuite) createQuote(fx *quoteFixture, extra map[string]interface{}) map[string]interface{} {
+	payload := map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"shippingFee":       "10",
+		"terms":             "Payment due within 14 days.",
+		"items": []map[string]interface{}{
+			{"variantId": fx.variant.ID, "quantity": 3, "unitPrice": "90"},
+		},
+	}
+	for k, v := range extra {
+		payload[k] = v
+	}
+	status, body := s.do(fx, "POST", "/quotes", payload)
+	s.Require().Equal(http.StatusCreated, status, body)
+	return body
+}
+
+func (s *OrderQuotesSuite) TestCreateQuote_ComputesTotals() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	q := s.createQuote(fx, map[string]interface{}{"discountType": "percent", "discountValue": "10"})
+	s.Equal("draft", q["status"])
+	s.NotEmpty(q["quoteNumber"])
+	s.Equal("270", q["subtotal"])
+	s.Equal("27", q["discount"])
+	s.Equal("37.8", q["vat"])
+	s.Equal("290.8", q["total"])
+	s.Len(q["items"], 1)
+
+	validUntil, err := time.Parse(time.RFC3339, q["validUntil"].(string))
+	s.Require().NoError(err)
+	s.WithinDuration(time.Now().Add(30*24*time.Hour), validUntil, time.Hour)
+}
+
+func (s *OrderQuotesSuite) TestCreateQuote_DefaultsToSalePrice() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	q := s.createQuote(fx, map[string]interface{}{
+		"items": []map[string]interface{}{{"variantId": fx.variant.ID, "quantity": 2}},
+	})
+	item := q["items"].([]interface{})[0].(map[string]interface{})
+	s.Equal("100", item["unitPrice"])
+	s.Equal("200", item["total"])
+}
+
+func (s *OrderQuotesSuite) TestCreateQuote_RejectsPastValidity() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	status, body := s.do(fx, "POST", "/quotes", map[string]interface{}{
+		"customerId": fx.cust.ID,
+		"validUntil": time.Now().Add(-time.Hour).UTC(),
+		"items":      []map[string]interface{}{{"variantId": fx.variant.ID, "quantity": 1}},
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+}
+
+func (s *OrderQuotesSuite) TestQuoteLifecycle_AcceptCreatesOrder() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	q := s.createQuote(fx, nil)
+	quoteID := q["id"].(string)
+
+	status, body := s.do(fx, "PATCH", "/quotes/"+quoteID, map[string]interface{}{
+		"items": []map[string]interface{}{{"variantId": fx.variant.ID, "quantity": 2, "unitPrice": "90"}},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("180", body["subtotal"])
+
+	status, body = s.do(fx, "POST", "/quotes/"+quoteID+"/send", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("sent", body["status"])
+	s.NotNil(body["sentAt"])
+
+	// Sent quotes are frozen.
+	status, _ = s.do(fx, "PATCH", "/quotes/"+quoteID, map[string]interface{}{"terms": "changed"})
+	s.Equal(http.StatusConflict, status)
+
+	status, body = s.do(fx, "POST", "/quotes/"+quoteID+"/accept", map[string]interface{}{"channel": "whatsapp"})
+	s.Require().Equal(http.StatusCreated, status, body)
+	quote := body["quote"].(map[string]interface{})
+	ord := body["order"].(map[string]interface{})
+	s.Equal("accepted", quote["status"])
+	s.Equal(ord["id"], quote["orderId"])
+	s.Equal("pending", ord["status"])
+	s.Equal("180", ord["subtotal"])
+	s.Equal("10", ord["shippingFee"])
+	s.Equal(fx.addr.ID, ord["shippingAddressId"])
+
+	v, err := s.helper.GetVariant(ctx, fx.variant.ID)
+	s.Require().NoError(err)
+	s.Equal(8, v.StockQuantity)
+
+	// A quote converts once.
+	status, _ = s.do(fx, "POST", "/quotes/"+quoteID+"/accept", map[string]interface{}{"channel": "whatsapp"})
+	s.Equal(http.StatusConflict, status)
+	status, _ = s.do(fx, "DELETE", "/quotes/"+quoteID, nil)
+	s.Equal(http.StatusConflict, status)
+}
+
+func (s *OrderQuotesSuite) TestAcceptQuote_RequiresSent() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	q := s.createQuote(fx, nil)
+
+	status, _ := s.do(fx, "POST", "/quotes/"+q["id"].(string)+"/accept", map[string]interface{}{"channel": "whatsapp"})
+	s.Equal(http.StatusConflict, status)
+}
+
+func (s *OrderQuotesSuite) TestQuoteExpiry() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	q := s.createQuote(fx, nil)
+	quoteID := q["id"].(string)
+	status, _ := s.do(fx, "POST", "/quotes/"+quoteID+"/send", nil)
+	s.Require().Equal(http.StatusOK, status)
+
+	s.NoError(testEnv.Database.GetDB().Exec("UPDATE quotes SET valid_until = ? WHERE id = ?", time.Now().Add(-time.Hour), quoteID).Error)
+
+	status, body := s.do(fx, "GET", "/quotes/"+quoteID, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("expired", body["status"])
+	s.NotNil(body["expiredAt"])
+
+	status, _ = s.do(fx, "POST", "/quotes/"+quoteID+"/accept", map[string]interface{}{"channel": "whatsapp"})
+	s.Equal(http.StatusConflict, status)
+}
+
+func (s *OrderQuotesSuite) TestDeclineAndDelete() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	q := s.createQuote(fx, nil)
+	quoteID := q["id"].(string)
+
+	status, _ := s.do(fx, "POST", "/quotes/"+quoteID+"/decline", nil)
+	s.Equal(http.StatusConflict, status, "draft quotes cannot be declined")
+
+	status, _ = s.do(fx, "POST", "/quotes/"+quoteID+"/send", nil)
+	s.Require().Equal(http.StatusOK, status)
+	status, body := s.do(fx, "POST", "/quotes/"+quoteID+"/decline", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("declined", body["status"])
+
+	status, _ = s.do(fx, "DELETE", "/quotes/"+quoteID, nil)
+	s.Equal(http.StatusNoContent, status)
+	status, _ = s.do(fx, "GET", "/quotes/"+quoteID, nil)
+	s.Equal(http.StatusNotFound, status)
+}
+
+func (s *OrderQuotesSuite) TestListQuotes_FiltersByCustomerAndStatus() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	s.createQuote(fx, nil)
+	sent := s.createQuote(fx, nil)
+	status, _ := s.do(fx, "POST", "/quotes/"+sent["id"].(string)+"/send", nil)
+	s.Require().Equal(http.StatusOK, status)
+
+	other, err := s.factory.Customer(ctx, fx.biz.ID)
+	s.Require().NoError(err)
+	s.createQuote(fx, map[string]interface{}{"customerId": other.ID, "shippingAddressId": nil})
+
+	status, body := s.do(fx, "GET", "/quotes?customerId="+fx.cust.ID, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.EqualValues(2, body["totalCount"])
+
+	status, body = s.do(fx, "GET", "/quotes?customerId="+fx.cust.ID+"&status=sent", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.EqualValues(1, body["totalCount"])
+	s.Equal(sent["id"], body["items"].([]interface{})[0].(map[string]interface{})["id"])
+}
+
+func (s *OrderQuotesSuite) TestDownloadQuotePDF() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	q := s.createQuote(fx, nil)
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+fx.biz.Descriptor+"/quotes/"+q["id"].(string)+"/pdf", nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Equal("application/pdf", resp.Header.Get("Content-Type"))
+	s.Contains(resp.Header.Get("Content-Disposition"), "quote-"+q["quoteNumber"].(string)+".pdf")
+	data, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	s.True(strings.HasPrefix(string(data), "%PDF-"))
+	s.Contains(string(data), q["quoteNumber"].(string))
+}
+
+func (s *OrderQuotesSuite) TestQuotes_BusinessIsolation() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	q := s.createQuote(fx, nil)
+
+	other := s.setup(ctx)
+	status, _ := s.do(other, "GET", "/quotes/"+q["id"].(string), nil)
+	s.Equal(http.StatusNotFound, status)
+}
+
+func TestOrderQuotesSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(OrderQuotesSuite))
+}
@@ -0,0 +1,296 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var recurringOrderTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes", "order_events",
+	"recurring_orders",
+}
+
+// OrderRecurringSuite tests recurring orders: scheduling, pause/resume and on-demand generation.
+type OrderRecurringSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *OrderRecurringSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *OrderRecurringSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, recurringOrderTables...))
+}
+
+func (s *OrderRecurringSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, recurringOrderTables...))
+}
+
+type recurringOrderFixture struct {
+	owner   *testutils.Owner
+	biz     *business.Business
+	cust    *customer.Customer
+	addr    *customer.CustomerAddress
+	variant *inventory.Variant
+}
+
+// setup creates a business with a customer and a variant selling for 100 with 10 in stock.
+func (s *OrderRecurringSuite) setup(ctx context.Context) *recurringOrderFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	addr, err := s.factory.Address(ctx, cust.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	return &recurringOrderFixture{owner: owner, biz: biz, cust: cust, addr: addr, variant: v}
+}
+
+func (s *OrderRecurringSuite) do(fx *recurringOrderFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *OrderRecurringSuite) createRecurringOrder(fx *recurringOrderFixture, extra map[string]interface{}) map[string]interface{} {
+	payload := map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"channel":           "whatsapp",
+		"frequency":         "weekly",
+		"tags":              []string{"Subscription"},
+		"items": []map[string]interface{}{
+			{"variantId": fx.variant.ID, "quantity": 2},
+		},
+	}
+	for k, v := range extra {
+		payload[k] = v
+	}
+	status, body := s.do(fx, "POST", "/recurring-orders", payload)
+	s.Require().Equal(http.StatusCreated, status, body)
+	return body
+}
+
+func (s *OrderRecurringSuite) problemCode(body map[string]interface{}) interface{} {
+	return body["extensions"].(map[string]interface{})["code"]
+}
+
+func dateOf(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339, s)
+	return t.UTC()
+}
+
+func today() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (s *OrderRecurringSuite) TestCreateRecurringOrder_Defaults() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	ro := s.createRecurringOrder(fx, nil)
+	s.Equal("active", ro["status"])
+	s.Equal("weekly", ro["frequency"])
+	s.Equal("bank_transfer", ro["paymentMethod"])
+	s.EqualValues(0, ro["ordersCreated"])
+	s.Equal([]interface{}{"subscription"}, ro["tags"])
+	s.Len(ro["items"], 1)
+	s.True(today().Equal(dateOf(ro["startDate"].(string))))
+	s.True(today().Equal(dateOf(ro["nextRunDate"].(string))))
+	s.NotNil(ro["customer"])
+}
+
+func (s *OrderRecurringSuite) TestCreateRecurringOrder_Validation() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	status, body := s.do(fx, "POST", "/recurring-orders", map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"channel":           "whatsapp",
+		"frequency":         "weekly",
+		"startDate":         today().AddDate(0, 0, 10).Format(time.DateOnly),
+		"endDate":           today().AddDate(0, 0, 5).Format(time.DateOnly),
+		"items":             []map[string]interface{}{{"variantId": fx.variant.ID, "quantity": 1}},
+	})
+	s.Require().Equal(http.StatusBadRequest, status, body)
+	s.Equal("order.recurring_order_invalid_date_range", s.problemCode(body))
+
+	status, body = s.do(fx, "POST", "/recurring-orders", map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"channel":           "whatsapp",
+		"frequency":         "daily",
+		"items":             []map[string]interface{}{{"variantId": fx.variant.ID, "quantity": 1}},
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+
+	status, body = s.do(fx, "POST", "/recurring-orders", map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"channel":           "whatsapp",
+		"frequency":         "monthly",
+		"items":             []map[string]interface{}{{"variantId": "var_missing", "quantity": 1}},
+	})
+	s.Equal(http.StatusNotFound, status, body)
+}
+
+func (s *OrderRecurringSuite) TestGenerateRecurringOrder_CreatesOrderAndMovesSchedule() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	ro := s.createRecurringOrder(fx, map[string]interface{}{"note": "Leave at the door"})
+	id := ro["id"].(string)
+
+	status, body := s.do(fx, "POST", "/recurring-orders/"+id+"/generate", nil)
+	s.Require().Equal(http.StatusCreated, status, body)
+	ord := body["order"].(map[string]interface{})
+	rec := body["recurringOrder"].(map[string]interface{})
+	s.Equal("pending", ord["status"])
+	s.Equal("whatsapp", ord["channel"])
+	s.Equal("200", ord["subtotal"])
+	s.Equal([]interface{}{"subscription"}, ord["tags"])
+	s.Equal(fx.addr.ID, ord["shippingAddressId"])
+	s.EqualValues(1, rec["ordersCreated"])
+	s.Equal(ord["id"], rec["lastOrderId"])
+	s.NotNil(rec["lastRunAt"])
+	s.True(today().AddDate(0, 0, 7).Equal(dateOf(rec["nextRunDate"].(string))))
+
+	v, err := s.helper.GetVariant(ctx, fx.variant.ID)
+	s.Require().NoError(err)
+	s.Equal(8, v.StockQuantity)
+}
+
+func (s *OrderRecurringSuite) TestGenerateRecurringOrder_InsufficientStock() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	ro := s.createRecurringOrder(fx, map[string]interface{}{
+		"items": []map[string]interface{}{
+			{"variantId": fx.variant.ID, "quantity": 6},
+			{"variantId": fx.variant.ID, "quantity": 6},
+		},
+	})
+	id := ro["id"].(string)
+
+	status, body := s.do(fx, "POST", "/recurring-orders/"+id+"/generate", nil)
+	s.Require().Equal(http.StatusConflict, status, body)
+	s.Equal("order.insufficient_stock", s.problemCode(body))
+
+	status, body = s.do(fx, "GET", "/recurring-orders/"+id, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.EqualValues(0, body["ordersCreated"])
+	s.True(today().Equal(dateOf(body["nextRunDate"].(string))))
+
+	v, err := s.helper.GetVariant(ctx, fx.variant.ID)
+	s.Require().NoError(err)
+	s.Equal(10, v.StockQuantity)
+}
+
+func (s *OrderRecurringSuite) TestPauseResume() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	ro := s.createRecurringOrder(fx, nil)
+	id := ro["id"].(string)
+
+	status, body := s.do(fx, "POST", "/recurring-orders/"+id+"/pause", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("paused", body["status"])
+
+	status, body = s.do(fx, "POST", "/recurring-orders/"+id+"/pause", nil)
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("order.recurring_order_status_update_not_allowed", s.problemCode(body))
+
+	status, body = s.do(fx, "POST", "/recurring-orders/"+id+"/generate", nil)
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("order.recurring_order_not_active", s.problemCode(body))
+
+	status, body = s.do(fx, "POST", "/recurring-orders/"+id+"/resume", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("active", body["status"])
+	s.True(today().Equal(dateOf(body["nextRunDate"].(string))))
+}
+
+func (s *OrderRecurringSuite) TestUpdateRecurringOrder() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	ro := s.createRecurringOrder(fx, nil)
+	id := ro["id"].(string)
+
+	next := today().AddDate(0, 0, 3)
+	status, body := s.do(fx, "PATCH", "/recurring-orders/"+id, map[string]interface{}{
+		"frequency":   "monthly",
+		"nextRunDate": next.Format(time.DateOnly),
+		"items":       []map[string]interface{}{{"variantId": fx.variant.ID, "quantity": 1}},
+	})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("monthly", body["frequency"])
+	s.True(next.Equal(dateOf(body["nextRunDate"].(string))))
+	item := body["items"].([]interface{})[0].(map[string]interface{})
+	s.EqualValues(1, item["quantity"])
+
+	status, body = s.do(fx, "PATCH", "/recurring-orders/"+id, map[string]interface{}{
+		"nextRunDate": today().AddDate(0, 0, -1).Format(time.DateOnly),
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("order.recurring_order_next_run_in_past", s.problemCode(body))
+}
+
+func (s *OrderRecurringSuite) TestListAndDelete() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	first := s.createRecurringOrder(fx, nil)
+	second := s.createRecurringOrder(fx, map[string]interface{}{"frequency": "monthly"})
+	status, _ := s.do(fx, "POST", "/recurring-orders/"+second["id"].(string)+"/pause", nil)
+	s.Require().Equal(http.StatusOK, status)
+
+	status, body := s.do(fx, "GET", "/recurring-orders?status=active", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	items := body["items"].([]interface{})
+	s.Require().Len(items, 1)
+	s.Equal(first["id"], items[0].(map[string]interface{})["id"])
+
+	status, body = s.do(fx, "GET", "/recurring-orders?customerId="+fx.cust.ID, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.EqualValues(2, body["totalCount"])
+
+	status, _ = s.do(fx, "DELETE", "/recurring-orders/"+first["id"].(string), nil)
+	s.Equal(http.StatusNoContent, status)
+	status, body = s.do(fx, "GET", "/recurring-orders/"+first["id"].(string), nil)
+	s.Equal(http.StatusNotFound, status)
+	s.Equal("order.recurring_order_not_found", s.problemCode(body))
+
+	other := s.setup(ctx)
+	status, _ = s.do(other, "GET", "/recurring-orders/"+second["id"].(string), nil)
+	s.Equal(http.StatusNotFound, status)
+}
+
+func TestOrderRecurringSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(OrderRecurringSuite))
+}
@@ -0,0 +1,204 @@
+package e2e_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var shippingLabelTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes",
+	"order_events", "shipping_labels",
+}
+
+// OrderShippingLabelsSuite tests carrier rates and label purchase against the mock carrier.
+type OrderShippingLabelsSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *OrderShippingLabelsSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *OrderShippingLabelsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, shippingLabelTables...))
+}
+
+func (s *OrderShippingLabelsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, shippingLabelTables...))
+}
+
+type shippingLabelFixture struct {
+	owner   *testutils.Owner
+	biz     *business.Business
+	cust    *customer.Customer
+	addr    *customer.CustomerAddress
+	variant *inventory.Variant
+}
+
+func (s *OrderShippingLabelsSuite) setup(ctx context.Context) *shippingLabelFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	addr, err := s.factory.Address(ctx, cust.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	return &shippingLabelFixture{owner: owner, biz: biz, cust: cust, addr: addr, variant: v}
+}
+
+func (s *OrderShippingLabelsSuite) do(fx *shippingLabelFixture, method, path string, payload interface{}, out interface{}) int {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, out))
+	}
+	return resp.StatusCode
+}
+
+func (s *OrderShippingLabelsSuite) createOrder(fx *shippingLabelFixture, status string) string {
+	var created map[string]interface{}
+	code := s.do(fx, "POST", "/orders", map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"channel":           "instagram",
+		"status":            status,
+		"items": []map[string]interface{}{
+			{"variantId": fx.variant.ID, "quantity": 2, "unitPrice": "100"},
+		},
+	}, &created)
+	s.Require().Equal(http.StatusCreated, code, created)
+	return created["id"].(string)
+}
+
+func shipmentPayload(extra map[string]interface{}) map[string]interface{} {
+	payload := map[string]interface{}{
+		"shipFrom": map[string]interface{}{"city": "Giza", "addressLine1": "1 Warehouse Rd", "phone": "+201000000000"},
+		"parcel":   map[string]interface{}{"weightKg": "1.5", "lengthCm": "30", "widthCm": "20", "heightCm": "10"},
+	}
+	for k, v := range extra {
+		payload[k] = v
+	}
+	return payload
+}
+
+func (s *OrderShippingLabelsSuite) TestCarriers_ListsConfigured() {
+	fx := s.setup(context.Background())
+	var carriers []string
+	s.Require().Equal(http.StatusOK, s.do(fx, "GET", "/shipping-carriers", nil, &carriers))
+	s.Contains(carriers, "mock")
+}
+
+func (s *OrderShippingLabelsSuite) TestRates_QuotesMockCarrier() {
+	fx := s.setup(context.Background())
+	orderID := s.createOrder(fx, "placed")
+
+	var rates []map[string]interface{}
+	code := s.do(fx, "POST", "/orders/"+orderID+"/shipping-rates", shipmentPayload(map[string]interface{}{"carrier": "mock"}), &rates)
+	s.Require().Equal(http.StatusOK, code, rates)
+	s.Require().Len(rates, 2)
+	s.Equal("standard", rates[0]["service"])
+	s.Equal("13", rates[0]["amount"])
+	s.Equal("USD", rates[0]["currency"])
+	s.Equal("express", rates[1]["service"])
+	s.Equal("26", rates[1]["amount"])
+}
+
+func (s *OrderShippingLabelsSuite) TestPurchase_StoresLabelAndTracking() {
+	fx := s.setup(context.Background())
+	orderID := s.createOrder(fx, "placed")
+
+	var label map[string]interface{}
+	code := s.do(fx, "POST", "/orders/"+orderID+"/shipping-labels", shipmentPayload(map[string]interface{}{"carrier": "mock", "service": "express"}), &label)
+	s.Require().Equal(http.StatusCreated, code, label)
+	s.Equal(orderID, label["orderId"])
+	s.Equal("mock", label["carrier"])
+	s.Equal("express", label["service"])
+	s.Equal("26", label["amount"])
+	tracking := label["trackingNumber"].(string)
+	s.True(strings.HasPrefix(tracking, "MOCK"), tracking)
+
+	var labels []map[string]interface{}
+	s.Require().Equal(http.StatusOK, s.do(fx, "GET", "/orders/"+orderID+"/shipping-labels", nil, &labels))
+	s.Require().Len(labels, 1)
+	s.Equal(label["id"], labels[0]["id"])
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+fx.biz.Descriptor+"/orders/"+orderID+"/shipping-labels/"+label["id"].(string)+"/label.pdf", nil, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+	s.Equal("application/pdf", resp.Header.Get("Content-Type"))
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	s.True(strings.HasPrefix(string(body), "%PDF"))
+
+	var events []map[string]interface{}
+	s.Require().Equal(http.StatusOK, s.do(fx, "GET", "/orders/"+orderID+"/timeline", nil, &events))
+	last := events[len(events)-1]
+	s.Equal("shipping_label_purchased", last["type"])
+	changes := last["changes"].([]interface{})
+	s.Equal(tracking, changes[2].(map[string]interface{})["to"])
+}
+
+func (s *OrderShippingLabelsSuite) TestPurchase_RejectsUnknownServiceAndCarrier() {
+	fx := s.setup(context.Background())
+	orderID := s.createOrder(fx, "placed")
+
+	var body map[string]interface{}
+	code := s.do(fx, "POST", "/orders/"+orderID+"/shipping-labels", shipmentPayload(map[string]interface{}{"carrier": "mock", "service": "overnight"}), &body)
+	s.Equal(http.StatusBadRequest, code, body)
+	s.Equal("business.carrier_service_unavailable", body["extensions"].(map[string]interface{})["code"])
+
+	body = nil
+	code = s.do(fx, "POST", "/orders/"+orderID+"/shipping-labels", shipmentPayload(map[string]interface{}{"carrier": "aramex", "service": "PPX"}), &body)
+	s.Equal(http.StatusBadRequest, code, body)
+	s.Equal("business.carrier_not_configured", body["extensions"].(map[string]interface{})["code"])
+}
+
+func (s *OrderShippingLabelsSuite) TestRates_RequireShippableOrder() {
+	fx := s.setup(context.Background())
+	orderID := s.createOrder(fx, "pending")
+
+	var body map[string]interface{}
+	code := s.do(fx, "POST", "/orders/"+orderID+"/shipping-rates", shipmentPayload(nil), &body)
+	s.Equal(http.StatusConflict, code, body)
+	s.Equal("order.not_shippable", body["extensions"].(map[string]interface{})["code"])
+}
+
+func (s *OrderShippingLabelsSuite) TestRates_ValidatesParcel() {
+	fx := s.setup(context.Background())
+	orderID := s.createOrder(fx, "placed")
+
+	var body map[string]interface{}
+	code := s.do(fx, "POST", "/orders/"+orderID+"/shipping-rates", map[string]interface{}{
+		"shipFrom": map[string]interface{}{"city": "Giza"},
+		"parcel":   map[string]interface{}{"weightKg": "0", "lengthCm": "30", "widthCm": "20", "heightCm": "10"},
+	}, &body)
+	s.Equal(http.StatusBadRequest, code, body)
+}
+
+func TestOrderShippingLabelsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(OrderShippingLabelsSuite))
+}
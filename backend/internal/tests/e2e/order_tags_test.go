@@ -0,0 +1,210 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var orderTagsTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes",
+	"order_events",
+}
+
+// OrderTagsSuite tests order tags, custom fields and tag filtering.
+type OrderTagsSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *OrderTagsSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *OrderTagsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, orderTagsTables...))
+}
+
+func (s *OrderTagsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, orderTagsTables...))
+}
+
+type orderTagsFixture struct {
+	owner   *testutils.Owner
+	biz     *business.Business
+	cust    *customer.Customer
+	addr    *customer.CustomerAddress
+	variant *inventory.Variant
+}
+
+func (s *OrderTagsSuite) setup(ctx context.Context) *orderTagsFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	addr, err := s.factory.Address(ctx, cust.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	return &orderTagsFixture{owner: owner, biz: biz, cust: cust, addr: addr, variant: v}
+}
+
+func (s *OrderTagsSuite) do(fx *orderTagsFixture, method, path string, payload interface{}, out interface{}) int {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, out))
+	}
+	return resp.StatusCode
+}
+
+func (s *OrderTagsSuite) createOrder(fx *orderTagsFixture, tags []string, customFields map[string]string) map[string]interface{} {
+	var created map[string]interface{}
+	status := s.do(fx, "POST", "/orders", map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"channel":           "instagram",
+		"tags":              tags,
+		"customFields":      customFields,
+		"items": []map[string]interface{}{
+			{"variantId": fx.variant.ID, "quantity": 1, "unitPrice": "100", "unitCost": "50"},
+		},
+	}, &created)
+	s.Require().Equal(http.StatusCreated, status, created)
+	return created
+}
+
+func (s *OrderTagsSuite) listOrderIDs(fx *orderTagsFixture, query string) []string {
+	var body map[string]interface{}
+	status := s.do(fx, "GET", "/orders?"+query, nil, &body)
+	s.Require().Equal(http.StatusOK, status, body)
+	var ids []string
+	for _, it := range body["items"].([]interface{}) {
+		ids = append(ids, it.(map[string]interface{})["id"].(string))
+	}
+	return ids
+}
+
+func (s *OrderTagsSuite) TestCreate_NormalizesTagsAndStoresCustomFields() {
+	fx := s.setup(context.Background())
+	created := s.createOrder(fx, []string{" Gift ", "gift", "Wholesale"}, map[string]string{"giftMessage": "Happy birthday"})
+
+	s.Equal([]interface{}{"gift", "wholesale"}, created["tags"])
+	s.Equal(map[string]interface{}{"giftMessage": "Happy birthday"}, created["customFields"])
+}
+
+func (s *OrderTagsSuite) TestCreate_RejectsInvalidTag() {
+	fx := s.setup(context.Background())
+	var body map[string]interface{}
+	status := s.do(fx, "POST", "/orders", map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"channel":           "instagram",
+		"tags":              []string{"vip!"},
+		"items": []map[string]interface{}{
+			{"variantId": fx.variant.ID, "quantity": 1, "unitPrice": "100"},
+		},
+	}, &body)
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("order.invalid_tag", body["extensions"].(map[string]interface{})["code"])
+}
+
+func (s *OrderTagsSuite) TestList_FiltersByAllTags() {
+	fx := s.setup(context.Background())
+	gift := s.createOrder(fx, []string{"gift"}, nil)
+	both := s.createOrder(fx, []string{"gift", "priority"}, nil)
+	s.createOrder(fx, nil, nil)
+
+	s.ElementsMatch([]string{gift["id"].(string), both["id"].(string)}, s.listOrderIDs(fx, "tags=GIFT"))
+	s.Equal([]string{both["id"].(string)}, s.listOrderIDs(fx, "tags=gift&tags=priority"))
+	s.Empty(s.listOrderIDs(fx, "tags=influencer"))
+}
+
+func (s *OrderTagsSuite) TestUpdate_MergesCustomFieldsAndReplacesTags() {
+	fx := s.setup(context.Background())
+	created := s.createOrder(fx, []string{"gift"}, map[string]string{"giftMessage": "Hi", "source": "dm"})
+	orderID := created["id"].(string)
+
+	var updated map[string]interface{}
+	status := s.do(fx, "PATCH", "/orders/"+orderID, map[string]interface{}{
+		"tags":         []string{"wholesale"},
+		"customFields": map[string]interface{}{"giftMessage": nil, "poNumber": "PO-7"},
+	}, &updated)
+	s.Require().Equal(http.StatusOK, status, updated)
+	s.Equal([]interface{}{"wholesale"}, updated["tags"])
+	s.Equal(map[string]interface{}{"source": "dm", "poNumber": "PO-7"}, updated["customFields"])
+}
+
+func (s *OrderTagsSuite) TestSetOrderTags_RecordsTimeline() {
+	fx := s.setup(context.Background())
+	orderID := s.createOrder(fx, []string{"gift"}, nil)["id"].(string)
+
+	var updated map[string]interface{}
+	status := s.do(fx, "PUT", "/orders/"+orderID+"/tags", map[string]interface{}{"tags": []string{"Influencer", "gift"}}, &updated)
+	s.Require().Equal(http.StatusOK, status, updated)
+	s.Equal([]interface{}{"influencer", "gift"}, updated["tags"])
+
+	var events []map[string]interface{}
+	s.Require().Equal(http.StatusOK, s.do(fx, "GET", "/orders/"+orderID+"/timeline", nil, &events))
+	last := events[len(events)-1]
+	s.Equal("updated", last["type"])
+	change := last["changes"].([]interface{})[0].(map[string]interface{})
+	s.Equal("tags", change["field"])
+	s.Equal([]interface{}{"gift"}, change["from"])
+}
+
+func (s *OrderTagsSuite) TestTagCatalog_ListRenameDelete() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	first := s.createOrder(fx, []string{"gift", "vip"}, nil)["id"].(string)
+	second := s.createOrder(fx, []string{"gift"}, nil)["id"].(string)
+	other := s.setup(ctx)
+	s.createOrder(other, []string{"gift"}, nil)
+
+	var tags []map[string]interface{}
+	s.Require().Equal(http.StatusOK, s.do(fx, "GET", "/orders/tags", nil, &tags))
+	s.Equal([]map[string]interface{}{
+		{"tag": "gift", "orders": float64(2)},
+		{"tag": "vip", "orders": float64(1)},
+	}, tags)
+
+	var renamed map[string]interface{}
+	s.Require().Equal(http.StatusOK, s.do(fx, "PATCH", "/orders/tags/gift", map[string]interface{}{"name": "vip"}, &renamed))
+	s.Equal(float64(2), renamed["updated"])
+
+	var ord map[string]interface{}
+	s.Require().Equal(http.StatusOK, s.do(fx, "GET", "/orders/"+first, nil, &ord))
+	s.Equal([]interface{}{"vip"}, ord["tags"])
+
+	var deleted map[string]interface{}
+	s.Require().Equal(http.StatusOK, s.do(fx, "DELETE", "/orders/tags/vip", nil, &deleted))
+	s.Equal(float64(2), deleted["updated"])
+	s.Require().Equal(http.StatusOK, s.do(fx, "GET", "/orders/"+second, nil, &ord))
+	s.Equal([]interface{}{}, ord["tags"])
+
+	// The other business' tags are untouched.
+	s.Require().Equal(http.StatusOK, s.do(other, "GET", "/orders/tags", nil, &tags))
+	s.Equal([]map[string]interface{}{{"tag": "gift", "orders": float64(1)}}, tags)
+}
+
+func TestOrderTagsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(OrderTagsSuite))
+}
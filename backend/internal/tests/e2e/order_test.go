@@ -628,6 +628,75 @@ func (s *OrderSuite) TestListOrders_Search_ByCustomerAndOrderNumber() {
 	s.Equal(float64(1), listResult2["totalCount"])
 }
 
+func (s *OrderSuite) TestListOrders_SummaryView() {
+	ctx := context.Background()
+	_, ws, token, err := s.accountHelper.CreateTestUser(ctx, "admin@example.com", "Password123!", "Admin", "User", role.RoleAdmin)
+	s.NoError(err)
+	s.NoError(s.accountHelper.CreateTestSubscription(ctx, ws.ID))
+
+	biz, err := s.orderHelper.CreateTestBusiness(ctx, ws.ID, "test-biz")
+	s.NoError(err)
+
+	cust, addr, err := s.orderHelper.CreateTestCustomer(ctx, biz.ID, "customer@example.com", "Summary Customer")
+	s.NoError(err)
+
+	cat, err := s.orderHelper.CreateTestCategory(ctx, biz.ID, "Electronics", "electronics")
+	s.NoError(err)
+
+	_, variant, err := s.orderHelper.CreateTestProduct(ctx, biz.ID, cat.ID, "Test Product", decimal.NewFromFloat(100), decimal.NewFromFloat(200), 10)
+	s.NoError(err)
+
+	payload := map[string]interface{}{
+		"customerId":        cust.ID,
+		"shippingAddressId": addr.ID,
+		"channel":           "instagram",
+		"items": []map[string]interface{}{
+			{
+				"variantId": variant.ID,
+				"quantity":  1,
+				"unitPrice": 200,
+				"unitCost":  100,
+			},
+		},
+	}
+	resp, err := s.orderHelper.Client.AuthenticatedRequest("POST", "/v1/businesses/test-biz/orders", payload, token)
+	s.NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusCreated, resp.StatusCode)
+	var created map[string]interface{}
+	s.NoError(testutils.DecodeJSON(resp, &created))
+
+	for _, path := range []string{
+		"/v1/businesses/test-biz/orders?view=summary",
+		"/v1/businesses/test-biz/orders?view=summary&search=Summary%20Customer",
+	} {
+		listResp, err := s.orderHelper.Client.AuthenticatedRequest("GET", path, nil, token)
+		s.NoError(err)
+		defer listResp.Body.Close()
+		s.Equal(http.StatusOK, listResp.StatusCode)
+
+		var listResult map[string]interface{}
+		s.NoError(testutils.DecodeJSON(listResp, &listResult))
+		s.Equal(float64(1), listResult["totalCount"])
+		items := listResult["items"].([]interface{})
+		s.Len(items, 1)
+		item := items[0].(map[string]interface{})
+		s.Equal(created["id"], item["id"])
+		s.Equal(created["orderNumber"], item["orderNumber"])
+		s.Equal("Summary Customer", item["customerName"])
+		s.Equal(created["total"], item["total"])
+		s.NotContains(item, "items")
+		s.NotContains(item, "notes")
+		s.NotContains(item, "shippingAddress")
+		s.NotContains(item, "customer")
+	}
+
+	badResp, err := s.orderHelper.Client.AuthenticatedRequest("GET", "/v1/businesses/test-biz/orders?view=compact", nil, token)
+	s.NoError(err)
+	defer badResp.Body.Close()
+	s.Equal(http.StatusBadRequest, badResp.StatusCode)
+}
+
 func (s *OrderSuite) TestListOrders_Filter_ByPlatform() {
 	ctx := context.Background()
 	_, ws, token, err := s.accountHelper.CreateTestUser(ctx, "admin@example.com", "Password123!", "Admin", "User", role.RoleAdmin)
@@ -923,6 +992,78 @@ func (s *OrderSuite) TestCreateOrderWithAmountDiscount() {
 	s.Equal("406", total)
 }
 
+func (s *OrderSuite) TestCreateOrder_VATInclusivePricing() {
+	ctx := context.Background()
+	_, ws, token, err := s.accountHelper.CreateTestUser(ctx, "admin@example.com", "Password123!", "Admin", "User", role.RoleAdmin)
+	s.NoError(err)
+	s.NoError(s.accountHelper.CreateTestSubscription(ctx, ws.ID))
+
+	biz, err := s.orderHelper.CreateTestBusiness(ctx, ws.ID, "test-biz")
+	s.NoError(err)
+
+	cust, addr, err := s.orderHelper.CreateTestCustomer(ctx, biz.ID, "customer@example.com", "Test Customer")
+	s.NoError(err)
+
+	cat, err := s.orderHelper.CreateTestCategory(ctx, biz.ID, "Electronics", "electronics")
+	s.NoError(err)
+
+	_, variant, err := s.orderHelper.CreateTestProduct(ctx, biz.ID, cat.ID, "Test Product",
+		decimal.NewFromFloat(100), decimal.NewFromFloat(228), 10)
+	s.NoError(err)
+
+	bizResp, err := s.orderHelper.Client.AuthenticatedRequest("PATCH", "/v1/businesses/test-biz", map[string]interface{}{
+		"pricesIncludeVat": true,
+	}, token)
+	s.NoError(err)
+	defer bizResp.Body.Close()
+	s.Equal(http.StatusOK, bizResp.StatusCode)
+	var updatedBiz map[string]interface{}
+	s.NoError(testutils.DecodeJSON(bizResp, &updatedBiz))
+	s.Equal(true, updatedBiz["pricesIncludeVat"])
+
+	payload := map[string]interface{}{
+		"customerId":        cust.ID,
+		"shippingAddressId": addr.ID,
+		"channel":           "instagram",
+		"items": []map[string]interface{}{
+			{
+				"variantId": variant.ID,
+				"quantity":  1,
+				"unitPrice": 228, // gross price including 14% VAT
+				"unitCost":  100,
+			},
+		},
+	}
+
+	previewResp, err := s.orderHelper.Client.AuthenticatedRequest("POST", "/v1/businesses/test-biz/orders/preview", payload, token)
+	s.NoError(err)
+	defer previewResp.Body.Close()
+	s.Equal(http.StatusOK, previewResp.StatusCode)
+	var preview map[string]interface{}
+	s.NoError(testutils.DecodeJSON(previewResp, &preview))
+	s.Equal(true, preview["pricesIncludeVat"])
+	s.Equal("28", preview["vat"].(string))
+	s.Equal("228", preview["total"].(string))
+
+	resp, err := s.orderHelper.Client.AuthenticatedRequest("POST", "/v1/businesses/test-biz/orders", payload, token)
+	s.NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusCreated, resp.StatusCode)
+
+	var created map[string]interface{}
+	s.NoError(testutils.DecodeJSON(resp, &created))
+
+	// VAT is extracted from the gross subtotal: 228 * 0.14 / 1.14 = 28, and not added on top
+	s.Equal("228", created["subtotal"].(string))
+	s.Equal("28", created["vat"].(string))
+	s.Equal("228", created["total"].(string))
+	s.Equal(true, created["pricesIncludeVat"])
+
+	ord, err := s.orderHelper.GetOrder(ctx, created["id"].(string))
+	s.NoError(err)
+	s.True(ord.PricesIncludeVAT)
+}
+
 func (s *OrderSuite) TestCreateOrderWithTargetStatus() {
 	ctx := context.Background()
 	_, ws, token, err := s.accountHelper.CreateTestUser(ctx, "admin@example.com", "Password123!", "Admin", "User", role.RoleAdmin)
@@ -1204,6 +1345,129 @@ func (s *OrderSuite) TestUpdateOrderShippingAddress_AfterShipped_ShouldFail() {
 	s.Contains(strings.ToLower(errResp["detail"].(string)), "cannot update shipping address after")
 }
 
+func (s *OrderSuite) TestCreateAndDeleteOrder_MultipleVariants_AdjustsStockTogether() {
+	ctx := context.Background()
+	_, ws, token, err := s.accountHelper.CreateTestUser(ctx, "admin@example.com", "Password123!", "Admin", "User", role.RoleAdmin)
+	s.NoError(err)
+	s.NoError(s.accountHelper.CreateTestSubscription(ctx, ws.ID))
+
+	biz, err := s.orderHelper.CreateTestBusiness(ctx, ws.ID, "test-biz")
+	s.NoError(err)
+	cust, addr, err := s.orderHelper.CreateTestCustomer(ctx, biz.ID, "customer@example.com", "Test Customer")
+	s.NoError(err)
+	cat, err := s.orderHelper.CreateTestCategory(ctx, biz.ID, "Electronics", "electronics")
+	s.NoError(err)
+
+	_, first, err := s.orderHelper.CreateTestProduct(ctx, biz.ID, cat.ID, "First", decimal.NewFromFloat(10), decimal.NewFromFloat(20), 10)
+	s.NoError(err)
+	_, second, err := s.orderHelper.CreateTestProduct(ctx, biz.ID, cat.ID, "Second", decimal.NewFromFloat(10), decimal.NewFromFloat(20), 5)
+	s.NoError(err)
+
+	payload := map[string]interface{}{
+		"customerId":        cust.ID,
+		"shippingAddressId": addr.ID,
+		"channel":           "instagram",
+		"items": []map[string]interface{}{
+			{"variantId": first.ID, "quantity": 3, "unitPrice": 20, "unitCost": 10},
+			{"variantId": second.ID, "quantity": 5, "unitPrice": 20, "unitCost": 10},
+		},
+	}
+	resp, err := s.orderHelper.Client.AuthenticatedRequest("POST", "/v1/businesses/test-biz/orders", payload, token)
+	s.NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusCreated, resp.StatusCode)
+
+	var created map[string]interface{}
+	s.NoError(testutils.DecodeJSON(resp, &created))
+	orderID := created["id"].(string)
+
+	firstAfter, err := s.orderHelper.GetVariant(ctx, first.ID)
+	s.NoError(err)
+	s.Equal(7, firstAfter.StockQuantity)
+	secondAfter, err := s.orderHelper.GetVariant(ctx, second.ID)
+	s.NoError(err)
+	s.Equal(0, secondAfter.StockQuantity)
+
+	deleteResp, err := s.orderHelper.Client.AuthenticatedRequest("DELETE", fmt.Sprintf("/v1/businesses/test-biz/orders/%s", orderID), nil, token)
+	s.NoError(err)
+	defer deleteResp.Body.Close()
+	s.Equal(http.StatusNoContent, deleteResp.StatusCode)
+
+	firstAfter, err = s.orderHelper.GetVariant(ctx, first.ID)
+	s.NoError(err)
+	s.Equal(10, firstAfter.StockQuantity)
+	secondAfter, err = s.orderHelper.GetVariant(ctx, second.ID)
+	s.NoError(err)
+	s.Equal(5, secondAfter.StockQuantity)
+}
+
+func (s *OrderSuite) TestReconcileOrderTotals_DetectsAndRepairsDrift() {
+	ctx := context.Background()
+	_, ws, token, err := s.accountHelper.CreateTestUser(ctx, "admin@example.com", "Password123!", "Admin", "User", role.RoleAdmin)
+	s.NoError(err)
+	s.NoError(s.accountHelper.CreateTestSubscription(ctx, ws.ID))
+
+	biz, err := s.orderHelper.CreateTestBusiness(ctx, ws.ID, "test-biz")
+	s.NoError(err)
+	cust, addr, err := s.orderHelper.CreateTestCustomer(ctx, biz.ID, "customer@example.com", "Test Customer")
+	s.NoError(err)
+	cat, err := s.orderHelper.CreateTestCategory(ctx, biz.ID, "Electronics", "electronics")
+	s.NoError(err)
+	_, variant, err := s.orderHelper.CreateTestProduct(ctx, biz.ID, cat.ID, "Test Product", decimal.NewFromFloat(10), decimal.NewFromFloat(20), 10)
+	s.NoError(err)
+
+	payload := map[string]interface{}{
+		"customerId":        cust.ID,
+		"shippingAddressId": addr.ID,
+		"channel":           "instagram",
+		"items": []map[string]interface{}{
+			{"variantId": variant.ID, "quantity": 2, "unitPrice": 20, "unitCost": 10},
+		},
+	}
+	resp, err := s.orderHelper.Client.AuthenticatedRequest("POST", "/v1/businesses/test-biz/orders", payload, token)
+	s.NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusCreated, resp.StatusCode)
+	var created map[string]interface{}
+	s.NoError(testutils.DecodeJSON(resp, &created))
+	orderID := created["id"].(string)
+
+	before, err := s.orderHelper.GetOrder(ctx, orderID)
+	s.NoError(err)
+	s.NoError(testEnv.Database.GetDB().Exec("UPDATE orders SET subtotal = subtotal + 5, total = total + 5 WHERE id = ?", orderID).Error)
+
+	// Report only.
+	checkResp, err := s.orderHelper.Client.AuthenticatedRequest("POST", "/v1/businesses/test-biz/orders/reconcile-totals", map[string]interface{}{}, token)
+	s.NoError(err)
+	defer checkResp.Body.Close()
+	s.Equal(http.StatusOK, checkResp.StatusCode)
+	var check map[string]interface{}
+	s.NoError(testutils.DecodeJSON(checkResp, &check))
+	s.Equal(float64(1), check["checked"])
+	s.Equal(float64(0), check["repaired"])
+	mismatches := check["mismatches"].([]interface{})
+	s.Len(mismatches, 1)
+	s.Equal(orderID, mismatches[0].(map[string]interface{})["orderId"])
+
+	drifted, err := s.orderHelper.GetOrder(ctx, orderID)
+	s.NoError(err)
+	s.True(drifted.Total.Equal(before.Total.Add(decimal.NewFromInt(5))))
+
+	// Repair.
+	repairResp, err := s.orderHelper.Client.AuthenticatedRequest("POST", "/v1/businesses/test-biz/orders/reconcile-totals", map[string]interface{}{"repair": true}, token)
+	s.NoError(err)
+	defer repairResp.Body.Close()
+	s.Equal(http.StatusOK, repairResp.StatusCode)
+	var repaired map[string]interface{}
+	s.NoError(testutils.DecodeJSON(repairResp, &repaired))
+	s.Equal(float64(1), repaired["repaired"])
+
+	fixed, err := s.orderHelper.GetOrder(ctx, orderID)
+	s.NoError(err)
+	s.True(fixed.Subtotal.Equal(before.Subtotal))
+	s.True(fixed.Total.Equal(before.Total))
+}
+
 func TestOrderSuite(t *testing.T) {
 	if testServer == nil {
 		t.Skip("Test server not initialized")
@@ -0,0 +1,181 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var timelineTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes",
+	"order_events",
+}
+
+// OrderTimelineSuite tests the activity timeline recorded for order mutations.
+type OrderTimelineSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *OrderTimelineSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *OrderTimelineSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, timelineTables...))
+}
+
+func (s *OrderTimelineSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, timelineTables...))
+}
+
+type timelineFixture struct {
+	owner   *testutils.Owner
+	biz     *business.Business
+	cust    *customer.Customer
+	addr    *customer.CustomerAddress
+	variant *inventory.Variant
+}
+
+func (s *OrderTimelineSuite) setup(ctx context.Context) *timelineFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	addr, err := s.factory.Address(ctx, cust.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	return &timelineFixture{owner: owner, biz: biz, cust: cust, addr: addr, variant: v}
+}
+
+func (s *OrderTimelineSuite) do(fx *timelineFixture, method, path string, payload interface{}, out interface{}) int {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, out))
+	}
+	return resp.StatusCode
+}
+
+func (s *OrderTimelineSuite) createOrder(fx *timelineFixture) string {
+	var created map[string]interface{}
+	status := s.do(fx, "POST", "/orders", map[string]interface{}{
+		"customerId":        fx.cust.ID,
+		"shippingAddressId": fx.addr.ID,
+		"channel":           "instagram",
+		"note":              "leave at the door",
+		"items": []map[string]interface{}{
+			{"variantId": fx.variant.ID, "quantity": 1, "unitPrice": "100", "unitCost": "50"},
+		},
+	}, &created)
+	s.Require().Equal(http.StatusCreated, status, created)
+	return created["id"].(string)
+}
+
+func (s *OrderTimelineSuite) timeline(fx *timelineFixture, orderID string) []map[string]interface{} {
+	var events []map[string]interface{}
+	status := s.do(fx, "GET", "/orders/"+orderID+"/timeline", nil, &events)
+	s.Require().Equal(http.StatusOK, status)
+	return events
+}
+
+func eventTypes(events []map[string]interface{}) []string {
+	types := make([]string, len(events))
+	for i, e := range events {
+		types[i] = e["type"].(string)
+	}
+	return types
+}
+
+func (s *OrderTimelineSuite) TestTimeline_RecordsCreation() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	orderID := s.createOrder(fx)
+
+	events := s.timeline(fx, orderID)
+	s.Equal([]string{"created", "note_added"}, eventTypes(events))
+	s.Equal(fx.owner.User.ID, events[0]["actorId"])
+	s.Equal(orderID, events[0]["orderId"])
+	changes := events[1]["changes"].([]interface{})
+	s.Require().Len(changes, 1)
+	s.Equal("leave at the door", changes[0].(map[string]interface{})["to"])
+}
+
+func (s *OrderTimelineSuite) TestTimeline_RecordsMutationsInOrder() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	orderID := s.createOrder(fx)
+
+	s.Require().Equal(http.StatusOK, s.do(fx, "PATCH", "/orders/"+orderID, map[string]interface{}{"channel": "whatsapp"}, nil))
+	s.Require().Equal(http.StatusOK, s.do(fx, "PATCH", "/orders/"+orderID+"/status", map[string]interface{}{"status": "placed"}, nil))
+	s.Require().Equal(http.StatusOK, s.do(fx, "PATCH", "/orders/"+orderID+"/payment-status", map[string]interface{}{"paymentStatus": "paid"}, nil))
+
+	var note map[string]interface{}
+	s.Require().Equal(http.StatusCreated, s.do(fx, "POST", "/orders/"+orderID+"/notes", map[string]interface{}{"content": "called customer"}, &note))
+	noteID := note["id"].(string)
+	s.Require().Equal(http.StatusOK, s.do(fx, "PATCH", "/orders/"+orderID+"/notes/"+noteID, map[string]interface{}{"content": "called customer twice"}, nil))
+	s.Require().Equal(http.StatusNoContent, s.do(fx, "DELETE", "/orders/"+orderID+"/notes/"+noteID, nil, nil))
+
+	events := s.timeline(fx, orderID)
+	s.Equal([]string{
+		"created", "note_added", "updated", "status_changed", "payment_status_changed",
+		"note_added", "note_updated", "note_deleted",
+	}, eventTypes(events))
+
+	updated := events[2]["changes"].([]interface{})
+	s.Require().Len(updated, 1)
+	s.Equal(map[string]interface{}{"field": "channel", "from": "instagram", "to": "whatsapp"}, updated[0])
+
+	status := events[3]["changes"].([]interface{})
+	s.Equal(map[string]interface{}{"field": "status", "from": "pending", "to": "placed"}, status[0])
+
+	payment := events[4]["changes"].([]interface{})
+	s.Equal(map[string]interface{}{"field": "paymentStatus", "from": "pending", "to": "paid"}, payment[0])
+
+	edited := events[6]["changes"].([]interface{})[0].(map[string]interface{})
+	s.Equal("called customer", edited["from"])
+	s.Equal("called customer twice", edited["to"])
+}
+
+func (s *OrderTimelineSuite) TestTimeline_NoEventForNoopUpdate() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	orderID := s.createOrder(fx)
+
+	s.Require().Equal(http.StatusOK, s.do(fx, "PATCH", "/orders/"+orderID, map[string]interface{}{"channel": "instagram"}, nil))
+	s.Equal([]string{"created", "note_added"}, eventTypes(s.timeline(fx, orderID)))
+}
+
+func (s *OrderTimelineSuite) TestTimeline_OtherBusinessOrderNotFound() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	other := s.setup(ctx)
+	orderID := s.createOrder(other)
+
+	var body map[string]interface{}
+	status := s.do(fx, "GET", "/orders/"+orderID+"/timeline", nil, &body)
+	s.Equal(http.StatusNotFound, status, body)
+}
+
+func TestOrderTimelineSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(OrderTimelineSuite))
+}
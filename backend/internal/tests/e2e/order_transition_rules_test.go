@@ -0,0 +1,195 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var orderTransitionRuleTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes", "order_events",
+	"order_transition_rules", "tasks",
+}
+
+// OrderTransitionRulesSuite tests the per-business side effects of order status changes.
+type OrderTransitionRulesSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *OrderTransitionRulesSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *OrderTransitionRulesSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, orderTransitionRuleTables...))
+}
+
+func (s *OrderTransitionRulesSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, orderTransitionRuleTables...))
+}
+
+func (s *OrderTransitionRulesSuite) setup(ctx context.Context) (*testutils.Owner, *business.Business) {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	return owner, biz
+}
+
+func (s *OrderTransitionRulesSuite) order(ctx context.Context, biz *business.Business, status order.OrderStatus, opts ...testutils.Option[order.Order]) *order.Order {
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	opts = append([]testutils.Option[order.Order]{func(o *order.Order) { o.Status = status }}, opts...)
+	ord, err := s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: v, Quantity: 1}}, opts...)
+	s.Require().NoError(err)
+	return ord
+}
+
+func (s *OrderTransitionRulesSuite) do(owner *testutils.Owner, biz *business.Business, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+biz.Descriptor+path, payload, owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func (s *OrderTransitionRulesSuite) problemCode(body map[string]interface{}) interface{} {
+	return body["extensions"].(map[string]interface{})["code"]
+}
+
+func (s *OrderTransitionRulesSuite) createRule(owner *testutils.Owner, biz *business.Business, rule map[string]interface{}) map[string]interface{} {
+	status, body := s.do(owner, biz, "POST", "/order-transition-rules", rule)
+	s.Require().Equal(http.StatusCreated, status, body)
+	return body
+}
+
+func (s *OrderTransitionRulesSuite) TestCreateRule_Validation() {
+	owner, biz := s.setup(context.Background())
+
+	status, body := s.do(owner, biz, "POST", "/order-transition-rules", map[string]interface{}{"toStatus": "cancelled", "action": "mark_paid"})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("order.invalid_transition_rule", s.problemCode(body))
+
+	status, body = s.do(owner, biz, "POST", "/order-transition-rules", map[string]interface{}{"fromStatus": "pending", "toStatus": "shipped", "action": "require_payment"})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("order.invalid_transition_rule", s.problemCode(body))
+
+	status, body = s.do(owner, biz, "POST", "/order-transition-rules", map[string]interface{}{"toStatus": "shipped", "action": "notify_customer"})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("order.invalid_transition_rule", s.problemCode(body))
+
+	rule := s.createRule(owner, biz, map[string]interface{}{"toStatus": "shipped", "action": "require_payment"})
+	s.Equal(true, rule["enabled"])
+	status, body = s.do(owner, biz, "POST", "/order-transition-rules", map[string]interface{}{"toStatus": "shipped", "action": "require_payment"})
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("order.transition_rule_exists", s.problemCode(body))
+
+	status, body = s.do(owner, biz, "PATCH", "/order-transition-rules/"+rule["id"].(string), map[string]interface{}{"enabled": false})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(false, body["enabled"])
+}
+
+func (s *OrderTransitionRulesSuite) TestRequirePayment_BlocksUnpaidShipment() {
+	ctx := context.Background()
+	owner, biz := s.setup(ctx)
+	rule := s.createRule(owner, biz, map[string]interface{}{"toStatus": "shipped", "action": "require_payment"})
+	ord := s.order(ctx, biz, order.OrderStatusPlaced)
+
+	status, body := s.do(owner, biz, "PATCH", "/orders/"+ord.ID+"/status", map[string]interface{}{"status": "shipped"})
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal("order.transition_requires_payment", s.problemCode(body))
+
+	// A disabled rule is skipped.
+	status, body = s.do(owner, biz, "PATCH", "/order-transition-rules/"+rule["id"].(string), map[string]interface{}{"enabled": false})
+	s.Require().Equal(http.StatusOK, status, body)
+	status, body = s.do(owner, biz, "PATCH", "/orders/"+ord.ID+"/status", map[string]interface{}{"status": "shipped"})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	// Paid orders pass.
+	s.createRule(owner, biz, map[string]interface{}{"toStatus": "ready_for_shipment", "action": "require_payment"})
+	paid := s.order(ctx, biz, order.OrderStatusPlaced, func(o *order.Order) { o.PaymentStatus = order.OrderPaymentStatusPaid })
+	status, body = s.do(owner, biz, "PATCH", "/orders/"+paid.ID+"/status", map[string]interface{}{"status": "ready_for_shipment"})
+	s.Require().Equal(http.StatusOK, status, body)
+}
+
+func (s *OrderTransitionRulesSuite) TestMarkPaid_OnFulfilment() {
+	ctx := context.Background()
+	owner, biz := s.setup(ctx)
+	s.createRule(owner, biz, map[string]interface{}{"fromStatus": "shipped", "toStatus": "fulfilled", "action": "mark_paid"})
+	ord := s.order(ctx, biz, order.OrderStatusShipped)
+
+	status, body := s.do(owner, biz, "PATCH", "/orders/"+ord.ID+"/status", map[string]interface{}{"status": "fulfilled"})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("fulfilled", body["status"])
+	s.Equal("paid", body["paymentStatus"])
+
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+biz.Descriptor+"/orders/"+ord.ID+"/timeline", nil, owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var events []map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &events))
+	types := eventTypes(events)
+	s.Contains(types, string(order.OrderEventStatusChanged))
+	s.Contains(types, string(order.OrderEventPaymentStatusChanged))
+}
+
+func (s *OrderTransitionRulesSuite) TestNotifyCustomer_OpensTaskWithWhatsappLink() {
+	ctx := context.Background()
+	owner, biz := s.setup(ctx)
+	s.createRule(owner, biz, map[string]interface{}{
+		"toStatus": "shipped", "action": "notify_customer",
+		"message": "Hi {customerName}, order {orderNumber} is {status}!",
+	})
+	cust, err := s.factory.Customer(ctx, biz.ID, func(c *customer.Customer) {
+		c.Name = "Mona"
+		c.WhatsappNumber = transformer.ToNullableString("+971 50 123 4567")
+	})
+	s.Require().NoError(err)
+	ord := s.order(ctx, biz, order.OrderStatusPlaced, func(o *order.Order) { o.CustomerID = cust.ID })
+
+	status, body := s.do(owner, biz, "PATCH", "/orders/"+ord.ID+"/status", map[string]interface{}{"status": "shipped"})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	var task map[string]interface{}
+	s.Require().Eventually(func() bool {
+		status, body := s.do(owner, biz, "GET", "/tasks?orderId="+ord.ID, nil)
+		if status != http.StatusOK {
+			return false
+		}
+		items, _ := body["items"].([]interface{})
+		if len(items) == 0 {
+			return false
+		}
+		task = items[0].(map[string]interface{})
+		return true
+	}, 2*time.Second, 50*time.Millisecond)
+	s.Equal("follow_up_customer", task["type"])
+	s.Equal("Message customer about order "+ord.OrderNumber, task["title"])
+	description := task["description"].(string)
+	s.True(strings.HasPrefix(description, "Hi Mona, order "+ord.OrderNumber+" is shipped!"), description)
+	s.Contains(description, "https://wa.me/971501234567?text=")
+}
+
+func TestOrderTransitionRulesSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(OrderTransitionRulesSuite))
+}
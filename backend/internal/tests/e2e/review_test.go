@@ -0,0 +1,187 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/domain/review"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var reviewTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes",
+	"product_reviews", "review_requests",
+}
+
+// ReviewSuite tests storefront reviews and questions, seller moderation and review requests.
+type ReviewSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *ReviewSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *ReviewSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, reviewTables...))
+}
+
+func (s *ReviewSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, reviewTables...))
+}
+
+func (s *ReviewSuite) setup(ctx context.Context, opts ...testutils.Option[business.Business]) (*testutils.Owner, *business.Business, *inventory.Product) {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID, append([]testutils.Option[business.Business]{func(b *business.Business) {
+		b.StorefrontEnabled = true
+	}}, opts...)...)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	return owner, biz, prod
+}
+
+func (s *ReviewSuite) do(owner *testutils.Owner, biz *business.Business, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+biz.Descriptor+path, payload, owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *ReviewSuite) public(method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.Request(method, path, payload)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func (s *ReviewSuite) TestReviewModeration() {
+	ctx := context.Background()
+	owner, biz, prod := s.setup(ctx)
+	reviewsPath := "/v1/storefront/" + biz.StorefrontPublicID + "/products/" + prod.ID + "/reviews"
+
+	status, body := s.public("POST", reviewsPath, map[string]interface{}{
+		"body": "No rating", "authorName": "Sara", "authorEmail": "sara@example.com",
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("review.invalid_rating", body["extensions"].(map[string]interface{})["code"])
+	status, body = s.public("POST", reviewsPath, map[string]interface{}{
+		"kind": "question", "rating": 4, "body": "Is it cotton?", "authorName": "Sara", "authorEmail": "sara@example.com",
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+	status, _ = s.public("POST", "/v1/storefront/"+biz.StorefrontPublicID+"/products/prd_missing/reviews", map[string]interface{}{
+		"rating": 5, "body": "Great", "authorName": "Sara", "authorEmail": "sara@example.com",
+	})
+	s.Equal(http.StatusNotFound, status)
+
+	status, body = s.public("POST", reviewsPath, map[string]interface{}{
+		"rating": 4, "title": "Lovely", "body": "Fits well", "authorName": "Sara", "authorEmail": "Sara@Example.com",
+	})
+	s.Require().Equal(http.StatusAccepted, status, body)
+	s.NotContains(body, "authorEmail")
+	reviewID := body["id"].(string)
+
+	question := &review.Review{BusinessID: biz.ID, ProductID: prod.ID, Kind: review.ReviewKindQuestion, Status: review.ReviewStatusPending, Body: "Is it cotton?", AuthorName: "Omar", AuthorEmail: "omar@example.com"}
+	s.Require().NoError(database.NewRepository[review.Review](testEnv.Database).CreateOne(ctx, question))
+
+	status, body = s.public("GET", reviewsPath, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Empty(body["items"], "pending reviews are not public")
+
+	status, body = s.do(owner, biz, "GET", "/reviews?status=pending", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Len(body["items"], 2)
+	status, body = s.do(owner, biz, "GET", "/reviews?kind=review", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Require().Len(body["items"], 1)
+	s.Equal("sara@example.com", body["items"].([]interface{})[0].(map[string]interface{})["authorEmail"])
+
+	status, body = s.do(owner, biz, "PATCH", "/reviews/"+reviewID, map[string]interface{}{"status": "approved"})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("approved", body["status"])
+	s.Equal(owner.User.ID, body["moderatedById"])
+	status, body = s.do(owner, biz, "PATCH", "/reviews/"+question.ID, map[string]interface{}{"status": "approved", "reply": "Yes, 100% cotton."})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("Yes, 100% cotton.", body["reply"])
+	s.NotEmpty(body["repliedAt"])
+
+	status, body = s.public("GET", reviewsPath, nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Len(body["items"], 2)
+	status, body = s.public("GET", reviewsPath+"?kind=question", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Require().Len(body["items"], 1)
+	s.Equal("Yes, 100% cotton.", body["items"].([]interface{})[0].(map[string]interface{})["reply"])
+
+	status, body = s.public("GET", "/v1/storefront/"+biz.StorefrontPublicID+"/catalog", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	rating := body["products"].([]interface{})[0].(map[string]interface{})["rating"].(map[string]interface{})
+	s.Equal("4", rating["average"])
+	s.Equal(float64(1), rating["count"])
+
+	status, body = s.do(owner, biz, "PATCH", "/reviews/"+reviewID, map[string]interface{}{"status": "hidden"})
+	s.Require().Equal(http.StatusOK, status, body)
+	status, body = s.public("GET", "/v1/storefront/"+biz.StorefrontPublicID+"/catalog", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.NotContains(body["products"].([]interface{})[0].(map[string]interface{}), "rating")
+
+	status, _ = s.do(owner, biz, "DELETE", "/reviews/"+reviewID, nil)
+	s.Equal(http.StatusNoContent, status)
+	status, _ = s.do(owner, biz, "GET", "/reviews/"+reviewID, nil)
+	s.Equal(http.StatusNotFound, status)
+}
+
+func (s *ReviewSuite) TestFulfilledOrder_SendsReviewRequestOnce() {
+	ctx := context.Background()
+	owner, biz, prod := s.setup(ctx, func(b *business.Business) { b.StorefrontReviewRequests = true })
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	ord, err := s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: v, Quantity: 1}}, func(o *order.Order) {
+		o.Status = order.OrderStatusPlaced
+	})
+	s.Require().NoError(err)
+
+	for _, next := range []string{"shipped", "fulfilled"} {
+		status, body := s.do(owner, biz, "PATCH", "/orders/"+ord.ID+"/status", map[string]interface{}{"status": next})
+		s.Require().Equal(http.StatusOK, status, body)
+	}
+
+	repo := database.NewRepository[review.ReviewRequest](testEnv.Database)
+	var count int64
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		count, err = repo.Count(ctx, repo.ScopeEquals(review.ReviewRequestSchema.OrderID, ord.ID))
+		s.Require().NoError(err)
+		if count > 0 {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	s.Equal(int64(1), count, "expected a review request for the fulfilled order")
+}
+
+func TestReviewSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(ReviewSuite))
+}
@@ -0,0 +1,120 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var sampleDataTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes",
+	"expenses", "sample_data_sets", "sample_records",
+}
+
+// SampleDataSuite tests loading and removing the onboarding sample data of a business.
+type SampleDataSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *SampleDataSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *SampleDataSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, sampleDataTables...))
+}
+
+func (s *SampleDataSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, sampleDataTables...))
+}
+
+func (s *SampleDataSuite) setup(ctx context.Context) (*testutils.Owner, *business.Business) {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	return owner, biz
+}
+
+func (s *SampleDataSuite) do(biz *business.Business, token, method, path string) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+biz.Descriptor+path, nil, token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *SampleDataSuite) count(table, businessID string) int64 {
+	var n int64
+	s.Require().NoError(testEnv.Database.GetDB().Table(table).Where("business_id = ? AND deleted_at IS NULL", businessID).Count(&n).Error)
+	return n
+}
+
+func (s *SampleDataSuite) TestSampleData_LoadIsIdempotentAndRemovable() {
+	ctx := context.Background()
+	owner, biz := s.setup(ctx)
+
+	status, body := s.do(biz, owner.Token, "GET", "/sample-data")
+	s.Equal(http.StatusNotFound, status, body)
+
+	status, body = s.do(biz, owner.Token, "POST", "/sample-data")
+	s.Require().Equal(http.StatusOK, status, body)
+	setID := body["id"].(string)
+	s.Equal("loaded", body["status"])
+	s.NotNil(body["loadedAt"])
+	s.Equal(float64(s.count("products", biz.ID)), body["products"])
+	s.Equal(float64(s.count("customers", biz.ID)), body["customers"])
+	s.Equal(float64(s.count("orders", biz.ID)), body["orders"])
+	s.Equal(float64(s.count("expenses", biz.ID)), body["expenses"])
+	s.Positive(s.count("orders", biz.ID))
+
+	status, body = s.do(biz, owner.Token, "POST", "/sample-data")
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(setID, body["id"])
+	s.Equal(float64(s.count("orders", biz.ID)), body["orders"])
+
+	status, body = s.do(biz, owner.Token, "DELETE", "/sample-data")
+	s.Require().Equal(http.StatusNoContent, status, body)
+	for _, table := range []string{"categories", "products", "customers", "orders", "expenses"} {
+		s.Zero(s.count(table, biz.ID), table)
+	}
+	status, body = s.do(biz, owner.Token, "GET", "/sample-data")
+	s.Equal(http.StatusNotFound, status, body)
+
+	status, body = s.do(biz, owner.Token, "DELETE", "/sample-data")
+	s.Equal(http.StatusNoContent, status, body)
+
+	status, body = s.do(biz, owner.Token, "POST", "/sample-data")
+	s.Require().Equal(http.StatusOK, status, body)
+	s.NotEqual(setID, body["id"])
+}
+
+func (s *SampleDataSuite) TestSampleData_RejectsBusinessWithData() {
+	ctx := context.Background()
+	owner, biz := s.setup(ctx)
+	_, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+
+	status, body := s.do(biz, owner.Token, "POST", "/sample-data")
+	s.Equal(http.StatusConflict, status, body)
+	s.Equal(int64(1), s.count("customers", biz.ID))
+}
+
+func TestSampleDataSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(SampleDataSuite))
+}
@@ -0,0 +1,178 @@
+package e2e_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/accounting"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/domain/search"
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+var searchTables = append([]string{"customers", "customer_addresses", "orders", "order_items", "expenses"}, inventoryTables...)
+
+// SearchSuite tests the business-wide search endpoint.
+type SearchSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *SearchSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *SearchSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, searchTables...))
+}
+
+func (s *SearchSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, searchTables...))
+}
+
+func (s *SearchSuite) search(biz *business.Business, query, token string) (int, search.SearchResponse) {
+	resp, err := s.helper.Client.AuthenticatedRequest("GET", "/v1/businesses/"+biz.Descriptor+"/search?"+query, nil, token)
+	s.Require().NoError(err)
+	var body search.SearchResponse
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return resp.StatusCode, body
+	}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+// seed creates one order, customer, product and expense matching "zephyr" and returns the order.
+func (s *SearchSuite) seed(ctx context.Context, biz *business.Business) *order.Order {
+	cust, err := s.factory.Customer(ctx, biz.ID, func(c *customer.Customer) { c.Name = "Zephyr Adams" })
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID, func(p *inventory.Product) { p.Name = "Zephyr Lamp" })
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	ord, err := s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: v, Quantity: 1}}, func(o *order.Order) {
+		o.CustomerID = cust.ID
+	})
+	s.Require().NoError(err)
+	expense := &accounting.Expense{
+		BusinessID: biz.ID,
+		Amount:     decimal.NewFromInt(40),
+		Currency:   biz.Currency,
+		Category:   accounting.ExpenseCategoryMarketing,
+		Type:       accounting.ExpenseTypeOneTime,
+		OccurredOn: time.Now().UTC(),
+		Note:       sql.NullString{String: "Zephyr photo shoot", Valid: true},
+	}
+	s.Require().NoError(database.NewRepository[accounting.Expense](testEnv.Database).CreateOne(ctx, expense))
+	return ord
+}
+
+func (s *SearchSuite) types(body search.SearchResponse) map[search.ResultType]int {
+	counts := map[search.ResultType]int{}
+	for _, item := range body.Items {
+		counts[item.Type]++
+	}
+	return counts
+}
+
+func (s *SearchSuite) TestSearchReturnsRankedMixedResults() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	ord := s.seed(ctx, biz)
+
+	other, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	otherBiz, err := s.factory.Business(ctx, other.Workspace.ID)
+	s.Require().NoError(err)
+	s.seed(ctx, otherBiz)
+
+	status, body := s.search(biz, "q=zephyr", owner.Token)
+	s.Require().Equal(http.StatusOK, status)
+	s.Equal("zephyr", body.Query)
+	s.Equal(map[search.ResultType]int{
+		search.ResultTypeOrder:    1,
+		search.ResultTypeCustomer: 1,
+		search.ResultTypeProduct:  1,
+		search.ResultTypeExpense:  1,
+	}, s.types(body), "results of other businesses are not returned")
+	for i := 1; i < len(body.Items); i++ {
+		s.GreaterOrEqual(body.Items[i-1].Score, body.Items[i].Score)
+	}
+
+	// typo tolerance
+	_, body = s.search(biz, "q=zephir", owner.Token)
+	s.NotEmpty(body.Items)
+
+	// the exact order number ranks first
+	_, body = s.search(biz, "q="+url.QueryEscape(ord.OrderNumber), owner.Token)
+	s.Require().NotEmpty(body.Items)
+	s.Equal(search.ResultTypeOrder, body.Items[0].Type)
+	s.Equal(ord.ID, body.Items[0].ID)
+
+	_, body = s.search(biz, "q=zephyr&types=customer&types=expense&limit=1", owner.Token)
+	s.Require().Len(body.Items, 1)
+	s.Contains([]search.ResultType{search.ResultTypeCustomer, search.ResultTypeExpense}, body.Items[0].Type)
+}
+
+func (s *SearchSuite) TestSearchValidationAndPermissions() {
+	ctx := context.Background()
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	s.seed(ctx, biz)
+
+	for _, q := range []string{"", "q=z", "q=%20%20", "q=zephyr&types=invoice", "q=zephyr&limit=51"} {
+		status, _ := s.search(biz, q, owner.Token)
+		s.Equal(http.StatusBadRequest, status, q)
+	}
+
+	member := &account.User{
+		WorkspaceID:        owner.Workspace.ID,
+		Role:               role.RoleUser,
+		FirstName:          "Staff",
+		LastName:           "Member",
+		Email:              fmt.Sprintf("staff-%s@example.com", owner.Workspace.ID),
+		IsEmailVerified:    true,
+		RestrictFinancials: true,
+	}
+	s.Require().NoError(database.NewRepository[account.User](testEnv.Database).CreateOne(ctx, member))
+	token, err := auth.NewJwtToken(member.ID, member.WorkspaceID, member.AuthVersion)
+	s.Require().NoError(err)
+
+	status, body := s.search(biz, "q=zephyr", token)
+	s.Require().Equal(http.StatusOK, status)
+	counts := s.types(body)
+	s.Zero(counts[search.ResultTypeExpense], "expenses are hidden from members restricted from financials")
+	s.Equal(1, counts[search.ResultTypeCustomer])
+
+	status, body = s.search(biz, "q=zephyr&types=expense", token)
+	s.Require().Equal(http.StatusOK, status)
+	s.Empty(body.Items)
+}
+
+func TestSearchSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(SearchSuite))
+}
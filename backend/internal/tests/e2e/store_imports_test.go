@@ -0,0 +1,211 @@
+package e2e_test
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var storeImportTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants", "variant_cost_changes", "stock_movements",
+	"store_imports", "store_import_files", "store_import_records",
+}
+
+const shopifyProductsExport = `Handle,Title,Body (HTML),Type,Option1 Name,Option1 Value,Variant SKU,Variant Inventory Qty,Variant Price,Variant Barcode,Image Src,Variant Image,Cost per item
+linen-shirt,Linen Shirt,<p>Breathable <b>linen</b></p>,Shirts,Size,S,LS-S,4,120.00,,https://cdn.example.com/shirt.jpg,,55.00
+linen-shirt,,,,,M,LS-M,6,125.00,,https://cdn.example.com/shirt-back.jpg,,55.00
+soy-candle,Soy Candle,Hand poured,,Title,Default Title,,10,40.00,,,,12.50
+`
+
+// StoreImportsSuite tests importing a Shopify or WooCommerce store.
+type StoreImportsSuite struct {
+	suite.Suite
+	helper  *InventoryTestHelper
+	factory *testutils.Factory
+}
+
+func (s *StoreImportsSuite) SetupSuite() {
+	s.helper = NewInventoryTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *StoreImportsSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, storeImportTables...))
+}
+
+func (s *StoreImportsSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, storeImportTables...))
+}
+
+type storeImportFixture struct {
+	owner *testutils.Owner
+	biz   *business.Business
+}
+
+func (s *StoreImportsSuite) setup(ctx context.Context) *storeImportFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	return &storeImportFixture{owner: owner, biz: biz}
+}
+
+func (s *StoreImportsSuite) do(fx *storeImportFixture, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+func (s *StoreImportsSuite) importFile(fx *storeImportFixture, platform, content string) (int, map[string]interface{}) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	s.Require().NoError(w.WriteField("platform", platform))
+	part, err := w.CreateFormFile("file", "products_export.csv")
+	s.Require().NoError(err)
+	_, err = part.Write([]byte(content))
+	s.Require().NoError(err)
+	s.Require().NoError(w.Close())
+
+	resp, err := s.helper.Client.AuthenticatedRequestRaw("POST", "/v1/businesses/"+fx.biz.Descriptor+"/store-imports/file", buf.Bytes(), map[string]string{"Content-Type": w.FormDataContentType()}, fx.owner.Token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body
+}
+
+// waitForImport polls the import until it leaves pending/running.
+func (s *StoreImportsSuite) waitForImport(fx *storeImportFixture, importID string) map[string]interface{} {
+	var body map[string]interface{}
+	s.Require().Eventually(func() bool {
+		var status int
+		status, body = s.do(fx, "GET", "/store-imports/"+importID, nil)
+		s.Require().Equal(http.StatusOK, status, body)
+		return body["status"] == "completed" || body["status"] == "failed"
+	}, 10*time.Second, 100*time.Millisecond)
+	return body
+}
+
+func (s *StoreImportsSuite) counts(body map[string]interface{}, kind string) map[string]interface{} {
+	return body["progress"].(map[string]interface{})[kind].(map[string]interface{})
+}
+
+func (s *StoreImportsSuite) TestShopifyExportImportsProductsOnce() {
+	fx := s.setup(context.Background())
+
+	status, body := s.importFile(fx, "shopify", shopifyProductsExport)
+	s.Require().Equal(http.StatusAccepted, status, body)
+	s.Equal("pending", body["status"])
+	s.Equal("file", body["source"])
+
+	body = s.waitForImport(fx, body["id"].(string))
+	s.Require().Equal("completed", body["status"], body)
+	s.Equal("done", body["stage"])
+	s.Equal(float64(2), s.counts(body, "products")["imported"])
+	s.Equal(float64(3), s.counts(body, "variants")["imported"])
+
+	db := testEnv.Database.GetDB()
+	var shirt inventory.Product
+	s.Require().NoError(db.Where("business_id = ? AND name = ?", fx.biz.ID, "Linen Shirt").First(&shirt).Error)
+	s.Equal("Breathable linen", shirt.Description)
+	s.Len(shirt.Photos, 2)
+	var category inventory.Category
+	s.Require().NoError(db.Where("id = ?", shirt.CategoryID).First(&category).Error)
+	s.Equal("Shirts", category.Name)
+
+	var variants []inventory.Variant
+	s.Require().NoError(db.Where("product_id = ?", shirt.ID).Order("code").Find(&variants).Error)
+	s.Require().Len(variants, 2)
+	s.Equal("M", variants[1].Code)
+	s.Equal("LS-M", variants[1].SKU)
+	s.Equal("125", variants[1].SalePrice.String())
+	s.Equal("55", variants[1].CostPrice.String())
+	s.Equal(6, variants[1].StockQuantity)
+
+	var candle inventory.Variant
+	s.Require().NoError(db.Where("business_id = ? AND name = ?", fx.biz.ID, "Soy Candle - default").First(&candle).Error)
+	s.NotEmpty(candle.SKU)
+
+	// importing the same export again skips what was already brought over
+	status, body = s.importFile(fx, "shopify", shopifyProductsExport)
+	s.Require().Equal(http.StatusAccepted, status, body)
+	body = s.waitForImport(fx, body["id"].(string))
+	s.Require().Equal("completed", body["status"], body)
+	s.Equal(float64(0), s.counts(body, "products")["imported"])
+	s.Equal(float64(2), s.counts(body, "products")["skipped"])
+	var products int64
+	s.Require().NoError(db.Model(&inventory.Product{}).Where("business_id = ?", fx.biz.ID).Count(&products).Error)
+	s.Equal(int64(2), products)
+
+	var files int64
+	s.Require().NoError(db.Table("store_import_files").Count(&files).Error)
+	s.Equal(int64(0), files)
+}
+
+func (s *StoreImportsSuite) TestWooCommerceExportWithVariations() {
+	fx := s.setup(context.Background())
+	export := `ID,Type,SKU,Name,Description,Stock,Regular price,Sale price,Categories,Images,Parent,Attribute 1 name,Attribute 1 value(s)
+10,variable,TEE,Basic Tee,Cotton tee,,,,Clothing > Tees,https://cdn.example.com/tee.jpg,,Color,"Red, Blue"
+11,variation,TEE-RED,Basic Tee - Red,,3,30,,,,id:10,Color,Red
+12,variation,TEE-BLUE,Basic Tee - Blue,,0,30,25,,,id:10,Color,Blue
+`
+	status, body := s.importFile(fx, "woocommerce", export)
+	s.Require().Equal(http.StatusAccepted, status, body)
+	body = s.waitForImport(fx, body["id"].(string))
+	s.Require().Equal("completed", body["status"], body)
+	s.Equal(float64(1), s.counts(body, "products")["imported"])
+	s.Equal(float64(2), s.counts(body, "variants")["imported"])
+
+	var tee inventory.Product
+	s.Require().NoError(testEnv.Database.GetDB().Where("business_id = ? AND name = ?", fx.biz.ID, "Basic Tee").First(&tee).Error)
+	var category inventory.Category
+	s.Require().NoError(testEnv.Database.GetDB().Where("id = ?", tee.CategoryID).First(&category).Error)
+	s.Equal("Tees", category.Name)
+}
+
+func (s *StoreImportsSuite) TestRejectsInvalidRequests() {
+	fx := s.setup(context.Background())
+
+	status, body := s.importFile(fx, "shopify", "Name,Price\nShirt,10\n")
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("store_import.invalid_file", body["extensions"].(map[string]interface{})["code"])
+
+	status, body = s.do(fx, "POST", "/store-imports", map[string]interface{}{
+		"platform": "shopify", "storeUrl": "https://example.com", "accessToken": "shpat_x",
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("store_import.invalid_store_url", body["extensions"].(map[string]interface{})["code"])
+
+	status, body = s.do(fx, "POST", "/store-imports", map[string]interface{}{
+		"platform": "woocommerce", "storeUrl": "http://shop.example.com",
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+
+	status, body = s.do(fx, "POST", "/store-imports", map[string]interface{}{
+		"platform": "woocommerce", "storeUrl": "https://shop.example.com", "consumerKey": "ck_x",
+	})
+	s.Equal(http.StatusBadRequest, status, body)
+	s.Equal("store_import.missing_credentials", body["extensions"].(map[string]interface{})["code"])
+
+	status, body = s.do(fx, "GET", "/store-imports/sim_missing", nil)
+	s.Equal(http.StatusNotFound, status, body)
+}
+
+func TestStoreImportsSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(StoreImportsSuite))
+}
@@ -0,0 +1,239 @@
+package e2e_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/domain/storefront"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/hash"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var storefrontCustomerAuthTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items",
+}
+
+// StorefrontCustomerAuthSuite tests passwordless customer login and the customer portal.
+type StorefrontCustomerAuthSuite struct {
+	suite.Suite
+	client  *testutils.HTTPClient
+	factory *testutils.Factory
+	storage *storefront.Storage
+}
+
+func (s *StorefrontCustomerAuthSuite) SetupSuite() {
+	s.client = testutils.NewHTTPClient(e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+	s.storage = storefront.NewStorage(testEnv.Database, testEnv.Cache)
+}
+
+func (s *StorefrontCustomerAuthSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, storefrontCustomerAuthTables...))
+}
+
+func (s *StorefrontCustomerAuthSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, storefrontCustomerAuthTables...))
+}
+
+func (s *StorefrontCustomerAuthSuite) setup(ctx context.Context) (*testutils.Owner, *business.Business, *customer.Customer) {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID, func(b *business.Business) { b.StorefrontEnabled = true })
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID, func(c *customer.Customer) {
+		c.PhoneCode = transformer.ToNullableString("+971")
+		c.PhoneNumber = transformer.ToNullableString("501234567")
+	})
+	s.Require().NoError(err)
+	return owner, biz, cust
+}
+
+// issueLogin replaces the customer's pending login with one of a known code.
+func (s *StorefrontCustomerAuthSuite) issueLogin(biz *business.Business, cust *customer.Customer, code string) string {
+	codeHash, err := hash.Password(code)
+	s.Require().NoError(err)
+	token, _, err := s.storage.CreateCustomerLogin(&storefront.CustomerLoginPayload{
+		BusinessID: biz.ID,
+		CustomerID: cust.ID,
+		Channel:    storefront.LoginChannelEmail,
+		CodeHash:   codeHash,
+	})
+	s.Require().NoError(err)
+	return token
+}
+
+func (s *StorefrontCustomerAuthSuite) do(method, path string, payload interface{}, token string) (int, map[string]interface{}, []interface{}) {
+	resp, err := s.client.AuthenticatedRequest(method, path, payload, token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNoContent {
+		return resp.StatusCode, nil, nil
+	}
+	if strings.HasSuffix(path, "/addresses") && resp.StatusCode == http.StatusOK {
+		var items []interface{}
+		s.Require().NoError(testutils.DecodeJSON(resp, &items))
+		return resp.StatusCode, nil, items
+	}
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	return resp.StatusCode, body, nil
+}
+
+func (s *StorefrontCustomerAuthSuite) path(biz *business.Business, p string) string {
+	return "/v1/storefront/" + biz.StorefrontPublicID + p
+}
+
+func (s *StorefrontCustomerAuthSuite) signIn(biz *business.Business, cust *customer.Customer) string {
+	token := s.issueLogin(biz, cust, "123456")
+	status, body, _ := s.do("POST", s.path(biz, "/auth/verify"), map[string]interface{}{"token": token}, "")
+	s.Require().Equal(http.StatusOK, status, body)
+	return body["token"].(string)
+}
+
+func (s *StorefrontCustomerAuthSuite) TestLoginWithEmailCode() {
+	_, biz, cust := s.setup(context.Background())
+
+	status, body, _ := s.do("POST", s.path(biz, "/auth/login"), map[string]interface{}{"email": cust.Email.String}, "")
+	s.Require().Equal(http.StatusNoContent, status, body)
+	// asking again right away is refused
+	status, body, _ = s.do("POST", s.path(biz, "/auth/login"), map[string]interface{}{"email": cust.Email.String}, "")
+	s.Equal(http.StatusTooManyRequests, status, body)
+
+	s.issueLogin(biz, cust, "482913")
+	status, body, _ = s.do("POST", s.path(biz, "/auth/verify"), map[string]interface{}{
+		"email": strings.ToUpper(cust.Email.String),
+		"code":  "482913",
+	}, "")
+	s.Require().Equal(http.StatusOK, status, body)
+	s.NotEmpty(body["token"])
+	s.NotNil(body["expiresAt"])
+	s.Equal(cust.ID, body["customer"].(map[string]interface{})["id"])
+	token := body["token"].(string)
+
+	status, body, _ = s.do("GET", s.path(biz, "/me"), nil, token)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(cust.Name, body["name"])
+
+	// codes are single use
+	status, body, _ = s.do("POST", s.path(biz, "/auth/verify"), map[string]interface{}{"email": cust.Email.String, "code": "482913"}, "")
+	s.Equal(http.StatusUnauthorized, status, body)
+	s.Equal("storefront.invalid_login_code", body["extensions"].(map[string]interface{})["code"])
+}
+
+func (s *StorefrontCustomerAuthSuite) TestLoginWithWhatsappCode() {
+	_, biz, cust := s.setup(context.Background())
+
+	status, body, _ := s.do("POST", s.path(biz, "/auth/login"), map[string]interface{}{"phone": "+971 50 123 4567"}, "")
+	s.Require().Equal(http.StatusNoContent, status, body)
+
+	s.issueLogin(biz, cust, "246810")
+	status, body, _ = s.do("POST", s.path(biz, "/auth/verify"), map[string]interface{}{"phone": "971501234567", "code": "246810"}, "")
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(cust.ID, body["customer"].(map[string]interface{})["id"])
+}
+
+func (s *StorefrontCustomerAuthSuite) TestUnknownContactIsNotRevealed() {
+	_, biz, _ := s.setup(context.Background())
+
+	status, body, _ := s.do("POST", s.path(biz, "/auth/login"), map[string]interface{}{"email": "nobody@example.com"}, "")
+	s.Equal(http.StatusNoContent, status, body)
+
+	status, body, _ = s.do("POST", s.path(biz, "/auth/verify"), map[string]interface{}{"email": "nobody@example.com", "code": "123456"}, "")
+	s.Equal(http.StatusUnauthorized, status, body)
+
+	status, body, _ = s.do("POST", s.path(biz, "/auth/login"), map[string]interface{}{}, "")
+	s.Equal(http.StatusBadRequest, status, body)
+}
+
+func (s *StorefrontCustomerAuthSuite) TestWrongCodesBurnTheLogin() {
+	_, biz, cust := s.setup(context.Background())
+	s.issueLogin(biz, cust, "111111")
+
+	for i := 0; i < 5; i++ {
+		status, body, _ := s.do("POST", s.path(biz, "/auth/verify"), map[string]interface{}{"email": cust.Email.String, "code": "999999"}, "")
+		s.Require().Equal(http.StatusUnauthorized, status, body)
+	}
+	status, body, _ := s.do("POST", s.path(biz, "/auth/verify"), map[string]interface{}{"email": cust.Email.String, "code": "111111"}, "")
+	s.Equal(http.StatusUnauthorized, status, body)
+}
+
+func (s *StorefrontCustomerAuthSuite) TestPortalShowsOnlyOwnData() {
+	ctx := context.Background()
+	_, biz, cust := s.setup(ctx)
+	other, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	addr, err := s.factory.Address(ctx, cust.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	mine, err := s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: v, Quantity: 2}}, func(o *order.Order) {
+		o.CustomerID = cust.ID
+		o.OrderedAt = time.Now().UTC().Add(-time.Hour)
+	})
+	s.Require().NoError(err)
+	theirs, err := s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: v, Quantity: 1}}, func(o *order.Order) { o.CustomerID = other.ID })
+	s.Require().NoError(err)
+	token := s.signIn(biz, cust)
+
+	status, body, _ := s.do("GET", s.path(biz, "/me/orders"), nil, token)
+	s.Require().Equal(http.StatusOK, status, body)
+	items := body["items"].([]interface{})
+	s.Require().Len(items, 1)
+	got := items[0].(map[string]interface{})
+	s.Equal(mine.OrderNumber, got["orderNumber"])
+	s.Equal(mine.Total.String(), got["total"])
+	s.Len(got["items"], 1)
+	s.Nil(got["cogs"])
+
+	status, body, _ = s.do("GET", s.path(biz, "/me/orders/"+mine.OrderNumber), nil, token)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(mine.OrderNumber, body["orderNumber"])
+
+	status, body, _ = s.do("GET", s.path(biz, "/me/orders/"+theirs.OrderNumber), nil, token)
+	s.Equal(http.StatusNotFound, status, body)
+
+	status, _, addresses := s.do("GET", s.path(biz, "/me/addresses"), nil, token)
+	s.Require().Equal(http.StatusOK, status)
+	s.Require().Len(addresses, 1)
+	s.Equal(addr.ID, addresses[0].(map[string]interface{})["id"])
+}
+
+func (s *StorefrontCustomerAuthSuite) TestTokenIsScopedToItsStorefrontAndCustomer() {
+	ctx := context.Background()
+	owner, biz, cust := s.setup(ctx)
+	otherBiz, err := s.factory.Business(ctx, owner.Workspace.ID, func(b *business.Business) { b.StorefrontEnabled = true })
+	s.Require().NoError(err)
+	token := s.signIn(biz, cust)
+
+	status, body, _ := s.do("GET", s.path(otherBiz, "/me"), nil, token)
+	s.Equal(http.StatusUnauthorized, status, body)
+	// dashboard tokens are not customer tokens
+	status, body, _ = s.do("GET", s.path(biz, "/me"), nil, owner.Token)
+	s.Equal(http.StatusUnauthorized, status, body)
+	status, body, _ = s.do("GET", s.path(biz, "/me"), nil, "")
+	s.Equal(http.StatusUnauthorized, status, body)
+
+	// erased customers lose access
+	s.Require().NoError(testEnv.Database.GetDB().Model(&customer.Customer{}).Where("id = ?", cust.ID).Update("erased_at", time.Now().UTC()).Error)
+	status, body, _ = s.do("GET", s.path(biz, "/me"), nil, token)
+	s.Equal(http.StatusUnauthorized, status, body)
+}
+
+func TestStorefrontCustomerAuthSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(StorefrontCustomerAuthSuite))
+}
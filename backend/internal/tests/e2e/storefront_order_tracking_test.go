@@ -0,0 +1,186 @@
+package e2e_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+var storefrontTrackingTables = []string{
+	"users", "workspaces", "businesses", "shipping_labels",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items",
+}
+
+// StorefrontOrderTrackingSuite tests the public order tracking endpoint.
+type StorefrontOrderTrackingSuite struct {
+	suite.Suite
+	client  *testutils.HTTPClient
+	factory *testutils.Factory
+}
+
+func (s *StorefrontOrderTrackingSuite) SetupSuite() {
+	s.client = testutils.NewHTTPClient(e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *StorefrontOrderTrackingSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, storefrontTrackingTables...))
+}
+
+func (s *StorefrontOrderTrackingSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, storefrontTrackingTables...))
+}
+
+// setup creates a business and a shipped order of one customer.
+func (s *StorefrontOrderTrackingSuite) setup(ctx context.Context) (*business.Business, *customer.Customer, *order.Order) {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID, func(b *business.Business) {
+		b.SupportEmail = "support@example.com"
+		b.WhatsappNumber = "+201000000000"
+	})
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+	prod, err := s.factory.Product(ctx, biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	shippedAt := time.Now().UTC().Add(-time.Hour)
+	ord, err := s.factory.Order(ctx, biz, []testutils.OrderLine{{Variant: v, Quantity: 2}}, func(o *order.Order) {
+		o.CustomerID = cust.ID
+		o.Status = order.OrderStatusShipped
+		o.PlacedAt = sql.NullTime{Time: shippedAt.Add(-time.Hour), Valid: true}
+		o.ShippedAt = sql.NullTime{Time: shippedAt, Valid: true}
+	})
+	s.Require().NoError(err)
+	return biz, cust, ord
+}
+
+func (s *StorefrontOrderTrackingSuite) track(biz *business.Business, orderNumber, email string) (int, map[string]interface{}) {
+	path := "/v1/storefront/" + biz.StorefrontPublicID + "/orders/" + url.PathEscape(orderNumber) + "/track?email=" + url.QueryEscape(email)
+	resp, err := s.client.Get(path)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	if resp.StatusCode == http.StatusOK {
+		s.Equal("private, no-store", resp.Header.Get("Cache-Control"))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *StorefrontOrderTrackingSuite) TestTrackOrder_ReturnsSanitizedStatusAndShipments() {
+	ctx := context.Background()
+	biz, cust, ord := s.setup(ctx)
+
+	label := &business.ShippingLabel{
+		BusinessID:     biz.ID,
+		OrderID:        ord.ID,
+		Carrier:        business.CarrierAramex,
+		Service:        "PPX",
+		TrackingNumber: "AWB123456",
+		TransitDays:    3,
+		Amount:         decimal.NewFromInt(40),
+		Currency:       biz.Currency,
+	}
+	s.Require().NoError(database.NewRepository[business.ShippingLabel](testEnv.Database).CreateOne(ctx, label))
+
+	// The email is matched case-insensitively.
+	status, body := s.track(biz, ord.OrderNumber, strings.ToUpper(cust.Email.String))
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal(ord.OrderNumber, body["orderNumber"])
+	s.Equal("shipped", body["status"])
+	s.Equal("pending", body["paymentStatus"])
+	s.NotNil(body["shippedAt"])
+	s.NotNil(body["estimatedDeliveryAt"])
+
+	items := body["items"].([]interface{})
+	s.Require().Len(items, 1)
+	s.EqualValues(2, items[0].(map[string]interface{})["quantity"])
+
+	shipments := body["shipments"].([]interface{})
+	s.Require().Len(shipments, 1)
+	shipment := shipments[0].(map[string]interface{})
+	s.Equal("aramex", shipment["carrier"])
+	s.Equal("AWB123456", shipment["trackingNumber"])
+	s.Contains(shipment["trackingUrl"], "AWB123456")
+	s.Equal(body["estimatedDeliveryAt"], shipment["estimatedDeliveryAt"])
+
+	biz2 := body["business"].(map[string]interface{})
+	s.Equal("support@example.com", biz2["supportEmail"])
+
+	// Prices, addresses and customer details are not exposed.
+	for _, key := range []string{"id", "total", "subtotal", "customer", "customerId", "shippingAddress", "notes", "businessId"} {
+		s.NotContains(body, key)
+	}
+	s.NotContains(items[0], "unitPrice")
+	s.NotContains(shipment, "amount")
+}
+
+func (s *StorefrontOrderTrackingSuite) TestTrackOrder_NoEstimateWithoutShipment() {
+	ctx := context.Background()
+	biz, cust, ord := s.setup(ctx)
+
+	status, body := s.track(biz, ord.OrderNumber, cust.Email.String)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Empty(body["shipments"])
+	s.Nil(body["estimatedDeliveryAt"])
+}
+
+func (s *StorefrontOrderTrackingSuite) TestTrackOrder_WrongEmailLooksLikeUnknownOrder() {
+	ctx := context.Background()
+	biz, _, ord := s.setup(ctx)
+
+	status, wrongEmail := s.track(biz, ord.OrderNumber, "someone@example.com")
+	s.Equal(http.StatusNotFound, status)
+	s.Equal("storefront.order_not_found", wrongEmail["extensions"].(map[string]interface{})["code"])
+
+	status, unknown := s.track(biz, "ORD-UNKNOWN", "someone@example.com")
+	s.Equal(http.StatusNotFound, status)
+	s.Equal(wrongEmail["extensions"].(map[string]interface{})["code"], unknown["extensions"].(map[string]interface{})["code"])
+}
+
+func (s *StorefrontOrderTrackingSuite) TestTrackOrder_OtherBusinessOrderNotFound() {
+	ctx := context.Background()
+	_, cust, ord := s.setup(ctx)
+	other, _, _ := s.setup(ctx)
+
+	status, _ := s.track(other, ord.OrderNumber, cust.Email.String)
+	s.Equal(http.StatusNotFound, status)
+}
+
+func (s *StorefrontOrderTrackingSuite) TestTrackOrder_Validation() {
+	ctx := context.Background()
+	biz, _, ord := s.setup(ctx)
+
+	status, _ := s.track(biz, ord.OrderNumber, "")
+	s.Equal(http.StatusBadRequest, status)
+	status, _ = s.track(biz, ord.OrderNumber, "not-an-email")
+	s.Equal(http.StatusBadRequest, status)
+
+	resp, err := s.client.Get("/v1/storefront/sf_missing/orders/" + ord.OrderNumber + "/track?email=a@example.com")
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func TestStorefrontOrderTrackingSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(StorefrontOrderTrackingSuite))
+}
@@ -186,6 +186,43 @@ func (s *StorefrontSuite) TestGetCatalog_DisabledStorefront() {
 	s.Equal(http.StatusNotFound, resp.StatusCode)
 }
 
+func (s *StorefrontSuite) TestGetCatalog_ComingSoonRequiresAccessCode() {
+	ctx := context.Background()
+	ws := s.createWorkspace(ctx)
+	biz := s.createBusiness(ctx, ws.ID, "biz", true)
+	biz.StorefrontComingSoon = true
+	biz.StorefrontComingSoonMessage = "Launching next week"
+	biz.StorefrontAccessCode = "preview-2024"
+	s.NoError(database.NewRepository[business.Business](s.db).UpdateOne(ctx, biz))
+	path := "/v1/storefront/" + biz.StorefrontPublicID + "/catalog"
+
+	resp, err := s.client.Get(path)
+	s.NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+	var problemBody map[string]interface{}
+	s.NoError(testutils.DecodeJSON(resp, &problemBody))
+	ext := problemBody["extensions"].(map[string]interface{})
+	s.Equal("storefront.coming_soon", ext["code"])
+	s.Equal("Launching next week", ext["message"])
+
+	resp, err = s.client.RequestRaw("GET", path, nil, map[string]string{"X-Storefront-Access-Code": "wrong"})
+	s.NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+
+	resp, err = s.client.RequestRaw("GET", path, nil, map[string]string{"X-Storefront-Access-Code": "preview-2024"})
+	s.NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Equal("private, no-store", resp.Header.Get("Cache-Control"))
+
+	resp, err = s.client.Get("/v1/storefront/" + biz.StorefrontPublicID + "/shipping-zones")
+	s.NoError(err)
+	defer resp.Body.Close()
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+}
+
 func (s *StorefrontSuite) TestCreateOrder_IdempotentAndCreatesNote() {
 	ctx := context.Background()
 	ws := s.createWorkspace(ctx)
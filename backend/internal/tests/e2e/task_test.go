@@ -0,0 +1,253 @@
+package e2e_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/suite"
+)
+
+var taskTables = []string{
+	"users", "workspaces", "businesses", "subscriptions", "plans",
+	"categories", "products", "variants",
+	"customers", "customer_addresses", "orders", "order_items", "order_notes",
+	"tasks",
+}
+
+// TaskSuite tests team tasks: assignment, "my tasks" and tasks automated from order events.
+type TaskSuite struct {
+	suite.Suite
+	helper  *OrderTestHelper
+	factory *testutils.Factory
+}
+
+func (s *TaskSuite) SetupSuite() {
+	s.helper = NewOrderTestHelper(testEnv.Database, e2eBaseURL)
+	s.factory = testutils.NewFactory(testEnv.Database)
+}
+
+func (s *TaskSuite) SetupTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, taskTables...))
+}
+
+func (s *TaskSuite) TearDownTest() {
+	s.NoError(testutils.TruncateTables(testEnv.Database, taskTables...))
+}
+
+type taskFixture struct {
+	owner      *testutils.Owner
+	staff      *account.User
+	staffToken string
+	biz        *business.Business
+	cust       *customer.Customer
+}
+
+// setup creates a business whose workspace has the owner and one staff member.
+func (s *TaskSuite) setup(ctx context.Context) *taskFixture {
+	owner, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+	biz, err := s.factory.Business(ctx, owner.Workspace.ID)
+	s.Require().NoError(err)
+	cust, err := s.factory.Customer(ctx, biz.ID)
+	s.Require().NoError(err)
+
+	staff := &account.User{
+		WorkspaceID:     owner.Workspace.ID,
+		Role:            role.RoleUser,
+		FirstName:       "Staff",
+		LastName:        "Member",
+		Email:           fmt.Sprintf("staff-%s@example.com", owner.Workspace.ID),
+		IsEmailVerified: true,
+	}
+	s.Require().NoError(database.NewRepository[account.User](testEnv.Database).CreateOne(ctx, staff))
+	token, err := auth.NewJwtToken(staff.ID, staff.WorkspaceID, staff.AuthVersion)
+	s.Require().NoError(err)
+	return &taskFixture{owner: owner, staff: staff, staffToken: token, biz: biz, cust: cust}
+}
+
+func (s *TaskSuite) do(fx *taskFixture, token, method, path string, payload interface{}) (int, map[string]interface{}) {
+	resp, err := s.helper.Client.AuthenticatedRequest(method, "/v1/businesses/"+fx.biz.Descriptor+path, payload, token)
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if resp.StatusCode != http.StatusNoContent {
+		s.Require().NoError(testutils.DecodeJSON(resp, &body))
+	}
+	return resp.StatusCode, body
+}
+
+func (s *TaskSuite) items(body map[string]interface{}) []map[string]interface{} {
+	var out []map[string]interface{}
+	for _, it := range body["items"].([]interface{}) {
+		out = append(out, it.(map[string]interface{}))
+	}
+	return out
+}
+
+func (s *TaskSuite) TestTaskLifecycle_AssignAndComplete() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+
+	due := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	status, body := s.do(fx, fx.owner.Token, "POST", "/tasks", map[string]interface{}{
+		"title":      "Call back about sizing",
+		"type":       "follow_up_customer",
+		"assigneeId": fx.staff.ID,
+		"customerId": fx.cust.ID,
+		"dueAt":      due,
+	})
+	s.Require().Equal(http.StatusCreated, status, body)
+	taskID := body["id"].(string)
+	s.Equal("open", body["status"])
+	s.Equal("manual", body["source"])
+	s.Equal(true, body["overdue"])
+	s.Equal(fx.owner.User.ID, body["createdById"])
+
+	status, body = s.do(fx, fx.owner.Token, "POST", "/tasks", map[string]interface{}{"title": "Owner's own task", "assigneeId": fx.owner.User.ID})
+	s.Require().Equal(http.StatusCreated, status, body)
+
+	status, body = s.do(fx, fx.staffToken, "GET", "/tasks/mine", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	mine := s.items(body)
+	s.Require().Len(mine, 1)
+	s.Equal(taskID, mine[0]["id"])
+
+	status, body = s.do(fx, fx.staffToken, "PATCH", "/tasks/"+taskID, map[string]interface{}{"status": "done"})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Equal("done", body["status"])
+	s.Equal(fx.staff.ID, body["completedById"])
+	s.NotEmpty(body["completedAt"])
+	s.Equal(false, body["overdue"])
+
+	status, body = s.do(fx, fx.staffToken, "GET", "/tasks/mine", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Empty(body["items"])
+	status, body = s.do(fx, fx.staffToken, "GET", "/tasks/mine?status=done", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Len(body["items"], 1)
+
+	status, body = s.do(fx, fx.owner.Token, "PATCH", "/tasks/"+taskID, map[string]interface{}{"status": "open", "assigneeId": "", "clearDueAt": true})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Nil(body["assigneeId"])
+	s.Nil(body["dueAt"])
+	s.Nil(body["completedAt"])
+
+	status, _ = s.do(fx, fx.owner.Token, "DELETE", "/tasks/"+taskID, nil)
+	s.Equal(http.StatusNoContent, status)
+	status, _ = s.do(fx, fx.owner.Token, "GET", "/tasks/"+taskID, nil)
+	s.Equal(http.StatusNotFound, status)
+}
+
+func (s *TaskSuite) TestCreateTask_Validation() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	stranger, err := s.factory.Workspace(ctx)
+	s.Require().NoError(err)
+
+	status, _ := s.do(fx, fx.owner.Token, "POST", "/tasks", map[string]interface{}{})
+	s.Equal(http.StatusBadRequest, status)
+
+	status, body := s.do(fx, fx.owner.Token, "POST", "/tasks", map[string]interface{}{"title": "x", "assigneeId": stranger.User.ID})
+	s.Equal(http.StatusNotFound, status, body)
+
+	status, body = s.do(fx, fx.owner.Token, "POST", "/tasks", map[string]interface{}{"title": "x", "orderId": "ord_missing"})
+	s.Equal(http.StatusNotFound, status, body)
+	s.Equal("order.not_found", body["extensions"].(map[string]interface{})["code"])
+
+	status, _ = s.do(fx, fx.owner.Token, "POST", "/tasks", map[string]interface{}{"title": "x", "type": "unknown"})
+	s.Equal(http.StatusBadRequest, status)
+}
+
+func (s *TaskSuite) TestPaidOrder_CreatesPrepareTask() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	prod, err := s.factory.Product(ctx, fx.biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	ord, err := s.factory.Order(ctx, fx.biz, []testutils.OrderLine{{Variant: v, Quantity: 1}}, func(o *order.Order) {
+		o.Status = order.OrderStatusPlaced
+	})
+	s.Require().NoError(err)
+
+	status, body := s.do(fx, fx.owner.Token, "PATCH", "/orders/"+ord.ID+"/payment-status", map[string]interface{}{"paymentStatus": "paid"})
+	s.Require().Equal(http.StatusOK, status, body)
+
+	prepare := s.waitForOrderTask(fx, ord.ID, func(t map[string]interface{}) bool { return t != nil })
+	s.Require().NotNil(prepare, "expected a prepare order task")
+	s.Equal("prepare_order", prepare["type"])
+	s.Equal("automation", prepare["source"])
+	s.Equal("Prepare order "+ord.OrderNumber, prepare["title"])
+	s.NotEmpty(prepare["dueAt"])
+
+	status, body = s.do(fx, fx.staffToken, "GET", "/tasks/mine?includeUnassigned=true", nil)
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Len(body["items"], 1)
+
+	for _, next := range []string{"shipped", "fulfilled"} {
+		status, body = s.do(fx, fx.owner.Token, "PATCH", "/orders/"+ord.ID+"/status", map[string]interface{}{"status": next})
+		s.Require().Equal(http.StatusOK, status, body)
+	}
+	done := s.waitForOrderTask(fx, ord.ID, func(t map[string]interface{}) bool { return t != nil && t["status"] == "done" })
+	s.NotNil(done, "expected the prepare task to be completed on fulfillment")
+}
+
+func (s *TaskSuite) TestCancelledOrder_DiscardsPrepareTask() {
+	ctx := context.Background()
+	fx := s.setup(ctx)
+	prod, err := s.factory.Product(ctx, fx.biz.ID)
+	s.Require().NoError(err)
+	v, err := s.factory.Variant(ctx, prod)
+	s.Require().NoError(err)
+	ord, err := s.factory.Order(ctx, fx.biz, []testutils.OrderLine{{Variant: v, Quantity: 1}}, func(o *order.Order) {
+		o.Status = order.OrderStatusPlaced
+	})
+	s.Require().NoError(err)
+
+	status, body := s.do(fx, fx.owner.Token, "PATCH", "/orders/"+ord.ID+"/payment-status", map[string]interface{}{"paymentStatus": "paid"})
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Require().NotNil(s.waitForOrderTask(fx, ord.ID, func(t map[string]interface{}) bool { return t != nil }))
+
+	status, body = s.do(fx, fx.owner.Token, "PATCH", "/orders/"+ord.ID+"/status", map[string]interface{}{"status": "cancelled"})
+	s.Require().Equal(http.StatusOK, status, body)
+	gone := s.waitForOrderTask(fx, ord.ID, func(t map[string]interface{}) bool { return t == nil })
+	s.Nil(gone)
+}
+
+// waitForOrderTask polls the order's task until match accepts it (nil when there is none),
+// since tasks are maintained by async bus handlers.
+func (s *TaskSuite) waitForOrderTask(fx *taskFixture, orderID string, match func(map[string]interface{}) bool) map[string]interface{} {
+	var found map[string]interface{}
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		status, body := s.do(fx, fx.owner.Token, "GET", "/tasks?orderId="+orderID, nil)
+		s.Require().Equal(http.StatusOK, status, body)
+		found = nil
+		if items := s.items(body); len(items) > 0 {
+			found = items[0]
+		}
+		if match(found) {
+			return found
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return found
+}
+
+func TestTaskSuite(t *testing.T) {
+	if testServer == nil {
+		t.Skip("Test server not initialized")
+	}
+	suite.Run(t, new(TaskSuite))
+}
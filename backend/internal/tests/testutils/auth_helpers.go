@@ -125,6 +125,27 @@ func CreateEmailVerificationToken(ctx context.Context, storage *account.Storage,
 	return token, err
 }
 
+// CreateEmailChangeToken creates the confirmation token of a change of user's email to newEmail
+func CreateEmailChangeToken(ctx context.Context, storage *account.Storage, user *account.User, newEmail string) (string, error) {
+	token, _, err := storage.CreateEmailChangeToken(&account.EmailChangePayload{
+		UserID:      user.ID,
+		WorkspaceID: user.WorkspaceID,
+		OldEmail:    user.Email,
+		NewEmail:    newEmail,
+	})
+	return token, err
+}
+
+// CreateMagicLinkToken creates a one-time sign-in token for a user
+func CreateMagicLinkToken(ctx context.Context, storage *account.Storage, user *account.User) (string, error) {
+	token, _, err := storage.CreateMagicLinkToken(&account.MagicLinkPayload{
+		UserID:      user.ID,
+		WorkspaceID: user.WorkspaceID,
+		Email:       user.Email,
+	})
+	return token, err
+}
+
 // CreateInvitationToken creates a workspace invitation token
 func CreateInvitationToken(ctx context.Context, storage *account.Storage, invitation *account.UserInvitation, inviterID string) (string, error) {
 	payload := &account.WorkspaceInvitationPayload{
@@ -133,6 +154,7 @@ func CreateInvitationToken(ctx context.Context, storage *account.Storage, invita
 		Email:        invitation.Email,
 		Role:         string(invitation.Role),
 		InviterID:    inviterID,
+		TokenVersion: invitation.TokenVersion,
 	}
 	token, _, err := storage.CreateWorkspaceInvitationToken(payload)
 	return token, err
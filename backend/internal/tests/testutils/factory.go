@@ -0,0 +1,270 @@
+package testutils
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/domain/business"
+	"github.com/abdelrahman146/kyora/internal/domain/customer"
+	"github.com/abdelrahman146/kyora/internal/domain/inventory"
+	"github.com/abdelrahman146/kyora/internal/domain/order"
+	"github.com/abdelrahman146/kyora/internal/platform/database"
+	"github.com/abdelrahman146/kyora/internal/platform/types/role"
+	"github.com/abdelrahman146/kyora/internal/platform/utils/transformer"
+	"github.com/shopspring/decimal"
+)
+
+// Option customizes an entity built by Factory before it is persisted.
+type Option[T any] func(*T)
+
+// Factory persists test entities with sane defaults so suites only spell out the
+// fields a test actually cares about. Unique fields (emails, descriptors, SKUs,
+// order numbers) are derived from a per-factory sequence, so repeated calls never collide.
+type Factory struct {
+	db  *database.Database
+	seq atomic.Int64
+}
+
+// NewFactory creates a Factory writing to db.
+func NewFactory(db *database.Database) *Factory {
+	return &Factory{db: db}
+}
+
+func (f *Factory) next() int64 {
+	return f.seq.Add(1)
+}
+
+// Owner is a workspace together with its owner and a valid access token for them.
+type Owner struct {
+	User      *account.User
+	Workspace *account.Workspace
+	Token     string
+}
+
+// Workspace creates a workspace owned by a verified admin with an active subscription.
+// Options apply to the owner before it is created.
+func (f *Factory) Workspace(ctx context.Context, opts ...Option[account.User]) (*Owner, error) {
+	n := f.next()
+	u := &account.User{
+		Email:     fmt.Sprintf("owner%d@example.com", n),
+		FirstName: "Owner",
+		LastName:  fmt.Sprintf("%d", n),
+		Role:      role.RoleAdmin,
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	user, ws, token, err := CreateAuthenticatedUser(ctx, f.db, u.Email, "Password123!", u.FirstName, u.LastName, u.Role)
+	if err != nil {
+		return nil, err
+	}
+	if err := CreateTestSubscription(ctx, f.db, ws.ID); err != nil {
+		return nil, err
+	}
+	return &Owner{User: user, Workspace: ws, Token: token}, nil
+}
+
+// Business creates a USD business in Egypt under workspaceID.
+func (f *Factory) Business(ctx context.Context, workspaceID string, opts ...Option[business.Business]) (*business.Business, error) {
+	n := f.next()
+	biz := &business.Business{
+		WorkspaceID:   workspaceID,
+		Descriptor:    fmt.Sprintf("biz-%d", n),
+		Name:          fmt.Sprintf("Business %d", n),
+		CountryCode:   "EG",
+		Currency:      "USD",
+		Timezone:      "UTC",
+		VatRate:       decimal.NewFromFloat(0.14),
+		SafetyBuffer:  decimal.NewFromFloat(100),
+		EstablishedAt: time.Now().UTC(),
+	}
+	for _, opt := range opts {
+		opt(biz)
+	}
+	if err := database.NewRepository[business.Business](f.db).CreateOne(ctx, biz); err != nil {
+		return nil, err
+	}
+	return biz, nil
+}
+
+// Category creates an inventory category for businessID.
+func (f *Factory) Category(ctx context.Context, businessID string, opts ...Option[inventory.Category]) (*inventory.Category, error) {
+	n := f.next()
+	cat := &inventory.Category{
+		BusinessID: businessID,
+		Name:       fmt.Sprintf("Category %d", n),
+		Descriptor: fmt.Sprintf("category-%d", n),
+	}
+	for _, opt := range opts {
+		opt(cat)
+	}
+	if err := database.NewRepository[inventory.Category](f.db).CreateOne(ctx, cat); err != nil {
+		return nil, err
+	}
+	return cat, nil
+}
+
+// Product creates a product for businessID. A category is created for it unless an
+// option sets CategoryID.
+func (f *Factory) Product(ctx context.Context, businessID string, opts ...Option[inventory.Product]) (*inventory.Product, error) {
+	n := f.next()
+	prod := &inventory.Product{
+		BusinessID:  businessID,
+		Name:        fmt.Sprintf("Product %d", n),
+		Description: fmt.Sprintf("Product %d description", n),
+	}
+	for _, opt := range opts {
+		opt(prod)
+	}
+	if prod.CategoryID == "" {
+		cat, err := f.Category(ctx, businessID)
+		if err != nil {
+			return nil, err
+		}
+		prod.CategoryID = cat.ID
+	}
+	if err := database.NewRepository[inventory.Product](f.db).CreateOne(ctx, prod); err != nil {
+		return nil, err
+	}
+	return prod, nil
+}
+
+// Variant creates a variant of product costing 50 and selling for 100, with 10 in stock.
+func (f *Factory) Variant(ctx context.Context, product *inventory.Product, opts ...Option[inventory.Variant]) (*inventory.Variant, error) {
+	n := f.next()
+	v := &inventory.Variant{
+		BusinessID:         product.BusinessID,
+		ProductID:          product.ID,
+		Name:               fmt.Sprintf("%s - Variant %d", product.Name, n),
+		Code:               fmt.Sprintf("V%d", n),
+		SKU:                fmt.Sprintf("SKU-%d", n),
+		CostPrice:          decimal.NewFromInt(50),
+		SalePrice:          decimal.NewFromInt(100),
+		Currency:           "USD",
+		StockQuantity:      10,
+		StockQuantityAlert: 2,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	if err := database.NewRepository[inventory.Variant](f.db).CreateOne(ctx, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Customer creates a customer of businessID with a unique email.
+func (f *Factory) Customer(ctx context.Context, businessID string, opts ...Option[customer.Customer]) (*customer.Customer, error) {
+	n := f.next()
+	cust := &customer.Customer{
+		BusinessID:  businessID,
+		Name:        fmt.Sprintf("Customer %d", n),
+		Email:       transformer.ToNullableString(fmt.Sprintf("customer%d@example.com", n)),
+		CountryCode: "EG",
+		Gender:      customer.GenderMale,
+		JoinedAt:    time.Now().UTC(),
+	}
+	for _, opt := range opts {
+		opt(cust)
+	}
+	if err := database.NewRepository[customer.Customer](f.db).CreateOne(ctx, cust); err != nil {
+		return nil, err
+	}
+	return cust, nil
+}
+
+// Address creates a Cairo shipping address for customerID.
+func (f *Factory) Address(ctx context.Context, customerID string, opts ...Option[customer.CustomerAddress]) (*customer.CustomerAddress, error) {
+	addr := &customer.CustomerAddress{
+		CustomerID:  customerID,
+		CountryCode: "EG",
+		State:       "Cairo",
+		City:        "Cairo",
+		Street:      transformer.ToNullableString("123 Test St"),
+		PhoneCode:   "+20",
+		PhoneNumber: "1234567890",
+	}
+	for _, opt := range opts {
+		opt(addr)
+	}
+	if err := database.NewRepository[customer.CustomerAddress](f.db).CreateOne(ctx, addr); err != nil {
+		return nil, err
+	}
+	return addr, nil
+}
+
+// OrderLine is one item of an order built by Factory.Order, priced from its variant.
+type OrderLine struct {
+	Variant  *inventory.Variant
+	Quantity int
+}
+
+// Order creates a pending order for biz with the given lines. Subtotal, COGS and total
+// are computed from the variants' sale and cost prices. A customer and shipping address
+// are created unless options set CustomerID and ShippingAddressID.
+func (f *Factory) Order(ctx context.Context, biz *business.Business, lines []OrderLine, opts ...Option[order.Order]) (*order.Order, error) {
+	n := f.next()
+	now := time.Now().UTC()
+	ord := &order.Order{
+		BusinessID:    biz.ID,
+		OrderNumber:   fmt.Sprintf("ORD-%d-%d", now.UnixNano(), n),
+		Channel:       "instagram",
+		Status:        order.OrderStatusPending,
+		PaymentStatus: order.OrderPaymentStatusPending,
+		Currency:      biz.Currency,
+		OrderedAt:     now,
+	}
+
+	items := make([]*order.OrderItem, 0, len(lines))
+	for _, line := range lines {
+		qty := decimal.NewFromInt(int64(line.Quantity))
+		item := &order.OrderItem{
+			ProductID: line.Variant.ProductID,
+			VariantID: line.Variant.ID,
+			Quantity:  line.Quantity,
+			UnitPrice: line.Variant.SalePrice,
+			UnitCost:  line.Variant.CostPrice,
+			Total:     line.Variant.SalePrice.Mul(qty),
+			TotalCost: line.Variant.CostPrice.Mul(qty),
+			Currency:  biz.Currency,
+		}
+		ord.Subtotal = ord.Subtotal.Add(item.Total)
+		ord.COGS = ord.COGS.Add(item.TotalCost)
+		items = append(items, item)
+	}
+	ord.Total = ord.Subtotal
+
+	for _, opt := range opts {
+		opt(ord)
+	}
+	if ord.CustomerID == "" {
+		cust, err := f.Customer(ctx, biz.ID)
+		if err != nil {
+			return nil, err
+		}
+		ord.CustomerID = cust.ID
+	}
+	if ord.ShippingAddressID == "" {
+		addr, err := f.Address(ctx, ord.CustomerID)
+		if err != nil {
+			return nil, err
+		}
+		ord.ShippingAddressID = addr.ID
+	}
+
+	if err := database.NewRepository[order.Order](f.db).CreateOne(ctx, ord); err != nil {
+		return nil, err
+	}
+	itemRepo := database.NewRepository[order.OrderItem](f.db)
+	for _, item := range items {
+		item.OrderID = ord.ID
+		if err := itemRepo.CreateOne(ctx, item); err != nil {
+			return nil, err
+		}
+	}
+	ord.Items = items
+	return ord, nil
+}
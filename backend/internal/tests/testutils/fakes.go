@@ -0,0 +1,162 @@
+package testutils
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"sync"
+
+	"github.com/abdelrahman146/kyora/internal/domain/account"
+	"github.com/abdelrahman146/kyora/internal/platform/auth"
+	"github.com/stripe/stripe-go/v83"
+)
+
+// FakeGoogleOAuth is an auth.GoogleOAuthProvider for e2e tests. Codes registered with
+// RegisterCode exchange for the given profile; any other code fails like a rejected
+// exchange with Google would.
+type FakeGoogleOAuth struct {
+	mu    sync.RWMutex
+	users map[string]auth.GoogleUserInfo
+}
+
+var _ auth.GoogleOAuthProvider = (*FakeGoogleOAuth)(nil)
+
+// NewFakeGoogleOAuth returns a fake provider with no registered codes.
+func NewFakeGoogleOAuth() *FakeGoogleOAuth {
+	return &FakeGoogleOAuth{users: map[string]auth.GoogleUserInfo{}}
+}
+
+// RegisterCode makes code exchange for info.
+func (f *FakeGoogleOAuth) RegisterCode(code string, info auth.GoogleUserInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.users[code] = info
+}
+
+// Reset forgets every registered code.
+func (f *FakeGoogleOAuth) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.users = map[string]auth.GoogleUserInfo{}
+}
+
+// AuthURL returns a Google-shaped consent URL carrying state.
+func (f *FakeGoogleOAuth) AuthURL(_ context.Context, state string) (string, error) {
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("scope", "openid email profile")
+	q.Set("state", state)
+	return "https://accounts.google.com/o/oauth2/auth?" + q.Encode(), nil
+}
+
+// ExchangeAndFetchUser returns the profile registered for code.
+func (f *FakeGoogleOAuth) ExchangeAndFetchUser(_ context.Context, code string) (*auth.GoogleUserInfo, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	info, ok := f.users[code]
+	if !ok {
+		return nil, fmt.Errorf("code exchange failed: unknown code")
+	}
+	return &info, nil
+}
+
+// FakeOIDC is an auth.OIDCProvider for e2e tests. Codes registered with RegisterCode
+// exchange for the given identity; any other code fails like a rejected exchange with an
+// identity provider would.
+type FakeOIDC struct {
+	mu         sync.RWMutex
+	identities map[string]auth.OIDCIdentity
+}
+
+var _ auth.OIDCProvider = (*FakeOIDC)(nil)
+
+// NewFakeOIDC returns a fake provider with no registered codes.
+func NewFakeOIDC() *FakeOIDC {
+	return &FakeOIDC{identities: map[string]auth.OIDCIdentity{}}
+}
+
+// RegisterCode makes code exchange for identity.
+func (f *FakeOIDC) RegisterCode(code string, identity auth.OIDCIdentity) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.identities[code] = identity
+}
+
+// Reset forgets every registered code.
+func (f *FakeOIDC) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.identities = map[string]auth.OIDCIdentity{}
+}
+
+// AuthURL returns an authorization URL at the client's issuer carrying state and nonce.
+func (f *FakeOIDC) AuthURL(_ context.Context, client auth.OIDCClient, state, nonce string) (string, error) {
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", client.ClientID)
+	q.Set("redirect_uri", client.RedirectURL)
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	return client.Issuer + "/authorize?" + q.Encode(), nil
+}
+
+// Exchange returns the identity registered for code.
+func (f *FakeOIDC) Exchange(_ context.Context, _ auth.OIDCClient, code, _ string) (*auth.OIDCIdentity, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	identity, ok := f.identities[code]
+	if !ok {
+		return nil, fmt.Errorf("code exchange failed: unknown code")
+	}
+	return &identity, nil
+}
+
+// FakeTXTResolver is an account.TXTResolver for e2e tests. Names published with Publish
+// resolve to their records; any other name fails like a missing DNS record would.
+type FakeTXTResolver struct {
+	mu      sync.RWMutex
+	records map[string][]string
+}
+
+var _ account.TXTResolver = (*FakeTXTResolver)(nil)
+
+// NewFakeTXTResolver returns a fake resolver with no records.
+func NewFakeTXTResolver() *FakeTXTResolver {
+	return &FakeTXTResolver{records: map[string][]string{}}
+}
+
+// Publish adds the TXT record value under name.
+func (f *FakeTXTResolver) Publish(name, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.records[name] = append(f.records[name], value)
+}
+
+// Reset removes every record.
+func (f *FakeTXTResolver) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.records = map[string][]string{}
+}
+
+// LookupTXT returns the records published under name.
+func (f *FakeTXTResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	records, ok := f.records[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return slices.Clone(records), nil
+}
+
+// ConfigureStripeMock routes stripe-go calls made from the test process (fixtures and
+// assertions) to the stripe-mock at baseURL, authenticating with apiKey.
+// The server is wired separately through server.WithStripeBaseURL.
+func ConfigureStripeMock(apiKey, baseURL string) {
+	stripe.Key = apiKey
+	backend := stripe.GetBackendWithConfig(stripe.APIBackend, &stripe.BackendConfig{URL: &baseURL})
+	stripe.SetBackend(stripe.APIBackend, backend)
+}
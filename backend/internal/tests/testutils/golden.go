@@ -0,0 +1,99 @@
+package testutils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// goldenDir is where golden files live, relative to the package under test.
+const goldenDir = "testdata/golden"
+
+// UpdateGoldenEnv rewrites golden files from the actual responses when set to "1".
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+const (
+	goldenIDPlaceholder        = "<id>"
+	goldenTimestampPlaceholder = "<timestamp>"
+	goldenIgnoredPlaceholder   = "<ignored>"
+)
+
+// AssertGoldenJSON compares a JSON response body with testdata/golden/<name>.json.
+//
+// Before comparing, volatile values are replaced with placeholders: non-empty strings
+// under "id" or any key ending in "Id" become "<id>", RFC3339 timestamps become
+// "<timestamp>", and values under the ignore keys become "<ignored>". Object keys are
+// sorted, so golden files are stable and diff cleanly.
+//
+// Run the tests with UPDATE_GOLDEN=1 to create or refresh golden files.
+func AssertGoldenJSON(t testing.TB, name string, body []byte, ignore ...string) {
+	t.Helper()
+
+	got, err := NormalizeJSON(body, ignore...)
+	require.NoError(t, err, "golden %s: response is not valid JSON: %s", name, body)
+
+	path := filepath.Join(goldenDir, name+".json")
+	if os.Getenv(UpdateGoldenEnv) == "1" {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "golden %s: missing file, run with %s=1 to create it", name, UpdateGoldenEnv)
+	require.JSONEq(t, string(want), string(got), "golden %s: response differs from %s", name, path)
+}
+
+// NormalizeJSON re-encodes body with volatile values replaced as described in
+// AssertGoldenJSON, indented and terminated by a newline.
+func NormalizeJSON(body []byte, ignore ...string) ([]byte, error) {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil, err
+	}
+	ignored := make(map[string]bool, len(ignore))
+	for _, key := range ignore {
+		ignored[key] = true
+	}
+	out, err := json.MarshalIndent(normalizeGoldenValue("", v, ignored), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+func normalizeGoldenValue(key string, v any, ignored map[string]bool) any {
+	if ignored[key] {
+		return goldenIgnoredPlaceholder
+	}
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			val[k] = normalizeGoldenValue(k, child, ignored)
+		}
+		return val
+	case []any:
+		for i, child := range val {
+			val[i] = normalizeGoldenValue(key, child, ignored)
+		}
+		return val
+	case string:
+		if val == "" {
+			return val
+		}
+		if key == "id" || strings.HasSuffix(key, "Id") {
+			return goldenIDPlaceholder
+		}
+		if _, err := time.Parse(time.RFC3339Nano, val); err == nil {
+			return goldenTimestampPlaceholder
+		}
+		return val
+	default:
+		return val
+	}
+}
@@ -0,0 +1,32 @@
+package testutils_test
+
+import (
+	"testing"
+
+	"github.com/abdelrahman146/kyora/internal/tests/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeJSON(t *testing.T) {
+	body := []byte(`{
+		"id": "cat_2abc",
+		"businessId": "bus_9xyz",
+		"parentId": "",
+		"name": "T-Shirts",
+		"createdAt": "2026-01-02T03:04:05.123456Z",
+		"orderNumber": "ORD-1",
+		"items": [{"variantId": "var_1", "quantity": 2}]
+	}`)
+
+	got, err := testutils.NormalizeJSON(body, "orderNumber")
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"id": "<id>",
+		"businessId": "<id>",
+		"parentId": "",
+		"name": "T-Shirts",
+		"createdAt": "<timestamp>",
+		"orderNumber": "<ignored>",
+		"items": [{"variantId": "<id>", "quantity": 2}]
+	}`, string(got))
+}
@@ -31,6 +31,7 @@ import type {
   CreateInvestmentRequest,
   CreateRecurringExpenseRequest,
   CreateWithdrawalRequest,
+  CreateWithdrawalResponse,
   Expense,
   ExpenseCategory,
   Investment,
@@ -489,8 +490,8 @@ export const accountingApi = {
   async createWithdrawal(
     businessDescriptor: string,
     data: CreateWithdrawalRequest,
-  ): Promise<Withdrawal> {
-    return post<Withdrawal>(
+  ): Promise<CreateWithdrawalResponse> {
+    return post<CreateWithdrawalResponse>(
       `v1/businesses/${businessDescriptor}/accounting/withdrawals`,
       { json: data },
     )
@@ -153,6 +153,24 @@ export const withdrawalSchema = z.object({
 
 export type Withdrawal = z.infer<typeof withdrawalSchema>
 
+// Set on a created withdrawal that exceeds the safe-to-draw amount
+export const safeToDrawWarningSchema = z.object({
+  code: z.string(),
+  safeToDrawAmount: z.string(),
+  excess: z.string(),
+  overridden: z.boolean(),
+})
+
+export type SafeToDrawWarning = z.infer<typeof safeToDrawWarningSchema>
+
+export const createWithdrawalResponseSchema = withdrawalSchema.extend({
+  warning: safeToDrawWarningSchema.optional(),
+})
+
+export type CreateWithdrawalResponse = z.infer<
+  typeof createWithdrawalResponseSchema
+>
+
 // =============================================================================
 // Asset Type Enum
 // =============================================================================
@@ -195,6 +213,10 @@ export const accountingSummarySchema = z.object({
   totalInvestments: z.string(),
   totalWithdrawals: z.string(),
   totalExpenses: z.string(),
+  totalRevenue: z.string(),
+  totalCogs: z.string(),
+  grossProfit: z.string(),
+  netProfit: z.string(),
   safeToDrawAmount: z.string(),
   currency: z.string(),
   from: z.string().nullable().optional(),
@@ -321,6 +343,7 @@ export const createWithdrawalRequestSchema = z.object({
   amount: z.string().min(1),
   withdrawnAt: z.string(), // RFC3339/ISO8601
   note: z.string().optional(),
+  override: z.boolean().optional(), // admins only, past an enforced safe-to-draw limit
 })
 
 export type CreateWithdrawalRequest = z.infer<